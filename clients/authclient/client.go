@@ -0,0 +1,84 @@
+// Package authclient is the client Marketback services use to call the Auth
+// service on their own behalf. It wraps the generated auth client with the
+// service's introspection credentials (INTROSPECTION_CLIENTS on the Auth
+// side), which the generated client has no notion of. GRPCClient does the
+// same for the Auth service's gRPC API (authpb). Keys caches the public keys
+// access tokens are signed with, so services can verify them locally, and
+// DenylistKey names the Redis key of a revoked one.
+package authclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Zifeldev/marketback/clients/auth"
+)
+
+// DefaultTimeout bounds a call when the caller's context has no deadline.
+const DefaultTimeout = 5 * time.Second
+
+// Introspection is the Auth service's view of a token.
+type Introspection = auth.IntrospectionResponse
+
+// Client calls the Auth service with HTTP Basic client credentials.
+type Client struct {
+	api *auth.Client
+}
+
+// New returns a client for the Auth service at baseURL that authenticates
+// as clientID.
+func New(baseURL, clientID, clientSecret string) *Client {
+	httpClient := &http.Client{
+		Timeout: DefaultTimeout,
+		Transport: &basicAuthTransport{
+			clientID:     clientID,
+			clientSecret: clientSecret,
+			base:         http.DefaultTransport,
+		},
+	}
+	return &Client{api: auth.NewClient(baseURL, auth.WithHTTPClient(httpClient))}
+}
+
+// Introspect asks the Auth service whether an access token is still active.
+// Expired, revoked and unknown tokens are reported with Active false, not as
+// an error; errors mean the Auth service could not answer.
+func (c *Client) Introspect(ctx context.Context, token string) (*Introspection, error) {
+	if token == "" {
+		return nil, errors.New("empty token")
+	}
+	form := url.Values{}
+	form.Set("token", token)
+	form.Set("token_type_hint", "access_token")
+	return c.api.IntrospectToken(ctx, form)
+}
+
+// ErrUserNotFound is returned for a user the Auth service does not know,
+// e.g. one whose account was deleted.
+var ErrUserNotFound = errors.New("user not found")
+
+// SuspendUser suspends a user's account in the Auth service: every sign-in
+// is refused and all sessions are revoked until an admin lifts it.
+// Suspending a suspended user succeeds again.
+func (c *Client) SuspendUser(ctx context.Context, userID int64) error {
+	_, err := c.api.SuspendUserForService(ctx, int(userID))
+	var apiErr *auth.Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+type basicAuthTransport struct {
+	clientID     string
+	clientSecret string
+	base         http.RoundTripper
+}
+
+func (t *basicAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.SetBasicAuth(t.clientID, t.clientSecret)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,97 @@
+package authclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/clients/auth"
+)
+
+func TestIntrospect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if r.Method != http.MethodPost || r.URL.Path != "/auth/introspect" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if !ok || id != "market" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid client credentials"})
+			return
+		}
+		if got := r.PostFormValue("token_type_hint"); got != "access_token" {
+			t.Errorf("token_type_hint = %q", got)
+		}
+
+		resp := map[string]interface{}{"active": false}
+		if r.PostFormValue("token") == "good" {
+			resp = map[string]interface{}{"active": true, "user_id": 7, "role": "seller", "token_type": "access_token"}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := New(srv.URL, "market", "s3cret")
+
+	got, err := c.Introspect(ctx, "good")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Active || got.UserID != 7 || got.Role != "seller" {
+		t.Fatalf("unexpected introspection %+v", got)
+	}
+
+	got, err = c.Introspect(ctx, "revoked")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Active {
+		t.Fatal("revoked token reported active")
+	}
+
+	_, err = New(srv.URL, "market", "wrong").Introspect(ctx, "good")
+	var apiErr *auth.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected a 401 auth.Error, got %v", err)
+	}
+	if apiErr.Response == nil || apiErr.Response.Error != "invalid client credentials" {
+		t.Fatalf("error envelope not decoded: %+v", apiErr.Response)
+	}
+
+	if _, err := c.Introspect(ctx, ""); err == nil {
+		t.Fatal("expected an error for an empty token")
+	}
+}
+
+func TestSuspendUser(t *testing.T) {
+	var suspended []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, _, _ := r.BasicAuth(); r.Method != http.MethodPost || id != "market" {
+			t.Errorf("unexpected request %s %s by %q", r.Method, r.URL.Path, id)
+		}
+		if r.URL.Path == "/auth/users/8/suspend" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "user not found"})
+			return
+		}
+		suspended = append(suspended, r.URL.Path)
+		json.NewEncoder(w).Encode(map[string]string{"message": "user suspended; sessions revoked"})
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "market", "s3cret")
+	if err := c.SuspendUser(context.Background(), 7); err != nil {
+		t.Fatal(err)
+	}
+	if len(suspended) != 1 || suspended[0] != "/auth/users/7/suspend" {
+		t.Fatalf("unexpected requests %v", suspended)
+	}
+
+	if err := c.SuspendUser(context.Background(), 8); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,12 @@
+package authclient
+
+// denylistKeyPrefix follows the Auth service's REDIS_PREFIX in denylist keys.
+const denylistKeyPrefix = "blacklist:token:"
+
+// DenylistKey is the Redis key under which the Auth service records the
+// access token whose jti claim is jti as revoked until it expires; prefix
+// is the Auth service's REDIS_PREFIX. Services reading that Redis reject
+// revoked tokens without introspecting every one.
+func DenylistKey(prefix, jti string) string {
+	return prefix + denylistKeyPrefix + jti
+}
@@ -0,0 +1,9 @@
+package authclient
+
+import "testing"
+
+func TestDenylistKey(t *testing.T) {
+	if got := DenylistKey("auth:", "abc123"); got != "auth:blacklist:token:abc123" {
+		t.Fatalf("DenylistKey = %q", got)
+	}
+}
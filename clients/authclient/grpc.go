@@ -0,0 +1,111 @@
+package authclient
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+
+	"github.com/Zifeldev/marketback/clients/authpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// MaxUsersPerLookup is how many IDs the Auth service accepts in one
+// ListUsersByIDs call; longer lists are split.
+const MaxUsersPerLookup = 500
+
+// User is a user as the Auth service reports it.
+type User = authpb.User
+
+// GRPCClient calls the Auth service's gRPC API with the same service
+// credentials as Client.
+type GRPCClient struct {
+	conn *grpc.ClientConn
+	api  authpb.AuthClient
+}
+
+// NewGRPC returns a client for the Auth service's gRPC API at addr
+// (host:port) that authenticates as clientID. The connection is made lazily
+// on the first call; opts are applied after the defaults, e.g. to dial over
+// TLS.
+func NewGRPC(addr, clientID, clientSecret string, opts ...grpc.DialOption) (*GRPCClient, error) {
+	opts = append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(basicCredentials{clientID: clientID, clientSecret: clientSecret}),
+	}, opts...)
+	conn, err := grpc.NewClient(addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &GRPCClient{conn: conn, api: authpb.NewAuthClient(conn)}, nil
+}
+
+// Close closes the connection.
+func (c *GRPCClient) Close() error {
+	return c.conn.Close()
+}
+
+// ValidateToken asks the Auth service whether an access token is still
+// active. Like Introspect, inactive tokens are not an error.
+func (c *GRPCClient) ValidateToken(ctx context.Context, token string) (*authpb.ValidateTokenResponse, error) {
+	if token == "" {
+		return nil, errors.New("empty token")
+	}
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return c.api.ValidateToken(ctx, &authpb.ValidateTokenRequest{Token: token})
+}
+
+// GetUser returns a user; unknown users are a NotFound status error.
+func (c *GRPCClient) GetUser(ctx context.Context, id int64) (*User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return c.api.GetUser(ctx, &authpb.GetUserRequest{Id: id})
+}
+
+// ListUsersByIDs returns the users with the given IDs by ID. Unknown IDs are
+// missing from the map.
+func (c *GRPCClient) ListUsersByIDs(ctx context.Context, ids []int64) (map[int64]*User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	users := make(map[int64]*User, len(ids))
+	for len(ids) > 0 {
+		batch := ids[:min(len(ids), MaxUsersPerLookup)]
+		ids = ids[len(batch):]
+
+		resp, err := c.api.ListUsersByIDs(ctx, &authpb.ListUsersByIDsRequest{Ids: batch})
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range resp.GetUsers() {
+			users[user.GetId()] = user
+		}
+	}
+	return users, nil
+}
+
+// withTimeout applies DefaultTimeout when ctx has no deadline.
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, DefaultTimeout)
+}
+
+// basicCredentials sends the client credentials with every call, as the
+// HTTP client does. The Auth service is reached inside the deployment's
+// network, so they are sent without transport security too.
+type basicCredentials struct {
+	clientID     string
+	clientSecret string
+}
+
+func (b basicCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	auth := base64.StdEncoding.EncodeToString([]byte(b.clientID + ":" + b.clientSecret))
+	return map[string]string{"authorization": "Basic " + auth}, nil
+}
+
+func (basicCredentials) RequireTransportSecurity() bool {
+	return false
+}
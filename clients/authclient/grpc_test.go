@@ -0,0 +1,118 @@
+package authclient
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"testing"
+
+	"github.com/Zifeldev/marketback/clients/authpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type fakeAuthServer struct {
+	authpb.UnimplementedAuthServer
+	batches []int
+}
+
+func (s *fakeAuthServer) ValidateToken(ctx context.Context, req *authpb.ValidateTokenRequest) (*authpb.ValidateTokenResponse, error) {
+	if req.GetToken() != "good" {
+		return &authpb.ValidateTokenResponse{}, nil
+	}
+	return &authpb.ValidateTokenResponse{Active: true, UserId: 7, Role: "seller"}, nil
+}
+
+func (s *fakeAuthServer) GetUser(ctx context.Context, req *authpb.GetUserRequest) (*authpb.User, error) {
+	if req.GetId() != 7 {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return &authpb.User{Id: 7, Email: "seller@example.com", Role: "seller"}, nil
+}
+
+// ListUsersByIDs knows the users with even IDs.
+func (s *fakeAuthServer) ListUsersByIDs(ctx context.Context, req *authpb.ListUsersByIDsRequest) (*authpb.ListUsersByIDsResponse, error) {
+	s.batches = append(s.batches, len(req.GetIds()))
+	resp := &authpb.ListUsersByIDsResponse{}
+	for _, id := range req.GetIds() {
+		if id%2 == 0 {
+			resp.Users = append(resp.Users, &authpb.User{Id: id, Email: "user@example.com"})
+		}
+	}
+	return resp, nil
+}
+
+func TestGRPCClient(t *testing.T) {
+	want := "Basic " + base64.StdEncoding.EncodeToString([]byte("market:s3cret"))
+	fake := &fakeAuthServer{}
+	srv := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if got := md.Get("authorization"); len(got) != 1 || got[0] != want {
+			return nil, status.Error(codes.Unauthenticated, "invalid client credentials")
+		}
+		return handler(ctx, req)
+	}))
+	authpb.RegisterAuthServer(srv, fake)
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	dial := grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	})
+	newClient := func(secret string) *GRPCClient {
+		c, err := NewGRPC("passthrough:///auth", "market", secret, dial)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { c.Close() })
+		return c
+	}
+
+	ctx := context.Background()
+	c := newClient("s3cret")
+
+	got, err := c.ValidateToken(ctx, "good")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.GetActive() || got.GetUserId() != 7 {
+		t.Fatalf("unexpected validation %+v", got)
+	}
+	if got, err := c.ValidateToken(ctx, "revoked"); err != nil || got.GetActive() {
+		t.Fatalf("revoked token: %+v, %v", got, err)
+	}
+	if _, err := c.ValidateToken(ctx, ""); err == nil {
+		t.Fatal("expected an error for an empty token")
+	}
+
+	user, err := c.GetUser(ctx, 7)
+	if err != nil || user.GetEmail() != "seller@example.com" {
+		t.Fatalf("GetUser: %+v, %v", user, err)
+	}
+	if _, err := c.GetUser(ctx, 8); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+
+	ids := make([]int64, MaxUsersPerLookup+10)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	users, err := c.ListUsersByIDs(ctx, ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != len(ids)/2 || users[2] == nil || users[3] != nil {
+		t.Fatalf("unexpected users: %d", len(users))
+	}
+	if len(fake.batches) != 2 || fake.batches[0] != MaxUsersPerLookup || fake.batches[1] != 10 {
+		t.Fatalf("unexpected batches %v", fake.batches)
+	}
+
+	if _, err := newClient("wrong").GetUser(ctx, 7); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+}
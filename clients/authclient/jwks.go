@@ -0,0 +1,115 @@
+package authclient
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Zifeldev/marketback/clients/auth"
+)
+
+const (
+	// KeysTTL is how long fetched signing keys are trusted, matching the
+	// Cache-Control the Auth service sends with them.
+	KeysTTL = time.Hour
+	// KeysMinRefresh limits refetches for tokens with an unknown key ID,
+	// so a key added by a rotation is picked up within a minute.
+	KeysMinRefresh = time.Minute
+)
+
+// ErrUnknownKey is returned for a key ID the Auth service does not publish.
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// Keys caches the RS256 public keys the Auth service signs access tokens
+// with, as published at /.well-known/jwks.json. It is safe for concurrent
+// use.
+type Keys struct {
+	url    string
+	client *http.Client
+	now    func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+	// triedAt is the last fetch attempt, successful or not
+	triedAt time.Time
+}
+
+// NewKeys returns a cache of the key set at jwksURL, the Auth service's
+// /.well-known/jwks.json. The key set is public, so no credentials are sent.
+func NewKeys(jwksURL string) *Keys {
+	return &Keys{url: jwksURL, client: &http.Client{Timeout: DefaultTimeout}, now: time.Now}
+}
+
+// Key returns the public key with the ID kid, fetching the key set when it
+// is unknown or the cached one is older than KeysTTL.
+func (k *Keys) Key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := k.now()
+	if key, ok := k.keys[kid]; ok && now.Sub(k.fetchedAt) < KeysTTL {
+		return key, nil
+	}
+	if k.triedAt.IsZero() || now.Sub(k.triedAt) >= KeysMinRefresh {
+		k.triedAt = now
+		keys, err := k.fetch(ctx)
+		if err != nil {
+			// Keep trusting known keys while the Auth service is down
+			if key, ok := k.keys[kid]; ok {
+				return key, nil
+			}
+			return nil, err
+		}
+		k.keys = keys
+		k.fetchedAt = now
+	}
+
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, kid)
+	}
+	return key, nil
+}
+
+func (k *Keys) fetch(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch signing keys: unexpected status %d", resp.StatusCode)
+	}
+	var set auth.JSONWebKeySet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("fetch signing keys: %w", err)
+	}
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" || jwk.Kid == "" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: invalid modulus: %w", jwk.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: invalid exponent: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys, nil
+}
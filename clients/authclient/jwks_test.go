@@ -0,0 +1,87 @@
+package authclient
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestKeys(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kid atomic.Value
+	kid.Store("2026-04")
+	var fetches, down atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/jwks.json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		fetches.Add(1)
+		if down.Load() == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": kid.Load().(string),
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	keys := NewKeys(srv.URL + "/.well-known/jwks.json")
+	keys.now = func() time.Time { return now }
+
+	got, err := keys.Key(ctx, "2026-04")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(&key.PublicKey) {
+		t.Fatal("fetched key differs from the published one")
+	}
+	if _, err := keys.Key(ctx, "2026-04"); err != nil || fetches.Load() != 1 {
+		t.Fatalf("cached key refetched: err=%v fetches=%d", err, fetches.Load())
+	}
+
+	// Unknown key IDs refetch at most once per KeysMinRefresh
+	if _, err := keys.Key(ctx, "2026-10"); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey, got %v", err)
+	}
+	kid.Store("2026-10")
+	now = now.Add(KeysMinRefresh)
+	if _, err := keys.Key(ctx, "2026-10"); err != nil {
+		t.Fatalf("rotated key not picked up: %v", err)
+	}
+
+	// Known keys outlive an Auth outage
+	down.Store(1)
+	now = now.Add(KeysTTL)
+	if _, err := keys.Key(ctx, "2026-10"); err != nil {
+		t.Fatalf("known key dropped while the Auth service is down: %v", err)
+	}
+	if _, err := keys.Key(ctx, "2027-04"); err == nil {
+		t.Fatal("expected an error for an unknown key while the Auth service is down")
+	}
+	before := fetches.Load()
+	if _, err := keys.Key(ctx, "2026-10"); err != nil || fetches.Load() != before {
+		t.Fatalf("failed fetch retried at once: err=%v fetches=%d", err, fetches.Load()-before)
+	}
+}
@@ -0,0 +1,6 @@
+// Package authpb holds the protobuf definition of the Auth service's gRPC
+// API and the Go code generated from it (protoc with protoc-gen-go and
+// protoc-gen-go-grpc).
+package authpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative auth.proto
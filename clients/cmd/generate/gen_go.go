@@ -0,0 +1,472 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// goClient renders the Go package for the API. The output is formatted by
+// the caller.
+func (a *api) goClient() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated by clients/cmd/generate from %s. DO NOT EDIT.\n\n", specPath(a.svc))
+	fmt.Fprintf(&b, "// Package %s is a client for the %s.\n", a.svc.Name, a.doc.Info.Title)
+	fmt.Fprintf(&b, "package %s\n\n", a.svc.Name)
+
+	imports := []string{"bytes", "context", "encoding/json", "fmt", "io", "net/http", "net/url", "strings"}
+	if a.usesStrconv() {
+		imports = append(imports, "strconv")
+	}
+	sort.Strings(imports)
+	b.WriteString("import (\n")
+	for _, imp := range imports {
+		fmt.Fprintf(&b, "\t%q\n", imp)
+	}
+	b.WriteString(")\n\n")
+
+	fmt.Fprintf(&b, "// APIVersion is the version of the API description the client was generated from.\nconst APIVersion = %q\n\n", a.doc.Info.Version)
+	b.WriteString(goRuntime)
+
+	for _, component := range sortedByValue(a.typeNames) {
+		a.goType(&b, a.typeNames[component], component, a.doc.Components.Schemas[component])
+	}
+	for _, e := range a.endpoints {
+		if name, ok := a.inline[e.BodySchema]; ok {
+			a.goType(&b, name, "the request body of "+e.Name, e.BodySchema)
+		}
+	}
+	for _, e := range a.endpoints {
+		a.goParams(&b, e)
+	}
+	for _, e := range a.endpoints {
+		a.goMethod(&b, e)
+	}
+	return b.String()
+}
+
+func specPath(svc service) string {
+	return "service/" + strings.ToUpper(svc.Name[:1]) + svc.Name[1:] + "/docs/openapi.json"
+}
+
+func sortedByValue(m map[string]string) []string {
+	keys := sortedKeys(m)
+	sort.SliceStable(keys, func(i, j int) bool { return m[keys[i]] < m[keys[j]] })
+	return keys
+}
+
+func (a *api) usesStrconv() bool {
+	for _, e := range a.endpoints {
+		for _, p := range append(append([]*parameter{}, e.PathParams...), e.QueryParams...) {
+			if p.Schema != nil && (p.Schema.Type == "integer" || p.Schema.Type == "boolean" || p.Schema.Type == "number") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (a *api) goType(b *strings.Builder, name, origin string, s *schema) {
+	if s.Description != "" {
+		b.WriteString(comment("", s.Description))
+	} else {
+		fmt.Fprintf(b, "// %s is generated from %s.\n", name, origin)
+	}
+
+	if len(s.Enum) > 0 {
+		fmt.Fprintf(b, "type %s string\n\nconst (\n", name)
+		for i, v := range s.Enum {
+			constName := name + pascal(fmt.Sprint(v))
+			if i < len(s.EnumVarNames) {
+				constName = name + strings.TrimPrefix(s.EnumVarNames[i], name)
+			}
+			fmt.Fprintf(b, "\t%s %s = %q\n", constName, name, fmt.Sprint(v))
+		}
+		b.WriteString(")\n\n")
+		return
+	}
+
+	if s.Properties == nil {
+		fmt.Fprintf(b, "type %s = %s\n\n", name, a.goExpr(s, false))
+		return
+	}
+
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	fmt.Fprintf(b, "type %s struct {\n", name)
+	for _, prop := range sortedKeys(s.Properties) {
+		ps := s.Properties[prop]
+		if ps.Description != "" {
+			b.WriteString(comment("\t", ps.Description))
+		}
+		tag := prop
+		if !required[prop] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(b, "\t%s %s `json:%q`\n", pascal(prop), a.goExpr(ps, true), tag)
+	}
+	b.WriteString("}\n\n")
+}
+
+// goExpr is the Go type of a schema. Object references are pointers in
+// struct fields so optional objects can be left out.
+func (a *api) goExpr(s *schema, field bool) string {
+	if s == nil {
+		return "json.RawMessage"
+	}
+	if s.Ref != "" {
+		name := a.refName(s.Ref)
+		target := a.doc.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+		if field && target != nil && len(target.Enum) == 0 && target.Properties != nil {
+			return "*" + name
+		}
+		return name
+	}
+	if name, ok := a.inline[s]; ok {
+		if field {
+			return "*" + name
+		}
+		return name
+	}
+	switch s.Type {
+	case "integer":
+		if s.Format == "int64" {
+			return "int64"
+		}
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "string":
+		if s.Format == "binary" {
+			return "[]byte"
+		}
+		return "string"
+	case "array":
+		return "[]" + a.goExpr(s.Items, false)
+	case "object":
+		if s.Properties != nil {
+			return a.goInlineStruct(s)
+		}
+		if values, ok := s.mapValues(); ok && values != nil {
+			return "map[string]" + a.goExpr(values, false)
+		}
+		return "map[string]interface{}"
+	}
+	return "json.RawMessage"
+}
+
+func (a *api) goInlineStruct(s *schema) string {
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	for _, prop := range sortedKeys(s.Properties) {
+		tag := prop
+		if !required[prop] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&b, "%s %s `json:%q`\n", pascal(prop), a.goExpr(s.Properties[prop], true), tag)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func (a *api) goParams(b *strings.Builder, e *endpoint) {
+	if len(e.QueryParams) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "// %sParams are the query parameters of %s. Zero values are not sent.\n", e.Name, e.Name)
+	fmt.Fprintf(b, "type %sParams struct {\n", e.Name)
+	for _, p := range e.QueryParams {
+		doc := p.Description
+		if p.Schema != nil && p.Schema.Default != nil {
+			doc = strings.TrimSuffix(doc, ".") + fmt.Sprintf(" (server default %v)", p.Schema.Default)
+		}
+		if doc != "" {
+			b.WriteString(comment("\t", doc))
+		}
+		fmt.Fprintf(b, "\t%s %s\n", pascal(p.Name), a.goExpr(p.Schema, false))
+	}
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(b, "func (p *%sParams) values() url.Values {\n\tq := url.Values{}\n\tif p == nil {\n\t\treturn q\n\t}\n", e.Name)
+	for _, p := range e.QueryParams {
+		field := "p." + pascal(p.Name)
+		switch a.goExpr(p.Schema, false) {
+		case "int":
+			fmt.Fprintf(b, "\tif %s != 0 {\n\t\tq.Set(%q, strconv.Itoa(%s))\n\t}\n", field, p.Name, field)
+		case "float64":
+			fmt.Fprintf(b, "\tif %s != 0 {\n\t\tq.Set(%q, strconv.FormatFloat(%s, 'f', -1, 64))\n\t}\n", field, p.Name, field)
+		case "bool":
+			fmt.Fprintf(b, "\tif %s {\n\t\tq.Set(%q, \"true\")\n\t}\n", field, p.Name)
+		case "[]string":
+			fmt.Fprintf(b, "\tif len(%s) > 0 {\n\t\tq.Set(%q, strings.Join(%s, \",\"))\n\t}\n", field, p.Name, field)
+		default:
+			fmt.Fprintf(b, "\tif %s != \"\" {\n\t\tq.Set(%q, %s)\n\t}\n", field, p.Name, field)
+		}
+	}
+	b.WriteString("\treturn q\n}\n\n")
+}
+
+func (a *api) goMethod(b *strings.Builder, e *endpoint) {
+	args := []string{"ctx context.Context"}
+	vars := map[string]string{}
+	for _, p := range e.PathParams {
+		v := goIdent(camel(p.Name))
+		vars[p.Name] = v
+		args = append(args, v+" "+a.goExpr(p.Schema, false))
+	}
+	query := "nil"
+	if len(e.QueryParams) > 0 {
+		args = append(args, fmt.Sprintf("params *%sParams", e.Name))
+		query = "params.values()"
+	}
+	switch e.Body {
+	case bodyJSON:
+		args = append(args, "body "+a.goExpr(e.BodySchema, true))
+	case bodyForm:
+		args = append(args, "form url.Values")
+	case bodyMultipart:
+		args = append(args, "body io.Reader", "contentType string")
+	}
+
+	result, zero := "", ""
+	switch {
+	case e.Result == nil:
+	case e.Binary:
+		result, zero = "[]byte", "nil"
+	default:
+		result = a.goExpr(e.Result, true)
+		zero = "nil"
+		if result == "string" {
+			zero = `""`
+		}
+	}
+
+	doc := e.Doc
+	if e.Body == bodyForm || e.Body == bodyMultipart {
+		doc += " " + formFields(e)
+	}
+	fmt.Fprintf(b, "// %s calls %s %s.\n//\n", e.Name, e.Method, e.Path)
+	b.WriteString(comment("", doc))
+
+	returns := "error"
+	if result != "" {
+		returns = "(" + result + ", error)"
+	}
+	fmt.Fprintf(b, "func (c *Client) %s(%s) %s {\n", e.Name, strings.Join(args, ", "), returns)
+	fmt.Fprintf(b, "\tpath := %s\n", goPath(e.Path, e.PathParams, vars, a))
+
+	out := "nil"
+	if result != "" {
+		fmt.Fprintf(b, "\tvar out %s\n", strings.TrimPrefix(result, "*"))
+		out = "&out"
+	}
+	switch e.Body {
+	case bodyJSON:
+		fmt.Fprintf(b, "\terr := c.doJSON(ctx, %q, path, %s, body, %s)\n", e.Method, query, out)
+	case bodyForm:
+		fmt.Fprintf(b, "\terr := c.do(ctx, %q, path, %s, strings.NewReader(form.Encode()), \"application/x-www-form-urlencoded\", %s)\n", e.Method, query, out)
+	case bodyMultipart:
+		fmt.Fprintf(b, "\terr := c.do(ctx, %q, path, %s, body, contentType, %s)\n", e.Method, query, out)
+	default:
+		fmt.Fprintf(b, "\terr := c.do(ctx, %q, path, %s, nil, \"\", %s)\n", e.Method, query, out)
+	}
+
+	switch {
+	case result == "":
+		b.WriteString("\treturn err\n}\n\n")
+	case strings.HasPrefix(result, "*"):
+		fmt.Fprintf(b, "\tif err != nil {\n\t\treturn nil, err\n\t}\n\treturn &out, nil\n}\n\n")
+	default:
+		fmt.Fprintf(b, "\tif err != nil {\n\t\treturn %s, err\n\t}\n\treturn out, nil\n}\n\n", zero)
+	}
+}
+
+// formFields documents the fields of a form body, which the generated
+// method takes already encoded.
+func formFields(e *endpoint) string {
+	if e.BodySchema == nil || len(e.BodySchema.Properties) == 0 {
+		return ""
+	}
+	required := map[string]bool{}
+	for _, r := range e.BodySchema.Required {
+		required[r] = true
+	}
+	var fields []string
+	for _, name := range sortedKeys(e.BodySchema.Properties) {
+		f := name
+		if e.BodySchema.Properties[name].Format == "binary" {
+			f += " (file"
+			if required[name] {
+				f += ", required"
+			}
+			f += ")"
+		} else if required[name] {
+			f += " (required)"
+		}
+		fields = append(fields, f)
+	}
+	kind := "form"
+	if e.Body == bodyMultipart {
+		kind = "multipart form"
+	}
+	return "The " + kind + " has the fields " + strings.Join(fields, ", ") + "."
+}
+
+func goPath(path string, params []*parameter, vars map[string]string, a *api) string {
+	parts := []string{}
+	rest := path
+	for rest != "" {
+		open := strings.Index(rest, "{")
+		if open < 0 {
+			parts = append(parts, strconv.Quote(rest))
+			break
+		}
+		if open > 0 {
+			parts = append(parts, strconv.Quote(rest[:open]))
+		}
+		end := strings.Index(rest, "}")
+		name := rest[open+1 : end]
+		v := vars[name]
+		for _, p := range params {
+			if p.Name == name && a.goExpr(p.Schema, false) == "int" {
+				v = "strconv.Itoa(" + v + ")"
+			}
+		}
+		parts = append(parts, "url.PathEscape("+v+")")
+		rest = rest[end+1:]
+	}
+	return strings.Join(parts, " + ")
+}
+
+// goIdent avoids parameter names that are keywords or taken by the
+// generated method.
+func goIdent(name string) string {
+	switch {
+	case token.IsKeyword(name), name == "ctx", name == "params", name == "body", name == "form", name == "path", name == "out", name == "err", name == "c":
+		return name + "Param"
+	}
+	return name
+}
+
+// goRuntime is the part of every Go client that does not depend on the
+// API description.
+const goRuntime = `// Client calls the API over HTTP. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests. It defaults to
+// http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithToken sends token as a bearer access token with every request.
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// NewClient returns a client for the API at baseURL, e.g.
+// "http://localhost:8080".
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{baseURL: strings.TrimRight(baseURL, "/"), httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Error is returned for responses outside the 2xx range.
+type Error struct {
+	Method     string
+	Path       string
+	StatusCode int
+	// Response is the decoded error envelope, or nil when the body is not one.
+	Response *ErrorResponse
+	Body     []byte
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s %s: %d %s: %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode), strings.TrimSpace(string(e.Body)))
+}
+
+func (c *Client) doJSON(ctx context.Context, method, path string, query url.Values, in, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("failed to encode request body: %w", err)
+	}
+	return c.do(ctx, method, path, query, bytes.NewReader(data), "application/json", out)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body io.Reader, contentType string, out interface{}) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if _, raw := out.(*[]byte); raw {
+		req.Header.Set("Accept", "*/*")
+	} else {
+		req.Header.Set("Accept", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &Error{Method: method, Path: path, StatusCode: resp.StatusCode, Body: data}
+		var envelope ErrorResponse
+		if json.Unmarshal(data, &envelope) == nil {
+			apiErr.Response = &envelope
+		}
+		return apiErr
+	}
+
+	switch out := out.(type) {
+	case nil:
+		return nil
+	case *[]byte:
+		*out = data
+		return nil
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+	return nil
+}
+
+`
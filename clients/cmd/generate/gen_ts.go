@@ -0,0 +1,317 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// tsClient renders the TypeScript module for the API.
+func (a *api) tsClient() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated by clients/cmd/generate from %s. DO NOT EDIT.\n\n", specPath(a.svc))
+	b.WriteString("import { BaseClient, type ClientOptions } from \"./runtime.js\";\n\n")
+	fmt.Fprintf(&b, "/** Version of the API description the client was generated from. */\nexport const API_VERSION = %q;\n\n", a.doc.Info.Version)
+
+	for _, component := range sortedByValue(a.typeNames) {
+		a.tsType(&b, a.typeNames[component], a.doc.Components.Schemas[component])
+	}
+	for _, e := range a.endpoints {
+		if name, ok := a.inline[e.BodySchema]; ok {
+			a.tsType(&b, name, e.BodySchema)
+		}
+		if e.Body == bodyForm {
+			a.tsType(&b, e.Name+"Form", e.BodySchema)
+		}
+		if len(e.QueryParams) > 0 {
+			a.tsParams(&b, e)
+		}
+	}
+
+	fmt.Fprintf(&b, "/** Client for the %s. */\n", a.doc.Info.Title)
+	fmt.Fprintf(&b, "export class %s extends BaseClient {\n", a.svc.TSClass)
+	b.WriteString("  constructor(baseUrl: string, options: ClientOptions = {}) {\n    super(baseUrl, options);\n  }\n")
+	for _, e := range a.endpoints {
+		a.tsMethod(&b, e)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func (a *api) tsType(b *strings.Builder, name string, s *schema) {
+	if s.Description != "" {
+		fmt.Fprintf(b, "/** %s */\n", jsdoc(s.Description))
+	}
+	if len(s.Enum) > 0 {
+		values := make([]string, len(s.Enum))
+		for i, v := range s.Enum {
+			values[i] = fmt.Sprintf("%q", fmt.Sprint(v))
+		}
+		fmt.Fprintf(b, "export type %s = %s;\n\n", name, strings.Join(values, " | "))
+		return
+	}
+	if s.Properties == nil {
+		fmt.Fprintf(b, "export type %s = %s;\n\n", name, a.tsExpr(s))
+		return
+	}
+	fmt.Fprintf(b, "export interface %s %s\n\n", name, a.tsObject(s, "  "))
+}
+
+func (a *api) tsObject(s *schema, indent string) string {
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, prop := range sortedKeys(s.Properties) {
+		ps := s.Properties[prop]
+		if ps.Description != "" {
+			fmt.Fprintf(&b, "%s/** %s */\n", indent, jsdoc(ps.Description))
+		}
+		optional := "?"
+		if required[prop] {
+			optional = ""
+		}
+		fmt.Fprintf(&b, "%s%s%s: %s;\n", indent, tsKey(prop), optional, a.tsExpr(ps))
+	}
+	b.WriteString(strings.TrimSuffix(indent, "  ") + "}")
+	return b.String()
+}
+
+func (a *api) tsExpr(s *schema) string {
+	if s == nil {
+		return "unknown"
+	}
+	if s.Ref != "" {
+		return a.refName(s.Ref)
+	}
+	if name, ok := a.inline[s]; ok {
+		return name
+	}
+	switch s.Type {
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "string":
+		if s.Format == "binary" {
+			return "Blob"
+		}
+		return "string"
+	case "array":
+		item := a.tsExpr(s.Items)
+		if strings.Contains(item, " ") {
+			item = "(" + item + ")"
+		}
+		return item + "[]"
+	case "object":
+		if s.Properties != nil {
+			return a.tsObject(s, "    ")
+		}
+		if values, ok := s.mapValues(); ok && values != nil {
+			return "Record<string, " + a.tsExpr(values) + ">"
+		}
+		return "Record<string, unknown>"
+	}
+	return "unknown"
+}
+
+func (a *api) tsParams(b *strings.Builder, e *endpoint) {
+	fmt.Fprintf(b, "/** Query parameters of %s. */\n", camel(e.Name))
+	fmt.Fprintf(b, "export interface %sParams {\n", e.Name)
+	for _, p := range e.QueryParams {
+		doc := p.Description
+		if p.Schema != nil && p.Schema.Default != nil {
+			doc = strings.TrimSuffix(doc, ".") + fmt.Sprintf(" (server default %v)", p.Schema.Default)
+		}
+		if doc = strings.TrimSpace(doc); doc != "" {
+			fmt.Fprintf(b, "  /** %s */\n", jsdoc(doc))
+		}
+		fmt.Fprintf(b, "  %s?: %s;\n", tsKey(p.Name), a.tsExpr(p.Schema))
+	}
+	b.WriteString("}\n\n")
+}
+
+func (a *api) tsMethod(b *strings.Builder, e *endpoint) {
+	var args []string
+	vars := map[string]string{}
+	for _, p := range e.PathParams {
+		v := tsIdent(camel(p.Name))
+		vars[p.Name] = v
+		args = append(args, v+": "+a.tsExpr(p.Schema))
+	}
+	init := []string{}
+	switch e.Body {
+	case bodyJSON:
+		args = append(args, "body: "+a.tsExpr(e.BodySchema))
+		init = append(init, "json: body")
+	case bodyForm:
+		args = append(args, "form: "+e.Name+"Form")
+		init = append(init, "form")
+	case bodyMultipart:
+		args = append(args, "form: FormData")
+		init = append(init, "body: form")
+	}
+	if len(e.QueryParams) > 0 {
+		args = append(args, "params: "+e.Name+"Params = {}")
+		init = append([]string{"query: { ...params }"}, init...)
+	}
+
+	result := "void"
+	switch {
+	case e.Result == nil:
+	case e.Binary:
+		result = "Blob"
+		init = append(init, "raw: true")
+	default:
+		result = a.tsExpr(e.Result)
+	}
+
+	doc := e.Doc
+	if e.Body == bodyMultipart {
+		doc += " " + formFields(e)
+	}
+	fmt.Fprintf(b, "\n  /**\n   * %s\n   *\n   * `%s %s`\n   */\n", jsdoc(doc), e.Method, e.Path)
+	fmt.Fprintf(b, "  %s(%s): Promise<%s> {\n", camel(e.Name), strings.Join(args, ", "), result)
+
+	path := e.Path
+	for name, v := range vars {
+		path = strings.ReplaceAll(path, "{"+name+"}", "${encodeURIComponent(String("+v+"))}")
+	}
+	initArg := ""
+	if len(init) > 0 {
+		initArg = ", { " + strings.Join(init, ", ") + " }"
+	}
+	fmt.Fprintf(b, "    return this.request<%s>(%q, `%s`%s);\n  }\n", result, e.Method, path, initArg)
+}
+
+var tsReserved = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"default": true, "delete": true, "do": true, "else": true, "enum": true, "export": true,
+	"extends": true, "false": true, "finally": true, "for": true, "function": true, "if": true,
+	"import": true, "in": true, "instanceof": true, "new": true, "null": true, "return": true,
+	"super": true, "switch": true, "this": true, "throw": true, "true": true, "try": true,
+	"typeof": true, "var": true, "void": true, "while": true, "with": true,
+	"body": true, "form": true, "params": true,
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsKey quotes property names that are not identifiers.
+func tsKey(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	return fmt.Sprintf("%q", name)
+}
+
+// jsdoc keeps text from closing the comment it is written into.
+func jsdoc(text string) string {
+	return strings.ReplaceAll(text, "*/", "*\\/")
+}
+
+func tsIdent(name string) string {
+	if tsReserved[name] {
+		return name + "Param"
+	}
+	return name
+}
+
+// tsRuntime is the request plumbing shared by the TypeScript clients.
+const tsRuntime = `// Code generated by clients/cmd/generate. DO NOT EDIT.
+
+export interface ClientOptions {
+  /** Bearer access token sent with every request. */
+  token?: string;
+  /** fetch implementation; defaults to the global fetch. */
+  fetch?: typeof fetch;
+}
+
+/** Thrown for responses outside the 2xx range. */
+export class ApiError extends Error {
+  constructor(
+    readonly method: string,
+    readonly path: string,
+    readonly status: number,
+    /** The decoded error envelope, or the raw text when it is not JSON. */
+    readonly body: unknown,
+  ) {
+    super(` + "`${method} ${path}: ${status}`" + `);
+    this.name = "ApiError";
+  }
+}
+
+export type Query = Record<string, string | number | boolean | undefined>;
+
+export interface RequestOptions {
+  query?: Query;
+  json?: unknown;
+  /** Sent as application/x-www-form-urlencoded; undefined fields are left out. */
+  form?: object;
+  body?: BodyInit;
+  /** Return the response body as a Blob instead of decoding JSON. */
+  raw?: boolean;
+}
+
+export class BaseClient {
+  private readonly baseUrl: string;
+  private readonly options: ClientOptions;
+
+  constructor(baseUrl: string, options: ClientOptions = {}) {
+    this.baseUrl = baseUrl.replace(/\/+$/, "");
+    this.options = options;
+  }
+
+  protected async request<T>(method: string, path: string, init: RequestOptions = {}): Promise<T> {
+    let url = this.baseUrl + path;
+    const query = encode(init.query ?? {}).toString();
+    if (query) {
+      url += "?" + query;
+    }
+
+    const headers: Record<string, string> = { Accept: init.raw ? "*/*" : "application/json" };
+    let body = init.body;
+    if (init.json !== undefined) {
+      headers["Content-Type"] = "application/json";
+      body = JSON.stringify(init.json);
+    } else if (init.form !== undefined) {
+      body = encode(init.form);
+    }
+    if (this.options.token) {
+      headers.Authorization = ` + "`Bearer ${this.options.token}`" + `;
+    }
+
+    // Called through a local so browsers do not reject fetch for being
+    // invoked on the options object.
+    const doFetch = this.options.fetch ?? fetch;
+    const res = await doFetch(url, { method, headers, body });
+    if (!res.ok) {
+      const text = await res.text();
+      let parsed: unknown = text;
+      try {
+        parsed = JSON.parse(text);
+      } catch {
+        // Keep the raw text.
+      }
+      throw new ApiError(method, path, res.status, parsed);
+    }
+
+    if (init.raw) {
+      return (await res.blob()) as T;
+    }
+    const text = await res.text();
+    return (text ? JSON.parse(text) : undefined) as T;
+  }
+}
+
+function encode(values: object): URLSearchParams {
+  const out = new URLSearchParams();
+  for (const [key, value] of Object.entries(values)) {
+    if (value !== undefined && value !== null) {
+      out.set(key, String(value));
+    }
+  }
+  return out;
+}
+`
@@ -0,0 +1,412 @@
+// Command generate writes the Go and TypeScript API clients from the OpenAPI
+// documents the services commit under service/<name>/docs/openapi.json.
+//
+// Run it from the clients directory after regenerating the documents:
+//
+//	go run ./cmd/generate
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// service is an API the clients are generated for.
+type service struct {
+	Name    string // Go package name, e.g. "market"
+	Spec    string // path of the OpenAPI document
+	TSClass string // TypeScript client class, e.g. "MarketClient"
+}
+
+func main() {
+	root := flag.String("root", "..", "repository root")
+	out := flag.String("out", ".", "clients directory")
+	flag.Parse()
+
+	files, err := generate(*root)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, name := range sortedKeys(files) {
+		path := filepath.Join(*out, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(path, files[name], 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// generate returns the client sources keyed by their path in the clients
+// directory.
+func generate(root string) (map[string][]byte, error) {
+	services := []service{
+		{Name: "auth", Spec: filepath.Join(root, "service", "Auth", "docs", "openapi.json"), TSClass: "AuthClient"},
+		{Name: "market", Spec: filepath.Join(root, "service", "Market", "docs", "openapi.json"), TSClass: "MarketClient"},
+	}
+
+	files := map[string][]byte{
+		filepath.Join("ts", "src", "runtime.ts"): []byte(tsRuntime),
+	}
+	for _, svc := range services {
+		api, err := load(svc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", svc.Name, err)
+		}
+
+		goSrc, err := format.Source([]byte(api.goClient()))
+		if err != nil {
+			return nil, fmt.Errorf("%s: generated Go does not compile: %w", svc.Name, err)
+		}
+		files[filepath.Join(svc.Name, "client.gen.go")] = goSrc
+		files[filepath.Join("ts", "src", svc.Name+".ts")] = []byte(api.tsClient())
+	}
+	return files, nil
+}
+
+// schema is the subset of an OpenAPI schema the services emit.
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Description          string             `json:"description"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *schema            `json:"items"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Enum                 []interface{}      `json:"enum"`
+	EnumVarNames         []string           `json:"x-enum-varnames"`
+	Default              interface{}        `json:"default"`
+}
+
+// mapValues reports whether s is a map and the schema of its values, which
+// is nil for "additionalProperties": true.
+func (s *schema) mapValues() (*schema, bool) {
+	if len(s.AdditionalProperties) == 0 || string(s.AdditionalProperties) == "false" {
+		return nil, false
+	}
+	values := &schema{}
+	if err := json.Unmarshal(s.AdditionalProperties, values); err != nil {
+		return nil, true
+	}
+	return values, true
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description"`
+	Required    bool    `json:"required"`
+	Schema      *schema `json:"schema"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*mediaType `json:"content"`
+}
+
+type response struct {
+	Description string                `json:"description"`
+	Content     map[string]*mediaType `json:"content"`
+}
+
+type operation struct {
+	Summary     string               `json:"summary"`
+	Description string               `json:"description"`
+	Parameters  []*parameter         `json:"parameters"`
+	RequestBody *requestBody         `json:"requestBody"`
+	Responses   map[string]*response `json:"responses"`
+}
+
+type document struct {
+	Info struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	} `json:"info"`
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components struct {
+		Schemas map[string]*schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// bodyKind is how an operation sends its request body.
+type bodyKind int
+
+const (
+	bodyNone bodyKind = iota
+	bodyJSON
+	bodyForm
+	bodyMultipart
+)
+
+// endpoint is an operation ready for code generation.
+type endpoint struct {
+	Name        string
+	Method      string
+	Path        string
+	Doc         string
+	PathParams  []*parameter
+	QueryParams []*parameter
+	Body        bodyKind
+	BodySchema  *schema // JSON body, or the fields of a form
+	Result      *schema // nil when the response has no body
+	Binary      bool    // the response is a file
+}
+
+type api struct {
+	svc       service
+	doc       *document
+	typeNames map[string]string // component name to generated type name
+	endpoints []*endpoint
+	inline    map[*schema]string // request bodies declared in the operation
+}
+
+var methodOrder = []string{"get", "post", "put", "patch", "delete"}
+
+func load(svc service) (*api, error) {
+	raw, err := os.ReadFile(svc.Spec)
+	if err != nil {
+		return nil, err
+	}
+	doc := &document{}
+	if err := json.Unmarshal(raw, doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", svc.Spec, err)
+	}
+
+	a := &api{svc: svc, doc: doc, typeNames: map[string]string{}}
+	for name := range doc.Components.Schemas {
+		a.typeNames[name] = typeName(name)
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		for _, method := range methodOrder {
+			op, ok := doc.Paths[path][method]
+			if !ok {
+				continue
+			}
+			a.endpoints = append(a.endpoints, newEndpoint(method, path, op))
+		}
+	}
+	if err := nameEndpoints(a.endpoints); err != nil {
+		return nil, err
+	}
+
+	// Bodies documented as an inline object get a named type so both
+	// languages can spell them.
+	a.inline = map[*schema]string{}
+	taken := map[string]bool{}
+	for _, name := range a.typeNames {
+		taken[name] = true
+	}
+	for _, e := range a.endpoints {
+		if e.Body != bodyJSON || e.BodySchema.Ref != "" || e.BodySchema.Properties == nil {
+			continue
+		}
+		name := e.Name + "Request"
+		if taken[name] {
+			name = e.Name + "Body"
+		}
+		if taken[name] {
+			return nil, fmt.Errorf("no free type name for the request body of %s", e.Name)
+		}
+		taken[name] = true
+		a.inline[e.BodySchema] = name
+	}
+	return a, nil
+}
+
+func newEndpoint(method, path string, op *operation) *endpoint {
+	e := &endpoint{Method: strings.ToUpper(method), Path: path, Name: pascal(parenthetical.ReplaceAllString(op.Summary, ""))}
+	e.Doc = sentence(op.Summary)
+	if op.Description != "" && op.Description != op.Summary {
+		e.Doc += " " + sentence(op.Description)
+	}
+
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path":
+			e.PathParams = append(e.PathParams, p)
+		case "query":
+			e.QueryParams = append(e.QueryParams, p)
+		}
+	}
+	sort.SliceStable(e.PathParams, func(i, j int) bool {
+		return strings.Index(path, "{"+e.PathParams[i].Name+"}") < strings.Index(path, "{"+e.PathParams[j].Name+"}")
+	})
+
+	if op.RequestBody != nil {
+		switch {
+		case op.RequestBody.Content["application/json"] != nil:
+			e.Body, e.BodySchema = bodyJSON, op.RequestBody.Content["application/json"].Schema
+		case op.RequestBody.Content["multipart/form-data"] != nil:
+			e.Body, e.BodySchema = bodyMultipart, op.RequestBody.Content["multipart/form-data"].Schema
+		case op.RequestBody.Content["application/x-www-form-urlencoded"] != nil:
+			e.Body, e.BodySchema = bodyForm, op.RequestBody.Content["application/x-www-form-urlencoded"].Schema
+		}
+	}
+
+	for _, code := range []string{"200", "201", "202", "204"} {
+		r, ok := op.Responses[code]
+		if !ok {
+			continue
+		}
+		mediaTypes := sortedKeys(r.Content)
+		sort.SliceStable(mediaTypes, func(i, j int) bool { return mediaTypes[i] == "application/json" })
+		for _, mt := range mediaTypes {
+			if s := r.Content[mt].Schema; s != nil {
+				e.Result = s
+				e.Binary = s.Type == "string" && s.Format == "binary"
+				break
+			}
+		}
+		break
+	}
+	return e
+}
+
+// audiences prefix operations that are mounted for several roles, e.g.
+// AdminGetSupportTicket and GetSupportTicket.
+var audiences = map[string]bool{"admin": true, "seller": true, "courier": true, "user": true}
+
+func nameEndpoints(endpoints []*endpoint) error {
+	count := map[string]int{}
+	for _, e := range endpoints {
+		count[e.Name]++
+	}
+	seen := map[string]bool{}
+	for _, e := range endpoints {
+		if count[e.Name] > 1 {
+			segments := strings.Split(strings.Trim(e.Path, "/"), "/")
+			if len(segments) > 1 && segments[0] == "api" && audiences[segments[1]] {
+				e.Name = pascal(segments[1]) + e.Name
+			}
+		}
+		if seen[e.Name] {
+			return fmt.Errorf("two operations are named %s; give one of them a distinct @Summary", e.Name)
+		}
+		seen[e.Name] = true
+	}
+	return nil
+}
+
+// typeName drops the Go package of models and controllers types and keeps
+// it for the rest, e.g. models.Category -> Category, settings.Entry ->
+// SettingsEntry.
+func typeName(component string) string {
+	pkg, name, ok := strings.Cut(component, ".")
+	if !ok {
+		return pascal(component)
+	}
+	if pkg == "models" || pkg == "controllers" {
+		return pascal(name)
+	}
+	return pascal(pkg) + pascal(name)
+}
+
+var (
+	wordSplit     = regexp.MustCompile(`[^A-Za-z0-9]+`)
+	parenthetical = regexp.MustCompile(`\([^)]*\)`)
+)
+
+// commonInitialisms are written in upper case in Go identifiers.
+var commonInitialisms = map[string]bool{
+	"api": true, "id": true, "ids": true, "url": true, "qr": true, "ip": true,
+	"json": true, "csv": true, "pdf": true, "http": true, "jwks": true, "cod": true,
+}
+
+// articles are dropped from names built from summaries, so "Share a
+// product" becomes ShareProduct.
+var articles = map[string]bool{"a": true, "an": true, "the": true}
+
+func pascal(s string) string {
+	var b strings.Builder
+	for _, w := range wordSplit.Split(strings.ReplaceAll(strings.ReplaceAll(s, "'s", ""), "'", ""), -1) {
+		if w == "" || articles[w] {
+			continue
+		}
+		if commonInitialisms[strings.ToLower(w)] {
+			b.WriteString(strings.ToUpper(w))
+			continue
+		}
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return b.String()
+}
+
+// sentence capitalizes s and ends it with a period.
+func sentence(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return s
+	}
+	s = strings.ToUpper(s[:1]) + s[1:]
+	if !strings.HasSuffix(s, ".") {
+		s += "."
+	}
+	return s
+}
+
+func camel(s string) string {
+	p := pascal(s)
+	for i, r := range p {
+		if r < 'A' || r > 'Z' {
+			if i > 1 {
+				i--
+			}
+			if i == 0 {
+				i = 1
+			}
+			return strings.ToLower(p[:i]) + p[i:]
+		}
+	}
+	return strings.ToLower(p)
+}
+
+func (a *api) refName(ref string) string {
+	return a.typeNames[strings.TrimPrefix(ref, "#/components/schemas/")]
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// comment wraps text into // lines of about 80 columns.
+func comment(indent, text string) string {
+	var b strings.Builder
+	line := indent + "//"
+	for _, word := range strings.Fields(text) {
+		if len(line)+1+len(word) > 80 && line != indent+"//" {
+			b.WriteString(line + "\n")
+			line = indent + "//"
+		}
+		line += " " + word
+	}
+	if line != indent+"//" {
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestGeneratedUpToDate fails when the committed clients no longer match the
+// services' OpenAPI documents. Regenerate them with go generate ./...
+func TestGeneratedUpToDate(t *testing.T) {
+	files, err := generate(filepath.Join("..", "..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join("..", "..", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is out of date; run go generate ./... in clients", name)
+		}
+	}
+}
+
+func TestNames(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{pascal("Change a seller's API plan"), "ChangeSellerAPIPlan"},
+		{pascal("Get category by id"), "GetCategoryByID"},
+		{pascal("user_id"), "UserID"},
+		{camel("GetProductByID"), "getProductByID"},
+		{camel("APIKeys"), "apiKeys"},
+		{camel("ID"), "id"},
+		{typeName("models.Category"), "Category"},
+		{typeName("controllers.HealthResponse"), "HealthResponse"},
+		{typeName("settings.Entry"), "SettingsEntry"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestNameEndpoints(t *testing.T) {
+	endpoints := []*endpoint{
+		{Name: "GetDeliveryProofs", Path: "/api/admin/orders/{id}/proofs"},
+		{Name: "GetDeliveryProofs", Path: "/api/user/orders/{id}/proofs"},
+		{Name: "HealthCheck", Path: "/health"},
+	}
+	if err := nameEndpoints(endpoints); err != nil {
+		t.Fatal(err)
+	}
+	if endpoints[0].Name != "AdminGetDeliveryProofs" || endpoints[1].Name != "UserGetDeliveryProofs" || endpoints[2].Name != "HealthCheck" {
+		t.Fatalf("unexpected names %s, %s, %s", endpoints[0].Name, endpoints[1].Name, endpoints[2].Name)
+	}
+
+	clash := []*endpoint{{Name: "List", Path: "/a"}, {Name: "List", Path: "/b"}}
+	if err := nameEndpoints(clash); err == nil {
+		t.Fatal("expected an error for operations with the same name")
+	}
+}
@@ -0,0 +1,7 @@
+// Package clients holds the Go and TypeScript clients for the Marketback
+// APIs. The auth and market packages and ts/src are generated from the
+// services' OpenAPI documents, authpb from the Auth service's protobuf
+// definition; authclient is written by hand.
+package clients
+
+//go:generate go run ./cmd/generate
@@ -0,0 +1,736 @@
+// Code generated by clients/cmd/generate from service/Auth/docs/openapi.json. DO NOT EDIT.
+
+import { BaseClient, type ClientOptions } from "./runtime.js";
+
+/** Version of the API description the client was generated from. */
+export const API_VERSION = "1.0";
+
+export interface BuildinfoInfo {
+  build_date?: string;
+  commit?: string;
+  go_version?: string;
+  platform?: string;
+  version?: string;
+}
+
+export interface CreateRoleRequestRequest {
+  message?: string;
+  role: string;
+}
+
+export interface CreateUserRequest {
+  email: string;
+  /** OTP is the acting admin's current two-factor code, required when
+Role is admin */
+  otp?: string;
+  password: string;
+  role: string;
+}
+
+export interface ErrorResponse {
+  /** Machine-readable reason, e.g. password_reset_required */
+  code?: string;
+  error: string;
+}
+
+export interface GoogleIdentityRequest {
+  id_token: string;
+}
+
+export interface Identity {
+  created_at?: string;
+  id?: number;
+  provider?: string;
+  subject?: string;
+  user_id?: number;
+}
+
+export interface IntrospectionResponse {
+  active?: boolean;
+  email?: string;
+  exp?: number;
+  iat?: number;
+  iss?: string;
+  jti?: string;
+  role?: string;
+  scope?: string;
+  sub?: string;
+  token_type?: string;
+  user_id?: number;
+}
+
+export interface JSONWebKey {
+  alg?: string;
+  e?: string;
+  kid?: string;
+  kty?: string;
+  n?: string;
+  use?: string;
+}
+
+export interface JSONWebKeySet {
+  keys?: JSONWebKey[];
+}
+
+export interface LoginAlertDenyRequest {
+  token: string;
+}
+
+export interface LoginRequest {
+  email: string;
+  password: string;
+}
+
+export interface MailerMessage {
+  body?: string;
+  sent_at?: string;
+  subject?: string;
+  to?: string;
+}
+
+export interface OpenIDConfiguration {
+  claims_supported?: string[];
+  id_token_signing_alg_values_supported?: string[];
+  introspection_endpoint?: string;
+  introspection_endpoint_auth_methods_supported?: string[];
+  issuer?: string;
+  jwks_uri?: string;
+  subject_types_supported?: string[];
+  userinfo_endpoint?: string;
+}
+
+export interface PasswordChangeRequest {
+  email: string;
+  new_password: string;
+  password: string;
+}
+
+export interface PasswordResetConfirmRequest {
+  password: string;
+  token: string;
+}
+
+export interface PasswordResetRequest {
+  email: string;
+}
+
+export interface PhoneCodeRequest {
+  phone: string;
+}
+
+export interface PhoneIdentityRequest {
+  code: string;
+  phone: string;
+}
+
+export interface RefreshRequest {
+  refresh_token: string;
+}
+
+export interface RegisterRequest {
+  email: string;
+  password: string;
+  /** Role may only be empty or "user"; privileged roles are requested
+through /api/role-requests and granted by an admin */
+  role?: string;
+}
+
+export interface ReviewRoleRequestRequest {
+  note?: string;
+}
+
+export interface RoleChange {
+  changed_by?: number;
+  created_at?: string;
+  id?: number;
+  new_role?: string;
+  old_role?: string;
+  reason?: string;
+  request_id?: number;
+  user_id?: number;
+}
+
+export interface RoleRequest {
+  created_at?: string;
+  email?: string;
+  id?: number;
+  message?: string;
+  review_note?: string;
+  reviewed_at?: string;
+  reviewed_by?: number;
+  role?: string;
+  status?: string;
+  user_id?: number;
+}
+
+export interface ScopedTokenRequest {
+  scope: string;
+}
+
+export interface ScopedTokenResponse {
+  access_token?: string;
+  expires_in?: number;
+  scope?: string;
+  token_type?: string;
+}
+
+export interface Session {
+  /** Current marks the session of the access token making the request */
+  current?: boolean;
+  expires_at?: string;
+  id?: string;
+  ip_address?: string;
+  /** LastUsedAt is when the session last signed in or refreshed its
+tokens */
+  last_used_at?: string;
+  user_agent?: string;
+}
+
+export interface TokenPair {
+  access_token?: string;
+  expires_in?: number;
+  refresh_token?: string;
+}
+
+export interface TwoFactorCodeRequest {
+  code: string;
+}
+
+export interface TwoFactorSetupResponse {
+  otpauth_uri?: string;
+  secret?: string;
+}
+
+export interface UpdateRoleRequest {
+  /** OTP is the acting admin's current two-factor code, required when
+Role is admin */
+  otp?: string;
+  reason?: string;
+  role: string;
+}
+
+export interface User {
+  created_at?: string;
+  email?: string;
+  /** EmailVerified is set once the link in the verification email was
+followed; accounts older than email verification count as verified */
+  email_verified?: boolean;
+  id?: number;
+  /** PasswordChangeRequired blocks sign-in with a temporary password set
+by an admin until the user chose their own */
+  password_change_required?: boolean;
+  /** PasswordResetRequired blocks password sign-in until the password is
+reset, e.g. after a sign-in was reported as not the user's */
+  password_reset_required?: boolean;
+  role?: string;
+  /** SessionsRevokedAt is when all sessions were last revoked; access
+tokens issued earlier are no longer active */
+  sessions_revoked_at?: string;
+  /** Suspended blocks every sign-in until an admin lifts it */
+  suspended?: boolean;
+  updated_at?: string;
+}
+
+export interface UserImportResult {
+  created?: number;
+  dry_run?: boolean;
+  failed?: number;
+  /** Invited is set when created users are emailed an invitation */
+  invited?: boolean;
+  rows?: UserImportRow[];
+  valid?: number;
+}
+
+export interface UserImportRow {
+  email?: string;
+  error?: string;
+  /** Line is the row's line in the file; the header is line 1 */
+  line?: number;
+  role?: string;
+  status?: string;
+  /** TemporaryPassword reports whether the row set a temporary password;
+the password itself is never returned */
+  temporary_password?: boolean;
+  user_id?: number;
+}
+
+/** Query parameters of roleChangeAuditTrail. */
+export interface RoleChangeAuditTrailParams {
+  /** Only changes of this user */
+  user_id?: number;
+  /** Limit (server default 10) */
+  limit?: number;
+  /** Offset (server default 0) */
+  offset?: number;
+}
+
+/** Query parameters of listRoleRequests. */
+export interface ListRoleRequestsParams {
+  /** pending, approved or rejected (default pending, all for every status) */
+  status?: string;
+  /** Limit (server default 10) */
+  limit?: number;
+  /** Offset (server default 0) */
+  offset?: number;
+}
+
+/** Query parameters of listAllUsers. */
+export interface ListAllUsersParams {
+  /** Limit (server default 10) */
+  limit?: number;
+  /** Offset (server default 0) */
+  offset?: number;
+}
+
+/** Query parameters of importUsersFromCSV. */
+export interface ImportUsersFromCSVParams {
+  /** Only validate the rows */
+  dry_run?: boolean;
+}
+
+/** Query parameters of listCapturedEmails. */
+export interface ListCapturedEmailsParams {
+  /** Only emails sent to this address */
+  to?: string;
+}
+
+export interface IntrospectTokenForm {
+  token: string;
+  token_type_hint?: string;
+}
+
+/** Query parameters of verifyEmailAddress. */
+export interface VerifyEmailAddressParams {
+  /** Token from the verification link */
+  token?: string;
+}
+
+/** Client for the Auth Service API. */
+export class AuthClient extends BaseClient {
+  constructor(baseUrl: string, options: ClientOptions = {}) {
+    super(baseUrl, options);
+  }
+
+  /**
+   * JSON Web Key Set. Public keys of the RS256 access tokens, the one signing new tokens first; keys retired by a rotation stay listed until tokens they signed have expired. Empty while tokens are signed with the shared HMAC secret, which is never published.
+   *
+   * `GET /.well-known/jwks.json`
+   */
+  jsonWebKeySet(): Promise<JSONWebKeySet> {
+    return this.request<JSONWebKeySet>("GET", `/.well-known/jwks.json`);
+  }
+
+  /**
+   * OpenID Connect discovery.
+   *
+   * `GET /.well-known/openid-configuration`
+   */
+  openIDConnectDiscovery(): Promise<OpenIDConfiguration> {
+    return this.request<OpenIDConfiguration>("GET", `/.well-known/openid-configuration`);
+  }
+
+  /**
+   * Role change audit trail (Admin only).
+   *
+   * `GET /admin/role-changes`
+   */
+  roleChangeAuditTrail(params: RoleChangeAuditTrailParams = {}): Promise<RoleChange[]> {
+    return this.request<RoleChange[]>("GET", `/admin/role-changes`, { query: { ...params } });
+  }
+
+  /**
+   * List role requests (Admin only).
+   *
+   * `GET /admin/role-requests`
+   */
+  listRoleRequests(params: ListRoleRequestsParams = {}): Promise<RoleRequest[]> {
+    return this.request<RoleRequest[]>("GET", `/admin/role-requests`, { query: { ...params } });
+  }
+
+  /**
+   * Approve a role request (Admin only). Grants the requested role and records the change; the user gets the role in tokens issued after approval.
+   *
+   * `POST /admin/role-requests/{id}/approve`
+   */
+  approveRoleRequest(id: number, body: ReviewRoleRequestRequest): Promise<RoleRequest> {
+    return this.request<RoleRequest>("POST", `/admin/role-requests/${encodeURIComponent(String(id))}/approve`, { json: body });
+  }
+
+  /**
+   * Reject a role request (Admin only).
+   *
+   * `POST /admin/role-requests/{id}/reject`
+   */
+  rejectRoleRequest(id: number, body: ReviewRoleRequestRequest): Promise<RoleRequest> {
+    return this.request<RoleRequest>("POST", `/admin/role-requests/${encodeURIComponent(String(id))}/reject`, { json: body });
+  }
+
+  /**
+   * List all users (Admin only).
+   *
+   * `GET /admin/users`
+   */
+  listAllUsers(params: ListAllUsersParams = {}): Promise<User[]> {
+    return this.request<User[]>("GET", `/admin/users`, { query: { ...params } });
+  }
+
+  /**
+   * Create new user (Admin only). Creating an admin requires the acting admin's two-factor code in otp.
+   *
+   * `POST /admin/users`
+   */
+  createNewUser(body: CreateUserRequest): Promise<User> {
+    return this.request<User>("POST", `/admin/users`, { json: body });
+  }
+
+  /**
+   * Import users from CSV (Admin only). Creates a user per row of a CSV file whose header names the columns email, role (user, seller or courier; default user) and password, at most 200 rows. A password is temporary: signing in answers 403 with code password_change_required until it is changed with /auth/password-change. Rows without a password need invitation emails, which link to choosing a password; with a password the invitation tells the user to sign in. Each row is reported with its status (created, valid or failed) and error; a failed row does not stop the others. With dry_run nothing is created or sent. The multipart form has the fields file (file, required).
+   *
+   * `POST /admin/users/import`
+   */
+  importUsersFromCSV(form: FormData, params: ImportUsersFromCSVParams = {}): Promise<UserImportResult> {
+    return this.request<UserImportResult>("POST", `/admin/users/import`, { query: { ...params }, body: form });
+  }
+
+  /**
+   * Delete user (Admin only).
+   *
+   * `DELETE /admin/users/{id}`
+   */
+  deleteUser(id: number): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("DELETE", `/admin/users/${encodeURIComponent(String(id))}`);
+  }
+
+  /**
+   * Force a password reset (Admin only). Signs the user out of every session, blocks password login until the password is reset and emails a reset link. Access tokens already issued are rejected by Auth and stop passing introspection at once.
+   *
+   * `POST /admin/users/{id}/force-password-reset`
+   */
+  forcePasswordReset(id: number): Promise<Record<string, unknown>> {
+    return this.request<Record<string, unknown>>("POST", `/admin/users/${encodeURIComponent(String(id))}/force-password-reset`);
+  }
+
+  /**
+   * Re-send the verification email of a user (Admin only). Emails the user a new email verification link. Links sent earlier stay valid until they expire.
+   *
+   * `POST /admin/users/{id}/resend-verification`
+   */
+  reSendVerificationEmailOfUser(id: number): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("POST", `/admin/users/${encodeURIComponent(String(id))}/resend-verification`);
+  }
+
+  /**
+   * Revoke all sessions of a user (Admin only). Revokes every refresh token of the user. Access tokens already issued are rejected by Auth and stop passing introspection at once.
+   *
+   * `POST /admin/users/{id}/revoke-sessions`
+   */
+  revokeAllSessionsOfUser(id: number): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("POST", `/admin/users/${encodeURIComponent(String(id))}/revoke-sessions`);
+  }
+
+  /**
+   * Update user role (Admin only). The change is recorded in the role audit trail with the optional reason. Granting admin requires the acting admin's two-factor code in otp.
+   *
+   * `PUT /admin/users/{id}/role`
+   */
+  updateUserRole(id: number, body: UpdateRoleRequest): Promise<User> {
+    return this.request<User>("PUT", `/admin/users/${encodeURIComponent(String(id))}/role`, { json: body });
+  }
+
+  /**
+   * Suspend a user (Admin only). Blocks every sign-in of the user, which answers 403 with code account_suspended, and revokes all sessions like revoke-sessions. Access tokens already issued are rejected at once.
+   *
+   * `POST /admin/users/{id}/suspend`
+   */
+  suspendUser(id: number): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("POST", `/admin/users/${encodeURIComponent(String(id))}/suspend`);
+  }
+
+  /**
+   * Lift the suspension of a user (Admin only). Lets a suspended user sign in again. Sessions revoked by the suspension stay revoked.
+   *
+   * `POST /admin/users/{id}/unsuspend`
+   */
+  liftSuspensionOfUser(id: number): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("POST", `/admin/users/${encodeURIComponent(String(id))}/unsuspend`);
+  }
+
+  /**
+   * Disable two-factor authentication.
+   *
+   * `POST /api/2fa/disable`
+   */
+  disableTwoFactorAuthentication(body: TwoFactorCodeRequest): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("POST", `/api/2fa/disable`, { json: body });
+  }
+
+  /**
+   * Enable two-factor authentication.
+   *
+   * `POST /api/2fa/enable`
+   */
+  enableTwoFactorAuthentication(body: TwoFactorCodeRequest): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("POST", `/api/2fa/enable`, { json: body });
+  }
+
+  /**
+   * Start two-factor setup. Returns a new TOTP secret and otpauth:// URI for an authenticator app; confirm it with /api/2fa/enable.
+   *
+   * `POST /api/2fa/setup`
+   */
+  startTwoFactorSetup(): Promise<TwoFactorSetupResponse> {
+    return this.request<TwoFactorSetupResponse>("POST", `/api/2fa/setup`);
+  }
+
+  /**
+   * List captured emails. List the emails captured by the mail sandbox instead of being sent, oldest first. Available only with MAIL_SANDBOX, outside production.
+   *
+   * `GET /api/dev/outbox`
+   */
+  listCapturedEmails(params: ListCapturedEmailsParams = {}): Promise<MailerMessage[]> {
+    return this.request<MailerMessage[]>("GET", `/api/dev/outbox`, { query: { ...params } });
+  }
+
+  /**
+   * List linked sign-in methods.
+   *
+   * `GET /api/identities`
+   */
+  listLinkedSignInMethods(): Promise<Identity[]> {
+    return this.request<Identity[]>("GET", `/api/identities`);
+  }
+
+  /**
+   * Link a Google account.
+   *
+   * `POST /api/identities/google`
+   */
+  linkGoogleAccount(body: GoogleIdentityRequest): Promise<Identity> {
+    return this.request<Identity>("POST", `/api/identities/google`, { json: body });
+  }
+
+  /**
+   * Link a phone number.
+   *
+   * `POST /api/identities/phone`
+   */
+  linkPhoneNumber(body: PhoneIdentityRequest): Promise<Identity> {
+    return this.request<Identity>("POST", `/api/identities/phone`, { json: body });
+  }
+
+  /**
+   * Send a code to a phone number to link it.
+   *
+   * `POST /api/identities/phone/code`
+   */
+  sendCodeToPhoneNumberToLinkIt(body: PhoneCodeRequest): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("POST", `/api/identities/phone/code`, { json: body });
+  }
+
+  /**
+   * Unlink a sign-in method.
+   *
+   * `DELETE /api/identities/{id}`
+   */
+  unlinkSignInMethod(id: number): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("DELETE", `/api/identities/${encodeURIComponent(String(id))}`);
+  }
+
+  /**
+   * List my sessions. Sign-ins that can still be refreshed, most recently used first, with the User-Agent and IP address of the client. The session of the presented access token is marked current.
+   *
+   * `GET /api/me/sessions`
+   */
+  listMySessions(): Promise<Session[]> {
+    return this.request<Session[]>("GET", `/api/me/sessions`);
+  }
+
+  /**
+   * Log out everywhere. Revokes every session of the user, including the current one, and the presented access token. Other access tokens are reported inactive by introspection and expire shortly after.
+   *
+   * `DELETE /api/me/sessions`
+   */
+  logOutEverywhere(): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("DELETE", `/api/me/sessions`);
+  }
+
+  /**
+   * Sign a session out. Revokes the refresh tokens of one session so the device cannot refresh again; its access tokens expire shortly after.
+   *
+   * `DELETE /api/me/sessions/{id}`
+   */
+  signSessionOut(id: string): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("DELETE", `/api/me/sessions/${encodeURIComponent(String(id))}`);
+  }
+
+  /**
+   * List my role requests.
+   *
+   * `GET /api/role-requests`
+   */
+  listMyRoleRequests(): Promise<RoleRequest[]> {
+    return this.request<RoleRequest[]>("GET", `/api/role-requests`);
+  }
+
+  /**
+   * Apply for a privileged role. Only accounts with the user role can apply, one pending request at a time.
+   *
+   * `POST /api/role-requests`
+   */
+  applyForPrivilegedRole(body: CreateRoleRequestRequest): Promise<RoleRequest> {
+    return this.request<RoleRequest>("POST", `/api/role-requests`, { json: body });
+  }
+
+  /**
+   * Issue a scoped access token. Mints an access token limited to some of the current token's scopes (space separated, e.g. "market:read"). No refresh token is issued.
+   *
+   * `POST /api/tokens/scoped`
+   */
+  issueScopedAccessToken(body: ScopedTokenRequest): Promise<ScopedTokenResponse> {
+    return this.request<ScopedTokenResponse>("POST", `/api/tokens/scoped`, { json: body });
+  }
+
+  /**
+   * Introspect a token. RFC 7662 style introspection for other services, authenticated with HTTP Basic client credentials. Unknown, expired and revoked tokens return only {"active": false}.
+   *
+   * `POST /auth/introspect`
+   */
+  introspectToken(form: IntrospectTokenForm): Promise<IntrospectionResponse> {
+    return this.request<IntrospectionResponse>("POST", `/auth/introspect`, { form });
+  }
+
+  /**
+   * Login user.
+   *
+   * `POST /auth/login`
+   */
+  loginUser(body: LoginRequest): Promise<TokenPair> {
+    return this.request<TokenPair>("POST", `/auth/login`, { json: body });
+  }
+
+  /**
+   * Report a sign-in as not made by the user. Used by the "it wasn't me" link of a sign-in alert email. Signs the reported session out, blocks password sign-in until the password is reset and emails a reset link.
+   *
+   * `POST /auth/login-alerts/deny`
+   */
+  reportSignInAsNotMadeByUser(body: LoginAlertDenyRequest): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("POST", `/auth/login-alerts/deny`, { json: body });
+  }
+
+  /**
+   * Login with a linked Google account.
+   *
+   * `POST /auth/login/google`
+   */
+  loginWithLinkedGoogleAccount(body: GoogleIdentityRequest): Promise<TokenPair> {
+    return this.request<TokenPair>("POST", `/auth/login/google`, { json: body });
+  }
+
+  /**
+   * Login with a linked phone number.
+   *
+   * `POST /auth/login/phone`
+   */
+  loginWithLinkedPhoneNumber(body: PhoneIdentityRequest): Promise<TokenPair> {
+    return this.request<TokenPair>("POST", `/auth/login/phone`, { json: body });
+  }
+
+  /**
+   * Send a login code to a linked phone number. Always accepted for valid numbers; the code is only sent when the number is linked to an account.
+   *
+   * `POST /auth/login/phone/code`
+   */
+  sendLoginCodeToLinkedPhoneNumber(body: PhoneCodeRequest): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("POST", `/auth/login/phone/code`, { json: body });
+  }
+
+  /**
+   * Logout user. Revokes the refresh token and, when one is presented, the current access token.
+   *
+   * `POST /auth/logout`
+   */
+  logoutUser(body: RefreshRequest): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("POST", `/auth/logout`, { json: body });
+  }
+
+  /**
+   * Change the password. Signs in with the current password to choose a new one, e.g. the temporary password of an imported account, which has to be changed before signing in. All sessions of the user are signed out.
+   *
+   * `POST /auth/password-change`
+   */
+  changePassword(body: PasswordChangeRequest): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("POST", `/auth/password-change`, { json: body });
+  }
+
+  /**
+   * Choose a new password. Uses the token from a password reset email. All sessions of the user are signed out.
+   *
+   * `POST /auth/password-reset`
+   */
+  chooseNewPassword(body: PasswordResetConfirmRequest): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("POST", `/auth/password-reset`, { json: body });
+  }
+
+  /**
+   * Request a password reset email. Always accepted; the email is only sent when the address belongs to an account.
+   *
+   * `POST /auth/password-reset/request`
+   */
+  requestPasswordResetEmail(body: PasswordResetRequest): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("POST", `/auth/password-reset/request`, { json: body });
+  }
+
+  /**
+   * Refresh tokens. Rotates the refresh token from the refresh_token cookie or the body and issues a new token pair. Presenting a refresh token that was already rotated is treated as theft: every token rotated from it is revoked, signing the session out, and the 401 carries code refresh_token_reused.
+   *
+   * `POST /auth/refresh`
+   */
+  refreshTokens(body: RefreshRequest): Promise<TokenPair> {
+    return this.request<TokenPair>("POST", `/auth/refresh`, { json: body });
+  }
+
+  /**
+   * Register user. Creates an account with the user role and signs it in. Privileged roles are requested afterwards via /api/role-requests.
+   *
+   * `POST /auth/register`
+   */
+  registerUser(body: RegisterRequest): Promise<TokenPair> {
+    return this.request<TokenPair>("POST", `/auth/register`, { json: body });
+  }
+
+  /**
+   * Suspend a user for a service. Suspends the user like /admin/users/{id}/suspend for other services, authenticated with HTTP Basic client credentials, e.g. when a Market moderator bans the user.
+   *
+   * `POST /auth/users/{id}/suspend`
+   */
+  suspendUserForService(id: number): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("POST", `/auth/users/${encodeURIComponent(String(id))}/suspend`);
+  }
+
+  /**
+   * Verify an email address. Used by the link of a verification email sent at registration or by an admin.
+   *
+   * `GET /auth/verify-email`
+   */
+  verifyEmailAddress(params: VerifyEmailAddressParams = {}): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>("GET", `/auth/verify-email`, { query: { ...params } });
+  }
+
+  /**
+   * Health check.
+   *
+   * `GET /health`
+   */
+  healthCheck(): Promise<Record<string, unknown>> {
+    return this.request<Record<string, unknown>>("GET", `/health`);
+  }
+
+  /**
+   * Build information. Version, git commit, build date and Go runtime of the running binary.
+   *
+   * `GET /version`
+   */
+  buildInformation(): Promise<BuildinfoInfo> {
+    return this.request<BuildinfoInfo>("GET", `/version`);
+  }
+}
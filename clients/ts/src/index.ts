@@ -0,0 +1,5 @@
+export { ApiError, type ClientOptions } from "./runtime.js";
+export * as auth from "./auth.js";
+export * as market from "./market.js";
+export { AuthClient } from "./auth.js";
+export { MarketClient } from "./market.js";
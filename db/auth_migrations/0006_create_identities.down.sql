@@ -0,0 +1,3 @@
+-- Drop linked identities and phone codes
+DROP TABLE IF EXISTS phone_codes;
+DROP TABLE IF EXISTS identities;
@@ -0,0 +1,23 @@
+-- Additional sign-in methods linked to a user (Google account, phone number).
+-- Email/password stays on users; each provider can be linked once per user
+-- and each identity belongs to one user.
+CREATE TABLE IF NOT EXISTS identities (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(20) NOT NULL, -- google, phone
+    subject VARCHAR(255) NOT NULL, -- Google account ID or E.164 phone number
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, subject),
+    UNIQUE (user_id, provider)
+);
+
+-- One-time codes proving ownership of a phone number
+CREATE TABLE IF NOT EXISTS phone_codes (
+    phone VARCHAR(20) PRIMARY KEY,
+    code_hash VARCHAR(64) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_phone_codes_expires ON phone_codes(expires_at);
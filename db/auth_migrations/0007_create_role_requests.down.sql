@@ -0,0 +1,3 @@
+-- Drop role requests and the role change audit trail
+DROP TABLE IF EXISTS role_changes;
+DROP TABLE IF EXISTS role_requests;
@@ -0,0 +1,32 @@
+-- Requests from users to be granted a privileged role (seller, courier).
+-- A user can have one pending request at a time.
+CREATE TABLE IF NOT EXISTS role_requests (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, approved, rejected
+    message TEXT NOT NULL DEFAULT '',
+    reviewed_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    review_note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_role_requests_pending_user ON role_requests(user_id) WHERE status = 'pending';
+CREATE INDEX idx_role_requests_status ON role_requests(status, created_at);
+
+-- Audit trail of every role change made by an admin
+CREATE TABLE IF NOT EXISTS role_changes (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    old_role VARCHAR(20) NOT NULL,
+    new_role VARCHAR(20) NOT NULL,
+    changed_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    request_id BIGINT REFERENCES role_requests(id) ON DELETE SET NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_role_changes_user ON role_changes(user_id, created_at);
+
+COMMENT ON TABLE role_changes IS 'Role changes made by admins directly or by approving a role request';
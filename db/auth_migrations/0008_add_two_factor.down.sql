@@ -0,0 +1,3 @@
+-- Drop two-factor columns; the locked seed admin stays locked
+ALTER TABLE users DROP COLUMN IF EXISTS totp_enabled;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_secret;
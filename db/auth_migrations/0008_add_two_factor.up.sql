@@ -0,0 +1,11 @@
+-- TOTP two-factor authentication. A secret is stored at setup and only
+-- used once totp_enabled is set after the first valid code.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret VARCHAR(64);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Lock the admin seeded by 0004 if it still has the published default
+-- password. Use cmd/createadmin to bootstrap an admin instead.
+UPDATE users
+SET password_hash = '!', updated_at = NOW()
+WHERE email = 'admin@example.com'
+  AND password_hash = '$2a$10$RiEfkw/nnkK8eZkzXRcBVON3paKySbNBQEVYJg4QBkFi1ogk/BAES';
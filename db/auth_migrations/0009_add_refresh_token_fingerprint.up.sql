@@ -0,0 +1,3 @@
+-- Hash of the client (User-Agent, optionally IP) a refresh token was issued
+-- to; NULL for tokens issued before binding was introduced
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS client_fingerprint VARCHAR(64);
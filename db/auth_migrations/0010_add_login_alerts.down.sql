@@ -0,0 +1,6 @@
+-- Drop sign-in alert state
+DROP TABLE IF EXISTS security_tokens;
+DROP TABLE IF EXISTS login_devices;
+DROP INDEX IF EXISTS idx_refresh_tokens_session;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS session_id;
+ALTER TABLE users DROP COLUMN IF EXISTS password_reset_required;
@@ -0,0 +1,33 @@
+-- Set when a sign-in was reported as "it wasn't me"; password sign-in is
+-- refused until the password is reset
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_reset_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Refresh tokens rotated from the same sign-in share a session ID, so a
+-- whole session can be revoked at once. NULL for tokens issued earlier.
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS session_id VARCHAR(32);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session ON refresh_tokens(user_id, session_id);
+
+-- Devices (User-Agent hash) and countries a user has signed in from
+CREATE TABLE IF NOT EXISTS login_devices (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device VARCHAR(64) NOT NULL,
+    country VARCHAR(2) NOT NULL DEFAULT '',
+    first_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, device, country)
+);
+
+-- Single-use links sent by email. Only the hash of the token is stored.
+CREATE TABLE IF NOT EXISTS security_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(20) NOT NULL, -- login_alert, password_reset
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    session_id VARCHAR(32) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_security_tokens_expires ON security_tokens(expires_at);
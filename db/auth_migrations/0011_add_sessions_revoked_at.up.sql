@@ -0,0 +1,3 @@
+-- When all sessions of a user were last revoked; access tokens issued
+-- before that are reported inactive by introspection
+ALTER TABLE users ADD COLUMN IF NOT EXISTS sessions_revoked_at TIMESTAMPTZ;
@@ -0,0 +1,2 @@
+-- Drop email verification state
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified;
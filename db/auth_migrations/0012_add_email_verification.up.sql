@@ -0,0 +1,4 @@
+-- Accounts created before email verification count as verified; new ones
+-- start unverified until the link in the verification email is followed
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE users ALTER COLUMN email_verified SET DEFAULT FALSE;
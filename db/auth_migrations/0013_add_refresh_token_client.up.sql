@@ -0,0 +1,5 @@
+-- User-Agent and IP address of the client a refresh token was issued to,
+-- shown to the user in their list of sessions. NULL for tokens issued
+-- earlier.
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS user_agent VARCHAR(512);
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS ip_address VARCHAR(45);
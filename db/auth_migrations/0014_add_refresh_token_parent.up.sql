@@ -0,0 +1,6 @@
+-- Token a refresh token was rotated from. The tokens linked this way form
+-- a family: a revoked token that already has a child is being reused, and
+-- every token rotated from it is revoked. NULL for tokens issued at
+-- sign-in and before rotation was tracked.
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS parent_id BIGINT REFERENCES refresh_tokens(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_parent_id ON refresh_tokens(parent_id);
@@ -0,0 +1,2 @@
+-- Drop the temporary password state
+ALTER TABLE users DROP COLUMN IF EXISTS password_change_required;
@@ -0,0 +1,3 @@
+-- Accounts imported with a temporary password must choose their own before
+-- they can sign in
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_change_required BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,10 @@
+-- Sealed values do not fit the old columns; decrypt them before rolling back.
+DELETE FROM phone_codes;
+ALTER TABLE phone_codes ALTER COLUMN phone TYPE VARCHAR(20);
+
+ALTER TABLE users ALTER COLUMN totp_secret TYPE VARCHAR(64);
+
+ALTER TABLE identities DROP CONSTRAINT IF EXISTS identities_provider_subject_index_key;
+ALTER TABLE identities DROP COLUMN IF EXISTS subject_index;
+ALTER TABLE identities ALTER COLUMN subject TYPE VARCHAR(255);
+ALTER TABLE identities ADD CONSTRAINT identities_provider_subject_key UNIQUE (provider, subject);
@@ -0,0 +1,17 @@
+-- Google account IDs, phone numbers and TOTP secrets are sealed with the
+-- field encryption keyring when ENCRYPTION_KEYS is set. Sealed subjects are
+-- matched by subject_index, their blind index; rows written before then keep
+-- the plaintext subject as index until ENCRYPTION_ROTATE_ON_START rewrites
+-- them.
+ALTER TABLE identities ALTER COLUMN subject TYPE TEXT;
+ALTER TABLE identities ADD COLUMN IF NOT EXISTS subject_index TEXT;
+UPDATE identities SET subject_index = subject WHERE subject_index IS NULL;
+ALTER TABLE identities ALTER COLUMN subject_index SET NOT NULL;
+ALTER TABLE identities DROP CONSTRAINT IF EXISTS identities_provider_subject_key;
+ALTER TABLE identities ADD CONSTRAINT identities_provider_subject_index_key UNIQUE (provider, subject_index);
+
+ALTER TABLE users ALTER COLUMN totp_secret TYPE TEXT;
+
+-- Pending phone codes are keyed by the blind index of the number. Codes
+-- sent before the index key was set stop matching and have to be resent.
+ALTER TABLE phone_codes ALTER COLUMN phone TYPE TEXT;
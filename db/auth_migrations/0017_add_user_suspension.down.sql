@@ -0,0 +1,2 @@
+-- Drop the suspension state
+ALTER TABLE users DROP COLUMN IF EXISTS suspended;
@@ -0,0 +1,3 @@
+-- Suspended accounts cannot sign in by any method, e.g. after a moderator
+-- of the Market service banned the user. Admins lift the suspension.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,17 @@
+-- Add human-friendly order numbers
+CREATE SEQUENCE IF NOT EXISTS order_number_seq;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS order_number VARCHAR(64);
+
+-- Backfill existing orders using the default MB-{YYYY}-{SEQ} format
+UPDATE orders o
+SET order_number = 'MB-' || to_char(o.created_at, 'YYYY') || '-' || lpad(n.seq::text, 6, '0')
+FROM (
+    SELECT id, nextval('order_number_seq') AS seq
+    FROM (SELECT id FROM orders WHERE order_number IS NULL ORDER BY id) ordered
+) n
+WHERE o.id = n.id;
+
+ALTER TABLE orders ALTER COLUMN order_number SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_order_number ON orders(order_number);
@@ -0,0 +1,2 @@
+-- Drop courier assignments
+DROP TABLE IF EXISTS order_deliveries;
@@ -0,0 +1,10 @@
+-- Courier assignments; one active delivery per order
+CREATE TABLE IF NOT EXISTS order_deliveries (
+    order_id INTEGER PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    courier_id INTEGER NOT NULL,
+    assigned_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP,
+    proof_url TEXT
+);
+
+CREATE INDEX idx_order_deliveries_courier_id ON order_deliveries(courier_id, delivered_at);
@@ -0,0 +1,2 @@
+-- Drop delivery proofs
+DROP TABLE IF EXISTS delivery_proofs;
@@ -0,0 +1,18 @@
+-- Delivery photos and signatures attached by couriers or sellers
+CREATE TABLE IF NOT EXISTS delivery_proofs (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('photo', 'signature')),
+    url TEXT NOT NULL,
+    uploaded_by INTEGER NOT NULL,
+    uploader_role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_delivery_proofs_order_id ON delivery_proofs(order_id);
+
+-- Keep proofs captured when a courier closed a delivery
+INSERT INTO delivery_proofs (order_id, kind, url, uploaded_by, uploader_role, created_at)
+SELECT order_id, 'photo', proof_url, courier_id, 'courier', delivered_at
+FROM order_deliveries
+WHERE proof_url IS NOT NULL;
@@ -0,0 +1,3 @@
+-- Drop abuse reports and notifications
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS reports;
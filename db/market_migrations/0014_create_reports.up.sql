@@ -0,0 +1,32 @@
+-- Abuse reports filed by users against products and sellers
+CREATE TABLE IF NOT EXISTS reports (
+    id SERIAL PRIMARY KEY,
+    reporter_id INTEGER NOT NULL,
+    target_type VARCHAR(20) NOT NULL CHECK (target_type IN ('product', 'seller')),
+    target_id INTEGER NOT NULL,
+    reason VARCHAR(30) NOT NULL CHECK (reason IN ('spam', 'fraud', 'counterfeit', 'offensive', 'prohibited', 'other')),
+    details TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved')),
+    resolution VARCHAR(20) CHECK (resolution IN ('dismiss', 'hide_content', 'warn_seller', 'ban_user')),
+    resolution_note TEXT,
+    resolved_by INTEGER,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One open report per user and target
+CREATE UNIQUE INDEX idx_reports_open_unique ON reports(reporter_id, target_type, target_id) WHERE status = 'open';
+CREATE INDEX idx_reports_status ON reports(status, created_at);
+CREATE INDEX idx_reports_target ON reports(target_type, target_id);
+
+-- In-app notifications (moderation outcomes, warnings)
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    kind VARCHAR(50) NOT NULL,
+    message TEXT NOT NULL,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_notifications_user_id ON notifications(user_id, created_at);
@@ -0,0 +1,10 @@
+-- Remove automated moderation holds
+DROP INDEX IF EXISTS idx_reports_open_auto_unique;
+DELETE FROM reports WHERE source = 'auto';
+
+ALTER TABLE reports DROP CONSTRAINT IF EXISTS reports_reason_check;
+ALTER TABLE reports ADD CONSTRAINT reports_reason_check
+    CHECK (reason IN ('spam', 'fraud', 'counterfeit', 'offensive', 'prohibited', 'other'));
+
+ALTER TABLE reports DROP COLUMN IF EXISTS source;
+ALTER TABLE reports ALTER COLUMN reporter_id SET NOT NULL;
@@ -0,0 +1,10 @@
+-- Automated moderation holds share the reports queue with user reports
+ALTER TABLE reports ALTER COLUMN reporter_id DROP NOT NULL;
+ALTER TABLE reports ADD COLUMN source VARCHAR(20) NOT NULL DEFAULT 'user' CHECK (source IN ('user', 'auto'));
+
+ALTER TABLE reports DROP CONSTRAINT IF EXISTS reports_reason_check;
+ALTER TABLE reports ADD CONSTRAINT reports_reason_check
+    CHECK (reason IN ('spam', 'fraud', 'counterfeit', 'offensive', 'prohibited', 'other', 'auto_moderation'));
+
+-- One open automated hold per target
+CREATE UNIQUE INDEX idx_reports_open_auto_unique ON reports(target_type, target_id) WHERE source = 'auto' AND status = 'open';
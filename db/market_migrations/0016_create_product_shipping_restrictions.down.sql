@@ -0,0 +1,2 @@
+-- Drop product shipping restrictions
+DROP TABLE IF EXISTS product_shipping_restrictions;
@@ -0,0 +1,8 @@
+-- Per-product shipping destinations. Codes are ISO 3166-1 alpha-2 countries
+-- (DE) or ISO 3166-2 regions (US-AK). Empty ships_to means worldwide.
+CREATE TABLE IF NOT EXISTS product_shipping_restrictions (
+    product_id INTEGER PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+    ships_to TEXT[] NOT NULL DEFAULT '{}',
+    no_ship_to TEXT[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
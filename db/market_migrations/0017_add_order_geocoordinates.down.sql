@@ -0,0 +1,3 @@
+-- Drop geocoded delivery coordinates
+ALTER TABLE orders DROP COLUMN IF EXISTS delivery_lng;
+ALTER TABLE orders DROP COLUMN IF EXISTS delivery_lat;
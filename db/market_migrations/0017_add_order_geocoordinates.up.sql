@@ -0,0 +1,4 @@
+-- Geocoded delivery coordinates for routing. NULL when no address provider
+-- is configured or the provider returned no position.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS delivery_lat DOUBLE PRECISION;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS delivery_lng DOUBLE PRECISION;
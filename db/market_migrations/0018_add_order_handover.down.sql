@@ -0,0 +1,6 @@
+-- Drop pickup verification columns
+DROP INDEX IF EXISTS idx_orders_pickup_code;
+ALTER TABLE orders DROP COLUMN IF EXISTS handed_over_role;
+ALTER TABLE orders DROP COLUMN IF EXISTS handed_over_by;
+ALTER TABLE orders DROP COLUMN IF EXISTS handed_over_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS pickup_code;
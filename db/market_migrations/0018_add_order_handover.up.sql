@@ -0,0 +1,8 @@
+-- Pickup/COD verification. pickup_code is the secret encoded in the buyer's
+-- QR code; handed_over_* are set exactly once when it is scanned.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS pickup_code VARCHAR(64);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS handed_over_at TIMESTAMP;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS handed_over_by INTEGER;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS handed_over_role VARCHAR(20);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_pickup_code ON orders(pickup_code);
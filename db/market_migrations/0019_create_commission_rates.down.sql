@@ -0,0 +1,4 @@
+-- Drop commission rates
+ALTER TABLE order_items DROP COLUMN IF EXISTS commission_amount;
+ALTER TABLE order_items DROP COLUMN IF EXISTS commission_rate;
+DROP TABLE IF EXISTS commission_rates;
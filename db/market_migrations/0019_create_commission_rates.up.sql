@@ -0,0 +1,19 @@
+-- Marketplace commission rates. A row with neither category nor seller is
+-- the marketplace default; seller rows override category rows, and a row
+-- with both applies to one seller in one category. Rates are never edited:
+-- a new row with a later effective_from supersedes the previous one.
+CREATE TABLE IF NOT EXISTS commission_rates (
+    id SERIAL PRIMARY KEY,
+    category_id INTEGER REFERENCES categories(id) ON DELETE CASCADE,
+    seller_id INTEGER REFERENCES sellers(id) ON DELETE CASCADE,
+    rate NUMERIC(5, 4) NOT NULL CHECK (rate >= 0 AND rate <= 1),
+    effective_from TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_commission_rates_scope ON commission_rates(seller_id, category_id, effective_from DESC);
+
+-- Commission is snapshotted per order item when the order is placed
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS commission_rate NUMERIC(5, 4) NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS commission_amount NUMERIC(10, 2) NOT NULL DEFAULT 0;
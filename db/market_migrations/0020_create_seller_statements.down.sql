@@ -0,0 +1,2 @@
+-- Drop seller statements
+DROP TABLE IF EXISTS seller_statements;
@@ -0,0 +1,16 @@
+-- Monthly seller statements produced by the statement scheduler. Amounts
+-- cover non-cancelled orders placed in the period; refunded orders count
+-- towards refunds and carry no commission.
+CREATE TABLE IF NOT EXISTS seller_statements (
+    id SERIAL PRIMARY KEY,
+    seller_id INTEGER NOT NULL REFERENCES sellers(id) ON DELETE CASCADE,
+    period DATE NOT NULL,
+    orders_count INTEGER NOT NULL DEFAULT 0,
+    items_sold INTEGER NOT NULL DEFAULT 0,
+    gross_sales NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    commission NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    refunds NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    payout NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    generated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (seller_id, period)
+);
@@ -0,0 +1,3 @@
+-- Drop support tickets
+DROP TABLE IF EXISTS ticket_messages;
+DROP TABLE IF EXISTS support_tickets;
@@ -0,0 +1,29 @@
+-- Support tickets opened by buyers and sellers and answered by admins
+CREATE TABLE IF NOT EXISTS support_tickets (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    user_role VARCHAR(20) NOT NULL,
+    user_email VARCHAR(255),
+    category VARCHAR(20) NOT NULL CHECK (category IN ('order', 'payment', 'delivery', 'account', 'product', 'other')),
+    subject VARCHAR(200) NOT NULL,
+    order_id INTEGER REFERENCES orders(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'pending', 'resolved', 'closed')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_support_tickets_user_id ON support_tickets(user_id, created_at);
+CREATE INDEX idx_support_tickets_status ON support_tickets(status, updated_at);
+
+-- Conversation on a ticket; the first message is the requester's description
+CREATE TABLE IF NOT EXISTS ticket_messages (
+    id SERIAL PRIMARY KEY,
+    ticket_id INTEGER NOT NULL REFERENCES support_tickets(id) ON DELETE CASCADE,
+    author_id INTEGER NOT NULL,
+    author_role VARCHAR(20) NOT NULL,
+    body TEXT NOT NULL,
+    attachments TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ticket_messages_ticket_id ON ticket_messages(ticket_id, created_at);
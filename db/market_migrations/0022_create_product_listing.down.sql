@@ -0,0 +1,2 @@
+-- Drop product listing view
+DROP MATERIALIZED VIEW IF EXISTS product_listing;
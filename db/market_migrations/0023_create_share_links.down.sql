@@ -0,0 +1,2 @@
+-- Drop product share links
+DROP TABLE IF EXISTS share_links;
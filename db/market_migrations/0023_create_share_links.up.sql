@@ -0,0 +1,14 @@
+-- Short links for sharing products. One link per product, sharer and
+-- source, so repeated shares reuse the same code.
+CREATE TABLE IF NOT EXISTS share_links (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(16) NOT NULL UNIQUE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    created_by INTEGER NOT NULL,
+    source VARCHAR(50) NOT NULL DEFAULT '',
+    clicks BIGINT NOT NULL DEFAULT 0,
+    last_clicked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_share_links_owner ON share_links(product_id, created_by, source);
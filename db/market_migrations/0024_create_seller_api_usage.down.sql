@@ -0,0 +1,3 @@
+-- Drop seller API usage and plans
+DROP TABLE IF EXISTS seller_api_usage;
+ALTER TABLE sellers DROP COLUMN IF EXISTS api_plan;
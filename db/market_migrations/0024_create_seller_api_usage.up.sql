@@ -0,0 +1,12 @@
+-- API plans decide how many seller API requests are allowed per window.
+ALTER TABLE sellers ADD COLUMN IF NOT EXISTS api_plan VARCHAR(20) NOT NULL DEFAULT 'basic';
+
+-- Daily seller API usage, rolled up from the Redis counters.
+CREATE TABLE IF NOT EXISTS seller_api_usage (
+    user_id INTEGER NOT NULL,
+    day DATE NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    errors BIGINT NOT NULL DEFAULT 0,
+    throttled BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, day)
+);
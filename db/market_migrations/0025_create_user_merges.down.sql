@@ -0,0 +1,2 @@
+-- Drop user merge audit log
+DROP TABLE IF EXISTS user_merges;
@@ -0,0 +1,18 @@
+-- Audit log of merged duplicate user accounts. Counts record how many rows
+-- were moved from the source user to the target user.
+CREATE TABLE IF NOT EXISTS user_merges (
+    id SERIAL PRIMARY KEY,
+    source_user_id INTEGER NOT NULL,
+    target_user_id INTEGER NOT NULL,
+    merged_by INTEGER NOT NULL,
+    orders BIGINT NOT NULL DEFAULT 0,
+    cart_items BIGINT NOT NULL DEFAULT 0,
+    seller_moved BOOLEAN NOT NULL DEFAULT false,
+    tickets BIGINT NOT NULL DEFAULT 0,
+    notifications BIGINT NOT NULL DEFAULT 0,
+    reports BIGINT NOT NULL DEFAULT 0,
+    share_links BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_user_merges_created_at ON user_merges(created_at);
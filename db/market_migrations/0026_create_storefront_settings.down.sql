@@ -0,0 +1,2 @@
+-- Drop storefront branding
+DROP TABLE IF EXISTS storefront_settings;
@@ -0,0 +1,18 @@
+-- Branding of white-label storefronts. tenant is the storefront's host name
+-- ("shop.example.com"); the "default" row applies to unknown hosts.
+CREATE TABLE IF NOT EXISTS storefront_settings (
+    tenant VARCHAR(100) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL DEFAULT '',
+    logo_url VARCHAR(500) NOT NULL DEFAULT '',
+    primary_color VARCHAR(7) NOT NULL DEFAULT '',
+    secondary_color VARCHAR(7) NOT NULL DEFAULT '',
+    accent_color VARCHAR(7) NOT NULL DEFAULT '',
+    contact_email VARCHAR(255) NOT NULL DEFAULT '',
+    contact_phone VARCHAR(50) NOT NULL DEFAULT '',
+    contact_address VARCHAR(500) NOT NULL DEFAULT '',
+    footer_links JSONB NOT NULL DEFAULT '[]', -- [{"label", "url"}]
+    default_currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    default_locale VARCHAR(35) NOT NULL DEFAULT 'en-US',
+    updated_by INTEGER,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,2 @@
+-- Drop site-wide configuration
+DROP TABLE IF EXISTS settings;
@@ -0,0 +1,9 @@
+-- Site-wide configuration changed by admins at runtime. The known keys and
+-- their types live in internal/settings; keys without a row use the
+-- built-in defaults.
+CREATE TABLE IF NOT EXISTS settings (
+    key VARCHAR(100) PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_by INTEGER,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,2 @@
+-- Drop payments
+DROP TABLE IF EXISTS payments;
@@ -0,0 +1,4 @@
+-- Drop seller onboarding details
+ALTER TABLE sellers DROP COLUMN IF EXISTS shipping_policy;
+ALTER TABLE sellers DROP COLUMN IF EXISTS return_policy;
+ALTER TABLE sellers DROP COLUMN IF EXISTS payout_details;
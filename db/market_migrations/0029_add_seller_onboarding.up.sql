@@ -0,0 +1,5 @@
+-- Payout details (encrypted when ENCRYPTION_KEYS is set) and shop policies,
+-- collected during seller onboarding.
+ALTER TABLE sellers ADD COLUMN IF NOT EXISTS payout_details TEXT;
+ALTER TABLE sellers ADD COLUMN IF NOT EXISTS return_policy TEXT;
+ALTER TABLE sellers ADD COLUMN IF NOT EXISTS shipping_policy TEXT;
@@ -0,0 +1,3 @@
+-- Drop order item fulfillment
+ALTER TABLE order_items DROP COLUMN IF EXISTS fulfillment_updated_at;
+ALTER TABLE order_items DROP COLUMN IF EXISTS fulfillment_status;
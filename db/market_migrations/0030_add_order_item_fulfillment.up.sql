@@ -0,0 +1,4 @@
+-- Sellers fulfil their own items of an order independently of each other.
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS fulfillment_status VARCHAR(20) NOT NULL DEFAULT 'pending'
+    CHECK (fulfillment_status IN ('pending', 'processing', 'shipped', 'delivered', 'cancelled'));
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS fulfillment_updated_at TIMESTAMP;
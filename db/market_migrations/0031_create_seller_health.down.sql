@@ -0,0 +1,4 @@
+-- Drop seller health rules
+DROP TABLE IF EXISTS seller_health_actions;
+DROP TABLE IF EXISTS seller_health_rules;
+ALTER TABLE order_items DROP COLUMN IF EXISTS shipped_at;
@@ -0,0 +1,36 @@
+-- When each item shipped, so late shipments can be measured against the
+-- order time. Items shipped before this column existed use their last
+-- fulfillment update.
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS shipped_at TIMESTAMP;
+UPDATE order_items SET shipped_at = fulfillment_updated_at
+    WHERE shipped_at IS NULL AND fulfillment_status IN ('shipped', 'delivered');
+
+-- Thresholds on seller health metrics. Rates breach when above threshold,
+-- the score when below it. Sellers with fewer than min_orders orders in the
+-- window are not judged.
+CREATE TABLE IF NOT EXISTS seller_health_rules (
+    id SERIAL PRIMARY KEY,
+    metric VARCHAR(30) NOT NULL CHECK (metric IN ('score', 'cancellation_rate', 'late_shipment_rate', 'dispute_rate')),
+    threshold DECIMAL(7, 4) NOT NULL CHECK (threshold >= 0),
+    action VARCHAR(20) NOT NULL CHECK (action IN ('warn', 'suspend')),
+    min_orders INTEGER NOT NULL DEFAULT 10 CHECK (min_orders >= 0),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Warnings and suspensions applied by the rules, newest last. The latest
+-- action of a rule on a seller holds off repeats until the cooldown ends.
+CREATE TABLE IF NOT EXISTS seller_health_actions (
+    id SERIAL PRIMARY KEY,
+    seller_id INTEGER NOT NULL REFERENCES sellers(id) ON DELETE CASCADE,
+    rule_id INTEGER REFERENCES seller_health_rules(id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL,
+    metric VARCHAR(30) NOT NULL,
+    value DECIMAL(7, 4) NOT NULL,
+    threshold DECIMAL(7, 4) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_seller_health_actions_seller_id ON seller_health_actions(seller_id, created_at);
@@ -0,0 +1 @@
+ALTER TABLE sellers DROP COLUMN IF EXISTS cancellation_window_minutes;
@@ -0,0 +1,4 @@
+-- How long after purchase buyers may cancel orders containing the seller's
+-- items; NULL falls back to the order_cancellation_window setting.
+ALTER TABLE sellers ADD COLUMN IF NOT EXISTS cancellation_window_minutes INTEGER
+    CHECK (cancellation_window_minutes >= 0);
@@ -0,0 +1,50 @@
+-- Buyers rate products they received. Product and seller ratings are the
+-- averages of these reviews, kept up to date as reviews come and go.
+CREATE TABLE IF NOT EXISTS reviews (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    comment TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (product_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviews_product_created ON reviews(product_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_reviews_user_id ON reviews(user_id);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS rating DECIMAL(3, 2) NOT NULL DEFAULT 0 CHECK (rating >= 0 AND rating <= 5);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS review_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE sellers ADD COLUMN IF NOT EXISTS review_count INTEGER NOT NULL DEFAULT 0;
+
+-- The listing shows product ratings too.
+DROP MATERIALIZED VIEW IF EXISTS product_listing;
+CREATE MATERIALIZED VIEW product_listing AS
+SELECT
+    p.id,
+    p.seller_id,
+    p.category_id,
+    p.title,
+    COALESCE(p.description, '') AS description,
+    p.price::float8 AS price,
+    p.stock,
+    p.sizes,
+    COALESCE(p.image_url, '') AS image_url,
+    COALESCE(p.status, 'pending') AS status,
+    p.created_at,
+    p.updated_at,
+    p.rating::float8 AS rating,
+    p.review_count,
+    COALESCE(s.shop_name, '') AS seller_name,
+    COALESCE(s.rating, 0)::float8 AS seller_rating,
+    COALESCE(c.name, '') AS category_name
+FROM products p
+LEFT JOIN sellers s ON p.seller_id = s.id
+LEFT JOIN categories c ON p.category_id = c.id
+WHERE p.category_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_listing_id ON product_listing(id);
+CREATE INDEX IF NOT EXISTS idx_product_listing_created_at ON product_listing(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_product_listing_category_created ON product_listing(category_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_product_listing_seller_created ON product_listing(seller_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_product_listing_status_created ON product_listing(status, created_at DESC);
@@ -0,0 +1,3 @@
+-- Gift orders ship with a packing slip that shows no prices.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS is_gift BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS gift_message TEXT;
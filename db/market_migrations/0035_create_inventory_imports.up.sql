@@ -0,0 +1,27 @@
+-- Sellers update stock and prices in bulk. Every import keeps its
+-- reconciliation report; rows changing a product by more than the risk
+-- threshold are held until the seller confirms or discards them.
+CREATE TABLE IF NOT EXISTS inventory_imports (
+    id SERIAL PRIMARY KEY,
+    seller_id INTEGER NOT NULL REFERENCES sellers(id) ON DELETE CASCADE,
+    status VARCHAR(30) NOT NULL CHECK (status IN ('applied', 'awaiting_confirmation', 'confirmed', 'discarded')),
+    risk_percent DECIMAL(6, 2) NOT NULL CHECK (risk_percent >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    decided_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_imports_seller_created ON inventory_imports(seller_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS inventory_import_rows (
+    id SERIAL PRIMARY KEY,
+    import_id INTEGER NOT NULL REFERENCES inventory_imports(id) ON DELETE CASCADE,
+    line INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    stock INTEGER CHECK (stock >= 0),
+    price DECIMAL(10, 2) CHECK (price > 0),
+    previous_stock INTEGER,
+    previous_price DECIMAL(10, 2),
+    issues TEXT[] NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL CHECK (status IN ('applied', 'held', 'skipped', 'discarded')),
+    UNIQUE (import_id, line)
+);
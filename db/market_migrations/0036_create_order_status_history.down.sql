@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS order_status_history;
+
+UPDATE orders SET status = 'confirmed' WHERE status = 'paid';
+UPDATE orders SET status = 'cancelled' WHERE status = 'refunded';
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('pending', 'confirmed', 'shipped', 'delivered', 'cancelled'));
@@ -0,0 +1,4 @@
+DELETE FROM order_status_history WHERE actor = 'payment';
+ALTER TABLE order_status_history DROP CONSTRAINT IF EXISTS order_status_history_actor_check;
+ALTER TABLE order_status_history ADD CONSTRAINT order_status_history_actor_check
+    CHECK (actor IN ('admin', 'buyer', 'courier'));
@@ -0,0 +1,12 @@
+-- The legal entity a seller invoices as. Printed on statements; the tax ID
+-- and postal code are checked against the country's format when saved.
+CREATE TABLE IF NOT EXISTS seller_invoicing (
+    seller_id INTEGER PRIMARY KEY REFERENCES sellers(id) ON DELETE CASCADE,
+    legal_name VARCHAR(255) NOT NULL,
+    country CHAR(2) NOT NULL,
+    tax_id VARCHAR(32) NOT NULL,
+    address_line VARCHAR(255) NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    postal_code VARCHAR(16) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
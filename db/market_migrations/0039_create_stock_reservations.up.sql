@@ -0,0 +1,12 @@
+-- Stock held for cart items until expires_at. A product's available stock
+-- is its stock minus the unexpired reservations of other carts; expired
+-- rows are deleted by the reservation sweeper.
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    cart_item_id INTEGER PRIMARY KEY REFERENCES cart_items(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_product_expires ON stock_reservations(product_id, expires_at);
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_expires ON stock_reservations(expires_at);
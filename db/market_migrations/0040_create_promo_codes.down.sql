@@ -0,0 +1,9 @@
+ALTER TABLE seller_statements DROP COLUMN IF EXISTS discounts;
+
+ALTER TABLE order_items DROP COLUMN IF EXISTS discount_funded_by;
+ALTER TABLE order_items DROP COLUMN IF EXISTS discount_amount;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_amount;
+ALTER TABLE orders DROP COLUMN IF EXISTS promo_code;
+
+DROP TABLE IF EXISTS promo_codes;
@@ -0,0 +1,28 @@
+-- Promotion codes take percent_off off the order lines they apply to.
+-- Marketplace-funded codes apply to every line and the marketplace bears
+-- the discount; seller-funded codes apply to their seller's lines only and
+-- reduce that seller's payout.
+CREATE TABLE IF NOT EXISTS promo_codes (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    funded_by VARCHAR(20) NOT NULL CHECK (funded_by IN ('marketplace', 'seller')),
+    seller_id INTEGER REFERENCES sellers(id) ON DELETE CASCADE,
+    percent_off INTEGER NOT NULL CHECK (percent_off > 0 AND percent_off <= 100),
+    expires_at TIMESTAMP,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((funded_by = 'seller') = (seller_id IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_promo_codes_seller ON promo_codes(seller_id);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS promo_code VARCHAR(32);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS discount_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS discount_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS discount_funded_by VARCHAR(20)
+    CHECK (discount_funded_by IN ('marketplace', 'seller'));
+
+-- Only seller-funded discounts come out of the payout.
+ALTER TABLE seller_statements ADD COLUMN IF NOT EXISTS discounts NUMERIC(12, 2) NOT NULL DEFAULT 0;
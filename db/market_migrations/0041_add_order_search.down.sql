@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_orders_created_id;
+DROP INDEX IF EXISTS idx_products_title_trgm;
+DROP INDEX IF EXISTS idx_orders_buyer_email_trgm;
+DROP INDEX IF EXISTS idx_orders_order_number_trgm;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS buyer_email;
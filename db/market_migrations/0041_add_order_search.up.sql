@@ -0,0 +1,15 @@
+-- Order search: buyers search their orders by order number or product
+-- title; admins also by buyer email, seller, amount and date.
+-- buyer_email is the address the order confirmation went to; orders
+-- placed before it was recorded have none.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS buyer_email VARCHAR(255);
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Partial matches (ILIKE '%...%') on order numbers, emails and titles.
+CREATE INDEX IF NOT EXISTS idx_orders_order_number_trgm ON orders USING gin(order_number gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_orders_buyer_email_trgm ON orders USING gin(buyer_email gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_products_title_trgm ON products USING gin(title gin_trgm_ops);
+
+-- Date ranges, newest first, as the admin list is sorted.
+CREATE INDEX IF NOT EXISTS idx_orders_created_id ON orders(created_at DESC, id DESC);
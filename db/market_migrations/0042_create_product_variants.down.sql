@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_order_items_variant_id;
+DROP INDEX IF EXISTS idx_stock_reservations_variant_expires;
+
+ALTER TABLE stock_reservations DROP COLUMN IF EXISTS variant_id;
+
+ALTER TABLE order_items DROP COLUMN IF EXISTS color;
+ALTER TABLE order_items DROP COLUMN IF EXISTS sku;
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_id;
+ALTER TABLE cart_items DROP COLUMN IF EXISTS variant_id;
+
+DROP TABLE IF EXISTS product_variants;
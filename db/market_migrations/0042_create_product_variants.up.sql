@@ -0,0 +1,32 @@
+-- Product variants: each sellable combination of size and color has its own
+-- SKU, stock and price, the product's price plus price_delta. A product with
+-- variants keeps the sum of their stock and their sizes in stock and sizes,
+-- so listings and filters read as before; products without variants are
+-- sold as a whole, as they were.
+CREATE TABLE IF NOT EXISTS product_variants (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    sku VARCHAR(64) NOT NULL,
+    size VARCHAR(50) NOT NULL DEFAULT '',
+    color VARCHAR(50) NOT NULL DEFAULT '',
+    price_delta DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    stock INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (product_id, sku),
+    UNIQUE (product_id, size, color)
+);
+
+-- Cart and order items name the variant they are for; items of products
+-- without variants have none. Order items keep the SKU and color sold even
+-- if the variant is later removed.
+ALTER TABLE cart_items ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants(id) ON DELETE SET NULL;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS sku VARCHAR(64);
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS color VARCHAR(50);
+
+-- Reservations of variant items hold the variant's stock.
+ALTER TABLE stock_reservations ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_variant_expires ON stock_reservations(variant_id, expires_at) WHERE variant_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_order_items_variant_id ON order_items(variant_id) WHERE variant_id IS NOT NULL;
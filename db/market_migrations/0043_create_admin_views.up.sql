@@ -0,0 +1,13 @@
+-- Named filter presets admins save for the back office lists. query is
+-- the list's query string (e.g. status=paid&min_amount=100); each admin
+-- has their own views, and saving a name again replaces its query.
+CREATE TABLE IF NOT EXISTS admin_views (
+    id SERIAL PRIMARY KEY,
+    admin_id INTEGER NOT NULL,
+    list VARCHAR(20) NOT NULL CHECK (list IN ('orders', 'products', 'users')),
+    name VARCHAR(100) NOT NULL,
+    query TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (admin_id, list, name)
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_sellers_custom_domain;
+ALTER TABLE sellers DROP COLUMN IF EXISTS custom_domain;
@@ -0,0 +1,5 @@
+-- Host name a seller's own storefront is served on, e.g. shop.example.com.
+-- Requests for it only see the seller's catalog, and its storefront
+-- settings are stored under the domain as tenant.
+ALTER TABLE sellers ADD COLUMN IF NOT EXISTS custom_domain VARCHAR(100);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sellers_custom_domain ON sellers (custom_domain);
@@ -0,0 +1,13 @@
+-- Domain events for the message broker, written in the same transaction as
+-- the change they describe (the outbox pattern) and removed once the relay
+-- has published them. Events that fail to publish stay, with their attempts
+-- and the last error, and are retried in order.
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    key VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_event_outbox_pending;
+DELETE FROM event_outbox WHERE published_at IS NOT NULL;
+ALTER TABLE event_outbox DROP COLUMN IF EXISTS published_at;
@@ -0,0 +1,5 @@
+-- Published events are kept, marked with when the relay handed them to the
+-- broker, until the retention job purges them; only pending events are
+-- scanned by the relay.
+ALTER TABLE event_outbox ADD COLUMN IF NOT EXISTS published_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_event_outbox_pending ON event_outbox (id) WHERE published_at IS NULL;
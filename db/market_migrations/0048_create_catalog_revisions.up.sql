@@ -0,0 +1,16 @@
+-- Versioned snapshots of a seller's catalog: every product with its
+-- variants and prices, as JSONB. They are taken before spreadsheet imports
+-- and on request, and a catalog can be rolled back to one. Only the newest
+-- revisions per seller are kept.
+CREATE TABLE IF NOT EXISTS catalog_revisions (
+    id SERIAL PRIMARY KEY,
+    seller_id INTEGER NOT NULL REFERENCES sellers(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('manual', 'import', 'restore')),
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    product_count INTEGER NOT NULL DEFAULT 0,
+    products JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_by INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (seller_id, version)
+);
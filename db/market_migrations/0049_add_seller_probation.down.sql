@@ -0,0 +1,33 @@
+DROP MATERIALIZED VIEW IF EXISTS product_listing;
+CREATE MATERIALIZED VIEW product_listing AS
+SELECT
+    p.id,
+    p.seller_id,
+    p.category_id,
+    p.title,
+    COALESCE(p.description, '') AS description,
+    p.price::float8 AS price,
+    p.stock,
+    p.sizes,
+    COALESCE(p.image_url, '') AS image_url,
+    COALESCE(p.status, 'pending') AS status,
+    p.created_at,
+    p.updated_at,
+    p.rating::float8 AS rating,
+    p.review_count,
+    COALESCE(s.shop_name, '') AS seller_name,
+    COALESCE(s.rating, 0)::float8 AS seller_rating,
+    COALESCE(c.name, '') AS category_name
+FROM products p
+LEFT JOIN sellers s ON p.seller_id = s.id
+LEFT JOIN categories c ON p.category_id = c.id
+WHERE p.category_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_listing_id ON product_listing(id);
+CREATE INDEX IF NOT EXISTS idx_product_listing_created_at ON product_listing(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_product_listing_category_created ON product_listing(category_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_product_listing_seller_created ON product_listing(seller_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_product_listing_status_created ON product_listing(status, created_at DESC);
+
+ALTER TABLE sellers DROP COLUMN IF EXISTS probation_traffic_percent;
+ALTER TABLE sellers DROP COLUMN IF EXISTS probation_orders;
@@ -0,0 +1,46 @@
+-- Sellers on probation are listed to a share of visitors only until they
+-- have delivered probation_orders orders. Direct links to their products
+-- and their own storefront domain always work.
+ALTER TABLE sellers ADD COLUMN IF NOT EXISTS probation_orders INTEGER CHECK (probation_orders > 0);
+ALTER TABLE sellers ADD COLUMN IF NOT EXISTS probation_traffic_percent SMALLINT
+    CHECK (probation_traffic_percent BETWEEN 0 AND 100);
+
+-- The listing carries the traffic share of products whose seller is still
+-- on probation, NULL for everyone else.
+DROP MATERIALIZED VIEW IF EXISTS product_listing;
+CREATE MATERIALIZED VIEW product_listing AS
+SELECT
+    p.id,
+    p.seller_id,
+    p.category_id,
+    p.title,
+    COALESCE(p.description, '') AS description,
+    p.price::float8 AS price,
+    p.stock,
+    p.sizes,
+    COALESCE(p.image_url, '') AS image_url,
+    COALESCE(p.status, 'pending') AS status,
+    p.created_at,
+    p.updated_at,
+    p.rating::float8 AS rating,
+    p.review_count,
+    COALESCE(s.shop_name, '') AS seller_name,
+    COALESCE(s.rating, 0)::float8 AS seller_rating,
+    COALESCE(c.name, '') AS category_name,
+    CASE WHEN s.probation_orders > (
+        SELECT COUNT(DISTINCT oi.order_id)
+        FROM order_items oi
+        JOIN orders o ON o.id = oi.order_id
+        JOIN products sp ON sp.id = oi.product_id
+        WHERE sp.seller_id = s.id AND (oi.fulfillment_status = 'delivered' OR o.status = 'delivered')
+    ) THEN COALESCE(s.probation_traffic_percent, 0) END AS probation_traffic_percent
+FROM products p
+LEFT JOIN sellers s ON p.seller_id = s.id
+LEFT JOIN categories c ON p.category_id = c.id
+WHERE p.category_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_listing_id ON product_listing(id);
+CREATE INDEX IF NOT EXISTS idx_product_listing_created_at ON product_listing(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_product_listing_category_created ON product_listing(category_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_product_listing_seller_created ON product_listing(seller_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_product_listing_status_created ON product_listing(status, created_at DESC);
@@ -0,0 +1,14 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS package_height_mm;
+ALTER TABLE orders DROP COLUMN IF EXISTS package_width_mm;
+ALTER TABLE orders DROP COLUMN IF EXISTS package_length_mm;
+ALTER TABLE orders DROP COLUMN IF EXISTS package_weight_grams;
+
+ALTER TABLE product_variants DROP COLUMN IF EXISTS height_mm;
+ALTER TABLE product_variants DROP COLUMN IF EXISTS width_mm;
+ALTER TABLE product_variants DROP COLUMN IF EXISTS length_mm;
+ALTER TABLE product_variants DROP COLUMN IF EXISTS weight_grams;
+
+ALTER TABLE products DROP COLUMN IF EXISTS height_mm;
+ALTER TABLE products DROP COLUMN IF EXISTS width_mm;
+ALTER TABLE products DROP COLUMN IF EXISTS length_mm;
+ALTER TABLE products DROP COLUMN IF EXISTS weight_grams;
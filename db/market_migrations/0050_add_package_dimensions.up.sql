@@ -0,0 +1,18 @@
+-- Shipping weight and size of one unit, set by the seller. A variant's
+-- values override its product's; NULL means not set.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS weight_grams INTEGER CHECK (weight_grams > 0);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS length_mm INTEGER CHECK (length_mm > 0);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS width_mm INTEGER CHECK (width_mm > 0);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS height_mm INTEGER CHECK (height_mm > 0);
+
+ALTER TABLE product_variants ADD COLUMN IF NOT EXISTS weight_grams INTEGER CHECK (weight_grams > 0);
+ALTER TABLE product_variants ADD COLUMN IF NOT EXISTS length_mm INTEGER CHECK (length_mm > 0);
+ALTER TABLE product_variants ADD COLUMN IF NOT EXISTS width_mm INTEGER CHECK (width_mm > 0);
+ALTER TABLE product_variants ADD COLUMN IF NOT EXISTS height_mm INTEGER CHECK (height_mm > 0);
+
+-- The parcel computed at checkout for the carrier; NULL for orders placed
+-- before weights were recorded or whose items have none.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS package_weight_grams INTEGER;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS package_length_mm INTEGER;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS package_width_mm INTEGER;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS package_height_mm INTEGER;
@@ -0,0 +1,10 @@
+DELETE FROM payments WHERE method <> 'card';
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payments_status_check;
+ALTER TABLE payments ADD CONSTRAINT payments_status_check
+    CHECK (status IN ('pending', 'paid', 'failed', 'refunded'));
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payments_gift_card_check;
+ALTER TABLE payments DROP COLUMN IF EXISTS gift_card_id;
+ALTER TABLE payments DROP COLUMN IF EXISTS method;
+
+DROP TABLE IF EXISTS store_credits;
+DROP TABLE IF EXISTS gift_cards;
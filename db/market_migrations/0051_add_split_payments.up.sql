@@ -0,0 +1,34 @@
+-- Gift cards are issued by admins with an amount the buyer can spend on
+-- any number of orders until the balance runs out or the card expires.
+CREATE TABLE IF NOT EXISTS gift_cards (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    initial_amount DECIMAL(10, 2) NOT NULL CHECK (initial_amount > 0),
+    balance DECIMAL(10, 2) NOT NULL CHECK (balance >= 0),
+    expires_at TIMESTAMP,
+    created_by INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Store credit granted to a buyer, spent like a gift card of their own.
+CREATE TABLE IF NOT EXISTS store_credits (
+    user_id INTEGER PRIMARY KEY,
+    balance DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- An order can be paid by a gift card and store credit with the card
+-- charged for the rest, one payment per method. Balance payments are held
+-- pending, their amount already taken off the balance, until the card
+-- charge settles: they are paid with it, or voided and given back when it
+-- fails. orders.payment_status now sums up all of an order's payments.
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS method VARCHAR(20) NOT NULL DEFAULT 'card'
+    CHECK (method IN ('card', 'gift_card', 'store_credit'));
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS gift_card_id INTEGER REFERENCES gift_cards(id);
+ALTER TABLE payments ADD CONSTRAINT payments_gift_card_check
+    CHECK ((method = 'gift_card') = (gift_card_id IS NOT NULL));
+
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payments_status_check;
+ALTER TABLE payments ADD CONSTRAINT payments_status_check
+    CHECK (status IN ('pending', 'paid', 'failed', 'refunded', 'voided'));
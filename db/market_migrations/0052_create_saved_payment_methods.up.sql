@@ -0,0 +1,23 @@
+-- Buyers' customers at the payment provider, which saved payment methods
+-- belong to.
+CREATE TABLE IF NOT EXISTS payment_customers (
+    user_id INTEGER NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    customer_ref VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, provider)
+);
+
+-- Payment methods the provider keeps for one-click payments. Only the
+-- provider's IDs are stored, never card details.
+CREATE TABLE IF NOT EXISTS saved_payment_methods (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    customer_ref VARCHAR(100) NOT NULL,
+    method_ref VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, method_ref)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_payment_methods_user ON saved_payment_methods(user_id);
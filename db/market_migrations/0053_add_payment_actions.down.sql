@@ -0,0 +1,7 @@
+UPDATE payments SET status = 'pending' WHERE status = 'requires_action';
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payments_status_check;
+ALTER TABLE payments ADD CONSTRAINT payments_status_check
+    CHECK (status IN ('pending', 'paid', 'failed', 'refunded', 'voided'));
+
+ALTER TABLE payments DROP COLUMN IF EXISTS action_url;
+ALTER TABLE payments DROP COLUMN IF EXISTS action_type;
@@ -0,0 +1,9 @@
+-- Card payments the bank wants the buyer to authenticate (3-D Secure) wait
+-- in requires_action with what the buyer has to do: action_type says how,
+-- action_url where to send them. Both are cleared once the payment settles.
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payments_status_check;
+ALTER TABLE payments ADD CONSTRAINT payments_status_check
+    CHECK (status IN ('pending', 'requires_action', 'paid', 'failed', 'refunded', 'voided'));
+
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS action_type VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS action_url TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+ALTER TABLE seller_statements DROP COLUMN IF EXISTS chargebacks;
+DROP TABLE IF EXISTS payout_holds;
+DROP TABLE IF EXISTS chargebacks;
@@ -0,0 +1,40 @@
+-- Chargebacks: the buyer's bank taking a paid card payment back until the
+-- sellers prove the sale was genuine. The provider decides them won or
+-- lost.
+CREATE TABLE IF NOT EXISTS chargebacks (
+    id SERIAL PRIMARY KEY,
+    payment_id INTEGER NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    provider_ref VARCHAR(100) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount > 0),
+    reason VARCHAR(100) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'won', 'lost')),
+    evidence_due_by TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, provider_ref)
+);
+
+CREATE INDEX IF NOT EXISTS idx_chargebacks_order_id ON chargebacks(order_id);
+
+-- The share of a chargeback each seller of the order answers for, held
+-- from their payout in the month the chargeback opened. A won chargeback
+-- releases it in the month it is decided; a lost one debits it for good.
+-- ticket_id is the dispute ticket the seller submits evidence on.
+CREATE TABLE IF NOT EXISTS payout_holds (
+    id SERIAL PRIMARY KEY,
+    chargeback_id INTEGER NOT NULL REFERENCES chargebacks(id) ON DELETE CASCADE,
+    seller_id INTEGER NOT NULL REFERENCES sellers(id) ON DELETE CASCADE,
+    ticket_id INTEGER REFERENCES support_tickets(id) ON DELETE SET NULL,
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'held' CHECK (status IN ('held', 'released', 'debited')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP,
+    UNIQUE (chargeback_id, seller_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payout_holds_seller_id ON payout_holds(seller_id, created_at);
+
+-- Holds taken in the month less holds released in it
+ALTER TABLE seller_statements ADD COLUMN IF NOT EXISTS chargebacks NUMERIC(12, 2) NOT NULL DEFAULT 0;
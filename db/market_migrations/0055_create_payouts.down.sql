@@ -0,0 +1,3 @@
+ALTER TABLE seller_statements DROP COLUMN IF EXISTS payout_id;
+DROP TABLE IF EXISTS payouts;
+DROP TABLE IF EXISTS seller_payout_accounts;
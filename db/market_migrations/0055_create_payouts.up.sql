@@ -0,0 +1,38 @@
+-- Where a seller's payouts are sent: a bank account (IBAN) or a Stripe
+-- Connect account. account is sealed with the field encryption keyring;
+-- account_hint is its last four characters, shown back to the seller.
+CREATE TABLE IF NOT EXISTS seller_payout_accounts (
+    seller_id INTEGER PRIMARY KEY REFERENCES sellers(id) ON DELETE CASCADE,
+    method VARCHAR(20) NOT NULL CHECK (method IN ('bank_transfer', 'stripe_connect')),
+    holder_name VARCHAR(200) NOT NULL,
+    account TEXT NOT NULL,
+    account_hint VARCHAR(4) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Money sent to a seller for the statements linked to it. Pending payouts
+-- are sent, and retried after next_attempt_at when the provider refuses
+-- them; a payout that runs out of attempts fails and lets go of its
+-- statements, which the next batch pays again.
+CREATE TABLE IF NOT EXISTS payouts (
+    id SERIAL PRIMARY KEY,
+    seller_id INTEGER NOT NULL REFERENCES sellers(id) ON DELETE CASCADE,
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount > 0),
+    method VARCHAR(20) NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    provider_ref VARCHAR(100),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'paid', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_payouts_seller_id ON payouts(seller_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_payouts_due ON payouts(next_attempt_at) WHERE status = 'pending';
+
+-- The payout a statement was paid in; statements with one are no longer
+-- regenerated
+ALTER TABLE seller_statements ADD COLUMN IF NOT EXISTS payout_id INTEGER REFERENCES payouts(id) ON DELETE SET NULL;
@@ -0,0 +1,9 @@
+-- Product page views per product and day, flushed from the in-process
+-- counters of the Market service. Seller analytics divide orders by them
+-- for conversion.
+CREATE TABLE IF NOT EXISTS product_views (
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    views BIGINT NOT NULL DEFAULT 0 CHECK (views >= 0),
+    PRIMARY KEY (product_id, day)
+);
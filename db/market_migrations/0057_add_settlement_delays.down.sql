@@ -0,0 +1,2 @@
+ALTER TABLE sellers DROP COLUMN IF EXISTS trusted;
+ALTER TABLE categories DROP COLUMN IF EXISTS risk_level;
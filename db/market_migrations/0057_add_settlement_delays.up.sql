@@ -0,0 +1,6 @@
+-- Escrow-style settlement: a seller's sales become payable only some time
+-- after delivery, longer in riskier categories and shorter for sellers the
+-- marketplace trusts. The delays themselves are configuration.
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS risk_level VARCHAR(10) NOT NULL DEFAULT 'standard'
+    CHECK (risk_level IN ('low', 'standard', 'high'));
+ALTER TABLE sellers ADD COLUMN IF NOT EXISTS trusted BOOLEAN NOT NULL DEFAULT false;
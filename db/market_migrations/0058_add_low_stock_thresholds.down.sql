@@ -0,0 +1,2 @@
+ALTER TABLE products DROP COLUMN IF EXISTS low_stock_alerted_at;
+ALTER TABLE products DROP COLUMN IF EXISTS low_stock_threshold;
@@ -0,0 +1,9 @@
+-- Sellers set the stock at or below which a product counts as low; NULL
+-- uses the site default. low_stock_alerted_at is when the seller was last
+-- told the product ran low, cleared once it is restocked above it.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS low_stock_threshold INTEGER CHECK (low_stock_threshold >= 0);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS low_stock_alerted_at TIMESTAMP;
+
+-- Products already at or below the default threshold are not alerted on
+-- the first run.
+UPDATE products SET low_stock_alerted_at = NOW() WHERE stock <= 5;
@@ -0,0 +1,17 @@
+-- Admin-defined JSON Patch rules applied to outbound payloads: domain
+-- events sent to the broker (target event, matched by event type) and
+-- in-app notifications (target notification, matched by kind). A rule
+-- without a seller applies to everyone; '*' matches every type or kind.
+-- Rules apply in id order.
+CREATE TABLE IF NOT EXISTS transform_rules (
+    id SERIAL PRIMARY KEY,
+    seller_id INTEGER REFERENCES sellers(id) ON DELETE CASCADE,
+    target VARCHAR(20) NOT NULL CHECK (target IN ('event', 'notification')),
+    match VARCHAR(100) NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    patch JSONB NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    created_by INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS payment_locked_until;
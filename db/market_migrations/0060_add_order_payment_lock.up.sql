@@ -0,0 +1,4 @@
+-- An order being paid is claimed until payment_locked_until, so a second
+-- attempt cannot charge it again while the first waits on the provider.
+-- An attempt that never finishes only holds it until the time passes.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS payment_locked_until TIMESTAMP;
@@ -0,0 +1,8 @@
+ALTER TABLE user_merges DROP COLUMN IF EXISTS reviews;
+
+DELETE FROM reports WHERE target_type = 'review';
+ALTER TABLE reports DROP CONSTRAINT IF EXISTS reports_target_type_check;
+ALTER TABLE reports ADD CONSTRAINT reports_target_type_check
+    CHECK (target_type IN ('product', 'seller'));
+
+ALTER TABLE reviews DROP COLUMN IF EXISTS status;
@@ -0,0 +1,13 @@
+-- Review comments go through content moderation. A held review is pending
+-- until an admin resolves its automated report: dismissing it publishes
+-- the review, hiding the content hides it. Only published reviews are
+-- listed and count towards ratings.
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published'
+    CHECK (status IN ('published', 'pending', 'hidden'));
+
+ALTER TABLE reports DROP CONSTRAINT IF EXISTS reports_target_type_check;
+ALTER TABLE reports ADD CONSTRAINT reports_target_type_check
+    CHECK (target_type IN ('product', 'seller', 'review'));
+
+-- Merges count the reviews they move
+ALTER TABLE user_merges ADD COLUMN IF NOT EXISTS reviews BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE user_merges DROP COLUMN IF EXISTS store_credit;
@@ -0,0 +1,3 @@
+-- Merges add the source user's store credit to the target's balance and
+-- record the amount moved.
+ALTER TABLE user_merges ADD COLUMN IF NOT EXISTS store_credit DECIMAL(10, 2) NOT NULL DEFAULT 0;
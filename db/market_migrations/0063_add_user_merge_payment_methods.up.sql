@@ -0,0 +1,2 @@
+-- Merges move the source user's saved payment methods and count them.
+ALTER TABLE user_merges ADD COLUMN IF NOT EXISTS payment_methods BIGINT NOT NULL DEFAULT 0;
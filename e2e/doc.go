@@ -0,0 +1,9 @@
+// Package e2e runs the Auth and Market services together against real
+// Postgres and Redis containers and drives them through the generated
+// clients, so claim-format and role mismatches between the two services are
+// caught before deployment.
+//
+// The tests need Docker and are behind the integration build tag:
+//
+//	cd e2e && go test -tags integration -count=1 ./...
+package e2e
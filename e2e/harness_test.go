@@ -0,0 +1,280 @@
+//go:build integration
+// +build integration
+
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	jwtAccessSecret    = "e2e-access-secret"
+	jwtRefreshSecret   = "e2e-refresh-secret"
+	introspectClient   = "market"
+	introspectSecret   = "e2e-introspection-secret"
+	seedAdminEmail     = "admin@example.com"
+	seedAdminPassword  = "Admin123!"
+	dbUser, dbPassword = "e2e", "e2e"
+)
+
+// repoRoot is the repository root relative to this package.
+var repoRoot = filepath.Join("..")
+
+// stack is a running Auth and Market pair.
+type stack struct {
+	AuthURL   string
+	MarketURL string
+}
+
+// startStack starts Postgres and Redis containers, migrates one database per
+// service, builds both services from source and runs them. Everything is
+// torn down when the test ends; service logs are printed if it failed.
+func startStack(t *testing.T) *stack {
+	t.Helper()
+	ctx := context.Background()
+
+	pg, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "postgres:15-alpine",
+			ExposedPorts: []string{"5432/tcp"},
+			Env: map[string]string{
+				"POSTGRES_USER":     dbUser,
+				"POSTGRES_PASSWORD": dbPassword,
+				"POSTGRES_DB":       "auth",
+			},
+			WaitingFor: wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(60 * time.Second),
+		},
+		Started: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start postgres: %v", err)
+	}
+	t.Cleanup(func() { pg.Terminate(ctx) })
+
+	redis, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "redis:7-alpine",
+			ExposedPorts: []string{"6379/tcp"},
+			WaitingFor:   wait.ForLog("Ready to accept connections").WithStartupTimeout(30 * time.Second),
+		},
+		Started: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start redis: %v", err)
+	}
+	t.Cleanup(func() { redis.Terminate(ctx) })
+
+	pgHost, _ := pg.Host(ctx)
+	pgPort, _ := pg.MappedPort(ctx, "5432")
+	redisHost, _ := redis.Host(ctx)
+	redisPort, _ := redis.MappedPort(ctx, "6379")
+	redisAddr := net.JoinHostPort(redisHost, redisPort.Port())
+
+	dsn := func(db string) string {
+		return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", dbUser, dbPassword, pgHost, pgPort.Port(), db)
+	}
+	execSQL(t, dsn("auth"), "CREATE DATABASE market")
+	migrate(t, dsn("auth"), filepath.Join(repoRoot, "db", "auth_migrations"))
+	migrate(t, dsn("market"), filepath.Join(repoRoot, "db", "market_migrations"))
+
+	bin := t.TempDir()
+	authBin := build(t, filepath.Join(repoRoot, "service", "Auth"), filepath.Join(bin, "auth"))
+	marketBin := build(t, filepath.Join(repoRoot, "service", "Market"), filepath.Join(bin, "market"))
+
+	authPort, authGRPCPort, marketPort := freePort(t), freePort(t), freePort(t)
+	s := &stack{
+		AuthURL:   fmt.Sprintf("http://127.0.0.1:%d", authPort),
+		MarketURL: fmt.Sprintf("http://127.0.0.1:%d", marketPort),
+	}
+
+	common := map[string]string{
+		"DB_HOST":            pgHost,
+		"DB_PORT":            pgPort.Port(),
+		"DB_USER":            dbUser,
+		"DB_PASSWORD":        dbPassword,
+		"DB_SSLMODE":         "disable",
+		"REDIS_ENABLED":      "true",
+		"RATE_LIMIT_ENABLED": "false",
+		"REDIS_ADDR":         redisAddr,
+		"JWT_ACCESS_SECRET":  jwtAccessSecret,
+		"JWT_AUDIENCE":       "marketback",
+		"LOG_LEVEL":          "debug",
+		"LOG_FORMAT":         "text",
+		// Emails are captured and read back through GET /api/dev/outbox
+		"MAIL_SANDBOX": "true",
+		"MAIL_FROM":    "noreply@marketback.test",
+	}
+	run(t, "auth", authBin, merge(common, map[string]string{
+		"HTTP_HOST":             fmt.Sprintf("127.0.0.1:%d", authPort),
+		"DB_NAME":               "auth",
+		"REDIS_DB":              "1",
+		"JWT_REFRESH_SECRET":    jwtRefreshSecret,
+		"INTROSPECTION_CLIENTS": introspectClient + ":" + introspectSecret,
+		"GRPC_HOST":             fmt.Sprintf("127.0.0.1:%d", authGRPCPort),
+		"LOGIN_ALERTS_ENABLED":  "false",
+	}))
+	waitHealthy(t, s.AuthURL)
+
+	run(t, "market", marketBin, merge(common, map[string]string{
+		"HTTP_HOST":  fmt.Sprintf("127.0.0.1:%d", marketPort),
+		"DB_NAME":    "market",
+		"REDIS_DB":   "0",
+		"UPLOAD_DIR": t.TempDir(),
+		"BASE_URL":   s.MarketURL,
+		// Products reach GET /api/products through the listing view
+		"LISTING_REFRESH_INTERVAL": "250ms",
+		"AUTH_URL":                 s.AuthURL,
+		"AUTH_GRPC_ADDR":           fmt.Sprintf("127.0.0.1:%d", authGRPCPort),
+		"AUTH_CLIENT_ID":           introspectClient,
+		"AUTH_CLIENT_SECRET":       introspectSecret,
+		// Every request is introspected, so a logout is visible at once
+		"AUTH_SESSION_CACHE_TTL": "0s",
+	}))
+	waitHealthy(t, s.MarketURL)
+
+	return s
+}
+
+func execSQL(t *testing.T, dsn, sql string) {
+	t.Helper()
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to %s: %v", dsn, err)
+	}
+	defer conn.Close(ctx)
+	if _, err := conn.Exec(ctx, sql); err != nil {
+		t.Fatalf("failed to run %q: %v", sql, err)
+	}
+}
+
+// migrate applies the up migrations of a service in order. The Market dev
+// seed is skipped: it creates a seller for user 2, who is a fresh account
+// in these tests.
+func migrate(t *testing.T, dsn, dir string) {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		if strings.Contains(filepath.Base(file), "dev_seed") {
+			continue
+		}
+		sql, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		execSQL(t, dsn, string(sql))
+	}
+}
+
+func build(t *testing.T, dir, out string) string {
+	t.Helper()
+	cmd := exec.Command("go", "build", "-o", out, "./cmd/main.go")
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to build %s: %v\n%s", dir, err, output)
+	}
+	return out
+}
+
+// syncBuffer collects service output written from the process's goroutines.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func run(t *testing.T, name, bin string, env map[string]string) {
+	t.Helper()
+	cmd := exec.Command(bin)
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	logs := &syncBuffer{}
+	cmd.Stdout, cmd.Stderr = logs, logs
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start %s: %v", name, err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Signal(os.Interrupt)
+		done := make(chan struct{})
+		go func() { cmd.Wait(); close(done) }()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			cmd.Process.Kill()
+		}
+		if t.Failed() {
+			t.Logf("%s logs:\n%s", name, logs.String())
+		}
+	})
+}
+
+func waitHealthy(t *testing.T, baseURL string) {
+	t.Helper()
+	deadline := time.Now().Add(60 * time.Second)
+	for time.Now().Before(deadline) {
+		resp, err := http.Get(baseURL + "/health")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return
+			}
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+	t.Fatalf("%s did not become healthy", baseURL)
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func merge(maps ...map[string]string) map[string]string {
+	out := map[string]string{}
+	for _, m := range maps {
+		for k, v := range m {
+			out[k] = v
+		}
+	}
+	return out
+}
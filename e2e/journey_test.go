@@ -0,0 +1,294 @@
+//go:build integration
+// +build integration
+
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/clients/auth"
+	"github.com/Zifeldev/marketback/clients/market"
+)
+
+// session is one signed-in account with clients for both services.
+type session struct {
+	tokens *auth.TokenPair
+	auth   *auth.Client
+	market *market.Client
+}
+
+func (s *stack) session(tokens *auth.TokenPair) *session {
+	return &session{
+		tokens: tokens,
+		auth:   auth.NewClient(s.AuthURL, auth.WithToken(tokens.AccessToken)),
+		market: market.NewClient(s.MarketURL, market.WithToken(tokens.AccessToken)),
+	}
+}
+
+func (s *stack) login(t *testing.T, email, password string) *session {
+	t.Helper()
+	tokens, err := auth.NewClient(s.AuthURL).LoginUser(context.Background(), &auth.LoginRequest{Email: email, Password: password})
+	if err != nil {
+		t.Fatalf("failed to log in %s: %v", email, err)
+	}
+	return s.session(tokens)
+}
+
+func (s *stack) register(t *testing.T, email, password string) *session {
+	t.Helper()
+	tokens, err := auth.NewClient(s.AuthURL).RegisterUser(context.Background(), &auth.RegisterRequest{Email: email, Password: password})
+	if err != nil {
+		t.Fatalf("failed to register %s: %v", email, err)
+	}
+	return s.session(tokens)
+}
+
+// requireStatus fails unless err is an API error with the given status.
+func requireStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	var marketErr *market.Error
+	var authErr *auth.Error
+	switch {
+	case errors.As(err, &marketErr):
+		if marketErr.StatusCode != status {
+			t.Fatalf("expected %d, got %v", status, err)
+		}
+	case errors.As(err, &authErr):
+		if authErr.StatusCode != status {
+			t.Fatalf("expected %d, got %v", status, err)
+		}
+	default:
+		t.Fatalf("expected a %d API error, got %v", status, err)
+	}
+}
+
+// waitListed polls GET /api/products until the product shows up, since the
+// listing view is refreshed in the background.
+func waitListed(t *testing.T, c *market.Client, productID int) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		page, err := c.GetAllProducts(context.Background(), nil)
+		noErr(t, err)
+		var listed []market.Product
+		if err := json.Unmarshal(page.Data, &listed); err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range listed {
+			if p.ID == productID {
+				return
+			}
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+	t.Fatalf("product %d not listed after activation", productID)
+}
+
+// waitEmail polls a mail sandbox outbox until a message with the subject
+// arrives, since emails are sent in the background.
+func waitEmail(t *testing.T, subjects func() ([]string, error), want string) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		got, err := subjects()
+		noErr(t, err)
+		for _, subject := range got {
+			if subject == want {
+				return
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("no email %q in the outbox", want)
+}
+
+func noErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestJourney walks a buyer, a seller and an admin through both services
+// using only tokens minted by Auth, so Market's view of the claims is tested
+// against the real issuer rather than hand-built JWTs.
+func TestJourney(t *testing.T) {
+	s := startStack(t)
+	ctx := context.Background()
+
+	admin := s.login(t, seedAdminEmail, seedAdminPassword)
+	buyer := s.register(t, "buyer@example.com", "Buyer123!")
+	seller := s.register(t, "seller@example.com", "Seller123!")
+
+	// Seller role is requested in Auth and granted by the admin; the new
+	// role is only in tokens issued after the approval.
+	request, err := seller.auth.ApplyForPrivilegedRole(ctx, &auth.CreateRoleRequestRequest{Role: "seller", Message: "e2e shop"})
+	noErr(t, err)
+	_, err = admin.auth.ApproveRoleRequest(ctx, request.ID, &auth.ReviewRoleRequestRequest{Note: "ok"})
+	noErr(t, err)
+	seller = s.login(t, "seller@example.com", "Seller123!")
+
+	profile, err := seller.market.RegisterSellerProfile(ctx, &market.CreateSellerRequest{ShopName: "E2E Shop"})
+	noErr(t, err)
+	_, err = admin.market.UpdateSellerStatus(ctx, profile.ID, &market.UpdateSellerStatusRequest{IsActive: true})
+	noErr(t, err)
+	// The admin's seller list names the owner, looked up in Auth over gRPC
+	sellers, err := admin.market.GetAllSellers(ctx)
+	noErr(t, err)
+	for _, sl := range sellers {
+		if sl.ID == profile.ID && sl.UserEmail != "seller@example.com" {
+			t.Fatalf("seller user_email = %q", sl.UserEmail)
+		}
+	}
+	category, err := admin.market.CreateCategory(ctx, &market.CreateCategoryRequest{Name: "E2E"})
+	noErr(t, err)
+
+	product, err := seller.market.CreateProduct(ctx, &market.CreateProductRequest{
+		CategoryID: category.ID,
+		Title:      "E2E mug",
+		Price:      12.5,
+		Stock:      3,
+	})
+	noErr(t, err)
+	_, err = admin.market.UpdateProductStatus(ctx, product.ID, &market.UpdateProductStatusRequest{Status: "active"})
+	noErr(t, err)
+
+	t.Run("buyer checks out", func(t *testing.T) {
+		waitListed(t, buyer.market, product.ID)
+
+		_, err := buyer.market.AddItemToCart(ctx, &market.AddToCartRequest{ProductID: product.ID, Quantity: 2})
+		noErr(t, err)
+		order, err := buyer.market.CreateOrder(ctx, &market.CreateOrderRequest{
+			DeliveryAddress: "1 Test Street",
+			PaymentMethod:   "card",
+		})
+		noErr(t, err)
+		if order.TotalAmount != 25 || len(order.Items) != 1 {
+			t.Fatalf("unexpected order %+v", order)
+		}
+		waitEmail(t, func() ([]string, error) {
+			messages, err := buyer.market.ListCapturedEmails(ctx, &market.ListCapturedEmailsParams{To: "buyer@example.com"})
+			subjects := make([]string, len(messages))
+			for i, m := range messages {
+				subjects[i] = m.Subject
+			}
+			return subjects, err
+		}, "Order "+order.OrderNumber+" confirmed")
+
+		page, err := buyer.market.GetUserOrders(ctx, nil)
+		noErr(t, err)
+		var mine []market.Order
+		if err := json.Unmarshal(page.Data, &mine); err != nil {
+			t.Fatal(err)
+		}
+		if len(mine) != 1 || mine[0].ID != order.ID {
+			t.Fatalf("buyer orders = %+v", mine)
+		}
+
+		updated, err := admin.market.UpdateOrderStatus(ctx, order.ID, &market.UpdateOrderStatusRequest{Status: "confirmed"})
+		noErr(t, err)
+		if updated.Status != "confirmed" {
+			t.Fatalf("order status = %q", updated.Status)
+		}
+		// Status changes are emailed from the event outbox
+		waitEmail(t, func() ([]string, error) {
+			messages, err := buyer.market.ListCapturedEmails(ctx, &market.ListCapturedEmailsParams{To: "buyer@example.com"})
+			subjects := make([]string, len(messages))
+			for i, m := range messages {
+				subjects[i] = m.Subject
+			}
+			return subjects, err
+		}, "Order "+order.OrderNumber+" is confirmed")
+
+		page, err = seller.market.GetSellerOrders(ctx, &market.GetSellerOrdersParams{FulfillmentStatus: "pending"})
+		noErr(t, err)
+		var sold []market.SellerOrder
+		if err := json.Unmarshal(page.Data, &sold); err != nil {
+			t.Fatal(err)
+		}
+		if len(sold) != 1 || sold[0].ID != order.ID || len(sold[0].Items) != 1 || sold[0].SellerTotal != 25 {
+			t.Fatalf("seller orders = %+v", sold)
+		}
+
+		shipped, err := seller.market.UpdateFulfillmentStatus(ctx, order.ID, &market.UpdateFulfillmentRequest{Status: "shipped"})
+		noErr(t, err)
+		if shipped.Items[0].FulfillmentStatus != "shipped" {
+			t.Fatalf("item fulfillment = %q", shipped.Items[0].FulfillmentStatus)
+		}
+		_, err = seller.market.UpdateFulfillmentStatus(ctx, order.ID, &market.UpdateFulfillmentRequest{Status: "cancelled"})
+		requireStatus(t, err, http.StatusConflict)
+		_, err = buyer.market.GetSellerOrder(ctx, order.ID)
+		requireStatus(t, err, http.StatusForbidden)
+	})
+
+	t.Run("roles are enforced across services", func(t *testing.T) {
+		_, err := buyer.market.CreateProduct(ctx, &market.CreateProductRequest{CategoryID: category.ID, Title: "nope", Price: 1, Stock: 1})
+		requireStatus(t, err, http.StatusForbidden)
+
+		_, err = seller.market.CreateCategory(ctx, &market.CreateCategoryRequest{Name: "nope"})
+		requireStatus(t, err, http.StatusForbidden)
+
+		_, err = buyer.auth.ApproveRoleRequest(ctx, request.ID, &auth.ReviewRoleRequestRequest{})
+		requireStatus(t, err, http.StatusForbidden)
+	})
+
+	t.Run("scoped tokens are read only", func(t *testing.T) {
+		scoped, err := buyer.auth.IssueScopedAccessToken(ctx, &auth.ScopedTokenRequest{Scope: "market:read"})
+		noErr(t, err)
+		reader := market.NewClient(s.MarketURL, market.WithToken(scoped.AccessToken))
+
+		_, err = reader.GetUserOrders(ctx, nil)
+		noErr(t, err)
+		_, err = reader.AddItemToCart(ctx, &market.AddToCartRequest{ProductID: product.ID, Quantity: 1})
+		requireStatus(t, err, http.StatusForbidden)
+	})
+
+	t.Run("welcome email is captured", func(t *testing.T) {
+		waitEmail(t, func() ([]string, error) {
+			messages, err := buyer.auth.ListCapturedEmails(ctx, &auth.ListCapturedEmailsParams{To: "buyer@example.com"})
+			subjects := make([]string, len(messages))
+			for i, m := range messages {
+				subjects[i] = m.Subject
+			}
+			return subjects, err
+		}, "Welcome to Marketback")
+	})
+
+	t.Run("verification email is captured", func(t *testing.T) {
+		waitEmail(t, func() ([]string, error) {
+			messages, err := buyer.auth.ListCapturedEmails(ctx, &auth.ListCapturedEmailsParams{To: "buyer@example.com"})
+			subjects := make([]string, len(messages))
+			for i, m := range messages {
+				subjects[i] = m.Subject
+			}
+			return subjects, err
+		}, "Verify your email address")
+	})
+
+	t.Run("password reset email is captured", func(t *testing.T) {
+		_, err := buyer.auth.RequestPasswordResetEmail(ctx, &auth.PasswordResetRequest{Email: "buyer@example.com"})
+		noErr(t, err)
+
+		waitEmail(t, func() ([]string, error) {
+			messages, err := buyer.auth.ListCapturedEmails(ctx, &auth.ListCapturedEmailsParams{To: "buyer@example.com"})
+			subjects := make([]string, len(messages))
+			for i, m := range messages {
+				subjects[i] = m.Subject
+			}
+			return subjects, err
+		}, "Reset your password")
+	})
+
+	t.Run("logout revokes market access", func(t *testing.T) {
+		_, err := buyer.auth.LogoutUser(ctx, &auth.RefreshRequest{RefreshToken: buyer.tokens.RefreshToken})
+		noErr(t, err)
+
+		_, err = buyer.market.GetUserOrders(ctx, nil)
+		requireStatus(t, err, http.StatusUnauthorized)
+	})
+}
@@ -0,0 +1,5 @@
+// Package pkg holds the server-side code the Marketback services share:
+// fieldcrypt, their encryption of personal data columns, logsampling, their
+// debug log sampling, and phone, their phone number validation. Unlike the
+// clients module it is not meant for API consumers.
+package pkg
@@ -0,0 +1,171 @@
+// Package fieldcrypt encrypts personal data stored in database columns, for
+// every Marketback service that stores it. Values are sealed with
+// AES-256-GCM under a rotatable keyring; an Index lets sealed columns still
+// be looked up by value.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// prefix marks values produced by Encrypt. Values without it are treated as
+// legacy plaintext so existing rows keep working until they are rotated.
+const prefix = "enc:v1:"
+
+var (
+	ErrUnknownKey   = errors.New("unknown encryption key id")
+	ErrInvalidValue = errors.New("invalid encrypted value")
+)
+
+// Keyring holds AES-256-GCM keys indexed by key id. New values are always
+// sealed with the primary key; any known key can open existing values.
+type Keyring struct {
+	primary string
+	aeads   map[string]cipher.AEAD
+}
+
+// ParseKeyring parses "kid:base64key[,kid:base64key...]". The first entry
+// becomes the primary key.
+func ParseKeyring(spec string) (*Keyring, error) {
+	k := &Keyring{aeads: make(map[string]cipher.AEAD)}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kid, encoded, ok := strings.Cut(entry, ":")
+		if !ok || kid == "" {
+			return nil, fmt.Errorf("invalid key entry %q: expected kid:base64key", entry)
+		}
+		if _, exists := k.aeads[kid]; exists {
+			return nil, fmt.Errorf("duplicate key id %q", kid)
+		}
+
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", kid, err)
+		}
+		if len(raw) != 32 {
+			return nil, fmt.Errorf("invalid key %q: must be 32 bytes, got %d", kid, len(raw))
+		}
+
+		block, err := aes.NewCipher(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", kid, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", kid, err)
+		}
+
+		k.aeads[kid] = aead
+		if k.primary == "" {
+			k.primary = kid
+		}
+	}
+
+	if k.primary == "" {
+		return nil, errors.New("keyring is empty")
+	}
+
+	return k, nil
+}
+
+// Load builds a keyring from an inline spec or from a mounted secret file.
+// It returns nil when neither is set, which disables encryption.
+func Load(spec, file string) (*Keyring, error) {
+	if spec == "" && file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keyring file: %w", err)
+		}
+		spec = strings.TrimSpace(string(data))
+	}
+	if spec == "" {
+		return nil, nil
+	}
+	return ParseKeyring(spec)
+}
+
+// PrimaryKeyID returns the id of the key used for new values
+func (k *Keyring) PrimaryKeyID() string {
+	if k == nil {
+		return ""
+	}
+	return k.primary
+}
+
+// Encrypt seals plaintext with the primary key. A nil keyring returns the
+// value unchanged.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if k == nil || plaintext == "" {
+		return plaintext, nil
+	}
+
+	aead := k.aeads[k.primary]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(k.primary))
+	return prefix + k.primary + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value produced by Encrypt. Plaintext values are returned
+// as-is.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	if k == nil {
+		return "", ErrUnknownKey
+	}
+
+	kid, encoded, ok := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	if !ok {
+		return "", ErrInvalidValue
+	}
+	aead, ok := k.aeads[kid]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKey, kid)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrInvalidValue
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(kid))
+	if err != nil {
+		return "", ErrInvalidValue
+	}
+
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether value is plaintext or sealed with a key
+// other than the primary one.
+func (k *Keyring) NeedsRotation(value string) bool {
+	if k == nil || value == "" {
+		return false
+	}
+	if !IsEncrypted(value) {
+		return true
+	}
+	return !strings.HasPrefix(value, prefix+k.primary+":")
+}
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
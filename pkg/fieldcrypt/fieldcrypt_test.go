@@ -0,0 +1,176 @@
+package fieldcrypt
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))
+}
+
+func mustKeyring(t *testing.T, spec string) *Keyring {
+	t.Helper()
+	k, err := ParseKeyring(spec)
+	if err != nil {
+		t.Fatalf("ParseKeyring(%q): %v", spec, err)
+	}
+	return k
+}
+
+func mustEncrypt(t *testing.T, k *Keyring, value string) string {
+	t.Helper()
+	enc, err := k.Encrypt(value)
+	if err != nil {
+		t.Fatalf("Encrypt(%q): %v", value, err)
+	}
+	return enc
+}
+
+func mustDecrypt(t *testing.T, k *Keyring, value string) string {
+	t.Helper()
+	dec, err := k.Decrypt(value)
+	if err != nil {
+		t.Fatalf("Decrypt(%q): %v", value, err)
+	}
+	return dec
+}
+
+func TestKeyring_RoundTrip(t *testing.T) {
+	k := mustKeyring(t, "k1:"+testKey('a'))
+
+	enc := mustEncrypt(t, k, "221B Baker Street")
+	if !IsEncrypted(enc) || strings.Contains(enc, "Baker") {
+		t.Errorf("Encrypt = %q, want a sealed value", enc)
+	}
+	if dec := mustDecrypt(t, k, enc); dec != "221B Baker Street" {
+		t.Errorf("Decrypt = %q, want %q", dec, "221B Baker Street")
+	}
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	enc := mustEncrypt(t, mustKeyring(t, "k1:"+testKey('a')), "secret")
+
+	rotated := mustKeyring(t, "k2:"+testKey('b')+",k1:"+testKey('a'))
+	if id := rotated.PrimaryKeyID(); id != "k2" {
+		t.Errorf("PrimaryKeyID = %q, want k2", id)
+	}
+	if !rotated.NeedsRotation(enc) {
+		t.Error("value sealed with k1 does not need rotation")
+	}
+
+	dec := mustDecrypt(t, rotated, enc)
+	if dec != "secret" {
+		t.Errorf("Decrypt = %q, want secret", dec)
+	}
+	if rotated.NeedsRotation(mustEncrypt(t, rotated, dec)) {
+		t.Error("value sealed with the primary key needs rotation")
+	}
+}
+
+func TestKeyring_LegacyPlaintext(t *testing.T) {
+	k := mustKeyring(t, "k1:"+testKey('a'))
+
+	if dec := mustDecrypt(t, k, "plain address"); dec != "plain address" {
+		t.Errorf("Decrypt = %q, want plain address", dec)
+	}
+	if !k.NeedsRotation("plain address") {
+		t.Error("plaintext does not need rotation")
+	}
+}
+
+func TestKeyring_Errors(t *testing.T) {
+	k := mustKeyring(t, "k1:"+testKey('a'))
+
+	if _, err := k.Decrypt("enc:v1:k9:AAAA"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Decrypt with an unknown key: %v, want ErrUnknownKey", err)
+	}
+	enc := mustEncrypt(t, k, "secret")
+	if _, err := k.Decrypt(enc[:len(enc)-4] + "AAAA"); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("Decrypt of a tampered value: %v, want ErrInvalidValue", err)
+	}
+
+	for _, spec := range []string{
+		"",
+		"k1:" + base64.StdEncoding.EncodeToString([]byte("short")),
+		"k1:" + testKey('a') + ",k1:" + testKey('b'),
+	} {
+		if _, err := ParseKeyring(spec); err == nil {
+			t.Errorf("ParseKeyring(%q) succeeded", spec)
+		}
+	}
+}
+
+func TestKeyring_Nil(t *testing.T) {
+	var k *Keyring
+
+	if enc := mustEncrypt(t, k, "plain"); enc != "plain" {
+		t.Errorf("Encrypt = %q, want plain", enc)
+	}
+	if k.NeedsRotation("plain") {
+		t.Error("nil keyring asks for rotation")
+	}
+	if _, err := k.Decrypt("enc:v1:k1:AAAA"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Decrypt: %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestLoad_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(path, []byte("k1:"+testKey('a')+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	k, err := Load("", path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if id := k.PrimaryKeyID(); id != "k1" {
+		t.Errorf("PrimaryKeyID = %q, want k1", id)
+	}
+
+	if k, err = Load("", ""); err != nil || k != nil {
+		t.Errorf("Load with nothing set = %v, %v, want nil", k, err)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	i, err := ParseIndex(testKey('a'))
+	if err != nil {
+		t.Fatalf("ParseIndex: %v", err)
+	}
+
+	h := i.Hash("+4915112345678")
+	if !IsIndexed(h) || strings.Contains(h, "151") {
+		t.Errorf("Hash = %q, want a blind index", h)
+	}
+	if again := i.Hash("+4915112345678"); again != h {
+		t.Errorf("Hash is not deterministic: %q != %q", again, h)
+	}
+	if other := i.Hash("+4915112345679"); other == h {
+		t.Error("different values share an index")
+	}
+
+	j, _ := ParseIndex(testKey('b'))
+	if j.Hash("+4915112345678") == h {
+		t.Error("different keys share an index")
+	}
+
+	if _, err := ParseIndex(base64.StdEncoding.EncodeToString([]byte("short"))); err == nil {
+		t.Error("ParseIndex accepted a short key")
+	}
+}
+
+func TestIndex_Nil(t *testing.T) {
+	i, err := LoadIndex("", "")
+	if err != nil || i != nil {
+		t.Fatalf("LoadIndex with nothing set = %v, %v, want nil", i, err)
+	}
+	if h := i.Hash("plain"); h != "plain" {
+		t.Errorf("Hash = %q, want plain", h)
+	}
+}
@@ -0,0 +1,65 @@
+package fieldcrypt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// indexPrefix marks values produced by Hash, so lookups can tell them from
+// legacy plaintext rows.
+const indexPrefix = "hmac:v1:"
+
+// Index derives blind indexes: keyed HMAC-SHA256 digests that let a column
+// sealed with a Keyring be matched by equality. Unlike a keyring its key
+// cannot be rotated, since every stored digest would change with it.
+type Index struct {
+	key []byte
+}
+
+// ParseIndex parses a base64 key of at least 32 bytes
+func ParseIndex(encoded string) (*Index, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid index key: %w", err)
+	}
+	if len(raw) < 32 {
+		return nil, fmt.Errorf("invalid index key: must be at least 32 bytes, got %d", len(raw))
+	}
+	return &Index{key: raw}, nil
+}
+
+// LoadIndex builds an index from an inline key or from a mounted secret
+// file. It returns nil when neither is set, which disables hashing.
+func LoadIndex(encoded, file string) (*Index, error) {
+	if encoded == "" && file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read index key file: %w", err)
+		}
+		encoded = strings.TrimSpace(string(data))
+	}
+	if encoded == "" {
+		return nil, nil
+	}
+	return ParseIndex(encoded)
+}
+
+// Hash returns the blind index of value. A nil index returns the value
+// unchanged.
+func (i *Index) Hash(value string) string {
+	if i == nil || value == "" {
+		return value
+	}
+	mac := hmac.New(sha256.New, i.key)
+	mac.Write([]byte(value))
+	return indexPrefix + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// IsIndexed reports whether value was produced by Hash
+func IsIndexed(value string) bool {
+	return strings.HasPrefix(value, indexPrefix)
+}
@@ -0,0 +1,7 @@
+module github.com/Zifeldev/marketback/pkg
+
+go 1.24.2
+
+require github.com/sirupsen/logrus v1.9.3
+
+require golang.org/x/sys v0.34.0 // indirect
@@ -0,0 +1,65 @@
+// Package logsampling thins out repeated debug and trace log entries for
+// every Marketback service.
+package logsampling
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Formatter drops repeated debug and trace entries. Entries at info
+// level and above are always formatted.
+type Formatter struct {
+	logrus.Formatter
+
+	first      int
+	thereafter int
+	tick       time.Duration
+
+	mu     sync.Mutex
+	window time.Time
+	counts map[string]int
+}
+
+// NewFormatter wraps inner. Within each tick the first entries with the same
+// level and message are kept, then every thereafter-th one.
+func NewFormatter(inner logrus.Formatter, first, thereafter int, tick time.Duration) *Formatter {
+	if tick <= 0 {
+		tick = time.Second
+	}
+	return &Formatter{
+		Formatter:  inner,
+		first:      first,
+		thereafter: thereafter,
+		tick:       tick,
+		counts:     make(map[string]int),
+	}
+}
+
+func (f *Formatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if entry.Level <= logrus.InfoLevel || f.allow(entry) {
+		return f.Formatter.Format(entry)
+	}
+	return nil, nil
+}
+
+func (f *Formatter) allow(entry *logrus.Entry) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if entry.Time.Sub(f.window) >= f.tick {
+		f.window = entry.Time
+		f.counts = make(map[string]int)
+	}
+
+	key := entry.Level.String() + "|" + entry.Message
+	f.counts[key]++
+	n := f.counts[key]
+
+	if n <= f.first {
+		return true
+	}
+	return f.thereafter > 0 && (n-f.first)%f.thereafter == 0
+}
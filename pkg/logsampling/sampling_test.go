@@ -0,0 +1,65 @@
+package logsampling
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newLogger(first, thereafter int) (*logrus.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	log := logrus.New()
+	log.SetLevel(logrus.DebugLevel)
+	log.SetOutput(&buf)
+	log.SetFormatter(NewFormatter(&logrus.JSONFormatter{}, first, thereafter, time.Hour))
+	return log, &buf
+}
+
+func TestFormatter_DebugSampled(t *testing.T) {
+	log, buf := newLogger(2, 3)
+
+	for i := 0; i < 8; i++ {
+		log.Debug("cache lookup")
+	}
+
+	// entries 1, 2, then every 3rd after the first two: 5, 8
+	if n := strings.Count(buf.String(), "cache lookup"); n != 4 {
+		t.Errorf("logged %d entries, want 4", n)
+	}
+}
+
+func TestFormatter_InfoNotSampled(t *testing.T) {
+	log, buf := newLogger(1, 0)
+
+	for i := 0; i < 5; i++ {
+		log.Info("order created")
+	}
+
+	if n := strings.Count(buf.String(), "order created"); n != 5 {
+		t.Errorf("logged %d entries, want 5", n)
+	}
+}
+
+func TestFormatter_WindowResets(t *testing.T) {
+	f := NewFormatter(&logrus.JSONFormatter{}, 1, 0, time.Second)
+	start := time.Now()
+
+	entry := &logrus.Entry{Level: logrus.DebugLevel, Message: "tick", Time: start, Data: logrus.Fields{}}
+	out, err := f.Format(entry)
+	if err != nil || len(out) == 0 {
+		t.Fatalf("first entry = %q, %v, want it formatted", out, err)
+	}
+
+	entry.Time = start.Add(100 * time.Millisecond)
+	if out, _ = f.Format(entry); len(out) != 0 {
+		t.Errorf("repeated entry = %q, want it dropped", out)
+	}
+
+	entry.Time = start.Add(2 * time.Second)
+	if out, _ = f.Format(entry); len(out) == 0 {
+		t.Error("entry in a new window was dropped")
+	}
+}
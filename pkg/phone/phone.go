@@ -0,0 +1,165 @@
+// Package phone normalizes phone numbers to E.164 and validates them, for
+// every Marketback service that collects them. Numbers written with a
+// country code (+49…, 0049…) carry their country; national numbers
+// (0151…) are read as numbers of a region the caller names.
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalid is wrapped by every error about a malformed number; the
+// message of the wrapping error says what is wrong with it.
+var ErrInvalid = errors.New("invalid phone number")
+
+var (
+	regionPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+	// separators may appear between digits; a leading + is kept apart
+	separators = strings.NewReplacer(" ", "", "-", "", ".", "", "/", "", "(", "", ")", "")
+)
+
+// plan is how one country code numbers its phones: the length of the
+// national significant number (after the country code) and the trunk
+// prefix dialled before national numbers, dropped in E.164.
+type plan struct {
+	Regions []string
+	Min     int
+	Max     int
+	Trunk   string
+}
+
+// plans covers the countries most customers and sellers are in. Other
+// country codes get the general E.164 checks only. The first region of a
+// shared code is the one Region reports.
+var plans = map[string]plan{
+	"1":   {[]string{"US", "CA"}, 10, 10, "1"},
+	"7":   {[]string{"RU", "KZ"}, 10, 10, "8"},
+	"31":  {[]string{"NL"}, 9, 9, "0"},
+	"32":  {[]string{"BE"}, 8, 9, "0"},
+	"33":  {[]string{"FR"}, 9, 9, "0"},
+	"34":  {[]string{"ES"}, 9, 9, ""},
+	"39":  {[]string{"IT"}, 6, 11, ""},
+	"41":  {[]string{"CH"}, 9, 9, "0"},
+	"43":  {[]string{"AT"}, 4, 13, "0"},
+	"44":  {[]string{"GB"}, 9, 10, "0"},
+	"45":  {[]string{"DK"}, 8, 8, ""},
+	"46":  {[]string{"SE"}, 7, 9, "0"},
+	"47":  {[]string{"NO"}, 8, 8, ""},
+	"48":  {[]string{"PL"}, 9, 9, ""},
+	"49":  {[]string{"DE"}, 6, 13, "0"},
+	"61":  {[]string{"AU"}, 9, 9, "0"},
+	"91":  {[]string{"IN"}, 10, 10, "0"},
+	"351": {[]string{"PT"}, 9, 9, ""},
+	"353": {[]string{"IE"}, 7, 9, "0"},
+	"380": {[]string{"UA"}, 9, 9, "0"},
+	"420": {[]string{"CZ"}, 9, 9, ""},
+}
+
+// countryCodes maps a region to its country code.
+var countryCodes = func() map[string]string {
+	m := make(map[string]string)
+	for code, p := range plans {
+		for _, region := range p.Regions {
+			m[region] = code
+		}
+	}
+	return m
+}()
+
+// Normalize returns number in E.164 form (+4915112345678). Spaces, dots,
+// dashes, slashes and parentheses are ignored. A number without a country
+// code is read as a national number of region (ISO 3166-1 alpha-2), which
+// may be empty when every number must carry one.
+func Normalize(number, region string) (string, error) {
+	n := separators.Replace(strings.TrimSpace(number))
+	switch {
+	case n == "":
+		return "", fmt.Errorf("%w: must not be empty", ErrInvalid)
+	case strings.HasPrefix(n, "+"):
+		n = n[1:]
+	case strings.HasPrefix(n, "00"):
+		n = n[2:]
+	default:
+		national, err := nationalNumber(n, region)
+		if err != nil {
+			return "", err
+		}
+		n = national
+	}
+
+	if !isDigits(n) {
+		return "", fmt.Errorf("%w: may only contain digits after the country code", ErrInvalid)
+	}
+	if len(n) < 7 || len(n) > 15 || n[0] == '0' {
+		return "", fmt.Errorf("%w: must be a country code and number of 7 to 15 digits, e.g. +4915112345678", ErrInvalid)
+	}
+	if code, p, ok := lookup(n); ok {
+		if nsn := len(n) - len(code); nsn < p.Min || nsn > p.Max {
+			return "", fmt.Errorf("%w: %s numbers have %s after the country code", ErrInvalid, "+"+code, digits(p))
+		}
+	}
+	return "+" + n, nil
+}
+
+// Region returns the region of an E.164 number, or "" when its country
+// code is not one this package knows.
+func Region(e164 string) string {
+	if _, p, ok := lookup(strings.TrimPrefix(e164, "+")); ok {
+		return p.Regions[0]
+	}
+	return ""
+}
+
+// ValidRegion reports whether national numbers of region can be read.
+func ValidRegion(region string) bool {
+	_, ok := countryCodes[strings.ToUpper(region)]
+	return ok
+}
+
+func nationalNumber(n, region string) (string, error) {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if region == "" {
+		return "", fmt.Errorf("%w: must start with + and the country code, e.g. +4915112345678", ErrInvalid)
+	}
+	if !regionPattern.MatchString(region) {
+		return "", fmt.Errorf("%w: unknown country %q", ErrInvalid, region)
+	}
+	code, ok := countryCodes[region]
+	if !ok {
+		return "", fmt.Errorf("%w: national numbers of %s are not supported, start with + and the country code", ErrInvalid, region)
+	}
+	if p := plans[code]; p.Trunk != "" {
+		n = strings.TrimPrefix(n, p.Trunk)
+	}
+	return code + n, nil
+}
+
+// lookup finds the country code n starts with; country codes are prefix
+// free, so at most one matches.
+func lookup(n string) (string, plan, bool) {
+	for i := 1; i <= 3 && i <= len(n); i++ {
+		if p, ok := plans[n[:i]]; ok {
+			return n[:i], p, true
+		}
+	}
+	return "", plan{}, false
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func digits(p plan) string {
+	if p.Min == p.Max {
+		return fmt.Sprintf("%d digits", p.Min)
+	}
+	return fmt.Sprintf("%d to %d digits", p.Min, p.Max)
+}
@@ -0,0 +1,75 @@
+package phone
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		number, region, want string
+	}{
+		{" +49 (151) 123-45678 ", "", "+4915112345678"},
+		{"0049 151 12345678", "", "+4915112345678"},
+		{"0151 12345678", "DE", "+4915112345678"},
+		{"0151/12345678", "de", "+4915112345678"},
+		{"(415) 555-2671", "US", "+14155552671"},
+		{"1 415 555 2671", "US", "+14155552671"},
+		{"020 7946 0958", "GB", "+442079460958"},
+		{"06 12 34 56 78", "FR", "+33612345678"},
+		// Italian numbers keep their leading zero
+		{"06 1234 5678", "IT", "+390612345678"},
+		// Unknown country codes only get the E.164 checks
+		{"+971 50 123 4567", "", "+971501234567"},
+	}
+	for _, c := range cases {
+		got, err := Normalize(c.number, c.region)
+		if err != nil {
+			t.Errorf("Normalize(%q, %q): %v", c.number, c.region, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("Normalize(%q, %q) = %q, want %q", c.number, c.region, got, c.want)
+		}
+	}
+}
+
+func TestNormalize_Invalid(t *testing.T) {
+	cases := []struct {
+		number, region string
+	}{
+		{"", "DE"},
+		{"015112345678", ""},
+		{"015112345678", "XX"},
+		{"015112345678", "Germany"},
+		{"+49 151 1234 5678 9999", ""},
+		{"+1 415 555 267", ""},
+		{"+33 6 12 34 56 7", ""},
+		{"+0151 12345678", ""},
+		{"+49 151 CALL ME", ""},
+		{"+123", ""},
+	}
+	for _, c := range cases {
+		got, err := Normalize(c.number, c.region)
+		if !errors.Is(err, ErrInvalid) {
+			t.Errorf("Normalize(%q, %q) = %q, %v; want ErrInvalid", c.number, c.region, got, err)
+		}
+	}
+}
+
+func TestRegion(t *testing.T) {
+	cases := map[string]string{
+		"+4915112345678": "DE",
+		"+14155552671":   "US",
+		"+351912345678":  "PT",
+		"+971501234567":  "",
+	}
+	for number, want := range cases {
+		if got := Region(number); got != want {
+			t.Errorf("Region(%q) = %q, want %q", number, got, want)
+		}
+	}
+	if !ValidRegion("de") || ValidRegion("XX") {
+		t.Error("ValidRegion disagrees with the known regions")
+	}
+}
@@ -0,0 +1,106 @@
+// Command createadmin is the break-glass way to get an admin account when no
+// admin can sign in. It grants the admin role to an existing account or
+// creates a new one, and records the grant in the role audit trail.
+//
+// It reads the same environment as the auth service:
+//
+//	createadmin -email ops@example.com -reason "initial setup"
+//
+// When the account does not exist yet, the password is read from the first
+// line of standard input and the email is taken as verified.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/config"
+	"github.com/Zifeldev/marketback/service/Auth/internal/db"
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func main() {
+	email := flag.String("email", "", "email of the account to make admin")
+	reason := flag.String("reason", "", "why the admin is created, stored in the audit trail")
+	flag.Parse()
+
+	if err := run(context.Background(), strings.TrimSpace(*email), strings.TrimSpace(*reason)); err != nil {
+		fmt.Fprintln(os.Stderr, "createadmin:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, email, reason string) error {
+	if email == "" || reason == "" {
+		return errors.New("-email and -reason are required")
+	}
+
+	cfg, err := config.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	pool, err := db.New(ctx, cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer pool.Close()
+
+	userRepo := repository.NewUserRepository(pool, &cfg.JWT)
+	roleRepo := repository.NewRoleRepository(pool)
+	securityRepo := repository.NewSecurityRepository(pool)
+
+	user, err := userRepo.GetByEmail(ctx, email)
+	switch {
+	case errors.Is(err, repository.ErrUserNotFound):
+		user, err = createUser(ctx, userRepo, email)
+		if err != nil {
+			return err
+		}
+		if err := securityRepo.SetEmailVerified(ctx, user.ID); err != nil {
+			return fmt.Errorf("failed to verify email: %w", err)
+		}
+		fmt.Printf("created user %d (%s)\n", user.ID, user.Email)
+	case err != nil:
+		return fmt.Errorf("failed to look up user: %w", err)
+	case user.Role == models.RoleAdmin:
+		fmt.Printf("user %d (%s) is already an admin\n", user.ID, user.Email)
+		return nil
+	}
+
+	// adminID 0: the grant is recorded without an acting admin
+	user, err = roleRepo.SetRole(ctx, user.ID, models.RoleAdmin, 0, "createadmin: "+reason)
+	if err != nil {
+		return fmt.Errorf("failed to grant admin role: %w", err)
+	}
+
+	fmt.Printf("granted admin to user %d (%s); enable two-factor authentication before granting admin to others\n", user.ID, user.Email)
+	return nil
+}
+
+func createUser(ctx context.Context, userRepo repository.UserRepository, email string) (*models.User, error) {
+	fmt.Fprintf(os.Stderr, "no account for %s, enter a password for it: ", email)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return nil, fmt.Errorf("failed to read password: %w", err)
+	}
+
+	password := strings.TrimRight(line, "\r\n")
+	if len(password) < 8 {
+		return nil, errors.New("password must be at least 8 characters")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	return userRepo.CreateWithRole(ctx, email, string(hash), models.RoleUser)
+}
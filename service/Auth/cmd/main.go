@@ -2,25 +2,40 @@ package main
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/Zifeldev/marketback/pkg/fieldcrypt"
 	_ "github.com/Zifeldev/marketback/service/Auth/docs"
+	"github.com/Zifeldev/marketback/service/Auth/internal/buildinfo"
 	"github.com/Zifeldev/marketback/service/Auth/internal/config"
 	"github.com/Zifeldev/marketback/service/Auth/internal/controllers"
 	"github.com/Zifeldev/marketback/service/Auth/internal/db"
+	"github.com/Zifeldev/marketback/service/Auth/internal/grpcserver"
+	"github.com/Zifeldev/marketback/service/Auth/internal/httpserver"
+	"github.com/Zifeldev/marketback/service/Auth/internal/identity"
+	"github.com/Zifeldev/marketback/service/Auth/internal/keyring"
 	"github.com/Zifeldev/marketback/service/Auth/internal/logger"
+	"github.com/Zifeldev/marketback/service/Auth/internal/mailer"
+	"github.com/Zifeldev/marketback/service/Auth/internal/metrics"
 	"github.com/Zifeldev/marketback/service/Auth/internal/middleware"
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/openapi"
 	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/Zifeldev/marketback/service/Auth/internal/retention"
 	"github.com/Zifeldev/marketback/service/Auth/internal/service"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"google.golang.org/grpc"
 )
 
 // @title Auth Service API
@@ -28,6 +43,9 @@ import (
 // @description JWT-based authentication service with refresh tokens
 // @host localhost:8081
 // @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 func main() {
 	ctx := context.Background()
 
@@ -38,14 +56,30 @@ func main() {
 	}
 
 	// Setup logger
-	log := logger.New()
-	baseEntry := log.WithField("service", "auth")
+	log := logger.New(logger.Options{
+		Level:            cfg.Logger.Level,
+		Format:           cfg.Logger.Format,
+		SampleFirst:      cfg.Logger.SampleFirst,
+		SampleThereafter: cfg.Logger.SampleThereafter,
+		Fields: map[string]interface{}{
+			"service": "auth",
+			"version": buildinfo.Version,
+		},
+	})
+	baseEntry := logrus.NewEntry(log.Logger)
+
+	build := buildinfo.Get()
+	baseEntry.WithFields(logrus.Fields{
+		"commit":     build.Commit,
+		"build_date": build.BuildDate,
+	}).Info("starting auth service")
 
 	baseEntry.WithFields(logrus.Fields{
 		"http_addr":        cfg.HTTP.Host,
 		"shutdown_timeout": cfg.HTTP.ShutdownTimeout,
 		"req_timeout":      cfg.HTTP.RequestTimeout,
 		"db_query_timeout": cfg.Database.QueryTimeout,
+		"db_exec_mode":     cfg.Database.QueryExecMode,
 	}).Info("config loaded")
 
 	// Connect to PostgreSQL
@@ -54,6 +88,7 @@ func main() {
 		baseEntry.WithError(err).Fatal("failed to connect to database")
 	}
 	defer pool.Close()
+	metrics.RegisterPoolMetrics(pool)
 
 	// Connect to Redis
 	var rdb *redis.Client
@@ -71,26 +106,174 @@ func main() {
 		baseEntry.Info("redis connected")
 	}
 
+	// Linked identities, phone codes and TOTP secrets are sealed with the
+	// field encryption keyring
+	fieldKeys, err := fieldcrypt.Load(cfg.Encryption.Keys, cfg.Encryption.KeysFile)
+	if err != nil {
+		baseEntry.WithError(err).Fatal("failed to load encryption keys")
+	}
+	fieldIndex, err := fieldcrypt.LoadIndex(cfg.Encryption.IndexKey, cfg.Encryption.IndexKeyFile)
+	if err != nil {
+		baseEntry.WithError(err).Fatal("failed to load encryption index key")
+	}
+	if (fieldKeys == nil) != (fieldIndex == nil) {
+		baseEntry.Fatal("ENCRYPTION_KEYS and ENCRYPTION_INDEX_KEY must be set together")
+	}
+	if fieldKeys == nil {
+		baseEntry.Warn("column encryption disabled (ENCRYPTION_KEYS not set)")
+	} else {
+		baseEntry.WithField("kid", fieldKeys.PrimaryKeyID()).Info("column encryption enabled")
+	}
+
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(pool, &cfg.JWT)
 	tokenRepo := repository.NewTokenRepository(pool)
+	identityRepo := repository.NewIdentityRepository(pool, fieldKeys, fieldIndex)
+	roleRepo := repository.NewRoleRepository(pool)
+	twoFactorRepo := repository.NewTwoFactorRepository(pool, fieldKeys)
+	blacklistRepo := repository.NewBlacklistRepository(pool)
+	securityRepo := repository.NewSecurityRepository(pool)
+	if fieldKeys != nil && cfg.Encryption.RotateOnStart {
+		go func() {
+			rotateLog := baseEntry.WithField("component", "encryption")
+			identities, err := identityRepo.Rotate(context.Background(), 500)
+			if err != nil {
+				rotateLog.WithError(err).WithField("rotated", identities).Error("identity rotation failed")
+				return
+			}
+			secrets, err := twoFactorRepo.Rotate(context.Background(), 500)
+			if err != nil {
+				rotateLog.WithError(err).WithField("rotated", secrets).Error("two-factor secret rotation failed")
+				return
+			}
+			rotateLog.WithFields(logrus.Fields{
+				"identities": identities,
+				"secrets":    secrets,
+			}).Info("encryption rotation finished")
+		}()
+	}
 
 	// Initialize services
-	authService := service.NewAuthService(&cfg.JWT, userRepo, tokenRepo)
+	keys, err := keyring.Load(&cfg.JWT)
+	if err != nil {
+		baseEntry.WithError(err).Fatal("failed to load JWT signing keys")
+	}
+	if keys != nil {
+		baseEntry.WithFields(logrus.Fields{
+			"kid":         cfg.JWT.SigningKeyID,
+			"keys":        len(cfg.JWT.SigningKeys),
+			"accept_hmac": cfg.JWT.AccessSecret != "",
+		}).Info("access tokens signed with RS256")
+	}
+	// Revoked access tokens are also kept in Redis, where Market can check
+	// them on every request
+	var accessDenylist repository.BlacklistRepository = blacklistRepo
+	if rdb != nil {
+		accessDenylist = service.NewTokenBlacklistService(rdb, cfg.Redis.Prefix, blacklistRepo)
+	}
+	authService := service.NewAuthService(&cfg.JWT, userRepo, tokenRepo, accessDenylist, keys)
+
+	twoFactorService := service.NewTwoFactorService(twoFactorRepo, cfg.Identity.TOTPIssuer)
+
+	var googleVerifier service.IDTokenVerifier
+	if cfg.Identity.GoogleClientID != "" {
+		googleVerifier = identity.NewGoogleVerifier(cfg.Identity.GoogleClientID, cfg.Identity.GoogleCertsURL)
+	}
+	var smsSender identity.SMSSender
+	if cfg.Identity.SMSWebhookURL != "" {
+		smsSender = identity.NewWebhookSender(cfg.Identity.SMSWebhookURL)
+	}
+	identityService := service.NewIdentityService(authService, identityRepo, googleVerifier, smsSender, cfg.Identity.PhoneCodeTTL, cfg.Identity.PhoneRegion)
+	baseEntry.WithFields(logrus.Fields{
+		"google": googleVerifier != nil,
+		"phone":  smsSender != nil,
+	}).Info("linked identity sign-in")
+
+	// Welcome, verification, sign-in alert and password reset emails
+	// The sandbox captures them in an outbox instead of sending them
+	var (
+		mail   mailer.Mailer
+		outbox *mailer.Outbox
+	)
+	switch {
+	case cfg.Mail.Sandbox:
+		outbox, err = mailer.NewOutbox(cfg.Mail.From, cfg.Mail.SandboxDir, 500)
+		if err != nil {
+			baseEntry.WithError(err).Fatal("invalid mail sandbox configuration")
+		}
+		mail = outbox
+		baseEntry.WithField("maildir", cfg.Mail.SandboxDir).Warn("mail sandbox: emails are captured, see GET /api/dev/outbox")
+	case cfg.Mail.Provider == "sendgrid":
+		mail, err = mailer.NewSendGrid(cfg.Mail.SendGridAPIKey, cfg.Mail.From)
+		if err != nil {
+			baseEntry.WithError(err).Fatal("invalid mail configuration")
+		}
+	default:
+		mail, err = mailer.New(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, cfg.Mail.From)
+		if err != nil {
+			baseEntry.WithError(err).Fatal("invalid mail configuration")
+		}
+	}
+	notifier, err := mailer.NewNotifier(mail, cfg.Mail.TemplateDir, cfg.Mail.DisabledEvents)
+	if err != nil {
+		baseEntry.WithError(err).Fatal("invalid mail template configuration")
+	}
+	securityService := service.NewSecurityService(&cfg.Security, userRepo, tokenRepo, securityRepo, notifier)
+	// Without verification emails new accounts could never sign in
+	if cfg.JWT.RequireVerifiedEmail && !notifier.Enabled(mailer.EventEmailVerification) {
+		baseEntry.Fatal("EMAIL_VERIFICATION_REQUIRED needs verification emails: configure email and keep email_verification enabled")
+	}
+	baseEntry.WithFields(logrus.Fields{
+		"enabled":        cfg.Security.LoginAlerts,
+		"email":          notifier.Enabled(mailer.EventLoginAlert),
+		"country_header": cfg.Security.CountryHeader,
+	}).Info("sign-in alerts")
+
+	// Retention jobs
+	if cfg.Retention.Enabled {
+		retentionRunner := retention.NewRunner(pool, baseEntry.WithField("component", "retention"), cfg.Retention.DryRun,
+			retention.StaleRefreshTokens(cfg.Retention.RefreshTokenGrace),
+			retention.ExpiredBlacklist(cfg.Retention.BlacklistGrace),
+			retention.ExpiredPhoneCodes(cfg.Retention.BlacklistGrace),
+			retention.ExpiredSecurityTokens(cfg.Retention.BlacklistGrace),
+			retention.RoleChanges(cfg.Retention.AuditLogTTL),
+		)
+		retentionCtx, stopRetention := context.WithCancel(ctx)
+		defer stopRetention()
+		go retentionRunner.Start(retentionCtx, cfg.Retention.Interval)
+		baseEntry.WithFields(logrus.Fields{
+			"interval": cfg.Retention.Interval,
+			"dry_run":  cfg.Retention.DryRun,
+		}).Info("retention jobs enabled")
+	}
 
 	// Initialize controllers
-	authController := controllers.NewAuthController(authService, baseEntry)
-	adminController := controllers.NewAdminController(userRepo, baseEntry)
-	healthController := controllers.NewHealthController(pool, rdb, baseEntry, time.Now(), "1.0.0")
+	authController := controllers.NewAuthController(authService, securityService, baseEntry)
+	adminController := controllers.NewAdminController(userRepo, roleRepo, twoFactorService, securityService, baseEntry)
+	roleController := controllers.NewRoleController(roleRepo, baseEntry)
+	twoFactorController := controllers.NewTwoFactorController(twoFactorService, baseEntry)
+	identityController := controllers.NewIdentityController(identityService, securityService, baseEntry)
+	securityController := controllers.NewSecurityController(securityService, baseEntry)
+	userImportService := service.NewUserImportService(userRepo, roleRepo, securityRepo, notifier.Enabled(mailer.EventInvitation))
+	userImportController := controllers.NewUserImportController(userImportService, securityService, baseEntry)
+	sessionController := controllers.NewSessionController(authService, securityService, baseEntry)
+	discoveryController := controllers.NewDiscoveryController(&cfg.JWT, keys, len(cfg.Introspection.Clients) > 0)
+	healthController := controllers.NewHealthController(pool, rdb, baseEntry, time.Now(), buildinfo.Version)
 
 	// Setup Gin
 	if cfg.Logger.Level != "debug" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	spec, err := openapi.Spec()
+	if err != nil {
+		baseEntry.WithError(err).Fatal("failed to build OpenAPI document")
+	}
+
 	r := gin.New()
 	r.Use(gin.Recovery())
-	r.Use(gin.Logger())
+	r.Use(middleware.RequestLogger(baseEntry))
+	r.Use(middleware.Metrics())
 
 	// CORS
 	r.Use(func(c *gin.Context) {
@@ -106,52 +289,140 @@ func main() {
 
 	// Routes
 	r.GET("/health", healthController.Health)
+	r.GET("/version", healthController.Version)
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	r.GET("/openapi.json", openapi.Handler(spec))
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	r.GET("/.well-known/openid-configuration", discoveryController.OpenIDConfiguration)
+	r.GET("/.well-known/jwks.json", discoveryController.JWKS)
 
 	// Auth routes (public)
 	auth := r.Group("/auth")
+	auth.Use(middleware.ClientFingerprint(cfg.JWT.RefreshBindingIP, cfg.Security.CountryHeader))
 	{
 		auth.POST("/register", authController.Register)
 		auth.POST("/login", authController.Login)
 		auth.POST("/refresh", authController.Refresh)
 		auth.POST("/logout", authController.Logout)
+		auth.POST("/login/google", identityController.LoginGoogle)
+		auth.POST("/login/phone/code", identityController.SendLoginCode)
+		auth.POST("/login/phone", identityController.LoginPhone)
+		auth.POST("/login-alerts/deny", securityController.DenyLogin)
+		auth.POST("/password-reset/request", securityController.RequestPasswordReset)
+		auth.POST("/password-reset", securityController.ResetPassword)
+		auth.POST("/password-change", securityController.ChangePassword)
+		auth.GET("/verify-email", securityController.VerifyEmail)
+	}
+
+	// Token introspection and suspending users for other services (service
+	// credentials)
+	if len(cfg.Introspection.Clients) > 0 {
+		auth.POST("/introspect", middleware.ServiceAuth(cfg.Introspection.Clients), authController.Introspect)
+		auth.POST("/users/:id/suspend", middleware.ServiceAuth(cfg.Introspection.Clients), adminController.ServiceSuspendUser)
 	}
+	baseEntry.WithField("clients", len(cfg.Introspection.Clients)).Info("token introspection")
+	baseEntry.WithFields(logrus.Fields{
+		"mode":       cfg.JWT.RefreshBinding,
+		"include_ip": cfg.JWT.RefreshBindingIP,
+	}).Info("refresh token binding")
 
 	// Protected routes example
 	protected := r.Group("/api")
 	protected.Use(middleware.JWTAuth(authService))
+	protected.Use(middleware.RequireScope(models.ScopeAccount))
 	{
 		protected.GET("/me", func(c *gin.Context) {
 			userID, _ := middleware.GetUserID(c)
 			email, _ := middleware.GetUserEmail(c)
 			role, _ := middleware.GetUserRole(c)
 			c.JSON(http.StatusOK, gin.H{
+				"sub":     strconv.FormatInt(userID, 10),
 				"user_id": userID,
 				"email":   email,
 				"role":    role,
 			})
 		})
+		protected.GET("/me/sessions", sessionController.ListSessions)
+		protected.DELETE("/me/sessions", sessionController.RevokeAllSessions)
+		protected.DELETE("/me/sessions/:id", sessionController.RevokeSession)
+		protected.GET("/identities", identityController.ListIdentities)
+		protected.POST("/identities/google", identityController.LinkGoogle)
+		protected.POST("/identities/phone/code", identityController.SendPhoneCode)
+		protected.POST("/identities/phone", identityController.LinkPhone)
+		protected.DELETE("/identities/:id", identityController.Unlink)
+		protected.GET("/role-requests", roleController.ListMyRoleRequests)
+		protected.POST("/role-requests", roleController.CreateRoleRequest)
+		protected.POST("/2fa/setup", twoFactorController.Setup)
+		protected.POST("/2fa/enable", twoFactorController.Enable)
+		protected.POST("/2fa/disable", twoFactorController.Disable)
+		protected.POST("/tokens/scoped", authController.IssueScopedToken)
 	}
 
 	// Admin routes (admin only)
 	admin := r.Group("/admin")
 	admin.Use(middleware.JWTAuth(authService))
 	admin.Use(middleware.RequireRole("admin"))
+	admin.Use(middleware.RequireScope(models.ScopeAdmin))
 	{
 		admin.GET("/users", adminController.ListUsers)
 		admin.POST("/users", adminController.CreateUser)
+		admin.POST("/users/import", userImportController.ImportUsers)
 		admin.PUT("/users/:id/role", adminController.UpdateUserRole)
 		admin.DELETE("/users/:id", adminController.DeleteUser)
+		admin.POST("/users/:id/force-password-reset", adminController.ForcePasswordReset)
+		admin.POST("/users/:id/revoke-sessions", adminController.RevokeSessions)
+		admin.POST("/users/:id/suspend", adminController.SuspendUser)
+		admin.POST("/users/:id/unsuspend", adminController.UnsuspendUser)
+		admin.POST("/users/:id/resend-verification", adminController.ResendVerification)
+		admin.GET("/role-requests", roleController.ListRoleRequests)
+		admin.POST("/role-requests/:id/approve", roleController.ApproveRoleRequest)
+		admin.POST("/role-requests/:id/reject", roleController.RejectRoleRequest)
+		admin.GET("/role-changes", roleController.ListRoleChanges)
+	}
+
+	// Development tools (only outside production)
+	if outbox != nil {
+		r.GET("/api/dev/outbox", controllers.NewOutboxController(outbox).GetOutbox)
 	}
 
 	// Start server
-	srv := &http.Server{
-		Addr:    cfg.HTTP.Host,
-		Handler: r,
+	srv, err := httpserver.New(httpserver.Options{
+		Addr:              cfg.HTTP.Host,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+		H2C:               cfg.HTTP.H2C,
+		CertFile:          cfg.TLS.CertFile,
+		KeyFile:           cfg.TLS.KeyFile,
+		AutocertDomains:   cfg.TLS.AutocertDomains,
+		AutocertCacheDir:  cfg.TLS.AutocertCacheDir,
+		AutocertEmail:     cfg.TLS.AutocertEmail,
+	}, r)
+	if err != nil {
+		baseEntry.WithError(err).Fatal("invalid server configuration")
+	}
+
+	// gRPC API for other services (service credentials, as for introspection)
+	var grpcSrv *grpc.Server
+	if len(cfg.Introspection.Clients) > 0 && cfg.GRPC.Host != "" {
+		lis, err := net.Listen("tcp", cfg.GRPC.Host)
+		if err != nil {
+			baseEntry.WithError(err).Fatal("failed to listen for gRPC")
+		}
+		grpcSrv = grpcserver.New(authService, userRepo, cfg.Introspection.Clients, baseEntry)
+		go func() {
+			baseEntry.WithField("addr", cfg.GRPC.Host).Info("starting gRPC server")
+			if err := grpcSrv.Serve(lis); err != nil {
+				baseEntry.WithError(err).Fatal("gRPC server failed")
+			}
+		}()
+	} else {
+		baseEntry.Info("gRPC server disabled (needs INTROSPECTION_CLIENTS and GRPC_HOST)")
 	}
 
 	go func() {
-		baseEntry.WithField("addr", cfg.HTTP.Host).Info("starting HTTP server")
+		baseEntry.WithField("addr", cfg.HTTP.Host).WithField("tls", srv.TLS()).Info("starting HTTP server")
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			baseEntry.WithError(err).Fatal("server failed")
 		}
@@ -167,6 +438,9 @@ func main() {
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
 	defer cancel()
 
+	if grpcSrv != nil {
+		grpcSrv.GracefulStop()
+	}
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		baseEntry.WithError(err).Fatal("server forced to shutdown")
 	}
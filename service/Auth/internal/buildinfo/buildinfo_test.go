@@ -0,0 +1,21 @@
+package buildinfo
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestGet_UsesInjectedValues(t *testing.T) {
+	oldVersion, oldCommit, oldDate := Version, Commit, BuildDate
+	defer func() { Version, Commit, BuildDate = oldVersion, oldCommit, oldDate }()
+
+	Version, Commit, BuildDate = "2.3.4", "abc1234", "2025-01-02T03:04:05Z"
+
+	info := Get()
+	if info.Version != "2.3.4" || info.Commit != "abc1234" || info.BuildDate != "2025-01-02T03:04:05Z" {
+		t.Fatalf("unexpected build info: %+v", info)
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Fatalf("expected go version %s, got %s", runtime.Version(), info.GoVersion)
+	}
+}
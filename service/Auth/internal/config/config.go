@@ -5,8 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/Zifeldev/marketback/pkg/phone"
+	"github.com/Zifeldev/marketback/service/Auth/internal/fingerprint"
 )
 
 type DatabaseConfig struct {
@@ -22,16 +27,41 @@ type DatabaseConfig struct {
 	MaxConnIdleTime   time.Duration
 	HealthCheckPeriod time.Duration
 	QueryTimeout      time.Duration
+
+	// QueryExecMode is a pgx query exec mode: cache_statement, cache_describe,
+	// describe_exec, exec or simple_protocol.
+	QueryExecMode            string
+	StatementCacheCapacity   int
+	DescriptionCacheCapacity int
 }
 
 type HTTPConfig struct {
 	Host            string
 	ShutdownTimeout time.Duration
 	RequestTimeout  time.Duration
+
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	H2C               bool
+}
+
+// TLSConfig enables TLS termination in the service itself, either from
+// certificate files or via ACME for AutocertDomains.
+type TLSConfig struct {
+	CertFile         string
+	KeyFile          string
+	AutocertDomains  []string
+	AutocertCacheDir string
+	AutocertEmail    string
 }
 
 type LoggerConfig struct {
-	Level string
+	Level            string
+	Format           string
+	SampleFirst      int
+	SampleThereafter int
 }
 
 type RedisConfig struct {
@@ -44,12 +74,38 @@ type RedisConfig struct {
 }
 
 type JWTConfig struct {
-	AccessSecret      string
+	// AccessSecret signs access tokens with HS256 when no signing keys are
+	// configured, and keeps verifying HS256 tokens while moving to them
+	AccessSecret string
+	// SigningKeys are the RS256 keys published in the JWKS. SigningKeyID
+	// signs new access tokens; the others only verify tokens they signed
+	// before a rotation.
+	SigningKeys       []SigningKey
+	SigningKeyID      string
 	RefreshSecret     string
 	AccessExpiration  time.Duration
 	RefreshExpiration time.Duration
 	Issuer            string
-	FirstAdminEmail   string
+	// Audience is put in the aud claim of access tokens and required by
+	// every service validating them
+	Audience string
+	// PublicURL is where clients reach this service; discovery metadata
+	// points there
+	PublicURL string
+	// RefreshBinding decides what happens when a refresh token is used by
+	// another client than it was issued to: off, report or enforce.
+	// RefreshBindingIP adds the client IP to the fingerprint.
+	RefreshBinding   string
+	RefreshBindingIP bool
+	// RequireVerifiedEmail refuses password sign-in to accounts whose email
+	// is not verified yet
+	RequireVerifiedEmail bool
+}
+
+// SigningKey is an RSA private key in a PEM file, published under ID (kid)
+type SigningKey struct {
+	ID   string
+	File string
 }
 
 type RateLimitConfig struct {
@@ -58,18 +114,118 @@ type RateLimitConfig struct {
 	Max      int
 }
 
+type RetentionConfig struct {
+	Enabled           bool
+	DryRun            bool
+	Interval          time.Duration
+	RefreshTokenGrace time.Duration
+	BlacklistGrace    time.Duration
+	AuditLogTTL       time.Duration
+}
+
+// IdentityConfig enables signing in with linked identities. Google sign-in
+// needs GoogleClientID; phone sign-in needs SMSWebhookURL.
+type IdentityConfig struct {
+	GoogleClientID string
+	GoogleCertsURL string
+	SMSWebhookURL  string
+	PhoneCodeTTL   time.Duration
+	// PhoneRegion is the country (ISO 3166-1 alpha-2) of phone numbers
+	// entered without a country code; empty requires one
+	PhoneRegion string
+	// TOTPIssuer is the account label shown in authenticator apps
+	TOTPIssuer string
+}
+
+// EncryptionConfig seals linked identities, phone codes and TOTP secrets.
+// Keys is a field encryption keyring like Market's ENCRYPTION_KEYS;
+// IndexKey derives the blind indexes sealed subjects are looked up by and
+// cannot be rotated. Both are needed, or neither.
+type EncryptionConfig struct {
+	Keys          string
+	KeysFile      string
+	IndexKey      string
+	IndexKeyFile  string
+	RotateOnStart bool
+}
+
+// IntrospectionConfig holds the service credentials (client ID to secret)
+// accepted by /auth/introspect; the endpoint is disabled without any.
+type IntrospectionConfig struct {
+	Clients map[string]string
+}
+
+// GRPCConfig serves the gRPC API for other services (token validation and
+// user lookups) on Host. Like introspection, it needs INTROSPECTION_CLIENTS.
+type GRPCConfig struct {
+	Host string
+}
+
+type MailConfig struct {
+	// Provider sends the email: "smtp" through SMTPHost, or "sendgrid"
+	// through the SendGrid API with SendGridAPIKey.
+	Provider       string
+	SMTPHost       string
+	SMTPPort       int
+	SMTPUsername   string
+	SMTPPassword   string
+	SendGridAPIKey string
+	From           string
+	// TemplateDir holds email templates replacing the built-in ones;
+	// DisabledEvents are the events not emailed.
+	TemplateDir    string
+	DisabledEvents []string
+	// Sandbox captures outbound email in an outbox instead of sending it,
+	// also writing it to a maildir in SandboxDir when set. It is refused in
+	// production.
+	Sandbox    bool
+	SandboxDir string
+}
+
+// SecurityConfig controls sign-in alerts, password reset and email
+// verification emails. Links in those emails point to AppURL, the frontend,
+// except verification links, which point to VerifyEmailURL.
+type SecurityConfig struct {
+	LoginAlerts bool
+	AppURL      string
+	// CountryHeader names the request header carrying the client's country
+	// code (such as CF-IPCountry behind Cloudflare); empty disables country
+	// checks
+	CountryHeader    string
+	AlertLinkTTL     time.Duration
+	PasswordResetTTL time.Duration
+	VerifyEmailURL   string
+	VerifyEmailTTL   time.Duration
+	// InvitationTTL is how long the link choosing a password in the
+	// invitation of an imported user stays valid
+	InvitationTTL time.Duration
+}
+
 type Config struct {
+	// Env is the deployment environment, e.g. "development" or "production".
+	Env       string
 	Database  DatabaseConfig
 	HTTP      HTTPConfig
+	TLS       TLSConfig
 	Logger    LoggerConfig
 	Redis     RedisConfig
 	JWT       JWTConfig
 	RateLimit RateLimitConfig
+	Retention RetentionConfig
+	Identity  IdentityConfig
+	Mail      MailConfig
+	Security  SecurityConfig
+
+	Introspection IntrospectionConfig
+	GRPC          GRPCConfig
+	Encryption    EncryptionConfig
 }
 
 func Load(ctx context.Context) (*Config, error) {
 	cfg := &Config{}
 
+	cfg.Env = getEnv("ENV", "development")
+
 	// Database
 	port, err := strconv.Atoi(getEnv("DB_PORT", "5432"))
 	if err != nil {
@@ -91,6 +247,31 @@ func Load(ctx context.Context) (*Config, error) {
 		return nil, fmt.Errorf("invalid DB_QUERY_TIMEOUT: %w", err)
 	}
 
+	maxConnLifetime, err := time.ParseDuration(getEnv("DB_MAX_CONN_LIFETIME", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_MAX_CONN_LIFETIME: %w", err)
+	}
+
+	maxConnIdleTime, err := time.ParseDuration(getEnv("DB_MAX_CONN_IDLE_TIME", "30m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_MAX_CONN_IDLE_TIME: %w", err)
+	}
+
+	healthCheckPeriod, err := time.ParseDuration(getEnv("DB_HEALTH_CHECK_PERIOD", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_HEALTH_CHECK_PERIOD: %w", err)
+	}
+
+	statementCacheCapacity, err := strconv.Atoi(getEnv("DB_STATEMENT_CACHE_CAPACITY", "512"))
+	if err != nil || statementCacheCapacity < 0 {
+		return nil, fmt.Errorf("invalid DB_STATEMENT_CACHE_CAPACITY: must be a non-negative integer")
+	}
+
+	descriptionCacheCapacity, err := strconv.Atoi(getEnv("DB_DESCRIPTION_CACHE_CAPACITY", "512"))
+	if err != nil || descriptionCacheCapacity < 0 {
+		return nil, fmt.Errorf("invalid DB_DESCRIPTION_CACHE_CAPACITY: must be a non-negative integer")
+	}
+
 	cfg.Database = DatabaseConfig{
 		Host:              getEnv("DB_HOST", "localhost"),
 		Port:              port,
@@ -100,10 +281,14 @@ func Load(ctx context.Context) (*Config, error) {
 		SSLMode:           getEnv("DB_SSLMODE", "disable"),
 		MaxConns:          int32(maxConns),
 		MinConns:          int32(minConns),
-		MaxConnLifetime:   time.Hour,
-		MaxConnIdleTime:   30 * time.Minute,
-		HealthCheckPeriod: time.Minute,
+		MaxConnLifetime:   maxConnLifetime,
+		MaxConnIdleTime:   maxConnIdleTime,
+		HealthCheckPeriod: healthCheckPeriod,
 		QueryTimeout:      queryTimeout,
+
+		QueryExecMode:            getEnv("DB_QUERY_EXEC_MODE", "cache_statement"),
+		StatementCacheCapacity:   statementCacheCapacity,
+		DescriptionCacheCapacity: descriptionCacheCapacity,
 	}
 
 	// HTTP
@@ -117,15 +302,71 @@ func Load(ctx context.Context) (*Config, error) {
 		return nil, fmt.Errorf("invalid REQUEST_TIMEOUT: %w", err)
 	}
 
+	readHeaderTimeout, err := time.ParseDuration(getEnv("HTTP_READ_HEADER_TIMEOUT", "10s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP_READ_HEADER_TIMEOUT: %w", err)
+	}
+
+	readTimeout, err := time.ParseDuration(getEnv("HTTP_READ_TIMEOUT", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP_READ_TIMEOUT: %w", err)
+	}
+
+	writeTimeout, err := time.ParseDuration(getEnv("HTTP_WRITE_TIMEOUT", "60s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP_WRITE_TIMEOUT: %w", err)
+	}
+
+	idleTimeout, err := time.ParseDuration(getEnv("HTTP_IDLE_TIMEOUT", "120s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP_IDLE_TIMEOUT: %w", err)
+	}
+
 	cfg.HTTP = HTTPConfig{
-		Host:            getEnv("HTTP_HOST", ":8081"),
-		ShutdownTimeout: shutdownTimeout,
-		RequestTimeout:  requestTimeout,
+		Host:              getEnv("HTTP_HOST", ":8081"),
+		ShutdownTimeout:   shutdownTimeout,
+		RequestTimeout:    requestTimeout,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+		H2C:               getEnv("HTTP_H2C", "false") == "true",
+	}
+
+	// TLS
+	var autocertDomains []string
+	if domains := getEnv("TLS_AUTOCERT_DOMAINS", ""); domains != "" {
+		for _, d := range strings.Split(domains, ",") {
+			if d = strings.TrimSpace(d); d != "" {
+				autocertDomains = append(autocertDomains, d)
+			}
+		}
+	}
+
+	cfg.TLS = TLSConfig{
+		CertFile:         getEnv("TLS_CERT_FILE", ""),
+		KeyFile:          getEnv("TLS_KEY_FILE", ""),
+		AutocertDomains:  autocertDomains,
+		AutocertCacheDir: getEnv("TLS_AUTOCERT_CACHE_DIR", "./certs"),
+		AutocertEmail:    getEnv("TLS_AUTOCERT_EMAIL", ""),
 	}
 
 	// Logger
+	sampleFirst, err := strconv.Atoi(getEnv("LOG_SAMPLE_FIRST", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOG_SAMPLE_FIRST: %w", err)
+	}
+
+	sampleThereafter, err := strconv.Atoi(getEnv("LOG_SAMPLE_THEREAFTER", "100"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOG_SAMPLE_THEREAFTER: %w", err)
+	}
+
 	cfg.Logger = LoggerConfig{
-		Level: getEnv("LOG_LEVEL", "info"),
+		Level:            getEnv("LOG_LEVEL", "info"),
+		Format:           getEnv("LOG_FORMAT", "json"),
+		SampleFirst:      sampleFirst,
+		SampleThereafter: sampleThereafter,
 	}
 
 	// Redis
@@ -159,9 +400,25 @@ func Load(ctx context.Context) (*Config, error) {
 		return nil, fmt.Errorf("invalid JWT_REFRESH_EXPIRATION: %w", err)
 	}
 
+	signingKeys, err := parseSigningKeys(getEnv("JWT_SIGNING_KEYS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_SIGNING_KEYS: %w", err)
+	}
+	signingKeyID := getEnv("JWT_SIGNING_KEY_ID", "")
+	if len(signingKeys) > 0 {
+		if signingKeyID == "" {
+			signingKeyID = signingKeys[0].ID
+		}
+		if !slices.ContainsFunc(signingKeys, func(k SigningKey) bool { return k.ID == signingKeyID }) {
+			return nil, fmt.Errorf("invalid JWT_SIGNING_KEY_ID: must be one of the JWT_SIGNING_KEYS ids")
+		}
+	} else if signingKeyID != "" {
+		return nil, errors.New("JWT_SIGNING_KEY_ID requires JWT_SIGNING_KEYS")
+	}
+
 	accessSecret := getEnv("JWT_ACCESS_SECRET", "")
-	if accessSecret == "" {
-		return nil, errors.New("JWT_ACCESS_SECRET is required")
+	if accessSecret == "" && len(signingKeys) == 0 {
+		return nil, errors.New("JWT_ACCESS_SECRET is required unless JWT_SIGNING_KEYS is set")
 	}
 
 	refreshSecret := getEnv("JWT_REFRESH_SECRET", "")
@@ -169,13 +426,23 @@ func Load(ctx context.Context) (*Config, error) {
 		return nil, errors.New("JWT_REFRESH_SECRET is required")
 	}
 
+	refreshBinding := getEnv("REFRESH_TOKEN_BINDING", fingerprint.ModeOff)
+	if !fingerprint.IsMode(refreshBinding) {
+		return nil, fmt.Errorf("invalid REFRESH_TOKEN_BINDING: must be off, report or enforce")
+	}
+
 	cfg.JWT = JWTConfig{
 		AccessSecret:      accessSecret,
+		SigningKeys:       signingKeys,
+		SigningKeyID:      signingKeyID,
 		RefreshSecret:     refreshSecret,
 		AccessExpiration:  accessExpiration,
 		RefreshExpiration: refreshExpiration,
 		Issuer:            getEnv("JWT_ISSUER", "marketback-auth"),
-		FirstAdminEmail:   getEnv("FIRST_ADMIN_EMAIL", ""),
+		Audience:          getEnv("JWT_AUDIENCE", "marketback"),
+		PublicURL:         strings.TrimRight(getEnv("PUBLIC_URL", "http://localhost:8081"), "/"),
+		RefreshBinding:    refreshBinding,
+		RefreshBindingIP:  getEnv("REFRESH_TOKEN_BINDING_IP", "false") == "true",
 	}
 
 	// Rate Limit
@@ -195,9 +462,180 @@ func Load(ctx context.Context) (*Config, error) {
 		Max:      rateLimitMax,
 	}
 
+	// Retention
+	retentionInterval, err := time.ParseDuration(getEnv("RETENTION_INTERVAL", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RETENTION_INTERVAL: %w", err)
+	}
+
+	refreshTokenGrace, err := time.ParseDuration(getEnv("RETENTION_REFRESH_TOKEN_GRACE", "168h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RETENTION_REFRESH_TOKEN_GRACE: %w", err)
+	}
+
+	blacklistGrace, err := time.ParseDuration(getEnv("RETENTION_BLACKLIST_GRACE", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RETENTION_BLACKLIST_GRACE: %w", err)
+	}
+
+	auditLogTTL, err := time.ParseDuration(getEnv("RETENTION_AUDIT_LOG_TTL", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RETENTION_AUDIT_LOG_TTL: %w", err)
+	}
+
+	cfg.Retention = RetentionConfig{
+		Enabled:           getEnv("RETENTION_ENABLED", "false") == "true",
+		DryRun:            getEnv("RETENTION_DRY_RUN", "false") == "true",
+		Interval:          retentionInterval,
+		RefreshTokenGrace: refreshTokenGrace,
+		BlacklistGrace:    blacklistGrace,
+		AuditLogTTL:       auditLogTTL,
+	}
+
+	// Linked identities
+	phoneCodeTTL, err := time.ParseDuration(getEnv("PHONE_CODE_TTL", "10m"))
+	if err != nil || phoneCodeTTL <= 0 {
+		return nil, fmt.Errorf("invalid PHONE_CODE_TTL: must be a positive duration")
+	}
+	phoneRegion := strings.ToUpper(getEnv("PHONE_DEFAULT_REGION", ""))
+	if phoneRegion != "" && !phone.ValidRegion(phoneRegion) {
+		return nil, fmt.Errorf("invalid PHONE_DEFAULT_REGION: must be a supported ISO 3166-1 alpha-2 country code")
+	}
+
+	cfg.Identity = IdentityConfig{
+		GoogleClientID: getEnv("GOOGLE_CLIENT_ID", ""),
+		GoogleCertsURL: getEnv("GOOGLE_CERTS_URL", "https://www.googleapis.com/oauth2/v3/certs"),
+		SMSWebhookURL:  getEnv("SMS_WEBHOOK_URL", ""),
+		PhoneCodeTTL:   phoneCodeTTL,
+		PhoneRegion:    phoneRegion,
+		TOTPIssuer:     getEnv("TOTP_ISSUER", "Marketback"),
+	}
+
+	// Encryption
+	cfg.Encryption = EncryptionConfig{
+		Keys:          getEnv("ENCRYPTION_KEYS", ""),
+		KeysFile:      getEnv("ENCRYPTION_KEYS_FILE", ""),
+		IndexKey:      getEnv("ENCRYPTION_INDEX_KEY", ""),
+		IndexKeyFile:  getEnv("ENCRYPTION_INDEX_KEY_FILE", ""),
+		RotateOnStart: getEnv("ENCRYPTION_ROTATE_ON_START", "false") == "true",
+	}
+
+	// Token introspection
+	clients, err := parseClients(getEnv("INTROSPECTION_CLIENTS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid INTROSPECTION_CLIENTS: %w", err)
+	}
+	cfg.Introspection = IntrospectionConfig{Clients: clients}
+	cfg.GRPC = GRPCConfig{Host: getEnv("GRPC_HOST", ":9081")}
+
+	// Email
+	smtpPort, err := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP_PORT: %w", err)
+	}
+
+	cfg.Mail = MailConfig{
+		Provider:       getEnv("MAIL_PROVIDER", "smtp"),
+		SMTPHost:       getEnv("SMTP_HOST", ""),
+		SMTPPort:       smtpPort,
+		SMTPUsername:   getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:   getEnv("SMTP_PASSWORD", ""),
+		SendGridAPIKey: getEnv("SENDGRID_API_KEY", ""),
+		From:           getEnv("MAIL_FROM", ""),
+		TemplateDir:    getEnv("MAIL_TEMPLATE_DIR", ""),
+		Sandbox:        getEnv("MAIL_SANDBOX", "false") == "true",
+		SandboxDir:     getEnv("MAIL_SANDBOX_DIR", ""),
+	}
+	if cfg.Mail.Provider != "smtp" && cfg.Mail.Provider != "sendgrid" {
+		return nil, fmt.Errorf("invalid MAIL_PROVIDER: must be smtp or sendgrid")
+	}
+	for _, event := range strings.Split(getEnv("MAIL_DISABLED_EVENTS", ""), ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			cfg.Mail.DisabledEvents = append(cfg.Mail.DisabledEvents, event)
+		}
+	}
+	if cfg.Mail.Sandbox && cfg.Env == "production" {
+		return nil, fmt.Errorf("invalid MAIL_SANDBOX: the email sandbox is not allowed in production")
+	}
+
+	// Sign-in alerts and password reset
+	alertLinkTTL, err := time.ParseDuration(getEnv("LOGIN_ALERT_LINK_TTL", "72h"))
+	if err != nil || alertLinkTTL <= 0 {
+		return nil, fmt.Errorf("invalid LOGIN_ALERT_LINK_TTL: must be a positive duration")
+	}
+
+	passwordResetTTL, err := time.ParseDuration(getEnv("PASSWORD_RESET_TTL", "1h"))
+	if err != nil || passwordResetTTL <= 0 {
+		return nil, fmt.Errorf("invalid PASSWORD_RESET_TTL: must be a positive duration")
+	}
+
+	// Email verification
+	verifyEmailTTL, err := time.ParseDuration(getEnv("EMAIL_VERIFICATION_TTL", "72h"))
+	if err != nil || verifyEmailTTL <= 0 {
+		return nil, fmt.Errorf("invalid EMAIL_VERIFICATION_TTL: must be a positive duration")
+	}
+	cfg.JWT.RequireVerifiedEmail = getEnv("EMAIL_VERIFICATION_REQUIRED", "false") == "true"
+
+	// Invitations of imported users
+	invitationTTL, err := time.ParseDuration(getEnv("INVITATION_TTL", "168h"))
+	if err != nil || invitationTTL <= 0 {
+		return nil, fmt.Errorf("invalid INVITATION_TTL: must be a positive duration")
+	}
+
+	cfg.Security = SecurityConfig{
+		LoginAlerts:      getEnv("LOGIN_ALERTS_ENABLED", "true") == "true",
+		AppURL:           strings.TrimRight(getEnv("APP_URL", "http://localhost:3000"), "/"),
+		CountryHeader:    getEnv("LOGIN_COUNTRY_HEADER", ""),
+		AlertLinkTTL:     alertLinkTTL,
+		PasswordResetTTL: passwordResetTTL,
+		VerifyEmailURL:   getEnv("EMAIL_VERIFICATION_URL", cfg.JWT.PublicURL+"/auth/verify-email"),
+		VerifyEmailTTL:   verifyEmailTTL,
+		InvitationTTL:    invitationTTL,
+	}
+
 	return cfg, nil
 }
 
+// parseClients parses "id:secret,id:secret" service credentials
+func parseClients(value string) (map[string]string, error) {
+	clients := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(entry, ":")
+		if !ok || id == "" || secret == "" {
+			return nil, fmt.Errorf("entry %q must be client_id:secret", id)
+		}
+		if _, dup := clients[id]; dup {
+			return nil, fmt.Errorf("duplicate client %q", id)
+		}
+		clients[id] = secret
+	}
+	return clients, nil
+}
+
+// parseSigningKeys parses "kid=/path/key.pem,kid=/path/key.pem"
+func parseSigningKeys(value string) ([]SigningKey, error) {
+	var keys []SigningKey
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, file, ok := strings.Cut(entry, "=")
+		if !ok || id == "" || file == "" {
+			return nil, fmt.Errorf("entry %q must be kid=file", entry)
+		}
+		if slices.ContainsFunc(keys, func(k SigningKey) bool { return k.ID == id }) {
+			return nil, fmt.Errorf("duplicate key id %q", id)
+		}
+		keys = append(keys, SigningKey{ID: id, File: file})
+	}
+	return keys, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -1,29 +1,67 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/Zifeldev/marketback/service/Auth/internal/middleware"
 	"github.com/Zifeldev/marketback/service/Auth/internal/models"
 	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/Zifeldev/marketback/service/Auth/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AdminController struct {
-	userRepo repository.UserRepository
-	log      *logrus.Entry
+	userRepo  repository.UserRepository
+	roleRepo  repository.RoleRepository
+	twoFactor service.TwoFactorService
+	security  service.SecurityService
+	log       *logrus.Entry
 }
 
-func NewAdminController(userRepo repository.UserRepository, log *logrus.Entry) *AdminController {
+func NewAdminController(userRepo repository.UserRepository, roleRepo repository.RoleRepository, twoFactor service.TwoFactorService, security service.SecurityService, log *logrus.Entry) *AdminController {
 	return &AdminController{
-		userRepo: userRepo,
-		log:      log,
+		userRepo:  userRepo,
+		roleRepo:  roleRepo,
+		twoFactor: twoFactor,
+		security:  security,
+		log:       log,
 	}
 }
 
+// adminID returns the ID of the admin making the request
+func adminID(c *gin.Context) int64 {
+	id, _ := c.Get("user_id")
+	adminID, _ := id.(int64)
+	return adminID
+}
+
+// verifyAdminGrant requires the acting admin to confirm granting the admin
+// role with a two-factor code. It writes the response and returns false
+// when the grant is not allowed.
+func (ac *AdminController) verifyAdminGrant(c *gin.Context, role, otp string) bool {
+	if role != models.RoleAdmin {
+		return true
+	}
+
+	if err := ac.twoFactor.Verify(c.Request.Context(), adminID(c), otp); err != nil {
+		if errors.Is(err, service.ErrTwoFactorRequired) {
+			requestLog(c, ac.log).Warn("admin grant attempted without two-factor authentication")
+			c.JSON(http.StatusForbidden, gin.H{"error": "enable two-factor authentication to grant the admin role"})
+			return false
+		}
+		respondTwoFactorError(c, ac.log, err, "verify admin grant")
+		return false
+	}
+
+	return true
+}
+
 // @Summary Create new user (Admin only)
+// @Description Creating an admin requires the acting admin's two-factor code in otp
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -38,14 +76,14 @@ func NewAdminController(userRepo repository.UserRepository, log *logrus.Entry) *
 func (ac *AdminController) CreateUser(c *gin.Context) {
 	var req models.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		ac.log.WithField("error", err.Error()).Warn("invalid create user request")
+		requestLog(c, ac.log).WithField("error", err.Error()).Warn("invalid create user request")
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Validate role
 	if err := models.ValidateRole(req.Role); err != nil {
-		ac.log.WithFields(map[string]interface{}{
+		requestLog(c, ac.log).WithFields(map[string]interface{}{
 			"role":  req.Role,
 			"error": err.Error(),
 		}).Warn("invalid role")
@@ -53,28 +91,42 @@ func (ac *AdminController) CreateUser(c *gin.Context) {
 		return
 	}
 
+	if !ac.verifyAdminGrant(c, req.Role, req.OTP) {
+		return
+	}
+
 	// Hash password
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
-		ac.log.WithError(err).Error("failed to hash password")
+		requestLog(c, ac.log).WithError(err).Error("failed to hash password")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
 
-	// Create user with specified role
-	user, err := ac.userRepo.CreateWithRole(c.Request.Context(), req.Email, string(passwordHash), req.Role)
+	// Create as a regular user; privileged roles are granted below so the
+	// grant is recorded in the role audit trail
+	user, err := ac.userRepo.CreateWithRole(c.Request.Context(), req.Email, string(passwordHash), models.RoleUser)
 	if err != nil {
 		if err == repository.ErrUserExists {
-			ac.log.WithField("email", req.Email).Warn("user already exists")
+			requestLog(c, ac.log).WithField("email", req.Email).Warn("user already exists")
 			c.JSON(http.StatusConflict, gin.H{"error": "user already exists"})
 			return
 		}
-		ac.log.WithError(err).Error("failed to create user")
+		requestLog(c, ac.log).WithError(err).Error("failed to create user")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
 
-	ac.log.WithFields(map[string]interface{}{
+	if req.Role != models.RoleUser {
+		user, err = ac.roleRepo.SetRole(c.Request.Context(), user.ID, req.Role, adminID(c), "account created by admin")
+		if err != nil {
+			requestLog(c, ac.log).WithError(err).Error("failed to set role of created user")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+	}
+
+	requestLog(c, ac.log).WithFields(map[string]interface{}{
 		"email": req.Email,
 		"role":  req.Role,
 	}).Info("user created by admin")
@@ -85,6 +137,7 @@ func (ac *AdminController) CreateUser(c *gin.Context) {
 }
 
 // @Summary Update user role (Admin only)
+// @Description The change is recorded in the role audit trail with the optional reason. Granting admin requires the acting admin's two-factor code in otp.
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -100,21 +153,21 @@ func (ac *AdminController) CreateUser(c *gin.Context) {
 func (ac *AdminController) UpdateUserRole(c *gin.Context) {
 	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		ac.log.WithField("id", c.Param("id")).Warn("invalid user id")
+		requestLog(c, ac.log).WithField("id", c.Param("id")).Warn("invalid user id")
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
 		return
 	}
 
 	var req models.UpdateRoleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		ac.log.WithField("error", err.Error()).Warn("invalid update role request")
+		requestLog(c, ac.log).WithField("error", err.Error()).Warn("invalid update role request")
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Validate role
 	if err := models.ValidateRole(req.Role); err != nil {
-		ac.log.WithFields(map[string]interface{}{
+		requestLog(c, ac.log).WithFields(map[string]interface{}{
 			"role":  req.Role,
 			"error": err.Error(),
 		}).Warn("invalid role")
@@ -122,20 +175,24 @@ func (ac *AdminController) UpdateUserRole(c *gin.Context) {
 		return
 	}
 
-	// Update role
-	user, err := ac.userRepo.UpdateRole(c.Request.Context(), userID, req.Role)
+	if !ac.verifyAdminGrant(c, req.Role, req.OTP) {
+		return
+	}
+
+	// Update role and record the change
+	user, err := ac.roleRepo.SetRole(c.Request.Context(), userID, req.Role, adminID(c), req.Reason)
 	if err != nil {
 		if err == repository.ErrUserNotFound {
-			ac.log.WithField("user_id", userID).Warn("user not found")
+			requestLog(c, ac.log).WithField("user_id", userID).Warn("user not found")
 			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
 			return
 		}
-		ac.log.WithError(err).Error("failed to update user role")
+		requestLog(c, ac.log).WithError(err).Error("failed to update user role")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
 
-	ac.log.WithFields(map[string]interface{}{
+	requestLog(c, ac.log).WithFields(map[string]interface{}{
 		"user_id":  userID,
 		"email":    user.Email,
 		"new_role": req.Role,
@@ -162,7 +219,7 @@ func (ac *AdminController) UpdateUserRole(c *gin.Context) {
 func (ac *AdminController) DeleteUser(c *gin.Context) {
 	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		ac.log.WithField("id", c.Param("id")).Warn("invalid user id")
+		requestLog(c, ac.log).WithField("id", c.Param("id")).Warn("invalid user id")
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
 		return
 	}
@@ -170,7 +227,7 @@ func (ac *AdminController) DeleteUser(c *gin.Context) {
 	// Prevent deleting yourself
 	currentUserID, exists := c.Get("user_id")
 	if exists && currentUserID.(int64) == userID {
-		ac.log.WithField("user_id", userID).Warn("admin attempted to delete themselves")
+		requestLog(c, ac.log).WithField("user_id", userID).Warn("admin attempted to delete themselves")
 		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot delete yourself"})
 		return
 	}
@@ -179,20 +236,238 @@ func (ac *AdminController) DeleteUser(c *gin.Context) {
 	err = ac.userRepo.Delete(c.Request.Context(), userID)
 	if err != nil {
 		if err == repository.ErrUserNotFound {
-			ac.log.WithField("user_id", userID).Warn("user not found for deletion")
+			requestLog(c, ac.log).WithField("user_id", userID).Warn("user not found for deletion")
 			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
 			return
 		}
-		ac.log.WithError(err).Error("failed to delete user")
+		requestLog(c, ac.log).WithError(err).Error("failed to delete user")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
 
-	ac.log.WithField("user_id", userID).Info("user deleted by admin")
+	requestLog(c, ac.log).WithField("user_id", userID).Info("user deleted by admin")
 
 	c.JSON(http.StatusOK, gin.H{"message": "user deleted successfully"})
 }
 
+// @Summary Force a password reset (Admin only)
+// @Description Signs the user out of every session, blocks password login until the password is reset and emails a reset link. Access tokens already issued are rejected by Auth and stop passing introspection at once.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/users/{id}/force-password-reset [post]
+func (ac *AdminController) ForcePasswordReset(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		requestLog(c, ac.log).WithField("id", c.Param("id")).Warn("invalid user id")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	// The account is locked before the email is sent; without email the
+	// user resets the password through /auth/password-reset/request later
+	emailSent := true
+	if err := ac.security.ForcePasswordReset(c.Request.Context(), userID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrEmailDisabled):
+			emailSent = false
+		case errors.Is(err, repository.ErrUserNotFound):
+			requestLog(c, ac.log).WithField("user_id", userID).Warn("user not found")
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		default:
+			requestLog(c, ac.log).WithError(err).Error("failed to force password reset")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+	}
+
+	requestLog(c, ac.log).WithFields(map[string]interface{}{
+		"user_id":    userID,
+		"admin_id":   adminID(c),
+		"email_sent": emailSent,
+	}).Warn("password reset forced by admin")
+
+	c.JSON(http.StatusOK, gin.H{"message": "sessions revoked; password reset required", "email_sent": emailSent})
+}
+
+// @Summary Revoke all sessions of a user (Admin only)
+// @Description Revokes every refresh token of the user. Access tokens already issued are rejected by Auth and stop passing introspection at once.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/users/{id}/revoke-sessions [post]
+func (ac *AdminController) RevokeSessions(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		requestLog(c, ac.log).WithField("id", c.Param("id")).Warn("invalid user id")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	if err := ac.security.RevokeSessions(c.Request.Context(), userID); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			requestLog(c, ac.log).WithField("user_id", userID).Warn("user not found")
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		requestLog(c, ac.log).WithError(err).Error("failed to revoke sessions")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	requestLog(c, ac.log).WithFields(map[string]interface{}{
+		"user_id":  userID,
+		"admin_id": adminID(c),
+	}).Warn("sessions revoked by admin")
+
+	c.JSON(http.StatusOK, gin.H{"message": "sessions revoked"})
+}
+
+// @Summary Suspend a user (Admin only)
+// @Description Blocks every sign-in of the user, which answers 403 with code account_suspended, and revokes all sessions like revoke-sessions. Access tokens already issued are rejected at once.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/users/{id}/suspend [post]
+func (ac *AdminController) SuspendUser(c *gin.Context) {
+	ac.setSuspended(c, true)
+}
+
+// @Summary Suspend a user for a service
+// @Description Suspends the user like /admin/users/{id}/suspend for other services, authenticated with HTTP Basic client credentials, e.g. when a Market moderator bans the user.
+// @Tags auth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /auth/users/{id}/suspend [post]
+func (ac *AdminController) ServiceSuspendUser(c *gin.Context) {
+	ac.setSuspended(c, true)
+}
+
+// @Summary Lift the suspension of a user (Admin only)
+// @Description Lets a suspended user sign in again. Sessions revoked by the suspension stay revoked.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/users/{id}/unsuspend [post]
+func (ac *AdminController) UnsuspendUser(c *gin.Context) {
+	ac.setSuspended(c, false)
+}
+
+// setSuspended suspends the user or lifts the suspension for an admin or,
+// through service credentials, another service
+func (ac *AdminController) setSuspended(c *gin.Context, suspended bool) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		requestLog(c, ac.log).WithField("id", c.Param("id")).Warn("invalid user id")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	if suspended {
+		err = ac.security.Suspend(c.Request.Context(), userID)
+	} else {
+		err = ac.security.Unsuspend(c.Request.Context(), userID)
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			requestLog(c, ac.log).WithField("user_id", userID).Warn("user not found")
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		requestLog(c, ac.log).WithError(err).Error("failed to update user suspension")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	entry := requestLog(c, ac.log).WithFields(map[string]interface{}{
+		"user_id":  userID,
+		"admin_id": adminID(c),
+		"client":   c.GetString(middleware.ContextServiceClient),
+	})
+	if !suspended {
+		entry.Info("user suspension lifted")
+		c.JSON(http.StatusOK, gin.H{"message": "suspension lifted"})
+		return
+	}
+	entry.Warn("user suspended")
+	c.JSON(http.StatusOK, gin.H{"message": "user suspended; sessions revoked"})
+}
+
+// @Summary Re-send the verification email of a user (Admin only)
+// @Description Emails the user a new email verification link. Links sent earlier stay valid until they expire.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string "Email already verified"
+// @Failure 501 {object} map[string]string "Verification email disabled"
+// @Router /admin/users/{id}/resend-verification [post]
+func (ac *AdminController) ResendVerification(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		requestLog(c, ac.log).WithField("id", c.Param("id")).Warn("invalid user id")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	if err := ac.security.SendEmailVerification(c.Request.Context(), userID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrUserNotFound):
+			requestLog(c, ac.log).WithField("user_id", userID).Warn("user not found")
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		case errors.Is(err, service.ErrEmailAlreadyVerified):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrEmailDisabled):
+			c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+		default:
+			requestLog(c, ac.log).WithError(err).Error("failed to send verification email")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	requestLog(c, ac.log).WithFields(map[string]interface{}{
+		"user_id":  userID,
+		"admin_id": adminID(c),
+	}).Info("verification email re-sent by admin")
+
+	c.JSON(http.StatusOK, gin.H{"message": "verification email sent"})
+}
+
 // @Summary List all users (Admin only)
 // @Tags admin
 // @Accept json
@@ -223,12 +498,12 @@ func (ac *AdminController) ListUsers(c *gin.Context) {
 
 	users, err := ac.userRepo.List(c.Request.Context(), limit, offset)
 	if err != nil {
-		ac.log.WithError(err).Error("failed to list users")
+		requestLog(c, ac.log).WithError(err).Error("failed to list users")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
 
-	ac.log.WithFields(map[string]interface{}{
+	requestLog(c, ac.log).WithFields(map[string]interface{}{
 		"count":  len(users),
 		"limit":  limit,
 		"offset": offset,
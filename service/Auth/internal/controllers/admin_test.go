@@ -11,6 +11,7 @@ import (
 
 	"github.com/Zifeldev/marketback/service/Auth/internal/models"
 	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/Zifeldev/marketback/service/Auth/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -74,15 +75,34 @@ func (m *MockUserRepository) List(ctx context.Context, limit, offset int) ([]*mo
 	return args.Get(0).([]*models.User), args.Error(1)
 }
 
+func (m *MockUserRepository) ListByIDs(ctx context.Context, ids []int64) ([]*models.User, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.User), args.Error(1)
+}
+
 func setupAdminTest() (*gin.Engine, *MockUserRepository, *AdminController) {
+	r, mockRepo, _, _, controller := setupAdminRoleTest()
+	return r, mockRepo, controller
+}
+
+func setupAdminRoleTest() (*gin.Engine, *MockUserRepository, *MockRoleRepository, *MockTwoFactorService, *AdminController) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", int64(42))
+		c.Next()
+	})
 
 	mockRepo := new(MockUserRepository)
+	mockRoleRepo := new(MockRoleRepository)
+	mockTwoFactor := new(MockTwoFactorService)
 	log := logrus.NewEntry(logrus.New())
-	controller := NewAdminController(mockRepo, log)
+	controller := NewAdminController(mockRepo, mockRoleRepo, mockTwoFactor, new(MockSecurityService), log)
 
-	return r, mockRepo, controller
+	return r, mockRepo, mockRoleRepo, mockTwoFactor, controller
 }
 
 func TestCreateUser_Success(t *testing.T) {
@@ -237,7 +257,7 @@ func TestListUsers_WithPagination(t *testing.T) {
 }
 
 func TestUpdateUserRole_Success(t *testing.T) {
-	r, mockRepo, controller := setupAdminTest()
+	r, _, mockRoleRepo, mockTwoFactor, controller := setupAdminRoleTest()
 
 	r.PUT("/admin/users/:id/role", controller.UpdateUserRole)
 
@@ -247,11 +267,14 @@ func TestUpdateUserRole_Success(t *testing.T) {
 		Role:  models.RoleAdmin,
 	}
 
-	mockRepo.On("UpdateRole", mock.Anything, int64(1), models.RoleAdmin).
+	mockTwoFactor.On("Verify", mock.Anything, int64(42), "123456").Return(nil)
+	mockRoleRepo.On("SetRole", mock.Anything, int64(1), models.RoleAdmin, int64(42), "promoted to support lead").
 		Return(mockUser, nil)
 
 	reqBody := map[string]string{
-		"role": "admin",
+		"role":   "admin",
+		"reason": "promoted to support lead",
+		"otp":    "123456",
 	}
 	body, _ := json.Marshal(reqBody)
 
@@ -263,7 +286,7 @@ func TestUpdateUserRole_Success(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	mockRepo.AssertExpectations(t)
+	mockRoleRepo.AssertExpectations(t)
 }
 
 func TestUpdateUserRole_InvalidRole(t *testing.T) {
@@ -286,15 +309,15 @@ func TestUpdateUserRole_InvalidRole(t *testing.T) {
 }
 
 func TestUpdateUserRole_UserNotFound(t *testing.T) {
-	r, mockRepo, controller := setupAdminTest()
+	r, _, mockRoleRepo, _, controller := setupAdminRoleTest()
 
 	r.PUT("/admin/users/:id/role", controller.UpdateUserRole)
 
-	mockRepo.On("UpdateRole", mock.Anything, int64(999), models.RoleAdmin).
+	mockRoleRepo.On("SetRole", mock.Anything, int64(999), models.RoleSeller, int64(42), "").
 		Return(nil, repository.ErrUserNotFound)
 
 	reqBody := map[string]string{
-		"role": "admin",
+		"role": "seller",
 	}
 	body, _ := json.Marshal(reqBody)
 
@@ -306,7 +329,7 @@ func TestUpdateUserRole_UserNotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 
-	mockRepo.AssertExpectations(t)
+	mockRoleRepo.AssertExpectations(t)
 }
 
 func TestDeleteUser_Success(t *testing.T) {
@@ -362,3 +385,95 @@ func TestDeleteUser_InvalidID(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func setupAdminSecurityTest() (*gin.Engine, *MockSecurityService, *AdminController) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", int64(42))
+		c.Next()
+	})
+
+	mockSecurity := new(MockSecurityService)
+	controller := NewAdminController(new(MockUserRepository), new(MockRoleRepository), new(MockTwoFactorService), mockSecurity, logrus.NewEntry(logrus.New()))
+
+	return r, mockSecurity, controller
+}
+
+func TestForcePasswordReset(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		code      int
+		emailSent bool
+	}{
+		{"emailed", nil, http.StatusOK, true},
+		{"email disabled", service.ErrEmailDisabled, http.StatusOK, false},
+		{"not found", repository.ErrUserNotFound, http.StatusNotFound, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mockSecurity, controller := setupAdminSecurityTest()
+			r.POST("/admin/users/:id/force-password-reset", controller.ForcePasswordReset)
+
+			mockSecurity.On("ForcePasswordReset", mock.Anything, int64(7)).Return(tt.err)
+
+			w := postJSON(r, "/admin/users/7/force-password-reset", nil)
+
+			assert.Equal(t, tt.code, w.Code)
+			if tt.code == http.StatusOK {
+				var response map[string]interface{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.emailSent, response["email_sent"])
+			}
+			mockSecurity.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevokeSessions(t *testing.T) {
+	r, mockSecurity, controller := setupAdminSecurityTest()
+	r.POST("/admin/users/:id/revoke-sessions", controller.RevokeSessions)
+
+	mockSecurity.On("RevokeSessions", mock.Anything, int64(7)).Return(nil)
+	mockSecurity.On("RevokeSessions", mock.Anything, int64(8)).Return(repository.ErrUserNotFound)
+
+	assert.Equal(t, http.StatusOK, postJSON(r, "/admin/users/7/revoke-sessions", nil).Code)
+	assert.Equal(t, http.StatusNotFound, postJSON(r, "/admin/users/8/revoke-sessions", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, postJSON(r, "/admin/users/abc/revoke-sessions", nil).Code)
+	mockSecurity.AssertExpectations(t)
+}
+
+func TestSuspendUser(t *testing.T) {
+	r, mockSecurity, controller := setupAdminSecurityTest()
+	r.POST("/admin/users/:id/suspend", controller.SuspendUser)
+	r.POST("/admin/users/:id/unsuspend", controller.UnsuspendUser)
+
+	mockSecurity.On("Suspend", mock.Anything, int64(7)).Return(nil)
+	mockSecurity.On("Suspend", mock.Anything, int64(8)).Return(repository.ErrUserNotFound)
+	mockSecurity.On("Unsuspend", mock.Anything, int64(7)).Return(nil)
+
+	assert.Equal(t, http.StatusOK, postJSON(r, "/admin/users/7/suspend", nil).Code)
+	assert.Equal(t, http.StatusNotFound, postJSON(r, "/admin/users/8/suspend", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, postJSON(r, "/admin/users/abc/suspend", nil).Code)
+	assert.Equal(t, http.StatusOK, postJSON(r, "/admin/users/7/unsuspend", nil).Code)
+	mockSecurity.AssertExpectations(t)
+}
+
+func TestResendVerification(t *testing.T) {
+	r, mockSecurity, controller := setupAdminSecurityTest()
+	r.POST("/admin/users/:id/resend-verification", controller.ResendVerification)
+
+	mockSecurity.On("SendEmailVerification", mock.Anything, int64(7)).Return(nil)
+	mockSecurity.On("SendEmailVerification", mock.Anything, int64(8)).Return(repository.ErrUserNotFound)
+	mockSecurity.On("SendEmailVerification", mock.Anything, int64(9)).Return(service.ErrEmailAlreadyVerified)
+	mockSecurity.On("SendEmailVerification", mock.Anything, int64(10)).Return(service.ErrEmailDisabled)
+
+	assert.Equal(t, http.StatusOK, postJSON(r, "/admin/users/7/resend-verification", nil).Code)
+	assert.Equal(t, http.StatusNotFound, postJSON(r, "/admin/users/8/resend-verification", nil).Code)
+	assert.Equal(t, http.StatusConflict, postJSON(r, "/admin/users/9/resend-verification", nil).Code)
+	assert.Equal(t, http.StatusNotImplemented, postJSON(r, "/admin/users/10/resend-verification", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, postJSON(r, "/admin/users/abc/resend-verification", nil).Code)
+	mockSecurity.AssertExpectations(t)
+}
@@ -1,7 +1,9 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/Zifeldev/marketback/service/Auth/internal/models"
 	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
@@ -11,21 +13,36 @@ import (
 )
 
 type AuthController struct {
-	authService service.AuthService
-	log         *logrus.Entry
+	authService     service.AuthService
+	securityService service.SecurityService
+	log             *logrus.Entry
 }
 
-func NewAuthController(authService service.AuthService, log *logrus.Entry) *AuthController {
+// NewAuthController creates the controller; a nil securityService disables
+// sign-in alerts and registration emails
+func NewAuthController(authService service.AuthService, securityService service.SecurityService, log *logrus.Entry) *AuthController {
 	return &AuthController{
-		authService: authService,
-		log:         log,
+		authService:     authService,
+		securityService: securityService,
+		log:             log,
 	}
 }
 
+// @Summary Register user
+// @Description Creates an account with the user role and signs it in. Privileged roles are requested afterwards via /api/role-requests.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.RegisterRequest true "Account details"
+// @Success 201 {object} models.TokenPair
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /auth/register [post]
 func (ac *AuthController) Register(c *gin.Context) {
 	var req models.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		ac.log.WithField("error", err.Error()).Warn("invalid registration request")
+		requestLog(c, ac.log).WithField("error", err.Error()).Warn("invalid registration request")
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -33,20 +50,26 @@ func (ac *AuthController) Register(c *gin.Context) {
 
 	if req.Role != "" {
 		if err := models.ValidateRole(req.Role); err != nil {
-			ac.log.WithField("role", req.Role).Warn("invalid role provided")
+			requestLog(c, ac.log).WithField("role", req.Role).Warn("invalid role provided")
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid role"})
 			return
 		}
+		// Privileged roles are requested after sign-up and granted by an admin
+		if req.Role != models.RoleUser {
+			requestLog(c, ac.log).WithField("role", req.Role).Warn("privileged role requested at registration")
+			c.JSON(http.StatusForbidden, gin.H{"error": "privileged roles must be requested via /api/role-requests"})
+			return
+		}
 	}
 
-	tokens, err := ac.authService.Register(c.Request.Context(), req.Email, req.Password, req.Role)
+	tokens, err := ac.authService.Register(c.Request.Context(), req.Email, req.Password)
 	if err != nil {
 		if err == repository.ErrUserExists {
-			ac.log.WithField("email", req.Email).Warn("user already exists")
+			requestLog(c, ac.log).WithField("email", req.Email).Warn("user already exists")
 			c.JSON(http.StatusConflict, gin.H{"error": "user already exists"})
 			return
 		}
-		ac.log.WithError(err).Error("failed to register user")
+		requestLog(c, ac.log).WithError(err).Error("failed to register user")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
@@ -55,7 +78,9 @@ func (ac *AuthController) Register(c *gin.Context) {
 	c.SetCookie("access_token", tokens.AccessToken, 15*60, "/", "", false, true)
 	c.SetCookie("refresh_token", tokens.RefreshToken, 24*60*60, "/", "", false, true)
 
-	ac.log.WithField("email", req.Email).Info("user registered successfully")
+	requestLog(c, ac.log).WithField("email", req.Email).Info("user registered successfully")
+	notifyRegistered(c, ac.securityService, tokens, ac.log)
+	notifyLogin(c, ac.securityService, tokens, ac.log)
 
 	c.JSON(http.StatusCreated, gin.H{
 		"access_token":  tokens.AccessToken,
@@ -72,11 +97,12 @@ func (ac *AuthController) Register(c *gin.Context) {
 // @Success 200 {object} models.TokenPair
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string "Password reset or change required, email not verified or account suspended"
 // @Router /auth/login [post]
 func (ac *AuthController) Login(c *gin.Context) {
 	var req models.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		ac.log.WithField("error", err.Error()).Warn("invalid login request")
+		requestLog(c, ac.log).WithField("error", err.Error()).Warn("invalid login request")
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -84,11 +110,31 @@ func (ac *AuthController) Login(c *gin.Context) {
 	tokens, err := ac.authService.Login(c.Request.Context(), req.Email, req.Password)
 	if err != nil {
 		if err == service.ErrInvalidCredentials {
-			ac.log.WithField("email", req.Email).Warn("invalid credentials")
+			requestLog(c, ac.log).WithField("email", req.Email).Warn("invalid credentials")
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
 			return
 		}
-		ac.log.WithError(err).Error("failed to login user")
+		if err == service.ErrPasswordResetRequired {
+			requestLog(c, ac.log).WithField("email", req.Email).Warn("login blocked until password reset")
+			c.JSON(http.StatusForbidden, gin.H{"error": "password reset required", "code": "password_reset_required"})
+			return
+		}
+		if err == service.ErrPasswordChangeRequired {
+			requestLog(c, ac.log).WithField("email", req.Email).Warn("login blocked until temporary password is changed")
+			c.JSON(http.StatusForbidden, gin.H{"error": "password change required", "code": "password_change_required"})
+			return
+		}
+		if err == service.ErrEmailNotVerified {
+			requestLog(c, ac.log).WithField("email", req.Email).Warn("login blocked until email is verified")
+			c.JSON(http.StatusForbidden, gin.H{"error": "email not verified", "code": "email_not_verified"})
+			return
+		}
+		if err == service.ErrAccountSuspended {
+			requestLog(c, ac.log).WithField("email", req.Email).Warn("login blocked for suspended account")
+			c.JSON(http.StatusForbidden, gin.H{"error": "account suspended", "code": "account_suspended"})
+			return
+		}
+		requestLog(c, ac.log).WithError(err).Error("failed to login user")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
@@ -96,7 +142,8 @@ func (ac *AuthController) Login(c *gin.Context) {
 	c.SetCookie("access_token", tokens.AccessToken, 15*60, "/", "", false, true)
 	c.SetCookie("refresh_token", tokens.RefreshToken, 24*60*60, "/", "", false, true)
 
-	ac.log.WithField("email", req.Email).Info("user logged in successfully")
+	requestLog(c, ac.log).WithField("email", req.Email).Info("user logged in successfully")
+	notifyLogin(c, ac.securityService, tokens, ac.log)
 
 	c.JSON(http.StatusOK, gin.H{
 		"access_token":  tokens.AccessToken,
@@ -105,12 +152,22 @@ func (ac *AuthController) Login(c *gin.Context) {
 	})
 }
 
+// @Summary Refresh tokens
+// @Description Rotates the refresh token from the refresh_token cookie or the body and issues a new token pair. Presenting a refresh token that was already rotated is treated as theft: every token rotated from it is revoked, signing the session out, and the 401 carries code refresh_token_reused.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.RefreshRequest false "Refresh token, when not sent as a cookie"
+// @Success 200 {object} models.TokenPair
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /auth/refresh [post]
 func (ac *AuthController) Refresh(c *gin.Context) {
 	refreshToken, err := c.Cookie("refresh_token")
 	if err != nil || refreshToken == "" {
 		var req models.RefreshRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
-			ac.log.WithField("error", err.Error()).Warn("invalid refresh request")
+			requestLog(c, ac.log).WithField("error", err.Error()).Warn("invalid refresh request")
 			c.JSON(http.StatusBadRequest, gin.H{"error": "refresh token required in cookie or body"})
 			return
 		}
@@ -118,8 +175,23 @@ func (ac *AuthController) Refresh(c *gin.Context) {
 	}
 
 	tokens, err := ac.authService.RefreshTokens(c.Request.Context(), refreshToken)
+	if errors.Is(err, service.ErrTokenReused) {
+		requestLog(c, ac.log).WithError(err).WithFields(map[string]interface{}{
+			"security_event": "refresh_token_reuse",
+			"client_ip":      c.ClientIP(),
+		}).Warn("rotated refresh token presented again; token family revoked")
+		c.SetCookie("access_token", "", -1, "/", "", false, true)
+		c.SetCookie("refresh_token", "", -1, "/", "", false, true)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "refresh token reuse detected", "code": "refresh_token_reused"})
+		return
+	}
+	if errors.Is(err, service.ErrClientMismatch) {
+		requestLog(c, ac.log).WithField("client_ip", c.ClientIP()).Warn("refresh token presented by another client")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired refresh token"})
+		return
+	}
 	if err != nil {
-		ac.log.WithError(err).Warn("failed to refresh tokens")
+		requestLog(c, ac.log).WithError(err).Warn("failed to refresh tokens")
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired refresh token"})
 		return
 	}
@@ -127,7 +199,7 @@ func (ac *AuthController) Refresh(c *gin.Context) {
 	c.SetCookie("access_token", tokens.AccessToken, 15*60, "/", "", false, true)
 	c.SetCookie("refresh_token", tokens.RefreshToken, 24*60*60, "/", "", false, true)
 
-	ac.log.Info("tokens refreshed successfully")
+	requestLog(c, ac.log).Info("tokens refreshed successfully")
 
 	c.JSON(http.StatusOK, gin.H{
 		"access_token":  tokens.AccessToken,
@@ -136,12 +208,22 @@ func (ac *AuthController) Refresh(c *gin.Context) {
 	})
 }
 
+// @Summary Logout user
+// @Description Revokes the refresh token and, when one is presented, the current access token.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.RefreshRequest false "Refresh token, when not sent as a cookie"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /auth/logout [post]
 func (ac *AuthController) Logout(c *gin.Context) {
 	refreshToken, err := c.Cookie("refresh_token")
 	if err != nil || refreshToken == "" {
 		var req models.RefreshRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
-			ac.log.WithField("error", err.Error()).Warn("invalid logout request")
+			requestLog(c, ac.log).WithField("error", err.Error()).Warn("invalid logout request")
 			c.JSON(http.StatusBadRequest, gin.H{"error": "refresh token required in cookie or body"})
 			return
 		}
@@ -149,13 +231,108 @@ func (ac *AuthController) Logout(c *gin.Context) {
 	}
 
 	if err := ac.authService.RevokeToken(c.Request.Context(), refreshToken); err != nil {
-		ac.log.WithError(err).Error("failed to revoke token")
+		requestLog(c, ac.log).WithError(err).Error("failed to revoke token")
+	}
+
+	// Revoke the current access token too, so Auth and Market reject it
+	// before it expires
+	if accessToken := presentedAccessToken(c); accessToken != "" {
+		if err := ac.authService.RevokeAccessToken(c.Request.Context(), accessToken, "logout"); err != nil {
+			requestLog(c, ac.log).WithError(err).Warn("failed to revoke access token")
+		}
 	}
 
 	c.SetCookie("access_token", "", -1, "/", "", false, true)
 	c.SetCookie("refresh_token", "", -1, "/", "", false, true)
 
-	ac.log.Info("user logged out successfully")
+	requestLog(c, ac.log).Info("user logged out successfully")
 
 	c.JSON(http.StatusOK, gin.H{"message": "logged out successfully"})
 }
+
+// presentedAccessToken reads the access token from the Authorization header
+// or the access_token cookie
+func presentedAccessToken(c *gin.Context) string {
+	if header := c.GetHeader("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimPrefix(header, "Bearer ")
+	}
+	token, _ := c.Cookie("access_token")
+	return token
+}
+
+// @Summary Introspect a token
+// @Description RFC 7662 style introspection for other services, authenticated with HTTP Basic client credentials. Unknown, expired and revoked tokens return only {"active": false}.
+// @Tags auth
+// @Accept x-www-form-urlencoded,json
+// @Produce json
+// @Param token formData string true "Access or refresh token"
+// @Param token_type_hint formData string false "access_token or refresh_token"
+// @Success 200 {object} models.IntrospectionResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /auth/introspect [post]
+func (ac *AuthController) Introspect(c *gin.Context) {
+	var req models.IntrospectRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := ac.authService.Introspect(c.Request.Context(), req.Token, req.TokenTypeHint)
+	if err != nil {
+		requestLog(c, ac.log).WithError(err).Error("failed to introspect token")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	client, _ := c.Get("service_client")
+	requestLog(c, ac.log).WithFields(logrus.Fields{
+		"client":     client,
+		"active":     resp.Active,
+		"token_type": resp.TokenType,
+	}).Debug("token introspected")
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Issue a scoped access token
+// @Description Mints an access token limited to some of the current token's scopes (space separated, e.g. "market:read"). No refresh token is issued.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ScopedTokenRequest true "Requested scopes"
+// @Success 200 {object} models.ScopedTokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/tokens/scoped [post]
+func (ac *AuthController) IssueScopedToken(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	scopes, _ := c.Get("user_scopes")
+	granted, _ := scopes.([]string)
+
+	var req models.ScopedTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, err := ac.authService.IssueScopedToken(c.Request.Context(), userID.(int64), granted, req.Scope)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidScope):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "scope must be a subset of: " + models.FormatScope(granted)})
+		case errors.Is(err, repository.ErrUserNotFound):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		default:
+			requestLog(c, ac.log).WithError(err).Error("failed to issue scoped token")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	requestLog(c, ac.log).WithField("scope", token.Scope).Info("scoped access token issued")
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, token)
+}
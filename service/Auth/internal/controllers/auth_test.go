@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/Zifeldev/marketback/service/Auth/internal/models"
@@ -21,8 +23,8 @@ type MockAuthService struct {
 	mock.Mock
 }
 
-func (m *MockAuthService) Register(ctx context.Context, email, password, role string) (*models.TokenPair, error) {
-	args := m.Called(ctx, email, password, role)
+func (m *MockAuthService) Register(ctx context.Context, email, password string) (*models.TokenPair, error) {
+	args := m.Called(ctx, email, password)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -58,13 +60,47 @@ func (m *MockAuthService) ValidateAccessToken(token string) (*models.AccessToken
 	return args.Get(0).(*models.AccessTokenClaims), args.Error(1)
 }
 
+func (m *MockAuthService) RevokeAccessToken(ctx context.Context, accessToken, reason string) error {
+	args := m.Called(ctx, accessToken, reason)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) AccessTokenRevoked(ctx context.Context, claims *models.AccessTokenClaims) (bool, error) {
+	args := m.Called(ctx, claims)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAuthService) Introspect(ctx context.Context, token, tokenTypeHint string) (*models.IntrospectionResponse, error) {
+	args := m.Called(ctx, token, tokenTypeHint)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IntrospectionResponse), args.Error(1)
+}
+
+func (m *MockAuthService) IssueScopedToken(ctx context.Context, userID int64, granted []string, scope string) (*models.ScopedTokenResponse, error) {
+	args := m.Called(ctx, userID, granted, scope)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ScopedTokenResponse), args.Error(1)
+}
+
+func (m *MockAuthService) IssueTokens(ctx context.Context, userID int64) (*models.TokenPair, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TokenPair), args.Error(1)
+}
+
 func setupTest() (*gin.Engine, *MockAuthService, *AuthController) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
 
 	mockService := new(MockAuthService)
 	log := logrus.NewEntry(logrus.New())
-	controller := NewAuthController(mockService, log)
+	controller := NewAuthController(mockService, nil, log)
 
 	return r, mockService, controller
 }
@@ -80,7 +116,7 @@ func TestRegister_Success(t *testing.T) {
 		ExpiresIn:    900,
 	}
 
-	mockService.On("Register", mock.Anything, "test@example.com", "password123", mock.Anything).
+	mockService.On("Register", mock.Anything, "test@example.com", "password123").
 		Return(mockTokens, nil)
 
 	reqBody := map[string]string{
@@ -115,7 +151,7 @@ func TestRegister_UserExists(t *testing.T) {
 
 	r.POST("/auth/register", controller.Register)
 
-	mockService.On("Register", mock.Anything, "existing@example.com", "password123", mock.Anything).
+	mockService.On("Register", mock.Anything, "existing@example.com", "password123").
 		Return(nil, repository.ErrUserExists)
 
 	reqBody := map[string]string{
@@ -308,6 +344,29 @@ func TestRefresh_InvalidToken(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestRefresh_ReusedToken(t *testing.T) {
+	r, mockService, controller := setupTest()
+
+	r.POST("/auth/refresh", controller.Refresh)
+
+	mockService.On("RefreshTokens", mock.Anything, "rotated_token").
+		Return(nil, fmt.Errorf("%w: token family of user 7 revoked", service.ErrTokenReused))
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/refresh", nil)
+	req.AddCookie(&http.Cookie{Name: "refresh_token", Value: "rotated_token"})
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "refresh_token_reused", response["code"])
+	assert.Len(t, w.Result().Cookies(), 2)
+
+	mockService.AssertExpectations(t)
+}
+
 func TestLogout_Success_FromCookie(t *testing.T) {
 	r, mockService, controller := setupTest()
 
@@ -362,76 +421,136 @@ func TestLogout_Success_FromBody(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
-// --- Role-based Registration Tests ---
-
-func TestRegister_WithSellerRole(t *testing.T) {
+func TestLogout_RevokesAccessToken(t *testing.T) {
 	r, mockService, controller := setupTest()
 
-	r.POST("/auth/register", controller.Register)
+	r.POST("/auth/logout", controller.Logout)
 
-	mockTokens := &models.TokenPair{
-		AccessToken:  "seller_access_token",
-		RefreshToken: "seller_refresh_token",
-		ExpiresIn:    900,
-	}
+	mockService.On("RevokeToken", mock.Anything, "refresh").Return(nil)
+	mockService.On("RevokeAccessToken", mock.Anything, "access", "logout").Return(nil)
 
-	// Expect registration with seller role
-	mockService.On("Register", mock.Anything, "seller@example.com", "password123", "seller").
-		Return(mockTokens, nil)
+	req := httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+	req.Header.Set("Authorization", "Bearer access")
+	req.AddCookie(&http.Cookie{Name: "refresh_token", Value: "refresh"})
+	w := httptest.NewRecorder()
 
-	reqBody := map[string]string{
-		"email":    "seller@example.com",
-		"password": "password123",
-		"role":     "seller",
-	}
-	body, _ := json.Marshal(reqBody)
+	r.ServeHTTP(w, req)
 
-	req := httptest.NewRequest(http.MethodPost, "/auth/register", bytes.NewBuffer(body))
-	req.Header.Set("Content-Type", "application/json")
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+// --- Introspection Tests ---
+
+func TestIntrospect_Form(t *testing.T) {
+	r, mockService, controller := setupTest()
+
+	r.POST("/auth/introspect", controller.Introspect)
+
+	mockService.On("Introspect", mock.Anything, "some-token", "refresh_token").
+		Return(&models.IntrospectionResponse{Active: true, TokenType: models.TokenTypeRefresh, UserID: 3}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/introspect", strings.NewReader("token=some-token&token_type_hint=refresh_token"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	w := httptest.NewRecorder()
 
 	r.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusCreated, w.Code)
-
-	var response map[string]interface{}
-	err := json.Unmarshal(w.Body.Bytes(), &response)
-	assert.NoError(t, err)
-	assert.Equal(t, "seller_access_token", response["access_token"])
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
 
+	var response models.IntrospectionResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Active)
+	assert.Equal(t, int64(3), response.UserID)
 	mockService.AssertExpectations(t)
 }
 
-func TestRegister_WithAdminRole(t *testing.T) {
+func TestIntrospect_InactiveOnlyReportsActive(t *testing.T) {
 	r, mockService, controller := setupTest()
 
-	r.POST("/auth/register", controller.Register)
+	r.POST("/auth/introspect", controller.Introspect)
 
-	mockTokens := &models.TokenPair{
-		AccessToken:  "admin_access_token",
-		RefreshToken: "admin_refresh_token",
-		ExpiresIn:    900,
-	}
+	mockService.On("Introspect", mock.Anything, "expired", "").Return(&models.IntrospectionResponse{}, nil)
 
-	mockService.On("Register", mock.Anything, "admin@example.com", "password123", "admin").
-		Return(mockTokens, nil)
+	w := postJSON(r, "/auth/introspect", map[string]string{"token": "expired"})
 
-	reqBody := map[string]string{
-		"email":    "admin@example.com",
-		"password": "password123",
-		"role":     "admin",
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"active":false}`, w.Body.String())
+}
+
+func TestIntrospect_MissingToken(t *testing.T) {
+	r, mockService, controller := setupTest()
+
+	r.POST("/auth/introspect", controller.Introspect)
+
+	w := postJSON(r, "/auth/introspect", map[string]string{})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "Introspect", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// --- Scoped Token Tests ---
+
+func TestIssueScopedToken(t *testing.T) {
+	granted := models.ScopesForRole(models.RoleUser)
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"narrowed", nil, http.StatusOK},
+		{"not granted", models.ErrInvalidScope, http.StatusBadRequest},
 	}
-	body, _ := json.Marshal(reqBody)
 
-	req := httptest.NewRequest(http.MethodPost, "/auth/register", bytes.NewBuffer(body))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mockService, controller := setupTest()
+			r.POST("/api/tokens/scoped", func(c *gin.Context) {
+				c.Set("user_id", int64(5))
+				c.Set("user_scopes", granted)
+			}, controller.IssueScopedToken)
+
+			var resp *models.ScopedTokenResponse
+			if tt.err == nil {
+				resp = &models.ScopedTokenResponse{AccessToken: "scoped", TokenType: "Bearer", Scope: "market:read"}
+			}
+			mockService.On("IssueScopedToken", mock.Anything, int64(5), granted, "market:read").Return(resp, tt.err)
+
+			w := postJSON(r, "/api/tokens/scoped", map[string]string{"scope": "market:read"})
+
+			assert.Equal(t, tt.code, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
 
-	r.ServeHTTP(w, req)
+// --- Role-based Registration Tests ---
 
-	assert.Equal(t, http.StatusCreated, w.Code)
+func TestRegister_WithPrivilegedRole_Forbidden(t *testing.T) {
+	for _, role := range []string{"seller", "courier", "admin"} {
+		t.Run(role, func(t *testing.T) {
+			r, mockService, controller := setupTest()
 
-	mockService.AssertExpectations(t)
+			r.POST("/auth/register", controller.Register)
+
+			reqBody := map[string]string{
+				"email":    role + "@example.com",
+				"password": "password123",
+				"role":     role,
+			}
+			body, _ := json.Marshal(reqBody)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/register", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			mockService.AssertNotCalled(t, "Register", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
 }
 
 func TestRegister_WithInvalidRole(t *testing.T) {
@@ -472,7 +591,7 @@ func TestRegister_WithEmptyRole_DefaultsToUser(t *testing.T) {
 	}
 
 	// When role is empty, service receives empty string and defaults internally
-	mockService.On("Register", mock.Anything, "user@example.com", "password123", "").
+	mockService.On("Register", mock.Anything, "user@example.com", "password123").
 		Return(mockTokens, nil)
 
 	reqBody := map[string]string{
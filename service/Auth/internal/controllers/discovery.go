@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/config"
+	"github.com/Zifeldev/marketback/service/Auth/internal/keyring"
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// DiscoveryController serves OpenID Connect discovery metadata so standard
+// client libraries can find the issuer and endpoints
+type DiscoveryController struct {
+	document models.OpenIDConfiguration
+	jwks     models.JSONWebKeySet
+}
+
+// NewDiscoveryController builds the discovery document and key set once;
+// introspection is only advertised when service credentials are configured.
+func NewDiscoveryController(cfg *config.JWTConfig, keys *keyring.Ring, introspection bool) *DiscoveryController {
+	doc := models.OpenIDConfiguration{
+		Issuer:                cfg.Issuer,
+		JWKSURI:               cfg.PublicURL + "/.well-known/jwks.json",
+		UserinfoEndpoint:      cfg.PublicURL + "/api/me",
+		SubjectTypesSupported: []string{"public"},
+		ClaimsSupported:       []string{"sub", "aud", "iss", "iat", "exp", "jti", "sid", "email", "role"},
+	}
+	// Without signing keys tokens are HMAC signed: relying parties verify
+	// them with the shared secret (or via introspection)
+	if keys != nil {
+		doc.IDTokenSigningAlgValuesSupported = append(doc.IDTokenSigningAlgValuesSupported, "RS256")
+	}
+	if cfg.AccessSecret != "" {
+		doc.IDTokenSigningAlgValuesSupported = append(doc.IDTokenSigningAlgValuesSupported, "HS256")
+	}
+	if introspection {
+		doc.IntrospectionEndpoint = cfg.PublicURL + "/auth/introspect"
+		doc.IntrospectionEndpointAuthMethods = []string{"client_secret_basic"}
+	}
+
+	return &DiscoveryController{document: doc, jwks: keys.JWKS()}
+}
+
+// @Summary OpenID Connect discovery
+// @Tags discovery
+// @Produce json
+// @Success 200 {object} models.OpenIDConfiguration
+// @Router /.well-known/openid-configuration [get]
+func (dc *DiscoveryController) OpenIDConfiguration(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(http.StatusOK, dc.document)
+}
+
+// @Summary JSON Web Key Set
+// @Description Public keys of the RS256 access tokens, the one signing new tokens first; keys retired by a rotation stay listed until tokens they signed have expired. Empty while tokens are signed with the shared HMAC secret, which is never published
+// @Tags discovery
+// @Produce json
+// @Success 200 {object} models.JSONWebKeySet
+// @Router /.well-known/jwks.json [get]
+func (dc *DiscoveryController) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(http.StatusOK, dc.jwks)
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/config"
+	"github.com/Zifeldev/marketback/service/Auth/internal/keyring"
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenIDConfiguration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.JWTConfig{Issuer: "https://auth.example.com", PublicURL: "https://auth.example.com"}
+
+	for _, introspection := range []bool{true, false} {
+		r := gin.New()
+		r.GET("/.well-known/openid-configuration", NewDiscoveryController(cfg, nil, introspection).OpenIDConfiguration)
+
+		req := httptest.NewRequest(http.MethodGet, "/.well-known/openid-configuration", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var doc models.OpenIDConfiguration
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+		assert.Equal(t, "https://auth.example.com", doc.Issuer)
+		assert.Equal(t, "https://auth.example.com/.well-known/jwks.json", doc.JWKSURI)
+		assert.Contains(t, doc.ClaimsSupported, "aud")
+		if introspection {
+			assert.Equal(t, "https://auth.example.com/auth/introspect", doc.IntrospectionEndpoint)
+		} else {
+			assert.Empty(t, doc.IntrospectionEndpoint)
+		}
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ring, err := keyring.New([]keyring.Key{{ID: "2026-10", PrivateKey: key}}, "2026-10")
+	assert.NoError(t, err)
+	cfg := &config.JWTConfig{Issuer: "https://auth.example.com", PublicURL: "https://auth.example.com"}
+
+	for name, keys := range map[string]*keyring.Ring{"signing keys": ring, "hmac": nil} {
+		dc := NewDiscoveryController(cfg, keys, false)
+		r := gin.New()
+		r.GET("/.well-known/jwks.json", dc.JWKS)
+
+		req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, name)
+		var set models.JSONWebKeySet
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &set), name)
+		if keys == nil {
+			assert.NotNil(t, set.Keys, name)
+			assert.Empty(t, set.Keys, name)
+			assert.Empty(t, dc.document.IDTokenSigningAlgValuesSupported, name)
+			continue
+		}
+		assert.Len(t, set.Keys, 1)
+		assert.Equal(t, "2026-10", set.Keys[0].Kid)
+		assert.Equal(t, "RSA", set.Keys[0].Kty)
+		assert.Equal(t, []string{"RS256"}, dc.document.IDTokenSigningAlgValuesSupported)
+	}
+}
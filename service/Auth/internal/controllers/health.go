@@ -5,6 +5,7 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/Zifeldev/marketback/service/Auth/internal/buildinfo"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
@@ -43,7 +44,7 @@ func (h *HealthController) Health(c *gin.Context) {
 	start := time.Now()
 	if err := h.pool.Ping(ctx); err != nil {
 		pgStatus = "error"
-		h.log.WithError(err).Error("postgres health check failed")
+		requestLog(c, h.log).WithError(err).Error("postgres health check failed")
 	} else {
 		pgLatency = time.Since(start)
 	}
@@ -53,7 +54,7 @@ func (h *HealthController) Health(c *gin.Context) {
 	if h.redis != nil {
 		if err := h.redis.Ping(ctx).Err(); err != nil {
 			redisStatus = "error"
-			h.log.WithError(err).Error("redis health check failed")
+			requestLog(c, h.log).WithError(err).Error("redis health check failed")
 		}
 	} else {
 		redisStatus = "disabled"
@@ -87,3 +88,14 @@ func (h *HealthController) Health(c *gin.Context) {
 		},
 	})
 }
+
+// Version godoc
+// @Summary Build information
+// @Description Version, git commit, build date and Go runtime of the running binary
+// @Tags health
+// @Produce json
+// @Success 200 {object} buildinfo.Info
+// @Router /version [get]
+func (h *HealthController) Version(c *gin.Context) {
+	c.JSON(http.StatusOK, buildinfo.Get())
+}
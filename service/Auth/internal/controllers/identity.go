@@ -0,0 +1,286 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/identity"
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/Zifeldev/marketback/service/Auth/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type IdentityController struct {
+	identityService service.IdentityService
+	securityService service.SecurityService
+	log             *logrus.Entry
+}
+
+// NewIdentityController creates the controller; a nil securityService
+// disables sign-in alerts
+func NewIdentityController(identityService service.IdentityService, securityService service.SecurityService, log *logrus.Entry) *IdentityController {
+	return &IdentityController{
+		identityService: identityService,
+		securityService: securityService,
+		log:             log,
+	}
+}
+
+// respondIdentityError maps identity errors to HTTP responses
+func (ic *IdentityController) respondIdentityError(c *gin.Context, err error, action string) {
+	switch {
+	case errors.Is(err, service.ErrProviderDisabled):
+		c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+	case errors.Is(err, identity.ErrInvalidPhone):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "field": "phone"})
+	case errors.Is(err, service.ErrInvalidCredentials), errors.Is(err, service.ErrInvalidCode):
+		requestLog(c, ic.log).WithError(err).Warn(action + " rejected")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPasswordResetRequired):
+		requestLog(c, ic.log).Warn(action + " blocked until password reset")
+		c.JSON(http.StatusForbidden, gin.H{"error": "password reset required", "code": "password_reset_required"})
+	case errors.Is(err, service.ErrPasswordChangeRequired):
+		requestLog(c, ic.log).Warn(action + " blocked until temporary password is changed")
+		c.JSON(http.StatusForbidden, gin.H{"error": "password change required", "code": "password_change_required"})
+	case errors.Is(err, service.ErrEmailNotVerified):
+		requestLog(c, ic.log).Warn(action + " blocked until email is verified")
+		c.JSON(http.StatusForbidden, gin.H{"error": "email not verified", "code": "email_not_verified"})
+	case errors.Is(err, service.ErrAccountSuspended):
+		requestLog(c, ic.log).Warn(action + " blocked for suspended account")
+		c.JSON(http.StatusForbidden, gin.H{"error": "account suspended", "code": "account_suspended"})
+	case errors.Is(err, service.ErrCodeTooSoon):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrIdentityExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrIdentityNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		requestLog(c, ic.log).WithError(err).Error("failed to " + action)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
+
+func (ic *IdentityController) respondTokens(c *gin.Context, tokens *models.TokenPair) {
+	notifyLogin(c, ic.securityService, tokens, ic.log)
+
+	c.SetCookie("access_token", tokens.AccessToken, 15*60, "/", "", false, true)
+	c.SetCookie("refresh_token", tokens.RefreshToken, 24*60*60, "/", "", false, true)
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token":  tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	})
+}
+
+// @Summary List linked sign-in methods
+// @Tags identities
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Identity
+// @Failure 401 {object} map[string]string
+// @Router /api/identities [get]
+func (ic *IdentityController) ListIdentities(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	identities, err := ic.identityService.List(c.Request.Context(), userID.(int64))
+	if err != nil {
+		ic.respondIdentityError(c, err, "list identities")
+		return
+	}
+
+	c.JSON(http.StatusOK, identities)
+}
+
+// @Summary Link a Google account
+// @Tags identities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.GoogleIdentityRequest true "Google ID token"
+// @Success 201 {object} models.Identity
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/identities/google [post]
+func (ic *IdentityController) LinkGoogle(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.GoogleIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	linked, err := ic.identityService.LinkGoogle(c.Request.Context(), userID.(int64), req.IDToken)
+	if err != nil {
+		ic.respondIdentityError(c, err, "link google account")
+		return
+	}
+
+	requestLog(c, ic.log).WithField("user_id", linked.UserID).Info("google account linked")
+	c.JSON(http.StatusCreated, linked)
+}
+
+// @Summary Send a code to a phone number to link it
+// @Tags identities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.PhoneCodeRequest true "Phone number with country code, or national number of PHONE_DEFAULT_REGION"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /api/identities/phone/code [post]
+func (ic *IdentityController) SendPhoneCode(c *gin.Context) {
+	var req models.PhoneCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := ic.identityService.SendPhoneCode(c.Request.Context(), req.Phone); err != nil {
+		ic.respondIdentityError(c, err, "send phone code")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "code sent"})
+}
+
+// @Summary Link a phone number
+// @Tags identities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.PhoneIdentityRequest true "Phone number and the code sent to it"
+// @Success 201 {object} models.Identity
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/identities/phone [post]
+func (ic *IdentityController) LinkPhone(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.PhoneIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	linked, err := ic.identityService.LinkPhone(c.Request.Context(), userID.(int64), req.Phone, req.Code)
+	if err != nil {
+		ic.respondIdentityError(c, err, "link phone")
+		return
+	}
+
+	requestLog(c, ic.log).WithField("user_id", linked.UserID).Info("phone linked")
+	c.JSON(http.StatusCreated, linked)
+}
+
+// @Summary Unlink a sign-in method
+// @Tags identities
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Identity ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/identities/{id} [delete]
+func (ic *IdentityController) Unlink(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid identity id"})
+		return
+	}
+
+	if err := ic.identityService.Unlink(c.Request.Context(), userID.(int64), id); err != nil {
+		ic.respondIdentityError(c, err, "unlink identity")
+		return
+	}
+
+	requestLog(c, ic.log).WithField("identity_id", id).Info("identity unlinked")
+	c.JSON(http.StatusOK, gin.H{"message": "identity unlinked"})
+}
+
+// @Summary Login with a linked Google account
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.GoogleIdentityRequest true "Google ID token"
+// @Success 200 {object} models.TokenPair
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string "Password reset or change required, email not verified or account suspended"
+// @Router /auth/login/google [post]
+func (ic *IdentityController) LoginGoogle(c *gin.Context) {
+	var req models.GoogleIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := ic.identityService.LoginGoogle(c.Request.Context(), req.IDToken)
+	if err != nil {
+		ic.respondIdentityError(c, err, "login with google")
+		return
+	}
+
+	requestLog(c, ic.log).Info("user logged in with google")
+	ic.respondTokens(c, tokens)
+}
+
+// @Summary Send a login code to a linked phone number
+// @Description Always accepted for valid numbers; the code is only sent when the number is linked to an account
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.PhoneCodeRequest true "Phone number with country code, or national number of PHONE_DEFAULT_REGION"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /auth/login/phone/code [post]
+func (ic *IdentityController) SendLoginCode(c *gin.Context) {
+	var req models.PhoneCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := ic.identityService.SendLoginCode(c.Request.Context(), req.Phone); err != nil {
+		ic.respondIdentityError(c, err, "send login code")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "code sent if the number is linked to an account"})
+}
+
+// @Summary Login with a linked phone number
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.PhoneIdentityRequest true "Phone number and the code sent to it"
+// @Success 200 {object} models.TokenPair
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string "Password reset or change required, email not verified or account suspended"
+// @Router /auth/login/phone [post]
+func (ic *IdentityController) LoginPhone(c *gin.Context) {
+	var req models.PhoneIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := ic.identityService.LoginPhone(c.Request.Context(), req.Phone, req.Code)
+	if err != nil {
+		ic.respondIdentityError(c, err, "login with phone")
+		return
+	}
+
+	requestLog(c, ic.log).Info("user logged in with phone")
+	ic.respondTokens(c, tokens)
+}
@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/identity"
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/Zifeldev/marketback/service/Auth/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIdentityService struct {
+	mock.Mock
+}
+
+func (m *MockIdentityService) List(ctx context.Context, userID int64) ([]*models.Identity, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Identity), args.Error(1)
+}
+
+func (m *MockIdentityService) LinkGoogle(ctx context.Context, userID int64, idToken string) (*models.Identity, error) {
+	args := m.Called(ctx, userID, idToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Identity), args.Error(1)
+}
+
+func (m *MockIdentityService) SendPhoneCode(ctx context.Context, phone string) error {
+	return m.Called(ctx, phone).Error(0)
+}
+
+func (m *MockIdentityService) LinkPhone(ctx context.Context, userID int64, phone, code string) (*models.Identity, error) {
+	args := m.Called(ctx, userID, phone, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Identity), args.Error(1)
+}
+
+func (m *MockIdentityService) Unlink(ctx context.Context, userID, id int64) error {
+	return m.Called(ctx, userID, id).Error(0)
+}
+
+func (m *MockIdentityService) LoginGoogle(ctx context.Context, idToken string) (*models.TokenPair, error) {
+	args := m.Called(ctx, idToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TokenPair), args.Error(1)
+}
+
+func (m *MockIdentityService) SendLoginCode(ctx context.Context, phone string) error {
+	return m.Called(ctx, phone).Error(0)
+}
+
+func (m *MockIdentityService) LoginPhone(ctx context.Context, phone, code string) (*models.TokenPair, error) {
+	args := m.Called(ctx, phone, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TokenPair), args.Error(1)
+}
+
+func setupIdentityTest() (*gin.Engine, *MockIdentityService, *IdentityController) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", int64(5))
+		c.Next()
+	})
+
+	mockService := new(MockIdentityService)
+	controller := NewIdentityController(mockService, nil, logrus.NewEntry(logrus.New()))
+
+	return r, mockService, controller
+}
+
+func postJSON(r *gin.Engine, path string, body interface{}) *httptest.ResponseRecorder {
+	return sendJSON(r, http.MethodPost, path, body)
+}
+
+func putJSON(r *gin.Engine, path string, body interface{}) *httptest.ResponseRecorder {
+	return sendJSON(r, http.MethodPut, path, body)
+}
+
+func sendJSON(r *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+	data, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(data))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestLoginGoogle_Success(t *testing.T) {
+	r, mockService, controller := setupIdentityTest()
+	r.POST("/auth/login/google", controller.LoginGoogle)
+
+	mockService.On("LoginGoogle", mock.Anything, "id-token").
+		Return(&models.TokenPair{AccessToken: "a", RefreshToken: "r", ExpiresIn: 900}, nil)
+
+	w := postJSON(r, "/auth/login/google", map[string]string{"id_token": "id-token"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, w.Result().Cookies(), 2)
+	mockService.AssertExpectations(t)
+}
+
+func TestLoginGoogle_NotLinked(t *testing.T) {
+	r, mockService, controller := setupIdentityTest()
+	r.POST("/auth/login/google", controller.LoginGoogle)
+
+	mockService.On("LoginGoogle", mock.Anything, "id-token").Return(nil, service.ErrInvalidCredentials)
+
+	w := postJSON(r, "/auth/login/google", map[string]string{"id_token": "id-token"})
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestLoginGoogle_PasswordResetRequired(t *testing.T) {
+	r, mockService, controller := setupIdentityTest()
+	r.POST("/auth/login/google", controller.LoginGoogle)
+
+	mockService.On("LoginGoogle", mock.Anything, "id-token").Return(nil, service.ErrPasswordResetRequired)
+
+	w := postJSON(r, "/auth/login/google", map[string]string{"id_token": "id-token"})
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "password_reset_required")
+}
+
+func TestLinkPhone_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"disabled", service.ErrProviderDisabled, http.StatusNotImplemented},
+		{"invalid phone", identity.ErrInvalidPhone, http.StatusBadRequest},
+		{"wrong code", service.ErrInvalidCode, http.StatusUnauthorized},
+		{"linked elsewhere", repository.ErrIdentityExists, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mockService, controller := setupIdentityTest()
+			r.POST("/api/identities/phone", controller.LinkPhone)
+
+			mockService.On("LinkPhone", mock.Anything, int64(5), "+4915112345678", "123456").Return(nil, tt.err)
+
+			w := postJSON(r, "/api/identities/phone", map[string]string{"phone": "+4915112345678", "code": "123456"})
+
+			assert.Equal(t, tt.code, w.Code)
+			if errors.Is(tt.err, identity.ErrInvalidPhone) {
+				assert.Contains(t, w.Body.String(), `"field":"phone"`)
+			}
+		})
+	}
+}
+
+func TestSendPhoneCode_TooSoon(t *testing.T) {
+	r, mockService, controller := setupIdentityTest()
+	r.POST("/api/identities/phone/code", controller.SendPhoneCode)
+
+	mockService.On("SendPhoneCode", mock.Anything, "+4915112345678").Return(service.ErrCodeTooSoon)
+
+	w := postJSON(r, "/api/identities/phone/code", map[string]string{"phone": "+4915112345678"})
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
+
+func TestUnlink_NotFound(t *testing.T) {
+	r, mockService, controller := setupIdentityTest()
+	r.DELETE("/api/identities/:id", controller.Unlink)
+
+	mockService.On("Unlink", mock.Anything, int64(5), int64(9)).Return(repository.ErrIdentityNotFound)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/identities/9", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
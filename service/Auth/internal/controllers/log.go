@@ -0,0 +1,16 @@
+package controllers
+
+import (
+	"github.com/Zifeldev/marketback/service/Auth/internal/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// requestLog returns the request-scoped logger set by middleware.RequestLogger,
+// falling back to the controller's base entry
+func requestLog(c *gin.Context, fallback *logrus.Entry) *logrus.Entry {
+	if c.Request == nil {
+		return fallback
+	}
+	return logger.FromContext(c.Request.Context(), fallback)
+}
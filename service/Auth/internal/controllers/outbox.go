@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/mailer"
+	"github.com/gin-gonic/gin"
+)
+
+// OutboxController exposes the emails captured by the mail sandbox, so
+// end-to-end tests can read password reset links and sign-in alerts
+type OutboxController struct {
+	outbox *mailer.Outbox
+}
+
+func NewOutboxController(outbox *mailer.Outbox) *OutboxController {
+	return &OutboxController{outbox: outbox}
+}
+
+// @Summary List captured emails
+// @Description List the emails captured by the mail sandbox instead of being sent, oldest first. Available only with MAIL_SANDBOX, outside production
+// @Tags dev
+// @Produce json
+// @Param to query string false "Only emails sent to this address"
+// @Success 200 {array} mailer.Message
+// @Router /api/dev/outbox [get]
+func (oc *OutboxController) GetOutbox(c *gin.Context) {
+	c.JSON(http.StatusOK, oc.outbox.Messages(c.Query("to")))
+}
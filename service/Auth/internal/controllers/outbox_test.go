@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/mailer"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOutbox(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	outbox, err := mailer.NewOutbox("security@example.com", "", 10)
+	require.NoError(t, err)
+	require.NoError(t, outbox.Send(context.Background(), "a@example.com", "Reset your password", "link"))
+	require.NoError(t, outbox.Send(context.Background(), "b@example.com", "New sign-in to your account", "alert"))
+
+	r := gin.New()
+	r.GET("/api/dev/outbox", NewOutboxController(outbox).GetOutbox)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/dev/outbox?to=a@example.com", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var messages []mailer.Message
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &messages))
+	require.Len(t, messages, 1)
+	assert.Equal(t, "Reset your password", messages[0].Subject)
+}
@@ -0,0 +1,244 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// RoleController lets users apply for a privileged role and admins review
+// the applications and the role audit trail
+type RoleController struct {
+	roleRepo repository.RoleRepository
+	log      *logrus.Entry
+}
+
+func NewRoleController(roleRepo repository.RoleRepository, log *logrus.Entry) *RoleController {
+	return &RoleController{
+		roleRepo: roleRepo,
+		log:      log,
+	}
+}
+
+// respondRoleError maps role request errors to HTTP responses
+func (rc *RoleController) respondRoleError(c *gin.Context, err error, action string) {
+	switch {
+	case errors.Is(err, repository.ErrRoleRequestNotFound), errors.Is(err, repository.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrRoleRequestPending), errors.Is(err, repository.ErrRoleRequestReviewed), errors.Is(err, repository.ErrRoleRequestNotAllowed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		requestLog(c, rc.log).WithError(err).Error("failed to " + action)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
+
+// pagination reads limit and offset the same way ListUsers does
+func pagination(c *gin.Context) (int, int) {
+	limit := 10
+	offset := 0
+
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	if o := c.Query("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	return limit, offset
+}
+
+// @Summary Apply for a privileged role
+// @Description Only accounts with the user role can apply, one pending request at a time
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateRoleRequestRequest true "Requested role (seller or courier) and a message for the reviewer"
+// @Success 201 {object} models.RoleRequest
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/role-requests [post]
+func (rc *RoleController) CreateRoleRequest(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.CreateRoleRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !models.IsRequestableRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role must be one of: seller, courier"})
+		return
+	}
+
+	created, err := rc.roleRepo.CreateRequest(c.Request.Context(), userID.(int64), req.Role, req.Message)
+	if err != nil {
+		rc.respondRoleError(c, err, "create role request")
+		return
+	}
+
+	requestLog(c, rc.log).WithFields(map[string]interface{}{
+		"request_id": created.ID,
+		"role":       created.Role,
+	}).Info("role requested")
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// @Summary List my role requests
+// @Tags roles
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.RoleRequest
+// @Failure 401 {object} map[string]string
+// @Router /api/role-requests [get]
+func (rc *RoleController) ListMyRoleRequests(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	requests, err := rc.roleRepo.ListRequestsByUser(c.Request.Context(), userID.(int64))
+	if err != nil {
+		rc.respondRoleError(c, err, "list role requests")
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+// @Summary List role requests (Admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "pending, approved or rejected (default pending, all for every status)"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {array} models.RoleRequest
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /admin/role-requests [get]
+func (rc *RoleController) ListRoleRequests(c *gin.Context) {
+	status := c.DefaultQuery("status", models.RoleRequestPending)
+	if status == "all" {
+		status = ""
+	} else if !models.IsRoleRequestStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+		return
+	}
+
+	limit, offset := pagination(c)
+	requests, err := rc.roleRepo.ListRequests(c.Request.Context(), status, limit, offset)
+	if err != nil {
+		rc.respondRoleError(c, err, "list role requests")
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+// @Summary Approve a role request (Admin only)
+// @Description Grants the requested role and records the change; the user gets the role in tokens issued after approval
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Role request ID"
+// @Param request body models.ReviewRoleRequestRequest false "Review note"
+// @Success 200 {object} models.RoleRequest
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/role-requests/{id}/approve [post]
+func (rc *RoleController) ApproveRoleRequest(c *gin.Context) {
+	rc.review(c, true)
+}
+
+// @Summary Reject a role request (Admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Role request ID"
+// @Param request body models.ReviewRoleRequestRequest false "Review note shown to the user"
+// @Success 200 {object} models.RoleRequest
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/role-requests/{id}/reject [post]
+func (rc *RoleController) RejectRoleRequest(c *gin.Context) {
+	rc.review(c, false)
+}
+
+func (rc *RoleController) review(c *gin.Context, approve bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid role request id"})
+		return
+	}
+
+	var req models.ReviewRoleRequestRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	reviewed, err := rc.roleRepo.ReviewRequest(c.Request.Context(), id, adminID(c), approve, req.Note)
+	if err != nil {
+		rc.respondRoleError(c, err, "review role request")
+		return
+	}
+
+	requestLog(c, rc.log).WithFields(map[string]interface{}{
+		"request_id": reviewed.ID,
+		"user_id":    reviewed.UserID,
+		"role":       reviewed.Role,
+		"status":     reviewed.Status,
+	}).Info("role request reviewed by admin")
+
+	c.JSON(http.StatusOK, reviewed)
+}
+
+// @Summary Role change audit trail (Admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query int false "Only changes of this user"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {array} models.RoleChange
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /admin/role-changes [get]
+func (rc *RoleController) ListRoleChanges(c *gin.Context) {
+	var userID int64
+	if u := c.Query("user_id"); u != "" {
+		parsed, err := strconv.ParseInt(u, 10, 64)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+			return
+		}
+		userID = parsed
+	}
+
+	limit, offset := pagination(c)
+	changes, err := rc.roleRepo.ListChanges(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		rc.respondRoleError(c, err, "list role changes")
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
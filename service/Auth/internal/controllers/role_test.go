@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) CreateRequest(ctx context.Context, userID int64, role, message string) (*models.RoleRequest, error) {
+	args := m.Called(ctx, userID, role, message)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RoleRequest), args.Error(1)
+}
+
+func (m *MockRoleRepository) ListRequestsByUser(ctx context.Context, userID int64) ([]*models.RoleRequest, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.RoleRequest), args.Error(1)
+}
+
+func (m *MockRoleRepository) ListRequests(ctx context.Context, status string, limit, offset int) ([]*models.RoleRequest, error) {
+	args := m.Called(ctx, status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.RoleRequest), args.Error(1)
+}
+
+func (m *MockRoleRepository) ReviewRequest(ctx context.Context, id, adminID int64, approve bool, note string) (*models.RoleRequest, error) {
+	args := m.Called(ctx, id, adminID, approve, note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RoleRequest), args.Error(1)
+}
+
+func (m *MockRoleRepository) SetRole(ctx context.Context, userID int64, role string, adminID int64, reason string) (*models.User, error) {
+	args := m.Called(ctx, userID, role, adminID, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockRoleRepository) ListChanges(ctx context.Context, userID int64, limit, offset int) ([]*models.RoleChange, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.RoleChange), args.Error(1)
+}
+
+func setupRoleTest() (*gin.Engine, *MockRoleRepository, *RoleController) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", int64(7))
+		c.Next()
+	})
+
+	mockRepo := new(MockRoleRepository)
+	controller := NewRoleController(mockRepo, logrus.NewEntry(logrus.New()))
+
+	return r, mockRepo, controller
+}
+
+func TestCreateRoleRequest_Success(t *testing.T) {
+	r, mockRepo, controller := setupRoleTest()
+	r.POST("/api/role-requests", controller.CreateRoleRequest)
+
+	mockRepo.On("CreateRequest", mock.Anything, int64(7), models.RoleSeller, "I sell handmade mugs").
+		Return(&models.RoleRequest{ID: 1, UserID: 7, Role: models.RoleSeller, Status: models.RoleRequestPending}, nil)
+
+	w := postJSON(r, "/api/role-requests", map[string]string{"role": "seller", "message": "I sell handmade mugs"})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateRoleRequest_AdminNotRequestable(t *testing.T) {
+	r, mockRepo, controller := setupRoleTest()
+	r.POST("/api/role-requests", controller.CreateRoleRequest)
+
+	w := postJSON(r, "/api/role-requests", map[string]string{"role": "admin"})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(t, "CreateRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateRoleRequest_AlreadyPending(t *testing.T) {
+	r, mockRepo, controller := setupRoleTest()
+	r.POST("/api/role-requests", controller.CreateRoleRequest)
+
+	mockRepo.On("CreateRequest", mock.Anything, int64(7), models.RoleCourier, "").
+		Return(nil, repository.ErrRoleRequestPending)
+
+	w := postJSON(r, "/api/role-requests", map[string]string{"role": "courier"})
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestApproveRoleRequest_Success(t *testing.T) {
+	r, mockRepo, controller := setupRoleTest()
+	r.POST("/admin/role-requests/:id/approve", controller.ApproveRoleRequest)
+
+	mockRepo.On("ReviewRequest", mock.Anything, int64(3), int64(7), true, "verified business").
+		Return(&models.RoleRequest{ID: 3, UserID: 9, Role: models.RoleSeller, Status: models.RoleRequestApproved}, nil)
+
+	w := postJSON(r, "/admin/role-requests/3/approve", map[string]string{"note": "verified business"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRejectRoleRequest_WithoutBody(t *testing.T) {
+	r, mockRepo, controller := setupRoleTest()
+	r.POST("/admin/role-requests/:id/reject", controller.RejectRoleRequest)
+
+	mockRepo.On("ReviewRequest", mock.Anything, int64(3), int64(7), false, "").
+		Return(nil, repository.ErrRoleRequestReviewed)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/role-requests/3/reject", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListRoleRequests_DefaultsToPending(t *testing.T) {
+	r, mockRepo, controller := setupRoleTest()
+	r.GET("/admin/role-requests", controller.ListRoleRequests)
+
+	mockRepo.On("ListRequests", mock.Anything, models.RoleRequestPending, 10, 0).
+		Return([]*models.RoleRequest{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/role-requests", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/role-requests?status=bogus", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateUser_PrivilegedRoleIsAudited(t *testing.T) {
+	r, mockRepo, mockRoleRepo, _, controller := setupAdminRoleTest()
+	r.POST("/admin/users", controller.CreateUser)
+
+	mockRepo.On("CreateWithRole", mock.Anything, "courier@example.com", mock.Anything, models.RoleUser).
+		Return(&models.User{ID: 5, Email: "courier@example.com", Role: models.RoleUser}, nil)
+	mockRoleRepo.On("SetRole", mock.Anything, int64(5), models.RoleCourier, int64(42), "account created by admin").
+		Return(&models.User{ID: 5, Email: "courier@example.com", Role: models.RoleCourier}, nil)
+
+	body, _ := json.Marshal(map[string]string{
+		"email":    "courier@example.com",
+		"password": "password123",
+		"role":     "courier",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/admin/users", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockRepo.AssertExpectations(t)
+	mockRoleRepo.AssertExpectations(t)
+}
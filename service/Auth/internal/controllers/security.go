@@ -0,0 +1,235 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/fingerprint"
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/Zifeldev/marketback/service/Auth/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// emailTimeout bounds sending an email after the response was written
+const emailTimeout = 30 * time.Second
+
+type SecurityController struct {
+	securityService service.SecurityService
+	log             *logrus.Entry
+}
+
+func NewSecurityController(securityService service.SecurityService, log *logrus.Entry) *SecurityController {
+	return &SecurityController{
+		securityService: securityService,
+		log:             log,
+	}
+}
+
+// notifyLogin hands a new session to the sign-in alerts in the background,
+// so a slow mail server never delays signing in. A nil service disables it.
+func notifyLogin(c *gin.Context, securityService service.SecurityService, tokens *models.TokenPair, log *logrus.Entry) {
+	if securityService == nil || tokens.SessionID == "" {
+		return
+	}
+	client, ok := fingerprint.ClientFromContext(c.Request.Context())
+	if !ok {
+		return
+	}
+
+	entry := requestLog(c, log).WithField("user_id", tokens.UserID)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), emailTimeout)
+		defer cancel()
+		if err := securityService.LoginSucceeded(ctx, tokens.UserID, tokens.SessionID, client); err != nil {
+			entry.WithError(err).Error("failed to process sign-in alert")
+		}
+	}()
+}
+
+// notifyRegistered welcomes a new user and asks them to verify their email
+// in the background. A nil service disables it.
+func notifyRegistered(c *gin.Context, securityService service.SecurityService, tokens *models.TokenPair, log *logrus.Entry) {
+	if securityService == nil {
+		return
+	}
+
+	entry := requestLog(c, log).WithField("user_id", tokens.UserID)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), emailTimeout)
+		defer cancel()
+		if err := securityService.Registered(ctx, tokens.UserID); err != nil {
+			entry.WithError(err).Error("failed to send registration email")
+		}
+		if err := securityService.SendEmailVerification(ctx, tokens.UserID); err != nil && !errors.Is(err, service.ErrEmailDisabled) {
+			entry.WithError(err).Error("failed to send verification email")
+		}
+	}()
+}
+
+// @Summary Report a sign-in as not made by the user
+// @Description Used by the "it wasn't me" link of a sign-in alert email. Signs the reported session out, blocks password sign-in until the password is reset and emails a reset link.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.LoginAlertDenyRequest true "Token from the alert link"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /auth/login-alerts/deny [post]
+func (sc *SecurityController) DenyLogin(c *gin.Context) {
+	var req models.LoginAlertDenyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := sc.securityService.DenyLogin(c.Request.Context(), req.Token); err != nil {
+		if errors.Is(err, repository.ErrSecurityTokenInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		// The session is revoked before the reset email is sent, so report
+		// what happened even when the email could not be sent
+		if errors.Is(err, service.ErrEmailDisabled) {
+			requestLog(c, sc.log).WithError(err).Warn("sign-in denied without reset email")
+			c.JSON(http.StatusOK, gin.H{"message": "session signed out; reset your password to sign in again"})
+			return
+		}
+		requestLog(c, sc.log).WithError(err).Error("failed to deny sign-in")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	requestLog(c, sc.log).Warn("sign-in reported as not made by the user")
+	c.JSON(http.StatusOK, gin.H{"message": "session signed out; check your email to reset your password"})
+}
+
+// @Summary Verify an email address
+// @Description Used by the link of a verification email sent at registration or by an admin
+// @Tags auth
+// @Produce json
+// @Param token query string true "Token from the verification link"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /auth/verify-email [get]
+func (sc *SecurityController) VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "token is required"})
+		return
+	}
+
+	if err := sc.securityService.VerifyEmail(c.Request.Context(), token); err != nil {
+		if errors.Is(err, repository.ErrSecurityTokenInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		requestLog(c, sc.log).WithError(err).Error("failed to verify email")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	requestLog(c, sc.log).Info("email verified")
+	c.JSON(http.StatusOK, gin.H{"message": "email verified"})
+}
+
+// @Summary Request a password reset email
+// @Description Always accepted; the email is only sent when the address belongs to an account
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.PasswordResetRequest true "Account email"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /auth/password-reset/request [post]
+func (sc *SecurityController) RequestPasswordReset(c *gin.Context) {
+	var req models.PasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Sent in the background so the response time does not reveal whether
+	// the account exists
+	entry := requestLog(c, sc.log)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), emailTimeout)
+		defer cancel()
+		if err := sc.securityService.RequestPasswordReset(ctx, req.Email); err != nil {
+			entry.WithError(err).Error("failed to send password reset email")
+		}
+	}()
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "reset link sent if the address belongs to an account"})
+}
+
+// @Summary Choose a new password
+// @Description Uses the token from a password reset email. All sessions of the user are signed out.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.PasswordResetConfirmRequest true "Reset token and new password"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /auth/password-reset [post]
+func (sc *SecurityController) ResetPassword(c *gin.Context) {
+	var req models.PasswordResetConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := sc.securityService.ResetPassword(c.Request.Context(), req.Token, req.Password); err != nil {
+		if errors.Is(err, repository.ErrSecurityTokenInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		requestLog(c, sc.log).WithError(err).Error("failed to reset password")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	requestLog(c, sc.log).Info("password reset")
+	c.JSON(http.StatusOK, gin.H{"message": "password changed; sign in with the new password"})
+}
+
+// @Summary Change the password
+// @Description Signs in with the current password to choose a new one, e.g. the temporary password of an imported account, which has to be changed before signing in. All sessions of the user are signed out.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.PasswordChangeRequest true "Credentials and new password"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /auth/password-change [post]
+func (sc *SecurityController) ChangePassword(c *gin.Context) {
+	var req models.PasswordChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := sc.securityService.ChangePassword(c.Request.Context(), req.Email, req.Password, req.NewPassword); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidCredentials):
+			requestLog(c, sc.log).WithField("email", req.Email).Warn("password change with invalid credentials")
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrPasswordResetRequired):
+			c.JSON(http.StatusForbidden, gin.H{"error": "password reset required", "code": "password_reset_required"})
+		case errors.Is(err, service.ErrPasswordUnchanged):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			requestLog(c, sc.log).WithError(err).Error("failed to change password")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		}
+		return
+	}
+
+	requestLog(c, sc.log).Info("password changed")
+	c.JSON(http.StatusOK, gin.H{"message": "password changed; sign in with the new password"})
+}
@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/fingerprint"
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/Zifeldev/marketback/service/Auth/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSecurityService struct {
+	mock.Mock
+}
+
+func (m *MockSecurityService) Registered(ctx context.Context, userID int64) error {
+	return m.Called(ctx, userID).Error(0)
+}
+
+func (m *MockSecurityService) SendEmailVerification(ctx context.Context, userID int64) error {
+	return m.Called(ctx, userID).Error(0)
+}
+
+func (m *MockSecurityService) VerifyEmail(ctx context.Context, token string) error {
+	return m.Called(ctx, token).Error(0)
+}
+
+func (m *MockSecurityService) LoginSucceeded(ctx context.Context, userID int64, sessionID string, client fingerprint.Client) error {
+	return m.Called(ctx, userID, sessionID, client).Error(0)
+}
+
+func (m *MockSecurityService) DenyLogin(ctx context.Context, token string) error {
+	return m.Called(ctx, token).Error(0)
+}
+
+func (m *MockSecurityService) RequestPasswordReset(ctx context.Context, email string) error {
+	return m.Called(ctx, email).Error(0)
+}
+
+func (m *MockSecurityService) ResetPassword(ctx context.Context, token, password string) error {
+	return m.Called(ctx, token, password).Error(0)
+}
+
+func (m *MockSecurityService) ChangePassword(ctx context.Context, email, password, newPassword string) error {
+	return m.Called(ctx, email, password, newPassword).Error(0)
+}
+
+func (m *MockSecurityService) Invite(ctx context.Context, userID int64, temporaryPassword bool) error {
+	return m.Called(ctx, userID, temporaryPassword).Error(0)
+}
+
+func (m *MockSecurityService) ForcePasswordReset(ctx context.Context, userID int64) error {
+	return m.Called(ctx, userID).Error(0)
+}
+
+func (m *MockSecurityService) RevokeSessions(ctx context.Context, userID int64) error {
+	return m.Called(ctx, userID).Error(0)
+}
+
+func (m *MockSecurityService) Suspend(ctx context.Context, userID int64) error {
+	return m.Called(ctx, userID).Error(0)
+}
+
+func (m *MockSecurityService) Unsuspend(ctx context.Context, userID int64) error {
+	return m.Called(ctx, userID).Error(0)
+}
+
+func (m *MockSecurityService) Sessions(ctx context.Context, userID int64, currentSessionID string) ([]*models.Session, error) {
+	args := m.Called(ctx, userID, currentSessionID)
+	sessions, _ := args.Get(0).([]*models.Session)
+	return sessions, args.Error(1)
+}
+
+func (m *MockSecurityService) RevokeSession(ctx context.Context, userID int64, sessionID string) error {
+	return m.Called(ctx, userID, sessionID).Error(0)
+}
+
+func setupSecurityTest() (*gin.Engine, *MockSecurityService, *SecurityController) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	mockService := new(MockSecurityService)
+	controller := NewSecurityController(mockService, logrus.NewEntry(logrus.New()))
+
+	return r, mockService, controller
+}
+
+func TestDenyLogin(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"revoked", nil, http.StatusOK},
+		{"no email", service.ErrEmailDisabled, http.StatusOK},
+		{"used link", repository.ErrSecurityTokenInvalid, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mockService, controller := setupSecurityTest()
+			r.POST("/auth/login-alerts/deny", controller.DenyLogin)
+
+			mockService.On("DenyLogin", mock.Anything, "link-token").Return(tt.err)
+
+			w := postJSON(r, "/auth/login-alerts/deny", map[string]string{"token": "link-token"})
+
+			assert.Equal(t, tt.code, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResetPassword_Validation(t *testing.T) {
+	r, mockService, controller := setupSecurityTest()
+	r.POST("/auth/password-reset", controller.ResetPassword)
+
+	w := postJSON(r, "/auth/password-reset", map[string]string{"token": "reset-token", "password": "short"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockService.On("ResetPassword", mock.Anything, "reset-token", "long-enough").Return(nil)
+	w = postJSON(r, "/auth/password-reset", map[string]string{"token": "reset-token", "password": "long-enough"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestLogin_PasswordResetRequired(t *testing.T) {
+	r, mockService, controller := setupTest()
+	r.POST("/auth/login", controller.Login)
+
+	mockService.On("Login", mock.Anything, "test@example.com", "password123").Return(nil, service.ErrPasswordResetRequired)
+
+	w := postJSON(r, "/auth/login", map[string]string{"email": "test@example.com", "password": "password123"})
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "password_reset_required")
+}
+
+func TestLogin_PasswordChangeRequired(t *testing.T) {
+	r, mockService, controller := setupTest()
+	r.POST("/auth/login", controller.Login)
+
+	mockService.On("Login", mock.Anything, "test@example.com", "temporary1").Return(nil, service.ErrPasswordChangeRequired)
+
+	w := postJSON(r, "/auth/login", map[string]string{"email": "test@example.com", "password": "temporary1"})
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "password_change_required")
+}
+
+func TestChangePassword(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"changed", nil, http.StatusOK},
+		{"wrong password", service.ErrInvalidCredentials, http.StatusUnauthorized},
+		{"locked down", service.ErrPasswordResetRequired, http.StatusForbidden},
+		{"same password", service.ErrPasswordUnchanged, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mockService, controller := setupSecurityTest()
+			r.POST("/auth/password-change", controller.ChangePassword)
+
+			mockService.On("ChangePassword", mock.Anything, "test@example.com", "temporary1", "long-enough").Return(tt.err)
+
+			w := postJSON(r, "/auth/password-change", map[string]string{"email": "test@example.com", "password": "temporary1", "new_password": "long-enough"})
+
+			assert.Equal(t, tt.code, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+
+	r, _, controller := setupSecurityTest()
+	r.POST("/auth/password-change", controller.ChangePassword)
+	w := postJSON(r, "/auth/password-change", map[string]string{"email": "test@example.com", "password": "temporary1", "new_password": "short"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestVerifyEmail(t *testing.T) {
+	r, mockService, controller := setupSecurityTest()
+	r.GET("/auth/verify-email", controller.VerifyEmail)
+
+	mockService.On("VerifyEmail", mock.Anything, "verify-token").Return(nil)
+	mockService.On("VerifyEmail", mock.Anything, "used-token").Return(repository.ErrSecurityTokenInvalid)
+
+	for path, code := range map[string]int{
+		"/auth/verify-email?token=verify-token": http.StatusOK,
+		"/auth/verify-email?token=used-token":   http.StatusBadRequest,
+		"/auth/verify-email":                    http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, code, w.Code, path)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestLogin_EmailNotVerified(t *testing.T) {
+	r, mockService, controller := setupTest()
+	r.POST("/auth/login", controller.Login)
+
+	mockService.On("Login", mock.Anything, "test@example.com", "password123").Return(nil, service.ErrEmailNotVerified)
+
+	w := postJSON(r, "/auth/login", map[string]string{"email": "test@example.com", "password": "password123"})
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "email_not_verified")
+}
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/Zifeldev/marketback/service/Auth/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// SessionController lets users see where they are signed in and sign
+// devices out
+type SessionController struct {
+	authService     service.AuthService
+	securityService service.SecurityService
+	log             *logrus.Entry
+}
+
+func NewSessionController(authService service.AuthService, securityService service.SecurityService, log *logrus.Entry) *SessionController {
+	return &SessionController{
+		authService:     authService,
+		securityService: securityService,
+		log:             log,
+	}
+}
+
+// @Summary List my sessions
+// @Description Sign-ins that can still be refreshed, most recently used first, with the User-Agent and IP address of the client. The session of the presented access token is marked current.
+// @Tags sessions
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Session
+// @Failure 401 {object} map[string]string
+// @Router /api/me/sessions [get]
+func (sc *SessionController) ListSessions(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	sessions, err := sc.securityService.Sessions(c.Request.Context(), userID.(int64), c.GetString("session_id"))
+	if err != nil {
+		requestLog(c, sc.log).WithError(err).Error("failed to list sessions")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// @Summary Sign a session out
+// @Description Revokes the refresh tokens of one session so the device cannot refresh again; its access tokens expire shortly after.
+// @Tags sessions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/me/sessions/{id} [delete]
+func (sc *SessionController) RevokeSession(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	sessionID := c.Param("id")
+
+	if err := sc.securityService.RevokeSession(c.Request.Context(), userID.(int64), sessionID); err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		requestLog(c, sc.log).WithError(err).Error("failed to revoke session")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	requestLog(c, sc.log).WithField("session_id", sessionID).Info("session revoked by user")
+	c.JSON(http.StatusOK, gin.H{"message": "session signed out"})
+}
+
+// @Summary Log out everywhere
+// @Description Revokes every session of the user, including the current one, and the presented access token. Other access tokens are reported inactive by introspection and expire shortly after.
+// @Tags sessions
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/me/sessions [delete]
+func (sc *SessionController) RevokeAllSessions(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	if err := sc.securityService.RevokeSessions(c.Request.Context(), userID.(int64)); err != nil {
+		requestLog(c, sc.log).WithError(err).Error("failed to revoke sessions")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	if accessToken := presentedAccessToken(c); accessToken != "" {
+		if err := sc.authService.RevokeAccessToken(c.Request.Context(), accessToken, "logout_all"); err != nil {
+			requestLog(c, sc.log).WithError(err).Warn("failed to revoke access token")
+		}
+	}
+
+	c.SetCookie("access_token", "", -1, "/", "", false, true)
+	c.SetCookie("refresh_token", "", -1, "/", "", false, true)
+
+	requestLog(c, sc.log).Info("user logged out everywhere")
+	c.JSON(http.StatusOK, gin.H{"message": "signed out of all sessions"})
+}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupSessionTest() (*gin.Engine, *MockAuthService, *MockSecurityService) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", int64(5))
+		c.Set("session_id", "laptop")
+		c.Next()
+	})
+
+	authService, securityService := new(MockAuthService), new(MockSecurityService)
+	controller := NewSessionController(authService, securityService, logrus.NewEntry(logrus.New()))
+	r.GET("/api/me/sessions", controller.ListSessions)
+	r.DELETE("/api/me/sessions", controller.RevokeAllSessions)
+	r.DELETE("/api/me/sessions/:id", controller.RevokeSession)
+
+	return r, authService, securityService
+}
+
+func TestListSessions(t *testing.T) {
+	r, _, securityService := setupSessionTest()
+	securityService.On("Sessions", mock.Anything, int64(5), "laptop").Return([]*models.Session{
+		{ID: "laptop", UserAgent: "Firefox", IPAddress: "203.0.113.7", Current: true},
+		{ID: "phone", UserAgent: "Safari", IPAddress: "198.51.100.2"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/me/sessions", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var sessions []models.Session
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &sessions))
+	assert.Len(t, sessions, 2)
+	assert.True(t, sessions[0].Current)
+	assert.Equal(t, "198.51.100.2", sessions[1].IPAddress)
+}
+
+func TestRevokeSession(t *testing.T) {
+	r, _, securityService := setupSessionTest()
+	securityService.On("RevokeSession", mock.Anything, int64(5), "phone").Return(nil)
+	securityService.On("RevokeSession", mock.Anything, int64(5), "someone-elses").Return(repository.ErrSessionNotFound)
+
+	for path, code := range map[string]int{
+		"/api/me/sessions/phone":         http.StatusOK,
+		"/api/me/sessions/someone-elses": http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, path, nil))
+		assert.Equal(t, code, w.Code, path)
+	}
+	securityService.AssertExpectations(t)
+}
+
+func TestRevokeAllSessions(t *testing.T) {
+	r, authService, securityService := setupSessionTest()
+	securityService.On("RevokeSessions", mock.Anything, int64(5)).Return(nil)
+	authService.On("RevokeAccessToken", mock.Anything, "current-access", "logout_all").Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/me/sessions", nil)
+	req.Header.Set("Authorization", "Bearer current-access")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, w.Result().Cookies(), 2)
+	securityService.AssertExpectations(t)
+	authService.AssertExpectations(t)
+}
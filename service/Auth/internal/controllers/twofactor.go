@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/Zifeldev/marketback/service/Auth/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type TwoFactorController struct {
+	twoFactorService service.TwoFactorService
+	log              *logrus.Entry
+}
+
+func NewTwoFactorController(twoFactorService service.TwoFactorService, log *logrus.Entry) *TwoFactorController {
+	return &TwoFactorController{
+		twoFactorService: twoFactorService,
+		log:              log,
+	}
+}
+
+// respondTwoFactorError maps two-factor errors to HTTP responses
+func respondTwoFactorError(c *gin.Context, log *logrus.Entry, err error, action string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidOTP):
+		requestLog(c, log).Warn(action + ": invalid two-factor code")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrTwoFactorRequired):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrTwoFactorNotSetUp), errors.Is(err, repository.ErrTwoFactorEnabled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+	default:
+		requestLog(c, log).WithError(err).Error("failed to " + action)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
+
+// @Summary Start two-factor setup
+// @Description Returns a new TOTP secret and otpauth:// URI for an authenticator app; confirm it with /api/2fa/enable
+// @Tags 2fa
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TwoFactorSetupResponse
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/2fa/setup [post]
+func (tc *TwoFactorController) Setup(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	email, _ := c.Get("user_email")
+	account, _ := email.(string)
+
+	secret, uri, err := tc.twoFactorService.Setup(c.Request.Context(), userID.(int64), account)
+	if err != nil {
+		respondTwoFactorError(c, tc.log, err, "set up two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.TwoFactorSetupResponse{Secret: secret, URI: uri})
+}
+
+// @Summary Enable two-factor authentication
+// @Tags 2fa
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.TwoFactorCodeRequest true "Current code from the authenticator app"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/2fa/enable [post]
+func (tc *TwoFactorController) Enable(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := tc.twoFactorService.Enable(c.Request.Context(), userID.(int64), req.Code); err != nil {
+		respondTwoFactorError(c, tc.log, err, "enable two-factor authentication")
+		return
+	}
+
+	requestLog(c, tc.log).WithField("user_id", userID).Info("two-factor authentication enabled")
+	c.JSON(http.StatusOK, gin.H{"message": "two-factor authentication enabled"})
+}
+
+// @Summary Disable two-factor authentication
+// @Tags 2fa
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.TwoFactorCodeRequest true "Current code from the authenticator app"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/2fa/disable [post]
+func (tc *TwoFactorController) Disable(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := tc.twoFactorService.Disable(c.Request.Context(), userID.(int64), req.Code); err != nil {
+		respondTwoFactorError(c, tc.log, err, "disable two-factor authentication")
+		return
+	}
+
+	requestLog(c, tc.log).WithField("user_id", userID).Info("two-factor authentication disabled")
+	c.JSON(http.StatusOK, gin.H{"message": "two-factor authentication disabled"})
+}
@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTwoFactorService struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorService) Setup(ctx context.Context, userID int64, email string) (string, string, error) {
+	args := m.Called(ctx, userID, email)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockTwoFactorService) Enable(ctx context.Context, userID int64, code string) error {
+	return m.Called(ctx, userID, code).Error(0)
+}
+
+func (m *MockTwoFactorService) Disable(ctx context.Context, userID int64, code string) error {
+	return m.Called(ctx, userID, code).Error(0)
+}
+
+func (m *MockTwoFactorService) Verify(ctx context.Context, userID int64, code string) error {
+	return m.Called(ctx, userID, code).Error(0)
+}
+
+func setupTwoFactorTest() (*gin.Engine, *MockTwoFactorService, *TwoFactorController) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", int64(3))
+		c.Set("user_email", "admin@example.com")
+		c.Next()
+	})
+
+	mockService := new(MockTwoFactorService)
+	controller := NewTwoFactorController(mockService, logrus.NewEntry(logrus.New()))
+
+	return r, mockService, controller
+}
+
+func TestTwoFactorSetup(t *testing.T) {
+	r, mockService, controller := setupTwoFactorTest()
+	r.POST("/api/2fa/setup", controller.Setup)
+
+	mockService.On("Setup", mock.Anything, int64(3), "admin@example.com").
+		Return("JBSWY3DPEHPK3PXP", "otpauth://totp/Marketback:admin@example.com?secret=JBSWY3DPEHPK3PXP", nil)
+
+	w := postJSON(r, "/api/2fa/setup", nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "JBSWY3DPEHPK3PXP")
+	mockService.AssertExpectations(t)
+}
+
+func TestTwoFactorEnable_InvalidCode(t *testing.T) {
+	r, mockService, controller := setupTwoFactorTest()
+	r.POST("/api/2fa/enable", controller.Enable)
+
+	mockService.On("Enable", mock.Anything, int64(3), "000000").Return(service.ErrInvalidOTP)
+
+	w := postJSON(r, "/api/2fa/enable", map[string]string{"code": "000000"})
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = postJSON(r, "/api/2fa/enable", map[string]string{"code": "12ab"})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGrantAdmin_RequiresTwoFactor(t *testing.T) {
+	r, _, mockRoleRepo, mockTwoFactor, controller := setupAdminRoleTest()
+	r.PUT("/admin/users/:id/role", controller.UpdateUserRole)
+
+	mockTwoFactor.On("Verify", mock.Anything, int64(42), "").Return(service.ErrTwoFactorRequired)
+
+	w := putJSON(r, "/admin/users/1/role", map[string]string{"role": "admin"})
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockRoleRepo.AssertNotCalled(t, "SetRole", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGrantAdmin_WrongCode(t *testing.T) {
+	r, mockRepo, _, mockTwoFactor, controller := setupAdminRoleTest()
+	r.POST("/admin/users", controller.CreateUser)
+
+	mockTwoFactor.On("Verify", mock.Anything, int64(42), "999999").Return(service.ErrInvalidOTP)
+
+	w := postJSON(r, "/admin/users", map[string]string{
+		"email":    "ops@example.com",
+		"password": "password123",
+		"role":     models.RoleAdmin,
+		"otp":      "999999",
+	})
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockRepo.AssertNotCalled(t, "CreateWithRole", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
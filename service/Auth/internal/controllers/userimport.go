@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// maxImportSize bounds the body of an import request
+const maxImportSize = 1 << 20
+
+type UserImportController struct {
+	importService   service.UserImportService
+	securityService service.SecurityService
+	log             *logrus.Entry
+}
+
+func NewUserImportController(importService service.UserImportService, securityService service.SecurityService, log *logrus.Entry) *UserImportController {
+	return &UserImportController{
+		importService:   importService,
+		securityService: securityService,
+		log:             log,
+	}
+}
+
+// @Summary Import users from CSV (Admin only)
+// @Description Creates a user per row of a CSV file whose header names the columns email, role (user, seller or courier; default user) and password, at most 200 rows. A password is temporary: signing in answers 403 with code password_change_required until it is changed with /auth/password-change. Rows without a password need invitation emails, which link to choosing a password; with a password the invitation tells the user to sign in. Each row is reported with its status (created, valid or failed) and error; a failed row does not stop the others. With dry_run nothing is created or sent.
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV file"
+// @Param dry_run query bool false "Only validate the rows"
+// @Success 200 {object} models.UserImportResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Router /admin/users/import [post]
+func (uc *UserImportController) ImportUsers(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	dryRun := c.Query("dry_run") == "true"
+	result, err := uc.importService.Import(c.Request.Context(), adminID(c), file, dryRun)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidImport) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		requestLog(c, uc.log).WithError(err).Error("failed to import users")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	requestLog(c, uc.log).WithFields(logrus.Fields{
+		"admin_id": adminID(c),
+		"dry_run":  dryRun,
+		"created":  result.Created,
+		"failed":   result.Failed,
+	}).Info("users imported")
+	if result.Invited {
+		uc.invite(c, result.Rows)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// invite emails the created users their invitations in the background
+func (uc *UserImportController) invite(c *gin.Context, rows []models.UserImportRow) {
+	entry := requestLog(c, uc.log)
+	go func() {
+		for _, row := range rows {
+			if row.Status != models.ImportRowCreated {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), emailTimeout)
+			if err := uc.securityService.Invite(ctx, row.UserID, row.TemporaryPassword); err != nil {
+				entry.WithError(err).WithField("user_id", row.UserID).Error("failed to send invitation email")
+			}
+			cancel()
+		}
+	}()
+}
@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserImportService struct {
+	mock.Mock
+}
+
+func (m *MockUserImportService) Import(ctx context.Context, adminID int64, r io.Reader, dryRun bool) (*models.UserImportResult, error) {
+	data, _ := io.ReadAll(r)
+	args := m.Called(ctx, adminID, string(data), dryRun)
+	result, _ := args.Get(0).(*models.UserImportResult)
+	return result, args.Error(1)
+}
+
+func setupUserImportTest() (*gin.Engine, *MockUserImportService, *MockSecurityService) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", int64(42))
+		c.Next()
+	})
+
+	mockImport := new(MockUserImportService)
+	mockSecurity := new(MockSecurityService)
+	controller := NewUserImportController(mockImport, mockSecurity, logrus.NewEntry(logrus.New()))
+	r.POST("/admin/users/import", controller.ImportUsers)
+
+	return r, mockImport, mockSecurity
+}
+
+func postFile(r *gin.Engine, path, content string) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "users.csv")
+	_, _ = part.Write([]byte(content))
+	_ = form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, path, &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestImportUsers(t *testing.T) {
+	r, mockImport, mockSecurity := setupUserImportTest()
+
+	const file = "email,password\nann@example.com,\nbob@example.com,temporary1\nann@example.com,\n"
+	mockImport.On("Import", mock.Anything, int64(42), file, false).Return(&models.UserImportResult{
+		Valid: 2, Created: 2, Failed: 1, Invited: true,
+		Rows: []models.UserImportRow{
+			{Line: 2, Email: "ann@example.com", Status: models.ImportRowCreated, UserID: 7},
+			{Line: 3, Email: "bob@example.com", Status: models.ImportRowCreated, UserID: 8, TemporaryPassword: true},
+			{Line: 4, Email: "ann@example.com", Status: models.ImportRowFailed, Error: "duplicate of line 2"},
+		},
+	}, nil)
+	invited := make(chan int64, 2)
+	mockSecurity.On("Invite", mock.Anything, int64(7), false).Return(nil).Run(func(args mock.Arguments) { invited <- 7 })
+	mockSecurity.On("Invite", mock.Anything, int64(8), true).Return(nil).Run(func(args mock.Arguments) { invited <- 8 })
+
+	w := postFile(r, "/admin/users/import", file)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "duplicate of line 2")
+	for _, want := range []int64{7, 8} {
+		select {
+		case id := <-invited:
+			assert.Equal(t, want, id)
+		case <-time.After(time.Second):
+			t.Fatal("invitation not sent")
+		}
+	}
+	mockImport.AssertExpectations(t)
+}
+
+func TestImportUsers_DryRun(t *testing.T) {
+	r, mockImport, mockSecurity := setupUserImportTest()
+
+	mockImport.On("Import", mock.Anything, int64(42), "email\nann@example.com\n", true).Return(&models.UserImportResult{
+		DryRun: true, Valid: 1,
+		Rows: []models.UserImportRow{{Line: 2, Email: "ann@example.com", Status: models.ImportRowValid}},
+	}, nil)
+
+	w := postFile(r, "/admin/users/import?dry_run=true", "email\nann@example.com\n")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockImport.AssertExpectations(t)
+	mockSecurity.AssertNotCalled(t, "Invite", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestImportUsers_Invalid(t *testing.T) {
+	r, mockImport, _ := setupUserImportTest()
+
+	mockImport.On("Import", mock.Anything, int64(42), "name\nAnn\n", false).
+		Return(nil, fmt.Errorf("%w: unknown column %q", service.ErrInvalidImport, "name"))
+	w := postFile(r, "/admin/users/import", "name\nAnn\n")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown column")
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/users/import", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = postFile(r, "/admin/users/import", strings.Repeat("a", maxImportSize+1))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
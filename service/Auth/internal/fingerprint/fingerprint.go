@@ -0,0 +1,65 @@
+// Package fingerprint identifies the client a refresh token was issued to,
+// so refresh attempts from another client can be detected.
+package fingerprint
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Binding modes for refresh tokens
+const (
+	ModeOff     = "off"
+	ModeReport  = "report"
+	ModeEnforce = "enforce"
+)
+
+// IsMode reports whether mode is a supported binding mode
+func IsMode(mode string) bool {
+	return mode == ModeOff || mode == ModeReport || mode == ModeEnforce
+}
+
+// Compute hashes the client's User-Agent and, when given, IP address. Only
+// the hash is stored.
+func Compute(userAgent, ip string) string {
+	sum := sha256.Sum256([]byte(userAgent + "\n" + ip))
+	return hex.EncodeToString(sum[:])
+}
+
+type ctxKey struct{}
+
+// WithContext stores the fingerprint of the requesting client in ctx
+func WithContext(ctx context.Context, fp string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, fp)
+}
+
+// FromContext returns the client fingerprint, or "" when ctx has none
+func FromContext(ctx context.Context) string {
+	fp, _ := ctx.Value(ctxKey{}).(string)
+	return fp
+}
+
+// Client describes where a request came from, for sign-in alerts
+type Client struct {
+	// Device is the hash of the User-Agent alone, so it stays stable when
+	// the client's IP address changes
+	Device    string
+	Country   string
+	IP        string
+	UserAgent string
+}
+
+type clientKey struct{}
+
+// WithClient stores the requesting client in ctx
+func WithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFromContext returns the requesting client; ok is false when ctx
+// has none
+func ClientFromContext(ctx context.Context) (Client, bool) {
+	client, ok := ctx.Value(clientKey{}).(Client)
+	return client, ok
+}
@@ -0,0 +1,137 @@
+// Package grpcserver serves the Auth service's gRPC API (clients/authpb) to
+// the other Marketback services: token validation and user lookups, for
+// callers authenticated with the same service credentials as
+// /auth/introspect.
+package grpcserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/Zifeldev/marketback/clients/authpb"
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/Zifeldev/marketback/service/Auth/internal/service"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MaxUsersPerLookup bounds the IDs of one ListUsersByIDs call.
+const MaxUsersPerLookup = 500
+
+type Server struct {
+	authpb.UnimplementedAuthServer
+	auth  service.AuthService
+	users repository.UserRepository
+	log   *logrus.Entry
+}
+
+// New returns a grpc.Server with the Auth API registered, accepting callers
+// with one of clients' credentials (client ID to secret).
+func New(auth service.AuthService, users repository.UserRepository, clients map[string]string, log *logrus.Entry) *grpc.Server {
+	srv := grpc.NewServer(grpc.UnaryInterceptor(ServiceAuth(clients)))
+	authpb.RegisterAuthServer(srv, &Server{auth: auth, users: users, log: log})
+	return srv
+}
+
+func (s *Server) ValidateToken(ctx context.Context, req *authpb.ValidateTokenRequest) (*authpb.ValidateTokenResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	resp, err := s.auth.Introspect(ctx, req.GetToken(), models.TokenTypeAccess)
+	if err != nil {
+		s.log.WithError(err).Error("failed to validate token")
+		return nil, status.Error(codes.Internal, "failed to validate token")
+	}
+	// Introspection falls back to refresh tokens, which are not valid here
+	if !resp.Active || resp.TokenType != models.TokenTypeAccess {
+		return &authpb.ValidateTokenResponse{}, nil
+	}
+
+	return &authpb.ValidateTokenResponse{
+		Active:    resp.Active,
+		UserId:    resp.UserID,
+		Email:     resp.Email,
+		Role:      resp.Role,
+		Scope:     resp.Scope,
+		ExpiresAt: resp.ExpiresAt,
+		Jti:       resp.JTI,
+	}, nil
+}
+
+func (s *Server) GetUser(ctx context.Context, req *authpb.GetUserRequest) (*authpb.User, error) {
+	user, err := s.users.GetByID(ctx, req.GetId())
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	if err != nil {
+		s.log.WithError(err).Error("failed to get user")
+		return nil, status.Error(codes.Internal, "failed to get user")
+	}
+
+	return toUser(user), nil
+}
+
+func (s *Server) ListUsersByIDs(ctx context.Context, req *authpb.ListUsersByIDsRequest) (*authpb.ListUsersByIDsResponse, error) {
+	if len(req.GetIds()) > MaxUsersPerLookup {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d ids are allowed", MaxUsersPerLookup)
+	}
+	if len(req.GetIds()) == 0 {
+		return &authpb.ListUsersByIDsResponse{}, nil
+	}
+
+	users, err := s.users.ListByIDs(ctx, req.GetIds())
+	if err != nil {
+		s.log.WithError(err).Error("failed to list users")
+		return nil, status.Error(codes.Internal, "failed to list users")
+	}
+
+	resp := &authpb.ListUsersByIDsResponse{Users: make([]*authpb.User, 0, len(users))}
+	for _, user := range users {
+		resp.Users = append(resp.Users, toUser(user))
+	}
+	return resp, nil
+}
+
+func toUser(user *models.User) *authpb.User {
+	return &authpb.User{Id: user.ID, Email: user.Email, Role: user.Role}
+}
+
+// ServiceAuth authenticates callers with HTTP Basic credentials (client ID
+// and secret) in the authorization metadata.
+func ServiceAuth(clients map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		var header string
+		if values := md.Get("authorization"); len(values) > 0 {
+			header = values[0]
+		}
+
+		id, secret, ok := parseBasicAuth(header)
+		expected, known := clients[id]
+		if !ok || !known || subtle.ConstantTimeCompare([]byte(secret), []byte(expected)) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "invalid client credentials")
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func parseBasicAuth(header string) (id, secret string, ok bool) {
+	const prefix = "Basic "
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(header[len(prefix):])
+	if err != nil {
+		return "", "", false
+	}
+	return strings.Cut(string(decoded), ":")
+}
@@ -0,0 +1,117 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/Zifeldev/marketback/clients/authclient"
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/Zifeldev/marketback/service/Auth/internal/service"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type fakeAuth struct {
+	service.AuthService
+}
+
+func (fakeAuth) Introspect(ctx context.Context, token, tokenTypeHint string) (*models.IntrospectionResponse, error) {
+	switch token {
+	case "access":
+		return &models.IntrospectionResponse{Active: true, TokenType: models.TokenTypeAccess, UserID: 7, Email: "a@example.com", Role: "seller", Scope: "account market"}, nil
+	case "refresh":
+		return &models.IntrospectionResponse{Active: true, TokenType: models.TokenTypeRefresh, UserID: 7}, nil
+	}
+	return &models.IntrospectionResponse{}, nil
+}
+
+type fakeUsers struct {
+	repository.UserRepository
+	users map[int64]*models.User
+}
+
+func (f *fakeUsers) GetByID(ctx context.Context, id int64) (*models.User, error) {
+	if user, ok := f.users[id]; ok {
+		return user, nil
+	}
+	return nil, repository.ErrUserNotFound
+}
+
+func (f *fakeUsers) ListByIDs(ctx context.Context, ids []int64) ([]*models.User, error) {
+	var users []*models.User
+	for _, id := range ids {
+		if user, ok := f.users[id]; ok {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func newClient(t *testing.T, secret string) *authclient.GRPCClient {
+	t.Helper()
+	users := &fakeUsers{users: map[int64]*models.User{
+		7: {ID: 7, Email: "a@example.com", Role: "seller", PasswordHash: "hash"},
+		9: {ID: 9, Email: "b@example.com", Role: "user"},
+	}}
+	srv := New(fakeAuth{}, users, map[string]string{"market": "s3cret"}, logrus.NewEntry(logrus.New()))
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	c, err := authclient.NewGRPC("passthrough:///auth", "market", secret,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}))
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, "s3cret")
+
+	resp, err := c.ValidateToken(ctx, "access")
+	require.NoError(t, err)
+	require.True(t, resp.GetActive())
+	require.Equal(t, int64(7), resp.GetUserId())
+	require.Equal(t, "account market", resp.GetScope())
+
+	resp, err = c.ValidateToken(ctx, "refresh")
+	require.NoError(t, err)
+	require.False(t, resp.GetActive(), "refresh tokens are not valid access tokens")
+
+	user, err := c.GetUser(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, "a@example.com", user.GetEmail())
+	_, err = c.GetUser(ctx, 8)
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	users, err := c.ListUsersByIDs(ctx, []int64{7, 8, 9})
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	require.Equal(t, "b@example.com", users[9].GetEmail())
+}
+
+func TestServer_ServiceAuth(t *testing.T) {
+	_, err := newClient(t, "guess").GetUser(context.Background(), 7)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestParseBasicAuth(t *testing.T) {
+	id, secret, ok := parseBasicAuth("basic bWFya2V0OnMzY3JldA==")
+	require.True(t, ok)
+	require.Equal(t, "market", id)
+	require.Equal(t, "s3cret", secret)
+
+	for _, header := range []string{"", "Bearer token", "Basic !!!", "Basic bWFya2V0"} {
+		_, _, ok := parseBasicAuth(header)
+		require.False(t, ok, header)
+	}
+}
@@ -0,0 +1,102 @@
+// Package httpserver builds the service's http.Server with timeouts, HTTP/2
+// and optional TLS termination from certificate files or ACME (autocert).
+package httpserver
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// Options configures the server. Zero timeouts disable the respective limit.
+type Options struct {
+	Addr              string
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// H2C enables HTTP/2 over cleartext, for running behind a proxy that
+	// speaks HTTP/2 to the backend.
+	H2C bool
+
+	CertFile string
+	KeyFile  string
+
+	// AutocertDomains enables ACME certificates for the listed hosts. The
+	// TLS-ALPN-01 challenge is answered on the TLS listener itself.
+	AutocertDomains  []string
+	AutocertCacheDir string
+	AutocertEmail    string
+}
+
+type Server struct {
+	*http.Server
+	certFile string
+	keyFile  string
+}
+
+func New(opts Options, handler http.Handler) (*Server, error) {
+	if (opts.CertFile == "") != (opts.KeyFile == "") {
+		return nil, errors.New("both a TLS certificate and key file are required")
+	}
+	if opts.CertFile != "" && len(opts.AutocertDomains) > 0 {
+		return nil, errors.New("TLS certificate files and autocert are mutually exclusive")
+	}
+
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(opts.H2C)
+
+	srv := &http.Server{
+		Addr:              opts.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: opts.ReadHeaderTimeout,
+		ReadTimeout:       opts.ReadTimeout,
+		WriteTimeout:      opts.WriteTimeout,
+		IdleTimeout:       opts.IdleTimeout,
+		Protocols:         protocols,
+	}
+
+	switch {
+	case len(opts.AutocertDomains) > 0:
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(opts.AutocertDomains...),
+			Cache:      autocert.DirCache(opts.AutocertCacheDir),
+			Email:      opts.AutocertEmail,
+		}
+		srv.TLSConfig = manager.TLSConfig()
+		srv.TLSConfig.MinVersion = tls.VersionTLS12
+	case opts.CertFile != "":
+		srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	return &Server{Server: srv, certFile: opts.CertFile, keyFile: opts.KeyFile}, nil
+}
+
+// TLS reports whether the server terminates TLS itself.
+func (s *Server) TLS() bool {
+	return s.TLSConfig != nil
+}
+
+// ListenAndServe listens on the configured address and serves HTTPS when TLS
+// is configured, plain HTTP otherwise.
+func (s *Server) ListenAndServe() error {
+	ln, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ln)
+}
+
+func (s *Server) Serve(ln net.Listener) error {
+	if s.TLS() {
+		return s.Server.ServeTLS(ln, s.certFile, s.keyFile)
+	}
+	return s.Server.Serve(ln)
+}
@@ -0,0 +1,94 @@
+package httpserver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeSelfSignedCert(t *testing.T) (certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func TestNew_RejectsIncompleteTLS(t *testing.T) {
+	_, err := New(Options{CertFile: "cert.pem"}, http.NotFoundHandler())
+	require.Error(t, err)
+
+	_, err = New(Options{CertFile: "cert.pem", KeyFile: "key.pem", AutocertDomains: []string{"example.com"}}, http.NotFoundHandler())
+	require.Error(t, err)
+}
+
+func TestNew_PlainHTTPSetsTimeouts(t *testing.T) {
+	srv, err := New(Options{Addr: ":0", ReadHeaderTimeout: 5 * time.Second, IdleTimeout: time.Minute}, http.NotFoundHandler())
+	require.NoError(t, err)
+
+	require.False(t, srv.TLS())
+	require.Equal(t, 5*time.Second, srv.ReadHeaderTimeout)
+	require.Equal(t, time.Minute, srv.IdleTimeout)
+}
+
+func TestNew_Autocert(t *testing.T) {
+	srv, err := New(Options{AutocertDomains: []string{"shop.example.com"}, AutocertCacheDir: t.TempDir()}, http.NotFoundHandler())
+	require.NoError(t, err)
+
+	require.True(t, srv.TLS())
+	require.NotNil(t, srv.TLSConfig.GetCertificate)
+	require.Contains(t, srv.TLSConfig.NextProtos, "h2")
+}
+
+func TestServe_TLSNegotiatesHTTP2(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t)
+	srv, err := New(Options{CertFile: certFile, KeyFile: keyFile}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	require.NoError(t, err)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+		ForceAttemptHTTP2: true,
+	}}
+	resp, err := client.Get("https://" + ln.Addr().String())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	require.Equal(t, 2, resp.ProtoMajor)
+}
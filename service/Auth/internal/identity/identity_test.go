@@ -0,0 +1,132 @@
+package identity
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func newCertsServer(t *testing.T, kid string, key *rsa.PublicKey) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": kid,
+				"kty": "RSA",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func signIDToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestGoogleVerifier_Verify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	srv := newCertsServer(t, "k1", &key.PublicKey)
+	v := NewGoogleVerifier("client-123", srv.URL)
+
+	valid := jwt.MapClaims{
+		"iss": "https://accounts.google.com",
+		"aud": "client-123",
+		"sub": "10769150350006150715113082367",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	sub, err := v.Verify(context.Background(), signIDToken(t, key, "k1", valid))
+	require.NoError(t, err)
+	require.Equal(t, "10769150350006150715113082367", sub)
+
+	tests := map[string]jwt.MapClaims{
+		"other client":  {"iss": "accounts.google.com", "aud": "client-999", "sub": "1", "exp": time.Now().Add(time.Hour).Unix()},
+		"other issuer":  {"iss": "https://evil.example.com", "aud": "client-123", "sub": "1", "exp": time.Now().Add(time.Hour).Unix()},
+		"expired":       {"iss": "accounts.google.com", "aud": "client-123", "sub": "1", "exp": time.Now().Add(-time.Hour).Unix()},
+		"no expiry":     {"iss": "accounts.google.com", "aud": "client-123", "sub": "1"},
+		"empty subject": {"iss": "accounts.google.com", "aud": "client-123", "exp": time.Now().Add(time.Hour).Unix()},
+	}
+	for name, claims := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := v.Verify(context.Background(), signIDToken(t, key, "k1", claims))
+			require.ErrorIs(t, err, ErrInvalidIDToken)
+		})
+	}
+}
+
+func TestGoogleVerifier_RejectsForeignKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	srv := newCertsServer(t, "k1", &key.PublicKey)
+	v := NewGoogleVerifier("client-123", srv.URL)
+
+	claims := jwt.MapClaims{"iss": "accounts.google.com", "aud": "client-123", "sub": "1", "exp": time.Now().Add(time.Hour).Unix()}
+	_, err = v.Verify(context.Background(), signIDToken(t, other, "k1", claims))
+	require.ErrorIs(t, err, ErrInvalidIDToken)
+
+	_, err = v.Verify(context.Background(), signIDToken(t, key, "k2", claims))
+	require.ErrorIs(t, err, ErrInvalidIDToken)
+}
+
+func TestNormalizePhone(t *testing.T) {
+	phone, err := NormalizePhone(" +49 (151) 123-45678 ", "")
+	require.NoError(t, err)
+	require.Equal(t, "+4915112345678", phone)
+
+	// National numbers need the default region
+	phone, err = NormalizePhone("0151 12345678", "DE")
+	require.NoError(t, err)
+	require.Equal(t, "+4915112345678", phone)
+
+	for _, bad := range []string{"015112345678", "+0151123456", "+49abc", "+12", "+49 151"} {
+		_, err := NormalizePhone(bad, "")
+		require.ErrorIs(t, err, ErrInvalidPhone, bad)
+	}
+}
+
+func TestNewCodeAndHash(t *testing.T) {
+	code, err := NewCode()
+	require.NoError(t, err)
+	require.Len(t, code, 6)
+
+	require.Equal(t, HashCode("+4915112345678", code), HashCode("+4915112345678", code))
+	require.NotEqual(t, HashCode("+4915112345678", code), HashCode("+4915112345679", code))
+}
+
+func TestWebhookSender_Send(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	require.NoError(t, NewWebhookSender(srv.URL).Send(context.Background(), "+4915112345678", "hello"))
+	require.Equal(t, map[string]string{"to": "+4915112345678", "message": "hello"}, got)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	require.Error(t, NewWebhookSender(failing.URL).Send(context.Background(), "+4915112345678", "hello"))
+}
@@ -0,0 +1,84 @@
+package identity
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/Zifeldev/marketback/pkg/phone"
+)
+
+// ErrInvalidPhone is wrapped by the errors of malformed numbers
+var ErrInvalidPhone = phone.ErrInvalid
+
+// NormalizePhone returns the E.164 form of a number. Numbers without a
+// country code are read as national numbers of region, when it is set.
+func NormalizePhone(number, region string) (string, error) {
+	return phone.Normalize(number, region)
+}
+
+// NewCode returns a random six-digit verification code
+func NewCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// HashCode hashes a code for storage. The phone is mixed in so equal codes
+// for different numbers do not share a hash.
+func HashCode(phone, code string) string {
+	sum := sha256.Sum256([]byte(phone + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
+
+// SMSSender delivers text messages
+type SMSSender interface {
+	Send(ctx context.Context, to, message string) error
+}
+
+// WebhookSender posts messages as JSON ({"to": ..., "message": ...}) to an
+// SMS gateway webhook
+type WebhookSender struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSender(url string) *WebhookSender {
+	return &WebhookSender{
+		url:    url,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (s *WebhookSender) Send(ctx context.Context, to, message string) error {
+	body, err := json.Marshal(map[string]string{"to": to, "message": message})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send sms: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to send sms: status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+// Package keyring holds the RSA keys access tokens are signed with. One key
+// signs new tokens; the others are kept so tokens they signed before a
+// rotation still validate, and all of them are published in the JWKS.
+package keyring
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/config"
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minKeyBits is the smallest RSA key accepted for signing
+const minKeyBits = 2048
+
+type Key struct {
+	ID         string
+	PrivateKey *rsa.PrivateKey
+}
+
+type Ring struct {
+	active string
+	keys   []Key
+}
+
+// New builds a ring of keys signing with the one whose ID is active
+func New(keys []Key, active string) (*Ring, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signing keys")
+	}
+	found := false
+	for _, k := range keys {
+		if k.PrivateKey.N.BitLen() < minKeyBits {
+			return nil, fmt.Errorf("key %q: must be at least %d bits", k.ID, minKeyBits)
+		}
+		found = found || k.ID == active
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown signing key %q", active)
+	}
+	return &Ring{active: active, keys: keys}, nil
+}
+
+// Load reads the PEM private keys configured in cfg. It returns nil when
+// none are, in which case tokens are signed with the shared HS256 secret.
+func Load(cfg *config.JWTConfig) (*Ring, error) {
+	if len(cfg.SigningKeys) == 0 {
+		return nil, nil
+	}
+	keys := make([]Key, 0, len(cfg.SigningKeys))
+	for _, k := range cfg.SigningKeys {
+		data, err := os.ReadFile(k.File)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k.ID, err)
+		}
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k.ID, err)
+		}
+		keys = append(keys, Key{ID: k.ID, PrivateKey: privateKey})
+	}
+	return New(keys, cfg.SigningKeyID)
+}
+
+// Sign signs claims with the active key and names it in the kid header
+func (r *Ring) Sign(claims jwt.Claims) (string, error) {
+	key := r.Active()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.PrivateKey)
+}
+
+func (r *Ring) Active() Key {
+	for _, k := range r.keys {
+		if k.ID == r.active {
+			return k
+		}
+	}
+	return Key{}
+}
+
+// PublicKey returns the key that verifies tokens with the kid header id
+func (r *Ring) PublicKey(id string) (*rsa.PublicKey, bool) {
+	for _, k := range r.keys {
+		if k.ID == id {
+			return &k.PrivateKey.PublicKey, true
+		}
+	}
+	return nil, false
+}
+
+// JWKS lists the public halves of every key, the active one first
+func (r *Ring) JWKS() models.JSONWebKeySet {
+	set := models.JSONWebKeySet{Keys: []models.JSONWebKey{}}
+	if r == nil {
+		return set
+	}
+	active := r.Active()
+	set.Keys = append(set.Keys, jsonWebKey(active))
+	for _, k := range r.keys {
+		if k.ID != active.ID {
+			set.Keys = append(set.Keys, jsonWebKey(k))
+		}
+	}
+	return set
+}
+
+func jsonWebKey(k Key) models.JSONWebKey {
+	pub := k.PrivateKey.PublicKey
+	return models.JSONWebKey{
+		Kty: "RSA",
+		Use: "sig",
+		Alg: jwt.SigningMethodRS256.Alg(),
+		Kid: k.ID,
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}
+}
@@ -0,0 +1,83 @@
+package keyring
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func writeKey(t *testing.T, dir, name string, bits int) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	require.NoError(t, err)
+	block := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), pem.EncodeToMemory(block), 0600))
+	return key
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeKey(t, dir, "old.pem", 2048)
+	current := writeKey(t, dir, "new.pem", 2048)
+
+	ring, err := Load(&config.JWTConfig{
+		SigningKeys: []config.SigningKey{
+			{ID: "2026-04", File: filepath.Join(dir, "old.pem")},
+			{ID: "2026-10", File: filepath.Join(dir, "new.pem")},
+		},
+		SigningKeyID: "2026-10",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "2026-10", ring.Active().ID)
+
+	signed, err := ring.Sign(jwt.MapClaims{"sub": "1"})
+	require.NoError(t, err)
+	token, err := jwt.Parse(signed, func(token *jwt.Token) (interface{}, error) {
+		require.Equal(t, "2026-10", token.Header["kid"])
+		return &current.PublicKey, nil
+	}, jwt.WithValidMethods([]string{"RS256"}))
+	require.NoError(t, err)
+	require.True(t, token.Valid)
+
+	jwks := ring.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	require.Equal(t, "2026-10", jwks.Keys[0].Kid)
+	require.Equal(t, "2026-04", jwks.Keys[1].Kid)
+	require.Equal(t, "RS256", jwks.Keys[0].Alg)
+	require.Equal(t, "AQAB", jwks.Keys[0].E)
+
+	_, ok := ring.PublicKey("2026-04")
+	require.True(t, ok)
+	_, ok = ring.PublicKey("2025-10")
+	require.False(t, ok)
+}
+
+func TestLoad_NoKeys(t *testing.T) {
+	ring, err := Load(&config.JWTConfig{AccessSecret: "secret"})
+	require.NoError(t, err)
+	require.Nil(t, ring)
+	require.Empty(t, ring.JWKS().Keys)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeKey(t, dir, "weak.pem", 1024)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "junk.pem"), []byte("not a key"), 0600))
+
+	for name, keys := range map[string][]config.SigningKey{
+		"missing file": {{ID: "a", File: filepath.Join(dir, "missing.pem")}},
+		"not pem":      {{ID: "a", File: filepath.Join(dir, "junk.pem")}},
+		"weak key":     {{ID: "a", File: filepath.Join(dir, "weak.pem")}},
+	} {
+		_, err := Load(&config.JWTConfig{SigningKeys: keys, SigningKeyID: "a"})
+		require.Error(t, err, name)
+	}
+}
@@ -0,0 +1,25 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ctxKey struct{}
+
+// WithContext stores a request-scoped entry in ctx
+func WithContext(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, ctxKey{}, entry)
+}
+
+// FromContext returns the request-scoped entry, or fallback when the
+// request has none
+func FromContext(ctx context.Context, fallback *logrus.Entry) *logrus.Entry {
+	if ctx != nil {
+		if entry, ok := ctx.Value(ctxKey{}).(*logrus.Entry); ok {
+			return entry
+		}
+	}
+	return fallback
+}
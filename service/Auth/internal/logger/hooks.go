@@ -0,0 +1,21 @@
+package logger
+
+import "github.com/sirupsen/logrus"
+
+// staticFieldsHook adds fixed fields (service, version) to every entry
+type staticFieldsHook struct {
+	fields logrus.Fields
+}
+
+func (h staticFieldsHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h staticFieldsHook) Fire(entry *logrus.Entry) error {
+	for k, v := range h.fields {
+		if _, exists := entry.Data[k]; !exists {
+			entry.Data[k] = v
+		}
+	}
+	return nil
+}
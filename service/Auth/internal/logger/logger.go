@@ -4,34 +4,52 @@ import (
 	"os"
 	"time"
 
+	"github.com/Zifeldev/marketback/pkg/logsampling"
 	"github.com/sirupsen/logrus"
 )
 
 type Logger struct{ *logrus.Logger }
 type Fields map[string]interface{}
 
-func New() *Logger {
+// Options configures the service logger
+type Options struct {
+	Level  string
+	Format string // json (default) or text
+
+	// Debug and trace entries are sampled per message: the first
+	// SampleFirst entries in every SampleTick are logged, then every
+	// SampleThereafter-th. Zero SampleFirst disables sampling.
+	SampleFirst      int
+	SampleThereafter int
+	SampleTick       time.Duration
+
+	// Fields are added to every entry (e.g. service name and version)
+	Fields map[string]interface{}
+}
+
+func New(opts Options) *Logger {
 	log := logrus.New()
 
-	env := os.Getenv("ENV")
-	if env == "production" {
-		log.SetFormatter(&logrus.JSONFormatter{
-			TimestampFormat: time.RFC3339Nano,
-			FieldMap: logrus.FieldMap{
-				logrus.FieldKeyTime:  "@timestamp",
-				logrus.FieldKeyLevel: "severity",
-				logrus.FieldKeyMsg:   "message",
-				logrus.FieldKeyFunc:  "function",
-			},
-		})
-	} else {
-		log.SetFormatter(&logrus.TextFormatter{
+	var formatter logrus.Formatter
+	if opts.Format == "text" {
+		formatter = &logrus.TextFormatter{
 			TimestampFormat: time.RFC3339,
 			FullTimestamp:   true,
-		})
+		}
+	} else {
+		formatter = &logrus.JSONFormatter{
+			TimestampFormat: time.RFC3339Nano,
+		}
+	}
+	if opts.SampleFirst > 0 {
+		formatter = logsampling.NewFormatter(formatter, opts.SampleFirst, opts.SampleThereafter, opts.SampleTick)
+	}
+	log.SetFormatter(formatter)
+	if len(opts.Fields) > 0 {
+		log.AddHook(staticFieldsHook{fields: opts.Fields})
 	}
 
-	level := os.Getenv("LOG_LEVEL")
+	level := opts.Level
 	if level == "" {
 		level = "info"
 	}
@@ -0,0 +1,126 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_DisabledWithoutHost(t *testing.T) {
+	m, err := New("", 587, "", "", "")
+	require.NoError(t, err)
+	require.Nil(t, m)
+}
+
+func TestNew_RequiresSender(t *testing.T) {
+	_, err := New("smtp.example.com", 587, "", "", "")
+	require.Error(t, err)
+}
+
+func TestSMTP_Send(t *testing.T) {
+	m, err := New("smtp.example.com", 587, "", "", "security@example.com")
+	require.NoError(t, err)
+
+	var gotAddr string
+	var gotTo []string
+	var gotMsg []byte
+	m.(*SMTP).send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotMsg = addr, to, msg
+		return nil
+	}
+
+	require.NoError(t, m.Send(context.Background(), "buyer@example.com", "New sign-in to your account", "line one\nline two"))
+	require.Equal(t, "smtp.example.com:587", gotAddr)
+	require.Equal(t, []string{"buyer@example.com"}, gotTo)
+	require.Contains(t, string(gotMsg), "Subject: New sign-in to your account\r\n")
+	require.True(t, strings.HasSuffix(string(gotMsg), "\r\n\r\nline one\r\nline two"))
+}
+
+func TestBuildMessage_RejectsHeaderInjection(t *testing.T) {
+	_, err := buildMessage("a@example.com", "b@example.com", "hi\r\nBcc: x@example.com", "body", time.Now())
+	require.Error(t, err)
+}
+
+func TestOutbox(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "maildir")
+	outbox, err := NewOutbox("security@example.com", dir, 2)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, outbox.Send(ctx, "a@example.com", "first", "1"))
+	require.NoError(t, outbox.Send(ctx, "b@example.com", "second", "2"))
+	require.NoError(t, outbox.Send(ctx, "A@example.com", "third", "3"))
+
+	all := outbox.Messages("")
+	require.Len(t, all, 2)
+	require.Equal(t, "second", all[0].Subject)
+
+	mine := outbox.Messages("a@example.com")
+	require.Len(t, mine, 1)
+	require.Equal(t, "third", mine[0].Subject)
+
+	delivered, err := os.ReadDir(filepath.Join(dir, "new"))
+	require.NoError(t, err)
+	require.Len(t, delivered, 3)
+}
+
+func TestSendGrid_Send(t *testing.T) {
+	var got sendGridMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer SG.key", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	_, err := NewSendGrid("SG.key", "")
+	require.Error(t, err)
+	m, err := NewSendGrid("SG.key", "security@example.com")
+	require.NoError(t, err)
+	m.(*SendGrid).endpoint = srv.URL
+
+	require.NoError(t, m.Send(context.Background(), "user@example.com", "Reset your password", "link"))
+	require.Equal(t, "user@example.com", got.Personalizations[0].To[0].Email)
+	require.Equal(t, "security@example.com", got.From.Email)
+	require.Equal(t, "text/plain", got.Content[0].Type)
+
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+	require.ErrorContains(t, m.Send(context.Background(), "user@example.com", "Reset your password", "link"), "401")
+}
+
+func TestNotifier(t *testing.T) {
+	outbox, err := NewOutbox("security@example.com", "", 10)
+	require.NoError(t, err)
+
+	_, err = NewNotifier(outbox, "", []string{"welcome"})
+	require.ErrorContains(t, err, "login_alert, password_reset, registration")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "registration.tmpl"),
+		[]byte("Subject: Hi {{.Email}}\n\nWelcome aboard.\n"), 0o644))
+	n, err := NewNotifier(outbox, dir, []string{EventLoginAlert})
+	require.NoError(t, err)
+	require.False(t, n.Enabled(EventLoginAlert))
+	require.ErrorIs(t, n.Notify(context.Background(), EventLoginAlert, "user@example.com", nil), ErrDisabled)
+
+	require.NoError(t, n.Notify(context.Background(), EventRegistration, "user@example.com", map[string]string{"Email": "user@example.com"}))
+	msg := outbox.Messages("user@example.com")[0]
+	require.Equal(t, "Hi user@example.com", msg.Subject)
+	require.Equal(t, "Welcome aboard.\n", msg.Body)
+
+	subject, body, err := n.Render(EventPasswordReset, map[string]string{"Link": "https://shop.example.com/r", "ExpiresIn": "1 hour"})
+	require.NoError(t, err)
+	require.Equal(t, "Reset your password", subject)
+	require.Contains(t, body, "https://shop.example.com/r\n\nThe link expires in 1 hour.")
+}
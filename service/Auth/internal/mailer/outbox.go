@@ -0,0 +1,98 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is an email captured by an Outbox.
+type Message struct {
+	To      string    `json:"to"`
+	Subject string    `json:"subject"`
+	Body    string    `json:"body"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+// Outbox captures mail instead of sending it, for development and
+// end-to-end tests. It keeps the last limit messages in memory and, when
+// dir is set, also delivers every message to a maildir there, so a mail
+// client can browse them.
+type Outbox struct {
+	from  string
+	dir   string
+	limit int
+
+	mu       sync.Mutex
+	seq      int
+	messages []Message
+}
+
+func NewOutbox(from, dir string, limit int) (*Outbox, error) {
+	if dir != "" {
+		for _, sub := range []string{"tmp", "new", "cur"} {
+			if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+				return nil, fmt.Errorf("failed to create maildir: %w", err)
+			}
+		}
+	}
+	return &Outbox{from: from, dir: dir, limit: limit}, nil
+}
+
+func (o *Outbox) Send(ctx context.Context, to, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	now := time.Now()
+	msg, err := buildMessage(o.from, to, subject, body, now)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.seq++
+	if o.dir != "" {
+		if err := o.deliver(msg, now); err != nil {
+			return err
+		}
+	}
+	o.messages = append(o.messages, Message{To: to, Subject: subject, Body: body, SentAt: now})
+	if len(o.messages) > o.limit {
+		o.messages = o.messages[len(o.messages)-o.limit:]
+	}
+	return nil
+}
+
+// deliver writes msg to tmp and then moves it to new, so maildir readers
+// never see a partial message.
+func (o *Outbox) deliver(msg []byte, now time.Time) error {
+	name := fmt.Sprintf("%d.%d_%d.outbox", now.Unix(), os.Getpid(), o.seq)
+	tmp := filepath.Join(o.dir, "tmp", name)
+	if err := os.WriteFile(tmp, msg, 0o644); err != nil {
+		return fmt.Errorf("failed to write maildir message: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(o.dir, "new", name)); err != nil {
+		return fmt.Errorf("failed to deliver maildir message: %w", err)
+	}
+	return nil
+}
+
+// Messages returns the captured messages, oldest first, optionally only
+// those sent to the address to.
+func (o *Outbox) Messages(to string) []Message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	messages := []Message{}
+	for _, m := range o.messages {
+		if to == "" || strings.EqualFold(m.To, to) {
+			messages = append(messages, m)
+		}
+	}
+	return messages
+}
@@ -0,0 +1,94 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	sendGridEndpoint = "https://api.sendgrid.com/v3/mail/send"
+	sendGridTimeout  = 10 * time.Second
+)
+
+// SendGrid sends mail through the SendGrid v3 API.
+type SendGrid struct {
+	apiKey   string
+	from     string
+	endpoint string
+	client   *http.Client
+}
+
+// NewSendGrid returns a SendGrid mailer.
+func NewSendGrid(apiKey, from string) (Mailer, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("a SendGrid API key is required")
+	}
+	if from == "" {
+		return nil, fmt.Errorf("a sender address is required")
+	}
+	return &SendGrid{
+		apiKey:   apiKey,
+		from:     from,
+		endpoint: sendGridEndpoint,
+		client:   &http.Client{Timeout: sendGridTimeout},
+	}, nil
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+}
+
+type sendGridPersonalization struct {
+	To []sendGridAddress `json:"to"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendGridMessage struct {
+	Personalizations []sendGridPersonalization `json:"personalizations"`
+	From             sendGridAddress           `json:"from"`
+	Subject          string                    `json:"subject"`
+	Content          []sendGridContent         `json:"content"`
+}
+
+func (m *SendGrid) Send(ctx context.Context, to, subject, body string) error {
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return fmt.Errorf("email header contains a line break")
+	}
+
+	payload, err := json.Marshal(sendGridMessage{
+		Personalizations: []sendGridPersonalization{{To: []sendGridAddress{{Email: to}}}},
+		From:             sendGridAddress{Email: m.from},
+		Subject:          subject,
+		Content:          []sendGridContent{{Type: "text/plain", Value: body}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode email: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("failed to send email: SendGrid returned %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// Retention metrics
+	RetentionRowsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_retention_rows_total",
+			Help: "Total number of rows purged by retention policies",
+		},
+		[]string{"policy", "mode"},
+	)
+
+	RetentionRunFailuresTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "auth_retention_run_failures_total",
+			Help: "Total number of failed retention runs",
+		},
+	)
+
+	// RefreshBindingMismatchTotal counts refresh attempts by another client
+	// than the token was issued to
+	RefreshBindingMismatchTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_refresh_binding_mismatch_total",
+			Help: "Total number of refresh attempts whose client fingerprint did not match the token",
+		},
+		[]string{"mode"},
+	)
+
+	// RefreshTokenReuseTotal counts rotated refresh tokens presented again,
+	// each of which revoked its token family
+	RefreshTokenReuseTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "auth_refresh_token_reuse_total",
+			Help: "Total number of refresh attempts with a refresh token that was already rotated",
+		},
+	)
+
+	// HTTP metrics
+	HTTPRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "auth_http_request_duration_seconds",
+			Help:    "HTTP request latency by route template",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"method", "route", "status_class"},
+	)
+
+	HTTPRequestErrorsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_http_request_errors_total",
+			Help: "Total number of HTTP responses with status >= 400 by route template",
+		},
+		[]string{"method", "route", "status"},
+	)
+)
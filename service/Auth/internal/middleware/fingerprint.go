@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/fingerprint"
+	"github.com/gin-gonic/gin"
+)
+
+// ClientFingerprint stores a hash of the User-Agent (and client IP when
+// includeIP is set) in the request context for refresh token binding. The
+// client's device and country (read from countryHeader, when set) are
+// stored as well for sign-in alerts.
+func ClientFingerprint(includeIP bool, countryHeader string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ua := c.Request.UserAgent()
+		ip := ""
+		if includeIP {
+			ip = c.ClientIP()
+		}
+
+		client := fingerprint.Client{
+			Device:    fingerprint.Compute(ua, ""),
+			IP:        c.ClientIP(),
+			UserAgent: ua,
+		}
+		if countryHeader != "" {
+			client.Country = normalizeCountry(c.GetHeader(countryHeader))
+		}
+
+		ctx := fingerprint.WithContext(c.Request.Context(), fingerprint.Compute(ua, ip))
+		c.Request = c.Request.WithContext(fingerprint.WithClient(ctx, client))
+		c.Next()
+	}
+}
+
+// normalizeCountry keeps two-letter country codes; proxies send "XX" or
+// "T1" (Tor) for unknown locations, which are treated as unknown too
+func normalizeCountry(value string) string {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) != 2 || value == "XX" || value == "T1" {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/fingerprint"
+	"github.com/gin-gonic/gin"
+)
+
+func TestClientFingerprint_Country(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		header  string
+		country string
+	}{
+		{"de", "DE"},
+		{"XX", ""},
+		{"T1", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			var client fingerprint.Client
+			r := gin.New()
+			r.GET("/", ClientFingerprint(false, "CF-IPCountry"), func(c *gin.Context) {
+				client, _ = fingerprint.ClientFromContext(c.Request.Context())
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("User-Agent", "test-agent")
+			req.Header.Set("CF-IPCountry", tt.header)
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			if client.Country != tt.country {
+				t.Fatalf("expected country %q, got %q", tt.country, client.Country)
+			}
+			if client.Device != fingerprint.Compute("test-agent", "") {
+				t.Fatalf("device should only depend on the User-Agent")
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
 	"github.com/Zifeldev/marketback/service/Auth/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -17,6 +18,8 @@ const (
 	ContextUserID       = "user_id"
 	ContextUserEmail    = "user_email"
 	ContextUserRole     = "user_role"
+	ContextUserScopes   = "user_scopes"
+	ContextSessionID    = "session_id"
 )
 
 func JWTAuth(authService service.AuthService) gin.HandlerFunc {
@@ -41,10 +44,22 @@ func JWTAuth(authService service.AuthService) gin.HandlerFunc {
 			return
 		}
 
+		revoked, err := authService.AccessTokenRevoked(c.Request.Context(), claims)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "failed to check token"})
+			return
+		}
+		if revoked {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token has been revoked"})
+			return
+		}
+
 
 		c.Set(ContextUserID, claims.UserID)
 		c.Set(ContextUserEmail, claims.Email)
 		c.Set(ContextUserRole, claims.Role)
+		c.Set(ContextUserScopes, claims.Scopes)
+		c.Set(ContextSessionID, claims.SessionID)
 
 
 		c.Request.Header.Set(HeaderUserID, strconv.FormatInt(claims.UserID, 10))
@@ -74,6 +89,21 @@ func RequireRole(role string) gin.HandlerFunc {
 }
 
 
+// RequireScope aborts unless the access token grants scope
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, _ := GetUserScopes(c)
+		if !models.HasScope(scopes, scope) {
+			c.Header("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+scope+`"`)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient scope", "required_scope": scope})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+
 func GetUserID(c *gin.Context) (int64, bool) {
 	userID, exists := c.Get(ContextUserID)
 	if !exists {
@@ -102,3 +132,13 @@ func GetUserRole(c *gin.Context) (string, bool) {
 	r, ok := role.(string)
 	return r, ok
 }
+
+
+func GetUserScopes(c *gin.Context) ([]string, bool) {
+	scopes, exists := c.Get(ContextUserScopes)
+	if !exists {
+		return nil, false
+	}
+	s, ok := scopes.([]string)
+	return s, ok
+}
@@ -3,17 +3,24 @@ package middleware
 import (
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"context"
 
+	"github.com/Zifeldev/marketback/service/Auth/internal/config"
 	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/Zifeldev/marketback/service/Auth/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
-type stubAuth struct{ claims *models.AccessTokenClaims }
+type stubAuth struct {
+	claims  *models.AccessTokenClaims
+	revoked bool
+}
 
 // Implement service.AuthService methods (minimal stubs)
-func (s *stubAuth) Register(ctx context.Context, email, password, role string) (*models.TokenPair, error) {
+func (s *stubAuth) Register(ctx context.Context, email, password string) (*models.TokenPair, error) {
 	return nil, nil
 }
 func (s *stubAuth) Login(ctx context.Context, email, password string) (*models.TokenPair, error) {
@@ -26,6 +33,21 @@ func (s *stubAuth) RevokeToken(ctx context.Context, refreshToken string) error {
 func (s *stubAuth) ValidateAccessToken(tokenString string) (*models.AccessTokenClaims, error) {
 	return s.claims, nil
 }
+func (s *stubAuth) IssueTokens(ctx context.Context, userID int64) (*models.TokenPair, error) {
+	return nil, nil
+}
+func (s *stubAuth) IssueScopedToken(ctx context.Context, userID int64, granted []string, scope string) (*models.ScopedTokenResponse, error) {
+	return nil, nil
+}
+func (s *stubAuth) RevokeAccessToken(ctx context.Context, accessToken, reason string) error {
+	return nil
+}
+func (s *stubAuth) AccessTokenRevoked(ctx context.Context, claims *models.AccessTokenClaims) (bool, error) {
+	return s.revoked, nil
+}
+func (s *stubAuth) Introspect(ctx context.Context, token, tokenTypeHint string) (*models.IntrospectionResponse, error) {
+	return nil, nil
+}
 
 func TestJWTAuthMiddleware_SetsContextAndHeaders(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -55,6 +77,102 @@ func TestJWTAuthMiddleware_SetsContextAndHeaders(t *testing.T) {
 	}
 }
 
+func TestJWTAuthMiddleware_RevokedToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	claims := &models.AccessTokenClaims{UserID: 42, Role: models.RoleUser, JTI: "abc"}
+	stub := &stubAuth{claims: claims, revoked: true}
+
+	r.GET("/protected", JWTAuth(stub), func(c *gin.Context) { c.Status(200) })
+
+	req := httptest.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != 401 {
+		t.Fatalf("expected 401 for a revoked token, got %d", w.Code)
+	}
+}
+
+// userStore serves one user to a real AuthService; the token stores accept
+// everything
+type userStore struct {
+	repository.UserRepository
+	user *models.User
+}
+
+func (u *userStore) GetByID(ctx context.Context, id int64) (*models.User, error) {
+	return u.user, nil
+}
+
+type tokenStore struct{ repository.TokenRepository }
+
+func (tokenStore) CreateRefreshToken(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
+	return &models.RefreshToken{UserID: userID, Token: token, ExpiresAt: expiresAt}, nil
+}
+
+type blacklistStore struct{}
+
+func (blacklistStore) Add(ctx context.Context, jti string, userID int64, expiresAt time.Time, reason string) error {
+	return nil
+}
+func (blacklistStore) IsBlacklisted(ctx context.Context, jti string) (bool, error) { return false, nil }
+
+// issueToken signs in user with a real AuthService and returns it with an
+// /api route guarded by JWTAuth
+func issueToken(t *testing.T, user *models.User) (*gin.Engine, string) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.JWTConfig{AccessSecret: "access-secret", RefreshSecret: "refresh-secret", AccessExpiration: 15 * time.Minute, RefreshExpiration: time.Hour, Issuer: "test-issuer"}
+	auth := service.NewAuthService(cfg, &userStore{user: user}, tokenStore{}, blacklistStore{}, nil)
+
+	tp, err := auth.IssueTokens(context.Background(), user.ID)
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+
+	r := gin.New()
+	r.GET("/api/me", JWTAuth(auth), func(c *gin.Context) { c.Status(200) })
+	return r, tp.AccessToken
+}
+
+func getWithToken(r *gin.Engine, path, token string) int {
+	req := httptest.NewRequest("GET", path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestJWTAuthMiddleware_SessionsRevoked(t *testing.T) {
+	user := &models.User{ID: 7, Email: "buyer@example.com", Role: models.RoleUser}
+	r, token := issueToken(t, user)
+
+	if code := getWithToken(r, "/api/me", token); code != 200 {
+		t.Fatalf("expected 200 before revoke-sessions, got %d", code)
+	}
+
+	revokedAt := time.Now()
+	user.SessionsRevokedAt = &revokedAt
+
+	if code := getWithToken(r, "/api/me", token); code != 401 {
+		t.Fatalf("expected 401 for a token issued before revoke-sessions, got %d", code)
+	}
+}
+
+func TestJWTAuthMiddleware_Suspended(t *testing.T) {
+	user := &models.User{ID: 8, Email: "seller@example.com", Role: models.RoleSeller}
+	r, token := issueToken(t, user)
+
+	// A banned seller's token stops working at once, not when it expires
+	user.Suspended = true
+
+	if code := getWithToken(r, "/api/me", token); code != 401 {
+		t.Fatalf("expected 401 for a suspended user's token, got %d", code)
+	}
+}
+
 func TestRequireRole_Forbidden(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -72,3 +190,25 @@ func TestRequireRole_Forbidden(t *testing.T) {
 		t.Fatalf("expected 403, got %d", w.Code)
 	}
 }
+
+func TestRequireScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	claims := &models.AccessTokenClaims{UserID: 42, Role: models.RoleAdmin, Scopes: []string{models.ScopeMarketRead}}
+	r.GET("/admin/users", JWTAuth(&stubAuth{claims: claims}), RequireScope(models.ScopeAdmin), func(c *gin.Context) {
+		c.Status(200)
+	})
+
+	req := httptest.NewRequest("GET", "/admin/users", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != 403 {
+		t.Fatalf("expected 403 for a token narrowed to market:read, got %d", w.Code)
+	}
+	if w.Header().Get("WWW-Authenticate") == "" {
+		t.Fatalf("expected insufficient_scope challenge")
+	}
+}
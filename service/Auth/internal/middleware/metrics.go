@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics records per-route latency and error counts. Routes are labelled by
+// their template (e.g. /api/products/:id) so IDs never end up in labels.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := c.Writer.Status()
+
+		metrics.HTTPRequestDuration.
+			WithLabelValues(c.Request.Method, route, statusClass(status)).
+			Observe(time.Since(start).Seconds())
+
+		if status >= 400 {
+			metrics.HTTPRequestErrorsTotal.
+				WithLabelValues(c.Request.Method, route, strconv.Itoa(status)).
+				Inc()
+		}
+	}
+}
+
+func statusClass(status int) string {
+	switch {
+	case status >= 500:
+		return "5xx"
+	case status >= 400:
+		return "4xx"
+	case status >= 300:
+		return "3xx"
+	default:
+		return "2xx"
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/metrics"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics_RouteTemplateLabels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Metrics())
+	r.DELETE("/admin/users/:id", func(c *gin.Context) { c.Status(404) })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("DELETE", "/admin/users/1", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("DELETE", "/admin/users/2", nil))
+
+	got := testutil.ToFloat64(metrics.HTTPRequestErrorsTotal.WithLabelValues("DELETE", "/admin/users/:id", "404"))
+	if got != 2 {
+		t.Fatalf("expected 2 errors for route template, got %v", got)
+	}
+}
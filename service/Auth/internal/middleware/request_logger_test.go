@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRequestLogger_IncludesUserAndRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	log := logrus.New()
+	log.SetOutput(&buf)
+	log.SetFormatter(&logrus.JSONFormatter{})
+
+	stub := &stubAuth{claims: &models.AccessTokenClaims{UserID: 5, Role: models.RoleUser}}
+
+	r := gin.New()
+	r.Use(RequestLogger(logrus.NewEntry(log).WithField("service", "auth")))
+	r.GET("/api/me", JWTAuth(stub), func(c *gin.Context) { c.Status(200) })
+
+	req := httptest.NewRequest("GET", "/api/me", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Header().Get(RequestIDHeader) == "" {
+		t.Fatalf("expected %s header", RequestIDHeader)
+	}
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &line); err != nil {
+		t.Fatalf("expected a single JSON log line, got %q: %v", buf.String(), err)
+	}
+	if line["route"] != "/api/me" || line["service"] != "auth" || line["user_id"] != float64(5) || line["status"] != float64(200) {
+		t.Fatalf("unexpected access log fields: %v", line)
+	}
+}
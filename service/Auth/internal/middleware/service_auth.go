@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ContextServiceClient = "service_client"
+
+// ServiceAuth authenticates other services with HTTP Basic credentials
+// (client ID and secret), as RFC 7662 requires for introspection.
+func ServiceAuth(clients map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, secret, ok := c.Request.BasicAuth()
+		expected, known := clients[id]
+		if !ok || !known || subtle.ConstantTimeCompare([]byte(secret), []byte(expected)) != 1 {
+			c.Header("WWW-Authenticate", `Basic realm="auth-service"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid client credentials"})
+			return
+		}
+
+		c.Set(ContextServiceClient, id)
+		c.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestServiceAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/auth/introspect", ServiceAuth(map[string]string{"market": "s3cret"}), func(c *gin.Context) {
+		if client, _ := c.Get(ContextServiceClient); client != "market" {
+			c.AbortWithStatus(500)
+			return
+		}
+		c.Status(200)
+	})
+
+	tests := []struct {
+		name         string
+		user, secret string
+		basic        bool
+		code         int
+	}{
+		{"valid", "market", "s3cret", true, 200},
+		{"wrong secret", "market", "guess", true, 401},
+		{"unknown client", "gateway", "s3cret", true, 401},
+		{"no credentials", "", "", false, 401},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/auth/introspect", nil)
+			if tt.basic {
+				req.SetBasicAuth(tt.user, tt.secret)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.code {
+				t.Fatalf("expected %d, got %d", tt.code, w.Code)
+			}
+			if tt.code == 401 && w.Header().Get("WWW-Authenticate") == "" {
+				t.Fatalf("expected WWW-Authenticate challenge")
+			}
+		})
+	}
+}
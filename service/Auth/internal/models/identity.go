@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Identity providers that can be linked to an account in addition to its
+// email and password
+const (
+	ProviderGoogle = "google"
+	ProviderPhone  = "phone"
+)
+
+// Identity is a sign-in method linked to a user. Subject is the Google
+// account ID or the E.164 phone number.
+type Identity struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GoogleIdentityRequest struct {
+	IDToken string `json:"id_token" binding:"required"`
+}
+
+// PhoneCodeRequest names a phone number in E.164 form, or as a national
+// number of PHONE_DEFAULT_REGION when that is set.
+type PhoneCodeRequest struct {
+	Phone string `json:"phone" binding:"required" example:"+4915112345678"`
+}
+
+type PhoneIdentityRequest struct {
+	Phone string `json:"phone" binding:"required" example:"+4915112345678"`
+	Code  string `json:"code" binding:"required,len=6,numeric"`
+}
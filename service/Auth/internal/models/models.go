@@ -9,6 +9,20 @@ type User struct {
 	Role         string    `json:"role"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+	// PasswordResetRequired blocks password sign-in until the password is
+	// reset, e.g. after a sign-in was reported as not the user's
+	PasswordResetRequired bool `json:"password_reset_required"`
+	// PasswordChangeRequired blocks sign-in with a temporary password set
+	// by an admin until the user chose their own
+	PasswordChangeRequired bool `json:"password_change_required"`
+	// EmailVerified is set once the link in the verification email was
+	// followed; accounts older than email verification count as verified
+	EmailVerified bool `json:"email_verified"`
+	// SessionsRevokedAt is when all sessions were last revoked; access
+	// tokens issued earlier are no longer active
+	SessionsRevokedAt *time.Time `json:"sessions_revoked_at,omitempty"`
+	// Suspended blocks every sign-in until an admin lifts it
+	Suspended bool `json:"suspended"`
 }
 
 type RefreshToken struct {
@@ -18,6 +32,32 @@ type RefreshToken struct {
 	ExpiresAt time.Time `json:"expires_at"`
 	CreatedAt time.Time `json:"created_at"`
 	Revoked   bool      `json:"revoked"`
+	// Fingerprint identifies the client the token was issued to; empty
+	// when unknown
+	Fingerprint string `json:"-"`
+	// SessionID is shared by all refresh tokens rotated from one sign-in
+	SessionID string `json:"-"`
+	// UserAgent and IPAddress describe the client the token was issued
+	// to; empty when unknown
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
+	// ParentID is the token this one was rotated from; zero for tokens
+	// issued at sign-in
+	ParentID int64 `json:"-"`
+}
+
+// Session is a sign-in that can still be refreshed, described by the
+// client its latest refresh token was issued to
+type Session struct {
+	ID        string `json:"id"`
+	UserAgent string `json:"user_agent"`
+	IPAddress string `json:"ip_address"`
+	// LastUsedAt is when the session last signed in or refreshed its
+	// tokens
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// Current marks the session of the access token making the request
+	Current bool `json:"current"`
 }
 
 // TokenBlacklist represents an invalidated JWT token
@@ -34,12 +74,18 @@ type TokenPair struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 	ExpiresIn    int64  `json:"expires_in"`
+	// UserID and SessionID identify the sign-in for sign-in alerts; they
+	// are not part of the response
+	UserID    int64  `json:"-"`
+	SessionID string `json:"-"`
 }
 
 type RegisterRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=8"`
-	Role     string `json:"role,omitempty"`
+	// Role may only be empty or "user"; privileged roles are requested
+	// through /api/role-requests and granted by an admin
+	Role string `json:"role,omitempty"`
 }
 
 type LoginRequest struct {
@@ -47,15 +93,46 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// LoginAlertDenyRequest reports a sign-in from a security alert email as
+// not made by the user
+type LoginAlertDenyRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type PasswordResetRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type PasswordResetConfirmRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+// PasswordChangeRequest replaces a known password, e.g. a temporary one
+// set by an admin
+type PasswordChangeRequest struct {
+	Email       string `json:"email" binding:"required,email"`
+	Password    string `json:"password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
 type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
 type AccessTokenClaims struct {
-	UserID int64  `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
-	JTI    string `json:"jti"`
+	UserID    int64  `json:"user_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	JTI       string `json:"jti"`
+	Issuer    string `json:"iss"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	// SessionID is the sign-in the token was issued to; empty for scoped
+	// tokens and tokens issued before sessions were listed
+	SessionID string `json:"sid"`
+	// Scopes are empty for tokens issued before scopes were introduced
+	Scopes []string `json:"scopes"`
 }
 
 type RefreshTokenClaims struct {
@@ -63,13 +140,99 @@ type RefreshTokenClaims struct {
 	TokenID int64 `json:"token_id"`
 }
 
+// Token types reported by introspection (RFC 7662 token_type_hint values)
+const (
+	TokenTypeAccess  = "access_token"
+	TokenTypeRefresh = "refresh_token"
+)
+
+// IntrospectRequest is accepted as a form (as in RFC 7662) or as JSON
+type IntrospectRequest struct {
+	Token         string `form:"token" json:"token" binding:"required"`
+	TokenTypeHint string `form:"token_type_hint" json:"token_type_hint"`
+}
+
+// IntrospectionResponse describes a presented token. Inactive tokens
+// (unknown, expired, revoked or of a deleted user) only carry Active.
+type IntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	TokenType string `json:"token_type,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	UserID    int64  `json:"user_id,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Role      string `json:"role,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	Issuer    string `json:"iss,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	JTI       string `json:"jti,omitempty"`
+}
+
+// ScopedTokenRequest asks for an access token limited to some of the
+// presented token's scopes, e.g. "market:read" for a reporting script
+type ScopedTokenRequest struct {
+	Scope string `json:"scope" binding:"required"`
+}
+
+type ScopedTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
+
 // Admin request models
 type CreateUserRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=8"`
 	Role     string `json:"role" binding:"required"`
+	// OTP is the acting admin's current two-factor code, required when
+	// Role is admin
+	OTP string `json:"otp,omitempty"`
 }
 
 type UpdateRoleRequest struct {
-	Role string `json:"role" binding:"required"`
+	Role   string `json:"role" binding:"required"`
+	Reason string `json:"reason" binding:"max=2000"`
+	// OTP is the acting admin's current two-factor code, required when
+	// Role is admin
+	OTP string `json:"otp,omitempty"`
+}
+
+// Two-factor authentication models
+type TwoFactorSetupResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// OpenIDConfiguration is the discovery document served at
+// /.well-known/openid-configuration
+type OpenIDConfiguration struct {
+	Issuer                           string   `json:"issuer"`
+	JWKSURI                          string   `json:"jwks_uri"`
+	UserinfoEndpoint                 string   `json:"userinfo_endpoint"`
+	IntrospectionEndpoint            string   `json:"introspection_endpoint,omitempty"`
+	IntrospectionEndpointAuthMethods []string `json:"introspection_endpoint_auth_methods_supported,omitempty"`
+	SubjectTypesSupported            []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+	ClaimsSupported                  []string `json:"claims_supported"`
+}
+
+// JSONWebKeySet is the key set served at /.well-known/jwks.json
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JSONWebKey is a public RSA signing key (RFC 7517)
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
 }
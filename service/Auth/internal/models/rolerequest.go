@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+const (
+	RoleRequestPending  = "pending"
+	RoleRequestApproved = "approved"
+	RoleRequestRejected = "rejected"
+)
+
+// RequestableRoles are the roles a user can apply for. Admin is never
+// requestable and is only granted by another admin.
+var RequestableRoles = []string{RoleSeller, RoleCourier}
+
+func IsRequestableRole(role string) bool {
+	for _, r := range RequestableRoles {
+		if role == r {
+			return true
+		}
+	}
+	return false
+}
+
+func IsRoleRequestStatus(status string) bool {
+	return status == RoleRequestPending || status == RoleRequestApproved || status == RoleRequestRejected
+}
+
+// RoleRequest is a user's application for a privileged role
+type RoleRequest struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	Email      string     `json:"email,omitempty"`
+	Role       string     `json:"role"`
+	Status     string     `json:"status"`
+	Message    string     `json:"message"`
+	ReviewedBy *int64     `json:"reviewed_by,omitempty"`
+	ReviewNote string     `json:"review_note,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// RoleChange is an audit entry for a role changed by an admin, either
+// directly or by approving a RoleRequest
+type RoleChange struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	OldRole   string    `json:"old_role"`
+	NewRole   string    `json:"new_role"`
+	ChangedBy *int64    `json:"changed_by,omitempty"`
+	RequestID *int64    `json:"request_id,omitempty"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateRoleRequestRequest struct {
+	Role    string `json:"role" binding:"required"`
+	Message string `json:"message" binding:"max=2000"`
+}
+
+type ReviewRoleRequestRequest struct {
+	Note string `json:"note" binding:"max=2000"`
+}
@@ -6,9 +6,10 @@ import (
 )
 
 const (
-	RoleUser   = "user"
-	RoleAdmin  = "admin"
-	RoleSeller = "seller"
+	RoleUser    = "user"
+	RoleAdmin   = "admin"
+	RoleSeller  = "seller"
+	RoleCourier = "courier"
 )
 
 var (
@@ -16,7 +17,7 @@ var (
 	ErrEmptyRole   = errors.New("role cannot be empty")
 )
 
-var ValidRoles = []string{RoleUser, RoleSeller, RoleAdmin}
+var ValidRoles = []string{RoleUser, RoleSeller, RoleCourier, RoleAdmin}
 
 func ValidateRole(role string) error {
 	if role == "" {
@@ -9,6 +9,7 @@ func TestValidateRole(t *testing.T) {
 	}{
 		{"user", true},
 		{"seller", true},
+		{"courier", true},
 		{"admin", true},
 		{"", false},
 		{"unknown", false},
@@ -22,7 +23,7 @@ func TestValidateRole(t *testing.T) {
 }
 
 func TestIsValidRole(t *testing.T) {
-	if !IsValidRole(RoleUser) || !IsValidRole(RoleSeller) || !IsValidRole(RoleAdmin) {
+	if !IsValidRole(RoleUser) || !IsValidRole(RoleSeller) || !IsValidRole(RoleCourier) || !IsValidRole(RoleAdmin) {
 		t.Fatalf("expected core roles to be valid")
 	}
 	if IsValidRole("") || IsValidRole("nope") {
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// Access token scopes. Every service checks the scopes it needs, so a token
+// narrowed to market:read cannot manage the account or place orders.
+const (
+	ScopeAccount     = "account"
+	ScopeMarketRead  = "market:read"
+	ScopeMarketWrite = "market:write"
+	ScopeAdmin       = "admin"
+)
+
+// ErrInvalidScope is returned when a requested scope is unknown or not
+// granted to the presented token
+var ErrInvalidScope = errors.New("invalid scope")
+
+// ScopesForRole returns the scopes of tokens issued at login or refresh
+func ScopesForRole(role string) []string {
+	scopes := []string{ScopeAccount, ScopeMarketRead, ScopeMarketWrite}
+	if role == RoleAdmin {
+		scopes = append(scopes, ScopeAdmin)
+	}
+	return scopes
+}
+
+// ParseScope splits a space-delimited scope string (RFC 6749, section 3.3)
+func ParseScope(scope string) []string {
+	return strings.Fields(scope)
+}
+
+// FormatScope joins scopes into a space-delimited scope string
+func FormatScope(scopes []string) string {
+	return strings.Join(scopes, " ")
+}
+
+// HasScope reports whether scopes contains scope
+func HasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// NarrowScopes returns the requested scopes if all of them are granted
+func NarrowScopes(granted []string, requested string) ([]string, error) {
+	scopes := ParseScope(requested)
+	if len(scopes) == 0 {
+		return nil, ErrInvalidScope
+	}
+
+	narrowed := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		if !HasScope(granted, s) {
+			return nil, ErrInvalidScope
+		}
+		if !HasScope(narrowed, s) {
+			narrowed = append(narrowed, s)
+		}
+	}
+	return narrowed, nil
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestScopesForRole(t *testing.T) {
+	if HasScope(ScopesForRole(RoleSeller), ScopeAdmin) {
+		t.Fatalf("expected seller tokens without admin scope")
+	}
+	if !HasScope(ScopesForRole(RoleAdmin), ScopeAdmin) {
+		t.Fatalf("expected admin tokens with admin scope")
+	}
+}
+
+func TestNarrowScopes(t *testing.T) {
+	granted := ScopesForRole(RoleUser)
+
+	got, err := NarrowScopes(granted, "market:read  market:read")
+	if err != nil || !reflect.DeepEqual(got, []string{ScopeMarketRead}) {
+		t.Fatalf("expected [market:read], got %v (%v)", got, err)
+	}
+
+	for _, requested := range []string{"", "admin", "market:read market:delete"} {
+		if _, err := NarrowScopes(granted, requested); !errors.Is(err, ErrInvalidScope) {
+			t.Errorf("scope %q: expected ErrInvalidScope, got %v", requested, err)
+		}
+	}
+}
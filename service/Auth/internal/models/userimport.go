@@ -0,0 +1,34 @@
+package models
+
+// Statuses of the rows of a user import
+const (
+	ImportRowCreated = "created"
+	// ImportRowValid rows passed validation in a dry run
+	ImportRowValid  = "valid"
+	ImportRowFailed = "failed"
+)
+
+// UserImportRow is the outcome of one row of an imported CSV file
+type UserImportRow struct {
+	// Line is the row's line in the file; the header is line 1
+	Line   int    `json:"line"`
+	Email  string `json:"email"`
+	Role   string `json:"role,omitempty"`
+	Status string `json:"status"`
+	UserID int64  `json:"user_id,omitempty"`
+	// TemporaryPassword reports whether the row set a temporary password;
+	// the password itself is never returned
+	TemporaryPassword bool   `json:"temporary_password"`
+	Error             string `json:"error,omitempty"`
+}
+
+// UserImportResult reports every row of a user import
+type UserImportResult struct {
+	DryRun  bool `json:"dry_run"`
+	Valid   int  `json:"valid"`
+	Created int  `json:"created"`
+	Failed  int  `json:"failed"`
+	// Invited is set when created users are emailed an invitation
+	Invited bool            `json:"invited"`
+	Rows    []UserImportRow `json:"rows"`
+}
@@ -0,0 +1,111 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite docs/openapi.json")
+
+// goldenPath is the committed spec. CI regenerates the swag docs and runs
+// this test, so annotation changes must come with an updated golden file:
+//
+//	swag init -g cmd/main.go -o docs && go test ./internal/openapi -update
+var goldenPath = filepath.Join("..", "..", "docs", "openapi.json")
+
+func TestSpec_Golden(t *testing.T) {
+	spec, err := Spec()
+	require.NoError(t, err)
+
+	if *update {
+		require.NoError(t, os.WriteFile(goldenPath, spec, 0o644))
+	}
+
+	golden, err := os.ReadFile(goldenPath)
+	require.NoError(t, err)
+	if !bytes.Equal(golden, spec) {
+		t.Fatalf("%s is out of date; run go test ./internal/openapi -update", goldenPath)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	swagger := `{
+		"swagger": "2.0",
+		"info": {"title": "Test", "version": "1.0"},
+		"host": "localhost:8080",
+		"basePath": "/",
+		"paths": {
+			"/items/{id}": {
+				"put": {
+					"security": [{"BearerAuth": []}],
+					"consumes": ["application/json"],
+					"parameters": [
+						{"type": "integer", "name": "id", "in": "path", "required": true},
+						{"type": "array", "items": {"type": "string"}, "collectionFormat": "csv", "name": "tags", "in": "query"},
+						{"name": "request", "in": "body", "required": true, "schema": {"$ref": "#/definitions/models.Item"}}
+					],
+					"responses": {
+						"200": {"description": "OK", "schema": {"$ref": "#/definitions/models.Item"}},
+						"400": {"description": "Bad Request", "schema": {"type": "object", "additionalProperties": {"type": "string"}}}
+					}
+				}
+			},
+			"/upload": {
+				"post": {
+					"consumes": ["multipart/form-data"],
+					"parameters": [{"type": "file", "name": "file", "in": "formData", "required": true}],
+					"responses": {"201": {"description": "Created"}}
+				}
+			}
+		},
+		"definitions": {
+			"models.Item": {"type": "object", "properties": {"owner": {"$ref": "#/definitions/models.Owner"}}}
+		},
+		"securityDefinitions": {
+			"BearerAuth": {"type": "apiKey", "name": "Authorization", "in": "header"}
+		}
+	}`
+
+	out, err := Convert([]byte(swagger), Options{ErrorSchema: errorSchema})
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(out, &doc))
+	get := func(path ...string) interface{} {
+		var v interface{} = doc
+		for _, p := range path {
+			v = v.(map[string]interface{})[p]
+		}
+		return v
+	}
+
+	require.Equal(t, Version, doc["openapi"])
+	require.Equal(t, "http://localhost:8080", doc["servers"].([]interface{})[0].(map[string]interface{})["url"])
+
+	put := get("paths", "/items/{id}", "put").(map[string]interface{})
+	params := put["parameters"].([]interface{})
+	require.Len(t, params, 2)
+	require.Equal(t, map[string]interface{}{"type": "integer"}, params[0].(map[string]interface{})["schema"])
+	require.Equal(t, false, params[1].(map[string]interface{})["explode"])
+	require.Equal(t, "#/components/schemas/models.Item", get("paths", "/items/{id}", "put", "requestBody", "content", "application/json", "schema", "$ref"))
+	require.Equal(t, "#/components/schemas/ErrorResponse", get("paths", "/items/{id}", "put", "responses", "400", "content", "application/json", "schema", "$ref"))
+
+	upload := get("paths", "/upload", "post", "requestBody", "content", "multipart/form-data", "schema").(map[string]interface{})
+	require.Equal(t, map[string]interface{}{"type": "string", "format": "binary"}, get("paths", "/upload", "post", "requestBody", "content", "multipart/form-data", "schema", "properties", "file"))
+	require.Equal(t, []interface{}{"file"}, upload["required"])
+
+	require.Equal(t, "#/components/schemas/models.Owner", get("components", "schemas", "models.Item", "properties", "owner", "$ref"))
+	require.Equal(t, "bearer", get("components", "securitySchemes", "BearerAuth", "scheme"))
+	require.NotNil(t, get("components", "schemas", "ErrorResponse"))
+}
+
+func TestConvert_RejectsOpenAPI3(t *testing.T) {
+	_, err := Convert([]byte(`{"openapi": "3.0.3"}`), Options{})
+	require.Error(t, err)
+}
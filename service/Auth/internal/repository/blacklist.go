@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// BlacklistRepository stores revoked access tokens by their jti until they
+// would have expired anyway; the retention job removes them afterwards.
+type BlacklistRepository interface {
+	Add(ctx context.Context, jti string, userID int64, expiresAt time.Time, reason string) error
+	IsBlacklisted(ctx context.Context, jti string) (bool, error)
+}
+
+type blacklistRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewBlacklistRepository(pool *pgxpool.Pool) BlacklistRepository {
+	return &blacklistRepository{pool: pool}
+}
+
+func (r *blacklistRepository) Add(ctx context.Context, jti string, userID int64, expiresAt time.Time, reason string) error {
+	query := `
+		INSERT INTO token_blacklist (token_jti, user_id, expires_at, reason)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (token_jti) DO NOTHING
+	`
+	_, err := r.pool.Exec(ctx, query, jti, userID, expiresAt, reason)
+	return err
+}
+
+func (r *blacklistRepository) IsBlacklisted(ctx context.Context, jti string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM token_blacklist WHERE token_jti = $1)`
+	if err := r.pool.QueryRow(ctx, query, jti).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Zifeldev/marketback/pkg/fieldcrypt"
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrIdentityNotFound = errors.New("identity not found")
+	// ErrIdentityExists is returned when the identity is linked to another
+	// user or the user already linked this provider
+	ErrIdentityExists = errors.New("identity already linked")
+)
+
+type IdentityRepository interface {
+	Create(ctx context.Context, userID int64, provider, subject string) (*models.Identity, error)
+	GetUserID(ctx context.Context, provider, subject string) (int64, error)
+	ListByUser(ctx context.Context, userID int64) ([]*models.Identity, error)
+	Delete(ctx context.Context, userID, id int64) error
+	SavePhoneCode(ctx context.Context, phone, codeHash string, expiresAt, resendAfter time.Time) (bool, error)
+	ConsumePhoneCode(ctx context.Context, phone, codeHash string, maxAttempts int) (bool, error)
+	// Rotate seals subjects stored in plaintext or with a non-primary key
+	// and indexes them; it returns the number of updated identities
+	Rotate(ctx context.Context, batchSize int) (int, error)
+}
+
+// identityRepository seals subjects with keys and looks them up by their
+// blind index; both are nil when encryption is disabled, storing subjects
+// and phone numbers as they are.
+type identityRepository struct {
+	pool  *pgxpool.Pool
+	keys  *fieldcrypt.Keyring
+	index *fieldcrypt.Index
+}
+
+func NewIdentityRepository(pool *pgxpool.Pool, keys *fieldcrypt.Keyring, index *fieldcrypt.Index) IdentityRepository {
+	return &identityRepository{pool: pool, keys: keys, index: index}
+}
+
+// Create links the identity. Rows not yet rotated are indexed by their
+// plaintext subject, so the subject is also checked against that.
+func (r *identityRepository) Create(ctx context.Context, userID int64, provider, subject string) (*models.Identity, error) {
+	sealed, err := r.keys.Encrypt(subject)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &models.Identity{Subject: subject}
+	query := `
+		INSERT INTO identities (user_id, provider, subject, subject_index)
+		SELECT $1, $2, $3, $4
+		WHERE NOT EXISTS (SELECT 1 FROM identities WHERE provider = $2 AND subject_index = $5)
+		RETURNING id, user_id, provider, created_at
+	`
+
+	err = r.pool.QueryRow(ctx, query, userID, provider, sealed, r.index.Hash(subject), subject).Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.CreatedAt,
+	)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.Is(err, pgx.ErrNoRows) || errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrIdentityExists
+		}
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+func (r *identityRepository) GetUserID(ctx context.Context, provider, subject string) (int64, error) {
+	var userID int64
+	query := `SELECT user_id FROM identities WHERE provider = $1 AND subject_index IN ($2, $3)`
+
+	if err := r.pool.QueryRow(ctx, query, provider, r.index.Hash(subject), subject).Scan(&userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrIdentityNotFound
+		}
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+func (r *identityRepository) ListByUser(ctx context.Context, userID int64) ([]*models.Identity, error) {
+	query := `
+		SELECT id, user_id, provider, subject, created_at
+		FROM identities
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := make([]*models.Identity, 0)
+	for rows.Next() {
+		identity := &models.Identity{}
+		if err := rows.Scan(
+			&identity.ID,
+			&identity.UserID,
+			&identity.Provider,
+			&identity.Subject,
+			&identity.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if identity.Subject, err = r.keys.Decrypt(identity.Subject); err != nil {
+			return nil, fmt.Errorf("failed to decrypt identity %d: %w", identity.ID, err)
+		}
+		identities = append(identities, identity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+func (r *identityRepository) Delete(ctx context.Context, userID, id int64) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM identities WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrIdentityNotFound
+	}
+
+	return nil
+}
+
+// SavePhoneCode stores a new code for the phone, keyed by its blind index,
+// replacing a pending one sent before resendAfter. It reports false without
+// saving when the pending code is more recent.
+func (r *identityRepository) SavePhoneCode(ctx context.Context, phone, codeHash string, expiresAt, resendAfter time.Time) (bool, error) {
+	query := `
+		INSERT INTO phone_codes (phone, code_hash, attempts, expires_at, created_at)
+		VALUES ($1, $2, 0, $3, NOW())
+		ON CONFLICT (phone) DO UPDATE
+		SET code_hash = EXCLUDED.code_hash, attempts = 0, expires_at = EXCLUDED.expires_at, created_at = NOW()
+		WHERE phone_codes.created_at < $4
+		RETURNING phone
+	`
+
+	var saved string
+	if err := r.pool.QueryRow(ctx, query, r.index.Hash(phone), codeHash, expiresAt, resendAfter).Scan(&saved); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ConsumePhoneCode checks a code for the phone. A matching code is deleted;
+// a wrong one counts as an attempt, and after maxAttempts the code stops
+// working.
+func (r *identityRepository) ConsumePhoneCode(ctx context.Context, phone, codeHash string, maxAttempts int) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	phone = r.index.Hash(phone)
+	var storedHash string
+	var attempts int
+	query := `SELECT code_hash, attempts FROM phone_codes WHERE phone = $1 AND expires_at > NOW() FOR UPDATE`
+	if err := tx.QueryRow(ctx, query, phone).Scan(&storedHash, &attempts); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if attempts >= maxAttempts {
+		return false, nil
+	}
+
+	matched := storedHash == codeHash
+	if matched {
+		_, err = tx.Exec(ctx, `DELETE FROM phone_codes WHERE phone = $1`, phone)
+	} else {
+		_, err = tx.Exec(ctx, `UPDATE phone_codes SET attempts = attempts + 1 WHERE phone = $1`, phone)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+
+	return matched, nil
+}
+
+func (r *identityRepository) Rotate(ctx context.Context, batchSize int) (int, error) {
+	if r.keys == nil {
+		return 0, nil
+	}
+
+	type pending struct {
+		id      int64
+		subject string
+	}
+
+	rotated := 0
+	var lastID int64
+	for {
+		query := `
+			SELECT id, subject
+			FROM identities
+			WHERE id > $1 AND (subject NOT LIKE $2 OR subject_index NOT LIKE 'hmac:v1:%')
+			ORDER BY id
+			LIMIT $3
+		`
+		rows, err := r.pool.Query(ctx, query, lastID, "enc:v1:"+r.keys.PrimaryKeyID()+":%", batchSize)
+		if err != nil {
+			return rotated, err
+		}
+
+		var batch []pending
+		for rows.Next() {
+			var p pending
+			if err := rows.Scan(&p.id, &p.subject); err != nil {
+				rows.Close()
+				return rotated, err
+			}
+			batch = append(batch, p)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return rotated, err
+		}
+
+		if len(batch) == 0 {
+			return rotated, nil
+		}
+
+		for _, p := range batch {
+			lastID = p.id
+
+			plain, err := r.keys.Decrypt(p.subject)
+			if err != nil {
+				return rotated, fmt.Errorf("failed to decrypt identity %d: %w", p.id, err)
+			}
+			sealed, err := r.keys.Encrypt(plain)
+			if err != nil {
+				return rotated, err
+			}
+
+			query := `UPDATE identities SET subject = $1, subject_index = $2 WHERE id = $3 AND subject = $4`
+			if _, err := r.pool.Exec(ctx, query, sealed, r.index.Hash(plain), p.id, p.subject); err != nil {
+				return rotated, err
+			}
+			rotated++
+		}
+	}
+}
@@ -17,6 +17,9 @@ var (
 	ErrTokenNotFound = errors.New("refresh token not found")
 	ErrTokenRevoked  = errors.New("refresh token revoked")
 	ErrTokenExpired  = errors.New("refresh token expired")
+	// ErrSessionNotFound is returned for a session that is unknown, not
+	// the user's or already signed out
+	ErrSessionNotFound = errors.New("session not found")
 )
 
 type UserRepository interface {
@@ -27,13 +30,24 @@ type UserRepository interface {
 	UpdateRole(ctx context.Context, id int64, role string) (*models.User, error)
 	Delete(ctx context.Context, id int64) error
 	List(ctx context.Context, limit, offset int) ([]*models.User, error)
+	// ListByIDs returns the users with the given IDs; unknown IDs are skipped
+	ListByIDs(ctx context.Context, ids []int64) ([]*models.User, error)
 }
 
 type TokenRepository interface {
-	CreateRefreshToken(ctx context.Context, userID int64, token string, expiresAt time.Time) (*models.RefreshToken, error)
+	CreateRefreshToken(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error)
 	GetRefreshToken(ctx context.Context, token string) (*models.RefreshToken, error)
 	RevokeRefreshToken(ctx context.Context, token string) error
+	// RevokeTokenFamily revokes every token rotated, directly or in turn,
+	// from token and returns their user; ErrTokenNotFound when token was
+	// never rotated
+	RevokeTokenFamily(ctx context.Context, token string) (int64, error)
 	RevokeAllUserTokens(ctx context.Context, userID int64) error
+	// RevokeSession revokes every refresh token of one sign-in
+	RevokeSession(ctx context.Context, userID int64, sessionID string) error
+	// ListSessions returns the user's sessions that can still be
+	// refreshed, most recently used first
+	ListSessions(ctx context.Context, userID int64) ([]*models.Session, error)
 	CleanupExpiredTokens(ctx context.Context) error
 }
 
@@ -63,9 +77,6 @@ func (r *userRepository) Create(ctx context.Context, email, passwordHash string)
 	user := &models.User{}
 
 	role := models.RoleUser
-	if r.cfg.FirstAdminEmail != "" && email == r.cfg.FirstAdminEmail {
-		role = models.RoleAdmin
-	}
 
 	query := `
 		INSERT INTO users (email, password_hash, role, created_at, updated_at)
@@ -94,7 +105,7 @@ func (r *userRepository) Create(ctx context.Context, email, passwordHash string)
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	user := &models.User{}
-	query := `SELECT id, email, password_hash, role, created_at, updated_at FROM users WHERE email = $1`
+	query := `SELECT id, email, password_hash, role, created_at, updated_at, password_reset_required, password_change_required, email_verified, sessions_revoked_at, suspended FROM users WHERE email = $1`
 
 	err := r.pool.QueryRow(ctx, query, email).Scan(
 		&user.ID,
@@ -103,6 +114,11 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.
 		&user.Role,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.PasswordResetRequired,
+		&user.PasswordChangeRequired,
+		&user.EmailVerified,
+		&user.SessionsRevokedAt,
+		&user.Suspended,
 	)
 
 	if err != nil {
@@ -117,7 +133,7 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.
 
 func (r *userRepository) GetByID(ctx context.Context, id int64) (*models.User, error) {
 	user := &models.User{}
-	query := `SELECT id, email, password_hash, role, created_at, updated_at FROM users WHERE id = $1`
+	query := `SELECT id, email, password_hash, role, created_at, updated_at, password_reset_required, password_change_required, email_verified, sessions_revoked_at, suspended FROM users WHERE id = $1`
 
 	err := r.pool.QueryRow(ctx, query, id).Scan(
 		&user.ID,
@@ -126,6 +142,11 @@ func (r *userRepository) GetByID(ctx context.Context, id int64) (*models.User, e
 		&user.Role,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.PasswordResetRequired,
+		&user.PasswordChangeRequired,
+		&user.EmailVerified,
+		&user.SessionsRevokedAt,
+		&user.Suspended,
 	)
 
 	if err != nil {
@@ -221,7 +242,7 @@ func (r *userRepository) Delete(ctx context.Context, id int64) error {
 
 func (r *userRepository) List(ctx context.Context, limit, offset int) ([]*models.User, error) {
 	query := `
-		SELECT id, email, password_hash, role, created_at, updated_at 
+		SELECT id, email, password_hash, role, created_at, updated_at, password_reset_required, password_change_required, email_verified, sessions_revoked_at, suspended
 		FROM users 
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
@@ -243,6 +264,11 @@ func (r *userRepository) List(ctx context.Context, limit, offset int) ([]*models
 			&user.Role,
 			&user.CreatedAt,
 			&user.UpdatedAt,
+			&user.PasswordResetRequired,
+			&user.PasswordChangeRequired,
+			&user.EmailVerified,
+			&user.SessionsRevokedAt,
+			&user.Suspended,
 		)
 		if err != nil {
 			return nil, err
@@ -257,21 +283,70 @@ func (r *userRepository) List(ctx context.Context, limit, offset int) ([]*models
 	return users, nil
 }
 
-func (r *tokenRepository) CreateRefreshToken(ctx context.Context, userID int64, token string, expiresAt time.Time) (*models.RefreshToken, error) {
+func (r *userRepository) ListByIDs(ctx context.Context, ids []int64) ([]*models.User, error) {
+	query := `
+		SELECT id, email, password_hash, role, created_at, updated_at, password_reset_required, password_change_required, email_verified, sessions_revoked_at, suspended
+		FROM users
+		WHERE id = ANY($1)
+		ORDER BY id
+	`
+
+	rows, err := r.pool.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]*models.User, 0, len(ids))
+	for rows.Next() {
+		user := &models.User{}
+		err := rows.Scan(
+			&user.ID,
+			&user.Email,
+			&user.PasswordHash,
+			&user.Role,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.PasswordResetRequired,
+			&user.PasswordChangeRequired,
+			&user.EmailVerified,
+			&user.SessionsRevokedAt,
+			&user.Suspended,
+		)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *tokenRepository) CreateRefreshToken(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
 	rt := &models.RefreshToken{}
 	query := `
-		INSERT INTO refresh_tokens (user_id, token, expires_at, created_at, revoked)
-		VALUES ($1, $2, $3, NOW(), FALSE)
-		RETURNING id, user_id, token, expires_at, created_at, revoked
+		INSERT INTO refresh_tokens (user_id, token, expires_at, created_at, revoked, client_fingerprint, session_id, user_agent, ip_address, parent_id)
+		VALUES ($1, $2, $3, NOW(), FALSE, NULLIF($4, ''), NULLIF($5, ''), NULLIF(LEFT($6, 512), ''), NULLIF($7, ''), NULLIF($8, 0))
+		RETURNING id, user_id, token, expires_at, created_at, revoked, COALESCE(client_fingerprint, ''), COALESCE(session_id, ''),
+			COALESCE(user_agent, ''), COALESCE(ip_address, ''), COALESCE(parent_id, 0)
 	`
 
-	err := r.pool.QueryRow(ctx, query, userID, token, expiresAt).Scan(
+	err := r.pool.QueryRow(ctx, query, userID, token, expiresAt, fingerprint, sessionID, userAgent, ip, parentID).Scan(
 		&rt.ID,
 		&rt.UserID,
 		&rt.Token,
 		&rt.ExpiresAt,
 		&rt.CreatedAt,
 		&rt.Revoked,
+		&rt.Fingerprint,
+		&rt.SessionID,
+		&rt.UserAgent,
+		&rt.IPAddress,
+		&rt.ParentID,
 	)
 
 	if err != nil {
@@ -283,7 +358,10 @@ func (r *tokenRepository) CreateRefreshToken(ctx context.Context, userID int64,
 
 func (r *tokenRepository) GetRefreshToken(ctx context.Context, token string) (*models.RefreshToken, error) {
 	rt := &models.RefreshToken{}
-	query := `SELECT id, user_id, token, expires_at, created_at, revoked FROM refresh_tokens WHERE token = $1`
+	query := `
+		SELECT id, user_id, token, expires_at, created_at, revoked, COALESCE(client_fingerprint, ''), COALESCE(session_id, '')
+		FROM refresh_tokens WHERE token = $1
+	`
 
 	err := r.pool.QueryRow(ctx, query, token).Scan(
 		&rt.ID,
@@ -292,6 +370,8 @@ func (r *tokenRepository) GetRefreshToken(ctx context.Context, token string) (*m
 		&rt.ExpiresAt,
 		&rt.CreatedAt,
 		&rt.Revoked,
+		&rt.Fingerprint,
+		&rt.SessionID,
 	)
 
 	if err != nil {
@@ -318,12 +398,83 @@ func (r *tokenRepository) RevokeRefreshToken(ctx context.Context, token string)
 	return err
 }
 
+// RevokeTokenFamily walks the tokens rotated from token down the parent
+// links; the revoking statement runs even though the query only reads the
+// family.
+func (r *tokenRepository) RevokeTokenFamily(ctx context.Context, token string) (int64, error) {
+	query := `
+		WITH RECURSIVE family AS (
+			SELECT child.id, child.user_id
+			FROM refresh_tokens parent JOIN refresh_tokens child ON child.parent_id = parent.id
+			WHERE parent.token = $1
+			UNION
+			SELECT child.id, child.user_id
+			FROM refresh_tokens child JOIN family ON child.parent_id = family.id
+		), revoked AS (
+			UPDATE refresh_tokens SET revoked = TRUE WHERE id IN (SELECT id FROM family) AND revoked = FALSE
+		)
+		SELECT user_id FROM family LIMIT 1
+	`
+
+	var userID int64
+	if err := r.pool.QueryRow(ctx, query, token).Scan(&userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrTokenNotFound
+		}
+		return 0, err
+	}
+	return userID, nil
+}
+
 func (r *tokenRepository) RevokeAllUserTokens(ctx context.Context, userID int64) error {
 	query := `UPDATE refresh_tokens SET revoked = TRUE WHERE user_id = $1 AND revoked = FALSE`
 	_, err := r.pool.Exec(ctx, query, userID)
 	return err
 }
 
+func (r *tokenRepository) RevokeSession(ctx context.Context, userID int64, sessionID string) error {
+	query := `UPDATE refresh_tokens SET revoked = TRUE WHERE user_id = $1 AND session_id = $2 AND revoked = FALSE`
+	_, err := r.pool.Exec(ctx, query, userID, sessionID)
+	return err
+}
+
+// ListSessions describes each session by its only active refresh token;
+// older ones were revoked when it was rotated. Tokens issued before
+// sessions existed have no session and are not listed.
+func (r *tokenRepository) ListSessions(ctx context.Context, userID int64) ([]*models.Session, error) {
+	query := `
+		SELECT session_id, COALESCE(user_agent, ''), COALESCE(ip_address, ''), created_at, expires_at
+		FROM (
+			SELECT DISTINCT ON (session_id) session_id, user_agent, ip_address, created_at, expires_at
+			FROM refresh_tokens
+			WHERE user_id = $1 AND session_id IS NOT NULL AND revoked = FALSE AND expires_at > NOW()
+			ORDER BY session_id, created_at DESC
+		) latest
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]*models.Session, 0)
+	for rows.Next() {
+		session := &models.Session{}
+		if err := rows.Scan(&session.ID, &session.UserAgent, &session.IPAddress, &session.LastUsedAt, &session.ExpiresAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
 func (r *tokenRepository) CleanupExpiredTokens(ctx context.Context) error {
 	query := `DELETE FROM refresh_tokens WHERE expires_at < NOW() OR revoked = TRUE`
 	_, err := r.pool.Exec(ctx, query)
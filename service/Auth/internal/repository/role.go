@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrRoleRequestNotFound = errors.New("role request not found")
+	// ErrRoleRequestPending is returned when the user already has a
+	// pending request
+	ErrRoleRequestPending = errors.New("a role request is already pending")
+	// ErrRoleRequestReviewed is returned when reviewing a request that was
+	// already approved or rejected
+	ErrRoleRequestReviewed = errors.New("role request already reviewed")
+	// ErrRoleRequestNotAllowed is returned when the user no longer has the
+	// user role, so there is nothing to elevate
+	ErrRoleRequestNotAllowed = errors.New("only users with the user role can request a role")
+)
+
+// RoleRepository stores role requests and is the only place roles are
+// changed on behalf of an admin; every change is written to role_changes.
+type RoleRepository interface {
+	CreateRequest(ctx context.Context, userID int64, role, message string) (*models.RoleRequest, error)
+	ListRequestsByUser(ctx context.Context, userID int64) ([]*models.RoleRequest, error)
+	ListRequests(ctx context.Context, status string, limit, offset int) ([]*models.RoleRequest, error)
+	ReviewRequest(ctx context.Context, id, adminID int64, approve bool, note string) (*models.RoleRequest, error)
+	SetRole(ctx context.Context, userID int64, role string, adminID int64, reason string) (*models.User, error)
+	ListChanges(ctx context.Context, userID int64, limit, offset int) ([]*models.RoleChange, error)
+}
+
+type roleRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRoleRepository(pool *pgxpool.Pool) RoleRepository {
+	return &roleRepository{pool: pool}
+}
+
+const roleRequestColumns = `r.id, r.user_id, u.email, r.role, r.status, r.message, r.reviewed_by, r.review_note, r.created_at, r.reviewed_at`
+
+func scanRoleRequest(row pgx.Row) (*models.RoleRequest, error) {
+	req := &models.RoleRequest{}
+	err := row.Scan(
+		&req.ID,
+		&req.UserID,
+		&req.Email,
+		&req.Role,
+		&req.Status,
+		&req.Message,
+		&req.ReviewedBy,
+		&req.ReviewNote,
+		&req.CreatedAt,
+		&req.ReviewedAt,
+	)
+	return req, err
+}
+
+func (r *roleRepository) CreateRequest(ctx context.Context, userID int64, role, message string) (*models.RoleRequest, error) {
+	query := `
+		WITH r AS (
+			INSERT INTO role_requests (user_id, role, message)
+			SELECT id, $2, $3 FROM users WHERE id = $1 AND role = 'user'
+			RETURNING *
+		)
+		SELECT ` + roleRequestColumns + `
+		FROM r JOIN users u ON u.id = r.user_id
+	`
+
+	req, err := scanRoleRequest(r.pool.QueryRow(ctx, query, userID, role, message))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrRoleRequestPending
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRoleRequestNotAllowed
+		}
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (r *roleRepository) listRequests(ctx context.Context, query string, args ...interface{}) ([]*models.RoleRequest, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := make([]*models.RoleRequest, 0)
+	for rows.Next() {
+		req, err := scanRoleRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+func (r *roleRepository) ListRequestsByUser(ctx context.Context, userID int64) ([]*models.RoleRequest, error) {
+	query := `
+		SELECT ` + roleRequestColumns + `
+		FROM role_requests r JOIN users u ON u.id = r.user_id
+		WHERE r.user_id = $1
+		ORDER BY r.created_at DESC
+	`
+	return r.listRequests(ctx, query, userID)
+}
+
+// ListRequests lists requests oldest first, optionally filtered by status
+func (r *roleRepository) ListRequests(ctx context.Context, status string, limit, offset int) ([]*models.RoleRequest, error) {
+	query := `
+		SELECT ` + roleRequestColumns + `
+		FROM role_requests r JOIN users u ON u.id = r.user_id
+		WHERE $1 = '' OR r.status = $1
+		ORDER BY r.created_at, r.id
+		LIMIT $2 OFFSET $3
+	`
+	return r.listRequests(ctx, query, status, limit, offset)
+}
+
+// ReviewRequest approves or rejects a pending request. Approving grants the
+// requested role in the same transaction and records it in role_changes.
+func (r *roleRepository) ReviewRequest(ctx context.Context, id, adminID int64, approve bool, note string) (*models.RoleRequest, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var userID int64
+	var role, status string
+	query := `SELECT user_id, role, status FROM role_requests WHERE id = $1 FOR UPDATE`
+	if err := tx.QueryRow(ctx, query, id).Scan(&userID, &role, &status); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRoleRequestNotFound
+		}
+		return nil, err
+	}
+
+	if status != models.RoleRequestPending {
+		return nil, ErrRoleRequestReviewed
+	}
+
+	status = models.RoleRequestRejected
+	if approve {
+		var current string
+		if err := tx.QueryRow(ctx, `SELECT role FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&current); err != nil {
+			return nil, err
+		}
+		if current != models.RoleUser {
+			return nil, ErrRoleRequestNotAllowed
+		}
+
+		status = models.RoleRequestApproved
+		reason := fmt.Sprintf("role request #%d approved", id)
+		if note != "" {
+			reason += ": " + note
+		}
+		if _, err := setRole(ctx, tx, userID, role, adminID, &id, reason); err != nil {
+			return nil, err
+		}
+	}
+
+	query = `
+		WITH r AS (
+			UPDATE role_requests
+			SET status = $2, reviewed_by = $3, review_note = $4, reviewed_at = NOW()
+			WHERE id = $1
+			RETURNING *
+		)
+		SELECT ` + roleRequestColumns + `
+		FROM r JOIN users u ON u.id = r.user_id
+	`
+	req, err := scanRoleRequest(tx.QueryRow(ctx, query, id, status, adminID, note))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// SetRole changes a user's role on behalf of an admin and records the
+// change. adminID 0 records a change made outside the API (cmd/createadmin).
+func (r *roleRepository) SetRole(ctx context.Context, userID int64, role string, adminID int64, reason string) (*models.User, error) {
+	if err := models.ValidateRole(role); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	user, err := setRole(ctx, tx, userID, role, adminID, nil, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// setRole updates the role inside tx and writes the audit entry. Setting the
+// role a user already has is not recorded.
+func setRole(ctx context.Context, tx pgx.Tx, userID int64, role string, adminID int64, requestID *int64, reason string) (*models.User, error) {
+	var oldRole string
+	if err := tx.QueryRow(ctx, `SELECT role FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&oldRole); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	user := &models.User{}
+	query := `
+		UPDATE users
+		SET role = $2, updated_at = NOW()
+		WHERE id = $1
+		RETURNING id, email, password_hash, role, created_at, updated_at
+	`
+	if err := tx.QueryRow(ctx, query, userID, role).Scan(
+		&user.ID,
+		&user.Email,
+		&user.PasswordHash,
+		&user.Role,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if oldRole == role {
+		return user, nil
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO role_changes (user_id, old_role, new_role, changed_by, request_id, reason)
+		VALUES ($1, $2, $3, NULLIF($4::bigint, 0), $5, $6)
+	`, userID, oldRole, role, adminID, requestID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ListChanges lists role changes newest first; userID 0 lists all users
+func (r *roleRepository) ListChanges(ctx context.Context, userID int64, limit, offset int) ([]*models.RoleChange, error) {
+	query := `
+		SELECT id, user_id, old_role, new_role, changed_by, request_id, reason, created_at
+		FROM role_changes
+		WHERE $1 = 0 OR user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]*models.RoleChange, 0)
+	for rows.Next() {
+		change := &models.RoleChange{}
+		if err := rows.Scan(
+			&change.ID,
+			&change.UserID,
+			&change.OldRole,
+			&change.NewRole,
+			&change.ChangedBy,
+			&change.RequestID,
+			&change.Reason,
+			&change.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrSecurityTokenInvalid is returned for unknown, expired or already used
+// email links
+var ErrSecurityTokenInvalid = errors.New("invalid or expired link")
+
+// Purposes of single-use email links
+const (
+	PurposeLoginAlert    = "login_alert"
+	PurposePasswordReset = "password_reset"
+	// PurposeEmailVerification links verify the email of an account
+	PurposeEmailVerification = "email_verification"
+)
+
+// SecurityToken is a consumed email link
+type SecurityToken struct {
+	UserID    int64
+	SessionID string
+}
+
+// SecurityRepository keeps the devices users sign in from, single-use email
+// links and the password reset state of users.
+type SecurityRepository interface {
+	// RecordDevice notes a sign-in from device and country. It reports
+	// whether either was never seen for the user, and whether this is the
+	// user's first recorded sign-in at all.
+	RecordDevice(ctx context.Context, userID int64, device, country string) (newDevice, newCountry, first bool, err error)
+	// CreateToken stores the hash of an email link token
+	CreateToken(ctx context.Context, userID int64, purpose, tokenHash, sessionID string, expiresAt time.Time) error
+	// ConsumeToken marks a link as used and returns who it was issued to
+	ConsumeToken(ctx context.Context, purpose, tokenHash string) (*SecurityToken, error)
+	SetPasswordResetRequired(ctx context.Context, userID int64) error
+	// SetPasswordChangeRequired marks the user's password as temporary
+	SetPasswordChangeRequired(ctx context.Context, userID int64) error
+	// ResetPassword stores a new password hash and lifts the reset and
+	// change requirements
+	ResetPassword(ctx context.Context, userID int64, passwordHash string) error
+	// RevokeSessions revokes all refresh tokens of a user and records the
+	// time, so access tokens issued before it stop being active
+	RevokeSessions(ctx context.Context, userID int64) error
+	SetSuspended(ctx context.Context, userID int64, suspended bool) error
+	SetEmailVerified(ctx context.Context, userID int64) error
+}
+
+type securityRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewSecurityRepository(pool *pgxpool.Pool) SecurityRepository {
+	return &securityRepository{pool: pool}
+}
+
+func (r *securityRepository) RecordDevice(ctx context.Context, userID int64, device, country string) (bool, bool, bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, false, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	var known, knownDevice, knownCountry bool
+	query := `
+		SELECT COUNT(*) > 0,
+		       COALESCE(BOOL_OR(device = $2), FALSE),
+		       COALESCE(BOOL_OR(country = $3), FALSE)
+		FROM login_devices WHERE user_id = $1
+	`
+	if err := tx.QueryRow(ctx, query, userID, device, country).Scan(&known, &knownDevice, &knownCountry); err != nil {
+		return false, false, false, err
+	}
+
+	upsert := `
+		INSERT INTO login_devices (user_id, device, country)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, device, country) DO UPDATE SET last_seen_at = NOW()
+	`
+	if _, err := tx.Exec(ctx, upsert, userID, device, country); err != nil {
+		return false, false, false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, false, false, err
+	}
+	// An unknown country is never reported as new
+	return !knownDevice, country != "" && !knownCountry, !known, nil
+}
+
+func (r *securityRepository) CreateToken(ctx context.Context, userID int64, purpose, tokenHash, sessionID string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO security_tokens (user_id, purpose, token_hash, session_id, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := r.pool.Exec(ctx, query, userID, purpose, tokenHash, sessionID, expiresAt)
+	return err
+}
+
+func (r *securityRepository) ConsumeToken(ctx context.Context, purpose, tokenHash string) (*SecurityToken, error) {
+	token := &SecurityToken{}
+	query := `
+		UPDATE security_tokens SET used_at = NOW()
+		WHERE purpose = $1 AND token_hash = $2 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id, session_id
+	`
+
+	err := r.pool.QueryRow(ctx, query, purpose, tokenHash).Scan(&token.UserID, &token.SessionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSecurityTokenInvalid
+		}
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (r *securityRepository) SetPasswordResetRequired(ctx context.Context, userID int64) error {
+	query := `UPDATE users SET password_reset_required = TRUE, updated_at = NOW() WHERE id = $1`
+	result, err := r.pool.Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *securityRepository) SetPasswordChangeRequired(ctx context.Context, userID int64) error {
+	query := `UPDATE users SET password_change_required = TRUE, updated_at = NOW() WHERE id = $1`
+	result, err := r.pool.Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *securityRepository) ResetPassword(ctx context.Context, userID int64, passwordHash string) error {
+	query := `UPDATE users SET password_hash = $2, password_reset_required = FALSE, password_change_required = FALSE, updated_at = NOW() WHERE id = $1`
+	result, err := r.pool.Exec(ctx, query, userID, passwordHash)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *securityRepository) SetEmailVerified(ctx context.Context, userID int64) error {
+	query := `UPDATE users SET email_verified = TRUE, updated_at = NOW() WHERE id = $1`
+	result, err := r.pool.Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *securityRepository) SetSuspended(ctx context.Context, userID int64, suspended bool) error {
+	query := `UPDATE users SET suspended = $2, updated_at = NOW() WHERE id = $1`
+	result, err := r.pool.Exec(ctx, query, userID, suspended)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *securityRepository) RevokeSessions(ctx context.Context, userID int64) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `UPDATE users SET sessions_revoked_at = NOW(), updated_at = NOW() WHERE id = $1`, userID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE refresh_tokens SET revoked = TRUE WHERE user_id = $1 AND revoked = FALSE`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
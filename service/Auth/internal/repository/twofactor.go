@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Zifeldev/marketback/pkg/fieldcrypt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrTwoFactorEnabled is returned when starting setup for a user that
+// already has two-factor authentication enabled
+var ErrTwoFactorEnabled = errors.New("two-factor authentication already enabled")
+
+type TwoFactorRepository interface {
+	// Get returns the stored secret (empty when never set up) and whether
+	// two-factor authentication is enabled
+	Get(ctx context.Context, userID int64) (string, bool, error)
+	SetSecret(ctx context.Context, userID int64, secret string) error
+	Enable(ctx context.Context, userID int64) error
+	Disable(ctx context.Context, userID int64) error
+	// Rotate seals secrets stored in plaintext or with a non-primary key; it
+	// returns the number of updated users
+	Rotate(ctx context.Context, batchSize int) (int, error)
+}
+
+// twoFactorRepository seals secrets with keys; nil keys store them as they
+// are
+type twoFactorRepository struct {
+	pool *pgxpool.Pool
+	keys *fieldcrypt.Keyring
+}
+
+func NewTwoFactorRepository(pool *pgxpool.Pool, keys *fieldcrypt.Keyring) TwoFactorRepository {
+	return &twoFactorRepository{pool: pool, keys: keys}
+}
+
+func (r *twoFactorRepository) Get(ctx context.Context, userID int64) (string, bool, error) {
+	var secret *string
+	var enabled bool
+	query := `SELECT totp_secret, totp_enabled FROM users WHERE id = $1`
+
+	if err := r.pool.QueryRow(ctx, query, userID).Scan(&secret, &enabled); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", false, ErrUserNotFound
+		}
+		return "", false, err
+	}
+
+	if secret == nil {
+		return "", enabled, nil
+	}
+	plain, err := r.keys.Decrypt(*secret)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to decrypt two-factor secret: %w", err)
+	}
+	return plain, enabled, nil
+}
+
+// SetSecret stores a new pending secret; it is rejected while two-factor
+// authentication is enabled so an active secret is never replaced
+func (r *twoFactorRepository) SetSecret(ctx context.Context, userID int64, secret string) error {
+	sealed, err := r.keys.Encrypt(secret)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE users SET totp_secret = $2, updated_at = NOW() WHERE id = $1 AND NOT totp_enabled`
+	result, err := r.pool.Exec(ctx, query, userID, sealed)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		if _, _, err := r.Get(ctx, userID); err != nil {
+			return err
+		}
+		return ErrTwoFactorEnabled
+	}
+
+	return nil
+}
+
+func (r *twoFactorRepository) Enable(ctx context.Context, userID int64) error {
+	query := `UPDATE users SET totp_enabled = TRUE, updated_at = NOW() WHERE id = $1 AND totp_secret IS NOT NULL`
+	result, err := r.pool.Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *twoFactorRepository) Disable(ctx context.Context, userID int64) error {
+	query := `UPDATE users SET totp_enabled = FALSE, totp_secret = NULL, updated_at = NOW() WHERE id = $1`
+	result, err := r.pool.Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *twoFactorRepository) Rotate(ctx context.Context, batchSize int) (int, error) {
+	if r.keys == nil {
+		return 0, nil
+	}
+
+	type pending struct {
+		id     int64
+		secret string
+	}
+
+	rotated := 0
+	var lastID int64
+	for {
+		query := `
+			SELECT id, totp_secret
+			FROM users
+			WHERE id > $1 AND totp_secret IS NOT NULL AND totp_secret NOT LIKE $2
+			ORDER BY id
+			LIMIT $3
+		`
+		rows, err := r.pool.Query(ctx, query, lastID, "enc:v1:"+r.keys.PrimaryKeyID()+":%", batchSize)
+		if err != nil {
+			return rotated, err
+		}
+
+		var batch []pending
+		for rows.Next() {
+			var p pending
+			if err := rows.Scan(&p.id, &p.secret); err != nil {
+				rows.Close()
+				return rotated, err
+			}
+			batch = append(batch, p)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return rotated, err
+		}
+
+		if len(batch) == 0 {
+			return rotated, nil
+		}
+
+		for _, p := range batch {
+			lastID = p.id
+
+			plain, err := r.keys.Decrypt(p.secret)
+			if err != nil {
+				return rotated, fmt.Errorf("failed to decrypt two-factor secret of user %d: %w", p.id, err)
+			}
+			sealed, err := r.keys.Encrypt(plain)
+			if err != nil {
+				return rotated, err
+			}
+
+			query := `UPDATE users SET totp_secret = $1 WHERE id = $2 AND totp_secret = $3`
+			if _, err := r.pool.Exec(ctx, query, sealed, p.id, p.secret); err != nil {
+				return rotated, err
+			}
+			rotated++
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package retention
+
+import "time"
+
+// StaleRefreshTokens removes refresh tokens that expired or were revoked
+// before the cutoff
+func StaleRefreshTokens(after time.Duration) Policy {
+	return Policy{
+		Name:  "refresh_tokens",
+		After: after,
+		Count: `SELECT COUNT(*) FROM refresh_tokens WHERE expires_at < $1 OR (revoked = TRUE AND created_at < $1)`,
+		Purge: `DELETE FROM refresh_tokens WHERE expires_at < $1 OR (revoked = TRUE AND created_at < $1)`,
+	}
+}
+
+// ExpiredBlacklist removes blacklist entries whose tokens expired before the cutoff
+func ExpiredBlacklist(after time.Duration) Policy {
+	return Policy{
+		Name:  "token_blacklist",
+		After: after,
+		Count: `SELECT COUNT(*) FROM token_blacklist WHERE expires_at < $1`,
+		Purge: `DELETE FROM token_blacklist WHERE expires_at < $1`,
+	}
+}
+
+// ExpiredPhoneCodes removes phone verification codes that expired before the cutoff
+func ExpiredPhoneCodes(after time.Duration) Policy {
+	return Policy{
+		Name:  "phone_codes",
+		After: after,
+		Count: `SELECT COUNT(*) FROM phone_codes WHERE expires_at < $1`,
+		Purge: `DELETE FROM phone_codes WHERE expires_at < $1`,
+	}
+}
+
+// ExpiredSecurityTokens removes sign-in alert and password reset links that
+// expired before the cutoff
+func ExpiredSecurityTokens(after time.Duration) Policy {
+	return Policy{
+		Name:  "security_tokens",
+		After: after,
+		Count: `SELECT COUNT(*) FROM security_tokens WHERE expires_at < $1`,
+		Purge: `DELETE FROM security_tokens WHERE expires_at < $1`,
+	}
+}
+
+// RoleChanges removes the audit trail of role changes made before the cutoff
+func RoleChanges(after time.Duration) Policy {
+	return Policy{
+		Name:  "role_changes",
+		After: after,
+		Count: `SELECT COUNT(*) FROM role_changes WHERE created_at < $1`,
+		Purge: `DELETE FROM role_changes WHERE created_at < $1`,
+	}
+}
@@ -0,0 +1,95 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRow struct {
+	n   int64
+	err error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	*(dest[0].(*int64)) = r.n
+	return nil
+}
+
+type fakeDB struct {
+	execs   int
+	queries int
+	rows    int64
+	err     error
+}
+
+func (f *fakeDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	f.execs++
+	if f.err != nil {
+		return pgconn.CommandTag{}, f.err
+	}
+	return pgconn.NewCommandTag("DELETE " + strconv.FormatInt(f.rows, 10)), nil
+}
+
+func (f *fakeDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	f.queries++
+	return fakeRow{n: f.rows, err: f.err}
+}
+
+func testLogger() *logrus.Entry {
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	return logrus.NewEntry(l)
+}
+
+func TestRunner_RunOnce(t *testing.T) {
+	db := &fakeDB{rows: 5}
+	r := NewRunner(db, testLogger(), false, StaleRefreshTokens(time.Hour), ExpiredBlacklist(0))
+
+	result, err := r.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"refresh_tokens": 5}, result)
+	assert.Equal(t, 1, db.execs)
+	assert.Equal(t, 0, db.queries)
+}
+
+func TestRunner_RunOnce_DryRun(t *testing.T) {
+	db := &fakeDB{rows: 2}
+	r := NewRunner(db, testLogger(), true, StaleRefreshTokens(time.Hour), ExpiredBlacklist(time.Hour))
+
+	result, err := r.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result["token_blacklist"])
+	assert.Equal(t, 0, db.execs)
+	assert.Equal(t, 2, db.queries)
+}
+
+func TestRunner_RunOnce_Error(t *testing.T) {
+	db := &fakeDB{err: errors.New("boom")}
+	r := NewRunner(db, testLogger(), false, ExpiredBlacklist(time.Hour))
+
+	_, err := r.RunOnce(context.Background())
+	assert.Error(t, err)
+}
+
+func TestRunner_RunOnce_RoleChanges(t *testing.T) {
+	db := &fakeDB{rows: 4}
+	r := NewRunner(db, testLogger(), false, RoleChanges(365*24*time.Hour), RoleChanges(0))
+
+	result, err := r.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"role_changes": 4}, result)
+	assert.Equal(t, 1, db.execs)
+}
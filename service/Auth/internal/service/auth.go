@@ -3,11 +3,18 @@ package service
 import (
 	"context"
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/Zifeldev/marketback/service/Auth/internal/config"
+	"github.com/Zifeldev/marketback/service/Auth/internal/fingerprint"
+	"github.com/Zifeldev/marketback/service/Auth/internal/keyring"
+	"github.com/Zifeldev/marketback/service/Auth/internal/metrics"
 	"github.com/Zifeldev/marketback/service/Auth/internal/models"
 	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
 	"github.com/golang-jwt/jwt/v5"
@@ -17,41 +24,75 @@ import (
 var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrInvalidToken       = errors.New("invalid token")
+	// ErrClientMismatch is returned when a bound refresh token is used by
+	// another client and binding is enforced
+	ErrClientMismatch = errors.New("refresh token was issued to another client")
+	// ErrPasswordResetRequired is returned for a correct password that must
+	// be reset before it can be used again
+	ErrPasswordResetRequired = errors.New("password reset required")
+	// ErrPasswordChangeRequired is returned for a correct temporary
+	// password that must be changed before signing in
+	ErrPasswordChangeRequired = errors.New("password change required")
+	// ErrEmailNotVerified is returned for a correct password of an account
+	// whose email must be verified before signing in
+	ErrEmailNotVerified = errors.New("email not verified")
+	// ErrAccountSuspended is returned for a suspended account
+	ErrAccountSuspended = errors.New("account suspended")
+	// ErrTokenReused is returned for a refresh token that was already
+	// rotated, a sign it was stolen; every token rotated from it has been
+	// revoked
+	ErrTokenReused = errors.New("refresh token reused")
 )
 
 type AuthService interface {
-	Register(ctx context.Context, email, password, role string) (*models.TokenPair, error)
+	Register(ctx context.Context, email, password string) (*models.TokenPair, error)
 	Login(ctx context.Context, email, password string) (*models.TokenPair, error)
 	RefreshTokens(ctx context.Context, refreshToken string) (*models.TokenPair, error)
 	RevokeToken(ctx context.Context, refreshToken string) error
 	ValidateAccessToken(tokenString string) (*models.AccessTokenClaims, error)
+	IssueTokens(ctx context.Context, userID int64) (*models.TokenPair, error)
+	// RevokeAccessToken blacklists an access token until it expires
+	RevokeAccessToken(ctx context.Context, accessToken, reason string) error
+	// AccessTokenRevoked reports whether a valid access token is on the
+	// blacklist, predates a revocation of all the user's sessions, or
+	// belongs to a suspended or deleted user
+	AccessTokenRevoked(ctx context.Context, claims *models.AccessTokenClaims) (bool, error)
+	// Introspect reports whether an access or refresh token is still
+	// active. Invalid tokens are not an error, they are reported inactive.
+	Introspect(ctx context.Context, token, tokenTypeHint string) (*models.IntrospectionResponse, error)
+	// IssueScopedToken signs an access token (without refresh token) for a
+	// subset of the granted scopes
+	IssueScopedToken(ctx context.Context, userID int64, granted []string, scope string) (*models.ScopedTokenResponse, error)
 }
 
 type authService struct {
-	cfg       *config.JWTConfig
-	userRepo  repository.UserRepository
-	tokenRepo repository.TokenRepository
+	cfg           *config.JWTConfig
+	userRepo      repository.UserRepository
+	tokenRepo     repository.TokenRepository
+	blacklistRepo repository.BlacklistRepository
+	// keys signs access tokens with RS256; without it they are signed with
+	// the HS256 secret
+	keys *keyring.Ring
 }
 
-func NewAuthService(cfg *config.JWTConfig, userRepo repository.UserRepository, tokenRepo repository.TokenRepository) AuthService {
+func NewAuthService(cfg *config.JWTConfig, userRepo repository.UserRepository, tokenRepo repository.TokenRepository, blacklistRepo repository.BlacklistRepository, keys *keyring.Ring) AuthService {
 	return &authService{
-		cfg:       cfg,
-		userRepo:  userRepo,
-		tokenRepo: tokenRepo,
+		cfg:           cfg,
+		userRepo:      userRepo,
+		tokenRepo:     tokenRepo,
+		blacklistRepo: blacklistRepo,
+		keys:          keys,
 	}
 }
 
-func (s *authService) Register(ctx context.Context, email, password, role string) (*models.TokenPair, error) {
+func (s *authService) Register(ctx context.Context, email, password string) (*models.TokenPair, error) {
 	// Hash password
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, err
 	}
 
-	if role == "" {
-		role = models.DefaultRole()
-	}
-
+	role := models.DefaultRole()
 	user, err := s.userRepo.CreateWithRole(ctx, email, string(passwordHash), role)
 	if err != nil {
 		return nil, err
@@ -59,7 +100,7 @@ func (s *authService) Register(ctx context.Context, email, password, role string
 
 	user.Role = role
 
-	return s.generateTokenPair(ctx, user)
+	return s.generateTokenPair(ctx, user, nil)
 }
 
 func (s *authService) Login(ctx context.Context, email, password string) (*models.TokenPair, error) {
@@ -75,7 +116,29 @@ func (s *authService) Login(ctx context.Context, email, password string) (*model
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
 		return nil, ErrInvalidCredentials
 	}
-	return s.generateTokenPair(ctx, user)
+	if err := s.checkSignIn(user); err != nil {
+		return nil, err
+	}
+	return s.generateTokenPair(ctx, user, nil)
+}
+
+// checkSignIn returns why a user whose identity was proven may not sign in
+// yet. It applies to every sign-in method, so a locked-down account cannot
+// be entered through a linked Google account or phone either.
+func (s *authService) checkSignIn(user *models.User) error {
+	if user.Suspended {
+		return ErrAccountSuspended
+	}
+	if user.PasswordResetRequired {
+		return ErrPasswordResetRequired
+	}
+	if user.PasswordChangeRequired {
+		return ErrPasswordChangeRequired
+	}
+	if s.cfg.RequireVerifiedEmail && !user.EmailVerified {
+		return ErrEmailNotVerified
+	}
+	return nil
 }
 
 func (s *authService) RefreshTokens(ctx context.Context, refreshToken string) (*models.TokenPair, error) {
@@ -86,10 +149,17 @@ func (s *authService) RefreshTokens(ctx context.Context, refreshToken string) (*
 	}
 
 	storedToken, err := s.tokenRepo.GetRefreshToken(ctx, refreshToken)
+	if errors.Is(err, repository.ErrTokenRevoked) {
+		return nil, s.revokeReusedFamily(ctx, refreshToken)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.checkBinding(ctx, storedToken); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.GetByID(ctx, storedToken.UserID)
 	if err != nil {
 		return nil, err
@@ -98,7 +168,58 @@ func (s *authService) RefreshTokens(ctx context.Context, refreshToken string) (*
 	if err := s.tokenRepo.RevokeRefreshToken(ctx, refreshToken); err != nil {
 		return nil, err
 	}
-	return s.generateTokenPair(ctx, user)
+	return s.generateTokenPair(ctx, user, storedToken)
+}
+
+// revokeReusedFamily handles a revoked refresh token presented again. When
+// it was revoked by rotation, whoever holds its successor may be a thief,
+// so the successors are revoked and ErrTokenReused is returned. Tokens
+// revoked otherwise, e.g. by logout, are only refused.
+func (s *authService) revokeReusedFamily(ctx context.Context, refreshToken string) error {
+	userID, err := s.tokenRepo.RevokeTokenFamily(ctx, refreshToken)
+	if errors.Is(err, repository.ErrTokenNotFound) {
+		return repository.ErrTokenRevoked
+	}
+	if err != nil {
+		return err
+	}
+
+	metrics.RefreshTokenReuseTotal.Inc()
+	return fmt.Errorf("%w: token family of user %d revoked", ErrTokenReused, userID)
+}
+
+// checkBinding compares the requesting client with the one the refresh token
+// was issued to. Tokens without a fingerprint are not bound.
+func (s *authService) checkBinding(ctx context.Context, stored *models.RefreshToken) error {
+	mode := s.cfg.RefreshBinding
+	if mode == "" || mode == fingerprint.ModeOff || stored.Fingerprint == "" {
+		return nil
+	}
+
+	presented := fingerprint.FromContext(ctx)
+	if subtle.ConstantTimeCompare([]byte(presented), []byte(stored.Fingerprint)) == 1 {
+		return nil
+	}
+
+	metrics.RefreshBindingMismatchTotal.WithLabelValues(mode).Inc()
+	if mode == fingerprint.ModeEnforce {
+		return ErrClientMismatch
+	}
+	return nil
+}
+
+// IssueTokens signs a user in whose identity was verified by other means
+// than a password, such as a linked Google account. The account must be
+// allowed to sign in as with a password.
+func (s *authService) IssueTokens(ctx context.Context, userID int64) (*models.TokenPair, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSignIn(user); err != nil {
+		return nil, err
+	}
+	return s.generateTokenPair(ctx, user, nil)
 }
 
 func (s *authService) RevokeToken(ctx context.Context, refreshToken string) error {
@@ -106,12 +227,12 @@ func (s *authService) RevokeToken(ctx context.Context, refreshToken string) erro
 }
 
 func (s *authService) ValidateAccessToken(tokenString string) (*models.AccessTokenClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
-		}
-		return []byte(s.cfg.AccessSecret), nil
-	})
+	var opts []jwt.ParserOption
+	if s.cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(s.cfg.Audience))
+	}
+
+	token, err := jwt.Parse(tokenString, s.verificationKey, opts...)
 
 	if err != nil || !token.Valid {
 		return nil, ErrInvalidToken
@@ -137,15 +258,181 @@ func (s *authService) ValidateAccessToken(tokenString string) (*models.AccessTok
 		role = models.RoleUser
 	}
 
+	// Tokens issued before jti was added have none and cannot be revoked
+	jti, _ := claims["jti"].(string)
+	iss, _ := claims["iss"].(string)
+	iat, _ := claims["iat"].(float64)
+	exp, _ := claims["exp"].(float64)
+	scope, _ := claims["scope"].(string)
+	sid, _ := claims["sid"].(string)
+
 	return &models.AccessTokenClaims{
-		UserID: int64(userID),
-		Email:  email,
-		Role:   role,
+		UserID:    int64(userID),
+		Email:     email,
+		Role:      role,
+		JTI:       jti,
+		Issuer:    iss,
+		IssuedAt:  int64(iat),
+		ExpiresAt: int64(exp),
+		SessionID: sid,
+		Scopes:    models.ParseScope(scope),
 	}, nil
 }
 
-func (s *authService) generateTokenPair(ctx context.Context, user *models.User) (*models.TokenPair, error) {
-	accessToken, err := s.generateAccessToken(user)
+func (s *authService) IssueScopedToken(ctx context.Context, userID int64, granted []string, scope string) (*models.ScopedTokenResponse, error) {
+	scopes, err := models.NarrowScopes(granted, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	// The role may have changed since the presented token was issued
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	scopes, err = models.NarrowScopes(models.ScopesForRole(user.Role), models.FormatScope(scopes))
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.generateAccessToken(user, scopes, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ScopedTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.cfg.AccessExpiration.Seconds()),
+		Scope:       models.FormatScope(scopes),
+	}, nil
+}
+
+func (s *authService) RevokeAccessToken(ctx context.Context, accessToken, reason string) error {
+	claims, err := s.ValidateAccessToken(accessToken)
+	if err != nil {
+		return err
+	}
+	if claims.JTI == "" {
+		return nil
+	}
+	return s.blacklistRepo.Add(ctx, claims.JTI, claims.UserID, time.Unix(claims.ExpiresAt, 0), reason)
+}
+
+func (s *authService) AccessTokenRevoked(ctx context.Context, claims *models.AccessTokenClaims) (bool, error) {
+	if claims.JTI != "" {
+		revoked, err := s.blacklistRepo.IsBlacklisted(ctx, claims.JTI)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+	if user.Suspended {
+		return true, nil
+	}
+	// iat has second precision, so a token issued in the same second as
+	// the revocation counts as revoked
+	return user.SessionsRevokedAt != nil && claims.IssuedAt <= user.SessionsRevokedAt.Unix(), nil
+}
+
+func (s *authService) Introspect(ctx context.Context, token, tokenTypeHint string) (*models.IntrospectionResponse, error) {
+	first, second := s.introspectAccessToken, s.introspectRefreshToken
+	if tokenTypeHint == models.TokenTypeRefresh {
+		first, second = second, first
+	}
+
+	// The hint only decides which lookup runs first (RFC 7662, section 2.1)
+	resp, err := first(ctx, token)
+	if err != nil || resp.Active {
+		return resp, err
+	}
+	return second(ctx, token)
+}
+
+func (s *authService) introspectAccessToken(ctx context.Context, token string) (*models.IntrospectionResponse, error) {
+	claims, err := s.ValidateAccessToken(token)
+	if err != nil {
+		return &models.IntrospectionResponse{}, nil
+	}
+
+	revoked, err := s.AccessTokenRevoked(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return &models.IntrospectionResponse{}, nil
+	}
+
+	return &models.IntrospectionResponse{
+		Active:    true,
+		TokenType: models.TokenTypeAccess,
+		Subject:   strconv.FormatInt(claims.UserID, 10),
+		UserID:    claims.UserID,
+		Email:     claims.Email,
+		Role:      claims.Role,
+		Scope:     models.FormatScope(claims.Scopes),
+		Issuer:    claims.Issuer,
+		IssuedAt:  claims.IssuedAt,
+		ExpiresAt: claims.ExpiresAt,
+		JTI:       claims.JTI,
+	}, nil
+}
+
+func (s *authService) introspectRefreshToken(ctx context.Context, token string) (*models.IntrospectionResponse, error) {
+	stored, err := s.tokenRepo.GetRefreshToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, repository.ErrTokenNotFound) || errors.Is(err, repository.ErrTokenRevoked) || errors.Is(err, repository.ErrTokenExpired) {
+			return &models.IntrospectionResponse{}, nil
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return &models.IntrospectionResponse{}, nil
+		}
+		return nil, err
+	}
+
+	return &models.IntrospectionResponse{
+		Active:    true,
+		TokenType: models.TokenTypeRefresh,
+		Subject:   strconv.FormatInt(user.ID, 10),
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		Scope:     models.FormatScope(models.ScopesForRole(user.Role)),
+		Issuer:    s.cfg.Issuer,
+		IssuedAt:  stored.CreatedAt.Unix(),
+		ExpiresAt: stored.ExpiresAt.Unix(),
+	}, nil
+}
+
+// generateTokenPair issues tokens rotated from parent, within its session;
+// a nil parent starts a new session (a sign-in)
+func (s *authService) generateTokenPair(ctx context.Context, user *models.User, parent *models.RefreshToken) (*models.TokenPair, error) {
+	var sessionID string
+	var parentID int64
+	if parent != nil {
+		sessionID, parentID = parent.SessionID, parent.ID
+	}
+	if sessionID == "" {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return nil, err
+		}
+		sessionID = hex.EncodeToString(id)
+	}
+
+	accessToken, err := s.generateAccessToken(user, models.ScopesForRole(user.Role), sessionID)
 	if err != nil {
 		return nil, err
 	}
@@ -155,8 +442,10 @@ func (s *authService) generateTokenPair(ctx context.Context, user *models.User)
 		return nil, err
 	}
 
+	// The client is shown in the user's list of sessions
+	client, _ := fingerprint.ClientFromContext(ctx)
 	expiresAt := time.Now().Add(s.cfg.RefreshExpiration)
-	_, err = s.tokenRepo.CreateRefreshToken(ctx, user.ID, refreshToken, expiresAt)
+	_, err = s.tokenRepo.CreateRefreshToken(ctx, user.ID, refreshToken, expiresAt, fingerprint.FromContext(ctx), sessionID, client.UserAgent, client.IP, parentID)
 	if err != nil {
 		return nil, err
 	}
@@ -165,24 +454,71 @@ func (s *authService) generateTokenPair(ctx context.Context, user *models.User)
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		ExpiresIn:    int64(s.cfg.AccessExpiration.Seconds()),
+		UserID:       user.ID,
+		SessionID:    sessionID,
 	}, nil
 }
 
-func (s *authService) generateAccessToken(user *models.User) (string, error) {
+// generateAccessToken signs an access token; sessionID names the sign-in
+// in the sid claim and is empty for scoped tokens
+func (s *authService) generateAccessToken(user *models.User, scopes []string, sessionID string) (string, error) {
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+
+	// sub, aud, jti and sid are the standard (OIDC) claims; user_id is kept
+	// for clients that read it
 	now := time.Now()
 	claims := jwt.MapClaims{
+		"sub":     strconv.FormatInt(user.ID, 10),
 		"user_id": user.ID,
 		"email":   user.Email,
 		"role":    user.Role,
 		"iss":     s.cfg.Issuer,
 		"iat":     now.Unix(),
 		"exp":     now.Add(s.cfg.AccessExpiration).Unix(),
+		"jti":     hex.EncodeToString(jti),
+		"scope":   models.FormatScope(scopes),
+	}
+	if s.cfg.Audience != "" {
+		claims["aud"] = []string{s.cfg.Audience}
+	}
+	if sessionID != "" {
+		claims["sid"] = sessionID
 	}
 
+	if s.keys != nil {
+		return s.keys.Sign(claims)
+	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(s.cfg.AccessSecret))
 }
 
+// verificationKey picks the key of an access token: RS256 tokens name
+// theirs in the kid header, HS256 ones are accepted while the secret is
+// still configured.
+func (s *authService) verificationKey(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA:
+		if s.keys == nil || token.Method != jwt.SigningMethodRS256 {
+			return nil, ErrInvalidToken
+		}
+		kid, _ := token.Header["kid"].(string)
+		key, ok := s.keys.PublicKey(kid)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+		return key, nil
+	case *jwt.SigningMethodHMAC:
+		if s.cfg.AccessSecret == "" {
+			return nil, ErrInvalidToken
+		}
+		return []byte(s.cfg.AccessSecret), nil
+	}
+	return nil, ErrInvalidToken
+}
+
 func (s *authService) generateRefreshToken() (string, error) {
 	b := make([]byte, 32)
 	if _, err := rand.Read(b); err != nil {
@@ -2,13 +2,18 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/Zifeldev/marketback/service/Auth/internal/config"
+	"github.com/Zifeldev/marketback/service/Auth/internal/fingerprint"
+	"github.com/Zifeldev/marketback/service/Auth/internal/keyring"
 	"github.com/Zifeldev/marketback/service/Auth/internal/models"
 	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -41,17 +46,21 @@ func (m *mockUserRepo) Delete(ctx context.Context, id int64) error {
 func (m *mockUserRepo) List(ctx context.Context, limit, offset int) ([]*models.User, error) {
 	return nil, errors.New("not implemented")
 }
+func (m *mockUserRepo) ListByIDs(ctx context.Context, ids []int64) ([]*models.User, error) {
+	return nil, errors.New("not implemented")
+}
 
 type mockTokenRepo struct {
-	createFn       func(ctx context.Context, userID int64, token string, expiresAt time.Time) (*models.RefreshToken, error)
+	createFn       func(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error)
 	getFn          func(ctx context.Context, token string) (*models.RefreshToken, error)
 	revokeFn       func(ctx context.Context, token string) error
 	revokeAllFn    func(ctx context.Context, userID int64) error
+	revokeFamilyFn func(ctx context.Context, token string) (int64, error)
 	cleanupExpired func(ctx context.Context) error
 }
 
-func (m *mockTokenRepo) CreateRefreshToken(ctx context.Context, userID int64, token string, expiresAt time.Time) (*models.RefreshToken, error) {
-	return m.createFn(ctx, userID, token, expiresAt)
+func (m *mockTokenRepo) CreateRefreshToken(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
+	return m.createFn(ctx, userID, token, expiresAt, fingerprint, sessionID, userAgent, ip, parentID)
 }
 func (m *mockTokenRepo) GetRefreshToken(ctx context.Context, token string) (*models.RefreshToken, error) {
 	return m.getFn(ctx, token)
@@ -59,9 +68,18 @@ func (m *mockTokenRepo) GetRefreshToken(ctx context.Context, token string) (*mod
 func (m *mockTokenRepo) RevokeRefreshToken(ctx context.Context, token string) error {
 	return m.revokeFn(ctx, token)
 }
+func (m *mockTokenRepo) RevokeTokenFamily(ctx context.Context, token string) (int64, error) {
+	return m.revokeFamilyFn(ctx, token)
+}
 func (m *mockTokenRepo) RevokeAllUserTokens(ctx context.Context, userID int64) error {
 	return m.revokeAllFn(ctx, userID)
 }
+func (m *mockTokenRepo) RevokeSession(ctx context.Context, userID int64, sessionID string) error {
+	return nil
+}
+func (m *mockTokenRepo) ListSessions(ctx context.Context, userID int64) ([]*models.Session, error) {
+	return nil, nil
+}
 func (m *mockTokenRepo) CleanupExpiredTokens(ctx context.Context) error { return m.cleanupExpired(ctx) }
 
 // --- Helpers ---
@@ -72,7 +90,6 @@ func testConfig() *config.JWTConfig {
 		AccessExpiration:  15 * time.Minute,
 		RefreshExpiration: 7 * 24 * time.Hour,
 		Issuer:            "test-issuer",
-		FirstAdminEmail:   "admin@example.com",
 	}
 }
 
@@ -84,49 +101,52 @@ func TestAuthService_Register_DefaultRole(t *testing.T) {
 		capturedRole = role
 		return &models.User{ID: 10, Email: email, Role: role, PasswordHash: passHash, CreatedAt: time.Now()}, nil
 	}}
-	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time) (*models.RefreshToken, error) {
+	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
 		return &models.RefreshToken{ID: 1, UserID: userID, Token: token, ExpiresAt: expiresAt}, nil
 	}, getFn: func(ctx context.Context, token string) (*models.RefreshToken, error) {
 		return nil, repository.ErrTokenNotFound
 	}, revokeFn: func(ctx context.Context, token string) error { return nil }, revokeAllFn: func(ctx context.Context, userID int64) error { return nil }, cleanupExpired: func(ctx context.Context) error { return nil }}
 
-	svc := NewAuthService(cfg, uRepo, tRepo)
-	tp, err := svc.Register(context.Background(), "user@example.com", "pass123", "")
+	svc := NewAuthService(cfg, uRepo, tRepo, &fakeBlacklistRepo{}, nil)
+	tp, err := svc.Register(context.Background(), "user@example.com", "pass123")
 	require.NoError(t, err)
 	require.NotNil(t, tp)
 	require.Equal(t, models.RoleUser, capturedRole)
 }
 
-func TestAuthService_Register_ExplicitRole(t *testing.T) {
+func TestAuthService_Register_NeverGrantsPrivilegedRole(t *testing.T) {
 	cfg := testConfig()
 	uRepo := &mockUserRepo{createWithRoleFn: func(ctx context.Context, email, passHash, role string) (*models.User, error) {
 		return &models.User{ID: 11, Email: email, Role: role, PasswordHash: passHash}, nil
 	}}
-	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time) (*models.RefreshToken, error) {
+	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
 		return &models.RefreshToken{ID: 2, UserID: userID, Token: token, ExpiresAt: expiresAt}, nil
 	}, getFn: func(ctx context.Context, token string) (*models.RefreshToken, error) {
 		return nil, repository.ErrTokenNotFound
 	}, revokeFn: func(ctx context.Context, token string) error { return nil }, revokeAllFn: func(ctx context.Context, userID int64) error { return nil }, cleanupExpired: func(ctx context.Context) error { return nil }}
-	svc := NewAuthService(cfg, uRepo, tRepo)
-	tp, err := svc.Register(context.Background(), "seller@example.com", "pass123", models.RoleSeller)
+	svc := NewAuthService(cfg, uRepo, tRepo, &fakeBlacklistRepo{}, nil)
+	tp, err := svc.Register(context.Background(), "seller@example.com", "pass123")
 	require.NoError(t, err)
-	// We don't decode JWT here; just ensure token pair produced and role captured by mock user
 	require.NotNil(t, tp)
+
+	claims, err := svc.ValidateAccessToken(tp.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, models.RoleUser, claims.Role)
 }
 
-func TestAuthService_Register_TokenContainsSellerRole(t *testing.T) {
+func TestAuthService_IssueTokens_ContainsSellerRole(t *testing.T) {
 	cfg := testConfig()
-	uRepo := &mockUserRepo{createWithRoleFn: func(ctx context.Context, email, passHash, role string) (*models.User, error) {
-		return &models.User{ID: 100, Email: email, Role: role, PasswordHash: passHash}, nil
+	uRepo := &mockUserRepo{getByIDFn: func(ctx context.Context, id int64) (*models.User, error) {
+		return &models.User{ID: id, Email: "seller.jwt@example.com", Role: models.RoleSeller, PasswordHash: "hash"}, nil
 	}}
-	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time) (*models.RefreshToken, error) {
+	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
 		return &models.RefreshToken{ID: 200, UserID: userID, Token: token, ExpiresAt: expiresAt}, nil
 	}, getFn: func(ctx context.Context, token string) (*models.RefreshToken, error) {
 		return nil, repository.ErrTokenNotFound
 	}, revokeFn: func(ctx context.Context, token string) error { return nil }, revokeAllFn: func(ctx context.Context, userID int64) error { return nil }, cleanupExpired: func(ctx context.Context) error { return nil }}
 
-	svc := NewAuthService(cfg, uRepo, tRepo)
-	tp, err := svc.Register(context.Background(), "seller.jwt@example.com", "pass12345", models.RoleSeller)
+	svc := NewAuthService(cfg, uRepo, tRepo, &fakeBlacklistRepo{}, nil)
+	tp, err := svc.IssueTokens(context.Background(), 100)
 	require.NoError(t, err)
 	require.NotNil(t, tp)
 	require.NotEmpty(t, tp.AccessToken)
@@ -142,13 +162,13 @@ func TestAuthService_Register_UserExists(t *testing.T) {
 	uRepo := &mockUserRepo{createWithRoleFn: func(ctx context.Context, email, passHash, role string) (*models.User, error) {
 		return nil, repository.ErrUserExists
 	}}
-	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time) (*models.RefreshToken, error) {
+	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
 		return nil, errors.New("should not be called")
 	}, getFn: func(ctx context.Context, token string) (*models.RefreshToken, error) {
 		return nil, repository.ErrTokenNotFound
 	}, revokeFn: func(ctx context.Context, token string) error { return nil }, revokeAllFn: func(ctx context.Context, userID int64) error { return nil }, cleanupExpired: func(ctx context.Context) error { return nil }}
-	svc := NewAuthService(cfg, uRepo, tRepo)
-	tp, err := svc.Register(context.Background(), "exists@example.com", "pass123", "")
+	svc := NewAuthService(cfg, uRepo, tRepo, &fakeBlacklistRepo{}, nil)
+	tp, err := svc.Register(context.Background(), "exists@example.com", "pass123")
 	require.Error(t, err)
 	require.Nil(t, tp)
 	require.ErrorIs(t, err, repository.ErrUserExists)
@@ -162,12 +182,12 @@ func TestAuthService_Login_Success(t *testing.T) {
 	}, createWithRoleFn: func(ctx context.Context, email, passHash, role string) (*models.User, error) {
 		return nil, errors.New("unused")
 	}, getByIDFn: func(ctx context.Context, id int64) (*models.User, error) { return nil, errors.New("unused") }}
-	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time) (*models.RefreshToken, error) {
+	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
 		return &models.RefreshToken{ID: 3, UserID: userID, Token: token, ExpiresAt: expiresAt}, nil
 	}, getFn: func(ctx context.Context, token string) (*models.RefreshToken, error) {
 		return nil, repository.ErrTokenNotFound
 	}, revokeFn: func(ctx context.Context, token string) error { return nil }, revokeAllFn: func(ctx context.Context, userID int64) error { return nil }, cleanupExpired: func(ctx context.Context) error { return nil }}
-	svc := NewAuthService(cfg, uRepo, tRepo)
+	svc := NewAuthService(cfg, uRepo, tRepo, &fakeBlacklistRepo{}, nil)
 	tp, err := svc.Login(context.Background(), "user@example.com", "pass123")
 	require.NoError(t, err)
 	require.NotEmpty(t, tp.AccessToken)
@@ -182,12 +202,12 @@ func TestAuthService_Login_InvalidCredentials(t *testing.T) {
 	}, createWithRoleFn: func(ctx context.Context, email, passHash, role string) (*models.User, error) {
 		return nil, errors.New("unused")
 	}, getByIDFn: func(ctx context.Context, id int64) (*models.User, error) { return nil, errors.New("unused") }}
-	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time) (*models.RefreshToken, error) {
+	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
 		return &models.RefreshToken{}, nil
 	}, getFn: func(ctx context.Context, token string) (*models.RefreshToken, error) {
 		return nil, repository.ErrTokenNotFound
 	}, revokeFn: func(ctx context.Context, token string) error { return nil }, revokeAllFn: func(ctx context.Context, userID int64) error { return nil }, cleanupExpired: func(ctx context.Context) error { return nil }}
-	svc := NewAuthService(cfg, uRepo, tRepo)
+	svc := NewAuthService(cfg, uRepo, tRepo, &fakeBlacklistRepo{}, nil)
 	tp, err := svc.Login(context.Background(), "user@example.com", "wrongpass")
 	require.Error(t, err)
 	require.Nil(t, tp)
@@ -202,13 +222,13 @@ func TestAuthService_RefreshTokens_Success(t *testing.T) {
 	tRepo := &mockTokenRepo{
 		getFn:    func(ctx context.Context, token string) (*models.RefreshToken, error) { return oldRT, nil },
 		revokeFn: func(ctx context.Context, token string) error { oldRT.Revoked = true; return nil },
-		createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time) (*models.RefreshToken, error) {
+		createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
 			return &models.RefreshToken{ID: 6, UserID: userID, Token: token, ExpiresAt: expiresAt}, nil
 		},
 		revokeAllFn:    func(ctx context.Context, userID int64) error { return nil },
 		cleanupExpired: func(ctx context.Context) error { return nil },
 	}
-	svc := NewAuthService(cfg, uRepo, tRepo)
+	svc := NewAuthService(cfg, uRepo, tRepo, &fakeBlacklistRepo{}, nil)
 	tp, err := svc.RefreshTokens(context.Background(), "oldtoken")
 	require.NoError(t, err)
 	require.NotNil(t, tp)
@@ -222,15 +242,63 @@ func TestAuthService_RefreshTokens_Invalid(t *testing.T) {
 	}}
 	tRepo := &mockTokenRepo{getFn: func(ctx context.Context, token string) (*models.RefreshToken, error) {
 		return nil, repository.ErrTokenNotFound
-	}, createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time) (*models.RefreshToken, error) {
+	}, createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
 		return nil, errors.New("unused")
 	}, revokeFn: func(ctx context.Context, token string) error { return nil }, revokeAllFn: func(ctx context.Context, userID int64) error { return nil }, cleanupExpired: func(ctx context.Context) error { return nil }}
-	svc := NewAuthService(cfg, uRepo, tRepo)
+	svc := NewAuthService(cfg, uRepo, tRepo, &fakeBlacklistRepo{}, nil)
 	tp, err := svc.RefreshTokens(context.Background(), "badtoken")
 	require.Error(t, err)
 	require.Nil(t, tp)
 }
 
+func TestAuthService_RefreshTokens_Reused(t *testing.T) {
+	cfg := testConfig()
+	uRepo := &mockUserRepo{getByIDFn: func(ctx context.Context, id int64) (*models.User, error) { return nil, errors.New("unused") }}
+	rotated := map[string]bool{"rotated": true}
+	var revokedFamilies []string
+	tRepo := &mockTokenRepo{getFn: func(ctx context.Context, token string) (*models.RefreshToken, error) {
+		return nil, repository.ErrTokenRevoked
+	}, revokeFamilyFn: func(ctx context.Context, token string) (int64, error) {
+		if !rotated[token] {
+			return 0, repository.ErrTokenNotFound
+		}
+		revokedFamilies = append(revokedFamilies, token)
+		return 33, nil
+	}}
+	svc := NewAuthService(cfg, uRepo, tRepo, &fakeBlacklistRepo{}, nil)
+
+	_, err := svc.RefreshTokens(context.Background(), "rotated")
+	require.ErrorIs(t, err, ErrTokenReused)
+	require.Equal(t, []string{"rotated"}, revokedFamilies)
+
+	_, err = svc.RefreshTokens(context.Background(), "logged-out")
+	require.ErrorIs(t, err, repository.ErrTokenRevoked, "tokens revoked by logout are only refused")
+	require.NotErrorIs(t, err, ErrTokenReused)
+}
+
+func TestAuthService_RefreshTokens_LinksParent(t *testing.T) {
+	cfg := testConfig()
+	user := &models.User{ID: 33, Email: "u@example.com", Role: models.RoleUser}
+	uRepo := &mockUserRepo{getByIDFn: func(ctx context.Context, id int64) (*models.User, error) { return user, nil }}
+	oldRT := &models.RefreshToken{ID: 5, UserID: user.ID, Token: "oldtoken", SessionID: "laptop", ExpiresAt: time.Now().Add(time.Hour)}
+	var gotSession string
+	var gotParent int64
+	tRepo := &mockTokenRepo{
+		getFn:    func(ctx context.Context, token string) (*models.RefreshToken, error) { return oldRT, nil },
+		revokeFn: func(ctx context.Context, token string) error { return nil },
+		createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
+			gotSession, gotParent = sessionID, parentID
+			return &models.RefreshToken{ID: 6, UserID: userID, Token: token, ExpiresAt: expiresAt}, nil
+		},
+	}
+	svc := NewAuthService(cfg, uRepo, tRepo, &fakeBlacklistRepo{}, nil)
+
+	_, err := svc.RefreshTokens(context.Background(), "oldtoken")
+	require.NoError(t, err)
+	require.Equal(t, "laptop", gotSession)
+	require.Equal(t, int64(5), gotParent)
+}
+
 func TestAuthService_RevokeToken(t *testing.T) {
 	cfg := testConfig()
 	uRepo := &mockUserRepo{createWithRoleFn: func(ctx context.Context, email, passHash, role string) (*models.User, error) {
@@ -243,15 +311,348 @@ func TestAuthService_RevokeToken(t *testing.T) {
 	revoked := false
 	tRepo := &mockTokenRepo{revokeFn: func(ctx context.Context, token string) error { revoked = true; return nil }, getFn: func(ctx context.Context, token string) (*models.RefreshToken, error) {
 		return nil, repository.ErrTokenNotFound
-	}, createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time) (*models.RefreshToken, error) {
+	}, createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
 		return &models.RefreshToken{}, nil
 	}, revokeAllFn: func(ctx context.Context, userID int64) error { return nil }, cleanupExpired: func(ctx context.Context) error { return nil }}
-	svc := NewAuthService(cfg, uRepo, tRepo)
+	svc := NewAuthService(cfg, uRepo, tRepo, &fakeBlacklistRepo{}, nil)
 	err := svc.RevokeToken(context.Background(), "tkn")
 	require.NoError(t, err)
 	require.True(t, revoked)
 }
 
+func TestAuthService_Introspect_AccessTokenRevokedOnLogout(t *testing.T) {
+	cfg := testConfig()
+	user := &models.User{ID: 7, Email: "buyer@example.com", Role: models.RoleUser}
+	uRepo := &mockUserRepo{getByIDFn: func(ctx context.Context, id int64) (*models.User, error) { return user, nil }}
+	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
+		return &models.RefreshToken{ID: 1, UserID: userID, Token: token, ExpiresAt: expiresAt}, nil
+	}, getFn: func(ctx context.Context, token string) (*models.RefreshToken, error) {
+		return nil, repository.ErrTokenNotFound
+	}}
+	blacklist := &fakeBlacklistRepo{}
+	svc := NewAuthService(cfg, uRepo, tRepo, blacklist, nil)
+
+	tp, err := svc.IssueTokens(context.Background(), user.ID)
+	require.NoError(t, err)
+
+	resp, err := svc.Introspect(context.Background(), tp.AccessToken, "")
+	require.NoError(t, err)
+	require.True(t, resp.Active)
+	require.Equal(t, models.TokenTypeAccess, resp.TokenType)
+	require.Equal(t, "7", resp.Subject)
+	require.Equal(t, cfg.Issuer, resp.Issuer)
+	require.NotEmpty(t, resp.JTI)
+	require.Greater(t, resp.ExpiresAt, time.Now().Unix())
+
+	require.NoError(t, svc.RevokeAccessToken(context.Background(), tp.AccessToken, "logout"))
+	require.Equal(t, "logout", blacklist.jtis[resp.JTI])
+
+	claims, err := svc.ValidateAccessToken(tp.AccessToken)
+	require.NoError(t, err)
+	revoked, err := svc.AccessTokenRevoked(context.Background(), claims)
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	resp, err = svc.Introspect(context.Background(), tp.AccessToken, models.TokenTypeAccess)
+	require.NoError(t, err)
+	require.Equal(t, &models.IntrospectionResponse{}, resp)
+}
+
+func TestAuthService_Introspect_SessionsRevoked(t *testing.T) {
+	cfg := testConfig()
+	user := &models.User{ID: 7, Email: "buyer@example.com", Role: models.RoleUser}
+	uRepo := &mockUserRepo{getByIDFn: func(ctx context.Context, id int64) (*models.User, error) { return user, nil }}
+	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
+		return &models.RefreshToken{ID: 1, UserID: userID, Token: token, ExpiresAt: expiresAt}, nil
+	}, getFn: func(ctx context.Context, token string) (*models.RefreshToken, error) {
+		return nil, repository.ErrTokenNotFound
+	}}
+	svc := NewAuthService(cfg, uRepo, tRepo, &fakeBlacklistRepo{}, nil)
+
+	tp, err := svc.IssueTokens(context.Background(), user.ID)
+	require.NoError(t, err)
+
+	revokedAt := time.Now()
+	user.SessionsRevokedAt = &revokedAt
+
+	resp, err := svc.Introspect(context.Background(), tp.AccessToken, models.TokenTypeAccess)
+	require.NoError(t, err)
+	require.False(t, resp.Active)
+}
+
+// Market's ActiveSession check introspects, so a seller banned there is
+// signed out of Market too
+func TestAuthService_Introspect_Suspended(t *testing.T) {
+	cfg := testConfig()
+	user := &models.User{ID: 7, Email: "seller@example.com", Role: models.RoleSeller}
+	uRepo := &mockUserRepo{getByIDFn: func(ctx context.Context, id int64) (*models.User, error) { return user, nil }}
+	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
+		return &models.RefreshToken{ID: 1, UserID: userID, Token: token, ExpiresAt: expiresAt}, nil
+	}, getFn: func(ctx context.Context, token string) (*models.RefreshToken, error) {
+		return nil, repository.ErrTokenNotFound
+	}}
+	svc := NewAuthService(cfg, uRepo, tRepo, &fakeBlacklistRepo{}, nil)
+
+	tp, err := svc.IssueTokens(context.Background(), user.ID)
+	require.NoError(t, err)
+
+	user.Suspended = true
+
+	resp, err := svc.Introspect(context.Background(), tp.AccessToken, models.TokenTypeAccess)
+	require.NoError(t, err)
+	require.False(t, resp.Active)
+}
+
+func TestAuthService_Introspect_RefreshToken(t *testing.T) {
+	cfg := testConfig()
+	user := &models.User{ID: 8, Email: "seller@example.com", Role: models.RoleSeller}
+	uRepo := &mockUserRepo{getByIDFn: func(ctx context.Context, id int64) (*models.User, error) { return user, nil }}
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	tRepo := &mockTokenRepo{getFn: func(ctx context.Context, token string) (*models.RefreshToken, error) {
+		switch token {
+		case "live":
+			return &models.RefreshToken{ID: 3, UserID: user.ID, Token: token, ExpiresAt: expiresAt, CreatedAt: time.Now()}, nil
+		case "revoked":
+			return nil, repository.ErrTokenRevoked
+		}
+		return nil, repository.ErrTokenNotFound
+	}}
+	svc := NewAuthService(cfg, uRepo, tRepo, &fakeBlacklistRepo{}, nil)
+
+	// A wrong hint still finds the token
+	resp, err := svc.Introspect(context.Background(), "live", models.TokenTypeAccess)
+	require.NoError(t, err)
+	require.True(t, resp.Active)
+	require.Equal(t, models.TokenTypeRefresh, resp.TokenType)
+	require.Equal(t, models.RoleSeller, resp.Role)
+	require.Equal(t, expiresAt.Unix(), resp.ExpiresAt)
+
+	for _, token := range []string{"revoked", "garbage"} {
+		resp, err = svc.Introspect(context.Background(), token, models.TokenTypeRefresh)
+		require.NoError(t, err)
+		require.False(t, resp.Active, token)
+	}
+}
+
+func TestAuthService_Introspect_DeletedUser(t *testing.T) {
+	cfg := testConfig()
+	deleted := false
+	uRepo := &mockUserRepo{getByIDFn: func(ctx context.Context, id int64) (*models.User, error) {
+		if deleted {
+			return nil, repository.ErrUserNotFound
+		}
+		return &models.User{ID: id, Email: "gone@example.com", Role: models.RoleUser}, nil
+	}}
+	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
+		return &models.RefreshToken{ID: 1, UserID: userID, Token: token, ExpiresAt: expiresAt}, nil
+	}, getFn: func(ctx context.Context, token string) (*models.RefreshToken, error) {
+		return nil, repository.ErrTokenNotFound
+	}}
+	svc := NewAuthService(cfg, uRepo, tRepo, &fakeBlacklistRepo{}, nil)
+
+	tp, err := svc.IssueTokens(context.Background(), 9)
+	require.NoError(t, err)
+
+	deleted = true
+	resp, err := svc.Introspect(context.Background(), tp.AccessToken, "")
+	require.NoError(t, err)
+	require.False(t, resp.Active)
+}
+
+func TestAuthService_AccessToken_StandardClaimsAndAudience(t *testing.T) {
+	cfg := testConfig()
+	cfg.Audience = "marketback"
+	uRepo := &mockUserRepo{getByIDFn: func(ctx context.Context, id int64) (*models.User, error) {
+		return &models.User{ID: id, Email: "aud@example.com", Role: models.RoleUser}, nil
+	}}
+	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
+		return &models.RefreshToken{ID: 1, UserID: userID, Token: token, ExpiresAt: expiresAt}, nil
+	}}
+	svc := NewAuthService(cfg, uRepo, tRepo, &fakeBlacklistRepo{}, nil)
+
+	tp, err := svc.IssueTokens(context.Background(), 12)
+	require.NoError(t, err)
+
+	claims := jwt.MapClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(tp.AccessToken, claims)
+	require.NoError(t, err)
+	require.Equal(t, "12", claims["sub"])
+	require.Equal(t, []interface{}{"marketback"}, claims["aud"])
+	require.NotEmpty(t, claims["jti"])
+
+	_, err = svc.ValidateAccessToken(tp.AccessToken)
+	require.NoError(t, err)
+
+	// A token minted for another audience is rejected
+	other := *cfg
+	other.Audience = "other-service"
+	_, err = NewAuthService(&other, uRepo, tRepo, &fakeBlacklistRepo{}, nil).ValidateAccessToken(tp.AccessToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestAuthService_SessionClient(t *testing.T) {
+	uRepo := &mockUserRepo{getByIDFn: func(ctx context.Context, id int64) (*models.User, error) {
+		return &models.User{ID: id, Email: "device@example.com", Role: models.RoleUser}, nil
+	}}
+	var stored *models.RefreshToken
+	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
+		stored = &models.RefreshToken{UserID: userID, Token: token, SessionID: sessionID, UserAgent: userAgent, IPAddress: ip}
+		return stored, nil
+	}}
+	svc := NewAuthService(testConfig(), uRepo, tRepo, &fakeBlacklistRepo{}, nil)
+
+	ctx := fingerprint.WithClient(context.Background(), fingerprint.Client{UserAgent: "Firefox", IP: "203.0.113.7"})
+	tp, err := svc.IssueTokens(ctx, 4)
+	require.NoError(t, err)
+	require.Equal(t, "Firefox", stored.UserAgent)
+	require.Equal(t, "203.0.113.7", stored.IPAddress)
+
+	// The access token names its session so it can be shown as current
+	claims, err := svc.ValidateAccessToken(tp.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, tp.SessionID, claims.SessionID)
+	require.Equal(t, stored.SessionID, claims.SessionID)
+
+	scoped, err := svc.IssueScopedToken(ctx, 4, claims.Scopes, models.ScopeAccount)
+	require.NoError(t, err)
+	claims, err = svc.ValidateAccessToken(scoped.AccessToken)
+	require.NoError(t, err)
+	require.Empty(t, claims.SessionID, "scoped tokens belong to no session")
+}
+
+func newTestRing(t *testing.T, active string, ids ...string) *keyring.Ring {
+	t.Helper()
+	keys := make([]keyring.Key, 0, len(ids))
+	for _, id := range ids {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		keys = append(keys, keyring.Key{ID: id, PrivateKey: key})
+	}
+	ring, err := keyring.New(keys, active)
+	require.NoError(t, err)
+	return ring
+}
+
+func TestAuthService_AccessToken_SigningKeys(t *testing.T) {
+	uRepo := &mockUserRepo{getByIDFn: func(ctx context.Context, id int64) (*models.User, error) {
+		return &models.User{ID: id, Email: "rs@example.com", Role: models.RoleUser}, nil
+	}}
+	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
+		return &models.RefreshToken{ID: 1, UserID: userID, Token: token, ExpiresAt: expiresAt}, nil
+	}}
+
+	// Tokens signed with the shared secret before the switch
+	hmacToken, err := NewAuthService(testConfig(), uRepo, tRepo, &fakeBlacklistRepo{}, nil).IssueTokens(context.Background(), 3)
+	require.NoError(t, err)
+
+	ring := newTestRing(t, "k1", "k1")
+	svc := NewAuthService(testConfig(), uRepo, tRepo, &fakeBlacklistRepo{}, ring)
+	tp, err := svc.IssueTokens(context.Background(), 3)
+	require.NoError(t, err)
+
+	token, _, err := jwt.NewParser().ParseUnverified(tp.AccessToken, jwt.MapClaims{})
+	require.NoError(t, err)
+	require.Equal(t, "RS256", token.Method.Alg())
+	require.Equal(t, "k1", token.Header["kid"])
+
+	claims, err := svc.ValidateAccessToken(tp.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), claims.UserID)
+	_, err = svc.ValidateAccessToken(hmacToken.AccessToken)
+	require.NoError(t, err)
+
+	// Once the secret is removed HS256 tokens stop validating
+	noSecret := testConfig()
+	noSecret.AccessSecret = ""
+	strict := NewAuthService(noSecret, uRepo, tRepo, &fakeBlacklistRepo{}, ring)
+	_, err = strict.ValidateAccessToken(tp.AccessToken)
+	require.NoError(t, err)
+	_, err = strict.ValidateAccessToken(hmacToken.AccessToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// A key that is not in the ring is not trusted
+	other := NewAuthService(noSecret, uRepo, tRepo, &fakeBlacklistRepo{}, newTestRing(t, "k2", "k2"))
+	_, err = other.ValidateAccessToken(tp.AccessToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestAuthService_IssueScopedToken(t *testing.T) {
+	cfg := testConfig()
+	role := models.RoleAdmin
+	uRepo := &mockUserRepo{getByIDFn: func(ctx context.Context, id int64) (*models.User, error) {
+		return &models.User{ID: id, Email: "ops@example.com", Role: role}, nil
+	}}
+	tRepo := &mockTokenRepo{createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
+		return &models.RefreshToken{ID: 1, UserID: userID, Token: token, ExpiresAt: expiresAt}, nil
+	}}
+	svc := NewAuthService(cfg, uRepo, tRepo, &fakeBlacklistRepo{}, nil)
+
+	tp, err := svc.IssueTokens(context.Background(), 2)
+	require.NoError(t, err)
+	claims, err := svc.ValidateAccessToken(tp.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, models.ScopesForRole(models.RoleAdmin), claims.Scopes)
+
+	scoped, err := svc.IssueScopedToken(context.Background(), 2, claims.Scopes, "market:read")
+	require.NoError(t, err)
+	require.Equal(t, "market:read", scoped.Scope)
+
+	narrowed, err := svc.ValidateAccessToken(scoped.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, []string{models.ScopeMarketRead}, narrowed.Scopes)
+
+	// A narrowed token cannot be widened again
+	_, err = svc.IssueScopedToken(context.Background(), 2, narrowed.Scopes, "admin")
+	require.ErrorIs(t, err, models.ErrInvalidScope)
+
+	// Scopes the user lost with a role change are not granted either
+	role = models.RoleUser
+	_, err = svc.IssueScopedToken(context.Background(), 2, claims.Scopes, "admin")
+	require.ErrorIs(t, err, models.ErrInvalidScope)
+}
+
+func TestAuthService_RefreshTokens_ClientBinding(t *testing.T) {
+	issuedTo := fingerprint.Compute("app/1.0", "")
+	user := &models.User{ID: 3, Email: "bound@example.com", Role: models.RoleUser}
+	uRepo := &mockUserRepo{getByIDFn: func(ctx context.Context, id int64) (*models.User, error) { return user, nil }}
+	tRepo := &mockTokenRepo{
+		getFn: func(ctx context.Context, token string) (*models.RefreshToken, error) {
+			return &models.RefreshToken{ID: 1, UserID: user.ID, Token: token, ExpiresAt: time.Now().Add(time.Hour), Fingerprint: issuedTo}, nil
+		},
+		revokeFn: func(ctx context.Context, token string) error { return nil },
+		createFn: func(ctx context.Context, userID int64, token string, expiresAt time.Time, fp, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
+			return &models.RefreshToken{ID: 2, UserID: userID, Token: token, ExpiresAt: expiresAt, Fingerprint: fp}, nil
+		},
+	}
+
+	sameClient := fingerprint.WithContext(context.Background(), issuedTo)
+	otherClient := fingerprint.WithContext(context.Background(), fingerprint.Compute("curl/8.0", ""))
+
+	tests := []struct {
+		mode    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{fingerprint.ModeEnforce, sameClient, nil},
+		{fingerprint.ModeEnforce, otherClient, ErrClientMismatch},
+		{fingerprint.ModeReport, otherClient, nil},
+		{fingerprint.ModeOff, otherClient, nil},
+	}
+
+	for _, tt := range tests {
+		cfg := testConfig()
+		cfg.RefreshBinding = tt.mode
+		svc := NewAuthService(cfg, uRepo, tRepo, &fakeBlacklistRepo{}, nil)
+
+		_, err := svc.RefreshTokens(tt.ctx, "bound-token")
+		if tt.wantErr != nil {
+			require.ErrorIs(t, err, tt.wantErr, tt.mode)
+		} else {
+			require.NoError(t, err, tt.mode)
+		}
+	}
+}
+
 // bcryptGenerate small helper without exposing bcrypt directly in test names
 func bcryptGenerate(p string) (string, error) {
 	hash, err := bcrypt.GenerateFromPassword([]byte(p), bcrypt.DefaultCost)
@@ -7,6 +7,7 @@ import (
 
 	"github.com/Zifeldev/marketback/service/Auth/internal/config"
 	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
 )
 
 type fakeUserRepo struct{ user *models.User }
@@ -32,10 +33,16 @@ func (f *fakeUserRepo) Delete(ctx context.Context, id int64) error { return nil
 func (f *fakeUserRepo) List(ctx context.Context, limit, offset int) ([]*models.User, error) {
 	return []*models.User{f.user}, nil
 }
+func (f *fakeUserRepo) ListByIDs(ctx context.Context, ids []int64) ([]*models.User, error) {
+	return []*models.User{f.user}, nil
+}
 
-type fakeTokenRepo struct{}
+type fakeTokenRepo struct {
+	revokedSessions []string
+	sessions        []*models.Session
+}
 
-func (f *fakeTokenRepo) CreateRefreshToken(ctx context.Context, userID int64, token string, expiresAt time.Time) (*models.RefreshToken, error) {
+func (f *fakeTokenRepo) CreateRefreshToken(ctx context.Context, userID int64, token string, expiresAt time.Time, fingerprint, sessionID, userAgent, ip string, parentID int64) (*models.RefreshToken, error) {
 	return &models.RefreshToken{ID: 1, UserID: userID, Token: token, ExpiresAt: expiresAt, CreatedAt: time.Now()}, nil
 }
 func (f *fakeTokenRepo) GetRefreshToken(ctx context.Context, token string) (*models.RefreshToken, error) {
@@ -43,7 +50,31 @@ func (f *fakeTokenRepo) GetRefreshToken(ctx context.Context, token string) (*mod
 }
 func (f *fakeTokenRepo) RevokeRefreshToken(ctx context.Context, token string) error  { return nil }
 func (f *fakeTokenRepo) RevokeAllUserTokens(ctx context.Context, userID int64) error { return nil }
-func (f *fakeTokenRepo) CleanupExpiredTokens(ctx context.Context) error              { return nil }
+func (f *fakeTokenRepo) RevokeTokenFamily(ctx context.Context, token string) (int64, error) {
+	return 0, repository.ErrTokenNotFound
+}
+func (f *fakeTokenRepo) RevokeSession(ctx context.Context, userID int64, sessionID string) error {
+	f.revokedSessions = append(f.revokedSessions, sessionID)
+	return nil
+}
+func (f *fakeTokenRepo) ListSessions(ctx context.Context, userID int64) ([]*models.Session, error) {
+	return f.sessions, nil
+}
+func (f *fakeTokenRepo) CleanupExpiredTokens(ctx context.Context) error { return nil }
+
+type fakeBlacklistRepo struct{ jtis map[string]string }
+
+func (f *fakeBlacklistRepo) Add(ctx context.Context, jti string, userID int64, expiresAt time.Time, reason string) error {
+	if f.jtis == nil {
+		f.jtis = make(map[string]string)
+	}
+	f.jtis[jti] = reason
+	return nil
+}
+func (f *fakeBlacklistRepo) IsBlacklisted(ctx context.Context, jti string) (bool, error) {
+	_, ok := f.jtis[jti]
+	return ok, nil
+}
 
 func TestGenerateAndValidateAccessToken_WithSellerRole(t *testing.T) {
 	cfg := &config.JWTConfig{
@@ -52,17 +83,16 @@ func TestGenerateAndValidateAccessToken_WithSellerRole(t *testing.T) {
 		AccessExpiration:  time.Minute,
 		RefreshExpiration: time.Hour,
 		Issuer:            "auth-test",
-		FirstAdminEmail:   "",
 	}
 
-	uRepo := &fakeUserRepo{}
+	// Seller roles are granted by an admin, so sign in an existing seller
+	uRepo := &fakeUserRepo{user: &models.User{ID: 1, Email: "seller@example.com", Role: models.RoleSeller}}
 	tRepo := &fakeTokenRepo{}
-	svc := NewAuthService(cfg, uRepo, tRepo).(*authService)
+	svc := NewAuthService(cfg, uRepo, tRepo, &fakeBlacklistRepo{}, nil).(*authService)
 
-	// Register with seller role
-	pair, err := svc.Register(context.Background(), "seller@example.com", "password123", models.RoleSeller)
+	pair, err := svc.IssueTokens(context.Background(), 1)
 	if err != nil {
-		t.Fatalf("register error: %v", err)
+		t.Fatalf("issue tokens error: %v", err)
 	}
 	if pair.AccessToken == "" || pair.RefreshToken == "" {
 		t.Fatalf("expected tokens to be generated")
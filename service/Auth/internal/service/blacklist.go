@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Zifeldev/marketback/clients/authclient"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -12,18 +14,35 @@ const (
 	blacklistKeyPrefix = "blacklist:token:"
 )
 
+// TokenBlacklistService is the access token denylist in Redis, where other
+// services can check it too. Revocations are also written to store, which
+// answers while Redis cannot.
 type TokenBlacklistService struct {
 	redis  *redis.Client
 	prefix string
+	store  repository.BlacklistRepository
 }
 
-func NewTokenBlacklistService(redisClient *redis.Client, prefix string) *TokenBlacklistService {
+func NewTokenBlacklistService(redisClient *redis.Client, prefix string, store repository.BlacklistRepository) *TokenBlacklistService {
 	return &TokenBlacklistService{
 		redis:  redisClient,
 		prefix: prefix,
+		store:  store,
 	}
 }
 
+// Add revokes the access token jti until it expires at expiresAt
+func (s *TokenBlacklistService) Add(ctx context.Context, jti string, userID int64, expiresAt time.Time, reason string) error {
+	if err := s.store.Add(ctx, jti, userID, expiresAt, reason); err != nil {
+		return err
+	}
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return s.BlacklistToken(ctx, jti, userID, ttl, reason)
+}
+
 
 func (s *TokenBlacklistService) BlacklistToken(ctx context.Context, jti string, userID int64, ttl time.Duration, reason string) error {
 	if jti == "" {
@@ -52,7 +71,7 @@ func (s *TokenBlacklistService) IsBlacklisted(ctx context.Context, jti string) (
 
 	exists, err := s.redis.Exists(ctx, key).Result()
 	if err != nil {
-		return false, fmt.Errorf("failed to check blacklist: %w", err)
+		return s.store.IsBlacklisted(ctx, jti)
 	}
 
 	return exists > 0, nil
@@ -99,7 +118,7 @@ func (s *TokenBlacklistService) GetBlacklistInfo(ctx context.Context, jti string
 }
 
 func (s *TokenBlacklistService) getKey(jti string) string {
-	return fmt.Sprintf("%s%s%s", s.prefix, blacklistKeyPrefix, jti)
+	return authclient.DenylistKey(s.prefix, jti)
 }
 
 func (s *TokenBlacklistService) CountBlacklistedTokens(ctx context.Context) (int64, error) {
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBlacklistService_StoreAnswersWithoutRedis(t *testing.T) {
+	// Nothing listens on the discard port, so every Redis call fails
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:9", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	defer rdb.Close()
+	store := &fakeBlacklistRepo{}
+	svc := NewTokenBlacklistService(rdb, "auth:", store)
+
+	err := svc.Add(context.Background(), "abc", 7, time.Now().Add(time.Minute), "logout")
+	require.Error(t, err)
+	require.Equal(t, "logout", store.jtis["abc"], "the revocation is stored before Redis is tried")
+
+	revoked, err := svc.IsBlacklisted(context.Background(), "abc")
+	require.NoError(t, err)
+	require.True(t, revoked)
+	revoked, err = svc.IsBlacklisted(context.Background(), "other")
+	require.NoError(t, err)
+	require.False(t, revoked)
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/identity"
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+)
+
+var (
+	ErrProviderDisabled = errors.New("sign-in method not enabled")
+	ErrInvalidCode      = errors.New("invalid or expired code")
+	ErrCodeTooSoon      = errors.New("a code was sent recently")
+)
+
+const (
+	// phoneCodeResend is the minimum time between two codes for a number
+	phoneCodeResend = time.Minute
+	// phoneCodeAttempts is how many wrong codes are accepted before the
+	// code has to be requested again
+	phoneCodeAttempts = 5
+)
+
+// IDTokenVerifier verifies an OAuth ID token and returns the account ID
+type IDTokenVerifier interface {
+	Verify(ctx context.Context, idToken string) (string, error)
+}
+
+// IdentityService links Google accounts and phone numbers to users and
+// signs users in with them. A nil verifier or sender disables the method.
+type IdentityService interface {
+	List(ctx context.Context, userID int64) ([]*models.Identity, error)
+	LinkGoogle(ctx context.Context, userID int64, idToken string) (*models.Identity, error)
+	SendPhoneCode(ctx context.Context, phone string) error
+	LinkPhone(ctx context.Context, userID int64, phone, code string) (*models.Identity, error)
+	Unlink(ctx context.Context, userID, id int64) error
+	LoginGoogle(ctx context.Context, idToken string) (*models.TokenPair, error)
+	SendLoginCode(ctx context.Context, phone string) error
+	LoginPhone(ctx context.Context, phone, code string) (*models.TokenPair, error)
+}
+
+type identityService struct {
+	authService  AuthService
+	identityRepo repository.IdentityRepository
+	google       IDTokenVerifier
+	sms          identity.SMSSender
+	codeTTL      time.Duration
+	// phoneRegion reads numbers without a country code as national
+	// numbers of this region; empty requires a country code
+	phoneRegion string
+}
+
+func NewIdentityService(authService AuthService, identityRepo repository.IdentityRepository, google IDTokenVerifier, sms identity.SMSSender, codeTTL time.Duration, phoneRegion string) IdentityService {
+	return &identityService{
+		authService:  authService,
+		identityRepo: identityRepo,
+		google:       google,
+		sms:          sms,
+		codeTTL:      codeTTL,
+		phoneRegion:  phoneRegion,
+	}
+}
+
+func (s *identityService) List(ctx context.Context, userID int64) ([]*models.Identity, error) {
+	return s.identityRepo.ListByUser(ctx, userID)
+}
+
+func (s *identityService) verifyGoogle(ctx context.Context, idToken string) (string, error) {
+	if s.google == nil {
+		return "", ErrProviderDisabled
+	}
+	subject, err := s.google.Verify(ctx, idToken)
+	if err != nil {
+		return "", ErrInvalidCredentials
+	}
+	return subject, nil
+}
+
+func (s *identityService) LinkGoogle(ctx context.Context, userID int64, idToken string) (*models.Identity, error) {
+	subject, err := s.verifyGoogle(ctx, idToken)
+	if err != nil {
+		return nil, err
+	}
+	return s.identityRepo.Create(ctx, userID, models.ProviderGoogle, subject)
+}
+
+func (s *identityService) SendPhoneCode(ctx context.Context, phone string) error {
+	if s.sms == nil {
+		return ErrProviderDisabled
+	}
+	phone, err := identity.NormalizePhone(phone, s.phoneRegion)
+	if err != nil {
+		return err
+	}
+
+	code, err := identity.NewCode()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	saved, err := s.identityRepo.SavePhoneCode(ctx, phone, identity.HashCode(phone, code), now.Add(s.codeTTL), now.Add(-phoneCodeResend))
+	if err != nil {
+		return err
+	}
+	if !saved {
+		return ErrCodeTooSoon
+	}
+
+	return s.sms.Send(ctx, phone, "Your verification code is "+code)
+}
+
+// verifyPhone normalizes the number and consumes its code
+func (s *identityService) verifyPhone(ctx context.Context, phone, code string) (string, error) {
+	if s.sms == nil {
+		return "", ErrProviderDisabled
+	}
+	phone, err := identity.NormalizePhone(phone, s.phoneRegion)
+	if err != nil {
+		return "", err
+	}
+
+	ok, err := s.identityRepo.ConsumePhoneCode(ctx, phone, identity.HashCode(phone, code), phoneCodeAttempts)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", ErrInvalidCode
+	}
+	return phone, nil
+}
+
+func (s *identityService) LinkPhone(ctx context.Context, userID int64, phone, code string) (*models.Identity, error) {
+	phone, err := s.verifyPhone(ctx, phone, code)
+	if err != nil {
+		return nil, err
+	}
+	return s.identityRepo.Create(ctx, userID, models.ProviderPhone, phone)
+}
+
+// Unlink removes a linked identity. The account keeps its email and
+// password, so unlinking never locks the user out.
+func (s *identityService) Unlink(ctx context.Context, userID, id int64) error {
+	return s.identityRepo.Delete(ctx, userID, id)
+}
+
+func (s *identityService) LoginGoogle(ctx context.Context, idToken string) (*models.TokenPair, error) {
+	subject, err := s.verifyGoogle(ctx, idToken)
+	if err != nil {
+		return nil, err
+	}
+	return s.login(ctx, models.ProviderGoogle, subject)
+}
+
+// SendLoginCode sends a code only to linked numbers but succeeds either way,
+// so the endpoint does not reveal which numbers have an account
+func (s *identityService) SendLoginCode(ctx context.Context, phone string) error {
+	if s.sms == nil {
+		return ErrProviderDisabled
+	}
+	normalized, err := identity.NormalizePhone(phone, s.phoneRegion)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.identityRepo.GetUserID(ctx, models.ProviderPhone, normalized); err != nil {
+		if errors.Is(err, repository.ErrIdentityNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if err := s.SendPhoneCode(ctx, normalized); err != nil && !errors.Is(err, ErrCodeTooSoon) {
+		return err
+	}
+	return nil
+}
+
+func (s *identityService) LoginPhone(ctx context.Context, phone, code string) (*models.TokenPair, error) {
+	phone, err := s.verifyPhone(ctx, phone, code)
+	if err != nil {
+		return nil, err
+	}
+	return s.login(ctx, models.ProviderPhone, phone)
+}
+
+func (s *identityService) login(ctx context.Context, provider, subject string) (*models.TokenPair, error) {
+	userID, err := s.identityRepo.GetUserID(ctx, provider, subject)
+	if err != nil {
+		if errors.Is(err, repository.ErrIdentityNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	return s.authService.IssueTokens(ctx, userID)
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/identity"
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+type mockIdentityRepo struct {
+	createFn       func(ctx context.Context, userID int64, provider, subject string) (*models.Identity, error)
+	getUserIDFn    func(ctx context.Context, provider, subject string) (int64, error)
+	savePhoneFn    func(ctx context.Context, phone, codeHash string, expiresAt, resendAfter time.Time) (bool, error)
+	consumePhoneFn func(ctx context.Context, phone, codeHash string, maxAttempts int) (bool, error)
+}
+
+func (m *mockIdentityRepo) Create(ctx context.Context, userID int64, provider, subject string) (*models.Identity, error) {
+	return m.createFn(ctx, userID, provider, subject)
+}
+func (m *mockIdentityRepo) GetUserID(ctx context.Context, provider, subject string) (int64, error) {
+	return m.getUserIDFn(ctx, provider, subject)
+}
+func (m *mockIdentityRepo) ListByUser(ctx context.Context, userID int64) ([]*models.Identity, error) {
+	return nil, errors.New("not implemented")
+}
+func (m *mockIdentityRepo) Delete(ctx context.Context, userID, id int64) error {
+	return errors.New("not implemented")
+}
+func (m *mockIdentityRepo) SavePhoneCode(ctx context.Context, phone, codeHash string, expiresAt, resendAfter time.Time) (bool, error) {
+	return m.savePhoneFn(ctx, phone, codeHash, expiresAt, resendAfter)
+}
+func (m *mockIdentityRepo) ConsumePhoneCode(ctx context.Context, phone, codeHash string, maxAttempts int) (bool, error) {
+	return m.consumePhoneFn(ctx, phone, codeHash, maxAttempts)
+}
+func (m *mockIdentityRepo) Rotate(ctx context.Context, batchSize int) (int, error) {
+	return 0, nil
+}
+
+type stubVerifier struct {
+	subject string
+	err     error
+}
+
+func (v stubVerifier) Verify(ctx context.Context, idToken string) (string, error) {
+	return v.subject, v.err
+}
+
+type recordingSender struct {
+	sent []string
+}
+
+func (s *recordingSender) Send(ctx context.Context, to, message string) error {
+	s.sent = append(s.sent, to+": "+message)
+	return nil
+}
+
+// tokenIssuer is an AuthService that only issues tokens
+type tokenIssuer struct {
+	AuthService
+	issued []int64
+}
+
+func (a *tokenIssuer) IssueTokens(ctx context.Context, userID int64) (*models.TokenPair, error) {
+	a.issued = append(a.issued, userID)
+	return &models.TokenPair{AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+func TestIdentityService_LoginGoogle(t *testing.T) {
+	auth := &tokenIssuer{}
+	repo := &mockIdentityRepo{getUserIDFn: func(ctx context.Context, provider, subject string) (int64, error) {
+		require.Equal(t, models.ProviderGoogle, provider)
+		if subject == "g-1" {
+			return 7, nil
+		}
+		return 0, repository.ErrIdentityNotFound
+	}}
+
+	svc := NewIdentityService(auth, repo, stubVerifier{subject: "g-1"}, nil, time.Minute, "")
+	tp, err := svc.LoginGoogle(context.Background(), "token")
+	require.NoError(t, err)
+	require.Equal(t, "access", tp.AccessToken)
+	require.Equal(t, []int64{7}, auth.issued)
+
+	svc = NewIdentityService(auth, repo, stubVerifier{subject: "g-unlinked"}, nil, time.Minute, "")
+	_, err = svc.LoginGoogle(context.Background(), "token")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	svc = NewIdentityService(auth, repo, stubVerifier{err: identity.ErrInvalidIDToken}, nil, time.Minute, "")
+	_, err = svc.LoginGoogle(context.Background(), "token")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestIdentityService_ProviderDisabled(t *testing.T) {
+	svc := NewIdentityService(&tokenIssuer{}, &mockIdentityRepo{}, nil, nil, time.Minute, "")
+
+	_, err := svc.LinkGoogle(context.Background(), 1, "token")
+	require.ErrorIs(t, err, ErrProviderDisabled)
+	require.ErrorIs(t, svc.SendPhoneCode(context.Background(), "+4915112345678"), ErrProviderDisabled)
+	_, err = svc.LoginPhone(context.Background(), "+4915112345678", "123456")
+	require.ErrorIs(t, err, ErrProviderDisabled)
+}
+
+func TestIdentityService_PhoneFlow(t *testing.T) {
+	sms := &recordingSender{}
+	var storedHash string
+	repo := &mockIdentityRepo{
+		savePhoneFn: func(ctx context.Context, phone, codeHash string, expiresAt, resendAfter time.Time) (bool, error) {
+			if storedHash != "" {
+				return false, nil
+			}
+			storedHash = codeHash
+			return true, nil
+		},
+		consumePhoneFn: func(ctx context.Context, phone, codeHash string, maxAttempts int) (bool, error) {
+			return codeHash == storedHash, nil
+		},
+		createFn: func(ctx context.Context, userID int64, provider, subject string) (*models.Identity, error) {
+			return &models.Identity{ID: 1, UserID: userID, Provider: provider, Subject: subject}, nil
+		},
+	}
+	svc := NewIdentityService(&tokenIssuer{}, repo, nil, sms, time.Minute, "")
+
+	require.NoError(t, svc.SendPhoneCode(context.Background(), "+49 151 12345678"))
+	require.Len(t, sms.sent, 1)
+	require.ErrorIs(t, svc.SendPhoneCode(context.Background(), "+4915112345678"), ErrCodeTooSoon)
+
+	_, err := svc.LinkPhone(context.Background(), 3, "+4915112345678", "000000x")
+	require.ErrorIs(t, err, ErrInvalidCode)
+
+	code := sms.sent[0][len(sms.sent[0])-6:]
+	linked, err := svc.LinkPhone(context.Background(), 3, "+49 151 12345678", code)
+	require.NoError(t, err)
+	require.Equal(t, "+4915112345678", linked.Subject)
+	require.Equal(t, models.ProviderPhone, linked.Provider)
+}
+
+func TestIdentityService_SendLoginCode_UnlinkedNumber(t *testing.T) {
+	sms := &recordingSender{}
+	repo := &mockIdentityRepo{getUserIDFn: func(ctx context.Context, provider, subject string) (int64, error) {
+		return 0, repository.ErrIdentityNotFound
+	}}
+	svc := NewIdentityService(&tokenIssuer{}, repo, nil, sms, time.Minute, "")
+
+	require.NoError(t, svc.SendLoginCode(context.Background(), "+4915112345678"))
+	require.Empty(t, sms.sent)
+	require.ErrorIs(t, svc.SendLoginCode(context.Background(), "12345"), identity.ErrInvalidPhone)
+}
+
+func TestIdentityService_SendPhoneCode_DefaultRegion(t *testing.T) {
+	sms := &recordingSender{}
+	repo := &mockIdentityRepo{savePhoneFn: func(ctx context.Context, phone, codeHash string, expiresAt, resendAfter time.Time) (bool, error) {
+		return true, nil
+	}}
+	svc := NewIdentityService(&tokenIssuer{}, repo, nil, sms, time.Minute, "DE")
+
+	require.NoError(t, svc.SendPhoneCode(context.Background(), "0151 12345678"))
+	require.Len(t, sms.sent, 1)
+	require.True(t, strings.HasPrefix(sms.sent[0], "+4915112345678: "), sms.sent[0])
+	require.ErrorIs(t, svc.SendPhoneCode(context.Background(), "0151"), identity.ErrInvalidPhone)
+}
+
+func TestIdentityService_Login_BlockedAccount(t *testing.T) {
+	repo := &mockIdentityRepo{
+		getUserIDFn: func(ctx context.Context, provider, subject string) (int64, error) {
+			return 1, nil
+		},
+		consumePhoneFn: func(ctx context.Context, phone, codeHash string, maxAttempts int) (bool, error) {
+			return true, nil
+		},
+	}
+	tests := []struct {
+		name string
+		user models.User
+		err  error
+	}{
+		{"force reset", models.User{PasswordResetRequired: true}, ErrPasswordResetRequired},
+		{"temporary password", models.User{PasswordChangeRequired: true}, ErrPasswordChangeRequired},
+		{"email not verified", models.User{}, ErrEmailNotVerified},
+		{"suspended", models.User{EmailVerified: true, Suspended: true}, ErrAccountSuspended},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.RequireVerifiedEmail = true
+			user := tt.user
+			user.ID, user.Email, user.Role = 1, "user@example.com", models.RoleUser
+			uRepo := &fakeUserRepo{user: &user}
+			auth := NewAuthService(cfg, uRepo, &fakeTokenRepo{}, &fakeBlacklistRepo{}, nil)
+			svc := NewIdentityService(auth, repo, stubVerifier{subject: "g-1"}, &recordingSender{}, time.Minute, "")
+
+			// A linked Google account or phone does not get around the checks
+			// of password sign-in
+			_, err := svc.LoginGoogle(context.Background(), "token")
+			require.ErrorIs(t, err, tt.err)
+			_, err = svc.LoginPhone(context.Background(), "+4915112345678", "123456")
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,431 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/config"
+	"github.com/Zifeldev/marketback/service/Auth/internal/fingerprint"
+	"github.com/Zifeldev/marketback/service/Auth/internal/mailer"
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrEmailDisabled is returned for flows that need email when no mail
+// server is configured or their email is disabled
+var ErrEmailDisabled = errors.New("email is not configured")
+
+// ErrEmailAlreadyVerified is returned when asked to verify an email that
+// already is
+var ErrEmailAlreadyVerified = errors.New("email is already verified")
+
+// ErrPasswordUnchanged is returned when a new password is the current one
+var ErrPasswordUnchanged = errors.New("new password must differ from the current one")
+
+// SecurityService emails users about sign-ins from new devices or countries
+// and handles the "it wasn't me", password reset and email verification
+// links in those emails. It also lets users see and sign out their sessions.
+// Without a notifier sign-ins are still recorded but no email is sent.
+type SecurityService interface {
+	// Registered welcomes a new user by email
+	Registered(ctx context.Context, userID int64) error
+	// SendEmailVerification emails a link verifying the user's address
+	SendEmailVerification(ctx context.Context, userID int64) error
+	// VerifyEmail marks the address of the user a verification link was
+	// sent to as verified
+	VerifyEmail(ctx context.Context, token string) error
+	// LoginSucceeded records the client of a new session and emails an
+	// alert when the device or country was not seen before
+	LoginSucceeded(ctx context.Context, userID int64, sessionID string, client fingerprint.Client) error
+	// DenyLogin handles an alert link: it revokes the reported session,
+	// blocks password sign-in and emails a password reset link
+	DenyLogin(ctx context.Context, token string) error
+	// RequestPasswordReset emails a reset link; unknown addresses are
+	// ignored so accounts cannot be discovered
+	RequestPasswordReset(ctx context.Context, email string) error
+	// ResetPassword sets a new password from a reset link and signs the
+	// user out everywhere
+	ResetPassword(ctx context.Context, token, password string) error
+	// ChangePassword replaces the password of the account of email when
+	// password, also a temporary one, is correct and signs the user out
+	// everywhere. Accounts locked until a reset must use a reset link.
+	ChangePassword(ctx context.Context, email, password, newPassword string) error
+	// Invite emails a user an admin created: a link choosing their
+	// password, or where to sign in with a temporary password
+	Invite(ctx context.Context, userID int64, temporaryPassword bool) error
+	// ForcePasswordReset signs a user out everywhere, blocks password
+	// sign-in and emails a reset link. ErrEmailDisabled is returned after
+	// the account was locked when no email could be sent.
+	ForcePasswordReset(ctx context.Context, userID int64) error
+	// RevokeSessions signs a user out everywhere; access tokens issued
+	// earlier are rejected at once
+	RevokeSessions(ctx context.Context, userID int64) error
+	// Suspend blocks every sign-in of a user and signs them out everywhere;
+	// their access tokens are rejected at once
+	Suspend(ctx context.Context, userID int64) error
+	// Unsuspend lets a suspended user sign in again
+	Unsuspend(ctx context.Context, userID int64) error
+	// Sessions lists the user's sessions, marking currentSessionID as the
+	// current one
+	Sessions(ctx context.Context, userID int64, currentSessionID string) ([]*models.Session, error)
+	// RevokeSession signs one of the user's sessions out. Unlike
+	// RevokeSessions it revokes only the session's refresh token, so its
+	// access tokens stay valid until they expire.
+	RevokeSession(ctx context.Context, userID int64, sessionID string) error
+}
+
+type securityService struct {
+	cfg          *config.SecurityConfig
+	userRepo     repository.UserRepository
+	tokenRepo    repository.TokenRepository
+	securityRepo repository.SecurityRepository
+	notifier     *mailer.Notifier
+}
+
+func NewSecurityService(cfg *config.SecurityConfig, userRepo repository.UserRepository, tokenRepo repository.TokenRepository, securityRepo repository.SecurityRepository, notifier *mailer.Notifier) SecurityService {
+	return &securityService{
+		cfg:          cfg,
+		userRepo:     userRepo,
+		tokenRepo:    tokenRepo,
+		securityRepo: securityRepo,
+		notifier:     notifier,
+	}
+}
+
+// registrationEmail is the data of the registration email template
+type registrationEmail struct {
+	Email  string
+	AppURL string
+}
+
+// passwordResetEmail is the data of the password_reset email template
+type passwordResetEmail struct {
+	Link      string
+	ExpiresIn string
+}
+
+// emailVerificationEmail is the data of the email_verification email
+// template
+type emailVerificationEmail struct {
+	Email     string
+	Link      string
+	ExpiresIn string
+}
+
+// invitationEmail is the data of the invitation email template; Link is
+// empty for users given a temporary password
+type invitationEmail struct {
+	Email     string
+	Link      string
+	ExpiresIn string
+	AppURL    string
+}
+
+// loginAlertEmail is the data of the login_alert email template
+type loginAlertEmail struct {
+	NewCountry bool
+	Country    string
+	Time       string
+	IP         string
+	UserAgent  string
+	Link       string
+	ExpiresIn  string
+}
+
+func (s *securityService) Registered(ctx context.Context, userID int64) error {
+	if !s.notifier.Enabled(mailer.EventRegistration) {
+		return nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.notifier.Notify(ctx, mailer.EventRegistration, user.Email, registrationEmail{Email: user.Email, AppURL: s.cfg.AppURL})
+}
+
+func (s *securityService) SendEmailVerification(ctx context.Context, userID int64) error {
+	if !s.notifier.Enabled(mailer.EventEmailVerification) {
+		return ErrEmailDisabled
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+
+	token, err := s.issueToken(ctx, userID, repository.PurposeEmailVerification, "", s.cfg.VerifyEmailTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.notifier.Notify(ctx, mailer.EventEmailVerification, user.Email, emailVerificationEmail{
+		Email:     user.Email,
+		Link:      s.cfg.VerifyEmailURL + "?token=" + url.QueryEscape(token),
+		ExpiresIn: formatTTL(s.cfg.VerifyEmailTTL),
+	})
+}
+
+func (s *securityService) VerifyEmail(ctx context.Context, token string) error {
+	consumed, err := s.securityRepo.ConsumeToken(ctx, repository.PurposeEmailVerification, hashSecurityToken(token))
+	if err != nil {
+		return err
+	}
+	return s.securityRepo.SetEmailVerified(ctx, consumed.UserID)
+}
+
+func (s *securityService) LoginSucceeded(ctx context.Context, userID int64, sessionID string, client fingerprint.Client) error {
+	if !s.cfg.LoginAlerts || client.Device == "" {
+		return nil
+	}
+
+	newDevice, newCountry, first, err := s.securityRepo.RecordDevice(ctx, userID, client.Device, client.Country)
+	if err != nil {
+		return err
+	}
+	// The first sign-in (usually right after sign-up) has nothing to
+	// compare with
+	if first || (!newDevice && !newCountry) || !s.notifier.Enabled(mailer.EventLoginAlert) {
+		return nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	token, err := s.issueToken(ctx, userID, repository.PurposeLoginAlert, sessionID, s.cfg.AlertLinkTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.notifier.Notify(ctx, mailer.EventLoginAlert, user.Email, loginAlertEmail{
+		NewCountry: newCountry,
+		Country:    client.Country,
+		Time:       time.Now().UTC().Format(time.RFC1123),
+		IP:         client.IP,
+		UserAgent:  client.UserAgent,
+		Link:       s.link("/security/not-me", token),
+		ExpiresIn:  formatTTL(s.cfg.AlertLinkTTL),
+	})
+}
+
+func (s *securityService) DenyLogin(ctx context.Context, token string) error {
+	consumed, err := s.securityRepo.ConsumeToken(ctx, repository.PurposeLoginAlert, hashSecurityToken(token))
+	if err != nil {
+		return err
+	}
+
+	if consumed.SessionID != "" {
+		if err := s.tokenRepo.RevokeSession(ctx, consumed.UserID, consumed.SessionID); err != nil {
+			return err
+		}
+	}
+	if err := s.securityRepo.SetPasswordResetRequired(ctx, consumed.UserID); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, consumed.UserID)
+	if err != nil {
+		return err
+	}
+	return s.sendPasswordReset(ctx, user)
+}
+
+func (s *securityService) RequestPasswordReset(ctx context.Context, email string) error {
+	if !s.notifier.Enabled(mailer.EventPasswordReset) {
+		return ErrEmailDisabled
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+	return s.sendPasswordReset(ctx, user)
+}
+
+func (s *securityService) ResetPassword(ctx context.Context, token, password string) error {
+	consumed, err := s.securityRepo.ConsumeToken(ctx, repository.PurposePasswordReset, hashSecurityToken(token))
+	if err != nil {
+		return err
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	if err := s.securityRepo.ResetPassword(ctx, consumed.UserID, string(passwordHash)); err != nil {
+		return err
+	}
+	return s.securityRepo.RevokeSessions(ctx, consumed.UserID)
+}
+
+func (s *securityService) ChangePassword(ctx context.Context, email, password, newPassword string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return ErrInvalidCredentials
+		}
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return ErrInvalidCredentials
+	}
+	if user.PasswordResetRequired {
+		return ErrPasswordResetRequired
+	}
+	if newPassword == password {
+		return ErrPasswordUnchanged
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := s.securityRepo.ResetPassword(ctx, user.ID, string(passwordHash)); err != nil {
+		return err
+	}
+	return s.securityRepo.RevokeSessions(ctx, user.ID)
+}
+
+func (s *securityService) Invite(ctx context.Context, userID int64, temporaryPassword bool) error {
+	if !s.notifier.Enabled(mailer.EventInvitation) {
+		return ErrEmailDisabled
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	data := invitationEmail{Email: user.Email, AppURL: s.cfg.AppURL}
+	if !temporaryPassword {
+		token, err := s.issueToken(ctx, userID, repository.PurposePasswordReset, "", s.cfg.InvitationTTL)
+		if err != nil {
+			return err
+		}
+		data.Link = s.link("/security/reset-password", token)
+		data.ExpiresIn = formatTTL(s.cfg.InvitationTTL)
+	}
+	return s.notifier.Notify(ctx, mailer.EventInvitation, user.Email, data)
+}
+
+func (s *securityService) ForcePasswordReset(ctx context.Context, userID int64) error {
+	if err := s.securityRepo.SetPasswordResetRequired(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.securityRepo.RevokeSessions(ctx, userID); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.sendPasswordReset(ctx, user)
+}
+
+func (s *securityService) RevokeSessions(ctx context.Context, userID int64) error {
+	return s.securityRepo.RevokeSessions(ctx, userID)
+}
+
+func (s *securityService) Suspend(ctx context.Context, userID int64) error {
+	if err := s.securityRepo.SetSuspended(ctx, userID, true); err != nil {
+		return err
+	}
+	return s.securityRepo.RevokeSessions(ctx, userID)
+}
+
+func (s *securityService) Unsuspend(ctx context.Context, userID int64) error {
+	return s.securityRepo.SetSuspended(ctx, userID, false)
+}
+
+func (s *securityService) Sessions(ctx context.Context, userID int64, currentSessionID string) ([]*models.Session, error) {
+	sessions, err := s.tokenRepo.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		session.Current = currentSessionID != "" && session.ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+func (s *securityService) RevokeSession(ctx context.Context, userID int64, sessionID string) error {
+	sessions, err := s.tokenRepo.ListSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if session.ID == sessionID {
+			return s.tokenRepo.RevokeSession(ctx, userID, sessionID)
+		}
+	}
+	return repository.ErrSessionNotFound
+}
+
+func (s *securityService) sendPasswordReset(ctx context.Context, user *models.User) error {
+	if !s.notifier.Enabled(mailer.EventPasswordReset) {
+		return ErrEmailDisabled
+	}
+
+	token, err := s.issueToken(ctx, user.ID, repository.PurposePasswordReset, "", s.cfg.PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.notifier.Notify(ctx, mailer.EventPasswordReset, user.Email, passwordResetEmail{
+		Link:      s.link("/security/reset-password", token),
+		ExpiresIn: formatTTL(s.cfg.PasswordResetTTL),
+	})
+}
+
+// issueToken creates a single-use link token; only its hash is stored
+func (s *securityService) issueToken(ctx context.Context, userID int64, purpose, sessionID string, ttl time.Duration) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	if err := s.securityRepo.CreateToken(ctx, userID, purpose, hashSecurityToken(token), sessionID, time.Now().Add(ttl)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *securityService) link(path, token string) string {
+	return s.cfg.AppURL + path + "?token=" + url.QueryEscape(token)
+}
+
+func hashSecurityToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// formatTTL renders whole hours as "72 hours" and anything else as a
+// duration
+func formatTTL(ttl time.Duration) string {
+	if ttl%time.Hour == 0 {
+		if ttl == time.Hour {
+			return "1 hour"
+		}
+		return fmt.Sprintf("%d hours", ttl/time.Hour)
+	}
+	return ttl.String()
+}
@@ -0,0 +1,392 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/config"
+	"github.com/Zifeldev/marketback/service/Auth/internal/fingerprint"
+	"github.com/Zifeldev/marketback/service/Auth/internal/mailer"
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type fakeSecurityRepo struct {
+	devices         [][2]string // device, country
+	tokens          map[string]*fakeSecurityToken
+	resetRequired   bool
+	changeRequired  bool
+	passwordHash    string
+	sessionsRevoked bool
+	emailVerified   bool
+	suspended       bool
+}
+
+type fakeSecurityToken struct {
+	purpose string
+	repository.SecurityToken
+	used bool
+}
+
+func (f *fakeSecurityRepo) RecordDevice(ctx context.Context, userID int64, device, country string) (bool, bool, bool, error) {
+	first := len(f.devices) == 0
+	knownDevice, knownCountry := false, false
+	for _, seen := range f.devices {
+		knownDevice = knownDevice || seen[0] == device
+		knownCountry = knownCountry || seen[1] == country
+	}
+	f.devices = append(f.devices, [2]string{device, country})
+	return !knownDevice, country != "" && !knownCountry, first, nil
+}
+func (f *fakeSecurityRepo) CreateToken(ctx context.Context, userID int64, purpose, tokenHash, sessionID string, expiresAt time.Time) error {
+	if f.tokens == nil {
+		f.tokens = make(map[string]*fakeSecurityToken)
+	}
+	f.tokens[tokenHash] = &fakeSecurityToken{purpose: purpose, SecurityToken: repository.SecurityToken{UserID: userID, SessionID: sessionID}}
+	return nil
+}
+func (f *fakeSecurityRepo) ConsumeToken(ctx context.Context, purpose, tokenHash string) (*repository.SecurityToken, error) {
+	token, ok := f.tokens[tokenHash]
+	if !ok || token.used || token.purpose != purpose {
+		return nil, repository.ErrSecurityTokenInvalid
+	}
+	token.used = true
+	return &token.SecurityToken, nil
+}
+func (f *fakeSecurityRepo) SetPasswordResetRequired(ctx context.Context, userID int64) error {
+	f.resetRequired = true
+	return nil
+}
+func (f *fakeSecurityRepo) ResetPassword(ctx context.Context, userID int64, passwordHash string) error {
+	f.resetRequired, f.changeRequired, f.passwordHash = false, false, passwordHash
+	return nil
+}
+func (f *fakeSecurityRepo) SetPasswordChangeRequired(ctx context.Context, userID int64) error {
+	f.changeRequired = true
+	return nil
+}
+func (f *fakeSecurityRepo) RevokeSessions(ctx context.Context, userID int64) error {
+	f.sessionsRevoked = true
+	return nil
+}
+func (f *fakeSecurityRepo) SetSuspended(ctx context.Context, userID int64, suspended bool) error {
+	f.suspended = suspended
+	return nil
+}
+
+func (f *fakeSecurityRepo) SetEmailVerified(ctx context.Context, userID int64) error {
+	f.emailVerified = true
+	return nil
+}
+
+type sentMail struct{ to, subject, body string }
+
+type fakeMailer struct{ sent []sentMail }
+
+func (f *fakeMailer) Send(ctx context.Context, to, subject, body string) error {
+	f.sent = append(f.sent, sentMail{to, subject, body})
+	return nil
+}
+
+// notify sends the templated emails of a security service through m
+func notify(t *testing.T, m mailer.Mailer, disabled ...string) *mailer.Notifier {
+	t.Helper()
+	n, err := mailer.NewNotifier(m, "", disabled)
+	require.NoError(t, err)
+	return n
+}
+
+var linkToken = regexp.MustCompile(`token=(\S+)`)
+
+func tokenFromMail(t *testing.T, m sentMail) string {
+	t.Helper()
+	match := linkToken.FindStringSubmatch(m.body)
+	require.NotNil(t, match, "no link in %q", m.body)
+	token, err := url.QueryUnescape(match[1])
+	require.NoError(t, err)
+	return token
+}
+
+func TestSecurityService_AlertDenyAndReset(t *testing.T) {
+	cfg := &config.SecurityConfig{
+		LoginAlerts:      true,
+		AppURL:           "https://shop.example.com",
+		AlertLinkTTL:     72 * time.Hour,
+		PasswordResetTTL: time.Hour,
+	}
+	uRepo := &fakeUserRepo{user: &models.User{ID: 1, Email: "user@example.com", Role: models.RoleUser}}
+	tRepo := &fakeTokenRepo{}
+	sRepo := &fakeSecurityRepo{}
+	mail := &fakeMailer{}
+	svc := NewSecurityService(cfg, uRepo, tRepo, sRepo, notify(t, mail))
+	ctx := context.Background()
+
+	laptop := fingerprint.Client{Device: "laptop", Country: "DE", IP: "203.0.113.7", UserAgent: "Firefox"}
+	phone := fingerprint.Client{Device: "phone", Country: "DE", IP: "203.0.113.8", UserAgent: "Safari"}
+
+	// The first sign-in and sign-ins from a known device are not reported
+	require.NoError(t, svc.LoginSucceeded(ctx, 1, "s1", laptop))
+	require.NoError(t, svc.LoginSucceeded(ctx, 1, "s2", laptop))
+	require.Empty(t, mail.sent)
+
+	require.NoError(t, svc.LoginSucceeded(ctx, 1, "s3", phone))
+	require.Len(t, mail.sent, 1)
+	require.Equal(t, "user@example.com", mail.sent[0].to)
+	require.Contains(t, mail.sent[0].body, "https://shop.example.com/security/not-me?token=")
+	require.Contains(t, mail.sent[0].body, "Safari")
+
+	alert := tokenFromMail(t, mail.sent[0])
+	require.NoError(t, svc.DenyLogin(ctx, alert))
+	require.Equal(t, []string{"s3"}, tRepo.revokedSessions)
+	require.True(t, sRepo.resetRequired)
+	require.Len(t, mail.sent, 2)
+	require.Contains(t, mail.sent[1].body, "/security/reset-password?token=")
+
+	// Links are single-use and only valid for their purpose
+	require.ErrorIs(t, svc.DenyLogin(ctx, alert), repository.ErrSecurityTokenInvalid)
+	reset := tokenFromMail(t, mail.sent[1])
+	require.ErrorIs(t, svc.DenyLogin(ctx, reset), repository.ErrSecurityTokenInvalid)
+
+	require.NoError(t, svc.ResetPassword(ctx, reset, "new-password"))
+	require.False(t, sRepo.resetRequired)
+	require.True(t, sRepo.sessionsRevoked)
+	require.NoError(t, bcrypt.CompareHashAndPassword([]byte(sRepo.passwordHash), []byte("new-password")))
+}
+
+func TestSecurityService_NewCountry(t *testing.T) {
+	cfg := &config.SecurityConfig{LoginAlerts: true, AlertLinkTTL: time.Hour}
+	uRepo := &fakeUserRepo{user: &models.User{ID: 1, Email: "user@example.com"}}
+	mail := &fakeMailer{}
+	svc := NewSecurityService(cfg, uRepo, &fakeTokenRepo{}, &fakeSecurityRepo{}, notify(t, mail))
+	ctx := context.Background()
+
+	require.NoError(t, svc.LoginSucceeded(ctx, 1, "s1", fingerprint.Client{Device: "laptop", Country: "DE"}))
+	require.NoError(t, svc.LoginSucceeded(ctx, 1, "s2", fingerprint.Client{Device: "laptop", Country: "BR"}))
+	require.Len(t, mail.sent, 1)
+	require.Contains(t, mail.sent[0].body, "new location (BR)")
+}
+
+func TestSecurityService_RequestPasswordReset(t *testing.T) {
+	cfg := &config.SecurityConfig{PasswordResetTTL: time.Hour}
+	uRepo := &fakeUserRepo{user: &models.User{ID: 1, Email: "user@example.com"}}
+
+	disabled := NewSecurityService(cfg, uRepo, &fakeTokenRepo{}, &fakeSecurityRepo{}, nil)
+	require.ErrorIs(t, disabled.RequestPasswordReset(context.Background(), "user@example.com"), ErrEmailDisabled)
+
+	mail := &fakeMailer{}
+	svc := NewSecurityService(cfg, uRepo, &fakeTokenRepo{}, &fakeSecurityRepo{}, notify(t, mail))
+	require.NoError(t, svc.RequestPasswordReset(context.Background(), "user@example.com"))
+	require.Len(t, mail.sent, 1)
+	require.Contains(t, mail.sent[0].body, "1 hour")
+
+	// A disabled event is as good as no email at all
+	svc = NewSecurityService(cfg, uRepo, &fakeTokenRepo{}, &fakeSecurityRepo{}, notify(t, mail, mailer.EventPasswordReset))
+	require.ErrorIs(t, svc.RequestPasswordReset(context.Background(), "user@example.com"), ErrEmailDisabled)
+	require.Len(t, mail.sent, 1)
+}
+
+func TestSecurityService_Registered(t *testing.T) {
+	cfg := &config.SecurityConfig{AppURL: "https://shop.example.com"}
+	uRepo := &fakeUserRepo{user: &models.User{ID: 1, Email: "user@example.com"}}
+
+	require.NoError(t, NewSecurityService(cfg, uRepo, &fakeTokenRepo{}, &fakeSecurityRepo{}, nil).Registered(context.Background(), 1))
+
+	mail := &fakeMailer{}
+	svc := NewSecurityService(cfg, uRepo, &fakeTokenRepo{}, &fakeSecurityRepo{}, notify(t, mail))
+	require.NoError(t, svc.Registered(context.Background(), 1))
+	require.Len(t, mail.sent, 1)
+	require.Equal(t, "user@example.com", mail.sent[0].to)
+	require.Equal(t, "Welcome to Marketback", mail.sent[0].subject)
+	require.Contains(t, mail.sent[0].body, "https://shop.example.com")
+
+	svc = NewSecurityService(cfg, uRepo, &fakeTokenRepo{}, &fakeSecurityRepo{}, notify(t, mail, mailer.EventRegistration))
+	require.NoError(t, svc.Registered(context.Background(), 1))
+	require.Len(t, mail.sent, 1)
+}
+
+func TestSecurityService_ForcePasswordReset(t *testing.T) {
+	cfg := &config.SecurityConfig{AppURL: "https://shop.example.com", PasswordResetTTL: time.Hour}
+	uRepo := &fakeUserRepo{user: &models.User{ID: 7, Email: "user@example.com"}}
+
+	// Without email the account is still locked down
+	sRepo := &fakeSecurityRepo{}
+	disabled := NewSecurityService(cfg, uRepo, &fakeTokenRepo{}, sRepo, nil)
+	require.ErrorIs(t, disabled.ForcePasswordReset(context.Background(), 7), ErrEmailDisabled)
+	require.True(t, sRepo.resetRequired)
+	require.True(t, sRepo.sessionsRevoked)
+
+	mail := &fakeMailer{}
+	svc := NewSecurityService(cfg, uRepo, &fakeTokenRepo{}, &fakeSecurityRepo{}, notify(t, mail))
+	require.NoError(t, svc.ForcePasswordReset(context.Background(), 7))
+	require.Len(t, mail.sent, 1)
+	require.Contains(t, mail.sent[0].body, "https://shop.example.com/security/reset-password?token=")
+}
+
+func TestSecurityService_Suspend(t *testing.T) {
+	sRepo := &fakeSecurityRepo{}
+	svc := NewSecurityService(&config.SecurityConfig{}, &fakeUserRepo{}, &fakeTokenRepo{}, sRepo, nil)
+
+	require.NoError(t, svc.Suspend(context.Background(), 7))
+	require.True(t, sRepo.suspended)
+	require.True(t, sRepo.sessionsRevoked)
+
+	require.NoError(t, svc.Unsuspend(context.Background(), 7))
+	require.False(t, sRepo.suspended)
+}
+
+func TestSecurityService_Sessions(t *testing.T) {
+	tRepo := &fakeTokenRepo{sessions: []*models.Session{{ID: "phone"}, {ID: "laptop"}}}
+	svc := NewSecurityService(&config.SecurityConfig{}, &fakeUserRepo{}, tRepo, &fakeSecurityRepo{}, nil)
+	ctx := context.Background()
+
+	sessions, err := svc.Sessions(ctx, 1, "laptop")
+	require.NoError(t, err)
+	require.False(t, sessions[0].Current)
+	require.True(t, sessions[1].Current)
+
+	require.ErrorIs(t, svc.RevokeSession(ctx, 1, "tablet"), repository.ErrSessionNotFound)
+	require.Empty(t, tRepo.revokedSessions)
+	require.NoError(t, svc.RevokeSession(ctx, 1, "phone"))
+	require.Equal(t, []string{"phone"}, tRepo.revokedSessions)
+}
+
+func TestLogin_PasswordResetRequired(t *testing.T) {
+	cfg := &config.JWTConfig{AccessSecret: "secret", AccessExpiration: time.Minute, RefreshExpiration: time.Hour}
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	uRepo := &fakeUserRepo{user: &models.User{ID: 1, Email: "user@example.com", PasswordHash: string(hash), PasswordResetRequired: true}}
+	svc := NewAuthService(cfg, uRepo, &fakeTokenRepo{}, &fakeBlacklistRepo{}, nil)
+
+	_, err = svc.Login(context.Background(), "user@example.com", "wrong-password")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = svc.Login(context.Background(), "user@example.com", "password123")
+	require.ErrorIs(t, err, ErrPasswordResetRequired)
+}
+
+func TestSecurityService_EmailVerification(t *testing.T) {
+	cfg := &config.SecurityConfig{VerifyEmailURL: "https://auth.example.com/auth/verify-email", VerifyEmailTTL: 72 * time.Hour}
+	uRepo := &fakeUserRepo{user: &models.User{ID: 1, Email: "user@example.com"}}
+
+	disabled := NewSecurityService(cfg, uRepo, &fakeTokenRepo{}, &fakeSecurityRepo{}, nil)
+	require.ErrorIs(t, disabled.SendEmailVerification(context.Background(), 1), ErrEmailDisabled)
+
+	sRepo := &fakeSecurityRepo{}
+	mail := &fakeMailer{}
+	svc := NewSecurityService(cfg, uRepo, &fakeTokenRepo{}, sRepo, notify(t, mail))
+	require.NoError(t, svc.SendEmailVerification(context.Background(), 1))
+	require.Len(t, mail.sent, 1)
+	require.Equal(t, "Verify your email address", mail.sent[0].subject)
+	require.Contains(t, mail.sent[0].body, "https://auth.example.com/auth/verify-email?token=")
+	require.Contains(t, mail.sent[0].body, "72 hours")
+
+	token := tokenFromMail(t, mail.sent[0])
+	require.NoError(t, svc.VerifyEmail(context.Background(), token))
+	require.True(t, sRepo.emailVerified)
+	require.ErrorIs(t, svc.VerifyEmail(context.Background(), token), repository.ErrSecurityTokenInvalid)
+
+	uRepo.user.EmailVerified = true
+	require.ErrorIs(t, svc.SendEmailVerification(context.Background(), 1), ErrEmailAlreadyVerified)
+	require.Len(t, mail.sent, 1)
+}
+
+func TestLogin_EmailNotVerified(t *testing.T) {
+	cfg := &config.JWTConfig{AccessSecret: "secret", AccessExpiration: time.Minute, RefreshExpiration: time.Hour}
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	uRepo := &fakeUserRepo{user: &models.User{ID: 1, Email: "user@example.com", PasswordHash: string(hash)}}
+
+	// Unverified accounts sign in unless verification is required
+	_, err = NewAuthService(cfg, uRepo, &fakeTokenRepo{}, &fakeBlacklistRepo{}, nil).Login(context.Background(), "user@example.com", "password123")
+	require.NoError(t, err)
+
+	cfg.RequireVerifiedEmail = true
+	svc := NewAuthService(cfg, uRepo, &fakeTokenRepo{}, &fakeBlacklistRepo{}, nil)
+	_, err = svc.Login(context.Background(), "user@example.com", "password123")
+	require.ErrorIs(t, err, ErrEmailNotVerified)
+
+	uRepo.user.EmailVerified = true
+	_, err = svc.Login(context.Background(), "user@example.com", "password123")
+	require.NoError(t, err)
+}
+
+func TestLogin_PasswordChangeRequired(t *testing.T) {
+	cfg := &config.JWTConfig{AccessSecret: "secret", AccessExpiration: time.Minute, RefreshExpiration: time.Hour}
+	hash, err := bcrypt.GenerateFromPassword([]byte("temporary1"), bcrypt.MinCost)
+	require.NoError(t, err)
+	uRepo := &fakeUserRepo{user: &models.User{ID: 1, Email: "user@example.com", PasswordHash: string(hash), PasswordChangeRequired: true}}
+	svc := NewAuthService(cfg, uRepo, &fakeTokenRepo{}, &fakeBlacklistRepo{}, nil)
+
+	_, err = svc.Login(context.Background(), "user@example.com", "temporary1")
+	require.ErrorIs(t, err, ErrPasswordChangeRequired)
+}
+
+func TestSecurityService_ChangePassword(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("temporary1"), bcrypt.MinCost)
+	require.NoError(t, err)
+	uRepo := &fakeUserRepo{user: &models.User{ID: 1, Email: "user@example.com", PasswordHash: string(hash), PasswordChangeRequired: true}}
+	sRepo := &fakeSecurityRepo{changeRequired: true}
+	svc := NewSecurityService(&config.SecurityConfig{}, uRepo, &fakeTokenRepo{}, sRepo, nil)
+	ctx := context.Background()
+
+	require.ErrorIs(t, svc.ChangePassword(ctx, "user@example.com", "wrong-password", "new-password"), ErrInvalidCredentials)
+	require.ErrorIs(t, svc.ChangePassword(ctx, "user@example.com", "temporary1", "temporary1"), ErrPasswordUnchanged)
+	require.True(t, sRepo.changeRequired)
+
+	require.NoError(t, svc.ChangePassword(ctx, "user@example.com", "temporary1", "new-password"))
+	require.False(t, sRepo.changeRequired)
+	require.True(t, sRepo.sessionsRevoked)
+	require.NoError(t, bcrypt.CompareHashAndPassword([]byte(sRepo.passwordHash), []byte("new-password")))
+
+	// Accounts locked down for security must use the emailed reset link
+	uRepo.user.PasswordResetRequired = true
+	require.ErrorIs(t, svc.ChangePassword(ctx, "user@example.com", "temporary1", "new-password"), ErrPasswordResetRequired)
+}
+
+func TestSecurityService_Invite(t *testing.T) {
+	cfg := &config.SecurityConfig{AppURL: "https://shop.example.com", InvitationTTL: 168 * time.Hour}
+	uRepo := &fakeUserRepo{user: &models.User{ID: 1, Email: "user@example.com"}}
+	ctx := context.Background()
+
+	disabled := NewSecurityService(cfg, uRepo, &fakeTokenRepo{}, &fakeSecurityRepo{}, nil)
+	require.ErrorIs(t, disabled.Invite(ctx, 1, false), ErrEmailDisabled)
+
+	sRepo := &fakeSecurityRepo{}
+	mail := &fakeMailer{}
+	svc := NewSecurityService(cfg, uRepo, &fakeTokenRepo{}, sRepo, notify(t, mail))
+	require.NoError(t, svc.Invite(ctx, 1, false))
+	require.Len(t, mail.sent, 1)
+	require.Equal(t, "user@example.com", mail.sent[0].to)
+	require.Equal(t, "You're invited to Marketback", mail.sent[0].subject)
+	require.Contains(t, mail.sent[0].body, "https://shop.example.com/security/reset-password?token=")
+	require.Contains(t, mail.sent[0].body, "168 hours")
+
+	// The invitation link chooses the password like a reset link
+	require.NoError(t, svc.ResetPassword(ctx, tokenFromMail(t, mail.sent[0]), "chosen-password"))
+	require.NotEmpty(t, sRepo.passwordHash)
+
+	require.NoError(t, svc.Invite(ctx, 1, true))
+	require.Len(t, mail.sent, 2)
+	require.NotContains(t, mail.sent[1].body, "token=")
+	require.Contains(t, mail.sent[1].body, "https://shop.example.com")
+
+	svc = NewSecurityService(cfg, uRepo, &fakeTokenRepo{}, sRepo, notify(t, mail, mailer.EventInvitation))
+	require.ErrorIs(t, svc.Invite(ctx, 1, false), ErrEmailDisabled)
+	require.Len(t, mail.sent, 2)
+}
+
+func TestLogin_Suspended(t *testing.T) {
+	cfg := &config.JWTConfig{AccessSecret: "secret", AccessExpiration: time.Minute, RefreshExpiration: time.Hour}
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	uRepo := &fakeUserRepo{user: &models.User{ID: 1, Email: "user@example.com", PasswordHash: string(hash), Suspended: true}}
+	svc := NewAuthService(cfg, uRepo, &fakeTokenRepo{}, &fakeBlacklistRepo{}, nil)
+
+	_, err = svc.Login(context.Background(), "user@example.com", "password123")
+	require.ErrorIs(t, err, ErrAccountSuspended)
+}
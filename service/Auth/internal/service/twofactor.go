@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/Zifeldev/marketback/service/Auth/internal/totp"
+)
+
+var (
+	ErrTwoFactorRequired = errors.New("two-factor authentication required")
+	ErrInvalidOTP        = errors.New("invalid two-factor code")
+	ErrTwoFactorNotSetUp = errors.New("two-factor authentication not set up")
+)
+
+// TwoFactorService enrolls users in TOTP two-factor authentication and
+// verifies codes for sensitive actions such as granting the admin role
+type TwoFactorService interface {
+	// Setup creates a new secret and returns it with its otpauth:// URI
+	Setup(ctx context.Context, userID int64, email string) (string, string, error)
+	Enable(ctx context.Context, userID int64, code string) error
+	Disable(ctx context.Context, userID int64, code string) error
+	// Verify checks a code of a user with two-factor authentication
+	// enabled; users without it get ErrTwoFactorRequired
+	Verify(ctx context.Context, userID int64, code string) error
+}
+
+type twoFactorService struct {
+	repo   repository.TwoFactorRepository
+	issuer string
+	now    func() time.Time
+}
+
+func NewTwoFactorService(repo repository.TwoFactorRepository, issuer string) TwoFactorService {
+	return &twoFactorService{
+		repo:   repo,
+		issuer: issuer,
+		now:    time.Now,
+	}
+}
+
+func (s *twoFactorService) Setup(ctx context.Context, userID int64, email string) (string, string, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := s.repo.SetSecret(ctx, userID, secret); err != nil {
+		return "", "", err
+	}
+
+	return secret, totp.URI(s.issuer, email, secret), nil
+}
+
+func (s *twoFactorService) Enable(ctx context.Context, userID int64, code string) error {
+	secret, enabled, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if enabled {
+		return repository.ErrTwoFactorEnabled
+	}
+	if secret == "" {
+		return ErrTwoFactorNotSetUp
+	}
+
+	if !totp.Validate(secret, code, s.now()) {
+		return ErrInvalidOTP
+	}
+
+	return s.repo.Enable(ctx, userID)
+}
+
+func (s *twoFactorService) Disable(ctx context.Context, userID int64, code string) error {
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+	return s.repo.Disable(ctx, userID)
+}
+
+func (s *twoFactorService) Verify(ctx context.Context, userID int64, code string) error {
+	secret, enabled, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return ErrTwoFactorRequired
+	}
+
+	if !totp.Validate(secret, code, s.now()) {
+		return ErrInvalidOTP
+	}
+
+	return nil
+}
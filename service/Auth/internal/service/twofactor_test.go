@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/Zifeldev/marketback/service/Auth/internal/totp"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTwoFactorRepo struct {
+	secret  string
+	enabled bool
+}
+
+func (f *fakeTwoFactorRepo) Get(ctx context.Context, userID int64) (string, bool, error) {
+	return f.secret, f.enabled, nil
+}
+func (f *fakeTwoFactorRepo) SetSecret(ctx context.Context, userID int64, secret string) error {
+	if f.enabled {
+		return repository.ErrTwoFactorEnabled
+	}
+	f.secret = secret
+	return nil
+}
+func (f *fakeTwoFactorRepo) Enable(ctx context.Context, userID int64) error {
+	f.enabled = true
+	return nil
+}
+func (f *fakeTwoFactorRepo) Disable(ctx context.Context, userID int64) error {
+	f.secret, f.enabled = "", false
+	return nil
+}
+func (f *fakeTwoFactorRepo) Rotate(ctx context.Context, batchSize int) (int, error) {
+	return 0, nil
+}
+
+func TestTwoFactorService_Lifecycle(t *testing.T) {
+	repo := &fakeTwoFactorRepo{}
+	svc := NewTwoFactorService(repo, "Marketback")
+	ctx := context.Background()
+
+	require.ErrorIs(t, svc.Verify(ctx, 1, "123456"), ErrTwoFactorRequired)
+
+	secret, uri, err := svc.Setup(ctx, 1, "admin@example.com")
+	require.NoError(t, err)
+	require.Contains(t, uri, secret)
+
+	require.ErrorIs(t, svc.Enable(ctx, 1, "000000"), ErrInvalidOTP)
+	require.ErrorIs(t, svc.Verify(ctx, 1, "000000"), ErrTwoFactorRequired, "setup alone does not enable")
+
+	code, err := totp.Code(secret, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, svc.Enable(ctx, 1, code))
+	require.NoError(t, svc.Verify(ctx, 1, code))
+
+	_, _, err = svc.Setup(ctx, 1, "admin@example.com")
+	require.ErrorIs(t, err, repository.ErrTwoFactorEnabled)
+
+	require.NoError(t, svc.Disable(ctx, 1, code))
+	require.ErrorIs(t, svc.Verify(ctx, 1, code), ErrTwoFactorRequired)
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MaxImportRows bounds the users imported from one file, keeping an import
+// within the request timeout
+const MaxImportRows = 200
+
+// ErrInvalidImport is returned, wrapped with the reason, for files that
+// cannot be imported at all
+var ErrInvalidImport = errors.New("invalid import file")
+
+// Columns of an import file; role and password are optional
+const (
+	importColumnEmail    = "email"
+	importColumnRole     = "role"
+	importColumnPassword = "password"
+)
+
+// UserImportService creates users in bulk for admins, e.g. the employees of
+// a corporate buyer program.
+type UserImportService interface {
+	// Import creates a user per row of a CSV file with a header naming the
+	// columns email, role (default user) and password. Rows are validated
+	// and created one by one and reported in file order; a failed row does
+	// not stop the others. A password is temporary: it must be changed
+	// before signing in. Rows without one need invitations, which carry a
+	// link choosing the password. With dryRun nothing is created.
+	Import(ctx context.Context, adminID int64, r io.Reader, dryRun bool) (*models.UserImportResult, error)
+}
+
+type userImportService struct {
+	userRepo     repository.UserRepository
+	roleRepo     repository.RoleRepository
+	securityRepo repository.SecurityRepository
+	// invitations reports whether imported users are emailed an
+	// invitation
+	invitations bool
+}
+
+func NewUserImportService(userRepo repository.UserRepository, roleRepo repository.RoleRepository, securityRepo repository.SecurityRepository, invitations bool) UserImportService {
+	return &userImportService{
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		securityRepo: securityRepo,
+		invitations:  invitations,
+	}
+}
+
+// importRecord is a parsed row of an import file
+type importRecord struct {
+	line     int
+	email    string
+	role     string
+	password string
+	err      string
+}
+
+func (s *userImportService) Import(ctx context.Context, adminID int64, r io.Reader, dryRun bool) (*models.UserImportResult, error) {
+	records, err := readImport(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.UserImportResult{DryRun: dryRun, Invited: s.invitations && !dryRun, Rows: make([]models.UserImportRow, 0, len(records))}
+	seen := make(map[string]int, len(records))
+	for _, rec := range records {
+		row := models.UserImportRow{Line: rec.line, Email: rec.email, Role: rec.role, TemporaryPassword: rec.password != ""}
+		if msg := rec.err; msg != "" {
+			row.Error = msg
+		} else if msg := s.validate(rec); msg != "" {
+			row.Error = msg
+		} else if line, dup := seen[strings.ToLower(rec.email)]; dup {
+			row.Error = fmt.Sprintf("duplicate of line %d", line)
+		}
+		if _, dup := seen[strings.ToLower(rec.email)]; !dup && rec.email != "" {
+			seen[strings.ToLower(rec.email)] = rec.line
+		}
+
+		if row.Error == "" {
+			if dryRun {
+				row.Error, err = s.check(ctx, rec)
+			} else {
+				row.UserID, row.Error, err = s.create(ctx, adminID, rec)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		switch {
+		case row.Error != "":
+			row.Status = models.ImportRowFailed
+			result.Failed++
+		case dryRun:
+			row.Status = models.ImportRowValid
+			result.Valid++
+		default:
+			row.Status = models.ImportRowCreated
+			result.Valid++
+			result.Created++
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return result, nil
+}
+
+// readImport parses the header and rows of an import file
+func readImport(r io.Reader) ([]importRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: the file is empty", ErrInvalidImport)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		switch name {
+		case importColumnEmail, importColumnRole, importColumnPassword:
+		default:
+			return nil, fmt.Errorf("%w: unknown column %q, must be email, role or password", ErrInvalidImport, name)
+		}
+		if _, dup := columns[name]; dup {
+			return nil, fmt.Errorf("%w: duplicate column %q", ErrInvalidImport, name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns[importColumnEmail]; !ok {
+		return nil, fmt.Errorf("%w: the header must name an email column", ErrInvalidImport)
+	}
+
+	field := func(fields []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(fields) {
+			return ""
+		}
+		return strings.TrimSpace(fields[i])
+	}
+
+	var records []importRecord
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+		}
+		if len(records) == MaxImportRows {
+			return nil, fmt.Errorf("%w: at most %d rows can be imported at once", ErrInvalidImport, MaxImportRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		rec := importRecord{
+			line:     line,
+			email:    field(fields, importColumnEmail),
+			role:     field(fields, importColumnRole),
+			password: field(fields, importColumnPassword),
+		}
+		if len(fields) != len(header) {
+			rec.err = fmt.Sprintf("expected %d fields, got %d", len(header), len(fields))
+		}
+		records = append(records, rec)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: the file has no rows", ErrInvalidImport)
+	}
+	return records, nil
+}
+
+// validate returns why a row cannot be imported, or "" when it can
+func (s *userImportService) validate(rec importRecord) string {
+	if rec.email == "" {
+		return "email is required"
+	}
+	if addr, err := mail.ParseAddress(rec.email); err != nil || addr.Address != rec.email {
+		return "email is not a valid address"
+	}
+	if rec.role != "" {
+		if err := models.ValidateRole(rec.role); err != nil {
+			return err.Error()
+		}
+		if models.IsAdmin(rec.role) {
+			return "the admin role cannot be imported; grant it to the user afterwards"
+		}
+	}
+	if rec.password == "" && !s.invitations {
+		return "a temporary password is required while invitation emails are disabled"
+	}
+	if rec.password != "" && len(rec.password) < 8 {
+		return "password must be at least 8 characters"
+	}
+	return ""
+}
+
+// check reports a valid row whose user already exists in a dry run
+func (s *userImportService) check(ctx context.Context, rec importRecord) (string, error) {
+	_, err := s.userRepo.GetByEmail(ctx, rec.email)
+	switch {
+	case err == nil:
+		return "user already exists", nil
+	case errors.Is(err, repository.ErrUserNotFound):
+		return "", nil
+	default:
+		return "", err
+	}
+}
+
+// create creates the user of a valid row. Users without a temporary
+// password get a random one nobody knows; they choose theirs from the
+// invitation.
+func (s *userImportService) create(ctx context.Context, adminID int64, rec importRecord) (int64, string, error) {
+	password, cost := rec.password, bcrypt.DefaultCost
+	if password == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return 0, "", err
+		}
+		// A random secret is not guessable at any cost
+		password, cost = base64.RawURLEncoding.EncodeToString(b), bcrypt.MinCost
+	}
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return 0, "", err
+	}
+
+	// Created as a regular user; privileged roles are granted below so the
+	// grant is recorded in the role audit trail
+	user, err := s.userRepo.CreateWithRole(ctx, rec.email, string(passwordHash), models.RoleUser)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserExists) {
+			return 0, "user already exists", nil
+		}
+		return 0, "", err
+	}
+	if rec.role != "" && rec.role != models.RoleUser {
+		if _, err := s.roleRepo.SetRole(ctx, user.ID, rec.role, adminID, "imported by admin"); err != nil {
+			return 0, "", err
+		}
+	}
+	if rec.password != "" {
+		if err := s.securityRepo.SetPasswordChangeRequired(ctx, user.ID); err != nil {
+			return 0, "", err
+		}
+	}
+	return user.ID, "", nil
+}
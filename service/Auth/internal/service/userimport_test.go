@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Auth/internal/models"
+	"github.com/Zifeldev/marketback/service/Auth/internal/repository"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// importUserRepo keeps users by email
+type importUserRepo struct {
+	repository.UserRepository
+	users map[string]*models.User
+}
+
+func (f *importUserRepo) CreateWithRole(ctx context.Context, email, passwordHash, role string) (*models.User, error) {
+	if _, ok := f.users[email]; ok {
+		return nil, repository.ErrUserExists
+	}
+	user := &models.User{ID: int64(len(f.users) + 1), Email: email, PasswordHash: passwordHash, Role: role}
+	f.users[email] = user
+	return user, nil
+}
+
+func (f *importUserRepo) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	if user, ok := f.users[email]; ok {
+		return user, nil
+	}
+	return nil, repository.ErrUserNotFound
+}
+
+type importRoleRepo struct {
+	repository.RoleRepository
+	granted map[int64]string
+}
+
+func (f *importRoleRepo) SetRole(ctx context.Context, userID int64, role string, adminID int64, reason string) (*models.User, error) {
+	f.granted[userID] = role
+	return &models.User{ID: userID, Role: role}, nil
+}
+
+func TestUserImportService_Import(t *testing.T) {
+	uRepo := &importUserRepo{users: map[string]*models.User{"taken@example.com": {ID: 1, Email: "taken@example.com"}}}
+	rRepo := &importRoleRepo{granted: map[int64]string{}}
+	sRepo := &fakeSecurityRepo{}
+	svc := NewUserImportService(uRepo, rRepo, sRepo, true)
+
+	file := "\ufeffEmail,Role,Password\n" +
+		"ann@example.com,,\n" +
+		"bob@example.com,seller,temporary1\n" +
+		"ANN@example.com,,\n" +
+		"root@example.com,admin,\n" +
+		"not-an-email,,\n" +
+		"cat@example.com,,short\n" +
+		"taken@example.com,courier,\n" +
+		"dan@example.com\n"
+	result, err := svc.Import(context.Background(), 9, strings.NewReader(file), false)
+	require.NoError(t, err)
+	require.True(t, result.Invited)
+	require.Equal(t, 2, result.Created)
+	require.Equal(t, 6, result.Failed)
+	require.Len(t, result.Rows, 8)
+
+	ann, bob := result.Rows[0], result.Rows[1]
+	require.Equal(t, models.ImportRowCreated, ann.Status)
+	require.Equal(t, 2, ann.Line)
+	require.False(t, ann.TemporaryPassword)
+	require.Equal(t, models.RoleUser, uRepo.users["ann@example.com"].Role)
+
+	require.Equal(t, models.ImportRowCreated, bob.Status)
+	require.True(t, bob.TemporaryPassword)
+	require.Equal(t, models.RoleSeller, rRepo.granted[bob.UserID])
+	require.True(t, sRepo.changeRequired)
+	require.NoError(t, bcrypt.CompareHashAndPassword([]byte(uRepo.users["bob@example.com"].PasswordHash), []byte("temporary1")))
+
+	reasons := make([]string, 0, 6)
+	for _, row := range result.Rows[2:] {
+		require.Equal(t, models.ImportRowFailed, row.Status)
+		reasons = append(reasons, row.Error)
+	}
+	require.Equal(t, "duplicate of line 2", reasons[0])
+	require.Contains(t, reasons[1], "admin role cannot be imported")
+	require.Equal(t, "email is not a valid address", reasons[2])
+	require.Equal(t, "password must be at least 8 characters", reasons[3])
+	require.Equal(t, "user already exists", reasons[4])
+	require.Equal(t, "expected 3 fields, got 1", reasons[5])
+}
+
+func TestUserImportService_DryRun(t *testing.T) {
+	uRepo := &importUserRepo{users: map[string]*models.User{"taken@example.com": {ID: 1, Email: "taken@example.com"}}}
+	svc := NewUserImportService(uRepo, &importRoleRepo{granted: map[int64]string{}}, &fakeSecurityRepo{}, true)
+
+	result, err := svc.Import(context.Background(), 9, strings.NewReader("email\nann@example.com\ntaken@example.com\n"), true)
+	require.NoError(t, err)
+	require.False(t, result.Invited)
+	require.Equal(t, 1, result.Valid)
+	require.Zero(t, result.Created)
+	require.Equal(t, models.ImportRowValid, result.Rows[0].Status)
+	require.Equal(t, "user already exists", result.Rows[1].Error)
+	require.Len(t, uRepo.users, 1)
+}
+
+func TestUserImportService_WithoutInvitations(t *testing.T) {
+	uRepo := &importUserRepo{users: map[string]*models.User{}}
+	svc := NewUserImportService(uRepo, &importRoleRepo{granted: map[int64]string{}}, &fakeSecurityRepo{}, false)
+
+	// Users could never sign in without a password or an invitation
+	result, err := svc.Import(context.Background(), 9, strings.NewReader("email,password\nann@example.com,\nbob@example.com,temporary1\n"), false)
+	require.NoError(t, err)
+	require.False(t, result.Invited)
+	require.Contains(t, result.Rows[0].Error, "temporary password is required")
+	require.Equal(t, models.ImportRowCreated, result.Rows[1].Status)
+}
+
+func TestUserImportService_InvalidFile(t *testing.T) {
+	svc := NewUserImportService(&importUserRepo{users: map[string]*models.User{}}, &importRoleRepo{}, &fakeSecurityRepo{}, true)
+
+	for name, file := range map[string]string{
+		"empty":          "",
+		"no rows":        "email,role\n",
+		"no email":       "role,password\nuser,temporary1\n",
+		"unknown column": "email,name\nann@example.com,Ann\n",
+		"repeated":       "email,email\nann@example.com,ann@example.com\n",
+		"too many rows":  "email\n" + strings.Repeat("ann@example.com\n", MaxImportRows+1),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.Import(context.Background(), 9, strings.NewReader(file), false)
+			require.ErrorIs(t, err, ErrInvalidImport)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// RFC 6238 appendix B test vectors for SHA1, truncated to six digits
+func TestCode_RFC6238(t *testing.T) {
+	secret := encoding.EncodeToString([]byte("12345678901234567890"))
+
+	cases := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, want := range cases {
+		got, err := Code(secret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		require.Equal(t, want, got, "time %d", unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+
+	now := time.Now()
+	current, err := Code(secret, now)
+	require.NoError(t, err)
+	previous, err := Code(secret, now.Add(-step))
+	require.NoError(t, err)
+	old, err := Code(secret, now.Add(-3*step))
+	require.NoError(t, err)
+
+	require.True(t, Validate(secret, current, now))
+	require.True(t, Validate(secret, previous, now))
+	require.False(t, Validate(secret, old, now))
+	require.False(t, Validate(secret, "12345", now))
+	require.False(t, Validate("not base32!", current, now))
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Marketback", "admin@example.com", "JBSWY3DPEHPK3PXP")
+	require.True(t, strings.HasPrefix(uri, "otpauth://totp/Marketback:admin@example.com?"))
+	require.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+	require.Contains(t, uri, "issuer=Marketback")
+}
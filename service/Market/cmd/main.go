@@ -9,26 +9,63 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/Zifeldev/marketback/clients/authclient"
+	"github.com/Zifeldev/marketback/pkg/fieldcrypt"
+	"github.com/Zifeldev/marketback/service/Market/internal/apiusage"
+	"github.com/Zifeldev/marketback/service/Market/internal/botdetect"
+	"github.com/Zifeldev/marketback/service/Market/internal/buildinfo"
 	"github.com/Zifeldev/marketback/service/Market/internal/cache"
+	"github.com/Zifeldev/marketback/service/Market/internal/cachewarm"
+	"github.com/Zifeldev/marketback/service/Market/internal/chaos"
 	"github.com/Zifeldev/marketback/service/Market/internal/config"
 	"github.com/Zifeldev/marketback/service/Market/internal/controllers"
 	"github.com/Zifeldev/marketback/service/Market/internal/db"
+	"github.com/Zifeldev/marketback/service/Market/internal/events"
+	"github.com/Zifeldev/marketback/service/Market/internal/exports"
+	"github.com/Zifeldev/marketback/service/Market/internal/feed"
+	"github.com/Zifeldev/marketback/service/Market/internal/geocode"
+	"github.com/Zifeldev/marketback/service/Market/internal/httpserver"
+	"github.com/Zifeldev/marketback/service/Market/internal/listing"
 	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/mailer"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
 	"github.com/Zifeldev/marketback/service/Market/internal/middleware"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/moderation"
+	"github.com/Zifeldev/marketback/service/Market/internal/openapi"
+	"github.com/Zifeldev/marketback/service/Market/internal/ordernumber"
+	"github.com/Zifeldev/marketback/service/Market/internal/pagecache"
+	"github.com/Zifeldev/marketback/service/Market/internal/payment"
+	"github.com/Zifeldev/marketback/service/Market/internal/payouts"
+	"github.com/Zifeldev/marketback/service/Market/internal/productimport"
+	"github.com/Zifeldev/marketback/service/Market/internal/productviews"
+	"github.com/Zifeldev/marketback/service/Market/internal/readonly"
+	"github.com/Zifeldev/marketback/service/Market/internal/reload"
 	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/reservation"
+	"github.com/Zifeldev/marketback/service/Market/internal/retention"
+	"github.com/Zifeldev/marketback/service/Market/internal/sellerdomain"
+	"github.com/Zifeldev/marketback/service/Market/internal/sellerhealth"
 	"github.com/Zifeldev/marketback/service/Market/internal/service"
+	"github.com/Zifeldev/marketback/service/Market/internal/session"
+	"github.com/Zifeldev/marketback/service/Market/internal/settings"
+	"github.com/Zifeldev/marketback/service/Market/internal/shipping"
+	"github.com/Zifeldev/marketback/service/Market/internal/statements"
+	"github.com/Zifeldev/marketback/service/Market/internal/stockalerts"
+	"github.com/Zifeldev/marketback/service/Market/internal/storage"
+	"github.com/Zifeldev/marketback/service/Market/internal/transform"
+	"github.com/Zifeldev/marketback/service/Market/internal/trending"
+	"github.com/Zifeldev/marketback/service/Market/internal/users"
+	"github.com/Zifeldev/marketback/service/Market/internal/workers"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
-	ginprometheus "github.com/zsais/go-gin-prometheus"
 
 	_ "github.com/Zifeldev/marketback/service/Market/docs"
 )
 
-// Version is set at build time
-var Version = "1.0.0"
-
 // @title Market Service API
 // @version 1.0
 // @description Marketplace API with products, categories, cart, orders, seller and admin management
@@ -49,8 +86,18 @@ func main() {
 	}
 
 	// Initialize logger
-	log := logger.InitLogger(cfg.Logger.Level)
-	log.Info("Starting Market Service...")
+	build := buildinfo.Get()
+	log := logger.Init(logger.Options{
+		Level:            cfg.Logger.Level,
+		Format:           cfg.Logger.Format,
+		SampleFirst:      cfg.Logger.SampleFirst,
+		SampleThereafter: cfg.Logger.SampleThereafter,
+		Fields: map[string]interface{}{
+			"service": "market",
+			"version": build.Version,
+		},
+	})
+	log.WithField("commit", build.Commit).WithField("build_date", build.BuildDate).Info("Starting Market Service...")
 
 	// Initialize database
 	pool, err := db.InitDB(&cfg.Database)
@@ -58,6 +105,7 @@ func main() {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 	defer pool.Close()
+	metrics.RegisterPoolMetrics(pool)
 	log.Info("Database connection established")
 
 	// Initialize Redis cache
@@ -87,18 +135,317 @@ func main() {
 	}
 
 	// Initialize repositories
-	sellerRepo := repository.NewSellerRepository(pool)
 	categoryRepo := repository.NewCategoryRepository(pool, redisCache)
-	productRepo := repository.NewProductRepository(pool)
-	cartRepo := repository.NewCartRepository(pool)
-	orderRepo := repository.NewOrderRepository(pool)
+	readOnly := readonly.Parse(cfg.Maintenance.ReadOnlyTables)
+	if tables := readOnly.Tables(); len(tables) > 0 {
+		log.Warnf("Read-only tables: %v (writes will return 503)", tables)
+	}
+	productRepo := repository.NewProductRepository(pool, readOnly)
+	cartRepo := repository.NewCartRepository(pool, cfg.Reservation.TTL)
+	orderNumbers, err := ordernumber.NewGenerator(cfg.Order.NumberPrefix, cfg.Order.NumberFormat, cfg.Order.NumberSeqWidth)
+	if err != nil {
+		log.Fatalf("Invalid order number configuration: %v", err)
+	}
+	keyring, err := fieldcrypt.Load(cfg.Encryption.Keys, cfg.Encryption.KeysFile)
+	if err != nil {
+		log.Fatalf("Failed to load encryption keys: %v", err)
+	}
+	if keyring == nil {
+		log.Warn("Column encryption: DISABLED (ENCRYPTION_KEYS not set)")
+	} else {
+		log.Infof("Column encryption: ENABLED (primary key %s)", keyring.PrimaryKeyID())
+	}
+
+	// Email; the sandbox captures it in an outbox instead of sending it
+	var (
+		mail   mailer.Mailer
+		outbox *mailer.Outbox
+	)
+	switch {
+	case cfg.Mail.Sandbox:
+		outbox, err = mailer.NewOutbox(cfg.Mail.From, cfg.Mail.SandboxDir, 500)
+		if err != nil {
+			log.Fatalf("Invalid email sandbox configuration: %v", err)
+		}
+		mail = outbox
+		log.Warn("Email notifications: SANDBOX (captured, see GET /api/dev/outbox)")
+	case cfg.Mail.Provider == "sendgrid":
+		mail, err = mailer.NewSendGrid(cfg.Mail.SendGridAPIKey, cfg.Mail.From)
+		if err != nil {
+			log.Fatalf("Invalid email configuration: %v", err)
+		}
+		log.Info("Email notifications: ENABLED (SendGrid)")
+	default:
+		mail, err = mailer.New(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, cfg.Mail.From)
+		if err != nil {
+			log.Fatalf("Invalid email configuration: %v", err)
+		}
+		if mail == nil {
+			log.Info("Email notifications: DISABLED (SMTP_HOST not set)")
+		}
+	}
+	notifier, err := mailer.NewNotifier(mail, cfg.Mail.TemplateDir, cfg.Mail.DisabledEvents)
+	if err != nil {
+		log.Fatalf("Invalid email template configuration: %v", err)
+	}
+
+	// Admin order and seller lists show user emails looked up in Auth over gRPC
+	var userDirectory *users.Directory
+	if cfg.Auth.GRPCAddr != "" {
+		authGRPC, err := authclient.NewGRPC(cfg.Auth.GRPCAddr, cfg.Auth.ClientID, cfg.Auth.ClientSecret)
+		if err != nil {
+			log.Fatalf("Failed to create Auth gRPC client: %v", err)
+		}
+		defer authGRPC.Close()
+		userDirectory = users.NewDirectory(authGRPC)
+		log.WithField("auth_grpc_addr", cfg.Auth.GRPCAddr).Info("User emails in admin lists: ENABLED")
+	} else {
+		log.Info("User emails in admin lists: DISABLED (AUTH_GRPC_ADDR not set)")
+	}
+	// Domain events are recorded with the changes they describe and relayed
+	// in the background, to the broker and to buyers as order status emails
+	statusEmails := notifier.Enabled(mailer.EventOrderStatus) && userDirectory != nil
+	var eventOutbox *repository.EventOutbox
+	if cfg.Events.Enabled() || statusEmails {
+		eventOutbox = repository.NewEventOutbox(cfg.Events.LowStockThreshold)
+	}
+	sellerRepo := repository.NewSellerRepository(pool, keyring)
+	orderRepo := repository.NewOrderRepository(pool, orderNumbers, keyring, readOnly, eventOutbox)
+	deliveryRepo := repository.NewDeliveryRepository(pool, keyring, readOnly, eventOutbox)
+	// Moderators banning a user suspend the account in Auth
+	var (
+		authClient *authclient.Client
+		accounts   repository.AccountSuspender
+	)
+	if cfg.Auth.URL != "" {
+		authClient = authclient.New(cfg.Auth.URL, cfg.Auth.ClientID, cfg.Auth.ClientSecret)
+		accounts = authClient
+	}
+	reportRepo := repository.NewReportRepository(pool, accounts)
+	reviewRepo := repository.NewReviewRepository(pool, readOnly)
+	shippingRepo := repository.NewShippingRepository(pool, readOnly)
+	variantRepo := repository.NewVariantRepository(pool, readOnly)
+	inventoryRepo := repository.NewInventoryRepository(pool, readOnly)
+	notificationRepo := repository.NewNotificationRepository(pool)
+	commissionRepo := repository.NewCommissionRepository(pool)
+	adminViewRepo := repository.NewAdminViewRepository(pool)
+	promoRepo := repository.NewPromoRepository(pool)
+	statementRepo := repository.NewStatementRepository(pool)
+	sellerHealthRepo := repository.NewSellerHealthRepository(pool)
+	ticketRepo := repository.NewTicketRepository(pool)
+	shareRepo := repository.NewShareLinkRepository(pool)
+	apiUsageRepo := repository.NewAPIUsageRepository(pool)
+	userMergeRepo := repository.NewUserMergeRepository(pool)
+	storefrontRepo := repository.NewStorefrontRepository(pool)
+	settingsRepo := repository.NewSettingsRepository(pool)
+	transformRuleRepo := repository.NewTransformRuleRepository(pool)
+	catalogRevisionRepo := repository.NewCatalogRevisionRepository(pool, readOnly, cfg.Catalog.RevisionsKept)
+	if keyring != nil && cfg.Encryption.RotateOnStart {
+		go func() {
+			rotated, err := orderRepo.RotateDeliveryAddresses(context.Background(), 500)
+			if err != nil {
+				log.Errorf("Delivery address rotation failed after %d orders: %v", rotated, err)
+				return
+			}
+			log.Infof("Delivery address rotation finished: %d orders re-encrypted", rotated)
+		}()
+	}
+
+	var trendingTracker *trending.Tracker
+	if redisClient != nil {
+		trendingTracker = trending.NewTracker(redisClient, cfg.Trending.Decay)
+	}
+
+	// Product views for seller analytics, counted in memory and flushed
+	// periodically and on shutdown
+	sellerStatsRepo := repository.NewSellerStatsRepository(pool)
+	productViews := productviews.NewCounter(sellerStatsRepo)
+
+	// Site-wide settings; SUPPORT_EMAIL stays the default until an admin
+	// overrides it
+	siteSettings := settings.New(settingsRepo, redisCache)
+	siteSettings.SetDefault(settings.SupportEmail, cfg.Mail.SupportEmail)
 
 	// Initialize services
+	addressProvider, err := geocode.New(cfg.Address.Provider, cfg.Address.APIKey, cfg.Address.Timeout)
+	if err != nil {
+		log.Fatalf("Invalid address validation configuration: %v", err)
+	}
+	if addressProvider != nil {
+		log.Infof("Address validation: ENABLED (provider %s)", addressProvider.Name())
+	}
 	marketService := service.NewMarketService(
 		orderRepo,
 		cartRepo,
+		shippingRepo,
+		shipping.Limits{
+			MaxWeightGrams: cfg.Shipping.MaxWeightGrams,
+			MaxLengthMM:    cfg.Shipping.MaxLengthMM,
+			MaxGirthMM:     cfg.Shipping.MaxGirthMM,
+		},
+		addressProvider,
+		trendingTracker,
+		siteSettings,
+		notifier,
 	)
 
+	// Background workers report their liveness to the system status
+	workerRegistry := workers.NewRegistry()
+
+	// Retention jobs
+	if cfg.Retention.Enabled {
+		retentionRunner := retention.NewRunner(pool, cfg.Retention.DryRun,
+			retention.ExpiredCarts(cfg.Retention.CartTTL),
+			retention.OrderAddresses(cfg.Retention.OrderAddressTTL),
+			retention.PublishedEvents(cfg.Retention.EventOutboxTTL),
+			retention.UserMerges(cfg.Retention.AuditLogTTL),
+		)
+		retentionCtx, stopRetention := context.WithCancel(context.Background())
+		defer stopRetention()
+		workerRegistry.Go(retentionCtx, "retention", cfg.Retention.Interval, retentionRunner.Start)
+		log.Infof("Retention jobs: ENABLED (every %s, dry_run=%t)", cfg.Retention.Interval, cfg.Retention.DryRun)
+	}
+
+	viewsCtx, stopViews := context.WithCancel(context.Background())
+	defer stopViews()
+	workerRegistry.Go(viewsCtx, "product_views", cfg.SellerStats.ViewsFlushInterval, productViews.Start)
+
+	// Low stock alerts
+	lowStockScanner := stockalerts.NewScanner(sellerStatsRepo, cfg.SellerStats.LowStockThreshold)
+	lowStockCtx, stopLowStock := context.WithCancel(context.Background())
+	defer stopLowStock()
+	workerRegistry.Go(lowStockCtx, "low_stock", cfg.SellerStats.LowStockInterval, lowStockScanner.Start)
+
+	// Seller statements
+	if cfg.Statements.Enabled {
+		statementScheduler := statements.NewScheduler(statementRepo)
+		statementsCtx, stopStatements := context.WithCancel(context.Background())
+		defer stopStatements()
+		workerRegistry.Go(statementsCtx, "statements", cfg.Statements.Interval, statementScheduler.Start)
+		log.Infof("Seller statements: ENABLED (every %s)", cfg.Statements.Interval)
+	}
+
+	// Seller health rules
+	healthPolicy := sellerhealth.Policy{Window: cfg.Health.Window, ShipWithin: cfg.Health.ShipWithin}
+	if cfg.Health.Enabled {
+		healthEnforcer := sellerhealth.NewEnforcer(sellerHealthRepo, healthPolicy, cfg.Health.Cooldown)
+		healthCtx, stopHealth := context.WithCancel(context.Background())
+		defer stopHealth()
+		workerRegistry.Go(healthCtx, "seller_health", cfg.Health.Interval, healthEnforcer.Start)
+		log.Infof("Seller health rules: ENABLED (every %s, window %s)", cfg.Health.Interval, cfg.Health.Window)
+	}
+
+	// Product listing view
+	listingScheduler := listing.NewScheduler(productRepo)
+	listingCtx, stopListing := context.WithCancel(context.Background())
+	defer stopListing()
+	workerRegistry.Go(listingCtx, "listing", cfg.Listing.RefreshInterval, listingScheduler.Start)
+	log.Infof("Product listing refresh: every %s", cfg.Listing.RefreshInterval)
+
+	// Cart stock reservations
+	if cfg.Reservation.TTL > 0 {
+		reservationSweeper := reservation.NewSweeper(cartRepo)
+		reservationCtx, stopReservations := context.WithCancel(context.Background())
+		defer stopReservations()
+		workerRegistry.Go(reservationCtx, "reservation_sweep", cfg.Reservation.SweepInterval, reservationSweeper.Start)
+		log.Infof("Cart stock reservations: ENABLED (held %s, swept every %s)", cfg.Reservation.TTL, cfg.Reservation.SweepInterval)
+	} else {
+		log.Info("Cart stock reservations: DISABLED (RESERVATION_TTL=0)")
+	}
+
+	// Catalog feeds
+	var feedController *controllers.FeedController
+	if cfg.Feed.Enabled {
+		feedGenerator, err := feed.NewGenerator(productRepo, cfg.Feed.Dir, feed.Options{
+			Title:      cfg.Feed.Title,
+			SiteURL:    cfg.BaseURL,
+			Currency:   cfg.Feed.Currency,
+			ProductURL: cfg.ProductURL,
+		})
+		if err != nil {
+			log.Fatalf("Failed to initialize catalog feeds: %v", err)
+		}
+		feedCtx, stopFeeds := context.WithCancel(context.Background())
+		defer stopFeeds()
+		workerRegistry.Go(feedCtx, "feed", cfg.Feed.Interval, feedGenerator.Start)
+		feedController = controllers.NewFeedController(feedGenerator, feed.NewSigner(cfg.Feed.SigningKey), cfg.BaseURL, cfg.Feed.URLTTL)
+		log.Infof("Catalog feeds: ENABLED (every %s, URLs valid for %s)", cfg.Feed.Interval, cfg.Feed.URLTTL)
+	}
+
+	// Product exports: large ones run as background jobs
+	exportJobRepo := repository.NewExportJobRepository(pool)
+	var exportController *controllers.ExportController
+	if cfg.Export.Async() {
+		exportRunner, err := exports.NewRunner(exportJobRepo, productRepo, cfg.Export.Dir, cfg.Export.FileTTL)
+		if err != nil {
+			log.Fatalf("Failed to initialize product exports: %v", err)
+		}
+		exportCtx, stopExports := context.WithCancel(context.Background())
+		defer stopExports()
+		workerRegistry.Go(exportCtx, "exports", cfg.Export.Interval, exportRunner.Start)
+		exportController = controllers.NewExportController(sellerRepo, productRepo, exportJobRepo, exportRunner,
+			feed.NewSigner(cfg.Export.SigningKey), cfg.BaseURL, cfg.Export.AsyncThreshold)
+		log.Infof("Background product exports: ENABLED (over %d products, files kept for %s)", cfg.Export.AsyncThreshold, cfg.Export.FileTTL)
+	} else {
+		exportController = controllers.NewExportController(sellerRepo, productRepo, exportJobRepo, nil, nil, cfg.BaseURL, 0)
+		log.Info("Background product exports: DISABLED (EXPORT_SIGNING_KEY not set)")
+	}
+
+	// Admin transform rules for outbound events and notifications, loaded
+	// before the relay starts and reloaded periodically
+	transformRules := transform.NewRules(transformRuleRepo)
+	_ = transformRules.Refresh(context.Background())
+	transformsCtx, stopTransforms := context.WithCancel(context.Background())
+	defer stopTransforms()
+	workerRegistry.Go(transformsCtx, "transform_rules", cfg.Transforms.RefreshInterval, transformRules.Start)
+
+	// Domain events
+	var publishers []events.Publisher
+	if cfg.Events.Enabled() {
+		if cfg.Events.Broker == "nats" {
+			nats, err := events.NewNATS(cfg.Events.URL, cfg.Events.TopicPrefix, cfg.Events.Timeout)
+			if err != nil {
+				log.Fatalf("Failed to initialize event publishing: %v", err)
+			}
+			publishers = append(publishers, events.Transformed(nats, transformRules.Event))
+		} else {
+			kafka := events.NewKafka(cfg.Events.URL, cfg.Events.TopicPrefix, cfg.Events.Timeout)
+			publishers = append(publishers, events.Transformed(kafka, transformRules.Event))
+		}
+		log.Infof("Domain events: ENABLED (%s, topics %s.*, relayed every %s)", cfg.Events.Broker, cfg.Events.TopicPrefix, cfg.Events.Interval)
+	} else {
+		log.Info("Domain events: DISABLED (EVENTS_BROKER not set)")
+	}
+	if statusEmails {
+		publishers = append(publishers, events.NewMail(notifier, userDirectory))
+		log.Info("Order status emails: ENABLED")
+	} else {
+		log.Info("Order status emails: DISABLED (needs email, AUTH_GRPC_ADDR and the order_status event)")
+	}
+	if len(publishers) > 0 {
+		publisher := events.Fanout(publishers...)
+		defer publisher.Close()
+		eventsCtx, stopEvents := context.WithCancel(context.Background())
+		defer stopEvents()
+		relay := events.NewRelay(repository.NewEventRepository(pool), publisher, cfg.Events.BatchSize)
+		workerRegistry.Go(eventsCtx, "event_relay", cfg.Events.Interval, relay.Start)
+	}
+
+	// Seller API usage and plan limits
+	var apiUsageTracker *apiusage.Tracker
+	var apiUsageController *controllers.APIUsageController
+	if cfg.APIUsage.Enabled && redisClient != nil {
+		apiUsageTracker = apiusage.NewTracker(redisClient, apiUsageRepo, cfg.APIUsage.Plans, cfg.APIUsage.DefaultPlan, cfg.APIUsage.Window)
+		apiUsageRoller := apiusage.NewRoller(apiUsageTracker, apiUsageRepo)
+		apiUsageCtx, stopAPIUsage := context.WithCancel(context.Background())
+		defer stopAPIUsage()
+		workerRegistry.Go(apiUsageCtx, "api_usage_rollup", cfg.APIUsage.RollupInterval, apiUsageRoller.Start)
+		apiUsageController = controllers.NewAPIUsageController(apiUsageRepo, apiUsageTracker, cfg.APIUsage.Plans)
+		log.Infof("Seller API usage: ENABLED (plan window %s, rollup every %s)", cfg.APIUsage.Window, cfg.APIUsage.RollupInterval)
+	} else if cfg.APIUsage.Enabled {
+		log.Warn("Seller API usage: DISABLED (Redis unavailable)")
+	}
+
 	// Upload directory setup
 	uploadDir := cfg.UploadDir
 	if uploadDir == "" {
@@ -106,75 +453,336 @@ func main() {
 	}
 	baseURL := cfg.BaseURL
 
+	// Fault injection; config refuses to enable it in production
+	var injector *chaos.Injector
+	if cfg.Chaos.Enabled {
+		injector = chaos.New(chaos.Config{
+			Latency:     cfg.Chaos.Latency,
+			LatencyRate: cfg.Chaos.LatencyRate,
+			ErrorRate:   cfg.Chaos.ErrorRate,
+		})
+		log.Warnf("Fault injection ENABLED in %v: %s latency on %.0f%% and errors on %.0f%% of calls",
+			cfg.Chaos.Layers, cfg.Chaos.Latency, cfg.Chaos.LatencyRate*100, cfg.Chaos.ErrorRate*100)
+	}
+	var (
+		marketProducts   repository.ProductRepo  = productRepo
+		marketCategories repository.CategoryRepo = categoryRepo
+		marketCart       repository.CartRepo     = cartRepo
+		marketOrders     repository.OrderRepo    = orderRepo
+	)
+	if cfg.Chaos.Layer(chaos.LayerRepository) {
+		marketProducts = repository.NewChaosProductRepo(productRepo, injector)
+		marketCategories = repository.NewChaosCategoryRepo(categoryRepo, injector)
+		marketCart = repository.NewChaosCartRepo(cartRepo, injector)
+		marketOrders = repository.NewChaosOrderRepo(orderRepo, injector)
+	}
+
+	// Payments; config refuses the sandbox in production
+	var paymentController *controllers.PaymentController
+	var payoutProvider payment.Payouts
+	switch cfg.Payment.Provider {
+	case "sandbox":
+		sandbox := payment.NewSandbox(cfg.Payment.SandboxScenario, cfg.Payment.SandboxDelay)
+		paymentService := service.NewPaymentService(sandbox, repository.NewPaymentRepository(pool, readOnly, eventOutbox),
+			repository.NewPaymentMethodRepository(pool), orderRepo, repository.NewChargebackRepository(pool), cfg.Payment.ReturnURL)
+		sandbox.OnEvent(func(ctx context.Context, ev payment.Event) {
+			if _, err := paymentService.HandleEvent(ctx, ev); err != nil {
+				log.WithField("err", err).Errorf("Failed to settle sandbox payment %s", ev.Ref)
+			}
+		})
+		paymentController = controllers.NewPaymentController(paymentService)
+		payoutProvider = sandbox
+		log.Warnf("Payments: SANDBOX (scenario %s, delay %s); no money is moved", cfg.Payment.SandboxScenario, cfg.Payment.SandboxDelay)
+	case "stripe":
+		stripe := payment.NewStripe(cfg.Payment.StripeAPIURL, cfg.Payment.StripeSecretKey,
+			cfg.Payment.StripeWebhookSecret, cfg.Payment.Currency, cfg.Payment.Timeout)
+		paymentController = controllers.NewPaymentController(
+			service.NewPaymentService(stripe, repository.NewPaymentRepository(pool, readOnly, eventOutbox),
+				repository.NewPaymentMethodRepository(pool), orderRepo, repository.NewChargebackRepository(pool), cfg.Payment.ReturnURL))
+		payoutProvider = stripe
+		log.Infof("Payments: ENABLED (stripe, %s)", cfg.Payment.Currency)
+	default:
+		log.Info("Payments: DISABLED (PAYMENT_PROVIDER not set)")
+	}
+
+	// Seller payouts; sellers register accounts whenever the provider can pay them out
+	payoutRepo := repository.NewPayoutRepository(pool, keyring)
+	var payoutController *controllers.PayoutController
+	if payoutProvider != nil {
+		payoutController = controllers.NewPayoutController(payoutRepo, payoutProvider.PayoutMethods())
+	}
+	if cfg.Payouts.Enabled && payoutProvider != nil {
+		payoutRunner := payouts.NewRunner(payoutRepo, payoutProvider, cfg.Payment.Provider, cfg.Payouts.Minimum,
+			models.SettlementDelays{
+				Low:      cfg.Payouts.SettlementDelayLow,
+				Standard: cfg.Payouts.SettlementDelayStandard,
+				High:     cfg.Payouts.SettlementDelayHigh,
+				Trusted:  cfg.Payouts.TrustedSettlementDelay,
+			},
+			cfg.Payouts.MaxAttempts, cfg.Payouts.RetryBackoff)
+		payoutsCtx, stopPayouts := context.WithCancel(context.Background())
+		defer stopPayouts()
+		workerRegistry.Go(payoutsCtx, "payouts", cfg.Payouts.Interval, payoutRunner.Start)
+		log.Infof("Seller payouts: ENABLED (every %s, minimum %.2f, %s)", cfg.Payouts.Interval, cfg.Payouts.Minimum, cfg.Payment.Provider)
+	}
+	giftCardController := controllers.NewGiftCardController(repository.NewGiftCardRepository(pool))
+
 	// Initialize controllers
 	marketController := controllers.NewMarketController(
-		productRepo,
-		categoryRepo,
-		cartRepo,
-		orderRepo,
+		marketProducts,
+		marketCategories,
+		marketCart,
+		marketOrders,
 		marketService,
 	)
+	var checkers []moderation.Checker
+	if bannedWords := moderation.NewBannedWords(cfg.Moderation.BannedWords); bannedWords != nil {
+		checkers = append(checkers, bannedWords)
+	}
+	if cfg.Moderation.APIURL != "" {
+		checkers = append(checkers, moderation.NewAPIChecker(cfg.Moderation.APIURL, cfg.Moderation.APIKey, cfg.Moderation.APITimeout))
+	}
+	var moderator *moderation.Pipeline
+	if len(checkers) > 0 {
+		moderator = moderation.NewPipeline(cfg.Moderation.FailClosed, checkers...)
+		log.Infof("Content moderation: ENABLED (%d checkers, fail_closed=%t)", len(checkers), cfg.Moderation.FailClosed)
+	}
 	sellerController := controllers.NewSellerController(
 		sellerRepo,
 		productRepo,
+		reportRepo,
+		moderator,
+		catalogRevisionRepo,
+		siteSettings,
+	)
+	shippingController := controllers.NewShippingController(
+		sellerRepo,
+		productRepo,
+		shippingRepo,
+	)
+	variantController := controllers.NewVariantController(
+		sellerRepo,
+		productRepo,
+		variantRepo,
 	)
 	adminController := controllers.NewAdminController(
 		categoryRepo,
 		productRepo,
 		sellerRepo,
 		orderRepo,
+		deliveryRepo,
+		marketService,
+		readOnly,
+		userDirectory,
 	)
-	healthController := controllers.NewHealthController(pool, redisClient, startTime, Version)
-	uploadController, err := controllers.NewUploadController(uploadDir, baseURL)
+	store, err := storage.NewLocal(uploadDir, baseURL)
 	if err != nil {
-		log.Fatalf("Failed to create upload controller: %v", err)
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+	courierController := controllers.NewCourierController(deliveryRepo, store)
+	proofController := controllers.NewProofController(deliveryRepo, store)
+	pickupController := controllers.NewPickupController(deliveryRepo)
+	trendingController := controllers.NewTrendingController(productRepo, trendingTracker)
+	reportController := controllers.NewReportController(reportRepo)
+	reviewController := controllers.NewReviewController(reviewRepo, moderator)
+	commissionController := controllers.NewCommissionController(commissionRepo)
+	adminViewController := controllers.NewAdminViewController(adminViewRepo)
+	promoController := controllers.NewPromoController(sellerRepo, promoRepo)
+	statementController := controllers.NewStatementController(statementRepo)
+	userMergeController := controllers.NewUserMergeController(userMergeRepo)
+	storefrontController := controllers.NewStorefrontController(storefrontRepo)
+	domainResolver := sellerdomain.NewResolver(sellerRepo, cfg.Domains.ReservedHosts, cfg.Domains.CacheTTL)
+	log.WithField("reserved_hosts", cfg.Domains.ReservedHosts).Infof("Seller custom domains: ENABLED (lookups cached for %s)", cfg.Domains.CacheTTL)
+	sellerDomainController := controllers.NewSellerDomainController(sellerRepo, storefrontRepo, domainResolver)
+	settingsController := controllers.NewSettingsController(siteSettings)
+	productImportController := controllers.NewProductImportController(
+		productimport.New(cfg.Import.Timeout, cfg.Import.MaxPageSize),
+		sellerRepo,
+		productRepo,
+	)
+	inventoryController := controllers.NewInventoryController(sellerRepo, inventoryRepo, siteSettings)
+	catalogRevisionController := controllers.NewCatalogRevisionController(sellerRepo, catalogRevisionRepo)
+	sellerOrderController := controllers.NewSellerOrderController(orderRepo)
+	onboardingController := controllers.NewOnboardingController(sellerRepo, cfg.SellerDashboardURL)
+	sellerHealthController := controllers.NewSellerHealthController(sellerHealthRepo, healthPolicy)
+	shareController := controllers.NewShareController(shareRepo, cfg.Share.BaseURL, cfg.ProductURL)
+	sellerStatsController := controllers.NewSellerStatsController(sellerStatsRepo, cfg.SellerStats.LowStockThreshold)
+	ticketController := controllers.NewTicketController(ticketRepo, store, mail, siteSettings)
+	notificationController := controllers.NewNotificationController(notificationRepo, transformRules)
+	transformController := controllers.NewTransformController(transformRuleRepo, transformRules)
+	var outboxController *controllers.OutboxController
+	if outbox != nil {
+		outboxController = controllers.NewOutboxController(outbox)
 	}
+	healthController := controllers.NewHealthController(pool, redisClient, startTime, buildinfo.Version)
+	systemController := controllers.NewSystemController(pool, repository.NewSystemRepository(pool), redisClient, workerRegistry)
+	uploadController := controllers.NewUploadController(store)
 
 	// Setup Gin router
 	if cfg.Strict {
 		gin.SetMode(gin.ReleaseMode)
 	}
-	router := gin.Default()
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(middleware.RequestLogger())
 
-	// Prometheus metrics middleware
-	p := ginprometheus.NewPrometheus("market")
-	p.Use(router)
+	// Prometheus metrics
+	router.Use(middleware.Metrics())
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	// Middleware
 	router.Use(middleware.CORS())
+	router.Use(middleware.BodyLimit(cfg.HTTP.MaxJSONBody, cfg.HTTP.MaxUploadBody))
+	if cfg.Chaos.Layer(chaos.LayerHTTP) {
+		router.Use(middleware.Chaos(injector))
+	}
+	// Multipart parts beyond this size are spooled to temp files, not memory.
+	router.MaxMultipartMemory = 8 << 20
 
 	// Rate limiting
-	if redisCache != nil && cfg.RateLimit.Enabled {
-		router.Use(middleware.RateLimiter(redisCache, cfg.RateLimit.Max, cfg.RateLimit.Interval))
+	// The policy stays wired while Redis is up so a config reload can turn
+	// rate limiting on or off.
+	rateLimitPolicy := middleware.NewRateLimitPolicy(cfg.RateLimit.Enabled, cfg.RateLimit.Max, cfg.RateLimit.Interval)
+	if redisCache != nil {
+		router.Use(middleware.RateLimiterWithPolicy(redisCache, rateLimitPolicy))
 	}
 
+	// Bot detection
+	if cfg.Bots.Enabled {
+		var fingerprinter botdetect.Fingerprinter
+		if cfg.Bots.FingerprintURL != "" {
+			fingerprinter = botdetect.NewHTTPFingerprinter(cfg.Bots.FingerprintURL, cfg.Bots.FingerprintAPIKey, cfg.Bots.FingerprintTimeout)
+		}
+		botDetector := botdetect.New(cfg.Bots.BlockedAgents, fingerprinter)
+		router.Use(middleware.BotDetection(botDetector, redisCache, cfg.Bots.ScraperLimit, cfg.Bots.ScraperWindow))
+		log.Infof("Bot detection: ENABLED (scrapers limited to %d req/%s, fingerprinting: %t)", cfg.Bots.ScraperLimit, cfg.Bots.ScraperWindow, fingerprinter != nil)
+	}
+
+	// Config reload on SIGHUP or POST /api/admin/config/reload
+	reloader := reload.New(cfg, config.Load, log, rateLimitPolicy, readOnly)
+	reloadCtx, stopReload := context.WithCancel(context.Background())
+	defer stopReload()
+	go reloader.WatchSignals(reloadCtx)
+	configController := controllers.NewConfigController(reloader)
+
 	// Health check
 	router.GET("/health", healthController.Health)
+	router.GET("/version", healthController.Version)
 
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	spec, err := openapi.Spec()
+	if err != nil {
+		log.Fatalf("Failed to build OpenAPI document: %v", err)
+	}
+	router.GET("/openapi.json", openapi.Handler(spec))
 
 	// Static files for uploaded images
 	router.Static("/uploads", uploadDir)
 
+	var sitemapURL string
+	if feedController != nil {
+		router.GET("/sitemap.xml", feedController.GetSitemap)
+		if cfg.BaseURL != "" {
+			sitemapURL = cfg.BaseURL + "/sitemap.xml"
+		}
+	}
+	router.GET("/robots.txt", controllers.NewRobotsController(cfg.Bots.BlockedAgents, cfg.Bots.CrawlDelay, sitemapURL).GetRobots)
+
+	// Short links for shared products
+	router.GET("/s/:code", shareController.FollowShareLink)
+
+	// Response compression is applied per route group, see COMPRESSION_GROUPS.
+	compress := middleware.Compress(int(cfg.Compression.MinSize))
+	withCompression := func(name string, group *gin.RouterGroup) {
+		if cfg.Compression.Enabled(name) {
+			group.Use(compress)
+		}
+	}
+
+	// Public catalog pages are cached in Redis when PAGE_CACHE_TTL is set.
+	var pageCache *pagecache.Cache
+	if cfg.PageCache.Enabled() && redisCache != nil {
+		pageCache = pagecache.New(redisCache, cfg.PageCache.TTL)
+	}
+	cachePage := middleware.CachePage(pageCache)
+
+	// Authenticated routes check the token signature and scope and, when the
+	// Auth service is configured, that the session has not been revoked.
+	var signingKeys middleware.KeySource
+	if cfg.JWT.JWKSURL != "" {
+		signingKeys = authclient.NewKeys(cfg.JWT.JWKSURL)
+		log.Infof("Access tokens: RS256 keys from %s (HS256 accepted: %t)", cfg.JWT.JWKSURL, cfg.JWT.AccessSecret != "")
+	}
+	if len(cfg.JWT.PublicKeys) > 0 {
+		publicKeys, err := middleware.LoadPublicKeys(cfg.JWT.PublicKeys)
+		if err != nil {
+			log.Fatalf("Failed to load JWT public keys: %v", err)
+		}
+		signingKeys = publicKeys
+		log.Infof("Access tokens: RS256 keys from %d public key files (HS256 accepted: %t)", len(publicKeys), cfg.JWT.AccessSecret != "")
+	}
+	tokenKeys := middleware.NewTokenKeys(cfg.JWT.AccessSecret, signingKeys)
+	authenticated := []gin.HandlerFunc{middleware.JWTAuth(tokenKeys, cfg.JWT.Audience), middleware.MarketScope()}
+	if cfg.Auth.DenylistRedisAddr != "" {
+		denylistRedis, err := cache.NewRedisCache(cfg.Auth.DenylistRedisAddr, cfg.Auth.DenylistRedisPassword, cfg.Auth.DenylistRedisDB)
+		if err != nil {
+			log.Fatalf("Failed to connect to the access token denylist: %v", err)
+		}
+		defer denylistRedis.Close()
+		authenticated = append(authenticated, middleware.NotRevoked(session.NewDenylist(denylistRedis.GetClient(), cfg.Auth.DenylistPrefix)))
+		log.WithField("addr", cfg.Auth.DenylistRedisAddr).Info("Access token denylist: ENABLED")
+	}
+	if cfg.Auth.URL != "" {
+		authenticated = append(authenticated, middleware.ActiveSession(session.NewChecker(authClient, redisCache, cfg.Auth.SessionCacheTTL)))
+		log.WithField("auth_url", cfg.Auth.URL).Info("Session revocation checks: ENABLED")
+	} else {
+		log.Info("Session revocation checks: DISABLED (AUTH_URL not set)")
+	}
+
 	// API routes
 	api := router.Group("/api/")
 	{
 		// Public routes - no authentication required
 		public := api.Group("")
+		withCompression("public", public)
+		public.Use(middleware.SellerStorefront(domainResolver))
 		{
 			// Products
-			public.GET("/products", marketController.GetProducts)
-			public.GET("/products/:id", marketController.GetProduct)
+			public.GET("/products", middleware.VisitorBucket(), cachePage, marketController.GetProducts)
+			public.GET("/products/trending", cachePage, trendingController.GetTrending)
+			public.GET("/products/:id", middleware.TrackProductViews(trendingTracker, productViews), cachePage, marketController.GetProduct)
+			public.GET("/products/:id/shipping", shippingController.GetProductShipping)
+			public.GET("/products/:id/variants", variantController.GetProductVariants)
+			public.GET("/products/:id/reviews", reviewController.GetProductReviews)
 
 			// Categories
-			public.GET("/categories", marketController.GetCategories)
+			public.GET("/categories", cachePage, marketController.GetCategories)
 			public.GET("/categories/:id", marketController.GetCategory)
+
+			// White-label storefront branding
+			public.GET("/storefront-settings", storefrontController.GetStorefrontSettings)
+			public.GET("/checkout-settings", settingsController.GetCheckoutSettings)
+
+			// Catalog feeds, authorized by URL signature
+			if feedController != nil {
+				public.GET("/feeds/:name", feedController.GetFeed)
+			}
+
+			// Product export downloads, authorized by URL signature
+			if cfg.Export.Async() {
+				public.GET("/exports/:id", exportController.DownloadExport)
+			}
+
+			// Payment provider events, authorized by webhook signature
+			if paymentController != nil && !cfg.Payment.Sandbox() {
+				public.POST("/webhooks/payment", paymentController.PaymentWebhook)
+			}
 		}
 
 		// Upload routes - authentication required
 		upload := api.Group("/upload")
-		upload.Use(middleware.JWTAuth(cfg.JWT.AccessSecret))
+		upload.Use(authenticated...)
 		{
 			upload.POST("/image", uploadController.UploadImage)
 			upload.DELETE("/image/:filename", uploadController.DeleteImage)
@@ -182,9 +790,11 @@ func main() {
 
 		// Cart routes - authentication required
 		cart := api.Group("/cart")
-		cart.Use(middleware.JWTAuth(cfg.JWT.AccessSecret))
+		cart.Use(authenticated...)
+		withCompression("cart", cart)
 		{
 			cart.GET("", marketController.GetCart)
+			cart.GET("/package", marketController.GetCartPackage)
 			cart.POST("/items", marketController.AddToCart)
 			cart.PUT("/items/:id", marketController.UpdateCartItem)
 			cart.DELETE("/items/:id", marketController.DeleteCartItem)
@@ -192,50 +802,251 @@ func main() {
 
 		// User routes - authentication required
 		user := api.Group("/user")
-		user.Use(middleware.JWTAuth(cfg.JWT.AccessSecret))
+		user.Use(authenticated...)
+		withCompression("user", user)
 		{
 			user.POST("/orders", marketController.CreateOrder)
 			user.GET("/orders", marketController.GetUserOrders)
 			user.GET("/orders/:id", marketController.GetOrder)
+			user.POST("/orders/:id/cancel", marketController.CancelOrder)
+			user.GET("/orders/:id/proofs", proofController.GetProofs)
+			user.GET("/orders/:id/pickup-qr", pickupController.GetPickupQR)
+			if paymentController != nil {
+				user.POST("/orders/:id/pay", paymentController.PayOrder)
+				user.GET("/orders/:id/payments", paymentController.GetOrderPayments)
+				user.POST("/orders/:id/payments/confirm", paymentController.ConfirmOrderPayment)
+				user.GET("/gift-cards/:code", giftCardController.GetGiftCardBalance)
+				user.GET("/store-credit", giftCardController.GetStoreCredit)
+				user.GET("/payment-methods", paymentController.GetPaymentMethods)
+				user.DELETE("/payment-methods/:id", paymentController.DeletePaymentMethod)
+			}
+			user.GET("/notifications", notificationController.GetNotifications)
+			user.PUT("/notifications/:id/read", notificationController.MarkNotificationRead)
+			user.DELETE("/reviews/:id", reviewController.DeleteReview)
+		}
+
+		// Product sharing and reviews - authentication required
+		share := api.Group("/products")
+		share.Use(authenticated...)
+		{
+			share.POST("/:id/share", shareController.CreateShareLink)
+			share.POST("/:id/reviews", reviewController.CreateReview)
+		}
+
+		// Abuse reports - authentication required
+		reports := api.Group("/reports")
+		reports.Use(authenticated...)
+		{
+			reports.POST("", reportController.CreateReport)
+		}
+
+		// Support tickets - authentication required
+		tickets := api.Group("/tickets")
+		tickets.Use(authenticated...)
+		withCompression("tickets", tickets)
+		{
+			tickets.POST("", ticketController.CreateTicket)
+			tickets.GET("", ticketController.GetTickets)
+			tickets.GET("/:id", ticketController.GetTicket)
+			tickets.POST("/:id/messages", ticketController.ReplyTicket)
 		}
 
 		// Seller routes - seller role required
 		seller := api.Group("/seller")
-		seller.Use(middleware.JWTAuth(cfg.JWT.AccessSecret))
+		seller.Use(authenticated...)
+		withCompression("seller", seller)
 		seller.Use(middleware.RequireRole("seller", "admin"))
+		seller.Use(middleware.SellerAPIUsage(apiUsageTracker))
 		{
 			seller.POST("/register", sellerController.RegisterSeller)
 			seller.GET("/profile", sellerController.GetSellerProfile)
 			seller.PUT("/profile", sellerController.UpdateSellerProfile)
+			seller.GET("/invoicing", sellerController.GetInvoicingDetails)
+			seller.PUT("/invoicing", sellerController.UpdateInvoicingDetails)
+			seller.PUT("/domain", sellerDomainController.SetCustomDomain)
+			seller.DELETE("/domain", sellerDomainController.RemoveCustomDomain)
+			seller.GET("/storefront-settings", sellerDomainController.GetStorefrontSettings)
+			seller.PUT("/storefront-settings", sellerDomainController.UpdateStorefrontSettings)
+			seller.GET("/onboarding", onboardingController.GetOnboarding)
+			if payoutController != nil {
+				seller.GET("/payout-account", payoutController.GetPayoutAccount)
+				seller.PUT("/payout-account", payoutController.SetPayoutAccount)
+				seller.GET("/payouts", payoutController.GetPayouts)
+			}
+			seller.GET("/health", sellerHealthController.GetMyHealth)
 			seller.POST("/products", sellerController.CreateProduct)
+			seller.POST("/products/import-url", productImportController.ImportProductURL)
+			seller.POST("/products/import", sellerController.ImportProducts)
+			seller.GET("/products/export", exportController.ExportProducts)
+			seller.GET("/products/low-stock", sellerStatsController.GetLowStock)
+			if cfg.Export.Async() {
+				seller.GET("/exports/:id", exportController.GetExportJob)
+			}
 			seller.GET("/products", sellerController.GetSellerProducts)
 			seller.PUT("/products/:id", sellerController.UpdateProduct)
 			seller.DELETE("/products/:id", sellerController.DeleteProduct)
+			seller.PUT("/products/:id/shipping", shippingController.UpdateProductShipping)
+			seller.POST("/products/:id/variants", variantController.CreateProductVariant)
+			seller.PUT("/products/:id/variants/:variant_id", variantController.UpdateProductVariant)
+			seller.DELETE("/products/:id/variants/:variant_id", variantController.DeleteProductVariant)
+			seller.POST("/inventory-imports", inventoryController.CreateInventoryImport)
+			seller.GET("/inventory-imports/:id", inventoryController.GetInventoryImport)
+			seller.POST("/inventory-imports/:id/confirm", inventoryController.ConfirmInventoryImport)
+			seller.POST("/inventory-imports/:id/discard", inventoryController.DiscardInventoryImport)
+			seller.GET("/catalog/revisions", catalogRevisionController.GetCatalogRevisions)
+			seller.POST("/catalog/revisions", catalogRevisionController.CreateCatalogRevision)
+			seller.GET("/catalog/revisions/:id", catalogRevisionController.GetCatalogRevision)
+			seller.POST("/catalog/revisions/:id/restore", catalogRevisionController.RestoreCatalogRevision)
+			seller.GET("/orders", sellerOrderController.GetSellerOrders)
+			seller.GET("/orders/:id", sellerOrderController.GetSellerOrder)
+			seller.PUT("/orders/:id/fulfillment", sellerOrderController.UpdateFulfillment)
+			seller.GET("/orders/:id/packing-slip", sellerOrderController.GetPackingSlip)
+			seller.POST("/orders/:id/proofs", proofController.AddProof)
+			seller.POST("/orders/handover", pickupController.VerifyPickup)
+			seller.GET("/commission-rates", commissionController.GetSellerCommissionRates)
+			seller.GET("/promo-codes", promoController.GetSellerPromoCodes)
+			seller.POST("/promo-codes", promoController.CreateSellerPromoCode)
+			seller.DELETE("/promo-codes/:id", promoController.DeactivateSellerPromoCode)
+			seller.GET("/statements", statementController.GetStatements)
+			seller.GET("/analytics/shares", shareController.GetShareStats)
+			seller.GET("/stats", sellerStatsController.GetSellerStats)
+			if apiUsageController != nil {
+				seller.GET("/api-usage", apiUsageController.GetAPIUsage)
+			}
+		}
+
+		// Courier routes - courier role required
+		courier := api.Group("/courier")
+		courier.Use(authenticated...)
+		withCompression("courier", courier)
+		courier.Use(middleware.RequireRole("courier"))
+		{
+			courier.GET("/deliveries", courierController.GetDeliveries)
+			courier.POST("/deliveries/:id/deliver", courierController.MarkDelivered)
+			courier.POST("/deliveries/:id/proofs", proofController.AddProof)
+			courier.POST("/handover", pickupController.VerifyPickup)
 		}
 
 		// Admin routes - admin role required
 		admin := api.Group("/admin")
-		admin.Use(middleware.JWTAuth(cfg.JWT.AccessSecret))
-		admin.Use(middleware.RequireRole("admin"))
+		admin.Use(authenticated...)
+		withCompression("admin", admin)
+		admin.Use(middleware.RequireRole("admin"), middleware.RequireScope(middleware.ScopeAdmin))
 		{
 			admin.POST("/categories", adminController.CreateCategory)
 			admin.PUT("/categories/:id", adminController.UpdateCategory)
 			admin.DELETE("/categories/:id", adminController.DeleteCategory)
 			admin.GET("/sellers", adminController.GetAllSellers)
 			admin.PUT("/sellers/:id/status", adminController.UpdateSellerStatus)
+			admin.PUT("/sellers/:id/cancellation-window", adminController.UpdateSellerCancellationWindow)
+			admin.PUT("/sellers/:id/probation", adminController.UpdateSellerProbation)
+			admin.PUT("/sellers/:id/trusted", adminController.UpdateSellerTrusted)
+			admin.GET("/sellers/:id/health", sellerHealthController.GetSellerHealth)
+			admin.GET("/sellers/:id/catalog/revisions", catalogRevisionController.GetSellerCatalogRevisions)
+			admin.POST("/sellers/:id/catalog/revisions/:revision_id/restore", catalogRevisionController.RestoreSellerCatalogRevision)
+			admin.GET("/seller-health/rules", sellerHealthController.GetHealthRules)
+			admin.POST("/seller-health/rules", sellerHealthController.CreateHealthRule)
+			admin.PUT("/seller-health/rules/:id", sellerHealthController.UpdateHealthRule)
+			admin.DELETE("/seller-health/rules/:id", sellerHealthController.DeleteHealthRule)
+			admin.GET("/seller-health/actions", sellerHealthController.GetHealthActions)
+			admin.POST("/users/merge", userMergeController.MergeUsers)
+			admin.GET("/users/merges", userMergeController.GetUserMerges)
 			admin.PUT("/products/:id/status", adminController.UpdateProductStatus)
 			admin.GET("/orders", adminController.GetAllOrders)
+			admin.GET("/views", adminViewController.GetAdminViews)
+			admin.POST("/views", adminViewController.SaveAdminView)
+			admin.DELETE("/views/:id", adminViewController.DeleteAdminView)
 			admin.PUT("/orders/:id/status", adminController.UpdateOrderStatus)
+			admin.GET("/orders/:id/history", adminController.GetOrderHistory)
+			admin.PUT("/orders/:id/courier", adminController.AssignCourier)
+			if paymentController != nil {
+				admin.POST("/orders/:id/refund", paymentController.RefundOrder)
+				admin.POST("/chargebacks", paymentController.CreateChargeback)
+				admin.POST("/chargebacks/:id/resolve", paymentController.ResolveChargeback)
+				admin.POST("/gift-cards", giftCardController.CreateGiftCard)
+				admin.POST("/users/:id/store-credit", giftCardController.GrantStoreCredit)
+			}
+			admin.GET("/orders/:id/proofs", proofController.GetProofs)
+			admin.GET("/reports", reportController.GetReports)
+			admin.PUT("/reports/:id/resolve", reportController.ResolveReport)
+			admin.GET("/maintenance/read-only", adminController.GetReadOnly)
+			admin.PUT("/maintenance/read-only", adminController.SetReadOnly)
+			admin.POST("/config/reload", configController.ReloadConfig)
+			admin.GET("/system/status", systemController.GetSystemStatus)
+			admin.GET("/commission-rates", commissionController.GetCommissionRates)
+			admin.POST("/commission-rates", commissionController.CreateCommissionRate)
+			admin.DELETE("/commission-rates/:id", commissionController.DeleteCommissionRate)
+			admin.GET("/transform-rules", transformController.GetTransformRules)
+			admin.POST("/transform-rules", transformController.CreateTransformRule)
+			admin.POST("/transform-rules/preview", transformController.PreviewTransform)
+			admin.PUT("/transform-rules/:id", transformController.UpdateTransformRule)
+			admin.DELETE("/transform-rules/:id", transformController.DeleteTransformRule)
+			admin.GET("/promo-codes", promoController.GetPromoCodes)
+			admin.POST("/promo-codes", promoController.CreatePromoCode)
+			admin.DELETE("/promo-codes/:id", promoController.DeactivatePromoCode)
+			admin.GET("/storefront-settings", storefrontController.GetAllStorefrontSettings)
+			admin.PUT("/storefront-settings/:tenant", storefrontController.UpdateStorefrontSettings)
+			admin.DELETE("/storefront-settings/:tenant", storefrontController.DeleteStorefrontSettings)
+			admin.GET("/settings", settingsController.GetSettings)
+			admin.PUT("/settings/:key", settingsController.UpdateSetting)
+			admin.DELETE("/settings/:key", settingsController.ResetSetting)
+			admin.GET("/tickets", ticketController.GetAllTickets)
+			admin.GET("/tickets/:id", ticketController.GetTicket)
+			admin.POST("/tickets/:id/messages", ticketController.ReplyTicket)
+			admin.PUT("/tickets/:id/status", ticketController.UpdateTicketStatus)
+			if apiUsageController != nil {
+				admin.PUT("/sellers/:id/api-plan", apiUsageController.UpdateSellerAPIPlan)
+			}
+			if feedController != nil {
+				admin.GET("/feeds", feedController.GetFeeds)
+				admin.POST("/feeds/regenerate", feedController.RegenerateFeeds)
+			}
 		}
+
+		// Development tools - only registered with their sandboxes, which
+		// config refuses in production
+		registerDevRoutes(api, cfg.Payment, paymentController, outboxController)
 	}
 
-	srv := &http.Server{
-		Addr:    cfg.HTTP.Host,
-		Handler: router,
+	// Cache warming renders pages through a router of its own, so warm-ups
+	// skip request logs, rate limits and trending view counts.
+	if pageCache != nil {
+		warmRouter := gin.New()
+		warmRouter.GET("/api/products", middleware.VisitorBucket(), cachePage, marketController.GetProducts)
+		warmRouter.GET("/api/products/trending", cachePage, trendingController.GetTrending)
+		warmRouter.GET("/api/products/:id", cachePage, marketController.GetProduct)
+		warmRouter.GET("/api/categories", cachePage, marketController.GetCategories)
+		warmer := cachewarm.New(warmRouter, pageCache, categoryRepo, trendingTracker, cfg.PageCache.WarmConcurrency)
+		warmCtx, stopWarm := context.WithCancel(context.Background())
+		defer stopWarm()
+		workerRegistry.Go(warmCtx, "cache_warm", cfg.PageCache.WarmInterval, warmer.Start)
+		log.Infof("Catalog page cache: ENABLED (pages kept for %s, warmed every %s, %d at a time)",
+			cfg.PageCache.TTL, cfg.PageCache.WarmInterval, cfg.PageCache.WarmConcurrency)
+	} else if cfg.PageCache.Enabled() {
+		log.Warn("Catalog page cache: DISABLED (Redis unavailable)")
+	} else {
+		log.Info("Catalog page cache: DISABLED (PAGE_CACHE_TTL not set)")
+	}
+
+	srv, err := httpserver.New(httpserver.Options{
+		Addr:              cfg.HTTP.Host,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+		H2C:               cfg.HTTP.H2C,
+		CertFile:          cfg.TLS.CertFile,
+		KeyFile:           cfg.TLS.KeyFile,
+		AutocertDomains:   cfg.TLS.AutocertDomains,
+		AutocertCacheDir:  cfg.TLS.AutocertCacheDir,
+		AutocertEmail:     cfg.TLS.AutocertEmail,
+	}, router)
+	if err != nil {
+		log.Fatalf("Invalid server configuration: %v", err)
 	}
 
 	go func() {
-		log.Infof("Server starting on %s", cfg.HTTP.Host)
+		log.Infof("Server starting on %s (tls=%t)", cfg.HTTP.Host, srv.TLS())
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Failed to start server: %v", err)
 		}
@@ -254,6 +1065,9 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
+	if err := productViews.Flush(ctx); err != nil {
+		log.WithField("err", err).Error("Failed to flush product views")
+	}
 
 	log.Info("Server exited")
 }
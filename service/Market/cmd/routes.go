@@ -0,0 +1,25 @@
+package main
+
+import (
+	"github.com/Zifeldev/marketback/service/Market/internal/config"
+	"github.com/Zifeldev/marketback/service/Market/internal/controllers"
+	"github.com/gin-gonic/gin"
+)
+
+// registerDevRoutes registers the development tools under /api/dev, each
+// only with its sandbox. The payment webhook simulator settles payments
+// without a signature, so it must never exist with a real provider.
+func registerDevRoutes(api *gin.RouterGroup, payments config.PaymentConfig, paymentController *controllers.PaymentController, outboxController *controllers.OutboxController) {
+	simulatePayments := paymentController != nil && payments.Sandbox()
+	if !simulatePayments && outboxController == nil {
+		return
+	}
+
+	dev := api.Group("/dev")
+	if simulatePayments {
+		dev.POST("/payments/webhook", paymentController.SimulatePaymentWebhook)
+	}
+	if outboxController != nil {
+		dev.GET("/outbox", outboxController.GetOutbox)
+	}
+}
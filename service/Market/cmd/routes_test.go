@@ -0,0 +1,30 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/config"
+	"github.com/Zifeldev/marketback/service/Market/internal/controllers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func devRoutes(payments config.PaymentConfig) []string {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	registerDevRoutes(r.Group("/api"), payments, controllers.NewPaymentController(nil), nil)
+
+	var routes []string
+	for _, route := range r.Routes() {
+		routes = append(routes, route.Method+" "+route.Path)
+	}
+	return routes
+}
+
+func TestRegisterDevRoutes_PaymentSimulatorOnlyInSandbox(t *testing.T) {
+	assert.Contains(t, devRoutes(config.PaymentConfig{Provider: "sandbox"}), http.MethodPost+" /api/dev/payments/webhook")
+
+	// Anyone could mark their order paid against a real provider
+	assert.Empty(t, devRoutes(config.PaymentConfig{Provider: "stripe"}))
+}
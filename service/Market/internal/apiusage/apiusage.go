@@ -0,0 +1,197 @@
+// Package apiusage meters the seller API. Requests, errors and throttled
+// requests are counted per seller user and day in Redis hashes and rolled up
+// into Postgres; each seller's API plan caps the requests per fixed window.
+package apiusage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	keyPrefix = "apiusage"
+	// DayLayout formats the days usage is counted by (UTC).
+	DayLayout = "2006-01-02"
+	// dayTTL keeps a day's counters until the rollups after midnight ran.
+	dayTTL = 72 * time.Hour
+	// planTTL bounds how long a plan change takes to apply.
+	planTTL = time.Minute
+)
+
+// Plans maps API plan names to the requests allowed per window; 0 is
+// unlimited.
+type Plans map[string]int
+
+// PlanSource looks up the API plan of the seller owned by a user. An empty
+// plan means the user has no seller account.
+type PlanSource interface {
+	GetAPIPlan(ctx context.Context, userID int) (string, error)
+}
+
+type cachedPlan struct {
+	plan    string
+	expires time.Time
+}
+
+type Tracker struct {
+	client      redis.Cmdable
+	source      PlanSource
+	plans       Plans
+	defaultPlan string
+	window      time.Duration
+	now         func() time.Time
+
+	mu    sync.Mutex
+	cache map[int]cachedPlan
+}
+
+// NewTracker creates a tracker. Users whose plan is unknown to plans are
+// limited by defaultPlan.
+func NewTracker(client redis.Cmdable, source PlanSource, plans Plans, defaultPlan string, window time.Duration) *Tracker {
+	return &Tracker{
+		client:      client,
+		source:      source,
+		plans:       plans,
+		defaultPlan: defaultPlan,
+		window:      window,
+		now:         time.Now,
+		cache:       make(map[int]cachedPlan),
+	}
+}
+
+// Window is the throttling window the plan limits apply to.
+func (t *Tracker) Window() time.Duration {
+	return t.window
+}
+
+func dayKey(day string, userID int) string {
+	return fmt.Sprintf("%s:d:%s:%d", keyPrefix, day, userID)
+}
+
+func usersKey(day string) string {
+	return fmt.Sprintf("%s:d:%s:users", keyPrefix, day)
+}
+
+// Plan returns a user's API plan and its limit. Plans are cached for a
+// minute.
+func (t *Tracker) Plan(ctx context.Context, userID int) (string, int, error) {
+	now := t.now()
+	t.mu.Lock()
+	cached, ok := t.cache[userID]
+	t.mu.Unlock()
+
+	plan := cached.plan
+	if !ok || now.After(cached.expires) {
+		var err error
+		if plan, err = t.source.GetAPIPlan(ctx, userID); err != nil {
+			return "", 0, err
+		}
+		t.mu.Lock()
+		t.cache[userID] = cachedPlan{plan: plan, expires: now.Add(planTTL)}
+		t.mu.Unlock()
+	}
+
+	if _, ok := t.plans[plan]; !ok {
+		plan = t.defaultPlan
+	}
+	return plan, t.plans[plan], nil
+}
+
+// Allow counts a request against the user's current window and reports
+// whether it is within limit. A nil tracker or a limit of 0 allows
+// everything.
+func (t *Tracker) Allow(ctx context.Context, userID, limit int) (bool, error) {
+	if t == nil || limit <= 0 {
+		return true, nil
+	}
+	key := fmt.Sprintf("%s:w:%d:%d", keyPrefix, userID, t.now().UnixNano()/int64(t.window))
+	pipe := t.client.TxPipeline()
+	count := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, t.window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+	return count.Val() <= int64(limit), nil
+}
+
+// Record counts a finished request in the user's daily usage. Throttled
+// requests are not counted as errors. A nil tracker ignores requests.
+func (t *Tracker) Record(ctx context.Context, userID, status int, throttled bool) error {
+	if t == nil {
+		return nil
+	}
+	day := t.now().UTC().Format(DayLayout)
+	key := dayKey(day, userID)
+
+	pipe := t.client.TxPipeline()
+	pipe.HIncrBy(ctx, key, "requests", 1)
+	if throttled {
+		pipe.HIncrBy(ctx, key, "throttled", 1)
+	} else if status >= 400 {
+		pipe.HIncrBy(ctx, key, "errors", 1)
+	}
+	pipe.Expire(ctx, key, dayTTL)
+	pipe.SAdd(ctx, usersKey(day), userID)
+	pipe.Expire(ctx, usersKey(day), dayTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func usage(day string, fields map[string]string) *models.APIUsage {
+	u := &models.APIUsage{Day: day}
+	u.Requests, _ = strconv.ParseInt(fields["requests"], 10, 64)
+	u.Errors, _ = strconv.ParseInt(fields["errors"], 10, 64)
+	u.Throttled, _ = strconv.ParseInt(fields["throttled"], 10, 64)
+	return u
+}
+
+// Usage returns the live counters of a user on a day.
+func (t *Tracker) Usage(ctx context.Context, userID int, day time.Time) (*models.APIUsage, error) {
+	d := day.UTC().Format(DayLayout)
+	fields, err := t.client.HGetAll(ctx, dayKey(d, userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	return usage(d, fields), nil
+}
+
+// DayUsage returns the live counters of every user active on a day.
+func (t *Tracker) DayUsage(ctx context.Context, day time.Time) (map[int]*models.APIUsage, error) {
+	d := day.UTC().Format(DayLayout)
+	members, err := t.client.SMembers(ctx, usersKey(d)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]int, 0, len(members))
+	pipe := t.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, 0, len(members))
+	for _, m := range members {
+		id, err := strconv.Atoi(m)
+		if err != nil {
+			continue
+		}
+		userIDs = append(userIDs, id)
+		cmds = append(cmds, pipe.HGetAll(ctx, dayKey(d, id)))
+	}
+	if len(cmds) == 0 {
+		return map[int]*models.APIUsage{}, nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	result := make(map[int]*models.APIUsage, len(cmds))
+	for i, cmd := range cmds {
+		if fields := cmd.Val(); len(fields) > 0 {
+			result[userIDs[i]] = usage(d, fields)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package apiusage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type planSourceFunc func(ctx context.Context, userID int) (string, error)
+
+func (f planSourceFunc) GetAPIPlan(ctx context.Context, userID int) (string, error) {
+	return f(ctx, userID)
+}
+
+func TestTracker_Plan(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	lookups := 0
+	plan := "pro"
+	source := planSourceFunc(func(ctx context.Context, userID int) (string, error) {
+		lookups++
+		return plan, nil
+	})
+	tr := NewTracker(nil, source, Plans{"basic": 300, "pro": 1200}, "basic", time.Minute)
+	tr.now = func() time.Time { return now }
+
+	name, limit, err := tr.Plan(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, "pro", name)
+	require.Equal(t, 1200, limit)
+
+	// Cached until planTTL passes.
+	plan = "gold"
+	name, _, err = tr.Plan(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, "pro", name)
+	require.Equal(t, 1, lookups)
+
+	// Unknown plans fall back to the default.
+	now = now.Add(planTTL + time.Second)
+	name, limit, err = tr.Plan(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, "basic", name)
+	require.Equal(t, 300, limit)
+	require.Equal(t, 2, lookups)
+}
+
+func TestTracker_Nil(t *testing.T) {
+	var tr *Tracker
+	allowed, err := tr.Allow(context.Background(), 1, 10)
+	require.NoError(t, err)
+	require.True(t, allowed)
+	require.NoError(t, tr.Record(context.Background(), 1, 500, false))
+}
+
+func TestTracker_Unlimited(t *testing.T) {
+	tr := NewTracker(nil, nil, Plans{"enterprise": 0}, "enterprise", time.Minute)
+	allowed, err := tr.Allow(context.Background(), 1, 0)
+	require.NoError(t, err)
+	require.True(t, allowed)
+}
+
+func TestUsage(t *testing.T) {
+	u := usage("2026-03-01", map[string]string{"requests": "12", "errors": "2"})
+	require.Equal(t, "2026-03-01", u.Day)
+	require.EqualValues(t, 12, u.Requests)
+	require.EqualValues(t, 2, u.Errors)
+	require.Zero(t, u.Throttled)
+}
@@ -0,0 +1,71 @@
+package apiusage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/workers"
+)
+
+// Store persists daily usage rollups keyed by user ID.
+type Store interface {
+	UpsertAPIUsage(ctx context.Context, usage map[int]*models.APIUsage) error
+}
+
+// Roller copies the Redis counters of yesterday and today into Postgres.
+// Rollups overwrite, so every run converges on the Redis totals and the
+// runs after midnight make the previous day final.
+type Roller struct {
+	tracker *Tracker
+	store   Store
+}
+
+func NewRoller(tracker *Tracker, store Store) *Roller {
+	return &Roller{tracker: tracker, store: store}
+}
+
+// RunOnce rolls up yesterday and today.
+func (r *Roller) RunOnce(ctx context.Context) error {
+	now := r.tracker.now().UTC()
+	users := 0
+	for _, day := range []time.Time{now.AddDate(0, 0, -1), now} {
+		usage, err := r.tracker.DayUsage(ctx, day)
+		if err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to read api usage counters")
+			return fmt.Errorf("failed to read api usage counters: %w", err)
+		}
+		if len(usage) == 0 {
+			continue
+		}
+		if err := r.store.UpsertAPIUsage(ctx, usage); err != nil {
+			return err
+		}
+		users += len(usage)
+	}
+
+	logger.GetLogger().WithField("users", users).Info("api usage rolled up")
+	return nil
+}
+
+// Start runs the rollup every interval until ctx is cancelled.
+func (r *Roller) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		workers.Beat(ctx)
+		if err := r.RunOnce(ctx); err != nil {
+			metrics.APIUsageRollupFailuresTotal.Inc()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
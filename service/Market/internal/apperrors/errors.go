@@ -18,6 +18,13 @@ const (
 	CodeEmptyCart         = "EMPTY_CART"
 	CodeRateLimitExceeded = "RATE_LIMIT_EXCEEDED"
 	CodeTimeout           = "TIMEOUT"
+	CodeReadOnly          = "READ_ONLY"
+	CodeShippingBlocked   = "SHIPPING_RESTRICTED"
+	CodePayloadTooLarge   = "PAYLOAD_TOO_LARGE"
+	CodeBelowMinimumOrder = "BELOW_MINIMUM_ORDER"
+	CodeFaultInjected     = "FAULT_INJECTED"
+	CodeImportFailed      = "IMPORT_FAILED"
+	CodePackageTooLarge   = "PACKAGE_TOO_LARGE"
 )
 
 type AppError struct {
@@ -25,6 +32,8 @@ type AppError struct {
 	Message    string `json:"message"`
 	HTTPStatus int    `json:"-"`
 	Err        error  `json:"-"`
+	// Details carries structured context for the client, e.g. offending items.
+	Details interface{} `json:"details,omitempty"`
 }
 
 func (e *AppError) Error() string {
@@ -140,6 +149,14 @@ func OrderNotFound(id int) *AppError {
 	}
 }
 
+func OrderNumberNotFound(orderNumber string) *AppError {
+	return &AppError{
+		Code:       CodeNotFound,
+		Message:    fmt.Sprintf("order %s not found", orderNumber),
+		HTTPStatus: http.StatusNotFound,
+	}
+}
+
 func SellerNotFound(id int) *AppError {
 	return &AppError{
 		Code:       CodeNotFound,
@@ -180,6 +197,71 @@ func InsufficientStockForProduct(productID int) *AppError {
 	}
 }
 
+func ReadOnly(table string) *AppError {
+	return &AppError{
+		Code:       CodeReadOnly,
+		Message:    fmt.Sprintf("%s are temporarily read-only, try again later", table),
+		HTTPStatus: http.StatusServiceUnavailable,
+	}
+}
+
+func ShippingRestricted(destination string, items interface{}) *AppError {
+	return &AppError{
+		Code:       CodeShippingBlocked,
+		Message:    fmt.Sprintf("some items cannot be shipped to %s", destination),
+		HTTPStatus: http.StatusUnprocessableEntity,
+		Details:    items,
+	}
+}
+
+// FaultInjected is returned for a request failed on purpose by the chaos
+// middleware.
+func FaultInjected() *AppError {
+	return &AppError{
+		Code:       CodeFaultInjected,
+		Message:    "injected fault, try again later",
+		HTTPStatus: http.StatusServiceUnavailable,
+	}
+}
+
+func PayloadTooLarge(limit int64) *AppError {
+	return &AppError{
+		Code:       CodePayloadTooLarge,
+		Message:    fmt.Sprintf("request body exceeds the limit of %d bytes", limit),
+		HTTPStatus: http.StatusRequestEntityTooLarge,
+	}
+}
+
+// PackageTooLarge is returned when the parcel of an order exceeds the
+// carrier's limits; pkg says which items, or the parcel as a whole.
+func PackageTooLarge(message string, pkg interface{}) *AppError {
+	return &AppError{
+		Code:       CodePackageTooLarge,
+		Message:    message,
+		HTTPStatus: http.StatusUnprocessableEntity,
+		Details:    pkg,
+	}
+}
+
+func BelowMinimumOrder(minimum, total float64) *AppError {
+	return &AppError{
+		Code:       CodeBelowMinimumOrder,
+		Message:    fmt.Sprintf("orders must total at least %.2f", minimum),
+		HTTPStatus: http.StatusUnprocessableEntity,
+		Details:    map[string]float64{"min_order_amount": minimum, "cart_total": total},
+	}
+}
+
+// ImportFailed is returned when a product could not be imported from a
+// page the seller pointed at.
+func ImportFailed(message string) *AppError {
+	return &AppError{
+		Code:       CodeImportFailed,
+		Message:    message,
+		HTTPStatus: http.StatusUnprocessableEntity,
+	}
+}
+
 func IsAppError(err error) bool {
 	var appErr *AppError
 	return errors.As(err, &appErr)
@@ -0,0 +1,161 @@
+// Package botdetect tells search engine crawlers and scrapers apart from
+// buyers, by User-Agent and, optionally, by asking a fingerprinting service
+// about requests whose User-Agent passes.
+package botdetect
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+)
+
+// Traffic classes.
+const (
+	ClassHuman   = "human"
+	ClassCrawler = "crawler"
+	ClassScraper = "scraper"
+)
+
+// Classes lists every traffic class.
+var Classes = []string{ClassHuman, ClassCrawler, ClassScraper}
+
+// crawlerAgents are User-Agent substrings of search engines and link
+// preview fetchers, which are welcome.
+var crawlerAgents = []string{
+	"googlebot", "adsbot-google", "google-inspectiontool", "bingbot", "duckduckbot",
+	"yandexbot", "baiduspider", "applebot", "slurp", "facebookexternalhit",
+	"twitterbot", "linkedinbot", "pinterestbot", "slackbot", "discordbot",
+}
+
+// scraperAgents are User-Agent substrings of HTTP libraries, headless
+// browsers and self-declared bots other than the crawlers above.
+var scraperAgents = []string{
+	"curl", "wget", "python-requests", "python-urllib", "aiohttp", "httpx", "scrapy",
+	"go-http-client", "java/", "okhttp", "apache-httpclient", "libwww-perl", "node-fetch",
+	"axios", "headlesschrome", "phantomjs", "puppeteer", "playwright", "selenium",
+	"bot", "crawler", "spider",
+}
+
+// fingerprintCacheTTL is how long a fingerprinting verdict is reused for
+// the same client IP and User-Agent, and fingerprintCacheSize how many
+// verdicts are kept before expired ones are dropped.
+const (
+	fingerprintCacheTTL  = 10 * time.Minute
+	fingerprintCacheSize = 10000
+)
+
+// Fingerprinter classifies a request by signals beyond its User-Agent,
+// such as its IP reputation or header order.
+type Fingerprinter interface {
+	Classify(ctx context.Context, r *http.Request, clientIP string) (string, error)
+}
+
+// Detector classifies requests.
+type Detector struct {
+	blocked       []string
+	fingerprinter Fingerprinter
+
+	mu       sync.Mutex
+	verdicts map[string]verdict
+	now      func() time.Time
+}
+
+type verdict struct {
+	class     string
+	expiresAt time.Time
+}
+
+// New creates a detector. User-Agents containing any of blocked are
+// scrapers; a nil fingerprinter classifies by User-Agent only.
+func New(blocked []string, fingerprinter Fingerprinter) *Detector {
+	lower := make([]string, 0, len(blocked))
+	for _, agent := range blocked {
+		if agent = strings.ToLower(strings.TrimSpace(agent)); agent != "" {
+			lower = append(lower, agent)
+		}
+	}
+	return &Detector{
+		blocked:       lower,
+		fingerprinter: fingerprinter,
+		verdicts:      map[string]verdict{},
+		now:           time.Now,
+	}
+}
+
+// ClassifyAgent classifies a User-Agent. Blocked agents and agents that
+// are empty, HTTP libraries or unknown bots are scrapers.
+func (d *Detector) ClassifyAgent(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	if strings.TrimSpace(ua) == "" || matches(ua, d.blocked) {
+		return ClassScraper
+	}
+	if matches(ua, crawlerAgents) {
+		return ClassCrawler
+	}
+	if matches(ua, scraperAgents) {
+		return ClassScraper
+	}
+	return ClassHuman
+}
+
+// Classify classifies a request from clientIP. Requests the User-Agent
+// does not give away as scrapers are put to the fingerprinter, whose
+// verdict is cached per IP and User-Agent; scrapers posing as crawlers are
+// caught this way. When it fails the User-Agent decides.
+func (d *Detector) Classify(ctx context.Context, r *http.Request, clientIP string) string {
+	class := d.ClassifyAgent(r.UserAgent())
+	if class == ClassScraper || d.fingerprinter == nil {
+		return class
+	}
+
+	key := clientIP + "|" + r.UserAgent()
+	if cached, ok := d.cached(key); ok {
+		return cached
+	}
+	fingerprinted, err := d.fingerprinter.Classify(ctx, r, clientIP)
+	if err != nil {
+		logger.FromContext(ctx).WithField("err", err).Warn("failed to fingerprint request")
+		return class
+	}
+	d.remember(key, fingerprinted)
+	return fingerprinted
+}
+
+func (d *Detector) cached(key string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	v, ok := d.verdicts[key]
+	if !ok || d.now().After(v.expiresAt) {
+		return "", false
+	}
+	return v.class, true
+}
+
+func (d *Detector) remember(key, class string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	if len(d.verdicts) >= fingerprintCacheSize {
+		for k, v := range d.verdicts {
+			if now.After(v.expiresAt) {
+				delete(d.verdicts, k)
+			}
+		}
+	}
+	if len(d.verdicts) < fingerprintCacheSize {
+		d.verdicts[key] = verdict{class: class, expiresAt: now.Add(fingerprintCacheTTL)}
+	}
+}
+
+func matches(ua string, agents []string) bool {
+	for _, agent := range agents {
+		if strings.Contains(ua, agent) {
+			return true
+		}
+	}
+	return false
+}
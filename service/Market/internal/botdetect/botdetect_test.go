@@ -0,0 +1,76 @@
+package botdetect
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetector_ClassifyAgent(t *testing.T) {
+	d := New([]string{" AhrefsBot "}, nil)
+
+	cases := map[string]string{
+		"": ClassScraper,
+		"Mozilla/5.0 (X11; Linux x86_64) Firefox/131.0":                            ClassHuman,
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)": ClassCrawler,
+		"Mozilla/5.0 (compatible; bingbot/2.0)":                                    ClassCrawler,
+		"curl/8.4.0":                                                               ClassScraper,
+		"python-requests/2.31":                                                     ClassScraper,
+		"Mozilla/5.0 HeadlessChrome/120.0":                                         ClassScraper,
+		"Mozilla/5.0 (compatible; AhrefsBot/7.0)":                                  ClassScraper,
+		"Mozilla/5.0 (compatible; SomeUnknownBot/1.0)":                             ClassScraper,
+	}
+	for ua, want := range cases {
+		assert.Equal(t, want, d.ClassifyAgent(ua), ua)
+	}
+}
+
+func TestDetector_Classify_Fingerprint(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		assert.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		var req fingerprintRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		class := ClassHuman
+		if req.IP == "203.0.113.9" {
+			class = ClassScraper
+		}
+		_ = json.NewEncoder(w).Encode(fingerprintResponse{Class: class})
+	}))
+	defer srv.Close()
+
+	d := New(nil, NewHTTPFingerprinter(srv.URL, "key", time.Second))
+	r := httptest.NewRequest("GET", "/api/products", nil)
+	r.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Googlebot/2.1)")
+
+	assert.Equal(t, ClassScraper, d.Classify(context.Background(), r, "203.0.113.9"))
+	assert.Equal(t, ClassScraper, d.Classify(context.Background(), r, "203.0.113.9"))
+	assert.Equal(t, int32(1), calls.Load(), "verdicts are cached")
+
+	assert.Equal(t, ClassHuman, d.Classify(context.Background(), r, "198.51.100.1"))
+
+	r.Header.Set("User-Agent", "curl/8.4.0")
+	assert.Equal(t, ClassScraper, d.Classify(context.Background(), r, "198.51.100.2"))
+	assert.Equal(t, int32(2), calls.Load(), "scrapers by User-Agent are not fingerprinted")
+}
+
+func TestDetector_Classify_FingerprintFailureFallsBack(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"class":"alien"}`))
+	}))
+	defer srv.Close()
+
+	d := New(nil, NewHTTPFingerprinter(srv.URL, "", time.Second))
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("User-Agent", "Mozilla/5.0 (compatible; bingbot/2.0)")
+
+	assert.Equal(t, ClassCrawler, d.Classify(context.Background(), r, "198.51.100.1"))
+}
@@ -0,0 +1,82 @@
+package botdetect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// fingerprintHeaders are the request headers sent to the fingerprinting
+// service besides the User-Agent.
+var fingerprintHeaders = []string{
+	"Accept", "Accept-Language", "Accept-Encoding", "Sec-Ch-Ua", "Sec-Ch-Ua-Platform", "Sec-Fetch-Mode",
+}
+
+// HTTPFingerprinter asks a fingerprinting service over HTTP. The service
+// is sent {"ip", "user_agent", "headers"} and answers {"class"} with one of
+// Classes.
+type HTTPFingerprinter struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+// NewHTTPFingerprinter creates a fingerprinter posting to url, with apiKey
+// as a bearer token when set.
+func NewHTTPFingerprinter(url, apiKey string, timeout time.Duration) *HTTPFingerprinter {
+	return &HTTPFingerprinter{url: url, apiKey: apiKey, client: &http.Client{Timeout: timeout}}
+}
+
+type fingerprintRequest struct {
+	IP        string            `json:"ip"`
+	UserAgent string            `json:"user_agent"`
+	Headers   map[string]string `json:"headers"`
+}
+
+type fingerprintResponse struct {
+	Class string `json:"class"`
+}
+
+func (f *HTTPFingerprinter) Classify(ctx context.Context, r *http.Request, clientIP string) (string, error) {
+	headers := make(map[string]string, len(fingerprintHeaders))
+	for _, name := range fingerprintHeaders {
+		if value := r.Header.Get(name); value != "" {
+			headers[name] = value
+		}
+	}
+	payload, err := json.Marshal(fingerprintRequest{IP: clientIP, UserAgent: r.UserAgent(), Headers: headers})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode fingerprint request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.url, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to build fingerprint request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if f.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+f.apiKey)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fingerprint request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fingerprint service returned %d", resp.StatusCode)
+	}
+
+	var out fingerprintResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("failed to decode fingerprint response: %w", err)
+	}
+	if !slices.Contains(Classes, out.Class) {
+		return "", fmt.Errorf("fingerprint service returned unknown class %q", out.Class)
+	}
+	return out.Class, nil
+}
@@ -0,0 +1,54 @@
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Set at build time, e.g.
+//
+//	go build -ldflags "-X github.com/Zifeldev/marketback/service/Market/internal/buildinfo.Version=1.2.0 \
+//	  -X github.com/Zifeldev/marketback/service/Market/internal/buildinfo.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/Zifeldev/marketback/service/Market/internal/buildinfo.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version   = "1.0.0"
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
+
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+// Get returns the build information. When the commit was not injected via
+// ldflags it falls back to the VCS stamp embedded by the Go toolchain.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "unknown" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				if info.BuildDate == "unknown" {
+					info.BuildDate = s.Value
+				}
+			}
+		}
+	}
+
+	return info
+}
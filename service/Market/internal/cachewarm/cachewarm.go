@@ -0,0 +1,252 @@
+// Package cachewarm renders the most visited catalog pages into the page
+// cache ahead of buyers: right after a deploy, whenever the cache was
+// flushed and on a schedule. Pages are rendered in-process through a
+// handler serving the cached routes.
+package cachewarm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/pagecache"
+	"github.com/Zifeldev/marketback/service/Market/internal/trending"
+	"github.com/Zifeldev/marketback/service/Market/internal/workers"
+)
+
+const (
+	// TopCategories and TopProducts bound the sitemap: the categories with
+	// most products and the trending products whose pages are warmed.
+	TopCategories = 20
+	TopProducts   = 50
+
+	// trendingWindow is the window of the trending page buyers see by default.
+	trendingWindow = "24h"
+	// checkInterval is how often the cache is checked for a flush between
+	// scheduled warm-ups.
+	checkInterval = 30 * time.Second
+)
+
+// Categories lists the catalog's categories with their product counts.
+type Categories interface {
+	GetAll(ctx context.Context) ([]*models.Category, error)
+}
+
+// Ranker is the read side of trending.Tracker.
+type Ranker interface {
+	Top(ctx context.Context, window string, limit int) ([]trending.Score, error)
+}
+
+// Marker records when the cache was last warmed.
+type Marker interface {
+	MarkWarm(ctx context.Context, ttl time.Duration) error
+	Warm(ctx context.Context) (bool, error)
+}
+
+type Warmer struct {
+	handler     http.Handler
+	marker      Marker
+	categories  Categories
+	ranker      Ranker
+	concurrency int
+}
+
+// New creates a warmer rendering pages through handler, concurrency pages
+// at a time.
+func New(handler http.Handler, marker Marker, categories Categories, ranker Ranker, concurrency int) *Warmer {
+	return &Warmer{
+		handler:     handler,
+		marker:      marker,
+		categories:  categories,
+		ranker:      ranker,
+		concurrency: concurrency,
+	}
+}
+
+// Sitemap lists the pages to warm: the categories, the first page of the
+// product listing and of the largest categories, and the trending products
+// with their product pages.
+func (w *Warmer) Sitemap(ctx context.Context) ([]string, error) {
+	pages := []string{"/api/categories", "/api/products", "/api/products/trending"}
+
+	categories, err := w.categories.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	categories = append([]*models.Category(nil), categories...)
+	sort.SliceStable(categories, func(i, j int) bool {
+		return categories[i].ProductCount > categories[j].ProductCount
+	})
+	for i, category := range categories {
+		if i == TopCategories || category.ProductCount == 0 {
+			break
+		}
+		pages = append(pages, fmt.Sprintf("/api/products?category_id=%d", category.ID))
+	}
+
+	scores, err := w.ranker.Top(ctx, trendingWindow, TopProducts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank trending products: %w", err)
+	}
+	for _, score := range scores {
+		pages = append(pages, fmt.Sprintf("/api/products/%d", score.ProductID))
+	}
+	return pages, nil
+}
+
+// pageRender is a page to render for the visitors of bucket, or for
+// everyone with bucket -1.
+type pageRender struct {
+	page   string
+	bucket int
+}
+
+// RunOnce renders every page of the sitemap and returns how many were
+// cached. Product listings are rendered once per visitor bucket, as they
+// list sellers on probation to some buckets only. Pages that fail to
+// render are logged and skipped.
+func (w *Warmer) RunOnce(ctx context.Context) (int, error) {
+	pages, err := w.Sitemap(ctx)
+	if err != nil {
+		return 0, err
+	}
+	renders := make([]pageRender, 0, len(pages))
+	for _, page := range pages {
+		if path, _, _ := strings.Cut(page, "?"); path != "/api/products" {
+			renders = append(renders, pageRender{page: page, bucket: -1})
+			continue
+		}
+		for bucket := 0; bucket < models.TrafficBuckets; bucket++ {
+			renders = append(renders, pageRender{page: page, bucket: bucket})
+		}
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		cached int
+	)
+	queue := make(chan pageRender)
+	for i := 0; i < w.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range queue {
+				if w.render(ctx, r) {
+					mu.Lock()
+					cached++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, r := range renders {
+		if ctx.Err() != nil {
+			break
+		}
+		queue <- r
+	}
+	close(queue)
+	wg.Wait()
+
+	return cached, ctx.Err()
+}
+
+func (w *Warmer) render(ctx context.Context, r pageRender) bool {
+	ctx = pagecache.WithRefresh(ctx)
+	if r.bucket >= 0 {
+		ctx = pagecache.WithBucket(ctx, r.bucket)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.page, nil)
+	if err != nil {
+		return false
+	}
+	rw := &statusWriter{header: http.Header{}}
+	w.handler.ServeHTTP(rw, req)
+
+	if rw.status != http.StatusOK {
+		metrics.CacheWarmPagesTotal.WithLabelValues("failed").Inc()
+		logger.GetLogger().WithFields(map[string]interface{}{
+			"page":   r.page,
+			"status": rw.status,
+		}).Warn("failed to warm page")
+		return false
+	}
+	metrics.CacheWarmPagesTotal.WithLabelValues("cached").Inc()
+	return true
+}
+
+// Start warms the cache right away, then again whenever it was flushed or
+// the last warm-up is older than interval, until ctx is done. Instances
+// sharing the cache take turns: one that finds it warm leaves it alone.
+func (w *Warmer) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(min(interval, checkInterval))
+	defer ticker.Stop()
+
+	for {
+		workers.Beat(ctx)
+		w.warmIfCold(ctx, interval)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Warmer) warmIfCold(ctx context.Context, interval time.Duration) {
+	warm, err := w.marker.Warm(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Warn("failed to check page cache")
+		return
+	}
+	if warm {
+		return
+	}
+
+	start := time.Now()
+	cached, err := w.RunOnce(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.GetLogger().WithField("err", err).Error("failed to warm page cache")
+		}
+		return
+	}
+	if err := w.marker.MarkWarm(ctx, interval); err != nil {
+		logger.GetLogger().WithField("err", err).Warn("failed to mark page cache warm")
+	}
+	logger.GetLogger().WithFields(map[string]interface{}{
+		"pages":    cached,
+		"duration": time.Since(start).String(),
+	}).Info("page cache warmed")
+}
+
+// statusWriter discards a rendered page, which the page cache already
+// kept, and only remembers its status.
+type statusWriter struct {
+	header http.Header
+	status int
+}
+
+func (w *statusWriter) Header() http.Header { return w.header }
+
+func (w *statusWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return len(p), nil
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
@@ -0,0 +1,129 @@
+package cachewarm
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/pagecache"
+	"github.com/Zifeldev/marketback/service/Market/internal/trending"
+	"github.com/stretchr/testify/require"
+)
+
+type categoryList []*models.Category
+
+func (l categoryList) GetAll(ctx context.Context) ([]*models.Category, error) {
+	return l, nil
+}
+
+type ranking []trending.Score
+
+func (r ranking) Top(ctx context.Context, window string, limit int) ([]trending.Score, error) {
+	return r, nil
+}
+
+type marker struct {
+	warm  bool
+	marks []time.Duration
+}
+
+func (m *marker) MarkWarm(ctx context.Context, ttl time.Duration) error {
+	m.warm = true
+	m.marks = append(m.marks, ttl)
+	return nil
+}
+
+func (m *marker) Warm(ctx context.Context) (bool, error) {
+	return m.warm, nil
+}
+
+// pageRecorder serves every page, failing /api/products/404. Pages
+// rendered for a visitor bucket are recorded for bucket 0 only and counted
+// in buckets.
+type pageRecorder struct {
+	mu      sync.Mutex
+	pages   []string
+	buckets int
+}
+
+func (h *pageRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !pagecache.Refreshing(r.Context()) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	h.mu.Lock()
+	bucket, ok := pagecache.Bucket(r.Context())
+	if ok {
+		h.buckets++
+	}
+	if bucket == 0 {
+		h.pages = append(h.pages, r.URL.RequestURI())
+	}
+	h.mu.Unlock()
+	if r.URL.Path == "/api/products/404" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_, _ = w.Write([]byte(`{}`))
+}
+
+func TestWarmer_Sitemap(t *testing.T) {
+	categories := categoryList{
+		{ID: 1, ProductCount: 2},
+		{ID: 2, ProductCount: 0},
+		{ID: 3, ProductCount: 9},
+	}
+	w := New(&pageRecorder{}, &marker{}, categories, ranking{{ProductID: 12}, {ProductID: 5}}, 2)
+
+	pages, err := w.Sitemap(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"/api/categories",
+		"/api/products",
+		"/api/products/trending",
+		"/api/products?category_id=3",
+		"/api/products?category_id=1",
+		"/api/products/12",
+		"/api/products/5",
+	}, pages)
+	require.Equal(t, int64(2), categories[0].ProductCount, "categories are sorted in a copy")
+}
+
+func TestWarmer_Start(t *testing.T) {
+	handler := &pageRecorder{}
+	mark := &marker{}
+	w := New(handler, mark, categoryList{{ID: 1, ProductCount: 1}}, ranking{{ProductID: 7}, {ProductID: 404}}, 3)
+	ctx := context.Background()
+
+	cached, err := w.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 23, cached, "the failing page is skipped")
+	require.Len(t, handler.pages, 6)
+	require.Equal(t, 20, handler.buckets, "listings are rendered for every visitor bucket")
+
+	handler.pages = nil
+	w.warmIfCold(ctx, time.Minute)
+	require.Len(t, handler.pages, 6)
+	require.Equal(t, []time.Duration{time.Minute}, mark.marks)
+
+	// A warm cache is left alone until the mark expires or is flushed.
+	handler.pages = nil
+	w.warmIfCold(ctx, time.Minute)
+	require.Empty(t, handler.pages)
+
+	mark.warm = false
+	w.warmIfCold(ctx, time.Minute)
+	sort.Strings(handler.pages)
+	require.Equal(t, []string{
+		"/api/categories",
+		"/api/products",
+		"/api/products/404",
+		"/api/products/7",
+		"/api/products/trending",
+		"/api/products?category_id=1",
+	}, handler.pages)
+}
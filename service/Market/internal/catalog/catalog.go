@@ -0,0 +1,315 @@
+// Package catalog reads and writes seller product catalogs as CSV or XLSX
+// spreadsheets, one product per row under a header row. Exported catalogs
+// can be edited and imported back: rows with an id update that product,
+// rows without one create a product.
+package catalog
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/money"
+)
+
+// Formats.
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// MaxRows bounds the product rows of one import.
+const MaxRows = 5000
+
+// Columns is the header of exported catalogs. Sizes are separated by "|".
+// Status is exported for reference and ignored on import.
+var Columns = []string{"id", "title", "description", "category_id", "price", "stock", "sizes", "image_url", "status"}
+
+const (
+	maxTitle    = 255
+	maxImageURL = 500
+)
+
+// FormatOf returns the format of a file from its name.
+func FormatOf(filename string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	}
+	return "", fmt.Errorf("must be a .csv or .xlsx file")
+}
+
+// Row is one product row of an import: ID is nil for new products. For
+// updates only the non-empty cells are set.
+type Row struct {
+	Line   int
+	ID     *int
+	Update models.UpdateProductRequest
+}
+
+// Create returns the row as a new product; Next has checked that the
+// required fields are there.
+func (r *Row) Create() *models.CreateProductRequest {
+	req := &models.CreateProductRequest{
+		CategoryID: *r.Update.CategoryID,
+		Title:      *r.Update.Title,
+		Price:      *r.Update.Price,
+		Sizes:      models.SizesJSON{},
+	}
+	if r.Update.Description != nil {
+		req.Description = *r.Update.Description
+	}
+	if r.Update.Stock != nil {
+		req.Stock = *r.Update.Stock
+	}
+	if r.Update.Sizes != nil {
+		req.Sizes = *r.Update.Sizes
+	}
+	if r.Update.ImageURL != nil {
+		req.ImageURL = *r.Update.ImageURL
+	}
+	return req
+}
+
+// Reader reads the rows of a catalog file after its header.
+type Reader struct {
+	next   func() ([]string, int, error)
+	header map[string]int
+	line   int
+}
+
+// NewReader opens a catalog file and reads its header row. XLSX files are
+// zip archives and need random access; the first sheet is read.
+func NewReader(file io.ReaderAt, size int64, format string) (*Reader, error) {
+	var next func() ([]string, int, error)
+	switch format {
+	case FormatCSV:
+		cr := csv.NewReader(io.NewSectionReader(file, 0, size))
+		cr.FieldsPerRecord = -1
+		cr.TrimLeadingSpace = true
+		next = func() ([]string, int, error) {
+			record, err := cr.Read()
+			if err != nil {
+				return nil, 0, err
+			}
+			line, _ := cr.FieldPos(0)
+			return record, line, nil
+		}
+	case FormatXLSX:
+		sheet, err := openSheet(file, size)
+		if err != nil {
+			return nil, err
+		}
+		next = sheet.next
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	r := &Reader{next: next, header: map[string]int{}}
+	header, err := r.read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("file is empty")
+		}
+		return nil, err
+	}
+	for i, name := range header {
+		r.header[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := r.header["id"]; !ok {
+		if _, ok := r.header["title"]; !ok {
+			return nil, fmt.Errorf("header row must name the columns: %s", strings.Join(Columns, ", "))
+		}
+	}
+	return r, nil
+}
+
+// read returns the next non-blank record and notes its line.
+func (r *Reader) read() ([]string, error) {
+	for {
+		record, line, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		r.line = line
+		for _, cell := range record {
+			if strings.TrimSpace(cell) != "" {
+				return record, nil
+			}
+		}
+	}
+}
+
+// Next parses the next row, returning io.EOF after the last one. Rows with
+// invalid cells come back with their problems; the file can still be read
+// on. Other errors mean the file is unreadable.
+func (r *Reader) Next() (*Row, []string, error) {
+	record, err := r.read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, io.EOF
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, nil, fmt.Errorf("line %d: %w", parseErr.Line, parseErr.Err)
+		}
+		return nil, nil, err
+	}
+
+	cell := func(name string) string {
+		i, ok := r.header[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	row, problems := parse(cell)
+	row.Line = r.line
+	return row, problems, nil
+}
+
+// parse reads a row's cells. New products need a title, a category and a
+// price.
+func parse(cell func(name string) string) (*Row, []string) {
+	row := &Row{}
+	var problems []string
+	invalid := func(column, msg string) {
+		problems = append(problems, column+": "+msg)
+	}
+
+	if v := cell("id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id < 1 {
+			invalid("id", "must be a positive integer")
+		} else {
+			row.ID = &id
+		}
+	}
+	if v := cell("title"); v != "" {
+		if len(v) > maxTitle {
+			invalid("title", fmt.Sprintf("must be at most %d characters", maxTitle))
+		}
+		row.Update.Title = &v
+	}
+	if v := cell("description"); v != "" {
+		row.Update.Description = &v
+	}
+	if v := cell("category_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id < 1 {
+			invalid("category_id", "must be a positive integer")
+		} else {
+			row.Update.CategoryID = &id
+		}
+	}
+	if v := cell("price"); v != "" {
+		price, err := strconv.ParseFloat(v, 64)
+		if err != nil || price <= 0 {
+			invalid("price", "must be a positive number")
+		} else {
+			price = money.FromFloat(price).Float()
+			row.Update.Price = &price
+		}
+	}
+	if v := cell("stock"); v != "" {
+		// Spreadsheets may store whole numbers as "12.0".
+		stock, err := strconv.ParseFloat(v, 64)
+		if err != nil || stock < 0 || stock != float64(int(stock)) {
+			invalid("stock", "must be a non-negative integer")
+		} else {
+			n := int(stock)
+			row.Update.Stock = &n
+		}
+	}
+	if v := cell("sizes"); v != "" {
+		sizes := models.SizesJSON{}
+		for _, s := range strings.Split(v, "|") {
+			if s = strings.TrimSpace(s); s != "" {
+				sizes = append(sizes, s)
+			}
+		}
+		row.Update.Sizes = &sizes
+	}
+	if v := cell("image_url"); v != "" {
+		if len(v) > maxImageURL {
+			invalid("image_url", fmt.Sprintf("must be at most %d characters", maxImageURL))
+		}
+		row.Update.ImageURL = &v
+	}
+
+	if cell("id") == "" {
+		required := []struct {
+			column  string
+			missing bool
+		}{
+			{"title", row.Update.Title == nil},
+			{"category_id", row.Update.CategoryID == nil},
+			{"price", row.Update.Price == nil},
+		}
+		for _, r := range required {
+			if r.missing && !hasProblem(problems, r.column) {
+				invalid(r.column, "required for new products")
+			}
+		}
+	}
+	return row, problems
+}
+
+func hasProblem(problems []string, column string) bool {
+	for _, p := range problems {
+		if strings.HasPrefix(p, column+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// record renders a product as an export row.
+func record(p *models.Product) []string {
+	return []string{
+		strconv.Itoa(p.ID), p.Title, p.Description, strconv.Itoa(p.CategoryID),
+		strconv.FormatFloat(p.Price, 'f', 2, 64), strconv.Itoa(p.Stock),
+		strings.Join(p.Sizes, "|"), p.ImageURL, p.Status,
+	}
+}
+
+// WriteCSV writes products as a CSV catalog.
+func WriteCSV(w io.Writer, products []*models.Product) error {
+	return writeAll(w, FormatCSV, products)
+}
+
+// csvWriter writes the header row when created and flushes after every
+// batch, so streamed downloads receive rows as they are written.
+type csvWriter struct {
+	cw *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(Columns); err != nil {
+		return nil, err
+	}
+	return &csvWriter{cw: cw}, nil
+}
+
+func (w *csvWriter) Write(products []*models.Product) error {
+	for _, p := range products {
+		if err := w.cw.Write(record(p)); err != nil {
+			return err
+		}
+	}
+	w.cw.Flush()
+	return w.cw.Error()
+}
+
+func (w *csvWriter) Close() error {
+	w.cw.Flush()
+	return w.cw.Error()
+}
@@ -0,0 +1,140 @@
+package catalog
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testProducts() []*models.Product {
+	return []*models.Product{
+		{ID: 7, CategoryID: 2, Title: `Café "Tee"`, Description: "Soft, <cotton>", Price: 19.9, Stock: 12, Sizes: models.SizesJSON{"S", "M"}, Status: "active"},
+		{ID: 8, CategoryID: 3, Title: "Cap", Price: 5, Sizes: models.SizesJSON{}, Status: "pending"},
+	}
+}
+
+func readAll(t *testing.T, data []byte, format string) []*Row {
+	t.Helper()
+	r, err := NewReader(bytes.NewReader(data), int64(len(data)), format)
+	require.NoError(t, err)
+	var rows []*Row
+	for {
+		row, problems, err := r.Next()
+		if err == io.EOF {
+			return rows
+		}
+		require.NoError(t, err)
+		require.Empty(t, problems)
+		rows = append(rows, row)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []string{FormatCSV, FormatXLSX} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if format == FormatCSV {
+				require.NoError(t, WriteCSV(&buf, testProducts()))
+			} else {
+				require.NoError(t, WriteXLSX(&buf, testProducts()))
+			}
+
+			rows := readAll(t, buf.Bytes(), format)
+			require.Len(t, rows, 2)
+			assert.Equal(t, 2, rows[0].Line)
+			assert.Equal(t, 7, *rows[0].ID)
+			assert.Equal(t, `Café "Tee"`, *rows[0].Update.Title)
+			assert.Equal(t, "Soft, <cotton>", *rows[0].Update.Description)
+			assert.Equal(t, 19.9, *rows[0].Update.Price)
+			assert.Equal(t, 12, *rows[0].Update.Stock)
+			assert.Equal(t, models.SizesJSON{"S", "M"}, *rows[0].Update.Sizes)
+			assert.Nil(t, rows[0].Update.Status, "status is not imported")
+			assert.Nil(t, rows[1].Update.Sizes)
+		})
+	}
+}
+
+func TestWriter_Batches(t *testing.T) {
+	for _, format := range []string{FormatCSV, FormatXLSX} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, format)
+			require.NoError(t, err)
+			products := testProducts()
+			require.NoError(t, w.Write(products[:1]))
+			require.NoError(t, w.Write(nil))
+			require.NoError(t, w.Write(products[1:]))
+			require.NoError(t, w.Close())
+
+			rows := readAll(t, buf.Bytes(), format)
+			require.Len(t, rows, 2)
+			assert.Equal(t, 3, rows[1].Line)
+			assert.Equal(t, products[1].Title, *rows[1].Update.Title)
+		})
+	}
+}
+
+func TestReader_NewProducts(t *testing.T) {
+	csv := "Title,Category_ID,Price,Stock,Sizes\n" +
+		"Scarf,4,12.499,3,S | M |\n" +
+		"\n" +
+		"Hat,,-2,1.5,\n" +
+		"Belt,4,9,2.0,\n"
+	r, err := NewReader(strings.NewReader(csv), int64(len(csv)), FormatCSV)
+	require.NoError(t, err)
+
+	row, problems, err := r.Next()
+	require.NoError(t, err)
+	require.Empty(t, problems)
+	assert.Nil(t, row.ID)
+	assert.Equal(t, &models.CreateProductRequest{CategoryID: 4, Title: "Scarf", Price: 12.5, Stock: 3, Sizes: models.SizesJSON{"S", "M"}}, row.Create())
+
+	row, problems, err = r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, 4, row.Line, "blank lines still count")
+	assert.Equal(t, []string{
+		"price: must be a positive number",
+		"stock: must be a non-negative integer",
+		"category_id: required for new products",
+	}, problems)
+
+	row, problems, err = r.Next()
+	require.NoError(t, err)
+	require.Empty(t, problems)
+	assert.Equal(t, 2, *row.Update.Stock)
+
+	_, _, err = r.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestNewReader_Rejects(t *testing.T) {
+	for name, tc := range map[string]struct{ data, format string }{
+		"empty":          {"", FormatCSV},
+		"no header":      {"1,2,3\n", FormatCSV},
+		"not a zip":      {"id,title\n", FormatXLSX},
+		"unknown format": {"id\n", "ods"},
+	} {
+		_, err := NewReader(strings.NewReader(tc.data), int64(len(tc.data)), tc.format)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	f, err := FormatOf("Catalog.XLSX")
+	require.NoError(t, err)
+	assert.Equal(t, FormatXLSX, f)
+	_, err = FormatOf("catalog.xls")
+	assert.Error(t, err)
+}
+
+func TestColumnName(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		assert.Equal(t, want, columnName(i))
+		assert.Equal(t, i, columnIndex(want+"12"))
+	}
+}
@@ -0,0 +1,46 @@
+package catalog
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+)
+
+// Writer writes a catalog a batch of products at a time, so that large
+// catalogs need not be held in memory. Close finishes the file but does not
+// close the underlying writer.
+type Writer interface {
+	Write(products []*models.Product) error
+	Close() error
+}
+
+// NewWriter starts a catalog in format on w, writing its header row.
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+// ContentType returns the media type of catalogs in format.
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+func writeAll(w io.Writer, format string, products []*models.Product) error {
+	cw, err := NewWriter(w, format)
+	if err != nil {
+		return err
+	}
+	if err := cw.Write(products); err != nil {
+		return err
+	}
+	return cw.Close()
+}
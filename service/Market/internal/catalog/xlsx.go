@@ -0,0 +1,338 @@
+package catalog
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+)
+
+// maxSharedStrings bounds the decompressed shared strings part, which is
+// read whole, against zip bombs.
+const maxSharedStrings = 32 << 20
+
+// maxColumns bounds the cells read per row; catalogs have a few columns,
+// a stray cell far to the right should not allocate a huge row.
+const maxColumns = 64
+
+var errNotXLSX = errors.New("not a valid .xlsx file")
+
+// sheet streams the rows of a worksheet.
+type sheet struct {
+	dec     *xml.Decoder
+	strings []string
+	closer  io.Closer
+	row     int
+}
+
+// openSheet opens the first worksheet of a workbook.
+func openSheet(file io.ReaderAt, size int64) (*sheet, error) {
+	zr, err := zip.NewReader(file, size)
+	if err != nil {
+		return nil, errNotXLSX
+	}
+	parts := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		parts[f.Name] = f
+	}
+
+	name, err := firstSheet(parts)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := sharedStrings(parts["xl/sharedStrings.xml"])
+	if err != nil {
+		return nil, err
+	}
+
+	f, ok := parts[name]
+	if !ok {
+		return nil, errNotXLSX
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, errNotXLSX
+	}
+	return &sheet{dec: xml.NewDecoder(rc), strings: shared, closer: rc}, nil
+}
+
+// firstSheet resolves the part name of the workbook's first sheet.
+func firstSheet(parts map[string]*zip.File) (string, error) {
+	var workbook struct {
+		Sheets []struct {
+			RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	var rels struct {
+		Rels []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodePart(parts["xl/workbook.xml"], &workbook); err != nil || len(workbook.Sheets) == 0 {
+		return "", errNotXLSX
+	}
+	if err := decodePart(parts["xl/_rels/workbook.xml.rels"], &rels); err != nil {
+		return "", errNotXLSX
+	}
+	for _, rel := range rels.Rels {
+		if rel.ID == workbook.Sheets[0].RID {
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+			return path.Join("xl", rel.Target), nil
+		}
+	}
+	return "", errNotXLSX
+}
+
+// sharedStrings reads the workbook's string table; workbooks without
+// shared strings have none.
+func sharedStrings(f *zip.File) ([]string, error) {
+	if f == nil {
+		return nil, nil
+	}
+	if f.UncompressedSize64 > maxSharedStrings {
+		return nil, fmt.Errorf("spreadsheet text is too large")
+	}
+	var sst struct {
+		Items []richText `xml:"si"`
+	}
+	if err := decodePart(f, &sst); err != nil {
+		return nil, errNotXLSX
+	}
+	out := make([]string, len(sst.Items))
+	for i, si := range sst.Items {
+		out[i] = si.String()
+	}
+	return out, nil
+}
+
+func decodePart(f *zip.File, v any) error {
+	if f == nil {
+		return errNotXLSX
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(io.LimitReader(rc, maxSharedStrings)).Decode(v)
+}
+
+// richText is a string item: plain text or runs of formatted text.
+type richText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (rt richText) String() string {
+	if len(rt.Runs) == 0 {
+		return rt.T
+	}
+	var b strings.Builder
+	for _, r := range rt.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+type xlsxCell struct {
+	Ref    string   `xml:"r,attr"`
+	Type   string   `xml:"t,attr"`
+	Value  string   `xml:"v"`
+	Inline richText `xml:"is"`
+}
+
+// next returns the cells of the next row, placed by their column
+// references, with the row number, and io.EOF after the last row.
+func (s *sheet) next() ([]string, int, error) {
+	for {
+		tok, err := s.dec.Token()
+		if err != nil {
+			s.closer.Close()
+			if errors.Is(err, io.EOF) {
+				return nil, 0, io.EOF
+			}
+			return nil, 0, errNotXLSX
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var row struct {
+			Ref   int        `xml:"r,attr"`
+			Cells []xlsxCell `xml:"c"`
+		}
+		if err := s.dec.DecodeElement(&row, &start); err != nil {
+			s.closer.Close()
+			return nil, 0, errNotXLSX
+		}
+		s.row++
+		if row.Ref > 0 {
+			s.row = row.Ref
+		}
+		var record []string
+		for _, c := range row.Cells {
+			col := len(record)
+			if c.Ref != "" {
+				col = columnIndex(c.Ref)
+			}
+			if col < len(record) || col >= maxColumns {
+				continue
+			}
+			for len(record) < col {
+				record = append(record, "")
+			}
+			record = append(record, s.value(c))
+		}
+		return record, s.row, nil
+	}
+}
+
+func (s *sheet) value(c xlsxCell) string {
+	switch c.Type {
+	case "s":
+		i, err := strconv.Atoi(c.Value)
+		if err != nil || i < 0 || i >= len(s.strings) {
+			return ""
+		}
+		return s.strings[i]
+	case "inlineStr":
+		return c.Inline.String()
+	case "b":
+		if c.Value == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+	return c.Value
+}
+
+// columnIndex turns the letters of a cell reference such as "AB12" into a
+// zero-based column index.
+func columnIndex(ref string) int {
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		n = n*26 + int(r-'A'+1)
+	}
+	return n - 1
+}
+
+// columnName is the inverse of columnIndex.
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// The fixed parts of a single-sheet workbook.
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Products" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+)
+
+// numericColumns are written as numbers; the rest as inline strings.
+var numericColumns = map[string]bool{"id": true, "category_id": true, "price": true, "stock": true}
+
+// WriteXLSX writes products as a single-sheet XLSX catalog.
+func WriteXLSX(w io.Writer, products []*models.Product) error {
+	return writeAll(w, FormatXLSX, products)
+}
+
+// xlsxWriter writes the fixed workbook parts and the header row when
+// created; rows are added to the sheet as they are written.
+type xlsxWriter struct {
+	zw    *zip.Writer
+	sheet io.Writer
+	row   int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	zw := zip.NewWriter(w)
+	for _, part := range []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	} {
+		pw, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(pw, part.body); err != nil {
+			return nil, err
+		}
+	}
+
+	sw, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(sw, xml.Header+
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return nil, err
+	}
+	xw := &xlsxWriter{zw: zw, sheet: sw}
+	if err := xw.writeRow(Columns, true); err != nil {
+		return nil, err
+	}
+	return xw, nil
+}
+
+func (w *xlsxWriter) Write(products []*models.Product) error {
+	for _, p := range products {
+		if err := w.writeRow(record(p), false); err != nil {
+			return err
+		}
+	}
+	return w.zw.Flush()
+}
+
+func (w *xlsxWriter) writeRow(cells []string, header bool) error {
+	w.row++
+	var b strings.Builder
+	fmt.Fprintf(&b, `<row r="%d">`, w.row)
+	for i, v := range cells {
+		ref := columnName(i) + strconv.Itoa(w.row)
+		if !header && numericColumns[Columns[i]] {
+			fmt.Fprintf(&b, `<c r="%s"><v>%s</v></c>`, ref, v)
+			continue
+		}
+		fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+		if err := xml.EscapeText(&b, []byte(v)); err != nil {
+			return err
+		}
+		b.WriteString(`</t></is></c>`)
+	}
+	b.WriteString(`</row>`)
+	_, err := io.WriteString(w.sheet, b.String())
+	return err
+}
+
+func (w *xlsxWriter) Close() error {
+	if _, err := io.WriteString(w.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return w.zw.Close()
+}
@@ -0,0 +1,76 @@
+// Package chaos injects latency and errors into HTTP requests and repository
+// calls at configurable rates, so retry, timeout and circuit-breaker
+// behaviour can be exercised in staging. Configuration refuses to enable it
+// in production.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+)
+
+const (
+	LayerHTTP       = "http"
+	LayerRepository = "repository"
+)
+
+// ErrInjected is returned for an injected failure.
+var ErrInjected = errors.New("chaos: injected fault")
+
+type Config struct {
+	// Latency is added to a LatencyRate fraction of calls.
+	Latency     time.Duration
+	LatencyRate float64
+	// ErrorRate is the fraction of calls that fail with ErrInjected.
+	ErrorRate float64
+}
+
+// Injector decides per call whether to delay or fail it. A nil *Injector
+// injects nothing.
+type Injector struct {
+	cfg Config
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func New(cfg Config) *Injector {
+	return &Injector{cfg: cfg, rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// Inject delays and then possibly fails a call in the given layer. A delay
+// ends early with ctx.Err() when ctx is done.
+func (i *Injector) Inject(ctx context.Context, layer string) error {
+	if i == nil {
+		return nil
+	}
+	delay, fail := i.roll()
+
+	if delay && i.cfg.Latency > 0 {
+		metrics.ChaosFaultsTotal.WithLabelValues(layer, "latency").Inc()
+		timer := time.NewTimer(i.cfg.Latency)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if fail {
+		metrics.ChaosFaultsTotal.WithLabelValues(layer, "error").Inc()
+		return ErrInjected
+	}
+	return nil
+}
+
+func (i *Injector) roll() (delay, fail bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rand.Float64() < i.cfg.LatencyRate, i.rand.Float64() < i.cfg.ErrorRate
+}
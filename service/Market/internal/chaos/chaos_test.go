@@ -0,0 +1,46 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInject(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		injector *Injector
+		wantErr  error
+		minDelay time.Duration
+	}{
+		{name: "nil injector", injector: nil},
+		{name: "zero rates", injector: New(Config{Latency: time.Second})},
+		{name: "always fails", injector: New(Config{ErrorRate: 1}), wantErr: ErrInjected},
+		{name: "always delays", injector: New(Config{Latency: 20 * time.Millisecond, LatencyRate: 1}), minDelay: 20 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			err := tt.injector.Inject(ctx, LayerHTTP)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.GreaterOrEqual(t, time.Since(start), tt.minDelay)
+		})
+	}
+}
+
+func TestInject_DelayHonoursContext(t *testing.T) {
+	inj := New(Config{Latency: time.Minute, LatencyRate: 1})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := inj.Inject(ctx, LayerRepository)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -22,20 +25,75 @@ type DatabaseConfig struct {
 	MaxConnIdleTime   time.Duration
 	HealthCheckPeriod time.Duration
 	QueryTimeout      time.Duration
+
+	// QueryExecMode is a pgx query exec mode: cache_statement, cache_describe,
+	// describe_exec, exec or simple_protocol.
+	QueryExecMode            string
+	StatementCacheCapacity   int
+	DescriptionCacheCapacity int
 }
 
 type HTTPConfig struct {
 	Host            string
 	ShutdownTimeout time.Duration
 	RequestTimeout  time.Duration
+	MaxJSONBody     int64
+	MaxUploadBody   int64
+
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	H2C               bool
+}
+
+// TLSConfig enables TLS termination in the service itself, either from
+// certificate files or via ACME for AutocertDomains.
+type TLSConfig struct {
+	CertFile         string
+	KeyFile          string
+	AutocertDomains  []string
+	AutocertCacheDir string
+	AutocertEmail    string
 }
 
 type LoggerConfig struct {
-	Level string
+	Level            string
+	Format           string
+	SampleFirst      int
+	SampleThereafter int
 }
 
 type JWTConfig struct {
+	// AccessSecret verifies HS256 access tokens
 	AccessSecret string
+	// JWKSURL is the key set of the Auth service; RS256 access tokens are
+	// verified with the key named in their kid header
+	JWKSURL string
+	// PublicKeys are PEM files of the Auth service's public keys by kid,
+	// used instead of JWKSURL where Market should not fetch them
+	PublicKeys map[string]string
+	// Audience must be in the aud claim of accepted access tokens
+	Audience string
+}
+
+// AuthConfig points at the Auth service. When URL is set, access tokens are
+// introspected so revoked sessions stop working before they expire. When
+// GRPCAddr is set, admin order and seller lists are enriched with user
+// emails looked up over gRPC.
+type AuthConfig struct {
+	URL          string
+	GRPCAddr     string
+	ClientID     string
+	ClientSecret string
+	// SessionCacheTTL is how long an introspection result is reused
+	SessionCacheTTL time.Duration
+	// DenylistRedisAddr is the Auth service's Redis, where it records
+	// revoked access tokens under DenylistPrefix; empty skips the check
+	DenylistRedisAddr     string
+	DenylistRedisPassword string
+	DenylistRedisDB       int
+	DenylistPrefix        string
 }
 
 type RedisConfig struct {
@@ -51,28 +109,528 @@ type RateLimitConfig struct {
 	Interval time.Duration
 }
 
+type OrderConfig struct {
+	NumberPrefix   string
+	NumberFormat   string
+	NumberSeqWidth int
+}
+
+// ShippingConfig holds the carrier's parcel limits; zero disables a limit.
+// Girth is the longest side plus twice the sum of the other two.
+type ShippingConfig struct {
+	MaxWeightGrams int
+	MaxLengthMM    int
+	MaxGirthMM     int
+}
+
+type RetentionConfig struct {
+	Enabled         bool
+	DryRun          bool
+	Interval        time.Duration
+	CartTTL         time.Duration
+	OrderAddressTTL time.Duration
+	EventOutboxTTL  time.Duration
+	AuditLogTTL     time.Duration
+}
+
+type EncryptionConfig struct {
+	Keys          string
+	KeysFile      string
+	RotateOnStart bool
+}
+
+type ModerationConfig struct {
+	BannedWords []string
+	APIURL      string
+	APIKey      string
+	APITimeout  time.Duration
+	FailClosed  bool
+}
+
+type AddressConfig struct {
+	Provider string
+	APIKey   string
+	Timeout  time.Duration
+}
+
+type ListingConfig struct {
+	RefreshInterval time.Duration
+}
+
+// ReservationConfig controls stock reservations for cart items. A zero TTL
+// disables them; stock is then only checked at checkout.
+type ReservationConfig struct {
+	TTL           time.Duration
+	SweepInterval time.Duration
+}
+
+type StatementsConfig struct {
+	Enabled  bool
+	Interval time.Duration
+}
+
+// SellerStatsConfig controls seller analytics. Product views are counted
+// in memory and written every ViewsFlushInterval; products and variants
+// with at most their product's low-stock threshold in stock, or
+// LowStockThreshold without one, are flagged, and sellers are notified of
+// products that ran low every LowStockInterval.
+type SellerStatsConfig struct {
+	ViewsFlushInterval time.Duration
+	LowStockThreshold  int
+	LowStockInterval   time.Duration
+}
+
+// SellerHealthConfig controls seller health scoring. Orders placed within
+// Window count; items shipped later than ShipWithin after the order are
+// late. The rules are enforced every Interval, and a rule acts on a seller
+// at most once per Cooldown.
+type SellerHealthConfig struct {
+	Enabled    bool
+	Interval   time.Duration
+	Window     time.Duration
+	ShipWithin time.Duration
+	Cooldown   time.Duration
+}
+
+// FeedConfig controls the catalog feeds for ad platforms. Feed download
+// URLs are signed with SigningKey and stay valid for URLTTL.
+type FeedConfig struct {
+	Enabled    bool
+	SigningKey string
+	Dir        string
+	Interval   time.Duration
+	URLTTL     time.Duration
+	Currency   string
+	Title      string
+}
+
+// ExportConfig controls seller product exports. Exports of more than
+// AsyncThreshold products run as background jobs, checked for every
+// Interval, when SigningKey is set; their files are kept in Dir for FileTTL
+// and downloaded through URLs signed with SigningKey. Without a key every
+// export is streamed.
+type ExportConfig struct {
+	AsyncThreshold int
+	SigningKey     string
+	Dir            string
+	Interval       time.Duration
+	FileTTL        time.Duration
+}
+
+// Async reports whether large exports run as background jobs.
+func (c ExportConfig) Async() bool {
+	return c.SigningKey != ""
+}
+
+// ShareConfig controls product share links. Short links are served as
+// BaseURL + "/s/" + code.
+type ShareConfig struct {
+	BaseURL string
+}
+
+// SellerDomainConfig controls the custom domains sellers serve their own
+// storefront on. ReservedHosts are the marketplace's own hosts, which
+// sellers cannot claim; domain lookups are cached for CacheTTL.
+type SellerDomainConfig struct {
+	ReservedHosts []string
+	CacheTTL      time.Duration
+}
+
+// PageCacheConfig controls caching rendered public catalog pages in Redis
+// for TTL; 0 turns the page cache off. While it is on, the most visited
+// pages are warmed every WarmInterval, WarmConcurrency at a time, and right
+// away after a deploy or cache flush.
+type PageCacheConfig struct {
+	TTL             time.Duration
+	WarmInterval    time.Duration
+	WarmConcurrency int
+}
+
+// Enabled reports whether catalog pages are cached.
+func (c PageCacheConfig) Enabled() bool {
+	return c.TTL > 0
+}
+
+// APIUsageConfig controls seller API metering. Plans map API plan names
+// to the requests allowed per Window; 0 is unlimited. Sellers on a plan not
+// listed are limited by DefaultPlan.
+type APIUsageConfig struct {
+	Enabled        bool
+	Plans          map[string]int
+	DefaultPlan    string
+	Window         time.Duration
+	RollupInterval time.Duration
+}
+
+type MailConfig struct {
+	// Provider sends the email: "smtp" through SMTPHost, or "sendgrid"
+	// through the SendGrid API with SendGridAPIKey.
+	Provider       string
+	SMTPHost       string
+	SMTPPort       int
+	SMTPUsername   string
+	SMTPPassword   string
+	SendGridAPIKey string
+	From           string
+	SupportEmail   string
+	// TemplateDir holds email templates replacing the built-in ones;
+	// DisabledEvents are the events not emailed.
+	TemplateDir    string
+	DisabledEvents []string
+	// Sandbox captures outbound email in an outbox instead of sending it,
+	// also writing it to a maildir in SandboxDir when set. It is refused in
+	// production.
+	Sandbox    bool
+	SandboxDir string
+}
+
+type CompressionConfig struct {
+	MinSize int64
+	Groups  []string
+}
+
+// Enabled reports whether responses of the named route group are compressed.
+func (c CompressionConfig) Enabled(group string) bool {
+	for _, g := range c.Groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// ChaosConfig injects latency and errors for resilience testing. It can
+// only be enabled outside production.
+type ChaosConfig struct {
+	Enabled     bool
+	Layers      []string
+	Latency     time.Duration
+	LatencyRate float64
+	ErrorRate   float64
+}
+
+// Layer reports whether faults are injected into the named layer ("http" or
+// "repository").
+func (c ChaosConfig) Layer(name string) bool {
+	if !c.Enabled {
+		return false
+	}
+	for _, l := range c.Layers {
+		if l == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PaymentConfig selects the payment provider: "stripe", or "sandbox", the
+// built-in fake provider, which is refused in production.
+type PaymentConfig struct {
+	Provider string
+	// SandboxScenario is the default outcome of sandbox charges: succeed,
+	// fail, delay or challenge.
+	SandboxScenario string
+	// SandboxDelay is how long a delayed sandbox charge stays pending.
+	SandboxDelay time.Duration
+	// Currency charges are made in, lowercase ISO 4217.
+	Currency string
+	// Stripe API address and keys; WebhookSecret verifies webhook
+	// signatures.
+	StripeAPIURL        string
+	StripeSecretKey     string
+	StripeWebhookSecret string
+	// Timeout bounds each call to the provider's API.
+	Timeout time.Duration
+	// ReturnURL is the page buyers come back to after authenticating a
+	// charge, e.g. passing 3-D Secure. The order ID is added to it as the
+	// order_id query parameter.
+	ReturnURL string
+}
+
+// PayoutsConfig controls seller payouts through the payment provider.
+// Every Interval the unpaid statements of closed months are batched into
+// one payout per seller with a payout account, when they come to at least
+// Minimum, and due payouts are sent. A refused payout is retried after
+// RetryBackoff, doubling each time, and fails after MaxAttempts.
+//
+// A month is only batched once every sale in it has settled: delivered at
+// least the settlement delay of its category's risk level ago, or
+// TrustedSettlementDelay for trusted sellers when that is shorter, and
+// without an open chargeback or buyer support ticket.
+type PayoutsConfig struct {
+	Enabled      bool
+	Interval     time.Duration
+	Minimum      float64
+	MaxAttempts  int
+	RetryBackoff time.Duration
+
+	SettlementDelayLow      time.Duration
+	SettlementDelayStandard time.Duration
+	SettlementDelayHigh     time.Duration
+	TrustedSettlementDelay  time.Duration
+}
+
+// Sandbox reports whether the fake payment provider is in use.
+func (c PaymentConfig) Sandbox() bool {
+	return c.Provider == "sandbox"
+}
+
+// EventsConfig selects the message broker domain events are published to:
+// "kafka", with URL the address of a Kafka REST Proxy, or "nats", with URL
+// nats://[user:pass@]host:port. Without a broker no events are recorded.
+type EventsConfig struct {
+	Broker string
+	URL    string
+	// TopicPrefix starts every topic or subject, e.g. market.OrderCreated.
+	TopicPrefix string
+	// The outbox is relayed every Interval, BatchSize events at a time;
+	// Timeout bounds each call to the broker.
+	Interval  time.Duration
+	BatchSize int
+	Timeout   time.Duration
+	// LowStockThreshold is the stock at or below which an order's products
+	// are reported with ProductStockLow.
+	LowStockThreshold int
+}
+
+// Enabled reports whether domain events are recorded and published.
+func (c EventsConfig) Enabled() bool {
+	return c.Broker != ""
+}
+
+// TransformsConfig controls admin transform rules for outbound events and
+// notifications; rules changed outside the API take effect within
+// RefreshInterval.
+type TransformsConfig struct {
+	RefreshInterval time.Duration
+}
+
+// ProductImportConfig limits fetching product pages for imports.
+type ProductImportConfig struct {
+	Timeout     time.Duration
+	MaxPageSize int64
+}
+
+// CatalogConfig controls catalog revisions, the snapshots sellers roll
+// their catalog back to.
+type CatalogConfig struct {
+	// RevisionsKept is how many revisions are kept per seller; older ones
+	// are dropped as new ones are taken.
+	RevisionsKept int
+}
+
+// BotConfig controls bot detection. Requests are told apart by user agent
+// and, when FingerprintURL is set, by a fingerprinting service called with
+// FingerprintAPIKey within FingerprintTimeout. Scrapers may make
+// ScraperLimit requests per ScraperWindow and IP; 0 is unlimited.
+// BlockedAgents are always treated as scrapers and disallowed in robots.txt,
+// which asks crawlers to wait CrawlDelay between requests when set.
+type BotConfig struct {
+	Enabled            bool
+	ScraperLimit       int
+	ScraperWindow      time.Duration
+	BlockedAgents      []string
+	FingerprintURL     string
+	FingerprintAPIKey  string
+	FingerprintTimeout time.Duration
+	CrawlDelay         time.Duration
+}
+
+type TrendingConfig struct {
+	Decay float64
+}
+
+type MaintenanceConfig struct {
+	ReadOnlyTables string
+}
+
 type Config struct {
-	Strict    bool
-	Database  DatabaseConfig
-	HTTP      HTTPConfig
-	Logger    LoggerConfig
-	JWT       JWTConfig
-	Redis     RedisConfig
-	RateLimit RateLimitConfig
-	UploadDir string
-	BaseURL   string
+	// Env is the deployment environment, e.g. "development" or "production".
+	Env         string
+	Strict      bool
+	Database    DatabaseConfig
+	HTTP        HTTPConfig
+	TLS         TLSConfig
+	Logger      LoggerConfig
+	JWT         JWTConfig
+	Auth        AuthConfig
+	Redis       RedisConfig
+	RateLimit   RateLimitConfig
+	Order       OrderConfig
+	Shipping    ShippingConfig
+	Retention   RetentionConfig
+	Encryption  EncryptionConfig
+	Maintenance MaintenanceConfig
+	Moderation  ModerationConfig
+	Address     AddressConfig
+	Trending    TrendingConfig
+	Statements  StatementsConfig
+	SellerStats SellerStatsConfig
+	Health      SellerHealthConfig
+	Listing     ListingConfig
+	Reservation ReservationConfig
+	Feed        FeedConfig
+	Export      ExportConfig
+	Share       ShareConfig
+	Domains     SellerDomainConfig
+	PageCache   PageCacheConfig
+	APIUsage    APIUsageConfig
+	Mail        MailConfig
+	Compression CompressionConfig
+	Chaos       ChaosConfig
+	Payment     PaymentConfig
+	Payouts     PayoutsConfig
+	Events      EventsConfig
+	Transforms  TransformsConfig
+	Import      ProductImportConfig
+	Catalog     CatalogConfig
+	Bots        BotConfig
+	UploadDir   string
+	BaseURL     string
+	// ProductURL is the storefront page of a product; "{id}" is replaced
+	// with the product ID. Feeds, the sitemap and share links point here.
+	ProductURL string
+	// SellerDashboardURL is where the seller onboarding checklist links to.
+	SellerDashboardURL string
+}
+
+// parseAPIPlans parses plan limits such as "basic:300,pro:1200,enterprise:0".
+func parseAPIPlans(s string) (map[string]int, error) {
+	plans := make(map[string]int)
+	for _, entry := range strings.Split(s, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		name, limit, ok := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("plan %q must be name:limit", entry)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(limit))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("plan %q must have a non-negative limit", entry)
+		}
+		plans[name] = n
+	}
+	if len(plans) == 0 {
+		return nil, fmt.Errorf("at least one plan is required")
+	}
+	return plans, nil
+}
+
+// parsePublicKeys parses public key files such as
+// "2026-10=/run/secrets/2026-10.pub.pem,2026-04=/run/secrets/2026-04.pub.pem".
+func parsePublicKeys(s string) (map[string]string, error) {
+	var keys map[string]string
+	for _, entry := range strings.Split(s, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		kid, file, ok := strings.Cut(entry, "=")
+		if !ok || kid == "" || file == "" {
+			return nil, fmt.Errorf("entry %q must be kid=file", entry)
+		}
+		if _, ok := keys[kid]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", kid)
+		}
+		if keys == nil {
+			keys = make(map[string]string)
+		}
+		keys[kid] = file
+	}
+	return keys, nil
 }
 
+// parseByteSize parses sizes such as "512", "64KB" or "10MB" (binary units).
+func parseByteSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			multiplier = unit.size
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("size must be positive")
+	}
+	return n * multiplier, nil
+}
+
+var (
+	loadMu sync.Mutex
+	// fileValues holds the contents of CONFIG_FILE during Load.
+	fileValues map[string]string
+)
+
+// getEnv looks key up in CONFIG_FILE first, then in the process environment.
+// The file wins so that edits to it take effect on a config reload.
 func getEnv(key, defaultValue string) string {
+	if value := fileValues[key]; value != "" {
+		return value
+	}
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
 
+// readEnvFile parses KEY=VALUE lines; blank lines and # comments are skipped
+// and values may be quoted.
+func readEnvFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", i+1)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[strings.TrimSpace(key)] = value
+	}
+	return values, nil
+}
+
+// Load builds the configuration from the environment and the optional
+// CONFIG_FILE. It is safe to call again at runtime to reload.
 func Load(ctx context.Context) (*Config, error) {
+	loadMu.Lock()
+	defer loadMu.Unlock()
+
+	fileValues = nil
+	defer func() { fileValues = nil }()
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		values, err := readEnvFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CONFIG_FILE: %w", err)
+		}
+		fileValues = values
+	}
+
 	cfg := &Config{}
 
+	cfg.Env = getEnv("ENV", "development")
+
 	// Strict mode
 	cfg.Strict = getEnv("STRICT_MODE", "false") == "true"
 
@@ -97,6 +655,31 @@ func Load(ctx context.Context) (*Config, error) {
 		return nil, fmt.Errorf("invalid DB_QUERY_TIMEOUT: %w", err)
 	}
 
+	maxConnLifetime, err := time.ParseDuration(getEnv("DB_MAX_CONN_LIFETIME", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_MAX_CONN_LIFETIME: %w", err)
+	}
+
+	maxConnIdleTime, err := time.ParseDuration(getEnv("DB_MAX_CONN_IDLE_TIME", "30m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_MAX_CONN_IDLE_TIME: %w", err)
+	}
+
+	healthCheckPeriod, err := time.ParseDuration(getEnv("DB_HEALTH_CHECK_PERIOD", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_HEALTH_CHECK_PERIOD: %w", err)
+	}
+
+	statementCacheCapacity, err := strconv.Atoi(getEnv("DB_STATEMENT_CACHE_CAPACITY", "512"))
+	if err != nil || statementCacheCapacity < 0 {
+		return nil, fmt.Errorf("invalid DB_STATEMENT_CACHE_CAPACITY: must be a non-negative integer")
+	}
+
+	descriptionCacheCapacity, err := strconv.Atoi(getEnv("DB_DESCRIPTION_CACHE_CAPACITY", "512"))
+	if err != nil || descriptionCacheCapacity < 0 {
+		return nil, fmt.Errorf("invalid DB_DESCRIPTION_CACHE_CAPACITY: must be a non-negative integer")
+	}
+
 	cfg.Database = DatabaseConfig{
 		Host:              getEnv("DB_HOST", "localhost"),
 		Port:              port,
@@ -106,10 +689,14 @@ func Load(ctx context.Context) (*Config, error) {
 		SSLMode:           getEnv("DB_SSLMODE", "disable"),
 		MaxConns:          int32(maxConns),
 		MinConns:          int32(minConns),
-		MaxConnLifetime:   time.Hour,
-		MaxConnIdleTime:   30 * time.Minute,
-		HealthCheckPeriod: time.Minute,
+		MaxConnLifetime:   maxConnLifetime,
+		MaxConnIdleTime:   maxConnIdleTime,
+		HealthCheckPeriod: healthCheckPeriod,
 		QueryTimeout:      queryTimeout,
+
+		QueryExecMode:            getEnv("DB_QUERY_EXEC_MODE", "cache_statement"),
+		StatementCacheCapacity:   statementCacheCapacity,
+		DescriptionCacheCapacity: descriptionCacheCapacity,
 	}
 
 	// HTTP
@@ -123,25 +710,130 @@ func Load(ctx context.Context) (*Config, error) {
 		return nil, fmt.Errorf("invalid HTTP_REQUEST_TIMEOUT: %w", err)
 	}
 
+	maxJSONBody, err := parseByteSize(getEnv("HTTP_MAX_JSON_BODY", "1MB"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP_MAX_JSON_BODY: %w", err)
+	}
+
+	maxUploadBody, err := parseByteSize(getEnv("HTTP_MAX_UPLOAD_BODY", "30MB"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP_MAX_UPLOAD_BODY: %w", err)
+	}
+
+	readHeaderTimeout, err := time.ParseDuration(getEnv("HTTP_READ_HEADER_TIMEOUT", "10s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP_READ_HEADER_TIMEOUT: %w", err)
+	}
+
+	readTimeout, err := time.ParseDuration(getEnv("HTTP_READ_TIMEOUT", "60s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP_READ_TIMEOUT: %w", err)
+	}
+
+	writeTimeout, err := time.ParseDuration(getEnv("HTTP_WRITE_TIMEOUT", "60s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP_WRITE_TIMEOUT: %w", err)
+	}
+
+	idleTimeout, err := time.ParseDuration(getEnv("HTTP_IDLE_TIMEOUT", "120s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP_IDLE_TIMEOUT: %w", err)
+	}
+
 	cfg.HTTP = HTTPConfig{
-		Host:            getEnv("HTTP_HOST", ":8080"),
-		ShutdownTimeout: shutdownTimeout,
-		RequestTimeout:  requestTimeout,
+		Host:              getEnv("HTTP_HOST", ":8080"),
+		ShutdownTimeout:   shutdownTimeout,
+		RequestTimeout:    requestTimeout,
+		MaxJSONBody:       maxJSONBody,
+		MaxUploadBody:     maxUploadBody,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+		H2C:               getEnv("HTTP_H2C", "false") == "true",
+	}
+
+	// TLS
+	var autocertDomains []string
+	if domains := getEnv("TLS_AUTOCERT_DOMAINS", ""); domains != "" {
+		for _, d := range strings.Split(domains, ",") {
+			if d = strings.TrimSpace(d); d != "" {
+				autocertDomains = append(autocertDomains, d)
+			}
+		}
+	}
+
+	cfg.TLS = TLSConfig{
+		CertFile:         getEnv("TLS_CERT_FILE", ""),
+		KeyFile:          getEnv("TLS_KEY_FILE", ""),
+		AutocertDomains:  autocertDomains,
+		AutocertCacheDir: getEnv("TLS_AUTOCERT_CACHE_DIR", "./certs"),
+		AutocertEmail:    getEnv("TLS_AUTOCERT_EMAIL", ""),
 	}
 
 	// Logger
+	sampleFirst, err := strconv.Atoi(getEnv("LOG_SAMPLE_FIRST", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOG_SAMPLE_FIRST: %w", err)
+	}
+
+	sampleThereafter, err := strconv.Atoi(getEnv("LOG_SAMPLE_THEREAFTER", "100"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOG_SAMPLE_THEREAFTER: %w", err)
+	}
+
 	cfg.Logger = LoggerConfig{
-		Level: getEnv("LOG_LEVEL", "info"),
+		Level:            getEnv("LOG_LEVEL", "info"),
+		Format:           getEnv("LOG_FORMAT", "json"),
+		SampleFirst:      sampleFirst,
+		SampleThereafter: sampleThereafter,
 	}
 
 	// JWT
 	accessSecret := getEnv("JWT_ACCESS_SECRET", "")
-	if accessSecret == "" {
-		return nil, errors.New("JWT_ACCESS_SECRET is required")
+	jwksURL := getEnv("JWT_JWKS_URL", "")
+	publicKeys, err := parsePublicKeys(getEnv("JWT_PUBLIC_KEYS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_PUBLIC_KEYS: %w", err)
+	}
+	if jwksURL != "" && len(publicKeys) > 0 {
+		return nil, errors.New("JWT_JWKS_URL and JWT_PUBLIC_KEYS are mutually exclusive")
+	}
+	if accessSecret == "" && jwksURL == "" && len(publicKeys) == 0 {
+		return nil, errors.New("JWT_ACCESS_SECRET, JWT_JWKS_URL or JWT_PUBLIC_KEYS is required")
 	}
 
 	cfg.JWT = JWTConfig{
 		AccessSecret: accessSecret,
+		JWKSURL:      jwksURL,
+		PublicKeys:   publicKeys,
+		Audience:     getEnv("JWT_AUDIENCE", "marketback"),
+	}
+
+	// Auth service
+	sessionCacheTTL, err := time.ParseDuration(getEnv("AUTH_SESSION_CACHE_TTL", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AUTH_SESSION_CACHE_TTL: %w", err)
+	}
+
+	denylistRedisDB, err := strconv.Atoi(getEnv("AUTH_DENYLIST_REDIS_DB", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AUTH_DENYLIST_REDIS_DB: %w", err)
+	}
+
+	cfg.Auth = AuthConfig{
+		URL:                   strings.TrimRight(getEnv("AUTH_URL", ""), "/"),
+		GRPCAddr:              getEnv("AUTH_GRPC_ADDR", ""),
+		ClientID:              getEnv("AUTH_CLIENT_ID", ""),
+		ClientSecret:          getEnv("AUTH_CLIENT_SECRET", ""),
+		SessionCacheTTL:       sessionCacheTTL,
+		DenylistRedisAddr:     getEnv("AUTH_DENYLIST_REDIS_ADDR", ""),
+		DenylistRedisPassword: getEnv("AUTH_DENYLIST_REDIS_PASSWORD", ""),
+		DenylistRedisDB:       denylistRedisDB,
+		DenylistPrefix:        getEnv("AUTH_DENYLIST_PREFIX", "auth:"),
+	}
+	if (cfg.Auth.URL != "" || cfg.Auth.GRPCAddr != "") && (cfg.Auth.ClientID == "" || cfg.Auth.ClientSecret == "") {
+		return nil, errors.New("AUTH_CLIENT_ID and AUTH_CLIENT_SECRET are required with AUTH_URL or AUTH_GRPC_ADDR")
 	}
 
 	// Redis
@@ -174,9 +866,609 @@ func Load(ctx context.Context) (*Config, error) {
 		Interval: rateLimitInterval,
 	}
 
+	// Orders
+	orderSeqWidth, err := strconv.Atoi(getEnv("ORDER_NUMBER_SEQ_WIDTH", "6"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ORDER_NUMBER_SEQ_WIDTH: %w", err)
+	}
+
+	cfg.Order = OrderConfig{
+		NumberPrefix:   getEnv("ORDER_NUMBER_PREFIX", "MB"),
+		NumberFormat:   getEnv("ORDER_NUMBER_FORMAT", "{PREFIX}-{YYYY}-{SEQ}"),
+		NumberSeqWidth: orderSeqWidth,
+	}
+
+	// Carrier parcel limits
+	for _, limit := range []struct {
+		env, def string
+		dst      *int
+	}{
+		{"SHIPPING_MAX_WEIGHT_GRAMS", "31500", &cfg.Shipping.MaxWeightGrams},
+		{"SHIPPING_MAX_LENGTH_MM", "1200", &cfg.Shipping.MaxLengthMM},
+		{"SHIPPING_MAX_GIRTH_MM", "3000", &cfg.Shipping.MaxGirthMM},
+	} {
+		n, err := strconv.Atoi(getEnv(limit.env, limit.def))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid %s: must be a non-negative integer", limit.env)
+		}
+		*limit.dst = n
+	}
+
+	// Retention
+	retentionInterval, err := time.ParseDuration(getEnv("RETENTION_INTERVAL", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RETENTION_INTERVAL: %w", err)
+	}
+
+	cartTTL, err := time.ParseDuration(getEnv("RETENTION_CART_TTL", "720h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RETENTION_CART_TTL: %w", err)
+	}
+
+	orderAddressTTL, err := time.ParseDuration(getEnv("RETENTION_ORDER_ADDRESS_TTL", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RETENTION_ORDER_ADDRESS_TTL: %w", err)
+	}
+
+	eventOutboxTTL, err := time.ParseDuration(getEnv("RETENTION_EVENT_OUTBOX_TTL", "168h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RETENTION_EVENT_OUTBOX_TTL: %w", err)
+	}
+
+	auditLogTTL, err := time.ParseDuration(getEnv("RETENTION_AUDIT_LOG_TTL", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RETENTION_AUDIT_LOG_TTL: %w", err)
+	}
+
+	cfg.Retention = RetentionConfig{
+		Enabled:         getEnv("RETENTION_ENABLED", "false") == "true",
+		DryRun:          getEnv("RETENTION_DRY_RUN", "false") == "true",
+		Interval:        retentionInterval,
+		CartTTL:         cartTTL,
+		OrderAddressTTL: orderAddressTTL,
+		EventOutboxTTL:  eventOutboxTTL,
+		AuditLogTTL:     auditLogTTL,
+	}
+
+	// Encryption
+	cfg.Encryption = EncryptionConfig{
+		Keys:          getEnv("ENCRYPTION_KEYS", ""),
+		KeysFile:      getEnv("ENCRYPTION_KEYS_FILE", ""),
+		RotateOnStart: getEnv("ENCRYPTION_ROTATE_ON_START", "false") == "true",
+	}
+
+	// Maintenance
+	cfg.Maintenance = MaintenanceConfig{
+		ReadOnlyTables: getEnv("READ_ONLY_TABLES", ""),
+	}
+
+	// Moderation
+	moderationTimeout, err := time.ParseDuration(getEnv("MODERATION_API_TIMEOUT", "5s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MODERATION_API_TIMEOUT: %w", err)
+	}
+
+	var bannedWords []string
+	if words := getEnv("MODERATION_BANNED_WORDS", ""); words != "" {
+		bannedWords = strings.Split(words, ",")
+	}
+
+	cfg.Moderation = ModerationConfig{
+		BannedWords: bannedWords,
+		APIURL:      getEnv("MODERATION_API_URL", ""),
+		APIKey:      getEnv("MODERATION_API_KEY", ""),
+		APITimeout:  moderationTimeout,
+		FailClosed:  getEnv("MODERATION_FAIL_CLOSED", "false") == "true",
+	}
+
+	// Address validation
+	addressTimeout, err := time.ParseDuration(getEnv("ADDRESS_API_TIMEOUT", "5s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ADDRESS_API_TIMEOUT: %w", err)
+	}
+
+	cfg.Address = AddressConfig{
+		Provider: getEnv("ADDRESS_PROVIDER", ""),
+		APIKey:   getEnv("ADDRESS_API_KEY", ""),
+		Timeout:  addressTimeout,
+	}
+
+	// Trending
+	trendingDecay, err := strconv.ParseFloat(getEnv("TRENDING_DECAY", "0.9"), 64)
+	if err != nil || trendingDecay <= 0 || trendingDecay > 1 {
+		return nil, fmt.Errorf("invalid TRENDING_DECAY: must be in (0, 1]")
+	}
+
+	cfg.Trending = TrendingConfig{
+		Decay: trendingDecay,
+	}
+
+	// Seller statements
+	statementsInterval, err := time.ParseDuration(getEnv("STATEMENTS_INTERVAL", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid STATEMENTS_INTERVAL: %w", err)
+	}
+
+	cfg.Statements = StatementsConfig{
+		Enabled:  getEnv("STATEMENTS_ENABLED", "true") == "true",
+		Interval: statementsInterval,
+	}
+
+	// Seller analytics
+	viewsFlushInterval, err := time.ParseDuration(getEnv("SELLER_STATS_VIEWS_FLUSH_INTERVAL", "1m"))
+	if err != nil || viewsFlushInterval <= 0 {
+		return nil, fmt.Errorf("invalid SELLER_STATS_VIEWS_FLUSH_INTERVAL: must be a positive duration")
+	}
+	statsLowStock, err := strconv.Atoi(getEnv("SELLER_STATS_LOW_STOCK_THRESHOLD", "5"))
+	if err != nil || statsLowStock < 0 {
+		return nil, fmt.Errorf("invalid SELLER_STATS_LOW_STOCK_THRESHOLD: must be a non-negative stock level")
+	}
+	lowStockInterval, err := time.ParseDuration(getEnv("SELLER_STATS_LOW_STOCK_INTERVAL", "1m"))
+	if err != nil || lowStockInterval <= 0 {
+		return nil, fmt.Errorf("invalid SELLER_STATS_LOW_STOCK_INTERVAL: must be a positive duration")
+	}
+
+	cfg.SellerStats = SellerStatsConfig{
+		ViewsFlushInterval: viewsFlushInterval,
+		LowStockThreshold:  statsLowStock,
+		LowStockInterval:   lowStockInterval,
+	}
+
+	// Seller health
+	healthInterval, err := time.ParseDuration(getEnv("SELLER_HEALTH_INTERVAL", "1h"))
+	if err != nil || healthInterval <= 0 {
+		return nil, fmt.Errorf("invalid SELLER_HEALTH_INTERVAL: must be a positive duration")
+	}
+	healthWindow, err := time.ParseDuration(getEnv("SELLER_HEALTH_WINDOW", "720h"))
+	if err != nil || healthWindow <= 0 {
+		return nil, fmt.Errorf("invalid SELLER_HEALTH_WINDOW: must be a positive duration")
+	}
+	healthShipWithin, err := time.ParseDuration(getEnv("SELLER_HEALTH_SHIP_WITHIN", "72h"))
+	if err != nil || healthShipWithin <= 0 {
+		return nil, fmt.Errorf("invalid SELLER_HEALTH_SHIP_WITHIN: must be a positive duration")
+	}
+	healthCooldown, err := time.ParseDuration(getEnv("SELLER_HEALTH_COOLDOWN", "168h"))
+	if err != nil || healthCooldown < 0 {
+		return nil, fmt.Errorf("invalid SELLER_HEALTH_COOLDOWN: must not be negative")
+	}
+
+	cfg.Health = SellerHealthConfig{
+		Enabled:    getEnv("SELLER_HEALTH_ENABLED", "true") == "true",
+		Interval:   healthInterval,
+		Window:     healthWindow,
+		ShipWithin: healthShipWithin,
+		Cooldown:   healthCooldown,
+	}
+
+	// Product listing view
+	listingRefreshInterval, err := time.ParseDuration(getEnv("LISTING_REFRESH_INTERVAL", "1m"))
+	if err != nil || listingRefreshInterval <= 0 {
+		return nil, fmt.Errorf("invalid LISTING_REFRESH_INTERVAL: must be a positive duration")
+	}
+
+	cfg.Listing = ListingConfig{
+		RefreshInterval: listingRefreshInterval,
+	}
+
+	// Cart stock reservations
+	reservationTTL, err := time.ParseDuration(getEnv("RESERVATION_TTL", "15m"))
+	if err != nil || reservationTTL < 0 {
+		return nil, fmt.Errorf("invalid RESERVATION_TTL: must be a non-negative duration")
+	}
+	reservationSweep, err := time.ParseDuration(getEnv("RESERVATION_SWEEP_INTERVAL", "1m"))
+	if err != nil || reservationSweep <= 0 {
+		return nil, fmt.Errorf("invalid RESERVATION_SWEEP_INTERVAL: must be a positive duration")
+	}
+	cfg.Reservation = ReservationConfig{
+		TTL:           reservationTTL,
+		SweepInterval: reservationSweep,
+	}
+
+	// Catalog feeds
+	feedInterval, err := time.ParseDuration(getEnv("FEED_INTERVAL", "1h"))
+	if err != nil || feedInterval <= 0 {
+		return nil, fmt.Errorf("invalid FEED_INTERVAL: must be a positive duration")
+	}
+
+	feedURLTTL, err := time.ParseDuration(getEnv("FEED_URL_TTL", "168h"))
+	if err != nil || feedURLTTL <= 0 {
+		return nil, fmt.Errorf("invalid FEED_URL_TTL: must be a positive duration")
+	}
+
+	cfg.Feed = FeedConfig{
+		Enabled:    getEnv("FEEDS_ENABLED", "false") == "true",
+		SigningKey: getEnv("FEED_SIGNING_KEY", ""),
+		Dir:        getEnv("FEED_DIR", "./feeds"),
+		Interval:   feedInterval,
+		URLTTL:     feedURLTTL,
+		Currency:   strings.ToUpper(getEnv("FEED_CURRENCY", "USD")),
+		Title:      getEnv("FEED_TITLE", "Marketplace catalog"),
+	}
+	if cfg.Feed.Enabled {
+		if cfg.Feed.SigningKey == "" {
+			return nil, fmt.Errorf("invalid FEED_SIGNING_KEY: required when FEEDS_ENABLED=true")
+		}
+		if len(cfg.Feed.Currency) != 3 {
+			return nil, fmt.Errorf("invalid FEED_CURRENCY: must be an ISO 4217 code")
+		}
+	}
+
+	// Product exports
+	exportThreshold, err := strconv.Atoi(getEnv("EXPORT_ASYNC_THRESHOLD", "1000"))
+	if err != nil || exportThreshold < 0 {
+		return nil, fmt.Errorf("invalid EXPORT_ASYNC_THRESHOLD: must be a non-negative number of products")
+	}
+
+	exportInterval, err := time.ParseDuration(getEnv("EXPORT_INTERVAL", "5s"))
+	if err != nil || exportInterval <= 0 {
+		return nil, fmt.Errorf("invalid EXPORT_INTERVAL: must be a positive duration")
+	}
+
+	exportFileTTL, err := time.ParseDuration(getEnv("EXPORT_FILE_TTL", "24h"))
+	if err != nil || exportFileTTL <= 0 {
+		return nil, fmt.Errorf("invalid EXPORT_FILE_TTL: must be a positive duration")
+	}
+
+	cfg.Export = ExportConfig{
+		AsyncThreshold: exportThreshold,
+		SigningKey:     getEnv("EXPORT_SIGNING_KEY", ""),
+		Dir:            getEnv("EXPORT_DIR", "./exports"),
+		Interval:       exportInterval,
+		FileTTL:        exportFileTTL,
+	}
+
+	// Seller API usage
+	apiPlans, err := parseAPIPlans(getEnv("API_PLANS", "basic:300,pro:1200,enterprise:0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid API_PLANS: %w", err)
+	}
+
+	apiPlanWindow, err := time.ParseDuration(getEnv("API_PLAN_WINDOW", "1m"))
+	if err != nil || apiPlanWindow <= 0 {
+		return nil, fmt.Errorf("invalid API_PLAN_WINDOW: must be a positive duration")
+	}
+
+	apiUsageRollupInterval, err := time.ParseDuration(getEnv("API_USAGE_ROLLUP_INTERVAL", "15m"))
+	if err != nil || apiUsageRollupInterval <= 0 {
+		return nil, fmt.Errorf("invalid API_USAGE_ROLLUP_INTERVAL: must be a positive duration")
+	}
+
+	cfg.APIUsage = APIUsageConfig{
+		Enabled:        getEnv("API_USAGE_ENABLED", "true") == "true",
+		Plans:          apiPlans,
+		DefaultPlan:    getEnv("API_DEFAULT_PLAN", "basic"),
+		Window:         apiPlanWindow,
+		RollupInterval: apiUsageRollupInterval,
+	}
+	if _, ok := cfg.APIUsage.Plans[cfg.APIUsage.DefaultPlan]; !ok {
+		return nil, fmt.Errorf("invalid API_DEFAULT_PLAN: %q is not in API_PLANS", cfg.APIUsage.DefaultPlan)
+	}
+
+	// Response compression
+	compressionMinSize, err := parseByteSize(getEnv("COMPRESSION_MIN_SIZE", "1KB"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid COMPRESSION_MIN_SIZE: %w", err)
+	}
+
+	var compressionGroups []string
+	for _, g := range strings.Split(getEnv("COMPRESSION_GROUPS", "public,user"), ",") {
+		if g = strings.TrimSpace(g); g != "" && g != "none" {
+			compressionGroups = append(compressionGroups, g)
+		}
+	}
+
+	cfg.Compression = CompressionConfig{
+		MinSize: compressionMinSize,
+		Groups:  compressionGroups,
+	}
+
+	// Email
+	smtpPort, err := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP_PORT: %w", err)
+	}
+
+	cfg.Mail = MailConfig{
+		Provider:       getEnv("MAIL_PROVIDER", "smtp"),
+		SMTPHost:       getEnv("SMTP_HOST", ""),
+		SMTPPort:       smtpPort,
+		SMTPUsername:   getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:   getEnv("SMTP_PASSWORD", ""),
+		SendGridAPIKey: getEnv("SENDGRID_API_KEY", ""),
+		From:           getEnv("MAIL_FROM", ""),
+		SupportEmail:   getEnv("SUPPORT_EMAIL", ""),
+		TemplateDir:    getEnv("MAIL_TEMPLATE_DIR", ""),
+		Sandbox:        getEnv("MAIL_SANDBOX", "false") == "true",
+		SandboxDir:     getEnv("MAIL_SANDBOX_DIR", ""),
+	}
+	if cfg.Mail.Provider != "smtp" && cfg.Mail.Provider != "sendgrid" {
+		return nil, fmt.Errorf("invalid MAIL_PROVIDER: must be smtp or sendgrid")
+	}
+	for _, event := range strings.Split(getEnv("MAIL_DISABLED_EVENTS", ""), ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			cfg.Mail.DisabledEvents = append(cfg.Mail.DisabledEvents, event)
+		}
+	}
+	if cfg.Mail.Sandbox && (cfg.Env == "production" || cfg.Strict) {
+		return nil, fmt.Errorf("invalid MAIL_SANDBOX: the email sandbox is not allowed in production or strict mode")
+	}
+
 	// Upload settings
 	cfg.UploadDir = getEnv("UPLOAD_DIR", "./uploads")
 	cfg.BaseURL = getEnv("BASE_URL", "")
+	cfg.ProductURL = getEnv("PRODUCT_URL", cfg.BaseURL+"/products/{id}")
+	if !strings.Contains(cfg.ProductURL, "{id}") {
+		return nil, fmt.Errorf("invalid PRODUCT_URL: must contain {id}")
+	}
+	cfg.SellerDashboardURL = strings.TrimSuffix(getEnv("SELLER_DASHBOARD_URL", cfg.BaseURL+"/seller"), "/")
+
+	// Share links
+	cfg.Share = ShareConfig{
+		BaseURL: getEnv("SHARE_BASE_URL", cfg.BaseURL),
+	}
+
+	// Seller domains
+	domainCacheTTL, err := time.ParseDuration(getEnv("SELLER_DOMAIN_CACHE_TTL", "1m"))
+	if err != nil || domainCacheTTL < 0 {
+		return nil, fmt.Errorf("invalid SELLER_DOMAIN_CACHE_TTL: must be a non-negative duration")
+	}
+	reservedHosts := []string{"localhost"}
+	for _, raw := range []string{cfg.BaseURL, cfg.Share.BaseURL} {
+		if u, err := url.Parse(raw); err == nil && u.Hostname() != "" {
+			reservedHosts = append(reservedHosts, strings.ToLower(u.Hostname()))
+		}
+	}
+	for _, h := range strings.Split(getEnv("SELLER_DOMAIN_RESERVED_HOSTS", ""), ",") {
+		if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+			reservedHosts = append(reservedHosts, h)
+		}
+	}
+	cfg.Domains = SellerDomainConfig{ReservedHosts: reservedHosts, CacheTTL: domainCacheTTL}
+
+	// Catalog page cache
+	pageCacheTTL, err := time.ParseDuration(getEnv("PAGE_CACHE_TTL", "0"))
+	if err != nil || pageCacheTTL < 0 {
+		return nil, fmt.Errorf("invalid PAGE_CACHE_TTL: must be a non-negative duration")
+	}
+	warmInterval, err := time.ParseDuration(getEnv("CACHE_WARM_INTERVAL", "5m"))
+	if err != nil || warmInterval <= 0 {
+		return nil, fmt.Errorf("invalid CACHE_WARM_INTERVAL: must be a positive duration")
+	}
+	warmConcurrency, err := strconv.Atoi(getEnv("CACHE_WARM_CONCURRENCY", "4"))
+	if err != nil || warmConcurrency < 1 {
+		return nil, fmt.Errorf("invalid CACHE_WARM_CONCURRENCY: must be a positive number of pages")
+	}
+	cfg.PageCache = PageCacheConfig{
+		TTL:             pageCacheTTL,
+		WarmInterval:    warmInterval,
+		WarmConcurrency: warmConcurrency,
+	}
+
+	// Fault injection
+	chaosLatency, err := time.ParseDuration(getEnv("CHAOS_LATENCY", "500ms"))
+	if err != nil || chaosLatency < 0 {
+		return nil, fmt.Errorf("invalid CHAOS_LATENCY: must be a non-negative duration")
+	}
+	chaosLatencyRate, err := strconv.ParseFloat(getEnv("CHAOS_LATENCY_RATE", "0"), 64)
+	if err != nil || chaosLatencyRate < 0 || chaosLatencyRate > 1 {
+		return nil, fmt.Errorf("invalid CHAOS_LATENCY_RATE: must be in [0, 1]")
+	}
+	chaosErrorRate, err := strconv.ParseFloat(getEnv("CHAOS_ERROR_RATE", "0"), 64)
+	if err != nil || chaosErrorRate < 0 || chaosErrorRate > 1 {
+		return nil, fmt.Errorf("invalid CHAOS_ERROR_RATE: must be in [0, 1]")
+	}
+	var chaosLayers []string
+	for _, l := range strings.Split(getEnv("CHAOS_LAYERS", "http,repository"), ",") {
+		switch l = strings.TrimSpace(l); l {
+		case "":
+		case "http", "repository":
+			chaosLayers = append(chaosLayers, l)
+		default:
+			return nil, fmt.Errorf("invalid CHAOS_LAYERS: unknown layer %q", l)
+		}
+	}
+
+	cfg.Chaos = ChaosConfig{
+		Enabled:     getEnv("CHAOS_ENABLED", "false") == "true",
+		Layers:      chaosLayers,
+		Latency:     chaosLatency,
+		LatencyRate: chaosLatencyRate,
+		ErrorRate:   chaosErrorRate,
+	}
+	if cfg.Chaos.Enabled && (cfg.Env == "production" || cfg.Strict) {
+		return nil, fmt.Errorf("invalid CHAOS_ENABLED: fault injection is not allowed in production or strict mode")
+	}
+
+	// Payments
+	sandboxDelay, err := time.ParseDuration(getEnv("PAYMENT_SANDBOX_DELAY", "2s"))
+	if err != nil || sandboxDelay < 0 {
+		return nil, fmt.Errorf("invalid PAYMENT_SANDBOX_DELAY: must be a non-negative duration")
+	}
+	paymentTimeout, err := time.ParseDuration(getEnv("PAYMENT_TIMEOUT", "10s"))
+	if err != nil || paymentTimeout <= 0 {
+		return nil, fmt.Errorf("invalid PAYMENT_TIMEOUT: must be a positive duration")
+	}
+	cfg.Payment = PaymentConfig{
+		Provider:            getEnv("PAYMENT_PROVIDER", ""),
+		SandboxScenario:     getEnv("PAYMENT_SANDBOX_SCENARIO", "succeed"),
+		SandboxDelay:        sandboxDelay,
+		Currency:            strings.ToLower(getEnv("PAYMENT_CURRENCY", "usd")),
+		StripeAPIURL:        getEnv("STRIPE_API_URL", "https://api.stripe.com"),
+		StripeSecretKey:     getEnv("STRIPE_SECRET_KEY", ""),
+		StripeWebhookSecret: getEnv("STRIPE_WEBHOOK_SECRET", ""),
+		Timeout:             paymentTimeout,
+		ReturnURL:           getEnv("PAYMENT_RETURN_URL", ""),
+	}
+	if raw := cfg.Payment.ReturnURL; raw != "" {
+		if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid PAYMENT_RETURN_URL: must be an absolute http(s) URL")
+		}
+	}
+	switch cfg.Payment.Provider {
+	case "":
+	case "sandbox":
+		if cfg.Env == "production" || cfg.Strict {
+			return nil, fmt.Errorf("invalid PAYMENT_PROVIDER: the sandbox is not allowed in production or strict mode")
+		}
+	case "stripe":
+		if cfg.Payment.StripeSecretKey == "" {
+			return nil, fmt.Errorf("STRIPE_SECRET_KEY is required with PAYMENT_PROVIDER=stripe")
+		}
+		if cfg.Payment.StripeWebhookSecret == "" {
+			return nil, fmt.Errorf("STRIPE_WEBHOOK_SECRET is required with PAYMENT_PROVIDER=stripe")
+		}
+		if len(cfg.Payment.Currency) != 3 {
+			return nil, fmt.Errorf("invalid PAYMENT_CURRENCY: must be a three-letter ISO 4217 code")
+		}
+	default:
+		return nil, fmt.Errorf("invalid PAYMENT_PROVIDER: unknown provider %q", cfg.Payment.Provider)
+	}
+	switch cfg.Payment.SandboxScenario {
+	case "succeed", "fail", "delay", "challenge":
+	default:
+		return nil, fmt.Errorf("invalid PAYMENT_SANDBOX_SCENARIO: must be succeed, fail, delay or challenge")
+	}
+
+	// Seller payouts
+	payoutsInterval, err := time.ParseDuration(getEnv("PAYOUTS_INTERVAL", "1h"))
+	if err != nil || payoutsInterval <= 0 {
+		return nil, fmt.Errorf("invalid PAYOUTS_INTERVAL: must be a positive duration")
+	}
+	payoutsMinimum, err := strconv.ParseFloat(getEnv("PAYOUTS_MINIMUM", "10"), 64)
+	if err != nil || payoutsMinimum < 0 {
+		return nil, fmt.Errorf("invalid PAYOUTS_MINIMUM: must be a non-negative amount")
+	}
+	payoutsMaxAttempts, err := strconv.Atoi(getEnv("PAYOUTS_MAX_ATTEMPTS", "5"))
+	if err != nil || payoutsMaxAttempts < 1 {
+		return nil, fmt.Errorf("invalid PAYOUTS_MAX_ATTEMPTS: must be a positive number of attempts")
+	}
+	payoutsBackoff, err := time.ParseDuration(getEnv("PAYOUTS_RETRY_BACKOFF", "1h"))
+	if err != nil || payoutsBackoff <= 0 {
+		return nil, fmt.Errorf("invalid PAYOUTS_RETRY_BACKOFF: must be a positive duration")
+	}
+	cfg.Payouts = PayoutsConfig{
+		Enabled:      getEnv("PAYOUTS_ENABLED", "false") == "true",
+		Interval:     payoutsInterval,
+		Minimum:      payoutsMinimum,
+		MaxAttempts:  payoutsMaxAttempts,
+		RetryBackoff: payoutsBackoff,
+	}
+	for _, delay := range []struct {
+		env, def string
+		dst      *time.Duration
+	}{
+		{"PAYOUTS_SETTLEMENT_DELAY_LOW", "72h", &cfg.Payouts.SettlementDelayLow},
+		{"PAYOUTS_SETTLEMENT_DELAY_STANDARD", "168h", &cfg.Payouts.SettlementDelayStandard},
+		{"PAYOUTS_SETTLEMENT_DELAY_HIGH", "336h", &cfg.Payouts.SettlementDelayHigh},
+		{"PAYOUTS_TRUSTED_SETTLEMENT_DELAY", "24h", &cfg.Payouts.TrustedSettlementDelay},
+	} {
+		d, err := time.ParseDuration(getEnv(delay.env, delay.def))
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid %s: must be a non-negative duration", delay.env)
+		}
+		*delay.dst = d
+	}
+	if cfg.Payouts.Enabled && cfg.Payment.Provider == "" {
+		return nil, fmt.Errorf("PAYMENT_PROVIDER is required with PAYOUTS_ENABLED=true")
+	}
+
+	// Domain events
+	eventsInterval, err := time.ParseDuration(getEnv("EVENTS_INTERVAL", "1s"))
+	if err != nil || eventsInterval <= 0 {
+		return nil, fmt.Errorf("invalid EVENTS_INTERVAL: must be a positive duration")
+	}
+	eventsBatchSize, err := strconv.Atoi(getEnv("EVENTS_BATCH_SIZE", "100"))
+	if err != nil || eventsBatchSize < 1 {
+		return nil, fmt.Errorf("invalid EVENTS_BATCH_SIZE: must be a positive number of events")
+	}
+	eventsTimeout, err := time.ParseDuration(getEnv("EVENTS_TIMEOUT", "10s"))
+	if err != nil || eventsTimeout <= 0 {
+		return nil, fmt.Errorf("invalid EVENTS_TIMEOUT: must be a positive duration")
+	}
+	lowStockThreshold, err := strconv.Atoi(getEnv("EVENTS_LOW_STOCK_THRESHOLD", "5"))
+	if err != nil || lowStockThreshold < 0 {
+		return nil, fmt.Errorf("invalid EVENTS_LOW_STOCK_THRESHOLD: must be a non-negative stock level")
+	}
+	cfg.Events = EventsConfig{
+		Broker:            getEnv("EVENTS_BROKER", ""),
+		URL:               getEnv("EVENTS_URL", ""),
+		TopicPrefix:       getEnv("EVENTS_TOPIC_PREFIX", "market"),
+		Interval:          eventsInterval,
+		BatchSize:         eventsBatchSize,
+		Timeout:           eventsTimeout,
+		LowStockThreshold: lowStockThreshold,
+	}
+	switch cfg.Events.Broker {
+	case "":
+	case "kafka", "nats":
+		if cfg.Events.URL == "" {
+			return nil, fmt.Errorf("EVENTS_URL is required with EVENTS_BROKER=%s", cfg.Events.Broker)
+		}
+	default:
+		return nil, fmt.Errorf("invalid EVENTS_BROKER: must be kafka or nats")
+	}
+
+	// Transform rules
+	transformsRefresh, err := time.ParseDuration(getEnv("TRANSFORMS_REFRESH_INTERVAL", "30s"))
+	if err != nil || transformsRefresh <= 0 {
+		return nil, fmt.Errorf("invalid TRANSFORMS_REFRESH_INTERVAL: must be a positive duration")
+	}
+	cfg.Transforms = TransformsConfig{
+		RefreshInterval: transformsRefresh,
+	}
+
+	// Product import
+	importTimeout, err := time.ParseDuration(getEnv("PRODUCT_IMPORT_TIMEOUT", "10s"))
+	if err != nil || importTimeout <= 0 {
+		return nil, fmt.Errorf("invalid PRODUCT_IMPORT_TIMEOUT: must be a positive duration")
+	}
+	importMaxPageSize, err := strconv.ParseInt(getEnv("PRODUCT_IMPORT_MAX_PAGE_SIZE", "2097152"), 10, 64)
+	if err != nil || importMaxPageSize <= 0 {
+		return nil, fmt.Errorf("invalid PRODUCT_IMPORT_MAX_PAGE_SIZE: must be a positive number of bytes")
+	}
+	cfg.Import = ProductImportConfig{
+		Timeout:     importTimeout,
+		MaxPageSize: importMaxPageSize,
+	}
+
+	// Catalog revisions
+	revisionsKept, err := strconv.Atoi(getEnv("CATALOG_REVISIONS_KEPT", "20"))
+	if err != nil || revisionsKept < 1 {
+		return nil, fmt.Errorf("invalid CATALOG_REVISIONS_KEPT: must be a positive number of revisions")
+	}
+	cfg.Catalog = CatalogConfig{RevisionsKept: revisionsKept}
+
+	// Bot detection
+	scraperLimit, err := strconv.Atoi(getEnv("BOT_SCRAPER_LIMIT", "60"))
+	if err != nil || scraperLimit < 0 {
+		return nil, fmt.Errorf("invalid BOT_SCRAPER_LIMIT: must be a non-negative number of requests")
+	}
+	scraperWindow, err := time.ParseDuration(getEnv("BOT_SCRAPER_WINDOW", "1m"))
+	if err != nil || scraperWindow <= 0 {
+		return nil, fmt.Errorf("invalid BOT_SCRAPER_WINDOW: must be a positive duration")
+	}
+	fingerprintTimeout, err := time.ParseDuration(getEnv("BOT_FINGERPRINT_TIMEOUT", "300ms"))
+	if err != nil || fingerprintTimeout <= 0 {
+		return nil, fmt.Errorf("invalid BOT_FINGERPRINT_TIMEOUT: must be a positive duration")
+	}
+	crawlDelay, err := time.ParseDuration(getEnv("ROBOTS_CRAWL_DELAY", "0"))
+	if err != nil || crawlDelay < 0 {
+		return nil, fmt.Errorf("invalid ROBOTS_CRAWL_DELAY: must be a non-negative duration")
+	}
+	cfg.Bots = BotConfig{
+		Enabled:            getEnv("BOT_DETECTION_ENABLED", "false") == "true",
+		ScraperLimit:       scraperLimit,
+		ScraperWindow:      scraperWindow,
+		FingerprintURL:     getEnv("BOT_FINGERPRINT_URL", ""),
+		FingerprintAPIKey:  getEnv("BOT_FINGERPRINT_API_KEY", ""),
+		FingerprintTimeout: fingerprintTimeout,
+		CrawlDelay:         crawlDelay,
+	}
+	for _, agent := range strings.Split(getEnv("BOT_BLOCKED_AGENTS", ""), ",") {
+		if agent = strings.TrimSpace(agent); agent != "" {
+			cfg.Bots.BlockedAgents = append(cfg.Bots.BlockedAgents, agent)
+		}
+	}
 
 	return cfg, nil
 }
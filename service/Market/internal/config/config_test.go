@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -196,3 +197,520 @@ func TestConfig_FullStruct(t *testing.T) {
 	assert.Equal(t, "/uploads", cfg.UploadDir)
 	assert.Equal(t, "http://localhost:8080", cfg.BaseURL)
 }
+
+func TestLoad_RetentionDefaults(t *testing.T) {
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	defer os.Unsetenv("JWT_ACCESS_SECRET")
+
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+
+	assert.False(t, cfg.Retention.Enabled)
+	assert.False(t, cfg.Retention.DryRun)
+	assert.Equal(t, 24*time.Hour, cfg.Retention.Interval)
+	assert.Equal(t, 720*time.Hour, cfg.Retention.CartTTL)
+	assert.Equal(t, time.Duration(0), cfg.Retention.OrderAddressTTL)
+	assert.Equal(t, time.Duration(0), cfg.Retention.AuditLogTTL)
+}
+
+func TestLoad_InvalidRetentionInterval(t *testing.T) {
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	os.Setenv("RETENTION_INTERVAL", "daily")
+	defer func() {
+		os.Unsetenv("JWT_ACCESS_SECRET")
+		os.Unsetenv("RETENTION_INTERVAL")
+	}()
+
+	_, err := Load(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "RETENTION_INTERVAL")
+}
+
+func TestLoad_Reservation(t *testing.T) {
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	defer func() {
+		os.Unsetenv("JWT_ACCESS_SECRET")
+		os.Unsetenv("RESERVATION_TTL")
+	}()
+
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Minute, cfg.Reservation.TTL)
+	assert.Equal(t, time.Minute, cfg.Reservation.SweepInterval)
+
+	os.Setenv("RESERVATION_TTL", "0")
+	cfg, err = Load(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, cfg.Reservation.TTL, "zero disables reservations")
+
+	os.Setenv("RESERVATION_TTL", "-1m")
+	_, err = Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "RESERVATION_TTL")
+}
+
+func TestLoad_Moderation(t *testing.T) {
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	os.Setenv("MODERATION_BANNED_WORDS", "replica,fake rolex")
+	defer func() {
+		os.Unsetenv("JWT_ACCESS_SECRET")
+		os.Unsetenv("MODERATION_BANNED_WORDS")
+	}()
+
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"replica", "fake rolex"}, cfg.Moderation.BannedWords)
+	assert.Empty(t, cfg.Moderation.APIURL)
+	assert.Equal(t, 5*time.Second, cfg.Moderation.APITimeout)
+	assert.False(t, cfg.Moderation.FailClosed)
+}
+
+func TestLoad_Address(t *testing.T) {
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	os.Setenv("ADDRESS_PROVIDER", "here")
+	os.Setenv("ADDRESS_API_TIMEOUT", "2s")
+	defer func() {
+		os.Unsetenv("JWT_ACCESS_SECRET")
+		os.Unsetenv("ADDRESS_PROVIDER")
+		os.Unsetenv("ADDRESS_API_TIMEOUT")
+	}()
+
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "here", cfg.Address.Provider)
+	assert.Empty(t, cfg.Address.APIKey)
+	assert.Equal(t, 2*time.Second, cfg.Address.Timeout)
+}
+
+func TestLoad_MailDisabledByDefault(t *testing.T) {
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	defer os.Unsetenv("JWT_ACCESS_SECRET")
+
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+
+	assert.Empty(t, cfg.Mail.SMTPHost)
+	assert.Equal(t, 587, cfg.Mail.SMTPPort)
+	assert.Empty(t, cfg.Mail.SupportEmail)
+}
+
+func TestParseByteSize(t *testing.T) {
+	cases := map[string]int64{
+		"512":  512,
+		"64KB": 64 << 10,
+		"10mb": 10 << 20,
+		"1 GB": 1 << 30,
+		"100B": 100,
+	}
+	for in, want := range cases {
+		got, err := parseByteSize(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	for _, in := range []string{"", "ten", "0", "-1MB"} {
+		_, err := parseByteSize(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestLoad_BodyLimitDefaults(t *testing.T) {
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	defer os.Unsetenv("JWT_ACCESS_SECRET")
+
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1<<20), cfg.HTTP.MaxJSONBody)
+	assert.Equal(t, int64(30<<20), cfg.HTTP.MaxUploadBody)
+}
+
+func TestLoad_CompressionGroups(t *testing.T) {
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	os.Setenv("COMPRESSION_GROUPS", "public, admin")
+	defer func() {
+		os.Unsetenv("JWT_ACCESS_SECRET")
+		os.Unsetenv("COMPRESSION_GROUPS")
+	}()
+
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1024), cfg.Compression.MinSize)
+	assert.True(t, cfg.Compression.Enabled("public"))
+	assert.True(t, cfg.Compression.Enabled("admin"))
+	assert.False(t, cfg.Compression.Enabled("user"))
+}
+
+func TestLoad_Feeds(t *testing.T) {
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	os.Setenv("BASE_URL", "https://shop.example.com")
+	os.Setenv("FEEDS_ENABLED", "true")
+	defer func() {
+		os.Unsetenv("JWT_ACCESS_SECRET")
+		os.Unsetenv("BASE_URL")
+		os.Unsetenv("FEEDS_ENABLED")
+		os.Unsetenv("FEED_SIGNING_KEY")
+	}()
+
+	_, err := Load(context.Background())
+	require.Error(t, err, "enabled feeds need a signing key")
+
+	os.Setenv("FEED_SIGNING_KEY", "feedsecret")
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, cfg.Feed.Enabled)
+	assert.Equal(t, "https://shop.example.com/products/{id}", cfg.ProductURL)
+	assert.Equal(t, "USD", cfg.Feed.Currency)
+	assert.Equal(t, time.Hour, cfg.Feed.Interval)
+	assert.Equal(t, 7*24*time.Hour, cfg.Feed.URLTTL)
+}
+
+func TestLoad_APIUsage(t *testing.T) {
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	defer func() {
+		os.Unsetenv("JWT_ACCESS_SECRET")
+		os.Unsetenv("API_PLANS")
+		os.Unsetenv("API_DEFAULT_PLAN")
+	}()
+
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"basic": 300, "pro": 1200, "enterprise": 0}, cfg.APIUsage.Plans)
+	assert.Equal(t, "basic", cfg.APIUsage.DefaultPlan)
+	assert.Equal(t, time.Minute, cfg.APIUsage.Window)
+
+	os.Setenv("API_PLANS", "free:60, partner:6000")
+	_, err = Load(context.Background())
+	require.Error(t, err, "default plan must be one of the plans")
+
+	os.Setenv("API_DEFAULT_PLAN", "free")
+	cfg, err = Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"free": 60, "partner": 6000}, cfg.APIUsage.Plans)
+
+	os.Setenv("API_PLANS", "free:-1")
+	_, err = Load(context.Background())
+	require.Error(t, err)
+}
+
+func TestLoad_ServerTimeoutsAndTLS(t *testing.T) {
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	os.Setenv("HTTP_IDLE_TIMEOUT", "90s")
+	os.Setenv("TLS_AUTOCERT_DOMAINS", "shop.example.com, api.example.com")
+	defer func() {
+		os.Unsetenv("JWT_ACCESS_SECRET")
+		os.Unsetenv("HTTP_IDLE_TIMEOUT")
+		os.Unsetenv("TLS_AUTOCERT_DOMAINS")
+	}()
+
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 10*time.Second, cfg.HTTP.ReadHeaderTimeout)
+	assert.Equal(t, 90*time.Second, cfg.HTTP.IdleTimeout)
+	assert.False(t, cfg.HTTP.H2C)
+	assert.Equal(t, []string{"shop.example.com", "api.example.com"}, cfg.TLS.AutocertDomains)
+	assert.Equal(t, "./certs", cfg.TLS.AutocertCacheDir)
+}
+
+func TestLoad_ConfigFileOverridesEnvironment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "market.env")
+	require.NoError(t, os.WriteFile(path, []byte("# reloadable settings\nLOG_LEVEL=debug\nRATE_LIMIT_MAX=\"25\"\n"), 0o600))
+
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	os.Setenv("LOG_LEVEL", "warn")
+	os.Setenv("CONFIG_FILE", path)
+	defer func() {
+		os.Unsetenv("JWT_ACCESS_SECRET")
+		os.Unsetenv("LOG_LEVEL")
+		os.Unsetenv("CONFIG_FILE")
+	}()
+
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "debug", cfg.Logger.Level)
+	assert.Equal(t, 25, cfg.RateLimit.Max)
+}
+
+func TestLoad_InvalidConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "market.env")
+	require.NoError(t, os.WriteFile(path, []byte("LOG_LEVEL debug\n"), 0o600))
+
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	os.Setenv("CONFIG_FILE", path)
+	defer func() {
+		os.Unsetenv("JWT_ACCESS_SECRET")
+		os.Unsetenv("CONFIG_FILE")
+	}()
+
+	_, err := Load(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "CONFIG_FILE")
+}
+
+func TestLoad_AuthRequiresClientCredentials(t *testing.T) {
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	os.Setenv("AUTH_URL", "http://auth:8080/")
+	defer func() {
+		os.Unsetenv("JWT_ACCESS_SECRET")
+		os.Unsetenv("AUTH_URL")
+		os.Unsetenv("AUTH_CLIENT_ID")
+		os.Unsetenv("AUTH_CLIENT_SECRET")
+	}()
+
+	_, err := Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AUTH_CLIENT_ID")
+
+	os.Setenv("AUTH_CLIENT_ID", "market")
+	os.Setenv("AUTH_CLIENT_SECRET", "secret")
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "http://auth:8080", cfg.Auth.URL)
+	assert.Equal(t, 30*time.Second, cfg.Auth.SessionCacheTTL)
+}
+
+func TestLoad_JWTKeys(t *testing.T) {
+	os.Unsetenv("JWT_ACCESS_SECRET")
+	_, err := Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JWT_JWKS_URL")
+
+	// Signing keys published by the Auth service replace the shared secret
+	os.Setenv("JWT_JWKS_URL", "http://auth:8081/.well-known/jwks.json")
+	defer os.Unsetenv("JWT_JWKS_URL")
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, cfg.JWT.AccessSecret)
+	assert.Equal(t, "http://auth:8081/.well-known/jwks.json", cfg.JWT.JWKSURL)
+
+	// Or configured as files, but not both
+	os.Setenv("JWT_PUBLIC_KEYS", "2026-10=/run/secrets/2026-10.pub.pem")
+	defer os.Unsetenv("JWT_PUBLIC_KEYS")
+	_, err = Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mutually exclusive")
+
+	os.Unsetenv("JWT_JWKS_URL")
+	cfg, err = Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"2026-10": "/run/secrets/2026-10.pub.pem"}, cfg.JWT.PublicKeys)
+
+	os.Setenv("JWT_PUBLIC_KEYS", "/run/secrets/2026-10.pub.pem")
+	_, err = Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JWT_PUBLIC_KEYS")
+}
+
+func TestLoad_ChaosRefusedInProduction(t *testing.T) {
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	os.Setenv("CHAOS_ENABLED", "true")
+	os.Setenv("CHAOS_ERROR_RATE", "0.1")
+	defer func() {
+		os.Unsetenv("JWT_ACCESS_SECRET")
+		os.Unsetenv("CHAOS_ENABLED")
+		os.Unsetenv("CHAOS_ERROR_RATE")
+		os.Unsetenv("ENV")
+		os.Unsetenv("CHAOS_LAYERS")
+	}()
+
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+	assert.True(t, cfg.Chaos.Layer("http"))
+	assert.True(t, cfg.Chaos.Layer("repository"))
+	assert.Equal(t, 0.1, cfg.Chaos.ErrorRate)
+
+	os.Setenv("CHAOS_LAYERS", "database")
+	_, err = Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CHAOS_LAYERS")
+
+	os.Unsetenv("CHAOS_LAYERS")
+	os.Setenv("ENV", "production")
+	_, err = Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CHAOS_ENABLED")
+}
+
+func TestLoad_PaymentSandbox(t *testing.T) {
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	os.Setenv("PAYMENT_PROVIDER", "sandbox")
+	os.Setenv("PAYMENT_SANDBOX_SCENARIO", "delay")
+	defer func() {
+		os.Unsetenv("JWT_ACCESS_SECRET")
+		os.Unsetenv("PAYMENT_PROVIDER")
+		os.Unsetenv("PAYMENT_SANDBOX_SCENARIO")
+		os.Unsetenv("STRICT_MODE")
+	}()
+
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+	assert.True(t, cfg.Payment.Sandbox())
+	assert.Equal(t, "delay", cfg.Payment.SandboxScenario)
+	assert.Equal(t, 2*time.Second, cfg.Payment.SandboxDelay)
+
+	os.Setenv("PAYMENT_SANDBOX_SCENARIO", "explode")
+	_, err = Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "PAYMENT_SANDBOX_SCENARIO")
+
+	os.Setenv("PAYMENT_SANDBOX_SCENARIO", "challenge")
+	os.Setenv("PAYMENT_RETURN_URL", "https://shop.example/checkout/return")
+	cfg, err = Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "https://shop.example/checkout/return", cfg.Payment.ReturnURL)
+
+	os.Setenv("PAYMENT_RETURN_URL", "/checkout/return")
+	_, err = Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "PAYMENT_RETURN_URL")
+	os.Unsetenv("PAYMENT_RETURN_URL")
+
+	os.Unsetenv("PAYMENT_SANDBOX_SCENARIO")
+	os.Setenv("STRICT_MODE", "true")
+	_, err = Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "PAYMENT_PROVIDER")
+}
+
+func TestLoad_PaymentStripe(t *testing.T) {
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	os.Setenv("PAYMENT_PROVIDER", "stripe")
+	os.Setenv("STRIPE_SECRET_KEY", "sk_test")
+	os.Setenv("PAYMENT_CURRENCY", "EUR")
+	os.Setenv("STRICT_MODE", "true")
+	defer func() {
+		os.Unsetenv("JWT_ACCESS_SECRET")
+		os.Unsetenv("PAYMENT_PROVIDER")
+		os.Unsetenv("STRIPE_SECRET_KEY")
+		os.Unsetenv("STRIPE_WEBHOOK_SECRET")
+		os.Unsetenv("PAYMENT_CURRENCY")
+		os.Unsetenv("STRICT_MODE")
+	}()
+
+	_, err := Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "STRIPE_WEBHOOK_SECRET")
+
+	os.Setenv("STRIPE_WEBHOOK_SECRET", "whsec_test")
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+	assert.False(t, cfg.Payment.Sandbox())
+	assert.Equal(t, "eur", cfg.Payment.Currency)
+	assert.Equal(t, "https://api.stripe.com", cfg.Payment.StripeAPIURL)
+	assert.Equal(t, 10*time.Second, cfg.Payment.Timeout)
+
+	os.Setenv("PAYMENT_CURRENCY", "euro")
+	_, err = Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "PAYMENT_CURRENCY")
+}
+
+func TestLoad_Payouts(t *testing.T) {
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	os.Setenv("PAYOUTS_ENABLED", "true")
+	defer func() {
+		os.Unsetenv("JWT_ACCESS_SECRET")
+		os.Unsetenv("PAYOUTS_ENABLED")
+		os.Unsetenv("PAYMENT_PROVIDER")
+		os.Unsetenv("PAYOUTS_MAX_ATTEMPTS")
+		os.Unsetenv("PAYOUTS_SETTLEMENT_DELAY_HIGH")
+	}()
+
+	_, err := Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "PAYMENT_PROVIDER")
+
+	os.Setenv("PAYMENT_PROVIDER", "sandbox")
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+	assert.True(t, cfg.Payouts.Enabled)
+	assert.Equal(t, time.Hour, cfg.Payouts.Interval)
+	assert.Equal(t, 10.0, cfg.Payouts.Minimum)
+	assert.Equal(t, 5, cfg.Payouts.MaxAttempts)
+	assert.Equal(t, time.Hour, cfg.Payouts.RetryBackoff)
+	assert.Equal(t, 72*time.Hour, cfg.Payouts.SettlementDelayLow)
+	assert.Equal(t, 168*time.Hour, cfg.Payouts.SettlementDelayStandard)
+	assert.Equal(t, 336*time.Hour, cfg.Payouts.SettlementDelayHigh)
+	assert.Equal(t, 24*time.Hour, cfg.Payouts.TrustedSettlementDelay)
+
+	os.Setenv("PAYOUTS_SETTLEMENT_DELAY_HIGH", "-1h")
+	_, err = Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "PAYOUTS_SETTLEMENT_DELAY_HIGH")
+	os.Unsetenv("PAYOUTS_SETTLEMENT_DELAY_HIGH")
+
+	os.Setenv("PAYOUTS_MAX_ATTEMPTS", "0")
+	_, err = Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "PAYOUTS_MAX_ATTEMPTS")
+}
+
+func TestLoad_MailSandboxRefusedInProduction(t *testing.T) {
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	os.Setenv("MAIL_SANDBOX", "true")
+	defer func() {
+		os.Unsetenv("JWT_ACCESS_SECRET")
+		os.Unsetenv("MAIL_SANDBOX")
+		os.Unsetenv("ENV")
+	}()
+
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+	assert.True(t, cfg.Mail.Sandbox)
+
+	os.Setenv("ENV", "production")
+	_, err = Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "MAIL_SANDBOX")
+}
+
+func TestLoad_Bots(t *testing.T) {
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	os.Setenv("BOT_DETECTION_ENABLED", "true")
+	os.Setenv("BOT_BLOCKED_AGENTS", "AhrefsBot, ,SemrushBot")
+	defer func() {
+		os.Unsetenv("JWT_ACCESS_SECRET")
+		os.Unsetenv("BOT_DETECTION_ENABLED")
+		os.Unsetenv("BOT_BLOCKED_AGENTS")
+		os.Unsetenv("BOT_SCRAPER_LIMIT")
+	}()
+
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+	assert.True(t, cfg.Bots.Enabled)
+	assert.Equal(t, []string{"AhrefsBot", "SemrushBot"}, cfg.Bots.BlockedAgents)
+	assert.Equal(t, 60, cfg.Bots.ScraperLimit)
+	assert.Equal(t, time.Minute, cfg.Bots.ScraperWindow)
+	assert.Equal(t, 300*time.Millisecond, cfg.Bots.FingerprintTimeout)
+	assert.Zero(t, cfg.Bots.CrawlDelay)
+
+	os.Setenv("BOT_SCRAPER_LIMIT", "-1")
+	_, err = Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "BOT_SCRAPER_LIMIT")
+}
+
+func TestLoad_ShippingLimits(t *testing.T) {
+	os.Setenv("JWT_ACCESS_SECRET", "testsecret")
+	os.Setenv("SHIPPING_MAX_GIRTH_MM", "0")
+	defer func() {
+		os.Unsetenv("JWT_ACCESS_SECRET")
+		os.Unsetenv("SHIPPING_MAX_GIRTH_MM")
+		os.Unsetenv("SHIPPING_MAX_WEIGHT_GRAMS")
+	}()
+
+	cfg, err := Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, ShippingConfig{MaxWeightGrams: 31500, MaxLengthMM: 1200}, cfg.Shipping)
+
+	os.Setenv("SHIPPING_MAX_WEIGHT_GRAMS", "30kg")
+	_, err = Load(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SHIPPING_MAX_WEIGHT_GRAMS")
+}
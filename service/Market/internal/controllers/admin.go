@@ -1,12 +1,17 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
 	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/readonly"
 	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/service"
+	"github.com/Zifeldev/marketback/service/Market/internal/users"
 	"github.com/gin-gonic/gin"
 )
 
@@ -15,6 +20,10 @@ type AdminController struct {
 	productRepo  *repository.ProductRepository
 	sellerRepo   *repository.SellerRepository
 	orderRepo    *repository.OrderRepository
+	deliveryRepo *repository.DeliveryRepository
+	orders       *service.MarketService
+	readOnly     *readonly.Guard
+	users        *users.Directory
 }
 
 func NewAdminController(
@@ -22,18 +31,26 @@ func NewAdminController(
 	productRepo *repository.ProductRepository,
 	sellerRepo *repository.SellerRepository,
 	orderRepo *repository.OrderRepository,
+	deliveryRepo *repository.DeliveryRepository,
+	orders *service.MarketService,
+	readOnly *readonly.Guard,
+	users *users.Directory,
 ) *AdminController {
 	return &AdminController{
 		categoryRepo: categoryRepo,
 		productRepo:  productRepo,
 		sellerRepo:   sellerRepo,
 		orderRepo:    orderRepo,
+		deliveryRepo: deliveryRepo,
+		orders:       orders,
+		readOnly:     readOnly,
+		users:        users,
 	}
 }
 
 // CreateCategory godoc
 // @Summary Create category
-// @Description Create a new product category (admin only)
+// @Description Create a new product category (admin only). Its risk_level, low, standard (default) or high, sets how long after delivery sales in it become payable to sellers.
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -62,7 +79,7 @@ func (ac *AdminController) CreateCategory(c *gin.Context) {
 
 // UpdateCategory godoc
 // @Summary Update category
-// @Description Update an existing category (admin only)
+// @Description Update an existing category (admin only). Changing risk_level applies to sales not paid out yet.
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -98,16 +115,20 @@ func (ac *AdminController) UpdateCategory(c *gin.Context) {
 
 // DeleteCategory godoc
 // @Summary Delete category
-// @Description Delete a category (admin only)
+// @Description Delete a category (admin only). A category that still has products is rejected unless force=true moves them to reassign_to.
 // @Tags admin
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Category ID"
+// @Param force query bool false "Delete even if the category has products"
+// @Param reassign_to query int false "Category that takes over the products; required with force"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/admin/categories/{id} [delete]
 func (ac *AdminController) DeleteCategory(c *gin.Context) {
@@ -117,7 +138,22 @@ func (ac *AdminController) DeleteCategory(c *gin.Context) {
 		return
 	}
 
-	if err := ac.categoryRepo.Delete(c.Request.Context(), id); err != nil {
+	var params models.DeleteCategoryParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	var reassignTo *int
+	if params.Force {
+		if params.ReassignTo == nil {
+			respondError(c, apperrors.ValidationError("reassign_to", "is required with force=true"))
+			return
+		}
+		reassignTo = params.ReassignTo
+	}
+
+	if err := ac.categoryRepo.Delete(c.Request.Context(), id, reassignTo); err != nil {
 		handleError(c, err, apperrors.Internal("failed to delete category"))
 		return
 	}
@@ -169,7 +205,7 @@ func (ac *AdminController) UpdateProductStatus(c *gin.Context) {
 
 // GetAllSellers godoc
 // @Summary Get all sellers
-// @Description Get list of all sellers (admin only)
+// @Description Get list of all sellers (admin only). With the Auth service's gRPC API configured each seller has its owner's user_email.
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -185,6 +221,15 @@ func (ac *AdminController) GetAllSellers(c *gin.Context) {
 		return
 	}
 
+	userIDs := make([]int, len(sellers))
+	for i, seller := range sellers {
+		userIDs[i] = seller.UserID
+	}
+	emails := ac.users.Emails(c.Request.Context(), userIDs)
+	for _, seller := range sellers {
+		seller.UserEmail = emails[seller.UserID]
+	}
+
 	c.JSON(http.StatusOK, sellers)
 }
 
@@ -226,17 +271,139 @@ func (ac *AdminController) UpdateSellerStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "seller status updated"})
 }
 
+// UpdateSellerCancellationWindow godoc
+// @Summary Update seller cancellation window
+// @Description Set how many minutes after purchase buyers may cancel orders with the seller's items (0 disables self-cancellation), or null to use the order_cancellation_window setting (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Seller ID"
+// @Param request body models.UpdateCancellationWindowRequest true "Cancellation window"
+// @Success 200 {object} models.Seller
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/sellers/{id}/cancellation-window [put]
+func (ac *AdminController) UpdateSellerCancellationWindow(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("seller"))
+		return
+	}
+
+	var req models.UpdateCancellationWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	seller, err := ac.sellerRepo.SetCancellationWindow(c.Request.Context(), id, req.Minutes)
+	if handleError(c, err, apperrors.Internal("failed to update cancellation window")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, seller)
+}
+
+// UpdateSellerProbation godoc
+// @Summary Update seller probation
+// @Description Put a seller on probation until they have delivered the given number of orders, counting orders delivered before, or take them off it with orders 0 (admin only). Meanwhile product listings show the seller's products to traffic_percent of visitors only, in steps of 10; product pages, the seller's custom domain and the cart are not affected, and catalog feeds leave the seller out. Listings pick the change up on their next refresh.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Seller ID"
+// @Param request body models.SetSellerProbationRequest true "Probation"
+// @Success 200 {object} models.Seller
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/sellers/{id}/probation [put]
+func (ac *AdminController) UpdateSellerProbation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("seller"))
+		return
+	}
+
+	var req models.SetSellerProbationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	seller, err := ac.sellerRepo.SetProbation(c.Request.Context(), id, &req)
+	if handleError(c, err, apperrors.Internal("failed to update seller probation")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, seller)
+}
+
+// UpdateSellerTrusted godoc
+// @Summary Update seller trust
+// @Description Mark a seller as trusted or take the mark away (admin only). Sales of trusted sellers become payable after the trusted settlement delay when that is shorter than the one of the product's category risk level; open chargebacks and buyer support tickets still hold them.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Seller ID"
+// @Param request body models.SetSellerTrustedRequest true "Trust"
+// @Success 200 {object} models.Seller
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/sellers/{id}/trusted [put]
+func (ac *AdminController) UpdateSellerTrusted(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("seller"))
+		return
+	}
+
+	var req models.SetSellerTrustedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	seller, err := ac.sellerRepo.SetTrusted(c.Request.Context(), id, *req.Trusted)
+	if handleError(c, err, apperrors.Internal("failed to update seller trust")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, seller)
+}
+
 // GetAllOrders godoc
 // @Summary Get all orders
-// @Description Get list of all orders with pagination (admin only)
+// @Description Get list of all orders with pagination (admin only). Filters combine; text filters match partially, ignoring case. With the Auth service's gRPC API configured each order has the buyer's user_email.
 // @Tags admin
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(20)
+// @Param q query string false "Search by order number, buyer email or product title"
 // @Param status query string false "Filter by status"
+// @Param order_number query string false "Search by order number (partial match)"
+// @Param email query string false "Search by buyer email (partial match)"
+// @Param product query string false "Search by product title (partial match)"
+// @Param seller_id query int false "Orders with items from this seller"
+// @Param min_amount query number false "Minimum order total"
+// @Param max_amount query number false "Maximum order total"
+// @Param from query string false "Placed on or after this date (YYYY-MM-DD)"
+// @Param to query string false "Placed on or before this date (YYYY-MM-DD)"
+// @Param count query string false "exact (default) or estimate"
 // @Success 200 {object} models.PaginatedResponse
+// @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -250,24 +417,56 @@ func (ac *AdminController) GetAllOrders(c *gin.Context) {
 		pagination.Page = 1
 	}
 
-	status := c.Query("status")
+	var filter models.OrderFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+	if err := validateOrderFilterRanges(&filter); err != nil {
+		respondError(c, err)
+		return
+	}
 
-	orders, totalItems, err := ac.orderRepo.GetAll(c.Request.Context(), &pagination, status)
+	orders, totalItems, err := ac.orderRepo.GetAll(c.Request.Context(), &pagination, &filter)
 	if handleError(c, err, apperrors.Internal("failed to get orders")) {
 		return
 	}
 
+	userIDs := make([]int, len(orders))
+	for i, order := range orders {
+		userIDs[i] = order.UserID
+	}
+	emails := ac.users.Emails(c.Request.Context(), userIDs)
+	for _, order := range orders {
+		order.UserEmail = emails[order.UserID]
+	}
+
+	meta := models.NewPaginationMeta(pagination.Page, pagination.GetLimit(), totalItems)
+	meta.Estimated = pagination.EstimateCount()
+
 	response := models.PaginatedResponse{
 		Data:       orders,
-		Pagination: models.NewPaginationMeta(pagination.Page, pagination.GetLimit(), totalItems),
+		Pagination: meta,
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// validateOrderFilterRanges rejects amount and date ranges that end before
+// they start.
+func validateOrderFilterRanges(filter *models.OrderFilter) *apperrors.AppError {
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		return apperrors.ValidationError("max_amount", "must not be less than min_amount")
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return apperrors.ValidationError("to", "must not be before from")
+	}
+	return nil
+}
+
 // UpdateOrderStatus godoc
 // @Summary Update order status
-// @Description Update status of an order (admin only)
+// @Description Move an order along pending → confirmed/paid → shipped → delivered, cancel it before payment or refund it once paid (admin only). Other transitions are rejected with 409; every change is recorded in the order's status history.
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -278,9 +477,13 @@ func (ac *AdminController) GetAllOrders(c *gin.Context) {
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/admin/orders/{id}/status [put]
 func (ac *AdminController) UpdateOrderStatus(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		respondError(c, apperrors.InvalidID("order"))
@@ -293,10 +496,133 @@ func (ac *AdminController) UpdateOrderStatus(c *gin.Context) {
 		return
 	}
 
-	order, err := ac.orderRepo.UpdateStatus(c.Request.Context(), id, req.Status)
+	order, err := ac.orders.UpdateOrderStatus(c.Request.Context(), id, req.Status, userID.(int))
 	if handleError(c, err, apperrors.Internal("failed to update order status")) {
 		return
 	}
 
 	c.JSON(http.StatusOK, order)
 }
+
+// GetOrderHistory godoc
+// @Summary Get order status history
+// @Description Every status change of an order, oldest first, with who made it: an admin, the buyer cancelling or the courier delivering (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Success 200 {array} models.OrderStatusChange
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/orders/{id}/history [get]
+func (ac *AdminController) GetOrderHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("order"))
+		return
+	}
+
+	history, err := ac.orderRepo.GetStatusHistory(c.Request.Context(), id)
+	if handleError(c, err, apperrors.Internal("failed to get order status history")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// AssignCourier godoc
+// @Summary Assign order to courier
+// @Description Assign a confirmed or shipped order to a courier and mark it shipped (admin only). The user must have the courier role in the Auth service, which needs AUTH_GRPC_ADDR.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param request body models.AssignCourierRequest true "Courier user ID"
+// @Success 200 {object} models.Delivery
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/orders/{id}/courier [put]
+func (ac *AdminController) AssignCourier(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("order"))
+		return
+	}
+
+	var req models.AssignCourierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	// Only couriers can see and deliver the order
+	if ac.users == nil {
+		respondError(c, apperrors.ValidationError("courier_id", "couriers cannot be checked without the Auth service (AUTH_GRPC_ADDR)"))
+		return
+	}
+	role, err := ac.users.Role(c.Request.Context(), req.CourierID)
+	if errors.Is(err, users.ErrNotFound) || err == nil && role != "courier" {
+		respondError(c, apperrors.ValidationError("courier_id", "user is not a courier"))
+		return
+	}
+	if handleError(c, err, apperrors.Internal("failed to check courier")) {
+		return
+	}
+
+	delivery, err := ac.deliveryRepo.Assign(c.Request.Context(), id, req.CourierID, userID.(int))
+	if handleError(c, err, apperrors.Internal("failed to assign courier")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+// GetReadOnly godoc
+// @Summary Get read-only tables
+// @Description List tables whose writes are currently blocked (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.ReadOnlyTables
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/admin/maintenance/read-only [get]
+func (ac *AdminController) GetReadOnly(c *gin.Context) {
+	c.JSON(http.StatusOK, models.ReadOnlyTables{Tables: ac.readOnly.Tables()})
+}
+
+// SetReadOnly godoc
+// @Summary Set read-only tables
+// @Description Replace the set of tables whose writes are blocked; an empty list re-enables all writes (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ReadOnlyTables true "Tables to freeze"
+// @Success 200 {object} models.ReadOnlyTables
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/admin/maintenance/read-only [put]
+func (ac *AdminController) SetReadOnly(c *gin.Context) {
+	var req models.ReadOnlyTables
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	ac.readOnly.Set(req.Tables...)
+	logger.FromContext(c.Request.Context()).WithField("tables", ac.readOnly.Tables()).Warn("Read-only tables changed")
+
+	c.JSON(http.StatusOK, models.ReadOnlyTables{Tables: ac.readOnly.Tables()})
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/clients/authclient"
+	"github.com/Zifeldev/marketback/service/Market/internal/users"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// roleLookup answers user lookups with fixed roles; unknown IDs are missing
+type roleLookup struct {
+	roles map[int64]string
+	err   error
+}
+
+func (l *roleLookup) ListUsersByIDs(ctx context.Context, ids []int64) (map[int64]*authclient.User, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	found := map[int64]*authclient.User{}
+	for _, id := range ids {
+		if role, ok := l.roles[id]; ok {
+			found[id] = &authclient.User{Id: id, Role: role}
+		}
+	}
+	return found, nil
+}
+
+func TestAdminController_AssignCourier_NotCourier(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	lookup := &roleLookup{roles: map[int64]string{7: "user"}}
+
+	assign := func(directory *users.Directory, courierID string) *httptest.ResponseRecorder {
+		ctrl := NewAdminController(nil, nil, nil, nil, nil, nil, nil, directory)
+		router := gin.New()
+		router.PUT("/orders/:id/courier", func(c *gin.Context) {
+			c.Set("user_id", 1)
+			ctrl.AssignCourier(c)
+		})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/orders/5/courier", bytes.NewBufferString(`{"courier_id":`+courierID+`}`)))
+		return w
+	}
+
+	w := assign(users.NewDirectory(lookup), "7")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), "user is not a courier")
+
+	w = assign(users.NewDirectory(lookup), "8")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), "user is not a courier")
+
+	// Without Auth there is no telling who is a courier
+	w = assign(nil, "7")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), "AUTH_GRPC_ADDR")
+
+	lookup.err = errors.New("connection refused")
+	w = assign(users.NewDirectory(lookup), "7")
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+type AdminViewController struct {
+	viewRepo repository.AdminViewRepo
+}
+
+func NewAdminViewController(viewRepo repository.AdminViewRepo) *AdminViewController {
+	return &AdminViewController{viewRepo: viewRepo}
+}
+
+// GetAdminViews godoc
+// @Summary Get saved views
+// @Description Get the current admin's saved filter presets by name, optionally for one list only
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param list query string false "orders, products or users"
+// @Success 200 {array} models.AdminView
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/views [get]
+func (vc *AdminViewController) GetAdminViews(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	list := c.Query("list")
+	switch list {
+	case "", models.AdminViewOrders, models.AdminViewProducts, models.AdminViewUsers:
+	default:
+		respondError(c, apperrors.ValidationError("list", "must be orders, products or users"))
+		return
+	}
+
+	views, err := vc.viewRepo.GetByAdmin(c.Request.Context(), userID.(int), list)
+	if handleError(c, err, apperrors.Internal("failed to get views")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, views)
+}
+
+// SaveAdminView godoc
+// @Summary Save view
+// @Description Save a named filter preset for the orders, products or users list. query is the list's query string (e.g. status=paid&min_amount=100); order filters are checked like GET /api/admin/orders. Saving a name again replaces its query.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.SaveAdminViewRequest true "View"
+// @Success 201 {object} models.AdminView
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/views [post]
+func (vc *AdminViewController) SaveAdminView(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.SaveAdminViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+	if err := validateViewQuery(req.List, req.Query); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	view, err := vc.viewRepo.Save(c.Request.Context(), userID.(int), &req)
+	if handleError(c, err, apperrors.Internal("failed to save view")) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, view)
+}
+
+// DeleteAdminView godoc
+// @Summary Delete view
+// @Description Delete one of the current admin's saved views
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "View ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/views/{id} [delete]
+func (vc *AdminViewController) DeleteAdminView(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("view"))
+		return
+	}
+
+	err = vc.viewRepo.Delete(c.Request.Context(), id, userID.(int))
+	if handleError(c, err, apperrors.Internal("failed to delete view")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "view deleted"})
+}
+
+// validateViewQuery checks that query is a query string and, for the
+// orders list, that it binds to the filters GET /api/admin/orders takes.
+func validateViewQuery(list, query string) *apperrors.AppError {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return apperrors.ValidationError("query", "must be a URL query string")
+	}
+	if list != models.AdminViewOrders {
+		return nil
+	}
+
+	var filter models.OrderFilter
+	if err := binding.MapFormWithTag(&filter, values, "form"); err != nil {
+		return apperrors.ValidationError("query", err.Error())
+	}
+	if err := binding.Validator.ValidateStruct(&filter); err != nil {
+		return apperrors.ValidationError("query", err.Error())
+	}
+	return validateOrderFilterRanges(&filter)
+}
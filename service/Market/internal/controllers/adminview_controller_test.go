@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAdminViewRepo struct {
+	saveFn       func(ctx context.Context, adminID int, req *models.SaveAdminViewRequest) (*models.AdminView, error)
+	getByAdminFn func(ctx context.Context, adminID int, list string) ([]*models.AdminView, error)
+	deleteFn     func(ctx context.Context, id, adminID int) error
+}
+
+func (m *mockAdminViewRepo) Save(ctx context.Context, adminID int, req *models.SaveAdminViewRequest) (*models.AdminView, error) {
+	return m.saveFn(ctx, adminID, req)
+}
+
+func (m *mockAdminViewRepo) GetByAdmin(ctx context.Context, adminID int, list string) ([]*models.AdminView, error) {
+	return m.getByAdminFn(ctx, adminID, list)
+}
+
+func (m *mockAdminViewRepo) Delete(ctx context.Context, id, adminID int) error {
+	return m.deleteFn(ctx, id, adminID)
+}
+
+var _ repository.AdminViewRepo = (*mockAdminViewRepo)(nil)
+
+func TestAdminViewController_SaveAdminView(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"orders", `{"list":"orders","name":"Big paid","query":"status=paid&min_amount=100&from=2026-01-01"}`, http.StatusCreated},
+		{"products", `{"list":"products","name":"Drafts","query":"status=draft"}`, http.StatusCreated},
+		{"empty query", `{"list":"users","name":"All"}`, http.StatusCreated},
+		{"unknown list", `{"list":"sellers","name":"All"}`, http.StatusBadRequest},
+		{"bad query", `{"list":"products","name":"Broken","query":"status=%zz"}`, http.StatusBadRequest},
+		{"bad order date", `{"list":"orders","name":"Broken","query":"from=yesterday"}`, http.StatusBadRequest},
+		{"order q too long", `{"list":"orders","name":"Broken","query":"q=` + string(bytes.Repeat([]byte("a"), 101)) + `"}`, http.StatusBadRequest},
+		{"inverted amounts", `{"list":"orders","name":"Broken","query":"min_amount=200&max_amount=100"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("POST", "/api/admin/views", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("user_id", 1)
+
+			m := &mockAdminViewRepo{
+				saveFn: func(ctx context.Context, adminID int, req *models.SaveAdminViewRequest) (*models.AdminView, error) {
+					require.Equal(t, 1, adminID)
+					return &models.AdminView{ID: 3, AdminID: adminID, List: req.List, Name: req.Name, Query: req.Query}, nil
+				},
+			}
+
+			NewAdminViewController(m).SaveAdminView(c)
+
+			require.Equal(t, tt.status, r.Code, r.Body.String())
+		})
+	}
+}
+
+func TestAdminViewController_GetAdminViews(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/admin/views?list=orders", nil)
+	c.Set("user_id", 1)
+
+	m := &mockAdminViewRepo{
+		getByAdminFn: func(ctx context.Context, adminID int, list string) ([]*models.AdminView, error) {
+			require.Equal(t, 1, adminID)
+			require.Equal(t, models.AdminViewOrders, list)
+			return []*models.AdminView{{ID: 3, List: list, Name: "Big paid"}}, nil
+		},
+	}
+
+	NewAdminViewController(m).GetAdminViews(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	require.Contains(t, r.Body.String(), `"name":"Big paid"`)
+}
+
+func TestAdminViewController_GetAdminViews_UnknownList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/admin/views?list=sellers", nil)
+	c.Set("user_id", 1)
+
+	NewAdminViewController(&mockAdminViewRepo{}).GetAdminViews(c)
+
+	require.Equal(t, http.StatusBadRequest, r.Code)
+}
+
+func TestAdminViewController_DeleteAdminView_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("DELETE", "/api/admin/views/9", nil)
+	c.Params = gin.Params{{Key: "id", Value: "9"}}
+	c.Set("user_id", 1)
+
+	m := &mockAdminViewRepo{
+		deleteFn: func(ctx context.Context, id, adminID int) error {
+			require.Equal(t, 9, id)
+			require.Equal(t, 1, adminID)
+			return apperrors.NotFound("view with id 9 not found")
+		},
+	}
+
+	NewAdminViewController(m).DeleteAdminView(c)
+
+	require.Equal(t, http.StatusNotFound, r.Code)
+}
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apiusage"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+const defaultAPIUsageDays = 30
+
+// usageTracker reads plan limits and live counters.
+type usageTracker interface {
+	Plan(ctx context.Context, userID int) (string, int, error)
+	Usage(ctx context.Context, userID int, day time.Time) (*models.APIUsage, error)
+	Window() time.Duration
+}
+
+type APIUsageController struct {
+	usageRepo repository.APIUsageRepo
+	tracker   usageTracker
+	plans     apiusage.Plans
+	now       func() time.Time
+}
+
+func NewAPIUsageController(usageRepo repository.APIUsageRepo, tracker usageTracker, plans apiusage.Plans) *APIUsageController {
+	return &APIUsageController{
+		usageRepo: usageRepo,
+		tracker:   tracker,
+		plans:     plans,
+		now:       time.Now,
+	}
+}
+
+// GetAPIUsage godoc
+// @Summary Get seller API usage
+// @Description The seller's API plan and limit with requests, errors and throttled requests per day (UTC), newest first. Today's figures are live; earlier days are rolled up periodically.
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Days to return, today included (default 30, max 90)"
+// @Success 200 {object} models.APIUsageReport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/api-usage [get]
+func (ac *APIUsageController) GetAPIUsage(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	uid := userID.(int)
+
+	var params models.APIUsageParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+	if params.Days == 0 {
+		params.Days = defaultAPIUsageDays
+	}
+
+	ctx := c.Request.Context()
+	plan, limit, err := ac.tracker.Plan(ctx, uid)
+	if handleError(c, err, apperrors.Internal("failed to get api plan")) {
+		return
+	}
+
+	now := ac.now().UTC()
+	since := now.AddDate(0, 0, -(params.Days - 1)).Format(apiusage.DayLayout)
+	days, err := ac.usageRepo.GetAPIUsage(ctx, uid, since)
+	if handleError(c, err, apperrors.Internal("failed to get api usage")) {
+		return
+	}
+
+	// The rollup lags behind, so today comes from the live counters.
+	today, err := ac.tracker.Usage(ctx, uid, now)
+	if err != nil {
+		logger.FromContext(ctx).WithField("err", err).Warn("failed to read live api usage")
+	} else if len(days) > 0 && days[0].Day == today.Day {
+		days[0] = today
+	} else if today.Requests > 0 {
+		days = append([]*models.APIUsage{today}, days...)
+	}
+
+	c.JSON(http.StatusOK, models.APIUsageReport{
+		Plan:          plan,
+		Limit:         limit,
+		WindowSeconds: ac.tracker.Window().Seconds(),
+		Days:          days,
+	})
+}
+
+// UpdateSellerAPIPlan godoc
+// @Summary Change a seller's API plan
+// @Description Move a seller to another API plan. The new limit applies within a minute.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Seller ID"
+// @Param request body models.UpdateAPIPlanRequest true "API plan"
+// @Success 200 {object} models.Seller
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/sellers/{id}/api-plan [put]
+func (ac *APIUsageController) UpdateSellerAPIPlan(c *gin.Context) {
+	sellerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("seller"))
+		return
+	}
+
+	var req models.UpdateAPIPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+	if _, ok := ac.plans[req.Plan]; !ok {
+		names := make([]string, 0, len(ac.plans))
+		for name := range ac.plans {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		respondError(c, apperrors.ValidationError("plan", "must be one of "+strings.Join(names, ", ")))
+		return
+	}
+
+	seller, err := ac.usageRepo.SetAPIPlan(c.Request.Context(), sellerID, req.Plan)
+	if handleError(c, err, apperrors.Internal("failed to update api plan")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, seller)
+}
@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apiusage"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAPIUsageRepo struct {
+	getUsageFn func(ctx context.Context, userID int, since string) ([]*models.APIUsage, error)
+	setPlanFn  func(ctx context.Context, sellerID int, plan string) (*models.Seller, error)
+}
+
+func (m *mockAPIUsageRepo) GetAPIUsage(ctx context.Context, userID int, since string) ([]*models.APIUsage, error) {
+	return m.getUsageFn(ctx, userID, since)
+}
+
+func (m *mockAPIUsageRepo) SetAPIPlan(ctx context.Context, sellerID int, plan string) (*models.Seller, error) {
+	return m.setPlanFn(ctx, sellerID, plan)
+}
+
+var _ repository.APIUsageRepo = (*mockAPIUsageRepo)(nil)
+
+type mockUsageTracker struct {
+	plan  string
+	limit int
+	today *models.APIUsage
+	err   error
+}
+
+func (m *mockUsageTracker) Plan(ctx context.Context, userID int) (string, int, error) {
+	return m.plan, m.limit, nil
+}
+
+func (m *mockUsageTracker) Usage(ctx context.Context, userID int, day time.Time) (*models.APIUsage, error) {
+	return m.today, m.err
+}
+
+func (m *mockUsageTracker) Window() time.Duration {
+	return time.Minute
+}
+
+func newAPIUsageTestController(repo *mockAPIUsageRepo, tracker *mockUsageTracker) *APIUsageController {
+	ac := NewAPIUsageController(repo, tracker, apiusage.Plans{"basic": 300, "pro": 1200})
+	ac.now = func() time.Time { return time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC) }
+	return ac
+}
+
+func TestAPIUsageController_GetAPIUsage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/seller/api-usage?days=7", nil)
+	c.Set("user_id", 3)
+
+	repo := &mockAPIUsageRepo{
+		getUsageFn: func(ctx context.Context, userID int, since string) ([]*models.APIUsage, error) {
+			require.Equal(t, 3, userID)
+			require.Equal(t, "2026-03-04", since)
+			return []*models.APIUsage{
+				{Day: "2026-03-10", Requests: 5},
+				{Day: "2026-03-09", Requests: 40, Errors: 2},
+			}, nil
+		},
+	}
+	tracker := &mockUsageTracker{plan: "pro", limit: 1200, today: &models.APIUsage{Day: "2026-03-10", Requests: 9, Throttled: 1}}
+	newAPIUsageTestController(repo, tracker).GetAPIUsage(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	var report models.APIUsageReport
+	require.NoError(t, json.Unmarshal(r.Body.Bytes(), &report))
+	require.Equal(t, "pro", report.Plan)
+	require.Equal(t, 1200, report.Limit)
+	require.Equal(t, float64(60), report.WindowSeconds)
+	require.Len(t, report.Days, 2)
+	require.EqualValues(t, 9, report.Days[0].Requests)
+	require.EqualValues(t, 1, report.Days[0].Throttled)
+}
+
+func TestAPIUsageController_GetAPIUsage_TodayNotRolledUp(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/seller/api-usage", nil)
+	c.Set("user_id", 3)
+
+	repo := &mockAPIUsageRepo{
+		getUsageFn: func(ctx context.Context, userID int, since string) ([]*models.APIUsage, error) {
+			require.Equal(t, "2026-02-09", since)
+			return []*models.APIUsage{{Day: "2026-03-09", Requests: 40}}, nil
+		},
+	}
+	tracker := &mockUsageTracker{plan: "basic", limit: 300, today: &models.APIUsage{Day: "2026-03-10", Requests: 2}}
+	newAPIUsageTestController(repo, tracker).GetAPIUsage(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	var report models.APIUsageReport
+	require.NoError(t, json.Unmarshal(r.Body.Bytes(), &report))
+	require.Len(t, report.Days, 2)
+	require.Equal(t, "2026-03-10", report.Days[0].Day)
+}
+
+func TestAPIUsageController_GetAPIUsage_LiveCountersUnavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/seller/api-usage", nil)
+	c.Set("user_id", 3)
+
+	repo := &mockAPIUsageRepo{
+		getUsageFn: func(ctx context.Context, userID int, since string) ([]*models.APIUsage, error) {
+			return []*models.APIUsage{{Day: "2026-03-09", Requests: 40}}, nil
+		},
+	}
+	tracker := &mockUsageTracker{plan: "basic", limit: 300, err: errors.New("redis down")}
+	newAPIUsageTestController(repo, tracker).GetAPIUsage(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	var report models.APIUsageReport
+	require.NoError(t, json.Unmarshal(r.Body.Bytes(), &report))
+	require.Len(t, report.Days, 1)
+}
+
+func TestAPIUsageController_GetAPIUsage_InvalidDays(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/seller/api-usage?days=365", nil)
+	c.Set("user_id", 3)
+
+	newAPIUsageTestController(&mockAPIUsageRepo{}, &mockUsageTracker{}).GetAPIUsage(c)
+
+	require.Equal(t, http.StatusBadRequest, r.Code)
+}
+
+func TestAPIUsageController_UpdateSellerAPIPlan(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("PUT", "/api/admin/sellers/4/api-plan", strings.NewReader(`{"plan":"pro"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "4"}}
+
+	repo := &mockAPIUsageRepo{
+		setPlanFn: func(ctx context.Context, sellerID int, plan string) (*models.Seller, error) {
+			require.Equal(t, 4, sellerID)
+			return &models.Seller{ID: sellerID, APIPlan: plan}, nil
+		},
+	}
+	newAPIUsageTestController(repo, &mockUsageTracker{}).UpdateSellerAPIPlan(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	require.Contains(t, r.Body.String(), `"api_plan":"pro"`)
+}
+
+func TestAPIUsageController_UpdateSellerAPIPlan_UnknownPlan(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("PUT", "/api/admin/sellers/4/api-plan", strings.NewReader(`{"plan":"gold"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "4"}}
+
+	newAPIUsageTestController(&mockAPIUsageRepo{}, &mockUsageTracker{}).UpdateSellerAPIPlan(c)
+
+	require.Equal(t, http.StatusBadRequest, r.Code)
+	require.Contains(t, r.Body.String(), "basic, pro")
+}
@@ -72,6 +72,35 @@ func TestMarketController_GetCart_Success(t *testing.T) {
 	require.Contains(t, r.Body.String(), "Sneakers")
 }
 
+func TestMarketController_GetCartPackage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+
+	c.Request = httptest.NewRequest("GET", "/api/cart/package", nil)
+	c.Set("user_id", 42)
+
+	weight := 450
+	items := []*models.CartItemWithDetails{{
+		CartItem:     models.CartItem{ID: 1, UserID: 42, ProductID: 7, Quantity: 3},
+		ProductTitle: "Sneakers",
+		Dimensions:   models.Dimensions{WeightGrams: &weight},
+	}}
+
+	mrepo := &mockCartRepoFull{getFn: func(ctx context.Context, userID int) ([]*models.CartItemWithDetails, error) {
+		return items, nil
+	}, addFn: noopAdd, updateFn: noopUpdate, deleteFn: noopDelete, clearFn: noopClear}
+
+	mc := NewMarketController(nil, nil, mrepo, nil, nil)
+	mc.GetCartPackage(c)
+
+	require.Equal(t, 200, r.Code)
+	var pkg models.ShippingPackage
+	require.NoError(t, json.Unmarshal(r.Body.Bytes(), &pkg))
+	require.Equal(t, 1350, pkg.WeightGrams)
+	require.True(t, pkg.Complete)
+}
+
 func TestMarketController_UpdateCartItem_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := httptest.NewRecorder()
@@ -0,0 +1,237 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type CatalogRevisionController struct {
+	sellers   importSellers
+	revisions repository.CatalogRevisionRepo
+}
+
+func NewCatalogRevisionController(sellers importSellers, revisions repository.CatalogRevisionRepo) *CatalogRevisionController {
+	return &CatalogRevisionController{sellers: sellers, revisions: revisions}
+}
+
+// currentSeller resolves the seller profile of the user making the request.
+func (rc *CatalogRevisionController) currentSeller(c *gin.Context) (*models.Seller, bool) {
+	userID, _ := c.Get("user_id")
+	seller, err := rc.sellers.GetByUserID(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.Forbidden("seller profile not found")) {
+		return nil, false
+	}
+	return seller, true
+}
+
+// GetCatalogRevisions godoc
+// @Summary List catalog revisions
+// @Description The seller's catalog revisions, newest first, without their products. A revision is taken before every spreadsheet import, before every restore and on request; only the newest CATALOG_REVISIONS_KEPT are kept.
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.CatalogRevision
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/catalog/revisions [get]
+func (rc *CatalogRevisionController) GetCatalogRevisions(c *gin.Context) {
+	seller, ok := rc.currentSeller(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := rc.revisions.GetBySeller(c.Request.Context(), seller.ID)
+	if handleError(c, err, apperrors.Internal("failed to get catalog revisions")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// CreateCatalogRevision godoc
+// @Summary Snapshot catalog
+// @Description Record the seller's catalog as it is now, every product with its variants and prices, as a new revision to roll back to.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateCatalogRevisionRequest false "Note to remember the revision by"
+// @Success 201 {object} models.CatalogRevision
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/catalog/revisions [post]
+func (rc *CatalogRevisionController) CreateCatalogRevision(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.CreateCatalogRevisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	seller, ok := rc.currentSeller(c)
+	if !ok {
+		return
+	}
+
+	rev, err := rc.revisions.Create(c.Request.Context(), seller.ID, models.CatalogRevisionManual, req.Note, userID.(int))
+	if handleError(c, err, apperrors.Internal("failed to create catalog revision")) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, rev)
+}
+
+// GetCatalogRevision godoc
+// @Summary Get catalog revision
+// @Description One of the seller's catalog revisions with its products and their variants as they were.
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Revision ID"
+// @Success 200 {object} models.CatalogRevision
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/catalog/revisions/{id} [get]
+func (rc *CatalogRevisionController) GetCatalogRevision(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("catalog revision"))
+		return
+	}
+
+	seller, ok := rc.currentSeller(c)
+	if !ok {
+		return
+	}
+
+	rev, err := rc.revisions.GetByID(c.Request.Context(), id, seller.ID)
+	if handleError(c, err, apperrors.Internal("failed to get catalog revision")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, rev)
+}
+
+// RestoreCatalogRevision godoc
+// @Summary Restore catalog revision
+// @Description Roll the seller's catalog back to a revision, e.g. after a bad import. The catalog is first recorded in a restore revision, so the rollback can be undone. Products get back their texts, category, prices, images, status and variants; products deleted since are recreated and products added since are deleted. Blocked products stay blocked. Stock is only rolled back with stock set, since orders placed since took from it.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Revision ID"
+// @Param request body models.RestoreCatalogRevisionRequest false "Whether to roll back stock too"
+// @Success 200 {object} models.CatalogRestore
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/seller/catalog/revisions/{id}/restore [post]
+func (rc *CatalogRevisionController) RestoreCatalogRevision(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("catalog revision"))
+		return
+	}
+
+	seller, ok := rc.currentSeller(c)
+	if !ok {
+		return
+	}
+
+	rc.restore(c, id, seller.ID)
+}
+
+// GetSellerCatalogRevisions godoc
+// @Summary List a seller's catalog revisions
+// @Description A seller's catalog revisions, newest first, without their products (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Seller ID"
+// @Success 200 {array} models.CatalogRevision
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/sellers/{id}/catalog/revisions [get]
+func (rc *CatalogRevisionController) GetSellerCatalogRevisions(c *gin.Context) {
+	sellerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("seller"))
+		return
+	}
+
+	revisions, err := rc.revisions.GetBySeller(c.Request.Context(), sellerID)
+	if handleError(c, err, apperrors.Internal("failed to get catalog revisions")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// RestoreSellerCatalogRevision godoc
+// @Summary Restore a seller's catalog revision
+// @Description Roll a seller's catalog back to one of their revisions, as the seller can (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Seller ID"
+// @Param revision_id path int true "Revision ID"
+// @Param request body models.RestoreCatalogRevisionRequest false "Whether to roll back stock too"
+// @Success 200 {object} models.CatalogRestore
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/admin/sellers/{id}/catalog/revisions/{revision_id}/restore [post]
+func (rc *CatalogRevisionController) RestoreSellerCatalogRevision(c *gin.Context) {
+	sellerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("seller"))
+		return
+	}
+	id, err := strconv.Atoi(c.Param("revision_id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("catalog revision"))
+		return
+	}
+
+	rc.restore(c, id, sellerID)
+}
+
+func (rc *CatalogRevisionController) restore(c *gin.Context, id, sellerID int) {
+	userID, _ := c.Get("user_id")
+
+	var req models.RestoreCatalogRevisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	restored, err := rc.revisions.Restore(c.Request.Context(), id, sellerID, req.Stock, userID.(int))
+	if handleError(c, err, apperrors.Internal("failed to restore catalog revision")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, restored)
+}
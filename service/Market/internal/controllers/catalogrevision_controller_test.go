@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockCatalogRevisionRepo struct {
+	revisions []*models.CatalogRevision
+	createFn  func(ctx context.Context, sellerID int, reason, note string, createdBy int) (*models.CatalogRevision, error)
+	restoreFn func(ctx context.Context, id, sellerID int, restoreStock bool, restoredBy int) (*models.CatalogRestore, error)
+}
+
+func (m *mockCatalogRevisionRepo) Create(ctx context.Context, sellerID int, reason, note string, createdBy int) (*models.CatalogRevision, error) {
+	return m.createFn(ctx, sellerID, reason, note, createdBy)
+}
+
+func (m *mockCatalogRevisionRepo) GetBySeller(ctx context.Context, sellerID int) ([]*models.CatalogRevision, error) {
+	var revisions []*models.CatalogRevision
+	for _, rev := range m.revisions {
+		if rev.SellerID == sellerID {
+			revisions = append(revisions, rev)
+		}
+	}
+	return revisions, nil
+}
+
+func (m *mockCatalogRevisionRepo) GetByID(ctx context.Context, id, sellerID int) (*models.CatalogRevision, error) {
+	for _, rev := range m.revisions {
+		if rev.ID == id && rev.SellerID == sellerID {
+			return rev, nil
+		}
+	}
+	return nil, apperrors.NotFound(fmt.Sprintf("catalog revision with id %d not found", id))
+}
+
+func (m *mockCatalogRevisionRepo) Restore(ctx context.Context, id, sellerID int, restoreStock bool, restoredBy int) (*models.CatalogRestore, error) {
+	return m.restoreFn(ctx, id, sellerID, restoreStock, restoredBy)
+}
+
+var _ repository.CatalogRevisionRepo = (*mockCatalogRevisionRepo)(nil)
+
+func TestCatalogRevisionController_CreateCatalogRevision(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		userID   int
+		body     string
+		wantCode int
+		wantNote string
+	}{
+		{name: "with note", userID: 5, body: `{"note":"before summer prices"}`, wantCode: http.StatusCreated, wantNote: "before summer prices"},
+		{name: "without body", userID: 5, wantCode: http.StatusCreated},
+		{name: "malformed body", userID: 5, body: `{"note":`, wantCode: http.StatusBadRequest},
+		{name: "no seller profile", userID: 6, wantCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("POST", "/api/seller/catalog/revisions", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("user_id", tt.userID)
+
+			NewCatalogRevisionController(mockImportSellers{}, &mockCatalogRevisionRepo{
+				createFn: func(ctx context.Context, sellerID int, reason, note string, createdBy int) (*models.CatalogRevision, error) {
+					require.Equal(t, 11, sellerID)
+					require.Equal(t, models.CatalogRevisionManual, reason)
+					require.Equal(t, tt.userID, createdBy)
+					return &models.CatalogRevision{ID: 1, SellerID: sellerID, Version: 1, Reason: reason, Note: note, CreatedBy: createdBy}, nil
+				},
+			}).CreateCatalogRevision(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+			if tt.wantCode != http.StatusCreated {
+				return
+			}
+			var got models.CatalogRevision
+			require.NoError(t, json.Unmarshal(r.Body.Bytes(), &got))
+			require.Equal(t, tt.wantNote, got.Note)
+		})
+	}
+}
+
+func TestCatalogRevisionController_GetCatalogRevision(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &mockCatalogRevisionRepo{revisions: []*models.CatalogRevision{
+		{ID: 1, SellerID: 11, Version: 1, Products: []models.CatalogRevisionProduct{{ID: 3, Title: "Scarf", Price: 12}}},
+		{ID: 2, SellerID: 12, Version: 1},
+	}}
+
+	tests := []struct {
+		name     string
+		param    string
+		wantCode int
+	}{
+		{name: "own revision", param: "1", wantCode: http.StatusOK},
+		{name: "other seller's revision", param: "2", wantCode: http.StatusNotFound},
+		{name: "invalid id", param: "abc", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("GET", "/api/seller/catalog/revisions/"+tt.param, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.param}}
+			c.Set("user_id", 5)
+
+			NewCatalogRevisionController(mockImportSellers{}, repo).GetCatalogRevision(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var got models.CatalogRevision
+			require.NoError(t, json.Unmarshal(r.Body.Bytes(), &got))
+			require.Len(t, got.Products, 1)
+			require.Equal(t, "Scarf", got.Products[0].Title)
+		})
+	}
+}
+
+func TestCatalogRevisionController_RestoreCatalogRevision(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		admin     bool
+		params    gin.Params
+		body      string
+		wantCode  int
+		wantStock bool
+	}{
+		{name: "seller", params: gin.Params{{Key: "id", Value: "1"}}, wantCode: http.StatusOK},
+		{name: "seller with stock", params: gin.Params{{Key: "id", Value: "1"}}, body: `{"stock":true}`, wantCode: http.StatusOK, wantStock: true},
+		{name: "seller, missing revision", params: gin.Params{{Key: "id", Value: "2"}}, wantCode: http.StatusNotFound},
+		{name: "seller, malformed body", params: gin.Params{{Key: "id", Value: "1"}}, body: `{"stock":"yes"}`, wantCode: http.StatusBadRequest},
+		{name: "admin", admin: true, params: gin.Params{{Key: "id", Value: "11"}, {Key: "revision_id", Value: "1"}}, wantCode: http.StatusOK},
+		{name: "admin, other seller", admin: true, params: gin.Params{{Key: "id", Value: "12"}, {Key: "revision_id", Value: "1"}}, wantCode: http.StatusNotFound},
+		{name: "admin, invalid seller", admin: true, params: gin.Params{{Key: "id", Value: "abc"}, {Key: "revision_id", Value: "1"}}, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("POST", "/api/seller/catalog/revisions/1/restore", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = tt.params
+			c.Set("user_id", 5)
+
+			var gotStock bool
+			ctrl := NewCatalogRevisionController(mockImportSellers{}, &mockCatalogRevisionRepo{
+				restoreFn: func(ctx context.Context, id, sellerID int, restoreStock bool, restoredBy int) (*models.CatalogRestore, error) {
+					if id != 1 || sellerID != 11 {
+						return nil, apperrors.NotFound(fmt.Sprintf("catalog revision with id %d not found", id))
+					}
+					require.Equal(t, 5, restoredBy)
+					gotStock = restoreStock
+					return &models.CatalogRestore{
+						Revision: &models.CatalogRevision{ID: id, SellerID: sellerID, Version: 1},
+						Backup:   &models.CatalogRevision{ID: 3, SellerID: sellerID, Version: 3, Reason: models.CatalogRevisionRestore},
+						Restored: 4,
+					}, nil
+				},
+			})
+			if tt.admin {
+				ctrl.RestoreSellerCatalogRevision(c)
+			} else {
+				ctrl.RestoreCatalogRevision(c)
+			}
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var got models.CatalogRestore
+			require.NoError(t, json.Unmarshal(r.Body.Bytes(), &got))
+			require.Equal(t, 4, got.Restored)
+			require.Equal(t, tt.wantStock, gotStock)
+			require.Equal(t, models.CatalogRevisionRestore, got.Backup.Reason)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type CommissionController struct {
+	commissionRepo repository.CommissionRepo
+}
+
+func NewCommissionController(commissionRepo repository.CommissionRepo) *CommissionController {
+	return &CommissionController{commissionRepo: commissionRepo}
+}
+
+func optionalIntQuery(c *gin.Context, name string) (*int, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, true
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < 1 {
+		respondError(c, apperrors.ValidationError(name, "must be a positive integer"))
+		return nil, false
+	}
+	return &v, true
+}
+
+// GetCommissionRates godoc
+// @Summary Get commission rates
+// @Description Get paginated commission rates, newest first, optionally filtered by seller or category
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param seller_id query int false "Seller ID"
+// @Param category_id query int false "Category ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/commission-rates [get]
+func (cc *CommissionController) GetCommissionRates(c *gin.Context) {
+	sellerID, ok := optionalIntQuery(c, "seller_id")
+	if !ok {
+		return
+	}
+	categoryID, ok := optionalIntQuery(c, "category_id")
+	if !ok {
+		return
+	}
+
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		pagination = models.PaginationParams{Page: 1, PageSize: models.DefaultPageSize}
+	}
+	if pagination.Page < 1 {
+		pagination.Page = 1
+	}
+
+	rates, totalItems, err := cc.commissionRepo.GetAll(c.Request.Context(), sellerID, categoryID, &pagination)
+	if handleError(c, err, apperrors.Internal("failed to get commission rates")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Data:       rates,
+		Pagination: models.NewPaginationMeta(pagination.Page, pagination.GetLimit(), totalItems),
+	})
+}
+
+// CreateCommissionRate godoc
+// @Summary Create commission rate
+// @Description Schedule a commission rate (0..1) for the marketplace, a category, a seller or a seller within a category. It supersedes the previous rate of the same scope from effective_from (default now).
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateCommissionRateRequest true "Rate"
+// @Success 201 {object} models.CommissionRate
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/commission-rates [post]
+func (cc *CommissionController) CreateCommissionRate(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.CreateCommissionRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	rate, err := cc.commissionRepo.Create(c.Request.Context(), userID.(int), &req)
+	if handleError(c, err, apperrors.Internal("failed to create commission rate")) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, rate)
+}
+
+// DeleteCommissionRate godoc
+// @Summary Delete scheduled commission rate
+// @Description Delete a commission rate that is not yet in effect
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Commission rate ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/commission-rates/{id} [delete]
+func (cc *CommissionController) DeleteCommissionRate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("commission rate"))
+		return
+	}
+
+	err = cc.commissionRepo.Delete(c.Request.Context(), id)
+	if handleError(c, err, apperrors.Internal("failed to delete commission rate")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "commission rate deleted"})
+}
+
+// GetSellerCommissionRates godoc
+// @Summary Get my commission rates
+// @Description Get the commission rates that can apply to the current seller: the marketplace default, category rates and seller overrides, including scheduled ones
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.CommissionRate
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/commission-rates [get]
+func (cc *CommissionController) GetSellerCommissionRates(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	rates, err := cc.commissionRepo.ForSellerUser(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.Internal("failed to get commission rates")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, rates)
+}
@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockCommissionRepo struct {
+	createFn    func(ctx context.Context, adminID int, req *models.CreateCommissionRateRequest) (*models.CommissionRate, error)
+	getAllFn    func(ctx context.Context, sellerID, categoryID *int, pagination *models.PaginationParams) ([]*models.CommissionRate, int64, error)
+	forSellerFn func(ctx context.Context, userID int) ([]*models.CommissionRate, error)
+	deleteFn    func(ctx context.Context, id int) error
+}
+
+func (m *mockCommissionRepo) Create(ctx context.Context, adminID int, req *models.CreateCommissionRateRequest) (*models.CommissionRate, error) {
+	return m.createFn(ctx, adminID, req)
+}
+
+func (m *mockCommissionRepo) GetAll(ctx context.Context, sellerID, categoryID *int, pagination *models.PaginationParams) ([]*models.CommissionRate, int64, error) {
+	return m.getAllFn(ctx, sellerID, categoryID, pagination)
+}
+
+func (m *mockCommissionRepo) ForSellerUser(ctx context.Context, userID int) ([]*models.CommissionRate, error) {
+	return m.forSellerFn(ctx, userID)
+}
+
+func (m *mockCommissionRepo) Delete(ctx context.Context, id int) error {
+	return m.deleteFn(ctx, id)
+}
+
+var _ repository.CommissionRepo = (*mockCommissionRepo)(nil)
+
+func TestCommissionController_CreateCommissionRate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("POST", "/api/admin/commission-rates", bytes.NewBufferString(`{"category_id":3,"rate":0.12}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", 1)
+
+	m := &mockCommissionRepo{
+		createFn: func(ctx context.Context, adminID int, req *models.CreateCommissionRateRequest) (*models.CommissionRate, error) {
+			require.Equal(t, 1, adminID)
+			require.Equal(t, 3, *req.CategoryID)
+			require.Nil(t, req.SellerID)
+			require.Equal(t, 0.12, *req.Rate)
+			return &models.CommissionRate{ID: 5, CategoryID: req.CategoryID, Rate: *req.Rate, CreatedBy: adminID}, nil
+		},
+	}
+
+	NewCommissionController(m).CreateCommissionRate(c)
+
+	require.Equal(t, http.StatusCreated, r.Code)
+}
+
+func TestCommissionController_CreateCommissionRate_ZeroRateAllowed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("POST", "/api/admin/commission-rates", bytes.NewBufferString(`{"seller_id":2,"rate":0}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", 1)
+
+	m := &mockCommissionRepo{
+		createFn: func(ctx context.Context, adminID int, req *models.CreateCommissionRateRequest) (*models.CommissionRate, error) {
+			return &models.CommissionRate{ID: 6, SellerID: req.SellerID}, nil
+		},
+	}
+
+	NewCommissionController(m).CreateCommissionRate(c)
+
+	require.Equal(t, http.StatusCreated, r.Code)
+}
+
+func TestCommissionController_CreateCommissionRate_InvalidRate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for _, body := range []string{`{"rate":1.5}`, `{"rate":-0.1}`, `{}`} {
+		r := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(r)
+		c.Request = httptest.NewRequest("POST", "/api/admin/commission-rates", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("user_id", 1)
+
+		NewCommissionController(&mockCommissionRepo{}).CreateCommissionRate(c)
+
+		require.Equal(t, http.StatusBadRequest, r.Code, body)
+	}
+}
+
+func TestCommissionController_GetCommissionRates_Filters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/admin/commission-rates?seller_id=4", nil)
+
+	m := &mockCommissionRepo{
+		getAllFn: func(ctx context.Context, sellerID, categoryID *int, pagination *models.PaginationParams) ([]*models.CommissionRate, int64, error) {
+			require.Equal(t, 4, *sellerID)
+			require.Nil(t, categoryID)
+			return []*models.CommissionRate{}, 0, nil
+		},
+	}
+
+	NewCommissionController(m).GetCommissionRates(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+}
+
+func TestCommissionController_DeleteCommissionRate_InEffect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("DELETE", "/api/admin/commission-rates/5", nil)
+	c.Params = gin.Params{{Key: "id", Value: "5"}}
+
+	m := &mockCommissionRepo{
+		deleteFn: func(ctx context.Context, id int) error {
+			return apperrors.Conflict("commission rate is already in effect; create a new rate instead")
+		},
+	}
+
+	NewCommissionController(m).DeleteCommissionRate(c)
+
+	require.Equal(t, http.StatusConflict, r.Code)
+}
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// configReloader is satisfied by reload.Reloader.
+type configReloader interface {
+	Reload(ctx context.Context) ([]string, error)
+}
+
+type ConfigController struct {
+	reloader configReloader
+}
+
+func NewConfigController(reloader configReloader) *ConfigController {
+	return &ConfigController{reloader: reloader}
+}
+
+// ReloadConfig godoc
+// @Summary Reload configuration
+// @Description Re-read the environment and CONFIG_FILE and apply log level, rate limit and read-only table changes, same as SIGHUP (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string][]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/config/reload [post]
+func (cc *ConfigController) ReloadConfig(c *gin.Context) {
+	changed, err := cc.reloader.Reload(c.Request.Context())
+	if err != nil {
+		logger.FromContext(c.Request.Context()).WithField("err", err).Error("config reload failed")
+		respondError(c, apperrors.Internal(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"changed": changed})
+}
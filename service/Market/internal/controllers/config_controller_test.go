@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type reloaderFunc func(ctx context.Context) ([]string, error)
+
+func (f reloaderFunc) Reload(ctx context.Context) ([]string, error) {
+	return f(ctx)
+}
+
+func TestConfigController_ReloadConfig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("POST", "/api/admin/config/reload", nil)
+
+	NewConfigController(reloaderFunc(func(ctx context.Context) ([]string, error) {
+		return []string{"LOG_LEVEL"}, nil
+	})).ReloadConfig(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	require.JSONEq(t, `{"changed":["LOG_LEVEL"]}`, r.Body.String())
+}
+
+func TestConfigController_ReloadConfig_Invalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("POST", "/api/admin/config/reload", nil)
+
+	NewConfigController(reloaderFunc(func(ctx context.Context) ([]string, error) {
+		return nil, errors.New("invalid RATE_LIMIT_MAX")
+	})).ReloadConfig(c)
+
+	require.Equal(t, http.StatusInternalServerError, r.Code)
+}
@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+type CourierController struct {
+	deliveryRepo repository.DeliveryRepo
+	store        storage.Storage
+}
+
+func NewCourierController(deliveryRepo repository.DeliveryRepo, store storage.Storage) *CourierController {
+	return &CourierController{
+		deliveryRepo: deliveryRepo,
+		store:        store,
+	}
+}
+
+// GetDeliveries godoc
+// @Summary Get assigned deliveries
+// @Description Get paginated deliveries assigned to the current courier
+// @Tags courier
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param state query string false "active (default), delivered or all"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/courier/deliveries [get]
+func (cc *CourierController) GetDeliveries(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	state := c.DefaultQuery("state", models.DeliveryStateActive)
+	switch state {
+	case models.DeliveryStateActive, models.DeliveryStateDelivered:
+	case "all":
+		state = ""
+	default:
+		respondError(c, apperrors.ValidationError("state", "must be active, delivered or all"))
+		return
+	}
+
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		pagination = models.PaginationParams{Page: 1, PageSize: models.DefaultPageSize}
+	}
+	if pagination.Page < 1 {
+		pagination.Page = 1
+	}
+
+	deliveries, totalItems, err := cc.deliveryRepo.GetCourierDeliveries(c.Request.Context(), userID.(int), state, &pagination)
+	if handleError(c, err, apperrors.Internal("failed to get deliveries")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Data:       deliveries,
+		Pagination: models.NewPaginationMeta(pagination.Page, pagination.GetLimit(), totalItems),
+	})
+}
+
+// MarkDelivered godoc
+// @Summary Mark order delivered
+// @Description Close an assigned delivery with a proof-of-delivery photo
+// @Tags courier
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param proof formData file true "Proof-of-delivery photo"
+// @Success 200 {object} models.Delivery
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/courier/deliveries/{id}/deliver [post]
+func (cc *CourierController) MarkDelivered(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("order"))
+		return
+	}
+
+	file, err := c.FormFile("proof")
+	if err != nil {
+		respondError(c, apperrors.BadRequest("proof of delivery photo is required"))
+		return
+	}
+
+	key, proofURL, ok := storeImage(c, cc.store, file, proofKeyPrefix(orderID))
+	if !ok {
+		return
+	}
+
+	delivery, err := cc.deliveryRepo.MarkDelivered(c.Request.Context(), orderID, userID.(int), proofURL)
+	if err != nil {
+		_ = cc.store.Delete(c.Request.Context(), key)
+		handleError(c, err, apperrors.Internal("failed to mark order delivered"))
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
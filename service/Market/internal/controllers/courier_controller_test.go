@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockDeliveryRepo struct {
+	listFn      func(ctx context.Context, courierID int, state string, pagination *models.PaginationParams) ([]*models.Delivery, int64, error)
+	deliverFn   func(ctx context.Context, orderID, courierID int, proofURL string) (*models.Delivery, error)
+	addProofFn  func(ctx context.Context, proof *models.DeliveryProof) (*models.DeliveryProof, error)
+	getProofsFn func(ctx context.Context, orderID int) ([]*models.DeliveryProof, error)
+	pickupFn    func(ctx context.Context, orderID, userID int) (string, error)
+	byCodeFn    func(ctx context.Context, code string) (int, error)
+	handOverFn  func(ctx context.Context, orderID, userID int, role string) (*models.Handover, error)
+	courierOK   bool
+	sellerOK    bool
+	ownerOK     bool
+}
+
+func (m *mockDeliveryRepo) GetCourierDeliveries(ctx context.Context, courierID int, state string, pagination *models.PaginationParams) ([]*models.Delivery, int64, error) {
+	return m.listFn(ctx, courierID, state, pagination)
+}
+
+func (m *mockDeliveryRepo) MarkDelivered(ctx context.Context, orderID, courierID int, proofURL string) (*models.Delivery, error) {
+	return m.deliverFn(ctx, orderID, courierID, proofURL)
+}
+
+func (m *mockDeliveryRepo) AddProof(ctx context.Context, proof *models.DeliveryProof) (*models.DeliveryProof, error) {
+	return m.addProofFn(ctx, proof)
+}
+
+func (m *mockDeliveryRepo) GetProofs(ctx context.Context, orderID int) ([]*models.DeliveryProof, error) {
+	return m.getProofsFn(ctx, orderID)
+}
+
+func (m *mockDeliveryRepo) IsAssignedCourier(ctx context.Context, orderID, courierID int) (bool, error) {
+	return m.courierOK, nil
+}
+
+func (m *mockDeliveryRepo) HasSellerItems(ctx context.Context, orderID, sellerUserID int) (bool, error) {
+	return m.sellerOK, nil
+}
+
+func (m *mockDeliveryRepo) IsOrderOwner(ctx context.Context, orderID, userID int) (bool, error) {
+	return m.ownerOK, nil
+}
+
+func (m *mockDeliveryRepo) PickupCode(ctx context.Context, orderID, userID int) (string, error) {
+	return m.pickupFn(ctx, orderID, userID)
+}
+
+func (m *mockDeliveryRepo) OrderIDByPickupCode(ctx context.Context, code string) (int, error) {
+	return m.byCodeFn(ctx, code)
+}
+
+func (m *mockDeliveryRepo) MarkHandedOver(ctx context.Context, orderID, userID int, role string) (*models.Handover, error) {
+	return m.handOverFn(ctx, orderID, userID, role)
+}
+
+var _ repository.DeliveryRepo = (*mockDeliveryRepo)(nil)
+
+func newTestStorage(t *testing.T) (storage.Storage, string) {
+	dir := t.TempDir()
+	store, err := storage.NewLocal(dir, "http://localhost")
+	require.NoError(t, err)
+	return store, dir
+}
+
+func proofRequest(t *testing.T, field, filename string) *http.Request {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile(field, filename)
+	require.NoError(t, err)
+	_, err = part.Write([]byte("fake image"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	req := httptest.NewRequest("POST", "/api/courier/deliveries/7/deliver", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestCourierController_GetDeliveries_DefaultsToActive(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/courier/deliveries", nil)
+	c.Set("user_id", 5)
+
+	m := &mockDeliveryRepo{
+		listFn: func(ctx context.Context, courierID int, state string, pagination *models.PaginationParams) ([]*models.Delivery, int64, error) {
+			require.Equal(t, 5, courierID)
+			require.Equal(t, models.DeliveryStateActive, state)
+			return []*models.Delivery{{OrderID: 7, CourierID: 5, Status: "shipped"}}, 1, nil
+		},
+	}
+
+	store, _ := newTestStorage(t)
+	NewCourierController(m, store).GetDeliveries(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	var resp models.PaginatedResponse
+	require.NoError(t, json.Unmarshal(r.Body.Bytes(), &resp))
+	require.Equal(t, int64(1), resp.Pagination.TotalItems)
+}
+
+func TestCourierController_GetDeliveries_InvalidState(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/courier/deliveries?state=lost", nil)
+	c.Set("user_id", 5)
+
+	store, _ := newTestStorage(t)
+	NewCourierController(&mockDeliveryRepo{}, store).GetDeliveries(c)
+
+	require.Equal(t, http.StatusBadRequest, r.Code)
+}
+
+func TestCourierController_MarkDelivered_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = proofRequest(t, "proof", "door.jpg")
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set("user_id", 5)
+
+	m := &mockDeliveryRepo{
+		deliverFn: func(ctx context.Context, orderID, courierID int, proofURL string) (*models.Delivery, error) {
+			require.Equal(t, 7, orderID)
+			require.Equal(t, 5, courierID)
+			require.Contains(t, proofURL, "http://localhost/uploads/")
+			return &models.Delivery{OrderID: orderID, CourierID: courierID, Status: "delivered", ProofURL: proofURL}, nil
+		},
+	}
+
+	store, dir := newTestStorage(t)
+	NewCourierController(m, store).MarkDelivered(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	files, err := os.ReadDir(filepath.Join(dir, "proofs", "7"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
+
+func TestCourierController_MarkDelivered_MissingProof(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("POST", "/api/courier/deliveries/7/deliver", nil)
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set("user_id", 5)
+
+	store, _ := newTestStorage(t)
+	NewCourierController(&mockDeliveryRepo{}, store).MarkDelivered(c)
+
+	require.Equal(t, http.StatusBadRequest, r.Code)
+}
+
+func TestCourierController_MarkDelivered_NotAssigned(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = proofRequest(t, "proof", "door.png")
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set("user_id", 5)
+
+	m := &mockDeliveryRepo{
+		deliverFn: func(ctx context.Context, orderID, courierID int, proofURL string) (*models.Delivery, error) {
+			return nil, apperrors.NotFound("no open delivery for order 7")
+		},
+	}
+
+	store, dir := newTestStorage(t)
+	NewCourierController(m, store).MarkDelivered(c)
+
+	require.Equal(t, http.StatusNotFound, r.Code)
+	files, err := os.ReadDir(filepath.Join(dir, "proofs", "7"))
+	require.NoError(t, err)
+	require.Empty(t, files, "proof photo must be removed when the delivery is rejected")
+}
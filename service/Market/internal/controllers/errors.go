@@ -8,13 +8,14 @@ import (
 
 // ErrorResponse represents the standard error response structure
 type ErrorResponse struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
 }
 
 // respondError responds with an AppError
 func respondError(c *gin.Context, err *apperrors.AppError) {
-	logger.GetLogger().WithFields(map[string]interface{}{
+	logger.FromContext(c.Request.Context()).WithFields(map[string]interface{}{
 		"code":    err.Code,
 		"message": err.Message,
 		"path":    c.Request.URL.Path,
@@ -23,6 +24,7 @@ func respondError(c *gin.Context, err *apperrors.AppError) {
 	c.JSON(err.HTTPStatus, ErrorResponse{
 		Code:    err.Code,
 		Message: err.Message,
+		Details: err.Details,
 	})
 }
 
@@ -40,7 +42,7 @@ func handleError(c *gin.Context, err error, fallbackErr *apperrors.AppError) boo
 	}
 
 	// Use fallback error with original error message for logging
-	logger.GetLogger().WithField("err", err).Error(fallbackErr.Message)
+	logger.FromContext(c.Request.Context()).WithField("err", err).Error(fallbackErr.Message)
 	respondError(c, fallbackErr)
 	return true
 }
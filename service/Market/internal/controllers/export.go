@@ -0,0 +1,228 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/catalog"
+	"github.com/Zifeldev/marketback/service/Market/internal/exports"
+	"github.com/Zifeldev/marketback/service/Market/internal/feed"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// exportFiles is the part of exports.Runner the controller uses.
+type exportFiles interface {
+	Path(name string) (string, error)
+}
+
+type ExportController struct {
+	sellerRepo  *repository.SellerRepository
+	productRepo *repository.ProductRepository
+	jobRepo     *repository.ExportJobRepository
+	files       exportFiles
+	signer      *feed.Signer
+	baseURL     string
+	threshold   int
+	now         func() time.Time
+}
+
+// NewExportController creates the product export controller. With nil
+// files every export is streamed; otherwise exports of more than threshold
+// products run as jobs whose files are downloaded through URLs signed with
+// signer.
+func NewExportController(
+	sellerRepo *repository.SellerRepository,
+	productRepo *repository.ProductRepository,
+	jobRepo *repository.ExportJobRepository,
+	files exportFiles,
+	signer *feed.Signer,
+	baseURL string,
+	threshold int,
+) *ExportController {
+	return &ExportController{
+		sellerRepo:  sellerRepo,
+		productRepo: productRepo,
+		jobRepo:     jobRepo,
+		files:       files,
+		signer:      signer,
+		baseURL:     baseURL,
+		threshold:   threshold,
+		now:         time.Now,
+	}
+}
+
+// ExportProducts godoc
+// @Summary Export products
+// @Description Download the seller's products as a CSV or XLSX spreadsheet that can be edited and imported back. Sizes are separated by "|"; status is for reference and ignored on import. Catalogs larger than EXPORT_ASYNC_THRESHOLD products (or any with async=true) are exported in the background instead: the response is 202 with an export job to poll at GET /api/seller/exports/{id}.
+// @Tags seller
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce json
+// @Security BearerAuth
+// @Param format query string false "csv or xlsx (default csv)"
+// @Param async query bool false "Export in the background regardless of size"
+// @Success 200 {file} file
+// @Success 202 {object} models.ExportJob
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/products/export [get]
+func (ec *ExportController) ExportProducts(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := c.Request.Context()
+
+	format := c.DefaultQuery("format", catalog.FormatCSV)
+	if format != catalog.FormatCSV && format != catalog.FormatXLSX {
+		respondError(c, apperrors.ValidationError("format", "must be csv or xlsx"))
+		return
+	}
+
+	seller, err := ec.sellerRepo.GetByUserID(ctx, userID.(int))
+	if handleError(c, err, apperrors.Forbidden("seller profile not found")) {
+		return
+	}
+
+	if ec.files != nil {
+		count, err := ec.productRepo.CountBySellerID(ctx, seller.ID)
+		if handleError(c, err, apperrors.Internal("failed to count products")) {
+			return
+		}
+		if count > ec.threshold || c.Query("async") == "true" {
+			job, err := ec.jobRepo.Create(ctx, seller.ID, format, count)
+			if handleError(c, err, apperrors.Internal("failed to start export")) {
+				return
+			}
+			c.Header("Location", fmt.Sprintf("/api/seller/exports/%d", job.ID))
+			c.JSON(http.StatusAccepted, job)
+			return
+		}
+	}
+
+	page, err := ec.productRepo.GetSellerPage(ctx, seller.ID, 0, exports.PageSize)
+	if handleError(c, err, apperrors.Internal("failed to get products")) {
+		return
+	}
+
+	// Rows are streamed a page at a time; once the first page is out an
+	// error can only cut the download short.
+	c.Header("Content-Type", catalog.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="products.%s"`, format))
+	c.Status(http.StatusOK)
+	w, err := catalog.NewWriter(c.Writer, format)
+	for err == nil {
+		if err = w.Write(page); err != nil || len(page) < exports.PageSize {
+			break
+		}
+		c.Writer.Flush()
+		page, err = ec.productRepo.GetSellerPage(ctx, seller.ID, page[len(page)-1].ID, exports.PageSize)
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		logger.FromContext(ctx).WithField("err", err).Error("failed to stream product export")
+		c.Abort()
+	}
+}
+
+// GetExportJob godoc
+// @Summary Get export
+// @Description Poll a background product export: processed_rows out of total_rows shows its progress. Once done it has a signed download_url, valid until expires_at, after which the file is removed and the export is expired.
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Export ID"
+// @Success 200 {object} models.ExportJob
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/exports/{id} [get]
+func (ec *ExportController) GetExportJob(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("export"))
+		return
+	}
+
+	seller, err := ec.sellerRepo.GetByUserID(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.Forbidden("seller profile not found")) {
+		return
+	}
+
+	job, err := ec.jobRepo.GetByID(c.Request.Context(), id, seller.ID)
+	if handleError(c, err, apperrors.Internal("failed to get export")) {
+		return
+	}
+
+	if job.Status == models.ExportDone && job.ExpiresAt != nil {
+		job.DownloadURL = fmt.Sprintf("%s/api/exports/%d?expires=%d&signature=%s",
+			ec.baseURL, job.ID, job.ExpiresAt.Unix(), ec.signer.Sign(exportSigningName(job.ID), *job.ExpiresAt))
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// DownloadExport godoc
+// @Summary Download export
+// @Description Download the file of a finished product export through the signed URL from GET /api/seller/exports/{id}
+// @Tags seller
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param id path int true "Export ID"
+// @Param expires query int true "Expiry as Unix time"
+// @Param signature query string true "URL signature"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/exports/{id} [get]
+func (ec *ExportController) DownloadExport(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("export"))
+		return
+	}
+	if !ec.signer.Verify(exportSigningName(id), c.Query("expires"), c.Query("signature"), ec.now()) {
+		respondError(c, apperrors.Forbidden("invalid or expired export signature"))
+		return
+	}
+
+	job, err := ec.jobRepo.GetByID(c.Request.Context(), id, 0)
+	if handleError(c, err, apperrors.Internal("failed to get export")) {
+		return
+	}
+	if job.Status != models.ExportDone {
+		respondError(c, apperrors.NotFound("export file not found"))
+		return
+	}
+
+	path, err := ec.files.Path(job.FileName)
+	if errors.Is(err, exports.ErrNotFound) {
+		respondError(c, apperrors.NotFound("export file not found"))
+		return
+	}
+	if handleError(c, err, apperrors.Internal("failed to read export")) {
+		return
+	}
+
+	c.Header("Content-Type", catalog.ContentType(job.Format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="products.%s"`, job.Format))
+	c.File(path)
+}
+
+// exportSigningName is what the download URLs of an export are signed for.
+func exportSigningName(id int) string {
+	return fmt.Sprintf("exports/%d", id)
+}
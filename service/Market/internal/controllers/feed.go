@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/feed"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// feedFiles is the part of feed.Generator the controller uses.
+type feedFiles interface {
+	Stat(name string) (feed.File, error)
+	RunOnce(ctx context.Context) error
+}
+
+type FeedController struct {
+	files   feedFiles
+	signer  *feed.Signer
+	baseURL string
+	urlTTL  time.Duration
+	now     func() time.Time
+}
+
+func NewFeedController(files feedFiles, signer *feed.Signer, baseURL string, urlTTL time.Duration) *FeedController {
+	return &FeedController{
+		files:   files,
+		signer:  signer,
+		baseURL: baseURL,
+		urlTTL:  urlTTL,
+		now:     time.Now,
+	}
+}
+
+// GetFeed godoc
+// @Summary Download catalog feed
+// @Description Download a catalog feed through a signed URL issued by GET /api/admin/feeds
+// @Tags feeds
+// @Produce xml
+// @Produce text/csv
+// @Param name path string true "catalog.xml or catalog.csv"
+// @Param expires query int true "Expiry as Unix time"
+// @Param signature query string true "URL signature"
+// @Success 200 {file} file
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/feeds/{name} [get]
+func (fc *FeedController) GetFeed(c *gin.Context) {
+	name := c.Param("name")
+	if !isCatalog(name) {
+		respondError(c, apperrors.NotFound("feed not found"))
+		return
+	}
+	if !fc.signer.Verify(name, c.Query("expires"), c.Query("signature"), fc.now()) {
+		respondError(c, apperrors.Forbidden("invalid or expired feed signature"))
+		return
+	}
+
+	fc.serve(c, name)
+}
+
+// GetSitemap godoc
+// @Summary Get sitemap
+// @Description Sitemap of active product pages, regenerated with the catalog feeds
+// @Tags feeds
+// @Produce xml
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]string
+// @Router /sitemap.xml [get]
+func (fc *FeedController) GetSitemap(c *gin.Context) {
+	fc.serve(c, feed.Sitemap)
+}
+
+func (fc *FeedController) serve(c *gin.Context, name string) {
+	f, err := fc.files.Stat(name)
+	if errors.Is(err, feed.ErrNotFound) {
+		respondError(c, apperrors.NotFound("feed has not been generated yet"))
+		return
+	}
+	if handleError(c, err, apperrors.Internal("failed to read feed")) {
+		return
+	}
+
+	c.File(f.Path)
+}
+
+// GetFeeds godoc
+// @Summary List catalog feeds
+// @Description List generated catalog feeds with signed download URLs for ad platforms (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.FeedFile
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/feeds [get]
+func (fc *FeedController) GetFeeds(c *gin.Context) {
+	files, err := fc.list()
+	if handleError(c, err, apperrors.Internal("failed to list feeds")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, files)
+}
+
+// RegenerateFeeds godoc
+// @Summary Regenerate catalog feeds
+// @Description Regenerate the catalog feeds and sitemap now instead of waiting for the schedule (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.FeedFile
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/feeds/regenerate [post]
+func (fc *FeedController) RegenerateFeeds(c *gin.Context) {
+	if err := fc.files.RunOnce(c.Request.Context()); err != nil {
+		handleError(c, err, apperrors.Internal("failed to generate feeds"))
+		return
+	}
+
+	fc.GetFeeds(c)
+}
+
+func (fc *FeedController) list() ([]models.FeedFile, error) {
+	expires := fc.now().Add(fc.urlTTL)
+	files := []models.FeedFile{}
+	for _, name := range feed.Catalogs {
+		f, err := fc.files.Stat(name)
+		if errors.Is(err, feed.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, models.FeedFile{
+			Name:        name,
+			URL:         fc.signer.URL(fc.baseURL, name, expires),
+			ExpiresAt:   expires.UTC().Truncate(time.Second),
+			GeneratedAt: f.GeneratedAt,
+			Size:        f.Size,
+		})
+	}
+	return files, nil
+}
+
+func isCatalog(name string) bool {
+	for _, n := range feed.Catalogs {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
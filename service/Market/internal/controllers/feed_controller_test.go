@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/feed"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockFeedFiles struct {
+	dir   string
+	runFn func(ctx context.Context) error
+}
+
+func (m *mockFeedFiles) Stat(name string) (feed.File, error) {
+	path := filepath.Join(m.dir, name)
+	info, err := os.Stat(path)
+	if err != nil {
+		return feed.File{}, feed.ErrNotFound
+	}
+	return feed.File{Name: name, Path: path, GeneratedAt: info.ModTime(), Size: info.Size()}, nil
+}
+
+func (m *mockFeedFiles) RunOnce(ctx context.Context) error {
+	return m.runFn(ctx)
+}
+
+func newFeedTestController(t *testing.T) (*FeedController, *feed.Signer) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, feed.CatalogCSV), []byte("id,title\n1,Mug\n"), 0644))
+	signer := feed.NewSigner("secret")
+	return NewFeedController(&mockFeedFiles{dir: dir}, signer, "https://api.example.com", time.Hour), signer
+}
+
+func TestFeedController_GetFeed_Signed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fc, signer := newFeedTestController(t)
+
+	expires := time.Now().Add(time.Minute)
+	query := "?expires=" + strconv.FormatInt(expires.Unix(), 10) + "&signature=" + signer.Sign(feed.CatalogCSV, expires)
+
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/feeds/catalog.csv"+query, nil)
+	c.Params = gin.Params{{Key: "name", Value: feed.CatalogCSV}}
+	fc.GetFeed(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	require.Contains(t, r.Body.String(), "1,Mug")
+}
+
+func TestFeedController_GetFeed_BadSignature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fc, _ := newFeedTestController(t)
+
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/feeds/catalog.csv?expires=9999999999&signature=forged", nil)
+	c.Params = gin.Params{{Key: "name", Value: feed.CatalogCSV}}
+	fc.GetFeed(c)
+
+	require.Equal(t, http.StatusForbidden, r.Code)
+}
+
+func TestFeedController_GetFeeds_OnlyGenerated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fc, signer := newFeedTestController(t)
+
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/admin/feeds", nil)
+	fc.GetFeeds(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	var files []models.FeedFile
+	require.NoError(t, json.Unmarshal(r.Body.Bytes(), &files))
+	require.Len(t, files, 1)
+	require.Equal(t, feed.CatalogCSV, files[0].Name)
+	require.Equal(t, signer.URL("https://api.example.com", feed.CatalogCSV, files[0].ExpiresAt), files[0].URL)
+}
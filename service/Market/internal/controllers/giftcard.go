@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type GiftCardController struct {
+	giftCardRepo repository.GiftCardRepo
+}
+
+func NewGiftCardController(giftCardRepo repository.GiftCardRepo) *GiftCardController {
+	return &GiftCardController{giftCardRepo: giftCardRepo}
+}
+
+// CreateGiftCard godoc
+// @Summary Issue gift card
+// @Description Issue a gift card worth amount. Without a code a random 16 character one is made. Buyers spend it with the order pay endpoint until the balance runs out or it expires.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateGiftCardRequest true "Gift card"
+// @Success 201 {object} models.GiftCard
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/gift-cards [post]
+func (gc *GiftCardController) CreateGiftCard(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.CreateGiftCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	card, err := gc.giftCardRepo.Create(c.Request.Context(), userID.(int), &req)
+	if handleError(c, err, apperrors.Internal("failed to create gift card")) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, card)
+}
+
+// GetGiftCardBalance godoc
+// @Summary Get gift card balance
+// @Description Get what is left on a gift card and when it expires
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "Gift card code"
+// @Success 200 {object} models.GiftCardBalance
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/user/gift-cards/{code} [get]
+func (gc *GiftCardController) GetGiftCardBalance(c *gin.Context) {
+	balance, err := gc.giftCardRepo.GetBalance(c.Request.Context(), c.Param("code"))
+	if handleError(c, err, apperrors.Internal("failed to get gift card")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, balance)
+}
+
+// GetStoreCredit godoc
+// @Summary Get my store credit
+// @Description Get the current user's store credit, spent on orders with use_store_credit when paying
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.StoreCredit
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/user/store-credit [get]
+func (gc *GiftCardController) GetStoreCredit(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	credit, err := gc.giftCardRepo.GetStoreCredit(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.Internal("failed to get store credit")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, credit)
+}
+
+// GrantStoreCredit godoc
+// @Summary Grant store credit
+// @Description Add amount to a user's store credit
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body models.GrantStoreCreditRequest true "Amount"
+// @Success 200 {object} models.StoreCredit
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/users/{id}/store-credit [post]
+func (gc *GiftCardController) GrantStoreCredit(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || userID <= 0 {
+		respondError(c, apperrors.InvalidID("user"))
+		return
+	}
+
+	var req models.GrantStoreCreditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	credit, err := gc.giftCardRepo.GrantStoreCredit(c.Request.Context(), userID, req.Amount)
+	if handleError(c, err, apperrors.Internal("failed to grant store credit")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, credit)
+}
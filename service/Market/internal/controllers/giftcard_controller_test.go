@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockGiftCardRepo struct {
+	createFn           func(ctx context.Context, createdBy int, req *models.CreateGiftCardRequest) (*models.GiftCard, error)
+	getBalanceFn       func(ctx context.Context, code string) (*models.GiftCardBalance, error)
+	getStoreCreditFn   func(ctx context.Context, userID int) (*models.StoreCredit, error)
+	grantStoreCreditFn func(ctx context.Context, userID int, amount float64) (*models.StoreCredit, error)
+}
+
+func (m *mockGiftCardRepo) Create(ctx context.Context, createdBy int, req *models.CreateGiftCardRequest) (*models.GiftCard, error) {
+	return m.createFn(ctx, createdBy, req)
+}
+
+func (m *mockGiftCardRepo) GetBalance(ctx context.Context, code string) (*models.GiftCardBalance, error) {
+	return m.getBalanceFn(ctx, code)
+}
+
+func (m *mockGiftCardRepo) GetStoreCredit(ctx context.Context, userID int) (*models.StoreCredit, error) {
+	return m.getStoreCreditFn(ctx, userID)
+}
+
+func (m *mockGiftCardRepo) GrantStoreCredit(ctx context.Context, userID int, amount float64) (*models.StoreCredit, error) {
+	return m.grantStoreCreditFn(ctx, userID, amount)
+}
+
+var _ repository.GiftCardRepo = (*mockGiftCardRepo)(nil)
+
+func TestGiftCardController_CreateGiftCard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{name: "random code", body: `{"amount":50}`, wantCode: http.StatusCreated},
+		{name: "own code", body: `{"code":"WINTER2024","amount":25}`, wantCode: http.StatusCreated},
+		{name: "code too short", body: `{"code":"ABC","amount":25}`, wantCode: http.StatusBadRequest},
+		{name: "no amount", body: `{"code":"WINTER2024"}`, wantCode: http.StatusBadRequest},
+		{name: "negative amount", body: `{"amount":-5}`, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("POST", "/api/admin/gift-cards", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("user_id", 1)
+
+			NewGiftCardController(&mockGiftCardRepo{
+				createFn: func(ctx context.Context, createdBy int, req *models.CreateGiftCardRequest) (*models.GiftCard, error) {
+					require.Equal(t, 1, createdBy)
+					return &models.GiftCard{ID: 2, Code: req.Code, InitialAmount: req.Amount, Balance: req.Amount, CreatedBy: createdBy}, nil
+				},
+			}).CreateGiftCard(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+		})
+	}
+}
+
+func TestGiftCardController_GetGiftCardBalance_Unknown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/user/gift-cards/NOPE1234", nil)
+	c.Params = gin.Params{{Key: "code", Value: "NOPE1234"}}
+
+	NewGiftCardController(&mockGiftCardRepo{
+		getBalanceFn: func(ctx context.Context, code string) (*models.GiftCardBalance, error) {
+			return nil, apperrors.NotFound("gift card not found")
+		},
+	}).GetGiftCardBalance(c)
+
+	require.Equal(t, http.StatusNotFound, r.Code)
+}
+
+func TestGiftCardController_GrantStoreCredit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		param    string
+		body     string
+		wantCode int
+	}{
+		{name: "granted", param: "42", body: `{"amount":12.5}`, wantCode: http.StatusOK},
+		{name: "invalid user", param: "abc", body: `{"amount":12.5}`, wantCode: http.StatusBadRequest},
+		{name: "zero amount", param: "42", body: `{"amount":0}`, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("POST", "/api/admin/users/"+tt.param+"/store-credit", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.param}}
+
+			NewGiftCardController(&mockGiftCardRepo{
+				grantStoreCreditFn: func(ctx context.Context, userID int, amount float64) (*models.StoreCredit, error) {
+					require.Equal(t, 42, userID)
+					require.Equal(t, 12.5, amount)
+					return &models.StoreCredit{UserID: userID, Balance: amount}, nil
+				},
+			}).GrantStoreCredit(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/Zifeldev/marketback/service/Market/internal/buildinfo"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
@@ -115,3 +116,14 @@ func (h *HealthController) Health(c *gin.Context) {
 		},
 	})
 }
+
+// Version godoc
+// @Summary Build information
+// @Description Version, git commit, build date and Go runtime of the running binary
+// @Tags health
+// @Produce json
+// @Success 200 {object} buildinfo.Info
+// @Router /version [get]
+func (h *HealthController) Version(c *gin.Context) {
+	c.JSON(http.StatusOK, buildinfo.Get())
+}
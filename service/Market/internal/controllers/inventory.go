@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/settings"
+	"github.com/gin-gonic/gin"
+)
+
+type InventoryController struct {
+	sellers       importSellers
+	inventoryRepo repository.InventoryRepo
+	settings      *settings.Store
+}
+
+// NewInventoryController creates the inventory import controller. A nil
+// settings store uses the default risk threshold.
+func NewInventoryController(sellers importSellers, inventoryRepo repository.InventoryRepo, siteSettings *settings.Store) *InventoryController {
+	return &InventoryController{sellers: sellers, inventoryRepo: inventoryRepo, settings: siteSettings}
+}
+
+// withImport resolves the current seller and the import in the path and
+// responds with what fn returns.
+func (ic *InventoryController) withImport(c *gin.Context, failure string, fn func(ctx context.Context, id, sellerID int) (*models.InventoryImport, error)) {
+	userID, _ := c.Get("user_id")
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("inventory import"))
+		return
+	}
+
+	seller, err := ic.sellers.GetByUserID(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.Forbidden("seller profile not found")) {
+		return
+	}
+
+	imp, err := fn(c.Request.Context(), id, seller.ID)
+	if handleError(c, err, apperrors.Internal(failure)) {
+		return
+	}
+
+	c.JSON(http.StatusOK, imp)
+}
+
+// CreateInventoryImport godoc
+// @Summary Import inventory
+// @Description Set the stock, the price or both of many products at once. Each line is reconciled with the product and the response is the reconciliation report: stock decreases are flagged as negative adjustments, price changes beyond the inventory_risk_percent setting as price jumps, and lines naming products the seller does not have are skipped. Lines changing the price, or dropping the stock, by more than that percentage are held until the seller confirms the import; the others are applied right away.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.InventoryImportRequest true "Stock and price per product"
+// @Success 201 {object} models.InventoryImport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/inventory-imports [post]
+func (ic *InventoryController) CreateInventoryImport(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	seller, err := ic.sellers.GetByUserID(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.Forbidden("seller profile not found")) {
+		return
+	}
+
+	var req models.InventoryImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	ctx := c.Request.Context()
+	imp, err := ic.inventoryRepo.CreateImport(ctx, seller.ID, &req, ic.settings.InventoryRiskPercent(ctx))
+	if handleError(c, err, apperrors.Internal("failed to import inventory")) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, imp)
+}
+
+// GetInventoryImport godoc
+// @Summary Get inventory import
+// @Description One of the current seller's inventory imports with its reconciliation report
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Inventory import ID"
+// @Success 200 {object} models.InventoryImport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/inventory-imports/{id} [get]
+func (ic *InventoryController) GetInventoryImport(c *gin.Context) {
+	ic.withImport(c, "failed to get inventory import", ic.inventoryRepo.GetImport)
+}
+
+// ConfirmInventoryImport godoc
+// @Summary Confirm inventory import
+// @Description Apply the lines an inventory import holds for confirmation
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Inventory import ID"
+// @Success 200 {object} models.InventoryImport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/inventory-imports/{id}/confirm [post]
+func (ic *InventoryController) ConfirmInventoryImport(c *gin.Context) {
+	ic.withImport(c, "failed to confirm inventory import", ic.inventoryRepo.ConfirmImport)
+}
+
+// DiscardInventoryImport godoc
+// @Summary Discard inventory import
+// @Description Drop the lines an inventory import holds for confirmation; lines already applied stay applied
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Inventory import ID"
+// @Success 200 {object} models.InventoryImport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/inventory-imports/{id}/discard [post]
+func (ic *InventoryController) DiscardInventoryImport(c *gin.Context) {
+	ic.withImport(c, "failed to discard inventory import", ic.inventoryRepo.DiscardImport)
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockInventoryRepo struct {
+	createImportFn  func(ctx context.Context, sellerID int, req *models.InventoryImportRequest, riskPercent float64) (*models.InventoryImport, error)
+	getImportFn     func(ctx context.Context, id, sellerID int) (*models.InventoryImport, error)
+	confirmImportFn func(ctx context.Context, id, sellerID int) (*models.InventoryImport, error)
+	discardImportFn func(ctx context.Context, id, sellerID int) (*models.InventoryImport, error)
+}
+
+func (m *mockInventoryRepo) CreateImport(ctx context.Context, sellerID int, req *models.InventoryImportRequest, riskPercent float64) (*models.InventoryImport, error) {
+	return m.createImportFn(ctx, sellerID, req, riskPercent)
+}
+
+func (m *mockInventoryRepo) GetImport(ctx context.Context, id, sellerID int) (*models.InventoryImport, error) {
+	return m.getImportFn(ctx, id, sellerID)
+}
+
+func (m *mockInventoryRepo) ConfirmImport(ctx context.Context, id, sellerID int) (*models.InventoryImport, error) {
+	return m.confirmImportFn(ctx, id, sellerID)
+}
+
+func (m *mockInventoryRepo) DiscardImport(ctx context.Context, id, sellerID int) (*models.InventoryImport, error) {
+	return m.discardImportFn(ctx, id, sellerID)
+}
+
+var _ repository.InventoryRepo = (*mockInventoryRepo)(nil)
+
+func TestInventoryController_CreateInventoryImport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		userID   int
+		body     string
+		wantCode int
+	}{
+		{name: "applied", userID: 5, body: `{"rows":[{"product_id":3,"stock":10},{"product_id":4,"price":12.5}]}`, wantCode: http.StatusCreated},
+		{name: "line without changes", userID: 5, body: `{"rows":[{"product_id":3}]}`, wantCode: http.StatusBadRequest},
+		{name: "negative stock", userID: 5, body: `{"rows":[{"product_id":3,"stock":-1}]}`, wantCode: http.StatusBadRequest},
+		{name: "no rows", userID: 5, body: `{"rows":[]}`, wantCode: http.StatusBadRequest},
+		{name: "not a seller", userID: 6, body: `{"rows":[{"product_id":3,"stock":10}]}`, wantCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("POST", "/api/seller/inventory-imports", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("user_id", tt.userID)
+
+			NewInventoryController(mockImportSellers{}, &mockInventoryRepo{
+				createImportFn: func(ctx context.Context, sellerID int, req *models.InventoryImportRequest, riskPercent float64) (*models.InventoryImport, error) {
+					require.Equal(t, 11, sellerID)
+					require.Equal(t, 20.0, riskPercent, "the default threshold applies without settings")
+					for _, line := range req.Rows {
+						if line.Stock == nil && line.Price == nil {
+							return nil, apperrors.ValidationError("rows", "line 1 sets neither stock nor price")
+						}
+					}
+					return &models.InventoryImport{ID: 1, SellerID: sellerID, Status: models.InventoryImportApplied}, nil
+				},
+			}, nil).CreateInventoryImport(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+		})
+	}
+}
+
+func TestInventoryController_ConfirmInventoryImport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		param    string
+		wantCode int
+	}{
+		{name: "awaiting confirmation", param: "7", wantCode: http.StatusOK},
+		{name: "already applied", param: "8", wantCode: http.StatusConflict},
+		{name: "someone else's import", param: "9", wantCode: http.StatusNotFound},
+		{name: "invalid id", param: "abc", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("POST", "/api/seller/inventory-imports/"+tt.param+"/confirm", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.param}}
+			c.Set("user_id", 5)
+
+			NewInventoryController(mockImportSellers{}, &mockInventoryRepo{
+				confirmImportFn: func(ctx context.Context, id, sellerID int) (*models.InventoryImport, error) {
+					require.Equal(t, 11, sellerID)
+					switch id {
+					case 8:
+						return nil, apperrors.Conflict("inventory import is applied, nothing awaits confirmation")
+					case 9:
+						return nil, apperrors.NotFound("inventory import not found")
+					}
+					return &models.InventoryImport{ID: id, SellerID: sellerID, Status: models.InventoryImportConfirmed}, nil
+				},
+			}, nil).ConfirmInventoryImport(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+		})
+	}
+}
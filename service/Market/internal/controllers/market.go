@@ -1,17 +1,24 @@
 package controllers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/botdetect"
 	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
 	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/ordernumber"
 	"github.com/Zifeldev/marketback/service/Market/internal/repository"
 	"github.com/Zifeldev/marketback/service/Market/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
+// maxOrderQuery bounds order search text.
+const maxOrderQuery = 100
+
 type MarketController struct {
 	productRepo   repository.ProductRepo
 	categoryRepo  repository.CategoryRepo
@@ -38,15 +45,17 @@ func NewMarketController(
 
 // GetProducts godoc
 // @Summary Get all products
-// @Description Get paginated list of products with optional filters
+// @Description Get paginated list of products with optional filters. On a seller's custom domain only that seller's products are listed and seller_id is ignored. Elsewhere products of sellers on probation are listed to a share of visitors only, told apart by the X-Visitor-ID header, the visitor_id cookie or else their IP.
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param category_id query int false "Filter by category ID"
 // @Param seller_id query int false "Filter by seller ID"
 // @Param status query string false "Filter by status"
+// @Param X-Visitor-ID header string false "Stable ID of the visitor"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(20)
+// @Param count query string false "exact (default) or estimate; estimate skips the full COUNT on large listings"
 // @Success 200 {object} models.PaginatedResponse
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -66,21 +75,36 @@ func (mc *MarketController) GetProducts(c *gin.Context) {
 			sellerID = &sellID
 		}
 	}
+	if storefrontSellerID := c.GetInt("storefront_seller_id"); storefrontSellerID > 0 {
+		sellerID = &storefrontSellerID
+	}
+	var visitorBucket *int
+	if bucket, ok := c.Get("visitor_bucket"); ok {
+		b := bucket.(int)
+		visitorBucket = &b
+	}
 
 	var pagination models.PaginationParams
 	if err := c.ShouldBindQuery(&pagination); err != nil {
 		respondError(c, apperrors.BadRequest("invalid pagination parameters"))
 		return
 	}
+	// Crawlers walk every page; spare them the exact count.
+	if c.GetString("traffic_class") == botdetect.ClassCrawler {
+		pagination.Count = models.CountEstimate
+	}
 
-	products, totalItems, err := mc.productRepo.GetAll(c.Request.Context(), categoryID, sellerID, status, &pagination)
+	products, totalItems, err := mc.productRepo.GetAll(c.Request.Context(), categoryID, sellerID, status, visitorBucket, &pagination)
 	if handleError(c, err, apperrors.Internal("failed to get products")) {
 		return
 	}
 
+	meta := models.NewPaginationMeta(pagination.Page, pagination.GetLimit(), totalItems)
+	meta.Estimated = pagination.EstimateCount()
+
 	response := models.PaginatedResponse{
 		Data:       products,
-		Pagination: models.NewPaginationMeta(pagination.Page, pagination.GetLimit(), totalItems),
+		Pagination: meta,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -88,7 +112,7 @@ func (mc *MarketController) GetProducts(c *gin.Context) {
 
 // GetProduct godoc
 // @Summary Get product by ID
-// @Description Get detailed product information
+// @Description Get detailed product information. On a seller's custom domain other sellers' products are not found.
 // @Tags products
 // @Accept json
 // @Produce json
@@ -108,6 +132,10 @@ func (mc *MarketController) GetProduct(c *gin.Context) {
 	if handleError(c, err, apperrors.ProductNotFound(id)) {
 		return
 	}
+	if storefrontSellerID := c.GetInt("storefront_seller_id"); storefrontSellerID > 0 && product.SellerID != storefrontSellerID {
+		respondError(c, apperrors.ProductNotFound(id))
+		return
+	}
 
 	metrics.ProductsViewedTotal.Inc()
 
@@ -116,7 +144,7 @@ func (mc *MarketController) GetProduct(c *gin.Context) {
 
 // GetCategories godoc
 // @Summary Get all categories
-// @Description Get list of all product categories
+// @Description Get list of all product categories with their active product counts
 // @Tags categories
 // @Accept json
 // @Produce json
@@ -180,9 +208,30 @@ func (mc *MarketController) GetCart(c *gin.Context) {
 	c.JSON(http.StatusOK, cartItems)
 }
 
+// GetCartPackage godoc
+// @Summary Get cart shipping package
+// @Description Get the parcel the cart's items ship in: their total weight and a box they fit in, checked against the carrier's limits. Items listed in oversize, or a parcel over the weight limit (exceeds), cannot be ordered. complete is false when some items have no weight.
+// @Tags cart
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.ShippingPackage
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/cart/package [get]
+func (mc *MarketController) GetCartPackage(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	cartItems, err := mc.cartRepo.GetUserCart(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.Internal("failed to get cart")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, mc.marketService.Package(cartItems))
+}
+
 // AddToCart godoc
 // @Summary Add item to cart
-// @Description Add a product to user's cart
+// @Description Add a product to user's cart. The item's stock is reserved until reserved_until; 409 INSUFFICIENT_STOCK when other carts' reservations leave too little.
 // @Tags cart
 // @Accept json
 // @Produce json
@@ -191,6 +240,8 @@ func (mc *MarketController) GetCart(c *gin.Context) {
 // @Success 201 {object} models.CartItem
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/cart/items [post]
 func (mc *MarketController) AddToCart(c *gin.Context) {
@@ -214,7 +265,7 @@ func (mc *MarketController) AddToCart(c *gin.Context) {
 
 // UpdateCartItem godoc
 // @Summary Update cart item
-// @Description Update quantity of a cart item
+// @Description Update quantity of a cart item and renew its stock reservation; 409 INSUFFICIENT_STOCK when other carts' reservations leave too little.
 // @Tags cart
 // @Accept json
 // @Produce json
@@ -224,6 +275,8 @@ func (mc *MarketController) AddToCart(c *gin.Context) {
 // @Success 200 {object} models.CartItem
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/cart/items/{id} [put]
 func (mc *MarketController) UpdateCartItem(c *gin.Context) {
@@ -279,7 +332,7 @@ func (mc *MarketController) DeleteCartItem(c *gin.Context) {
 
 // CreateOrder godoc
 // @Summary Create order
-// @Description Create a new order from cart items
+// @Description Create a new order from cart items. The parcel the items ship in is recorded on the order; 422 PACKAGE_TOO_LARGE, with the cart's package as details, when an item or the parcel exceeds the carrier's limits.
 // @Tags orders
 // @Accept json
 // @Produce json
@@ -288,6 +341,8 @@ func (mc *MarketController) DeleteCartItem(c *gin.Context) {
 // @Success 201 {object} models.OrderWithItems
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/user/orders [post]
 func (mc *MarketController) CreateOrder(c *gin.Context) {
@@ -299,6 +354,8 @@ func (mc *MarketController) CreateOrder(c *gin.Context) {
 		return
 	}
 
+	req.Email = c.GetString("email")
+
 	order, err := mc.marketService.CreateOrder(c.Request.Context(), userID.(int), &req)
 	if handleError(c, err, apperrors.Internal("failed to create order")) {
 		return
@@ -311,14 +368,17 @@ func (mc *MarketController) CreateOrder(c *gin.Context) {
 
 // GetUserOrders godoc
 // @Summary Get user orders
-// @Description Get all orders for current user with pagination
+// @Description Get all orders for current user with pagination, optionally only those whose order number or a product title contains q
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param q query string false "Search by order number or product title (partial match)"
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(20)
+// @Param count query string false "exact (default) or estimate"
 // @Success 200 {object} models.PaginatedResponse
+// @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/user/orders [get]
@@ -333,41 +393,115 @@ func (mc *MarketController) GetUserOrders(c *gin.Context) {
 		pagination.Page = 1
 	}
 
-	orders, totalItems, err := mc.orderRepo.GetUserOrders(c.Request.Context(), userID.(int), &pagination)
+	query := strings.TrimSpace(c.Query("q"))
+	if len(query) > maxOrderQuery {
+		respondError(c, apperrors.ValidationError("q", fmt.Sprintf("must be at most %d characters", maxOrderQuery)))
+		return
+	}
+
+	orders, totalItems, err := mc.orderRepo.GetUserOrders(c.Request.Context(), userID.(int), query, &pagination)
 	if handleError(c, err, apperrors.Internal("failed to get orders")) {
 		return
 	}
 
+	meta := models.NewPaginationMeta(pagination.Page, pagination.GetLimit(), totalItems)
+	meta.Estimated = pagination.EstimateCount()
+
 	response := models.PaginatedResponse{
 		Data:       orders,
-		Pagination: models.NewPaginationMeta(pagination.Page, pagination.GetLimit(), totalItems),
+		Pagination: meta,
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
 // GetOrder godoc
-// @Summary Get order by ID
-// @Description Get detailed order information
+// @Summary Get order by ID or order number
+// @Description Get detailed order information by numeric ID or order number (e.g. MB-2024-000123). Other users' orders are not found, except for admins.
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param id path int true "Order ID"
+// @Param id path string true "Order ID or order number"
 // @Success 200 {object} models.OrderWithItems
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /api/user/orders/{id} [get]
 func (mc *MarketController) GetOrder(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	role, _ := c.Get("role")
+
+	var (
+		order    *models.OrderWithItems
+		notFound *apperrors.AppError
+	)
+	param := c.Param("id")
+	orderID, err := strconv.Atoi(param)
+	if err != nil {
+		if !ordernumber.Valid(param) {
+			respondError(c, apperrors.InvalidID("order"))
+			return
+		}
+		notFound = apperrors.OrderNumberNotFound(param)
+		order, err = mc.orderRepo.GetByNumber(c.Request.Context(), param)
+	} else {
+		notFound = apperrors.OrderNotFound(orderID)
+		order, err = mc.orderRepo.GetByID(c.Request.Context(), orderID)
+	}
+	if handleError(c, err, notFound) {
+		return
+	}
+	// Order numbers are sequential; others' orders are reported as not
+	// found so they cannot be walked. Tokens without a user, e.g. service
+	// tokens, own no orders.
+	uid, ok := userID.(int)
+	if role != "admin" && (!ok || order.UserID != uid) {
+		respondError(c, notFound)
+		return
+	}
+
+	mc.respondOrder(c, order)
+}
+
+// respondOrder writes order details, with the cancellation window still
+// open to the buyer.
+func (mc *MarketController) respondOrder(c *gin.Context, order *models.OrderWithItems) {
+	if mc.marketService != nil {
+		err := mc.marketService.SetCancellationWindow(c.Request.Context(), order)
+		if handleError(c, err, apperrors.Internal("failed to get order")) {
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// CancelOrder godoc
+// @Summary Cancel order
+// @Description Cancel one of the current user's orders within its cancellation window, counted from purchase. The window is the order_cancellation_window setting unless a seller of the order has its own; the shortest applies. Paid orders, shipped orders and orders with shipped items cannot be cancelled. Open items are restocked and their sellers notified.
+// @Tags orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} models.OrderWithItems
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/user/orders/{id}/cancel [post]
+func (mc *MarketController) CancelOrder(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
 	orderID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		respondError(c, apperrors.InvalidID("order"))
 		return
 	}
 
-	order, err := mc.orderRepo.GetByID(c.Request.Context(), orderID)
-	if handleError(c, err, apperrors.OrderNotFound(orderID)) {
+	order, err := mc.marketService.CancelOrder(c.Request.Context(), userID.(int), orderID)
+	if handleError(c, err, apperrors.Internal("failed to cancel order")) {
 		return
 	}
 
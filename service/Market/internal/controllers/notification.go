@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/transform"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationController struct {
+	notificationRepo repository.NotificationRepo
+	transforms       *transform.Rules
+}
+
+// NewNotificationController serves notifications with the notification
+// rules of transforms, if any, applied.
+func NewNotificationController(notificationRepo repository.NotificationRepo, transforms *transform.Rules) *NotificationController {
+	return &NotificationController{notificationRepo: notificationRepo, transforms: transforms}
+}
+
+// GetNotifications godoc
+// @Summary Get notifications
+// @Description Get paginated in-app notifications of the current user, newest first
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/user/notifications [get]
+func (nc *NotificationController) GetNotifications(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		pagination = models.PaginationParams{Page: 1, PageSize: models.DefaultPageSize}
+	}
+	if pagination.Page < 1 {
+		pagination.Page = 1
+	}
+
+	notifications, totalItems, err := nc.notificationRepo.GetUserNotifications(c.Request.Context(), userID.(int), &pagination)
+	if handleError(c, err, apperrors.Internal("failed to get notifications")) {
+		return
+	}
+	for _, n := range notifications {
+		nc.transforms.Notification(n)
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Data:       notifications,
+		Pagination: models.NewPaginationMeta(pagination.Page, pagination.GetLimit(), totalItems),
+	})
+}
+
+// MarkNotificationRead godoc
+// @Summary Mark notification read
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Notification ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/user/notifications/{id}/read [put]
+func (nc *NotificationController) MarkNotificationRead(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("notification"))
+		return
+	}
+
+	if err := nc.notificationRepo.MarkRead(c.Request.Context(), id, userID.(int)); err != nil {
+		handleError(c, err, apperrors.Internal("failed to mark notification read"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "notification marked as read"})
+}
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// onboardingStep describes a checklist step; done reads its completion
+// from the seller's onboarding state.
+type onboardingStep struct {
+	key         string
+	title       string
+	description string
+	path        string
+	done        func(s *models.SellerOnboardingState) bool
+}
+
+var onboardingSteps = []onboardingStep{
+	{
+		key:         models.OnboardingStepProfile,
+		title:       "Complete your shop profile",
+		description: "Add a shop name and a description buyers will see.",
+		path:        "/profile",
+		done:        func(s *models.SellerOnboardingState) bool { return s.HasProfile },
+	},
+	{
+		key:         models.OnboardingStepKYC,
+		title:       "Verify your identity",
+		description: "Your shop is activated once an administrator has verified it.",
+		path:        "/verification",
+		done:        func(s *models.SellerOnboardingState) bool { return s.Verified },
+	},
+	{
+		key:         models.OnboardingStepProduct,
+		title:       "List your first product",
+		description: "Create a product so buyers have something to order.",
+		path:        "/products/new",
+		done:        func(s *models.SellerOnboardingState) bool { return s.HasProduct },
+	},
+	{
+		key:         models.OnboardingStepPayout,
+		title:       "Add payout details",
+		description: "Tell us where to send the money from your sales.",
+		path:        "/payouts",
+		done:        func(s *models.SellerOnboardingState) bool { return s.HasPayoutDetails },
+	},
+	{
+		key:         models.OnboardingStepPolicies,
+		title:       "Set your shop policies",
+		description: "Describe how you handle returns and shipping.",
+		path:        "/policies",
+		done:        func(s *models.SellerOnboardingState) bool { return s.HasPolicies },
+	},
+}
+
+type OnboardingController struct {
+	onboardingRepo repository.SellerOnboardingRepo
+	dashboardURL   string
+}
+
+// NewOnboardingController links each step to dashboardURL + its page, such
+// as dashboardURL + "/payouts".
+func NewOnboardingController(onboardingRepo repository.SellerOnboardingRepo, dashboardURL string) *OnboardingController {
+	return &OnboardingController{onboardingRepo: onboardingRepo, dashboardURL: dashboardURL}
+}
+
+// GetOnboarding godoc
+// @Summary Get seller onboarding checklist
+// @Description The steps a seller completes before selling: shop profile, identity verification (KYC), first product, payout details and shop policies. Completion is read from the seller's data, and each step links to the dashboard page where it is done.
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.SellerOnboarding
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/onboarding [get]
+func (oc *OnboardingController) GetOnboarding(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	state, err := oc.onboardingRepo.GetOnboardingState(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.Internal("failed to get seller onboarding")) {
+		return
+	}
+
+	onboarding := models.SellerOnboarding{
+		Steps: make([]models.OnboardingStep, 0, len(onboardingSteps)),
+		Total: len(onboardingSteps),
+	}
+	for _, step := range onboardingSteps {
+		completed := step.done(state)
+		if completed {
+			onboarding.Completed++
+		}
+		onboarding.Steps = append(onboarding.Steps, models.OnboardingStep{
+			Key:         step.key,
+			Title:       step.title,
+			Description: step.description,
+			Completed:   completed,
+			Link:        oc.dashboardURL + step.path,
+		})
+	}
+	onboarding.Done = onboarding.Completed == onboarding.Total
+
+	c.JSON(http.StatusOK, onboarding)
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockOnboardingRepo struct {
+	getStateFn func(ctx context.Context, userID int) (*models.SellerOnboardingState, error)
+}
+
+func (m *mockOnboardingRepo) GetOnboardingState(ctx context.Context, userID int) (*models.SellerOnboardingState, error) {
+	return m.getStateFn(ctx, userID)
+}
+
+var _ repository.SellerOnboardingRepo = (*mockOnboardingRepo)(nil)
+
+func TestOnboardingController_GetOnboarding(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		state         *models.SellerOnboardingState
+		err           error
+		wantCode      int
+		wantCompleted []string
+		wantDone      bool
+	}{
+		{
+			name:     "new seller",
+			state:    &models.SellerOnboardingState{},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:          "partly done",
+			state:         &models.SellerOnboardingState{HasProfile: true, HasPayoutDetails: true},
+			wantCode:      http.StatusOK,
+			wantCompleted: []string{models.OnboardingStepProfile, models.OnboardingStepPayout},
+		},
+		{
+			name:     "all done",
+			state:    &models.SellerOnboardingState{HasProfile: true, Verified: true, HasProduct: true, HasPayoutDetails: true, HasPolicies: true},
+			wantCode: http.StatusOK,
+			wantCompleted: []string{
+				models.OnboardingStepProfile, models.OnboardingStepKYC, models.OnboardingStepProduct,
+				models.OnboardingStepPayout, models.OnboardingStepPolicies,
+			},
+			wantDone: true,
+		},
+		{
+			name:     "repository error",
+			err:      errors.New("db down"),
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("GET", "/api/seller/onboarding", nil)
+			c.Set("user_id", 5)
+
+			oc := NewOnboardingController(&mockOnboardingRepo{
+				getStateFn: func(ctx context.Context, userID int) (*models.SellerOnboardingState, error) {
+					require.Equal(t, 5, userID)
+					return tt.state, tt.err
+				},
+			}, "https://market.example.com/seller")
+			oc.GetOnboarding(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var got models.SellerOnboarding
+			require.NoError(t, json.Unmarshal(r.Body.Bytes(), &got))
+			require.Len(t, got.Steps, 5)
+			require.Equal(t, 5, got.Total)
+			require.Equal(t, len(tt.wantCompleted), got.Completed)
+			require.Equal(t, tt.wantDone, got.Done)
+
+			var completed []string
+			for _, step := range got.Steps {
+				if step.Completed {
+					completed = append(completed, step.Key)
+				}
+			}
+			require.Equal(t, tt.wantCompleted, completed)
+			require.Equal(t, "https://market.example.com/seller/payouts", got.Steps[3].Link)
+		})
+	}
+}
@@ -19,18 +19,23 @@ import (
 
 // mockOrderRepoFull implements OrderRepo interface for order tests
 type mockOrderRepoFull struct {
-	getUserOrdersFn func(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error)
+	getUserOrdersFn func(ctx context.Context, userID int, query string, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error)
 	getByIDFn       func(ctx context.Context, orderID int) (*models.OrderWithItems, error)
+	getByNumberFn   func(ctx context.Context, orderNumber string) (*models.OrderWithItems, error)
 }
 
-func (m *mockOrderRepoFull) GetUserOrders(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error) {
-	return m.getUserOrdersFn(ctx, userID, pagination)
+func (m *mockOrderRepoFull) GetUserOrders(ctx context.Context, userID int, query string, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error) {
+	return m.getUserOrdersFn(ctx, userID, query, pagination)
 }
 
 func (m *mockOrderRepoFull) GetByID(ctx context.Context, orderID int) (*models.OrderWithItems, error) {
 	return m.getByIDFn(ctx, orderID)
 }
 
+func (m *mockOrderRepoFull) GetByNumber(ctx context.Context, orderNumber string) (*models.OrderWithItems, error) {
+	return m.getByNumberFn(ctx, orderNumber)
+}
+
 var _ repository.OrderRepo = (*mockOrderRepoFull)(nil)
 
 func TestMarketController_GetUserOrders_Success(t *testing.T) {
@@ -48,7 +53,7 @@ func TestMarketController_GetUserOrders_Success(t *testing.T) {
 	}
 
 	mOrder := &mockOrderRepoFull{
-		getUserOrdersFn: func(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error) {
+		getUserOrdersFn: func(ctx context.Context, userID int, query string, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error) {
 			require.Equal(t, 42, userID)
 			return orders, 2, nil
 		},
@@ -61,6 +66,38 @@ func TestMarketController_GetUserOrders_Success(t *testing.T) {
 	require.Equal(t, 200, r.Code)
 }
 
+func TestMarketController_GetUserOrders_Query(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, tt := range []struct {
+		name      string
+		query     string
+		wantCode  int
+		wantQuery string
+	}{
+		{name: "trimmed", query: "?q=+MB-2024+", wantCode: 200, wantQuery: "MB-2024"},
+		{name: "none", wantCode: 200},
+		{name: "too long", query: "?q=" + strings.Repeat("a", 101), wantCode: 400},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("GET", "/api/user/orders"+tt.query, nil)
+			c.Set("user_id", 42)
+
+			mc := NewMarketController(nil, nil, nil, &mockOrderRepoFull{
+				getUserOrdersFn: func(ctx context.Context, userID int, query string, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error) {
+					require.Equal(t, tt.wantQuery, query)
+					return []*models.OrderWithItems{}, 0, nil
+				},
+			}, nil)
+			mc.GetUserOrders(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+		})
+	}
+}
+
 func TestMarketController_GetUserOrders_Empty(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := httptest.NewRecorder()
@@ -70,7 +107,7 @@ func TestMarketController_GetUserOrders_Empty(t *testing.T) {
 	c.Set("user_id", 99)
 
 	mOrder := &mockOrderRepoFull{
-		getUserOrdersFn: func(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error) {
+		getUserOrdersFn: func(ctx context.Context, userID int, query string, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error) {
 			return []*models.OrderWithItems{}, 0, nil
 		},
 		getByIDFn: func(ctx context.Context, orderID int) (*models.OrderWithItems, error) { return nil, nil },
@@ -91,7 +128,7 @@ func TestMarketController_GetUserOrders_Error(t *testing.T) {
 	c.Set("user_id", 42)
 
 	mOrder := &mockOrderRepoFull{
-		getUserOrdersFn: func(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error) {
+		getUserOrdersFn: func(ctx context.Context, userID int, query string, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error) {
 			return nil, 0, errors.New("database error")
 		},
 		getByIDFn: func(ctx context.Context, orderID int) (*models.OrderWithItems, error) { return nil, nil },
@@ -130,7 +167,7 @@ func TestMarketController_GetOrder_Success(t *testing.T) {
 	}
 
 	mOrder := &mockOrderRepoFull{
-		getUserOrdersFn: func(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error) {
+		getUserOrdersFn: func(ctx context.Context, userID int, query string, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error) {
 			return nil, 0, nil
 		},
 		getByIDFn: func(ctx context.Context, orderID int) (*models.OrderWithItems, error) {
@@ -156,7 +193,7 @@ func TestMarketController_GetOrder_BadID(t *testing.T) {
 
 	c.Request = httptest.NewRequest("GET", "/api/orders/abc", nil)
 	c.Set("user_id", 42)
-	c.Params = gin.Params{{Key: "id", Value: "abc"}}
+	c.Params = gin.Params{{Key: "id", Value: "abc$%"}}
 
 	mc := NewMarketController(nil, nil, nil, nil, nil)
 	mc.GetOrder(c)
@@ -164,6 +201,31 @@ func TestMarketController_GetOrder_BadID(t *testing.T) {
 	require.Equal(t, 400, r.Code)
 }
 
+func TestMarketController_GetOrder_ByNumber(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+
+	c.Request = httptest.NewRequest("GET", "/api/orders/MB-2024-000123", nil)
+	c.Set("user_id", 42)
+	c.Params = gin.Params{{Key: "id", Value: "MB-2024-000123"}}
+
+	mOrder := &mockOrderRepoFull{
+		getByNumberFn: func(ctx context.Context, orderNumber string) (*models.OrderWithItems, error) {
+			require.Equal(t, "MB-2024-000123", orderNumber)
+			return &models.OrderWithItems{Order: models.Order{ID: 123, OrderNumber: orderNumber, UserID: 42}}, nil
+		},
+	}
+
+	mc := NewMarketController(nil, nil, nil, mOrder, nil)
+	mc.GetOrder(c)
+
+	require.Equal(t, 200, r.Code)
+	var resp models.OrderWithItems
+	require.NoError(t, json.Unmarshal(r.Body.Bytes(), &resp))
+	require.Equal(t, "MB-2024-000123", resp.OrderNumber)
+}
+
 func TestMarketController_GetOrder_NotFound(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := httptest.NewRecorder()
@@ -174,7 +236,7 @@ func TestMarketController_GetOrder_NotFound(t *testing.T) {
 	c.Params = gin.Params{{Key: "id", Value: "999"}}
 
 	mOrder := &mockOrderRepoFull{
-		getUserOrdersFn: func(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error) {
+		getUserOrdersFn: func(ctx context.Context, userID int, query string, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error) {
 			return nil, 0, nil
 		},
 		getByIDFn: func(ctx context.Context, orderID int) (*models.OrderWithItems, error) {
@@ -188,6 +250,66 @@ func TestMarketController_GetOrder_NotFound(t *testing.T) {
 	require.Equal(t, 404, r.Code)
 }
 
+func TestMarketController_GetOrder_OtherUsersOrder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mOrder := &mockOrderRepoFull{
+		getByIDFn: func(ctx context.Context, orderID int) (*models.OrderWithItems, error) {
+			return &models.OrderWithItems{Order: models.Order{ID: orderID, OrderNumber: "MB-2024-000124", UserID: 7, DeliveryAddr: "Elsewhere 1"}}, nil
+		},
+		getByNumberFn: func(ctx context.Context, orderNumber string) (*models.OrderWithItems, error) {
+			return &models.OrderWithItems{Order: models.Order{ID: 124, OrderNumber: orderNumber, UserID: 7, DeliveryAddr: "Elsewhere 1"}}, nil
+		},
+	}
+	mc := NewMarketController(nil, nil, nil, mOrder, nil)
+
+	tests := []struct {
+		name   string
+		id     string
+		userID int
+		role   string
+		code   int
+	}{
+		{"by id", "124", 42, "user", 404},
+		{"by number", "MB-2024-000124", 42, "user", 404},
+		{"admin", "124", 42, "admin", 200},
+		{"no user", "124", 0, "", 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("GET", "/api/orders/"+tt.id, nil)
+			if tt.userID != 0 {
+				c.Set("user_id", tt.userID)
+			}
+			c.Set("role", tt.role)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+			mc.GetOrder(c)
+
+			require.Equal(t, tt.code, r.Code)
+			if tt.code == 404 {
+				require.NotContains(t, r.Body.String(), "Elsewhere")
+			}
+		})
+	}
+}
+
+func TestMarketController_CancelOrder_BadID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+
+	c.Request = httptest.NewRequest("POST", "/api/user/orders/MB-2024-000123/cancel", nil)
+	c.Set("user_id", 42)
+	c.Params = gin.Params{{Key: "id", Value: "MB-2024-000123"}}
+
+	mc := NewMarketController(nil, nil, nil, nil, nil)
+	mc.CancelOrder(c)
+
+	require.Equal(t, 400, r.Code)
+}
+
 // --- Category Tests ---
 
 type mockCategoryRepoFull struct {
@@ -287,7 +409,7 @@ func TestMarketController_GetProduct_Success(t *testing.T) {
 			require.Equal(t, 1, id)
 			return product, nil
 		},
-		getAllFn: func(ctx context.Context, categoryID, sellerID *int, status string, p *models.PaginationParams) ([]*models.ProductWithDetails, int64, error) {
+		getAllFn: func(ctx context.Context, categoryID, sellerID *int, status string, visitorBucket *int, p *models.PaginationParams) ([]*models.ProductWithDetails, int64, error) {
 			return nil, 0, nil
 		},
 	}
@@ -328,7 +450,7 @@ func TestMarketController_GetProduct_NotFound(t *testing.T) {
 		getByIDFn: func(ctx context.Context, id int) (*models.ProductWithDetails, error) {
 			return nil, errors.New("product not found")
 		},
-		getAllFn: func(ctx context.Context, categoryID, sellerID *int, status string, p *models.PaginationParams) ([]*models.ProductWithDetails, int64, error) {
+		getAllFn: func(ctx context.Context, categoryID, sellerID *int, status string, visitorBucket *int, p *models.PaginationParams) ([]*models.ProductWithDetails, int64, error) {
 			return nil, 0, nil
 		},
 	}
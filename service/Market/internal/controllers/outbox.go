@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/mailer"
+	"github.com/gin-gonic/gin"
+)
+
+type OutboxController struct {
+	outbox *mailer.Outbox
+}
+
+func NewOutboxController(outbox *mailer.Outbox) *OutboxController {
+	return &OutboxController{outbox: outbox}
+}
+
+// GetOutbox godoc
+// @Summary List captured emails
+// @Description List the emails captured by the email sandbox instead of being sent, oldest first. Available only with MAIL_SANDBOX, outside production.
+// @Tags dev
+// @Produce json
+// @Param to query string false "Only emails sent to this address"
+// @Success 200 {array} mailer.Message
+// @Router /api/dev/outbox [get]
+func (oc *OutboxController) GetOutbox(c *gin.Context) {
+	c.JSON(http.StatusOK, oc.outbox.Messages(c.Query("to")))
+}
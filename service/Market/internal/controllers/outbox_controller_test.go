@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/mailer"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxController_GetOutbox(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	outbox, err := mailer.NewOutbox("shop@example.com", "", 10)
+	require.NoError(t, err)
+	require.NoError(t, outbox.Send(context.Background(), "a@example.com", "Order MB-1 confirmed", "body"))
+	require.NoError(t, outbox.Send(context.Background(), "b@example.com", "Order MB-2 confirmed", "body"))
+
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/dev/outbox?to=b@example.com", nil)
+
+	NewOutboxController(outbox).GetOutbox(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	var got []mailer.Message
+	require.NoError(t, json.Unmarshal(r.Body.Bytes(), &got))
+	require.Len(t, got, 1)
+	require.Equal(t, "Order MB-2 confirmed", got[0].Subject)
+}
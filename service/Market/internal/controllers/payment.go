@@ -0,0 +1,334 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/payment"
+	"github.com/Zifeldev/marketback/service/Market/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentController struct {
+	paymentService *service.PaymentService
+}
+
+func NewPaymentController(paymentService *service.PaymentService) *PaymentController {
+	return &PaymentController{paymentService: paymentService}
+}
+
+// PayOrder godoc
+// @Summary Pay order
+// @Description Pay the order. A gift card and the buyer's store credit are spent first when given, and the configured payment provider is charged for the rest, each recorded as its own payment. saved_payment_method_id charges a saved payment method without the buyer confirming it; save_method keeps the method the buyer pays with for later. The card payment is paid or failed right away, or pending until the provider confirms it. When the buyer's bank wants the charge authenticated, e.g. by 3-D Secure, it requires action: the buyer follows its action_url, or completes action_type sdk with the client secret, and then confirms the payment. The gift card and store credit are taken when it is paid and given back when it fails. Failed payments may be retried; an order already being paid by another request answers 409. With the sandbox provider, scenario overrides the configured outcome.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param request body models.PayOrderRequest false "Payment options"
+// @Success 201 {object} models.OrderPayments
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/user/orders/{id}/pay [post]
+func (pc *PaymentController) PayOrder(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("order"))
+		return
+	}
+
+	var req models.PayOrderRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, apperrors.BadRequest(err.Error()))
+			return
+		}
+	}
+
+	p, err := pc.paymentService.Pay(c.Request.Context(), userID.(int), orderID, &req)
+	if handleError(c, err, apperrors.Internal("failed to pay order")) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, p)
+}
+
+// ConfirmOrderPayment godoc
+// @Summary Confirm order payment
+// @Description Once the buyer is back from authenticating the order's card charge, ask the payment provider how it went. The payment is paid or failed when the provider says so and left as it is while the charge still waits for the buyer.
+// @Tags orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} models.OrderPayments
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/user/orders/{id}/payments/confirm [post]
+func (pc *PaymentController) ConfirmOrderPayment(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("order"))
+		return
+	}
+
+	p, err := pc.paymentService.ConfirmPayment(c.Request.Context(), userID.(int), orderID)
+	if handleError(c, err, apperrors.Internal("failed to confirm order payment")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+// GetOrderPayments godoc
+// @Summary Get order payments
+// @Description Get every payment of the order, oldest first: the gift card, store credit and card payments of each attempt, and the payment status they add up to.
+// @Tags orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} models.OrderPayments
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/user/orders/{id}/payments [get]
+func (pc *PaymentController) GetOrderPayments(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("order"))
+		return
+	}
+
+	p, err := pc.paymentService.Payments(c.Request.Context(), userID.(int), orderID)
+	if handleError(c, err, apperrors.Internal("failed to get order payments")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+// GetPaymentMethods godoc
+// @Summary Get my payment methods
+// @Description Get the payment methods the provider keeps for the current user, newest first. Only the provider's customer and payment method IDs are stored.
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.SavedPaymentMethod
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/user/payment-methods [get]
+func (pc *PaymentController) GetPaymentMethods(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	methods, err := pc.paymentService.PaymentMethods(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.Internal("failed to get payment methods")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, methods)
+}
+
+// DeletePaymentMethod godoc
+// @Summary Delete payment method
+// @Description Delete one of the current user's saved payment methods at the provider and forget it
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Payment method ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/user/payment-methods/{id} [delete]
+func (pc *PaymentController) DeletePaymentMethod(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("payment method"))
+		return
+	}
+
+	err = pc.paymentService.DeletePaymentMethod(c.Request.Context(), userID.(int), id)
+	if handleError(c, err, apperrors.Internal("failed to delete payment method")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "payment method deleted"})
+}
+
+// RefundOrder godoc
+// @Summary Refund order
+// @Description Refund the order's payments, in full or by amount. The most recent payments are refunded first, so the card before store credit and gift cards, which get the amount back as balance. Partial refunds keep a payment paid until its whole amount is refunded.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param request body models.RefundPaymentRequest false "Refund amount"
+// @Success 200 {object} models.OrderPayments
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/orders/{id}/refund [post]
+func (pc *PaymentController) RefundOrder(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("order"))
+		return
+	}
+
+	var req models.RefundPaymentRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, apperrors.BadRequest(err.Error()))
+			return
+		}
+	}
+
+	p, err := pc.paymentService.Refund(c.Request.Context(), orderID, &req)
+	if handleError(c, err, apperrors.Internal("failed to refund order")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+// CreateChargeback godoc
+// @Summary Record chargeback
+// @Description Record a chargeback the payment provider reported outside its webhook against the order's paid card payment. Its amount is split over the order's sellers in proportion to what they sold in it and held from their payouts; each seller gets a dispute ticket with instructions for submitting evidence and a notification. Recording the same provider reference again returns the recorded chargeback.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateChargebackRequest true "Chargeback"
+// @Success 201 {object} models.Chargeback
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/chargebacks [post]
+func (pc *PaymentController) CreateChargeback(c *gin.Context) {
+	var req models.CreateChargebackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	cb, err := pc.paymentService.OpenChargeback(c.Request.Context(), &req)
+	if handleError(c, err, apperrors.Internal("failed to record chargeback")) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, cb)
+}
+
+// ResolveChargeback godoc
+// @Summary Resolve chargeback
+// @Description Decide an open chargeback. A won chargeback releases the sellers' held amounts to their next statement; a lost one debits them. The sellers' dispute tickets are resolved and the sellers notified.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Chargeback ID"
+// @Param request body models.ResolveChargebackRequest true "Outcome"
+// @Success 200 {object} models.Chargeback
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/chargebacks/{id}/resolve [post]
+func (pc *PaymentController) ResolveChargeback(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("chargeback"))
+		return
+	}
+
+	var req models.ResolveChargebackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	cb, err := pc.paymentService.ResolveChargeback(c.Request.Context(), id, req.Status)
+	if handleError(c, err, apperrors.Internal("failed to resolve chargeback")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, cb)
+}
+
+// SimulatePaymentWebhook godoc
+// @Summary Simulate payment webhook
+// @Description Deliver a provider event for a sandbox payment, as the provider's webhook would. Only pending payments change; repeated events are ignored. Available only with the sandbox provider, outside production.
+// @Tags dev
+// @Accept json
+// @Produce json
+// @Param request body models.PaymentWebhookRequest true "Provider event"
+// @Success 200 {object} models.Payment
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/dev/payments/webhook [post]
+func (pc *PaymentController) SimulatePaymentWebhook(c *gin.Context) {
+	var req models.PaymentWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	p, err := pc.paymentService.HandleEvent(c.Request.Context(), payment.Event{Ref: req.ProviderRef, Status: req.Status})
+	if handleError(c, err, apperrors.Internal("failed to apply payment event")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+// PaymentWebhook godoc
+// @Summary Payment provider webhook
+// @Description Receive a payment event from the configured provider. The delivery's signature is verified against the webhook secret before a pending payment is settled; a paid payment moves its order to paid. Disputes of a card payment are recorded as chargebacks: opening one holds the sellers' shares from their payouts and opens dispute tickets for their evidence, and closing one releases or debits the holds. Other events, and repeated deliveries, are acknowledged and ignored.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Payment
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/webhooks/payment [post]
+func (pc *PaymentController) PaymentWebhook(c *gin.Context) {
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		respondError(c, apperrors.BadRequest("failed to read request body"))
+		return
+	}
+
+	p, err := pc.paymentService.HandleWebhook(c.Request.Context(), payload, c.Request.Header)
+	if handleError(c, err, apperrors.Internal("failed to apply payment event")) {
+		return
+	}
+	if p == nil {
+		c.JSON(http.StatusOK, gin.H{"message": "event ignored"})
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
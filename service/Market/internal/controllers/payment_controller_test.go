@@ -0,0 +1,471 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/payment"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPaymentRepo struct {
+	createFn       func(ctx context.Context, p *models.Payment) (*models.Payment, error)
+	redeemFn       func(ctx context.Context, orderID, userID int, due float64, req *models.PayOrderRequest) ([]*models.Payment, float64, error)
+	getByRefFn     func(ctx context.Context, ref string) (*models.Payment, error)
+	listForOrderFn func(ctx context.Context, orderID int) ([]*models.Payment, error)
+	settleFn       func(ctx context.Context, id int, status string) (*models.Payment, error)
+	addRefundFn    func(ctx context.Context, id int, amount float64) (*models.Payment, error)
+}
+
+func (m *mockPaymentRepo) Create(ctx context.Context, p *models.Payment) (*models.Payment, error) {
+	return m.createFn(ctx, p)
+}
+
+func (m *mockPaymentRepo) Lock(ctx context.Context, orderID int, ttl time.Duration) error {
+	return nil
+}
+
+func (m *mockPaymentRepo) Unlock(ctx context.Context, orderID int) error {
+	return nil
+}
+
+func (m *mockPaymentRepo) Redeem(ctx context.Context, orderID, userID int, due float64, req *models.PayOrderRequest) ([]*models.Payment, float64, error) {
+	if m.redeemFn == nil {
+		return nil, due, nil
+	}
+	return m.redeemFn(ctx, orderID, userID, due, req)
+}
+
+func (m *mockPaymentRepo) GetByRef(ctx context.Context, ref string) (*models.Payment, error) {
+	return m.getByRefFn(ctx, ref)
+}
+
+func (m *mockPaymentRepo) ListForOrder(ctx context.Context, orderID int) ([]*models.Payment, error) {
+	return m.listForOrderFn(ctx, orderID)
+}
+
+func (m *mockPaymentRepo) Settle(ctx context.Context, id int, status string) (*models.Payment, error) {
+	return m.settleFn(ctx, id, status)
+}
+
+func (m *mockPaymentRepo) AddRefund(ctx context.Context, id int, amount float64) (*models.Payment, error) {
+	return m.addRefundFn(ctx, id, amount)
+}
+
+var _ repository.PaymentRepo = (*mockPaymentRepo)(nil)
+
+type mockPaymentMethodRepo struct {
+	listFn   func(ctx context.Context, userID int) ([]*models.SavedPaymentMethod, error)
+	getFn    func(ctx context.Context, id, userID int) (*models.SavedPaymentMethod, error)
+	deleteFn func(ctx context.Context, id, userID int) error
+}
+
+func (m *mockPaymentMethodRepo) GetCustomer(ctx context.Context, userID int, provider string) (string, error) {
+	return "", nil
+}
+
+func (m *mockPaymentMethodRepo) SaveCustomer(ctx context.Context, userID int, provider, customer string) (string, error) {
+	return customer, nil
+}
+
+func (m *mockPaymentMethodRepo) Save(ctx context.Context, method *models.SavedPaymentMethod) (*models.SavedPaymentMethod, error) {
+	return method, nil
+}
+
+func (m *mockPaymentMethodRepo) List(ctx context.Context, userID int) ([]*models.SavedPaymentMethod, error) {
+	return m.listFn(ctx, userID)
+}
+
+func (m *mockPaymentMethodRepo) Get(ctx context.Context, id, userID int) (*models.SavedPaymentMethod, error) {
+	return m.getFn(ctx, id, userID)
+}
+
+func (m *mockPaymentMethodRepo) Delete(ctx context.Context, id, userID int) error {
+	return m.deleteFn(ctx, id, userID)
+}
+
+var _ repository.PaymentMethodRepo = (*mockPaymentMethodRepo)(nil)
+
+type mockChargebackRepo struct {
+	openFn    func(ctx context.Context, cb *models.Chargeback) (*models.Chargeback, error)
+	resolveFn func(ctx context.Context, id int, status string) (*models.Chargeback, error)
+}
+
+func (m *mockChargebackRepo) Open(ctx context.Context, cb *models.Chargeback) (*models.Chargeback, error) {
+	return m.openFn(ctx, cb)
+}
+
+func (m *mockChargebackRepo) GetByID(ctx context.Context, id int) (*models.Chargeback, error) {
+	return nil, apperrors.NotFound("chargeback not found")
+}
+
+func (m *mockChargebackRepo) GetByRef(ctx context.Context, provider, ref string) (*models.Chargeback, error) {
+	return nil, nil
+}
+
+func (m *mockChargebackRepo) Resolve(ctx context.Context, id int, status string) (*models.Chargeback, error) {
+	return m.resolveFn(ctx, id, status)
+}
+
+var _ repository.ChargebackRepo = (*mockChargebackRepo)(nil)
+
+func newTestPaymentController(payments *mockPaymentRepo) *PaymentController {
+	return newTestVaultController(payments, &mockPaymentMethodRepo{})
+}
+
+func newTestVaultController(payments *mockPaymentRepo, methods *mockPaymentMethodRepo) *PaymentController {
+	return newTestChargebackController(payments, methods, &mockChargebackRepo{})
+}
+
+func newTestChargebackController(payments *mockPaymentRepo, methods *mockPaymentMethodRepo, chargebacks *mockChargebackRepo) *PaymentController {
+	orders := &mockOrderRepoFull{
+		getByIDFn: func(ctx context.Context, orderID int) (*models.OrderWithItems, error) {
+			return &models.OrderWithItems{Order: models.Order{ID: orderID, OrderNumber: "MB-2024-000009", UserID: 3, TotalAmount: 20, Status: "pending"}}, nil
+		},
+	}
+	sandbox := payment.NewSandbox(payment.ScenarioSucceed, time.Hour)
+	return NewPaymentController(service.NewPaymentService(sandbox, payments, methods, orders, chargebacks, ""))
+}
+
+func TestPaymentController_PayOrder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		param      string
+		body       string
+		wantCode   int
+		wantStatus string
+	}{
+		{name: "no body", param: "9", wantCode: http.StatusCreated, wantStatus: models.PaymentStatusPaid},
+		{name: "sandbox scenario", param: "9", body: `{"scenario":"fail"}`, wantCode: http.StatusCreated, wantStatus: models.PaymentStatusFailed},
+		{name: "unknown scenario", param: "9", body: `{"scenario":"explode"}`, wantCode: http.StatusBadRequest},
+		{name: "invalid id", param: "abc", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("POST", "/api/user/orders/"+tt.param+"/pay", bytes.NewBufferString(tt.body))
+			c.Params = gin.Params{{Key: "id", Value: tt.param}}
+			c.Set("user_id", 3)
+
+			var payments []*models.Payment
+			pc := newTestPaymentController(&mockPaymentRepo{
+				listForOrderFn: func(ctx context.Context, orderID int) ([]*models.Payment, error) { return payments, nil },
+				createFn: func(ctx context.Context, p *models.Payment) (*models.Payment, error) {
+					require.Equal(t, 9, p.OrderID)
+					require.Equal(t, 20.0, p.Amount)
+					payments = append(payments, p)
+					return p, nil
+				},
+			})
+			pc.PayOrder(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+			if tt.wantStatus != "" {
+				var got models.OrderPayments
+				require.NoError(t, json.Unmarshal(r.Body.Bytes(), &got))
+				require.Len(t, got.Payments, 1)
+				require.Equal(t, tt.wantStatus, got.Payments[0].Status)
+				require.Equal(t, tt.wantStatus, got.PaymentStatus)
+			}
+		})
+	}
+}
+
+func TestPaymentController_RefundOrder_ExceedsPaid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("POST", "/api/admin/orders/9/refund", bytes.NewBufferString(`{"amount":15}`))
+	c.Params = gin.Params{{Key: "id", Value: "9"}}
+
+	pc := newTestPaymentController(&mockPaymentRepo{
+		listForOrderFn: func(ctx context.Context, orderID int) ([]*models.Payment, error) {
+			return []*models.Payment{
+				{ID: 1, OrderID: orderID, Method: models.PaymentMethodCard, Amount: 20, RefundedAmount: 10, Status: models.PaymentStatusPaid},
+			}, nil
+		},
+	})
+	pc.RefundOrder(c)
+
+	require.Equal(t, http.StatusBadRequest, r.Code)
+	require.Contains(t, r.Body.String(), "refundable amount of 10.00")
+}
+
+func TestPaymentController_CreateChargeback(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{name: "holds the card payment", body: `{"order_id":9,"provider_ref":"dp_1","amount":20,"reason":"fraudulent"}`, wantCode: http.StatusCreated},
+		{name: "more than was paid", body: `{"order_id":9,"provider_ref":"dp_1","amount":25}`, wantCode: http.StatusBadRequest},
+		{name: "missing reference", body: `{"order_id":9,"amount":20}`, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("POST", "/api/admin/chargebacks", bytes.NewBufferString(tt.body))
+
+			var opened *models.Chargeback
+			pc := newTestChargebackController(&mockPaymentRepo{
+				listForOrderFn: func(ctx context.Context, orderID int) ([]*models.Payment, error) {
+					return []*models.Payment{
+						{ID: 4, OrderID: orderID, Method: models.PaymentMethodCard, Provider: "stripe", Amount: 20, Status: models.PaymentStatusPaid},
+					}, nil
+				},
+			}, &mockPaymentMethodRepo{}, &mockChargebackRepo{
+				openFn: func(ctx context.Context, cb *models.Chargeback) (*models.Chargeback, error) {
+					opened = cb
+					cb.ID, cb.Status = 1, models.ChargebackStatusOpen
+					return cb, nil
+				},
+			})
+			pc.CreateChargeback(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+			if tt.wantCode == http.StatusCreated {
+				require.Equal(t, 4, opened.PaymentID)
+				require.Equal(t, "stripe", opened.Provider)
+				require.Equal(t, "fraudulent", opened.Reason)
+			}
+		})
+	}
+}
+
+func TestPaymentController_ResolveChargeback(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		param    string
+		body     string
+		wantCode int
+	}{
+		{name: "lost", param: "1", body: `{"status":"lost"}`, wantCode: http.StatusOK},
+		{name: "already decided", param: "2", body: `{"status":"won"}`, wantCode: http.StatusConflict},
+		{name: "invalid status", param: "1", body: `{"status":"open"}`, wantCode: http.StatusBadRequest},
+		{name: "invalid id", param: "abc", body: `{"status":"won"}`, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("POST", "/api/admin/chargebacks/"+tt.param+"/resolve", bytes.NewBufferString(tt.body))
+			c.Params = gin.Params{{Key: "id", Value: tt.param}}
+
+			pc := newTestChargebackController(&mockPaymentRepo{}, &mockPaymentMethodRepo{}, &mockChargebackRepo{
+				resolveFn: func(ctx context.Context, id int, status string) (*models.Chargeback, error) {
+					if id == 2 {
+						return nil, apperrors.Conflict("chargeback is already lost")
+					}
+					return &models.Chargeback{ID: id, Status: status}, nil
+				},
+			})
+			pc.ResolveChargeback(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+		})
+	}
+}
+
+func TestPaymentController_SimulatePaymentWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{name: "settles pending payment", body: `{"provider_ref":"sandbox_a","status":"paid"}`, wantCode: http.StatusOK},
+		{name: "unknown payment", body: `{"provider_ref":"sandbox_b","status":"paid"}`, wantCode: http.StatusNotFound},
+		{name: "invalid status", body: `{"provider_ref":"sandbox_a","status":"refunded"}`, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("POST", "/api/dev/payments/webhook", bytes.NewBufferString(tt.body))
+
+			pc := newTestPaymentController(&mockPaymentRepo{
+				getByRefFn: func(ctx context.Context, ref string) (*models.Payment, error) {
+					if ref != "sandbox_a" {
+						return nil, nil
+					}
+					return &models.Payment{ID: 1, Method: models.PaymentMethodCard, ProviderRef: ref, Status: models.PaymentStatusPending}, nil
+				},
+				settleFn: func(ctx context.Context, id int, status string) (*models.Payment, error) {
+					return &models.Payment{ID: id, ProviderRef: "sandbox_a", Status: status}, nil
+				},
+			})
+			pc.SimulatePaymentWebhook(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+		})
+	}
+}
+
+func TestPaymentController_GetOrderPayments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		userID   int
+		wantCode int
+	}{
+		{name: "own order", userID: 3, wantCode: http.StatusOK},
+		{name: "someone else's order", userID: 4, wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("GET", "/api/user/orders/9/payments", nil)
+			c.Params = gin.Params{{Key: "id", Value: "9"}}
+			c.Set("user_id", tt.userID)
+
+			pc := newTestPaymentController(&mockPaymentRepo{
+				listForOrderFn: func(ctx context.Context, orderID int) ([]*models.Payment, error) {
+					return []*models.Payment{
+						{ID: 1, OrderID: orderID, Method: models.PaymentMethodGiftCard, Amount: 5, Status: models.PaymentStatusPaid},
+						{ID: 2, OrderID: orderID, Method: models.PaymentMethodCard, Amount: 15, Status: models.PaymentStatusPaid},
+					}, nil
+				},
+			})
+			pc.GetOrderPayments(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+			if tt.wantCode == http.StatusOK {
+				var got models.OrderPayments
+				require.NoError(t, json.Unmarshal(r.Body.Bytes(), &got))
+				require.Equal(t, models.PaymentStatusPaid, got.PaymentStatus)
+				require.Len(t, got.Payments, 2)
+			}
+		})
+	}
+}
+
+func TestPaymentController_ConfirmOrderPayment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		param    string
+		status   string
+		wantCode int
+	}{
+		{name: "still waiting for the buyer", param: "9", status: models.PaymentStatusRequiresAction, wantCode: http.StatusOK},
+		{name: "nothing to confirm", param: "9", status: models.PaymentStatusPaid, wantCode: http.StatusConflict},
+		{name: "invalid order", param: "abc", status: models.PaymentStatusRequiresAction, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("POST", "/api/user/orders/"+tt.param+"/payments/confirm", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.param}}
+			c.Set("user_id", 3)
+
+			pc := newTestPaymentController(&mockPaymentRepo{
+				listForOrderFn: func(ctx context.Context, orderID int) ([]*models.Payment, error) {
+					return []*models.Payment{
+						{ID: 1, OrderID: orderID, Method: models.PaymentMethodCard, ProviderRef: "sandbox_x", Amount: 20, Status: tt.status},
+					}, nil
+				},
+			})
+			pc.ConfirmOrderPayment(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+			if tt.wantCode == http.StatusOK {
+				var got models.OrderPayments
+				require.NoError(t, json.Unmarshal(r.Body.Bytes(), &got))
+				require.Equal(t, models.PaymentStatusPending, got.PaymentStatus)
+			}
+		})
+	}
+}
+
+func TestPaymentController_GetPaymentMethods(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/user/payment-methods", nil)
+	c.Set("user_id", 3)
+
+	pc := newTestVaultController(&mockPaymentRepo{}, &mockPaymentMethodRepo{
+		listFn: func(ctx context.Context, userID int) ([]*models.SavedPaymentMethod, error) {
+			require.Equal(t, 3, userID)
+			return []*models.SavedPaymentMethod{{ID: 1, UserID: 3, Provider: "sandbox", CustomerRef: "sandbox_cus_1", MethodRef: "sandbox_pm_1"}}, nil
+		},
+	})
+	pc.GetPaymentMethods(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	var got []models.SavedPaymentMethod
+	require.NoError(t, json.Unmarshal(r.Body.Bytes(), &got))
+	require.Len(t, got, 1)
+	require.Equal(t, "sandbox_pm_1", got[0].MethodRef)
+}
+
+func TestPaymentController_DeletePaymentMethod(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		param    string
+		wantCode int
+	}{
+		{name: "deleted", param: "1", wantCode: http.StatusOK},
+		{name: "someone else's", param: "2", wantCode: http.StatusNotFound},
+		{name: "invalid id", param: "abc", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("DELETE", "/api/user/payment-methods/"+tt.param, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.param}}
+			c.Set("user_id", 3)
+
+			var deleted bool
+			pc := newTestVaultController(&mockPaymentRepo{}, &mockPaymentMethodRepo{
+				getFn: func(ctx context.Context, id, userID int) (*models.SavedPaymentMethod, error) {
+					if id != 1 {
+						return nil, apperrors.NotFound("payment method not found")
+					}
+					return &models.SavedPaymentMethod{ID: id, UserID: userID, Provider: "sandbox", MethodRef: "sandbox_pm_1"}, nil
+				},
+				deleteFn: func(ctx context.Context, id, userID int) error {
+					deleted = true
+					return nil
+				},
+			})
+			pc.DeletePaymentMethod(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+			require.Equal(t, tt.wantCode == http.StatusOK, deleted)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/payouts"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type PayoutController struct {
+	payoutRepo repository.PayoutRepo
+	methods    []string
+}
+
+// NewPayoutController takes the payout methods the payment provider
+// supports; sellers can only register accounts it can pay out to.
+func NewPayoutController(payoutRepo repository.PayoutRepo, methods []string) *PayoutController {
+	return &PayoutController{payoutRepo: payoutRepo, methods: methods}
+}
+
+// SetPayoutAccount godoc
+// @Summary Set payout account
+// @Description Register where the seller's payouts are sent: an IBAN for bank_transfer or an acct_ ID for stripe_connect. Replaces the account on file; only the last four characters are ever returned.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.SetPayoutAccountRequest true "Payout account"
+// @Success 200 {object} models.PayoutAccount
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/payout-account [put]
+func (pc *PayoutController) SetPayoutAccount(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.SetPayoutAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	account, err := payouts.NormalizeAccount(req.Method, req.Account, pc.methods)
+	if err != nil {
+		respondError(c, apperrors.ValidationError("account", err.Error()))
+		return
+	}
+	req.Account = account
+
+	saved, err := pc.payoutRepo.SetAccount(c.Request.Context(), userID.(int), &req)
+	if handleError(c, err, apperrors.Internal("failed to save payout account")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, saved)
+}
+
+// GetPayoutAccount godoc
+// @Summary Get payout account
+// @Description Get the seller's payout account, with only the last four characters of the account
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.PayoutAccount
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/payout-account [get]
+func (pc *PayoutController) GetPayoutAccount(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	account, err := pc.payoutRepo.GetAccount(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.Internal("failed to get payout account")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// GetPayouts godoc
+// @Summary Get seller payouts
+// @Description List the payouts sent for the seller's statements, newest first, with the provider's reference once paid and the last error while retried
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/payouts [get]
+func (pc *PayoutController) GetPayouts(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		pagination = models.PaginationParams{Page: 1, PageSize: models.DefaultPageSize}
+	}
+	if pagination.Page < 1 {
+		pagination.Page = 1
+	}
+
+	list, totalItems, err := pc.payoutRepo.ListForSeller(c.Request.Context(), userID.(int), &pagination)
+	if handleError(c, err, apperrors.Internal("failed to get payouts")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Data:       list,
+		Pagination: models.NewPaginationMeta(pagination.Page, pagination.GetLimit(), totalItems),
+	})
+}
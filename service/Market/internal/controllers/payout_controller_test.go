@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPayoutRepo struct {
+	setFn  func(ctx context.Context, userID int, req *models.SetPayoutAccountRequest) (*models.PayoutAccount, error)
+	getFn  func(ctx context.Context, userID int) (*models.PayoutAccount, error)
+	listFn func(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.Payout, int64, error)
+}
+
+func (m *mockPayoutRepo) SetAccount(ctx context.Context, userID int, req *models.SetPayoutAccountRequest) (*models.PayoutAccount, error) {
+	return m.setFn(ctx, userID, req)
+}
+
+func (m *mockPayoutRepo) GetAccount(ctx context.Context, userID int) (*models.PayoutAccount, error) {
+	return m.getFn(ctx, userID)
+}
+
+func (m *mockPayoutRepo) ListForSeller(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.Payout, int64, error) {
+	return m.listFn(ctx, userID, pagination)
+}
+
+var _ repository.PayoutRepo = (*mockPayoutRepo)(nil)
+
+var bothPayoutMethods = []string{models.PayoutMethodBankTransfer, models.PayoutMethodStripeConnect}
+
+func TestPayoutController_SetPayoutAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("PUT", "/api/seller/payout-account",
+		bytes.NewBufferString(`{"method":"bank_transfer","holder_name":"Café Shop GmbH","account":"de89 3704 0044 0532 0130 00"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", 8)
+
+	m := &mockPayoutRepo{
+		setFn: func(ctx context.Context, userID int, req *models.SetPayoutAccountRequest) (*models.PayoutAccount, error) {
+			require.Equal(t, 8, userID)
+			require.Equal(t, "DE89370400440532013000", req.Account)
+			return &models.PayoutAccount{SellerID: 3, Method: req.Method, HolderName: req.HolderName, AccountHint: "3000"}, nil
+		},
+	}
+
+	NewPayoutController(m, bothPayoutMethods).SetPayoutAccount(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	require.Contains(t, r.Body.String(), `"account_hint":"3000"`)
+	require.NotContains(t, r.Body.String(), "DE89")
+}
+
+func TestPayoutController_SetPayoutAccount_Invalid(t *testing.T) {
+	for name, body := range map[string]string{
+		"bad iban":    `{"method":"bank_transfer","holder_name":"Shop","account":"DE89370400440532013001"}`,
+		"unsupported": `{"method":"bank_transfer","holder_name":"Shop","account":"DE89370400440532013000"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("PUT", "/api/seller/payout-account", bytes.NewBufferString(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("user_id", 8)
+
+			methods := bothPayoutMethods
+			if name == "unsupported" {
+				methods = []string{models.PayoutMethodStripeConnect}
+			}
+			NewPayoutController(&mockPayoutRepo{}, methods).SetPayoutAccount(c)
+
+			require.Equal(t, http.StatusBadRequest, r.Code)
+			require.Contains(t, r.Body.String(), "account")
+		})
+	}
+}
+
+func TestPayoutController_GetPayoutAccount_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/seller/payout-account", nil)
+	c.Set("user_id", 8)
+
+	m := &mockPayoutRepo{
+		getFn: func(ctx context.Context, userID int) (*models.PayoutAccount, error) {
+			return nil, apperrors.NotFound("no payout account on file")
+		},
+	}
+
+	NewPayoutController(m, bothPayoutMethods).GetPayoutAccount(c)
+
+	require.Equal(t, http.StatusNotFound, r.Code)
+}
+
+func TestPayoutController_GetPayouts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/seller/payouts?page=2&page_size=5", nil)
+	c.Set("user_id", 8)
+
+	ref := "tr_1"
+	m := &mockPayoutRepo{
+		listFn: func(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.Payout, int64, error) {
+			require.Equal(t, 2, pagination.Page)
+			return []*models.Payout{{ID: 1, Amount: 90, Status: models.PayoutStatusPaid, ProviderRef: &ref}}, 6, nil
+		},
+	}
+
+	NewPayoutController(m, bothPayoutMethods).GetPayouts(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	require.Contains(t, r.Body.String(), `"provider_ref":"tr_1"`)
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const pickupQRSize = 256
+
+type PickupController struct {
+	deliveryRepo repository.DeliveryRepo
+}
+
+func NewPickupController(deliveryRepo repository.DeliveryRepo) *PickupController {
+	return &PickupController{deliveryRepo: deliveryRepo}
+}
+
+// GetPickupQR godoc
+// @Summary Get order pickup QR code
+// @Description Get the QR code the buyer shows at pickup or cash-on-delivery handover. Returns a PNG, or the raw code with format=json.
+// @Tags orders
+// @Produce png
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param format query string false "png (default) or json"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/user/orders/{id}/pickup-qr [get]
+func (pc *PickupController) GetPickupQR(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("order"))
+		return
+	}
+
+	format := c.DefaultQuery("format", "png")
+	if format != "png" && format != "json" {
+		respondError(c, apperrors.ValidationError("format", "must be png or json"))
+		return
+	}
+
+	code, err := pc.deliveryRepo.PickupCode(c.Request.Context(), orderID, userID.(int))
+	if handleError(c, err, apperrors.Internal("failed to get pickup code")) {
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, gin.H{"order_id": orderID, "code": code})
+		return
+	}
+
+	png, err := qrcode.Encode(code, qrcode.Medium, pickupQRSize)
+	if handleError(c, err, apperrors.Internal("failed to render pickup QR code")) {
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// VerifyPickup godoc
+// @Summary Verify pickup QR code
+// @Description Scan a buyer's pickup QR code and mark the order handed over. Each order can be handed over once; couriers may verify orders assigned to them, sellers orders containing their products.
+// @Tags delivery
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.VerifyPickupRequest true "Scanned code"
+// @Success 200 {object} models.Handover
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/courier/handover [post]
+// @Router /api/seller/orders/handover [post]
+func (pc *PickupController) VerifyPickup(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	role, _ := c.Get("role")
+
+	var req models.VerifyPickupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	orderID, err := pc.deliveryRepo.OrderIDByPickupCode(c.Request.Context(), req.Code)
+	if handleError(c, err, apperrors.Internal("failed to verify pickup code")) {
+		return
+	}
+
+	var allowed bool
+	switch role {
+	case "courier":
+		allowed, err = pc.deliveryRepo.IsAssignedCourier(c.Request.Context(), orderID, userID.(int))
+	case "seller":
+		allowed, err = pc.deliveryRepo.HasSellerItems(c.Request.Context(), orderID, userID.(int))
+	case "admin":
+		allowed = true
+	}
+	if handleError(c, err, apperrors.Internal("failed to check order access")) {
+		return
+	}
+	if !allowed {
+		respondError(c, apperrors.Forbidden("order not found or access denied"))
+		return
+	}
+
+	handover, err := pc.deliveryRepo.MarkHandedOver(c.Request.Context(), orderID, userID.(int), fmt.Sprintf("%v", role))
+	if handleError(c, err, apperrors.Internal("failed to mark order handed over")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, handover)
+}
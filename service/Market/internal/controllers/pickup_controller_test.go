@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func verifyRequest(code string) *http.Request {
+	body, _ := json.Marshal(models.VerifyPickupRequest{Code: code})
+	req := httptest.NewRequest("POST", "/api/courier/handover", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestPickupController_GetPickupQR_PNG(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/user/orders/7/pickup-qr", nil)
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set("user_id", 3)
+
+	m := &mockDeliveryRepo{
+		pickupFn: func(ctx context.Context, orderID, userID int) (string, error) {
+			require.Equal(t, 7, orderID)
+			require.Equal(t, 3, userID)
+			return "abc123", nil
+		},
+	}
+
+	NewPickupController(m).GetPickupQR(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	require.Equal(t, "image/png", r.Header().Get("Content-Type"))
+	require.True(t, bytes.HasPrefix(r.Body.Bytes(), []byte("\x89PNG")))
+}
+
+func TestPickupController_GetPickupQR_JSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/user/orders/7/pickup-qr?format=json", nil)
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set("user_id", 3)
+
+	m := &mockDeliveryRepo{
+		pickupFn: func(ctx context.Context, orderID, userID int) (string, error) {
+			return "abc123", nil
+		},
+	}
+
+	NewPickupController(m).GetPickupQR(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	require.Contains(t, r.Body.String(), `"code":"abc123"`)
+}
+
+func TestPickupController_GetPickupQR_NotOwner(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/user/orders/7/pickup-qr", nil)
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set("user_id", 4)
+
+	m := &mockDeliveryRepo{
+		pickupFn: func(ctx context.Context, orderID, userID int) (string, error) {
+			return "", apperrors.OrderNotFound(orderID)
+		},
+	}
+
+	NewPickupController(m).GetPickupQR(c)
+
+	require.Equal(t, http.StatusNotFound, r.Code)
+}
+
+func TestPickupController_VerifyPickup_Courier(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = verifyRequest("abc123")
+	c.Set("user_id", 5)
+	c.Set("role", "courier")
+
+	m := &mockDeliveryRepo{
+		courierOK: true,
+		byCodeFn: func(ctx context.Context, code string) (int, error) {
+			require.Equal(t, "abc123", code)
+			return 7, nil
+		},
+		handOverFn: func(ctx context.Context, orderID, userID int, role string) (*models.Handover, error) {
+			require.Equal(t, 7, orderID)
+			require.Equal(t, 5, userID)
+			require.Equal(t, "courier", role)
+			return &models.Handover{OrderID: orderID, HandedOverBy: userID, HandedOverRole: role}, nil
+		},
+	}
+
+	NewPickupController(m).VerifyPickup(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+}
+
+func TestPickupController_VerifyPickup_NotAssigned(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = verifyRequest("abc123")
+	c.Set("user_id", 5)
+	c.Set("role", "courier")
+
+	m := &mockDeliveryRepo{
+		byCodeFn: func(ctx context.Context, code string) (int, error) { return 7, nil },
+	}
+
+	NewPickupController(m).VerifyPickup(c)
+
+	require.Equal(t, http.StatusForbidden, r.Code)
+}
+
+func TestPickupController_VerifyPickup_AlreadyHandedOver(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = verifyRequest("abc123")
+	c.Set("user_id", 9)
+	c.Set("role", "seller")
+
+	m := &mockDeliveryRepo{
+		sellerOK: true,
+		byCodeFn: func(ctx context.Context, code string) (int, error) { return 7, nil },
+		handOverFn: func(ctx context.Context, orderID, userID int, role string) (*models.Handover, error) {
+			return nil, apperrors.Conflict("order has already been handed over")
+		},
+	}
+
+	NewPickupController(m).VerifyPickup(c)
+
+	require.Equal(t, http.StatusConflict, r.Code)
+}
+
+func TestPickupController_VerifyPickup_UnknownCode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = verifyRequest("nope")
+	c.Set("user_id", 5)
+	c.Set("role", "courier")
+
+	m := &mockDeliveryRepo{
+		byCodeFn: func(ctx context.Context, code string) (int, error) {
+			return 0, apperrors.NotFound("unknown pickup code")
+		},
+	}
+
+	NewPickupController(m).VerifyPickup(c)
+
+	require.Equal(t, http.StatusNotFound, r.Code)
+}
@@ -16,16 +16,20 @@ import (
 
 // mockProductRepo implements ProductRepo for tests
 type mockProductRepo struct {
-	getAllFn  func(ctx context.Context, categoryID, sellerID *int, status string, p *models.PaginationParams) ([]*models.ProductWithDetails, int64, error)
+	getAllFn  func(ctx context.Context, categoryID, sellerID *int, status string, visitorBucket *int, p *models.PaginationParams) ([]*models.ProductWithDetails, int64, error)
 	getByIDFn func(ctx context.Context, id int) (*models.ProductWithDetails, error)
+	getByIDs  func(ctx context.Context, ids []int) (map[int]*models.ProductWithDetails, error)
 }
 
-func (m *mockProductRepo) GetAll(ctx context.Context, categoryID, sellerID *int, status string, p *models.PaginationParams) ([]*models.ProductWithDetails, int64, error) {
-	return m.getAllFn(ctx, categoryID, sellerID, status, p)
+func (m *mockProductRepo) GetAll(ctx context.Context, categoryID, sellerID *int, status string, visitorBucket *int, p *models.PaginationParams) ([]*models.ProductWithDetails, int64, error) {
+	return m.getAllFn(ctx, categoryID, sellerID, status, visitorBucket, p)
 }
 func (m *mockProductRepo) GetByID(ctx context.Context, id int) (*models.ProductWithDetails, error) {
 	return m.getByIDFn(ctx, id)
 }
+func (m *mockProductRepo) GetByIDs(ctx context.Context, ids []int) (map[int]*models.ProductWithDetails, error) {
+	return m.getByIDs(ctx, ids)
+}
 
 var _ repository.ProductRepo = (*mockProductRepo)(nil)
 
@@ -50,12 +54,15 @@ type mockOrderRepo struct {
 	getByIDFn func(ctx context.Context, orderID int) (*models.OrderWithItems, error)
 }
 
-func (m *mockOrderRepo) GetUserOrders(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error) {
+func (m *mockOrderRepo) GetUserOrders(ctx context.Context, userID int, query string, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error) {
 	return m.getUserFn(ctx, userID, pagination)
 }
 func (m *mockOrderRepo) GetByID(ctx context.Context, orderID int) (*models.OrderWithItems, error) {
 	return m.getByIDFn(ctx, orderID)
 }
+func (m *mockOrderRepo) GetByNumber(ctx context.Context, orderNumber string) (*models.OrderWithItems, error) {
+	return nil, nil
+}
 
 var _ repository.OrderRepo = (*mockOrderRepo)(nil)
 
@@ -67,15 +74,18 @@ func TestMarketController_GetProducts_PaginationAndFilters(t *testing.T) {
 	// Query params
 	req := httptest.NewRequest("GET", "/api/products?category_id=5&seller_id=9&status=active&page=2&page_size=3", nil)
 	c.Request = req
+	c.Set("visitor_bucket", 4)
 
 	prod := &models.ProductWithDetails{Product: models.Product{ID: 101, SellerID: 9, CategoryID: 5, Title: "Boots", Price: 77.7, CreatedAt: time.Now(), UpdatedAt: time.Now()}}
 	var capturedCat, capturedSeller *int
 	var capturedStatus string
+	var capturedBucket *int
 	var capturedPage, capturedLimit int
 
-	mProd := &mockProductRepo{getAllFn: func(ctx context.Context, categoryID, sellerID *int, status string, p *models.PaginationParams) ([]*models.ProductWithDetails, int64, error) {
+	mProd := &mockProductRepo{getAllFn: func(ctx context.Context, categoryID, sellerID *int, status string, visitorBucket *int, p *models.PaginationParams) ([]*models.ProductWithDetails, int64, error) {
 		capturedCat, capturedSeller = categoryID, sellerID
 		capturedStatus = status
+		capturedBucket = visitorBucket
 		capturedPage = p.Page
 		capturedLimit = p.GetLimit()
 		return []*models.ProductWithDetails{prod}, 11, nil // totalItems=11
@@ -95,6 +105,8 @@ func TestMarketController_GetProducts_PaginationAndFilters(t *testing.T) {
 	require.NotNil(t, capturedCat)
 	require.NotNil(t, capturedSeller)
 	require.Equal(t, "active", capturedStatus)
+	require.NotNil(t, capturedBucket)
+	require.Equal(t, 4, *capturedBucket)
 	require.Equal(t, 2, capturedPage)
 	require.Equal(t, 3, capturedLimit)
 
@@ -117,7 +129,7 @@ func TestMarketController_GetProducts_DefaultPagination(t *testing.T) {
 	// No page_size/page
 	req := httptest.NewRequest("GET", "/api/products", nil)
 	c.Request = req
-	mProd := &mockProductRepo{getAllFn: func(ctx context.Context, categoryID, sellerID *int, status string, p *models.PaginationParams) ([]*models.ProductWithDetails, int64, error) {
+	mProd := &mockProductRepo{getAllFn: func(ctx context.Context, categoryID, sellerID *int, status string, visitorBucket *int, p *models.PaginationParams) ([]*models.ProductWithDetails, int64, error) {
 		if p.Page == 0 {
 			p.Page = 1
 		} // mirror controller's implicit sanitation
@@ -137,5 +149,39 @@ func TestMarketController_GetProducts_DefaultPagination(t *testing.T) {
 	require.Equal(t, 200, r.Code)
 }
 
+func TestMarketController_GetProducts_EstimatedCount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/products?count=estimate", nil)
+
+	mProd := &mockProductRepo{getAllFn: func(ctx context.Context, categoryID, sellerID *int, status string, visitorBucket *int, p *models.PaginationParams) ([]*models.ProductWithDetails, int64, error) {
+		require.True(t, p.EstimateCount())
+		return []*models.ProductWithDetails{}, 250000, nil
+	}}
+	mc := NewMarketController(mProd, &mockCategoryRepo{}, nil, &mockOrderRepo{}, nil)
+	mc.GetProducts(c)
+
+	require.Equal(t, 200, r.Code)
+	var resp struct {
+		Pagination models.PaginationMeta `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(r.Body.Bytes(), &resp))
+	require.Equal(t, int64(250000), resp.Pagination.TotalItems)
+	require.True(t, resp.Pagination.Estimated)
+}
+
+func TestMarketController_GetProducts_InvalidCountMode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/products?count=fast", nil)
+
+	mc := NewMarketController(&mockProductRepo{}, &mockCategoryRepo{}, nil, &mockOrderRepo{}, nil)
+	mc.GetProducts(c)
+
+	require.Equal(t, 400, r.Code)
+}
+
 // helper to silence unused import of strconv in case future tests use conversions
 var _ = strconv.Atoi
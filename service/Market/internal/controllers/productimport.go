@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/money"
+	"github.com/Zifeldev/marketback/service/Market/internal/productimport"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// extensionParser names products sent by the browser extension.
+	extensionParser = "extension"
+	maxTitleLength  = 255
+	maxImageURL     = 500
+)
+
+// productFetcher reads a product from a page on another shop.
+type productFetcher interface {
+	Fetch(ctx context.Context, rawURL string) (*models.ScrapedProduct, string, error)
+}
+
+type importSellers interface {
+	GetByUserID(ctx context.Context, userID int) (*models.Seller, error)
+}
+
+type importProducts interface {
+	Create(ctx context.Context, sellerID int, req *models.CreateProductRequest) (*models.Product, error)
+}
+
+type ProductImportController struct {
+	fetcher  productFetcher
+	sellers  importSellers
+	products importProducts
+}
+
+func NewProductImportController(fetcher productFetcher, sellers importSellers, products importProducts) *ProductImportController {
+	return &ProductImportController{fetcher: fetcher, sellers: sellers, products: products}
+}
+
+// ImportProductURL godoc
+// @Summary Import product from URL
+// @Description Create a draft product from another shop's product page. The page is fetched and its title, description, price and images are read from its schema.org or Open Graph data; the browser extension may send the product it read instead. The draft has no stock and waits for review like any new product; images stay hosted at the source, with all of them listed for the seller to pick from. Prices are taken as-is, without currency conversion.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ImportProductRequest true "Page URL or product read by the extension"
+// @Success 201 {object} models.ProductImport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/products/import-url [post]
+func (pc *ProductImportController) ImportProductURL(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	seller, err := pc.sellers.GetByUserID(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.Forbidden("seller profile not found")) {
+		return
+	}
+
+	var req models.ImportProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	scraped, parser := req.Product, extensionParser
+	if scraped == nil {
+		scraped, parser, err = pc.fetcher.Fetch(c.Request.Context(), req.URL)
+		if err != nil {
+			respondError(c, importError(c, err))
+			return
+		}
+	}
+
+	draft := &models.CreateProductRequest{
+		CategoryID:  req.CategoryID,
+		Title:       truncate(scraped.Title, maxTitleLength),
+		Description: scraped.Description,
+		Price:       money.FromFloat(scraped.Price).Float(),
+	}
+	for _, img := range scraped.Images {
+		if len(img) <= maxImageURL {
+			draft.ImageURL = img
+			break
+		}
+	}
+
+	product, err := pc.products.Create(c.Request.Context(), seller.ID, draft)
+	if handleError(c, err, apperrors.Internal("failed to create product")) {
+		return
+	}
+
+	images := scraped.Images
+	if images == nil {
+		images = []string{}
+	}
+	c.JSON(http.StatusCreated, models.ProductImport{
+		Product:   product,
+		SourceURL: req.URL,
+		Parser:    parser,
+		Currency:  scraped.Currency,
+		Images:    images,
+	})
+}
+
+func importError(c *gin.Context, err error) *apperrors.AppError {
+	switch {
+	case errors.Is(err, productimport.ErrInvalidURL), errors.Is(err, productimport.ErrBlockedAddress):
+		return apperrors.BadRequest("url must be a public http or https address")
+	case errors.Is(err, productimport.ErrPageTooLarge), errors.Is(err, productimport.ErrNoProduct):
+		return apperrors.ImportFailed(err.Error())
+	}
+	logger.FromContext(c.Request.Context()).WithField("err", err).Warn("product import failed")
+	return apperrors.ImportFailed("the product page could not be fetched")
+}
+
+// truncate cuts s to at most n runes.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/productimport"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockProductFetcher struct {
+	fetchFn func(ctx context.Context, rawURL string) (*models.ScrapedProduct, string, error)
+}
+
+func (m *mockProductFetcher) Fetch(ctx context.Context, rawURL string) (*models.ScrapedProduct, string, error) {
+	return m.fetchFn(ctx, rawURL)
+}
+
+type mockImportSellers struct{}
+
+func (mockImportSellers) GetByUserID(ctx context.Context, userID int) (*models.Seller, error) {
+	if userID != 5 {
+		return nil, errors.New("seller not found")
+	}
+	return &models.Seller{ID: 11, UserID: userID}, nil
+}
+
+type mockImportProducts struct {
+	created *models.CreateProductRequest
+}
+
+func (m *mockImportProducts) Create(ctx context.Context, sellerID int, req *models.CreateProductRequest) (*models.Product, error) {
+	m.created = req
+	return &models.Product{
+		ID: 1, SellerID: sellerID, CategoryID: req.CategoryID, Title: req.Title,
+		Price: req.Price, ImageURL: req.ImageURL, Status: "pending",
+	}, nil
+}
+
+func TestProductImportController_ImportProductURL(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	beanie := &models.ScrapedProduct{
+		Title: "Wool beanie", Price: 19.999, Currency: "EUR",
+		Images: []string{"https://cdn.example.com/" + strings.Repeat("x", 500), "https://cdn.example.com/beanie.jpg"},
+	}
+
+	tests := []struct {
+		name       string
+		userID     int
+		body       string
+		fetchErr   error
+		wantCode   int
+		wantParser string
+		wantTitle  string
+		wantImage  string
+	}{
+		{
+			name:       "from url",
+			userID:     5,
+			body:       `{"category_id":2,"url":"https://shop.example.com/p/beanie"}`,
+			wantCode:   http.StatusCreated,
+			wantParser: "generic",
+			wantTitle:  "Wool beanie",
+			wantImage:  "https://cdn.example.com/beanie.jpg",
+		},
+		{
+			name:       "from extension",
+			userID:     5,
+			body:       `{"category_id":2,"url":"https://shop.example.com/p/scarf","product":{"title":"Scarf","price":12,"images":["https://cdn.example.com/scarf.jpg"]}}`,
+			wantCode:   http.StatusCreated,
+			wantParser: extensionParser,
+			wantTitle:  "Scarf",
+			wantImage:  "https://cdn.example.com/scarf.jpg",
+		},
+		{name: "neither url nor product", userID: 5, body: `{"category_id":2}`, wantCode: http.StatusBadRequest},
+		{name: "extension product without title", userID: 5, body: `{"category_id":2,"product":{"price":12}}`, wantCode: http.StatusBadRequest},
+		{
+			name:     "private address",
+			userID:   5,
+			body:     `{"category_id":2,"url":"http://10.0.0.1/admin"}`,
+			fetchErr: fmt.Errorf("dial: %w", productimport.ErrBlockedAddress),
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "no product on page",
+			userID:   5,
+			body:     `{"category_id":2,"url":"https://shop.example.com/about"}`,
+			fetchErr: productimport.ErrNoProduct,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "fetch failed",
+			userID:   5,
+			body:     `{"category_id":2,"url":"https://shop.example.com/p/beanie"}`,
+			fetchErr: errors.New("product page returned 503"),
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{name: "not a seller", userID: 6, body: `{"category_id":2,"url":"https://shop.example.com/p/beanie"}`, wantCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("POST", "/api/seller/products/import-url", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("user_id", tt.userID)
+
+			products := &mockImportProducts{}
+			pc := NewProductImportController(&mockProductFetcher{
+				fetchFn: func(ctx context.Context, rawURL string) (*models.ScrapedProduct, string, error) {
+					if tt.fetchErr != nil {
+						return nil, "", tt.fetchErr
+					}
+					return beanie, "generic", nil
+				},
+			}, mockImportSellers{}, products)
+			pc.ImportProductURL(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+			if tt.wantCode != http.StatusCreated {
+				require.Nil(t, products.created)
+				return
+			}
+
+			var got models.ProductImport
+			require.NoError(t, json.Unmarshal(r.Body.Bytes(), &got))
+			require.Equal(t, tt.wantParser, got.Parser)
+			require.Equal(t, tt.wantTitle, got.Product.Title)
+			require.Equal(t, tt.wantImage, got.Product.ImageURL)
+			require.Equal(t, 11, got.Product.SellerID)
+			require.Equal(t, "pending", got.Product.Status)
+			require.Zero(t, products.created.Stock)
+		})
+	}
+}
+
+func TestProductImportController_ExtensionPayload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("POST", "/api/seller/products/import-url",
+		bytes.NewBufferString(`{"category_id":2,"product":{"title":"`+strings.Repeat("é", 300)+`","price":19.999}}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", 5)
+
+	products := &mockImportProducts{}
+	NewProductImportController(&mockProductFetcher{}, mockImportSellers{}, products).ImportProductURL(c)
+
+	require.Equal(t, http.StatusBadRequest, r.Code, "titles over 255 characters are rejected from the extension")
+
+	r = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("POST", "/api/seller/products/import-url",
+		bytes.NewBufferString(`{"category_id":2,"product":{"title":"Beanie","price":19.999}}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", 5)
+
+	NewProductImportController(&mockProductFetcher{}, mockImportSellers{}, products).ImportProductURL(c)
+
+	require.Equal(t, http.StatusCreated, r.Code, r.Body.String())
+	require.Equal(t, 20.0, products.created.Price)
+}
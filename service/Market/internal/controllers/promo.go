@@ -0,0 +1,231 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type PromoController struct {
+	sellers   importSellers
+	promoRepo repository.PromoRepo
+}
+
+func NewPromoController(sellers importSellers, promoRepo repository.PromoRepo) *PromoController {
+	return &PromoController{sellers: sellers, promoRepo: promoRepo}
+}
+
+// list responds with a page of codes, only the seller's with a seller.
+func (pc *PromoController) list(c *gin.Context, sellerID *int) {
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		pagination = models.PaginationParams{Page: 1, PageSize: models.DefaultPageSize}
+	}
+	if pagination.Page < 1 {
+		pagination.Page = 1
+	}
+
+	promos, totalItems, err := pc.promoRepo.GetAll(c.Request.Context(), sellerID, &pagination)
+	if handleError(c, err, apperrors.Internal("failed to get promo codes")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Data:       promos,
+		Pagination: models.NewPaginationMeta(pagination.Page, pagination.GetLimit(), totalItems),
+	})
+}
+
+// deactivate deactivates the code in the path, only among the seller's
+// with a seller.
+func (pc *PromoController) deactivate(c *gin.Context, sellerID *int) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("promo code"))
+		return
+	}
+
+	promo, err := pc.promoRepo.Deactivate(c.Request.Context(), id, sellerID)
+	if handleError(c, err, apperrors.Internal("failed to deactivate promo code")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, promo)
+}
+
+// GetPromoCodes godoc
+// @Summary Get promo codes
+// @Description Get paginated promotion codes, newest first, optionally only those funded by a seller
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param seller_id query int false "Seller ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/promo-codes [get]
+func (pc *PromoController) GetPromoCodes(c *gin.Context) {
+	sellerID, ok := optionalIntQuery(c, "seller_id")
+	if !ok {
+		return
+	}
+	pc.list(c, sellerID)
+}
+
+// CreatePromoCode godoc
+// @Summary Create promo code
+// @Description Create a promotion code taking percent_off off the lines it applies to. Marketplace-funded codes (the default) apply to every line and the marketplace bears the discount; seller-funded codes need a seller_id, apply to that seller's lines only and come out of the seller's payout.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreatePromoCodeRequest true "Promo code"
+// @Success 201 {object} models.PromoCode
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/promo-codes [post]
+func (pc *PromoController) CreatePromoCode(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.CreatePromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+	if req.FundedBy == "" {
+		req.FundedBy = models.PromoFundedByMarketplace
+	}
+	switch {
+	case req.FundedBy == models.PromoFundedBySeller && req.SellerID == nil:
+		respondError(c, apperrors.ValidationError("seller_id", "required for seller-funded codes"))
+		return
+	case req.FundedBy == models.PromoFundedByMarketplace && req.SellerID != nil:
+		respondError(c, apperrors.ValidationError("seller_id", "only seller-funded codes belong to a seller"))
+		return
+	}
+
+	promo, err := pc.promoRepo.Create(c.Request.Context(), userID.(int), &req)
+	if handleError(c, err, apperrors.Internal("failed to create promo code")) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, promo)
+}
+
+// DeactivatePromoCode godoc
+// @Summary Deactivate promo code
+// @Description Stop a promotion code from being redeemed. Orders that already used it keep their discount.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Promo code ID"
+// @Success 200 {object} models.PromoCode
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/promo-codes/{id} [delete]
+func (pc *PromoController) DeactivatePromoCode(c *gin.Context) {
+	pc.deactivate(c, nil)
+}
+
+// GetSellerPromoCodes godoc
+// @Summary Get my promo codes
+// @Description Get the current seller's promotion codes, newest first
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/promo-codes [get]
+func (pc *PromoController) GetSellerPromoCodes(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	seller, err := pc.sellers.GetByUserID(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.Forbidden("seller profile not found")) {
+		return
+	}
+
+	pc.list(c, &seller.ID)
+}
+
+// CreateSellerPromoCode godoc
+// @Summary Create my promo code
+// @Description Create a promotion code funded by the current seller: it takes percent_off off the seller's own lines only, and the discount comes out of the seller's payout. funded_by and seller_id are ignored.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreatePromoCodeRequest true "Promo code"
+// @Success 201 {object} models.PromoCode
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/promo-codes [post]
+func (pc *PromoController) CreateSellerPromoCode(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	seller, err := pc.sellers.GetByUserID(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.Forbidden("seller profile not found")) {
+		return
+	}
+
+	var req models.CreatePromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+	req.FundedBy = models.PromoFundedBySeller
+	req.SellerID = &seller.ID
+
+	promo, err := pc.promoRepo.Create(c.Request.Context(), userID.(int), &req)
+	if handleError(c, err, apperrors.Internal("failed to create promo code")) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, promo)
+}
+
+// DeactivateSellerPromoCode godoc
+// @Summary Deactivate my promo code
+// @Description Stop one of the current seller's promotion codes from being redeemed. Orders that already used it keep their discount.
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Promo code ID"
+// @Success 200 {object} models.PromoCode
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/promo-codes/{id} [delete]
+func (pc *PromoController) DeactivateSellerPromoCode(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	seller, err := pc.sellers.GetByUserID(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.Forbidden("seller profile not found")) {
+		return
+	}
+
+	pc.deactivate(c, &seller.ID)
+}
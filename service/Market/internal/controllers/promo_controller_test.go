@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPromoRepo struct {
+	createFn     func(ctx context.Context, createdBy int, req *models.CreatePromoCodeRequest) (*models.PromoCode, error)
+	getAllFn     func(ctx context.Context, sellerID *int, pagination *models.PaginationParams) ([]*models.PromoCode, int64, error)
+	deactivateFn func(ctx context.Context, id int, sellerID *int) (*models.PromoCode, error)
+}
+
+func (m *mockPromoRepo) Create(ctx context.Context, createdBy int, req *models.CreatePromoCodeRequest) (*models.PromoCode, error) {
+	return m.createFn(ctx, createdBy, req)
+}
+
+func (m *mockPromoRepo) GetAll(ctx context.Context, sellerID *int, pagination *models.PaginationParams) ([]*models.PromoCode, int64, error) {
+	return m.getAllFn(ctx, sellerID, pagination)
+}
+
+func (m *mockPromoRepo) Deactivate(ctx context.Context, id int, sellerID *int) (*models.PromoCode, error) {
+	return m.deactivateFn(ctx, id, sellerID)
+}
+
+var _ repository.PromoRepo = (*mockPromoRepo)(nil)
+
+func TestPromoController_CreatePromoCode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		body         string
+		wantCode     int
+		wantFundedBy string
+	}{
+		{name: "marketplace by default", body: `{"code":"spring15","percent_off":15}`, wantCode: http.StatusCreated, wantFundedBy: "marketplace"},
+		{name: "seller-funded", body: `{"code":"SHOP10","percent_off":10,"funded_by":"seller","seller_id":11}`, wantCode: http.StatusCreated, wantFundedBy: "seller"},
+		{name: "seller-funded without seller", body: `{"code":"SHOP10","percent_off":10,"funded_by":"seller"}`, wantCode: http.StatusBadRequest},
+		{name: "marketplace with seller", body: `{"code":"SHOP10","percent_off":10,"seller_id":11}`, wantCode: http.StatusBadRequest},
+		{name: "unknown funder", body: `{"code":"SHOP10","percent_off":10,"funded_by":"courier"}`, wantCode: http.StatusBadRequest},
+		{name: "over 100 percent", body: `{"code":"FREE","percent_off":101}`, wantCode: http.StatusBadRequest},
+		{name: "code with spaces", body: `{"code":"SPRING 15","percent_off":15}`, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("POST", "/api/admin/promo-codes", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("user_id", 1)
+
+			NewPromoController(mockImportSellers{}, &mockPromoRepo{
+				createFn: func(ctx context.Context, createdBy int, req *models.CreatePromoCodeRequest) (*models.PromoCode, error) {
+					require.Equal(t, 1, createdBy)
+					require.Equal(t, tt.wantFundedBy, req.FundedBy)
+					return &models.PromoCode{ID: 3, Code: req.Code, FundedBy: req.FundedBy, SellerID: req.SellerID, PercentOff: req.PercentOff}, nil
+				},
+			}).CreatePromoCode(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+		})
+	}
+}
+
+func TestPromoController_CreateSellerPromoCode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("POST", "/api/seller/promo-codes",
+		bytes.NewBufferString(`{"code":"SHOP10","percent_off":10,"funded_by":"marketplace","seller_id":99}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", 5)
+
+	NewPromoController(mockImportSellers{}, &mockPromoRepo{
+		createFn: func(ctx context.Context, createdBy int, req *models.CreatePromoCodeRequest) (*models.PromoCode, error) {
+			require.Equal(t, models.PromoFundedBySeller, req.FundedBy, "sellers only fund their own codes")
+			require.Equal(t, 11, *req.SellerID)
+			return &models.PromoCode{ID: 4, Code: req.Code, FundedBy: req.FundedBy, SellerID: req.SellerID}, nil
+		},
+	}).CreateSellerPromoCode(c)
+
+	require.Equal(t, http.StatusCreated, r.Code, r.Body.String())
+}
+
+func TestPromoController_DeactivateSellerPromoCode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, tt := range []struct {
+		name     string
+		userID   int
+		wantCode int
+	}{
+		{name: "own code", userID: 5, wantCode: http.StatusOK},
+		{name: "not a seller", userID: 6, wantCode: http.StatusForbidden},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("DELETE", "/api/seller/promo-codes/3", nil)
+			c.Params = gin.Params{{Key: "id", Value: "3"}}
+			c.Set("user_id", tt.userID)
+
+			NewPromoController(mockImportSellers{}, &mockPromoRepo{
+				deactivateFn: func(ctx context.Context, id int, sellerID *int) (*models.PromoCode, error) {
+					require.Equal(t, 3, id)
+					require.Equal(t, 11, *sellerID)
+					return &models.PromoCode{ID: id, SellerID: sellerID}, nil
+				},
+			}).DeactivateSellerPromoCode(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+		})
+	}
+}
+
+func TestPromoController_DeactivatePromoCode_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("DELETE", "/api/admin/promo-codes/8", nil)
+	c.Params = gin.Params{{Key: "id", Value: "8"}}
+
+	NewPromoController(mockImportSellers{}, &mockPromoRepo{
+		deactivateFn: func(ctx context.Context, id int, sellerID *int) (*models.PromoCode, error) {
+			require.Nil(t, sellerID)
+			return nil, apperrors.NotFound("promo code with id 8 not found")
+		},
+	}).DeactivatePromoCode(c)
+
+	require.Equal(t, http.StatusNotFound, r.Code)
+}
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+type ProofController struct {
+	deliveryRepo repository.DeliveryRepo
+	store        storage.Storage
+}
+
+func NewProofController(deliveryRepo repository.DeliveryRepo, store storage.Storage) *ProofController {
+	return &ProofController{
+		deliveryRepo: deliveryRepo,
+		store:        store,
+	}
+}
+
+func proofKeyPrefix(orderID int) string {
+	return fmt.Sprintf("proofs/%d/", orderID)
+}
+
+// AddProof godoc
+// @Summary Attach delivery proof
+// @Description Attach a delivery photo or signature image to an order. Couriers may attach to orders assigned to them, sellers to orders containing their products.
+// @Tags delivery
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param file formData file true "Photo or signature image"
+// @Param kind formData string false "photo (default) or signature"
+// @Success 201 {object} models.DeliveryProof
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/courier/deliveries/{id}/proofs [post]
+// @Router /api/seller/orders/{id}/proofs [post]
+func (pc *ProofController) AddProof(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	role, _ := c.Get("role")
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("order"))
+		return
+	}
+
+	kind := c.DefaultPostForm("kind", models.ProofKindPhoto)
+	if kind != models.ProofKindPhoto && kind != models.ProofKindSignature {
+		respondError(c, apperrors.ValidationError("kind", "must be photo or signature"))
+		return
+	}
+
+	var allowed bool
+	switch role {
+	case "courier":
+		allowed, err = pc.deliveryRepo.IsAssignedCourier(c.Request.Context(), orderID, userID.(int))
+	case "seller":
+		allowed, err = pc.deliveryRepo.HasSellerItems(c.Request.Context(), orderID, userID.(int))
+	case "admin":
+		allowed = true
+	}
+	if handleError(c, err, apperrors.Internal("failed to check order access")) {
+		return
+	}
+	if !allowed {
+		respondError(c, apperrors.Forbidden("order not found or access denied"))
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		respondError(c, apperrors.BadRequest("file is required"))
+		return
+	}
+
+	key, url, ok := storeImage(c, pc.store, file, proofKeyPrefix(orderID))
+	if !ok {
+		return
+	}
+
+	proof, err := pc.deliveryRepo.AddProof(c.Request.Context(), &models.DeliveryProof{
+		OrderID:      orderID,
+		Kind:         kind,
+		URL:          url,
+		UploadedBy:   userID.(int),
+		UploaderRole: fmt.Sprintf("%v", role),
+	})
+	if err != nil {
+		_ = pc.store.Delete(c.Request.Context(), key)
+		handleError(c, err, apperrors.Internal("failed to store delivery proof"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, proof)
+}
+
+// GetProofs godoc
+// @Summary Get delivery proofs
+// @Description List delivery photos and signatures of an order. Buyers see their own orders, admins any order.
+// @Tags delivery
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Success 200 {array} models.DeliveryProof
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/user/orders/{id}/proofs [get]
+// @Router /api/admin/orders/{id}/proofs [get]
+func (pc *ProofController) GetProofs(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	role, _ := c.Get("role")
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("order"))
+		return
+	}
+
+	if role != "admin" {
+		owner, err := pc.deliveryRepo.IsOrderOwner(c.Request.Context(), orderID, userID.(int))
+		if handleError(c, err, apperrors.Internal("failed to check order access")) {
+			return
+		}
+		if !owner {
+			respondError(c, apperrors.OrderNotFound(orderID))
+			return
+		}
+	}
+
+	proofs, err := pc.deliveryRepo.GetProofs(c.Request.Context(), orderID)
+	if handleError(c, err, apperrors.Internal("failed to get delivery proofs")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, proofs)
+}
@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProofController_AddProof_AssignedCourier(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = proofRequest(t, "file", "signature.png")
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set("user_id", 5)
+	c.Set("role", "courier")
+
+	m := &mockDeliveryRepo{
+		courierOK: true,
+		addProofFn: func(ctx context.Context, proof *models.DeliveryProof) (*models.DeliveryProof, error) {
+			require.Equal(t, 7, proof.OrderID)
+			require.Equal(t, models.ProofKindPhoto, proof.Kind)
+			require.Equal(t, "courier", proof.UploaderRole)
+			require.Contains(t, proof.URL, "/uploads/proofs/7/")
+			proof.ID = 1
+			return proof, nil
+		},
+	}
+
+	store, _ := newTestStorage(t)
+	NewProofController(m, store).AddProof(c)
+
+	require.Equal(t, http.StatusCreated, r.Code)
+}
+
+func TestProofController_AddProof_SellerWithoutItems(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = proofRequest(t, "file", "photo.jpg")
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set("user_id", 9)
+	c.Set("role", "seller")
+
+	store, _ := newTestStorage(t)
+	NewProofController(&mockDeliveryRepo{sellerOK: false}, store).AddProof(c)
+
+	require.Equal(t, http.StatusForbidden, r.Code)
+}
+
+func TestProofController_GetProofs_OwnerOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	m := &mockDeliveryRepo{
+		getProofsFn: func(ctx context.Context, orderID int) ([]*models.DeliveryProof, error) {
+			return []*models.DeliveryProof{{ID: 1, OrderID: orderID, Kind: models.ProofKindSignature}}, nil
+		},
+	}
+	store, _ := newTestStorage(t)
+	pc := NewProofController(m, store)
+
+	for _, tc := range []struct {
+		role   string
+		owner  bool
+		status int
+	}{
+		{"user", true, http.StatusOK},
+		{"user", false, http.StatusNotFound},
+		{"admin", false, http.StatusOK},
+	} {
+		m.ownerOK = tc.owner
+		r := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(r)
+		c.Request = httptest.NewRequest("GET", "/api/user/orders/7/proofs", nil)
+		c.Params = gin.Params{{Key: "id", Value: "7"}}
+		c.Set("user_id", 3)
+		c.Set("role", tc.role)
+
+		pc.GetProofs(c)
+
+		require.Equal(t, tc.status, r.Code, "role=%s owner=%t", tc.role, tc.owner)
+	}
+}
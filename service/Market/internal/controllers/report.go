@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type ReportController struct {
+	reportRepo repository.ReportRepo
+}
+
+func NewReportController(reportRepo repository.ReportRepo) *ReportController {
+	return &ReportController{reportRepo: reportRepo}
+}
+
+// CreateReport godoc
+// @Summary Report content
+// @Description Flag a product or seller for moderation
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateReportRequest true "Report data"
+// @Success 201 {object} models.Report
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/reports [post]
+func (rc *ReportController) CreateReport(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	report, err := rc.reportRepo.Create(c.Request.Context(), userID.(int), &req)
+	if handleError(c, err, apperrors.Internal("failed to create report")) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// GetReports godoc
+// @Summary Moderation queue
+// @Description List abuse reports, oldest first (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "open (default), resolved or all"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/reports [get]
+func (rc *ReportController) GetReports(c *gin.Context) {
+	status := c.DefaultQuery("status", models.ReportStatusOpen)
+	switch status {
+	case models.ReportStatusOpen, models.ReportStatusResolved:
+	case "all":
+		status = ""
+	default:
+		respondError(c, apperrors.ValidationError("status", "must be open, resolved or all"))
+		return
+	}
+
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		pagination = models.PaginationParams{Page: 1, PageSize: models.DefaultPageSize}
+	}
+	if pagination.Page < 1 {
+		pagination.Page = 1
+	}
+
+	reports, totalItems, err := rc.reportRepo.GetAll(c.Request.Context(), status, &pagination)
+	if handleError(c, err, apperrors.Internal("failed to get reports")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Data:       reports,
+		Pagination: models.NewPaginationMeta(pagination.Page, pagination.GetLimit(), totalItems),
+	})
+}
+
+// ResolveReport godoc
+// @Summary Resolve report
+// @Description Close a report with an action: dismiss, hide_content, warn_seller or ban_user (admin only). ban_user also suspends the seller's account in the Auth service and is refused without it.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Report ID"
+// @Param request body models.ResolveReportRequest true "Resolution"
+// @Success 200 {object} models.Report
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/reports/{id}/resolve [put]
+func (rc *ReportController) ResolveReport(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("report"))
+		return
+	}
+
+	var req models.ResolveReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	report, err := rc.reportRepo.Resolve(c.Request.Context(), id, userID.(int), &req)
+	if handleError(c, err, apperrors.Internal("failed to resolve report")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockReportRepo struct {
+	createFn  func(ctx context.Context, reporterID int, req *models.CreateReportRequest) (*models.Report, error)
+	getAllFn  func(ctx context.Context, status string, pagination *models.PaginationParams) ([]*models.Report, int64, error)
+	resolveFn func(ctx context.Context, id, adminID int, req *models.ResolveReportRequest) (*models.Report, error)
+}
+
+func (m *mockReportRepo) Create(ctx context.Context, reporterID int, req *models.CreateReportRequest) (*models.Report, error) {
+	return m.createFn(ctx, reporterID, req)
+}
+
+func (m *mockReportRepo) GetAll(ctx context.Context, status string, pagination *models.PaginationParams) ([]*models.Report, int64, error) {
+	return m.getAllFn(ctx, status, pagination)
+}
+
+func (m *mockReportRepo) Resolve(ctx context.Context, id, adminID int, req *models.ResolveReportRequest) (*models.Report, error) {
+	return m.resolveFn(ctx, id, adminID, req)
+}
+
+var _ repository.ReportRepo = (*mockReportRepo)(nil)
+
+func TestReportController_CreateReport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("POST", "/api/reports", strings.NewReader(`{"target_type":"product","target_id":3,"reason":"counterfeit"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", 8)
+
+	m := &mockReportRepo{
+		createFn: func(ctx context.Context, reporterID int, req *models.CreateReportRequest) (*models.Report, error) {
+			require.Equal(t, 8, reporterID)
+			require.Equal(t, models.ReportTargetProduct, req.TargetType)
+			return &models.Report{ID: 1, ReporterID: &reporterID, TargetType: req.TargetType, TargetID: req.TargetID, Status: models.ReportStatusOpen}, nil
+		},
+	}
+
+	NewReportController(m).CreateReport(c)
+
+	require.Equal(t, http.StatusCreated, r.Code)
+}
+
+func TestReportController_CreateReport_InvalidReason(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("POST", "/api/reports", strings.NewReader(`{"target_type":"review","target_id":3,"reason":"boring"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", 8)
+
+	NewReportController(&mockReportRepo{}).CreateReport(c)
+
+	require.Equal(t, http.StatusBadRequest, r.Code)
+}
+
+func TestReportController_CreateReport_Duplicate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("POST", "/api/reports", strings.NewReader(`{"target_type":"seller","target_id":2,"reason":"fraud"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", 8)
+
+	m := &mockReportRepo{
+		createFn: func(ctx context.Context, reporterID int, req *models.CreateReportRequest) (*models.Report, error) {
+			return nil, apperrors.Conflict("you already have an open report for this seller")
+		},
+	}
+
+	NewReportController(m).CreateReport(c)
+
+	require.Equal(t, http.StatusConflict, r.Code)
+}
+
+func TestReportController_GetReports_DefaultsToOpen(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/admin/reports", nil)
+
+	m := &mockReportRepo{
+		getAllFn: func(ctx context.Context, status string, pagination *models.PaginationParams) ([]*models.Report, int64, error) {
+			require.Equal(t, models.ReportStatusOpen, status)
+			return []*models.Report{}, 0, nil
+		},
+	}
+
+	NewReportController(m).GetReports(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+}
+
+func TestReportController_ResolveReport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("PUT", "/api/admin/reports/4/resolve", strings.NewReader(`{"action":"hide_content","note":"fake brand"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "4"}}
+	c.Set("user_id", 1)
+
+	m := &mockReportRepo{
+		resolveFn: func(ctx context.Context, id, adminID int, req *models.ResolveReportRequest) (*models.Report, error) {
+			require.Equal(t, 4, id)
+			require.Equal(t, 1, adminID)
+			require.Equal(t, models.ReportActionHideContent, req.Action)
+			return &models.Report{ID: id, Status: models.ReportStatusResolved, Resolution: req.Action}, nil
+		},
+	}
+
+	NewReportController(m).ResolveReport(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+}
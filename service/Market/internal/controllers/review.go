@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/moderation"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewController struct {
+	reviewRepo repository.ReviewRepo
+	moderator  *moderation.Pipeline
+}
+
+// NewReviewController creates the review controller. With a nil moderator
+// review comments are published without automated checks.
+func NewReviewController(reviewRepo repository.ReviewRepo, moderator *moderation.Pipeline) *ReviewController {
+	return &ReviewController{reviewRepo: reviewRepo, moderator: moderator}
+}
+
+// CreateReview godoc
+// @Summary Review product
+// @Description Rate a product from 1 to 5 with an optional comment. Only buyers who received the product in an order can review it, once. The product and seller ratings are updated right away. A comment held by content moderation leaves the review pending, unlisted and out of the ratings until an admin resolves its report.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param request body models.CreateReviewRequest true "Review"
+// @Success 201 {object} models.Review
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/products/{id}/reviews [post]
+func (rc *ReviewController) CreateReview(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("product"))
+		return
+	}
+
+	var req models.CreateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	res := rc.moderator.Review(c.Request.Context(), req.Comment)
+	review, err := rc.reviewRepo.Create(c.Request.Context(), productID, userID.(int), &req, res.Reasons)
+	if handleError(c, err, apperrors.Internal("failed to create review")) {
+		return
+	}
+	if review.Status == models.ReviewStatusPending {
+		logger.FromContext(c.Request.Context()).WithFields(map[string]interface{}{
+			"review_id": review.ID,
+			"reasons":   res.Reasons,
+		}).Info("review held for moderation")
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
+
+// GetProductReviews godoc
+// @Summary Get product reviews
+// @Description Get a product's published reviews, newest first
+// @Tags reviews
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/products/{id}/reviews [get]
+func (rc *ReviewController) GetProductReviews(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("product"))
+		return
+	}
+
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		pagination = models.PaginationParams{Page: 1, PageSize: models.DefaultPageSize}
+	}
+	if pagination.Page < 1 {
+		pagination.Page = 1
+	}
+
+	reviews, totalItems, err := rc.reviewRepo.GetByProduct(c.Request.Context(), productID, &pagination)
+	if handleError(c, err, apperrors.Internal("failed to get reviews")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Data:       reviews,
+		Pagination: models.NewPaginationMeta(pagination.Page, pagination.GetLimit(), totalItems),
+	})
+}
+
+// DeleteReview godoc
+// @Summary Delete review
+// @Description Delete one of the current user's reviews; the product and seller ratings are updated right away
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/user/reviews/{id} [delete]
+func (rc *ReviewController) DeleteReview(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("review"))
+		return
+	}
+
+	if err := rc.reviewRepo.Delete(c.Request.Context(), id, userID.(int)); err != nil {
+		handleError(c, err, apperrors.Internal("failed to delete review"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "review deleted"})
+}
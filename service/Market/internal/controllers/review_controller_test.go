@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/moderation"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockReviewRepo struct {
+	createFn       func(ctx context.Context, productID, userID int, req *models.CreateReviewRequest, holdReasons []string) (*models.Review, error)
+	getByProductFn func(ctx context.Context, productID int, pagination *models.PaginationParams) ([]*models.Review, int64, error)
+	deleteFn       func(ctx context.Context, id, userID int) error
+}
+
+func (m *mockReviewRepo) Create(ctx context.Context, productID, userID int, req *models.CreateReviewRequest, holdReasons []string) (*models.Review, error) {
+	return m.createFn(ctx, productID, userID, req, holdReasons)
+}
+
+func (m *mockReviewRepo) GetByProduct(ctx context.Context, productID int, pagination *models.PaginationParams) ([]*models.Review, int64, error) {
+	return m.getByProductFn(ctx, productID, pagination)
+}
+
+func (m *mockReviewRepo) Delete(ctx context.Context, id, userID int) error {
+	return m.deleteFn(ctx, id, userID)
+}
+
+var _ repository.ReviewRepo = (*mockReviewRepo)(nil)
+
+func TestReviewController_CreateReview(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		param    string
+		body     string
+		wantCode int
+	}{
+		{name: "buyer", param: "3", body: `{"rating":4,"comment":"Sturdy"}`, wantCode: http.StatusCreated},
+		{name: "not a buyer", param: "4", body: `{"rating":4}`, wantCode: http.StatusForbidden},
+		{name: "already reviewed", param: "5", body: `{"rating":4}`, wantCode: http.StatusConflict},
+		{name: "rating too high", param: "3", body: `{"rating":6}`, wantCode: http.StatusBadRequest},
+		{name: "missing rating", param: "3", body: `{"comment":"Nice"}`, wantCode: http.StatusBadRequest},
+		{name: "invalid id", param: "abc", body: `{"rating":4}`, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("POST", "/api/products/"+tt.param+"/reviews", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.param}}
+			c.Set("user_id", 8)
+
+			NewReviewController(&mockReviewRepo{
+				createFn: func(ctx context.Context, productID, userID int, req *models.CreateReviewRequest, holdReasons []string) (*models.Review, error) {
+					require.Equal(t, 8, userID)
+					require.Empty(t, holdReasons)
+					switch productID {
+					case 4:
+						return nil, apperrors.Forbidden("only buyers who received the product can review it")
+					case 5:
+						return nil, apperrors.Conflict("you have already reviewed this product")
+					}
+					return &models.Review{ID: 1, ProductID: productID, UserID: userID, Rating: req.Rating, Comment: req.Comment}, nil
+				},
+			}, nil).CreateReview(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+		})
+	}
+}
+
+func TestReviewController_CreateReviewHeld(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("POST", "/api/products/3/reviews", strings.NewReader(`{"rating":1,"comment":"Total scam"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "3"}}
+	c.Set("user_id", 8)
+
+	moderator := moderation.NewPipeline(false, moderation.NewBannedWords([]string{"scam"}))
+	NewReviewController(&mockReviewRepo{
+		createFn: func(ctx context.Context, productID, userID int, req *models.CreateReviewRequest, holdReasons []string) (*models.Review, error) {
+			require.NotEmpty(t, holdReasons)
+			return &models.Review{ID: 1, ProductID: productID, UserID: userID, Rating: req.Rating, Status: models.ReviewStatusPending}, nil
+		},
+	}, moderator).CreateReview(c)
+
+	require.Equal(t, http.StatusCreated, r.Code)
+	var review models.Review
+	require.NoError(t, json.Unmarshal(r.Body.Bytes(), &review))
+	require.Equal(t, models.ReviewStatusPending, review.Status)
+}
+
+func TestReviewController_GetProductReviews(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/products/3/reviews?page=2&page_size=1", nil)
+	c.Params = gin.Params{{Key: "id", Value: "3"}}
+
+	NewReviewController(&mockReviewRepo{
+		getByProductFn: func(ctx context.Context, productID int, pagination *models.PaginationParams) ([]*models.Review, int64, error) {
+			require.Equal(t, 3, productID)
+			require.Equal(t, 2, pagination.Page)
+			return []*models.Review{{ID: 7, ProductID: 3, Rating: 5}}, 2, nil
+		},
+	}, nil).GetProductReviews(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	var resp struct {
+		Data       []models.Review       `json:"data"`
+		Pagination models.PaginationMeta `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(r.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	require.Equal(t, int64(2), resp.Pagination.TotalItems)
+}
+
+func TestReviewController_DeleteReview(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		param    string
+		wantCode int
+	}{
+		{name: "own review", param: "7", wantCode: http.StatusOK},
+		{name: "someone else's review", param: "9", wantCode: http.StatusNotFound},
+		{name: "invalid id", param: "abc", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("DELETE", "/api/user/reviews/"+tt.param, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.param}}
+			c.Set("user_id", 8)
+
+			NewReviewController(&mockReviewRepo{
+				deleteFn: func(ctx context.Context, id, userID int) error {
+					if id != 7 {
+						return apperrors.NotFound("review not found")
+					}
+					return nil
+				},
+			}, nil).DeleteReview(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+		})
+	}
+}
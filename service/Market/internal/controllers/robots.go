@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// robotsDisallowed are the path prefixes crawlers have no business in.
+var robotsDisallowed = []string{
+	"/api/admin/",
+	"/api/seller/",
+	"/api/user/",
+	"/api/cart",
+	"/api/courier/",
+	"/api/upload",
+	"/api/dev/",
+	"/swagger/",
+	"/metrics",
+}
+
+type RobotsController struct {
+	body string
+}
+
+// NewRobotsController builds robots.txt once: blocked agents are
+// disallowed everywhere, everyone else is kept out of private routes and
+// asked to wait crawlDelay between requests when set. sitemapURL, when
+// set, is advertised.
+func NewRobotsController(blockedAgents []string, crawlDelay time.Duration, sitemapURL string) *RobotsController {
+	var b strings.Builder
+	for _, agent := range blockedAgents {
+		fmt.Fprintf(&b, "User-agent: %s\nDisallow: /\n\n", agent)
+	}
+	b.WriteString("User-agent: *\n")
+	for _, path := range robotsDisallowed {
+		fmt.Fprintf(&b, "Disallow: %s\n", path)
+	}
+	if crawlDelay > 0 {
+		fmt.Fprintf(&b, "Crawl-delay: %d\n", int(crawlDelay.Round(time.Second).Seconds()))
+	}
+	if sitemapURL != "" {
+		fmt.Fprintf(&b, "\nSitemap: %s\n", sitemapURL)
+	}
+	return &RobotsController{body: b.String()}
+}
+
+// GetRobots godoc
+// @Summary Get robots.txt
+// @Description Crawler rules: private routes and blocked user agents are disallowed, and the sitemap is advertised while catalog feeds are generated
+// @Tags feeds
+// @Produce plain
+// @Success 200 {string} string
+// @Router /robots.txt [get]
+func (rc *RobotsController) GetRobots(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.String(http.StatusOK, rc.body)
+}
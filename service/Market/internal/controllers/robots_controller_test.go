@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRobotsController_GetRobots(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rc := NewRobotsController([]string{"AhrefsBot"}, 2*time.Second, "https://api.example.com/sitemap.xml")
+
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/robots.txt", nil)
+	rc.GetRobots(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	body := r.Body.String()
+	require.Contains(t, body, "User-agent: AhrefsBot\nDisallow: /\n")
+	require.Contains(t, body, "User-agent: *\n")
+	require.Contains(t, body, "Disallow: /api/admin/\n")
+	require.Contains(t, body, "Crawl-delay: 2\n")
+	require.Contains(t, body, "Sitemap: https://api.example.com/sitemap.xml\n")
+}
+
+func TestRobotsController_GetRobots_Defaults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rc := NewRobotsController(nil, 0, "")
+
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/robots.txt", nil)
+	rc.GetRobots(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	require.NotContains(t, r.Body.String(), "Disallow: /\n")
+	require.NotContains(t, r.Body.String(), "Crawl-delay")
+	require.NotContains(t, r.Body.String(), "Sitemap")
+}
@@ -1,30 +1,77 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/catalog"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
 	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/moderation"
 	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/settings"
+	"github.com/Zifeldev/marketback/service/Market/internal/taxid"
 	"github.com/gin-gonic/gin"
 )
 
 type SellerController struct {
 	sellerRepo  *repository.SellerRepository
 	productRepo *repository.ProductRepository
+	reportRepo  *repository.ReportRepository
+	moderator   *moderation.Pipeline
+	revisions   *repository.CatalogRevisionRepository
+	settings    *settings.Store
 }
 
-func NewSellerController(sellerRepo *repository.SellerRepository, productRepo *repository.ProductRepository) *SellerController {
+// NewSellerController creates the seller controller. With a nil moderator
+// product texts are published without automated checks; with nil revisions
+// no catalog revision is taken before spreadsheet imports. New sellers start
+// on the probation of the seller_probation_* settings; a nil settings store
+// puts them on none.
+func NewSellerController(
+	sellerRepo *repository.SellerRepository,
+	productRepo *repository.ProductRepository,
+	reportRepo *repository.ReportRepository,
+	moderator *moderation.Pipeline,
+	revisions *repository.CatalogRevisionRepository,
+	siteSettings *settings.Store,
+) *SellerController {
 	return &SellerController{
 		sellerRepo:  sellerRepo,
 		productRepo: productRepo,
+		reportRepo:  reportRepo,
+		moderator:   moderator,
+		revisions:   revisions,
+		settings:    siteSettings,
 	}
 }
 
+// moderate runs product texts through the moderation pipeline and holds the
+// product for manual review when a checker objects.
+func (sc *SellerController) moderate(c *gin.Context, product *models.Product, texts ...string) error {
+	res := sc.moderator.Review(c.Request.Context(), texts...)
+	if !res.Held {
+		return nil
+	}
+
+	if err := sc.reportRepo.HoldProduct(c.Request.Context(), product.ID, res.Reasons); err != nil {
+		return err
+	}
+	logger.FromContext(c.Request.Context()).WithFields(map[string]interface{}{
+		"product_id": product.ID,
+		"reasons":    res.Reasons,
+	}).Info("product held for moderation")
+	product.Status = "pending"
+	return nil
+}
+
 // RegisterSeller godoc
 // @Summary Register seller profile
-// @Description Create a seller profile for the authenticated user
+// @Description Create a seller profile for the authenticated user. With the seller_probation_orders setting above 0 the seller starts on probation: until that many orders are delivered their products are listed to the seller_probation_traffic share of visitors only.
 // @Tags seller
 // @Accept json
 // @Produce json
@@ -45,7 +92,8 @@ func (sc *SellerController) RegisterSeller(c *gin.Context) {
 		return
 	}
 
-	seller, err := sc.sellerRepo.Create(c.Request.Context(), userID.(int), &req)
+	ctx := c.Request.Context()
+	seller, err := sc.sellerRepo.Create(ctx, userID.(int), &req, sc.settings.SellerProbation(ctx))
 	if handleError(c, err, apperrors.Internal("failed to create seller")) {
 		return
 	}
@@ -111,6 +159,84 @@ func (sc *SellerController) UpdateSellerProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, updatedSeller)
 }
 
+// GetInvoicingDetails godoc
+// @Summary Get invoicing details
+// @Description Get the legal name, tax ID and invoicing address printed on the seller's statements
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.SellerInvoicing
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/invoicing [get]
+func (sc *SellerController) GetInvoicingDetails(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	seller, err := sc.sellerRepo.GetByUserID(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.NotFound("seller profile not found")) {
+		return
+	}
+
+	invoicing, err := sc.sellerRepo.GetInvoicing(c.Request.Context(), seller.ID)
+	if handleError(c, err, apperrors.Internal("failed to get invoicing details")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, invoicing)
+}
+
+// UpdateInvoicingDetails godoc
+// @Summary Update invoicing details
+// @Description Set the legal name, tax ID and invoicing address printed on the seller's statements. The tax ID (e.g. an EU VAT number with its country prefix, a US EIN) and postal code are checked against the country's format; tax IDs are stored without spaces, dots and dashes.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpdateSellerInvoicingRequest true "Invoicing details"
+// @Success 200 {object} models.SellerInvoicing
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/invoicing [put]
+func (sc *SellerController) UpdateInvoicingDetails(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	seller, err := sc.sellerRepo.GetByUserID(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.NotFound("seller profile not found")) {
+		return
+	}
+
+	var req models.UpdateSellerInvoicingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	if req.Country, err = taxid.Country(req.Country); err != nil {
+		respondError(c, apperrors.ValidationError("country", err.Error()))
+		return
+	}
+	if req.TaxID, err = taxid.Normalize(req.Country, req.TaxID); err != nil {
+		respondError(c, apperrors.ValidationError("tax_id", err.Error()))
+		return
+	}
+	if req.PostalCode, err = taxid.PostalCode(req.Country, req.PostalCode); err != nil {
+		respondError(c, apperrors.ValidationError("postal_code", err.Error()))
+		return
+	}
+
+	invoicing, err := sc.sellerRepo.SetInvoicing(c.Request.Context(), seller.ID, &req)
+	if handleError(c, err, apperrors.Internal("failed to update invoicing details")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, invoicing)
+}
+
 // CreateProduct godoc
 // @Summary Create product
 // @Description Create a new product for seller
@@ -144,6 +270,10 @@ func (sc *SellerController) CreateProduct(c *gin.Context) {
 		return
 	}
 
+	if err := sc.moderate(c, product, req.Title, req.Description); handleError(c, err, apperrors.Internal("failed to moderate product")) {
+		return
+	}
+
 	c.JSON(http.StatusCreated, product)
 }
 
@@ -175,6 +305,143 @@ func (sc *SellerController) GetSellerProducts(c *gin.Context) {
 	c.JSON(http.StatusOK, products)
 }
 
+// ImportProducts godoc
+// @Summary Import products
+// @Description Create and update products from a CSV or XLSX spreadsheet (first sheet) whose header row names the columns id, title, description, category_id, price, stock, sizes (separated by "|") and image_url, as in the export. Rows with an id update that product, setting only the non-empty cells; rows without one create a product and need a title, category_id and price. Rows are applied as they are read and each one is reported with its errors; failed rows change nothing. The catalog is first recorded in a catalog revision, returned as revision, to roll back to should the import go wrong.
+// @Tags seller
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Spreadsheet (.csv or .xlsx)"
+// @Success 200 {object} models.CatalogImport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/products/import [post]
+func (sc *SellerController) ImportProducts(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := c.Request.Context()
+
+	seller, err := sc.sellerRepo.GetByUserID(ctx, userID.(int))
+	if handleError(c, err, apperrors.Forbidden("seller profile not found")) {
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		respondError(c, apperrors.BadRequest("file is required"))
+		return
+	}
+	format, err := catalog.FormatOf(header.Filename)
+	if err != nil {
+		respondError(c, apperrors.ValidationError("file", err.Error()))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		respondError(c, apperrors.BadRequest("failed to read file"))
+		return
+	}
+	defer file.Close()
+
+	reader, err := catalog.NewReader(file, header.Size, format)
+	if err != nil {
+		respondError(c, apperrors.ValidationError("file", err.Error()))
+		return
+	}
+
+	products, err := sc.productRepo.GetBySellerID(ctx, seller.ID)
+	if handleError(c, err, apperrors.Internal("failed to get products")) {
+		return
+	}
+	owned := make(map[int]bool, len(products))
+	for _, p := range products {
+		owned[p.ID] = true
+	}
+
+	result := &models.CatalogImport{Rows: []models.CatalogImportRow{}}
+	if sc.revisions != nil {
+		result.Revision, err = sc.revisions.Create(ctx, seller.ID, models.CatalogRevisionImport, header.Filename, userID.(int))
+		if handleError(c, err, apperrors.Internal("failed to create catalog revision")) {
+			return
+		}
+	}
+	for n := 0; ; n++ {
+		row, problems, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			result.Add(models.CatalogImportRow{Status: models.CatalogRowFailed, Errors: []string{"file: " + err.Error()}})
+			break
+		}
+		if n == catalog.MaxRows {
+			result.Add(models.CatalogImportRow{Line: row.Line, Status: models.CatalogRowFailed,
+				Errors: []string{fmt.Sprintf("file: at most %d rows are imported at once", catalog.MaxRows)}})
+			break
+		}
+		if len(problems) > 0 {
+			result.Add(models.CatalogImportRow{Line: row.Line, Status: models.CatalogRowFailed, Errors: problems})
+			continue
+		}
+		result.Add(sc.importRow(c, seller.ID, row, owned))
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// importRow creates or updates the product of a valid row. Texts go through
+// moderation as with single products.
+func (sc *SellerController) importRow(c *gin.Context, sellerID int, row *catalog.Row, owned map[int]bool) models.CatalogImportRow {
+	ctx := c.Request.Context()
+	out := models.CatalogImportRow{Line: row.Line, Status: models.CatalogRowFailed}
+	failed := func(err error, fallback string) models.CatalogImportRow {
+		if appErr := apperrors.GetAppError(err); appErr != nil {
+			out.Errors = []string{appErr.Message}
+		} else {
+			logger.FromContext(ctx).WithFields(map[string]interface{}{
+				"err":  err,
+				"line": row.Line,
+			}).Error(fallback)
+			out.Errors = []string{fallback}
+		}
+		return out
+	}
+
+	var product *models.Product
+	var err error
+	if row.ID == nil {
+		if product, err = sc.productRepo.Create(ctx, sellerID, row.Create()); err != nil {
+			return failed(err, "failed to create product")
+		}
+		owned[product.ID] = true
+		out.Status = models.CatalogRowCreated
+	} else {
+		out.ProductID = *row.ID
+		if !owned[*row.ID] {
+			out.Errors = []string{fmt.Sprintf("id: product %d not found", *row.ID)}
+			return out
+		}
+		if product, err = sc.productRepo.Update(ctx, *row.ID, &row.Update); err != nil {
+			return failed(err, "failed to update product")
+		}
+		out.Status = models.CatalogRowUpdated
+	}
+	out.ProductID = product.ID
+
+	if row.ID == nil || row.Update.Title != nil || row.Update.Description != nil {
+		if err := sc.moderate(c, product, product.Title, product.Description); err != nil {
+			logger.FromContext(ctx).WithFields(map[string]interface{}{
+				"err":        err,
+				"product_id": product.ID,
+			}).Error("failed to moderate product")
+		}
+	}
+	return out
+}
+
 // UpdateProduct godoc
 // @Summary Update product
 // @Description Update seller's product
@@ -220,6 +487,12 @@ func (sc *SellerController) UpdateProduct(c *gin.Context) {
 		return
 	}
 
+	if req.Title != nil || req.Description != nil {
+		if err := sc.moderate(c, updatedProduct, updatedProduct.Title, updatedProduct.Description); handleError(c, err, apperrors.Internal("failed to moderate product")) {
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, updatedProduct)
 }
 
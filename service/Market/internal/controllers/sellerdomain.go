@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/sellerdomain"
+	"github.com/gin-gonic/gin"
+)
+
+type SellerDomainController struct {
+	sellerRepo     *repository.SellerRepository
+	storefrontRepo repository.StorefrontRepo
+	resolver       *sellerdomain.Resolver
+}
+
+func NewSellerDomainController(
+	sellerRepo *repository.SellerRepository,
+	storefrontRepo repository.StorefrontRepo,
+	resolver *sellerdomain.Resolver,
+) *SellerDomainController {
+	return &SellerDomainController{
+		sellerRepo:     sellerRepo,
+		storefrontRepo: storefrontRepo,
+		resolver:       resolver,
+	}
+}
+
+// SetCustomDomain godoc
+// @Summary Set custom domain
+// @Description Serve the seller's own storefront on a domain, whose DNS has to point at the marketplace. Requests for it only see the seller's products, and its storefront settings are managed at /api/seller/storefront-settings; changing the domain moves them along. The marketplace's own hosts cannot be claimed, and a domain can only belong to one seller (409).
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.SetCustomDomainRequest true "Domain"
+// @Success 200 {object} models.Seller
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/domain [put]
+func (dc *SellerDomainController) SetCustomDomain(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.SetCustomDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+	domain := sellerdomain.Host(req.Domain)
+	if dc.resolver.Reserved(domain) {
+		respondError(c, apperrors.ValidationError("domain", "is reserved by the marketplace"))
+		return
+	}
+
+	dc.setDomain(c, userID.(int), domain)
+}
+
+// RemoveCustomDomain godoc
+// @Summary Remove custom domain
+// @Description Stop serving the seller's storefront on its custom domain; its storefront settings are deleted
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.Seller
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/domain [delete]
+func (dc *SellerDomainController) RemoveCustomDomain(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	dc.setDomain(c, userID.(int), "")
+}
+
+func (dc *SellerDomainController) setDomain(c *gin.Context, userID int, domain string) {
+	seller, err := dc.sellerRepo.GetByUserID(c.Request.Context(), userID)
+	if handleError(c, err, apperrors.Forbidden("seller profile not found")) {
+		return
+	}
+
+	updated, err := dc.sellerRepo.SetCustomDomain(c.Request.Context(), seller.ID, domain)
+	if handleError(c, err, apperrors.Internal("failed to update custom domain")) {
+		return
+	}
+	dc.resolver.Forget(seller.CustomDomain)
+	dc.resolver.Forget(domain)
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// GetStorefrontSettings godoc
+// @Summary Get own storefront settings
+// @Description Get the storefront settings of the seller's custom domain; until they are changed these are the default settings, with tenant default
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.StorefrontSettings
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/storefront-settings [get]
+func (dc *SellerDomainController) GetStorefrontSettings(c *gin.Context) {
+	domain, ok := dc.sellerDomain(c)
+	if !ok {
+		return
+	}
+
+	settings, err := dc.storefrontRepo.Resolve(c.Request.Context(), domain)
+	if handleError(c, err, apperrors.Internal("failed to get storefront settings")) {
+		return
+	}
+	if settings == nil {
+		settings = models.DefaultStorefrontSettings()
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateStorefrontSettings godoc
+// @Summary Update own storefront settings
+// @Description Replace the storefront settings of the seller's custom domain
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpdateStorefrontSettingsRequest true "Settings"
+// @Success 200 {object} models.StorefrontSettings
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/storefront-settings [put]
+func (dc *SellerDomainController) UpdateStorefrontSettings(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.UpdateStorefrontSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	domain, ok := dc.sellerDomain(c)
+	if !ok {
+		return
+	}
+
+	settings, err := dc.storefrontRepo.Upsert(c.Request.Context(), domain, userID.(int), &req)
+	if handleError(c, err, apperrors.Internal("failed to update storefront settings")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// sellerDomain is the custom domain of the current user's seller account.
+// It responds with an error when there is none.
+func (dc *SellerDomainController) sellerDomain(c *gin.Context) (string, bool) {
+	userID, _ := c.Get("user_id")
+
+	seller, err := dc.sellerRepo.GetByUserID(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.Forbidden("seller profile not found")) {
+		return "", false
+	}
+	if seller.CustomDomain == "" {
+		respondError(c, apperrors.BadRequest("set a custom domain first"))
+		return "", false
+	}
+	return strings.ToLower(seller.CustomDomain), true
+}
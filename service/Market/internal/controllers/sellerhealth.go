@@ -0,0 +1,254 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/sellerhealth"
+	"github.com/gin-gonic/gin"
+)
+
+type SellerHealthController struct {
+	healthRepo repository.SellerHealthRepo
+	policy     sellerhealth.Policy
+}
+
+func NewSellerHealthController(healthRepo repository.SellerHealthRepo, policy sellerhealth.Policy) *SellerHealthController {
+	return &SellerHealthController{healthRepo: healthRepo, policy: policy}
+}
+
+func (hc *SellerHealthController) evaluate(c *gin.Context, counts *models.SellerHealthCounts) {
+	rules, err := hc.healthRepo.GetRules(c.Request.Context())
+	if handleError(c, err, apperrors.Internal("failed to get seller health rules")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, sellerhealth.Evaluate(counts, rules, hc.policy))
+}
+
+// GetMyHealth godoc
+// @Summary Get my seller health
+// @Description The current seller's cancellation, late shipment and dispute rates over the health window, the 0..100 score computed from them and the health rules currently breached. Rules can warn or suspend the shop.
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.SellerHealth
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/health [get]
+func (hc *SellerHealthController) GetMyHealth(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	counts, err := hc.healthRepo.GetCountsByUser(c.Request.Context(), userID.(int), hc.policy.Window, hc.policy.ShipWithin)
+	if handleError(c, err, apperrors.Internal("failed to get seller health")) {
+		return
+	}
+
+	hc.evaluate(c, counts)
+}
+
+// GetSellerHealth godoc
+// @Summary Get seller health
+// @Description A seller's health metrics, score and breached rules
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Seller ID"
+// @Success 200 {object} models.SellerHealth
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/sellers/{id}/health [get]
+func (hc *SellerHealthController) GetSellerHealth(c *gin.Context) {
+	sellerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("seller"))
+		return
+	}
+
+	counts, err := hc.healthRepo.GetCounts(c.Request.Context(), sellerID, hc.policy.Window, hc.policy.ShipWithin)
+	if handleError(c, err, apperrors.Internal("failed to get seller health")) {
+		return
+	}
+
+	hc.evaluate(c, counts)
+}
+
+// GetHealthRules godoc
+// @Summary Get seller health rules
+// @Description Get every seller health rule, oldest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.SellerHealthRule
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/seller-health/rules [get]
+func (hc *SellerHealthController) GetHealthRules(c *gin.Context) {
+	rules, err := hc.healthRepo.GetRules(c.Request.Context())
+	if handleError(c, err, apperrors.Internal("failed to get seller health rules")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+func bindHealthRule(c *gin.Context) (*models.SellerHealthRuleRequest, bool) {
+	var req models.SellerHealthRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return nil, false
+	}
+	if sellerhealth.IsRate(req.Metric) && *req.Threshold > 1 {
+		respondError(c, apperrors.ValidationError("threshold", "rates are fractions from 0 to 1"))
+		return nil, false
+	}
+	return &req, true
+}
+
+// CreateHealthRule godoc
+// @Summary Create seller health rule
+// @Description Warn or suspend active sellers whose metric breaches threshold: rates (0..1) above it, the score (0..100) below it. Sellers with fewer than min_orders orders (default 10) in the window are not judged. A rule acts on a seller at most once per cooldown.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.SellerHealthRuleRequest true "Rule"
+// @Success 201 {object} models.SellerHealthRule
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/seller-health/rules [post]
+func (hc *SellerHealthController) CreateHealthRule(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	req, ok := bindHealthRule(c)
+	if !ok {
+		return
+	}
+
+	rule, err := hc.healthRepo.CreateRule(c.Request.Context(), userID.(int), req)
+	if handleError(c, err, apperrors.Internal("failed to create seller health rule")) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateHealthRule godoc
+// @Summary Update seller health rule
+// @Description Replace a seller health rule
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Rule ID"
+// @Param request body models.SellerHealthRuleRequest true "Rule"
+// @Success 200 {object} models.SellerHealthRule
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/seller-health/rules/{id} [put]
+func (hc *SellerHealthController) UpdateHealthRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("seller health rule"))
+		return
+	}
+
+	req, ok := bindHealthRule(c)
+	if !ok {
+		return
+	}
+
+	rule, err := hc.healthRepo.UpdateRule(c.Request.Context(), id, req)
+	if handleError(c, err, apperrors.Internal("failed to update seller health rule")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteHealthRule godoc
+// @Summary Delete seller health rule
+// @Description Delete a seller health rule. Actions it took stay on record.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Rule ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/seller-health/rules/{id} [delete]
+func (hc *SellerHealthController) DeleteHealthRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("seller health rule"))
+		return
+	}
+
+	err = hc.healthRepo.DeleteRule(c.Request.Context(), id)
+	if handleError(c, err, apperrors.Internal("failed to delete seller health rule")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "seller health rule deleted"})
+}
+
+// GetHealthActions godoc
+// @Summary Get seller health actions
+// @Description Warnings and suspensions applied by seller health rules, newest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param seller_id query int false "Seller ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param count query string false "exact (default) or estimate"
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/seller-health/actions [get]
+func (hc *SellerHealthController) GetHealthActions(c *gin.Context) {
+	sellerID, ok := optionalIntQuery(c, "seller_id")
+	if !ok {
+		return
+	}
+
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		pagination = models.PaginationParams{Page: 1, PageSize: models.DefaultPageSize}
+	}
+	if pagination.Page < 1 {
+		pagination.Page = 1
+	}
+
+	actions, totalItems, err := hc.healthRepo.GetActions(c.Request.Context(), sellerID, &pagination)
+	if handleError(c, err, apperrors.Internal("failed to get seller health actions")) {
+		return
+	}
+
+	meta := models.NewPaginationMeta(pagination.Page, pagination.GetLimit(), totalItems)
+	meta.Estimated = pagination.EstimateCount()
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Data:       actions,
+		Pagination: meta,
+	})
+}
@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/sellerhealth"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSellerHealthRepo struct {
+	counts   map[int]*models.SellerHealthCounts // by user ID
+	rules    []*models.SellerHealthRule
+	createFn func(ctx context.Context, adminID int, req *models.SellerHealthRuleRequest) (*models.SellerHealthRule, error)
+	updateFn func(ctx context.Context, id int, req *models.SellerHealthRuleRequest) (*models.SellerHealthRule, error)
+}
+
+func (m *mockSellerHealthRepo) GetCounts(ctx context.Context, sellerID int, window, shipWithin time.Duration) (*models.SellerHealthCounts, error) {
+	for _, c := range m.counts {
+		if c.SellerID == sellerID {
+			return c, nil
+		}
+	}
+	return nil, apperrors.SellerNotFound(sellerID)
+}
+
+func (m *mockSellerHealthRepo) GetCountsByUser(ctx context.Context, userID int, window, shipWithin time.Duration) (*models.SellerHealthCounts, error) {
+	if c, ok := m.counts[userID]; ok {
+		return c, nil
+	}
+	return nil, apperrors.NotFound("seller profile not found")
+}
+
+func (m *mockSellerHealthRepo) GetRules(ctx context.Context) ([]*models.SellerHealthRule, error) {
+	return m.rules, nil
+}
+
+func (m *mockSellerHealthRepo) CreateRule(ctx context.Context, adminID int, req *models.SellerHealthRuleRequest) (*models.SellerHealthRule, error) {
+	return m.createFn(ctx, adminID, req)
+}
+
+func (m *mockSellerHealthRepo) UpdateRule(ctx context.Context, id int, req *models.SellerHealthRuleRequest) (*models.SellerHealthRule, error) {
+	return m.updateFn(ctx, id, req)
+}
+
+func (m *mockSellerHealthRepo) DeleteRule(ctx context.Context, id int) error {
+	return nil
+}
+
+func (m *mockSellerHealthRepo) GetActions(ctx context.Context, sellerID *int, p *models.PaginationParams) ([]*models.SellerHealthAction, int64, error) {
+	return []*models.SellerHealthAction{}, 0, nil
+}
+
+var _ repository.SellerHealthRepo = (*mockSellerHealthRepo)(nil)
+
+var testHealthPolicy = sellerhealth.Policy{Window: 30 * 24 * time.Hour, ShipWithin: 48 * time.Hour}
+
+func TestSellerHealthController_GetMyHealth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &mockSellerHealthRepo{
+		counts: map[int]*models.SellerHealthCounts{
+			8: {SellerID: 3, UserID: 8, IsActive: true, Orders: 20, Cancelled: 2, Late: 4},
+		},
+		rules: []*models.SellerHealthRule{
+			{ID: 1, Metric: models.HealthMetricLateShipmentRate, Threshold: 0.1, Action: models.HealthActionWarn, MinOrders: 10, Enabled: true},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		userID   int
+		wantCode int
+	}{
+		{name: "seller", userID: 8, wantCode: http.StatusOK},
+		{name: "no seller profile", userID: 9, wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("GET", "/api/seller/health", nil)
+			c.Set("user_id", tt.userID)
+
+			NewSellerHealthController(repo, testHealthPolicy).GetMyHealth(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var got models.SellerHealth
+			require.NoError(t, json.Unmarshal(r.Body.Bytes(), &got))
+			require.Equal(t, 30, got.WindowDays)
+			require.Equal(t, 0.1, got.CancellationRate)
+			require.Equal(t, 0.2, got.LateShipmentRate)
+			require.Equal(t, 60.0, got.Score)
+			require.Len(t, got.Breaches, 1)
+			require.Equal(t, 1, got.Breaches[0].RuleID)
+		})
+	}
+}
+
+func TestSellerHealthController_CreateHealthRule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{name: "rate rule", body: `{"metric":"dispute_rate","threshold":0.02,"action":"suspend","min_orders":25}`, wantCode: http.StatusCreated},
+		{name: "score rule", body: `{"metric":"score","threshold":60,"action":"warn"}`, wantCode: http.StatusCreated},
+		{name: "rate above one", body: `{"metric":"dispute_rate","threshold":2,"action":"warn"}`, wantCode: http.StatusBadRequest},
+		{name: "score above 100", body: `{"metric":"score","threshold":120,"action":"warn"}`, wantCode: http.StatusBadRequest},
+		{name: "unknown metric", body: `{"metric":"returns","threshold":0.1,"action":"warn"}`, wantCode: http.StatusBadRequest},
+		{name: "unknown action", body: `{"metric":"score","threshold":60,"action":"ban"}`, wantCode: http.StatusBadRequest},
+		{name: "missing threshold", body: `{"metric":"score","action":"warn"}`, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("POST", "/api/admin/seller-health/rules", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("user_id", 1)
+
+			created := false
+			NewSellerHealthController(&mockSellerHealthRepo{
+				createFn: func(ctx context.Context, adminID int, req *models.SellerHealthRuleRequest) (*models.SellerHealthRule, error) {
+					created = true
+					require.Equal(t, 1, adminID)
+					return &models.SellerHealthRule{ID: 4, Metric: req.Metric, Threshold: *req.Threshold, Action: req.Action, Enabled: true}, nil
+				},
+			}, testHealthPolicy).CreateHealthRule(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+			require.Equal(t, tt.wantCode == http.StatusCreated, created)
+		})
+	}
+}
+
+func TestSellerHealthController_UpdateHealthRule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		param    string
+		wantCode int
+	}{
+		{name: "existing rule", param: "4", wantCode: http.StatusOK},
+		{name: "missing rule", param: "5", wantCode: http.StatusNotFound},
+		{name: "invalid id", param: "abc", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("PUT", "/api/admin/seller-health/rules/"+tt.param,
+				bytes.NewBufferString(`{"metric":"score","threshold":50,"action":"suspend","enabled":false}`))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: tt.param}}
+
+			NewSellerHealthController(&mockSellerHealthRepo{
+				updateFn: func(ctx context.Context, id int, req *models.SellerHealthRuleRequest) (*models.SellerHealthRule, error) {
+					if id != 4 {
+						return nil, apperrors.NotFound("seller health rule not found")
+					}
+					require.False(t, *req.Enabled)
+					return &models.SellerHealthRule{ID: id, Metric: req.Metric, Threshold: *req.Threshold, Action: req.Action}, nil
+				},
+			}, testHealthPolicy).UpdateHealthRule(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/packingslip"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type SellerOrderController struct {
+	orderRepo repository.SellerOrderRepo
+}
+
+func NewSellerOrderController(orderRepo repository.SellerOrderRepo) *SellerOrderController {
+	return &SellerOrderController{orderRepo: orderRepo}
+}
+
+// linkPackingSlips points gift orders at their packing slip.
+func linkPackingSlips(orders ...*models.SellerOrder) {
+	for _, order := range orders {
+		if order.IsGift {
+			order.PackingSlipURL = fmt.Sprintf("/api/seller/orders/%d/packing-slip", order.ID)
+		}
+	}
+}
+
+// GetSellerOrders godoc
+// @Summary Get seller orders
+// @Description Orders containing the seller's products, newest first. Each order lists only the seller's items, with seller_total as their sum.
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param fulfillment_status query string false "Only orders with an item in this status: pending, processing, shipped, delivered or cancelled"
+// @Param count query string false "exact (default) or estimate"
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/orders [get]
+func (sc *SellerOrderController) GetSellerOrders(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		pagination = models.PaginationParams{Page: 1, PageSize: models.DefaultPageSize}
+	}
+	if pagination.Page < 1 {
+		pagination.Page = 1
+	}
+
+	status := c.Query("fulfillment_status")
+	if status != "" && !models.IsFulfillmentStatus(status) {
+		respondError(c, apperrors.BadRequest("invalid fulfillment_status"))
+		return
+	}
+
+	orders, totalItems, err := sc.orderRepo.GetSellerOrders(c.Request.Context(), userID.(int), status, &pagination)
+	if handleError(c, err, apperrors.Internal("failed to get orders")) {
+		return
+	}
+
+	linkPackingSlips(orders...)
+
+	meta := models.NewPaginationMeta(pagination.Page, pagination.GetLimit(), totalItems)
+	meta.Estimated = pagination.EstimateCount()
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Data:       orders,
+		Pagination: meta,
+	})
+}
+
+// GetSellerOrder godoc
+// @Summary Get seller order
+// @Description An order containing the seller's products, with only the seller's items
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} models.SellerOrder
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/orders/{id} [get]
+func (sc *SellerOrderController) GetSellerOrder(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("order"))
+		return
+	}
+
+	order, err := sc.orderRepo.GetSellerOrder(c.Request.Context(), orderID, userID.(int))
+	if handleError(c, err, apperrors.Internal("failed to get order")) {
+		return
+	}
+
+	linkPackingSlips(order)
+	c.JSON(http.StatusOK, order)
+}
+
+// UpdateFulfillment godoc
+// @Summary Update fulfillment status
+// @Description Move the seller's items of an order, or only item_ids among them, along pending → processing → shipped → delivered, or cancel them before they ship. Without item_ids, items that cannot make the move are left as they are.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param request body models.UpdateFulfillmentRequest true "New fulfillment status"
+// @Success 200 {object} models.SellerOrder
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/orders/{id}/fulfillment [put]
+func (sc *SellerOrderController) UpdateFulfillment(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("order"))
+		return
+	}
+
+	var req models.UpdateFulfillmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	order, err := sc.orderRepo.UpdateFulfillment(c.Request.Context(), orderID, userID.(int), &req)
+	if handleError(c, err, apperrors.Internal("failed to update fulfillment status")) {
+		return
+	}
+
+	linkPackingSlips(order)
+	c.JSON(http.StatusOK, order)
+}
+
+// GetPackingSlip godoc
+// @Summary Download packing slip
+// @Description Packing slip PDF of a gift order for the seller's parcel: the seller's items, sizes and quantities, the delivery address and the gift message, without any prices. Gift orders link to it as packing_slip_url.
+// @Tags seller
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/orders/{id}/packing-slip [get]
+func (sc *SellerOrderController) GetPackingSlip(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("order"))
+		return
+	}
+
+	slip, err := sc.orderRepo.GetPackingSlip(c.Request.Context(), orderID, userID.(int))
+	if handleError(c, err, apperrors.Internal("failed to get packing slip")) {
+		return
+	}
+
+	pdf, err := packingslip.PDF(slip)
+	if handleError(c, err, apperrors.Internal("failed to render packing slip")) {
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="packing-slip-%s.pdf"`, slip.OrderNumber))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
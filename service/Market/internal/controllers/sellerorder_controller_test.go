@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSellerOrderRepo struct {
+	getOrdersFn         func(ctx context.Context, sellerUserID int, status string, p *models.PaginationParams) ([]*models.SellerOrder, int64, error)
+	getOrderFn          func(ctx context.Context, orderID, sellerUserID int) (*models.SellerOrder, error)
+	updateFulfillmentFn func(ctx context.Context, orderID, sellerUserID int, req *models.UpdateFulfillmentRequest) (*models.SellerOrder, error)
+	getPackingSlipFn    func(ctx context.Context, orderID, sellerUserID int) (*models.PackingSlip, error)
+}
+
+func (m *mockSellerOrderRepo) GetSellerOrders(ctx context.Context, sellerUserID int, status string, p *models.PaginationParams) ([]*models.SellerOrder, int64, error) {
+	return m.getOrdersFn(ctx, sellerUserID, status, p)
+}
+
+func (m *mockSellerOrderRepo) GetSellerOrder(ctx context.Context, orderID, sellerUserID int) (*models.SellerOrder, error) {
+	return m.getOrderFn(ctx, orderID, sellerUserID)
+}
+
+func (m *mockSellerOrderRepo) UpdateFulfillment(ctx context.Context, orderID, sellerUserID int, req *models.UpdateFulfillmentRequest) (*models.SellerOrder, error) {
+	return m.updateFulfillmentFn(ctx, orderID, sellerUserID, req)
+}
+
+func (m *mockSellerOrderRepo) GetPackingSlip(ctx context.Context, orderID, sellerUserID int) (*models.PackingSlip, error) {
+	return m.getPackingSlipFn(ctx, orderID, sellerUserID)
+}
+
+var _ repository.SellerOrderRepo = (*mockSellerOrderRepo)(nil)
+
+func TestSellerOrderController_GetSellerOrders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantCode   int
+		wantStatus string
+	}{
+		{name: "all orders", query: "", wantCode: http.StatusOK},
+		{name: "by fulfillment status", query: "?fulfillment_status=shipped", wantCode: http.StatusOK, wantStatus: "shipped"},
+		{name: "unknown fulfillment status", query: "?fulfillment_status=lost", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("GET", "/api/seller/orders"+tt.query, nil)
+			c.Set("user_id", 8)
+
+			sc := NewSellerOrderController(&mockSellerOrderRepo{
+				getOrdersFn: func(ctx context.Context, sellerUserID int, status string, p *models.PaginationParams) ([]*models.SellerOrder, int64, error) {
+					require.Equal(t, 8, sellerUserID)
+					require.Equal(t, tt.wantStatus, status)
+					return []*models.SellerOrder{{
+						Order:       models.Order{ID: 4},
+						Items:       []models.OrderItem{{ID: 9, OrderID: 4, Quantity: 2, Price: 5, FulfillmentStatus: "shipped"}},
+						SellerTotal: 10,
+					}}, 1, nil
+				},
+			})
+			sc.GetSellerOrders(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var got struct {
+				Data       []models.SellerOrder  `json:"data"`
+				Pagination models.PaginationMeta `json:"pagination"`
+			}
+			require.NoError(t, json.Unmarshal(r.Body.Bytes(), &got))
+			require.Len(t, got.Data, 1)
+			require.Equal(t, 10.0, got.Data[0].SellerTotal)
+			require.Equal(t, int64(1), got.Pagination.TotalItems)
+		})
+	}
+}
+
+func TestSellerOrderController_GetSellerOrder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		param    string
+		wantCode int
+	}{
+		{name: "own order", param: "4", wantCode: http.StatusOK},
+		{name: "gift order", param: "6", wantCode: http.StatusOK},
+		{name: "order without the seller's items", param: "5", wantCode: http.StatusNotFound},
+		{name: "invalid id", param: "abc", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("GET", "/api/seller/orders/"+tt.param, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.param}}
+			c.Set("user_id", 8)
+
+			sc := NewSellerOrderController(&mockSellerOrderRepo{
+				getOrderFn: func(ctx context.Context, orderID, sellerUserID int) (*models.SellerOrder, error) {
+					if orderID != 4 && orderID != 6 {
+						return nil, apperrors.OrderNotFound(orderID)
+					}
+					return &models.SellerOrder{Order: models.Order{ID: orderID, IsGift: orderID == 6}}, nil
+				},
+			})
+			sc.GetSellerOrder(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var got models.SellerOrder
+			require.NoError(t, json.Unmarshal(r.Body.Bytes(), &got))
+			if got.IsGift {
+				require.Equal(t, "/api/seller/orders/6/packing-slip", got.PackingSlipURL)
+			} else {
+				require.Empty(t, got.PackingSlipURL)
+			}
+		})
+	}
+}
+
+func TestSellerOrderController_GetPackingSlip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		param    string
+		wantCode int
+	}{
+		{name: "gift order", param: "6", wantCode: http.StatusOK},
+		{name: "not a gift", param: "4", wantCode: http.StatusConflict},
+		{name: "invalid id", param: "abc", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("GET", "/api/seller/orders/"+tt.param+"/packing-slip", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.param}}
+			c.Set("user_id", 8)
+
+			sc := NewSellerOrderController(&mockSellerOrderRepo{
+				getPackingSlipFn: func(ctx context.Context, orderID, sellerUserID int) (*models.PackingSlip, error) {
+					require.Equal(t, 8, sellerUserID)
+					if orderID != 6 {
+						return nil, apperrors.Conflict("packing slips are only made for gift orders")
+					}
+					return &models.PackingSlip{
+						OrderNumber: "MB-2026-000006",
+						ShopName:    "Shop",
+						Items:       []models.PackingSlipItem{{Title: "Mug", Quantity: 1}},
+					}, nil
+				},
+			})
+			sc.GetPackingSlip(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			require.Equal(t, "application/pdf", r.Header().Get("Content-Type"))
+			require.True(t, bytes.HasPrefix(r.Body.Bytes(), []byte("%PDF-")))
+		})
+	}
+}
+
+func TestSellerOrderController_UpdateFulfillment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		body     string
+		repoErr  error
+		wantCode int
+	}{
+		{name: "ship all items", body: `{"status":"shipped"}`, wantCode: http.StatusOK},
+		{name: "ship some items", body: `{"status":"shipped","item_ids":[9]}`, wantCode: http.StatusOK},
+		{name: "back to pending", body: `{"status":"pending"}`, wantCode: http.StatusBadRequest},
+		{name: "invalid item id", body: `{"status":"shipped","item_ids":[0]}`, wantCode: http.StatusBadRequest},
+		{name: "invalid transition", body: `{"status":"delivered"}`, repoErr: apperrors.Conflict("no items can move to delivered"), wantCode: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("PUT", "/api/seller/orders/4/fulfillment", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: "4"}}
+			c.Set("user_id", 8)
+
+			sc := NewSellerOrderController(&mockSellerOrderRepo{
+				updateFulfillmentFn: func(ctx context.Context, orderID, sellerUserID int, req *models.UpdateFulfillmentRequest) (*models.SellerOrder, error) {
+					require.Equal(t, 4, orderID)
+					require.Equal(t, 8, sellerUserID)
+					if tt.repoErr != nil {
+						return nil, tt.repoErr
+					}
+					return &models.SellerOrder{
+						Order: models.Order{ID: orderID},
+						Items: []models.OrderItem{{ID: 9, FulfillmentStatus: req.Status}},
+					}, nil
+				},
+			})
+			sc.UpdateFulfillment(c)
+
+			require.Equal(t, tt.wantCode, r.Code, r.Body.String())
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// sellerStatsDefaultDays is the range reported without from and to.
+	sellerStatsDefaultDays = 30
+	// sellerStatsMaxDays bounds the range one request aggregates.
+	sellerStatsMaxDays = 366
+)
+
+type SellerStatsController struct {
+	statsRepo repository.SellerStatsRepo
+	lowStock  int
+	now       func() time.Time
+}
+
+func NewSellerStatsController(statsRepo repository.SellerStatsRepo, lowStock int) *SellerStatsController {
+	return &SellerStatsController{statsRepo: statsRepo, lowStock: lowStock, now: time.Now}
+}
+
+// GetSellerStats godoc
+// @Summary Get seller analytics
+// @Description Revenue over time (net of seller-funded discounts, without cancelled and refunded orders), units sold, orders and product page views per product with their conversion, and active products and variants low on stock. Covers the last 30 days unless from and to are given, at most 366 days.
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "First day, YYYY-MM-DD"
+// @Param to query string false "Last day, YYYY-MM-DD (default today)"
+// @Param interval query string false "Revenue buckets: day, week or month (default day)"
+// @Success 200 {object} models.SellerStats
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/stats [get]
+func (sc *SellerStatsController) GetSellerStats(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var query models.SellerStatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+	if query.Interval == "" {
+		query.Interval = "day"
+	}
+
+	now := sc.now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if query.To != nil {
+		to = query.To.UTC()
+	}
+	from := to.AddDate(0, 0, 1-sellerStatsDefaultDays)
+	if query.From != nil {
+		from = query.From.UTC()
+	}
+	if to.Before(from) {
+		respondError(c, apperrors.ValidationError("to", "must not be before from"))
+		return
+	}
+	if to.Sub(from) >= sellerStatsMaxDays*24*time.Hour {
+		respondError(c, apperrors.ValidationError("from", "range must be at most 366 days"))
+		return
+	}
+
+	stats, err := sc.statsRepo.GetSellerStats(c.Request.Context(), userID.(int), from, to.AddDate(0, 0, 1), query.Interval, sc.lowStock)
+	if handleError(c, err, apperrors.Internal("failed to get seller stats")) {
+		return
+	}
+	stats.From = from.Format("2006-01-02")
+	stats.To = to.Format("2006-01-02")
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetLowStock godoc
+// @Summary Get low-stock products
+// @Description Active products and variants at or below their product's low_stock_threshold, or the site default for products without one, emptiest first. Sellers are also notified once when an order runs a product low, and again after it was restocked above its threshold.
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.LowStockAlert
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/products/low-stock [get]
+func (sc *SellerStatsController) GetLowStock(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	alerts, err := sc.statsRepo.GetLowStock(c.Request.Context(), userID.(int), sc.lowStock)
+	if handleError(c, err, apperrors.Internal("failed to get low stock products")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, alerts)
+}
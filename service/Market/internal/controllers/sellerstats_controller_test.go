@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSellerStatsRepo struct {
+	getFn      func(ctx context.Context, userID int, from, to time.Time, interval string, lowStock int) (*models.SellerStats, error)
+	lowStockFn func(ctx context.Context, userID, threshold int) ([]*models.LowStockAlert, error)
+}
+
+func (m *mockSellerStatsRepo) GetSellerStats(ctx context.Context, userID int, from, to time.Time, interval string, lowStock int) (*models.SellerStats, error) {
+	return m.getFn(ctx, userID, from, to, interval, lowStock)
+}
+
+func (m *mockSellerStatsRepo) GetLowStock(ctx context.Context, userID, threshold int) ([]*models.LowStockAlert, error) {
+	return m.lowStockFn(ctx, userID, threshold)
+}
+
+var _ repository.SellerStatsRepo = (*mockSellerStatsRepo)(nil)
+
+func TestSellerStatsController_GetSellerStats_DefaultRange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/seller/stats", nil)
+	c.Set("user_id", 8)
+
+	m := &mockSellerStatsRepo{
+		getFn: func(ctx context.Context, userID int, from, to time.Time, interval string, lowStock int) (*models.SellerStats, error) {
+			require.Equal(t, 8, userID)
+			require.Equal(t, time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), from)
+			require.Equal(t, time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC), to, "the last day is included")
+			require.Equal(t, "day", interval)
+			require.Equal(t, 3, lowStock)
+			return &models.SellerStats{
+				Interval: interval,
+				LowStock: []*models.LowStockAlert{{ProductID: 4, Title: "Mug", Stock: 1}},
+			}, nil
+		},
+	}
+	sc := NewSellerStatsController(m, 3)
+	sc.now = func() time.Time { return time.Date(2026, 3, 11, 15, 0, 0, 0, time.UTC) }
+
+	sc.GetSellerStats(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	require.Contains(t, r.Body.String(), `"from":"2026-02-10"`)
+	require.Contains(t, r.Body.String(), `"to":"2026-03-11"`)
+	require.Contains(t, r.Body.String(), `"stock":1`)
+}
+
+func TestSellerStatsController_GetSellerStats_Invalid(t *testing.T) {
+	for name, url := range map[string]string{
+		"interval": "/api/seller/stats?interval=year",
+		"reversed": "/api/seller/stats?from=2026-03-01&to=2026-02-01",
+		"too long": "/api/seller/stats?from=2024-01-01&to=2026-02-01",
+		"bad date": "/api/seller/stats?from=01/02/2026",
+	} {
+		t.Run(name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("GET", url, nil)
+			c.Set("user_id", 8)
+
+			NewSellerStatsController(&mockSellerStatsRepo{}, 3).GetSellerStats(c)
+
+			require.Equal(t, http.StatusBadRequest, r.Code)
+		})
+	}
+}
+
+func TestSellerStatsController_GetLowStock(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/seller/products/low-stock", nil)
+	c.Set("user_id", 8)
+
+	m := &mockSellerStatsRepo{
+		lowStockFn: func(ctx context.Context, userID, threshold int) ([]*models.LowStockAlert, error) {
+			require.Equal(t, 8, userID)
+			require.Equal(t, 3, threshold)
+			return []*models.LowStockAlert{{ProductID: 4, Title: "Mug", Stock: 1, Threshold: 2}}, nil
+		},
+	}
+
+	NewSellerStatsController(m, 3).GetLowStock(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	require.Contains(t, r.Body.String(), `"threshold":2`)
+}
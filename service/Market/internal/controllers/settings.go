@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/settings"
+	"github.com/gin-gonic/gin"
+)
+
+type SettingsController struct {
+	settings *settings.Store
+}
+
+func NewSettingsController(siteSettings *settings.Store) *SettingsController {
+	return &SettingsController{settings: siteSettings}
+}
+
+// GetCheckoutSettings godoc
+// @Summary Get checkout settings
+// @Description Get the minimum order amount and the free-shipping threshold so the storefront can show them in the cart; 0 means disabled
+// @Tags orders
+// @Produce json
+// @Success 200 {object} map[string]float64
+// @Router /api/checkout-settings [get]
+func (sc *SettingsController) GetCheckoutSettings(c *gin.Context) {
+	ctx := c.Request.Context()
+	c.JSON(http.StatusOK, gin.H{
+		"min_order_amount":        sc.settings.MinOrderAmount(ctx),
+		"free_shipping_threshold": sc.settings.FreeShippingThreshold(ctx),
+	})
+}
+
+// GetSettings godoc
+// @Summary Get settings
+// @Description List every site-wide setting with its type, default and current value
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} settings.Entry
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/settings [get]
+func (sc *SettingsController) GetSettings(c *gin.Context) {
+	entries, err := sc.settings.List(c.Request.Context())
+	if handleError(c, err, apperrors.Internal("failed to get settings")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// UpdateSetting godoc
+// @Summary Update setting
+// @Description Override a site-wide setting. Amounts must be non-negative numbers, emails valid addresses or empty.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Setting key"
+// @Param request body models.UpdateSettingRequest true "Value"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/settings/{key} [put]
+func (sc *SettingsController) UpdateSetting(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.UpdateSettingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	err := sc.settings.Set(c.Request.Context(), c.Param("key"), req.Value, userID.(int))
+	if handleError(c, err, apperrors.Internal("failed to update setting")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "setting updated"})
+}
+
+// ResetSetting godoc
+// @Summary Reset setting
+// @Description Remove the override of a setting so its default applies again
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Setting key"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/settings/{key} [delete]
+func (sc *SettingsController) ResetSetting(c *gin.Context) {
+	err := sc.settings.Reset(c.Request.Context(), c.Param("key"))
+	if handleError(c, err, apperrors.Internal("failed to reset setting")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "setting reset to default"})
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type ShareController struct {
+	shareRepo  repository.ShareLinkRepo
+	baseURL    string
+	productURL string
+}
+
+// NewShareController builds short links as baseURL + "/s/" + code and
+// redirects them to productURL with "{id}" replaced by the product ID.
+func NewShareController(shareRepo repository.ShareLinkRepo, baseURL, productURL string) *ShareController {
+	return &ShareController{
+		shareRepo:  shareRepo,
+		baseURL:    baseURL,
+		productURL: productURL,
+	}
+}
+
+// CreateShareLink godoc
+// @Summary Share a product
+// @Description Get a short, trackable link to an active product. Sharing the same product to the same source again returns the existing link.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param request body models.CreateShareLinkRequest false "Where the link will be posted"
+// @Success 201 {object} models.ShareLink
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/products/{id}/share [post]
+func (sc *ShareController) CreateShareLink(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("product"))
+		return
+	}
+
+	var req models.CreateShareLinkRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, apperrors.BadRequest(err.Error()))
+			return
+		}
+	}
+
+	link, err := sc.shareRepo.Create(c.Request.Context(), productID, userID.(int), strings.ToLower(strings.TrimSpace(req.Source)))
+	if handleError(c, err, apperrors.Internal("failed to create share link")) {
+		return
+	}
+	link.URL = sc.baseURL + "/s/" + link.Code
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// FollowShareLink godoc
+// @Summary Follow a share link
+// @Description Count a click on a short link and redirect to the product page
+// @Tags products
+// @Param code path string true "Short link code"
+// @Success 302
+// @Failure 404 {object} map[string]string
+// @Router /s/{code} [get]
+func (sc *ShareController) FollowShareLink(c *gin.Context) {
+	productID, err := sc.shareRepo.Click(c.Request.Context(), c.Param("code"))
+	if handleError(c, err, apperrors.Internal("failed to follow share link")) {
+		return
+	}
+
+	c.Redirect(http.StatusFound, strings.ReplaceAll(sc.productURL, "{id}", strconv.Itoa(productID)))
+}
+
+// GetShareStats godoc
+// @Summary Get share link analytics
+// @Description Links and clicks of shared links to the seller's products, per product and source, most clicked first
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.ShareLinkStats
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/analytics/shares [get]
+func (sc *ShareController) GetShareStats(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	stats, err := sc.shareRepo.GetSellerStats(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.Internal("failed to get share link stats")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockShareLinkRepo struct {
+	createFn func(ctx context.Context, productID, userID int, source string) (*models.ShareLink, error)
+	clickFn  func(ctx context.Context, code string) (int, error)
+	statsFn  func(ctx context.Context, userID int) ([]*models.ShareLinkStats, error)
+}
+
+func (m *mockShareLinkRepo) Create(ctx context.Context, productID, userID int, source string) (*models.ShareLink, error) {
+	return m.createFn(ctx, productID, userID, source)
+}
+
+func (m *mockShareLinkRepo) Click(ctx context.Context, code string) (int, error) {
+	return m.clickFn(ctx, code)
+}
+
+func (m *mockShareLinkRepo) GetSellerStats(ctx context.Context, userID int) ([]*models.ShareLinkStats, error) {
+	return m.statsFn(ctx, userID)
+}
+
+var _ repository.ShareLinkRepo = (*mockShareLinkRepo)(nil)
+
+func newShareTestController(m *mockShareLinkRepo) *ShareController {
+	return NewShareController(m, "https://mk.example.com", "https://shop.example.com/products/{id}")
+}
+
+func TestShareController_CreateShareLink(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("POST", "/api/products/7/share", strings.NewReader(`{"source":" Instagram "}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set("user_id", 3)
+
+	m := &mockShareLinkRepo{
+		createFn: func(ctx context.Context, productID, userID int, source string) (*models.ShareLink, error) {
+			require.Equal(t, 7, productID)
+			require.Equal(t, 3, userID)
+			require.Equal(t, "instagram", source)
+			return &models.ShareLink{ID: 1, Code: "aB3dE5gH", ProductID: productID, Source: source}, nil
+		},
+	}
+	newShareTestController(m).CreateShareLink(c)
+
+	require.Equal(t, http.StatusCreated, r.Code)
+	var link models.ShareLink
+	require.NoError(t, json.Unmarshal(r.Body.Bytes(), &link))
+	require.Equal(t, "https://mk.example.com/s/aB3dE5gH", link.URL)
+}
+
+func TestShareController_CreateShareLink_WithoutBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("POST", "/api/products/7/share", nil)
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set("user_id", 3)
+
+	m := &mockShareLinkRepo{
+		createFn: func(ctx context.Context, productID, userID int, source string) (*models.ShareLink, error) {
+			require.Empty(t, source)
+			return nil, apperrors.ProductNotFound(productID)
+		},
+	}
+	newShareTestController(m).CreateShareLink(c)
+
+	require.Equal(t, http.StatusNotFound, r.Code)
+}
+
+func TestShareController_FollowShareLink(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/s/aB3dE5gH", nil)
+	c.Params = gin.Params{{Key: "code", Value: "aB3dE5gH"}}
+
+	m := &mockShareLinkRepo{
+		clickFn: func(ctx context.Context, code string) (int, error) {
+			require.Equal(t, "aB3dE5gH", code)
+			return 7, nil
+		},
+	}
+	newShareTestController(m).FollowShareLink(c)
+
+	require.Equal(t, http.StatusFound, r.Code)
+	require.Equal(t, "https://shop.example.com/products/7", r.Header().Get("Location"))
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/shipping"
+	"github.com/gin-gonic/gin"
+)
+
+type ShippingController struct {
+	sellerRepo   *repository.SellerRepository
+	productRepo  *repository.ProductRepository
+	shippingRepo *repository.ShippingRepository
+}
+
+func NewShippingController(
+	sellerRepo *repository.SellerRepository,
+	productRepo *repository.ProductRepository,
+	shippingRepo *repository.ShippingRepository,
+) *ShippingController {
+	return &ShippingController{
+		sellerRepo:   sellerRepo,
+		productRepo:  productRepo,
+		shippingRepo: shippingRepo,
+	}
+}
+
+// GetProductShipping godoc
+// @Summary Get product shipping restrictions
+// @Description Get the countries and regions a product ships to. Empty ships_to means worldwide except no_ship_to.
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} models.ShippingRestrictions
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/products/{id}/shipping [get]
+func (sc *ShippingController) GetProductShipping(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("product"))
+		return
+	}
+
+	if _, err := sc.productRepo.GetByID(c.Request.Context(), productID); err != nil {
+		respondError(c, apperrors.ProductNotFound(productID))
+		return
+	}
+
+	restrictions, err := sc.shippingRepo.Get(c.Request.Context(), productID)
+	if handleError(c, err, apperrors.Internal("failed to get shipping restrictions")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, restrictions)
+}
+
+// UpdateProductShipping godoc
+// @Summary Set product shipping restrictions
+// @Description Replace the countries (ISO 3166-1 alpha-2, e.g. DE) and regions (ISO 3166-2, e.g. US-AK) a product ships to or is blocked from
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param request body models.UpdateShippingRestrictionsRequest true "Restrictions"
+// @Success 200 {object} models.ShippingRestrictions
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/products/{id}/shipping [put]
+func (sc *ShippingController) UpdateProductShipping(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("product"))
+		return
+	}
+
+	seller, err := sc.sellerRepo.GetByUserID(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.Forbidden("seller profile not found")) {
+		return
+	}
+
+	product, err := sc.productRepo.GetByID(c.Request.Context(), productID)
+	if err != nil || product.SellerID != seller.ID {
+		respondError(c, apperrors.Forbidden("product not found or access denied"))
+		return
+	}
+
+	var req models.UpdateShippingRestrictionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	shipsTo, err := shipping.NormalizeCodes(req.ShipsTo)
+	if err != nil {
+		respondError(c, apperrors.ValidationError("ships_to", err.Error()))
+		return
+	}
+	noShipTo, err := shipping.NormalizeCodes(req.NoShipTo)
+	if err != nil {
+		respondError(c, apperrors.ValidationError("no_ship_to", err.Error()))
+		return
+	}
+
+	restrictions, err := sc.shippingRepo.Set(c.Request.Context(), productID, shipsTo, noShipTo)
+	if handleError(c, err, apperrors.Internal("failed to update shipping restrictions")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, restrictions)
+}
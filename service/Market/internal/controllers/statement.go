@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/statements"
+	"github.com/gin-gonic/gin"
+)
+
+type StatementController struct {
+	statementRepo repository.StatementRepo
+}
+
+func NewStatementController(statementRepo repository.StatementRepo) *StatementController {
+	return &StatementController{statementRepo: statementRepo}
+}
+
+// GetStatements godoc
+// @Summary Get seller statements
+// @Description List monthly statements (sales, refunds, commission, payout). With period, download that month's statement as CSV or PDF.
+// @Tags seller
+// @Produce json
+// @Produce text/csv
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param period query string false "Month to download, YYYY-MM"
+// @Param format query string false "csv or pdf (default pdf), used with period"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/statements [get]
+func (sc *StatementController) GetStatements(c *gin.Context) {
+	if c.Query("period") != "" {
+		sc.downloadStatement(c)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		pagination = models.PaginationParams{Page: 1, PageSize: models.DefaultPageSize}
+	}
+	if pagination.Page < 1 {
+		pagination.Page = 1
+	}
+
+	list, totalItems, err := sc.statementRepo.GetSellerStatements(c.Request.Context(), userID.(int), &pagination)
+	if handleError(c, err, apperrors.Internal("failed to get statements")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Data:       list,
+		Pagination: models.NewPaginationMeta(pagination.Page, pagination.GetLimit(), totalItems),
+	})
+}
+
+func (sc *StatementController) downloadStatement(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	period, err := statements.ParsePeriod(c.Query("period"))
+	if err != nil {
+		respondError(c, apperrors.ValidationError("period", err.Error()))
+		return
+	}
+
+	format := c.DefaultQuery("format", "pdf")
+	if format != "pdf" && format != "csv" {
+		respondError(c, apperrors.ValidationError("format", "must be csv or pdf"))
+		return
+	}
+
+	st, err := sc.statementRepo.GetSellerStatement(c.Request.Context(), userID.(int), period)
+	if handleError(c, err, apperrors.Internal("failed to get statement")) {
+		return
+	}
+
+	filename := fmt.Sprintf("statement-%s.%s", st.Period, format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	if format == "csv" {
+		var buf bytes.Buffer
+		if err := statements.WriteCSV(&buf, st); handleError(c, err, apperrors.Internal("failed to render statement")) {
+			return
+		}
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+		return
+	}
+
+	pdf, err := statements.PDF(st)
+	if handleError(c, err, apperrors.Internal("failed to render statement")) {
+		return
+	}
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
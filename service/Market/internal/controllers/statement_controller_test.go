@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStatementRepo struct {
+	listFn func(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.SellerStatement, int64, error)
+	getFn  func(ctx context.Context, userID int, period time.Time) (*models.SellerStatement, error)
+}
+
+func (m *mockStatementRepo) GetSellerStatements(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.SellerStatement, int64, error) {
+	return m.listFn(ctx, userID, pagination)
+}
+
+func (m *mockStatementRepo) GetSellerStatement(ctx context.Context, userID int, period time.Time) (*models.SellerStatement, error) {
+	return m.getFn(ctx, userID, period)
+}
+
+var _ repository.StatementRepo = (*mockStatementRepo)(nil)
+
+func TestStatementController_GetStatements_List(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/seller/statements", nil)
+	c.Set("user_id", 8)
+
+	m := &mockStatementRepo{
+		listFn: func(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.SellerStatement, int64, error) {
+			require.Equal(t, 8, userID)
+			return []*models.SellerStatement{{ID: 1, Period: "2026-02"}}, 1, nil
+		},
+	}
+
+	NewStatementController(m).GetStatements(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	require.Contains(t, r.Body.String(), `"period":"2026-02"`)
+}
+
+func TestStatementController_GetStatements_DownloadCSV(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/seller/statements?period=2026-02&format=csv", nil)
+	c.Set("user_id", 8)
+
+	m := &mockStatementRepo{
+		getFn: func(ctx context.Context, userID int, period time.Time) (*models.SellerStatement, error) {
+			require.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), period)
+			return &models.SellerStatement{Period: "2026-02", GrossSales: 100, Payout: 90, Commission: 10}, nil
+		},
+	}
+
+	NewStatementController(m).GetStatements(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	require.Equal(t, "text/csv; charset=utf-8", r.Header().Get("Content-Type"))
+	require.Contains(t, r.Header().Get("Content-Disposition"), "statement-2026-02.csv")
+	require.Contains(t, r.Body.String(), "Payout,90.00")
+}
+
+func TestStatementController_GetStatements_DownloadPDF(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/seller/statements?period=2026-02", nil)
+	c.Set("user_id", 8)
+
+	m := &mockStatementRepo{
+		getFn: func(ctx context.Context, userID int, period time.Time) (*models.SellerStatement, error) {
+			return &models.SellerStatement{Period: "2026-02"}, nil
+		},
+	}
+
+	NewStatementController(m).GetStatements(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	require.Equal(t, "application/pdf", r.Header().Get("Content-Type"))
+	require.True(t, bytes.HasPrefix(r.Body.Bytes(), []byte("%PDF-")))
+}
+
+func TestStatementController_GetStatements_Missing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/seller/statements?period=2026-02&format=csv", nil)
+	c.Set("user_id", 8)
+
+	m := &mockStatementRepo{
+		getFn: func(ctx context.Context, userID int, period time.Time) (*models.SellerStatement, error) {
+			return nil, apperrors.NotFound("no statement for 2026-02")
+		},
+	}
+
+	NewStatementController(m).GetStatements(c)
+
+	require.Equal(t, http.StatusNotFound, r.Code)
+}
+
+func TestStatementController_GetStatements_InvalidPeriod(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/seller/statements?period=february", nil)
+	c.Set("user_id", 8)
+
+	NewStatementController(&mockStatementRepo{}).GetStatements(c)
+
+	require.Equal(t, http.StatusBadRequest, r.Code)
+}
@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/Zifeldev/marketback/pkg/phone"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// tenantPattern matches host names and the default tenant.
+var tenantPattern = regexp.MustCompile(`^[a-z0-9.-]{1,100}$`)
+
+type StorefrontController struct {
+	storefrontRepo repository.StorefrontRepo
+}
+
+func NewStorefrontController(storefrontRepo repository.StorefrontRepo) *StorefrontController {
+	return &StorefrontController{storefrontRepo: storefrontRepo}
+}
+
+// storefrontTenant is the tenant query parameter or else the request host
+// without its port.
+func storefrontTenant(c *gin.Context) string {
+	tenant := c.Query("tenant")
+	if tenant == "" {
+		tenant = c.Request.Host
+		if host, _, err := net.SplitHostPort(tenant); err == nil {
+			tenant = host
+		}
+	}
+	return strings.ToLower(tenant)
+}
+
+// localeRegion is the region subtag of a BCP 47 locale ("DE" in de-DE),
+// which national contact phone numbers are read as numbers of.
+func localeRegion(locale string) string {
+	for _, subtag := range strings.Split(locale, "-")[1:] {
+		if len(subtag) == 2 {
+			return strings.ToUpper(subtag)
+		}
+	}
+	return ""
+}
+
+// GetStorefrontSettings godoc
+// @Summary Get storefront settings
+// @Description Get the branding of the storefront: logo, colors, contact info, footer links and currency/locale defaults. The tenant is the storefront host name, taken from the tenant parameter or the Host header; unknown tenants get the default settings.
+// @Tags storefront
+// @Produce json
+// @Param tenant query string false "Storefront host name"
+// @Success 200 {object} models.StorefrontSettings
+// @Failure 500 {object} map[string]string
+// @Router /api/storefront-settings [get]
+func (sc *StorefrontController) GetStorefrontSettings(c *gin.Context) {
+	settings, err := sc.storefrontRepo.Resolve(c.Request.Context(), storefrontTenant(c))
+	if handleError(c, err, apperrors.Internal("failed to get storefront settings")) {
+		return
+	}
+	if settings == nil {
+		settings = models.DefaultStorefrontSettings()
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, settings)
+}
+
+// GetAllStorefrontSettings godoc
+// @Summary List storefront settings
+// @Description List the settings of every configured storefront
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.StorefrontSettings
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/storefront-settings [get]
+func (sc *StorefrontController) GetAllStorefrontSettings(c *gin.Context) {
+	settings, err := sc.storefrontRepo.GetAll(c.Request.Context())
+	if handleError(c, err, apperrors.Internal("failed to get storefront settings")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateStorefrontSettings godoc
+// @Summary Update storefront settings
+// @Description Replace the settings of a storefront, creating them if needed. The tenant is the storefront host name or "default" for hosts without their own settings. The contact phone is stored in E.164 form; without a country code it is read as a national number of the region of default_locale.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tenant path string true "Storefront host name or default"
+// @Param request body models.UpdateStorefrontSettingsRequest true "Settings"
+// @Success 200 {object} models.StorefrontSettings
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/storefront-settings/{tenant} [put]
+func (sc *StorefrontController) UpdateStorefrontSettings(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	tenant := strings.ToLower(c.Param("tenant"))
+	if !tenantPattern.MatchString(tenant) {
+		respondError(c, apperrors.ValidationError("tenant", "must be a host name or default"))
+		return
+	}
+
+	var req models.UpdateStorefrontSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	if req.ContactPhone != "" {
+		number, err := phone.Normalize(req.ContactPhone, localeRegion(req.DefaultLocale))
+		if err != nil {
+			respondError(c, apperrors.ValidationError("contact_phone", err.Error()))
+			return
+		}
+		req.ContactPhone = number
+	}
+
+	settings, err := sc.storefrontRepo.Upsert(c.Request.Context(), tenant, userID.(int), &req)
+	if handleError(c, err, apperrors.Internal("failed to update storefront settings")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// DeleteStorefrontSettings godoc
+// @Summary Delete storefront settings
+// @Description Delete the settings of a storefront so it falls back to the default settings
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param tenant path string true "Storefront host name or default"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/storefront-settings/{tenant} [delete]
+func (sc *StorefrontController) DeleteStorefrontSettings(c *gin.Context) {
+	err := sc.storefrontRepo.Delete(c.Request.Context(), strings.ToLower(c.Param("tenant")))
+	if handleError(c, err, apperrors.Internal("failed to delete storefront settings")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "storefront settings deleted"})
+}
@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStorefrontRepo struct {
+	resolveFn func(ctx context.Context, tenant string) (*models.StorefrontSettings, error)
+	getAllFn  func(ctx context.Context) ([]*models.StorefrontSettings, error)
+	upsertFn  func(ctx context.Context, tenant string, adminID int, req *models.UpdateStorefrontSettingsRequest) (*models.StorefrontSettings, error)
+	deleteFn  func(ctx context.Context, tenant string) error
+}
+
+func (m *mockStorefrontRepo) Resolve(ctx context.Context, tenant string) (*models.StorefrontSettings, error) {
+	return m.resolveFn(ctx, tenant)
+}
+
+func (m *mockStorefrontRepo) GetAll(ctx context.Context) ([]*models.StorefrontSettings, error) {
+	return m.getAllFn(ctx)
+}
+
+func (m *mockStorefrontRepo) Upsert(ctx context.Context, tenant string, adminID int, req *models.UpdateStorefrontSettingsRequest) (*models.StorefrontSettings, error) {
+	return m.upsertFn(ctx, tenant, adminID, req)
+}
+
+func (m *mockStorefrontRepo) Delete(ctx context.Context, tenant string) error {
+	return m.deleteFn(ctx, tenant)
+}
+
+var _ repository.StorefrontRepo = (*mockStorefrontRepo)(nil)
+
+func TestStorefrontController_GetStorefrontSettings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		target string
+		host   string
+		tenant string
+	}{
+		{"host", "/api/storefront-settings", "Shop.Example.com:8080", "shop.example.com"},
+		{"query", "/api/storefront-settings?tenant=other.example.com", "shop.example.com", "other.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("GET", tt.target, nil)
+			c.Request.Host = tt.host
+
+			m := &mockStorefrontRepo{
+				resolveFn: func(ctx context.Context, tenant string) (*models.StorefrontSettings, error) {
+					require.Equal(t, tt.tenant, tenant)
+					return &models.StorefrontSettings{Tenant: tenant, Name: "Shop", DefaultCurrency: "EUR"}, nil
+				},
+			}
+
+			NewStorefrontController(m).GetStorefrontSettings(c)
+
+			require.Equal(t, http.StatusOK, r.Code)
+			require.Equal(t, "public, max-age=300", r.Header().Get("Cache-Control"))
+			var got models.StorefrontSettings
+			require.NoError(t, json.Unmarshal(r.Body.Bytes(), &got))
+			require.Equal(t, "EUR", got.DefaultCurrency)
+		})
+	}
+}
+
+func TestStorefrontController_GetStorefrontSettings_Defaults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/storefront-settings", nil)
+
+	m := &mockStorefrontRepo{
+		resolveFn: func(ctx context.Context, tenant string) (*models.StorefrontSettings, error) {
+			return nil, nil
+		},
+	}
+
+	NewStorefrontController(m).GetStorefrontSettings(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	var got models.StorefrontSettings
+	require.NoError(t, json.Unmarshal(r.Body.Bytes(), &got))
+	require.Equal(t, "USD", got.DefaultCurrency)
+	require.Equal(t, "en-US", got.DefaultLocale)
+}
+
+func TestStorefrontController_UpdateStorefrontSettings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		tenant string
+		body   string
+		code   int
+	}{
+		{"valid", "Shop.Example.com", `{"primary_color":"#1a73e8","footer_links":[{"label":"About","url":"https://example.com/about"}],"default_currency":"EUR","default_locale":"de-DE"}`, http.StatusOK},
+		{"bad color", "shop.example.com", `{"primary_color":"blue","default_currency":"EUR","default_locale":"de-DE"}`, http.StatusBadRequest},
+		{"bad currency", "shop.example.com", `{"default_currency":"XYZ","default_locale":"de-DE"}`, http.StatusBadRequest},
+		{"bad footer link", "shop.example.com", `{"footer_links":[{"label":"About","url":"not a url"}],"default_currency":"EUR","default_locale":"de-DE"}`, http.StatusBadRequest},
+		{"bad tenant", "shop_example", `{"default_currency":"EUR","default_locale":"de-DE"}`, http.StatusBadRequest},
+		{"bad phone", "shop.example.com", `{"contact_phone":"call us","default_currency":"EUR","default_locale":"de-DE"}`, http.StatusBadRequest},
+		{"national phone without region", "shop.example.com", `{"contact_phone":"030 123456","default_currency":"EUR","default_locale":"de"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(r)
+			c.Request = httptest.NewRequest("PUT", "/api/admin/storefront-settings/"+tt.tenant, bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "tenant", Value: tt.tenant}}
+			c.Set("user_id", 1)
+
+			m := &mockStorefrontRepo{
+				upsertFn: func(ctx context.Context, tenant string, adminID int, req *models.UpdateStorefrontSettingsRequest) (*models.StorefrontSettings, error) {
+					require.Equal(t, "shop.example.com", tenant)
+					require.Equal(t, 1, adminID)
+					require.Len(t, req.FooterLinks, 1)
+					return &models.StorefrontSettings{Tenant: tenant, PrimaryColor: req.PrimaryColor}, nil
+				},
+			}
+
+			NewStorefrontController(m).UpdateStorefrontSettings(c)
+
+			require.Equal(t, tt.code, r.Code)
+		})
+	}
+}
+
+func TestStorefrontController_UpdateStorefrontSettings_ContactPhone(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	body := `{"contact_phone":"030 123456","default_currency":"EUR","default_locale":"de-DE"}`
+	c.Request = httptest.NewRequest("PUT", "/api/admin/storefront-settings/default", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "tenant", Value: "default"}}
+	c.Set("user_id", 1)
+
+	m := &mockStorefrontRepo{
+		upsertFn: func(ctx context.Context, tenant string, adminID int, req *models.UpdateStorefrontSettingsRequest) (*models.StorefrontSettings, error) {
+			require.Equal(t, "+4930123456", req.ContactPhone)
+			return &models.StorefrontSettings{Tenant: tenant, ContactPhone: req.ContactPhone}, nil
+		},
+	}
+
+	NewStorefrontController(m).UpdateStorefrontSettings(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	require.Contains(t, r.Body.String(), `"contact_phone":"+4930123456"`)
+}
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/workers"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// systemStatusTimeout bounds the queries behind one status report.
+const systemStatusTimeout = 5 * time.Second
+
+type SystemController struct {
+	pool       *pgxpool.Pool
+	systemRepo repository.SystemRepo
+	redis      *redis.Client
+	workers    *workers.Registry
+	now        func() time.Time
+}
+
+func NewSystemController(pool *pgxpool.Pool, systemRepo repository.SystemRepo, redis *redis.Client, registry *workers.Registry) *SystemController {
+	return &SystemController{
+		pool:       pool,
+		systemRepo: systemRepo,
+		redis:      redis,
+		workers:    registry,
+		now:        time.Now,
+	}
+}
+
+// GetSystemStatus godoc
+// @Summary Get system status
+// @Description Database pool and replication, Redis memory, the event outbox and export job backlogs and the background workers of the instance answering. Parts that cannot be read are left out and explained in errors, and status is then degraded, as it is when a worker stalled or stopped.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.SystemStatus
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/admin/system/status [get]
+func (sc *SystemController) GetSystemStatus(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), systemStatusTimeout)
+	defer cancel()
+
+	status := models.SystemStatus{
+		Status:      "ok",
+		Timestamp:   sc.now().UTC(),
+		Replication: []models.ReplicaStatus{},
+		Workers:     sc.workers.Status(),
+		Errors:      map[string]string{},
+	}
+
+	if sc.pool != nil {
+		stat := sc.pool.Stat()
+		status.DBPool = &models.DBPoolStatus{
+			AcquiredConns:     stat.AcquiredConns(),
+			IdleConns:         stat.IdleConns(),
+			TotalConns:        stat.TotalConns(),
+			MaxConns:          stat.MaxConns(),
+			EmptyAcquireCount: stat.EmptyAcquireCount(),
+		}
+		if n := stat.AcquireCount(); n > 0 {
+			status.DBPool.AvgAcquireMs = float64(stat.AcquireDuration().Microseconds()) / float64(n) / 1000
+		}
+	}
+
+	var err error
+	if status.Replication, status.InRecovery, err = sc.systemRepo.GetReplication(ctx); err != nil {
+		status.Replication = []models.ReplicaStatus{}
+		status.Errors["replication"] = err.Error()
+	}
+	if status.Outbox, err = sc.systemRepo.GetOutboxStatus(ctx); err != nil {
+		status.Errors["outbox"] = err.Error()
+	}
+	if status.Exports, err = sc.systemRepo.GetExportQueueStatus(ctx); err != nil {
+		status.Errors["exports"] = err.Error()
+	}
+
+	status.Redis = sc.redisStatus(ctx)
+	if status.Redis.Status == "error" {
+		status.Errors["redis"] = "failed to read redis memory"
+	}
+
+	for _, w := range status.Workers {
+		if w.State != models.WorkerRunning {
+			status.Status = "degraded"
+		}
+	}
+	if len(status.Errors) > 0 {
+		status.Status = "degraded"
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func (sc *SystemController) redisStatus(ctx context.Context) models.RedisStatus {
+	if sc.redis == nil {
+		return models.RedisStatus{Status: "disabled"}
+	}
+	info, err := sc.redis.Info(ctx, "memory").Result()
+	if err != nil {
+		return models.RedisStatus{Status: "error"}
+	}
+	fields := parseRedisInfo(info)
+	return models.RedisStatus{
+		Status:          "ok",
+		UsedMemoryBytes: fields["used_memory"],
+		PeakMemoryBytes: fields["used_memory_peak"],
+		MaxMemoryBytes:  fields["maxmemory"],
+	}
+}
+
+// parseRedisInfo reads the numeric "name:value" lines of an INFO reply.
+func parseRedisInfo(info string) map[string]int64 {
+	fields := map[string]int64{}
+	scanner := bufio.NewScanner(strings.NewReader(info))
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok || strings.HasPrefix(name, "#") {
+			continue
+		}
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			fields[name] = n
+		}
+	}
+	return fields
+}
@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/workers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSystemRepo struct {
+	getOutboxStatusFn      func(ctx context.Context) (*models.OutboxStatus, error)
+	getExportQueueStatusFn func(ctx context.Context) (*models.ExportQueueStatus, error)
+	getReplicationFn       func(ctx context.Context) ([]models.ReplicaStatus, bool, error)
+}
+
+func (m *mockSystemRepo) GetOutboxStatus(ctx context.Context) (*models.OutboxStatus, error) {
+	return m.getOutboxStatusFn(ctx)
+}
+
+func (m *mockSystemRepo) GetExportQueueStatus(ctx context.Context) (*models.ExportQueueStatus, error) {
+	return m.getExportQueueStatusFn(ctx)
+}
+
+func (m *mockSystemRepo) GetReplication(ctx context.Context) ([]models.ReplicaStatus, bool, error) {
+	return m.getReplicationFn(ctx)
+}
+
+var _ repository.SystemRepo = (*mockSystemRepo)(nil)
+
+func newMockSystemRepo() *mockSystemRepo {
+	lag := 1.5
+	return &mockSystemRepo{
+		getOutboxStatusFn: func(ctx context.Context) (*models.OutboxStatus, error) {
+			return &models.OutboxStatus{Pending: 12, Failing: 2}, nil
+		},
+		getExportQueueStatusFn: func(ctx context.Context) (*models.ExportQueueStatus, error) {
+			return &models.ExportQueueStatus{Queued: 3, Running: 1}, nil
+		},
+		getReplicationFn: func(ctx context.Context) ([]models.ReplicaStatus, bool, error) {
+			return []models.ReplicaStatus{{Name: "replica-1", State: "streaming", ReplayLagSeconds: &lag}}, false, nil
+		},
+	}
+}
+
+func getSystemStatus(t *testing.T, sc *SystemController) models.SystemStatus {
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/admin/system/status", nil)
+	sc.GetSystemStatus(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	var status models.SystemStatus
+	require.NoError(t, json.Unmarshal(r.Body.Bytes(), &status))
+	return status
+}
+
+func TestSystemController_GetSystemStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	registry := workers.NewRegistry()
+	ran := make(chan struct{})
+	registry.Go(ctx, "listing", time.Minute, func(ctx context.Context, interval time.Duration) {
+		workers.Beat(ctx)
+		close(ran)
+		<-ctx.Done()
+	})
+	<-ran
+
+	status := getSystemStatus(t, NewSystemController(nil, newMockSystemRepo(), nil, registry))
+
+	require.Equal(t, "ok", status.Status)
+	require.Empty(t, status.Errors)
+	require.Nil(t, status.DBPool)
+	require.Equal(t, "disabled", status.Redis.Status)
+	require.Equal(t, int64(12), status.Outbox.Pending)
+	require.Equal(t, int64(3), status.Exports.Queued)
+	require.Len(t, status.Replication, 1)
+	require.Equal(t, 1.5, *status.Replication[0].ReplayLagSeconds)
+	require.Len(t, status.Workers, 1)
+	require.Equal(t, models.WorkerRunning, status.Workers[0].State)
+}
+
+func TestSystemController_GetSystemStatus_Degraded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := newMockSystemRepo()
+	repo.getReplicationFn = func(ctx context.Context) ([]models.ReplicaStatus, bool, error) {
+		return nil, false, errors.New("permission denied for pg_stat_replication")
+	}
+
+	status := getSystemStatus(t, NewSystemController(nil, repo, nil, nil))
+
+	require.Equal(t, "degraded", status.Status)
+	require.Contains(t, status.Errors, "replication")
+	require.Empty(t, status.Replication)
+	require.Equal(t, int64(12), status.Outbox.Pending)
+}
+
+func TestSystemController_GetSystemStatus_StoppedWorker(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registry := workers.NewRegistry()
+	done := make(chan struct{})
+	registry.Go(context.Background(), "feed", time.Hour, func(ctx context.Context, interval time.Duration) {
+		close(done)
+	})
+	<-done
+	sc := NewSystemController(nil, newMockSystemRepo(), nil, registry)
+
+	require.Eventually(t, func() bool {
+		return getSystemStatus(t, sc).Status == "degraded"
+	}, time.Second, time.Millisecond)
+}
+
+func TestParseRedisInfo(t *testing.T) {
+	info := "# Memory\r\nused_memory:1048576\r\nused_memory_human:1.00M\r\nused_memory_peak:2097152\r\nmaxmemory:0\r\n"
+	fields := parseRedisInfo(info)
+	require.Equal(t, int64(1048576), fields["used_memory"])
+	require.Equal(t, int64(2097152), fields["used_memory_peak"])
+	require.Equal(t, int64(0), fields["maxmemory"])
+	require.NotContains(t, fields, "used_memory_human")
+}
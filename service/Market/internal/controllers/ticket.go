@@ -0,0 +1,377 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/mailer"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/settings"
+	"github.com/Zifeldev/marketback/service/Market/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+const emailTimeout = 30 * time.Second
+
+type TicketController struct {
+	ticketRepo repository.TicketRepo
+	store      storage.Storage
+	mailer     mailer.Mailer
+	settings   *settings.Store
+}
+
+// NewTicketController wires the support desk. mail may be nil, in which case
+// only in-app notifications are sent; the support_email setting receives new
+// tickets and requester replies when set.
+func NewTicketController(ticketRepo repository.TicketRepo, store storage.Storage, mail mailer.Mailer, siteSettings *settings.Store) *TicketController {
+	return &TicketController{
+		ticketRepo: ticketRepo,
+		store:      store,
+		mailer:     mail,
+		settings:   siteSettings,
+	}
+}
+
+// CreateTicket godoc
+// @Summary Open support ticket
+// @Description Open a support ticket with a category and optional image attachments
+// @Tags tickets
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param category formData string true "order, payment, delivery, account, product or other"
+// @Param subject formData string true "Subject"
+// @Param message formData string true "Description of the problem"
+// @Param order_id formData int false "Related order"
+// @Param attachments formData file false "Up to 5 images"
+// @Success 201 {object} models.Ticket
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/tickets [post]
+func (tc *TicketController) CreateTicket(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	role, _ := c.Get("role")
+	email, _ := c.Get("email")
+	requesterEmail, _ := email.(string)
+
+	var req models.CreateTicketRequest
+	if err := c.ShouldBind(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	keys, urls, ok := tc.storeAttachments(c, userID.(int))
+	if !ok {
+		return
+	}
+
+	ticket, err := tc.ticketRepo.Create(c.Request.Context(), &models.Ticket{
+		UserID:    userID.(int),
+		UserRole:  fmt.Sprintf("%v", role),
+		UserEmail: requesterEmail,
+		Category:  req.Category,
+		Subject:   req.Subject,
+		OrderID:   req.OrderID,
+	}, &models.TicketMessage{
+		AuthorID:    userID.(int),
+		AuthorRole:  fmt.Sprintf("%v", role),
+		Body:        req.Message,
+		Attachments: urls,
+	})
+	if err != nil {
+		tc.deleteAttachments(c, keys)
+		handleError(c, err, apperrors.Internal("failed to create ticket"))
+		return
+	}
+
+	tc.sendEmail(tc.settings.SupportEmail(c.Request.Context()), fmt.Sprintf("[Ticket #%d] %s", ticket.ID, ticket.Subject),
+		fmt.Sprintf("New %s ticket from %s #%d:\n\n%s", ticket.Category, ticket.UserRole, ticket.UserID, req.Message))
+
+	c.JSON(http.StatusCreated, ticket)
+}
+
+// GetTickets godoc
+// @Summary List own support tickets
+// @Description List the current user's support tickets, most recently updated first
+// @Tags tickets
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "open, pending, resolved, closed or all (default)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/tickets [get]
+func (tc *TicketController) GetTickets(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	uid := userID.(int)
+	tc.listTickets(c, &uid, c.DefaultQuery("status", "all"))
+}
+
+// GetAllTickets godoc
+// @Summary Support queue
+// @Description List support tickets of all users (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "open (default), pending, resolved, closed or all"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/tickets [get]
+func (tc *TicketController) GetAllTickets(c *gin.Context) {
+	tc.listTickets(c, nil, c.DefaultQuery("status", models.TicketStatusOpen))
+}
+
+func (tc *TicketController) listTickets(c *gin.Context, userID *int, status string) {
+	switch status {
+	case models.TicketStatusOpen, models.TicketStatusPending, models.TicketStatusResolved, models.TicketStatusClosed:
+	case "all":
+		status = ""
+	default:
+		respondError(c, apperrors.ValidationError("status", "must be open, pending, resolved, closed or all"))
+		return
+	}
+
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		pagination = models.PaginationParams{Page: 1, PageSize: models.DefaultPageSize}
+	}
+	if pagination.Page < 1 {
+		pagination.Page = 1
+	}
+
+	tickets, totalItems, err := tc.ticketRepo.GetAll(c.Request.Context(), userID, status, &pagination)
+	if handleError(c, err, apperrors.Internal("failed to get tickets")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Data:       tickets,
+		Pagination: models.NewPaginationMeta(pagination.Page, pagination.GetLimit(), totalItems),
+	})
+}
+
+// GetTicket godoc
+// @Summary Get support ticket
+// @Description Get a ticket with its conversation. Users see their own tickets, admins any ticket.
+// @Tags tickets
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Ticket ID"
+// @Success 200 {object} models.Ticket
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/tickets/{id} [get]
+// @Router /api/admin/tickets/{id} [get]
+func (tc *TicketController) GetTicket(c *gin.Context) {
+	ticket, ok := tc.accessibleTicket(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, ticket)
+}
+
+// ReplyTicket godoc
+// @Summary Reply to support ticket
+// @Description Add a message to a ticket. A requester reply reopens the ticket; an admin reply sets it to pending unless another status is given.
+// @Tags tickets
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Ticket ID"
+// @Param message formData string true "Message"
+// @Param status formData string false "New status (admin only)"
+// @Param attachments formData file false "Up to 5 images"
+// @Success 201 {object} models.Ticket
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/tickets/{id}/messages [post]
+// @Router /api/admin/tickets/{id}/messages [post]
+func (tc *TicketController) ReplyTicket(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	role, _ := c.Get("role")
+
+	ticket, ok := tc.accessibleTicket(c)
+	if !ok {
+		return
+	}
+
+	var req models.TicketReplyRequest
+	if err := c.ShouldBind(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+	staff := role == "admin"
+	if req.Status != "" && !staff {
+		respondError(c, apperrors.ValidationError("status", "only support can change the status"))
+		return
+	}
+
+	keys, urls, ok := tc.storeAttachments(c, userID.(int))
+	if !ok {
+		return
+	}
+
+	updated, err := tc.ticketRepo.AddMessage(c.Request.Context(), &models.TicketMessage{
+		TicketID:    ticket.ID,
+		AuthorID:    userID.(int),
+		AuthorRole:  fmt.Sprintf("%v", role),
+		Body:        req.Message,
+		Attachments: urls,
+	}, req.Status)
+	if err != nil {
+		tc.deleteAttachments(c, keys)
+		handleError(c, err, apperrors.Internal("failed to add ticket message"))
+		return
+	}
+
+	subject := fmt.Sprintf("Re: [Ticket #%d] %s", updated.ID, updated.Subject)
+	if staff {
+		tc.sendEmail(updated.UserEmail, subject,
+			fmt.Sprintf("%s\n\nTicket status: %s", req.Message, updated.Status))
+	} else {
+		tc.sendEmail(tc.settings.SupportEmail(c.Request.Context()), subject,
+			fmt.Sprintf("New reply from %s #%d:\n\n%s", updated.UserRole, updated.UserID, req.Message))
+	}
+
+	c.JSON(http.StatusCreated, updated)
+}
+
+// UpdateTicketStatus godoc
+// @Summary Update support ticket status
+// @Description Change a ticket's status and notify the requester (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Ticket ID"
+// @Param request body models.UpdateTicketStatusRequest true "New status"
+// @Success 200 {object} models.Ticket
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/tickets/{id}/status [put]
+func (tc *TicketController) UpdateTicketStatus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("ticket"))
+		return
+	}
+
+	var req models.UpdateTicketStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	ticket, err := tc.ticketRepo.UpdateStatus(c.Request.Context(), id, req.Status)
+	if handleError(c, err, apperrors.Internal("failed to update ticket status")) {
+		return
+	}
+
+	tc.sendEmail(ticket.UserEmail, fmt.Sprintf("[Ticket #%d] %s", ticket.ID, ticket.Subject),
+		fmt.Sprintf("Your support ticket is now %s.", ticket.Status))
+
+	c.JSON(http.StatusOK, ticket)
+}
+
+// accessibleTicket loads the ticket named by the id parameter. Non-admins
+// only see their own tickets; others' tickets are reported as not found.
+func (tc *TicketController) accessibleTicket(c *gin.Context) (*models.Ticket, bool) {
+	userID, _ := c.Get("user_id")
+	role, _ := c.Get("role")
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("ticket"))
+		return nil, false
+	}
+
+	ticket, err := tc.ticketRepo.GetByID(c.Request.Context(), id)
+	if handleError(c, err, apperrors.Internal("failed to get ticket")) {
+		return nil, false
+	}
+	if role != "admin" && ticket.UserID != userID.(int) {
+		respondError(c, apperrors.NotFound(fmt.Sprintf("ticket with id %d not found", id)))
+		return nil, false
+	}
+	return ticket, true
+}
+
+// storeAttachments saves the images sent in the "attachments" form field.
+// On failure it removes what was already stored and writes the error
+// response.
+func (tc *TicketController) storeAttachments(c *gin.Context, userID int) (keys, urls []string, ok bool) {
+	if !strings.HasPrefix(c.ContentType(), "multipart/") {
+		return nil, nil, true
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		respondError(c, apperrors.BadRequest("invalid multipart form"))
+		return nil, nil, false
+	}
+
+	files := form.File["attachments"]
+	if len(files) > models.MaxTicketAttachments {
+		respondError(c, apperrors.ValidationError("attachments", fmt.Sprintf("at most %d files are allowed", models.MaxTicketAttachments)))
+		return nil, nil, false
+	}
+
+	for _, file := range files {
+		key, url, stored := storeImage(c, tc.store, file, fmt.Sprintf("tickets/%d/", userID))
+		if !stored {
+			tc.deleteAttachments(c, keys)
+			return nil, nil, false
+		}
+		keys = append(keys, key)
+		urls = append(urls, url)
+	}
+	return keys, urls, true
+}
+
+func (tc *TicketController) deleteAttachments(c *gin.Context, keys []string) {
+	for _, key := range keys {
+		_ = tc.store.Delete(c.Request.Context(), key)
+	}
+}
+
+// sendEmail delivers in the background so a slow relay never holds up the
+// request. Missing recipients and a disabled mailer are silently skipped.
+func (tc *TicketController) sendEmail(to, subject, body string) {
+	if tc.mailer == nil || to == "" {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), emailTimeout)
+		defer cancel()
+		if err := tc.mailer.Send(ctx, to, subject, body); err != nil {
+			logger.GetLogger().WithFields(map[string]interface{}{
+				"err":     err,
+				"subject": subject,
+			}).Error("failed to send ticket email")
+		}
+	}()
+}
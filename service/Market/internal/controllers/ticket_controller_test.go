@@ -0,0 +1,278 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/settings"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTicketRepo struct {
+	createFn       func(ctx context.Context, ticket *models.Ticket, first *models.TicketMessage) (*models.Ticket, error)
+	getByIDFn      func(ctx context.Context, id int) (*models.Ticket, error)
+	getAllFn       func(ctx context.Context, userID *int, status string, pagination *models.PaginationParams) ([]*models.Ticket, int64, error)
+	addMessageFn   func(ctx context.Context, msg *models.TicketMessage, status string) (*models.Ticket, error)
+	updateStatusFn func(ctx context.Context, id int, status string) (*models.Ticket, error)
+}
+
+func supportSettings(email string) *settings.Store {
+	s := settings.New(nil, nil)
+	s.SetDefault(settings.SupportEmail, email)
+	return s
+}
+
+func (m *mockTicketRepo) Create(ctx context.Context, ticket *models.Ticket, first *models.TicketMessage) (*models.Ticket, error) {
+	return m.createFn(ctx, ticket, first)
+}
+
+func (m *mockTicketRepo) GetByID(ctx context.Context, id int) (*models.Ticket, error) {
+	return m.getByIDFn(ctx, id)
+}
+
+func (m *mockTicketRepo) GetAll(ctx context.Context, userID *int, status string, pagination *models.PaginationParams) ([]*models.Ticket, int64, error) {
+	return m.getAllFn(ctx, userID, status, pagination)
+}
+
+func (m *mockTicketRepo) AddMessage(ctx context.Context, msg *models.TicketMessage, status string) (*models.Ticket, error) {
+	return m.addMessageFn(ctx, msg, status)
+}
+
+func (m *mockTicketRepo) UpdateStatus(ctx context.Context, id int, status string) (*models.Ticket, error) {
+	return m.updateStatusFn(ctx, id, status)
+}
+
+var _ repository.TicketRepo = (*mockTicketRepo)(nil)
+
+type sentEmail struct {
+	to, subject, body string
+}
+
+type fakeMailer struct {
+	sent chan sentEmail
+}
+
+func newFakeMailer() *fakeMailer {
+	return &fakeMailer{sent: make(chan sentEmail, 4)}
+}
+
+func (f *fakeMailer) Send(ctx context.Context, to, subject, body string) error {
+	f.sent <- sentEmail{to: to, subject: subject, body: body}
+	return nil
+}
+
+func (f *fakeMailer) wait(t *testing.T) sentEmail {
+	t.Helper()
+	select {
+	case e := <-f.sent:
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("no email sent")
+		return sentEmail{}
+	}
+}
+
+func ticketForm(t *testing.T, fields map[string]string, files ...string) *http.Request {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for k, v := range fields {
+		require.NoError(t, w.WriteField(k, v))
+	}
+	for _, name := range files {
+		part, err := w.CreateFormFile("attachments", name)
+		require.NoError(t, err)
+		_, err = part.Write([]byte("fake image"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	req := httptest.NewRequest("POST", "/api/tickets", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestTicketController_CreateTicket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = ticketForm(t, map[string]string{
+		"category": "delivery",
+		"subject":  "Parcel never arrived",
+		"message":  "Tracking says delivered but nothing came.",
+	}, "doorstep.jpg")
+	c.Set("user_id", 8)
+	c.Set("role", "user")
+	c.Set("email", "buyer@example.com")
+
+	m := &mockTicketRepo{
+		createFn: func(ctx context.Context, ticket *models.Ticket, first *models.TicketMessage) (*models.Ticket, error) {
+			require.Equal(t, 8, ticket.UserID)
+			require.Equal(t, "buyer@example.com", ticket.UserEmail)
+			require.Equal(t, "delivery", ticket.Category)
+			require.Len(t, first.Attachments, 1)
+			require.Contains(t, first.Attachments[0], "http://localhost/uploads/tickets/8/")
+			ticket.ID = 3
+			ticket.Status = models.TicketStatusOpen
+			ticket.Messages = []*models.TicketMessage{first}
+			return ticket, nil
+		},
+	}
+
+	store, _ := newTestStorage(t)
+	mail := newFakeMailer()
+	NewTicketController(m, store, mail, supportSettings("support@example.com")).CreateTicket(c)
+
+	require.Equal(t, http.StatusCreated, r.Code)
+	e := mail.wait(t)
+	require.Equal(t, "support@example.com", e.to)
+	require.Equal(t, "[Ticket #3] Parcel never arrived", e.subject)
+}
+
+func TestTicketController_CreateTicket_RemovesAttachmentsOnError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = ticketForm(t, map[string]string{
+		"category": "order",
+		"subject":  "Wrong item",
+		"message":  "Received a different colour.",
+		"order_id": "99",
+	}, "item.png")
+	c.Set("user_id", 8)
+	c.Set("role", "user")
+
+	m := &mockTicketRepo{
+		createFn: func(ctx context.Context, ticket *models.Ticket, first *models.TicketMessage) (*models.Ticket, error) {
+			require.Equal(t, 99, *ticket.OrderID)
+			return nil, apperrors.OrderNotFound(99)
+		},
+	}
+
+	store, dir := newTestStorage(t)
+	NewTicketController(m, store, nil, supportSettings("")).CreateTicket(c)
+
+	require.Equal(t, http.StatusNotFound, r.Code)
+	files, err := os.ReadDir(filepath.Join(dir, "tickets", "8"))
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
+func TestTicketController_CreateTicket_InvalidCategory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = ticketForm(t, map[string]string{"category": "refund", "subject": "x", "message": "y"})
+	c.Set("user_id", 8)
+	c.Set("role", "user")
+
+	store, _ := newTestStorage(t)
+	NewTicketController(&mockTicketRepo{}, store, nil, supportSettings("")).CreateTicket(c)
+
+	require.Equal(t, http.StatusBadRequest, r.Code)
+}
+
+func TestTicketController_GetTicket_OtherUsersTicket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/tickets/3", nil)
+	c.Params = gin.Params{{Key: "id", Value: "3"}}
+	c.Set("user_id", 8)
+	c.Set("role", "seller")
+
+	m := &mockTicketRepo{
+		getByIDFn: func(ctx context.Context, id int) (*models.Ticket, error) {
+			return &models.Ticket{ID: id, UserID: 9}, nil
+		},
+	}
+
+	store, _ := newTestStorage(t)
+	NewTicketController(m, store, nil, supportSettings("")).GetTicket(c)
+
+	require.Equal(t, http.StatusNotFound, r.Code)
+}
+
+func TestTicketController_ReplyTicket_AdminEmailsRequester(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = ticketForm(t, map[string]string{"message": "A replacement is on its way.", "status": "resolved"})
+	c.Params = gin.Params{{Key: "id", Value: "3"}}
+	c.Set("user_id", 1)
+	c.Set("role", "admin")
+
+	m := &mockTicketRepo{
+		getByIDFn: func(ctx context.Context, id int) (*models.Ticket, error) {
+			return &models.Ticket{ID: id, UserID: 8, Status: models.TicketStatusOpen}, nil
+		},
+		addMessageFn: func(ctx context.Context, msg *models.TicketMessage, status string) (*models.Ticket, error) {
+			require.Equal(t, 3, msg.TicketID)
+			require.Equal(t, "admin", msg.AuthorRole)
+			require.Equal(t, models.TicketStatusResolved, status)
+			return &models.Ticket{ID: 3, UserID: 8, UserEmail: "buyer@example.com", Subject: "Wrong item", Status: status}, nil
+		},
+	}
+
+	store, _ := newTestStorage(t)
+	mail := newFakeMailer()
+	NewTicketController(m, store, mail, supportSettings("support@example.com")).ReplyTicket(c)
+
+	require.Equal(t, http.StatusCreated, r.Code)
+	e := mail.wait(t)
+	require.Equal(t, "buyer@example.com", e.to)
+	require.Equal(t, "Re: [Ticket #3] Wrong item", e.subject)
+	require.True(t, strings.HasSuffix(e.body, "Ticket status: resolved"))
+}
+
+func TestTicketController_ReplyTicket_RequesterCannotSetStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = ticketForm(t, map[string]string{"message": "Done, thanks", "status": "closed"})
+	c.Params = gin.Params{{Key: "id", Value: "3"}}
+	c.Set("user_id", 8)
+	c.Set("role", "user")
+
+	m := &mockTicketRepo{
+		getByIDFn: func(ctx context.Context, id int) (*models.Ticket, error) {
+			return &models.Ticket{ID: id, UserID: 8}, nil
+		},
+	}
+
+	store, _ := newTestStorage(t)
+	NewTicketController(m, store, nil, supportSettings("")).ReplyTicket(c)
+
+	require.Equal(t, http.StatusBadRequest, r.Code)
+}
+
+func TestTicketController_GetAllTickets_DefaultsToOpen(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/admin/tickets", nil)
+
+	m := &mockTicketRepo{
+		getAllFn: func(ctx context.Context, userID *int, status string, pagination *models.PaginationParams) ([]*models.Ticket, int64, error) {
+			require.Nil(t, userID)
+			require.Equal(t, models.TicketStatusOpen, status)
+			return []*models.Ticket{}, 0, nil
+		},
+	}
+
+	store, _ := newTestStorage(t)
+	NewTicketController(m, store, nil, supportSettings("")).GetAllTickets(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+}
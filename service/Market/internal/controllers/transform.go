@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/transform"
+	"github.com/gin-gonic/gin"
+)
+
+type TransformController struct {
+	transformRepo repository.TransformRuleRepo
+	rules         *transform.Rules
+}
+
+// NewTransformController manages the rules that rules applies, reloading
+// them after each change.
+func NewTransformController(transformRepo repository.TransformRuleRepo, rules *transform.Rules) *TransformController {
+	return &TransformController{transformRepo: transformRepo, rules: rules}
+}
+
+// refresh makes a change take effect now rather than at the next periodic
+// reload; a failure is logged and left to that reload.
+func (tc *TransformController) refresh(c *gin.Context) {
+	if tc.rules != nil {
+		_ = tc.rules.Refresh(c.Request.Context())
+	}
+}
+
+func validPatch(c *gin.Context, raw []byte) bool {
+	if _, err := transform.Parse(raw); err != nil {
+		respondError(c, apperrors.ValidationError("patch", err.Error()))
+		return false
+	}
+	return true
+}
+
+// GetTransformRules godoc
+// @Summary Get transform rules
+// @Description Get all transform rules in the order they apply
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.TransformRule
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/transform-rules [get]
+func (tc *TransformController) GetTransformRules(c *gin.Context) {
+	rules, err := tc.transformRepo.GetAll(c.Request.Context())
+	if handleError(c, err, apperrors.Internal("failed to get transform rules")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// CreateTransformRule godoc
+// @Summary Create transform rule
+// @Description Add a JSON Patch (RFC 6902) applied to outbound payloads: domain events sent to the broker whose type is match (target event), or in-app notifications whose kind is match (target notification, patching {"kind","message"}); "*" matches all. With seller_id it only applies to events with that top-level seller_id and to notifications of the seller's owner. A patch whose test operation fails leaves the payload unchanged.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateTransformRuleRequest true "Rule"
+// @Success 201 {object} models.TransformRule
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/transform-rules [post]
+func (tc *TransformController) CreateTransformRule(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.CreateTransformRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+	if !validPatch(c, req.Patch) {
+		return
+	}
+
+	rule, err := tc.transformRepo.Create(c.Request.Context(), userID.(int), &req)
+	if handleError(c, err, apperrors.Internal("failed to create transform rule")) {
+		return
+	}
+	tc.refresh(c)
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateTransformRule godoc
+// @Summary Update transform rule
+// @Description Change the match, description, patch or enabled flag of a transform rule
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Transform rule ID"
+// @Param request body models.UpdateTransformRuleRequest true "Changes"
+// @Success 200 {object} models.TransformRule
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/transform-rules/{id} [put]
+func (tc *TransformController) UpdateTransformRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("transform rule"))
+		return
+	}
+
+	var req models.UpdateTransformRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+	if req.Patch != nil && !validPatch(c, req.Patch) {
+		return
+	}
+
+	rule, err := tc.transformRepo.Update(c.Request.Context(), id, &req)
+	if handleError(c, err, apperrors.Internal("failed to update transform rule")) {
+		return
+	}
+	tc.refresh(c)
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteTransformRule godoc
+// @Summary Delete transform rule
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Transform rule ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/transform-rules/{id} [delete]
+func (tc *TransformController) DeleteTransformRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("transform rule"))
+		return
+	}
+
+	err = tc.transformRepo.Delete(c.Request.Context(), id)
+	if handleError(c, err, apperrors.Internal("failed to delete transform rule")) {
+		return
+	}
+	tc.refresh(c)
+
+	c.JSON(http.StatusOK, gin.H{"message": "transform rule deleted"})
+}
+
+// PreviewTransform godoc
+// @Summary Preview transform
+// @Description Apply a patch to a sample event payload or notification document without saving anything. applied is false, with the reason in error, when the patch would leave the payload unchanged.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.PreviewTransformRequest true "Patch and document"
+// @Success 200 {object} models.PreviewTransformResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/admin/transform-rules/preview [post]
+func (tc *TransformController) PreviewTransform(c *gin.Context) {
+	var req models.PreviewTransformRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+	patch, err := transform.Parse(req.Patch)
+	if err != nil {
+		respondError(c, apperrors.ValidationError("patch", err.Error()))
+		return
+	}
+
+	out, err := patch.Apply(req.Document)
+	if err != nil {
+		c.JSON(http.StatusOK, models.PreviewTransformResponse{Document: req.Document, Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PreviewTransformResponse{Applied: true, Document: out})
+}
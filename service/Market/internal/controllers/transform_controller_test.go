@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/transform"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTransformRuleRepo struct {
+	createFn      func(ctx context.Context, adminID int, req *models.CreateTransformRuleRequest) (*models.TransformRule, error)
+	getAllFn      func(ctx context.Context) ([]*models.TransformRule, error)
+	updateFn      func(ctx context.Context, id int, req *models.UpdateTransformRuleRequest) (*models.TransformRule, error)
+	deleteFn      func(ctx context.Context, id int) error
+	listEnabledFn func(ctx context.Context) ([]*models.TransformRule, error)
+}
+
+func (m *mockTransformRuleRepo) Create(ctx context.Context, adminID int, req *models.CreateTransformRuleRequest) (*models.TransformRule, error) {
+	return m.createFn(ctx, adminID, req)
+}
+
+func (m *mockTransformRuleRepo) GetAll(ctx context.Context) ([]*models.TransformRule, error) {
+	return m.getAllFn(ctx)
+}
+
+func (m *mockTransformRuleRepo) Update(ctx context.Context, id int, req *models.UpdateTransformRuleRequest) (*models.TransformRule, error) {
+	return m.updateFn(ctx, id, req)
+}
+
+func (m *mockTransformRuleRepo) Delete(ctx context.Context, id int) error {
+	return m.deleteFn(ctx, id)
+}
+
+func (m *mockTransformRuleRepo) ListEnabled(ctx context.Context) ([]*models.TransformRule, error) {
+	return m.listEnabledFn(ctx)
+}
+
+var _ repository.TransformRuleRepo = (*mockTransformRuleRepo)(nil)
+
+func TestTransformController_CreateTransformRule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("POST", "/api/admin/transform-rules", bytes.NewBufferString(
+		`{"target":"event","match":"OrderCreated","patch":[{"op":"add","path":"/source","value":"market"}]}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", 1)
+
+	var stored []*models.TransformRule
+	m := &mockTransformRuleRepo{
+		createFn: func(ctx context.Context, adminID int, req *models.CreateTransformRuleRequest) (*models.TransformRule, error) {
+			require.Equal(t, 1, adminID)
+			require.Nil(t, req.SellerID)
+			rule := &models.TransformRule{ID: 3, Target: req.Target, Match: req.Match, Patch: req.Patch, Enabled: true, CreatedBy: adminID}
+			stored = append(stored, rule)
+			return rule, nil
+		},
+		listEnabledFn: func(ctx context.Context) ([]*models.TransformRule, error) {
+			return stored, nil
+		},
+	}
+	rules := transform.NewRules(m)
+
+	NewTransformController(m, rules).CreateTransformRule(c)
+
+	require.Equal(t, http.StatusCreated, r.Code)
+	require.JSONEq(t, `{"order_id":1,"source":"market"}`, string(rules.Event("OrderCreated", json.RawMessage(`{"order_id":1}`))),
+		"the new rule applies without waiting for the next reload")
+}
+
+func TestTransformController_CreateTransformRule_InvalidPatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for _, body := range []string{
+		`{"target":"event","match":"OrderCreated","patch":[{"op":"shout","path":"/a"}]}`,
+		`{"target":"event","match":"OrderCreated","patch":[]}`,
+		`{"target":"event","match":"OrderCreated","patch":{"op":"add"}}`,
+		`{"target":"webhook","match":"OrderCreated","patch":[{"op":"remove","path":"/a"}]}`,
+	} {
+		r := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(r)
+		c.Request = httptest.NewRequest("POST", "/api/admin/transform-rules", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("user_id", 1)
+
+		NewTransformController(&mockTransformRuleRepo{}, nil).CreateTransformRule(c)
+
+		require.Equal(t, http.StatusBadRequest, r.Code, body)
+	}
+}
+
+func TestTransformController_UpdateTransformRule_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("PUT", "/api/admin/transform-rules/9", bytes.NewBufferString(`{"enabled":false}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "9"}}
+
+	m := &mockTransformRuleRepo{
+		updateFn: func(ctx context.Context, id int, req *models.UpdateTransformRuleRequest) (*models.TransformRule, error) {
+			require.Equal(t, 9, id)
+			require.False(t, *req.Enabled)
+			return nil, apperrors.NotFound("transform rule with id 9 not found")
+		},
+	}
+
+	NewTransformController(m, nil).UpdateTransformRule(c)
+
+	require.Equal(t, http.StatusNotFound, r.Code)
+}
+
+func TestTransformController_PreviewTransform(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		patch    string
+		applied  bool
+		document string
+	}{
+		{`[{"op":"replace","path":"/message","value":"Restock soon"}]`, true, `{"kind":"low_stock","message":"Restock soon"}`},
+		{`[{"op":"test","path":"/kind","value":"order_status"}]`, false, `{"kind":"low_stock","message":"Mug is low"}`},
+		{`[{"op":"remove","path":"/title"}]`, false, `{"kind":"low_stock","message":"Mug is low"}`},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(r)
+		c.Request = httptest.NewRequest("POST", "/api/admin/transform-rules/preview", bytes.NewBufferString(
+			`{"patch":`+tc.patch+`,"document":{"kind":"low_stock","message":"Mug is low"}}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		NewTransformController(&mockTransformRuleRepo{}, nil).PreviewTransform(c)
+
+		require.Equal(t, http.StatusOK, r.Code, tc.patch)
+		var resp models.PreviewTransformResponse
+		require.NoError(t, json.Unmarshal(r.Body.Bytes(), &resp))
+		require.Equal(t, tc.applied, resp.Applied, tc.patch)
+		require.Equal(t, tc.applied, resp.Error == "", tc.patch)
+		require.JSONEq(t, tc.document, string(resp.Document), tc.patch)
+	}
+}
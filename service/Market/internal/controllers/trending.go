@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/trending"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultTrendingLimit = 20
+	maxTrendingLimit     = 100
+)
+
+// trendingRanker is the read side of trending.Tracker.
+type trendingRanker interface {
+	Top(ctx context.Context, window string, limit int) ([]trending.Score, error)
+}
+
+type TrendingController struct {
+	productRepo repository.ProductRepo
+	ranker      trendingRanker
+}
+
+func NewTrendingController(productRepo repository.ProductRepo, ranker trendingRanker) *TrendingController {
+	return &TrendingController{
+		productRepo: productRepo,
+		ranker:      ranker,
+	}
+}
+
+// GetTrending godoc
+// @Summary Get trending products
+// @Description Get active products ranked by recent views and purchases, with older activity decayed hourly. Empty when Redis is disabled. On a seller's custom domain only that seller's products are ranked.
+// @Tags products
+// @Produce json
+// @Param window query string false "1h, 24h (default) or 7d"
+// @Param limit query int false "Number of products (max 100)" default(20)
+// @Success 200 {array} models.TrendingProduct
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/products/trending [get]
+func (tc *TrendingController) GetTrending(c *gin.Context) {
+	window := c.DefaultQuery("window", "24h")
+	if _, ok := trending.Windows[window]; !ok {
+		respondError(c, apperrors.ValidationError("window", "must be 1h, 24h or 7d"))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultTrendingLimit)))
+	if err != nil || limit < 1 || limit > maxTrendingLimit {
+		respondError(c, apperrors.ValidationError("limit", "must be between 1 and 100"))
+		return
+	}
+
+	scores, err := tc.ranker.Top(c.Request.Context(), window, limit)
+	if handleError(c, err, apperrors.Internal("failed to get trending products")) {
+		return
+	}
+
+	result := []models.TrendingProduct{}
+	if len(scores) == 0 {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
+	ids := make([]int, 0, len(scores))
+	for _, s := range scores {
+		ids = append(ids, s.ProductID)
+	}
+	products, err := tc.productRepo.GetByIDs(c.Request.Context(), ids)
+	if handleError(c, err, apperrors.Internal("failed to get trending products")) {
+		return
+	}
+
+	// Keep the ranking order; products that were deleted or hidden since
+	// their events were recorded are skipped, as are other sellers'
+	// products on a seller's custom domain.
+	storefrontSellerID := c.GetInt("storefront_seller_id")
+	for _, s := range scores {
+		if p, ok := products[s.ProductID]; ok && (storefrontSellerID == 0 || p.SellerID == storefrontSellerID) {
+			result = append(result, models.TrendingProduct{ProductWithDetails: *p, TrendingScore: s.Score})
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
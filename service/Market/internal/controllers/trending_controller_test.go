@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/trending"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRanker struct {
+	topFn func(ctx context.Context, window string, limit int) ([]trending.Score, error)
+}
+
+func (m *mockRanker) Top(ctx context.Context, window string, limit int) ([]trending.Score, error) {
+	return m.topFn(ctx, window, limit)
+}
+
+func TestTrendingController_GetTrending_KeepsRankOrder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/products/trending?window=7d&limit=3", nil)
+
+	ranker := &mockRanker{topFn: func(ctx context.Context, window string, limit int) ([]trending.Score, error) {
+		require.Equal(t, "7d", window)
+		require.Equal(t, 3, limit)
+		return []trending.Score{{ProductID: 2, Score: 30}, {ProductID: 9, Score: 20}, {ProductID: 1, Score: 10}}, nil
+	}}
+	products := &mockProductRepo{getByIDs: func(ctx context.Context, ids []int) (map[int]*models.ProductWithDetails, error) {
+		require.Equal(t, []int{2, 9, 1}, ids)
+		// product 9 is no longer active
+		return map[int]*models.ProductWithDetails{
+			1: {Product: models.Product{ID: 1, Title: "Socks"}},
+			2: {Product: models.Product{ID: 2, Title: "Jacket"}},
+		}, nil
+	}}
+
+	NewTrendingController(products, ranker).GetTrending(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	var resp []models.TrendingProduct
+	require.NoError(t, json.Unmarshal(r.Body.Bytes(), &resp))
+	require.Len(t, resp, 2)
+	require.Equal(t, 2, resp[0].ID)
+	require.Equal(t, 30.0, resp[0].TrendingScore)
+	require.Equal(t, 1, resp[1].ID)
+}
+
+func TestTrendingController_GetTrending_SellerStorefront(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/products/trending", nil)
+	c.Set("storefront_seller_id", 5)
+
+	ranker := &mockRanker{topFn: func(ctx context.Context, window string, limit int) ([]trending.Score, error) {
+		return []trending.Score{{ProductID: 2, Score: 30}, {ProductID: 1, Score: 10}}, nil
+	}}
+	products := &mockProductRepo{getByIDs: func(ctx context.Context, ids []int) (map[int]*models.ProductWithDetails, error) {
+		return map[int]*models.ProductWithDetails{
+			1: {Product: models.Product{ID: 1, SellerID: 5, Title: "Socks"}},
+			2: {Product: models.Product{ID: 2, SellerID: 8, Title: "Jacket"}},
+		}, nil
+	}}
+
+	NewTrendingController(products, ranker).GetTrending(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	var resp []models.TrendingProduct
+	require.NoError(t, json.Unmarshal(r.Body.Bytes(), &resp))
+	require.Len(t, resp, 1, "other sellers' products are left out")
+	require.Equal(t, 1, resp[0].ID)
+}
+
+func TestTrendingController_GetTrending_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/products/trending", nil)
+
+	var tracker *trending.Tracker
+	NewTrendingController(&mockProductRepo{}, tracker).GetTrending(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	require.JSONEq(t, `[]`, r.Body.String())
+}
+
+func TestTrendingController_GetTrending_InvalidParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for _, q := range []string{"window=30d", "limit=0", "limit=500", "limit=abc"} {
+		r := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(r)
+		c.Request = httptest.NewRequest("GET", "/api/products/trending?"+q, nil)
+
+		NewTrendingController(&mockProductRepo{}, &mockRanker{}).GetTrending(c)
+
+		require.Equal(t, http.StatusBadRequest, r.Code, q)
+	}
+}
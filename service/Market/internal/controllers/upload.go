@@ -1,14 +1,16 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
+	"mime/multipart"
 	"net/http"
-	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/storage"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -26,18 +28,11 @@ var allowedImageExtensions = map[string]bool{
 }
 
 type UploadController struct {
-	uploadDir string
-	baseURL   string
+	store storage.Storage
 }
 
-func NewUploadController(uploadDir, baseURL string) (*UploadController, error) {
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create upload directory: %w", err)
-	}
-	return &UploadController{
-		uploadDir: uploadDir,
-		baseURL:   baseURL,
-	}, nil
+func NewUploadController(store storage.Storage) *UploadController {
+	return &UploadController{store: store}
 }
 
 // UploadImage godoc
@@ -46,7 +41,7 @@ func NewUploadController(uploadDir, baseURL string) (*UploadController, error) {
 // @Tags upload
 // @Accept multipart/form-data
 // @Produce json
-// @Param file formance_data file true "Image file"
+// @Param file formData file true "Image file"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -54,40 +49,55 @@ func NewUploadController(uploadDir, baseURL string) (*UploadController, error) {
 func (uc *UploadController) UploadImage(c *gin.Context) {
 	file, err := c.FormFile("file")
 	if err != nil {
-		logger.GetLogger().WithField("err", err).Warn("no file provided")
+		logger.FromContext(c.Request.Context()).WithField("err", err).Warn("no file provided")
 		c.JSON(http.StatusBadRequest, gin.H{"error": "no file provided"})
 		return
 	}
 
+	filename, imageURL, ok := storeImage(c, uc.store, file, "")
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"url":      imageURL,
+		"filename": filename,
+	})
+}
+
+// storeImage validates an uploaded image and saves it under prefix with a
+// unique name. On failure it writes the error response and returns false.
+func storeImage(c *gin.Context, store storage.Storage, file *multipart.FileHeader, prefix string) (key, url string, ok bool) {
 	ext := strings.ToLower(filepath.Ext(file.Filename))
 	if !allowedImageExtensions[ext] {
-		logger.GetLogger().WithField("ext", ext).Warn("invalid file type")
+		logger.FromContext(c.Request.Context()).WithField("ext", ext).Warn("invalid file type")
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid file type. Allowed: jpg, jpeg, png, gif, webp"})
-		return
+		return "", "", false
 	}
 
 	if file.Size > MaxFileSize {
-		logger.GetLogger().WithField("size", file.Size).Warn("file too large")
+		logger.FromContext(c.Request.Context()).WithField("size", file.Size).Warn("file too large")
 		c.JSON(http.StatusBadRequest, gin.H{"error": "file too large. Max size: 5MB"})
-		return
+		return "", "", false
 	}
 
-	filename := fmt.Sprintf("%s_%d%s", uuid.New().String(), time.Now().Unix(), ext)
-
-	savePath := filepath.Join(uc.uploadDir, filename)
-
-	if err := c.SaveUploadedFile(file, savePath); err != nil {
-		logger.GetLogger().WithField("err", err).Error("failed to save file")
+	src, err := file.Open()
+	if err != nil {
+		logger.FromContext(c.Request.Context()).WithField("err", err).Error("failed to open uploaded file")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save file"})
-		return
+		return "", "", false
 	}
+	defer src.Close()
 
-	imageURL := fmt.Sprintf("%s/uploads/%s", uc.baseURL, filename)
+	key = prefix + fmt.Sprintf("%s_%d%s", uuid.New().String(), time.Now().Unix(), ext)
+	url, err = store.Put(c.Request.Context(), key, src)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).WithField("err", err).Error("failed to save file")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save file"})
+		return "", "", false
+	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"url":      imageURL,
-		"filename": filename,
-	})
+	return key, url, true
 }
 
 // DeleteImage godoc
@@ -103,27 +113,24 @@ func (uc *UploadController) UploadImage(c *gin.Context) {
 func (uc *UploadController) DeleteImage(c *gin.Context) {
 	filename := c.Param("filename")
 	if filename == "" {
-		logger.GetLogger().Warn("filename required")
+		logger.FromContext(c.Request.Context()).Warn("filename required")
 		c.JSON(http.StatusBadRequest, gin.H{"error": "filename required"})
 		return
 	}
 
 	if strings.Contains(filename, "..") || strings.Contains(filename, "/") {
-		logger.GetLogger().WithField("filename", filename).Warn("invalid filename")
+		logger.FromContext(c.Request.Context()).WithField("filename", filename).Warn("invalid filename")
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filename"})
 		return
 	}
 
-	filePath := filepath.Join(uc.uploadDir, filename)
-
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		logger.GetLogger().WithField("filename", filename).Warn("file not found")
-		c.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
-		return
-	}
-
-	if err := os.Remove(filePath); err != nil {
-		logger.GetLogger().WithField("err", err).Error("failed to delete file")
+	if err := uc.store.Delete(c.Request.Context(), filename); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			logger.FromContext(c.Request.Context()).WithField("filename", filename).Warn("file not found")
+			c.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+			return
+		}
+		logger.FromContext(c.Request.Context()).WithField("err", err).Error("failed to delete file")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete file"})
 		return
 	}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type UserMergeController struct {
+	mergeRepo repository.UserMergeRepo
+}
+
+func NewUserMergeController(mergeRepo repository.UserMergeRepo) *UserMergeController {
+	return &UserMergeController{mergeRepo: mergeRepo}
+}
+
+// MergeUsers godoc
+// @Summary Merge duplicate user accounts
+// @Description Move the orders, cart, seller profile, support tickets, notifications, reports and share links of the source user to the target user in one transaction and record the merge (admin only). Users with a seller profile each cannot be merged. The source account itself lives in the Auth service and is not removed.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.MergeUsersRequest true "Users to merge"
+// @Success 201 {object} models.UserMerge
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/users/merge [post]
+func (mc *UserMergeController) MergeUsers(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req models.MergeUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	merge, err := mc.mergeRepo.Merge(c.Request.Context(), userID.(int), &req)
+	if handleError(c, err, apperrors.Internal("failed to merge users")) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, merge)
+}
+
+// GetUserMerges godoc
+// @Summary User merge audit log
+// @Description List merged user accounts, newest first (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/admin/users/merges [get]
+func (mc *UserMergeController) GetUserMerges(c *gin.Context) {
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		pagination = models.PaginationParams{Page: 1, PageSize: models.DefaultPageSize}
+	}
+	if pagination.Page < 1 {
+		pagination.Page = 1
+	}
+
+	merges, totalItems, err := mc.mergeRepo.GetAll(c.Request.Context(), &pagination)
+	if handleError(c, err, apperrors.Internal("failed to get user merges")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Data:       merges,
+		Pagination: models.NewPaginationMeta(pagination.Page, pagination.GetLimit(), totalItems),
+	})
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type mockUserMergeRepo struct {
+	mergeFn  func(ctx context.Context, adminID int, req *models.MergeUsersRequest) (*models.UserMerge, error)
+	getAllFn func(ctx context.Context, pagination *models.PaginationParams) ([]*models.UserMerge, int64, error)
+}
+
+func (m *mockUserMergeRepo) Merge(ctx context.Context, adminID int, req *models.MergeUsersRequest) (*models.UserMerge, error) {
+	return m.mergeFn(ctx, adminID, req)
+}
+
+func (m *mockUserMergeRepo) GetAll(ctx context.Context, pagination *models.PaginationParams) ([]*models.UserMerge, int64, error) {
+	return m.getAllFn(ctx, pagination)
+}
+
+var _ repository.UserMergeRepo = (*mockUserMergeRepo)(nil)
+
+func TestUserMergeController_MergeUsers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("POST", "/api/admin/users/merge", strings.NewReader(`{"source_user_id":12,"target_user_id":5}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", 1)
+
+	m := &mockUserMergeRepo{
+		mergeFn: func(ctx context.Context, adminID int, req *models.MergeUsersRequest) (*models.UserMerge, error) {
+			require.Equal(t, 1, adminID)
+			require.Equal(t, 12, req.SourceUserID)
+			require.Equal(t, 5, req.TargetUserID)
+			return &models.UserMerge{ID: 1, SourceUserID: 12, TargetUserID: 5, MergedBy: adminID, Orders: 3}, nil
+		},
+	}
+	NewUserMergeController(m).MergeUsers(c)
+
+	require.Equal(t, http.StatusCreated, r.Code)
+	require.Contains(t, r.Body.String(), `"orders":3`)
+}
+
+func TestUserMergeController_MergeUsers_MissingTarget(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("POST", "/api/admin/users/merge", strings.NewReader(`{"source_user_id":12}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", 1)
+
+	NewUserMergeController(&mockUserMergeRepo{}).MergeUsers(c)
+
+	require.Equal(t, http.StatusBadRequest, r.Code)
+}
+
+func TestUserMergeController_MergeUsers_BothSellers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("POST", "/api/admin/users/merge", strings.NewReader(`{"source_user_id":12,"target_user_id":5}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", 1)
+
+	m := &mockUserMergeRepo{
+		mergeFn: func(ctx context.Context, adminID int, req *models.MergeUsersRequest) (*models.UserMerge, error) {
+			return nil, apperrors.Conflict("both users have a seller profile")
+		},
+	}
+	NewUserMergeController(m).MergeUsers(c)
+
+	require.Equal(t, http.StatusConflict, r.Code)
+}
+
+func TestUserMergeController_GetUserMerges(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(r)
+	c.Request = httptest.NewRequest("GET", "/api/admin/users/merges?page=2&page_size=10", nil)
+
+	m := &mockUserMergeRepo{
+		getAllFn: func(ctx context.Context, pagination *models.PaginationParams) ([]*models.UserMerge, int64, error) {
+			require.Equal(t, 2, pagination.Page)
+			require.Equal(t, 10, pagination.GetLimit())
+			return []*models.UserMerge{{ID: 11}}, 11, nil
+		},
+	}
+	NewUserMergeController(m).GetUserMerges(c)
+
+	require.Equal(t, http.StatusOK, r.Code)
+	require.Contains(t, r.Body.String(), `"total_items":11`)
+}
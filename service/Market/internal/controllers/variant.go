@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type VariantController struct {
+	sellerRepo  *repository.SellerRepository
+	productRepo *repository.ProductRepository
+	variantRepo *repository.VariantRepository
+}
+
+func NewVariantController(
+	sellerRepo *repository.SellerRepository,
+	productRepo *repository.ProductRepository,
+	variantRepo *repository.VariantRepository,
+) *VariantController {
+	return &VariantController{
+		sellerRepo:  sellerRepo,
+		productRepo: productRepo,
+		variantRepo: variantRepo,
+	}
+}
+
+// GetProductVariants godoc
+// @Summary Get product variants
+// @Description Get the size/color variants of a product with their SKU, stock and price delta. Products sold as a whole have none; products with variants are added to the cart by variant_id.
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} models.ProductVariant
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/products/{id}/variants [get]
+func (vc *VariantController) GetProductVariants(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("product"))
+		return
+	}
+
+	if _, err := vc.productRepo.GetByID(c.Request.Context(), productID); err != nil {
+		respondError(c, apperrors.ProductNotFound(productID))
+		return
+	}
+
+	variants, err := vc.variantRepo.GetByProductID(c.Request.Context(), productID)
+	if handleError(c, err, apperrors.Internal("failed to get product variants")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, variants)
+}
+
+// CreateProductVariant godoc
+// @Summary Add product variant
+// @Description Add a size/color variant with its own SKU and stock; it sells at the product's price plus price_delta. Once a product has variants its stock and sizes are theirs, and cart items without a variant are dropped.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param request body models.CreateVariantRequest true "Variant"
+// @Success 201 {object} models.ProductVariant
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/products/{id}/variants [post]
+func (vc *VariantController) CreateProductVariant(c *gin.Context) {
+	productID, ok := vc.sellerProduct(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	variant, err := vc.variantRepo.Create(c.Request.Context(), productID, &req)
+	if handleError(c, err, apperrors.Internal("failed to create variant")) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, variant)
+}
+
+// UpdateProductVariant godoc
+// @Summary Update product variant
+// @Description Change a variant's SKU, size, color, price delta or stock
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param variant_id path int true "Variant ID"
+// @Param request body models.UpdateVariantRequest true "Update data"
+// @Success 200 {object} models.ProductVariant
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/products/{id}/variants/{variant_id} [put]
+func (vc *VariantController) UpdateProductVariant(c *gin.Context) {
+	productID, ok := vc.sellerProduct(c)
+	if !ok {
+		return
+	}
+	variantID, err := strconv.Atoi(c.Param("variant_id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("variant"))
+		return
+	}
+
+	var req models.UpdateVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.BadRequest(err.Error()))
+		return
+	}
+
+	variant, err := vc.variantRepo.Update(c.Request.Context(), productID, variantID, &req)
+	if handleError(c, err, apperrors.Internal("failed to update variant")) {
+		return
+	}
+
+	c.JSON(http.StatusOK, variant)
+}
+
+// DeleteProductVariant godoc
+// @Summary Delete product variant
+// @Description Remove a variant; cart items for it are removed and past orders keep its SKU. A product left without variants has no stock until it is set again.
+// @Tags seller
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param variant_id path int true "Variant ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seller/products/{id}/variants/{variant_id} [delete]
+func (vc *VariantController) DeleteProductVariant(c *gin.Context) {
+	productID, ok := vc.sellerProduct(c)
+	if !ok {
+		return
+	}
+	variantID, err := strconv.Atoi(c.Param("variant_id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("variant"))
+		return
+	}
+
+	if err := vc.variantRepo.Delete(c.Request.Context(), productID, variantID); err != nil {
+		handleError(c, err, apperrors.Internal("failed to delete variant"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "variant deleted"})
+}
+
+// sellerProduct returns the product in the path when the current seller
+// owns it, responding with an error otherwise.
+func (vc *VariantController) sellerProduct(c *gin.Context) (int, bool) {
+	userID, _ := c.Get("user_id")
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.InvalidID("product"))
+		return 0, false
+	}
+
+	seller, err := vc.sellerRepo.GetByUserID(c.Request.Context(), userID.(int))
+	if handleError(c, err, apperrors.Forbidden("seller profile not found")) {
+		return 0, false
+	}
+
+	product, err := vc.productRepo.GetByID(c.Request.Context(), productID)
+	if err != nil || product.SellerID != seller.ID {
+		respondError(c, apperrors.Forbidden("product not found or access denied"))
+		return 0, false
+	}
+	return productID, true
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/Zifeldev/marketback/service/Market/internal/config"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -31,6 +32,14 @@ func InitDB(cfg *config.DatabaseConfig) (*pgxpool.Pool, error) {
 	poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
 	poolConfig.HealthCheckPeriod = cfg.HealthCheckPeriod
 
+	execMode, err := parseQueryExecMode(cfg.QueryExecMode)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse database config: %w", err)
+	}
+	poolConfig.ConnConfig.DefaultQueryExecMode = execMode
+	poolConfig.ConnConfig.StatementCacheCapacity = cfg.StatementCacheCapacity
+	poolConfig.ConnConfig.DescriptionCacheCapacity = cfg.DescriptionCacheCapacity
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -46,3 +55,23 @@ func InitDB(cfg *config.DatabaseConfig) (*pgxpool.Pool, error) {
 
 	return pool, nil
 }
+
+// parseQueryExecMode maps the DB_QUERY_EXEC_MODE values onto pgx exec modes.
+// simple_protocol and exec avoid server-side prepared statements and are
+// the options to use behind PgBouncer in transaction pooling mode.
+func parseQueryExecMode(mode string) (pgx.QueryExecMode, error) {
+	switch mode {
+	case "", "cache_statement":
+		return pgx.QueryExecModeCacheStatement, nil
+	case "cache_describe":
+		return pgx.QueryExecModeCacheDescribe, nil
+	case "describe_exec":
+		return pgx.QueryExecModeDescribeExec, nil
+	case "exec":
+		return pgx.QueryExecModeExec, nil
+	case "simple_protocol":
+		return pgx.QueryExecModeSimpleProtocol, nil
+	default:
+		return 0, fmt.Errorf("unknown query exec mode %q", mode)
+	}
+}
@@ -0,0 +1,27 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQueryExecMode(t *testing.T) {
+	cases := map[string]pgx.QueryExecMode{
+		"":                pgx.QueryExecModeCacheStatement,
+		"cache_statement": pgx.QueryExecModeCacheStatement,
+		"cache_describe":  pgx.QueryExecModeCacheDescribe,
+		"describe_exec":   pgx.QueryExecModeDescribeExec,
+		"exec":            pgx.QueryExecModeExec,
+		"simple_protocol": pgx.QueryExecModeSimpleProtocol,
+	}
+	for in, want := range cases {
+		got, err := parseQueryExecMode(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, got, in)
+	}
+
+	_, err := parseQueryExecMode("prepared")
+	require.Error(t, err)
+}
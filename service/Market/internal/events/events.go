@@ -0,0 +1,135 @@
+// Package events publishes domain events, such as OrderCreated, to a
+// message broker for downstream services like email and analytics. Events
+// are recorded in the event_outbox table in the transaction of the change
+// they describe and relayed from there, so they are only published for
+// changes that committed, at least once and in order; consumers dedupe by
+// message ID.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/workers"
+)
+
+// Message is the envelope events are published in. Data is the event's
+// payload, e.g. a models.OrderCreatedEvent.
+type Message struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	Key        string          `json:"-"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// Publisher delivers messages to a broker, in order. Each event type goes
+// to its own topic (Kafka) or subject (NATS): the prefix, a dot and the
+// type, e.g. market.OrderCreated.
+type Publisher interface {
+	Name() string
+	Publish(ctx context.Context, msgs []Message) error
+	Close() error
+}
+
+// Topic is where events of eventType are published.
+func Topic(prefix, eventType string) string {
+	return prefix + "." + eventType
+}
+
+// maxBackoff caps how long the relay waits to retry after failed runs.
+const maxBackoff = time.Minute
+
+// Store hands out unpublished events; see repository.EventRepository. When
+// publish fails the events are kept for a retry and its error is returned.
+type Store interface {
+	Relay(ctx context.Context, limit int, publish func(ctx context.Context, events []*models.OutboxEvent) error) (int, error)
+}
+
+// Relay moves events from the outbox to the broker.
+type Relay struct {
+	store     Store
+	publisher Publisher
+	batchSize int
+}
+
+func NewRelay(store Store, publisher Publisher, batchSize int) *Relay {
+	return &Relay{store: store, publisher: publisher, batchSize: batchSize}
+}
+
+// RunOnce publishes events a batch at a time until the outbox is empty.
+// A batch that fails to publish stops the run; it is retried next time.
+func (r *Relay) RunOnce(ctx context.Context) error {
+	for ctx.Err() == nil {
+		n, err := r.store.Relay(ctx, r.batchSize, r.publish)
+		if err != nil {
+			return err
+		}
+		if n < r.batchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+func (r *Relay) publish(ctx context.Context, events []*models.OutboxEvent) error {
+	msgs := make([]Message, len(events))
+	for i, ev := range events {
+		msgs[i] = Message{ID: ev.ID, Type: ev.Type, Key: ev.Key, OccurredAt: ev.CreatedAt, Data: ev.Payload}
+	}
+	if err := r.publisher.Publish(ctx, msgs); err != nil {
+		metrics.EventPublishFailuresTotal.Inc()
+		logger.GetLogger().WithFields(map[string]interface{}{
+			"err":      err,
+			"broker":   r.publisher.Name(),
+			"first_id": events[0].ID,
+			"attempts": events[0].Attempts + 1,
+		}).Warn("failed to publish events")
+		return fmt.Errorf("%s: %w", r.publisher.Name(), err)
+	}
+	for _, msg := range msgs {
+		metrics.EventsPublishedTotal.WithLabelValues(msg.Type).Inc()
+	}
+	return nil
+}
+
+// Start relays events every interval until ctx is done. After failed runs
+// it backs off, doubling the wait up to maxBackoff, so an unavailable
+// broker is not hammered.
+func (r *Relay) Start(ctx context.Context, interval time.Duration) {
+	failures := 0
+	for {
+		workers.Beat(ctx)
+		if err := r.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			failures++
+			logger.GetLogger().WithFields(map[string]interface{}{
+				"err":      err,
+				"retry_in": backoff(interval, failures).String(),
+			}).Error("failed to relay events")
+		} else {
+			failures = 0
+		}
+
+		timer := time.NewTimer(backoff(interval, failures))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff is how long to wait after failures failed runs in a row.
+func backoff(interval time.Duration, failures int) time.Duration {
+	wait := interval
+	for i := 0; i < failures && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	return max(interval, min(wait, maxBackoff))
+}
@@ -0,0 +1,119 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+// memStore is an outbox in memory.
+type memStore struct {
+	events []*models.OutboxEvent
+}
+
+func (s *memStore) Relay(ctx context.Context, limit int, publish func(ctx context.Context, events []*models.OutboxEvent) error) (int, error) {
+	batch := s.events
+	if len(batch) > limit {
+		batch = batch[:limit]
+	}
+	if len(batch) == 0 {
+		return 0, nil
+	}
+	if err := publish(ctx, batch); err != nil {
+		for _, ev := range batch {
+			ev.Attempts++
+		}
+		return 0, err
+	}
+	s.events = s.events[len(batch):]
+	return len(batch), nil
+}
+
+type fakePublisher struct {
+	published []Message
+	err       error
+}
+
+func (p *fakePublisher) Name() string { return "fake" }
+
+func (p *fakePublisher) Publish(ctx context.Context, msgs []Message) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.published = append(p.published, msgs...)
+	return nil
+}
+
+func (p *fakePublisher) Close() error { return nil }
+
+func outboxEvents(n int) []*models.OutboxEvent {
+	events := make([]*models.OutboxEvent, n)
+	for i := range events {
+		events[i] = &models.OutboxEvent{
+			ID:        int64(i + 1),
+			Type:      models.EventOrderCreated,
+			Key:       "7",
+			Payload:   json.RawMessage(`{"order_id":7}`),
+			CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		}
+	}
+	return events
+}
+
+func TestRelay_RunOnce(t *testing.T) {
+	store := &memStore{events: outboxEvents(5)}
+	publisher := &fakePublisher{}
+
+	require.NoError(t, NewRelay(store, publisher, 2).RunOnce(context.Background()))
+
+	require.Empty(t, store.events)
+	require.Len(t, publisher.published, 5)
+	for i, msg := range publisher.published {
+		require.Equal(t, int64(i+1), msg.ID, "published in order")
+	}
+	msg := publisher.published[0]
+	require.Equal(t, models.EventOrderCreated, msg.Type)
+	require.Equal(t, "7", msg.Key)
+	require.JSONEq(t, `{"order_id":7}`, string(msg.Data))
+}
+
+func TestRelay_PublishFailure(t *testing.T) {
+	store := &memStore{events: outboxEvents(3)}
+	publisher := &fakePublisher{err: errors.New("broker down")}
+	relay := NewRelay(store, publisher, 2)
+
+	require.ErrorContains(t, relay.RunOnce(context.Background()), "broker down")
+	require.Len(t, store.events, 3, "failed events stay in the outbox")
+	require.Equal(t, 1, store.events[0].Attempts)
+	require.Equal(t, 0, store.events[2].Attempts, "the run stops at the failed batch")
+
+	publisher.err = nil
+	require.NoError(t, relay.RunOnce(context.Background()))
+	require.Empty(t, store.events)
+	require.Len(t, publisher.published, 3)
+}
+
+func TestBackoff(t *testing.T) {
+	require.Equal(t, time.Second, backoff(time.Second, 0))
+	require.Equal(t, 2*time.Second, backoff(time.Second, 1))
+	require.Equal(t, 8*time.Second, backoff(time.Second, 3))
+	require.Equal(t, maxBackoff, backoff(time.Second, 10))
+	require.Equal(t, 5*time.Minute, backoff(5*time.Minute, 2), "never shorter than the interval")
+}
+
+func TestMessage_JSON(t *testing.T) {
+	data, err := json.Marshal(Message{
+		ID:         42,
+		Type:       models.EventOrderPaid,
+		Key:        "7",
+		OccurredAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Data:       json.RawMessage(`{"order_id":7}`),
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":42,"type":"OrderPaid","occurred_at":"2026-03-01T12:00:00Z","data":{"order_id":7}}`, string(data))
+}
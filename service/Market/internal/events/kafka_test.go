@@ -0,0 +1,70 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKafka_Publish(t *testing.T) {
+	type request struct {
+		Topic   string
+		Records []struct {
+			Key   string          `json:"key"`
+			Value json.RawMessage `json:"value"`
+		} `json:"records"`
+	}
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/vnd.kafka.json.v2+json", r.Header.Get("Content-Type"))
+		var req request
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		req.Topic = r.URL.Path
+		requests = append(requests, req)
+		w.Write([]byte(`{"offsets":[{"partition":0,"offset":1,"error_code":null,"error":null}]}`))
+	}))
+	defer server.Close()
+
+	k := NewKafka(server.URL+"/", "market", time.Second)
+	err := k.Publish(context.Background(), []Message{
+		{ID: 1, Type: models.EventOrderCreated, Key: "7", Data: json.RawMessage(`{}`)},
+		{ID: 2, Type: models.EventOrderCreated, Key: "8", Data: json.RawMessage(`{}`)},
+		{ID: 3, Type: models.EventOrderPaid, Key: "7", Data: json.RawMessage(`{}`)},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2, "consecutive messages of a type are sent together")
+	require.Equal(t, "/topics/market.OrderCreated", requests[0].Topic)
+	require.Len(t, requests[0].Records, 2)
+	require.Equal(t, "8", requests[0].Records[1].Key)
+	var value Message
+	require.NoError(t, json.Unmarshal(requests[0].Records[1].Value, &value))
+	require.Equal(t, int64(2), value.ID)
+	require.Equal(t, "/topics/market.OrderPaid", requests[1].Topic)
+}
+
+func TestKafka_Errors(t *testing.T) {
+	status, body := http.StatusOK, `{"offsets":[{"error_code":40403,"error":"topic not authorized"}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	k := NewKafka(server.URL, "market", time.Second)
+	msgs := []Message{{ID: 1, Type: models.EventOrderPaid, Key: "7", Data: json.RawMessage(`{}`)}}
+
+	err := k.Publish(context.Background(), msgs)
+	require.ErrorContains(t, err, "topic not authorized")
+
+	status, body = http.StatusInternalServerError, `{"error_code":50002,"message":"kafka error"}`
+	err = k.Publish(context.Background(), msgs)
+	require.ErrorContains(t, err, "returned 500")
+}
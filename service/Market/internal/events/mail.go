@@ -0,0 +1,124 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/mailer"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+)
+
+// Directory resolves user IDs to emails; *users.Directory implements it.
+type Directory interface {
+	Emails(ctx context.Context, ids []int) map[int]string
+}
+
+// Mail emails buyers when their orders change status. It is relayed
+// events like a broker but never fails a batch: emails that cannot be sent
+// are logged and dropped, so a broker relayed alongside is not sent the
+// batch again.
+type Mail struct {
+	notifier  *mailer.Notifier
+	directory Directory
+}
+
+func NewMail(notifier *mailer.Notifier, directory Directory) *Mail {
+	return &Mail{notifier: notifier, directory: directory}
+}
+
+func (m *Mail) Name() string { return "email" }
+
+// orderStatusEmail is the data of the order_status email template.
+type orderStatusEmail struct {
+	OrderNumber string
+	FromStatus  string
+	Status      string
+}
+
+func (m *Mail) Publish(ctx context.Context, msgs []Message) error {
+	var changes []models.OrderStatusEvent
+	for _, msg := range msgs {
+		if msg.Type != models.EventOrderStatusChanged {
+			continue
+		}
+		var ev models.OrderStatusEvent
+		if err := json.Unmarshal(msg.Data, &ev); err != nil {
+			logger.GetLogger().WithFields(map[string]interface{}{
+				"err":      err,
+				"event_id": msg.ID,
+			}).Warn("failed to decode order status event")
+			continue
+		}
+		changes = append(changes, ev)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	buyers := make([]int, len(changes))
+	for i, ev := range changes {
+		buyers[i] = ev.UserID
+	}
+	emails := m.directory.Emails(ctx, buyers)
+	for _, ev := range changes {
+		to := emails[ev.UserID]
+		if to == "" {
+			continue
+		}
+		err := m.notifier.Notify(ctx, mailer.EventOrderStatus, to, orderStatusEmail{
+			OrderNumber: ev.OrderNumber,
+			FromStatus:  ev.FromStatus,
+			Status:      ev.Status,
+		})
+		if err != nil && !errors.Is(err, mailer.ErrDisabled) {
+			logger.GetLogger().WithFields(map[string]interface{}{
+				"err":          err,
+				"order_number": ev.OrderNumber,
+			}).Error("failed to send order status email")
+		}
+	}
+	return nil
+}
+
+func (m *Mail) Close() error { return nil }
+
+// fanout relays to several publishers in turn.
+type fanout []Publisher
+
+// Fanout publishes every batch to each publisher in turn, stopping at the
+// first that fails; the batch is then retried on all of them. Put
+// publishers that never fail, such as Mail, last.
+func Fanout(publishers ...Publisher) Publisher {
+	if len(publishers) == 1 {
+		return publishers[0]
+	}
+	return fanout(publishers)
+}
+
+func (f fanout) Name() string {
+	names := make([]string, len(f))
+	for i, p := range f {
+		names[i] = p.Name()
+	}
+	return strings.Join(names, "+")
+}
+
+func (f fanout) Publish(ctx context.Context, msgs []Message) error {
+	for _, p := range f {
+		if err := p.Publish(ctx, msgs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f fanout) Close() error {
+	var errs []error
+	for _, p := range f {
+		errs = append(errs, p.Close())
+	}
+	return errors.Join(errs...)
+}
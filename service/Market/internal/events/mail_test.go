@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/mailer"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDirectory map[int]string
+
+func (d fakeDirectory) Emails(ctx context.Context, ids []int) map[int]string {
+	return d
+}
+
+func statusMessage(t *testing.T, id int64, ev models.OrderStatusEvent) Message {
+	data, err := json.Marshal(ev)
+	require.NoError(t, err)
+	return Message{ID: id, Type: models.EventOrderStatusChanged, Data: data}
+}
+
+func TestMail_Publish(t *testing.T) {
+	outbox, err := mailer.NewOutbox("shop@example.com", "", 10)
+	require.NoError(t, err)
+	notifier, err := mailer.NewNotifier(outbox, "", nil)
+	require.NoError(t, err)
+	mail := NewMail(notifier, fakeDirectory{1: "buyer@example.com"})
+
+	require.NoError(t, mail.Publish(context.Background(), []Message{
+		{ID: 1, Type: models.EventOrderCreated, Data: json.RawMessage(`{"order_id":7}`)},
+		statusMessage(t, 2, models.OrderStatusEvent{OrderID: 7, OrderNumber: "MB-7", UserID: 1, FromStatus: "paid", Status: "shipped"}),
+		statusMessage(t, 3, models.OrderStatusEvent{OrderID: 8, OrderNumber: "MB-8", UserID: 2, FromStatus: "pending", Status: "cancelled"}),
+		{ID: 4, Type: models.EventOrderStatusChanged, Data: json.RawMessage(`not json`)},
+	}))
+
+	sent := outbox.Messages("")
+	require.Len(t, sent, 1, "only status changes of buyers with a known email are sent")
+	require.Equal(t, "buyer@example.com", sent[0].To)
+	require.Equal(t, "Order MB-7 is shipped", sent[0].Subject)
+}
+
+func TestFanout(t *testing.T) {
+	broker := &fakePublisher{}
+	second := &fakePublisher{}
+	publisher := Fanout(broker, second)
+	require.Equal(t, "fake+fake", publisher.Name())
+
+	msgs := []Message{{ID: 1, Type: models.EventOrderCreated}}
+	require.NoError(t, publisher.Publish(context.Background(), msgs))
+	require.Len(t, broker.published, 1)
+	require.Len(t, second.published, 1)
+
+	broker.err = errors.New("broker down")
+	require.ErrorContains(t, publisher.Publish(context.Background(), msgs), "broker down")
+	require.Len(t, second.published, 1, "publishers after a failed one are skipped")
+
+	require.Same(t, broker, Fanout(broker))
+}
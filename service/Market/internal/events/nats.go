@@ -0,0 +1,195 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NATS publishes to a NATS server over its client protocol. Messages carry
+// a Nats-Msg-Id header with the event ID, so JetStream streams on the
+// subjects drop redeliveries. A batch is confirmed with a PING once it has
+// been written; the connection is reopened after any error.
+type NATS struct {
+	addr    string
+	user    string
+	pass    string
+	token   string
+	prefix  string
+	timeout time.Duration
+
+	mu      sync.Mutex
+	conn    net.Conn
+	r       *bufio.Reader
+	headers bool
+}
+
+// NewNATS parses serverURL, nats://[user:pass@ or token@]host[:port].
+func NewNATS(serverURL, prefix string, timeout time.Duration) (*NATS, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil || u.Scheme != "nats" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid NATS URL: must be nats://host:port")
+	}
+	n := &NATS{addr: u.Host, prefix: prefix, timeout: timeout}
+	if u.Port() == "" {
+		n.addr = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			n.user, n.pass = u.User.Username(), pass
+		} else {
+			n.token = u.User.Username()
+		}
+	}
+	return n, nil
+}
+
+func (n *NATS) Name() string { return "nats" }
+
+type natsInfo struct {
+	Headers bool `json:"headers"`
+}
+
+type natsConnect struct {
+	Verbose  bool   `json:"verbose"`
+	Pedantic bool   `json:"pedantic"`
+	Headers  bool   `json:"headers"`
+	Name     string `json:"name"`
+	Lang     string `json:"lang"`
+	Version  string `json:"version"`
+	Protocol int    `json:"protocol"`
+	User     string `json:"user,omitempty"`
+	Pass     string `json:"pass,omitempty"`
+	Token    string `json:"auth_token,omitempty"`
+}
+
+func (n *NATS) connect(deadline time.Time) error {
+	conn, err := net.DialTimeout("tcp", n.addr, n.timeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to nats: %w", err)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+	r := bufio.NewReader(conn)
+
+	line, err := r.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to read nats info: %w", err)
+	}
+	var info natsInfo
+	if !strings.HasPrefix(line, "INFO ") || json.Unmarshal([]byte(strings.TrimSpace(line[5:])), &info) != nil {
+		conn.Close()
+		return fmt.Errorf("unexpected nats greeting: %q", strings.TrimSpace(line))
+	}
+
+	connect, _ := json.Marshal(natsConnect{
+		Headers:  info.Headers,
+		Name:     "market",
+		Lang:     "go",
+		Version:  "1",
+		Protocol: 1,
+		User:     n.user,
+		Pass:     n.pass,
+		Token:    n.token,
+	})
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\nPING\r\n", connect); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to send nats connect: %w", err)
+	}
+	n.conn, n.r, n.headers = conn, r, info.Headers
+	if err := n.awaitPong(); err != nil {
+		n.closeConn()
+		return err
+	}
+	return nil
+}
+
+// awaitPong reads until the server answers the last PING, answering its
+// own PINGs on the way.
+func (n *NATS) awaitPong() error {
+	for {
+		line, err := n.r.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read from nats: %w", err)
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := n.conn.Write([]byte("PONG\r\n")); err != nil {
+				return fmt.Errorf("failed to write to nats: %w", err)
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("nats error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+func (n *NATS) Publish(ctx context.Context, msgs []Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	deadline := time.Now().Add(n.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if n.conn == nil {
+		if err := n.connect(deadline); err != nil {
+			return err
+		}
+	} else if err := n.conn.SetDeadline(deadline); err != nil {
+		n.closeConn()
+		return err
+	}
+
+	var buf bytes.Buffer
+	for _, msg := range msgs {
+		payload, err := json.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("failed to encode event %d: %w", msg.ID, err)
+		}
+		subject := Topic(n.prefix, msg.Type)
+		if n.headers {
+			hdr := fmt.Sprintf("NATS/1.0\r\nNats-Msg-Id: %d\r\n\r\n", msg.ID)
+			fmt.Fprintf(&buf, "HPUB %s %d %d\r\n%s%s\r\n", subject, len(hdr), len(hdr)+len(payload), hdr, payload)
+		} else {
+			fmt.Fprintf(&buf, "PUB %s %d\r\n%s\r\n", subject, len(payload), payload)
+		}
+	}
+	buf.WriteString("PING\r\n")
+
+	if _, err := n.conn.Write(buf.Bytes()); err != nil {
+		n.closeConn()
+		return fmt.Errorf("failed to write to nats: %w", err)
+	}
+	if err := n.awaitPong(); err != nil {
+		n.closeConn()
+		return err
+	}
+	return nil
+}
+
+func (n *NATS) closeConn() {
+	if n.conn != nil {
+		n.conn.Close()
+		n.conn, n.r = nil, nil
+	}
+}
+
+func (n *NATS) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.closeConn()
+	return nil
+}
@@ -0,0 +1,137 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+// natsServer speaks enough of the NATS protocol to receive publishes.
+type natsServer struct {
+	ln       net.Listener
+	headers  bool
+	connects chan string
+	received chan string
+	reject   string
+}
+
+func startNATSServer(t *testing.T, headers bool) *natsServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &natsServer{ln: ln, headers: headers, connects: make(chan string, 10), received: make(chan string, 100)}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *natsServer) serve(conn net.Conn) {
+	defer conn.Close()
+	fmt.Fprintf(conn, "INFO {\"server_id\":\"test\",\"headers\":%t,\"max_payload\":1048576}\r\n", s.headers)
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "CONNECT":
+			s.connects <- strings.TrimSpace(strings.TrimPrefix(line, "CONNECT"))
+		case "PING":
+			if s.reject != "" {
+				fmt.Fprintf(conn, "-ERR '%s'\r\n", s.reject)
+				return
+			}
+			conn.Write([]byte("PING\r\nPONG\r\n"))
+		case "PONG":
+		case "PUB", "HPUB":
+			var size int
+			fmt.Sscan(fields[len(fields)-1], &size)
+			data := make([]byte, size+2)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return
+			}
+			s.received <- fields[0] + " " + fields[1] + "\n" + string(data[:size])
+		}
+	}
+}
+
+func TestNATS_Publish(t *testing.T) {
+	server := startNATSServer(t, true)
+	n, err := NewNATS("nats://svc:secret@"+server.ln.Addr().String(), "market", time.Second)
+	require.NoError(t, err)
+	defer n.Close()
+
+	msgs := []Message{
+		{ID: 41, Type: models.EventOrderCreated, Key: "7", Data: json.RawMessage(`{"order_id":7}`)},
+		{ID: 42, Type: models.EventProductStockLow, Key: "3", Data: json.RawMessage(`{"product_id":3}`)},
+	}
+	require.NoError(t, n.Publish(context.Background(), msgs))
+
+	var connect natsConnect
+	require.NoError(t, json.Unmarshal([]byte(<-server.connects), &connect))
+	require.Equal(t, "svc", connect.User)
+	require.Equal(t, "secret", connect.Pass)
+	require.True(t, connect.Headers)
+
+	first := <-server.received
+	require.True(t, strings.HasPrefix(first, "HPUB market.OrderCreated\nNATS/1.0\r\nNats-Msg-Id: 41\r\n\r\n"), first)
+	require.Contains(t, first, `"data":{"order_id":7}`)
+	second := <-server.received
+	require.True(t, strings.HasPrefix(second, "HPUB market.ProductStockLow\n"), second)
+
+	// The connection is reused.
+	require.NoError(t, n.Publish(context.Background(), msgs[:1]))
+	<-server.received
+	require.Empty(t, server.connects)
+}
+
+func TestNATS_WithoutHeaders(t *testing.T) {
+	server := startNATSServer(t, false)
+	n, err := NewNATS("nats://token@"+server.ln.Addr().String(), "market", time.Second)
+	require.NoError(t, err)
+	defer n.Close()
+
+	require.NoError(t, n.Publish(context.Background(), []Message{{ID: 1, Type: models.EventOrderPaid, Data: json.RawMessage(`{}`)}}))
+	var connect natsConnect
+	require.NoError(t, json.Unmarshal([]byte(<-server.connects), &connect))
+	require.Equal(t, "token", connect.Token)
+	require.True(t, strings.HasPrefix(<-server.received, "PUB market.OrderPaid\n{"))
+}
+
+func TestNATS_Errors(t *testing.T) {
+	server := startNATSServer(t, true)
+	server.reject = "Authorization Violation"
+	n, err := NewNATS("nats://"+server.ln.Addr().String(), "market", time.Second)
+	require.NoError(t, err)
+
+	err = n.Publish(context.Background(), []Message{{ID: 1, Type: models.EventOrderPaid, Data: json.RawMessage(`{}`)}})
+	require.ErrorContains(t, err, "Authorization Violation")
+
+	_, err = NewNATS("http://localhost:4222", "market", time.Second)
+	require.Error(t, err)
+	n, err = NewNATS("nats://localhost", "market", time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "localhost:4222", n.addr)
+}
@@ -0,0 +1,26 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Transformed returns a publisher that passes each message's data through
+// fn, e.g. transform.Rules.Event, before publishing it with p.
+func Transformed(p Publisher, fn func(eventType string, data json.RawMessage) json.RawMessage) Publisher {
+	return transformed{Publisher: p, fn: fn}
+}
+
+type transformed struct {
+	Publisher
+	fn func(eventType string, data json.RawMessage) json.RawMessage
+}
+
+func (t transformed) Publish(ctx context.Context, msgs []Message) error {
+	out := make([]Message, len(msgs))
+	for i, msg := range msgs {
+		msg.Data = t.fn(msg.Type, msg.Data)
+		out[i] = msg
+	}
+	return t.Publisher.Publish(ctx, out)
+}
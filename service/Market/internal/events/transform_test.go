@@ -0,0 +1,30 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformed(t *testing.T) {
+	broker := &fakePublisher{}
+	publisher := Transformed(broker, func(eventType string, data json.RawMessage) json.RawMessage {
+		if eventType == models.EventOrderCreated {
+			return json.RawMessage(`{"order_id":7,"source":"market"}`)
+		}
+		return data
+	})
+	require.Equal(t, "fake", publisher.Name())
+
+	msgs := []Message{
+		{ID: 1, Type: models.EventOrderCreated, Data: json.RawMessage(`{"order_id":7}`)},
+		{ID: 2, Type: models.EventOrderStatusChanged, Data: json.RawMessage(`{"order_id":7}`)},
+	}
+	require.NoError(t, publisher.Publish(context.Background(), msgs))
+	require.JSONEq(t, `{"order_id":7,"source":"market"}`, string(broker.published[0].Data))
+	require.JSONEq(t, `{"order_id":7}`, string(broker.published[1].Data))
+	require.JSONEq(t, `{"order_id":7}`, string(msgs[0].Data), "the relay's messages are left alone")
+}
@@ -0,0 +1,207 @@
+// Package exports runs product exports too large to stream as background
+// jobs. Each job is written page by page to a file in a directory, with its
+// progress recorded after every page, and the file is removed once the job
+// expires.
+package exports
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/catalog"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/workers"
+)
+
+// PageSize is how many products are read and written at a time.
+const PageSize = 500
+
+// staleAfter is how long a running job may go without progress before
+// another worker takes it over.
+const staleAfter = 5 * time.Minute
+
+// ErrNotFound is returned for export files that do not exist (any more).
+var ErrNotFound = errors.New("export file not found")
+
+// Jobs stores the export jobs.
+type Jobs interface {
+	Claim(ctx context.Context, stale time.Duration) (*models.ExportJob, error)
+	Progress(ctx context.Context, id, processed int) error
+	Finish(ctx context.Context, id, processed int, fileName string, expiresAt time.Time) error
+	Fail(ctx context.Context, id int, message string) error
+	Expire(ctx context.Context) ([]string, error)
+}
+
+// Products pages through a seller's products, newest first.
+type Products interface {
+	GetSellerPage(ctx context.Context, sellerID, beforeID, limit int) ([]*models.Product, error)
+}
+
+// Runner works through queued export jobs on every run.
+type Runner struct {
+	jobs     Jobs
+	products Products
+	dir      string
+	fileTTL  time.Duration
+	now      func() time.Time
+}
+
+// NewRunner creates dir if needed. Finished files are kept for fileTTL.
+func NewRunner(jobs Jobs, products Products, dir string, fileTTL time.Duration) (*Runner, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+	return &Runner{jobs: jobs, products: products, dir: dir, fileTTL: fileTTL, now: time.Now}, nil
+}
+
+// FileName is the name of a job's export file.
+func FileName(job *models.ExportJob) string {
+	return fmt.Sprintf("products-%d.%s", job.ID, job.Format)
+}
+
+// Path returns the path of a finished export file.
+func (r *Runner) Path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) {
+		return "", ErrNotFound
+	}
+	path := filepath.Join(r.dir, name)
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return path, nil
+}
+
+// RunOnce removes expired files, then runs queued jobs until none is left.
+func (r *Runner) RunOnce(ctx context.Context) error {
+	expired, err := r.jobs.Expire(ctx)
+	if err != nil {
+		return err
+	}
+	for _, name := range expired {
+		if err := os.Remove(filepath.Join(r.dir, name)); err != nil && !os.IsNotExist(err) {
+			logger.GetLogger().WithField("err", err).WithField("file", name).Warn("failed to remove expired export")
+		}
+	}
+
+	for ctx.Err() == nil {
+		job, err := r.jobs.Claim(ctx, staleAfter)
+		if err != nil {
+			return err
+		}
+		if job == nil {
+			return nil
+		}
+		r.run(ctx, job)
+	}
+	return ctx.Err()
+}
+
+func (r *Runner) run(ctx context.Context, job *models.ExportJob) {
+	log := logger.GetLogger().WithFields(map[string]interface{}{
+		"export_id": job.ID,
+		"seller_id": job.SellerID,
+	})
+
+	processed, err := r.write(ctx, job)
+	if err == nil {
+		err = r.jobs.Finish(ctx, job.ID, processed, FileName(job), r.now().Add(r.fileTTL))
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			// Shutting down; the job is picked up again once it goes stale.
+			return
+		}
+		log.WithField("err", err).Error("product export failed")
+		metrics.ExportJobsTotal.WithLabelValues(models.ExportFailed).Inc()
+		if err := r.jobs.Fail(ctx, job.ID, "export failed, please try again"); err != nil {
+			log.WithField("err", err).Error("failed to mark export failed")
+		}
+		return
+	}
+
+	metrics.ExportJobsTotal.WithLabelValues(models.ExportDone).Inc()
+	log.WithField("rows", processed).Info("product export finished")
+}
+
+// write writes the job's file through a temporary file, so downloads never
+// see a partial export, and returns the number of products written.
+func (r *Runner) write(ctx context.Context, job *models.ExportJob) (int, error) {
+	tmp, err := os.CreateTemp(r.dir, "."+FileName(job)+"-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	buf := bufio.NewWriter(tmp)
+	processed, err := r.writeProducts(ctx, job, buf)
+	if err == nil {
+		err = buf.Flush()
+	}
+	if err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+	return processed, os.Rename(tmp.Name(), filepath.Join(r.dir, FileName(job)))
+}
+
+func (r *Runner) writeProducts(ctx context.Context, job *models.ExportJob, buf *bufio.Writer) (int, error) {
+	w, err := catalog.NewWriter(buf, job.Format)
+	if err != nil {
+		return 0, err
+	}
+
+	processed, before := 0, 0
+	for {
+		page, err := r.products.GetSellerPage(ctx, job.SellerID, before, PageSize)
+		if err != nil {
+			return 0, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		if err := w.Write(page); err != nil {
+			return 0, err
+		}
+		processed += len(page)
+		before = page[len(page)-1].ID
+		if err := r.jobs.Progress(ctx, job.ID, processed); err != nil {
+			return 0, err
+		}
+		if len(page) < PageSize {
+			break
+		}
+	}
+	return processed, w.Close()
+}
+
+// Start runs queued jobs every interval until ctx is cancelled.
+func (r *Runner) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		workers.Beat(ctx)
+		if err := r.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			logger.GetLogger().WithField("err", err).Error("failed to run export jobs")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package exports
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/catalog"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeJobs struct {
+	queued   []*models.ExportJob
+	progress []int
+	finished map[int]string
+	failed   map[int]string
+	expired  []string
+}
+
+func (f *fakeJobs) Claim(ctx context.Context, stale time.Duration) (*models.ExportJob, error) {
+	if len(f.queued) == 0 {
+		return nil, nil
+	}
+	job := f.queued[0]
+	f.queued = f.queued[1:]
+	return job, nil
+}
+
+func (f *fakeJobs) Progress(ctx context.Context, id, processed int) error {
+	f.progress = append(f.progress, processed)
+	return nil
+}
+
+func (f *fakeJobs) Finish(ctx context.Context, id, processed int, fileName string, expiresAt time.Time) error {
+	f.finished[id] = fileName
+	return nil
+}
+
+func (f *fakeJobs) Fail(ctx context.Context, id int, message string) error {
+	f.failed[id] = message
+	return nil
+}
+
+func (f *fakeJobs) Expire(ctx context.Context) ([]string, error) {
+	expired := f.expired
+	f.expired = nil
+	return expired, nil
+}
+
+// fakeProducts holds products with ids 1..n of seller 1.
+type fakeProducts struct {
+	n   int
+	err error
+}
+
+func (f *fakeProducts) GetSellerPage(ctx context.Context, sellerID, beforeID, limit int) ([]*models.Product, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if sellerID != 1 {
+		return nil, nil
+	}
+	start := f.n
+	if beforeID > 0 {
+		start = beforeID - 1
+	}
+	page := []*models.Product{}
+	for id := start; id > 0 && len(page) < limit; id-- {
+		page = append(page, &models.Product{ID: id, SellerID: 1, CategoryID: 1, Title: "Tee", Price: 10})
+	}
+	return page, nil
+}
+
+func TestRunner_RunOnce(t *testing.T) {
+	dir := t.TempDir()
+	jobs := &fakeJobs{
+		queued:   []*models.ExportJob{{ID: 4, SellerID: 1, Format: catalog.FormatCSV}},
+		finished: map[int]string{},
+		failed:   map[int]string{},
+	}
+	r, err := NewRunner(jobs, &fakeProducts{n: PageSize + 20}, dir, time.Hour)
+	require.NoError(t, err)
+
+	require.NoError(t, r.RunOnce(context.Background()))
+	require.Equal(t, []int{PageSize, PageSize + 20}, jobs.progress)
+	require.Equal(t, "products-4.csv", jobs.finished[4])
+
+	path, err := r.Path("products-4.csv")
+	require.NoError(t, err)
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	info, err := f.Stat()
+	require.NoError(t, err)
+	reader, err := catalog.NewReader(f, info.Size(), catalog.FormatCSV)
+	require.NoError(t, err)
+	rows := 0
+	for {
+		row, problems, err := reader.Next()
+		if err != nil {
+			break
+		}
+		require.Empty(t, problems)
+		require.Equal(t, PageSize+20-rows, *row.ID, "newest first")
+		rows++
+	}
+	require.Equal(t, PageSize+20, rows)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "no temporary files are left")
+
+	jobs.expired = []string{"products-4.csv"}
+	require.NoError(t, r.RunOnce(context.Background()))
+	_, err = r.Path("products-4.csv")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestRunner_RunOnce_Fails(t *testing.T) {
+	dir := t.TempDir()
+	jobs := &fakeJobs{
+		queued:   []*models.ExportJob{{ID: 5, SellerID: 1, Format: catalog.FormatXLSX}},
+		finished: map[int]string{},
+		failed:   map[int]string{},
+	}
+	r, err := NewRunner(jobs, &fakeProducts{err: errors.New("connection reset")}, dir, time.Hour)
+	require.NoError(t, err)
+
+	require.NoError(t, r.RunOnce(context.Background()))
+	require.NotEmpty(t, jobs.failed[5])
+	require.Empty(t, jobs.finished)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestRunner_Path(t *testing.T) {
+	r, err := NewRunner(&fakeJobs{}, &fakeProducts{}, t.TempDir(), time.Hour)
+	require.NoError(t, err)
+
+	for _, name := range []string{"", "../products-1.csv", filepath.Join("sub", "products-1.csv"), "products-1.csv"} {
+		_, err := r.Path(name)
+		require.ErrorIs(t, err, ErrNotFound, name)
+	}
+}
@@ -0,0 +1,196 @@
+// Package feed renders the active catalog as product feeds for ad platforms
+// (Google Merchant Center XML, Facebook catalog CSV) and as a sitemap.
+package feed
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+)
+
+// File names written by every generation.
+const (
+	CatalogXML = "catalog.xml"
+	CatalogCSV = "catalog.csv"
+	Sitemap    = "sitemap.xml"
+)
+
+// Catalogs are the feeds handed out through signed URLs. The sitemap is
+// public.
+var Catalogs = []string{CatalogXML, CatalogCSV}
+
+// maxSitemapURLs is the per-file limit of the sitemap protocol.
+const maxSitemapURLs = 50000
+
+// Options control how products are rendered.
+type Options struct {
+	Title    string
+	SiteURL  string
+	Currency string
+	// ProductURL is the storefront page of a product; "{id}" is replaced
+	// with the product ID.
+	ProductURL string
+}
+
+func (o Options) productLink(id int) string {
+	return strings.ReplaceAll(o.ProductURL, "{id}", strconv.Itoa(id))
+}
+
+func (o Options) price(p *models.ProductWithDetails) string {
+	return strconv.FormatFloat(p.Price, 'f', 2, 64) + " " + o.Currency
+}
+
+// advertisable reports whether ad platforms accept the product: both
+// require an image and a price.
+func advertisable(p *models.ProductWithDetails) bool {
+	return p.ImageURL != "" && p.Price > 0
+}
+
+type rss struct {
+	XMLName xml.Name `xml:"rss"`
+	Version string   `xml:"version,attr"`
+	NS      string   `xml:"xmlns:g,attr"`
+	Channel channel  `xml:"channel"`
+}
+
+type channel struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	Description string        `xml:"description"`
+	Items       []merchantRow `xml:"item"`
+}
+
+type merchantRow struct {
+	ID           string `xml:"g:id"`
+	Title        string `xml:"g:title"`
+	Description  string `xml:"g:description"`
+	Link         string `xml:"g:link"`
+	ImageLink    string `xml:"g:image_link"`
+	Availability string `xml:"g:availability"`
+	Condition    string `xml:"g:condition"`
+	Price        string `xml:"g:price"`
+	Brand        string `xml:"g:brand,omitempty"`
+	ProductType  string `xml:"g:product_type,omitempty"`
+}
+
+// WriteXML writes an RSS 2.0 feed in the Google Merchant Center format.
+// Facebook catalogs accept the same file.
+func WriteXML(w io.Writer, products []*models.ProductWithDetails, opts Options) error {
+	feed := rss{
+		Version: "2.0",
+		NS:      "http://base.google.com/ns/1.0",
+		Channel: channel{
+			Title:       opts.Title,
+			Link:        opts.SiteURL,
+			Description: opts.Title,
+			Items:       []merchantRow{},
+		},
+	}
+	for _, p := range products {
+		if !advertisable(p) {
+			continue
+		}
+		availability := "in_stock"
+		if p.Stock <= 0 {
+			availability = "out_of_stock"
+		}
+		feed.Channel.Items = append(feed.Channel.Items, merchantRow{
+			ID:           strconv.Itoa(p.ID),
+			Title:        p.Title,
+			Description:  description(p),
+			Link:         opts.productLink(p.ID),
+			ImageLink:    p.ImageURL,
+			Availability: availability,
+			Condition:    "new",
+			Price:        opts.price(p),
+			Brand:        p.SellerName,
+			ProductType:  p.CategoryName,
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		return fmt.Errorf("failed to encode feed: %w", err)
+	}
+	return enc.Close()
+}
+
+// WriteCSV writes a Facebook catalog data feed.
+func WriteCSV(w io.Writer, products []*models.ProductWithDetails, opts Options) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"id", "title", "description", "availability", "condition", "price", "link", "image_link", "brand", "product_type"}); err != nil {
+		return err
+	}
+	for _, p := range products {
+		if !advertisable(p) {
+			continue
+		}
+		availability := "in stock"
+		if p.Stock <= 0 {
+			availability = "out of stock"
+		}
+		row := []string{
+			strconv.Itoa(p.ID), p.Title, description(p), availability, "new",
+			opts.price(p), opts.productLink(p.ID), p.ImageURL, p.SellerName, p.CategoryName,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// description falls back to the title; both platforms reject empty
+// descriptions.
+func description(p *models.ProductWithDetails) string {
+	if p.Description != "" {
+		return p.Description
+	}
+	return p.Title
+}
+
+type urlset struct {
+	XMLName xml.Name     `xml:"urlset"`
+	NS      string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// WriteSitemap writes a sitemap of the product pages. Products past the
+// protocol limit of 50,000 URLs are left out.
+func WriteSitemap(w io.Writer, products []*models.ProductWithDetails, opts Options) error {
+	set := urlset{NS: "http://www.sitemaps.org/schemas/sitemap/0.9", URLs: []sitemapURL{}}
+	for _, p := range products {
+		if len(set.URLs) == maxSitemapURLs {
+			break
+		}
+		set.URLs = append(set.URLs, sitemapURL{
+			Loc:     opts.productLink(p.ID),
+			LastMod: p.UpdatedAt.UTC().Format("2006-01-02"),
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(set); err != nil {
+		return fmt.Errorf("failed to encode sitemap: %w", err)
+	}
+	return enc.Close()
+}
@@ -0,0 +1,129 @@
+package feed
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+var testOpts = Options{
+	Title:      "Test shop",
+	SiteURL:    "https://shop.example.com",
+	Currency:   "USD",
+	ProductURL: "https://shop.example.com/products/{id}",
+}
+
+func testProducts() []*models.ProductWithDetails {
+	updated := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	return []*models.ProductWithDetails{
+		{
+			Product:      models.Product{ID: 1, Title: "Mug", Price: 9.5, Stock: 3, ImageURL: "https://cdn.example.com/mug.jpg", UpdatedAt: updated},
+			SellerName:   "Kiln & Co",
+			CategoryName: "Kitchen",
+		},
+		{
+			Product:    models.Product{ID: 2, Title: "Poster", Description: "A3 print", Price: 15, Stock: 0, ImageURL: "https://cdn.example.com/poster.jpg", UpdatedAt: updated},
+			SellerName: "Print Shop",
+		},
+		{
+			Product: models.Product{ID: 3, Title: "No image", Price: 5, Stock: 1, UpdatedAt: updated},
+		},
+	}
+}
+
+func TestWriteXML(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteXML(&buf, testProducts(), testOpts))
+
+	out := buf.String()
+	require.Contains(t, out, `xmlns:g="http://base.google.com/ns/1.0"`)
+	require.Contains(t, out, "<g:id>1</g:id>")
+	require.Contains(t, out, "<g:price>9.50 USD</g:price>")
+	require.Contains(t, out, "<g:brand>Kiln &amp; Co</g:brand>")
+	require.Contains(t, out, "<g:link>https://shop.example.com/products/1</g:link>")
+	require.Contains(t, out, "<g:availability>out_of_stock</g:availability>")
+	require.NotContains(t, out, "<g:id>3</g:id>", "products without an image are not advertised")
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, testProducts(), testOpts))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	require.Equal(t, "id", rows[0][0])
+	require.Equal(t, []string{"1", "Mug", "Mug", "in stock", "new", "9.50 USD", "https://shop.example.com/products/1", "https://cdn.example.com/mug.jpg", "Kiln & Co", "Kitchen"}, rows[1])
+	require.Equal(t, "out of stock", rows[2][3])
+}
+
+func TestWriteSitemap(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteSitemap(&buf, testProducts(), testOpts))
+
+	out := buf.String()
+	require.Contains(t, out, "<loc>https://shop.example.com/products/3</loc>")
+	require.Contains(t, out, "<lastmod>2026-03-04</lastmod>")
+}
+
+func TestSigner(t *testing.T) {
+	s := NewSigner("secret")
+	now := time.Unix(1_700_000_000, 0)
+	expires := now.Add(time.Hour)
+	sig := s.Sign(CatalogXML, expires)
+	exp := "1700003600"
+
+	require.True(t, s.Verify(CatalogXML, exp, sig, now))
+	require.False(t, s.Verify(CatalogCSV, exp, sig, now), "signature is bound to the feed name")
+	require.False(t, s.Verify(CatalogXML, "1700007200", sig, now), "signature is bound to the expiry")
+	require.False(t, s.Verify(CatalogXML, exp, sig, now.Add(2*time.Hour)), "expired")
+	require.False(t, NewSigner("other").Verify(CatalogXML, exp, sig, now))
+	require.Equal(t, "https://api.example.com/api/feeds/catalog.xml?expires=1700003600&signature="+sig, s.URL("https://api.example.com", CatalogXML, expires))
+}
+
+type fakeSource struct {
+	products []*models.ProductWithDetails
+	err      error
+}
+
+func (f *fakeSource) GetActiveListing(ctx context.Context) ([]*models.ProductWithDetails, error) {
+	return f.products, f.err
+}
+
+func TestGenerator_RunOnce(t *testing.T) {
+	g, err := NewGenerator(&fakeSource{products: testProducts()}, t.TempDir(), testOpts)
+	require.NoError(t, err)
+
+	_, err = g.Stat(CatalogXML)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, g.RunOnce(context.Background()))
+	for _, name := range []string{CatalogXML, CatalogCSV, Sitemap} {
+		f, err := g.Stat(name)
+		require.NoError(t, err)
+		require.Positive(t, f.Size)
+	}
+
+	_, err = g.Stat("../secrets")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	entries, err := os.ReadDir(g.dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 3, "temporary files are cleaned up")
+}
+
+func TestGenerator_RunOnce_SourceError(t *testing.T) {
+	g, err := NewGenerator(&fakeSource{err: errors.New("db down")}, t.TempDir(), testOpts)
+	require.NoError(t, err)
+
+	require.Error(t, g.RunOnce(context.Background()))
+	_, err = g.Stat(CatalogCSV)
+	require.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,142 @@
+package feed
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/workers"
+)
+
+// ErrNotFound is returned for unknown feeds and feeds not generated yet.
+var ErrNotFound = errors.New("feed not found")
+
+// Source lists the products that may appear in the feeds.
+type Source interface {
+	GetActiveListing(ctx context.Context) ([]*models.ProductWithDetails, error)
+}
+
+// File is a generated feed on disk.
+type File struct {
+	Name        string
+	Path        string
+	GeneratedAt time.Time
+	Size        int64
+}
+
+type writer func(io.Writer, []*models.ProductWithDetails, Options) error
+
+var writers = map[string]writer{
+	CatalogXML: WriteXML,
+	CatalogCSV: WriteCSV,
+	Sitemap:    WriteSitemap,
+}
+
+// Generator writes every feed into a directory on each run. Files are
+// replaced atomically, so downloads never see a partial feed.
+type Generator struct {
+	source Source
+	dir    string
+	opts   Options
+}
+
+// NewGenerator creates dir if needed.
+func NewGenerator(source Source, dir string, opts Options) (*Generator, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create feed directory: %w", err)
+	}
+	return &Generator{source: source, dir: dir, opts: opts}, nil
+}
+
+// RunOnce regenerates all feeds from the current active listing.
+func (g *Generator) RunOnce(ctx context.Context) error {
+	products, err := g.source.GetActiveListing(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load feed products: %w", err)
+	}
+
+	for name, write := range writers {
+		if err := g.write(name, products, write); err != nil {
+			logger.GetLogger().WithField("err", err).WithField("feed", name).Error("failed to write feed")
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	advertised := 0
+	for _, p := range products {
+		if advertisable(p) {
+			advertised++
+		}
+	}
+	metrics.FeedItems.Set(float64(advertised))
+	logger.GetLogger().WithFields(map[string]interface{}{
+		"products":   len(products),
+		"advertised": advertised,
+	}).Info("catalog feeds generated")
+	return nil
+}
+
+func (g *Generator) write(name string, products []*models.ProductWithDetails, write writer) error {
+	tmp, err := os.CreateTemp(g.dir, "."+name+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	buf := bufio.NewWriter(tmp)
+	if err := write(buf, products, g.opts); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := buf.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(g.dir, name))
+}
+
+// Stat returns the generated feed called name.
+func (g *Generator) Stat(name string) (File, error) {
+	if _, ok := writers[name]; !ok {
+		return File{}, ErrNotFound
+	}
+	path := filepath.Join(g.dir, name)
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return File{}, ErrNotFound
+		}
+		return File{}, err
+	}
+	return File{Name: name, Path: path, GeneratedAt: info.ModTime(), Size: info.Size()}, nil
+}
+
+// Start runs the generator every interval until ctx is cancelled.
+func (g *Generator) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		workers.Beat(ctx)
+		if err := g.RunOnce(ctx); err != nil {
+			metrics.FeedGenerationFailuresTotal.Inc()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Package geocode validates and normalizes free-form delivery addresses
+// through a pluggable provider and resolves them to coordinates.
+package geocode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when the provider cannot match the address.
+var ErrNotFound = errors.New("address could not be verified")
+
+// Address is a provider-normalized address. Lat/Lng are nil when the
+// provider does not resolve coordinates.
+type Address struct {
+	Formatted string
+	Country   string // ISO 3166-1 alpha-2
+	Region    string // ISO 3166-2, e.g. US-CA
+	Lat       *float64
+	Lng       *float64
+}
+
+// Provider validates a raw address.
+type Provider interface {
+	Name() string
+	Validate(ctx context.Context, raw string) (*Address, error)
+}
+
+// New builds the provider named by kind: "google", "here" or "stub".
+// An empty kind disables address validation and returns nil.
+func New(kind, apiKey string, timeout time.Duration) (Provider, error) {
+	switch strings.ToLower(kind) {
+	case "":
+		return nil, nil
+	case "stub":
+		return Stub{}, nil
+	case "google":
+		if apiKey == "" {
+			return nil, fmt.Errorf("google geocoding requires an API key")
+		}
+		return NewGoogle(apiKey, timeout), nil
+	case "here":
+		if apiKey == "" {
+			return nil, fmt.Errorf("HERE geocoding requires an API key")
+		}
+		return NewHERE(apiKey, timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown address provider %q", kind)
+	}
+}
+
+// Stub only normalizes whitespace and never resolves coordinates. It is
+// meant for development and tests.
+type Stub struct{}
+
+func (Stub) Name() string { return "stub" }
+
+func (Stub) Validate(ctx context.Context, raw string) (*Address, error) {
+	parts := strings.Split(raw, ",")
+	cleaned := parts[:0]
+	for _, p := range parts {
+		if p = strings.Join(strings.Fields(p), " "); p != "" {
+			cleaned = append(cleaned, p)
+		}
+	}
+	if len(cleaned) == 0 {
+		return nil, ErrNotFound
+	}
+	return &Address{Formatted: strings.Join(cleaned, ", ")}, nil
+}
+
+func regionCode(country, subdivision string) string {
+	if country == "" || subdivision == "" {
+		return ""
+	}
+	if strings.Contains(subdivision, "-") {
+		return strings.ToUpper(subdivision)
+	}
+	return strings.ToUpper(country + "-" + subdivision)
+}
@@ -0,0 +1,108 @@
+package geocode
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	p, err := New("", "", time.Second)
+	require.NoError(t, err)
+	require.Nil(t, p)
+
+	p, err = New("stub", "", time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "stub", p.Name())
+
+	_, err = New("google", "", time.Second)
+	require.Error(t, err)
+
+	_, err = New("osm", "key", time.Second)
+	require.Error(t, err)
+}
+
+func TestStub_Validate(t *testing.T) {
+	addr, err := Stub{}.Validate(context.Background(), "  12  Main   St ,, Springfield ")
+	require.NoError(t, err)
+	require.Equal(t, "12 Main St, Springfield", addr.Formatted)
+	require.Nil(t, addr.Lat)
+
+	_, err = Stub{}.Validate(context.Background(), " , ")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestGoogle_Validate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "secret", r.URL.Query().Get("key"))
+		if r.URL.Query().Get("address") == "nowhere" {
+			_, _ = w.Write([]byte(`{"status":"ZERO_RESULTS","results":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"OK","results":[{
+			"formatted_address":"1600 Amphitheatre Pkwy, Mountain View, CA 94043, USA",
+			"address_components":[
+				{"short_name":"CA","types":["administrative_area_level_1","political"]},
+				{"short_name":"US","types":["country","political"]}],
+			"geometry":{"location":{"lat":37.42,"lng":-122.08}}}]}`))
+	}))
+	defer srv.Close()
+
+	g := NewGoogle("secret", time.Second)
+	g.endpoint = srv.URL
+
+	addr, err := g.Validate(context.Background(), "1600 amphitheatre")
+	require.NoError(t, err)
+	require.Equal(t, "US", addr.Country)
+	require.Equal(t, "US-CA", addr.Region)
+	require.InDelta(t, 37.42, *addr.Lat, 1e-9)
+	require.InDelta(t, -122.08, *addr.Lng, 1e-9)
+
+	_, err = g.Validate(context.Background(), "nowhere")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestHERE_Validate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "secret", r.URL.Query().Get("apiKey"))
+		if r.URL.Query().Get("q") == "nowhere" {
+			_, _ = w.Write([]byte(`{"items":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"items":[{
+			"address":{"label":"Invalidenstraße 116, 10115 Berlin, Deutschland","stateCode":"BE"},
+			"countryInfo":{"alpha2":"DE","alpha3":"DEU"},
+			"position":{"lat":52.53,"lng":13.38}}]}`))
+	}))
+	defer srv.Close()
+
+	h := NewHERE("secret", time.Second)
+	h.endpoint = srv.URL
+
+	addr, err := h.Validate(context.Background(), "invalidenstr 116 berlin")
+	require.NoError(t, err)
+	require.Equal(t, "DE", addr.Country)
+	require.Equal(t, "DE-BE", addr.Region)
+	require.InDelta(t, 52.53, *addr.Lat, 1e-9)
+
+	_, err = h.Validate(context.Background(), "nowhere")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestGoogle_Validate_ServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	g := NewGoogle("secret", time.Second)
+	g.endpoint = srv.URL
+
+	_, err := g.Validate(context.Background(), "anything")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrNotFound)
+}
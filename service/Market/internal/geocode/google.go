@@ -0,0 +1,101 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const googleEndpoint = "https://maps.googleapis.com/maps/api/geocode/json"
+
+// Google uses the Google Maps Geocoding API.
+type Google struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+func NewGoogle(apiKey string, timeout time.Duration) *Google {
+	return &Google{
+		endpoint: googleEndpoint,
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (g *Google) Name() string { return "google" }
+
+type googleResponse struct {
+	Status  string `json:"status"`
+	Results []struct {
+		FormattedAddress  string `json:"formatted_address"`
+		AddressComponents []struct {
+			ShortName string   `json:"short_name"`
+			Types     []string `json:"types"`
+		} `json:"address_components"`
+		Geometry struct {
+			Location struct {
+				Lat float64 `json:"lat"`
+				Lng float64 `json:"lng"`
+			} `json:"location"`
+		} `json:"geometry"`
+	} `json:"results"`
+}
+
+func (g *Google) Validate(ctx context.Context, raw string) (*Address, error) {
+	q := url.Values{"address": {raw}, "key": {g.apiKey}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.endpoint+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("geocoding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoding API returned %d", resp.StatusCode)
+	}
+
+	var out googleResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode geocoding response: %w", err)
+	}
+
+	switch out.Status {
+	case "OK":
+	case "ZERO_RESULTS":
+		return nil, ErrNotFound
+	default:
+		return nil, fmt.Errorf("geocoding API status %s", out.Status)
+	}
+	if len(out.Results) == 0 {
+		return nil, ErrNotFound
+	}
+
+	best := out.Results[0]
+	addr := &Address{
+		Formatted: best.FormattedAddress,
+		Lat:       &best.Geometry.Location.Lat,
+		Lng:       &best.Geometry.Location.Lng,
+	}
+	var subdivision string
+	for _, c := range best.AddressComponents {
+		for _, t := range c.Types {
+			switch t {
+			case "country":
+				addr.Country = strings.ToUpper(c.ShortName)
+			case "administrative_area_level_1":
+				subdivision = c.ShortName
+			}
+		}
+	}
+	addr.Region = regionCode(addr.Country, subdivision)
+	return addr, nil
+}
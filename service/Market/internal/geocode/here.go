@@ -0,0 +1,82 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const hereEndpoint = "https://geocode.search.hereapi.com/v1/geocode"
+
+// HERE uses the HERE Geocoding & Search API v7.
+type HERE struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+func NewHERE(apiKey string, timeout time.Duration) *HERE {
+	return &HERE{
+		endpoint: hereEndpoint,
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (h *HERE) Name() string { return "here" }
+
+type hereResponse struct {
+	Items []struct {
+		Address struct {
+			Label     string `json:"label"`
+			StateCode string `json:"stateCode"`
+		} `json:"address"`
+		CountryInfo struct {
+			Alpha2 string `json:"alpha2"`
+		} `json:"countryInfo"`
+		Position struct {
+			Lat float64 `json:"lat"`
+			Lng float64 `json:"lng"`
+		} `json:"position"`
+	} `json:"items"`
+}
+
+func (h *HERE) Validate(ctx context.Context, raw string) (*Address, error) {
+	q := url.Values{"q": {raw}, "apiKey": {h.apiKey}, "show": {"countryInfo"}, "limit": {"1"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.endpoint+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("geocoding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoding API returned %d", resp.StatusCode)
+	}
+
+	var out hereResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode geocoding response: %w", err)
+	}
+	if len(out.Items) == 0 {
+		return nil, ErrNotFound
+	}
+
+	best := out.Items[0]
+	country := strings.ToUpper(best.CountryInfo.Alpha2)
+	return &Address{
+		Formatted: best.Address.Label,
+		Country:   country,
+		Region:    regionCode(country, best.Address.StateCode),
+		Lat:       &best.Position.Lat,
+		Lng:       &best.Position.Lng,
+	}, nil
+}
@@ -0,0 +1,60 @@
+// Package listing keeps the product_listing materialized view fresh.
+package listing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+	"github.com/Zifeldev/marketback/service/Market/internal/workers"
+)
+
+// Refresher rebuilds the listing view.
+type Refresher interface {
+	RefreshListing(ctx context.Context) error
+}
+
+// Scheduler refreshes the listing view on a fixed interval. New, edited and
+// moderated products appear in GET /api/products after the next run.
+type Scheduler struct {
+	refresher Refresher
+	now       func() time.Time
+}
+
+func NewScheduler(refresher Refresher) *Scheduler {
+	return &Scheduler{refresher: refresher, now: time.Now}
+}
+
+// RunOnce refreshes the view and records how long it took.
+func (s *Scheduler) RunOnce(ctx context.Context) error {
+	start := s.now()
+	if err := s.refresher.RefreshListing(ctx); err != nil {
+		return fmt.Errorf("failed to refresh product listing: %w", err)
+	}
+
+	elapsed := s.now().Sub(start)
+	metrics.ListingRefreshDuration.Observe(elapsed.Seconds())
+	logger.GetLogger().WithField("duration_ms", elapsed.Milliseconds()).Debug("product listing refreshed")
+	return nil
+}
+
+// Start runs the scheduler every interval until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		workers.Beat(ctx)
+		if err := s.RunOnce(ctx); err != nil {
+			metrics.ListingRefreshFailuresTotal.Inc()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
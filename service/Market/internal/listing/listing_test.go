@@ -0,0 +1,53 @@
+package listing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRefresher struct {
+	calls int
+	err   error
+}
+
+func (f *fakeRefresher) RefreshListing(ctx context.Context) error {
+	f.calls++
+	return f.err
+}
+
+func TestScheduler_RunOnce(t *testing.T) {
+	r := &fakeRefresher{}
+	s := NewScheduler(r)
+
+	require.NoError(t, s.RunOnce(context.Background()))
+	require.Equal(t, 1, r.calls)
+}
+
+func TestScheduler_RunOnce_Error(t *testing.T) {
+	s := NewScheduler(&fakeRefresher{err: errors.New("db down")})
+	require.Error(t, s.RunOnce(context.Background()))
+}
+
+func TestScheduler_Start_StopsOnCancel(t *testing.T) {
+	r := &fakeRefresher{}
+	s := NewScheduler(r)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Start(ctx, time.Hour)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop")
+	}
+	require.Equal(t, 1, r.calls)
+}
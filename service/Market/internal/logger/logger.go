@@ -2,24 +2,70 @@ package logger
 
 import (
 	"os"
+	"time"
 
+	"github.com/Zifeldev/marketback/pkg/logsampling"
 	"github.com/sirupsen/logrus"
 )
 
 var Log *logrus.Logger
 
+// Options configures the service logger
+type Options struct {
+	Level  string
+	Format string // json (default) or text
+
+	// Debug and trace entries are sampled per message: the first
+	// SampleFirst entries in every SampleTick are logged, then every
+	// SampleThereafter-th. Zero SampleFirst disables sampling.
+	SampleFirst      int
+	SampleThereafter int
+	SampleTick       time.Duration
+
+	// Fields are added to every entry (e.g. service name and version)
+	Fields map[string]interface{}
+}
+
 func InitLogger(level string) *logrus.Logger {
-	Log = logrus.New()
-	Log.SetOutput(os.Stdout)
-	Log.SetFormatter(&logrus.JSONFormatter{})
+	return Init(Options{Level: level})
+}
+
+// Init builds the global logger from opts
+func Init(opts Options) *logrus.Logger {
+	Log = New(opts)
+	return Log
+}
+
+func New(opts Options) *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(os.Stdout)
 
-	parsedLevel, err := logrus.ParseLevel(level)
+	var formatter logrus.Formatter
+	if opts.Format == "text" {
+		formatter = &logrus.TextFormatter{
+			TimestampFormat: time.RFC3339,
+			FullTimestamp:   true,
+		}
+	} else {
+		formatter = &logrus.JSONFormatter{
+			TimestampFormat: time.RFC3339Nano,
+		}
+	}
+	if opts.SampleFirst > 0 {
+		formatter = logsampling.NewFormatter(formatter, opts.SampleFirst, opts.SampleThereafter, opts.SampleTick)
+	}
+	log.SetFormatter(formatter)
+	if len(opts.Fields) > 0 {
+		log.AddHook(staticFieldsHook{fields: opts.Fields})
+	}
+
+	parsedLevel, err := logrus.ParseLevel(opts.Level)
 	if err != nil {
 		parsedLevel = logrus.InfoLevel
 	}
-	Log.SetLevel(parsedLevel)
+	log.SetLevel(parsedLevel)
 
-	return Log
+	return log
 }
 
 func GetLogger() *logrus.Logger {
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_Sampled(t *testing.T) {
+	log := New(Options{Level: "debug", SampleFirst: 2, SampleThereafter: 3, SampleTick: time.Hour})
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+
+	for i := 0; i < 8; i++ {
+		log.Debug("cache lookup")
+	}
+
+	assert.Equal(t, 4, strings.Count(buf.String(), "cache lookup"))
+}
+
+func TestNew_TextFormat(t *testing.T) {
+	log := New(Options{Level: "info", Format: "text"})
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	log.Info("plain")
+
+	assert.Contains(t, buf.String(), "msg=plain")
+}
+
+func TestFromContext(t *testing.T) {
+	entry := logrus.NewEntry(GetLogger()).WithField("request_id", "abc")
+	ctx := WithContext(context.Background(), entry)
+
+	assert.Same(t, entry, FromContext(ctx))
+	assert.NotNil(t, FromContext(context.Background()))
+}
+
+func TestNew_StaticFields(t *testing.T) {
+	log := New(Options{Level: "info", Fields: map[string]interface{}{"version": "1.2.3"}})
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	log.WithField("version", "override").Info("first")
+	log.Info("second")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"version":"override"`)
+	assert.Contains(t, lines[1], `"version":"1.2.3"`)
+}
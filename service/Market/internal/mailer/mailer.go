@@ -0,0 +1,83 @@
+// Package mailer sends plain-text transactional email.
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Mailer delivers a single message.
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// SMTP sends mail through an SMTP relay. Authentication is skipped when no
+// username is configured, e.g. for a local relay.
+type SMTP struct {
+	addr string
+	from string
+	auth smtp.Auth
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// New returns an SMTP mailer, or nil when host is empty and email is
+// disabled.
+func New(host string, port int, username, password, from string) (Mailer, error) {
+	if host == "" {
+		return nil, nil
+	}
+	if from == "" {
+		return nil, fmt.Errorf("a sender address is required")
+	}
+	if port <= 0 {
+		return nil, fmt.Errorf("invalid SMTP port %d", port)
+	}
+
+	m := &SMTP{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		from: from,
+		send: smtp.SendMail,
+	}
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m, nil
+}
+
+func (m *SMTP) Send(ctx context.Context, to, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	msg, err := buildMessage(m.from, to, subject, body, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := m.send(m.addr, m.auth, m.from, []string{to}, msg); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+func buildMessage(from, to, subject, body string, date time.Time) ([]byte, error) {
+	for _, h := range []string{from, to, subject} {
+		if strings.ContainsAny(h, "\r\n") {
+			return nil, fmt.Errorf("email header contains a line break")
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + to + "\r\n")
+	b.WriteString("Subject: " + subject + "\r\n")
+	b.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String()), nil
+}
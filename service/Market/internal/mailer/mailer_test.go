@@ -0,0 +1,165 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_DisabledWithoutHost(t *testing.T) {
+	m, err := New("", 587, "", "", "")
+	require.NoError(t, err)
+	require.Nil(t, m)
+}
+
+func TestNew_RequiresSender(t *testing.T) {
+	_, err := New("smtp.example.com", 587, "", "", "")
+	require.Error(t, err)
+}
+
+func TestSMTP_Send(t *testing.T) {
+	m, err := New("smtp.example.com", 587, "", "", "support@example.com")
+	require.NoError(t, err)
+
+	var gotAddr string
+	var gotTo []string
+	var gotMsg []byte
+	m.(*SMTP).send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotMsg = addr, to, msg
+		return nil
+	}
+
+	require.NoError(t, m.Send(context.Background(), "buyer@example.com", "Ticket #4 updated", "line one\nline two"))
+	require.Equal(t, "smtp.example.com:587", gotAddr)
+	require.Equal(t, []string{"buyer@example.com"}, gotTo)
+	require.Contains(t, string(gotMsg), "Subject: Ticket #4 updated\r\n")
+	require.True(t, strings.HasSuffix(string(gotMsg), "\r\n\r\nline one\r\nline two"))
+}
+
+func TestBuildMessage_RejectsHeaderInjection(t *testing.T) {
+	_, err := buildMessage("a@example.com", "b@example.com", "hi\r\nBcc: x@example.com", "body", time.Now())
+	require.Error(t, err)
+}
+
+func TestOutbox(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "maildir")
+	outbox, err := NewOutbox("shop@example.com", dir, 2)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, outbox.Send(ctx, "a@example.com", "first", "1"))
+	require.NoError(t, outbox.Send(ctx, "b@example.com", "second", "2"))
+	require.NoError(t, outbox.Send(ctx, "A@example.com", "third", "3"))
+
+	all := outbox.Messages("")
+	require.Len(t, all, 2)
+	require.Equal(t, "second", all[0].Subject)
+
+	mine := outbox.Messages("a@example.com")
+	require.Len(t, mine, 1)
+	require.Equal(t, "third", mine[0].Subject)
+
+	delivered, err := os.ReadDir(filepath.Join(dir, "new"))
+	require.NoError(t, err)
+	require.Len(t, delivered, 3)
+	raw, err := os.ReadFile(filepath.Join(dir, "new", delivered[0].Name()))
+	require.NoError(t, err)
+	require.Contains(t, string(raw), "From: shop@example.com\r\n")
+}
+
+func TestSendGrid_Send(t *testing.T) {
+	var got sendGridMessage
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		if got.Personalizations[0].To[0].Email == "bounce@example.com" {
+			http.Error(w, `{"errors":[{"message":"bad recipient"}]}`, http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	_, err := NewSendGrid("", "shop@example.com")
+	require.Error(t, err)
+	m, err := NewSendGrid("SG.key", "shop@example.com")
+	require.NoError(t, err)
+	m.(*SendGrid).endpoint = srv.URL
+
+	require.NoError(t, m.Send(context.Background(), "buyer@example.com", "Order shipped", "On its way"))
+	require.Equal(t, "Bearer SG.key", auth)
+	require.Equal(t, "shop@example.com", got.From.Email)
+	require.Equal(t, "Order shipped", got.Subject)
+	require.Equal(t, "On its way", got.Content[0].Value)
+
+	err = m.Send(context.Background(), "bounce@example.com", "Order shipped", "On its way")
+	require.ErrorContains(t, err, "bad recipient")
+	require.Error(t, m.Send(context.Background(), "buyer@example.com", "hi\nBcc: x@example.com", "body"))
+}
+
+func TestNotifier(t *testing.T) {
+	outbox, err := NewOutbox("shop@example.com", "", 10)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	none, err := NewNotifier(nil, "", nil)
+	require.NoError(t, err)
+	require.False(t, none.Enabled(EventOrderStatus))
+	require.ErrorIs(t, none.Notify(ctx, EventOrderStatus, "a@example.com", nil), ErrDisabled)
+
+	_, err = NewNotifier(outbox, "", []string{"order_shipped"})
+	require.ErrorContains(t, err, "order_confirmation, order_status")
+
+	n, err := NewNotifier(outbox, "", []string{EventOrderConfirmation})
+	require.NoError(t, err)
+	require.False(t, n.Enabled(EventOrderConfirmation))
+	require.True(t, n.Enabled(EventOrderStatus))
+
+	require.NoError(t, n.Notify(ctx, EventOrderStatus, "a@example.com", map[string]string{
+		"OrderNumber": "MB-2024-000042", "FromStatus": "paid", "Status": "shipped",
+	}))
+	msg := outbox.Messages("a@example.com")[0]
+	require.Equal(t, "Order MB-2024-000042 is shipped", msg.Subject)
+	require.Equal(t, "Your order MB-2024-000042 is on its way.\n", msg.Body)
+
+	// Missing fields are an error rather than an email saying "<no value>"
+	require.Error(t, n.Notify(ctx, EventOrderStatus, "a@example.com", map[string]string{"Status": "shipped"}))
+}
+
+func TestNotifier_CustomTemplates(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "order_status.tmpl"),
+		[]byte("Subject: {{.OrderNumber}}: {{.Status}}\n\nStatus {{.FromStatus}} → {{.Status}}\n"), 0o644))
+
+	n, err := NewNotifier(&Outbox{limit: 1}, dir, nil)
+	require.NoError(t, err)
+	subject, body, err := n.Render(EventOrderStatus, map[string]string{"OrderNumber": "MB-1", "FromStatus": "paid", "Status": "shipped"})
+	require.NoError(t, err)
+	require.Equal(t, "MB-1: shipped", subject)
+	require.Equal(t, "Status paid → shipped\n", body)
+
+	// Templates the directory does not replace stay built in
+	subject, _, err = n.Render(EventOrderConfirmation, map[string]any{"OrderNumber": "MB-1", "Items": nil, "Total": "1.00", "DeliveryAddress": "1 Main St"})
+	require.NoError(t, err)
+	require.Equal(t, "Order MB-1 confirmed", subject)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "order_status.tmpl"), []byte("Order {{.OrderNumber}}\n"), 0o644))
+	n, err = NewNotifier(&Outbox{limit: 1}, dir, nil)
+	require.NoError(t, err)
+	_, _, err = n.Render(EventOrderStatus, map[string]string{"OrderNumber": "MB-1"})
+	require.ErrorContains(t, err, "Subject: line")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "order_status.tmpl"), []byte("Subject: {{.OrderNumber\n"), 0o644))
+	_, err = NewNotifier(&Outbox{limit: 1}, dir, nil)
+	require.ErrorContains(t, err, "invalid email template order_status")
+}
@@ -0,0 +1,125 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Events emailed through a Notifier; each one renders the template of the
+// same name.
+const (
+	EventOrderConfirmation = "order_confirmation"
+	EventOrderStatus       = "order_status"
+)
+
+// ErrDisabled is returned by Notify for events that are not emailed.
+var ErrDisabled = errors.New("email is disabled for this event")
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// Notifier emails users about events from text templates, one per event
+// named <event>.tmpl. A template renders a "Subject: " line, a blank line
+// and the body. A nil Notifier emails nothing.
+type Notifier struct {
+	mailer    Mailer
+	templates map[string]*template.Template
+	disabled  map[string]bool
+}
+
+// NewNotifier creates a notifier sending through m, or returns nil when m
+// is nil. Templates in dir, when set, replace the built-in ones of the
+// same name; events in disabled are not emailed.
+func NewNotifier(m Mailer, dir string, disabled []string) (*Notifier, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	n := &Notifier{mailer: m, templates: map[string]*template.Template{}, disabled: map[string]bool{}}
+	names, err := defaultTemplates.ReadDir("templates")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read email templates: %w", err)
+	}
+	for _, entry := range names {
+		event := strings.TrimSuffix(entry.Name(), ".tmpl")
+		text, err := defaultTemplates.ReadFile("templates/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read email template %s: %w", event, err)
+		}
+		if dir != "" {
+			custom, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+			if err == nil {
+				text = custom
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return nil, fmt.Errorf("failed to read email template %s: %w", event, err)
+			}
+		}
+		tmpl, err := template.New(event).Option("missingkey=error").Parse(string(text))
+		if err != nil {
+			return nil, fmt.Errorf("invalid email template %s: %w", event, err)
+		}
+		n.templates[event] = tmpl
+	}
+
+	for _, event := range disabled {
+		if _, ok := n.templates[event]; !ok {
+			return nil, fmt.Errorf("unknown email event %q, must be one of %s", event, strings.Join(n.Events(), ", "))
+		}
+		n.disabled[event] = true
+	}
+	return n, nil
+}
+
+// Events lists the events the notifier has templates for.
+func (n *Notifier) Events() []string {
+	events := make([]string, 0, len(n.templates))
+	for event := range n.templates {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+	return events
+}
+
+// Enabled reports whether event is emailed.
+func (n *Notifier) Enabled(event string) bool {
+	return n != nil && n.templates[event] != nil && !n.disabled[event]
+}
+
+// Notify renders the template of event with data and emails it to to.
+func (n *Notifier) Notify(ctx context.Context, event, to string, data any) error {
+	if !n.Enabled(event) {
+		return ErrDisabled
+	}
+	subject, body, err := n.Render(event, data)
+	if err != nil {
+		return err
+	}
+	return n.mailer.Send(ctx, to, subject, body)
+}
+
+// Render renders the subject and body of event's template.
+func (n *Notifier) Render(event string, data any) (string, string, error) {
+	tmpl := n.templates[event]
+	if tmpl == nil {
+		return "", "", fmt.Errorf("unknown email event %q", event)
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", "", fmt.Errorf("failed to render email template %s: %w", event, err)
+	}
+
+	head, body, _ := strings.Cut(strings.TrimLeft(out.String(), "\n"), "\n")
+	subject, ok := strings.CutPrefix(head, "Subject: ")
+	if !ok || strings.TrimSpace(subject) == "" {
+		return "", "", fmt.Errorf("email template %s must start with a Subject: line", event)
+	}
+	return strings.TrimSpace(subject), strings.TrimSpace(body) + "\n", nil
+}
@@ -21,6 +21,13 @@ var (
 		},
 	)
 
+	ProductViewFlushFailuresTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "market_product_view_flush_failures_total",
+			Help: "Total number of failed flushes of per-product view counts",
+		},
+	)
+
 	CartItemsAddedTotal = promauto.NewCounter(
 		prometheus.CounterOpts{
 			Name: "market_cart_items_added_total",
@@ -49,4 +56,279 @@ var (
 			Help: "Total number of Redis cache misses",
 		},
 	)
+
+	// Retention metrics
+	RetentionRowsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "market_retention_rows_total",
+			Help: "Total number of rows purged or anonymized by retention policies",
+		},
+		[]string{"policy", "mode"},
+	)
+
+	RetentionRunFailuresTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "market_retention_run_failures_total",
+			Help: "Total number of failed retention runs",
+		},
+	)
+
+	// HTTP metrics
+	HTTPRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "market_http_request_duration_seconds",
+			Help:    "HTTP request latency by route template",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"method", "route", "status_class"},
+	)
+
+	HTTPRequestErrorsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "market_http_request_errors_total",
+			Help: "Total number of HTTP responses with status >= 400 by route template",
+		},
+		[]string{"method", "route", "status"},
+	)
+
+	HTTPCompressionRatio = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "market_http_compression_ratio",
+			Help:    "Compressed to original response size ratio by route template and encoding",
+			Buckets: []float64{0.05, 0.1, 0.15, 0.2, 0.3, 0.4, 0.5, 0.7, 1},
+		},
+		[]string{"route", "encoding"},
+	)
+
+	HTTPCompressedBytesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "market_http_compressed_bytes_total",
+			Help: "Total bytes of compressed responses before (original) and after (compressed) encoding",
+		},
+		[]string{"encoding", "stage"},
+	)
+
+	// Address validation metrics
+	AddressValidationsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "market_address_validations_total",
+			Help: "Total number of checkout address validations by provider and outcome (ok, not_found, error)",
+		},
+		[]string{"provider", "outcome"},
+	)
+
+	// Seller statement metrics
+	StatementsGeneratedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "market_statements_generated_total",
+			Help: "Total number of seller statements written by the statement scheduler",
+		},
+	)
+
+	StatementRunFailuresTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "market_statement_run_failures_total",
+			Help: "Total number of failed statement scheduler runs",
+		},
+	)
+
+	// Low stock alert metrics
+	LowStockAlertsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "market_low_stock_alerts_total",
+			Help: "Total number of low stock notifications sent to sellers",
+		},
+	)
+
+	LowStockRunFailuresTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "market_low_stock_run_failures_total",
+			Help: "Total number of failed low stock alert runs",
+		},
+	)
+
+	// Transform rule metrics
+	TransformFailuresTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "market_transform_failures_total",
+			Help: "Total number of transform rules that failed to apply by target (event, notification)",
+		},
+		[]string{"target"},
+	)
+
+	// Seller payout metrics
+	PayoutsSentTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "market_payouts_sent_total",
+			Help: "Total number of seller payouts sent through the payment provider",
+		},
+	)
+
+	PayoutFailuresTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "market_payout_failures_total",
+			Help: "Total number of seller payouts that failed after their last attempt",
+		},
+	)
+
+	PayoutRunFailuresTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "market_payout_run_failures_total",
+			Help: "Total number of failed payout runs",
+		},
+	)
+
+	// Seller health metrics
+	SellerHealthActionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "market_seller_health_actions_total",
+			Help: "Total number of seller warnings and suspensions applied by seller health rules",
+		},
+		[]string{"action"},
+	)
+
+	SellerHealthRunFailuresTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "market_seller_health_run_failures_total",
+			Help: "Total number of failed seller health enforcement runs",
+		},
+	)
+
+	// Product listing view metrics
+	ListingRefreshDuration = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "market_listing_refresh_duration_seconds",
+			Help:    "Time taken to refresh the product_listing materialized view",
+			Buckets: prometheus.DefBuckets,
+		},
+	)
+
+	ListingRefreshFailuresTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "market_listing_refresh_failures_total",
+			Help: "Total number of failed product_listing refreshes",
+		},
+	)
+
+	// Catalog feed metrics
+	FeedItems = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "market_feed_items",
+			Help: "Number of products in the last generated catalog feed",
+		},
+	)
+
+	FeedGenerationFailuresTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "market_feed_generation_failures_total",
+			Help: "Total number of failed catalog feed generations",
+		},
+	)
+
+	// Product export job metrics
+	ExportJobsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "market_export_jobs_total",
+			Help: "Total number of finished product export jobs, by status",
+		},
+		[]string{"status"},
+	)
+
+	// Domain event metrics
+	EventsPublishedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "market_events_published_total",
+			Help: "Total number of domain events published to the message broker, by type",
+		},
+		[]string{"type"},
+	)
+
+	EventPublishFailuresTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "market_event_publish_failures_total",
+			Help: "Total number of event batches that failed to publish and are retried",
+		},
+	)
+
+	// Cache warming metrics
+	CacheWarmPagesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "market_cache_warm_pages_total",
+			Help: "Total number of catalog pages rendered by the cache warmer, by result",
+		},
+		[]string{"result"},
+	)
+
+	// Seller API usage metrics
+	SellerAPIThrottledTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "market_seller_api_throttled_total",
+			Help: "Total number of seller API requests rejected by their API plan limit, by plan",
+		},
+		[]string{"plan"},
+	)
+
+	APIUsageRollupFailuresTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "market_api_usage_rollup_failures_total",
+			Help: "Total number of failed seller API usage rollups",
+		},
+	)
+
+	// Stock reservation metrics
+	StockReservationsReleasedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "market_stock_reservations_released_total",
+			Help: "Total number of expired cart stock reservations released",
+		},
+	)
+
+	StockReservationSweepFailuresTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "market_stock_reservation_sweep_failures_total",
+			Help: "Total number of failed stock reservation sweeps",
+		},
+	)
+
+	// Money invariant metrics
+	MoneyInvariantViolationsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "market_money_invariant_violations_total",
+			Help: "Total number of orders rejected because their amounts failed an invariant check",
+		},
+	)
+
+	// Fault injection metrics
+	ChaosFaultsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "market_chaos_faults_total",
+			Help: "Total number of injected faults by layer (http, repository) and kind (latency, error)",
+		},
+		[]string{"layer", "kind"},
+	)
+
+	// Bot detection metrics
+	HTTPRequestsByClassTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "market_http_requests_by_class_total",
+			Help: "Total number of HTTP requests by route and traffic class (human, crawler, scraper)",
+		},
+		[]string{"route", "class"},
+	)
+
+	BotRequestsBlockedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "market_bot_requests_blocked_total",
+			Help: "Total number of scraper requests rejected over the bot request limit",
+		},
+	)
+
+	// Moderation metrics
+	ModerationChecksTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "market_moderation_checks_total",
+			Help: "Total number of moderation checks by checker and outcome (pass, hold, error)",
+		},
+		[]string{"checker", "outcome"},
+	)
 )
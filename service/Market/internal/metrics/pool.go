@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// RegisterPoolMetrics exposes pgxpool statistics. Values are read from
+// pool.Stat() on every scrape, so it must be called once per pool.
+func RegisterPoolMetrics(pool *pgxpool.Pool) {
+	promauto.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "market_db_pool_acquired_conns",
+			Help: "Number of connections currently checked out of the pool",
+		},
+		func() float64 { return float64(pool.Stat().AcquiredConns()) },
+	)
+
+	promauto.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "market_db_pool_idle_conns",
+			Help: "Number of idle connections in the pool",
+		},
+		func() float64 { return float64(pool.Stat().IdleConns()) },
+	)
+
+	promauto.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "market_db_pool_total_conns",
+			Help: "Total number of connections in the pool",
+		},
+		func() float64 { return float64(pool.Stat().TotalConns()) },
+	)
+
+	promauto.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "market_db_pool_max_conns",
+			Help: "Maximum size of the pool",
+		},
+		func() float64 { return float64(pool.Stat().MaxConns()) },
+	)
+
+	promauto.NewCounterFunc(
+		prometheus.CounterOpts{
+			Name: "market_db_pool_acquire_total",
+			Help: "Total number of successful connection acquires",
+		},
+		func() float64 { return float64(pool.Stat().AcquireCount()) },
+	)
+
+	promauto.NewCounterFunc(
+		prometheus.CounterOpts{
+			Name: "market_db_pool_empty_acquire_total",
+			Help: "Total number of acquires that had to wait for a connection",
+		},
+		func() float64 { return float64(pool.Stat().EmptyAcquireCount()) },
+	)
+
+	promauto.NewCounterFunc(
+		prometheus.CounterOpts{
+			Name: "market_db_pool_canceled_acquire_total",
+			Help: "Total number of acquires canceled by their context",
+		},
+		func() float64 { return float64(pool.Stat().CanceledAcquireCount()) },
+	)
+
+	promauto.NewCounterFunc(
+		prometheus.CounterOpts{
+			Name: "market_db_pool_acquire_duration_seconds_total",
+			Help: "Total time spent acquiring connections, including waits on an exhausted pool",
+		},
+		func() float64 { return pool.Stat().AcquireDuration().Seconds() },
+	)
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apiusage"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// SellerAPIUsage throttles sellers by their API plan and counts every
+// request in their daily usage. It must run after JWTAuth. Tracking errors
+// never fail the request; a nil tracker disables the middleware.
+func SellerAPIUsage(tracker *apiusage.Tracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := c.Get("user_id")
+		if tracker == nil || !ok {
+			c.Next()
+			return
+		}
+		uid := userID.(int)
+		ctx := c.Request.Context()
+
+		plan, limit, err := tracker.Plan(ctx, uid)
+		if err != nil {
+			logger.FromContext(ctx).WithField("err", err).Warn("failed to get api plan")
+		}
+		allowed, err := tracker.Allow(ctx, uid, limit)
+		if err != nil {
+			logger.FromContext(ctx).WithField("err", err).Warn("failed to check api plan limit")
+			allowed = true
+		}
+
+		if allowed {
+			if limit > 0 {
+				c.Header("X-API-Plan-Limit", fmt.Sprintf("%d", limit))
+			}
+			c.Next()
+		} else {
+			metrics.SellerAPIThrottledTotal.WithLabelValues(plan).Inc()
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":       "api plan limit exceeded",
+				"plan":        plan,
+				"retry_after": tracker.Window().Seconds(),
+			})
+			c.Abort()
+		}
+
+		if err := tracker.Record(ctx, uid, c.Writer.Status(), !allowed); err != nil {
+			logger.FromContext(ctx).WithField("err", err).Warn("failed to record api usage")
+		}
+	}
+}
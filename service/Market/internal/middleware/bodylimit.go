@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimit rejects request bodies larger than jsonLimit bytes, or
+// uploadLimit bytes for multipart uploads, with 413 PAYLOAD_TOO_LARGE.
+//
+// Non-multipart bodies are read up front (they are bound into memory by the
+// handlers anyway), so bodies without a Content-Length are caught before any
+// binding happens. Multipart bodies are streamed through http.MaxBytesReader.
+func BodyLimit(jsonLimit, uploadLimit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		multipart := strings.HasPrefix(c.ContentType(), "multipart/")
+		limit := jsonLimit
+		if multipart {
+			limit = uploadLimit
+		}
+
+		if c.Request.ContentLength > limit {
+			rejectBody(c, limit)
+			return
+		}
+
+		if multipart {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+		c.Request.Body.Close()
+		if err != nil {
+			logger.FromContext(c.Request.Context()).WithField("err", err).Warn("failed to read request body")
+			c.AbortWithStatusJSON(http.StatusBadRequest, apperrors.BadRequest("failed to read request body"))
+			return
+		}
+		if int64(len(body)) > limit {
+			rejectBody(c, limit)
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+func rejectBody(c *gin.Context, limit int64) {
+	logger.FromContext(c.Request.Context()).WithFields(map[string]interface{}{
+		"content_length": c.Request.ContentLength,
+		"limit":          limit,
+		"path":           c.Request.URL.Path,
+	}).Warn("request body too large")
+
+	// Ask the client to drop the connection instead of draining the body.
+	c.Header("Connection", "close")
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, apperrors.PayloadTooLarge(limit))
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bodyLimitRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(BodyLimit(16, 1024))
+	router.POST("/json", func(c *gin.Context) {
+		var req map[string]string
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, req)
+	})
+	router.POST("/upload", func(c *gin.Context) {
+		if _, err := c.FormFile("file"); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func uploadBody(t *testing.T, size int) (io.Reader, string) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", "a.png")
+	require.NoError(t, err)
+	_, err = part.Write(bytes.Repeat([]byte("x"), size))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return &body, w.FormDataContentType()
+}
+
+func TestBodyLimit_JSONWithinLimit(t *testing.T) {
+	req := httptest.NewRequest("POST", "/json", strings.NewReader(`{"a":"b"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	bodyLimitRouter().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestBodyLimit_JSONTooLarge(t *testing.T) {
+	req := httptest.NewRequest("POST", "/json", strings.NewReader(`{"a":"bbbbbbbbbbbbbbbbbbbb"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	bodyLimitRouter().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"PAYLOAD_TOO_LARGE"`)
+}
+
+func TestBodyLimit_JSONWithoutContentLength(t *testing.T) {
+	req := httptest.NewRequest("POST", "/json", strings.NewReader(`{"a":"bbbbbbbbbbbbbbbbbbbb"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.ContentLength = -1
+	rec := httptest.NewRecorder()
+	bodyLimitRouter().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestBodyLimit_UploadUsesUploadLimit(t *testing.T) {
+	body, contentType := uploadBody(t, 512)
+	req := httptest.NewRequest("POST", "/upload", body)
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	bodyLimitRouter().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestBodyLimit_UploadTooLarge(t *testing.T) {
+	body, contentType := uploadBody(t, 4096)
+	req := httptest.NewRequest("POST", "/upload", body)
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	bodyLimitRouter().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/botdetect"
+	"github.com/Zifeldev/marketback/service/Market/internal/cache"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// BotDetection classifies each request as human, crawler or scraper and
+// sets traffic_class, which Metrics and RequestLogger report and handlers
+// use to serve crawlers lighter responses. Scrapers may make limit requests
+// per window and client IP, counted in Redis or, without it, in memory;
+// limit 0 lets them through. A nil detector classifies nothing.
+func BotDetection(detector *botdetect.Detector, redis *cache.RedisCache, limit int, window time.Duration) gin.HandlerFunc {
+	memLimiter := newInMemoryLimiter()
+
+	return func(c *gin.Context) {
+		if detector == nil {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		class := detector.Classify(ctx, c.Request, c.ClientIP())
+		c.Set("traffic_class", class)
+		if class != botdetect.ClassScraper || limit <= 0 {
+			c.Next()
+			return
+		}
+
+		count, allowed := countRequest(ctx, redis, memLimiter, fmt.Sprintf("bots:%s", c.ClientIP()), limit, window)
+		if !allowed {
+			metrics.BotRequestsBlockedTotal.Inc()
+			if count == int64(limit)+1 {
+				logger.FromContext(ctx).WithFields(map[string]interface{}{
+					"client_ip":  c.ClientIP(),
+					"user_agent": c.Request.UserAgent(),
+				}).Warn("scraper blocked")
+			}
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":       "too many requests",
+				"retry_after": window.Seconds(),
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/botdetect"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBotDetection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(BotDetection(botdetect.New(nil, nil), nil, 2, time.Minute))
+	router.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("traffic_class"))
+	})
+
+	get := func(ua, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("User-Agent", ua)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("Mozilla/5.0 (compatible; Googlebot/2.1)", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, botdetect.ClassCrawler, w.Body.String())
+
+	for i := 0; i < 2; i++ {
+		w = get("curl/8.4.0", "10.0.0.2")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, botdetect.ClassScraper, w.Body.String())
+	}
+	assert.Equal(t, http.StatusTooManyRequests, get("curl/8.4.0", "10.0.0.2").Code)
+	assert.Equal(t, http.StatusOK, get("curl/8.4.0", "10.0.0.3").Code, "limits are per IP")
+
+	for i := 0; i < 3; i++ {
+		w = get("Mozilla/5.0 (X11; Linux x86_64) Firefox/131.0", "10.0.0.4")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, botdetect.ClassHuman, w.Body.String())
+	}
+}
+
+func TestBotDetection_NilDetector(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(BotDetection(nil, nil, 1, time.Minute))
+	router.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("traffic_class"))
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/chaos"
+	"github.com/gin-gonic/gin"
+)
+
+// Chaos delays or fails requests through inj. Failed requests get 503
+// FAULT_INJECTED, as from an overloaded instance; requests whose context
+// ends during the delay get 504. The health check is never touched so
+// orchestrators keep the instance in rotation.
+func Chaos(inj *chaos.Injector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.URL.Path == "/health" {
+			c.Next()
+			return
+		}
+
+		err := inj.Inject(c.Request.Context(), chaos.LayerHTTP)
+		switch {
+		case err == nil:
+			c.Next()
+		case errors.Is(err, chaos.ErrInjected):
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, apperrors.FaultInjected())
+		default:
+			c.AbortWithStatusJSON(http.StatusGatewayTimeout, apperrors.ErrTimeout)
+		}
+	}
+}
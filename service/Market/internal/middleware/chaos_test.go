@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/chaos"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChaos(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		injector *chaos.Injector
+		path     string
+		timeout  time.Duration
+		wantCode int
+	}{
+		{name: "disabled", injector: nil, path: "/api/products", wantCode: http.StatusOK},
+		{name: "injected error", injector: chaos.New(chaos.Config{ErrorRate: 1}), path: "/api/products", wantCode: http.StatusServiceUnavailable},
+		{name: "health is exempt", injector: chaos.New(chaos.Config{ErrorRate: 1}), path: "/health", wantCode: http.StatusOK},
+		{
+			name:     "latency past the deadline",
+			injector: chaos.New(chaos.Config{Latency: time.Minute, LatencyRate: 1}),
+			path:     "/api/products",
+			timeout:  10 * time.Millisecond,
+			wantCode: http.StatusGatewayTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(Chaos(tt.injector))
+			router.GET(tt.path, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.timeout > 0 {
+				ctx, cancel := context.WithTimeout(req.Context(), tt.timeout)
+				defer cancel()
+				req = req.WithContext(ctx)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantCode == http.StatusServiceUnavailable {
+				assert.Contains(t, rec.Body.String(), "FAULT_INJECTED")
+			}
+		})
+	}
+}
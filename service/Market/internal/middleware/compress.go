@@ -0,0 +1,224 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+
+	// brotliLevel trades ratio for speed; levels above ~5 cost far more CPU
+	// than they save on JSON.
+	brotliLevel = 4
+)
+
+var (
+	gzipWriters = sync.Pool{New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return w
+	}}
+	brotliWriters = sync.Pool{New: func() interface{} {
+		return brotli.NewWriterLevel(io.Discard, brotliLevel)
+	}}
+)
+
+// Compress negotiates brotli or gzip with the client and compresses textual
+// responses of at least minSize bytes. Smaller responses, binary content
+// and responses that already carry a Content-Encoding are passed through.
+func Compress(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		c.Header("Vary", "Accept-Encoding")
+		cw := &compressWriter{ResponseWriter: c.Writer, encoding: encoding, minSize: minSize}
+		c.Writer = cw
+		defer func() {
+			cw.finish()
+			c.Writer = cw.ResponseWriter
+
+			if cw.encoder != nil && cw.original > 0 {
+				route := c.FullPath()
+				if route == "" {
+					route = "unmatched"
+				}
+				metrics.HTTPCompressionRatio.
+					WithLabelValues(route, encoding).
+					Observe(float64(cw.compressed.n) / float64(cw.original))
+				metrics.HTTPCompressedBytesTotal.WithLabelValues(encoding, "original").Add(float64(cw.original))
+				metrics.HTTPCompressedBytesTotal.WithLabelValues(encoding, "compressed").Add(float64(cw.compressed.n))
+			}
+		}()
+
+		c.Next()
+	}
+}
+
+// negotiateEncoding picks the supported coding with the highest q-value,
+// preferring brotli on ties. It returns "" when the client accepts neither.
+func negotiateEncoding(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		switch name {
+		case encodingBrotli, encodingGzip:
+		case "*":
+			name = encodingBrotli
+		default:
+			continue
+		}
+		if q > bestQ || (q == bestQ && name == encodingBrotli) {
+			best, bestQ = name, q
+		}
+	}
+	if bestQ <= 0 {
+		return ""
+	}
+	return best
+}
+
+func compressible(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.TrimSpace(strings.ToLower(mediaType))
+	switch {
+	case strings.HasPrefix(mediaType, "text/"):
+		return true
+	case mediaType == "application/json", mediaType == "application/xml",
+		mediaType == "application/javascript", strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	return false
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// compressWriter buffers the first minSize bytes of a response to decide
+// whether compression is worth it, then streams through the encoder.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	minSize  int
+
+	buf        bytes.Buffer
+	decided    bool
+	encoder    io.WriteCloser
+	compressed countingWriter
+	original   int64
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if w.decided {
+		return w.write(p)
+	}
+	w.buf.Write(p)
+	if w.buf.Len() >= w.minSize {
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush decides early so streamed responses are not held back.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.decide()
+	}
+	if f, ok := w.encoder.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressWriter) decide() error {
+	w.decided = true
+
+	header := w.ResponseWriter.Header()
+	status := w.ResponseWriter.Status()
+	if w.buf.Len() >= w.minSize && header.Get("Content-Encoding") == "" &&
+		status != http.StatusNoContent && status != http.StatusNotModified &&
+		compressible(header.Get("Content-Type")) {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		w.compressed.w = w.ResponseWriter
+		switch w.encoding {
+		case encodingBrotli:
+			bw := brotliWriters.Get().(*brotli.Writer)
+			bw.Reset(&w.compressed)
+			w.encoder = bw
+		default:
+			gw := gzipWriters.Get().(*gzip.Writer)
+			gw.Reset(&w.compressed)
+			w.encoder = gw
+		}
+	}
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := w.write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+func (w *compressWriter) write(p []byte) (int, error) {
+	if w.encoder == nil {
+		return w.ResponseWriter.Write(p)
+	}
+	w.original += int64(len(p))
+	return w.encoder.Write(p)
+}
+
+func (w *compressWriter) finish() {
+	if !w.decided {
+		_ = w.decide()
+	}
+	if w.encoder == nil {
+		return
+	}
+	_ = w.encoder.Close()
+	switch enc := w.encoder.(type) {
+	case *brotli.Writer:
+		brotliWriters.Put(enc)
+	case *gzip.Writer:
+		gzipWriters.Put(enc)
+	}
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var largeJSON = `{"data":"` + strings.Repeat("product ", 500) + `"}`
+
+func compressRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Compress(1024))
+	router.GET("/large", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(largeJSON))
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	router.GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", []byte(largeJSON))
+	})
+	return router
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	cases := map[string]string{
+		"":                             "",
+		"identity":                     "",
+		"gzip":                         "gzip",
+		"gzip, deflate, br":            "br",
+		"br;q=0.5, gzip":               "gzip",
+		"gzip;q=0, br;q=0":             "",
+		"*":                            "br",
+		"deflate, gzip;q=0.8, *;q=0.1": "gzip",
+	}
+	for header, want := range cases {
+		assert.Equal(t, want, negotiateEncoding(header), header)
+	}
+}
+
+func TestCompress_Gzip(t *testing.T) {
+	req := httptest.NewRequest("GET", "/large", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	compressRouter().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+	assert.Less(t, rec.Body.Len(), len(largeJSON))
+
+	zr, err := gzip.NewReader(rec.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, largeJSON, string(body))
+}
+
+func TestCompress_Brotli(t *testing.T) {
+	req := httptest.NewRequest("GET", "/large", nil)
+	req.Header.Set("Accept-Encoding", "gzip, br")
+	rec := httptest.NewRecorder()
+	compressRouter().ServeHTTP(rec, req)
+
+	assert.Equal(t, "br", rec.Header().Get("Content-Encoding"))
+	body, err := io.ReadAll(brotli.NewReader(rec.Body))
+	require.NoError(t, err)
+	assert.Equal(t, largeJSON, string(body))
+}
+
+func TestCompress_SkipsSmallResponses(t *testing.T) {
+	req := httptest.NewRequest("GET", "/small", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	compressRouter().ServeHTTP(rec, req)
+
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.JSONEq(t, `{"ok":true}`, rec.Body.String())
+}
+
+func TestCompress_SkipsBinaryContent(t *testing.T) {
+	req := httptest.NewRequest("GET", "/image", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	compressRouter().ServeHTTP(rec, req)
+
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, largeJSON, rec.Body.String())
+}
+
+func TestCompress_NoAcceptEncoding(t *testing.T) {
+	req := httptest.NewRequest("GET", "/large", nil)
+	rec := httptest.NewRecorder()
+	compressRouter().ServeHTTP(rec, req)
+
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, largeJSON, rec.Body.String())
+}
@@ -1,8 +1,11 @@
 package middleware
 
 import (
+	"context"
+	"crypto/rsa"
 	"fmt"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 
@@ -14,10 +17,103 @@ import (
 type Claims struct {
 	UserID int    `json:"user_id"`
 	Role   string `json:"role"`
+	Email  string `json:"email,omitempty"`
+	// Scope is the space-delimited list of scopes granted to the token
+	Scope string `json:"scope,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func JWTAuth(jwtSecret string) gin.HandlerFunc {
+// KeySource returns the RS256 public key an access token names in its kid
+// header, e.g. from the key set the Auth service publishes
+type KeySource interface {
+	Key(ctx context.Context, kid string) (*rsa.PublicKey, error)
+}
+
+// PublicKeys are the Auth service's RS256 public keys by kid, configured
+// instead of fetched from its key set
+type PublicKeys map[string]*rsa.PublicKey
+
+// LoadPublicKeys reads the PEM encoded public key of each kid from its file
+func LoadPublicKeys(files map[string]string) (PublicKeys, error) {
+	keys := make(PublicKeys, len(files))
+	for kid, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("read public key %q: %w", kid, err)
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("parse public key %q: %w", kid, err)
+		}
+		keys[kid] = key
+	}
+	return keys, nil
+}
+
+func (k PublicKeys) Key(_ context.Context, kid string) (*rsa.PublicKey, error) {
+	key, ok := k[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// TokenKeys verifies access token signatures: HS256 with the shared
+// secret, RS256 with the Auth service's published keys, or either while
+// moving from one to the other
+type TokenKeys struct {
+	secret  []byte
+	keys    KeySource
+	methods []string
+}
+
+// NewTokenKeys accepts HS256 tokens when secret is set and RS256 tokens
+// when keys is not nil
+func NewTokenKeys(secret string, keys KeySource) *TokenKeys {
+	tk := &TokenKeys{secret: []byte(secret), keys: keys}
+	if secret != "" {
+		tk.methods = append(tk.methods, jwt.SigningMethodHS256.Alg())
+	}
+	if keys != nil {
+		tk.methods = append(tk.methods, jwt.SigningMethodRS256.Alg())
+	}
+	return tk
+}
+
+func (tk *TokenKeys) keyFunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		if token.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+			return tk.secret, nil
+		}
+		kid, _ := token.Header["kid"].(string)
+		return tk.keys.Key(ctx, kid)
+	}
+}
+
+// parseToken verifies an access token and, when audience is set, that it
+// was issued for it
+func parseToken(ctx context.Context, tokenString string, keys *TokenKeys, audience string, claims *Claims) (*jwt.Token, error) {
+	opts := []jwt.ParserOption{jwt.WithValidMethods(keys.methods)}
+	if audience != "" {
+		opts = append(opts, jwt.WithAudience(audience))
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, keys.keyFunc(ctx), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Standard clients only know sub; it carries the same ID as user_id
+	if claims.UserID == 0 && claims.Subject != "" {
+		if uid, err := strconv.Atoi(claims.Subject); err == nil {
+			claims.UserID = uid
+		}
+	}
+
+	return token, nil
+}
+
+func JWTAuth(keys *TokenKeys, audience string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var tokenString string
 
@@ -44,10 +140,7 @@ func JWTAuth(jwtSecret string) gin.HandlerFunc {
 
 		claims := &Claims{}
 
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(jwtSecret), nil
-		})
-
+		token, err := parseToken(c.Request.Context(), tokenString, keys, audience, claims)
 		if err != nil || !token.Valid {
 			logger.GetLogger().WithField("err", err).Warn("invalid or expired token")
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
@@ -55,9 +148,16 @@ func JWTAuth(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
+		c.Set("access_token", tokenString)
+		c.Set("token_id", claims.ID)
+		c.Set("scopes", strings.Fields(claims.Scope))
+
 		if claims.UserID != 0 {
 			c.Set("user_id", claims.UserID)
 			c.Set("role", claims.Role)
+			if claims.Email != "" {
+				c.Set("email", claims.Email)
+			}
 			c.Next()
 			return
 		}
@@ -83,7 +183,7 @@ func JWTAuth(jwtSecret string) gin.HandlerFunc {
 	}
 }
 
-func JWTAuthOptional(jwtSecret string) gin.HandlerFunc {
+func JWTAuthOptional(keys *TokenKeys, audience string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var tokenString string
 
@@ -109,10 +209,7 @@ func JWTAuthOptional(jwtSecret string) gin.HandlerFunc {
 
 		claims := &Claims{}
 
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(jwtSecret), nil
-		})
-
+		token, err := parseToken(c.Request.Context(), tokenString, keys, audience, claims)
 		if err == nil && token.Valid {
 			if claims.UserID != 0 {
 				c.Set("user_id", claims.UserID)
@@ -1,7 +1,14 @@
 package middleware
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -63,7 +70,7 @@ func TestJWTAuth_SetsContextFromStructClaims(t *testing.T) {
 	c.Request = req
 
 	// run middleware
-	h := JWTAuth(testSecret)
+	h := JWTAuth(NewTokenKeys(testSecret, nil), "")
 	h(c)
 
 	// check context
@@ -84,6 +91,38 @@ func TestJWTAuth_SetsContextFromStructClaims(t *testing.T) {
 	}
 }
 
+// Test middleware exposes the email claim issued by the auth service
+func TestJWTAuth_SetsEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+
+	mc := jwt.MapClaims{}
+	mc["user_id"] = float64(13)
+	mc["role"] = "user"
+	mc["email"] = "buyer@example.com"
+	mc["exp"] = time.Now().Add(time.Hour).Unix()
+	tok := jwt.NewWithClaims(jwt.SigningMethodHS256, mc)
+	signed, err := tok.SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer "+signed)
+	c.Request = req
+
+	JWTAuth(NewTokenKeys(testSecret, nil), "")(c)
+
+	email, exists := c.Get("email")
+	if !exists {
+		t.Fatalf("email not set in context")
+	}
+	if email.(string) != "buyer@example.com" {
+		t.Fatalf("unexpected email %v", email)
+	}
+}
+
 // Test middleware supports MapClaims numeric types (float64)
 func TestJWTAuth_SetsContextFromMapClaimsFloat(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -104,7 +143,7 @@ func TestJWTAuth_SetsContextFromMapClaimsFloat(t *testing.T) {
 	req.Header.Set("Authorization", "Bearer "+signed)
 	c.Request = req
 
-	h := JWTAuth(testSecret)
+	h := JWTAuth(NewTokenKeys(testSecret, nil), "")
 	h(c)
 
 	uid, exists := c.Get("user_id")
@@ -181,7 +220,7 @@ func TestJWTAuth_MissingToken(t *testing.T) {
 	c, _ := gin.CreateTestContext(recorder)
 	c.Request = httptest.NewRequest("GET", "/", nil)
 
-	h := JWTAuth(testSecret)
+	h := JWTAuth(NewTokenKeys(testSecret, nil), "")
 	h(c)
 
 	if !c.IsAborted() {
@@ -212,7 +251,7 @@ func TestJWTAuth_ExpiredToken(t *testing.T) {
 	req.Header.Set("Authorization", "Bearer "+signed)
 	c.Request = req
 
-	h := JWTAuth(testSecret)
+	h := JWTAuth(NewTokenKeys(testSecret, nil), "")
 	h(c)
 
 	if !c.IsAborted() {
@@ -242,7 +281,7 @@ func TestJWTAuth_InvalidSignature(t *testing.T) {
 	req.Header.Set("Authorization", "Bearer "+signed)
 	c.Request = req
 
-	h := JWTAuth(testSecret)
+	h := JWTAuth(NewTokenKeys(testSecret, nil), "")
 	h(c)
 
 	if !c.IsAborted() {
@@ -252,3 +291,137 @@ func TestJWTAuth_InvalidSignature(t *testing.T) {
 		t.Fatalf("expected 401 status, got %d", recorder.Code)
 	}
 }
+
+// Test JWTAuth accepts a token for its audience and reads the user from sub
+func TestJWTAuth_AudienceAndSubject(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	sign := func(aud string) string {
+		tok := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+			Role: "user",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "42",
+				Audience:  jwt.ClaimStrings{aud},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		})
+		signed, _ := tok.SignedString([]byte(testSecret))
+		return signed
+	}
+
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest("GET", "/", nil)
+	c.Request.Header.Set("Authorization", "Bearer "+sign("marketback"))
+
+	JWTAuth(NewTokenKeys(testSecret, nil), "marketback")(c)
+
+	if c.IsAborted() {
+		t.Fatalf("expected token for the configured audience to be accepted")
+	}
+	if uid, _ := c.Get("user_id"); uid != 42 {
+		t.Fatalf("expected user_id 42 from sub, got %v", uid)
+	}
+
+	recorder = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest("GET", "/", nil)
+	c.Request.Header.Set("Authorization", "Bearer "+sign("other-service"))
+
+	JWTAuth(NewTokenKeys(testSecret, nil), "marketback")(c)
+
+	if !c.IsAborted() || recorder.Code != 401 {
+		t.Fatalf("expected token for another audience to be rejected, got %d", recorder.Code)
+	}
+}
+
+// Test JWTAuth verifies RS256 tokens with the key named by kid, and HS256
+// ones only while the secret is configured
+func TestJWTAuth_SigningKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := Claims{UserID: 7, Role: "user", RegisteredClaims: jwt.RegisteredClaims{
+		ID:        "3f2a9c",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}}
+	rs := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	rs.Header["kid"] = "2026-10"
+	rsSigned, _ := rs.SignedString(key)
+	unknown := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	unknown.Header["kid"] = "2026-04"
+	unknownSigned, _ := unknown.SignedString(key)
+	hsSigned, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	keys := PublicKeys{"2026-10": &key.PublicKey}
+
+	tests := []struct {
+		name   string
+		keys   *TokenKeys
+		token  string
+		wantOK bool
+	}{
+		{"rs256", NewTokenKeys("", keys), rsSigned, true},
+		{"unknown kid", NewTokenKeys("", keys), unknownSigned, false},
+		{"hs256 without secret", NewTokenKeys("", keys), hsSigned, false},
+		{"hs256 while moving over", NewTokenKeys(testSecret, keys), hsSigned, true},
+		{"rs256 without keys", NewTokenKeys(testSecret, nil), rsSigned, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request = httptest.NewRequest("GET", "/", nil)
+			c.Request.Header.Set("Authorization", "Bearer "+tt.token)
+
+			JWTAuth(tt.keys, "")(c)
+
+			if c.IsAborted() == tt.wantOK {
+				t.Fatalf("accepted = %v, want %v (status %d)", !c.IsAborted(), tt.wantOK, recorder.Code)
+			}
+			if tt.wantOK {
+				if uid, _ := c.Get("user_id"); uid != 7 {
+					t.Fatalf("expected user_id 7, got %v", uid)
+				}
+				if jti := c.GetString("token_id"); jti != "3f2a9c" {
+					t.Fatalf("expected token_id 3f2a9c, got %q", jti)
+				}
+			}
+		})
+	}
+}
+
+// Test LoadPublicKeys reads PEM public keys by kid
+func TestLoadPublicKeys(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	file := filepath.Join(dir, "2026-10.pub.pem")
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := LoadPublicKeys(map[string]string{"2026-10": file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := keys.Key(context.Background(), "2026-10")
+	if err != nil || !got.Equal(&key.PublicKey) {
+		t.Fatalf("expected the loaded key, got %v (%v)", got, err)
+	}
+	if _, err := keys.Key(context.Background(), "2026-04"); err == nil {
+		t.Fatal("expected an error for an unknown kid")
+	}
+
+	if _, err := LoadPublicKeys(map[string]string{"2026-10": filepath.Join(dir, "missing.pem")}); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_UsesRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Metrics())
+	router.GET("/api/orders/:id", func(c *gin.Context) {
+		if c.Param("id") == "404" {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	for _, id := range []string{"1", "2", "404"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/orders/"+id, nil))
+	}
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/nope/123", nil))
+
+	// one series per route template and status class, regardless of IDs
+	assert.Equal(t, 3, testutil.CollectAndCount(metrics.HTTPRequestDuration))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.HTTPRequestErrorsTotal.WithLabelValues("GET", "/api/orders/:id", "404")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.HTTPRequestErrorsTotal.WithLabelValues("GET", "unmatched", "404")))
+}
+
+func TestStatusClass(t *testing.T) {
+	assert.Equal(t, "2xx", statusClass(201))
+	assert.Equal(t, "3xx", statusClass(304))
+	assert.Equal(t, "4xx", statusClass(429))
+	assert.Equal(t, "5xx", statusClass(503))
+}
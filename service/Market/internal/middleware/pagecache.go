@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/pagecache"
+	"github.com/gin-gonic/gin"
+)
+
+// CachePage serves GET responses of public catalog pages from the page
+// cache and caches successful ones, per storefront and, after
+// VisitorBucket, per visitor bucket. X-Cache tells whether a response came
+// from the cache. With a nil cache it does nothing.
+func CachePage(cache *pagecache.Cache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cache == nil || c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		key := pagecache.Key(c.GetInt("storefront_seller_id"), c.Request.URL.Path, c.Request.URL.RawQuery)
+		if bucket, ok := c.Get("visitor_bucket"); ok {
+			key = pagecache.BucketKey(key, bucket.(int))
+		}
+		if !pagecache.Refreshing(ctx) {
+			if body, ok := cache.Get(ctx, key); ok {
+				c.Header("X-Cache", "HIT")
+				c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+				c.Abort()
+				return
+			}
+		}
+
+		c.Header("X-Cache", "MISS")
+		w := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if c.Writer.Status() != http.StatusOK || w.body.Len() == 0 {
+			return
+		}
+		if err := cache.Set(ctx, key, w.body.Bytes()); err != nil {
+			logger.FromContext(ctx).WithField("err", err).Warn("failed to cache page")
+		}
+	}
+}
+
+// recordingWriter keeps a copy of the response body.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	w.body.Write(p)
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
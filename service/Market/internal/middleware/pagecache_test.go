@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/pagecache"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type pageStore map[string][]byte
+
+func (s pageStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	s[key] = data
+	return err
+}
+
+func (s pageStore) Get(ctx context.Context, key string, dest interface{}) error {
+	data, ok := s[key]
+	if !ok {
+		return errors.New("redis: nil")
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func (s pageStore) Exists(ctx context.Context, key string) (bool, error) {
+	_, ok := s[key]
+	return ok, nil
+}
+
+func TestCachePage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := pageStore{}
+	rendered := 0
+	router := gin.New()
+	router.GET("/api/products/:id", func(c *gin.Context) {
+		if host := c.Request.Host; host == "shop.example.com" {
+			c.Set("storefront_seller_id", 7)
+		}
+	}, VisitorBucket(), CachePage(pagecache.New(store, time.Minute)), func(c *gin.Context) {
+		rendered++
+		if c.Param("id") == "404" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id"), "render": rendered})
+	})
+
+	get := func(ctx context.Context, host, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil).WithContext(ctx)
+		req.Host = host
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	ctx := context.Background()
+
+	w := get(ctx, "market.example.com", "/api/products/1")
+	require.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	require.JSONEq(t, `{"id":"1","render":1}`, w.Body.String())
+
+	w = get(ctx, "market.example.com", "/api/products/1")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "HIT", w.Header().Get("X-Cache"))
+	require.JSONEq(t, `{"id":"1","render":1}`, w.Body.String())
+	require.Equal(t, 1, rendered)
+
+	// Seller storefronts have pages of their own.
+	w = get(ctx, "shop.example.com", "/api/products/1")
+	require.Equal(t, "MISS", w.Header().Get("X-Cache"))
+
+	// Visitor buckets have pages of their own.
+	w = get(pagecache.WithBucket(ctx, 3), "market.example.com", "/api/products/2")
+	require.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	w = get(pagecache.WithBucket(ctx, 3), "market.example.com", "/api/products/2")
+	require.Equal(t, "HIT", w.Header().Get("X-Cache"))
+	w = get(pagecache.WithBucket(ctx, 4), "market.example.com", "/api/products/2")
+	require.Equal(t, "MISS", w.Header().Get("X-Cache"))
+
+	// Refreshing re-renders the page and replaces the cached copy.
+	w = get(pagecache.WithRefresh(ctx), "market.example.com", "/api/products/1")
+	require.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	w = get(ctx, "market.example.com", "/api/products/1")
+	require.JSONEq(t, `{"id":"1","render":5}`, w.Body.String())
+
+	// Errors are not cached.
+	get(ctx, "market.example.com", "/api/products/404")
+	w = get(ctx, "market.example.com", "/api/products/404")
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	require.Len(t, store, 4)
+}
+
+func TestCachePage_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/categories", CachePage(nil), func(c *gin.Context) {
+		c.JSON(http.StatusOK, []string{})
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/categories", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Empty(t, w.Header().Get("X-Cache"))
+}
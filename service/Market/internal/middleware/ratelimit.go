@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"sync"
@@ -14,8 +15,6 @@ import (
 type inMemoryLimiter struct {
 	mu       sync.RWMutex
 	counters map[string]*rateLimitEntry
-	limit    int
-	window   time.Duration
 }
 
 type rateLimitEntry struct {
@@ -23,11 +22,9 @@ type rateLimitEntry struct {
 	expiresAt time.Time
 }
 
-func newInMemoryLimiter(limit int, window time.Duration) *inMemoryLimiter {
+func newInMemoryLimiter() *inMemoryLimiter {
 	limiter := &inMemoryLimiter{
 		counters: make(map[string]*rateLimitEntry),
-		limit:    limit,
-		window:   window,
 	}
 	go limiter.cleanup()
 	return limiter
@@ -47,7 +44,7 @@ func (l *inMemoryLimiter) cleanup() {
 	}
 }
 
-func (l *inMemoryLimiter) increment(key string) (int, bool) {
+func (l *inMemoryLimiter) increment(key string, limit int, window time.Duration) (int, bool) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -57,49 +54,78 @@ func (l *inMemoryLimiter) increment(key string) (int, bool) {
 	if !exists || now.After(entry.expiresAt) {
 		l.counters[key] = &rateLimitEntry{
 			count:     1,
-			expiresAt: now.Add(l.window),
+			expiresAt: now.Add(window),
 		}
 		return 1, true
 	}
 
 	entry.count++
-	return entry.count, entry.count <= l.limit
+	return entry.count, entry.count <= limit
+}
+
+// RateLimitPolicy holds the rate limit settings. Update takes effect for the
+// following requests, so limits can be changed on a config reload.
+type RateLimitPolicy struct {
+	mu      sync.RWMutex
+	enabled bool
+	limit   int
+	window  time.Duration
+}
+
+func NewRateLimitPolicy(enabled bool, limit int, window time.Duration) *RateLimitPolicy {
+	return &RateLimitPolicy{enabled: enabled, limit: limit, window: window}
+}
+
+func (p *RateLimitPolicy) Update(enabled bool, limit int, window time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.enabled, p.limit, p.window = enabled, limit, window
+}
+
+func (p *RateLimitPolicy) settings() (bool, int, time.Duration) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.enabled, p.limit, p.window
+}
+
+// countRequest counts a request against key's limit per window in Redis
+// or, when Redis is nil or fails, in memory.
+func countRequest(ctx context.Context, redis *cache.RedisCache, mem *inMemoryLimiter, key string, limit int, window time.Duration) (int64, bool) {
+	if redis != nil {
+		count, err := redis.Increment(ctx, key)
+		if err == nil {
+			if count == 1 {
+				_ = redis.Expire(ctx, key, window)
+			}
+			return count, count <= int64(limit)
+		}
+		logger.GetLogger().WithField("err", err).Warn("Redis rate limit failed, using in-memory fallback")
+	}
+	count, ok := mem.increment(key, limit, window)
+	return int64(count), ok
 }
 
 func RateLimiter(redis *cache.RedisCache, limit int, window time.Duration) gin.HandlerFunc {
-	memLimiter := newInMemoryLimiter(limit, window)
+	return RateLimiterWithPolicy(redis, NewRateLimitPolicy(true, limit, window))
+}
+
+func RateLimiterWithPolicy(redis *cache.RedisCache, policy *RateLimitPolicy) gin.HandlerFunc {
+	memLimiter := newInMemoryLimiter()
 
 	return func(c *gin.Context) {
+		enabled, limit, window := policy.settings()
+		if !enabled {
+			c.Next()
+			return
+		}
+
 		clientID := c.ClientIP()
 		if userID, exists := c.Get("user_id"); exists {
 			clientID = fmt.Sprintf("user:%v", userID)
 		}
 
 		key := fmt.Sprintf("ratelimit:%s", clientID)
-		ctx := c.Request.Context()
-
-		var count int64
-		var allowed bool
-
-		if redis != nil {
-			redisCount, err := redis.Increment(ctx, key)
-			if err != nil {
-				logger.GetLogger().WithField("err", err).Warn("Redis rate limit failed, using in-memory fallback")
-				memCount, ok := memLimiter.increment(key)
-				count = int64(memCount)
-				allowed = ok
-			} else {
-				count = redisCount
-				if count == 1 {
-					_ = redis.Expire(ctx, key, window)
-				}
-				allowed = count <= int64(limit)
-			}
-		} else {
-			memCount, ok := memLimiter.increment(key)
-			count = int64(memCount)
-			allowed = ok
-		}
+		count, allowed := countRequest(c.Request.Context(), redis, memLimiter, key, limit, window)
 
 		if !allowed {
 			logger.GetLogger().WithFields(map[string]interface{}{
@@ -154,3 +154,30 @@ func TestRateLimitMiddleware_ResetAfterWindow(t *testing.T) {
 	router.ServeHTTP(recorder, req)
 	assert.Equal(t, http.StatusOK, recorder.Code)
 }
+
+func TestRateLimiterWithPolicy_Update(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	policy := NewRateLimitPolicy(true, 1, time.Minute)
+	router := gin.New()
+	router.Use(RateLimiterWithPolicy(nil, policy))
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	do := func() int {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest("GET", "/test", nil))
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusOK, do())
+	assert.Equal(t, http.StatusTooManyRequests, do())
+
+	policy.Update(true, 5, time.Minute)
+	assert.Equal(t, http.StatusOK, do())
+
+	policy.Update(false, 1, time.Minute)
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, do())
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// RequestLogger attaches a request-scoped logger (request_id, method, route)
+// to the request context and writes one structured line per request with
+// status, latency, user_id and traffic_class.
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		c.Header(RequestIDHeader, requestID)
+		c.Set("request_id", requestID)
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		entry := logrus.NewEntry(logger.GetLogger()).WithFields(logrus.Fields{
+			"request_id": requestID,
+			"method":     c.Request.Method,
+			"route":      route,
+		})
+		c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context(), entry))
+
+		c.Next()
+
+		fields := logrus.Fields{
+			"status":     c.Writer.Status(),
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"client_ip":  c.ClientIP(),
+			"path":       c.Request.URL.Path,
+		}
+		if userID, ok := c.Get("user_id"); ok {
+			fields["user_id"] = userID
+		}
+		if class := c.GetString("traffic_class"); class != "" {
+			fields["traffic_class"] = class
+		}
+		if len(c.Errors) > 0 {
+			fields["errors"] = c.Errors.String()
+		}
+
+		entry = entry.WithFields(fields)
+		switch status := c.Writer.Status(); {
+		case status >= 500:
+			entry.Error("request completed")
+		case status >= 400:
+			entry.Warn("request completed")
+		default:
+			entry.Info("request completed")
+		}
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
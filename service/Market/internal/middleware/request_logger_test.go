@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLogger_Fields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	logger.InitLogger("info").SetOutput(&buf)
+	defer logger.InitLogger("info")
+
+	router := gin.New()
+	router.Use(RequestLogger())
+	router.GET("/api/products/:id", func(c *gin.Context) {
+		c.Set("user_id", 7)
+		logger.FromContext(c.Request.Context()).Info("inside handler")
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/api/products/42", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, "req-1", recorder.Header().Get(RequestIDHeader))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var inner map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &inner))
+	assert.Equal(t, "req-1", inner["request_id"])
+	assert.Equal(t, "/api/products/:id", inner["route"])
+
+	var access map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[1], &access))
+	assert.Equal(t, float64(200), access["status"])
+	assert.Equal(t, float64(7), access["user_id"])
+	assert.Equal(t, "/api/products/42", access["path"])
+	assert.Contains(t, access, "latency_ms")
+}
+
+func TestRequestLogger_GeneratesRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	logger.InitLogger("info").SetOutput(&buf)
+	defer logger.InitLogger("info")
+
+	router := gin.New()
+	router.Use(RequestLogger())
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/missing", nil))
+
+	assert.Len(t, recorder.Header().Get(RequestIDHeader), 16)
+	assert.Contains(t, buf.String(), `"route":"unmatched"`)
+	assert.Contains(t, buf.String(), `"level":"warning"`)
+}
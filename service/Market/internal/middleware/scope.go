@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Access token scopes issued by the Auth service
+const (
+	ScopeMarketRead  = "market:read"
+	ScopeMarketWrite = "market:write"
+	ScopeAdmin       = "admin"
+)
+
+// RequireScope aborts with 403 unless the token granted every scope. It must
+// run after JWTAuth.
+func RequireScope(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, scope := range scopes {
+			if !hasScope(c, scope) {
+				abortInsufficientScope(c, scope)
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// MarketScope requires market:read for safe methods and market:write for
+// everything else, so a read-only token cannot change orders or listings
+func MarketScope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope := ScopeMarketWrite
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			scope = ScopeMarketRead
+		}
+
+		if !hasScope(c, scope) {
+			abortInsufficientScope(c, scope)
+			return
+		}
+		c.Next()
+	}
+}
+
+func hasScope(c *gin.Context, scope string) bool {
+	granted, _ := c.Get("scopes")
+	scopes, _ := granted.([]string)
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func abortInsufficientScope(c *gin.Context, scope string) {
+	c.Header("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+scope+`"`)
+	c.JSON(http.StatusForbidden, gin.H{"error": "insufficient scope", "required_scope": scope})
+	c.Abort()
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMarketScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name    string
+		method  string
+		scopes  []string
+		aborted bool
+	}{
+		{"read with read scope", "GET", []string{ScopeMarketRead}, false},
+		{"write with read scope", "POST", []string{ScopeMarketRead}, true},
+		{"write with write scope", "PUT", []string{ScopeMarketWrite}, false},
+		{"token without scopes", "GET", nil, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request = httptest.NewRequest(tc.method, "/", nil)
+			c.Set("scopes", tc.scopes)
+
+			MarketScope()(c)
+
+			if c.IsAborted() != tc.aborted {
+				t.Fatalf("expected aborted=%v", tc.aborted)
+			}
+			if tc.aborted && recorder.Code != 403 {
+				t.Fatalf("expected 403, got %d", recorder.Code)
+			}
+		})
+	}
+}
+
+func TestRequireScope_Admin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest("GET", "/", nil)
+	c.Set("scopes", []string{ScopeMarketRead, ScopeMarketWrite})
+
+	RequireScope(ScopeAdmin)(c)
+
+	if !c.IsAborted() || recorder.Code != 403 {
+		t.Fatalf("expected admin routes to require the admin scope, got %d", recorder.Code)
+	}
+	if recorder.Header().Get("WWW-Authenticate") == "" {
+		t.Fatalf("expected insufficient_scope challenge")
+	}
+}
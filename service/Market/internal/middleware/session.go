@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// SessionChecker reports whether an access token is still active.
+// *session.Checker implements it.
+type SessionChecker interface {
+	Active(ctx context.Context, token string) (bool, error)
+}
+
+// RevokedTokens reports whether an access token was revoked by its ID (jti).
+// *session.Denylist implements it.
+type RevokedTokens interface {
+	Revoked(ctx context.Context, jti string) (bool, error)
+}
+
+// NotRevoked rejects access tokens on the Auth service's denylist, e.g.
+// after a logout. It must run after JWTAuth. When the denylist cannot be
+// read the request is let through on the strength of the token signature,
+// as ActiveSession does.
+func NotRevoked(tokens RevokedTokens) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		revoked, err := tokens.Revoked(c.Request.Context(), c.GetString("token_id"))
+		if err != nil {
+			logger.GetLogger().WithField("err", err).Warn("access token denylist unavailable, trusting token signature")
+			c.Next()
+			return
+		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "token has been revoked"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// ActiveSession rejects access tokens the Auth service has revoked, e.g. by
+// a logout or an admin ending a user's sessions. It must run after JWTAuth.
+// When the Auth service cannot answer the request is let through on the
+// strength of the token signature, so an Auth outage does not take Market
+// down with it.
+func ActiveSession(checker SessionChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetString("access_token")
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		active, err := checker.Active(c.Request.Context(), token)
+		if err != nil {
+			logger.GetLogger().WithField("err", err).Warn("session check failed, trusting token signature")
+			c.Next()
+			return
+		}
+		if !active {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "session has been revoked"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
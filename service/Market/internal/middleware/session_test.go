@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeSessionChecker struct {
+	active bool
+	err    error
+}
+
+func (f fakeSessionChecker) Active(ctx context.Context, token string) (bool, error) {
+	return f.active, f.err
+}
+
+func TestActiveSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name    string
+		token   string
+		checker fakeSessionChecker
+		aborted bool
+	}{
+		{"active session", "t", fakeSessionChecker{active: true}, false},
+		{"revoked session", "t", fakeSessionChecker{active: false}, true},
+		{"auth unavailable", "t", fakeSessionChecker{err: errors.New("timeout")}, false},
+		{"no token", "", fakeSessionChecker{active: false}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request = httptest.NewRequest("GET", "/", nil)
+			if tc.token != "" {
+				c.Set("access_token", tc.token)
+			}
+
+			ActiveSession(tc.checker)(c)
+
+			if c.IsAborted() != tc.aborted {
+				t.Fatalf("expected aborted=%v", tc.aborted)
+			}
+			if tc.aborted && recorder.Code != 401 {
+				t.Fatalf("expected 401, got %d", recorder.Code)
+			}
+		})
+	}
+}
+
+type fakeRevokedTokens struct {
+	revoked map[string]bool
+	err     error
+}
+
+func (f fakeRevokedTokens) Revoked(ctx context.Context, jti string) (bool, error) {
+	return f.revoked[jti], f.err
+}
+
+func TestNotRevoked(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tokens := map[string]bool{"logged-out": true}
+
+	cases := []struct {
+		name    string
+		jti     string
+		denied  fakeRevokedTokens
+		aborted bool
+	}{
+		{"live token", "live", fakeRevokedTokens{revoked: tokens}, false},
+		{"revoked token", "logged-out", fakeRevokedTokens{revoked: tokens}, true},
+		{"denylist unavailable", "logged-out", fakeRevokedTokens{err: errors.New("timeout")}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request = httptest.NewRequest("GET", "/", nil)
+			c.Set("token_id", tc.jti)
+
+			NotRevoked(tc.denied)(c)
+
+			if c.IsAborted() != tc.aborted {
+				t.Fatalf("expected aborted=%v", tc.aborted)
+			}
+			if tc.aborted && recorder.Code != 401 {
+				t.Fatalf("expected 401, got %d", recorder.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/sellerdomain"
+	"github.com/gin-gonic/gin"
+)
+
+// SellerStorefront resolves requests made on a seller's custom domain and
+// sets storefront_seller_id, which scopes public listings to the seller's
+// catalog. Lookup errors never fail the request; it is served as on the
+// marketplace's own host.
+func SellerStorefront(resolver *sellerdomain.Resolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sellerID, err := resolver.Resolve(c.Request.Context(), c.Request.Host)
+		if err != nil {
+			logger.FromContext(c.Request.Context()).WithField("err", err).Warn("failed to resolve seller domain")
+		}
+		if sellerID > 0 {
+			c.Set("storefront_seller_id", sellerID)
+		}
+		c.Next()
+	}
+}
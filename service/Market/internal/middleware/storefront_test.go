@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/sellerdomain"
+	"github.com/gin-gonic/gin"
+)
+
+type domainSource map[string]int
+
+func (s domainSource) GetIDByDomain(ctx context.Context, domain string) (int, error) {
+	if domain == "broken.example.com" {
+		return 0, errors.New("db down")
+	}
+	return s[domain], nil
+}
+
+func TestSellerStorefront(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	resolver := sellerdomain.NewResolver(domainSource{"shop.example.com": 7}, []string{"market.example.com"}, time.Minute)
+
+	cases := []struct {
+		host     string
+		sellerID int
+	}{
+		{"shop.example.com:8080", 7},
+		{"market.example.com", 0},
+		{"unknown.example.com", 0},
+		{"broken.example.com", 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.host, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request = httptest.NewRequest("GET", "/api/products", nil)
+			c.Request.Host = tc.host
+
+			SellerStorefront(resolver)(c)
+
+			if c.IsAborted() {
+				t.Fatal("expected request to continue")
+			}
+			if got := c.GetInt("storefront_seller_id"); got != tc.sellerID {
+				t.Fatalf("expected seller %d, got %d", tc.sellerID, got)
+			}
+		})
+	}
+}
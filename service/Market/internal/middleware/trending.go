@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/botdetect"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/productviews"
+	"github.com/Zifeldev/marketback/service/Market/internal/trending"
+	"github.com/gin-gonic/gin"
+)
+
+// TrackProductViews records a trending view, and a view for seller
+// analytics, for every successful product page response to a buyer;
+// crawler and scraper views are not counted. Tracking errors never fail
+// the request.
+func TrackProductViews(tracker *trending.Tracker, views *productviews.Counter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if (tracker == nil && views == nil) || c.Writer.Status() != http.StatusOK {
+			return
+		}
+		if class := c.GetString("traffic_class"); class != "" && class != botdetect.ClassHuman {
+			return
+		}
+		productID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return
+		}
+		views.Record(productID)
+		if tracker == nil {
+			return
+		}
+		if err := tracker.RecordView(c.Request.Context(), productID); err != nil {
+			logger.FromContext(c.Request.Context()).WithField("err", err).Warn("failed to record product view")
+		}
+	}
+}
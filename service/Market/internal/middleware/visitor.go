@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"hash/fnv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/pagecache"
+	"github.com/gin-gonic/gin"
+)
+
+// VisitorCookie and VisitorHeader carry the ID a storefront gives each
+// visitor; without one visitors are told apart by IP.
+const (
+	VisitorCookie = "visitor_id"
+	VisitorHeader = "X-Visitor-ID"
+)
+
+// VisitorBucket puts the visitor in one of models.TrafficBuckets buckets
+// and sets visitor_bucket, which decides whether listings show them the
+// products of sellers on probation. The same visitor always lands in the
+// same bucket. Requests on a seller's custom domain are not bucketed: the
+// seller's own storefront lists all of their products.
+func VisitorBucket() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetInt("storefront_seller_id") > 0 {
+			c.Next()
+			return
+		}
+
+		bucket, ok := pagecache.Bucket(c.Request.Context())
+		if !ok {
+			id := c.GetHeader(VisitorHeader)
+			if id == "" {
+				id, _ = c.Cookie(VisitorCookie)
+			}
+			if id == "" {
+				id = c.ClientIP()
+			}
+			h := fnv.New32a()
+			h.Write([]byte(id))
+			bucket = int(h.Sum32() % models.TrafficBuckets)
+		}
+		c.Set("visitor_bucket", bucket)
+		c.Next()
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/pagecache"
+	"github.com/gin-gonic/gin"
+)
+
+func visitorBucket(t *testing.T, build func(c *gin.Context)) (int, bool) {
+	t.Helper()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/api/products", nil)
+	build(c)
+
+	VisitorBucket()(c)
+
+	if c.IsAborted() {
+		t.Fatal("expected request to continue")
+	}
+	bucket, ok := c.Get("visitor_bucket")
+	if !ok {
+		return 0, false
+	}
+	return bucket.(int), true
+}
+
+func TestVisitorBucket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	seen := map[int]bool{}
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		byHeader, ok := visitorBucket(t, func(c *gin.Context) { c.Request.Header.Set(VisitorHeader, id) })
+		if !ok || byHeader < 0 || byHeader >= 10 {
+			t.Fatalf("expected a bucket between 0 and 9, got %d", byHeader)
+		}
+		byCookie, _ := visitorBucket(t, func(c *gin.Context) {
+			c.Request.AddCookie(&http.Cookie{Name: VisitorCookie, Value: id})
+		})
+		if byCookie != byHeader {
+			t.Fatalf("visitor %s: header bucket %d, cookie bucket %d", id, byHeader, byCookie)
+		}
+		seen[byHeader] = true
+	}
+	if len(seen) < 2 {
+		t.Fatal("expected visitors to be spread over buckets")
+	}
+
+	byIP, _ := visitorBucket(t, func(c *gin.Context) { c.Request.RemoteAddr = "203.0.113.9:4000" })
+	again, _ := visitorBucket(t, func(c *gin.Context) { c.Request.RemoteAddr = "203.0.113.9:5000" })
+	if byIP != again {
+		t.Fatalf("expected the same bucket for the same IP, got %d and %d", byIP, again)
+	}
+
+	warmed, _ := visitorBucket(t, func(c *gin.Context) {
+		c.Request = c.Request.WithContext(pagecache.WithBucket(c.Request.Context(), 7))
+		c.Request.Header.Set(VisitorHeader, "a")
+	})
+	if warmed != 7 {
+		t.Fatalf("expected the warm-up bucket 7, got %d", warmed)
+	}
+
+	if _, ok := visitorBucket(t, func(c *gin.Context) { c.Set("storefront_seller_id", 4) }); ok {
+		t.Fatal("expected storefront requests not to be bucketed")
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Back office lists an admin can save views for.
+const (
+	AdminViewOrders   = "orders"
+	AdminViewProducts = "products"
+	AdminViewUsers    = "users"
+)
+
+// AdminView is a named filter preset an admin saved for a back office
+// list. Query is the list's query string, e.g. "status=paid&min_amount=100".
+type AdminView struct {
+	ID        int       `json:"id" db:"id"`
+	AdminID   int       `json:"admin_id" db:"admin_id"`
+	List      string    `json:"list" db:"list"`
+	Name      string    `json:"name" db:"name"`
+	Query     string    `json:"query" db:"query"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type SaveAdminViewRequest struct {
+	List  string `json:"list" binding:"required,oneof=orders products users"`
+	Name  string `json:"name" binding:"required,max=100"`
+	Query string `json:"query" binding:"max=2000"`
+}
@@ -0,0 +1,28 @@
+package models
+
+// APIUsage is a seller's API traffic on one day (UTC).
+type APIUsage struct {
+	Day       string `json:"day" db:"day"`
+	Requests  int64  `json:"requests" db:"requests"`
+	Errors    int64  `json:"errors" db:"errors"`
+	Throttled int64  `json:"throttled" db:"throttled"`
+}
+
+// APIUsageParams selects how many days of usage to return, today included.
+type APIUsageParams struct {
+	Days int `form:"days" binding:"omitempty,min=1,max=90"`
+}
+
+// APIUsageReport is a seller's API plan with the daily usage, newest day
+// first. Today's figures are live.
+type APIUsageReport struct {
+	Plan string `json:"plan"`
+	// Limit is the requests allowed per window; 0 is unlimited.
+	Limit         int         `json:"limit"`
+	WindowSeconds float64     `json:"window_seconds"`
+	Days          []*APIUsage `json:"days"`
+}
+
+type UpdateAPIPlanRequest struct {
+	Plan string `json:"plan" binding:"required"`
+}
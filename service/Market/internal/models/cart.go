@@ -3,29 +3,44 @@ package models
 import "time"
 
 type CartItem struct {
-	ID        int       `json:"id" db:"id"`
-	UserID    int       `json:"user_id" db:"user_id"`
-	ProductID int       `json:"product_id" db:"product_id"`
-	Quantity  int       `json:"quantity" db:"quantity"`
-	Size      string    `json:"size" db:"size"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID        int    `json:"id" db:"id"`
+	UserID    int    `json:"user_id" db:"user_id"`
+	ProductID int    `json:"product_id" db:"product_id"`
+	Quantity  int    `json:"quantity" db:"quantity"`
+	Size      string `json:"size" db:"size"`
+	// VariantID is the variant ordered of a product with variants; Size
+	// and Color are then the variant's.
+	VariantID *int   `json:"variant_id,omitempty" db:"variant_id"`
+	Color     string `json:"color,omitempty" db:"color"`
+	// ReservedUntil is when the stock held for the item is released;
+	// nil when nothing is held.
+	ReservedUntil *time.Time `json:"reserved_until,omitempty" db:"reserved_until"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 type CartItemWithDetails struct {
 	CartItem
-	ProductTitle string  `json:"product_title" db:"product_title"`
+	ProductTitle string `json:"product_title" db:"product_title"`
+	// ProductPrice is the variant's price for variant items.
 	ProductPrice float64 `json:"product_price" db:"product_price"`
+	SKU          string  `json:"sku,omitempty" db:"sku"`
 	ProductImage string  `json:"product_image" db:"product_image"`
+	// Dimensions are the variant's where set, the product's otherwise.
+	Dimensions
 }
 
 type AddToCartRequest struct {
 	ProductID int    `json:"product_id" binding:"required"`
 	Quantity  int    `json:"quantity" binding:"required,gt=0"`
 	Size      string `json:"size"`
+	// VariantID is required for products with variants; Size is then
+	// ignored.
+	VariantID *int `json:"variant_id" binding:"omitempty,gt=0"`
 }
 
 type UpdateCartItemRequest struct {
-	Quantity int    `json:"quantity" binding:"required,gt=0"`
-	Size     string `json:"size"`
+	Quantity int `json:"quantity" binding:"required,gt=0"`
+	// Size cannot change on variant items; choose another variant instead.
+	Size string `json:"size"`
 }
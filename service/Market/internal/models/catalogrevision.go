@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// Reasons catalog revisions are taken for: on the seller's or an admin's
+// request, before a spreadsheet import, or before a restore so it can be
+// undone.
+const (
+	CatalogRevisionManual  = "manual"
+	CatalogRevisionImport  = "import"
+	CatalogRevisionRestore = "restore"
+)
+
+// CatalogRevision is a snapshot of a seller's catalog. Versions count up
+// per seller; Products are only filled in when a single revision is read.
+type CatalogRevision struct {
+	ID           int                      `json:"id" db:"id"`
+	SellerID     int                      `json:"seller_id" db:"seller_id"`
+	Version      int                      `json:"version" db:"version"`
+	Reason       string                   `json:"reason" db:"reason" example:"import"`
+	Note         string                   `json:"note" db:"note" example:"products.csv"`
+	ProductCount int                      `json:"product_count" db:"product_count"`
+	CreatedBy    int                      `json:"created_by" db:"created_by"`
+	CreatedAt    time.Time                `json:"created_at" db:"created_at"`
+	Products     []CatalogRevisionProduct `json:"products,omitempty" db:"-"`
+}
+
+// CatalogRevisionProduct is a product as it was when the revision was
+// taken.
+type CatalogRevisionProduct struct {
+	ID          int                      `json:"id"`
+	CategoryID  *int                     `json:"category_id"`
+	Title       string                   `json:"title"`
+	Description string                   `json:"description"`
+	Price       float64                  `json:"price"`
+	Stock       int                      `json:"stock"`
+	Sizes       SizesJSON                `json:"sizes"`
+	ImageURL    string                   `json:"image_url"`
+	Status      string                   `json:"status"`
+	Variants    []CatalogRevisionVariant `json:"variants"`
+	Dimensions
+}
+
+type CatalogRevisionVariant struct {
+	ID         int     `json:"id"`
+	SKU        string  `json:"sku"`
+	Size       string  `json:"size"`
+	Color      string  `json:"color"`
+	PriceDelta float64 `json:"price_delta"`
+	Stock      int     `json:"stock"`
+	Dimensions
+}
+
+type CreateCatalogRevisionRequest struct {
+	Note string `json:"note" binding:"max=255" example:"before summer prices"`
+}
+
+// RestoreCatalogRevisionRequest rolls a catalog back. Stock is only rolled
+// back with Stock set, since orders placed since the revision took from it.
+type RestoreCatalogRevisionRequest struct {
+	Stock bool `json:"stock"`
+}
+
+// CatalogRestore reports a rollback: Backup is the revision of the catalog
+// as it was before. Restored products were rolled back, Recreated ones had
+// been deleted since and Hidden ones were added since and are now deleted.
+type CatalogRestore struct {
+	Revision  *CatalogRevision `json:"revision"`
+	Backup    *CatalogRevision `json:"backup"`
+	Restored  int              `json:"restored"`
+	Recreated int              `json:"recreated"`
+	Hidden    int              `json:"hidden"`
+}
@@ -2,20 +2,42 @@ package models
 
 import "time"
 
+// Category risk levels set how long sales in a category are held after
+// delivery before they become payable to the seller.
+const (
+	CategoryRiskLow      = "low"
+	CategoryRiskStandard = "standard"
+	CategoryRiskHigh     = "high"
+)
+
 type Category struct {
-	ID          int       `json:"id" db:"id"`
-	Name        string    `json:"name" db:"name"`
-	Description string    `json:"description" db:"description"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID          int    `json:"id" db:"id"`
+	Name        string `json:"name" db:"name"`
+	Description string `json:"description" db:"description"`
+	RiskLevel   string `json:"risk_level" db:"risk_level" example:"standard"`
+	// ProductCount is the number of active products listed in the category.
+	ProductCount int64     `json:"product_count" db:"product_count"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
 }
 
 type CreateCategoryRequest struct {
 	Name        string `json:"name" binding:"required"`
 	Description string `json:"description"`
+	// RiskLevel defaults to standard.
+	RiskLevel string `json:"risk_level" binding:"omitempty,oneof=low standard high"`
 }
 
 type UpdateCategoryRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	RiskLevel   string `json:"risk_level" binding:"omitempty,oneof=low standard high"`
+}
+
+// DeleteCategoryParams are the query parameters of a category deletion.
+// Categories that still have products are only deleted with Force, which
+// moves their products to ReassignTo.
+type DeleteCategoryParams struct {
+	Force      bool `form:"force"`
+	ReassignTo *int `form:"reassign_to" binding:"omitempty,gt=0"`
 }
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+const (
+	ChargebackStatusOpen = "open"
+	ChargebackStatusWon  = "won"
+	ChargebackStatusLost = "lost"
+
+	// PayoutHoldHeld amounts are kept from the seller's payout until the
+	// chargeback is decided: released when it is won, debited when lost.
+	PayoutHoldHeld     = "held"
+	PayoutHoldReleased = "released"
+	PayoutHoldDebited  = "debited"
+)
+
+// Chargeback is the buyer's bank taking a paid card payment back. Each
+// seller of the order answers for a share of it, held from their payout,
+// and submits evidence on the dispute ticket opened for them.
+type Chargeback struct {
+	ID          int     `json:"id" db:"id"`
+	PaymentID   int     `json:"payment_id" db:"payment_id"`
+	OrderID     int     `json:"order_id" db:"order_id"`
+	Provider    string  `json:"provider" db:"provider" example:"stripe"`
+	ProviderRef string  `json:"provider_ref" db:"provider_ref" example:"dp_1Nq2w3"`
+	Amount      float64 `json:"amount" db:"amount"`
+	Reason      string  `json:"reason" db:"reason" example:"fraudulent"`
+	Status      string  `json:"status" db:"status" example:"open"`
+	// EvidenceDueBy is the provider's deadline for the sellers' evidence.
+	EvidenceDueBy *time.Time    `json:"evidence_due_by,omitempty" db:"evidence_due_by"`
+	Holds         []*PayoutHold `json:"holds" db:"-"`
+	CreatedAt     time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// PayoutHold is one seller's share of a chargeback.
+type PayoutHold struct {
+	ID           int        `json:"id" db:"id"`
+	ChargebackID int        `json:"chargeback_id" db:"chargeback_id"`
+	SellerID     int        `json:"seller_id" db:"seller_id"`
+	TicketID     *int       `json:"ticket_id,omitempty" db:"ticket_id"`
+	Amount       float64    `json:"amount" db:"amount"`
+	Status       string     `json:"status" db:"status" example:"held"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	ResolvedAt   *time.Time `json:"resolved_at,omitempty" db:"resolved_at"`
+}
+
+// CreateChargebackRequest records a chargeback the provider reported
+// outside its webhook, against the order's paid card payment.
+type CreateChargebackRequest struct {
+	OrderID       int        `json:"order_id" binding:"required,gt=0"`
+	ProviderRef   string     `json:"provider_ref" binding:"required,max=100"`
+	Amount        float64    `json:"amount" binding:"required,gt=0"`
+	Reason        string     `json:"reason" binding:"max=100"`
+	EvidenceDueBy *time.Time `json:"evidence_due_by"`
+}
+
+type ResolveChargebackRequest struct {
+	Status string `json:"status" binding:"required,oneof=won lost"`
+}
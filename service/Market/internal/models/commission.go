@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// CommissionRate is the marketplace's share of a sale. CategoryID and
+// SellerID narrow its scope; both nil is the marketplace default.
+type CommissionRate struct {
+	ID            int       `json:"id" db:"id"`
+	CategoryID    *int      `json:"category_id,omitempty" db:"category_id"`
+	SellerID      *int      `json:"seller_id,omitempty" db:"seller_id"`
+	Rate          float64   `json:"rate" db:"rate"`
+	EffectiveFrom time.Time `json:"effective_from" db:"effective_from"`
+	CreatedBy     int       `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+type CreateCommissionRateRequest struct {
+	CategoryID    *int       `json:"category_id" binding:"omitempty,gt=0"`
+	SellerID      *int       `json:"seller_id" binding:"omitempty,gt=0"`
+	Rate          *float64   `json:"rate" binding:"required,gte=0,lte=1"`
+	EffectiveFrom *time.Time `json:"effective_from"`
+}
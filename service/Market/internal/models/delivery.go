@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+const (
+	DeliveryStateActive    = "active"
+	DeliveryStateDelivered = "delivered"
+)
+
+// Delivery is an order as seen by the courier it is assigned to.
+type Delivery struct {
+	OrderID      int        `json:"order_id" db:"order_id"`
+	OrderNumber  string     `json:"order_number" db:"order_number"`
+	Status       string     `json:"status" db:"status"`
+	TotalAmount  float64    `json:"total_amount" db:"total_amount"`
+	DeliveryAddr string     `json:"delivery_address" db:"delivery_address"`
+	DeliveryLat  *float64   `json:"delivery_lat,omitempty" db:"delivery_lat"`
+	DeliveryLng  *float64   `json:"delivery_lng,omitempty" db:"delivery_lng"`
+	CourierID    int        `json:"courier_id" db:"courier_id"`
+	AssignedAt   time.Time  `json:"assigned_at" db:"assigned_at"`
+	DeliveredAt  *time.Time `json:"delivered_at,omitempty" db:"delivered_at"`
+	ProofURL     string     `json:"proof_url,omitempty" db:"proof_url"`
+}
+
+type AssignCourierRequest struct {
+	CourierID int `json:"courier_id" binding:"required,gt=0"`
+}
+
+const (
+	ProofKindPhoto     = "photo"
+	ProofKindSignature = "signature"
+)
+
+// DeliveryProof is a photo or signature image attached to an order's shipment.
+type DeliveryProof struct {
+	ID           int       `json:"id" db:"id"`
+	OrderID      int       `json:"order_id" db:"order_id"`
+	Kind         string    `json:"kind" db:"kind"`
+	URL          string    `json:"url" db:"url"`
+	UploadedBy   int       `json:"uploaded_by" db:"uploaded_by"`
+	UploaderRole string    `json:"uploader_role" db:"uploader_role"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// Handover records the moment an order left the seller or courier, verified
+// by scanning the buyer's pickup QR code.
+type Handover struct {
+	OrderID        int       `json:"order_id" db:"id"`
+	OrderNumber    string    `json:"order_number" db:"order_number"`
+	Status         string    `json:"status" db:"status"`
+	PaymentMethod  string    `json:"payment_method" db:"payment_method"`
+	TotalAmount    float64   `json:"total_amount" db:"total_amount"`
+	HandedOverAt   time.Time `json:"handed_over_at" db:"handed_over_at"`
+	HandedOverBy   int       `json:"handed_over_by" db:"handed_over_by"`
+	HandedOverRole string    `json:"handed_over_role" db:"handed_over_role"`
+}
+
+type VerifyPickupRequest struct {
+	Code string `json:"code" binding:"required"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Domain event types published to the message broker.
+const (
+	EventOrderCreated   = "OrderCreated"
+	EventOrderPaid      = "OrderPaid"
+	EventOrderCancelled = "OrderCancelled"
+	// EventOrderStatusChanged is recorded for every status change, next
+	// to OrderPaid and OrderCancelled.
+	EventOrderStatusChanged = "OrderStatusChanged"
+	EventProductStockLow    = "ProductStockLow"
+)
+
+// OutboxEvent is a domain event waiting in the outbox to be published. Key
+// orders events about the same thing, e.g. an order's ID.
+type OutboxEvent struct {
+	ID        int64           `json:"id" db:"id"`
+	Type      string          `json:"type" db:"type"`
+	Key       string          `json:"key" db:"key"`
+	Payload   json.RawMessage `json:"payload" db:"payload"`
+	Attempts  int             `json:"attempts" db:"attempts"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+type OrderEventItem struct {
+	ProductID int     `json:"product_id"`
+	VariantID *int    `json:"variant_id,omitempty"`
+	Quantity  int     `json:"quantity"`
+	Price     float64 `json:"price"`
+}
+
+// OrderCreatedEvent is the payload of OrderCreated.
+type OrderCreatedEvent struct {
+	OrderID     int              `json:"order_id"`
+	OrderNumber string           `json:"order_number"`
+	UserID      int              `json:"user_id"`
+	Email       string           `json:"email,omitempty"`
+	TotalAmount float64          `json:"total_amount"`
+	Items       []OrderEventItem `json:"items"`
+}
+
+// OrderStatusEvent is the payload of OrderStatusChanged, OrderPaid and
+// OrderCancelled. Actor is who moved the order, e.g. the buyer or the
+// payment provider.
+type OrderStatusEvent struct {
+	OrderID     int    `json:"order_id"`
+	OrderNumber string `json:"order_number"`
+	UserID      int    `json:"user_id"`
+	FromStatus  string `json:"from_status"`
+	Status      string `json:"status"`
+	Actor       string `json:"actor"`
+}
+
+// ProductStockLowEvent is the payload of ProductStockLow, recorded when an
+// order takes a product's stock to Threshold or below: the product's own
+// low-stock threshold or the default.
+type ProductStockLowEvent struct {
+	ProductID int `json:"product_id"`
+	SellerID  int `json:"seller_id"`
+	Stock     int `json:"stock"`
+	Threshold int `json:"threshold"`
+}
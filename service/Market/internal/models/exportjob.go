@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Export job statuses. Done jobs become expired once their file is
+// removed.
+const (
+	ExportQueued  = "queued"
+	ExportRunning = "running"
+	ExportDone    = "done"
+	ExportFailed  = "failed"
+	ExportExpired = "expired"
+)
+
+// ExportJob is a product export written to a file in the background.
+// DownloadURL is a signed link to the file while the job is done.
+type ExportJob struct {
+	ID            int        `json:"id" db:"id"`
+	SellerID      int        `json:"seller_id" db:"seller_id"`
+	Format        string     `json:"format" db:"format"`
+	Status        string     `json:"status" db:"status"`
+	TotalRows     int        `json:"total_rows" db:"total_rows"`
+	ProcessedRows int        `json:"processed_rows" db:"processed_rows"`
+	FileName      string     `json:"-" db:"file_name"`
+	Error         string     `json:"error,omitempty" db:"error"`
+	DownloadURL   string     `json:"download_url,omitempty" db:"-"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// FeedFile describes a generated catalog feed and a signed URL to fetch it.
+type FeedFile struct {
+	Name        string    `json:"name"`
+	URL         string    `json:"url"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Size        int64     `json:"size"`
+}
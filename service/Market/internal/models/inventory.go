@@ -0,0 +1,130 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Inventory import statuses. An import is applied at once unless some of
+// its rows need the seller's confirmation.
+const (
+	InventoryImportApplied              = "applied"
+	InventoryImportAwaitingConfirmation = "awaiting_confirmation"
+	InventoryImportConfirmed            = "confirmed"
+	InventoryImportDiscarded            = "discarded"
+)
+
+// Inventory import row statuses.
+const (
+	InventoryRowApplied   = "applied"
+	InventoryRowHeld      = "held"
+	InventoryRowSkipped   = "skipped"
+	InventoryRowDiscarded = "discarded"
+)
+
+// Mismatches found when reconciling an import row with the product.
+const (
+	InventoryIssueNotFound           = "product_not_found"
+	InventoryIssueNegativeAdjustment = "negative_adjustment"
+	InventoryIssuePriceJump          = "price_jump"
+)
+
+// InventoryImport is a bulk stock and price update with its reconciliation
+// report.
+type InventoryImport struct {
+	ID       int    `json:"id" db:"id"`
+	SellerID int    `json:"seller_id" db:"seller_id"`
+	Status   string `json:"status" db:"status" example:"awaiting_confirmation"`
+	// RiskPercent is the largest price change or stock drop, in percent of
+	// the current value, applied without confirmation.
+	RiskPercent float64    `json:"risk_percent" db:"risk_percent" example:"20"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty" db:"decided_at"`
+	// Applied, Held and Skipped count the rows by status; Mismatches counts
+	// the rows with at least one issue.
+	Applied    int                   `json:"applied" db:"-"`
+	Held       int                   `json:"held" db:"-"`
+	Skipped    int                   `json:"skipped" db:"-"`
+	Mismatches int                   `json:"mismatches" db:"-"`
+	Rows       []*InventoryImportRow `json:"rows" db:"-"`
+}
+
+// InventoryImportRow is one line of an import: the requested stock and
+// price next to the product's values before the import.
+type InventoryImportRow struct {
+	Line          int      `json:"line" db:"line"`
+	ProductID     int      `json:"product_id" db:"product_id"`
+	Stock         *int     `json:"stock,omitempty" db:"stock"`
+	Price         *float64 `json:"price,omitempty" db:"price"`
+	PreviousStock *int     `json:"previous_stock,omitempty" db:"previous_stock"`
+	PreviousPrice *float64 `json:"previous_price,omitempty" db:"previous_price"`
+	Issues        []string `json:"issues" db:"issues"`
+	Status        string   `json:"status" db:"status" example:"held"`
+}
+
+type InventoryImportRequest struct {
+	Rows []InventoryImportLine `json:"rows" binding:"required,min=1,max=1000,dive"`
+}
+
+// InventoryImportLine sets the stock, the price or both of a product.
+type InventoryImportLine struct {
+	ProductID int      `json:"product_id" binding:"required"`
+	Stock     *int     `json:"stock" binding:"omitempty,gte=0"`
+	Price     *float64 `json:"price" binding:"omitempty,gt=0"`
+}
+
+// Reconcile compares the row with the product's current stock and price,
+// records the mismatches and holds the row when its price changes, or its
+// stock drops, by more than riskPercent.
+func (r *InventoryImportRow) Reconcile(stock int, price, riskPercent float64) {
+	r.PreviousStock, r.PreviousPrice = &stock, &price
+	r.Issues = []string{}
+	r.Status = InventoryRowApplied
+
+	if r.Stock != nil && *r.Stock < stock {
+		r.Issues = append(r.Issues, InventoryIssueNegativeAdjustment)
+		if percentChange(float64(stock), float64(*r.Stock)) > riskPercent {
+			r.Status = InventoryRowHeld
+		}
+	}
+	if r.Price != nil && percentChange(price, *r.Price) > riskPercent {
+		r.Issues = append(r.Issues, InventoryIssuePriceJump)
+		r.Status = InventoryRowHeld
+	}
+}
+
+// NotFound marks a row whose product is not one of the seller's.
+func (r *InventoryImportRow) NotFound() {
+	r.Issues = []string{InventoryIssueNotFound}
+	r.Status = InventoryRowSkipped
+}
+
+// Tally counts the rows of the report.
+func (i *InventoryImport) Tally() {
+	i.Applied, i.Held, i.Skipped, i.Mismatches = 0, 0, 0, 0
+	for _, row := range i.Rows {
+		switch row.Status {
+		case InventoryRowApplied:
+			i.Applied++
+		case InventoryRowHeld:
+			i.Held++
+		case InventoryRowSkipped:
+			i.Skipped++
+		}
+		if len(row.Issues) > 0 {
+			i.Mismatches++
+		}
+	}
+}
+
+// percentChange is how much to differs from from, in percent of from. Any
+// change from zero counts as unbounded.
+func percentChange(from, to float64) float64 {
+	if from == to {
+		return 0
+	}
+	if from == 0 {
+		return math.Inf(1)
+	}
+	return math.Abs(to-from) / from * 100
+}
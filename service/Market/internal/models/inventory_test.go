@@ -0,0 +1,61 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInventoryImportRow_Reconcile(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name       string
+		stock      *int
+		price      *float64
+		wantIssues []string
+		wantStatus string
+	}{
+		{name: "restock", stock: intPtr(40), wantIssues: []string{}, wantStatus: InventoryRowApplied},
+		{name: "small stock drop", stock: intPtr(9), wantIssues: []string{InventoryIssueNegativeAdjustment}, wantStatus: InventoryRowApplied},
+		{name: "large stock drop", stock: intPtr(2), wantIssues: []string{InventoryIssueNegativeAdjustment}, wantStatus: InventoryRowHeld},
+		{name: "price within threshold", price: floatPtr(110), wantIssues: []string{}, wantStatus: InventoryRowApplied},
+		{name: "price jump", price: floatPtr(150), wantIssues: []string{InventoryIssuePriceJump}, wantStatus: InventoryRowHeld},
+		{name: "price cut", price: floatPtr(50), wantIssues: []string{InventoryIssuePriceJump}, wantStatus: InventoryRowHeld},
+		{
+			name: "both", stock: intPtr(9), price: floatPtr(150),
+			wantIssues: []string{InventoryIssueNegativeAdjustment, InventoryIssuePriceJump}, wantStatus: InventoryRowHeld,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := &InventoryImportRow{ProductID: 1, Stock: tt.stock, Price: tt.price}
+			row.Reconcile(10, 100, 20)
+
+			assert.Equal(t, tt.wantIssues, row.Issues)
+			assert.Equal(t, tt.wantStatus, row.Status)
+			assert.Equal(t, 10, *row.PreviousStock)
+			assert.Equal(t, 100.0, *row.PreviousPrice)
+		})
+	}
+}
+
+func TestInventoryImport_Tally(t *testing.T) {
+	imp := &InventoryImport{Rows: []*InventoryImportRow{
+		{Status: InventoryRowApplied, Issues: []string{}},
+		{Status: InventoryRowApplied, Issues: []string{InventoryIssueNegativeAdjustment}},
+		{Status: InventoryRowHeld, Issues: []string{InventoryIssuePriceJump}},
+	}}
+	skipped := &InventoryImportRow{}
+	skipped.NotFound()
+	imp.Rows = append(imp.Rows, skipped)
+
+	imp.Tally()
+
+	assert.Equal(t, 2, imp.Applied)
+	assert.Equal(t, 1, imp.Held)
+	assert.Equal(t, 1, imp.Skipped)
+	assert.Equal(t, 3, imp.Mismatches)
+}
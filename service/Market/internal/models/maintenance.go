@@ -0,0 +1,6 @@
+package models
+
+// ReadOnlyTables lists the tables whose writes are currently blocked.
+type ReadOnlyTables struct {
+	Tables []string `json:"tables" binding:"dive,oneof=products orders"`
+}
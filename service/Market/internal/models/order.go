@@ -3,37 +3,263 @@ package models
 import "time"
 
 type Order struct {
-	ID            int       `json:"id" db:"id"`
-	UserID        int       `json:"user_id" db:"user_id"`
-	TotalAmount   float64   `json:"total_amount" db:"total_amount"`
-	Status        string    `json:"status" db:"status"`
-	PaymentMethod string    `json:"payment_method" db:"payment_method"`
-	PaymentStatus string    `json:"payment_status" db:"payment_status"`
-	DeliveryAddr  string    `json:"delivery_address" db:"delivery_address"`
-	CreatedAt     time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	ID            int     `json:"id" db:"id"`
+	OrderNumber   string  `json:"order_number" db:"order_number"`
+	UserID        int     `json:"user_id" db:"user_id"`
+	TotalAmount   float64 `json:"total_amount" db:"total_amount"`
+	Status        string  `json:"status" db:"status"`
+	PaymentMethod string  `json:"payment_method" db:"payment_method"`
+	PaymentStatus string  `json:"payment_status" db:"payment_status"`
+	DeliveryAddr  string  `json:"delivery_address" db:"delivery_address"`
+	// IsGift orders ship with a packing slip without prices, carrying
+	// GiftMessage.
+	IsGift      bool   `json:"is_gift" db:"is_gift"`
+	GiftMessage string `json:"gift_message,omitempty" db:"gift_message"`
+	// PromoCode is the code redeemed at checkout and DiscountAmount what
+	// it took off; TotalAmount is after the discount.
+	PromoCode      string    `json:"promo_code,omitempty" db:"promo_code"`
+	DiscountAmount float64   `json:"discount_amount" db:"discount_amount"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+	// The Package fields are the parcel computed at checkout for the
+	// carrier; nil when none of the items had a weight.
+	PackageWeightGrams *int `json:"package_weight_grams,omitempty" db:"package_weight_grams"`
+	PackageLengthMM    *int `json:"package_length_mm,omitempty" db:"package_length_mm"`
+	PackageWidthMM     *int `json:"package_width_mm,omitempty" db:"package_width_mm"`
+	PackageHeightMM    *int `json:"package_height_mm,omitempty" db:"package_height_mm"`
+	// UserEmail is the buyer's account email from the Auth service, filled
+	// in for admins when AUTH_GRPC_ADDR is set.
+	UserEmail string `json:"user_email,omitempty" db:"-"`
 }
 
 type OrderItem struct {
-	ID        int       `json:"id" db:"id"`
-	OrderID   int       `json:"order_id" db:"order_id"`
-	ProductID int       `json:"product_id" db:"product_id"`
-	Quantity  int       `json:"quantity" db:"quantity"`
-	Size      string    `json:"size" db:"size"`
-	Price     float64   `json:"price" db:"price"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID        int     `json:"id" db:"id"`
+	OrderID   int     `json:"order_id" db:"order_id"`
+	ProductID int     `json:"product_id" db:"product_id"`
+	Quantity  int     `json:"quantity" db:"quantity"`
+	Size      string  `json:"size" db:"size"`
+	Price     float64 `json:"price" db:"price"`
+	// VariantID, SKU and Color describe the variant sold; SKU and Color
+	// stay when the variant is removed.
+	VariantID *int   `json:"variant_id,omitempty" db:"variant_id"`
+	SKU       string `json:"sku,omitempty" db:"sku"`
+	Color     string `json:"color,omitempty" db:"color"`
+	// DiscountAmount is taken off price × quantity; DiscountFundedBy says
+	// whether the marketplace or the seller bears it.
+	DiscountAmount   float64 `json:"discount_amount" db:"discount_amount"`
+	DiscountFundedBy string  `json:"discount_funded_by,omitempty" db:"discount_funded_by"`
+	// FulfillmentStatus is set by the seller of the item.
+	FulfillmentStatus string    `json:"fulfillment_status" db:"fulfillment_status"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
 }
 
 type OrderWithItems struct {
 	Order
-	Items []OrderItem `json:"items"`
+	Items []OrderItem `json:"items" db:"-"`
+	// CancellableUntil and CancellationSecondsLeft are set on order
+	// details while the buyer may still cancel the order.
+	CancellableUntil        *time.Time `json:"cancellable_until,omitempty" db:"-"`
+	CancellationSecondsLeft int64      `json:"cancellation_seconds_left,omitempty" db:"-"`
+}
+
+// CancelBlocker explains why the buyer cannot cancel the order whatever
+// its cancellation window, or returns "" if nothing but the window stops
+// them.
+func (o *OrderWithItems) CancelBlocker() string {
+	switch o.Status {
+	case OrderStatusCancelled:
+		return "order is already cancelled"
+	case OrderStatusRefunded:
+		return "order is refunded"
+	case OrderStatusShipped, OrderStatusDelivered:
+		return "shipped orders cannot be cancelled"
+	case OrderStatusPaid:
+		return "paid orders can only be cancelled by support"
+	}
+	switch o.PaymentStatus {
+	case "paid", "refunded":
+		return "paid orders can only be cancelled by support"
+	}
+	for _, item := range o.Items {
+		if item.FulfillmentStatus == FulfillmentShipped || item.FulfillmentStatus == FulfillmentDelivered {
+			return "orders with shipped items cannot be cancelled"
+		}
+	}
+	return ""
+}
+
+// SetCancellation records that the buyer may cancel the order until
+// deadline, left from now. Nothing is set once the window has closed.
+func (o *OrderWithItems) SetCancellation(deadline time.Time, left time.Duration) {
+	if left < time.Second {
+		return
+	}
+	o.CancellableUntil = &deadline
+	o.CancellationSecondsLeft = int64(left / time.Second)
 }
 
 type CreateOrderRequest struct {
-	PaymentMethod string `json:"payment_method" binding:"required"`
-	DeliveryAddr  string `json:"delivery_address" binding:"required"`
+	PaymentMethod   string `json:"payment_method" binding:"required"`
+	DeliveryAddr    string `json:"delivery_address" binding:"required"`
+	DeliveryCountry string `json:"delivery_country" binding:"omitempty,len=2,alpha"`
+	DeliveryRegion  string `json:"delivery_region"`
+	IsGift          bool   `json:"is_gift"`
+	GiftMessage     string `json:"gift_message" binding:"max=500"`
+	PromoCode       string `json:"promo_code" binding:"omitempty,max=32"`
+	// DeliveryLat/DeliveryLng are filled in by address validation at
+	// checkout, never by the client.
+	DeliveryLat *float64 `json:"-"`
+	DeliveryLng *float64 `json:"-"`
+	// Package is the parcel computed at checkout, never by the client.
+	Package *ShippingPackage `json:"-"`
+	// Email receives the order confirmation; it comes from the access
+	// token, never from the client.
+	Email string `json:"-"`
+}
+
+// OrderFilter narrows the admin order list. Text filters match partially
+// and ignore case; Q matches any of order number, buyer email and product
+// title. The date range covers whole days, To included.
+type OrderFilter struct {
+	Q           string     `form:"q" binding:"omitempty,max=100"`
+	Status      string     `form:"status"`
+	OrderNumber string     `form:"order_number" binding:"omitempty,max=50"`
+	Email       string     `form:"email" binding:"omitempty,max=255"`
+	Product     string     `form:"product" binding:"omitempty,max=255"`
+	SellerID    *int       `form:"seller_id" binding:"omitempty,gt=0"`
+	MinAmount   *float64   `form:"min_amount" binding:"omitempty,gte=0"`
+	MaxAmount   *float64   `form:"max_amount" binding:"omitempty,gte=0"`
+	From        *time.Time `form:"from" time_format:"2006-01-02"`
+	To          *time.Time `form:"to" time_format:"2006-01-02"`
 }
 
 type UpdateOrderStatusRequest struct {
-	Status string `json:"status" binding:"required"`
+	Status string `json:"status" binding:"required,oneof=pending confirmed paid shipped delivered cancelled refunded"`
+}
+
+// Order statuses.
+const (
+	OrderStatusPending   = "pending"
+	OrderStatusConfirmed = "confirmed"
+	OrderStatusPaid      = "paid"
+	OrderStatusShipped   = "shipped"
+	OrderStatusDelivered = "delivered"
+	OrderStatusCancelled = "cancelled"
+	OrderStatusRefunded  = "refunded"
+)
+
+// orderStatusFrom lists the statuses an order may move to each status from.
+// Unpaid orders are cancelled, paid ones refunded; cancelled and refunded
+// orders stay as they are.
+var orderStatusFrom = map[string][]string{
+	OrderStatusConfirmed: {OrderStatusPending},
+	OrderStatusPaid:      {OrderStatusPending, OrderStatusConfirmed},
+	OrderStatusShipped:   {OrderStatusConfirmed, OrderStatusPaid},
+	OrderStatusDelivered: {OrderStatusShipped},
+	OrderStatusCancelled: {OrderStatusPending, OrderStatusConfirmed},
+	OrderStatusRefunded:  {OrderStatusPaid, OrderStatusShipped, OrderStatusDelivered},
+}
+
+// CanMoveOrder reports whether an order may move from one status to
+// another.
+func CanMoveOrder(from, to string) bool {
+	for _, s := range orderStatusFrom[to] {
+		if s == from {
+			return true
+		}
+	}
+	return false
+}
+
+// Who moved an order to a status.
+const (
+	StatusActorAdmin   = "admin"
+	StatusActorBuyer   = "buyer"
+	StatusActorCourier = "courier"
+	StatusActorPayment = "payment"
+)
+
+// OrderStatusChange is one entry of an order's status history.
+type OrderStatusChange struct {
+	ID         int    `json:"id" db:"id"`
+	OrderID    int    `json:"order_id" db:"order_id"`
+	FromStatus string `json:"from_status" db:"from_status" example:"pending"`
+	ToStatus   string `json:"to_status" db:"to_status" example:"paid"`
+	Actor      string `json:"actor" db:"actor" example:"admin"`
+	// ChangedBy is the user id of the admin, buyer or courier; payment
+	// events have none.
+	ChangedBy *int      `json:"changed_by,omitempty" db:"changed_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Order item fulfillment statuses.
+const (
+	FulfillmentPending    = "pending"
+	FulfillmentProcessing = "processing"
+	FulfillmentShipped    = "shipped"
+	FulfillmentDelivered  = "delivered"
+	FulfillmentCancelled  = "cancelled"
+)
+
+// fulfillmentFrom lists the statuses an item may move to each status from.
+var fulfillmentFrom = map[string][]string{
+	FulfillmentProcessing: {FulfillmentPending},
+	FulfillmentShipped:    {FulfillmentPending, FulfillmentProcessing},
+	FulfillmentDelivered:  {FulfillmentShipped},
+	FulfillmentCancelled:  {FulfillmentPending, FulfillmentProcessing},
+}
+
+// IsFulfillmentStatus reports whether s is a fulfillment status.
+func IsFulfillmentStatus(s string) bool {
+	switch s {
+	case FulfillmentPending, FulfillmentProcessing, FulfillmentShipped, FulfillmentDelivered, FulfillmentCancelled:
+		return true
+	}
+	return false
+}
+
+// CanFulfill reports whether an item may move from one fulfillment status
+// to another.
+func CanFulfill(from, to string) bool {
+	for _, s := range fulfillmentFrom[to] {
+		if s == from {
+			return true
+		}
+	}
+	return false
+}
+
+// SellerOrder is an order as one seller sees it: only the seller's items,
+// and their total less the discounts the seller funded.
+type SellerOrder struct {
+	Order
+	Items       []OrderItem `json:"items" db:"-"`
+	SellerTotal float64     `json:"seller_total" db:"-"`
+	// PackingSlipURL downloads the price-free packing slip of gift orders.
+	PackingSlipURL string `json:"packing_slip_url,omitempty" db:"-"`
+}
+
+// PackingSlip is what goes in the parcel of a gift order: the seller's
+// items without prices.
+type PackingSlip struct {
+	OrderNumber  string            `json:"order_number"`
+	OrderedAt    time.Time         `json:"ordered_at"`
+	ShopName     string            `json:"shop_name"`
+	DeliveryAddr string            `json:"delivery_address"`
+	GiftMessage  string            `json:"gift_message,omitempty"`
+	Items        []PackingSlipItem `json:"items"`
+}
+
+type PackingSlipItem struct {
+	Title    string `json:"title" db:"title"`
+	Size     string `json:"size,omitempty" db:"size"`
+	Color    string `json:"color,omitempty" db:"color"`
+	Quantity int    `json:"quantity" db:"quantity"`
+}
+
+// UpdateFulfillmentRequest moves the seller's items of an order, or only
+// ItemIDs among them, to Status.
+type UpdateFulfillmentRequest struct {
+	Status  string `json:"status" binding:"required,oneof=processing shipped delivered cancelled"`
+	ItemIDs []int  `json:"item_ids" binding:"omitempty,dive,gt=0"`
 }
@@ -159,3 +159,102 @@ func TestCreateOrderRequest_PaymentMethods(t *testing.T) {
 		assert.Equal(t, method, req.PaymentMethod)
 	}
 }
+
+func TestCanFulfill(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{FulfillmentPending, FulfillmentProcessing, true},
+		{FulfillmentPending, FulfillmentShipped, true},
+		{FulfillmentProcessing, FulfillmentShipped, true},
+		{FulfillmentShipped, FulfillmentDelivered, true},
+		{FulfillmentPending, FulfillmentCancelled, true},
+		{FulfillmentProcessing, FulfillmentCancelled, true},
+		{FulfillmentPending, FulfillmentDelivered, false},
+		{FulfillmentShipped, FulfillmentCancelled, false},
+		{FulfillmentShipped, FulfillmentProcessing, false},
+		{FulfillmentCancelled, FulfillmentProcessing, false},
+		{FulfillmentDelivered, FulfillmentPending, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, CanFulfill(tt.from, tt.to), "%s -> %s", tt.from, tt.to)
+	}
+}
+
+func TestOrderWithItems_CancelBlocker(t *testing.T) {
+	items := func(statuses ...string) []OrderItem {
+		var out []OrderItem
+		for _, s := range statuses {
+			out = append(out, OrderItem{FulfillmentStatus: s})
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		order   OrderWithItems
+		blocked bool
+	}{
+		{"pending", OrderWithItems{Order: Order{Status: "pending", PaymentStatus: "pending"}, Items: items(FulfillmentPending, FulfillmentProcessing)}, false},
+		{"failed payment", OrderWithItems{Order: Order{Status: "confirmed", PaymentStatus: "failed"}, Items: items(FulfillmentCancelled)}, false},
+		{"cancelled", OrderWithItems{Order: Order{Status: "cancelled", PaymentStatus: "pending"}}, true},
+		{"shipped", OrderWithItems{Order: Order{Status: "shipped", PaymentStatus: "pending"}}, true},
+		{"paid", OrderWithItems{Order: Order{Status: "confirmed", PaymentStatus: "paid"}}, true},
+		{"marked paid", OrderWithItems{Order: Order{Status: "paid", PaymentStatus: "pending"}}, true},
+		{"refunded", OrderWithItems{Order: Order{Status: "refunded"}}, true},
+		{"item shipped", OrderWithItems{Order: Order{Status: "confirmed", PaymentStatus: "pending"}, Items: items(FulfillmentPending, FulfillmentShipped)}, true},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.blocked, tt.order.CancelBlocker() != "", tt.name)
+	}
+}
+
+func TestOrderWithItems_SetCancellation(t *testing.T) {
+	deadline := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	var order OrderWithItems
+	order.SetCancellation(deadline, 90*time.Second+500*time.Millisecond)
+	require.NotNil(t, order.CancellableUntil)
+	assert.Equal(t, deadline, *order.CancellableUntil)
+	assert.Equal(t, int64(90), order.CancellationSecondsLeft)
+
+	var closed OrderWithItems
+	closed.SetCancellation(deadline, -time.Minute)
+	assert.Nil(t, closed.CancellableUntil)
+
+	data, err := json.Marshal(closed)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "cancellable_until")
+}
+
+func TestCanMoveOrder(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{OrderStatusPending, OrderStatusPaid, true},
+		{OrderStatusPending, OrderStatusConfirmed, true},
+		{OrderStatusConfirmed, OrderStatusShipped, true},
+		{OrderStatusPaid, OrderStatusShipped, true},
+		{OrderStatusShipped, OrderStatusDelivered, true},
+		{OrderStatusPending, OrderStatusCancelled, true},
+		{OrderStatusDelivered, OrderStatusRefunded, true},
+		{OrderStatusPending, OrderStatusShipped, false},
+		{OrderStatusPending, OrderStatusRefunded, false},
+		{OrderStatusPaid, OrderStatusCancelled, false},
+		{OrderStatusShipped, OrderStatusPaid, false},
+		{OrderStatusDelivered, OrderStatusShipped, false},
+		{OrderStatusCancelled, OrderStatusPending, false},
+		{OrderStatusRefunded, OrderStatusDelivered, false},
+		{OrderStatusPaid, OrderStatusPaid, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			require.Equal(t, tt.want, CanMoveOrder(tt.from, tt.to))
+		})
+	}
+}
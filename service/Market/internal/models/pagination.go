@@ -5,9 +5,16 @@ const (
 	MaxPageSize     = 100
 )
 
+// Count modes for the total_items of a paginated listing.
+const (
+	CountExact    = "exact"
+	CountEstimate = "estimate"
+)
+
 type PaginationParams struct {
-	Page     int `form:"page" binding:"omitempty,min=1"`
-	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Count    string `form:"count" binding:"omitempty,oneof=exact estimate"`
 }
 
 type PaginationMeta struct {
@@ -15,6 +22,8 @@ type PaginationMeta struct {
 	PageSize   int   `json:"page_size"`
 	TotalItems int64 `json:"total_items"`
 	TotalPages int   `json:"total_pages"`
+	// Estimated is set when TotalItems came from count=estimate.
+	Estimated bool `json:"estimated,omitempty"`
 }
 
 type PaginatedResponse struct {
@@ -29,6 +38,11 @@ func (p *PaginationParams) GetOffset() int {
 	return (p.Page - 1) * p.GetLimit()
 }
 
+// EstimateCount reports whether the caller accepts an approximate total.
+func (p *PaginationParams) EstimateCount() bool {
+	return p != nil && p.Count == CountEstimate
+}
+
 func (p *PaginationParams) GetLimit() int {
 	if p.PageSize < 1 {
 		return DefaultPageSize
@@ -153,3 +153,10 @@ func TestNewPaginationMeta(t *testing.T) {
 		})
 	}
 }
+
+func TestPaginationParams_EstimateCount(t *testing.T) {
+	assert.False(t, (*PaginationParams)(nil).EstimateCount())
+	assert.False(t, (&PaginationParams{}).EstimateCount())
+	assert.False(t, (&PaginationParams{Count: CountExact}).EstimateCount())
+	assert.True(t, (&PaginationParams{Count: CountEstimate}).EstimateCount())
+}
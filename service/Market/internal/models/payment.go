@@ -0,0 +1,177 @@
+package models
+
+import "time"
+
+// Payment statuses, shared with orders.payment_status.
+const (
+	PaymentStatusPending = "pending"
+	// PaymentStatusRequiresAction is a card payment waiting for the buyer
+	// to authenticate it with their bank (3-D Secure). Its order stays
+	// pending.
+	PaymentStatusRequiresAction = "requires_action"
+	PaymentStatusPaid           = "paid"
+	PaymentStatusFailed         = "failed"
+	PaymentStatusRefunded       = "refunded"
+	// PaymentStatusVoided is a gift card or store credit payment given
+	// back because the card charge for the rest of the order failed. It
+	// is not an order payment status.
+	PaymentStatusVoided = "voided"
+)
+
+// Actions a buyer takes to authenticate a payment in requires_action.
+const (
+	// PaymentActionRedirect sends the buyer's browser to the payment's
+	// ActionURL, which returns them to PAYMENT_RETURN_URL when done.
+	PaymentActionRedirect = "redirect"
+	// PaymentActionSDK is handled by the provider's browser library with
+	// the payment's client secret.
+	PaymentActionSDK = "sdk"
+)
+
+// Payment methods. An order is paid by at most one payment of each
+// balance method, used first, and card payments for the rest.
+const (
+	PaymentMethodCard        = "card"
+	PaymentMethodGiftCard    = "gift_card"
+	PaymentMethodStoreCredit = "store_credit"
+)
+
+// Payment is one charge attempt for an order. RefundedAmount grows with
+// each refund; the status becomes refunded once it reaches Amount.
+// Gift card and store credit payments are held pending until the card
+// charge for the rest of the order settles.
+type Payment struct {
+	ID             int     `json:"id" db:"id"`
+	OrderID        int     `json:"order_id" db:"order_id"`
+	Method         string  `json:"method" db:"method" example:"card"`
+	GiftCardID     *int    `json:"gift_card_id,omitempty" db:"gift_card_id"`
+	Provider       string  `json:"provider" db:"provider"`
+	ProviderRef    string  `json:"provider_ref" db:"provider_ref"`
+	Amount         float64 `json:"amount" db:"amount"`
+	RefundedAmount float64 `json:"refunded_amount" db:"refunded_amount"`
+	Status         string  `json:"status" db:"status"`
+	// ActionType and ActionURL tell the buyer how to authenticate a
+	// payment in requires_action; empty otherwise.
+	ActionType string    `json:"action_type,omitempty" db:"action_type" example:"redirect"`
+	ActionURL  string    `json:"action_url,omitempty" db:"action_url"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	// ClientSecret lets the buyer's browser confirm a pending charge with
+	// the provider. Returned only when the payment is created.
+	ClientSecret string `json:"client_secret,omitempty" db:"-"`
+}
+
+// OrderPayments are all payments of an order, oldest first, and the
+// payment status they add up to.
+type OrderPayments struct {
+	OrderID       int        `json:"order_id"`
+	PaymentStatus string     `json:"payment_status"`
+	Payments      []*Payment `json:"payments"`
+}
+
+// OrderPaymentStatus sums up an order's payments: pending while any is or
+// waits for the buyer to authenticate it,
+// then paid once anything was collected, refunded once all of that was
+// given back, and failed when nothing was. Voided payments are left out.
+func OrderPaymentStatus(payments []*Payment) string {
+	var collected, refunded, failed int
+	for _, p := range payments {
+		switch p.Status {
+		case PaymentStatusPending, PaymentStatusRequiresAction:
+			return PaymentStatusPending
+		case PaymentStatusPaid:
+			collected++
+		case PaymentStatusRefunded:
+			collected++
+			refunded++
+		case PaymentStatusFailed:
+			failed++
+		}
+	}
+	switch {
+	case collected > 0 && refunded == collected:
+		return PaymentStatusRefunded
+	case collected > 0:
+		return PaymentStatusPaid
+	case failed > 0:
+		return PaymentStatusFailed
+	}
+	return PaymentStatusPending
+}
+
+// PayOrderRequest pays an order. GiftCardCode and UseStoreCredit are
+// spent first, in that order, and the card is charged for the rest: the
+// saved method SavedPaymentMethodID without the buyer confirming it, or
+// else the card the buyer enters, kept for later with SaveMethod.
+type PayOrderRequest struct {
+	GiftCardCode         string `json:"gift_card_code" binding:"omitempty,max=32"`
+	UseStoreCredit       bool   `json:"use_store_credit"`
+	SavedPaymentMethodID int    `json:"saved_payment_method_id" binding:"omitempty,gt=0"`
+	SaveMethod           bool   `json:"save_method"`
+	// Scenario overrides the sandbox provider's configured outcome for
+	// this charge. Ignored by real providers.
+	Scenario string `json:"scenario" binding:"omitempty,oneof=succeed fail delay challenge"`
+}
+
+// RefundPaymentRequest refunds an order's payments, the most recent first,
+// so the card is refunded before store credit and gift cards.
+type RefundPaymentRequest struct {
+	// Amount to refund; the whole refundable amount when omitted.
+	Amount float64 `json:"amount" binding:"omitempty,gt=0"`
+}
+
+// PaymentWebhookRequest is a provider event as delivered by the sandbox
+// webhook simulator.
+type PaymentWebhookRequest struct {
+	ProviderRef string `json:"provider_ref" binding:"required"`
+	Status      string `json:"status" binding:"required,oneof=paid failed"`
+}
+
+// GiftCard can be spent on any number of orders until its balance runs out
+// or it expires.
+type GiftCard struct {
+	ID            int        `json:"id" db:"id"`
+	Code          string     `json:"code" db:"code" example:"K7Q2M9XV4T8B3N6P"`
+	InitialAmount float64    `json:"initial_amount" db:"initial_amount"`
+	Balance       float64    `json:"balance" db:"balance"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	CreatedBy     int        `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
+
+// GiftCardBalance is what a buyer may see of a gift card.
+type GiftCardBalance struct {
+	Code      string     `json:"code" db:"code"`
+	Balance   float64    `json:"balance" db:"balance"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+}
+
+// CreateGiftCardRequest issues a gift card; a random code is made when
+// Code is empty.
+type CreateGiftCardRequest struct {
+	Code      string     `json:"code" binding:"omitempty,min=8,max=32,alphanum"`
+	Amount    float64    `json:"amount" binding:"required,gt=0,lte=10000"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// StoreCredit is a buyer's balance to pay orders with.
+type StoreCredit struct {
+	UserID    int       `json:"user_id" db:"user_id"`
+	Balance   float64   `json:"balance" db:"balance"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type GrantStoreCreditRequest struct {
+	Amount float64 `json:"amount" binding:"required,gt=0,lte=10000"`
+}
+
+// SavedPaymentMethod is a payment method the provider keeps for a buyer.
+// Only the provider's IDs are stored.
+type SavedPaymentMethod struct {
+	ID          int       `json:"id" db:"id"`
+	UserID      int       `json:"user_id" db:"user_id"`
+	Provider    string    `json:"provider" db:"provider" example:"stripe"`
+	CustomerRef string    `json:"customer_ref" db:"customer_ref" example:"cus_Q1w2e3"`
+	MethodRef   string    `json:"method_ref" db:"method_ref" example:"pm_1Nq2w3"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
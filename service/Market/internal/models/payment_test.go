@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderPaymentStatus(t *testing.T) {
+	payments := func(statuses ...string) []*Payment {
+		var ps []*Payment
+		for _, s := range statuses {
+			ps = append(ps, &Payment{Status: s})
+		}
+		return ps
+	}
+
+	tests := []struct {
+		name     string
+		payments []*Payment
+		want     string
+	}{
+		{name: "no payments", want: PaymentStatusPending},
+		{name: "declined", payments: payments(PaymentStatusFailed), want: PaymentStatusFailed},
+		{name: "retried after decline", payments: payments(PaymentStatusFailed, PaymentStatusPaid), want: PaymentStatusPaid},
+		{name: "balance held for card", payments: payments(PaymentStatusPending, PaymentStatusPending), want: PaymentStatusPending},
+		{name: "card waiting for 3-D Secure", payments: payments(PaymentStatusPending, PaymentStatusRequiresAction), want: PaymentStatusPending},
+		{name: "balance voided by declined card", payments: payments(PaymentStatusVoided, PaymentStatusFailed), want: PaymentStatusFailed},
+		{name: "split paid", payments: payments(PaymentStatusPaid, PaymentStatusPaid), want: PaymentStatusPaid},
+		{name: "card refunded", payments: payments(PaymentStatusPaid, PaymentStatusRefunded), want: PaymentStatusPaid},
+		{name: "all refunded", payments: payments(PaymentStatusFailed, PaymentStatusRefunded, PaymentStatusRefunded), want: PaymentStatusRefunded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, OrderPaymentStatus(tt.payments))
+		})
+	}
+}
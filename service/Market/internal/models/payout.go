@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+const (
+	PayoutMethodBankTransfer  = "bank_transfer"
+	PayoutMethodStripeConnect = "stripe_connect"
+
+	// PayoutStatusPending payouts are waiting to be sent, or to be retried
+	// after the provider refused them.
+	PayoutStatusPending = "pending"
+	PayoutStatusPaid    = "paid"
+	// PayoutStatusFailed payouts ran out of attempts; their statements are
+	// paid in a later payout.
+	PayoutStatusFailed = "failed"
+)
+
+// PayoutAccount is where the seller's payouts are sent. The account itself
+// is never returned, only its last four characters.
+type PayoutAccount struct {
+	SellerID    int       `json:"seller_id" db:"seller_id"`
+	Method      string    `json:"method" db:"method" example:"bank_transfer"`
+	HolderName  string    `json:"holder_name" db:"holder_name" example:"Café Shop GmbH"`
+	AccountHint string    `json:"account_hint" db:"account_hint" example:"3000"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SetPayoutAccountRequest registers the seller's payout account: an IBAN
+// for bank transfers or an acct_ ID for Stripe Connect.
+type SetPayoutAccountRequest struct {
+	Method     string `json:"method" binding:"required,oneof=bank_transfer stripe_connect"`
+	HolderName string `json:"holder_name" binding:"required,max=200"`
+	Account    string `json:"account" binding:"required,max=64" example:"DE89370400440532013000"`
+}
+
+// Payout is money sent to a seller for their unpaid statements.
+type Payout struct {
+	ID          int     `json:"id" db:"id"`
+	SellerID    int     `json:"seller_id" db:"seller_id"`
+	Amount      float64 `json:"amount" db:"amount"`
+	Method      string  `json:"method" db:"method" example:"bank_transfer"`
+	Provider    string  `json:"provider" db:"provider" example:"stripe"`
+	ProviderRef *string `json:"provider_ref,omitempty" db:"provider_ref" example:"tr_1Nq2w3"`
+	Status      string  `json:"status" db:"status" example:"paid"`
+	Attempts    int     `json:"attempts" db:"attempts"`
+	// LastError is why the provider refused the last attempt.
+	LastError     string     `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// SettlementDelays are how long after delivery a seller's sales become
+// payable, by the risk level of the product's category. Trusted sellers'
+// sales wait Trusted at most.
+type SettlementDelays struct {
+	Low      time.Duration
+	Standard time.Duration
+	High     time.Duration
+	Trusted  time.Duration
+}
+
+// DuePayout is a pending payout with the account it is sent to.
+type DuePayout struct {
+	Payout
+	Account    string `db:"account"`
+	HolderName string `db:"holder_name"`
+}
@@ -15,12 +15,20 @@ type Product struct {
 	Status      string    `json:"status" db:"status"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	// LowStockThreshold is the stock at or below which the seller is
+	// alerted; without one the site default applies.
+	LowStockThreshold *int `json:"low_stock_threshold,omitempty" db:"low_stock_threshold"`
+	Dimensions
 }
 
 type ProductWithDetails struct {
 	Product
-	SellerName   string `json:"seller_name" db:"seller_name"`
-	CategoryName string `json:"category_name" db:"category_name"`
+	// Rating is the average of the product's reviews, 0 without any.
+	Rating       float64 `json:"rating" db:"rating"`
+	ReviewCount  int     `json:"review_count" db:"review_count"`
+	SellerName   string  `json:"seller_name" db:"seller_name"`
+	SellerRating float64 `json:"seller_rating" db:"seller_rating"`
+	CategoryName string  `json:"category_name" db:"category_name"`
 }
 
 type CreateProductRequest struct {
@@ -28,9 +36,15 @@ type CreateProductRequest struct {
 	Title       string    `json:"title" binding:"required"`
 	Description string    `json:"description"`
 	Price       float64   `json:"price" binding:"required,gt=0"`
-	Stock       int       `json:"stock" binding:"required,gte=0"`
+	Stock       int       `json:"stock" binding:"gte=0"`
 	Sizes       SizesJSON `json:"sizes"`
 	ImageURL    string    `json:"image_url"`
+	// LowStockThreshold defaults to the site's.
+	LowStockThreshold *int `json:"low_stock_threshold" binding:"omitempty,gte=0"`
+	Dimensions
+	// Variants, when given, are sold instead of the product as a whole;
+	// Stock and Sizes then come from them.
+	Variants []CreateVariantRequest `json:"variants" binding:"omitempty,max=100,dive"`
 }
 
 type UpdateProductRequest struct {
@@ -41,5 +55,17 @@ type UpdateProductRequest struct {
 	Stock       *int       `json:"stock"`
 	Sizes       *SizesJSON `json:"sizes"`
 	ImageURL    *string    `json:"image_url"`
-	Status      *string    `json:"status"`
+	// Status is only changed by admins and moderation, never from a
+	// seller's request: a seller could publish a product awaiting
+	// moderation or lift a block.
+	Status *string `json:"-"`
+	// LowStockThreshold replaces the product's threshold.
+	LowStockThreshold *int `json:"low_stock_threshold" binding:"omitempty,gte=0"`
+	Dimensions
+}
+
+// TrendingProduct is a product ranked by recent views and purchases.
+type TrendingProduct struct {
+	ProductWithDetails
+	TrendingScore float64 `json:"trending_score"`
 }
@@ -0,0 +1,71 @@
+package models
+
+// ScrapedProduct is a product read from another shop's page, either by a
+// parser or by the seller's browser extension.
+type ScrapedProduct struct {
+	Title       string   `json:"title" binding:"required,max=255" example:"Wool beanie"`
+	Description string   `json:"description"`
+	Price       float64  `json:"price" binding:"gte=0" example:"19.99"`
+	Currency    string   `json:"currency,omitempty" example:"EUR"`
+	Images      []string `json:"images" binding:"max=20,dive,url"`
+}
+
+// ImportProductRequest imports a product from url, or from product when the
+// browser extension has already read the page.
+type ImportProductRequest struct {
+	CategoryID int             `json:"category_id" binding:"required"`
+	URL        string          `json:"url" binding:"required_without=Product,omitempty,url" example:"https://shop.example.com/p/beanie"`
+	Product    *ScrapedProduct `json:"product"`
+}
+
+// ProductImport is the draft product created by an import, with what was
+// read from the source page.
+type ProductImport struct {
+	Product   *Product `json:"product"`
+	SourceURL string   `json:"source_url,omitempty"`
+	// Parser is the parser that read the page, or "extension" when the
+	// browser extension sent the product.
+	Parser   string   `json:"parser" example:"generic"`
+	Currency string   `json:"currency,omitempty" example:"EUR"`
+	Images   []string `json:"images"`
+}
+
+// Catalog import row statuses.
+const (
+	CatalogRowCreated = "created"
+	CatalogRowUpdated = "updated"
+	CatalogRowFailed  = "failed"
+)
+
+// CatalogImport reports a bulk product import from a spreadsheet, row by
+// row. Rows are applied as they are read; failed rows change nothing.
+// Revision is the catalog as it was before the import.
+type CatalogImport struct {
+	Created  int                `json:"created"`
+	Updated  int                `json:"updated"`
+	Failed   int                `json:"failed"`
+	Rows     []CatalogImportRow `json:"rows"`
+	Revision *CatalogRevision   `json:"revision,omitempty"`
+}
+
+// CatalogImportRow is the outcome of one spreadsheet row. Line is the row
+// number in the file, counting the header.
+type CatalogImportRow struct {
+	Line      int      `json:"line" example:"2"`
+	ProductID int      `json:"product_id,omitempty"`
+	Status    string   `json:"status" example:"failed"`
+	Errors    []string `json:"errors,omitempty" example:"price: must be a positive number"`
+}
+
+// Add records a row's outcome.
+func (i *CatalogImport) Add(row CatalogImportRow) {
+	switch row.Status {
+	case CatalogRowCreated:
+		i.Created++
+	case CatalogRowUpdated:
+		i.Updated++
+	case CatalogRowFailed:
+		i.Failed++
+	}
+	i.Rows = append(i.Rows, row)
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Who funds a promotion code's discount.
+const (
+	PromoFundedByMarketplace = "marketplace"
+	PromoFundedBySeller      = "seller"
+)
+
+// PromoCode takes PercentOff off the order lines it applies to. Who funds
+// it decides whose revenue the discount comes out of: marketplace-funded
+// codes apply to every line and leave seller payouts whole, seller-funded
+// codes apply to SellerID's lines only and come out of that seller's
+// payout.
+type PromoCode struct {
+	ID         int        `json:"id" db:"id"`
+	Code       string     `json:"code" db:"code" example:"SPRING15"`
+	FundedBy   string     `json:"funded_by" db:"funded_by" example:"seller"`
+	SellerID   *int       `json:"seller_id,omitempty" db:"seller_id"`
+	PercentOff int        `json:"percent_off" db:"percent_off" example:"15"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	Active     bool       `json:"active" db:"active"`
+	CreatedBy  int        `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// AppliesTo reports whether the code discounts lines sold by the seller.
+func (p *PromoCode) AppliesTo(sellerID int) bool {
+	return p.FundedBy == PromoFundedByMarketplace || (p.SellerID != nil && *p.SellerID == sellerID)
+}
+
+// CreatePromoCodeRequest creates a promotion code. FundedBy and SellerID
+// are only read from admins; codes created by sellers are always funded by
+// their own shop.
+type CreatePromoCodeRequest struct {
+	Code       string     `json:"code" binding:"required,min=3,max=32,alphanum"`
+	PercentOff int        `json:"percent_off" binding:"required,gt=0,lte=100"`
+	FundedBy   string     `json:"funded_by" binding:"omitempty,oneof=marketplace seller"`
+	SellerID   *int       `json:"seller_id" binding:"omitempty,gt=0"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+const (
+	ReportTargetProduct = "product"
+	ReportTargetSeller  = "seller"
+	ReportTargetReview  = "review"
+
+	ReportSourceUser = "user"
+	ReportSourceAuto = "auto"
+
+	ReportReasonAutoModeration = "auto_moderation"
+
+	ReportStatusOpen     = "open"
+	ReportStatusResolved = "resolved"
+
+	ReportActionDismiss     = "dismiss"
+	ReportActionHideContent = "hide_content"
+	ReportActionWarnSeller  = "warn_seller"
+	ReportActionBanUser     = "ban_user"
+)
+
+type Report struct {
+	ID             int        `json:"id" db:"id"`
+	ReporterID     *int       `json:"reporter_id,omitempty" db:"reporter_id"`
+	Source         string     `json:"source" db:"source"`
+	TargetType     string     `json:"target_type" db:"target_type"`
+	TargetID       int        `json:"target_id" db:"target_id"`
+	Reason         string     `json:"reason" db:"reason"`
+	Details        string     `json:"details,omitempty" db:"details"`
+	Status         string     `json:"status" db:"status"`
+	Resolution     string     `json:"resolution,omitempty" db:"resolution"`
+	ResolutionNote string     `json:"resolution_note,omitempty" db:"resolution_note"`
+	ResolvedBy     *int       `json:"resolved_by,omitempty" db:"resolved_by"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty" db:"resolved_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+type CreateReportRequest struct {
+	TargetType string `json:"target_type" binding:"required,oneof=product seller"`
+	TargetID   int    `json:"target_id" binding:"required,gt=0"`
+	Reason     string `json:"reason" binding:"required,oneof=spam fraud counterfeit offensive prohibited other"`
+	Details    string `json:"details" binding:"max=2000"`
+}
+
+type ResolveReportRequest struct {
+	Action string `json:"action" binding:"required,oneof=dismiss hide_content warn_seller ban_user"`
+	Note   string `json:"note" binding:"max=2000"`
+}
+
+type Notification struct {
+	ID        int        `json:"id" db:"id"`
+	UserID    int        `json:"user_id" db:"user_id"`
+	Kind      string     `json:"kind" db:"kind"`
+	Message   string     `json:"message" db:"message"`
+	ReadAt    *time.Time `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
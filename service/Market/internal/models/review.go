@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+const (
+	ReviewStatusPublished = "published"
+	ReviewStatusPending   = "pending"
+	ReviewStatusHidden    = "hidden"
+)
+
+// Review is a buyer's rating of a product they received. A review whose
+// comment moderation held is pending until an admin publishes or hides it.
+type Review struct {
+	ID        int       `json:"id" db:"id"`
+	ProductID int       `json:"product_id" db:"product_id"`
+	UserID    int       `json:"user_id" db:"user_id"`
+	Rating    int       `json:"rating" db:"rating"`
+	Comment   string    `json:"comment,omitempty" db:"comment"`
+	Status    string    `json:"status" db:"status"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type CreateReviewRequest struct {
+	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
+	Comment string `json:"comment" binding:"max=2000"`
+}
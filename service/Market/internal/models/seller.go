@@ -3,14 +3,40 @@ package models
 import "time"
 
 type Seller struct {
-	ID          int       `json:"id" db:"id"`
-	UserID      int       `json:"user_id" db:"user_id"`
-	ShopName    string    `json:"shop_name" db:"shop_name"`
-	Description string    `json:"description" db:"description"`
-	Rating      float64   `json:"rating" db:"rating"`
-	IsActive    bool      `json:"is_active" db:"is_active"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID             int    `json:"id" db:"id"`
+	UserID         int    `json:"user_id" db:"user_id"`
+	ShopName       string `json:"shop_name" db:"shop_name"`
+	Description    string `json:"description" db:"description"`
+	ReturnPolicy   string `json:"return_policy" db:"return_policy"`
+	ShippingPolicy string `json:"shipping_policy" db:"shipping_policy"`
+	// HasPayoutDetails tells whether payout details are on file; the
+	// details themselves are never returned.
+	HasPayoutDetails bool    `json:"has_payout_details" db:"has_payout_details"`
+	Rating           float64 `json:"rating" db:"rating"`
+	IsActive         bool    `json:"is_active" db:"is_active"`
+	APIPlan          string  `json:"api_plan" db:"api_plan"`
+	// CancellationWindowMinutes overrides the site-wide buyer cancellation
+	// window for orders with this seller's items.
+	CancellationWindowMinutes *int `json:"cancellation_window_minutes,omitempty" db:"cancellation_window_minutes"`
+	// CustomDomain is the host name of the seller's own storefront, which
+	// only lists the seller's products.
+	CustomDomain string `json:"custom_domain,omitempty" db:"custom_domain"`
+	// ProbationOrders is how many delivered orders take the seller off
+	// probation; nil when the seller is not put on it.
+	ProbationOrders *int `json:"probation_orders,omitempty" db:"probation_orders"`
+	// ProbationTrafficPercent is the share of visitors shown the seller's
+	// products in listings while on probation.
+	ProbationTrafficPercent *int `json:"probation_traffic_percent,omitempty" db:"probation_traffic_percent"`
+	// OnProbation tells whether the seller has yet to deliver
+	// ProbationOrders orders.
+	OnProbation bool `json:"on_probation" db:"on_probation"`
+	// Trusted sellers' sales become payable sooner after delivery.
+	Trusted   bool      `json:"trusted" db:"trusted"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	// UserEmail is the owner's account email from the Auth service, filled
+	// in for admins when AUTH_GRPC_ADDR is set.
+	UserEmail string `json:"user_email,omitempty" db:"-"`
 }
 
 type CreateSellerRequest struct {
@@ -19,6 +45,100 @@ type CreateSellerRequest struct {
 }
 
 type UpdateSellerRequest struct {
-	ShopName    string `json:"shop_name"`
+	ShopName       string `json:"shop_name"`
+	Description    string `json:"description"`
+	ReturnPolicy   string `json:"return_policy" binding:"max=5000"`
+	ShippingPolicy string `json:"shipping_policy" binding:"max=5000"`
+	// PayoutDetails is the account payouts are sent to, such as an IBAN.
+	PayoutDetails string `json:"payout_details" binding:"max=255"`
+}
+
+// Seller onboarding steps, in the order sellers are asked to complete them.
+const (
+	OnboardingStepProfile  = "profile"
+	OnboardingStepKYC      = "kyc"
+	OnboardingStepProduct  = "first_product"
+	OnboardingStepPayout   = "payout_details"
+	OnboardingStepPolicies = "policies"
+)
+
+// SellerOnboardingState is what the onboarding checklist is computed from.
+type SellerOnboardingState struct {
+	HasProfile       bool `db:"has_profile"`
+	Verified         bool `db:"verified"`
+	HasProduct       bool `db:"has_product"`
+	HasPayoutDetails bool `db:"has_payout_details"`
+	HasPolicies      bool `db:"has_policies"`
+}
+
+type OnboardingStep struct {
+	Key         string `json:"key" example:"payout_details"`
+	Title       string `json:"title" example:"Add payout details"`
 	Description string `json:"description"`
+	Completed   bool   `json:"completed"`
+	// Link opens the page of the seller dashboard where the step is done.
+	Link string `json:"link" example:"https://market.example.com/seller/payouts"`
+}
+
+type SellerOnboarding struct {
+	Steps     []OnboardingStep `json:"steps"`
+	Completed int              `json:"completed" example:"3"`
+	Total     int              `json:"total" example:"5"`
+	Done      bool             `json:"done"`
+}
+
+// UpdateCancellationWindowRequest sets a seller's own cancellation window;
+// null minutes fall back to the site-wide setting.
+type UpdateCancellationWindowRequest struct {
+	Minutes *int `json:"minutes" binding:"omitempty,gte=0,lte=43200"`
+}
+
+// TrafficBuckets is how many groups visitors are split into when listing
+// the products of sellers on probation, so their traffic share goes in
+// steps of 100/TrafficBuckets percent.
+const TrafficBuckets = 10
+
+// SetSellerProbationRequest puts a seller on probation until they deliver
+// Orders orders, meanwhile listing their products to TrafficPercent of
+// visitors only; Orders 0 takes the seller off probation. Orders delivered
+// before count towards it.
+type SetSellerProbationRequest struct {
+	Orders         int `json:"orders" binding:"gte=0,lte=10000" example:"5"`
+	TrafficPercent int `json:"traffic_percent" binding:"gte=0,lte=100" example:"10"`
+}
+
+// SetSellerTrustedRequest marks a seller as trusted, releasing their
+// payouts early, or takes the mark away.
+type SetSellerTrustedRequest struct {
+	Trusted *bool `json:"trusted" binding:"required"`
+}
+
+// SetCustomDomainRequest maps a host name to the seller's storefront. The
+// host's DNS has to point at the marketplace.
+type SetCustomDomainRequest struct {
+	Domain string `json:"domain" binding:"required,fqdn,max=100" example:"shop.example.com"`
+}
+
+// SellerInvoicing is the legal entity a seller invoices as, printed on
+// their statements.
+type SellerInvoicing struct {
+	SellerID    int       `json:"seller_id" db:"seller_id"`
+	LegalName   string    `json:"legal_name" db:"legal_name" example:"Cafe Shop GmbH"`
+	Country     string    `json:"country" db:"country" example:"DE"`
+	TaxID       string    `json:"tax_id" db:"tax_id" example:"DE123456789"`
+	AddressLine string    `json:"address_line" db:"address_line" example:"Hauptstrasse 1"`
+	City        string    `json:"city" db:"city" example:"Berlin"`
+	PostalCode  string    `json:"postal_code" db:"postal_code" example:"10115"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UpdateSellerInvoicingRequest replaces a seller's invoicing details. The
+// tax ID and postal code must match the country's format.
+type UpdateSellerInvoicingRequest struct {
+	LegalName   string `json:"legal_name" binding:"required,max=255"`
+	Country     string `json:"country" binding:"required"`
+	TaxID       string `json:"tax_id" binding:"required,max=32"`
+	AddressLine string `json:"address_line" binding:"required,max=255"`
+	City        string `json:"city" binding:"required,max=100"`
+	PostalCode  string `json:"postal_code" binding:"required,max=16"`
 }
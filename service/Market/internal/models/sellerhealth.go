@@ -0,0 +1,92 @@
+package models
+
+import "time"
+
+// Seller health metrics a rule can watch.
+const (
+	HealthMetricScore            = "score"
+	HealthMetricCancellationRate = "cancellation_rate"
+	HealthMetricLateShipmentRate = "late_shipment_rate"
+	HealthMetricDisputeRate      = "dispute_rate"
+)
+
+// Actions taken on sellers breaching a health rule.
+const (
+	HealthActionWarn    = "warn"
+	HealthActionSuspend = "suspend"
+)
+
+// SellerHealthCounts are a seller's orders within the health window and how
+// many of them went wrong. An order counts once however many of the
+// seller's items it holds.
+type SellerHealthCounts struct {
+	SellerID  int  `db:"seller_id"`
+	UserID    int  `db:"user_id"`
+	IsActive  bool `db:"is_active"`
+	Orders    int  `db:"orders"`
+	Cancelled int  `db:"cancelled"`
+	Late      int  `db:"late"`
+	Disputed  int  `db:"disputed"`
+}
+
+// SellerHealth is a seller's performance over the last WindowDays days.
+// Rates are fractions of Orders; Score runs from 0 to 100.
+type SellerHealth struct {
+	SellerID         int     `json:"seller_id"`
+	WindowDays       int     `json:"window_days" example:"90"`
+	Orders           int     `json:"orders" example:"40"`
+	CancelledOrders  int     `json:"cancelled_orders" example:"2"`
+	LateOrders       int     `json:"late_orders" example:"3"`
+	DisputedOrders   int     `json:"disputed_orders" example:"1"`
+	CancellationRate float64 `json:"cancellation_rate" example:"0.05"`
+	LateShipmentRate float64 `json:"late_shipment_rate" example:"0.075"`
+	DisputeRate      float64 `json:"dispute_rate" example:"0.025"`
+	Score            float64 `json:"score" example:"75"`
+	// Breaches are the enabled rules the seller is currently breaching.
+	Breaches []HealthBreach `json:"breaches"`
+}
+
+type HealthBreach struct {
+	RuleID    int     `json:"rule_id"`
+	Metric    string  `json:"metric" example:"cancellation_rate"`
+	Value     float64 `json:"value" example:"0.05"`
+	Threshold float64 `json:"threshold" example:"0.04"`
+	Action    string  `json:"action" example:"warn"`
+}
+
+// SellerHealthRule warns or suspends sellers whose metric passes threshold:
+// rates breach above it, the score below it. Sellers with fewer than
+// MinOrders orders in the window are not judged.
+type SellerHealthRule struct {
+	ID        int       `json:"id" db:"id"`
+	Metric    string    `json:"metric" db:"metric" example:"cancellation_rate"`
+	Threshold float64   `json:"threshold" db:"threshold" example:"0.1"`
+	Action    string    `json:"action" db:"action" example:"suspend"`
+	MinOrders int       `json:"min_orders" db:"min_orders" example:"10"`
+	Enabled   bool      `json:"enabled" db:"enabled"`
+	CreatedBy *int      `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SellerHealthRuleRequest creates or replaces a rule. Rate thresholds are
+// fractions from 0 to 1; score thresholds run from 0 to 100.
+type SellerHealthRuleRequest struct {
+	Metric    string   `json:"metric" binding:"required,oneof=score cancellation_rate late_shipment_rate dispute_rate"`
+	Threshold *float64 `json:"threshold" binding:"required,gte=0,lte=100"`
+	Action    string   `json:"action" binding:"required,oneof=warn suspend"`
+	MinOrders *int     `json:"min_orders" binding:"omitempty,gte=0"`
+	Enabled   *bool    `json:"enabled"`
+}
+
+// SellerHealthAction records a warning or suspension applied by a rule.
+type SellerHealthAction struct {
+	ID        int       `json:"id" db:"id"`
+	SellerID  int       `json:"seller_id" db:"seller_id"`
+	RuleID    *int      `json:"rule_id,omitempty" db:"rule_id"`
+	Action    string    `json:"action" db:"action"`
+	Metric    string    `json:"metric" db:"metric"`
+	Value     float64   `json:"value" db:"value"`
+	Threshold float64   `json:"threshold" db:"threshold"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
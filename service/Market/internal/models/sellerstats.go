@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// SellerStatsQuery selects the days seller analytics cover, To included,
+// and the buckets revenue is reported in: day, week or month.
+type SellerStatsQuery struct {
+	From     *time.Time `form:"from" time_format:"2006-01-02"`
+	To       *time.Time `form:"to" time_format:"2006-01-02"`
+	Interval string     `form:"interval" binding:"omitempty,oneof=day week month"`
+}
+
+// SellerStats is a seller's sales over a range of days. Revenue is what the
+// seller sold for less the discounts they funded; cancelled and refunded
+// orders are left out.
+type SellerStats struct {
+	From     string          `json:"from" example:"2026-02-01"`
+	To       string          `json:"to" example:"2026-02-28"`
+	Interval string          `json:"interval" example:"day"`
+	Revenue  []*RevenuePoint `json:"revenue"`
+	Products []*ProductStats `json:"products"`
+	// LowStock lists the active products and variants with at most their
+	// product's threshold in stock, LowStockThreshold for products without
+	// one, emptiest first.
+	LowStock          []*LowStockAlert `json:"low_stock"`
+	LowStockThreshold int              `json:"low_stock_threshold"`
+}
+
+// RevenuePoint is one bucket of the revenue series, starting on Period.
+type RevenuePoint struct {
+	Period  string  `json:"period" db:"period" example:"2026-02-01"`
+	Orders  int     `json:"orders" db:"orders"`
+	Units   int     `json:"units" db:"units"`
+	Revenue float64 `json:"revenue" db:"revenue"`
+}
+
+// ProductStats is a product's sales and page views over the range.
+// Conversion is the share of views that led to an order.
+type ProductStats struct {
+	ProductID  int     `json:"product_id" db:"product_id"`
+	Title      string  `json:"title" db:"title"`
+	UnitsSold  int     `json:"units_sold" db:"units_sold"`
+	Orders     int     `json:"orders" db:"orders"`
+	Revenue    float64 `json:"revenue" db:"revenue"`
+	Views      int64   `json:"views" db:"views"`
+	Conversion float64 `json:"conversion" db:"conversion" example:"0.034"`
+}
+
+// LowStockAlert is a product, or a variant of one, running out of stock.
+type LowStockAlert struct {
+	ProductID int    `json:"product_id" db:"product_id"`
+	VariantID *int   `json:"variant_id,omitempty" db:"variant_id"`
+	Title     string `json:"title" db:"title"`
+	SKU       string `json:"sku,omitempty" db:"sku"`
+	Stock     int    `json:"stock" db:"stock"`
+	Threshold int    `json:"threshold" db:"threshold"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Setting is an admin override of a site-wide configuration key.
+type Setting struct {
+	Key       string    `json:"key" db:"key"`
+	Value     string    `json:"value" db:"value"`
+	UpdatedBy *int      `json:"updated_by,omitempty" db:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type UpdateSettingRequest struct {
+	Value string `json:"value" binding:"max=1000"`
+}
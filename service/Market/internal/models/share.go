@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// ShareLink is a short, trackable link to a product page.
+type ShareLink struct {
+	ID            int        `json:"id" db:"id"`
+	Code          string     `json:"code" db:"code"`
+	URL           string     `json:"url" db:"-"`
+	ProductID     int        `json:"product_id" db:"product_id"`
+	Source        string     `json:"source,omitempty" db:"source"`
+	Clicks        int64      `json:"clicks" db:"clicks"`
+	LastClickedAt *time.Time `json:"last_clicked_at,omitempty" db:"last_clicked_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
+
+// CreateShareLinkRequest names where the link is going to be posted, such
+// as "instagram" or "newsletter", so that clicks can be attributed.
+type CreateShareLinkRequest struct {
+	Source string `json:"source" binding:"omitempty,max=50"`
+}
+
+// ShareLinkStats aggregates the share links of one product and source.
+type ShareLinkStats struct {
+	ProductID     int        `json:"product_id" db:"product_id"`
+	ProductTitle  string     `json:"product_title" db:"product_title"`
+	Source        string     `json:"source" db:"source"`
+	Links         int64      `json:"links" db:"links"`
+	Clicks        int64      `json:"clicks" db:"clicks"`
+	LastClickedAt *time.Time `json:"last_clicked_at,omitempty" db:"last_clicked_at"`
+}
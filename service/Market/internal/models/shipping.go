@@ -0,0 +1,47 @@
+package models
+
+// ShippingRestrictions limits where a product can be delivered. Codes are
+// ISO 3166-1 alpha-2 countries ("DE") or ISO 3166-2 regions ("US-AK").
+// An empty ShipsTo means the product ships everywhere not in NoShipTo.
+type ShippingRestrictions struct {
+	ProductID int      `json:"product_id" db:"product_id"`
+	ShipsTo   []string `json:"ships_to" db:"ships_to"`
+	NoShipTo  []string `json:"no_ship_to" db:"no_ship_to"`
+}
+
+type UpdateShippingRestrictionsRequest struct {
+	ShipsTo  []string `json:"ships_to"`
+	NoShipTo []string `json:"no_ship_to"`
+}
+
+// BlockedItem is a cart item that cannot be shipped to the requested address.
+type BlockedItem struct {
+	ProductID    int    `json:"product_id"`
+	ProductTitle string `json:"product_title"`
+	Reason       string `json:"reason"`
+}
+
+// Dimensions are the shipping weight and size of one unit of a product or
+// variant, nil when the seller has not set them. A variant's override its
+// product's.
+type Dimensions struct {
+	WeightGrams *int `json:"weight_grams,omitempty" db:"weight_grams" binding:"omitempty,gt=0,lte=1000000" example:"450"`
+	LengthMM    *int `json:"length_mm,omitempty" db:"length_mm" binding:"omitempty,gt=0,lte=10000" example:"300"`
+	WidthMM     *int `json:"width_mm,omitempty" db:"width_mm" binding:"omitempty,gt=0,lte=10000" example:"200"`
+	HeightMM    *int `json:"height_mm,omitempty" db:"height_mm" binding:"omitempty,gt=0,lte=10000" example:"40"`
+}
+
+// ShippingPackage is the parcel a cart ships in: the weight of all its
+// items and a box they fit in. Complete is false when some items have no
+// weight, which is then missing from WeightGrams. Oversize lists the items
+// too large for the carrier on their own and Exceeds says why the parcel
+// as a whole is; checkout is rejected while either is set.
+type ShippingPackage struct {
+	WeightGrams int           `json:"weight_grams"`
+	LengthMM    int           `json:"length_mm"`
+	WidthMM     int           `json:"width_mm"`
+	HeightMM    int           `json:"height_mm"`
+	Complete    bool          `json:"complete"`
+	Oversize    []BlockedItem `json:"oversize,omitempty"`
+	Exceeds     string        `json:"exceeds,omitempty"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// SellerStatement summarizes a seller's month. Payout is what the seller is
+// owed: gross sales minus the discounts the seller funded, refunds,
+// commission and chargebacks. Chargebacks are the payout holds taken in
+// the month less those released in it. Marketplace-funded discounts do not
+// reduce it. PayoutID is the payout that pays it, once it is batched.
+type SellerStatement struct {
+	ID       int    `json:"id" db:"id"`
+	SellerID int    `json:"seller_id" db:"seller_id"`
+	ShopName string `json:"shop_name" db:"shop_name"`
+	// LegalName, TaxID and InvoiceAddress are the seller's invoicing
+	// details, empty until the seller has filed them.
+	LegalName      string    `json:"legal_name,omitempty" db:"legal_name"`
+	TaxID          string    `json:"tax_id,omitempty" db:"tax_id"`
+	InvoiceAddress string    `json:"invoice_address,omitempty" db:"invoice_address"`
+	Period         string    `json:"period" db:"period"` // YYYY-MM
+	OrdersCount    int       `json:"orders_count" db:"orders_count"`
+	ItemsSold      int       `json:"items_sold" db:"items_sold"`
+	GrossSales     float64   `json:"gross_sales" db:"gross_sales"`
+	Discounts      float64   `json:"discounts" db:"discounts"`
+	Commission     float64   `json:"commission" db:"commission"`
+	Refunds        float64   `json:"refunds" db:"refunds"`
+	Chargebacks    float64   `json:"chargebacks" db:"chargebacks"`
+	Payout         float64   `json:"payout" db:"payout"`
+	PayoutID       *int      `json:"payout_id,omitempty" db:"payout_id"`
+	GeneratedAt    time.Time `json:"generated_at" db:"generated_at"`
+}
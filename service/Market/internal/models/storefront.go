@@ -0,0 +1,93 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// DefaultStorefrontTenant holds the settings used for hosts without their
+// own storefront settings.
+const DefaultStorefrontTenant = "default"
+
+// FooterLink is a link shown in the storefront footer.
+type FooterLink struct {
+	Label string `json:"label" binding:"required,max=100"`
+	URL   string `json:"url" binding:"required,url,max=500"`
+}
+
+type FooterLinksJSON []FooterLink
+
+func (l FooterLinksJSON) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+func (l *FooterLinksJSON) Scan(value interface{}) error {
+	if value == nil {
+		*l = FooterLinksJSON{}
+		return nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return errors.New("failed to unmarshal JSONB value")
+		}
+		return json.Unmarshal(b, l)
+	}
+}
+
+// StorefrontSettings is the branding a white-label storefront renders:
+// logo, colors, contact details, footer links and currency/locale defaults.
+type StorefrontSettings struct {
+	Tenant          string          `json:"tenant" db:"tenant"`
+	Name            string          `json:"name" db:"name"`
+	LogoURL         string          `json:"logo_url" db:"logo_url"`
+	PrimaryColor    string          `json:"primary_color" db:"primary_color"`
+	SecondaryColor  string          `json:"secondary_color" db:"secondary_color"`
+	AccentColor     string          `json:"accent_color" db:"accent_color"`
+	ContactEmail    string          `json:"contact_email" db:"contact_email"`
+	ContactPhone    string          `json:"contact_phone" db:"contact_phone"`
+	ContactAddress  string          `json:"contact_address" db:"contact_address"`
+	FooterLinks     FooterLinksJSON `json:"footer_links" db:"footer_links"`
+	DefaultCurrency string          `json:"default_currency" db:"default_currency"`
+	DefaultLocale   string          `json:"default_locale" db:"default_locale"`
+	UpdatedBy       *int            `json:"updated_by,omitempty" db:"updated_by"`
+	UpdatedAt       *time.Time      `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// DefaultStorefrontSettings is served until an admin configures the
+// default storefront.
+func DefaultStorefrontSettings() *StorefrontSettings {
+	return &StorefrontSettings{
+		Tenant:          DefaultStorefrontTenant,
+		Name:            "Marketback",
+		FooterLinks:     FooterLinksJSON{},
+		DefaultCurrency: "USD",
+		DefaultLocale:   "en-US",
+	}
+}
+
+// UpdateStorefrontSettingsRequest replaces all settings of a tenant.
+// Colors are hex codes such as "#1a73e8".
+type UpdateStorefrontSettingsRequest struct {
+	Name            string       `json:"name" binding:"max=100"`
+	LogoURL         string       `json:"logo_url" binding:"omitempty,url,max=500"`
+	PrimaryColor    string       `json:"primary_color" binding:"omitempty,hexcolor,max=7"`
+	SecondaryColor  string       `json:"secondary_color" binding:"omitempty,hexcolor,max=7"`
+	AccentColor     string       `json:"accent_color" binding:"omitempty,hexcolor,max=7"`
+	ContactEmail    string       `json:"contact_email" binding:"omitempty,email,max=255"`
+	ContactPhone    string       `json:"contact_phone" binding:"max=50" example:"+4930123456"`
+	ContactAddress  string       `json:"contact_address" binding:"max=500"`
+	FooterLinks     []FooterLink `json:"footer_links" binding:"max=20,dive"`
+	DefaultCurrency string       `json:"default_currency" binding:"required,iso4217"`
+	DefaultLocale   string       `json:"default_locale" binding:"required,bcp47_language_tag,max=35"`
+}
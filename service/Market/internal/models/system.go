@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// States of a background worker.
+const (
+	WorkerRunning = "running"
+	WorkerStalled = "stalled"
+	WorkerStopped = "stopped"
+)
+
+// SystemStatus is what ops look at during an incident: database pool and
+// replication, Redis memory, the event outbox and export job backlogs and
+// whether the background workers of this instance still run.
+type SystemStatus struct {
+	Status      string          `json:"status"`
+	Timestamp   time.Time       `json:"timestamp"`
+	DBPool      *DBPoolStatus   `json:"db_pool,omitempty"`
+	Replication []ReplicaStatus `json:"replication"`
+	// InRecovery is set when the database is itself a replica.
+	InRecovery bool               `json:"in_recovery"`
+	Redis      RedisStatus        `json:"redis"`
+	Outbox     *OutboxStatus      `json:"outbox,omitempty"`
+	Exports    *ExportQueueStatus `json:"exports,omitempty"`
+	Workers    []WorkerStatus     `json:"workers"`
+	Errors     map[string]string  `json:"errors,omitempty"`
+}
+
+type DBPoolStatus struct {
+	AcquiredConns     int32   `json:"acquired_conns"`
+	IdleConns         int32   `json:"idle_conns"`
+	TotalConns        int32   `json:"total_conns"`
+	MaxConns          int32   `json:"max_conns"`
+	EmptyAcquireCount int64   `json:"empty_acquire_count"`
+	AvgAcquireMs      float64 `json:"avg_acquire_ms"`
+}
+
+// ReplicaStatus is a streaming replica of the database as the primary
+// sees it; lags are nil when the database user may not read them.
+type ReplicaStatus struct {
+	Name             string   `json:"name" db:"name"`
+	State            string   `json:"state" db:"state"`
+	ReplayLagSeconds *float64 `json:"replay_lag_seconds" db:"replay_lag_seconds"`
+	ReplayLagBytes   *int64   `json:"replay_lag_bytes" db:"replay_lag_bytes"`
+}
+
+type RedisStatus struct {
+	Status          string `json:"status"`
+	UsedMemoryBytes int64  `json:"used_memory_bytes,omitempty"`
+	PeakMemoryBytes int64  `json:"peak_memory_bytes,omitempty"`
+	// MaxMemoryBytes is 0 when Redis has no memory limit.
+	MaxMemoryBytes int64 `json:"max_memory_bytes"`
+}
+
+// OutboxStatus is the backlog of domain events not published yet.
+type OutboxStatus struct {
+	Pending         int64      `json:"pending" db:"pending"`
+	Failing         int64      `json:"failing" db:"failing"`
+	OldestPendingAt *time.Time `json:"oldest_pending_at" db:"oldest_pending_at"`
+}
+
+// ExportQueueStatus is the backlog of background product exports.
+type ExportQueueStatus struct {
+	Queued         int64      `json:"queued" db:"queued"`
+	Running        int64      `json:"running" db:"running"`
+	OldestQueuedAt *time.Time `json:"oldest_queued_at" db:"oldest_queued_at"`
+}
+
+type WorkerStatus struct {
+	Name      string     `json:"name"`
+	State     string     `json:"state"`
+	Interval  string     `json:"interval"`
+	StartedAt time.Time  `json:"started_at"`
+	LastRunAt *time.Time `json:"last_run_at"`
+}
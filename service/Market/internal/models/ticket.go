@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+const (
+	// TicketStatusOpen tickets are waiting for support.
+	TicketStatusOpen = "open"
+	// TicketStatusPending tickets are waiting for the requester.
+	TicketStatusPending  = "pending"
+	TicketStatusResolved = "resolved"
+	TicketStatusClosed   = "closed"
+
+	MaxTicketAttachments = 5
+)
+
+type Ticket struct {
+	ID        int              `json:"id" db:"id"`
+	UserID    int              `json:"user_id" db:"user_id"`
+	UserRole  string           `json:"user_role" db:"user_role"`
+	UserEmail string           `json:"-" db:"user_email"`
+	Category  string           `json:"category" db:"category"`
+	Subject   string           `json:"subject" db:"subject"`
+	OrderID   *int             `json:"order_id,omitempty" db:"order_id"`
+	Status    string           `json:"status" db:"status"`
+	Messages  []*TicketMessage `json:"messages,omitempty" db:"-"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+type TicketMessage struct {
+	ID          int       `json:"id" db:"id"`
+	TicketID    int       `json:"ticket_id" db:"ticket_id"`
+	AuthorID    int       `json:"author_id" db:"author_id"`
+	AuthorRole  string    `json:"author_role" db:"author_role"`
+	Body        string    `json:"body" db:"body"`
+	Attachments []string  `json:"attachments" db:"attachments"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreateTicketRequest is sent as multipart form data so that attachments can
+// travel with the first message.
+type CreateTicketRequest struct {
+	Category string `form:"category" binding:"required,oneof=order payment delivery account product other"`
+	Subject  string `form:"subject" binding:"required,max=200"`
+	Message  string `form:"message" binding:"required,max=5000"`
+	OrderID  *int   `form:"order_id" binding:"omitempty,gt=0"`
+}
+
+type TicketReplyRequest struct {
+	Message string `form:"message" binding:"required,max=5000"`
+	// Status optionally moves the ticket along with an admin reply.
+	Status string `form:"status" binding:"omitempty,oneof=open pending resolved closed"`
+}
+
+type UpdateTicketStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=open pending resolved closed"`
+}
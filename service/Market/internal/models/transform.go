@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Targets of transform rules.
+const (
+	TransformTargetEvent        = "event"
+	TransformTargetNotification = "notification"
+)
+
+// TransformRule is a JSON Patch (RFC 6902) an admin applies to outbound
+// payloads: domain events sent to the broker, whose type Match names, or
+// in-app notifications, whose kind it names; "*" matches all. With a
+// SellerID it only applies to events about that seller (their seller_id)
+// and to notifications of the seller's owner.
+type TransformRule struct {
+	ID          int             `json:"id" db:"id"`
+	SellerID    *int            `json:"seller_id,omitempty" db:"seller_id"`
+	Target      string          `json:"target" db:"target" example:"event"`
+	Match       string          `json:"match" db:"match" example:"ProductStockLow"`
+	Description string          `json:"description" db:"description"`
+	Patch       json.RawMessage `json:"patch" db:"patch" swaggertype:"array,object"`
+	Enabled     bool            `json:"enabled" db:"enabled"`
+	CreatedBy   int             `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+	// SellerUserID is the owner of the rule's seller, whose notifications
+	// it applies to.
+	SellerUserID *int `json:"-" db:"seller_user_id"`
+}
+
+type CreateTransformRuleRequest struct {
+	SellerID    *int            `json:"seller_id" binding:"omitempty,gt=0"`
+	Target      string          `json:"target" binding:"required,oneof=event notification"`
+	Match       string          `json:"match" binding:"required,max=100" example:"ProductStockLow"`
+	Description string          `json:"description" binding:"max=500"`
+	Patch       json.RawMessage `json:"patch" binding:"required" swaggertype:"array,object"`
+	// Enabled defaults to true.
+	Enabled *bool `json:"enabled"`
+}
+
+type UpdateTransformRuleRequest struct {
+	Match       *string         `json:"match" binding:"omitempty,min=1,max=100"`
+	Description *string         `json:"description" binding:"omitempty,max=500"`
+	Patch       json.RawMessage `json:"patch" swaggertype:"array,object"`
+	Enabled     *bool           `json:"enabled"`
+}
+
+// PreviewTransformRequest applies a patch to a sample document without
+// saving anything.
+type PreviewTransformRequest struct {
+	Patch    json.RawMessage `json:"patch" binding:"required" swaggertype:"array,object"`
+	Document json.RawMessage `json:"document" binding:"required" swaggertype:"object"`
+}
+
+// PreviewTransformResponse is the patched document, or why the patch did
+// not apply.
+type PreviewTransformResponse struct {
+	Applied  bool            `json:"applied"`
+	Document json.RawMessage `json:"document" swaggertype:"object"`
+	Error    string          `json:"error,omitempty"`
+}
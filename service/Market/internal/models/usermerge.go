@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// MergeUsersRequest merges a duplicate account (source) into the account
+// that is kept (target).
+type MergeUsersRequest struct {
+	SourceUserID int `json:"source_user_id" binding:"required,gt=0"`
+	TargetUserID int `json:"target_user_id" binding:"required,gt=0"`
+}
+
+// UserMerge is the audit record of a merge with the number of rows moved to
+// the target user.
+type UserMerge struct {
+	ID             int       `json:"id" db:"id"`
+	SourceUserID   int       `json:"source_user_id" db:"source_user_id"`
+	TargetUserID   int       `json:"target_user_id" db:"target_user_id"`
+	MergedBy       int       `json:"merged_by" db:"merged_by"`
+	Orders         int64     `json:"orders" db:"orders"`
+	CartItems      int64     `json:"cart_items" db:"cart_items"`
+	SellerMoved    bool      `json:"seller_moved" db:"seller_moved"`
+	Tickets        int64     `json:"tickets" db:"tickets"`
+	Notifications  int64     `json:"notifications" db:"notifications"`
+	Reports        int64     `json:"reports" db:"reports"`
+	ShareLinks     int64     `json:"share_links" db:"share_links"`
+	Reviews        int64     `json:"reviews" db:"reviews"`
+	StoreCredit    float64   `json:"store_credit" db:"store_credit"`
+	PaymentMethods int64     `json:"payment_methods" db:"payment_methods"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
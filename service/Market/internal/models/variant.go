@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// ProductVariant is one size/color combination of a product with its own
+// SKU and stock. It sells at the product's price plus PriceDelta.
+type ProductVariant struct {
+	ID         int       `json:"id" db:"id"`
+	ProductID  int       `json:"product_id" db:"product_id"`
+	SKU        string    `json:"sku" db:"sku"`
+	Size       string    `json:"size" db:"size"`
+	Color      string    `json:"color" db:"color"`
+	PriceDelta float64   `json:"price_delta" db:"price_delta"`
+	Stock      int       `json:"stock" db:"stock"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	Dimensions
+}
+
+type CreateVariantRequest struct {
+	SKU        string  `json:"sku" binding:"required,max=64"`
+	Size       string  `json:"size" binding:"max=50"`
+	Color      string  `json:"color" binding:"max=50"`
+	PriceDelta float64 `json:"price_delta"`
+	Stock      int     `json:"stock" binding:"gte=0"`
+	Dimensions
+}
+
+type UpdateVariantRequest struct {
+	SKU        *string  `json:"sku" binding:"omitempty,min=1,max=64"`
+	Size       *string  `json:"size" binding:"omitempty,max=50"`
+	Color      *string  `json:"color" binding:"omitempty,max=50"`
+	PriceDelta *float64 `json:"price_delta"`
+	Stock      *int     `json:"stock" binding:"omitempty,gte=0"`
+	Dimensions
+}
@@ -0,0 +1,86 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// APIChecker calls an OpenAI-compatible moderation endpoint
+// (POST {"input": text} -> {"results": [{"flagged": bool, "categories": {...}}]}).
+type APIChecker struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+func NewAPIChecker(url, apiKey string, timeout time.Duration) *APIChecker {
+	return &APIChecker{
+		url:    url,
+		apiKey: apiKey,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (a *APIChecker) Name() string { return "api" }
+
+type apiResponse struct {
+	Results []struct {
+		Flagged    bool            `json:"flagged"`
+		Categories map[string]bool `json:"categories"`
+	} `json:"results"`
+}
+
+func (a *APIChecker) Check(ctx context.Context, text string) ([]string, error) {
+	body, err := json.Marshal(map[string]string{"input": text})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if a.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+a.apiKey)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("moderation request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("moderation API returned %d", resp.StatusCode)
+	}
+
+	var out apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode moderation response: %w", err)
+	}
+
+	var reasons []string
+	for _, r := range out.Results {
+		if !r.Flagged {
+			continue
+		}
+		var cats []string
+		for name, hit := range r.Categories {
+			if hit {
+				cats = append(cats, name)
+			}
+		}
+		sort.Strings(cats)
+		if len(cats) == 0 {
+			cats = []string{"flagged"}
+		}
+		reasons = append(reasons, cats...)
+	}
+	return reasons, nil
+}
@@ -0,0 +1,46 @@
+package moderation
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// BannedWords holds content containing any of the configured words or
+// phrases, matched case-insensitively on word boundaries.
+type BannedWords struct {
+	words []string
+}
+
+// NewBannedWords ignores empty entries. It returns nil when no words remain.
+func NewBannedWords(words []string) *BannedWords {
+	var clean []string
+	for _, w := range words {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			clean = append(clean, w)
+		}
+	}
+	if len(clean) == 0 {
+		return nil
+	}
+	return &BannedWords{words: clean}
+}
+
+func (b *BannedWords) Name() string { return "banned_words" }
+
+func (b *BannedWords) Check(_ context.Context, text string) ([]string, error) {
+	normalized := " " + strings.Join(strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}), " ") + " "
+
+	var found []string
+	for _, w := range b.words {
+		phrase := " " + strings.Join(strings.FieldsFunc(w, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		}), " ") + " "
+		if strings.Contains(normalized, phrase) {
+			found = append(found, "contains \""+w+"\"")
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,77 @@
+// Package moderation screens user-generated text before it is published.
+// Checkers are pluggable; a Pipeline runs all of them and decides whether
+// the content must be held for manual review.
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+)
+
+// Checker inspects text and returns the reasons it should be held, or none
+// when the text is acceptable.
+type Checker interface {
+	Name() string
+	Check(ctx context.Context, text string) ([]string, error)
+}
+
+// Result is the combined verdict of a Pipeline.
+type Result struct {
+	Held    bool
+	Reasons []string
+}
+
+// Pipeline runs checkers in order. A nil *Pipeline holds nothing.
+type Pipeline struct {
+	checkers   []Checker
+	failClosed bool
+}
+
+// NewPipeline builds a pipeline. With failClosed a checker error holds the
+// content; otherwise errors are logged and the checker is skipped.
+func NewPipeline(failClosed bool, checkers ...Checker) *Pipeline {
+	return &Pipeline{checkers: checkers, failClosed: failClosed}
+}
+
+// Review checks the concatenation of texts with every checker.
+func (p *Pipeline) Review(ctx context.Context, texts ...string) Result {
+	if p == nil || len(p.checkers) == 0 {
+		return Result{}
+	}
+
+	text := strings.TrimSpace(strings.Join(texts, "\n"))
+	if text == "" {
+		return Result{}
+	}
+
+	var res Result
+	for _, c := range p.checkers {
+		reasons, err := c.Check(ctx, text)
+		if err != nil {
+			metrics.ModerationChecksTotal.WithLabelValues(c.Name(), "error").Inc()
+			logger.FromContext(ctx).WithFields(map[string]interface{}{
+				"err":     err,
+				"checker": c.Name(),
+			}).Warn("moderation checker failed")
+			if p.failClosed {
+				res.Reasons = append(res.Reasons, fmt.Sprintf("%s: unavailable", c.Name()))
+			}
+			continue
+		}
+		if len(reasons) == 0 {
+			metrics.ModerationChecksTotal.WithLabelValues(c.Name(), "pass").Inc()
+			continue
+		}
+		metrics.ModerationChecksTotal.WithLabelValues(c.Name(), "hold").Inc()
+		for _, r := range reasons {
+			res.Reasons = append(res.Reasons, fmt.Sprintf("%s: %s", c.Name(), r))
+		}
+	}
+
+	res.Held = len(res.Reasons) > 0
+	return res
+}
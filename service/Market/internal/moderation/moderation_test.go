@@ -0,0 +1,96 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingChecker struct{}
+
+func (failingChecker) Name() string { return "broken" }
+func (failingChecker) Check(context.Context, string) ([]string, error) {
+	return nil, errors.New("timeout")
+}
+
+func TestBannedWords(t *testing.T) {
+	b := NewBannedWords([]string{" Replica ", "", "fake rolex"})
+	require.NotNil(t, b)
+
+	reasons, err := b.Check(context.Background(), "Genuine FAKE   Rolex watch!")
+	require.NoError(t, err)
+	assert.Equal(t, []string{`contains "fake rolex"`}, reasons)
+
+	// Substrings of longer words do not match.
+	reasons, err = b.Check(context.Background(), "Replicated cells")
+	require.NoError(t, err)
+	assert.Empty(t, reasons)
+
+	assert.Nil(t, NewBannedWords([]string{" ", ""}))
+}
+
+func TestPipelineReview(t *testing.T) {
+	var nilPipeline *Pipeline
+	assert.False(t, nilPipeline.Review(context.Background(), "anything").Held)
+
+	p := NewPipeline(false, NewBannedWords([]string{"replica"}))
+	res := p.Review(context.Background(), "Leather bag", "High quality replica")
+	assert.True(t, res.Held)
+	assert.Equal(t, []string{`banned_words: contains "replica"`}, res.Reasons)
+
+	assert.False(t, p.Review(context.Background(), "Leather bag").Held)
+}
+
+func TestPipelineFailureModes(t *testing.T) {
+	open := NewPipeline(false, failingChecker{})
+	assert.False(t, open.Review(context.Background(), "text").Held)
+
+	closed := NewPipeline(true, failingChecker{})
+	res := closed.Review(context.Background(), "text")
+	assert.True(t, res.Held)
+	assert.Equal(t, []string{"broken: unavailable"}, res.Reasons)
+}
+
+func TestAPIChecker(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var in map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+
+		flagged := in["input"] == "bad"
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"results": []map[string]interface{}{{
+				"flagged":    flagged,
+				"categories": map[string]bool{"violence": flagged, "hate": flagged, "sexual": false},
+			}},
+		})
+	}))
+	defer srv.Close()
+
+	c := NewAPIChecker(srv.URL, "secret", time.Second)
+
+	reasons, err := c.Check(context.Background(), "bad")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hate", "violence"}, reasons)
+
+	reasons, err = c.Check(context.Background(), "fine")
+	require.NoError(t, err)
+	assert.Empty(t, reasons)
+}
+
+func TestAPICheckerHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	_, err := NewAPIChecker(srv.URL, "", time.Second).Check(context.Background(), "x")
+	assert.Error(t, err)
+}
@@ -0,0 +1,155 @@
+// Package money does order arithmetic in integer cents, so line totals add
+// up to the order total exactly, and checks the invariants checkout and
+// refunds rely on. Prices stay float64 in the models and the database uses
+// DECIMAL(10, 2); convert at the edges with FromFloat and Float.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvariant is wrapped by every invariant violation.
+var ErrInvariant = errors.New("money invariant violated")
+
+// MaxQuantity bounds a single line's quantity.
+const MaxQuantity = 1_000_000
+
+// Cents is an amount in minor currency units.
+type Cents int64
+
+// FromFloat rounds a decimal amount to the nearest cent, halves away from
+// zero.
+func FromFloat(v float64) Cents {
+	return Cents(math.Round(v * 100))
+}
+
+// Float returns the amount in major units, as stored in the models.
+func (c Cents) Float() float64 {
+	return float64(c) / 100
+}
+
+func (c Cents) String() string {
+	sign := ""
+	if c < 0 {
+		sign, c = "-", -c
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, c/100, c%100)
+}
+
+// Line is one order line: a unit price times a quantity, the discount
+// taken off it and the marketplace commission taken from it.
+type Line struct {
+	Price      Cents
+	Quantity   int
+	Discount   Cents
+	Commission Cents
+}
+
+// LineTotal returns price × quantity. Negative prices, quantities outside
+// 1..MaxQuantity and overflow are errors.
+func LineTotal(price Cents, quantity int) (Cents, error) {
+	if price < 0 {
+		return 0, fmt.Errorf("%w: negative price %s", ErrInvariant, price)
+	}
+	if quantity <= 0 || quantity > MaxQuantity {
+		return 0, fmt.Errorf("%w: quantity %d out of range", ErrInvariant, quantity)
+	}
+	if price > math.MaxInt64/Cents(quantity) {
+		return 0, fmt.Errorf("%w: %s × %d overflows", ErrInvariant, price, quantity)
+	}
+	return price * Cents(quantity), nil
+}
+
+// Total sums the line totals less their discounts. A negative discount or
+// one exceeding its line is an error.
+func Total(lines []Line) (Cents, error) {
+	var total Cents
+	for _, l := range lines {
+		lt, err := LineTotal(l.Price, l.Quantity)
+		if err != nil {
+			return 0, err
+		}
+		if l.Discount < 0 || l.Discount > lt {
+			return 0, fmt.Errorf("%w: discount %s outside line total %s", ErrInvariant, l.Discount, lt)
+		}
+		lt -= l.Discount
+		if total > math.MaxInt64-lt {
+			return 0, fmt.Errorf("%w: order total overflows", ErrInvariant)
+		}
+		total += lt
+	}
+	return total, nil
+}
+
+// Commission returns rate (0..1) of amount, rounded to the nearest cent.
+func Commission(amount Cents, rate float64) Cents {
+	return Cents(math.Round(float64(amount) * rate))
+}
+
+// PercentOff returns percent (0..100) of amount, rounded to the nearest
+// cent.
+func PercentOff(amount Cents, percent int) Cents {
+	return Cents(math.Round(float64(amount) * float64(percent) / 100))
+}
+
+// Split shares amount out in proportion to weights, in cents that add up
+// to amount exactly: the cents lost to rounding go to the last share.
+// Without a positive weight it all goes to the last share.
+func Split(amount Cents, weights []Cents) []Cents {
+	shares := make([]Cents, len(weights))
+	if len(weights) == 0 {
+		return shares
+	}
+	var total Cents
+	for _, w := range weights {
+		total += max(w, 0)
+	}
+	var given Cents
+	for i, w := range weights[:len(weights)-1] {
+		if total > 0 {
+			shares[i] = Cents(math.Floor(float64(amount) * float64(max(w, 0)) / float64(total)))
+		}
+		given += shares[i]
+	}
+	shares[len(shares)-1] = amount - given
+	return shares
+}
+
+// CheckOrder verifies that total is non-negative and equal to the sum of
+// the discounted line totals, and that no commission is negative or exceeds its line.
+func CheckOrder(lines []Line, total Cents) error {
+	if total < 0 {
+		return fmt.Errorf("%w: negative order total %s", ErrInvariant, total)
+	}
+	sum, err := Total(lines)
+	if err != nil {
+		return err
+	}
+	if sum != total {
+		return fmt.Errorf("%w: order total %s differs from sum of items %s", ErrInvariant, total, sum)
+	}
+	for _, l := range lines {
+		lt, _ := LineTotal(l.Price, l.Quantity)
+		if l.Commission < 0 || l.Commission > lt {
+			return fmt.Errorf("%w: commission %s outside line total %s", ErrInvariant, l.Commission, lt)
+		}
+	}
+	return nil
+}
+
+// CheckRefund verifies that a refund is positive and that together with
+// what was already refunded it does not exceed the paid amount.
+func CheckRefund(paid, refunded, amount Cents) error {
+	if amount <= 0 {
+		return fmt.Errorf("%w: refund %s must be positive", ErrInvariant, amount)
+	}
+	if refunded < 0 || refunded > paid {
+		return fmt.Errorf("%w: already refunded %s of %s", ErrInvariant, refunded, paid)
+	}
+	if amount > paid-refunded {
+		return fmt.Errorf("%w: refund %s exceeds refundable %s", ErrInvariant, amount, paid-refunded)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package money
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// order is a random but valid order for quick.Check.
+type order struct {
+	Lines []Line
+	Rate  float64
+}
+
+func (order) Generate(r *rand.Rand, size int) reflect.Value {
+	o := order{Rate: r.Float64() * 0.3}
+	for i := 0; i < 1+r.Intn(size+1); i++ {
+		o.Lines = append(o.Lines, Line{
+			Price:    Cents(r.Int63n(1_000_000_00)),
+			Quantity: 1 + r.Intn(1000),
+		})
+	}
+	return reflect.ValueOf(o)
+}
+
+func TestProperty_TotalsAreNonNegativeAndAddUp(t *testing.T) {
+	f := func(o order) bool {
+		total, err := Total(o.Lines)
+		if err != nil || total < 0 {
+			return false
+		}
+		var sum Cents
+		for i := range o.Lines {
+			lt, err := LineTotal(o.Lines[i].Price, o.Lines[i].Quantity)
+			if err != nil {
+				return false
+			}
+			o.Lines[i].Commission = Commission(lt, o.Rate)
+			sum += lt
+		}
+		return sum == total && CheckOrder(o.Lines, total) == nil
+	}
+	require.NoError(t, quick.Check(f, nil))
+}
+
+func TestProperty_WrongTotalIsRejected(t *testing.T) {
+	f := func(o order, delta int16) bool {
+		if delta == 0 {
+			return true
+		}
+		total, err := Total(o.Lines)
+		if err != nil {
+			return false
+		}
+		return errors.Is(CheckOrder(o.Lines, total+Cents(delta)), ErrInvariant)
+	}
+	require.NoError(t, quick.Check(f, nil))
+}
+
+func TestProperty_RefundsNeverExceedPaid(t *testing.T) {
+	f := func(paid uint32, refunds []uint32) bool {
+		var refunded Cents
+		for _, r := range refunds {
+			// Attempts range up to twice the paid amount
+			amount := Cents(uint64(r) % (2*uint64(paid) + 2))
+			if CheckRefund(Cents(paid), refunded, amount) == nil {
+				refunded += amount
+			}
+			if refunded > Cents(paid) {
+				return false
+			}
+		}
+		return true
+	}
+	require.NoError(t, quick.Check(f, nil))
+}
+
+func TestProperty_FromFloatRoundTrips(t *testing.T) {
+	f := func(c int32) bool {
+		return FromFloat(Cents(c).Float()) == Cents(c)
+	}
+	require.NoError(t, quick.Check(f, nil))
+}
+
+func TestProperty_SplitAddsUp(t *testing.T) {
+	f := func(amount uint32, weights []uint16) bool {
+		ws := make([]Cents, len(weights))
+		for i, w := range weights {
+			ws[i] = Cents(w)
+		}
+		shares := Split(Cents(amount), ws)
+		if len(ws) == 0 {
+			return len(shares) == 0
+		}
+		var sum Cents
+		for _, s := range shares {
+			if s < 0 {
+				return false
+			}
+			sum += s
+		}
+		return sum == Cents(amount)
+	}
+	require.NoError(t, quick.Check(f, nil))
+}
+
+func TestSplit(t *testing.T) {
+	require.Equal(t, []Cents{333, 667}, Split(1000, []Cents{1, 2}))
+	require.Equal(t, []Cents{750, 250}, Split(1000, []Cents{3000, 1000}))
+	require.Equal(t, []Cents{0, 1000}, Split(1000, []Cents{0, 0}))
+}
+
+func TestLineTotal_Rejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		price    Cents
+		quantity int
+	}{
+		{name: "negative price", price: -1, quantity: 1},
+		{name: "zero quantity", price: 100, quantity: 0},
+		{name: "quantity too large", price: 100, quantity: MaxQuantity + 1},
+		{name: "overflow", price: 1 << 62, quantity: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LineTotal(tt.price, tt.quantity)
+			assert.ErrorIs(t, err, ErrInvariant)
+		})
+	}
+}
+
+func TestCheckOrder_Commission(t *testing.T) {
+	lines := []Line{{Price: 1000, Quantity: 2, Commission: 2001}}
+	assert.ErrorIs(t, CheckOrder(lines, 2000), ErrInvariant)
+
+	lines[0].Commission = -1
+	assert.ErrorIs(t, CheckOrder(lines, 2000), ErrInvariant)
+
+	lines[0].Commission = 200
+	assert.NoError(t, CheckOrder(lines, 2000))
+	assert.ErrorIs(t, CheckOrder(lines, -2000), ErrInvariant)
+}
+
+func TestCheckOrder_Discount(t *testing.T) {
+	lines := []Line{{Price: 1000, Quantity: 2, Discount: PercentOff(2000, 15), Commission: 170}}
+	assert.Equal(t, Cents(300), lines[0].Discount)
+	assert.NoError(t, CheckOrder(lines, 1700))
+	assert.ErrorIs(t, CheckOrder(lines, 2000), ErrInvariant, "the total is after discounts")
+
+	lines[0].Discount = 2001
+	assert.ErrorIs(t, CheckOrder(lines, -1), ErrInvariant)
+	lines[0].Discount = -1
+	assert.ErrorIs(t, CheckOrder(lines, 2001), ErrInvariant)
+}
+
+func TestCents_String(t *testing.T) {
+	assert.Equal(t, "12.50", Cents(1250).String())
+	assert.Equal(t, "-0.05", Cents(-5).String())
+	assert.Equal(t, Cents(1999), FromFloat(19.99))
+}
@@ -0,0 +1,350 @@
+// Package openapi turns the Swagger 2.0 document that swag generates from the
+// handler annotations into an OpenAPI 3 document, so client SDKs can be
+// generated from /openapi.json. Error responses are rewritten to reference
+// the service's error envelope.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Zifeldev/marketback/service/Market/docs"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	Version = "3.0.3"
+
+	// ErrorSchemaName is the component that documents the error envelope.
+	ErrorSchemaName = "ErrorResponse"
+)
+
+// errorSchema matches controllers.ErrorResponse.
+var errorSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []interface{}{"code", "message"},
+	"properties": map[string]interface{}{
+		"code":    map[string]interface{}{"type": "string", "example": "VALIDATION_ERROR"},
+		"message": map[string]interface{}{"type": "string"},
+		"details": map[string]interface{}{"description": "Structured context such as the offending items"},
+	},
+}
+
+// Spec returns the service's OpenAPI 3 document.
+func Spec() ([]byte, error) {
+	return Convert([]byte(docs.SwaggerInfo.ReadDoc()), Options{ErrorSchema: errorSchema})
+}
+
+// Handler serves a document built by Spec.
+func Handler(spec []byte) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", spec)
+	}
+}
+
+type Options struct {
+	// ErrorSchema documents the error envelope. 4xx and 5xx responses
+	// annotated as a plain string map reference it instead.
+	ErrorSchema map[string]interface{}
+}
+
+type object = map[string]interface{}
+
+// Convert translates a Swagger 2.0 document into OpenAPI 3. The output is
+// indented JSON with sorted keys, so it is stable enough to compare with a
+// golden file.
+func Convert(swagger []byte, opts Options) ([]byte, error) {
+	var in object
+	if err := json.Unmarshal(swagger, &in); err != nil {
+		return nil, fmt.Errorf("failed to parse swagger document: %w", err)
+	}
+	if v, _ := in["swagger"].(string); v != "2.0" {
+		return nil, fmt.Errorf("unsupported swagger version %q", v)
+	}
+
+	c := &converter{
+		consumes: stringList(in["consumes"], "application/json"),
+		produces: stringList(in["produces"], "application/json"),
+		opts:     opts,
+	}
+
+	out := object{
+		"openapi": Version,
+		"info":    in["info"],
+		"paths":   c.paths(objectOf(in["paths"])),
+	}
+	if servers := servers(in); servers != nil {
+		out["servers"] = servers
+	}
+	for _, key := range []string{"tags", "security", "externalDocs"} {
+		if v, ok := in[key]; ok {
+			out[key] = v
+		}
+	}
+
+	schemas := object{}
+	for name, schema := range objectOf(in["definitions"]) {
+		schemas[name] = convertSchema(schema)
+	}
+	if opts.ErrorSchema != nil {
+		schemas[ErrorSchemaName] = opts.ErrorSchema
+	}
+	components := object{"schemas": schemas}
+	if schemes := securitySchemes(objectOf(in["securityDefinitions"])); len(schemes) > 0 {
+		components["securitySchemes"] = schemes
+	}
+	out["components"] = components
+
+	spec, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode openapi document: %w", err)
+	}
+	return append(spec, '\n'), nil
+}
+
+type converter struct {
+	consumes []string
+	produces []string
+	opts     Options
+}
+
+func (c *converter) paths(paths object) object {
+	out := object{}
+	for path, item := range paths {
+		ops := object{}
+		for method, op := range objectOf(item) {
+			ops[method] = c.operation(objectOf(op))
+		}
+		out[path] = ops
+	}
+	return out
+}
+
+func (c *converter) operation(op object) object {
+	out := object{}
+	for _, key := range []string{"summary", "description", "operationId", "tags", "security", "deprecated"} {
+		if v, ok := op[key]; ok {
+			out[key] = v
+		}
+	}
+
+	consumes := c.consumes
+	if v, ok := op["consumes"]; ok {
+		consumes = stringList(v, consumes...)
+	}
+	produces := c.produces
+	if v, ok := op["produces"]; ok {
+		produces = stringList(v, produces...)
+	}
+
+	var params []interface{}
+	form := object{}
+	var formRequired []interface{}
+	for _, p := range listOf(op["parameters"]) {
+		param := objectOf(p)
+		switch param["in"] {
+		case "body":
+			body := object{"content": content(consumes, convertSchema(param["schema"]))}
+			copyKeys(body, param, "description", "required")
+			out["requestBody"] = body
+		case "formData":
+			form[fmt.Sprint(param["name"])] = parameterSchema(param)
+			if required, _ := param["required"].(bool); required {
+				formRequired = append(formRequired, param["name"])
+			}
+		default:
+			converted := object{"schema": parameterSchema(param)}
+			copyKeys(converted, param, "name", "in", "description", "required")
+			if param["type"] == "array" {
+				converted["explode"] = param["collectionFormat"] == "multi"
+			}
+			params = append(params, converted)
+		}
+	}
+	if params != nil {
+		out["parameters"] = params
+	}
+	if len(form) > 0 {
+		schema := object{"type": "object", "properties": form}
+		if formRequired != nil {
+			schema["required"] = formRequired
+		}
+		mediaType := "application/x-www-form-urlencoded"
+		for _, ct := range consumes {
+			if ct == "multipart/form-data" {
+				mediaType = ct
+			}
+		}
+		out["requestBody"] = object{"required": formRequired != nil, "content": content([]string{mediaType}, schema)}
+	}
+
+	responses := object{}
+	for code, r := range objectOf(op["responses"]) {
+		responses[code] = c.response(code, objectOf(r), produces)
+	}
+	out["responses"] = responses
+	return out
+}
+
+func (c *converter) response(code string, r object, produces []string) object {
+	out := object{"description": r["description"]}
+	if out["description"] == nil {
+		out["description"] = http.StatusText(atoi(code))
+	}
+
+	if raw, ok := r["schema"]; ok {
+		schema := convertSchema(raw)
+		if c.opts.ErrorSchema != nil && atoi(code) >= 400 && isStringMap(schema) {
+			schema = object{"$ref": "#/components/schemas/" + ErrorSchemaName}
+		}
+		out["content"] = content(produces, schema)
+	}
+
+	if headers := objectOf(r["headers"]); len(headers) > 0 {
+		converted := object{}
+		for name, h := range headers {
+			header := objectOf(h)
+			item := object{"schema": parameterSchema(header)}
+			copyKeys(item, header, "description")
+			converted[name] = item
+		}
+		out["headers"] = converted
+	}
+	return out
+}
+
+// parameterSchema moves the type keywords of a non-body parameter into a
+// schema.
+func parameterSchema(param object) interface{} {
+	schema := object{}
+	copyKeys(schema, param, "type", "format", "items", "enum", "default", "minimum", "maximum",
+		"exclusiveMinimum", "exclusiveMaximum", "minLength", "maxLength", "pattern", "minItems", "maxItems", "uniqueItems")
+	return convertSchema(schema)
+}
+
+// convertSchema rewrites definition references and the Swagger-only
+// keywords of a schema and everything nested in it.
+func convertSchema(v interface{}) interface{} {
+	switch s := v.(type) {
+	case map[string]interface{}:
+		out := object{}
+		for key, value := range s {
+			switch key {
+			case "$ref":
+				ref, _ := value.(string)
+				out[key] = strings.Replace(ref, "#/definitions/", "#/components/schemas/", 1)
+			case "x-nullable":
+				out["nullable"] = value
+			case "collectionFormat":
+			default:
+				out[key] = convertSchema(value)
+			}
+		}
+		if out["type"] == "file" {
+			out["type"] = "string"
+			out["format"] = "binary"
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(s))
+		for i, value := range s {
+			out[i] = convertSchema(value)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func securitySchemes(defs object) object {
+	out := object{}
+	for name, d := range defs {
+		def := objectOf(d)
+		switch def["type"] {
+		case "apiKey":
+			// Bearer tokens are documented as an Authorization API key in
+			// Swagger 2.0, which has no bearer scheme.
+			if strings.EqualFold(fmt.Sprint(def["name"]), "Authorization") && def["in"] == "header" {
+				out[name] = object{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"}
+				continue
+			}
+			scheme := object{"type": "apiKey"}
+			copyKeys(scheme, def, "name", "in", "description")
+			out[name] = scheme
+		case "basic":
+			out[name] = object{"type": "http", "scheme": "basic"}
+		}
+	}
+	return out
+}
+
+func servers(in object) []interface{} {
+	host, _ := in["host"].(string)
+	if host == "" {
+		return nil
+	}
+	scheme := "http"
+	if schemes := stringList(in["schemes"]); len(schemes) > 0 {
+		scheme = schemes[0]
+	}
+	basePath, _ := in["basePath"].(string)
+	return []interface{}{object{"url": scheme + "://" + host + strings.TrimSuffix(basePath, "/")}}
+}
+
+func content(mediaTypes []string, schema interface{}) object {
+	out := object{}
+	for _, mt := range mediaTypes {
+		out[mt] = object{"schema": schema}
+	}
+	return out
+}
+
+func isStringMap(schema interface{}) bool {
+	s := objectOf(schema)
+	if s["type"] != "object" || len(s) != 2 {
+		return false
+	}
+	return objectOf(s["additionalProperties"])["type"] == "string"
+}
+
+func copyKeys(dst, src object, keys ...string) {
+	for _, key := range keys {
+		if v, ok := src[key]; ok {
+			dst[key] = v
+		}
+	}
+}
+
+func objectOf(v interface{}) object {
+	o, _ := v.(map[string]interface{})
+	return o
+}
+
+func listOf(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+// stringList returns the strings in v, or fallback when there are none.
+func stringList(v interface{}, fallback ...string) []string {
+	var out []string
+	for _, item := range listOf(v) {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	if len(out) == 0 {
+		return fallback
+	}
+	sort.Strings(out)
+	return out
+}
+
+func atoi(code string) int {
+	n, _ := strconv.Atoi(code)
+	return n
+}
@@ -0,0 +1,76 @@
+package ordernumber
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPrefix = "MB"
+	DefaultFormat = "{PREFIX}-{YYYY}-{SEQ}"
+	DefaultWidth  = 6
+)
+
+// Generator renders order numbers from a format template. Supported
+// placeholders: {PREFIX}, {YYYY}, {YY}, {MM}, {DD} and {SEQ}.
+type Generator struct {
+	prefix string
+	format string
+	width  int
+}
+
+func NewGenerator(prefix, format string, width int) (*Generator, error) {
+	if format == "" {
+		format = DefaultFormat
+	}
+	if !strings.Contains(format, "{SEQ}") {
+		return nil, errors.New("order number format must contain {SEQ}")
+	}
+	if width < 1 || width > 18 {
+		return nil, fmt.Errorf("order number sequence width must be between 1 and 18, got %d", width)
+	}
+
+	return &Generator{
+		prefix: prefix,
+		format: format,
+		width:  width,
+	}, nil
+}
+
+// Default returns a generator using the default MB-{YYYY}-{SEQ} format
+func Default() *Generator {
+	return &Generator{
+		prefix: DefaultPrefix,
+		format: DefaultFormat,
+		width:  DefaultWidth,
+	}
+}
+
+func (g *Generator) Format(seq int64, t time.Time) string {
+	r := strings.NewReplacer(
+		"{PREFIX}", g.prefix,
+		"{YYYY}", t.Format("2006"),
+		"{YY}", t.Format("06"),
+		"{MM}", t.Format("01"),
+		"{DD}", t.Format("02"),
+		"{SEQ}", fmt.Sprintf("%0*d", g.width, seq),
+	)
+	return r.Replace(g.format)
+}
+
+// Valid reports whether s could be an order number produced by a Generator
+func Valid(s string) bool {
+	if s == "" || len(s) > 64 {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_', r == '/':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package ordernumber
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator_Format(t *testing.T) {
+	ts := time.Date(2024, time.March, 7, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		prefix   string
+		format   string
+		width    int
+		seq      int64
+		expected string
+	}{
+		{
+			name:     "default format",
+			prefix:   "MB",
+			format:   DefaultFormat,
+			width:    6,
+			seq:      123,
+			expected: "MB-2024-000123",
+		},
+		{
+			name:     "empty format falls back to default",
+			prefix:   "MB",
+			width:    6,
+			seq:      1,
+			expected: "MB-2024-000001",
+		},
+		{
+			name:     "date parts",
+			prefix:   "SHOP",
+			format:   "{PREFIX}{YY}{MM}{DD}-{SEQ}",
+			width:    4,
+			seq:      42,
+			expected: "SHOP240307-0042",
+		},
+		{
+			name:     "sequence wider than width",
+			prefix:   "MB",
+			format:   "{PREFIX}-{SEQ}",
+			width:    3,
+			seq:      12345,
+			expected: "MB-12345",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewGenerator(tt.prefix, tt.format, tt.width)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, g.Format(tt.seq, ts))
+		})
+	}
+}
+
+func TestNewGenerator_Invalid(t *testing.T) {
+	_, err := NewGenerator("MB", "{PREFIX}-{YYYY}", 6)
+	assert.Error(t, err)
+
+	_, err = NewGenerator("MB", DefaultFormat, 0)
+	assert.Error(t, err)
+}
+
+func TestDefault(t *testing.T) {
+	ts := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "MB-2025-000007", Default().Format(7, ts))
+}
+
+func TestValid(t *testing.T) {
+	assert.True(t, Valid("MB-2024-000123"))
+	assert.True(t, Valid("SHOP240307_0042"))
+	assert.False(t, Valid(""))
+	assert.False(t, Valid("MB 2024"))
+	assert.False(t, Valid("abc$%"))
+}
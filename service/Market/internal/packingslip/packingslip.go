@@ -0,0 +1,62 @@
+// Package packingslip renders the price-free packing slips sellers put in
+// the parcels of gift orders.
+package packingslip
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// PDF renders the slip as an A4 document listing items and quantities
+// only; prices never appear on it.
+func PDF(slip *models.PackingSlip) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(fmt.Sprintf("Packing slip %s", slip.OrderNumber), true)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.Cell(0, 10, tr("Packing slip"))
+	pdf.Ln(10)
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.Cell(0, 7, tr(slip.ShopName))
+	pdf.Ln(7)
+	pdf.Cell(0, 7, tr(fmt.Sprintf("Order %s of %s", slip.OrderNumber, slip.OrderedAt.UTC().Format("2006-01-02"))))
+	pdf.Ln(10)
+
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.Cell(0, 7, tr("Ship to"))
+	pdf.Ln(7)
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.MultiCell(0, 6, tr(slip.DeliveryAddr), "", "L", false)
+	pdf.Ln(6)
+
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(95, 8, tr("Item"), "B", 0, "L", false, 0, "")
+	pdf.CellFormat(25, 8, tr("Size"), "B", 0, "L", false, 0, "")
+	pdf.CellFormat(30, 8, tr("Color"), "B", 0, "L", false, 0, "")
+	pdf.CellFormat(20, 8, tr("Qty"), "B", 1, "R", false, 0, "")
+	pdf.SetFont("Helvetica", "", 11)
+	for _, item := range slip.Items {
+		pdf.CellFormat(95, 8, tr(item.Title), "B", 0, "L", false, 0, "")
+		pdf.CellFormat(25, 8, tr(item.Size), "B", 0, "L", false, 0, "")
+		pdf.CellFormat(30, 8, tr(item.Color), "B", 0, "L", false, 0, "")
+		pdf.CellFormat(20, 8, strconv.Itoa(item.Quantity), "B", 1, "R", false, 0, "")
+	}
+
+	if slip.GiftMessage != "" {
+		pdf.Ln(10)
+		pdf.SetFont("Helvetica", "I", 12)
+		pdf.MultiCell(0, 7, tr(slip.GiftMessage), "", "L", false)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
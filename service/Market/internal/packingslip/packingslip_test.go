@@ -0,0 +1,26 @@
+package packingslip
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPDF(t *testing.T) {
+	out, err := PDF(&models.PackingSlip{
+		OrderNumber:  "MB-2026-000042",
+		OrderedAt:    time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		ShopName:     "Café Shop",
+		DeliveryAddr: "1 Main St\nSpringfield",
+		GiftMessage:  "Happy birthday, Zoë!",
+		Items: []models.PackingSlipItem{
+			{Title: "Mug", Quantity: 2},
+			{Title: "T-shirt", Size: "M", Quantity: 1},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(out, []byte("%PDF-")))
+}
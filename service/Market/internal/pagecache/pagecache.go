@@ -0,0 +1,110 @@
+// Package pagecache keeps the rendered JSON of public catalog pages in
+// Redis, so popular listings and product pages are served without touching
+// the database. Pages are only refreshed by expiring or being re-rendered
+// by the cache warmer.
+package pagecache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+)
+
+const (
+	keyPrefix = "page:"
+	// warmKey is present while the pages were warmed recently; it going
+	// missing early means the cache was flushed.
+	warmKey = "page-warm"
+)
+
+// Store is the part of cache.RedisCache pages are kept in.
+type Store interface {
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Get(ctx context.Context, key string, dest interface{}) error
+	Exists(ctx context.Context, key string) (bool, error)
+}
+
+type Cache struct {
+	store Store
+	ttl   time.Duration
+}
+
+// New creates a cache keeping pages for ttl.
+func New(store Store, ttl time.Duration) *Cache {
+	return &Cache{store: store, ttl: ttl}
+}
+
+// Key identifies a page by the storefront it is served on (0 for the
+// marketplace itself), its path and its query, whose parameters are sorted
+// so their order does not matter.
+func Key(sellerID int, path, rawQuery string) string {
+	if query, err := url.ParseQuery(rawQuery); err == nil {
+		rawQuery = query.Encode()
+	}
+	return fmt.Sprintf("%s%d:%s?%s", keyPrefix, sellerID, path, rawQuery)
+}
+
+// BucketKey identifies a page rendered for the visitors of a bucket, for
+// pages listing sellers on probation (see middleware.VisitorBucket).
+func BucketKey(key string, bucket int) string {
+	return fmt.Sprintf("%s#%d", key, bucket)
+}
+
+// Get returns the cached body of a page.
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, bool) {
+	var body json.RawMessage
+	if err := c.store.Get(ctx, key, &body); err != nil {
+		metrics.RedisMissesTotal.Inc()
+		return nil, false
+	}
+	metrics.RedisHitsTotal.Inc()
+	return body, true
+}
+
+// Set caches the body of a page, which has to be JSON.
+func (c *Cache) Set(ctx context.Context, key string, body []byte) error {
+	return c.store.Set(ctx, key, json.RawMessage(body), c.ttl)
+}
+
+// MarkWarm records that the pages were just warmed; the mark lasts for ttl.
+func (c *Cache) MarkWarm(ctx context.Context, ttl time.Duration) error {
+	return c.store.Set(ctx, warmKey, time.Now().UTC(), ttl)
+}
+
+// Warm tells whether the pages were warmed recently and not flushed since.
+func (c *Cache) Warm(ctx context.Context) (bool, error) {
+	return c.store.Exists(ctx, warmKey)
+}
+
+type refreshKey struct{}
+
+// WithRefresh marks a request context as re-rendering its page: the cached
+// copy is ignored and replaced.
+func WithRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, refreshKey{}, true)
+}
+
+// Refreshing tells whether a request re-renders its page.
+func Refreshing(ctx context.Context) bool {
+	refresh, _ := ctx.Value(refreshKey{}).(bool)
+	return refresh
+}
+
+type bucketKey struct{}
+
+// WithBucket marks a request context as rendering its page for the visitors
+// of bucket, whoever made the request.
+func WithBucket(ctx context.Context, bucket int) context.Context {
+	return context.WithValue(ctx, bucketKey{}, bucket)
+}
+
+// Bucket returns the visitor bucket a request renders its page for, if set
+// by WithBucket.
+func Bucket(ctx context.Context) (int, bool) {
+	bucket, ok := ctx.Value(bucketKey{}).(int)
+	return bucket, ok
+}
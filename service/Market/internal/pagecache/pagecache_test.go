@@ -0,0 +1,89 @@
+package pagecache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// memStore is a Redis cache in memory; values are kept as JSON like
+// cache.RedisCache does.
+type memStore struct {
+	values map[string][]byte
+	ttls   map[string]time.Duration
+}
+
+func newMemStore() *memStore {
+	return &memStore{values: map[string][]byte{}, ttls: map[string]time.Duration{}}
+}
+
+func (s *memStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	s.values[key] = data
+	s.ttls[key] = expiration
+	return nil
+}
+
+func (s *memStore) Get(ctx context.Context, key string, dest interface{}) error {
+	data, ok := s.values[key]
+	if !ok {
+		return errors.New("redis: nil")
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func (s *memStore) Exists(ctx context.Context, key string) (bool, error) {
+	_, ok := s.values[key]
+	return ok, nil
+}
+
+func TestKey(t *testing.T) {
+	require.Equal(t, Key(0, "/api/products", "page=2&category_id=3"), Key(0, "/api/products", "category_id=3&page=2"))
+	require.NotEqual(t, Key(0, "/api/products", ""), Key(7, "/api/products", ""), "storefronts are cached apart")
+	require.Equal(t, "page:0:/api/categories?", Key(0, "/api/categories", ""))
+	require.Equal(t, "page:0:/api/products?#3", BucketKey(Key(0, "/api/products", ""), 3))
+}
+
+func TestCache(t *testing.T) {
+	store := newMemStore()
+	cache := New(store, time.Minute)
+	ctx := context.Background()
+
+	_, ok := cache.Get(ctx, "page:0:/api/categories?")
+	require.False(t, ok)
+
+	require.NoError(t, cache.Set(ctx, "page:0:/api/categories?", []byte(`[{"id":1}]`)))
+	body, ok := cache.Get(ctx, "page:0:/api/categories?")
+	require.True(t, ok)
+	require.JSONEq(t, `[{"id":1}]`, string(body))
+	require.Equal(t, time.Minute, store.ttls["page:0:/api/categories?"])
+
+	warm, err := cache.Warm(ctx)
+	require.NoError(t, err)
+	require.False(t, warm)
+	require.NoError(t, cache.MarkWarm(ctx, 5*time.Minute))
+	warm, err = cache.Warm(ctx)
+	require.NoError(t, err)
+	require.True(t, warm)
+	require.Equal(t, 5*time.Minute, store.ttls[warmKey])
+}
+
+func TestRefreshing(t *testing.T) {
+	require.False(t, Refreshing(context.Background()))
+	require.True(t, Refreshing(WithRefresh(context.Background())))
+}
+
+func TestBucket(t *testing.T) {
+	_, ok := Bucket(context.Background())
+	require.False(t, ok)
+	bucket, ok := Bucket(WithBucket(context.Background(), 0))
+	require.True(t, ok)
+	require.Equal(t, 0, bucket)
+}
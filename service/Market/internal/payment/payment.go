@@ -0,0 +1,145 @@
+// Package payment charges and refunds orders through a payment provider:
+// Stripe, or Sandbox, a fake used for development and integration tests
+// that configuration refuses in production. Providers that implement Vault
+// also keep buyers' payment methods for later charges; the market only
+// stores the provider's customer and payment method IDs. Charges the bank
+// wants authenticated (3-D Secure) come back requiring an Action from the
+// buyer, and Confirmer looks their outcome up once the buyer is back.
+// Webhooks also report chargebacks, as events carrying a Dispute. Providers
+// that implement Payouts also send sellers their payouts.
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/money"
+)
+
+// ErrDeclined is returned when the provider refuses a refund or payout.
+var ErrDeclined = errors.New("payment: declined by provider")
+
+// ErrBadSignature is returned for webhook deliveries that were not signed
+// by the provider.
+var ErrBadSignature = errors.New("payment: bad webhook signature")
+
+type ChargeRequest struct {
+	OrderNumber string
+	Amount      money.Cents
+	// Scenario picks the outcome of a sandbox charge; real providers
+	// ignore it.
+	Scenario string
+	// Customer is the provider's customer of the buyer. With
+	// PaymentMethod, that saved method is charged without the buyer
+	// confirming it; with SaveMethod, the method the buyer pays with is
+	// kept for later.
+	Customer      string
+	PaymentMethod string
+	SaveMethod    bool
+	// ReturnURL is where the buyer is sent back to after authenticating
+	// a charge away from the shop; empty when not configured.
+	ReturnURL string
+}
+
+// Charge is the provider's answer to a charge. Status is paid, failed,
+// pending or requires_action; the last two are settled later by an Event.
+type Charge struct {
+	Ref    string
+	Status string
+	// Action is what the buyer has to do for a charge in requires_action.
+	Action *Action
+	// ClientSecret lets the buyer's browser confirm a pending charge with
+	// the provider, for providers that work that way.
+	ClientSecret string
+	// PaymentMethod is the method kept for a paid charge that asked to
+	// save it.
+	PaymentMethod string
+}
+
+// Action asks the buyer to authenticate a charge with their bank. Type is
+// models.PaymentActionRedirect, with the URL of the bank's challenge, or
+// models.PaymentActionSDK, for the provider's browser library.
+type Action struct {
+	Type string
+	URL  string
+}
+
+// Event is an asynchronous status change of a charge, as delivered by a
+// provider webhook.
+type Event struct {
+	Ref    string
+	Status string
+	// Customer and PaymentMethod are set when a charge that asked to save
+	// its method is paid.
+	Customer      string
+	PaymentMethod string
+	// Dispute is set, and Status empty, when the event reports a
+	// chargeback against the charge instead.
+	Dispute *Dispute
+}
+
+// Dispute is a chargeback: the buyer's bank taking a paid charge back
+// until the seller proves the sale was genuine. Status is
+// models.ChargebackStatusOpen until the bank decides it won or lost.
+type Dispute struct {
+	Ref    string
+	Amount money.Cents
+	Reason string
+	Status string
+	// EvidenceDueBy is the provider's deadline for evidence; zero when
+	// unknown.
+	EvidenceDueBy time.Time
+}
+
+type Provider interface {
+	Name() string
+	Charge(ctx context.Context, req ChargeRequest) (*Charge, error)
+	Refund(ctx context.Context, ref string, amount money.Cents) error
+}
+
+// Webhooks is implemented by providers that report charge status changes
+// to a webhook endpoint.
+type Webhooks interface {
+	// ParseEvent checks a webhook delivery's signature and reads the event
+	// from it. Deliveries that do not concern a charge's status return a
+	// nil event.
+	ParseEvent(payload []byte, header http.Header) (*Event, error)
+}
+
+// Confirmer is implemented by providers whose charges may require the
+// buyer to authenticate them.
+type Confirmer interface {
+	// Confirm looks up the current status of a charge once the buyer is
+	// back from authenticating it. Charges still waiting for the buyer or
+	// the bank keep their status.
+	Confirm(ctx context.Context, ref string) (*Event, error)
+}
+
+// Vault is implemented by providers that keep buyers' payment methods.
+type Vault interface {
+	// CreateCustomer makes the provider's customer for a user, which
+	// saved payment methods belong to.
+	CreateCustomer(ctx context.Context, userID int) (string, error)
+	// DetachMethod deletes a saved payment method at the provider.
+	DetachMethod(ctx context.Context, method string) error
+}
+
+// Payouts is implemented by providers that send sellers their payouts.
+type Payouts interface {
+	// PayoutMethods lists the models.PayoutMethod* accounts the provider
+	// can send payouts to.
+	PayoutMethods() []string
+	// Payout sends a payout and returns the provider's reference of the
+	// transfer. Retrying with the same Key sends it once.
+	Payout(ctx context.Context, req PayoutRequest) (string, error)
+}
+
+type PayoutRequest struct {
+	Key        string
+	Method     string
+	Account    string
+	HolderName string
+	Amount     money.Cents
+}
@@ -0,0 +1,189 @@
+package payment
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/money"
+)
+
+// Sandbox scenarios.
+const (
+	ScenarioSucceed = "succeed"
+	ScenarioFail    = "fail"
+	ScenarioDelay   = "delay"
+	// ScenarioChallenge asks the buyer to authenticate the charge; it is
+	// paid once confirmed.
+	ScenarioChallenge = "challenge"
+)
+
+// sandboxChallengeURL stands in for a bank's 3-D Secure page when no
+// return URL is configured.
+const sandboxChallengeURL = "https://sandbox.invalid/3ds/"
+
+// Sandbox is a fake provider that never moves money. Charges succeed,
+// fail, stay pending for a delay and then succeed through an Event, or
+// require a challenge that passes as soon as the charge is confirmed,
+// according to the request's scenario or the default one. Refunds are
+// declined in the fail scenario and accepted otherwise, and so are
+// payouts, by bank transfer or to a Stripe Connect account. Paid charges
+// that ask to save their method get a made-up one.
+type Sandbox struct {
+	scenario string
+	delay    time.Duration
+
+	mu         sync.Mutex
+	onEvent    func(ctx context.Context, ev Event)
+	challenged map[string]Event
+}
+
+func NewSandbox(scenario string, delay time.Duration) *Sandbox {
+	return &Sandbox{scenario: scenario, delay: delay, challenged: map[string]Event{}}
+}
+
+// OnEvent sets the receiver of the events that settle delayed charges,
+// normally the same handler as the webhook endpoint.
+func (s *Sandbox) OnEvent(fn func(ctx context.Context, ev Event)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onEvent = fn
+}
+
+func (s *Sandbox) Name() string { return "sandbox" }
+
+func (s *Sandbox) Charge(ctx context.Context, req ChargeRequest) (*Charge, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	ref, err := newRef("sandbox_")
+	if err != nil {
+		return nil, err
+	}
+	var method string
+	if req.SaveMethod {
+		if method, err = newRef("sandbox_pm_"); err != nil {
+			return nil, err
+		}
+	}
+
+	switch s.pick(req.Scenario) {
+	case ScenarioChallenge:
+		ev := Event{Ref: ref, Status: models.PaymentStatusPaid}
+		if method != "" {
+			ev.Customer, ev.PaymentMethod = req.Customer, method
+		}
+		s.mu.Lock()
+		s.challenged[ref] = ev
+		s.mu.Unlock()
+		return &Charge{
+			Ref:    ref,
+			Status: models.PaymentStatusRequiresAction,
+			Action: &Action{Type: models.PaymentActionRedirect, URL: challengeURL(req.ReturnURL, ref)},
+		}, nil
+	case ScenarioFail:
+		return &Charge{Ref: ref, Status: models.PaymentStatusFailed}, nil
+	case ScenarioDelay:
+		ev := Event{Ref: ref, Status: models.PaymentStatusPaid}
+		if method != "" {
+			ev.Customer, ev.PaymentMethod = req.Customer, method
+		}
+		time.AfterFunc(s.delay, func() { s.emit(ev) })
+		return &Charge{Ref: ref, Status: models.PaymentStatusPending}, nil
+	default:
+		return &Charge{Ref: ref, Status: models.PaymentStatusPaid, PaymentMethod: method}, nil
+	}
+}
+
+// Confirm passes the challenge of a charge made in the challenge scenario.
+// Other charges are reported as still pending.
+func (s *Sandbox) Confirm(ctx context.Context, ref string) (*Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ev, ok := s.challenged[ref]
+	if !ok {
+		return &Event{Ref: ref, Status: models.PaymentStatusPending}, nil
+	}
+	delete(s.challenged, ref)
+	return &ev, nil
+}
+
+func (s *Sandbox) CreateCustomer(ctx context.Context, userID int) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return newRef("sandbox_cus_")
+}
+
+func (s *Sandbox) DetachMethod(ctx context.Context, method string) error {
+	return ctx.Err()
+}
+
+func (s *Sandbox) Refund(ctx context.Context, ref string, amount money.Cents) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.scenario == ScenarioFail {
+		return fmt.Errorf("%w: refund of %s on %s", ErrDeclined, amount, ref)
+	}
+	return nil
+}
+
+func (s *Sandbox) PayoutMethods() []string {
+	return []string{models.PayoutMethodBankTransfer, models.PayoutMethodStripeConnect}
+}
+
+func (s *Sandbox) Payout(ctx context.Context, req PayoutRequest) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if s.scenario == ScenarioFail {
+		return "", fmt.Errorf("%w: payout of %s to %s", ErrDeclined, req.Amount, req.HolderName)
+	}
+	return newRef("sandbox_po_")
+}
+
+func (s *Sandbox) pick(scenario string) string {
+	if scenario != "" {
+		return scenario
+	}
+	return s.scenario
+}
+
+func (s *Sandbox) emit(ev Event) {
+	s.mu.Lock()
+	fn := s.onEvent
+	s.mu.Unlock()
+	if fn != nil {
+		fn(context.Background(), ev)
+	}
+}
+
+// challengeURL sends the buyer straight back to returnURL, as if they had
+// passed the bank's challenge, with the charge's reference added.
+func challengeURL(returnURL, ref string) string {
+	u, err := url.Parse(returnURL)
+	if returnURL == "" || err != nil {
+		return sandboxChallengeURL + ref
+	}
+	q := u.Query()
+	q.Set("payment_ref", ref)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func newRef(prefix string) (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate payment reference: %w", err)
+	}
+	return prefix + hex.EncodeToString(b), nil
+}
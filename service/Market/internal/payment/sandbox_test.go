@@ -0,0 +1,105 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandbox_Charge(t *testing.T) {
+	tests := []struct {
+		name       string
+		defaults   string
+		scenario   string
+		wantStatus string
+	}{
+		{name: "default succeed", defaults: ScenarioSucceed, wantStatus: models.PaymentStatusPaid},
+		{name: "default fail", defaults: ScenarioFail, wantStatus: models.PaymentStatusFailed},
+		{name: "request overrides default", defaults: ScenarioSucceed, scenario: ScenarioFail, wantStatus: models.PaymentStatusFailed},
+		{name: "delay stays pending", defaults: ScenarioSucceed, scenario: ScenarioDelay, wantStatus: models.PaymentStatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSandbox(tt.defaults, time.Hour)
+			charge, err := s.Charge(context.Background(), ChargeRequest{OrderNumber: "MB-1", Amount: 1000, Scenario: tt.scenario})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, charge.Status)
+			assert.True(t, strings.HasPrefix(charge.Ref, "sandbox_"))
+		})
+	}
+}
+
+func TestSandbox_DelayedChargeSettlesThroughEvent(t *testing.T) {
+	s := NewSandbox(ScenarioDelay, 10*time.Millisecond)
+	events := make(chan Event, 1)
+	s.OnEvent(func(ctx context.Context, ev Event) { events <- ev })
+
+	charge, err := s.Charge(context.Background(), ChargeRequest{Amount: 500})
+	require.NoError(t, err)
+
+	select {
+	case ev := <-events:
+		assert.Equal(t, Event{Ref: charge.Ref, Status: models.PaymentStatusPaid}, ev)
+	case <-time.After(5 * time.Second):
+		t.Fatal("delayed charge was never settled")
+	}
+}
+
+func TestSandbox_SaveMethod(t *testing.T) {
+	s := NewSandbox(ScenarioSucceed, time.Hour)
+	customer, err := s.CreateCustomer(context.Background(), 3)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(customer, "sandbox_cus_"))
+
+	charge, err := s.Charge(context.Background(), ChargeRequest{Amount: 500, Customer: customer, SaveMethod: true})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(charge.PaymentMethod, "sandbox_pm_"))
+
+	charge, err = s.Charge(context.Background(), ChargeRequest{Amount: 500, Customer: customer, PaymentMethod: charge.PaymentMethod})
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentStatusPaid, charge.Status)
+	assert.Empty(t, charge.PaymentMethod, "charging a saved method saves nothing new")
+	require.NoError(t, s.DetachMethod(context.Background(), "sandbox_pm_x"))
+}
+
+func TestSandbox_Challenge(t *testing.T) {
+	s := NewSandbox(ScenarioChallenge, time.Hour)
+	charge, err := s.Charge(context.Background(), ChargeRequest{Amount: 500, ReturnURL: "https://shop.example/pay?order_id=4"})
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentStatusRequiresAction, charge.Status)
+	require.NotNil(t, charge.Action)
+	assert.Equal(t, models.PaymentActionRedirect, charge.Action.Type)
+	assert.Equal(t, "https://shop.example/pay?order_id=4&payment_ref="+charge.Ref, charge.Action.URL)
+
+	ev, err := s.Confirm(context.Background(), charge.Ref)
+	require.NoError(t, err)
+	assert.Equal(t, &Event{Ref: charge.Ref, Status: models.PaymentStatusPaid}, ev)
+
+	ev, err = s.Confirm(context.Background(), charge.Ref)
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentStatusPending, ev.Status, "a challenge is passed once")
+}
+
+func TestSandbox_Refund(t *testing.T) {
+	require.NoError(t, NewSandbox(ScenarioSucceed, 0).Refund(context.Background(), "sandbox_x", 100))
+
+	err := NewSandbox(ScenarioFail, 0).Refund(context.Background(), "sandbox_x", 100)
+	assert.True(t, errors.Is(err, ErrDeclined))
+}
+
+func TestSandbox_Payout(t *testing.T) {
+	req := PayoutRequest{Key: "payout-1", Method: models.PayoutMethodBankTransfer, HolderName: "Shop", Amount: 100}
+	ref, err := NewSandbox(ScenarioSucceed, 0).Payout(context.Background(), req)
+	require.NoError(t, err)
+	assert.Contains(t, ref, "sandbox_po_")
+
+	_, err = NewSandbox(ScenarioFail, 0).Payout(context.Background(), req)
+	assert.ErrorIs(t, err, ErrDeclined)
+}
@@ -0,0 +1,369 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/money"
+)
+
+// StripeAPIURL is the default Stripe API address.
+const StripeAPIURL = "https://api.stripe.com"
+
+// stripeTolerance is how far a webhook signature's timestamp may be from
+// now, so captured deliveries cannot be replayed later.
+const stripeTolerance = 5 * time.Minute
+
+// Stripe charges through Stripe PaymentIntents. A charge creates a pending
+// intent whose client secret the buyer's browser uses to confirm it; Stripe
+// then reports the outcome to the webhook. Charges of a saved payment
+// method are confirmed right away: with a return URL the buyer is asked to
+// authenticate them when the bank wants it, otherwise they are charged
+// off-session. Amounts are sent in cents, so the currency must have two
+// decimals. Payouts are transfers to the sellers' Stripe Connect accounts.
+type Stripe struct {
+	apiURL        string
+	secretKey     string
+	webhookSecret string
+	currency      string
+	client        *http.Client
+	now           func() time.Time
+}
+
+func NewStripe(apiURL, secretKey, webhookSecret, currency string, timeout time.Duration) *Stripe {
+	return &Stripe{
+		apiURL:        strings.TrimRight(apiURL, "/"),
+		secretKey:     secretKey,
+		webhookSecret: webhookSecret,
+		currency:      strings.ToLower(currency),
+		client:        &http.Client{Timeout: timeout},
+		now:           time.Now,
+	}
+}
+
+func (s *Stripe) Name() string { return "stripe" }
+
+type stripeIntent struct {
+	ID               string `json:"id"`
+	Status           string `json:"status"`
+	ClientSecret     string `json:"client_secret"`
+	Customer         string `json:"customer"`
+	PaymentMethod    string `json:"payment_method"`
+	SetupFutureUsage string `json:"setup_future_usage"`
+	NextAction       *struct {
+		Type          string `json:"type"`
+		RedirectToURL struct {
+			URL string `json:"url"`
+		} `json:"redirect_to_url"`
+	} `json:"next_action"`
+	LastPaymentError *struct {
+		Code string `json:"code"`
+	} `json:"last_payment_error"`
+}
+
+type stripeError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type stripeEvent struct {
+	Type string `json:"type"`
+	Data struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+type stripeDispute struct {
+	ID              string `json:"id"`
+	Amount          int64  `json:"amount"`
+	Reason          string `json:"reason"`
+	Status          string `json:"status"`
+	PaymentIntent   string `json:"payment_intent"`
+	EvidenceDetails struct {
+		DueBy int64 `json:"due_by"`
+	} `json:"evidence_details"`
+}
+
+func (s *Stripe) Charge(ctx context.Context, req ChargeRequest) (*Charge, error) {
+	form := url.Values{
+		"amount":                 {strconv.FormatInt(int64(req.Amount), 10)},
+		"currency":               {s.currency},
+		"metadata[order_number]": {req.OrderNumber},
+	}
+	if req.Customer != "" {
+		form.Set("customer", req.Customer)
+	}
+	switch {
+	case req.PaymentMethod != "":
+		form.Set("payment_method", req.PaymentMethod)
+		form.Set("confirm", "true")
+		if req.ReturnURL != "" {
+			form.Set("return_url", req.ReturnURL)
+		} else {
+			form.Set("off_session", "true")
+		}
+	case req.SaveMethod:
+		form.Set("setup_future_usage", "off_session")
+		fallthrough
+	default:
+		form.Set("automatic_payment_methods[enabled]", "true")
+	}
+
+	var intent stripeIntent
+	if err := s.post(ctx, "/v1/payment_intents", form, &intent); err != nil {
+		return nil, err
+	}
+	charge := &Charge{Ref: intent.ID, Status: intentStatus(intent.Status), ClientSecret: intent.ClientSecret}
+	if req.SaveMethod && charge.Status == models.PaymentStatusPaid {
+		charge.PaymentMethod = intent.PaymentMethod
+	}
+	if charge.Status == models.PaymentStatusRequiresAction {
+		charge.Action = &Action{Type: models.PaymentActionSDK}
+		if next := intent.NextAction; next != nil && next.Type == "redirect_to_url" {
+			charge.Action = &Action{Type: models.PaymentActionRedirect, URL: next.RedirectToURL.URL}
+		}
+	}
+	return charge, nil
+}
+
+// Confirm retrieves the intent of a charge. An intent that needs a new
+// payment method after an attempt failed, e.g. a failed 3-D Secure
+// challenge, is reported failed.
+func (s *Stripe) Confirm(ctx context.Context, ref string) (*Event, error) {
+	var intent stripeIntent
+	if err := s.do(ctx, http.MethodGet, "/v1/payment_intents/"+url.PathEscape(ref), "", nil, &intent); err != nil {
+		return nil, err
+	}
+	if intent.Status == "requires_payment_method" && intent.LastPaymentError != nil {
+		return &Event{Ref: intent.ID, Status: models.PaymentStatusFailed}, nil
+	}
+	return intentEvent(intent), nil
+}
+
+func (s *Stripe) CreateCustomer(ctx context.Context, userID int) (string, error) {
+	var customer struct {
+		ID string `json:"id"`
+	}
+	form := url.Values{"metadata[user_id]": {strconv.Itoa(userID)}}
+	if err := s.post(ctx, "/v1/customers", form, &customer); err != nil {
+		return "", err
+	}
+	return customer.ID, nil
+}
+
+func (s *Stripe) DetachMethod(ctx context.Context, method string) error {
+	return s.post(ctx, "/v1/payment_methods/"+url.PathEscape(method)+"/detach", url.Values{}, nil)
+}
+
+func (s *Stripe) Refund(ctx context.Context, ref string, amount money.Cents) error {
+	form := url.Values{
+		"payment_intent": {ref},
+		"amount":         {strconv.FormatInt(int64(amount), 10)},
+	}
+	return s.post(ctx, "/v1/refunds", form, nil)
+}
+
+// PayoutMethods reports that Stripe pays sellers out to their Stripe
+// Connect accounts, which Stripe then pays out to their banks.
+func (s *Stripe) PayoutMethods() []string {
+	return []string{models.PayoutMethodStripeConnect}
+}
+
+// Payout transfers the amount from the platform's balance to the seller's
+// connected account.
+func (s *Stripe) Payout(ctx context.Context, req PayoutRequest) (string, error) {
+	if req.Method != models.PayoutMethodStripeConnect {
+		return "", fmt.Errorf("stripe cannot send payouts by %s", req.Method)
+	}
+	form := url.Values{
+		"amount":           {strconv.FormatInt(int64(req.Amount), 10)},
+		"currency":         {s.currency},
+		"destination":      {req.Account},
+		"metadata[payout]": {req.Key},
+	}
+	var transfer struct {
+		ID string `json:"id"`
+	}
+	if err := s.do(ctx, http.MethodPost, "/v1/transfers", req.Key, form, &transfer); err != nil {
+		return "", err
+	}
+	return transfer.ID, nil
+}
+
+// ParseEvent reads payment_intent.succeeded, payment_failed and canceled
+// events, and charge.dispute.created and closed events for chargebacks;
+// other event types return a nil event. Succeeded intents set up for
+// future use carry their customer and payment method.
+func (s *Stripe) ParseEvent(payload []byte, header http.Header) (*Event, error) {
+	if err := s.verify(payload, header.Get("Stripe-Signature")); err != nil {
+		return nil, err
+	}
+
+	var ev stripeEvent
+	if err := json.Unmarshal(payload, &ev); err != nil {
+		return nil, fmt.Errorf("failed to decode stripe event: %w", err)
+	}
+	switch ev.Type {
+	case "payment_intent.succeeded", "payment_intent.payment_failed", "payment_intent.canceled":
+		var intent stripeIntent
+		if err := json.Unmarshal(ev.Data.Object, &intent); err != nil {
+			return nil, fmt.Errorf("failed to decode stripe payment intent: %w", err)
+		}
+		if ev.Type != "payment_intent.succeeded" {
+			return &Event{Ref: intent.ID, Status: models.PaymentStatusFailed}, nil
+		}
+		return intentEvent(intent), nil
+	case "charge.dispute.created", "charge.dispute.closed":
+		var dispute stripeDispute
+		if err := json.Unmarshal(ev.Data.Object, &dispute); err != nil {
+			return nil, fmt.Errorf("failed to decode stripe dispute: %w", err)
+		}
+		return disputeEvent(dispute, ev.Type == "charge.dispute.closed"), nil
+	}
+	return nil, nil
+}
+
+// disputeEvent reports a dispute against its payment intent. Closed
+// disputes are won unless Stripe says they were lost; an inquiry closed
+// without a chargeback counts as won.
+func disputeEvent(dispute stripeDispute, closed bool) *Event {
+	d := &Dispute{
+		Ref:    dispute.ID,
+		Amount: money.Cents(dispute.Amount),
+		Reason: dispute.Reason,
+		Status: models.ChargebackStatusOpen,
+	}
+	if dispute.EvidenceDetails.DueBy > 0 {
+		d.EvidenceDueBy = time.Unix(dispute.EvidenceDetails.DueBy, 0).UTC()
+	}
+	if closed {
+		d.Status = models.ChargebackStatusWon
+		if dispute.Status == "lost" {
+			d.Status = models.ChargebackStatusLost
+		}
+	}
+	return &Event{Ref: dispute.PaymentIntent, Dispute: d}
+}
+
+// intentEvent reports an intent's status. Succeeded intents set up for
+// future use carry their customer and payment method.
+func intentEvent(intent stripeIntent) *Event {
+	ev := &Event{Ref: intent.ID, Status: intentStatus(intent.Status)}
+	if ev.Status == models.PaymentStatusPaid && intent.SetupFutureUsage != "" && intent.Customer != "" {
+		ev.Customer, ev.PaymentMethod = intent.Customer, intent.PaymentMethod
+	}
+	return ev
+}
+
+// verify checks a Stripe-Signature header, "t=<unix time>,v1=<signature>",
+// where the signature is the hex HMAC-SHA256 of "<t>.<payload>" under the
+// webhook secret. Any of several v1 signatures may match.
+func (s *Stripe) verify(payload []byte, header string) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrBadSignature
+	}
+	if age := s.now().Sub(time.Unix(unix, 0)); age > stripeTolerance || age < -stripeTolerance {
+		return ErrBadSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.webhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	want := mac.Sum(nil)
+	for _, sig := range signatures {
+		if got, err := hex.DecodeString(sig); err == nil && hmac.Equal(got, want) {
+			return nil
+		}
+	}
+	return ErrBadSignature
+}
+
+// post sends a form to the Stripe API and decodes the response into out,
+// unless out is nil. Card errors are reported as ErrDeclined.
+func (s *Stripe) post(ctx context.Context, path string, form url.Values, out any) error {
+	return s.do(ctx, http.MethodPost, path, "", form, out)
+}
+
+// do calls the Stripe API, sending form when it is not nil. Requests with
+// an idempotency key are carried out once however often they are sent.
+func (s *Stripe) do(ctx context.Context, method, path, idempotencyKey string, form url.Values, out any) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.apiURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.secretKey)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("stripe request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr stripeError
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&apiErr)
+		if resp.StatusCode == http.StatusPaymentRequired || apiErr.Error.Type == "card_error" {
+			return fmt.Errorf("%w: %s", ErrDeclined, apiErr.Error.Message)
+		}
+		return fmt.Errorf("stripe API returned %d: %s", resp.StatusCode, apiErr.Error.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode stripe response: %w", err)
+	}
+	return nil
+}
+
+// intentStatus maps a PaymentIntent status to a payment status. Intents
+// the buyer has to authenticate require action; others waiting for the
+// buyer or still processing are pending.
+func intentStatus(status string) string {
+	switch status {
+	case "succeeded":
+		return models.PaymentStatusPaid
+	case "canceled":
+		return models.PaymentStatusFailed
+	case "requires_action":
+		return models.PaymentStatusRequiresAction
+	}
+	return models.PaymentStatusPending
+}
@@ -0,0 +1,237 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStripe_Charge(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/payment_intents", r.URL.Path)
+		assert.Equal(t, "Bearer sk_test", r.Header.Get("Authorization"))
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "2550", r.PostForm.Get("amount"))
+		assert.Equal(t, "eur", r.PostForm.Get("currency"))
+		assert.Equal(t, "MB-7", r.PostForm.Get("metadata[order_number]"))
+		fmt.Fprint(w, `{"id":"pi_1","status":"requires_payment_method","client_secret":"pi_1_secret"}`)
+	}))
+	defer srv.Close()
+
+	s := NewStripe(srv.URL, "sk_test", "whsec", "EUR", time.Second)
+	charge, err := s.Charge(context.Background(), ChargeRequest{OrderNumber: "MB-7", Amount: 2550})
+	require.NoError(t, err)
+	assert.Equal(t, &Charge{Ref: "pi_1", Status: models.PaymentStatusPending, ClientSecret: "pi_1_secret"}, charge)
+}
+
+func TestStripe_ChargeSavedMethod(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		switch r.PostForm.Get("customer") {
+		case "cus_save":
+			assert.Equal(t, "off_session", r.PostForm.Get("setup_future_usage"))
+			assert.Equal(t, "true", r.PostForm.Get("automatic_payment_methods[enabled]"))
+			fmt.Fprint(w, `{"id":"pi_1","status":"succeeded","payment_method":"pm_new"}`)
+		case "cus_saved":
+			assert.Equal(t, "pm_1", r.PostForm.Get("payment_method"))
+			assert.Equal(t, "true", r.PostForm.Get("off_session"))
+			assert.Equal(t, "true", r.PostForm.Get("confirm"))
+			assert.Empty(t, r.PostForm.Get("automatic_payment_methods[enabled]"))
+			w.WriteHeader(http.StatusPaymentRequired)
+			fmt.Fprint(w, `{"error":{"type":"card_error","message":"authentication_required"}}`)
+		}
+	}))
+	defer srv.Close()
+
+	s := NewStripe(srv.URL, "sk_test", "whsec", "usd", time.Second)
+	charge, err := s.Charge(context.Background(), ChargeRequest{Amount: 100, Customer: "cus_save", SaveMethod: true})
+	require.NoError(t, err)
+	assert.Equal(t, &Charge{Ref: "pi_1", Status: models.PaymentStatusPaid, PaymentMethod: "pm_new"}, charge)
+
+	_, err = s.Charge(context.Background(), ChargeRequest{Amount: 100, Customer: "cus_saved", PaymentMethod: "pm_1"})
+	assert.ErrorIs(t, err, ErrDeclined, "off-session charges needing the buyer are declined")
+}
+
+func TestStripe_Challenge(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v1/payment_intents":
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "https://shop.example/pay/return", r.PostForm.Get("return_url"))
+			assert.Empty(t, r.PostForm.Get("off_session"))
+			fmt.Fprint(w, `{"id":"pi_1","status":"requires_action","next_action":{"type":"redirect_to_url","redirect_to_url":{"url":"https://hooks.stripe.test/3ds"}}}`)
+		case "GET /v1/payment_intents/pi_1":
+			fmt.Fprint(w, `{"id":"pi_1","status":"succeeded","customer":"cus_1","payment_method":"pm_1","setup_future_usage":"off_session"}`)
+		case "GET /v1/payment_intents/pi_2":
+			fmt.Fprint(w, `{"id":"pi_2","status":"requires_payment_method","last_payment_error":{"code":"payment_intent_authentication_failure"}}`)
+		case "GET /v1/payment_intents/pi_3":
+			fmt.Fprint(w, `{"id":"pi_3","status":"requires_action","next_action":{"type":"use_stripe_sdk"}}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	s := NewStripe(srv.URL, "sk_test", "whsec", "usd", time.Second)
+	charge, err := s.Charge(context.Background(), ChargeRequest{
+		Amount: 100, Customer: "cus_1", PaymentMethod: "pm_1", ReturnURL: "https://shop.example/pay/return",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentStatusRequiresAction, charge.Status)
+	assert.Equal(t, &Action{Type: models.PaymentActionRedirect, URL: "https://hooks.stripe.test/3ds"}, charge.Action)
+
+	ev, err := s.Confirm(context.Background(), "pi_1")
+	require.NoError(t, err)
+	assert.Equal(t, &Event{Ref: "pi_1", Status: models.PaymentStatusPaid, Customer: "cus_1", PaymentMethod: "pm_1"}, ev)
+
+	ev, err = s.Confirm(context.Background(), "pi_2")
+	require.NoError(t, err)
+	assert.Equal(t, &Event{Ref: "pi_2", Status: models.PaymentStatusFailed}, ev, "failed challenges fail the payment")
+
+	ev, err = s.Confirm(context.Background(), "pi_3")
+	require.NoError(t, err)
+	assert.Equal(t, &Event{Ref: "pi_3", Status: models.PaymentStatusRequiresAction}, ev)
+}
+
+func TestStripe_Vault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		switch r.URL.Path {
+		case "/v1/customers":
+			assert.Equal(t, "3", r.PostForm.Get("metadata[user_id]"))
+			fmt.Fprint(w, `{"id":"cus_1"}`)
+		case "/v1/payment_methods/pm_1/detach":
+			fmt.Fprint(w, `{"id":"pm_1"}`)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	s := NewStripe(srv.URL, "sk_test", "whsec", "usd", time.Second)
+	customer, err := s.CreateCustomer(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, "cus_1", customer)
+	require.NoError(t, s.DetachMethod(context.Background(), "pm_1"))
+}
+
+func TestStripe_Payout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/transfers", r.URL.Path)
+		assert.Equal(t, "payout-12", r.Header.Get("Idempotency-Key"))
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "18000", r.PostForm.Get("amount"))
+		assert.Equal(t, "eur", r.PostForm.Get("currency"))
+		assert.Equal(t, "acct_1", r.PostForm.Get("destination"))
+		fmt.Fprint(w, `{"id":"tr_1"}`)
+	}))
+	defer srv.Close()
+
+	s := NewStripe(srv.URL, "sk_test", "whsec", "EUR", time.Second)
+	ref, err := s.Payout(context.Background(), PayoutRequest{
+		Key: "payout-12", Method: models.PayoutMethodStripeConnect, Account: "acct_1", Amount: 18000,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "tr_1", ref)
+
+	_, err = s.Payout(context.Background(), PayoutRequest{Key: "payout-13", Method: models.PayoutMethodBankTransfer, Amount: 100})
+	assert.Error(t, err, "bank transfers go through the connected account")
+}
+
+func TestStripe_Refund(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/refunds", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "pi_1", r.PostForm.Get("payment_intent"))
+		assert.Equal(t, "500", r.PostForm.Get("amount"))
+		w.WriteHeader(status)
+		switch status {
+		case http.StatusOK:
+			fmt.Fprint(w, `{"id":"re_1","status":"succeeded"}`)
+		case http.StatusPaymentRequired:
+			fmt.Fprint(w, `{"error":{"type":"card_error","message":"charge expired"}}`)
+		default:
+			fmt.Fprint(w, `{"error":{"type":"api_error","message":"boom"}}`)
+		}
+	}))
+	defer srv.Close()
+
+	s := NewStripe(srv.URL, "sk_test", "whsec", "usd", time.Second)
+	require.NoError(t, s.Refund(context.Background(), "pi_1", 500))
+
+	status = http.StatusPaymentRequired
+	err := s.Refund(context.Background(), "pi_1", 500)
+	assert.True(t, errors.Is(err, ErrDeclined))
+
+	status = http.StatusInternalServerError
+	err = s.Refund(context.Background(), "pi_1", 500)
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, ErrDeclined))
+}
+
+func TestStripe_ParseEvent(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	s := NewStripe(StripeAPIURL, "sk_test", "whsec_test", "usd", time.Second)
+	s.now = func() time.Time { return now }
+
+	sign := func(payload string, at time.Time, secret string) http.Header {
+		ts := fmt.Sprint(at.Unix())
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(ts + "." + payload))
+		h := http.Header{}
+		h.Set("Stripe-Signature", "t="+ts+",v0=ignored,v1="+hex.EncodeToString(mac.Sum(nil)))
+		return h
+	}
+	succeeded := `{"type":"payment_intent.succeeded","data":{"object":{"id":"pi_1","status":"succeeded"}}}`
+	failed := `{"type":"payment_intent.payment_failed","data":{"object":{"id":"pi_2"}}}`
+	saved := `{"type":"payment_intent.succeeded","data":{"object":{"id":"pi_3","status":"succeeded","customer":"cus_1","payment_method":"pm_1","setup_future_usage":"off_session"}}}`
+	disputed := `{"type":"charge.dispute.created","data":{"object":{"id":"dp_1","amount":2550,"reason":"fraudulent","status":"needs_response","payment_intent":"pi_1","evidence_details":{"due_by":1700600000}}}}`
+	lost := `{"type":"charge.dispute.closed","data":{"object":{"id":"dp_1","amount":2550,"status":"lost","payment_intent":"pi_1"}}}`
+	other := `{"type":"charge.refunded","data":{"object":{"id":"ch_1"}}}`
+
+	ev, err := s.ParseEvent([]byte(succeeded), sign(succeeded, now, "whsec_test"))
+	require.NoError(t, err)
+	assert.Equal(t, &Event{Ref: "pi_1", Status: models.PaymentStatusPaid}, ev)
+
+	ev, err = s.ParseEvent([]byte(failed), sign(failed, now.Add(-time.Minute), "whsec_test"))
+	require.NoError(t, err)
+	assert.Equal(t, &Event{Ref: "pi_2", Status: models.PaymentStatusFailed}, ev)
+
+	ev, err = s.ParseEvent([]byte(saved), sign(saved, now, "whsec_test"))
+	require.NoError(t, err)
+	assert.Equal(t, &Event{Ref: "pi_3", Status: models.PaymentStatusPaid, Customer: "cus_1", PaymentMethod: "pm_1"}, ev)
+
+	ev, err = s.ParseEvent([]byte(disputed), sign(disputed, now, "whsec_test"))
+	require.NoError(t, err)
+	assert.Equal(t, &Event{Ref: "pi_1", Dispute: &Dispute{
+		Ref: "dp_1", Amount: 2550, Reason: "fraudulent", Status: models.ChargebackStatusOpen,
+		EvidenceDueBy: time.Unix(1_700_600_000, 0).UTC(),
+	}}, ev)
+
+	ev, err = s.ParseEvent([]byte(lost), sign(lost, now, "whsec_test"))
+	require.NoError(t, err)
+	assert.Equal(t, &Dispute{Ref: "dp_1", Amount: 2550, Status: models.ChargebackStatusLost}, ev.Dispute)
+
+	ev, err = s.ParseEvent([]byte(other), sign(other, now, "whsec_test"))
+	require.NoError(t, err)
+	assert.Nil(t, ev, "events about other objects are ignored")
+
+	_, err = s.ParseEvent([]byte(succeeded), sign(succeeded, now, "whsec_other"))
+	assert.ErrorIs(t, err, ErrBadSignature)
+	_, err = s.ParseEvent([]byte(succeeded), sign(succeeded, now.Add(-time.Hour), "whsec_test"))
+	assert.ErrorIs(t, err, ErrBadSignature, "stale deliveries are refused")
+	_, err = s.ParseEvent([]byte(succeeded), http.Header{})
+	assert.ErrorIs(t, err, ErrBadSignature)
+}
@@ -0,0 +1,195 @@
+// Package payouts sends sellers the balances of their closed statements
+// through the payment provider.
+package payouts
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/money"
+	"github.com/Zifeldev/marketback/service/Market/internal/payment"
+	"github.com/Zifeldev/marketback/service/Market/internal/statements"
+	"github.com/Zifeldev/marketback/service/Market/internal/workers"
+)
+
+// batchSize caps how many payouts one run sends; the rest go on the next.
+const batchSize = 100
+
+var (
+	ibanPattern           = regexp.MustCompile(`^[A-Z]{2}\d{2}[A-Z0-9]{11,30}$`)
+	connectAccountPattern = regexp.MustCompile(`^acct_[A-Za-z0-9]{8,}$`)
+)
+
+// NormalizeAccount checks that account is a valid account of the payout
+// method, one of supported, and returns it in the form it is stored in:
+// IBANs upper case without spaces.
+func NormalizeAccount(method, account string, supported []string) (string, error) {
+	if !slices.Contains(supported, method) {
+		return "", fmt.Errorf("payout method %s is not supported by the payment provider", method)
+	}
+	switch method {
+	case models.PayoutMethodBankTransfer:
+		iban := strings.ToUpper(strings.ReplaceAll(account, " ", ""))
+		if !ibanPattern.MatchString(iban) || !ibanChecksumOK(iban) {
+			return "", fmt.Errorf("account must be a valid IBAN")
+		}
+		return iban, nil
+	case models.PayoutMethodStripeConnect:
+		account = strings.TrimSpace(account)
+		if !connectAccountPattern.MatchString(account) {
+			return "", fmt.Errorf("account must be a Stripe Connect account ID (acct_...)")
+		}
+		return account, nil
+	}
+	return "", fmt.Errorf("unknown payout method %s", method)
+}
+
+// ibanChecksumOK runs the ISO 13616 mod-97 check.
+func ibanChecksumOK(iban string) bool {
+	var digits strings.Builder
+	for _, r := range iban[4:] + iban[:4] {
+		if r >= 'A' && r <= 'Z' {
+			fmt.Fprintf(&digits, "%d", r-'A'+10)
+			continue
+		}
+		digits.WriteRune(r)
+	}
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// Store keeps payouts and the statements they pay.
+type Store interface {
+	// Batch creates one pending payout per seller for the unpaid statements
+	// of months before before whose sales have settled after delays, when
+	// they come to at least minimum.
+	Batch(ctx context.Context, provider string, before time.Time, minimum float64, delays models.SettlementDelays) (int64, error)
+	Due(ctx context.Context, now time.Time, limit int) ([]*models.DuePayout, error)
+	MarkPaid(ctx context.Context, id int, ref string) error
+	// MarkFailed records a refused attempt; a nil retryAt fails the payout.
+	MarkFailed(ctx context.Context, id int, reason string, retryAt *time.Time) error
+}
+
+// Runner batches closed months into payouts once their sales have settled,
+// delays after delivery, and sends the due ones. A refused payout is
+// retried after backoff, doubling each attempt, until it has had
+// maxAttempts.
+type Runner struct {
+	store       Store
+	provider    payment.Payouts
+	name        string
+	minimum     float64
+	delays      models.SettlementDelays
+	maxAttempts int
+	backoff     time.Duration
+	now         func() time.Time
+}
+
+// NewRunner sends payouts through provider, recorded under name.
+func NewRunner(store Store, provider payment.Payouts, name string, minimum float64, delays models.SettlementDelays, maxAttempts int, backoff time.Duration) *Runner {
+	return &Runner{
+		store:       store,
+		provider:    provider,
+		name:        name,
+		minimum:     minimum,
+		delays:      delays,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+		now:         time.Now,
+	}
+}
+
+// RunOnce batches the settled, unpaid statements of closed months and
+// sends every payout that is due. A payout the provider refuses does not
+// stop the run.
+func (r *Runner) RunOnce(ctx context.Context) error {
+	now := r.now()
+
+	created, err := r.store.Batch(ctx, r.name, statements.MonthStart(now), r.minimum, r.delays)
+	if err != nil {
+		return err
+	}
+
+	due, err := r.store.Due(ctx, now, batchSize)
+	if err != nil {
+		return err
+	}
+
+	var sent, failed int
+	for _, p := range due {
+		ref, err := r.send(ctx, p)
+		if err == nil {
+			if err := r.store.MarkPaid(ctx, p.ID, ref); err != nil {
+				return err
+			}
+			metrics.PayoutsSentTotal.Inc()
+			sent++
+			continue
+		}
+
+		var retryAt *time.Time
+		if p.Attempts+1 < r.maxAttempts {
+			at := now.Add(r.backoff << p.Attempts)
+			retryAt = &at
+		}
+		logger.GetLogger().WithFields(map[string]interface{}{
+			"payout":   p.ID,
+			"attempts": p.Attempts + 1,
+			"err":      err,
+		}).Warn("payout refused by the provider")
+		if err := r.store.MarkFailed(ctx, p.ID, err.Error(), retryAt); err != nil {
+			return err
+		}
+		if retryAt == nil {
+			metrics.PayoutFailuresTotal.Inc()
+		}
+		failed++
+	}
+
+	logger.GetLogger().WithFields(map[string]interface{}{
+		"created": created,
+		"sent":    sent,
+		"refused": failed,
+	}).Info("seller payouts run")
+	return nil
+}
+
+func (r *Runner) send(ctx context.Context, p *models.DuePayout) (string, error) {
+	if !slices.Contains(r.provider.PayoutMethods(), p.Method) {
+		return "", fmt.Errorf("payout method %s is not supported by the payment provider", p.Method)
+	}
+	return r.provider.Payout(ctx, payment.PayoutRequest{
+		Key:        fmt.Sprintf("payout-%d", p.ID),
+		Method:     p.Method,
+		Account:    p.Account,
+		HolderName: p.HolderName,
+		Amount:     money.FromFloat(p.Amount),
+	})
+}
+
+// Start runs the runner every interval until ctx is cancelled.
+func (r *Runner) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		workers.Beat(ctx)
+		if err := r.RunOnce(ctx); err != nil {
+			metrics.PayoutRunFailuresTotal.Inc()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package payouts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/money"
+	"github.com/Zifeldev/marketback/service/Market/internal/payment"
+	"github.com/stretchr/testify/require"
+)
+
+type failure struct {
+	reason  string
+	retryAt *time.Time
+}
+
+type fakeStore struct {
+	before   time.Time
+	minimum  float64
+	delays   models.SettlementDelays
+	due      []*models.DuePayout
+	paid     map[int]string
+	failed   map[int]failure
+	batchErr error
+}
+
+func (f *fakeStore) Batch(ctx context.Context, provider string, before time.Time, minimum float64, delays models.SettlementDelays) (int64, error) {
+	f.before, f.minimum, f.delays = before, minimum, delays
+	return int64(len(f.due)), f.batchErr
+}
+
+func (f *fakeStore) Due(ctx context.Context, now time.Time, limit int) ([]*models.DuePayout, error) {
+	return f.due, nil
+}
+
+func (f *fakeStore) MarkPaid(ctx context.Context, id int, ref string) error {
+	f.paid[id] = ref
+	return nil
+}
+
+func (f *fakeStore) MarkFailed(ctx context.Context, id int, reason string, retryAt *time.Time) error {
+	f.failed[id] = failure{reason, retryAt}
+	return nil
+}
+
+type fakeProvider struct {
+	requests []payment.PayoutRequest
+	refuse   map[string]bool
+}
+
+func (f *fakeProvider) PayoutMethods() []string {
+	return []string{models.PayoutMethodStripeConnect}
+}
+
+func (f *fakeProvider) Payout(ctx context.Context, req payment.PayoutRequest) (string, error) {
+	f.requests = append(f.requests, req)
+	if f.refuse[req.Account] {
+		return "", errors.New("no such destination")
+	}
+	return "tr_" + req.Key, nil
+}
+
+func duePayout(id int, method, account string, attempts int) *models.DuePayout {
+	return &models.DuePayout{
+		Payout:  models.Payout{ID: id, Amount: 42.5, Method: method, Attempts: attempts},
+		Account: account,
+	}
+}
+
+func TestRunner_RunOnce(t *testing.T) {
+	now := time.Date(2026, 3, 4, 6, 0, 0, 0, time.UTC)
+	store := &fakeStore{
+		due: []*models.DuePayout{
+			duePayout(1, models.PayoutMethodStripeConnect, "acct_good", 0),
+			duePayout(2, models.PayoutMethodStripeConnect, "acct_closed", 1),
+			duePayout(3, models.PayoutMethodStripeConnect, "acct_closed", 2),
+			duePayout(4, models.PayoutMethodBankTransfer, "DE89370400440532013000", 0),
+		},
+		paid:   map[int]string{},
+		failed: map[int]failure{},
+	}
+	provider := &fakeProvider{refuse: map[string]bool{"acct_closed": true}}
+	delays := models.SettlementDelays{Low: 72 * time.Hour, Standard: 168 * time.Hour, High: 336 * time.Hour, Trusted: 24 * time.Hour}
+	r := NewRunner(store, provider, "stripe", 10, delays, 3, time.Hour)
+	r.now = func() time.Time { return now }
+
+	require.NoError(t, r.RunOnce(context.Background()))
+	require.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), store.before, "only closed months are paid out")
+	require.Equal(t, 10.0, store.minimum)
+	require.Equal(t, delays, store.delays)
+
+	require.Equal(t, map[int]string{1: "tr_payout-1"}, store.paid)
+	require.Equal(t, money.Cents(4250), provider.requests[0].Amount)
+	require.Len(t, provider.requests, 3, "unsupported methods are not sent")
+
+	retry := now.Add(2 * time.Hour)
+	require.Equal(t, failure{"no such destination", &retry}, store.failed[2], "backoff doubles per attempt")
+	require.Nil(t, store.failed[3].retryAt, "the last attempt fails the payout")
+	require.Contains(t, store.failed[4].reason, "not supported")
+}
+
+func TestRunner_RunOnce_Error(t *testing.T) {
+	store := &fakeStore{batchErr: errors.New("db down")}
+	r := NewRunner(store, &fakeProvider{}, "stripe", 10, models.SettlementDelays{}, 3, time.Hour)
+	require.Error(t, r.RunOnce(context.Background()))
+}
+
+func TestNormalizeAccount(t *testing.T) {
+	both := []string{models.PayoutMethodBankTransfer, models.PayoutMethodStripeConnect}
+
+	iban, err := NormalizeAccount(models.PayoutMethodBankTransfer, "de89 3704 0044 0532 0130 00", both)
+	require.NoError(t, err)
+	require.Equal(t, "DE89370400440532013000", iban)
+
+	_, err = NormalizeAccount(models.PayoutMethodBankTransfer, "DE89370400440532013001", both)
+	require.Error(t, err, "bad checksum")
+
+	acct, err := NormalizeAccount(models.PayoutMethodStripeConnect, " acct_1Nq2w3E4r5 ", both)
+	require.NoError(t, err)
+	require.Equal(t, "acct_1Nq2w3E4r5", acct)
+
+	_, err = NormalizeAccount(models.PayoutMethodStripeConnect, "DE89370400440532013000", both)
+	require.Error(t, err)
+
+	_, err = NormalizeAccount(models.PayoutMethodBankTransfer, "DE89370400440532013000", []string{models.PayoutMethodStripeConnect})
+	require.Error(t, err)
+}
@@ -0,0 +1,217 @@
+package productimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"golang.org/x/net/html"
+)
+
+// Generic reads the schema.org Product most shops embed as JSON-LD for
+// search engines, and falls back to Open Graph product tags.
+type Generic struct{}
+
+func (Generic) Name() string { return "generic" }
+
+func (Generic) Match(*url.URL) bool { return true }
+
+func (Generic) Parse(_ *url.URL, page []byte) (*models.ScrapedProduct, error) {
+	doc := scan(page)
+
+	product := &models.ScrapedProduct{}
+	for _, block := range doc.jsonLD {
+		if ld := findProduct(block); ld != nil {
+			product = ld
+			break
+		}
+	}
+
+	if product.Title == "" {
+		product.Title = firstNonEmpty(doc.meta["og:title"], doc.title)
+	}
+	if product.Description == "" {
+		product.Description = firstNonEmpty(doc.meta["og:description"], doc.meta["description"])
+	}
+	if product.Price == 0 {
+		product.Price = parsePrice(firstNonEmpty(doc.meta["product:price:amount"], doc.meta["og:price:amount"]))
+	}
+	if product.Currency == "" {
+		product.Currency = firstNonEmpty(doc.meta["product:price:currency"], doc.meta["og:price:currency"])
+	}
+	if len(product.Images) == 0 {
+		product.Images = doc.ogImages
+	}
+
+	product.Title = strings.TrimSpace(product.Title)
+	if product.Title == "" {
+		return nil, ErrNoProduct
+	}
+	return product, nil
+}
+
+// document is what Generic needs from a page.
+type document struct {
+	title    string
+	meta     map[string]string
+	ogImages []string
+	jsonLD   []any
+}
+
+func scan(page []byte) *document {
+	doc := &document{meta: map[string]string{}}
+	z := html.NewTokenizer(bytes.NewReader(page))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return doc
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			switch tok.Data {
+			case "title":
+				if doc.title == "" && z.Next() == html.TextToken {
+					doc.title = html.UnescapeString(string(z.Text()))
+				}
+			case "meta":
+				key := strings.ToLower(firstNonEmpty(attr(tok, "property"), attr(tok, "name")))
+				content := attr(tok, "content")
+				if key == "og:image" || key == "og:image:url" {
+					doc.ogImages = append(doc.ogImages, content)
+				} else if _, seen := doc.meta[key]; key != "" && !seen {
+					doc.meta[key] = content
+				}
+			case "script":
+				if !strings.EqualFold(attr(tok, "type"), "application/ld+json") || z.Next() != html.TextToken {
+					continue
+				}
+				var block any
+				if err := json.Unmarshal(z.Text(), &block); err == nil {
+					doc.jsonLD = append(doc.jsonLD, block)
+				}
+			}
+		}
+	}
+}
+
+func attr(tok html.Token, name string) string {
+	for _, a := range tok.Attr {
+		if a.Key == name {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+// findProduct looks for a Product in a JSON-LD block, which may be a single
+// node, a list of nodes or a node with an @graph.
+func findProduct(v any) *models.ScrapedProduct {
+	switch node := v.(type) {
+	case []any:
+		for _, n := range node {
+			if p := findProduct(n); p != nil {
+				return p
+			}
+		}
+	case map[string]any:
+		if isType(node["@type"], "Product") {
+			return productFromLD(node)
+		}
+		if graph, ok := node["@graph"]; ok {
+			return findProduct(graph)
+		}
+	}
+	return nil
+}
+
+func isType(v any, want string) bool {
+	switch t := v.(type) {
+	case string:
+		return t == want
+	case []any:
+		for _, s := range t {
+			if s == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func productFromLD(node map[string]any) *models.ScrapedProduct {
+	p := &models.ScrapedProduct{
+		Title:       str(node["name"]),
+		Description: str(node["description"]),
+		Images:      images(node["image"]),
+	}
+
+	offers := node["offers"]
+	if list, ok := offers.([]any); ok && len(list) > 0 {
+		offers = list[0]
+	}
+	if offer, ok := offers.(map[string]any); ok {
+		price := offer["price"]
+		if price == nil {
+			price = offer["lowPrice"]
+		}
+		p.Price = parsePrice(str(price))
+		p.Currency = str(offer["priceCurrency"])
+	}
+	return p
+}
+
+// images reads a schema.org image, which is a URL, an ImageObject or a list
+// of either.
+func images(v any) []string {
+	switch img := v.(type) {
+	case string:
+		return []string{img}
+	case map[string]any:
+		if u := str(img["url"]); u != "" {
+			return []string{u}
+		}
+	case []any:
+		var out []string
+		for _, i := range img {
+			out = append(out, images(i)...)
+		}
+		return out
+	}
+	return nil
+}
+
+func str(v any) string {
+	switch s := v.(type) {
+	case string:
+		return html.UnescapeString(strings.TrimSpace(s))
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64)
+	}
+	return ""
+}
+
+// parsePrice reads prices such as "19.99", "19,99" or "1,299.00".
+func parsePrice(s string) float64 {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndexAny(s, ".,"); i >= 0 && len(s)-i == 3 {
+		s = strings.NewReplacer(".", "", ",", "").Replace(s[:i]) + "." + s[i+1:]
+	} else {
+		s = strings.NewReplacer(".", "", ",", "").Replace(s)
+	}
+	price, err := strconv.ParseFloat(s, 64)
+	if err != nil || price < 0 {
+		return 0
+	}
+	return price
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,161 @@
+// Package productimport reads products from other shops' pages so sellers
+// can list them without retyping. Pages are fetched over HTTP(S) and read
+// by the first Parser that matches their URL, with Generic as the fallback.
+package productimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+)
+
+const maxRedirects = 5
+
+var (
+	ErrInvalidURL     = errors.New("url must be an absolute http or https URL")
+	ErrBlockedAddress = errors.New("url points to a private or local address")
+	ErrPageTooLarge   = errors.New("product page is too large")
+	ErrNoProduct      = errors.New("no product found on the page")
+)
+
+// Parser extracts a product from a fetched page.
+type Parser interface {
+	Name() string
+	// Match reports whether the parser understands pages at u.
+	Match(u *url.URL) bool
+	// Parse reads the product from page, which was served from u. It
+	// returns ErrNoProduct when the page does not describe a product.
+	Parse(u *url.URL, page []byte) (*models.ScrapedProduct, error)
+}
+
+// Importer fetches product pages. It only connects to public addresses,
+// checked after DNS resolution, so sellers cannot make it reach internal
+// services.
+type Importer struct {
+	client      *http.Client
+	maxPageSize int64
+	parsers     []Parser
+	// allowAddr decides which addresses may be dialed.
+	allowAddr func(netip.Addr) bool
+}
+
+// New creates an Importer that tries parsers in order before Generic.
+func New(timeout time.Duration, maxPageSize int64, parsers ...Parser) *Importer {
+	im := &Importer{
+		maxPageSize: maxPageSize,
+		parsers:     append(parsers, Generic{}),
+		allowAddr:   publicAddr,
+	}
+
+	dialer := &net.Dialer{Timeout: timeout, Control: im.control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would dial on our behalf and bypass the address check.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	im.client = &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return ErrInvalidURL
+			}
+			return nil
+		},
+	}
+	return im
+}
+
+func (im *Importer) control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !im.allowAddr(addr.Unmap()) {
+		return ErrBlockedAddress
+	}
+	return nil
+}
+
+func publicAddr(addr netip.Addr) bool {
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
+
+// Fetch downloads the page at rawURL and reads the product on it with the
+// matching parser, whose name it returns too. Image URLs are made absolute.
+func (im *Importer) Fetch(ctx context.Context, rawURL string) (*models.ScrapedProduct, string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, "", ErrInvalidURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("User-Agent", "MarketbackProductImport/1.0")
+
+	resp, err := im.client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrBlockedAddress) || errors.Is(err, ErrInvalidURL) {
+			return nil, "", err
+		}
+		return nil, "", fmt.Errorf("failed to fetch product page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("product page returned %d", resp.StatusCode)
+	}
+
+	page, err := io.ReadAll(io.LimitReader(resp.Body, im.maxPageSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read product page: %w", err)
+	}
+	if int64(len(page)) > im.maxPageSize {
+		return nil, "", ErrPageTooLarge
+	}
+
+	// Redirects may have moved us; links on the page are relative to
+	// where it was served from.
+	final := resp.Request.URL
+	parser := im.parser(final)
+	product, err := parser.Parse(final, page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	images := make([]string, 0, len(product.Images))
+	for _, img := range product.Images {
+		ref, err := final.Parse(img)
+		if err != nil || (ref.Scheme != "http" && ref.Scheme != "https") {
+			continue
+		}
+		images = append(images, ref.String())
+	}
+	product.Images = images
+
+	return product, parser.Name(), nil
+}
+
+func (im *Importer) parser(u *url.URL) Parser {
+	for _, p := range im.parsers {
+		if p.Match(u) {
+			return p
+		}
+	}
+	return Generic{}
+}
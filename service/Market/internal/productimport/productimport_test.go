@@ -0,0 +1,185 @@
+package productimport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+)
+
+const jsonLDPage = `<!doctype html>
+<html><head>
+<title>Beanie | Example Shop</title>
+<meta property="og:title" content="Beanie on sale">
+<script type="application/ld+json">
+{"@context":"https://schema.org","@graph":[
+  {"@type":"BreadcrumbList"},
+  {"@type":["Product","Thing"],"name":"Wool beanie","description":"Warm &amp; soft",
+   "image":[{"@type":"ImageObject","url":"/img/beanie.jpg"},"https://cdn.example.com/beanie-2.jpg"],
+   "offers":[{"@type":"Offer","price":"19.99","priceCurrency":"EUR"}]}
+]}
+</script>
+</head><body></body></html>`
+
+const openGraphPage = `<html><head>
+<title>Scarf</title>
+<meta property="og:title" content="Striped scarf">
+<meta name="description" content="Long and striped">
+<meta property="og:image" content="https://cdn.example.com/scarf.jpg">
+<meta property="og:image" content="scarf-back.jpg">
+<meta property="product:price:amount" content="1.299,00">
+<meta property="product:price:currency" content="EUR">
+</head></html>`
+
+func TestGeneric_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		page    string
+		want    *models.ScrapedProduct
+		wantErr error
+	}{
+		{
+			name: "json-ld",
+			page: jsonLDPage,
+			want: &models.ScrapedProduct{
+				Title: "Wool beanie", Description: "Warm & soft", Price: 19.99, Currency: "EUR",
+				Images: []string{"/img/beanie.jpg", "https://cdn.example.com/beanie-2.jpg"},
+			},
+		},
+		{
+			name: "open graph",
+			page: openGraphPage,
+			want: &models.ScrapedProduct{
+				Title: "Striped scarf", Description: "Long and striped", Price: 1299, Currency: "EUR",
+				Images: []string{"https://cdn.example.com/scarf.jpg", "scarf-back.jpg"},
+			},
+		},
+		{
+			name:    "no product",
+			page:    `<html><body>Hello</body></html>`,
+			wantErr: ErrNoProduct,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Generic{}.Parse(&url.URL{}, []byte(tt.page))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParsePrice(t *testing.T) {
+	for in, want := range map[string]float64{
+		"19.99":    19.99,
+		"19,99":    19.99,
+		"1,299.00": 1299,
+		"1.299,00": 1299,
+		"1299":     1299,
+		"free":     0,
+		"-5":       0,
+	} {
+		assert.Equal(t, want, parsePrice(in), in)
+	}
+}
+
+// pathParser claims pages under /custom/.
+type pathParser struct{}
+
+func (pathParser) Name() string { return "custom" }
+
+func (pathParser) Match(u *url.URL) bool { return strings.HasPrefix(u.Path, "/custom/") }
+
+func (pathParser) Parse(*url.URL, []byte) (*models.ScrapedProduct, error) {
+	return &models.ScrapedProduct{Title: "Custom"}, nil
+}
+
+func TestImporter_Fetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/moved":
+			http.Redirect(w, r, "/shop/beanie", http.StatusFound)
+		case "/shop/beanie", "/custom/beanie":
+			w.Write([]byte(jsonLDPage))
+		case "/big":
+			w.Write([]byte(strings.Repeat("x", 2048)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	im := New(time.Second, 1024, pathParser{})
+	im.allowAddr = func(netip.Addr) bool { return true }
+	ctx := context.Background()
+
+	t.Run("resolves images against the final url", func(t *testing.T) {
+		product, parser, err := im.Fetch(ctx, srv.URL+"/moved")
+		require.NoError(t, err)
+		assert.Equal(t, "generic", parser)
+		assert.Equal(t, "Wool beanie", product.Title)
+		assert.Equal(t, []string{srv.URL + "/img/beanie.jpg", "https://cdn.example.com/beanie-2.jpg"}, product.Images)
+	})
+
+	t.Run("uses the matching parser", func(t *testing.T) {
+		product, parser, err := im.Fetch(ctx, srv.URL+"/custom/beanie")
+		require.NoError(t, err)
+		assert.Equal(t, "custom", parser)
+		assert.Equal(t, "Custom", product.Title)
+	})
+
+	t.Run("page too large", func(t *testing.T) {
+		_, _, err := im.Fetch(ctx, srv.URL+"/big")
+		assert.ErrorIs(t, err, ErrPageTooLarge)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, _, err := im.Fetch(ctx, srv.URL+"/missing")
+		assert.ErrorContains(t, err, "404")
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		for _, u := range []string{"ftp://example.com/x", "/relative", "file:///etc/passwd"} {
+			_, _, err := im.Fetch(ctx, u)
+			assert.ErrorIs(t, err, ErrInvalidURL, u)
+		}
+	})
+}
+
+func TestImporter_FetchBlocksPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(jsonLDPage))
+	}))
+	defer srv.Close()
+
+	_, _, err := New(time.Second, 1024).Fetch(context.Background(), srv.URL)
+	assert.ErrorIs(t, err, ErrBlockedAddress)
+
+	for addr, public := range map[string]bool{
+		"93.184.216.34":   true,
+		"2606:4700::1":    true,
+		"127.0.0.1":       false,
+		"10.0.0.8":        false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"::1":             false,
+		"fd00::1":         false,
+		"0.0.0.0":         false,
+	} {
+		assert.Equal(t, public, publicAddr(netip.MustParseAddr(addr)), addr)
+	}
+}
@@ -0,0 +1,101 @@
+// Package productviews counts product page views per product and day in
+// memory and flushes them into Postgres, where seller analytics read them.
+package productviews
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+	"github.com/Zifeldev/marketback/service/Market/internal/workers"
+)
+
+// Store adds views to the stored counts of a day (UTC).
+type Store interface {
+	AddViews(ctx context.Context, day time.Time, views map[int]int64) error
+}
+
+// Counter buffers views between flushes so product pages do not write to
+// the database. Views not yet flushed are lost if the process dies.
+type Counter struct {
+	store Store
+	now   func() time.Time
+
+	mu      sync.Mutex
+	pending map[time.Time]map[int]int64
+}
+
+func NewCounter(store Store) *Counter {
+	return &Counter{store: store, now: time.Now, pending: make(map[time.Time]map[int]int64)}
+}
+
+// Record counts a view of a product. A nil counter ignores views.
+func (c *Counter) Record(productID int) {
+	if c == nil {
+		return
+	}
+	t := c.now().UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pending[day] == nil {
+		c.pending[day] = make(map[int]int64)
+	}
+	c.pending[day][productID]++
+}
+
+// Flush writes the buffered views. Days that fail to write are kept for the
+// next flush.
+func (c *Counter) Flush(ctx context.Context) error {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = make(map[time.Time]map[int]int64)
+	c.mu.Unlock()
+
+	var firstErr error
+	for day, views := range pending {
+		if err := c.store.AddViews(ctx, day, views); err != nil {
+			logger.GetLogger().WithField("err", err).WithField("day", day.Format("2006-01-02")).Error("failed to flush product views")
+			c.restore(day, views)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+func (c *Counter) restore(day time.Time, views map[int]int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pending[day] == nil {
+		c.pending[day] = make(map[int]int64)
+	}
+	for productID, n := range views {
+		c.pending[day][productID] += n
+	}
+}
+
+// Start flushes every interval until ctx is cancelled. Callers flush once
+// more on shutdown.
+func (c *Counter) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		workers.Beat(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := c.Flush(ctx); err != nil {
+			metrics.ProductViewFlushFailuresTotal.Inc()
+		}
+	}
+}
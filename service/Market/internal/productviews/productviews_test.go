@@ -0,0 +1,69 @@
+package productviews
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	added map[time.Time]map[int]int64
+	err   error
+}
+
+func (f *fakeStore) AddViews(ctx context.Context, day time.Time, views map[int]int64) error {
+	if f.err != nil {
+		return f.err
+	}
+	if f.added[day] == nil {
+		f.added[day] = map[int]int64{}
+	}
+	for productID, n := range views {
+		f.added[day][productID] += n
+	}
+	return nil
+}
+
+func TestCounter_FlushPerDay(t *testing.T) {
+	store := &fakeStore{added: map[time.Time]map[int]int64{}}
+	c := NewCounter(store)
+	now := time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	c.Record(4)
+	c.Record(4)
+	now = now.Add(2 * time.Minute)
+	c.Record(4)
+	c.Record(7)
+
+	require.NoError(t, c.Flush(context.Background()))
+	require.Equal(t, map[time.Time]map[int]int64{
+		time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC): {4: 2},
+		time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC): {4: 1, 7: 1},
+	}, store.added)
+
+	require.NoError(t, c.Flush(context.Background()))
+	require.Equal(t, int64(1), store.added[time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)][7], "flushed views are not written twice")
+}
+
+func TestCounter_FlushErrorKeepsViews(t *testing.T) {
+	store := &fakeStore{added: map[time.Time]map[int]int64{}, err: errors.New("db down")}
+	c := NewCounter(store)
+	c.now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) }
+
+	c.Record(4)
+	require.Error(t, c.Flush(context.Background()))
+	c.Record(4)
+
+	store.err = nil
+	require.NoError(t, c.Flush(context.Background()))
+	require.Equal(t, int64(2), store.added[time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)][4])
+}
+
+func TestCounter_Nil(t *testing.T) {
+	var c *Counter
+	c.Record(4)
+}
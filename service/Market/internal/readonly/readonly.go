@@ -0,0 +1,105 @@
+// Package readonly lets operators freeze writes to individual tables (for
+// example while a schema migration runs on products or orders) without
+// taking the whole service down. Reads are never affected.
+package readonly
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+)
+
+const (
+	TableProducts = "products"
+	TableOrders   = "orders"
+)
+
+// Guard holds the set of tables that currently reject mutations. A nil
+// *Guard treats every table as writable.
+type Guard struct {
+	mu     sync.RWMutex
+	tables map[string]struct{}
+}
+
+// New returns a guard with the given tables already locked.
+func New(tables ...string) *Guard {
+	g := &Guard{tables: make(map[string]struct{})}
+	g.Lock(tables...)
+	return g
+}
+
+// Parse builds a guard from a comma-separated list such as "products,orders".
+func Parse(spec string) *Guard {
+	return New(strings.Split(spec, ",")...)
+}
+
+// Lock marks tables as read-only.
+func (g *Guard) Lock(tables ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	add(g.tables, tables)
+}
+
+// Unlock makes tables writable again.
+func (g *Guard) Unlock(tables ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, t := range tables {
+		delete(g.tables, normalize(t))
+	}
+}
+
+// Set replaces the locked set with exactly the given tables. Tables in
+// both the old and the new set stay locked throughout.
+func (g *Guard) Set(tables ...string) {
+	locked := make(map[string]struct{}, len(tables))
+	add(locked, tables)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.tables = locked
+}
+
+// Tables returns the locked tables in sorted order.
+func (g *Guard) Tables() []string {
+	if g == nil {
+		return []string{}
+	}
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	out := make([]string, 0, len(g.tables))
+	for t := range g.tables {
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Check returns an apperrors.ReadOnly error for the first locked table
+// among the arguments, or nil when all of them are writable.
+func (g *Guard) Check(tables ...string) error {
+	if g == nil {
+		return nil
+	}
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	for _, t := range tables {
+		if _, ok := g.tables[t]; ok {
+			return apperrors.ReadOnly(t)
+		}
+	}
+	return nil
+}
+
+func add(set map[string]struct{}, tables []string) {
+	for _, t := range tables {
+		if t = normalize(t); t != "" {
+			set[t] = struct{}{}
+		}
+	}
+}
+
+func normalize(table string) string {
+	return strings.ToLower(strings.TrimSpace(table))
+}
@@ -0,0 +1,70 @@
+package readonly
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNilGuardAllowsWrites(t *testing.T) {
+	var g *Guard
+	assert.NoError(t, g.Check(TableProducts, TableOrders))
+	assert.Empty(t, g.Tables())
+}
+
+func TestParse(t *testing.T) {
+	g := Parse(" Products, ,orders ")
+	assert.Equal(t, []string{"orders", "products"}, g.Tables())
+	assert.Empty(t, Parse("").Tables())
+}
+
+func TestCheckReturnsServiceUnavailable(t *testing.T) {
+	g := New(TableOrders)
+
+	assert.NoError(t, g.Check(TableProducts))
+
+	err := g.Check(TableProducts, TableOrders)
+	require.Error(t, err)
+	appErr := apperrors.GetAppError(err)
+	require.NotNil(t, appErr)
+	assert.Equal(t, apperrors.CodeReadOnly, appErr.Code)
+	assert.Equal(t, http.StatusServiceUnavailable, appErr.HTTPStatus)
+	assert.Contains(t, appErr.Message, "orders")
+}
+
+func TestLockUnlockSet(t *testing.T) {
+	g := New()
+	g.Lock(TableProducts)
+	assert.Error(t, g.Check(TableProducts))
+
+	g.Unlock(TableProducts)
+	assert.NoError(t, g.Check(TableProducts))
+
+	g.Lock(TableProducts)
+	g.Set(TableOrders)
+	assert.Equal(t, []string{"orders"}, g.Tables())
+}
+
+func TestSetKeepsTablesLocked(t *testing.T) {
+	g := New(TableProducts)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 10000 {
+			g.Set(TableProducts, TableOrders)
+			g.Set(TableProducts)
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			return
+		default:
+			require.Error(t, g.Check(TableProducts), "products were writable while being set")
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Package reload applies the runtime-adjustable part of the configuration
+// (log level, rate limits, read-only tables) without restarting the process.
+package reload
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"syscall"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/config"
+	"github.com/Zifeldev/marketback/service/Market/internal/middleware"
+	"github.com/Zifeldev/marketback/service/Market/internal/readonly"
+	"github.com/sirupsen/logrus"
+)
+
+// Loader reads the configuration, normally config.Load.
+type Loader func(ctx context.Context) (*config.Config, error)
+
+// Reloader re-reads the configuration and applies settings whose value
+// changed since the previous load. Unchanged settings are left alone, so a
+// reload never undoes e.g. read-only tables set through the admin API.
+type Reloader struct {
+	mu        sync.Mutex
+	load      Loader
+	current   *config.Config
+	log       *logrus.Logger
+	rateLimit *middleware.RateLimitPolicy
+	readOnly  *readonly.Guard
+}
+
+// New returns a Reloader starting from current. rateLimit and readOnly may
+// be nil when the respective feature is not wired.
+func New(current *config.Config, load Loader, log *logrus.Logger, rateLimit *middleware.RateLimitPolicy, readOnly *readonly.Guard) *Reloader {
+	return &Reloader{
+		load:      load,
+		current:   current,
+		log:       log,
+		rateLimit: rateLimit,
+		readOnly:  readOnly,
+	}
+}
+
+// Reload loads the configuration again and returns the names of the
+// settings it changed. Nothing is applied when loading fails.
+func (r *Reloader) Reload(ctx context.Context) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := r.load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	level, err := logrus.ParseLevel(next.Logger.Level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOG_LEVEL: %w", err)
+	}
+
+	changed := []string{}
+	if next.Logger.Level != r.current.Logger.Level {
+		r.log.SetLevel(level)
+		changed = append(changed, "LOG_LEVEL")
+	}
+
+	if r.rateLimit != nil && next.RateLimit != r.current.RateLimit {
+		r.rateLimit.Update(next.RateLimit.Enabled, next.RateLimit.Max, next.RateLimit.Interval)
+		changed = append(changed, "RATE_LIMIT")
+	}
+
+	if r.readOnly != nil {
+		prev := readonly.Parse(r.current.Maintenance.ReadOnlyTables).Tables()
+		tables := readonly.Parse(next.Maintenance.ReadOnlyTables).Tables()
+		if !slices.Equal(prev, tables) {
+			r.readOnly.Set(tables...)
+			changed = append(changed, "READ_ONLY_TABLES")
+		}
+	}
+
+	r.current = next
+	r.log.WithField("changed", changed).Info("Configuration reloaded")
+	return changed, nil
+}
+
+// WatchSignals reloads on every SIGHUP until ctx is cancelled.
+func (r *Reloader) WatchSignals(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			if _, err := r.Reload(ctx); err != nil {
+				r.log.WithField("err", err).Error("Configuration reload failed, keeping current settings")
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package reload
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/config"
+	"github.com/Zifeldev/marketback/service/Market/internal/middleware"
+	"github.com/Zifeldev/marketback/service/Market/internal/readonly"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func baseConfig() *config.Config {
+	return &config.Config{
+		Logger:    config.LoggerConfig{Level: "info"},
+		RateLimit: config.RateLimitConfig{Enabled: true, Max: 100, Interval: time.Minute},
+	}
+}
+
+func newTestLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return log
+}
+
+func TestReload_AppliesChangedSettings(t *testing.T) {
+	next := baseConfig()
+	next.Logger.Level = "debug"
+	next.RateLimit.Max = 10
+	next.Maintenance.ReadOnlyTables = "products"
+
+	log := newTestLogger()
+	guard := readonly.New()
+	r := New(baseConfig(), func(context.Context) (*config.Config, error) { return next, nil },
+		log, middleware.NewRateLimitPolicy(true, 100, time.Minute), guard)
+
+	changed, err := r.Reload(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"LOG_LEVEL", "RATE_LIMIT", "READ_ONLY_TABLES"}, changed)
+	require.Equal(t, logrus.DebugLevel, log.GetLevel())
+	require.Equal(t, []string{"products"}, guard.Tables())
+}
+
+func TestReload_KeepsRuntimeReadOnlyChanges(t *testing.T) {
+	guard := readonly.New()
+	r := New(baseConfig(), func(context.Context) (*config.Config, error) { return baseConfig(), nil },
+		newTestLogger(), nil, guard)
+
+	// Set through the admin API after startup.
+	guard.Set("orders")
+
+	changed, err := r.Reload(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, changed)
+	require.Equal(t, []string{"orders"}, guard.Tables())
+}
+
+func TestReload_LoadErrorKeepsSettings(t *testing.T) {
+	log := newTestLogger()
+	r := New(baseConfig(), func(context.Context) (*config.Config, error) { return nil, errors.New("invalid RATE_LIMIT_MAX") },
+		log, nil, nil)
+
+	_, err := r.Reload(context.Background())
+	require.Error(t, err)
+	require.Equal(t, logrus.InfoLevel, log.GetLevel())
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// adminViewColumns select an admin_views row into models.AdminView.
+var adminViewColumns = []string{"id", "admin_id", "list", "name", "query", "created_at", "updated_at"}
+
+type AdminViewRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAdminViewRepository(db *pgxpool.Pool) *AdminViewRepository {
+	return &AdminViewRepository{db: db}
+}
+
+// Save stores a view for the admin, replacing the query of their view of
+// the same list and name if there is one.
+func (r *AdminViewRepository) Save(ctx context.Context, adminID int, req *models.SaveAdminViewRequest) (*models.AdminView, error) {
+	query, args, err := psql.Insert("admin_views").
+		Columns("admin_id", "list", "name", "query").
+		Values(adminID, req.List, req.Name, req.Query).
+		Suffix("ON CONFLICT (admin_id, list, name) DO UPDATE SET query = EXCLUDED.query, updated_at = NOW()").
+		Suffix(returning(adminViewColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build save admin view query")
+		return nil, fmt.Errorf("failed to build save admin view query: %w", err)
+	}
+
+	var view models.AdminView
+	if err := pgxscan.Get(ctx, r.db, &view, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to save admin view")
+		return nil, fmt.Errorf("failed to save admin view: %w", err)
+	}
+
+	return &view, nil
+}
+
+// GetByAdmin lists the admin's views by name, optionally for one list only.
+func (r *AdminViewRepository) GetByAdmin(ctx context.Context, adminID int, list string) ([]*models.AdminView, error) {
+	where := sq.Eq{"admin_id": adminID}
+	if list != "" {
+		where["list"] = list
+	}
+
+	query, args, err := psql.Select(adminViewColumns...).From("admin_views").
+		Where(where).
+		OrderBy("list", "name").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build admin views query")
+		return nil, fmt.Errorf("failed to build admin views query: %w", err)
+	}
+
+	views := []*models.AdminView{}
+	if err := pgxscan.Select(ctx, r.db, &views, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get admin views")
+		return nil, fmt.Errorf("failed to get admin views: %w", err)
+	}
+
+	return views, nil
+}
+
+// Delete removes one of the admin's views; other admins' views are not
+// found.
+func (r *AdminViewRepository) Delete(ctx context.Context, id, adminID int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM admin_views WHERE id = $1 AND admin_id = $2`, id, adminID)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to delete admin view")
+		return fmt.Errorf("failed to delete admin view: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperrors.NotFound(fmt.Sprintf("view with id %d not found", id))
+	}
+
+	return nil
+}
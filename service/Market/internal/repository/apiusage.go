@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// apiUsageColumns select a seller_api_usage row into models.APIUsage.
+var apiUsageColumns = []string{"to_char(day, 'YYYY-MM-DD') AS day", "requests", "errors", "throttled"}
+
+type APIUsageRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAPIUsageRepository(db *pgxpool.Pool) *APIUsageRepository {
+	return &APIUsageRepository{db: db}
+}
+
+// UpsertAPIUsage stores daily counters by user ID, replacing earlier
+// rollups of the same day.
+func (r *APIUsageRepository) UpsertAPIUsage(ctx context.Context, usage map[int]*models.APIUsage) error {
+	if len(usage) == 0 {
+		return nil
+	}
+
+	builder := psql.Insert("seller_api_usage").Columns("user_id", "day", "requests", "errors", "throttled")
+	for userID, u := range usage {
+		builder = builder.Values(userID, sq.Expr("?::date", u.Day), u.Requests, u.Errors, u.Throttled)
+	}
+	query, args, err := builder.
+		Suffix(`ON CONFLICT (user_id, day) DO UPDATE SET requests = EXCLUDED.requests,
+			errors = EXCLUDED.errors, throttled = EXCLUDED.throttled`).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build upsert api usage query")
+		return fmt.Errorf("failed to build upsert api usage query: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to upsert api usage")
+		return fmt.Errorf("failed to upsert api usage: %w", err)
+	}
+	return nil
+}
+
+// GetAPIUsage returns the rolled up usage of a user from since (YYYY-MM-DD)
+// on, newest day first.
+func (r *APIUsageRepository) GetAPIUsage(ctx context.Context, userID int, since string) ([]*models.APIUsage, error) {
+	query, args, err := psql.Select(apiUsageColumns...).
+		From("seller_api_usage").
+		Where(sq.Eq{"user_id": userID}).
+		Where("day >= ?::date", since).
+		OrderBy("day DESC").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build api usage query")
+		return nil, fmt.Errorf("failed to build api usage query: %w", err)
+	}
+
+	usage := []*models.APIUsage{}
+	if err := pgxscan.Select(ctx, r.db, &usage, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get api usage")
+		return nil, fmt.Errorf("failed to get api usage: %w", err)
+	}
+	return usage, nil
+}
+
+// GetAPIPlan returns the API plan of the seller owned by a user, or "" when
+// the user is not a seller.
+func (r *APIUsageRepository) GetAPIPlan(ctx context.Context, userID int) (string, error) {
+	var plan string
+	err := r.db.QueryRow(ctx, `SELECT api_plan FROM sellers WHERE user_id = $1`, userID).Scan(&plan)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get api plan")
+		return "", fmt.Errorf("failed to get api plan: %w", err)
+	}
+	return plan, nil
+}
+
+// SetAPIPlan moves a seller to another API plan.
+func (r *APIUsageRepository) SetAPIPlan(ctx context.Context, sellerID int, plan string) (*models.Seller, error) {
+	query, args, err := psql.Update("sellers").
+		Set("api_plan", plan).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": sellerID}).
+		Suffix(returning(sellerColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build update api plan query")
+		return nil, fmt.Errorf("failed to build update api plan query: %w", err)
+	}
+
+	var seller models.Seller
+	if err := pgxscan.Get(ctx, r.db, &seller, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.SellerNotFound(sellerID)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to update api plan")
+		return nil, fmt.Errorf("failed to update api plan: %w", err)
+	}
+	return &seller, nil
+}
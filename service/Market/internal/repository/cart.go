@@ -2,55 +2,96 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
 	"github.com/Zifeldev/marketback/service/Market/internal/logger"
 	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// cartItemColumns select a cart_items row into models.CartItem; UserID comes
+// from the cart and is set by the caller.
+var cartItemColumns = []string{
+	"id", "product_id", "quantity", "COALESCE(size, '') AS size", "variant_id", "COALESCE(color, '') AS color",
+	"created_at", "updated_at",
+}
+
 type CartRepository struct {
-	db *pgxpool.Pool
+	db             *pgxpool.Pool
+	reservationTTL time.Duration
 }
 
-func NewCartRepository(db *pgxpool.Pool) *CartRepository {
-	return &CartRepository{db: db}
+// NewCartRepository creates the cart repository. Adding or updating a cart
+// item reserves its stock for reservationTTL; with a zero TTL nothing is
+// reserved and stock is only checked at checkout.
+func NewCartRepository(db *pgxpool.Pool, reservationTTL time.Duration) *CartRepository {
+	return &CartRepository{db: db, reservationTTL: reservationTTL}
 }
 
+// AddItem adds the product, or its variant, to the user's cart, or adds to
+// the quantity of the matching item. It fails with insufficient stock,
+// leaving the cart as it was, when the available stock cannot cover the
+// item.
 func (r *CartRepository) AddItem(ctx context.Context, userID int, req *models.AddToCartRequest) (*models.CartItem, error) {
 	cartID, err := r.getOrCreateCartID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get or create cart: %w", err)
 	}
 
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	variant, err := itemVariant(ctx, tx, req.ProductID, req.VariantID)
+	if err != nil {
+		return nil, err
+	}
+	// Variant items take the variant's size and color; each variant is
+	// then one item of the cart.
+	size, color := req.Size, interface{}(nil)
+	if variant != nil {
+		size, color = variant.Size, variant.Color
+	}
+
 	query, args, err := psql.Insert("cart_items").
-		Columns("cart_id", "product_id", "quantity", "size", "color").
-		Values(cartID, req.ProductID, req.Quantity, req.Size, nil).
+		Columns("cart_id", "product_id", "quantity", "size", "color", "variant_id").
+		Values(cartID, req.ProductID, req.Quantity, size, color, req.VariantID).
 		Suffix("ON CONFLICT (cart_id, product_id, size, color) DO UPDATE SET quantity = cart_items.quantity + EXCLUDED.quantity, updated_at = NOW()").
-		Suffix("RETURNING id, cart_id, product_id, quantity, COALESCE(size, '') as size, created_at, updated_at").
+		Suffix(returning(cartItemColumns)).
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build add item query: %w", err)
 	}
 
-	var item models.CartItem
-	var returnedCartID int
-	err = r.db.QueryRow(ctx, query, args...).Scan(
-		&item.ID,
-		&returnedCartID,
-		&item.ProductID,
-		&item.Quantity,
-		&item.Size,
-		&item.CreatedAt,
-		&item.UpdatedAt,
-	)
-
+	stock, err := r.lockStock(ctx, tx, req.ProductID, req.VariantID)
 	if err != nil {
+		return nil, err
+	}
+
+	var item models.CartItem
+	if err := pgxscan.Get(ctx, tx, &item, query, args...); err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to add item to cart")
 		return nil, fmt.Errorf("failed to add item to cart: %w", err)
 	}
 
+	if err := r.reserve(ctx, tx, &item, stock); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	item.UserID = userID
 	return &item, nil
 }
@@ -89,13 +130,21 @@ func (r *CartRepository) getOrCreateCartID(ctx context.Context, userID int) (int
 
 func (r *CartRepository) GetUserCart(ctx context.Context, userID int) ([]*models.CartItemWithDetails, error) {
 	query, args, err := psql.Select(
-		"ci.id", "c.user_id", "ci.product_id", "ci.quantity", "COALESCE(ci.size, '') as size", "ci.created_at", "ci.updated_at",
-		"p.title as product_title",
-		"p.price::float8 as product_price",
-		"COALESCE(p.image_url, '') as product_image",
+		"ci.id", "c.user_id", "ci.product_id", "ci.quantity", "COALESCE(ci.size, '') AS size", "r.expires_at AS reserved_until",
+		"ci.variant_id", "COALESCE(ci.color, '') AS color", "COALESCE(v.sku, '') AS sku",
+		"ci.created_at", "ci.updated_at",
+		"p.title AS product_title",
+		"(p.price + COALESCE(v.price_delta, 0))::float8 AS product_price",
+		"COALESCE(p.image_url, '') AS product_image",
+		"COALESCE(v.weight_grams, p.weight_grams) AS weight_grams",
+		"COALESCE(v.length_mm, p.length_mm) AS length_mm",
+		"COALESCE(v.width_mm, p.width_mm) AS width_mm",
+		"COALESCE(v.height_mm, p.height_mm) AS height_mm",
 	).From("cart_items ci").
 		Join("carts c ON ci.cart_id = c.id").
 		Join("products p ON ci.product_id = p.id").
+		LeftJoin("product_variants v ON v.id = ci.variant_id").
+		LeftJoin("stock_reservations r ON r.cart_item_id = ci.id AND r.expires_at > NOW()").
 		Where(sq.Eq{"c.user_id": userID}).
 		OrderBy("ci.created_at DESC").
 		ToSql()
@@ -103,36 +152,17 @@ func (r *CartRepository) GetUserCart(ctx context.Context, userID int) ([]*models
 		return nil, fmt.Errorf("failed to build get cart query: %w", err)
 	}
 
-	rows, err := r.db.Query(ctx, query, args...)
-	if err != nil {
+	var items []*models.CartItemWithDetails
+	if err := pgxscan.Select(ctx, r.db, &items, query, args...); err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to get user cart")
 		return nil, fmt.Errorf("failed to get user cart: %w", err)
 	}
-	defer rows.Close()
-
-	var items []*models.CartItemWithDetails
-	for rows.Next() {
-		var item models.CartItemWithDetails
-		if err := rows.Scan(
-			&item.ID,
-			&item.UserID,
-			&item.ProductID,
-			&item.Quantity,
-			&item.Size,
-			&item.CreatedAt,
-			&item.UpdatedAt,
-			&item.ProductTitle,
-			&item.ProductPrice,
-			&item.ProductImage,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan cart item: %w", err)
-		}
-		items = append(items, &item)
-	}
 
 	return items, nil
 }
 
+// UpdateItem changes the quantity, and optionally the size, of one of the
+// user's cart items and renews its reservation.
 func (r *CartRepository) UpdateItem(ctx context.Context, itemID, userID int, req *models.UpdateCartItemRequest) (*models.CartItem, error) {
 	updateBuilder := psql.Update("cart_items").
 		Set("quantity", req.Quantity).
@@ -141,7 +171,7 @@ func (r *CartRepository) UpdateItem(ctx context.Context, itemID, userID int, req
 			sq.Eq{"id": itemID},
 			sq.Expr("cart_id = (SELECT id FROM carts WHERE user_id = ?)", userID),
 		}).
-		Suffix("RETURNING id, cart_id, product_id, quantity, COALESCE(size, '') as size, created_at, updated_at")
+		Suffix(returning(cartItemColumns))
 
 	if req.Size != "" {
 		updateBuilder = updateBuilder.Set("size", req.Size)
@@ -152,23 +182,48 @@ func (r *CartRepository) UpdateItem(ctx context.Context, itemID, userID int, req
 		return nil, fmt.Errorf("failed to build update cart item query: %w", err)
 	}
 
-	var item models.CartItem
-	var returnedCartID int
-	err = r.db.QueryRow(ctx, query, args...).Scan(
-		&item.ID,
-		&returnedCartID,
-		&item.ProductID,
-		&item.Quantity,
-		&item.Size,
-		&item.CreatedAt,
-		&item.UpdatedAt,
-	)
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
 
+	// Lock the product before the item, in the same order as AddItem.
+	var productID int
+	var variantID *int
+	err = tx.QueryRow(ctx, `SELECT ci.product_id, ci.variant_id FROM cart_items ci JOIN carts c ON c.id = ci.cart_id
+		WHERE ci.id = $1 AND c.user_id = $2`, itemID, userID).Scan(&productID, &variantID)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.CartItemNotFound(itemID)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get cart item")
+		return nil, fmt.Errorf("failed to get cart item: %w", err)
+	}
+	if variantID != nil && req.Size != "" {
+		return nil, apperrors.ValidationError("size", "the size of a variant cannot change; add another variant instead")
+	}
+	stock, err := r.lockStock(ctx, tx, productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var item models.CartItem
+	if err := pgxscan.Get(ctx, tx, &item, query, args...); err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to update cart item")
 		return nil, fmt.Errorf("failed to update cart item: %w", err)
 	}
 
+	if err := r.reserve(ctx, tx, &item, stock); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	item.UserID = userID
 	return &item, nil
 }
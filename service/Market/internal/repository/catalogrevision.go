@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/readonly"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// catalogRevisionColumns select a catalog_revisions row, without its
+// products, into models.CatalogRevision.
+var catalogRevisionColumns = []string{
+	"id", "seller_id", "version", "reason", "note", "product_count", "created_by", "created_at",
+}
+
+// snapshotCatalogQuery records the products of seller $1 with their
+// variants as the seller's next revision.
+var snapshotCatalogQuery = `INSERT INTO catalog_revisions (seller_id, version, reason, note, created_by, product_count, products)
+	SELECT $1, (SELECT COALESCE(MAX(version), 0) + 1 FROM catalog_revisions WHERE seller_id = $1), $2, $3, $4,
+		COUNT(*), COALESCE(jsonb_agg(jsonb_build_object(
+			'id', p.id, 'category_id', p.category_id, 'title', p.title, 'description', COALESCE(p.description, ''),
+			'price', p.price, 'stock', p.stock, 'sizes', COALESCE(p.sizes, '[]'::jsonb),
+			'image_url', COALESCE(p.image_url, ''), 'status', p.status,
+			'weight_grams', p.weight_grams, 'length_mm', p.length_mm, 'width_mm', p.width_mm, 'height_mm', p.height_mm,
+			'variants', COALESCE((SELECT jsonb_agg(jsonb_build_object(
+				'id', v.id, 'sku', v.sku, 'size', v.size, 'color', v.color, 'price_delta', v.price_delta, 'stock', v.stock,
+				'weight_grams', v.weight_grams, 'length_mm', v.length_mm, 'width_mm', v.width_mm, 'height_mm', v.height_mm
+			) ORDER BY v.id) FROM product_variants v WHERE v.product_id = p.id), '[]'::jsonb)
+		) ORDER BY p.id), '[]'::jsonb)
+	FROM products p WHERE p.seller_id = $1
+	RETURNING ` + strings.Join(catalogRevisionColumns, ", ")
+
+// The restore queries read the revision's products ($2) or variants ($1)
+// as JSON records. Blocked products and those awaiting moderation keep
+// their status and none is blocked by a restore; stock is only rolled back when $3 (products) or $2 (variants)
+// is true. Categories removed since are left empty, and dimensions missing
+// from revisions taken before they were recorded are kept.
+const (
+	restoreProductsQuery = `UPDATE products p SET
+			category_id = (SELECT c.id FROM categories c WHERE c.id = s.category_id),
+			title = s.title, description = s.description, price = s.price,
+			stock = CASE WHEN $3 THEN s.stock ELSE p.stock END,
+			sizes = s.sizes, image_url = s.image_url,
+			status = CASE WHEN p.status IN ('blocked', 'pending') OR s.status = 'blocked' THEN p.status ELSE s.status END,
+			weight_grams = COALESCE(s.weight_grams, p.weight_grams), length_mm = COALESCE(s.length_mm, p.length_mm),
+			width_mm = COALESCE(s.width_mm, p.width_mm), height_mm = COALESCE(s.height_mm, p.height_mm),
+			updated_at = NOW()
+		FROM jsonb_to_recordset($2::jsonb) AS s(id int, category_id int, title text, description text,
+			price numeric, stock int, sizes jsonb, image_url text, status text,
+			weight_grams int, length_mm int, width_mm int, height_mm int)
+		WHERE p.id = s.id AND p.seller_id = $1`
+
+	recreateProductsQuery = `INSERT INTO products (id, seller_id, category_id, title, description, price, stock, sizes, image_url, status,
+			weight_grams, length_mm, width_mm, height_mm)
+		SELECT s.id, $1, (SELECT c.id FROM categories c WHERE c.id = s.category_id), s.title, s.description, s.price,
+			CASE WHEN $3 THEN s.stock ELSE 0 END, s.sizes, s.image_url,
+			CASE WHEN s.status = 'blocked' THEN 'pending' ELSE s.status END,
+			s.weight_grams, s.length_mm, s.width_mm, s.height_mm
+		FROM jsonb_to_recordset($2::jsonb) AS s(id int, category_id int, title text, description text,
+			price numeric, stock int, sizes jsonb, image_url text, status text,
+			weight_grams int, length_mm int, width_mm int, height_mm int)
+		WHERE NOT EXISTS (SELECT 1 FROM products p WHERE p.id = s.id)`
+
+	restoreVariantsQuery = `UPDATE product_variants v SET
+			sku = s.sku, size = s.size, color = s.color, price_delta = s.price_delta,
+			stock = CASE WHEN $2 THEN s.stock ELSE v.stock END,
+			weight_grams = COALESCE(s.weight_grams, v.weight_grams), length_mm = COALESCE(s.length_mm, v.length_mm),
+			width_mm = COALESCE(s.width_mm, v.width_mm), height_mm = COALESCE(s.height_mm, v.height_mm),
+			updated_at = NOW()
+		FROM jsonb_to_recordset($1::jsonb) AS s(id int, product_id int, sku text, size text, color text,
+			price_delta numeric, stock int, weight_grams int, length_mm int, width_mm int, height_mm int)
+		WHERE v.id = s.id AND v.product_id = s.product_id`
+
+	recreateVariantsQuery = `INSERT INTO product_variants (id, product_id, sku, size, color, price_delta, stock,
+			weight_grams, length_mm, width_mm, height_mm)
+		SELECT s.id, s.product_id, s.sku, s.size, s.color, s.price_delta, CASE WHEN $2 THEN s.stock ELSE 0 END,
+			s.weight_grams, s.length_mm, s.width_mm, s.height_mm
+		FROM jsonb_to_recordset($1::jsonb) AS s(id int, product_id int, sku text, size text, color text,
+			price_delta numeric, stock int, weight_grams int, length_mm int, width_mm int, height_mm int)
+		WHERE NOT EXISTS (SELECT 1 FROM product_variants v WHERE v.id = s.id)`
+)
+
+// revisionVariant is a variant of a revision with the product it belongs
+// to, as the restore queries read it.
+type revisionVariant struct {
+	models.CatalogRevisionVariant
+	ProductID int `json:"product_id"`
+}
+
+type CatalogRevisionRepository struct {
+	db       *pgxpool.Pool
+	readOnly *readonly.Guard
+	keep     int
+}
+
+// NewCatalogRevisionRepository creates a catalog revision repository
+// keeping the newest keep revisions of each seller. A nil guard never
+// blocks restores.
+func NewCatalogRevisionRepository(db *pgxpool.Pool, guard *readonly.Guard, keep int) *CatalogRevisionRepository {
+	return &CatalogRevisionRepository{db: db, readOnly: guard, keep: keep}
+}
+
+// Create records the seller's catalog as a new revision, on behalf of user
+// createdBy.
+func (r *CatalogRevisionRepository) Create(ctx context.Context, sellerID int, reason, note string, createdBy int) (*models.CatalogRevision, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockSeller(ctx, tx, sellerID); err != nil {
+		return nil, err
+	}
+	rev, err := r.snapshot(ctx, tx, sellerID, reason, note, createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return rev, nil
+}
+
+// snapshot records the catalog of a locked seller and drops the revisions
+// beyond the newest r.keep.
+func (r *CatalogRevisionRepository) snapshot(ctx context.Context, tx pgx.Tx, sellerID int, reason, note string, createdBy int) (*models.CatalogRevision, error) {
+	var rev models.CatalogRevision
+	if err := pgxscan.Get(ctx, tx, &rev, snapshotCatalogQuery, sellerID, reason, note, createdBy); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to create catalog revision")
+		return nil, fmt.Errorf("failed to create catalog revision: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM catalog_revisions WHERE seller_id = $1 AND version <= $2`,
+		sellerID, rev.Version-r.keep); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to prune catalog revisions")
+		return nil, fmt.Errorf("failed to prune catalog revisions: %w", err)
+	}
+	return &rev, nil
+}
+
+// GetBySeller lists the seller's revisions, newest first, without their
+// products.
+func (r *CatalogRevisionRepository) GetBySeller(ctx context.Context, sellerID int) ([]*models.CatalogRevision, error) {
+	query, args, err := psql.Select(catalogRevisionColumns...).
+		From("catalog_revisions").
+		Where("seller_id = ?", sellerID).
+		OrderBy("version DESC").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build catalog revisions query")
+		return nil, fmt.Errorf("failed to build catalog revisions query: %w", err)
+	}
+
+	revisions := []*models.CatalogRevision{}
+	if err := pgxscan.Select(ctx, r.db, &revisions, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get catalog revisions")
+		return nil, fmt.Errorf("failed to get catalog revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+// GetByID returns one of the seller's revisions with its products.
+func (r *CatalogRevisionRepository) GetByID(ctx context.Context, id, sellerID int) (*models.CatalogRevision, error) {
+	return getCatalogRevision(ctx, r.db, id, sellerID)
+}
+
+func getCatalogRevision(ctx context.Context, db pgxscan.Querier, id, sellerID int) (*models.CatalogRevision, error) {
+	var rev struct {
+		models.CatalogRevision
+		Products json.RawMessage `db:"products"`
+	}
+	query := `SELECT ` + strings.Join(catalogRevisionColumns, ", ") + `, products FROM catalog_revisions
+		WHERE id = $1 AND seller_id = $2`
+	if err := pgxscan.Get(ctx, db, &rev, query, id, sellerID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.NotFound(fmt.Sprintf("catalog revision with id %d not found", id))
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get catalog revision")
+		return nil, fmt.Errorf("failed to get catalog revision: %w", err)
+	}
+
+	if err := json.Unmarshal(rev.Products, &rev.CatalogRevision.Products); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to decode catalog revision")
+		return nil, fmt.Errorf("failed to decode catalog revision: %w", err)
+	}
+	return &rev.CatalogRevision, nil
+}
+
+// Restore rolls the seller's catalog back to a revision on behalf of user
+// restoredBy, after recording the catalog as it is in a restore revision.
+// Products are put back as they were, recreated under their IDs if they
+// were deleted since, and products added since are deleted; their rows
+// stay for the orders that name them. Variants are rolled back with their
+// products. Stock is only rolled back with restoreStock.
+func (r *CatalogRevisionRepository) Restore(ctx context.Context, id, sellerID int, restoreStock bool, restoredBy int) (*models.CatalogRestore, error) {
+	if err := r.readOnly.Check(readonly.TableProducts); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockSeller(ctx, tx, sellerID); err != nil {
+		return nil, err
+	}
+	rev, err := getCatalogRevision(ctx, tx, id, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM products WHERE seller_id = $1 FOR UPDATE`, sellerID); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to lock products")
+		return nil, fmt.Errorf("failed to lock products: %w", err)
+	}
+
+	backup, err := r.snapshot(ctx, tx, sellerID, models.CatalogRevisionRestore,
+		fmt.Sprintf("before restoring version %d", rev.Version), restoredBy)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.CatalogRestore{Revision: rev, Backup: backup}
+
+	productIDs := make([]int, len(rev.Products))
+	variantIDs := []int{}
+	variants := []revisionVariant{}
+	for i, p := range rev.Products {
+		productIDs[i] = p.ID
+		for _, v := range p.Variants {
+			variantIDs = append(variantIDs, v.ID)
+			variants = append(variants, revisionVariant{CatalogRevisionVariant: v, ProductID: p.ID})
+		}
+	}
+	products, err := json.Marshal(rev.Products)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode catalog revision: %w", err)
+	}
+	variantRecords, err := json.Marshal(variants)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode catalog revision: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `UPDATE products SET status = 'deleted', updated_at = NOW()
+		WHERE seller_id = $1 AND NOT (id = ANY($2)) AND status NOT IN ('deleted', 'blocked')`, sellerID, productIDs)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to hide products")
+		return nil, fmt.Errorf("failed to hide products: %w", err)
+	}
+	result.Hidden = int(tag.RowsAffected())
+
+	if tag, err = tx.Exec(ctx, restoreProductsQuery, sellerID, products, restoreStock); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to restore products")
+		return nil, fmt.Errorf("failed to restore products: %w", err)
+	}
+	result.Restored = int(tag.RowsAffected())
+	if tag, err = tx.Exec(ctx, recreateProductsQuery, sellerID, products, restoreStock); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to recreate products")
+		return nil, fmt.Errorf("failed to recreate products: %w", err)
+	}
+	result.Recreated = int(tag.RowsAffected())
+
+	// Variants added since go; the others are renamed out of the way first,
+	// so SKUs and sizes can move between them without clashing.
+	if _, err := tx.Exec(ctx, `DELETE FROM product_variants
+		WHERE product_id = ANY($1) AND NOT (id = ANY($2))`, productIDs, variantIDs); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to delete variants")
+		return nil, fmt.Errorf("failed to delete variants: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE product_variants SET sku = '#' || id, size = '#' || id, color = ''
+		WHERE product_id = ANY($1)`, productIDs); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to restore variants")
+		return nil, fmt.Errorf("failed to restore variants: %w", err)
+	}
+	for _, query := range []string{restoreVariantsQuery, recreateVariantsQuery} {
+		if _, err := tx.Exec(ctx, query, variantRecords, restoreStock); err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to restore variants")
+			return nil, fmt.Errorf("failed to restore variants: %w", err)
+		}
+	}
+	for _, p := range rev.Products {
+		if len(p.Variants) == 0 {
+			continue
+		}
+		if err := syncVariantTotals(ctx, tx, p.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return result, nil
+}
+
+// lockSeller locks a seller's row, serializing their catalog revisions.
+func lockSeller(ctx context.Context, tx pgx.Tx, sellerID int) error {
+	var id int
+	if err := tx.QueryRow(ctx, `SELECT id FROM sellers WHERE id = $1 FOR UPDATE`, sellerID).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperrors.SellerNotFound(sellerID)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to lock seller")
+		return fmt.Errorf("failed to lock seller: %w", err)
+	}
+	return nil
+}
@@ -2,17 +2,29 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
 	"github.com/Zifeldev/marketback/service/Market/internal/cache"
 	"github.com/Zifeldev/marketback/service/Market/internal/logger"
 	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
 	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// categoryColumns select a categories row and its active product count into
+// models.Category.
+var categoryColumns = []string{
+	"id", "name", "COALESCE(description, '') AS description", "risk_level",
+	"(SELECT COUNT(*) FROM products p WHERE p.category_id = categories.id AND p.status = 'active') AS product_count",
+	"created_at", "updated_at",
+}
+
 type CategoryRepository struct {
 	db    *pgxpool.Pool
 	cache *cache.RedisCache
@@ -35,10 +47,14 @@ func (r *CategoryRepository) invalidateCategoriesCache(ctx context.Context) {
 }
 
 func (r *CategoryRepository) Create(ctx context.Context, req *models.CreateCategoryRequest) (*models.Category, error) {
+	riskLevel := req.RiskLevel
+	if riskLevel == "" {
+		riskLevel = models.CategoryRiskStandard
+	}
 	query, args, err := psql.Insert("categories").
-		Columns("name", "description").
-		Values(req.Name, req.Description).
-		Suffix("RETURNING id, name, description, created_at, updated_at").
+		Columns("name", "description", "risk_level").
+		Values(req.Name, req.Description, riskLevel).
+		Suffix(returning(categoryColumns)).
 		ToSql()
 	if err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to build insert category query")
@@ -46,15 +62,7 @@ func (r *CategoryRepository) Create(ctx context.Context, req *models.CreateCateg
 	}
 
 	var category models.Category
-	err = r.db.QueryRow(ctx, query, args...).Scan(
-		&category.ID,
-		&category.Name,
-		&category.Description,
-		&category.CreatedAt,
-		&category.UpdatedAt,
-	)
-
-	if err != nil {
+	if err := pgxscan.Get(ctx, r.db, &category, query, args...); err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to create category")
 		return nil, fmt.Errorf("failed to create category: %w", err)
 	}
@@ -66,7 +74,7 @@ func (r *CategoryRepository) Create(ctx context.Context, req *models.CreateCateg
 }
 
 func (r *CategoryRepository) GetByID(ctx context.Context, id int) (*models.Category, error) {
-	query, args, err := psql.Select("id", "name", "description", "created_at", "updated_at").
+	query, args, err := psql.Select(categoryColumns...).
 		From("categories").
 		Where(sq.Eq{"id": id}).
 		ToSql()
@@ -76,15 +84,7 @@ func (r *CategoryRepository) GetByID(ctx context.Context, id int) (*models.Categ
 	}
 
 	var category models.Category
-	err = r.db.QueryRow(ctx, query, args...).Scan(
-		&category.ID,
-		&category.Name,
-		&category.Description,
-		&category.CreatedAt,
-		&category.UpdatedAt,
-	)
-
-	if err != nil {
+	if err := pgxscan.Get(ctx, r.db, &category, query, args...); err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to get category")
 		return nil, fmt.Errorf("failed to get category: %w", err)
 	}
@@ -104,7 +104,7 @@ func (r *CategoryRepository) GetAll(ctx context.Context) ([]*models.Category, er
 		metrics.RedisMissesTotal.Inc()
 	}
 
-	query, args, err := psql.Select("id", "name", "description", "created_at", "updated_at").
+	query, args, err := psql.Select(categoryColumns...).
 		From("categories").
 		OrderBy("name").
 		ToSql()
@@ -113,28 +113,11 @@ func (r *CategoryRepository) GetAll(ctx context.Context) ([]*models.Category, er
 		return nil, fmt.Errorf("failed to build select all categories query: %w", err)
 	}
 
-	rows, err := r.db.Query(ctx, query, args...)
-	if err != nil {
+	categories = []*models.Category{}
+	if err := pgxscan.Select(ctx, r.db, &categories, query, args...); err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to get categories")
 		return nil, fmt.Errorf("failed to get categories: %w", err)
 	}
-	defer rows.Close()
-
-	categories = []*models.Category{}
-	for rows.Next() {
-		var category models.Category
-		if err := rows.Scan(
-			&category.ID,
-			&category.Name,
-			&category.Description,
-			&category.CreatedAt,
-			&category.UpdatedAt,
-		); err != nil {
-			logger.GetLogger().WithField("err", err).Error("failed to scan category")
-			return nil, fmt.Errorf("failed to scan category: %w", err)
-		}
-		categories = append(categories, &category)
-	}
 
 	if r.cache != nil {
 		_ = r.cache.Set(ctx, cacheKey, categories, 10*time.Minute)
@@ -147,7 +130,7 @@ func (r *CategoryRepository) Update(ctx context.Context, id int, req *models.Upd
 	updateBuilder := psql.Update("categories").
 		Set("updated_at", sq.Expr("NOW()")).
 		Where(sq.Eq{"id": id}).
-		Suffix("RETURNING id, name, description, created_at, updated_at")
+		Suffix(returning(categoryColumns))
 
 	if req.Name != "" {
 		updateBuilder = updateBuilder.Set("name", req.Name)
@@ -155,6 +138,9 @@ func (r *CategoryRepository) Update(ctx context.Context, id int, req *models.Upd
 	if req.Description != "" {
 		updateBuilder = updateBuilder.Set("description", req.Description)
 	}
+	if req.RiskLevel != "" {
+		updateBuilder = updateBuilder.Set("risk_level", req.RiskLevel)
+	}
 
 	query, args, err := updateBuilder.ToSql()
 	if err != nil {
@@ -163,15 +149,7 @@ func (r *CategoryRepository) Update(ctx context.Context, id int, req *models.Upd
 	}
 
 	var category models.Category
-	err = r.db.QueryRow(ctx, query, args...).Scan(
-		&category.ID,
-		&category.Name,
-		&category.Description,
-		&category.CreatedAt,
-		&category.UpdatedAt,
-	)
-
-	if err != nil {
+	if err := pgxscan.Get(ctx, r.db, &category, query, args...); err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to update category")
 		return nil, fmt.Errorf("failed to update category: %w", err)
 	}
@@ -182,7 +160,59 @@ func (r *CategoryRepository) Update(ctx context.Context, id int, req *models.Upd
 	return &category, nil
 }
 
-func (r *CategoryRepository) Delete(ctx context.Context, id int) error {
+// Delete removes a category. A category that still has products is only
+// deleted when reassignTo names the category that takes them over, so that
+// no product drops out of the public listings.
+func (r *CategoryRepository) Delete(ctx context.Context, id int, reassignTo *int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Locking the row keeps products from being added to the category
+	// while it is being emptied.
+	var locked int
+	err = tx.QueryRow(ctx, `SELECT id FROM categories WHERE id = $1 FOR UPDATE`, id).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperrors.CategoryNotFound(id)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to lock category")
+		return fmt.Errorf("failed to lock category: %w", err)
+	}
+
+	var products int64
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM products WHERE category_id = $1`, id).Scan(&products); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to count category products")
+		return fmt.Errorf("failed to count category products: %w", err)
+	}
+
+	if products > 0 {
+		if reassignTo == nil {
+			return apperrors.Conflict(fmt.Sprintf("category %d still has %d products; pass force=true and reassign_to to move them", id, products))
+		}
+		if *reassignTo == id {
+			return apperrors.ValidationError("reassign_to", "must differ from the deleted category")
+		}
+
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1)`, *reassignTo).Scan(&exists); err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to check target category")
+			return fmt.Errorf("failed to check target category: %w", err)
+		}
+		if !exists {
+			return apperrors.CategoryNotFound(*reassignTo)
+		}
+
+		_, err := tx.Exec(ctx, `UPDATE products SET category_id = $1, updated_at = NOW() WHERE category_id = $2`, *reassignTo, id)
+		if err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to reassign category products")
+			return fmt.Errorf("failed to reassign category products: %w", err)
+		}
+	}
+
 	query, args, err := psql.Delete("categories").
 		Where(sq.Eq{"id": id}).
 		ToSql()
@@ -191,15 +221,14 @@ func (r *CategoryRepository) Delete(ctx context.Context, id int) error {
 		return fmt.Errorf("failed to build delete category query: %w", err)
 	}
 
-	result, err := r.db.Exec(ctx, query, args...)
-	if err != nil {
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to delete category")
 		return fmt.Errorf("failed to delete category: %w", err)
 	}
 
-	if result.RowsAffected() == 0 {
-		logger.GetLogger().WithField("category_id", id).Error("category not found")
-		return fmt.Errorf("category not found")
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	// Invalidate cache after deleting a category
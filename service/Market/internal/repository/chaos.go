@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/chaos"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+)
+
+// The chaos repositories run every call through a fault injector before
+// the wrapped repository, so handlers see injected latency and errors the
+// way they would see a slow or failing database.
+
+type chaosCartRepo struct {
+	next CartRepo
+	inj  *chaos.Injector
+}
+
+func NewChaosCartRepo(next CartRepo, inj *chaos.Injector) CartRepo {
+	return &chaosCartRepo{next: next, inj: inj}
+}
+
+func (r *chaosCartRepo) AddItem(ctx context.Context, userID int, req *models.AddToCartRequest) (*models.CartItem, error) {
+	if err := r.inj.Inject(ctx, chaos.LayerRepository); err != nil {
+		return nil, err
+	}
+	return r.next.AddItem(ctx, userID, req)
+}
+
+func (r *chaosCartRepo) GetUserCart(ctx context.Context, userID int) ([]*models.CartItemWithDetails, error) {
+	if err := r.inj.Inject(ctx, chaos.LayerRepository); err != nil {
+		return nil, err
+	}
+	return r.next.GetUserCart(ctx, userID)
+}
+
+func (r *chaosCartRepo) UpdateItem(ctx context.Context, itemID, userID int, req *models.UpdateCartItemRequest) (*models.CartItem, error) {
+	if err := r.inj.Inject(ctx, chaos.LayerRepository); err != nil {
+		return nil, err
+	}
+	return r.next.UpdateItem(ctx, itemID, userID, req)
+}
+
+func (r *chaosCartRepo) DeleteItem(ctx context.Context, itemID, userID int) error {
+	if err := r.inj.Inject(ctx, chaos.LayerRepository); err != nil {
+		return err
+	}
+	return r.next.DeleteItem(ctx, itemID, userID)
+}
+
+func (r *chaosCartRepo) ClearCart(ctx context.Context, userID int) error {
+	if err := r.inj.Inject(ctx, chaos.LayerRepository); err != nil {
+		return err
+	}
+	return r.next.ClearCart(ctx, userID)
+}
+
+type chaosProductRepo struct {
+	next ProductRepo
+	inj  *chaos.Injector
+}
+
+func NewChaosProductRepo(next ProductRepo, inj *chaos.Injector) ProductRepo {
+	return &chaosProductRepo{next: next, inj: inj}
+}
+
+func (r *chaosProductRepo) GetAll(ctx context.Context, categoryID, sellerID *int, status string, visitorBucket *int, pagination *models.PaginationParams) ([]*models.ProductWithDetails, int64, error) {
+	if err := r.inj.Inject(ctx, chaos.LayerRepository); err != nil {
+		return nil, 0, err
+	}
+	return r.next.GetAll(ctx, categoryID, sellerID, status, visitorBucket, pagination)
+}
+
+func (r *chaosProductRepo) GetByID(ctx context.Context, id int) (*models.ProductWithDetails, error) {
+	if err := r.inj.Inject(ctx, chaos.LayerRepository); err != nil {
+		return nil, err
+	}
+	return r.next.GetByID(ctx, id)
+}
+
+func (r *chaosProductRepo) GetByIDs(ctx context.Context, ids []int) (map[int]*models.ProductWithDetails, error) {
+	if err := r.inj.Inject(ctx, chaos.LayerRepository); err != nil {
+		return nil, err
+	}
+	return r.next.GetByIDs(ctx, ids)
+}
+
+type chaosCategoryRepo struct {
+	next CategoryRepo
+	inj  *chaos.Injector
+}
+
+func NewChaosCategoryRepo(next CategoryRepo, inj *chaos.Injector) CategoryRepo {
+	return &chaosCategoryRepo{next: next, inj: inj}
+}
+
+func (r *chaosCategoryRepo) GetAll(ctx context.Context) ([]*models.Category, error) {
+	if err := r.inj.Inject(ctx, chaos.LayerRepository); err != nil {
+		return nil, err
+	}
+	return r.next.GetAll(ctx)
+}
+
+func (r *chaosCategoryRepo) GetByID(ctx context.Context, id int) (*models.Category, error) {
+	if err := r.inj.Inject(ctx, chaos.LayerRepository); err != nil {
+		return nil, err
+	}
+	return r.next.GetByID(ctx, id)
+}
+
+type chaosOrderRepo struct {
+	next OrderRepo
+	inj  *chaos.Injector
+}
+
+func NewChaosOrderRepo(next OrderRepo, inj *chaos.Injector) OrderRepo {
+	return &chaosOrderRepo{next: next, inj: inj}
+}
+
+func (r *chaosOrderRepo) GetUserOrders(ctx context.Context, userID int, query string, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error) {
+	if err := r.inj.Inject(ctx, chaos.LayerRepository); err != nil {
+		return nil, 0, err
+	}
+	return r.next.GetUserOrders(ctx, userID, query, pagination)
+}
+
+func (r *chaosOrderRepo) GetByID(ctx context.Context, orderID int) (*models.OrderWithItems, error) {
+	if err := r.inj.Inject(ctx, chaos.LayerRepository); err != nil {
+		return nil, err
+	}
+	return r.next.GetByID(ctx, orderID)
+}
+
+func (r *chaosOrderRepo) GetByNumber(ctx context.Context, orderNumber string) (*models.OrderWithItems, error) {
+	if err := r.inj.Inject(ctx, chaos.LayerRepository); err != nil {
+		return nil, err
+	}
+	return r.next.GetByNumber(ctx, orderNumber)
+}
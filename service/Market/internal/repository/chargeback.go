@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/money"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var chargebackColumns = []string{
+	"id", "payment_id", "order_id", "provider", "provider_ref", "amount::float8 AS amount", "reason", "status",
+	"evidence_due_by", "created_at", "updated_at",
+}
+
+var payoutHoldColumns = []string{
+	"id", "chargeback_id", "seller_id", "ticket_id", "amount::float8 AS amount", "status", "created_at", "resolved_at",
+}
+
+// orderSellerSales is what each seller of an order sold in it, net of the
+// discounts they funded.
+const orderSellerSales = `SELECT s.id, s.user_id, SUM(oi.price * oi.quantity - ` + sellerDiscount + `)::float8
+	FROM order_items oi
+	JOIN products p ON p.id = oi.product_id
+	JOIN sellers s ON s.id = p.seller_id
+	WHERE oi.order_id = $1
+	GROUP BY s.id, s.user_id
+	ORDER BY s.id`
+
+// ChargebackRepository stores chargebacks and the payout holds they put on
+// the order's sellers. Opening one holds each seller's share of it and
+// opens a dispute ticket for the seller's evidence; deciding it releases or
+// debits the holds and resolves the tickets, all in one transaction.
+type ChargebackRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewChargebackRepository(db *pgxpool.Pool) *ChargebackRepository {
+	return &ChargebackRepository{db: db}
+}
+
+// Open records a chargeback and splits its amount over the sellers of the
+// order in proportion to what they sold in it. Each seller gets a hold on
+// their share, a dispute ticket pending their evidence and a notification.
+// Opening a chargeback the provider already reported returns the recorded
+// one.
+func (r *ChargebackRepository) Open(ctx context.Context, cb *models.Chargeback) (*models.Chargeback, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query, args, err := psql.Insert("chargebacks").
+		Columns("payment_id", "order_id", "provider", "provider_ref", "amount", "reason", "evidence_due_by").
+		Values(cb.PaymentID, cb.OrderID, cb.Provider, cb.ProviderRef, cb.Amount, cb.Reason, cb.EvidenceDueBy).
+		Suffix("ON CONFLICT (provider, provider_ref) DO NOTHING " + returning(chargebackColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build insert chargeback query")
+		return nil, fmt.Errorf("failed to build insert chargeback query: %w", err)
+	}
+
+	created := &models.Chargeback{}
+	if err := pgxscan.Get(ctx, tx, created, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return r.getOne(ctx, r.db, sq.Eq{"provider": cb.Provider, "provider_ref": cb.ProviderRef}, "")
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to create chargeback")
+		return nil, fmt.Errorf("failed to create chargeback: %w", err)
+	}
+
+	var orderNumber string
+	if err := tx.QueryRow(ctx, `SELECT order_number FROM orders WHERE id = $1`, cb.OrderID).Scan(&orderNumber); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.OrderNotFound(cb.OrderID)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get chargeback order")
+		return nil, fmt.Errorf("failed to get chargeback order: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, orderSellerSales, cb.OrderID)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get order sellers")
+		return nil, fmt.Errorf("failed to get order sellers: %w", err)
+	}
+	var sellerIDs, sellerUserIDs []int
+	var sales []money.Cents
+	for rows.Next() {
+		var sellerID, userID int
+		var sold float64
+		if err := rows.Scan(&sellerID, &userID, &sold); err != nil {
+			rows.Close()
+			logger.GetLogger().WithField("err", err).Error("failed to scan order seller")
+			return nil, fmt.Errorf("failed to scan order seller: %w", err)
+		}
+		sellerIDs, sellerUserIDs = append(sellerIDs, sellerID), append(sellerUserIDs, userID)
+		sales = append(sales, money.FromFloat(sold))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get order sellers")
+		return nil, fmt.Errorf("failed to get order sellers: %w", err)
+	}
+
+	due := "as soon as possible"
+	if created.EvidenceDueBy != nil {
+		due = "by " + created.EvidenceDueBy.Format("2 January 2006 15:04 MST")
+	}
+	shares := money.Split(money.FromFloat(created.Amount), sales)
+	for i, sellerID := range sellerIDs {
+		var ticketID int
+		err := tx.QueryRow(ctx, `INSERT INTO support_tickets (user_id, user_role, category, subject, order_id, status)
+			VALUES ($1, 'seller', 'payment', $2, $3, $4) RETURNING id`,
+			sellerUserIDs[i], "Chargeback on order "+orderNumber, cb.OrderID, models.TicketStatusPending).Scan(&ticketID)
+		if err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to open dispute ticket")
+			return nil, fmt.Errorf("failed to open dispute ticket: %w", err)
+		}
+		if _, err := insertTicketMessage(ctx, tx, &models.TicketMessage{
+			TicketID:   ticketID,
+			AuthorRole: "system",
+			Body: fmt.Sprintf("The buyer's bank charged back %s of order %s (reason: %s). %s of your payout is held until "+
+				"the chargeback is decided. To dispute it, reply to this ticket %s with proof of delivery or tracking, "+
+				"your correspondence with the buyer and anything else that shows the sale was genuine. The amount is "+
+				"released to you if the chargeback is won and debited if it is lost.",
+				money.FromFloat(created.Amount), orderNumber, reasonOrUnknown(created.Reason), shares[i], due),
+		}); err != nil {
+			return nil, err
+		}
+
+		hold := &models.PayoutHold{}
+		if err := pgxscan.Get(ctx, tx, hold, `INSERT INTO payout_holds (chargeback_id, seller_id, ticket_id, amount)
+			VALUES ($1, $2, $3, $4) `+returning(payoutHoldColumns), created.ID, sellerID, ticketID, shares[i].Float()); err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to hold seller payout")
+			return nil, fmt.Errorf("failed to hold seller payout: %w", err)
+		}
+		created.Holds = append(created.Holds, hold)
+
+		if err := notify(ctx, tx, sellerUserIDs[i], "chargeback",
+			fmt.Sprintf("Order %s was charged back. %s of your payout is held; submit your evidence on ticket #%d %s.",
+				orderNumber, shares[i], ticketID, due)); err != nil {
+			return nil, err
+		}
+	}
+	if created.Holds == nil {
+		created.Holds = []*models.PayoutHold{}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return created, nil
+}
+
+func reasonOrUnknown(reason string) string {
+	if reason == "" {
+		return "not given"
+	}
+	return reason
+}
+
+// GetByID returns a chargeback with its payout holds.
+func (r *ChargebackRepository) GetByID(ctx context.Context, id int) (*models.Chargeback, error) {
+	cb, err := r.getOne(ctx, r.db, sq.Eq{"id": id}, "")
+	if err == nil && cb == nil {
+		return nil, apperrors.NotFound(fmt.Sprintf("chargeback with id %d not found", id))
+	}
+	return cb, err
+}
+
+// GetByRef returns the provider's chargeback with its payout holds, or nil
+// when it was never recorded.
+func (r *ChargebackRepository) GetByRef(ctx context.Context, provider, ref string) (*models.Chargeback, error) {
+	return r.getOne(ctx, r.db, sq.Eq{"provider": provider, "provider_ref": ref}, "")
+}
+
+// Resolve decides an open chargeback. A won chargeback releases the
+// sellers' holds, a lost one debits them; either way their dispute tickets
+// are resolved and they are notified. Deciding a chargeback the same way
+// twice returns it unchanged.
+func (r *ChargebackRepository) Resolve(ctx context.Context, id int, status string) (*models.Chargeback, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	cb, err := r.getOne(ctx, tx, sq.Eq{"id": id}, "FOR UPDATE")
+	if err != nil {
+		return nil, err
+	}
+	if cb == nil {
+		return nil, apperrors.NotFound(fmt.Sprintf("chargeback with id %d not found", id))
+	}
+	if cb.Status == status {
+		return cb, nil
+	}
+	if cb.Status != models.ChargebackStatusOpen {
+		return nil, apperrors.Conflict(fmt.Sprintf("chargeback is already %s", cb.Status))
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE chargebacks SET status = $2, updated_at = NOW() WHERE id = $1`, id, status); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to update chargeback status")
+		return nil, fmt.Errorf("failed to update chargeback status: %w", err)
+	}
+	holdStatus, outcome := models.PayoutHoldReleased, "won; the held amount is released to your payout"
+	if status == models.ChargebackStatusLost {
+		holdStatus, outcome = models.PayoutHoldDebited, "lost; the held amount is debited from your payout"
+	}
+	if _, err := tx.Exec(ctx, `UPDATE payout_holds SET status = $2, resolved_at = NOW()
+		WHERE chargeback_id = $1 AND status = 'held'`, id, holdStatus); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to resolve payout holds")
+		return nil, fmt.Errorf("failed to resolve payout holds: %w", err)
+	}
+
+	var orderNumber string
+	if err := tx.QueryRow(ctx, `SELECT order_number FROM orders WHERE id = $1`, cb.OrderID).Scan(&orderNumber); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get chargeback order")
+		return nil, fmt.Errorf("failed to get chargeback order: %w", err)
+	}
+	message := fmt.Sprintf("The chargeback on order %s was %s.", orderNumber, outcome)
+	for _, hold := range cb.Holds {
+		var sellerUserID int
+		if err := tx.QueryRow(ctx, `SELECT user_id FROM sellers WHERE id = $1`, hold.SellerID).Scan(&sellerUserID); err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to get chargeback seller")
+			return nil, fmt.Errorf("failed to get chargeback seller: %w", err)
+		}
+		if hold.TicketID != nil {
+			if _, err := insertTicketMessage(ctx, tx, &models.TicketMessage{
+				TicketID: *hold.TicketID, AuthorRole: "system", Body: message,
+			}); err != nil {
+				return nil, err
+			}
+			if err := setTicketStatus(ctx, tx, *hold.TicketID, models.TicketStatusResolved); err != nil {
+				return nil, err
+			}
+		}
+		if err := notify(ctx, tx, sellerUserID, "chargeback", message); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// getOne returns the chargeback matching where with its payout holds, or
+// nil when there is none.
+func (r *ChargebackRepository) getOne(ctx context.Context, db pgxscan.Querier, where sq.Eq, suffix string) (*models.Chargeback, error) {
+	query, args, err := psql.Select(chargebackColumns...).From("chargebacks").Where(where).Suffix(suffix).ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build chargeback query")
+		return nil, fmt.Errorf("failed to build chargeback query: %w", err)
+	}
+
+	cb := &models.Chargeback{}
+	if err := pgxscan.Get(ctx, db, cb, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get chargeback")
+		return nil, fmt.Errorf("failed to get chargeback: %w", err)
+	}
+
+	cb.Holds = []*models.PayoutHold{}
+	holdsQuery, holdsArgs, err := psql.Select(payoutHoldColumns...).From("payout_holds").
+		Where(sq.Eq{"chargeback_id": cb.ID}).
+		OrderBy("seller_id").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build payout holds query")
+		return nil, fmt.Errorf("failed to build payout holds query: %w", err)
+	}
+	if err := pgxscan.Select(ctx, db, &cb.Holds, holdsQuery, holdsArgs...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get payout holds")
+		return nil, fmt.Errorf("failed to get payout holds: %w", err)
+	}
+	return cb, nil
+}
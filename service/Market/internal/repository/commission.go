@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// commissionColumns select a commission_rates row into models.CommissionRate.
+var commissionColumns = []string{"id", "category_id", "seller_id", "rate::float8 AS rate", "effective_from", "created_by", "created_at"}
+
+// productCommissionRateQuery resolves the rate in effect for a product now:
+// seller-specific beats category-specific beats the default, and the latest
+// effective row wins within the same scope. Missing rates mean no commission.
+const productCommissionRateQuery = `SELECT COALESCE((
+		SELECT cr.rate FROM commission_rates cr
+		WHERE cr.effective_from <= NOW()
+		AND (cr.seller_id = p.seller_id OR cr.seller_id IS NULL)
+		AND (cr.category_id = p.category_id OR cr.category_id IS NULL)
+		ORDER BY (cr.seller_id IS NOT NULL) DESC, (cr.category_id IS NOT NULL) DESC, cr.effective_from DESC
+		LIMIT 1), 0)::float8
+	FROM products p WHERE p.id = $1`
+
+type CommissionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCommissionRepository(db *pgxpool.Pool) *CommissionRepository {
+	return &CommissionRepository{db: db}
+}
+
+// Create schedules a new rate. Without EffectiveFrom it applies immediately.
+func (r *CommissionRepository) Create(ctx context.Context, adminID int, req *models.CreateCommissionRateRequest) (*models.CommissionRate, error) {
+	effectiveFrom := time.Now()
+	if req.EffectiveFrom != nil {
+		effectiveFrom = *req.EffectiveFrom
+	}
+
+	query, args, err := psql.Insert("commission_rates").
+		Columns("category_id", "seller_id", "rate", "effective_from", "created_by").
+		Values(req.CategoryID, req.SellerID, *req.Rate, effectiveFrom, adminID).
+		Suffix(returning(commissionColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build insert commission rate query")
+		return nil, fmt.Errorf("failed to build insert commission rate query: %w", err)
+	}
+
+	var cr models.CommissionRate
+	if err := pgxscan.Get(ctx, r.db, &cr, query, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, apperrors.NotFound("category or seller not found")
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to create commission rate")
+		return nil, fmt.Errorf("failed to create commission rate: %w", err)
+	}
+
+	return &cr, nil
+}
+
+// GetAll lists rates newest first, optionally narrowed to a seller or
+// category scope.
+func (r *CommissionRepository) GetAll(ctx context.Context, sellerID, categoryID *int, pagination *models.PaginationParams) ([]*models.CommissionRate, int64, error) {
+	where := sq.And{}
+	if sellerID != nil {
+		where = append(where, sq.Eq{"seller_id": *sellerID})
+	}
+	if categoryID != nil {
+		where = append(where, sq.Eq{"category_id": *categoryID})
+	}
+
+	countQuery, countArgs, err := psql.Select("COUNT(*)").From("commission_rates").Where(where).ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build count query")
+		return nil, 0, fmt.Errorf("failed to build count query: %w", err)
+	}
+
+	var totalItems int64
+	if err := r.db.QueryRow(ctx, countQuery, countArgs...).Scan(&totalItems); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to count commission rates")
+		return nil, 0, fmt.Errorf("failed to count commission rates: %w", err)
+	}
+
+	if totalItems == 0 {
+		return []*models.CommissionRate{}, 0, nil
+	}
+
+	query, args, err := psql.Select(commissionColumns...).From("commission_rates").
+		Where(where).
+		OrderBy("effective_from DESC", "id DESC").
+		Limit(uint64(pagination.GetLimit())).
+		Offset(uint64(pagination.GetOffset())).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build commission rates query")
+		return nil, 0, fmt.Errorf("failed to build commission rates query: %w", err)
+	}
+
+	rates, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return rates, totalItems, nil
+}
+
+// ForSellerUser returns every rate that can apply to the seller owned by
+// the user: the default, category rates and the seller's own overrides,
+// including scheduled ones.
+func (r *CommissionRepository) ForSellerUser(ctx context.Context, userID int) ([]*models.CommissionRate, error) {
+	query := `SELECT ` + strings.Join(commissionColumns, ", ") + ` FROM commission_rates
+		WHERE seller_id IS NULL OR seller_id = (SELECT id FROM sellers WHERE user_id = $1)
+		ORDER BY seller_id NULLS FIRST, category_id NULLS FIRST, effective_from DESC`
+
+	return r.query(ctx, query, userID)
+}
+
+func (r *CommissionRepository) query(ctx context.Context, query string, args ...interface{}) ([]*models.CommissionRate, error) {
+	rates := []*models.CommissionRate{}
+	if err := pgxscan.Select(ctx, r.db, &rates, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get commission rates")
+		return nil, fmt.Errorf("failed to get commission rates: %w", err)
+	}
+
+	return rates, nil
+}
+
+// Delete removes a scheduled rate. Rates already in effect are part of the
+// billing history and cannot be deleted; supersede them with a new rate.
+func (r *CommissionRepository) Delete(ctx context.Context, id int) error {
+	var effective bool
+	err := r.db.QueryRow(ctx, `SELECT effective_from <= NOW() FROM commission_rates WHERE id = $1`, id).Scan(&effective)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperrors.NotFound(fmt.Sprintf("commission rate with id %d not found", id))
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get commission rate")
+		return fmt.Errorf("failed to get commission rate: %w", err)
+	}
+	if effective {
+		return apperrors.Conflict("commission rate is already in effect; create a new rate instead")
+	}
+
+	if _, err := r.db.Exec(ctx, `DELETE FROM commission_rates WHERE id = $1 AND effective_from > NOW()`, id); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to delete commission rate")
+		return fmt.Errorf("failed to delete commission rate: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// estimateCountCap bounds the rows a filtered count=estimate query scans.
+// Totals at or above the cap are reported as the cap.
+const estimateCountCap = 10000
+
+// countRows counts the rows of table matching where. An exact COUNT(*) is
+// the default; with count=estimate an unfiltered total is read from the
+// planner statistics in pg_class and a filtered one stops at
+// estimateCountCap.
+func countRows(ctx context.Context, db *pgxpool.Pool, table string, where sq.And, pagination *models.PaginationParams) (int64, error) {
+	if pagination.EstimateCount() && len(where) == 0 {
+		var estimate int64
+		err := db.QueryRow(ctx, "SELECT reltuples::bigint FROM pg_class WHERE oid = $1::regclass", table).Scan(&estimate)
+		if err != nil {
+			logger.GetLogger().WithField("err", err).WithField("table", table).Error("failed to estimate row count")
+			return 0, fmt.Errorf("failed to estimate %s count: %w", table, err)
+		}
+		// reltuples is -1 until the table has been vacuumed or analyzed.
+		if estimate >= 0 {
+			return estimate, nil
+		}
+	}
+
+	var builder sq.SelectBuilder
+	if pagination.EstimateCount() {
+		capped := sq.Select("1").From(table).Where(where).Limit(estimateCountCap)
+		builder = psql.Select("COUNT(*)").FromSelect(capped, "capped")
+	} else {
+		builder = psql.Select("COUNT(*)").From(table).Where(where)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build count query")
+		return 0, fmt.Errorf("failed to build count query: %w", err)
+	}
+
+	var total int64
+	if err := db.QueryRow(ctx, query, args...).Scan(&total); err != nil {
+		logger.GetLogger().WithField("err", err).WithField("table", table).Error("failed to count rows")
+		return 0, fmt.Errorf("failed to count %s: %w", table, err)
+	}
+	return total, nil
+}
@@ -0,0 +1,315 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/pkg/fieldcrypt"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/readonly"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Order statuses from which an order can be handed to a courier.
+var assignableStatuses = map[string]bool{
+	models.OrderStatusConfirmed: true,
+	models.OrderStatusPaid:      true,
+	models.OrderStatusShipped:   true,
+}
+
+type DeliveryRepository struct {
+	db       *pgxpool.Pool
+	keyring  *fieldcrypt.Keyring
+	readOnly *readonly.Guard
+	events   *EventOutbox
+}
+
+func NewDeliveryRepository(db *pgxpool.Pool, keyring *fieldcrypt.Keyring, guard *readonly.Guard, events *EventOutbox) *DeliveryRepository {
+	return &DeliveryRepository{db: db, keyring: keyring, readOnly: guard, events: events}
+}
+
+// deliveryColumns select an order_deliveries row joined with its order into
+// models.Delivery.
+var deliveryColumns = []string{
+	"o.id AS order_id", "o.order_number", "COALESCE(o.status, 'pending') AS status", "o.total_amount::float8 AS total_amount",
+	"o.delivery_address", "o.delivery_lat", "o.delivery_lng",
+	"d.courier_id", "d.assigned_at", "d.delivered_at", "COALESCE(d.proof_url, '') AS proof_url",
+}
+
+// deliveryProofColumns select a delivery_proofs row into models.DeliveryProof.
+var deliveryProofColumns = []string{"id", "order_id", "kind", "url", "uploaded_by", "uploader_role", "created_at"}
+
+func deliverySelect() sq.SelectBuilder {
+	return psql.Select(deliveryColumns...).
+		From("order_deliveries d").
+		Join("orders o ON o.id = d.order_id")
+}
+
+// decrypt replaces the stored delivery address with its plaintext.
+func (r *DeliveryRepository) decrypt(d *models.Delivery) error {
+	addr, err := r.keyring.Decrypt(d.DeliveryAddr)
+	if err != nil {
+		logger.GetLogger().WithFields(map[string]interface{}{
+			"err":      err,
+			"order_id": d.OrderID,
+		}).Error("failed to decrypt delivery address")
+		return fmt.Errorf("failed to decrypt delivery address: %w", err)
+	}
+	d.DeliveryAddr = addr
+
+	return nil
+}
+
+// Assign hands an order to a courier and moves it to "shipped" on behalf
+// of adminID. Assigning an already assigned order replaces the courier.
+func (r *DeliveryRepository) Assign(ctx context.Context, orderID, courierID, adminID int) (*models.Delivery, error) {
+	if err := r.readOnly.Check(readonly.TableOrders); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	err = tx.QueryRow(ctx, `SELECT COALESCE(status, 'pending') FROM orders WHERE id = $1 FOR UPDATE`, orderID).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.OrderNotFound(orderID)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to lock order")
+		return nil, fmt.Errorf("failed to lock order: %w", err)
+	}
+	if !assignableStatuses[status] {
+		return nil, apperrors.Conflict(fmt.Sprintf("order in status %q cannot be assigned to a courier", status))
+	}
+
+	upsertQuery, upsertArgs, err := psql.Insert("order_deliveries").
+		Columns("order_id", "courier_id").
+		Values(orderID, courierID).
+		Suffix("ON CONFLICT (order_id) DO UPDATE SET courier_id = EXCLUDED.courier_id, assigned_at = NOW()").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build assign query")
+		return nil, fmt.Errorf("failed to build assign query: %w", err)
+	}
+	if _, err := tx.Exec(ctx, upsertQuery, upsertArgs...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to assign courier")
+		return nil, fmt.Errorf("failed to assign courier: %w", err)
+	}
+
+	if err := setOrderStatus(ctx, tx, r.events, orderID, models.OrderStatusShipped, models.StatusActorAdmin, &adminID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.get(ctx, orderID)
+}
+
+// GetCourierDeliveries lists deliveries assigned to a courier. state is
+// models.DeliveryStateActive, models.DeliveryStateDelivered or empty for both.
+func (r *DeliveryRepository) GetCourierDeliveries(ctx context.Context, courierID int, state string, pagination *models.PaginationParams) ([]*models.Delivery, int64, error) {
+	where := sq.And{sq.Eq{"d.courier_id": courierID}}
+	switch state {
+	case models.DeliveryStateActive:
+		where = append(where, sq.Eq{"d.delivered_at": nil})
+	case models.DeliveryStateDelivered:
+		where = append(where, sq.NotEq{"d.delivered_at": nil})
+	}
+
+	countQuery, countArgs, err := psql.Select("COUNT(*)").
+		From("order_deliveries d").
+		Where(where).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build count query")
+		return nil, 0, fmt.Errorf("failed to build count query: %w", err)
+	}
+
+	var totalItems int64
+	if err := r.db.QueryRow(ctx, countQuery, countArgs...).Scan(&totalItems); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to count deliveries")
+		return nil, 0, fmt.Errorf("failed to count deliveries: %w", err)
+	}
+
+	if totalItems == 0 {
+		return []*models.Delivery{}, 0, nil
+	}
+
+	query, args, err := deliverySelect().
+		Where(where).
+		OrderBy("d.assigned_at DESC").
+		Limit(uint64(pagination.GetLimit())).
+		Offset(uint64(pagination.GetOffset())).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build deliveries query")
+		return nil, 0, fmt.Errorf("failed to build deliveries query: %w", err)
+	}
+
+	deliveries := []*models.Delivery{}
+	if err := pgxscan.Select(ctx, r.db, &deliveries, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get deliveries")
+		return nil, 0, fmt.Errorf("failed to get deliveries: %w", err)
+	}
+	for _, d := range deliveries {
+		if err := r.decrypt(d); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return deliveries, totalItems, nil
+}
+
+// MarkDelivered closes the courier's open delivery for an order, stores the
+// proof-of-delivery URL and moves the order to "delivered".
+func (r *DeliveryRepository) MarkDelivered(ctx context.Context, orderID, courierID int, proofURL string) (*models.Delivery, error) {
+	if err := r.readOnly.Check(readonly.TableOrders); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query, args, err := psql.Update("order_deliveries").
+		Set("delivered_at", sq.Expr("NOW()")).
+		Set("proof_url", proofURL).
+		Where(sq.Eq{"order_id": orderID, "courier_id": courierID, "delivered_at": nil}).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build deliver query")
+		return nil, fmt.Errorf("failed to build deliver query: %w", err)
+	}
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to mark delivery complete")
+		return nil, fmt.Errorf("failed to mark delivery complete: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, apperrors.NotFound(fmt.Sprintf("no open delivery for order %d", orderID))
+	}
+
+	if _, err := tx.Exec(ctx, insertProofQuery, orderID, models.ProofKindPhoto, proofURL, courierID, "courier"); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to store delivery proof")
+		return nil, fmt.Errorf("failed to store delivery proof: %w", err)
+	}
+
+	if err := setOrderStatus(ctx, tx, r.events, orderID, models.OrderStatusDelivered, models.StatusActorCourier, &courierID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.get(ctx, orderID)
+}
+
+var insertProofQuery = `INSERT INTO delivery_proofs (order_id, kind, url, uploaded_by, uploader_role)
+	VALUES ($1, $2, $3, $4, $5) ` + returning(deliveryProofColumns)
+
+// AddProof attaches a photo or signature to an order's shipment.
+func (r *DeliveryRepository) AddProof(ctx context.Context, proof *models.DeliveryProof) (*models.DeliveryProof, error) {
+	if err := r.readOnly.Check(readonly.TableOrders); err != nil {
+		return nil, err
+	}
+
+	var saved models.DeliveryProof
+	err := pgxscan.Get(ctx, r.db, &saved, insertProofQuery, proof.OrderID, proof.Kind, proof.URL, proof.UploadedBy, proof.UploaderRole)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to store delivery proof")
+		return nil, fmt.Errorf("failed to store delivery proof: %w", err)
+	}
+
+	return &saved, nil
+}
+
+// GetProofs returns all proofs attached to an order, oldest first.
+func (r *DeliveryRepository) GetProofs(ctx context.Context, orderID int) ([]*models.DeliveryProof, error) {
+	query, args, err := psql.Select(deliveryProofColumns...).
+		From("delivery_proofs").
+		Where(sq.Eq{"order_id": orderID}).
+		OrderBy("created_at", "id").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build proofs query")
+		return nil, fmt.Errorf("failed to build proofs query: %w", err)
+	}
+
+	proofs := []*models.DeliveryProof{}
+	if err := pgxscan.Select(ctx, r.db, &proofs, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get delivery proofs")
+		return nil, fmt.Errorf("failed to get delivery proofs: %w", err)
+	}
+
+	return proofs, nil
+}
+
+// IsAssignedCourier reports whether the order is assigned to the courier.
+func (r *DeliveryRepository) IsAssignedCourier(ctx context.Context, orderID, courierID int) (bool, error) {
+	return r.exists(ctx, `SELECT EXISTS(SELECT 1 FROM order_deliveries WHERE order_id = $1 AND courier_id = $2)`, orderID, courierID)
+}
+
+// HasSellerItems reports whether the order contains products of the seller
+// owned by the given user.
+func (r *DeliveryRepository) HasSellerItems(ctx context.Context, orderID, sellerUserID int) (bool, error) {
+	return r.exists(ctx, `SELECT EXISTS(
+		SELECT 1 FROM order_items oi
+		JOIN products p ON p.id = oi.product_id
+		JOIN sellers s ON s.id = p.seller_id
+		WHERE oi.order_id = $1 AND s.user_id = $2)`, orderID, sellerUserID)
+}
+
+// IsOrderOwner reports whether the order was placed by the user.
+func (r *DeliveryRepository) IsOrderOwner(ctx context.Context, orderID, userID int) (bool, error) {
+	return r.exists(ctx, `SELECT EXISTS(SELECT 1 FROM orders WHERE id = $1 AND user_id = $2)`, orderID, userID)
+}
+
+func (r *DeliveryRepository) exists(ctx context.Context, query string, args ...interface{}) (bool, error) {
+	var ok bool
+	if err := r.db.QueryRow(ctx, query, args...).Scan(&ok); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to check order access")
+		return false, fmt.Errorf("failed to check order access: %w", err)
+	}
+	return ok, nil
+}
+
+func (r *DeliveryRepository) get(ctx context.Context, orderID int) (*models.Delivery, error) {
+	query, args, err := deliverySelect().
+		Where(sq.Eq{"d.order_id": orderID}).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build delivery query")
+		return nil, fmt.Errorf("failed to build delivery query: %w", err)
+	}
+
+	d := &models.Delivery{}
+	if err := pgxscan.Get(ctx, r.db, d, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get delivery")
+		return nil, fmt.Errorf("failed to get delivery: %w", err)
+	}
+	if err := r.decrypt(d); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EventOutbox records domain events in the transaction of the change they
+// describe, so events exist exactly for the changes that commit; the events
+// relay publishes them afterwards. A nil outbox records nothing.
+type EventOutbox struct {
+	lowStockThreshold int
+}
+
+// NewEventOutbox creates an outbox. ProductStockLow is recorded when an
+// order takes a product's stock to its low-stock threshold or below,
+// lowStockThreshold for products without one.
+func NewEventOutbox(lowStockThreshold int) *EventOutbox {
+	return &EventOutbox{lowStockThreshold: lowStockThreshold}
+}
+
+func (o *EventOutbox) add(ctx context.Context, db execer, eventType string, key int, payload interface{}) error {
+	if o == nil {
+		return nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+	_, err = db.Exec(ctx, `INSERT INTO event_outbox (type, key, payload) VALUES ($1, $2, $3)`,
+		eventType, strconv.Itoa(key), data)
+	if err != nil {
+		logger.GetLogger().WithFields(map[string]interface{}{
+			"err":  err,
+			"type": eventType,
+		}).Error("failed to record event")
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+	return nil
+}
+
+// stockTaken records ProductStockLow when a product's stock went from above
+// its threshold, the outbox's when nil, to at or below it.
+func (o *EventOutbox) stockTaken(ctx context.Context, db execer, productID, sellerID, before, after int, threshold *int) error {
+	if o == nil {
+		return nil
+	}
+	limit := o.lowStockThreshold
+	if threshold != nil {
+		limit = *threshold
+	}
+	if before <= limit || after > limit {
+		return nil
+	}
+	return o.add(ctx, db, models.EventProductStockLow, productID, models.ProductStockLowEvent{
+		ProductID: productID,
+		SellerID:  sellerID,
+		Stock:     after,
+		Threshold: limit,
+	})
+}
+
+// orderStatusEvents are the status changes published as an event of their
+// own besides OrderStatusChanged.
+var orderStatusEvents = map[string]string{
+	models.OrderStatusPaid:      models.EventOrderPaid,
+	models.OrderStatusCancelled: models.EventOrderCancelled,
+}
+
+// orderStatusChanged records OrderStatusChanged for an order that moved to
+// ev.Status, followed by OrderPaid or OrderCancelled where they apply.
+func (o *EventOutbox) orderStatusChanged(ctx context.Context, db execer, ev models.OrderStatusEvent) error {
+	if err := o.add(ctx, db, models.EventOrderStatusChanged, ev.OrderID, ev); err != nil {
+		return err
+	}
+	eventType, ok := orderStatusEvents[ev.Status]
+	if !ok {
+		return nil
+	}
+	return o.add(ctx, db, eventType, ev.OrderID, ev)
+}
+
+type EventRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewEventRepository(db *pgxpool.Pool) *EventRepository {
+	return &EventRepository{db: db}
+}
+
+// Relay hands up to limit unpublished events, oldest first, to publish and
+// marks them published once it succeeds. The events stay locked meanwhile, so
+// several instances can relay at once without publishing an event twice;
+// when publish fails they are kept for the next run with the error, which
+// is returned. It returns how many events were published.
+func (r *EventRepository) Relay(ctx context.Context, limit int, publish func(ctx context.Context, events []*models.OutboxEvent) error) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var events []*models.OutboxEvent
+	err = pgxscan.Select(ctx, tx, &events,
+		`SELECT id, type, key, payload, attempts, created_at FROM event_outbox
+		WHERE published_at IS NULL
+		ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get outbox events")
+		return 0, fmt.Errorf("failed to get outbox events: %w", err)
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+	ids := make([]int64, len(events))
+	for i, ev := range events {
+		ids[i] = ev.ID
+	}
+
+	pubErr := publish(ctx, events)
+	if pubErr != nil {
+		_, err = tx.Exec(ctx, `UPDATE event_outbox SET attempts = attempts + 1, last_error = $2 WHERE id = ANY($1)`, ids, pubErr.Error())
+	} else {
+		_, err = tx.Exec(ctx, `UPDATE event_outbox SET published_at = NOW() WHERE id = ANY($1)`, ids)
+	}
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to update outbox events")
+		return 0, fmt.Errorf("failed to update outbox events: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	if pubErr != nil {
+		return 0, fmt.Errorf("failed to publish events: %w", pubErr)
+	}
+	return len(events), nil
+}
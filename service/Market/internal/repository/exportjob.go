@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// exportJobColumns select an export_jobs row into models.ExportJob.
+var exportJobColumns = []string{
+	"id", "seller_id", "format", "status", "total_rows", "processed_rows", "file_name", "error",
+	"expires_at", "created_at", "updated_at", "finished_at",
+}
+
+// claimExportJobQuery starts the oldest queued job, or a running one whose
+// progress has not moved for $1 seconds because its worker went away.
+var claimExportJobQuery = `UPDATE export_jobs SET status = 'running', processed_rows = 0, updated_at = NOW()
+	WHERE id = (
+		SELECT id FROM export_jobs
+		WHERE status = 'queued' OR (status = 'running' AND updated_at < NOW() - make_interval(secs => $1))
+		ORDER BY id LIMIT 1
+		FOR UPDATE SKIP LOCKED)
+	RETURNING ` + strings.Join(exportJobColumns, ", ")
+
+type ExportJobRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewExportJobRepository(db *pgxpool.Pool) *ExportJobRepository {
+	return &ExportJobRepository{db: db}
+}
+
+// Create queues an export of the seller's total products.
+func (r *ExportJobRepository) Create(ctx context.Context, sellerID int, format string, total int) (*models.ExportJob, error) {
+	query, args, err := psql.Insert("export_jobs").
+		Columns("seller_id", "format", "total_rows").
+		Values(sellerID, format, total).
+		Suffix(returning(exportJobColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build insert export job query")
+		return nil, fmt.Errorf("failed to build insert export job query: %w", err)
+	}
+
+	var job models.ExportJob
+	if err := pgxscan.Get(ctx, r.db, &job, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to create export job")
+		return nil, fmt.Errorf("failed to create export job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// GetByID returns an export job. sellerID, when non-zero, restricts it to
+// that seller's jobs.
+func (r *ExportJobRepository) GetByID(ctx context.Context, id, sellerID int) (*models.ExportJob, error) {
+	query := `SELECT ` + strings.Join(exportJobColumns, ", ") + ` FROM export_jobs
+		WHERE id = $1 AND ($2 = 0 OR seller_id = $2)`
+
+	var job models.ExportJob
+	if err := pgxscan.Get(ctx, r.db, &job, query, id, sellerID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.NotFound(fmt.Sprintf("export with id %d not found", id))
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get export job")
+		return nil, fmt.Errorf("failed to get export job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// Claim starts the next job to run, or returns nil when there is none.
+// Running jobs idle for longer than stale are taken over.
+func (r *ExportJobRepository) Claim(ctx context.Context, stale time.Duration) (*models.ExportJob, error) {
+	var job models.ExportJob
+	if err := pgxscan.Get(ctx, r.db, &job, claimExportJobQuery, stale.Seconds()); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to claim export job")
+		return nil, fmt.Errorf("failed to claim export job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// Progress records how many rows a running job has written.
+func (r *ExportJobRepository) Progress(ctx context.Context, id, processed int) error {
+	_, err := r.db.Exec(ctx, `UPDATE export_jobs SET processed_rows = $2, updated_at = NOW()
+		WHERE id = $1 AND status = 'running'`, id, processed)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to update export job progress")
+		return fmt.Errorf("failed to update export job progress: %w", err)
+	}
+	return nil
+}
+
+// Finish marks a job done with its file, kept until expiresAt.
+func (r *ExportJobRepository) Finish(ctx context.Context, id, processed int, fileName string, expiresAt time.Time) error {
+	_, err := r.db.Exec(ctx, `UPDATE export_jobs SET status = 'done', processed_rows = $2, file_name = $3,
+		expires_at = $4, updated_at = NOW(), finished_at = NOW()
+		WHERE id = $1`, id, processed, fileName, expiresAt)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to finish export job")
+		return fmt.Errorf("failed to finish export job: %w", err)
+	}
+	return nil
+}
+
+// Fail marks a job failed with a message for the seller.
+func (r *ExportJobRepository) Fail(ctx context.Context, id int, message string) error {
+	_, err := r.db.Exec(ctx, `UPDATE export_jobs SET status = 'failed', error = $2, updated_at = NOW(), finished_at = NOW()
+		WHERE id = $1`, id, message)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to fail export job")
+		return fmt.Errorf("failed to fail export job: %w", err)
+	}
+	return nil
+}
+
+// Expire marks done jobs past their expiry as expired and returns their
+// file names so the files can be removed.
+func (r *ExportJobRepository) Expire(ctx context.Context) ([]string, error) {
+	var files []string
+	err := pgxscan.Select(ctx, r.db, &files, `UPDATE export_jobs SET status = 'expired', updated_at = NOW()
+		WHERE status = 'done' AND expires_at <= NOW()
+		RETURNING file_name`)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to expire export jobs")
+		return nil, fmt.Errorf("failed to expire export jobs: %w", err)
+	}
+	return files, nil
+}
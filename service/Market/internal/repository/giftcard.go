@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// giftCardColumns select a gift_cards row into models.GiftCard.
+var giftCardColumns = []string{
+	"id", "code", "initial_amount::float8 AS initial_amount", "balance::float8 AS balance",
+	"expires_at", "created_by", "created_at",
+}
+
+// giftCardCodeAlphabet leaves out letters and digits that read alike.
+const giftCardCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// GiftCardRepository issues gift cards and keeps buyers' store credit.
+// Both are spent and given back by PaymentRepository.
+type GiftCardRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewGiftCardRepository(db *pgxpool.Pool) *GiftCardRepository {
+	return &GiftCardRepository{db: db}
+}
+
+// Create issues a gift card worth req.Amount under the upper-cased code,
+// or a random 16 character one when the request has none.
+func (r *GiftCardRepository) Create(ctx context.Context, createdBy int, req *models.CreateGiftCardRequest) (*models.GiftCard, error) {
+	code := strings.ToUpper(req.Code)
+	if code == "" {
+		var err error
+		if code, err = newGiftCardCode(); err != nil {
+			return nil, err
+		}
+	}
+
+	query, args, err := psql.Insert("gift_cards").
+		Columns("code", "initial_amount", "balance", "expires_at", "created_by").
+		Values(code, req.Amount, req.Amount, req.ExpiresAt, createdBy).
+		Suffix(returning(giftCardColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build insert gift card query")
+		return nil, fmt.Errorf("failed to build insert gift card query: %w", err)
+	}
+
+	var card models.GiftCard
+	if err := pgxscan.Get(ctx, r.db, &card, query, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, apperrors.Conflict(fmt.Sprintf("gift card %s already exists", code))
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to create gift card")
+		return nil, fmt.Errorf("failed to create gift card: %w", err)
+	}
+
+	return &card, nil
+}
+
+// GetBalance returns what is left on a gift card, expired or not.
+func (r *GiftCardRepository) GetBalance(ctx context.Context, code string) (*models.GiftCardBalance, error) {
+	var balance models.GiftCardBalance
+	err := pgxscan.Get(ctx, r.db, &balance,
+		`SELECT code, balance::float8 AS balance, expires_at FROM gift_cards WHERE code = $1`,
+		strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.NotFound("gift card not found")
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get gift card")
+		return nil, fmt.Errorf("failed to get gift card: %w", err)
+	}
+
+	return &balance, nil
+}
+
+// GetStoreCredit returns the user's store credit, zero when they never had
+// any.
+func (r *GiftCardRepository) GetStoreCredit(ctx context.Context, userID int) (*models.StoreCredit, error) {
+	credit := models.StoreCredit{UserID: userID}
+	err := pgxscan.Get(ctx, r.db, &credit,
+		`SELECT user_id, balance::float8 AS balance, updated_at FROM store_credits WHERE user_id = $1`, userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		logger.GetLogger().WithField("err", err).Error("failed to get store credit")
+		return nil, fmt.Errorf("failed to get store credit: %w", err)
+	}
+
+	return &credit, nil
+}
+
+// GrantStoreCredit adds amount to the user's store credit.
+func (r *GiftCardRepository) GrantStoreCredit(ctx context.Context, userID int, amount float64) (*models.StoreCredit, error) {
+	var credit models.StoreCredit
+	err := pgxscan.Get(ctx, r.db, &credit,
+		`INSERT INTO store_credits (user_id, balance) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET balance = store_credits.balance + EXCLUDED.balance, updated_at = NOW()
+		RETURNING user_id, balance::float8 AS balance, updated_at`, userID, amount)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to grant store credit")
+		return nil, fmt.Errorf("failed to grant store credit: %w", err)
+	}
+
+	return &credit, nil
+}
+
+func newGiftCardCode() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate gift card code: %w", err)
+	}
+	for i := range b {
+		b[i] = giftCardCodeAlphabet[int(b[i])%len(giftCardCodeAlphabet)]
+	}
+	return string(b), nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/readonly"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+)
+
+// handoverColumns select an orders row into models.Handover.
+var handoverColumns = []string{
+	"id", "order_number", "COALESCE(status, 'pending') AS status", "COALESCE(payment_method, '') AS payment_method",
+	"total_amount::float8 AS total_amount", "handed_over_at", "handed_over_by", "handed_over_role",
+}
+
+func newPickupCode() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// PickupCode returns the buyer's pickup code for an order, generating it on
+// first use. Orders of other users are reported as not found.
+func (r *DeliveryRepository) PickupCode(ctx context.Context, orderID, userID int) (string, error) {
+	var code *string
+	err := r.db.QueryRow(ctx, `SELECT pickup_code FROM orders WHERE id = $1 AND user_id = $2`, orderID, userID).Scan(&code)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", apperrors.OrderNotFound(orderID)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get pickup code")
+		return "", fmt.Errorf("failed to get pickup code: %w", err)
+	}
+	if code != nil {
+		return *code, nil
+	}
+
+	if err := r.readOnly.Check(readonly.TableOrders); err != nil {
+		return "", err
+	}
+	generated, err := newPickupCode()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate pickup code: %w", err)
+	}
+
+	// COALESCE keeps the code of a concurrent request that won the race.
+	var stored string
+	err = r.db.QueryRow(ctx, `UPDATE orders SET pickup_code = COALESCE(pickup_code, $3)
+		WHERE id = $1 AND user_id = $2 RETURNING pickup_code`, orderID, userID, generated).Scan(&stored)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to store pickup code")
+		return "", fmt.Errorf("failed to store pickup code: %w", err)
+	}
+
+	return stored, nil
+}
+
+// OrderIDByPickupCode resolves a scanned pickup code to its order.
+func (r *DeliveryRepository) OrderIDByPickupCode(ctx context.Context, code string) (int, error) {
+	var orderID int
+	err := r.db.QueryRow(ctx, `SELECT id FROM orders WHERE pickup_code = $1`, code).Scan(&orderID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, apperrors.NotFound("unknown pickup code")
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to resolve pickup code")
+		return 0, fmt.Errorf("failed to resolve pickup code: %w", err)
+	}
+	return orderID, nil
+}
+
+// MarkHandedOver records the handover of an order. It succeeds exactly once
+// per order; later scans return a conflict.
+func (r *DeliveryRepository) MarkHandedOver(ctx context.Context, orderID, userID int, role string) (*models.Handover, error) {
+	if err := r.readOnly.Check(readonly.TableOrders); err != nil {
+		return nil, err
+	}
+
+	var h models.Handover
+	err := pgxscan.Get(ctx, r.db, &h, `UPDATE orders
+		SET handed_over_at = NOW(), handed_over_by = $2, handed_over_role = $3, updated_at = NOW()
+		WHERE id = $1 AND handed_over_at IS NULL AND COALESCE(status, 'pending') <> 'cancelled'
+		`+returning(handoverColumns), orderID, userID, role)
+	if err == nil {
+		return &h, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		logger.GetLogger().WithField("err", err).Error("failed to mark order handed over")
+		return nil, fmt.Errorf("failed to mark order handed over: %w", err)
+	}
+
+	var status string
+	var handedOver bool
+	err = r.db.QueryRow(ctx, `SELECT COALESCE(status, 'pending'), handed_over_at IS NOT NULL FROM orders WHERE id = $1`, orderID).Scan(&status, &handedOver)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.OrderNotFound(orderID)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to check order handover")
+		return nil, fmt.Errorf("failed to check order handover: %w", err)
+	}
+	if handedOver {
+		return nil, apperrors.Conflict("order has already been handed over")
+	}
+	return nil, apperrors.Conflict(fmt.Sprintf("order in status %q cannot be handed over", status))
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/Zifeldev/marketback/service/Market/internal/models"
 )
@@ -15,8 +16,9 @@ type CartRepo interface {
 }
 
 type ProductRepo interface {
-	GetAll(ctx context.Context, categoryID, sellerID *int, status string, pagination *models.PaginationParams) ([]*models.ProductWithDetails, int64, error)
+	GetAll(ctx context.Context, categoryID, sellerID *int, status string, visitorBucket *int, pagination *models.PaginationParams) ([]*models.ProductWithDetails, int64, error)
 	GetByID(ctx context.Context, id int) (*models.ProductWithDetails, error)
+	GetByIDs(ctx context.Context, ids []int) (map[int]*models.ProductWithDetails, error)
 }
 
 type CategoryRepo interface {
@@ -25,6 +27,191 @@ type CategoryRepo interface {
 }
 
 type OrderRepo interface {
-	GetUserOrders(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error)
+	GetUserOrders(ctx context.Context, userID int, query string, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error)
 	GetByID(ctx context.Context, orderID int) (*models.OrderWithItems, error)
+	GetByNumber(ctx context.Context, orderNumber string) (*models.OrderWithItems, error)
+}
+
+type DeliveryRepo interface {
+	GetCourierDeliveries(ctx context.Context, courierID int, state string, pagination *models.PaginationParams) ([]*models.Delivery, int64, error)
+	MarkDelivered(ctx context.Context, orderID, courierID int, proofURL string) (*models.Delivery, error)
+	AddProof(ctx context.Context, proof *models.DeliveryProof) (*models.DeliveryProof, error)
+	GetProofs(ctx context.Context, orderID int) ([]*models.DeliveryProof, error)
+	IsAssignedCourier(ctx context.Context, orderID, courierID int) (bool, error)
+	HasSellerItems(ctx context.Context, orderID, sellerUserID int) (bool, error)
+	IsOrderOwner(ctx context.Context, orderID, userID int) (bool, error)
+	PickupCode(ctx context.Context, orderID, userID int) (string, error)
+	OrderIDByPickupCode(ctx context.Context, code string) (int, error)
+	MarkHandedOver(ctx context.Context, orderID, userID int, role string) (*models.Handover, error)
+}
+
+type ReportRepo interface {
+	Create(ctx context.Context, reporterID int, req *models.CreateReportRequest) (*models.Report, error)
+	GetAll(ctx context.Context, status string, pagination *models.PaginationParams) ([]*models.Report, int64, error)
+	Resolve(ctx context.Context, id, adminID int, req *models.ResolveReportRequest) (*models.Report, error)
+}
+
+type ReviewRepo interface {
+	Create(ctx context.Context, productID, userID int, req *models.CreateReviewRequest, holdReasons []string) (*models.Review, error)
+	GetByProduct(ctx context.Context, productID int, pagination *models.PaginationParams) ([]*models.Review, int64, error)
+	Delete(ctx context.Context, id, userID int) error
+}
+
+type InventoryRepo interface {
+	CreateImport(ctx context.Context, sellerID int, req *models.InventoryImportRequest, riskPercent float64) (*models.InventoryImport, error)
+	GetImport(ctx context.Context, id, sellerID int) (*models.InventoryImport, error)
+	ConfirmImport(ctx context.Context, id, sellerID int) (*models.InventoryImport, error)
+	DiscardImport(ctx context.Context, id, sellerID int) (*models.InventoryImport, error)
+}
+
+type NotificationRepo interface {
+	GetUserNotifications(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.Notification, int64, error)
+	MarkRead(ctx context.Context, id, userID int) error
+}
+
+type CommissionRepo interface {
+	Create(ctx context.Context, adminID int, req *models.CreateCommissionRateRequest) (*models.CommissionRate, error)
+	GetAll(ctx context.Context, sellerID, categoryID *int, pagination *models.PaginationParams) ([]*models.CommissionRate, int64, error)
+	ForSellerUser(ctx context.Context, userID int) ([]*models.CommissionRate, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type PromoRepo interface {
+	Create(ctx context.Context, createdBy int, req *models.CreatePromoCodeRequest) (*models.PromoCode, error)
+	GetAll(ctx context.Context, sellerID *int, pagination *models.PaginationParams) ([]*models.PromoCode, int64, error)
+	Deactivate(ctx context.Context, id int, sellerID *int) (*models.PromoCode, error)
+}
+
+type StatementRepo interface {
+	GetSellerStatements(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.SellerStatement, int64, error)
+	GetSellerStatement(ctx context.Context, userID int, period time.Time) (*models.SellerStatement, error)
+}
+
+type TicketRepo interface {
+	Create(ctx context.Context, ticket *models.Ticket, first *models.TicketMessage) (*models.Ticket, error)
+	GetByID(ctx context.Context, id int) (*models.Ticket, error)
+	GetAll(ctx context.Context, userID *int, status string, pagination *models.PaginationParams) ([]*models.Ticket, int64, error)
+	AddMessage(ctx context.Context, msg *models.TicketMessage, status string) (*models.Ticket, error)
+	UpdateStatus(ctx context.Context, id int, status string) (*models.Ticket, error)
+}
+
+type ShareLinkRepo interface {
+	Create(ctx context.Context, productID, userID int, source string) (*models.ShareLink, error)
+	Click(ctx context.Context, code string) (int, error)
+	GetSellerStats(ctx context.Context, userID int) ([]*models.ShareLinkStats, error)
+}
+
+type APIUsageRepo interface {
+	GetAPIUsage(ctx context.Context, userID int, since string) ([]*models.APIUsage, error)
+	SetAPIPlan(ctx context.Context, sellerID int, plan string) (*models.Seller, error)
+}
+
+type UserMergeRepo interface {
+	Merge(ctx context.Context, adminID int, req *models.MergeUsersRequest) (*models.UserMerge, error)
+	GetAll(ctx context.Context, pagination *models.PaginationParams) ([]*models.UserMerge, int64, error)
+}
+
+type StorefrontRepo interface {
+	Resolve(ctx context.Context, tenant string) (*models.StorefrontSettings, error)
+	GetAll(ctx context.Context) ([]*models.StorefrontSettings, error)
+	Upsert(ctx context.Context, tenant string, adminID int, req *models.UpdateStorefrontSettingsRequest) (*models.StorefrontSettings, error)
+	Delete(ctx context.Context, tenant string) error
+}
+
+type SettingsRepo interface {
+	GetAll(ctx context.Context) ([]*models.Setting, error)
+	Set(ctx context.Context, key, value string, adminID int) (*models.Setting, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type PaymentRepo interface {
+	Create(ctx context.Context, payment *models.Payment) (*models.Payment, error)
+	Lock(ctx context.Context, orderID int, ttl time.Duration) error
+	Unlock(ctx context.Context, orderID int) error
+	Redeem(ctx context.Context, orderID, userID int, due float64, req *models.PayOrderRequest) ([]*models.Payment, float64, error)
+	GetByRef(ctx context.Context, providerRef string) (*models.Payment, error)
+	ListForOrder(ctx context.Context, orderID int) ([]*models.Payment, error)
+	Settle(ctx context.Context, id int, status string) (*models.Payment, error)
+	AddRefund(ctx context.Context, id int, amount float64) (*models.Payment, error)
+}
+
+type ChargebackRepo interface {
+	Open(ctx context.Context, cb *models.Chargeback) (*models.Chargeback, error)
+	GetByID(ctx context.Context, id int) (*models.Chargeback, error)
+	GetByRef(ctx context.Context, provider, ref string) (*models.Chargeback, error)
+	Resolve(ctx context.Context, id int, status string) (*models.Chargeback, error)
+}
+
+type SellerStatsRepo interface {
+	GetSellerStats(ctx context.Context, userID int, from, to time.Time, interval string, lowStock int) (*models.SellerStats, error)
+	GetLowStock(ctx context.Context, userID, threshold int) ([]*models.LowStockAlert, error)
+}
+
+type PayoutRepo interface {
+	SetAccount(ctx context.Context, userID int, req *models.SetPayoutAccountRequest) (*models.PayoutAccount, error)
+	GetAccount(ctx context.Context, userID int) (*models.PayoutAccount, error)
+	ListForSeller(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.Payout, int64, error)
+}
+
+type PaymentMethodRepo interface {
+	GetCustomer(ctx context.Context, userID int, provider string) (string, error)
+	SaveCustomer(ctx context.Context, userID int, provider, customer string) (string, error)
+	Save(ctx context.Context, method *models.SavedPaymentMethod) (*models.SavedPaymentMethod, error)
+	List(ctx context.Context, userID int) ([]*models.SavedPaymentMethod, error)
+	Get(ctx context.Context, id, userID int) (*models.SavedPaymentMethod, error)
+	Delete(ctx context.Context, id, userID int) error
+}
+
+type GiftCardRepo interface {
+	Create(ctx context.Context, createdBy int, req *models.CreateGiftCardRequest) (*models.GiftCard, error)
+	GetBalance(ctx context.Context, code string) (*models.GiftCardBalance, error)
+	GetStoreCredit(ctx context.Context, userID int) (*models.StoreCredit, error)
+	GrantStoreCredit(ctx context.Context, userID int, amount float64) (*models.StoreCredit, error)
+}
+
+type SellerOnboardingRepo interface {
+	GetOnboardingState(ctx context.Context, userID int) (*models.SellerOnboardingState, error)
+}
+
+type SellerOrderRepo interface {
+	GetSellerOrders(ctx context.Context, sellerUserID int, fulfillmentStatus string, pagination *models.PaginationParams) ([]*models.SellerOrder, int64, error)
+	GetSellerOrder(ctx context.Context, orderID, sellerUserID int) (*models.SellerOrder, error)
+	UpdateFulfillment(ctx context.Context, orderID, sellerUserID int, req *models.UpdateFulfillmentRequest) (*models.SellerOrder, error)
+	GetPackingSlip(ctx context.Context, orderID, sellerUserID int) (*models.PackingSlip, error)
+}
+
+type SellerHealthRepo interface {
+	GetCounts(ctx context.Context, sellerID int, window, shipWithin time.Duration) (*models.SellerHealthCounts, error)
+	GetCountsByUser(ctx context.Context, userID int, window, shipWithin time.Duration) (*models.SellerHealthCounts, error)
+	GetRules(ctx context.Context) ([]*models.SellerHealthRule, error)
+	CreateRule(ctx context.Context, adminID int, req *models.SellerHealthRuleRequest) (*models.SellerHealthRule, error)
+	UpdateRule(ctx context.Context, id int, req *models.SellerHealthRuleRequest) (*models.SellerHealthRule, error)
+	DeleteRule(ctx context.Context, id int) error
+	GetActions(ctx context.Context, sellerID *int, pagination *models.PaginationParams) ([]*models.SellerHealthAction, int64, error)
+}
+
+type AdminViewRepo interface {
+	Save(ctx context.Context, adminID int, req *models.SaveAdminViewRequest) (*models.AdminView, error)
+	GetByAdmin(ctx context.Context, adminID int, list string) ([]*models.AdminView, error)
+	Delete(ctx context.Context, id, adminID int) error
+}
+
+type SystemRepo interface {
+	GetOutboxStatus(ctx context.Context) (*models.OutboxStatus, error)
+	GetExportQueueStatus(ctx context.Context) (*models.ExportQueueStatus, error)
+	GetReplication(ctx context.Context) ([]models.ReplicaStatus, bool, error)
+}
+
+type CatalogRevisionRepo interface {
+	Create(ctx context.Context, sellerID int, reason, note string, createdBy int) (*models.CatalogRevision, error)
+	GetBySeller(ctx context.Context, sellerID int) ([]*models.CatalogRevision, error)
+	GetByID(ctx context.Context, id, sellerID int) (*models.CatalogRevision, error)
+	Restore(ctx context.Context, id, sellerID int, restoreStock bool, restoredBy int) (*models.CatalogRestore, error)
+}
+
+type TransformRuleRepo interface {
+	Create(ctx context.Context, adminID int, req *models.CreateTransformRuleRequest) (*models.TransformRule, error)
+	GetAll(ctx context.Context) ([]*models.TransformRule, error)
+	Update(ctx context.Context, id int, req *models.UpdateTransformRuleRequest) (*models.TransformRule, error)
+	Delete(ctx context.Context, id int) error
 }
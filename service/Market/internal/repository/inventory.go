@@ -0,0 +1,288 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/readonly"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// inventoryImportColumns select an inventory_imports row into
+// models.InventoryImport.
+var inventoryImportColumns = []string{
+	"id", "seller_id", "status", "risk_percent::float8 AS risk_percent", "created_at", "decided_at",
+}
+
+// inventoryImportRowColumns select an inventory_import_rows row into
+// models.InventoryImportRow.
+var inventoryImportRowColumns = []string{
+	"line", "product_id", "stock", "price::float8 AS price",
+	"previous_stock", "previous_price::float8 AS previous_price", "issues", "status",
+}
+
+// applyHeldRowsQuery applies the held rows of import $1 to the products
+// seller $2 still has. Products that have since got variants keep their
+// stock.
+const applyHeldRowsQuery = `UPDATE products p SET
+		stock = CASE WHEN EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = p.id)
+			THEN p.stock ELSE COALESCE(r.stock, p.stock) END,
+		price = COALESCE(r.price, p.price),
+		updated_at = NOW()
+	FROM inventory_import_rows r
+	WHERE r.import_id = $1 AND r.status = 'held' AND p.id = r.product_id AND p.seller_id = $2`
+
+type InventoryRepository struct {
+	db       *pgxpool.Pool
+	readOnly *readonly.Guard
+}
+
+// NewInventoryRepository creates an inventory import repository. A nil
+// guard never blocks writes.
+func NewInventoryRepository(db *pgxpool.Pool, guard *readonly.Guard) *InventoryRepository {
+	return &InventoryRepository{db: db, readOnly: guard}
+}
+
+type inventoryProduct struct {
+	ID          int     `db:"id"`
+	Stock       int     `db:"stock"`
+	Price       float64 `db:"price"`
+	HasVariants bool    `db:"has_variants"`
+}
+
+// CreateImport reconciles the lines with the seller's products, applies the
+// rows within riskPercent and stores the import with its report. Rows above
+// the threshold wait for ConfirmImport; lines naming products the seller
+// does not have are skipped.
+func (r *InventoryRepository) CreateImport(ctx context.Context, sellerID int, req *models.InventoryImportRequest, riskPercent float64) (*models.InventoryImport, error) {
+	if err := r.readOnly.Check(readonly.TableProducts); err != nil {
+		return nil, err
+	}
+
+	productIDs := make([]int, 0, len(req.Rows))
+	seen := make(map[int]bool, len(req.Rows))
+	for i, line := range req.Rows {
+		if line.Stock == nil && line.Price == nil {
+			return nil, apperrors.ValidationError("rows", fmt.Sprintf("line %d sets neither stock nor price", i+1))
+		}
+		if seen[line.ProductID] {
+			return nil, apperrors.ValidationError("rows", fmt.Sprintf("line %d repeats product %d", i+1, line.ProductID))
+		}
+		seen[line.ProductID] = true
+		productIDs = append(productIDs, line.ProductID)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var products []*inventoryProduct
+	err = pgxscan.Select(ctx, tx, &products,
+		`SELECT id, stock, price::float8 AS price,
+			EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = products.id) AS has_variants
+		FROM products
+		WHERE seller_id = $1 AND id = ANY($2) ORDER BY id FOR UPDATE`, sellerID, productIDs)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to lock products")
+		return nil, fmt.Errorf("failed to lock products: %w", err)
+	}
+	current := make(map[int]*inventoryProduct, len(products))
+	for _, p := range products {
+		current[p.ID] = p
+	}
+	// The stock of products with variants is theirs.
+	for i, line := range req.Rows {
+		if p, ok := current[line.ProductID]; ok && p.HasVariants && line.Stock != nil {
+			return nil, apperrors.ValidationError("rows", fmt.Sprintf("line %d sets the stock of product %d, which has variants", i+1, line.ProductID))
+		}
+	}
+
+	imp := &models.InventoryImport{
+		SellerID:    sellerID,
+		Status:      models.InventoryImportApplied,
+		RiskPercent: riskPercent,
+		Rows:        make([]*models.InventoryImportRow, 0, len(req.Rows)),
+	}
+	for i, line := range req.Rows {
+		row := &models.InventoryImportRow{Line: i + 1, ProductID: line.ProductID, Stock: line.Stock, Price: line.Price}
+		p, ok := current[line.ProductID]
+		if !ok {
+			row.NotFound()
+			imp.Rows = append(imp.Rows, row)
+			continue
+		}
+
+		row.Reconcile(p.Stock, p.Price, riskPercent)
+		switch row.Status {
+		case models.InventoryRowHeld:
+			imp.Status = models.InventoryImportAwaitingConfirmation
+		case models.InventoryRowApplied:
+			if _, err := tx.Exec(ctx,
+				`UPDATE products SET stock = COALESCE($2, stock), price = COALESCE($3, price), updated_at = NOW() WHERE id = $1`,
+				row.ProductID, row.Stock, row.Price); err != nil {
+				logger.GetLogger().WithField("err", err).Error("failed to update product inventory")
+				return nil, fmt.Errorf("failed to update product inventory: %w", err)
+			}
+		}
+		imp.Rows = append(imp.Rows, row)
+	}
+
+	query, args, err := psql.Insert("inventory_imports").
+		Columns("seller_id", "status", "risk_percent").
+		Values(imp.SellerID, imp.Status, imp.RiskPercent).
+		Suffix("RETURNING id, created_at").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build insert inventory import query")
+		return nil, fmt.Errorf("failed to build insert inventory import query: %w", err)
+	}
+	if err := tx.QueryRow(ctx, query, args...).Scan(&imp.ID, &imp.CreatedAt); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to create inventory import")
+		return nil, fmt.Errorf("failed to create inventory import: %w", err)
+	}
+
+	insert := psql.Insert("inventory_import_rows").
+		Columns("import_id", "line", "product_id", "stock", "price", "previous_stock", "previous_price", "issues", "status")
+	for _, row := range imp.Rows {
+		insert = insert.Values(imp.ID, row.Line, row.ProductID, row.Stock, row.Price, row.PreviousStock, row.PreviousPrice, row.Issues, row.Status)
+	}
+	query, args, err = insert.ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build insert inventory import rows query")
+		return nil, fmt.Errorf("failed to build insert inventory import rows query: %w", err)
+	}
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to create inventory import rows")
+		return nil, fmt.Errorf("failed to create inventory import rows: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	imp.Tally()
+	return imp, nil
+}
+
+// GetImport returns one of the seller's imports with its report.
+func (r *InventoryRepository) GetImport(ctx context.Context, id, sellerID int) (*models.InventoryImport, error) {
+	return getInventoryImport(ctx, r.db, id, sellerID)
+}
+
+// ConfirmImport applies the rows held by an import awaiting confirmation.
+func (r *InventoryRepository) ConfirmImport(ctx context.Context, id, sellerID int) (*models.InventoryImport, error) {
+	return r.decide(ctx, id, sellerID, true)
+}
+
+// DiscardImport drops the rows held by an import awaiting confirmation. Rows
+// already applied stay applied.
+func (r *InventoryRepository) DiscardImport(ctx context.Context, id, sellerID int) (*models.InventoryImport, error) {
+	return r.decide(ctx, id, sellerID, false)
+}
+
+func (r *InventoryRepository) decide(ctx context.Context, id, sellerID int, confirm bool) (*models.InventoryImport, error) {
+	if err := r.readOnly.Check(readonly.TableProducts); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	err = tx.QueryRow(ctx,
+		`SELECT status FROM inventory_imports WHERE id = $1 AND seller_id = $2 FOR UPDATE`, id, sellerID).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.NotFound("inventory import not found")
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to lock inventory import")
+		return nil, fmt.Errorf("failed to lock inventory import: %w", err)
+	}
+	if status != models.InventoryImportAwaitingConfirmation {
+		return nil, apperrors.Conflict(fmt.Sprintf("inventory import is %s, nothing awaits confirmation", status))
+	}
+
+	rowStatus, importStatus := models.InventoryRowDiscarded, models.InventoryImportDiscarded
+	if confirm {
+		rowStatus, importStatus = models.InventoryRowApplied, models.InventoryImportConfirmed
+		if _, err := tx.Exec(ctx, applyHeldRowsQuery, id, sellerID); err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to apply held inventory rows")
+			return nil, fmt.Errorf("failed to apply held inventory rows: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE inventory_import_rows SET status = $2 WHERE import_id = $1 AND status = 'held'`, id, rowStatus); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to update inventory import rows")
+		return nil, fmt.Errorf("failed to update inventory import rows: %w", err)
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE inventory_imports SET status = $2, decided_at = NOW() WHERE id = $1`, id, importStatus); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to update inventory import")
+		return nil, fmt.Errorf("failed to update inventory import: %w", err)
+	}
+
+	imp, err := getInventoryImport(ctx, tx, id, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return imp, nil
+}
+
+func getInventoryImport(ctx context.Context, db pgxscan.Querier, id, sellerID int) (*models.InventoryImport, error) {
+	query, args, err := psql.Select(inventoryImportColumns...).
+		From("inventory_imports").
+		Where("id = ? AND seller_id = ?", id, sellerID).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build inventory import query")
+		return nil, fmt.Errorf("failed to build inventory import query: %w", err)
+	}
+
+	var imp models.InventoryImport
+	if err := pgxscan.Get(ctx, db, &imp, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.NotFound("inventory import not found")
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get inventory import")
+		return nil, fmt.Errorf("failed to get inventory import: %w", err)
+	}
+
+	query, args, err = psql.Select(inventoryImportRowColumns...).
+		From("inventory_import_rows").
+		Where("import_id = ?", id).
+		OrderBy("line").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build inventory import rows query")
+		return nil, fmt.Errorf("failed to build inventory import rows query: %w", err)
+	}
+
+	imp.Rows = []*models.InventoryImportRow{}
+	if err := pgxscan.Select(ctx, db, &imp.Rows, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get inventory import rows")
+		return nil, fmt.Errorf("failed to get inventory import rows: %w", err)
+	}
+
+	imp.Tally()
+	return &imp, nil
+}
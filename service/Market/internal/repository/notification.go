@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// execer is satisfied by both *pgxpool.Pool and pgx.Tx.
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// notify stores an in-app notification for a user.
+func notify(ctx context.Context, db execer, userID int, kind, message string) error {
+	_, err := db.Exec(ctx, `INSERT INTO notifications (user_id, kind, message) VALUES ($1, $2, $3)`, userID, kind, message)
+	if err != nil {
+		logger.GetLogger().WithFields(map[string]interface{}{
+			"err":     err,
+			"user_id": userID,
+			"kind":    kind,
+		}).Error("failed to create notification")
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+	return nil
+}
+
+type NotificationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewNotificationRepository(db *pgxpool.Pool) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+func (r *NotificationRepository) GetUserNotifications(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.Notification, int64, error) {
+	var totalItems int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM notifications WHERE user_id = $1`, userID).Scan(&totalItems); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to count notifications")
+		return nil, 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	if totalItems == 0 {
+		return []*models.Notification{}, 0, nil
+	}
+
+	query, args, err := psql.Select("id", "user_id", "kind", "message", "read_at", "created_at").
+		From("notifications").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(pagination.GetLimit())).
+		Offset(uint64(pagination.GetOffset())).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build notifications query")
+		return nil, 0, fmt.Errorf("failed to build notifications query: %w", err)
+	}
+
+	notifications := []*models.Notification{}
+	if err := pgxscan.Select(ctx, r.db, &notifications, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get notifications")
+		return nil, 0, fmt.Errorf("failed to get notifications: %w", err)
+	}
+
+	return notifications, totalItems, nil
+}
+
+func (r *NotificationRepository) MarkRead(ctx context.Context, id, userID int) error {
+	result, err := r.db.Exec(ctx, `UPDATE notifications SET read_at = COALESCE(read_at, NOW()) WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to mark notification read")
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperrors.NotFound(fmt.Sprintf("notification with id %d not found", id))
+	}
+	return nil
+}
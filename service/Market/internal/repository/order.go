@@ -2,25 +2,63 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/pkg/fieldcrypt"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
 	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
 	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/money"
+	"github.com/Zifeldev/marketback/service/Market/internal/ordernumber"
+	"github.com/Zifeldev/marketback/service/Market/internal/readonly"
+	"github.com/Zifeldev/marketback/service/Market/internal/retention"
+	"github.com/georgysavva/scany/v2/pgxscan"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type OrderRepository struct {
-	db *pgxpool.Pool
+	db       *pgxpool.Pool
+	numbers  *ordernumber.Generator
+	keyring  *fieldcrypt.Keyring
+	readOnly *readonly.Guard
+	events   *EventOutbox
 }
 
-func NewOrderRepository(db *pgxpool.Pool) *OrderRepository {
-	return &OrderRepository{db: db}
+// NewOrderRepository creates an order repository. A nil keyring stores
+// delivery addresses in plaintext; a nil guard never blocks writes; with a
+// nil outbox no events are recorded.
+func NewOrderRepository(db *pgxpool.Pool, numbers *ordernumber.Generator, keyring *fieldcrypt.Keyring, guard *readonly.Guard, events *EventOutbox) *OrderRepository {
+	if numbers == nil {
+		numbers = ordernumber.Default()
+	}
+	return &OrderRepository{db: db, numbers: numbers, keyring: keyring, readOnly: guard, events: events}
+}
+
+func (r *OrderRepository) openAddress(order *models.Order) error {
+	addr, err := r.keyring.Decrypt(order.DeliveryAddr)
+	if err != nil {
+		logger.GetLogger().WithFields(map[string]interface{}{
+			"err":      err,
+			"order_id": order.ID,
+		}).Error("failed to decrypt delivery address")
+		return fmt.Errorf("failed to decrypt delivery address: %w", err)
+	}
+	order.DeliveryAddr = addr
+	return nil
 }
 
 func (r *OrderRepository) Create(ctx context.Context, userID int, req *models.CreateOrderRequest, items []*models.CartItemWithDetails) (*models.OrderWithItems, error) {
+	// Placing an order also decrements product stock.
+	if err := r.readOnly.Check(readonly.TableOrders, readonly.TableProducts); err != nil {
+		return nil, err
+	}
+
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
@@ -28,12 +66,21 @@ func (r *OrderRepository) Create(ctx context.Context, userID int, req *models.Cr
 	}
 	defer tx.Rollback(ctx)
 
-	for _, item := range items {
+	var promo *models.PromoCode
+	if req.PromoCode != "" {
+		if promo, err = redeemablePromo(ctx, tx, req.PromoCode); err != nil {
+			return nil, err
+		}
+	}
+
+	sellerIDs := make([]int, len(items))
+	thresholds := make([]*int, len(items))
+	for i, item := range items {
 		var currentStock int
-		lockQuery := `SELECT stock FROM products WHERE id = $1 FOR UPDATE`
-		err := tx.QueryRow(ctx, lockQuery, item.ProductID).Scan(&currentStock)
+		lockQuery := `SELECT stock, seller_id, low_stock_threshold FROM products WHERE id = $1 FOR UPDATE`
+		err := tx.QueryRow(ctx, lockQuery, item.ProductID).Scan(&currentStock, &sellerIDs[i], &thresholds[i])
 		if err != nil {
-			if err == pgx.ErrNoRows {
+			if errors.Is(err, pgx.ErrNoRows) {
 				logger.GetLogger().WithField("product_id", item.ProductID).Error("product not found")
 				return nil, fmt.Errorf("product %d not found", item.ProductID)
 			}
@@ -41,45 +88,131 @@ func (r *OrderRepository) Create(ctx context.Context, userID int, req *models.Cr
 			return nil, fmt.Errorf("failed to lock product for stock check: %w", err)
 		}
 
-		if currentStock < item.Quantity {
+		// Variant items draw on the variant's stock; the product's is the
+		// sum of its variants'.
+		if item.VariantID != nil {
+			err := tx.QueryRow(ctx, `SELECT stock FROM product_variants WHERE id = $1 FOR UPDATE`, *item.VariantID).Scan(&currentStock)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return nil, apperrors.NotFound(fmt.Sprintf("variant with id %d not found", *item.VariantID))
+				}
+				logger.GetLogger().WithField("err", err).Error("failed to lock variant for stock check")
+				return nil, fmt.Errorf("failed to lock variant for stock check: %w", err)
+			}
+		}
+
+		// Other carts' reservations are not for sale; this cart's own are
+		// converted into the deduction below.
+		held, err := heldByOthers(ctx, tx, item.ProductID, item.VariantID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if available := currentStock - held; available < item.Quantity {
 			logger.GetLogger().WithFields(map[string]interface{}{
 				"product_id": item.ProductID,
 				"requested":  item.Quantity,
-				"available":  currentStock,
-			}).Error("insufficient stock for product")
-			return nil, fmt.Errorf("insufficient stock for product %d: requested %d, available %d",
-				item.ProductID, item.Quantity, currentStock)
+				"available":  available,
+			}).Warn("insufficient stock for product")
+			return nil, apperrors.InsufficientStockForProduct(item.ProductID)
 		}
 	}
 
-	for _, item := range items {
+	for i, item := range items {
 		updateStockQuery := `UPDATE products SET stock = stock - $1, updated_at = NOW() 
-			WHERE id = $2 AND stock >= $1`
+			WHERE id = $2 AND stock >= $1 RETURNING stock`
 
-		result, err := tx.Exec(ctx, updateStockQuery, item.Quantity, item.ProductID)
+		var stock int
+		err := tx.QueryRow(ctx, updateStockQuery, item.Quantity, item.ProductID).Scan(&stock)
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.GetLogger().WithField("product_id", item.ProductID).Error("stock update affected no rows")
+			return nil, fmt.Errorf("failed to deduct stock for product %d: concurrent modification detected", item.ProductID)
+		}
 		if err != nil {
 			logger.GetLogger().WithField("err", err).Error("failed to update product stock")
 			return nil, fmt.Errorf("failed to update product stock: %w", err)
 		}
+		if err := r.events.stockTaken(ctx, tx, item.ProductID, sellerIDs[i], stock+item.Quantity, stock, thresholds[i]); err != nil {
+			return nil, err
+		}
 
-		if result.RowsAffected() != 1 {
-			logger.GetLogger().WithFields(map[string]interface{}{
-				"product_id":    item.ProductID,
-				"affected_rows": result.RowsAffected(),
-			}).Error("stock update affected unexpected number of rows")
-			return nil, fmt.Errorf("failed to deduct stock for product %d: concurrent modification detected", item.ProductID)
+		if item.VariantID != nil {
+			result, err := tx.Exec(ctx, `UPDATE product_variants SET stock = stock - $1, updated_at = NOW()
+				WHERE id = $2 AND stock >= $1`, item.Quantity, *item.VariantID)
+			if err != nil {
+				logger.GetLogger().WithField("err", err).Error("failed to update variant stock")
+				return nil, fmt.Errorf("failed to update variant stock: %w", err)
+			}
+			if result.RowsAffected() != 1 {
+				return nil, fmt.Errorf("failed to deduct stock for variant %d: concurrent modification detected", *item.VariantID)
+			}
 		}
 	}
 
-	var totalAmount float64
-	for _, item := range items {
-		totalAmount += item.ProductPrice * float64(item.Quantity)
+	// A promotion code discounts the lines it applies to; who funds it is
+	// recorded per line so the discount comes out of the right revenue.
+	lines := make([]money.Line, len(items))
+	fundedBy := make([]*string, len(items))
+	var discount money.Cents
+	applied := false
+	for i, item := range items {
+		lines[i] = money.Line{Price: money.FromFloat(item.ProductPrice), Quantity: item.Quantity}
+		if promo != nil && promo.AppliesTo(sellerIDs[i]) {
+			lineTotal, _ := money.LineTotal(lines[i].Price, lines[i].Quantity)
+			lines[i].Discount = money.PercentOff(lineTotal, promo.PercentOff)
+			fundedBy[i] = &promo.FundedBy
+			discount += lines[i].Discount
+			applied = true
+		}
+	}
+	var promoCode *string
+	if promo != nil {
+		if !applied {
+			return nil, apperrors.ValidationError("promo_code", "does not apply to any item in the cart")
+		}
+		promoCode = &promo.Code
+	}
+	total, err := money.Total(lines)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("invalid order amounts")
+		return nil, fmt.Errorf("invalid order amounts: %w", err)
+	}
+	totalAmount := total.Float()
+
+	var seq int64
+	if err := tx.QueryRow(ctx, `SELECT nextval('order_number_seq')`).Scan(&seq); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to allocate order number")
+		return nil, fmt.Errorf("failed to allocate order number: %w", err)
+	}
+	orderNumber := r.numbers.Format(seq, time.Now())
+
+	deliveryAddr, err := r.keyring.Encrypt(req.DeliveryAddr)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to encrypt delivery address")
+		return nil, fmt.Errorf("failed to encrypt delivery address: %w", err)
+	}
+
+	var giftMessage *string
+	if req.IsGift && req.GiftMessage != "" {
+		giftMessage = &req.GiftMessage
+	}
+	var buyerEmail *string
+	if req.Email != "" {
+		buyerEmail = &req.Email
+	}
+	var packageWeight, packageLength, packageWidth, packageHeight *int
+	if pkg := req.Package; pkg != nil {
+		packageWeight, packageLength = positive(pkg.WeightGrams), positive(pkg.LengthMM)
+		packageWidth, packageHeight = positive(pkg.WidthMM), positive(pkg.HeightMM)
 	}
 
 	orderQuery, orderArgs, err := psql.Insert("orders").
-		Columns("user_id", "total_amount", "payment_method", "delivery_address").
-		Values(userID, totalAmount, req.PaymentMethod, req.DeliveryAddr).
-		Suffix("RETURNING id, user_id, total_amount::float8, COALESCE(status, 'pending') as status, COALESCE(payment_method, '') as payment_method, COALESCE(payment_status, 'pending') as payment_status, delivery_address, created_at, updated_at").
+		Columns("order_number", "user_id", "buyer_email", "total_amount", "payment_method", "delivery_address", "delivery_lat", "delivery_lng",
+			"is_gift", "gift_message", "promo_code", "discount_amount",
+			"package_weight_grams", "package_length_mm", "package_width_mm", "package_height_mm").
+		Values(orderNumber, userID, buyerEmail, totalAmount, req.PaymentMethod, deliveryAddr, req.DeliveryLat, req.DeliveryLng,
+			req.IsGift, giftMessage, promoCode, discount.Float(),
+			packageWeight, packageLength, packageWidth, packageHeight).
+		Suffix(returning(orderColumns)).
 		ToSql()
 	if err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to build order insert query")
@@ -87,28 +220,39 @@ func (r *OrderRepository) Create(ctx context.Context, userID int, req *models.Cr
 	}
 
 	var order models.Order
-	err = tx.QueryRow(ctx, orderQuery, orderArgs...).Scan(
-		&order.ID,
-		&order.UserID,
-		&order.TotalAmount,
-		&order.Status,
-		&order.PaymentMethod,
-		&order.PaymentStatus,
-		&order.DeliveryAddr,
-		&order.CreatedAt,
-		&order.UpdatedAt,
-	)
-	if err != nil {
+	if err := pgxscan.Get(ctx, tx, &order, orderQuery, orderArgs...); err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to create order")
 		return nil, fmt.Errorf("failed to create order: %w", err)
 	}
+	order.DeliveryAddr = req.DeliveryAddr
 
 	orderItems := []models.OrderItem{}
-	for _, cartItem := range items {
+	for i, cartItem := range items {
+		var commissionRate float64
+		if err := tx.QueryRow(ctx, productCommissionRateQuery, cartItem.ProductID).Scan(&commissionRate); err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to resolve commission rate")
+			return nil, fmt.Errorf("failed to resolve commission rate: %w", err)
+		}
+		// Commission is taken from what the seller sells for: after their
+		// own discounts, before the marketplace's.
+		lineTotal, _ := money.LineTotal(lines[i].Price, lines[i].Quantity)
+		if fundedBy[i] != nil && *fundedBy[i] == models.PromoFundedBySeller {
+			lineTotal -= lines[i].Discount
+		}
+		lines[i].Commission = money.Commission(lineTotal, commissionRate)
+		commissionAmount := lines[i].Commission.Float()
+
+		var sku, color *string
+		if cartItem.VariantID != nil {
+			sku, color = &cartItem.SKU, &cartItem.Color
+		}
+
 		itemQuery, itemArgs, err := psql.Insert("order_items").
-			Columns("order_id", "product_id", "quantity", "size", "price").
-			Values(order.ID, cartItem.ProductID, cartItem.Quantity, cartItem.Size, cartItem.ProductPrice).
-			Suffix("RETURNING id, order_id, product_id, quantity, COALESCE(size, '') as size, price::float8, created_at").
+			Columns("order_id", "product_id", "quantity", "size", "price", "commission_rate", "commission_amount",
+				"discount_amount", "discount_funded_by", "variant_id", "sku", "color").
+			Values(order.ID, cartItem.ProductID, cartItem.Quantity, cartItem.Size, cartItem.ProductPrice, commissionRate, commissionAmount,
+				lines[i].Discount.Float(), fundedBy[i], cartItem.VariantID, sku, color).
+			Suffix(returning(orderItemColumns)).
 			ToSql()
 		if err != nil {
 			logger.GetLogger().WithField("err", err).Error("failed to build order item insert query")
@@ -116,22 +260,44 @@ func (r *OrderRepository) Create(ctx context.Context, userID int, req *models.Cr
 		}
 
 		var orderItem models.OrderItem
-		err = tx.QueryRow(ctx, itemQuery, itemArgs...).Scan(
-			&orderItem.ID,
-			&orderItem.OrderID,
-			&orderItem.ProductID,
-			&orderItem.Quantity,
-			&orderItem.Size,
-			&orderItem.Price,
-			&orderItem.CreatedAt,
-		)
-		if err != nil {
+		if err := pgxscan.Get(ctx, tx, &orderItem, itemQuery, itemArgs...); err != nil {
 			logger.GetLogger().WithField("err", err).Error("failed to create order item")
 			return nil, fmt.Errorf("failed to create order item: %w", err)
 		}
 
 		orderItems = append(orderItems, orderItem)
 	}
+
+	// Defensive check of what was written, after the DECIMAL columns rounded it
+	stored := make([]money.Line, len(orderItems))
+	for i, item := range orderItems {
+		stored[i] = money.Line{Price: money.FromFloat(item.Price), Quantity: item.Quantity,
+			Discount: money.FromFloat(item.DiscountAmount), Commission: lines[i].Commission}
+	}
+	if err := money.CheckOrder(stored, money.FromFloat(order.TotalAmount)); err != nil {
+		metrics.MoneyInvariantViolationsTotal.Inc()
+		logger.GetLogger().WithFields(map[string]interface{}{
+			"err":      err,
+			"order_id": order.ID,
+		}).Error("order amounts failed invariant check")
+		return nil, fmt.Errorf("order amounts failed invariant check: %w", err)
+	}
+
+	created := models.OrderCreatedEvent{
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		UserID:      userID,
+		Email:       req.Email,
+		TotalAmount: order.TotalAmount,
+		Items:       make([]models.OrderEventItem, len(orderItems)),
+	}
+	for i, item := range orderItems {
+		created.Items[i] = models.OrderEventItem{ProductID: item.ProductID, VariantID: item.VariantID, Quantity: item.Quantity, Price: item.Price}
+	}
+	if err := r.events.add(ctx, tx, models.EventOrderCreated, order.ID, created); err != nil {
+		return nil, err
+	}
+
 	clearCartQuery, clearCartArgs, err := psql.Delete("carts").
 		Where(sq.Eq{"user_id": userID}).
 		ToSql()
@@ -158,344 +324,312 @@ func (r *OrderRepository) Create(ctx context.Context, userID int, req *models.Cr
 }
 
 func (r *OrderRepository) GetByID(ctx context.Context, orderID int) (*models.OrderWithItems, error) {
-	orderQuery, orderArgs, err := psql.Select(
-		"id", "user_id", "total_amount::float8", "COALESCE(status, 'pending') as status", "COALESCE(payment_method, '') as payment_method",
-		"COALESCE(payment_status, 'pending') as payment_status", "delivery_address", "created_at", "updated_at",
-	).From("orders").
-		Where(sq.Eq{"id": orderID}).
+	return r.getOne(ctx, sq.Eq{"id": orderID})
+}
+
+// GetByNumber looks an order up by its public order number
+func (r *OrderRepository) GetByNumber(ctx context.Context, orderNumber string) (*models.OrderWithItems, error) {
+	return r.getOne(ctx, sq.Eq{"order_number": orderNumber})
+}
+
+// positive returns &n, or nil for an unknown (zero) measure.
+func positive(n int) *int {
+	if n <= 0 {
+		return nil
+	}
+	return &n
+}
+
+// orderColumns select an orders row into models.Order.
+var orderColumns = []string{
+	"id", "order_number", "user_id", "total_amount::float8 AS total_amount",
+	"COALESCE(status, 'pending') AS status", "COALESCE(payment_method, '') AS payment_method",
+	"COALESCE(payment_status, 'pending') AS payment_status", "delivery_address",
+	"is_gift", "COALESCE(gift_message, '') AS gift_message", "COALESCE(promo_code, '') AS promo_code",
+	"discount_amount::float8 AS discount_amount", "created_at", "updated_at",
+	"package_weight_grams", "package_length_mm", "package_width_mm", "package_height_mm",
+}
+
+// orderItemColumns select an order_items row into models.OrderItem.
+var orderItemColumns = []string{
+	"id", "order_id", "product_id", "quantity", "COALESCE(size, '') AS size", "price::float8 AS price",
+	"discount_amount::float8 AS discount_amount", "COALESCE(discount_funded_by, '') AS discount_funded_by",
+	"variant_id", "COALESCE(sku, '') AS sku", "COALESCE(color, '') AS color",
+	"fulfillment_status", "created_at",
+}
+
+func (r *OrderRepository) getOne(ctx context.Context, where sq.Eq) (*models.OrderWithItems, error) {
+	orderQuery, orderArgs, err := psql.Select(orderColumns...).
+		From("orders").
+		Where(where).
 		ToSql()
 	if err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to build order select query")
 		return nil, fmt.Errorf("failed to build order select query: %w", err)
 	}
 
-	var order models.Order
-	err = r.db.QueryRow(ctx, orderQuery, orderArgs...).Scan(
-		&order.ID,
-		&order.UserID,
-		&order.TotalAmount,
-		&order.Status,
-		&order.PaymentMethod,
-		&order.PaymentStatus,
-		&order.DeliveryAddr,
-		&order.CreatedAt,
-		&order.UpdatedAt,
-	)
-	if err != nil {
+	order := &models.OrderWithItems{}
+	if err := pgxscan.Get(ctx, r.db, &order.Order, orderQuery, orderArgs...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.NotFound("order not found")
+		}
 		logger.GetLogger().WithField("err", err).Error("failed to get order")
 		return nil, fmt.Errorf("failed to get order: %w", err)
 	}
-
-	itemsQuery, itemsArgs, err := psql.Select(
-		"id", "order_id", "product_id", "quantity", "COALESCE(size, '') as size", "price::float8", "created_at",
-	).From("order_items").
-		Where(sq.Eq{"order_id": orderID}).
-		ToSql()
-	if err != nil {
-		logger.GetLogger().WithField("err", err).Error("failed to build order items select query")
-		return nil, fmt.Errorf("failed to build order items select query: %w", err)
-	}
-
-	rows, err := r.db.Query(ctx, itemsQuery, itemsArgs...)
-	if err != nil {
-		logger.GetLogger().WithField("err", err).Error("failed to get order items")
-		return nil, fmt.Errorf("failed to get order items: %w", err)
+	if err := r.openAddress(&order.Order); err != nil {
+		return nil, err
 	}
-	defer rows.Close()
 
-	var items []models.OrderItem
-	for rows.Next() {
-		var item models.OrderItem
-		if err := rows.Scan(
-			&item.ID,
-			&item.OrderID,
-			&item.ProductID,
-			&item.Quantity,
-			&item.Size,
-			&item.Price,
-			&item.CreatedAt,
-		); err != nil {
-			logger.GetLogger().WithField("err", err).Error("failed to scan order item")
-			return nil, fmt.Errorf("failed to scan order item: %w", err)
-		}
-		items = append(items, item)
+	if err := r.attachItems(ctx, []*models.OrderWithItems{order}); err != nil {
+		return nil, err
 	}
-
-	return &models.OrderWithItems{
-		Order: order,
-		Items: items,
-	}, nil
+	return order, nil
 }
 
-func (r *OrderRepository) GetUserOrders(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error) {
-	countQuery, countArgs, err := psql.Select("COUNT(*)").
+// listOrders returns one page of orders matching where, newest first. The
+// page is taken over orders alone; items are loaded afterwards in one query.
+func (r *OrderRepository) listOrders(ctx context.Context, where sq.And, pagination *models.PaginationParams) ([]*models.OrderWithItems, error) {
+	query, args, err := psql.Select(orderColumns...).
 		From("orders").
-		Where(sq.Eq{"user_id": userID}).
+		Where(where).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(pagination.GetLimit())).
+		Offset(uint64(pagination.GetOffset())).
 		ToSql()
-
 	if err != nil {
-		logger.GetLogger().WithField("err", err).Error("failed to build count query")
-		return nil, 0, fmt.Errorf("failed to build count query: %w", err)
+		logger.GetLogger().WithField("err", err).Error("failed to build orders query")
+		return nil, fmt.Errorf("failed to build orders query: %w", err)
 	}
 
-	var totalItems int64
-	if err := r.db.QueryRow(ctx, countQuery, countArgs...).Scan(&totalItems); err != nil {
-		logger.GetLogger().WithField("err", err).Error("failed to count orders")
-		return nil, 0, fmt.Errorf("failed to count orders: %w", err)
+	orders := []*models.OrderWithItems{}
+	if err := pgxscan.Select(ctx, r.db, &orders, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get orders")
+		return nil, fmt.Errorf("failed to get orders: %w", err)
+	}
+	for _, order := range orders {
+		if err := r.openAddress(&order.Order); err != nil {
+			return nil, err
+		}
 	}
 
-	if totalItems == 0 {
-		return []*models.OrderWithItems{}, 0, nil
+	if err := r.attachItems(ctx, orders); err != nil {
+		return nil, err
 	}
+	return orders, nil
+}
 
-	query, args, err := psql.Select(
-		"o.id", "o.user_id", "o.total_amount::float8",
-		"COALESCE(o.status, 'pending') as status",
-		"COALESCE(o.payment_method, '') as payment_method",
-		"COALESCE(o.payment_status, 'pending') as payment_status",
-		"o.delivery_address", "o.created_at", "o.updated_at",
-		"oi.id as item_id", "oi.product_id", "oi.quantity",
-		"COALESCE(oi.size, '') as size", "oi.price::float8", "oi.created_at as item_created_at",
-		"COALESCE(p.title, '') as product_title",
-	).From("orders o").
-		LeftJoin("order_items oi ON o.id = oi.order_id").
-		LeftJoin("products p ON oi.product_id = p.id").
-		Where(sq.Eq{"o.user_id": userID}).
-		OrderBy("o.created_at DESC", "oi.id").
-		Limit(uint64(pagination.GetLimit())).
-		Offset(uint64(pagination.GetOffset())).
-		ToSql()
+// attachItems loads the items of all orders with a single
+// WHERE order_id = ANY($1) query.
+func (r *OrderRepository) attachItems(ctx context.Context, orders []*models.OrderWithItems) error {
+	if len(orders) == 0 {
+		return nil
+	}
 
-	if err != nil {
-		logger.GetLogger().WithField("err", err).Error("failed to build user orders query")
-		return nil, 0, fmt.Errorf("failed to build user orders query: %w", err)
+	byID := make(map[int]*models.OrderWithItems, len(orders))
+	ids := make([]int, 0, len(orders))
+	for _, order := range orders {
+		order.Items = []models.OrderItem{}
+		byID[order.ID] = order
+		ids = append(ids, order.ID)
 	}
 
-	rows, err := r.db.Query(ctx, query, args...)
+	query, args, err := psql.Select(orderItemColumns...).
+		From("order_items").
+		Where("order_id = ANY(?)", ids).
+		OrderBy("order_id", "id").
+		ToSql()
 	if err != nil {
-		logger.GetLogger().WithField("err", err).Error("failed to get user orders")
-		return nil, 0, fmt.Errorf("failed to get user orders: %w", err)
-	}
-	defer rows.Close()
-
-	ordersMap := make(map[int]*models.OrderWithItems)
-	var orderIDs []int
-
-	for rows.Next() {
-		var order models.Order
-		var itemID, productID, quantity *int
-		var size, productTitle *string
-		var itemPrice *float64
-		var itemCreatedAt *time.Time
-
-		if err := rows.Scan(
-			&order.ID,
-			&order.UserID,
-			&order.TotalAmount,
-			&order.Status,
-			&order.PaymentMethod,
-			&order.PaymentStatus,
-			&order.DeliveryAddr,
-			&order.CreatedAt,
-			&order.UpdatedAt,
-			&itemID,
-			&productID,
-			&quantity,
-			&size,
-			&itemPrice,
-			&itemCreatedAt,
-			&productTitle,
-		); err != nil {
-			logger.GetLogger().WithField("err", err).Error("failed to scan order row")
-			return nil, 0, fmt.Errorf("failed to scan order row: %w", err)
-		}
+		logger.GetLogger().WithField("err", err).Error("failed to build order items select query")
+		return fmt.Errorf("failed to build order items select query: %w", err)
+	}
 
-		if _, exists := ordersMap[order.ID]; !exists {
-			ordersMap[order.ID] = &models.OrderWithItems{
-				Order: order,
-				Items: []models.OrderItem{},
-			}
-			orderIDs = append(orderIDs, order.ID)
-		}
+	var items []models.OrderItem
+	if err := pgxscan.Select(ctx, r.db, &items, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get order items")
+		return fmt.Errorf("failed to get order items: %w", err)
+	}
 
-		if itemID != nil {
-			item := models.OrderItem{
-				ID:        *itemID,
-				OrderID:   order.ID,
-				ProductID: *productID,
-				Quantity:  *quantity,
-				Price:     *itemPrice,
-				CreatedAt: *itemCreatedAt,
-			}
-			if size != nil {
-				item.Size = *size
-			}
-			ordersMap[order.ID].Items = append(ordersMap[order.ID].Items, item)
+	for _, item := range items {
+		if order, ok := byID[item.OrderID]; ok {
+			order.Items = append(order.Items, item)
 		}
 	}
+	return nil
+}
 
-	result := make([]*models.OrderWithItems, 0, len(orderIDs))
-	for _, id := range orderIDs {
-		result = append(result, ordersMap[id])
+// GetUserOrders returns a page of the user's orders. A non-empty query
+// keeps orders whose number or a product title contains it.
+func (r *OrderRepository) GetUserOrders(ctx context.Context, userID int, query string, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error) {
+	where := sq.And{sq.Eq{"user_id": userID}}
+	if query != "" {
+		pattern := likePattern(query)
+		where = append(where, sq.Or{sq.ILike{"order_number": pattern}, orderHasItem("p.title ILIKE ?", pattern)})
 	}
 
-	return result, totalItems, nil
-}
+	totalItems, err := countRows(ctx, r.db, "orders", where, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
 
-func (r *OrderRepository) GetAll(ctx context.Context, pagination *models.PaginationParams, status string) ([]*models.OrderWithItems, int64, error) {
-	countBuilder := psql.Select("COUNT(*)").From("orders")
-	if status != "" {
-		countBuilder = countBuilder.Where(sq.Eq{"status": status})
+	if totalItems == 0 {
+		return []*models.OrderWithItems{}, 0, nil
 	}
 
-	countQuery, countArgs, err := countBuilder.ToSql()
+	orders, err := r.listOrders(ctx, where, pagination)
 	if err != nil {
-		logger.GetLogger().WithField("err", err).Error("failed to build count query")
-		return nil, 0, fmt.Errorf("failed to build count query: %w", err)
+		return nil, 0, err
 	}
+	return orders, totalItems, nil
+}
 
-	var totalItems int64
-	if err := r.db.QueryRow(ctx, countQuery, countArgs...).Scan(&totalItems); err != nil {
-		logger.GetLogger().WithField("err", err).Error("failed to count orders")
-		return nil, 0, fmt.Errorf("failed to count orders: %w", err)
+// GetAll returns a page of all orders matching filter.
+func (r *OrderRepository) GetAll(ctx context.Context, pagination *models.PaginationParams, filter *models.OrderFilter) ([]*models.OrderWithItems, int64, error) {
+	where := orderFilterWhere(filter)
+
+	totalItems, err := countRows(ctx, r.db, "orders", where, pagination)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	if totalItems == 0 {
+	// A planner estimate can lag behind new rows, so only trust an exact zero.
+	if totalItems == 0 && !pagination.EstimateCount() {
 		return []*models.OrderWithItems{}, 0, nil
 	}
 
-	queryBuilder := psql.Select(
-		"o.id", "o.user_id", "o.total_amount::float8",
-		"COALESCE(o.status, 'pending') as status",
-		"COALESCE(o.payment_method, '') as payment_method",
-		"COALESCE(o.payment_status, 'pending') as payment_status",
-		"o.delivery_address", "o.created_at", "o.updated_at",
-		"oi.id as item_id", "oi.product_id", "oi.quantity",
-		"COALESCE(oi.size, '') as size", "oi.price::float8", "oi.created_at as item_created_at",
-		"COALESCE(p.title, '') as product_title",
-	).From("orders o").
-		LeftJoin("order_items oi ON o.id = oi.order_id").
-		LeftJoin("products p ON oi.product_id = p.id")
+	orders, err := r.listOrders(ctx, where, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+	return orders, totalItems, nil
+}
 
-	if status != "" {
-		queryBuilder = queryBuilder.Where(sq.Eq{"o.status": status})
+func orderFilterWhere(filter *models.OrderFilter) sq.And {
+	where := sq.And{}
+	if filter.Q != "" {
+		pattern := likePattern(filter.Q)
+		where = append(where, sq.Or{
+			sq.ILike{"order_number": pattern},
+			sq.ILike{"buyer_email": pattern},
+			orderHasItem("p.title ILIKE ?", pattern),
+		})
+	}
+	if filter.Status != "" {
+		where = append(where, sq.Eq{"status": filter.Status})
+	}
+	if filter.OrderNumber != "" {
+		where = append(where, sq.ILike{"order_number": likePattern(filter.OrderNumber)})
+	}
+	if filter.Email != "" {
+		where = append(where, sq.ILike{"buyer_email": likePattern(filter.Email)})
+	}
+	if filter.Product != "" {
+		where = append(where, orderHasItem("p.title ILIKE ?", likePattern(filter.Product)))
+	}
+	if filter.SellerID != nil {
+		where = append(where, orderHasItem("p.seller_id = ?", *filter.SellerID))
+	}
+	if filter.MinAmount != nil {
+		where = append(where, sq.GtOrEq{"total_amount": *filter.MinAmount})
+	}
+	if filter.MaxAmount != nil {
+		where = append(where, sq.LtOrEq{"total_amount": *filter.MaxAmount})
+	}
+	if filter.From != nil {
+		where = append(where, sq.GtOrEq{"created_at": *filter.From})
+	}
+	if filter.To != nil {
+		where = append(where, sq.Lt{"created_at": filter.To.AddDate(0, 0, 1)})
 	}
+	return where
+}
 
-	query, args, err := queryBuilder.
-		OrderBy("o.created_at DESC", "oi.id").
-		Limit(uint64(pagination.GetLimit())).
-		Offset(uint64(pagination.GetOffset())).
-		ToSql()
+// orderHasItem matches orders with an item whose product, aliased p,
+// satisfies cond.
+func orderHasItem(cond string, args ...interface{}) sq.Sqlizer {
+	return sq.Expr("EXISTS (SELECT 1 FROM order_items oi JOIN products p ON p.id = oi.product_id "+
+		"WHERE oi.order_id = orders.id AND "+cond+")", args...)
+}
 
-	if err != nil {
-		logger.GetLogger().WithField("err", err).Error("failed to build all orders query")
-		return nil, 0, fmt.Errorf("failed to build all orders query: %w", err)
-	}
+// likePattern matches values containing s, with LIKE wildcards in s
+// taken literally.
+func likePattern(s string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s) + "%"
+}
 
-	rows, err := r.db.Query(ctx, query, args...)
-	if err != nil {
-		logger.GetLogger().WithField("err", err).Error("failed to get all orders")
-		return nil, 0, fmt.Errorf("failed to get all orders: %w", err)
-	}
-	defer rows.Close()
-
-	ordersMap := make(map[int]*models.OrderWithItems)
-	var orderIDs []int
-
-	for rows.Next() {
-		var order models.Order
-		var itemID, productID, quantity *int
-		var size, productTitle *string
-		var itemPrice *float64
-		var itemCreatedAt *time.Time
-
-		if err := rows.Scan(
-			&order.ID,
-			&order.UserID,
-			&order.TotalAmount,
-			&order.Status,
-			&order.PaymentMethod,
-			&order.PaymentStatus,
-			&order.DeliveryAddr,
-			&order.CreatedAt,
-			&order.UpdatedAt,
-			&itemID,
-			&productID,
-			&quantity,
-			&size,
-			&itemPrice,
-			&itemCreatedAt,
-			&productTitle,
-		); err != nil {
-			logger.GetLogger().WithField("err", err).Error("failed to scan order row")
-			return nil, 0, fmt.Errorf("failed to scan order row: %w", err)
+// RotateDeliveryAddresses re-encrypts delivery addresses that are stored in
+// plaintext or sealed with a non-primary key. It returns the number of
+// updated orders.
+func (r *OrderRepository) RotateDeliveryAddresses(ctx context.Context, batchSize int) (int, error) {
+	if r.keyring == nil {
+		return 0, nil
+	}
+	if err := r.readOnly.Check(readonly.TableOrders); err != nil {
+		return 0, err
+	}
+
+	rotated := 0
+	lastID := 0
+	for {
+		query, args, err := psql.Select("id", "delivery_address").
+			From("orders").
+			Where(sq.Gt{"id": lastID}).
+			Where(sq.NotLike{"delivery_address": "enc:v1:" + r.keyring.PrimaryKeyID() + ":%"}).
+			Where(sq.NotEq{"delivery_address": retention.AnonymizedAddress}).
+			OrderBy("id").
+			Limit(uint64(batchSize)).
+			ToSql()
+		if err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to build rotation query")
+			return rotated, fmt.Errorf("failed to build rotation query: %w", err)
 		}
 
-		if _, exists := ordersMap[order.ID]; !exists {
-			ordersMap[order.ID] = &models.OrderWithItems{
-				Order: order,
-				Items: []models.OrderItem{},
-			}
-			orderIDs = append(orderIDs, order.ID)
+		rows, err := r.db.Query(ctx, query, args...)
+		if err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to select orders for rotation")
+			return rotated, fmt.Errorf("failed to select orders for rotation: %w", err)
 		}
 
-		if itemID != nil {
-			item := models.OrderItem{
-				ID:        *itemID,
-				OrderID:   order.ID,
-				ProductID: *productID,
-				Quantity:  *quantity,
-				Price:     *itemPrice,
-				CreatedAt: *itemCreatedAt,
-			}
-			if size != nil {
-				item.Size = *size
+		type pending struct {
+			id   int
+			addr string
+		}
+		var batch []pending
+		for rows.Next() {
+			var p pending
+			if err := rows.Scan(&p.id, &p.addr); err != nil {
+				rows.Close()
+				logger.GetLogger().WithField("err", err).Error("failed to scan order for rotation")
+				return rotated, fmt.Errorf("failed to scan order for rotation: %w", err)
 			}
-			ordersMap[order.ID].Items = append(ordersMap[order.ID].Items, item)
+			batch = append(batch, p)
 		}
-	}
+		rows.Close()
 
-	result := make([]*models.OrderWithItems, 0, len(orderIDs))
-	for _, id := range orderIDs {
-		result = append(result, ordersMap[id])
-	}
+		if len(batch) == 0 {
+			return rotated, nil
+		}
 
-	return result, totalItems, nil
-}
+		for _, p := range batch {
+			lastID = p.id
 
-func (r *OrderRepository) UpdateStatus(ctx context.Context, orderID int, status string) (*models.Order, error) {
-	query, args, err := psql.Update("orders").
-		Set("status", status).
-		Set("updated_at", sq.Expr("NOW()")).
-		Where(sq.Eq{"id": orderID}).
-		Suffix("RETURNING id, user_id, total_amount::float8, COALESCE(status, 'pending') as status, COALESCE(payment_method, '') as payment_method, COALESCE(payment_status, 'pending') as payment_status, delivery_address, created_at, updated_at").
-		ToSql()
-	if err != nil {
-		logger.GetLogger().WithField("err", err).Error("failed to build update status query")
-		return nil, fmt.Errorf("failed to build update status query: %w", err)
-	}
-
-	var order models.Order
-	err = r.db.QueryRow(ctx, query, args...).Scan(
-		&order.ID,
-		&order.UserID,
-		&order.TotalAmount,
-		&order.Status,
-		&order.PaymentMethod,
-		&order.PaymentStatus,
-		&order.DeliveryAddr,
-		&order.CreatedAt,
-		&order.UpdatedAt,
-	)
+			plain, err := r.keyring.Decrypt(p.addr)
+			if err != nil {
+				logger.GetLogger().WithFields(map[string]interface{}{
+					"err":      err,
+					"order_id": p.id,
+				}).Warn("skipping order with undecryptable delivery address")
+				continue
+			}
+			sealed, err := r.keyring.Encrypt(plain)
+			if err != nil {
+				return rotated, fmt.Errorf("failed to encrypt delivery address: %w", err)
+			}
 
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			logger.GetLogger().WithField("order_id", orderID).Error("order not found")
-			return nil, fmt.Errorf("order not found")
+			if _, err := r.db.Exec(ctx, `UPDATE orders SET delivery_address = $1 WHERE id = $2 AND delivery_address = $3`, sealed, p.id, p.addr); err != nil {
+				logger.GetLogger().WithField("err", err).Error("failed to rotate delivery address")
+				return rotated, fmt.Errorf("failed to rotate delivery address: %w", err)
+			}
+			rotated++
 		}
-		logger.GetLogger().WithField("err", err).Error("failed to update order status")
-		return nil, fmt.Errorf("failed to update order status: %w", err)
 	}
-
-	return &order, nil
 }
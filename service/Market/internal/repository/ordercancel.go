@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/readonly"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+)
+
+// cancellationDeadlineQuery returns when the buyer's window to cancel order
+// $1 closes and the seconds left until then. The shortest window among the
+// sellers of the order's items applies; sellers without their own window,
+// and orders without items, use $2 minutes.
+const cancellationDeadlineQuery = `
+	SELECT o.created_at + w.minutes * INTERVAL '1 minute',
+		EXTRACT(EPOCH FROM o.created_at + w.minutes * INTERVAL '1 minute' - NOW())::float8
+	FROM orders o,
+	LATERAL (
+		SELECT COALESCE(MIN(COALESCE(s.cancellation_window_minutes, $2)), $2) AS minutes
+		FROM order_items oi
+		JOIN products p ON p.id = oi.product_id
+		JOIN sellers s ON s.id = p.seller_id
+		WHERE oi.order_id = o.id
+	) w
+	WHERE o.id = $1`
+
+// restockCancelledItemsQuery cancels the open items of order $1 and puts
+// their quantities back in stock, the variants' and their products'.
+const restockCancelledItemsQuery = `
+	WITH cancelled AS (
+		UPDATE order_items SET fulfillment_status = 'cancelled', fulfillment_updated_at = NOW()
+		WHERE order_id = $1 AND fulfillment_status IN ('pending', 'processing')
+		RETURNING product_id, variant_id, quantity
+	), variants AS (
+		UPDATE product_variants v SET stock = v.stock + c.quantity, updated_at = NOW()
+		FROM (SELECT variant_id, SUM(quantity) AS quantity FROM cancelled WHERE variant_id IS NOT NULL GROUP BY variant_id) c
+		WHERE v.id = c.variant_id
+	)
+	UPDATE products p SET stock = p.stock + c.quantity, updated_at = NOW()
+	FROM (SELECT product_id, SUM(quantity) AS quantity FROM cancelled GROUP BY product_id) c
+	WHERE p.id = c.product_id`
+
+// CancellationDeadline returns when the buyer's window to cancel an order
+// closes and how much of it is left; global is the window of sellers
+// without their own.
+func (r *OrderRepository) CancellationDeadline(ctx context.Context, orderID int, global time.Duration) (time.Time, time.Duration, error) {
+	var deadline time.Time
+	var left float64
+	err := r.db.QueryRow(ctx, cancellationDeadlineQuery, orderID, int(global/time.Minute)).Scan(&deadline, &left)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, 0, apperrors.OrderNotFound(orderID)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get cancellation deadline")
+		return time.Time{}, 0, fmt.Errorf("failed to get cancellation deadline: %w", err)
+	}
+	return deadline, time.Duration(left * float64(time.Second)), nil
+}
+
+// CancelByBuyer cancels userID's order while its cancellation window is
+// open: open items are cancelled and restocked and the sellers notified.
+func (r *OrderRepository) CancelByBuyer(ctx context.Context, orderID, userID int, global time.Duration) (*models.OrderWithItems, error) {
+	// Cancelling an order also restores product stock.
+	if err := r.readOnly.Check(readonly.TableOrders, readonly.TableProducts); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	order := &models.OrderWithItems{}
+	err = tx.QueryRow(ctx,
+		`SELECT user_id, order_number, COALESCE(status, 'pending'), COALESCE(payment_status, 'pending')
+		FROM orders WHERE id = $1 FOR UPDATE`, orderID).
+		Scan(&order.UserID, &order.OrderNumber, &order.Status, &order.PaymentStatus)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.OrderNotFound(orderID)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to lock order")
+		return nil, fmt.Errorf("failed to lock order: %w", err)
+	}
+	if order.UserID != userID {
+		return nil, apperrors.OrderNotFound(orderID)
+	}
+
+	// Locking the items keeps sellers from shipping them meanwhile.
+	itemsQuery, itemsArgs, err := psql.Select(orderItemColumns...).
+		From("order_items").
+		Where("order_id = ?", orderID).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build order items select query")
+		return nil, fmt.Errorf("failed to build order items select query: %w", err)
+	}
+	if err := pgxscan.Select(ctx, tx, &order.Items, itemsQuery, itemsArgs...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to lock order items")
+		return nil, fmt.Errorf("failed to lock order items: %w", err)
+	}
+	if reason := order.CancelBlocker(); reason != "" {
+		return nil, apperrors.Conflict(reason)
+	}
+
+	var deadline time.Time
+	var left float64
+	if err := tx.QueryRow(ctx, cancellationDeadlineQuery, orderID, int(global/time.Minute)).Scan(&deadline, &left); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get cancellation deadline")
+		return nil, fmt.Errorf("failed to get cancellation deadline: %w", err)
+	}
+	if left <= 0 {
+		return nil, apperrors.Conflict("the cancellation window for this order has closed")
+	}
+
+	if _, err := tx.Exec(ctx, restockCancelledItemsQuery, orderID); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to cancel order items")
+		return nil, fmt.Errorf("failed to cancel order items: %w", err)
+	}
+
+	if err := setOrderStatus(ctx, tx, r.events, orderID, models.OrderStatusCancelled, models.StatusActorBuyer, &userID); err != nil {
+		return nil, err
+	}
+
+	var sellerUserIDs []int
+	err = pgxscan.Select(ctx, tx, &sellerUserIDs,
+		`SELECT DISTINCT s.user_id FROM order_items oi
+		JOIN products p ON p.id = oi.product_id
+		JOIN sellers s ON s.id = p.seller_id
+		WHERE oi.order_id = $1`, orderID)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get order sellers")
+		return nil, fmt.Errorf("failed to get order sellers: %w", err)
+	}
+	message := fmt.Sprintf("Order %s was cancelled by the buyer.", order.OrderNumber)
+	for _, sellerUserID := range sellerUserIDs {
+		if err := notify(ctx, tx, sellerUserID, "order_cancelled", message); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByID(ctx, orderID)
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/readonly"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+)
+
+// orderStatusChangeColumns select an order_status_history row into
+// models.OrderStatusChange.
+var orderStatusChangeColumns = []string{
+	"id", "order_id", "from_status", "to_status", "actor", "changed_by", "created_at",
+}
+
+// setOrderStatus moves an order to status and records the change in its
+// status history, and in events for the statuses published as events.
+// Setting the status an order already has records nothing.
+func setOrderStatus(ctx context.Context, tx pgx.Tx, events *EventOutbox, orderID int, status, actor string, changedBy *int) error {
+	var from, orderNumber string
+	var userID int
+	err := tx.QueryRow(ctx, `UPDATE orders o SET status = $2, updated_at = NOW()
+		FROM (SELECT id, COALESCE(status, 'pending') AS status FROM orders WHERE id = $1 FOR UPDATE) old
+		WHERE o.id = old.id
+		RETURNING old.status, o.order_number, o.user_id`, orderID, status).Scan(&from, &orderNumber, &userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperrors.OrderNotFound(orderID)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to update order status")
+		return fmt.Errorf("failed to update order status: %w", err)
+	}
+	if from == status {
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO order_status_history (order_id, from_status, to_status, actor, changed_by)
+		VALUES ($1, $2, $3, $4, $5)`, orderID, from, status, actor, changedBy)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to record order status change")
+		return fmt.Errorf("failed to record order status change: %w", err)
+	}
+	return events.orderStatusChanged(ctx, tx, models.OrderStatusEvent{
+		OrderID:     orderID,
+		OrderNumber: orderNumber,
+		UserID:      userID,
+		FromStatus:  from,
+		Status:      status,
+		Actor:       actor,
+	})
+}
+
+// UpdateStatus moves an order from status from to status to on behalf of
+// an admin. It fails with a conflict if the order has left from meanwhile;
+// whether the transition is allowed is up to the caller.
+func (r *OrderRepository) UpdateStatus(ctx context.Context, orderID int, from, to string, adminID int) (*models.Order, error) {
+	if err := r.readOnly.Check(readonly.TableOrders); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var current string
+	err = tx.QueryRow(ctx, `SELECT COALESCE(status, 'pending') FROM orders WHERE id = $1 FOR UPDATE`, orderID).Scan(&current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.OrderNotFound(orderID)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to lock order")
+		return nil, fmt.Errorf("failed to lock order: %w", err)
+	}
+	if current != from {
+		return nil, apperrors.Conflict(fmt.Sprintf("order status changed to %s meanwhile", current))
+	}
+
+	if err := setOrderStatus(ctx, tx, r.events, orderID, to, models.StatusActorAdmin, &adminID); err != nil {
+		return nil, err
+	}
+
+	query, args, err := psql.Select(orderColumns...).
+		From("orders").
+		Where("id = ?", orderID).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build order query")
+		return nil, fmt.Errorf("failed to build order query: %w", err)
+	}
+
+	var order models.Order
+	if err := pgxscan.Get(ctx, tx, &order, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get order")
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if err := r.openAddress(&order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetStatusHistory returns an order's status changes, oldest first.
+func (r *OrderRepository) GetStatusHistory(ctx context.Context, orderID int) ([]*models.OrderStatusChange, error) {
+	var exists bool
+	if err := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM orders WHERE id = $1)`, orderID).Scan(&exists); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to check order")
+		return nil, fmt.Errorf("failed to check order: %w", err)
+	}
+	if !exists {
+		return nil, apperrors.OrderNotFound(orderID)
+	}
+
+	query, args, err := psql.Select(orderStatusChangeColumns...).
+		From("order_status_history").
+		Where("order_id = ?", orderID).
+		OrderBy("created_at", "id").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build order status history query")
+		return nil, fmt.Errorf("failed to build order status history query: %w", err)
+	}
+
+	changes := []*models.OrderStatusChange{}
+	if err := pgxscan.Select(ctx, r.db, &changes, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get order status history")
+		return nil, fmt.Errorf("failed to get order status history: %w", err)
+	}
+	return changes, nil
+}
@@ -0,0 +1,456 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/money"
+	"github.com/Zifeldev/marketback/service/Market/internal/readonly"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var paymentColumns = []string{
+	"id", "order_id", "method", "gift_card_id", "provider", "provider_ref", "amount::float8 AS amount",
+	"refunded_amount::float8 AS refunded_amount", "status", "action_type", "action_url", "created_at", "updated_at",
+}
+
+// PaymentRepository stores payments. Every write also moves
+// orders.payment_status, and the order status when the order is paid or
+// refunded, in the same transaction. Gift card and store credit payments
+// take their amount off the balance when they are made and give it back
+// when they are voided or refunded.
+type PaymentRepository struct {
+	db       *pgxpool.Pool
+	readOnly *readonly.Guard
+	events   *EventOutbox
+}
+
+// NewPaymentRepository creates a payment repository. With a nil outbox no
+// OrderPaid events are recorded.
+func NewPaymentRepository(db *pgxpool.Pool, readOnly *readonly.Guard, events *EventOutbox) *PaymentRepository {
+	return &PaymentRepository{db: db, readOnly: readOnly, events: events}
+}
+
+func (r *PaymentRepository) Create(ctx context.Context, payment *models.Payment) (*models.Payment, error) {
+	if err := r.readOnly.Check(readonly.TableOrders); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	created, err := insertPayment(ctx, tx, payment)
+	if err != nil {
+		return nil, err
+	}
+	if err := settleHolds(ctx, tx, created); err != nil {
+		return nil, err
+	}
+	if err := setOrderPaymentStatus(ctx, tx, r.events, created.OrderID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return created, nil
+}
+
+// Redeem spends up to due of the gift card and, when asked, the user's
+// store credit on the order. The payments are paid right away when they
+// cover due and held pending for the card charge of the rest otherwise.
+// It returns the payments made and what is left to charge.
+func (r *PaymentRepository) Redeem(ctx context.Context, orderID, userID int, due float64, req *models.PayOrderRequest) ([]*models.Payment, float64, error) {
+	if req.GiftCardCode == "" && !req.UseStoreCredit {
+		return nil, due, nil
+	}
+	if err := r.readOnly.Check(readonly.TableOrders); err != nil {
+		return nil, 0, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	left := money.FromFloat(due)
+	var spent []*models.Payment
+
+	if req.GiftCardCode != "" {
+		var cardID int
+		var balance float64
+		err := tx.QueryRow(ctx, `SELECT id, balance::float8 FROM gift_cards
+			WHERE code = $1 AND (expires_at IS NULL OR expires_at > NOW()) FOR UPDATE`,
+			strings.ToUpper(strings.TrimSpace(req.GiftCardCode))).Scan(&cardID, &balance)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, 0, apperrors.ValidationError("gift_card_code", "unknown or expired gift card")
+			}
+			logger.GetLogger().WithField("err", err).Error("failed to get gift card")
+			return nil, 0, fmt.Errorf("failed to get gift card: %w", err)
+		}
+
+		amount := min(left, money.FromFloat(balance))
+		if amount == 0 {
+			return nil, 0, apperrors.ValidationError("gift_card_code", "gift card has no balance left")
+		}
+		if _, err := tx.Exec(ctx, `UPDATE gift_cards SET balance = balance - $2, updated_at = NOW() WHERE id = $1`,
+			cardID, amount.Float()); err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to spend gift card")
+			return nil, 0, fmt.Errorf("failed to spend gift card: %w", err)
+		}
+		spent = append(spent, &models.Payment{Method: models.PaymentMethodGiftCard, GiftCardID: &cardID, Amount: amount.Float()})
+		left -= amount
+	}
+
+	if req.UseStoreCredit && left > 0 {
+		var balance float64
+		err := tx.QueryRow(ctx, `SELECT balance::float8 FROM store_credits WHERE user_id = $1 FOR UPDATE`, userID).Scan(&balance)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			logger.GetLogger().WithField("err", err).Error("failed to get store credit")
+			return nil, 0, fmt.Errorf("failed to get store credit: %w", err)
+		}
+
+		if amount := min(left, money.FromFloat(balance)); amount > 0 {
+			if _, err := tx.Exec(ctx, `UPDATE store_credits SET balance = balance - $2, updated_at = NOW() WHERE user_id = $1`,
+				userID, amount.Float()); err != nil {
+				logger.GetLogger().WithField("err", err).Error("failed to spend store credit")
+				return nil, 0, fmt.Errorf("failed to spend store credit: %w", err)
+			}
+			spent = append(spent, &models.Payment{Method: models.PaymentMethodStoreCredit, Amount: amount.Float()})
+			left -= amount
+		}
+	}
+
+	if len(spent) == 0 {
+		return nil, due, nil
+	}
+
+	status := models.PaymentStatusPending
+	if left == 0 {
+		status = models.PaymentStatusPaid
+	}
+	for i, p := range spent {
+		p.OrderID, p.Provider, p.Status = orderID, p.Method, status
+		if spent[i], err = insertPayment(ctx, tx, p); err != nil {
+			return nil, 0, err
+		}
+	}
+	if err := setOrderPaymentStatus(ctx, tx, r.events, orderID); err != nil {
+		return nil, 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return spent, left.Float(), nil
+}
+
+// insertPayment stores a payment, by card unless it says otherwise.
+// Payments without a provider reference get a random one.
+func insertPayment(ctx context.Context, tx pgx.Tx, payment *models.Payment) (*models.Payment, error) {
+	method := payment.Method
+	if method == "" {
+		method = models.PaymentMethodCard
+	}
+	var ref interface{} = payment.ProviderRef
+	if payment.ProviderRef == "" {
+		ref = sq.Expr("? || '_' || replace(gen_random_uuid()::text, '-', '')", method)
+	}
+
+	query, args, err := psql.Insert("payments").
+		Columns("order_id", "method", "gift_card_id", "provider", "provider_ref", "amount", "status", "action_type", "action_url").
+		Values(payment.OrderID, method, payment.GiftCardID, payment.Provider, ref, payment.Amount, payment.Status,
+			payment.ActionType, payment.ActionURL).
+		Suffix(returning(paymentColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build create payment query")
+		return nil, fmt.Errorf("failed to build create payment query: %w", err)
+	}
+
+	var created models.Payment
+	if err := pgxscan.Get(ctx, tx, &created, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to create payment")
+		return nil, fmt.Errorf("failed to create payment: %w", err)
+	}
+	return &created, nil
+}
+
+// Lock claims the order for one payment attempt until Unlock or, should
+// the attempt never finish, until ttl has passed. It returns a conflict
+// while another attempt holds the order; the row lock the update takes
+// makes concurrent attempts wait and then see the claim.
+func (r *PaymentRepository) Lock(ctx context.Context, orderID int, ttl time.Duration) error {
+	if err := r.readOnly.Check(readonly.TableOrders); err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, `UPDATE orders SET payment_locked_until = NOW() + $2 * INTERVAL '1 second'
+		WHERE id = $1 AND (payment_locked_until IS NULL OR payment_locked_until < NOW())`, orderID, ttl.Seconds())
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to lock order for payment")
+		return fmt.Errorf("failed to lock order for payment: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperrors.Conflict("a payment for this order is already in progress")
+	}
+	return nil
+}
+
+// Unlock ends the order's payment attempt.
+func (r *PaymentRepository) Unlock(ctx context.Context, orderID int) error {
+	if err := r.readOnly.Check(readonly.TableOrders); err != nil {
+		return err
+	}
+
+	if _, err := r.db.Exec(ctx, `UPDATE orders SET payment_locked_until = NULL WHERE id = $1`, orderID); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to unlock order for payment")
+		return fmt.Errorf("failed to unlock order for payment: %w", err)
+	}
+	return nil
+}
+
+// GetByRef returns the payment with the provider's reference, or nil.
+func (r *PaymentRepository) GetByRef(ctx context.Context, providerRef string) (*models.Payment, error) {
+	query, args, err := psql.Select(paymentColumns...).From("payments").
+		Where(sq.Eq{"provider_ref": providerRef}).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build payment query")
+		return nil, fmt.Errorf("failed to build payment query: %w", err)
+	}
+
+	var payment models.Payment
+	if err := pgxscan.Get(ctx, r.db, &payment, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get payment")
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+
+	return &payment, nil
+}
+
+// ListForOrder returns all payments of the order, oldest first.
+func (r *PaymentRepository) ListForOrder(ctx context.Context, orderID int) ([]*models.Payment, error) {
+	query, args, err := psql.Select(paymentColumns...).From("payments").
+		Where(sq.Eq{"order_id": orderID}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build payments query")
+		return nil, fmt.Errorf("failed to build payments query: %w", err)
+	}
+
+	payments := []*models.Payment{}
+	if err := pgxscan.Select(ctx, r.db, &payments, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get payments")
+		return nil, fmt.Errorf("failed to get payments: %w", err)
+	}
+
+	return payments, nil
+}
+
+// Settle moves a pending payment, or one waiting for the buyer's action, to
+// status and clears the action. It returns nil when the payment is already
+// settled, so repeated provider events are harmless. Settling
+// a card payment settles the order's held balance payments with it; a
+// voided balance payment gives its amount back.
+func (r *PaymentRepository) Settle(ctx context.Context, id int, status string) (*models.Payment, error) {
+	if err := r.readOnly.Check(readonly.TableOrders); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var payment models.Payment
+	err = pgxscan.Get(ctx, tx, &payment,
+		`UPDATE payments SET status = $2, action_type = '', action_url = '', updated_at = NOW()
+		WHERE id = $1 AND status IN ('pending', 'requires_action') `+returning(paymentColumns), id, status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to settle payment")
+		return nil, fmt.Errorf("failed to settle payment: %w", err)
+	}
+	if err := settleHolds(ctx, tx, &payment); err != nil {
+		return nil, err
+	}
+	if payment.Status == models.PaymentStatusVoided {
+		if err := giveBack(ctx, tx, &payment, payment.Amount); err != nil {
+			return nil, err
+		}
+	}
+	if err := setOrderPaymentStatus(ctx, tx, r.events, payment.OrderID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &payment, nil
+}
+
+// AddRefund records a refund of amount against a paid payment, giving it
+// back to the gift card or store credit it was paid with. The database
+// refuses refunds past the paid amount even when two run at once.
+func (r *PaymentRepository) AddRefund(ctx context.Context, id int, amount float64) (*models.Payment, error) {
+	if err := r.readOnly.Check(readonly.TableOrders); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var payment models.Payment
+	err = pgxscan.Get(ctx, tx, &payment,
+		`UPDATE payments SET refunded_amount = refunded_amount + $2,
+			status = CASE WHEN refunded_amount + $2 >= amount THEN 'refunded' ELSE status END,
+			updated_at = NOW()
+		WHERE id = $1 AND status = 'paid' AND refunded_amount + $2 <= amount `+returning(paymentColumns), id, amount)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.Conflict("payment cannot be refunded by this amount")
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to refund payment")
+		return nil, fmt.Errorf("failed to refund payment: %w", err)
+	}
+	if err := giveBack(ctx, tx, &payment, amount); err != nil {
+		return nil, err
+	}
+	if err := setOrderPaymentStatus(ctx, tx, r.events, payment.OrderID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &payment, nil
+}
+
+// settleHolds settles the order's held gift card and store credit payments
+// once its card payment has: they are paid with a paid charge, and voided
+// and given back when the charge failed.
+func settleHolds(ctx context.Context, tx pgx.Tx, card *models.Payment) error {
+	var status string
+	switch {
+	case card.Method != models.PaymentMethodCard:
+		return nil
+	case card.Status == models.PaymentStatusPaid:
+		status = models.PaymentStatusPaid
+	case card.Status == models.PaymentStatusFailed:
+		status = models.PaymentStatusVoided
+	default:
+		return nil
+	}
+
+	var held []*models.Payment
+	err := pgxscan.Select(ctx, tx, &held,
+		`UPDATE payments SET status = $2, updated_at = NOW()
+		WHERE order_id = $1 AND method <> 'card' AND status = 'pending' `+returning(paymentColumns), card.OrderID, status)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to settle held payments")
+		return fmt.Errorf("failed to settle held payments: %w", err)
+	}
+	if status == models.PaymentStatusVoided {
+		for _, p := range held {
+			if err := giveBack(ctx, tx, p, p.Amount); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// giveBack returns amount of a gift card or store credit payment to its
+// balance. Card payments are refunded by the provider instead.
+func giveBack(ctx context.Context, tx pgx.Tx, payment *models.Payment, amount float64) error {
+	var err error
+	switch payment.Method {
+	case models.PaymentMethodGiftCard:
+		_, err = tx.Exec(ctx, `UPDATE gift_cards SET balance = balance + $2, updated_at = NOW() WHERE id = $1`,
+			*payment.GiftCardID, amount)
+	case models.PaymentMethodStoreCredit:
+		_, err = tx.Exec(ctx, `INSERT INTO store_credits (user_id, balance)
+			SELECT user_id, $2 FROM orders WHERE id = $1
+			ON CONFLICT (user_id) DO UPDATE SET balance = store_credits.balance + EXCLUDED.balance, updated_at = NOW()`,
+			payment.OrderID, amount)
+	default:
+		return nil
+	}
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to give back payment")
+		return fmt.Errorf("failed to give back payment: %w", err)
+	}
+	return nil
+}
+
+// paymentOrderStatus is the order status a payment status moves its order
+// to, when the status machine allows it.
+var paymentOrderStatus = map[string]string{
+	models.PaymentStatusPaid:     models.OrderStatusPaid,
+	models.PaymentStatusRefunded: models.OrderStatusRefunded,
+}
+
+// setOrderPaymentStatus records what the order's payments add up to on the
+// order and moves paid orders to "paid" and fully refunded ones to
+// "refunded". Orders the status machine keeps where they are, e.g.
+// cancelled ones, only get the payment status.
+func setOrderPaymentStatus(ctx context.Context, tx pgx.Tx, events *EventOutbox, orderID int) error {
+	var payments []*models.Payment
+	if err := pgxscan.Select(ctx, tx, &payments, `SELECT status FROM payments WHERE order_id = $1`, orderID); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get order payments")
+		return fmt.Errorf("failed to get order payments: %w", err)
+	}
+	status := models.OrderPaymentStatus(payments)
+
+	var current string
+	err := tx.QueryRow(ctx, `UPDATE orders o SET payment_status = $2, updated_at = NOW()
+		FROM (SELECT id, COALESCE(status, 'pending') AS status FROM orders WHERE id = $1 FOR UPDATE) old
+		WHERE o.id = old.id
+		RETURNING old.status`, orderID, status).Scan(&current)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to update order payment status")
+		return fmt.Errorf("failed to update order payment status: %w", err)
+	}
+
+	if to, ok := paymentOrderStatus[status]; ok && models.CanMoveOrder(current, to) {
+		return setOrderStatus(ctx, tx, events, orderID, to, models.StatusActorPayment, nil)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var savedPaymentMethodColumns = []string{
+	"id", "user_id", "provider", "customer_ref", "method_ref", "created_at",
+}
+
+// PaymentMethodRepository stores buyers' customers at the payment provider
+// and the payment methods saved for them.
+type PaymentMethodRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPaymentMethodRepository(db *pgxpool.Pool) *PaymentMethodRepository {
+	return &PaymentMethodRepository{db: db}
+}
+
+// GetCustomer returns the user's customer at the provider, or "" when they
+// have none yet.
+func (r *PaymentMethodRepository) GetCustomer(ctx context.Context, userID int, provider string) (string, error) {
+	var customer string
+	err := r.db.QueryRow(ctx, `SELECT customer_ref FROM payment_customers WHERE user_id = $1 AND provider = $2`,
+		userID, provider).Scan(&customer)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		logger.GetLogger().WithField("err", err).Error("failed to get payment customer")
+		return "", fmt.Errorf("failed to get payment customer: %w", err)
+	}
+	return customer, nil
+}
+
+// SaveCustomer records the user's customer at the provider. When another
+// request recorded one first, that one is kept and returned.
+func (r *PaymentMethodRepository) SaveCustomer(ctx context.Context, userID int, provider, customer string) (string, error) {
+	var saved string
+	err := r.db.QueryRow(ctx, `INSERT INTO payment_customers (user_id, provider, customer_ref) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, provider) DO UPDATE SET customer_ref = payment_customers.customer_ref
+		RETURNING customer_ref`, userID, provider, customer).Scan(&saved)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to save payment customer")
+		return "", fmt.Errorf("failed to save payment customer: %w", err)
+	}
+	return saved, nil
+}
+
+// Save stores a payment method kept by the provider. Saving the same
+// method twice returns the first one.
+func (r *PaymentMethodRepository) Save(ctx context.Context, method *models.SavedPaymentMethod) (*models.SavedPaymentMethod, error) {
+	query, args, err := psql.Insert("saved_payment_methods").
+		Columns("user_id", "provider", "customer_ref", "method_ref").
+		Values(method.UserID, method.Provider, method.CustomerRef, method.MethodRef).
+		Suffix("ON CONFLICT (provider, method_ref) DO UPDATE SET method_ref = EXCLUDED.method_ref " +
+			returning(savedPaymentMethodColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build save payment method query")
+		return nil, fmt.Errorf("failed to build save payment method query: %w", err)
+	}
+
+	var saved models.SavedPaymentMethod
+	if err := pgxscan.Get(ctx, r.db, &saved, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to save payment method")
+		return nil, fmt.Errorf("failed to save payment method: %w", err)
+	}
+
+	return &saved, nil
+}
+
+// List returns the user's saved payment methods, newest first.
+func (r *PaymentMethodRepository) List(ctx context.Context, userID int) ([]*models.SavedPaymentMethod, error) {
+	query, args, err := psql.Select(savedPaymentMethodColumns...).From("saved_payment_methods").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("id DESC").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build payment methods query")
+		return nil, fmt.Errorf("failed to build payment methods query: %w", err)
+	}
+
+	methods := []*models.SavedPaymentMethod{}
+	if err := pgxscan.Select(ctx, r.db, &methods, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get payment methods")
+		return nil, fmt.Errorf("failed to get payment methods: %w", err)
+	}
+
+	return methods, nil
+}
+
+// Get returns one of the user's saved payment methods.
+func (r *PaymentMethodRepository) Get(ctx context.Context, id, userID int) (*models.SavedPaymentMethod, error) {
+	query, args, err := psql.Select(savedPaymentMethodColumns...).From("saved_payment_methods").
+		Where(sq.Eq{"id": id, "user_id": userID}).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build payment method query")
+		return nil, fmt.Errorf("failed to build payment method query: %w", err)
+	}
+
+	var method models.SavedPaymentMethod
+	if err := pgxscan.Get(ctx, r.db, &method, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.NotFound(fmt.Sprintf("payment method with id %d not found", id))
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get payment method")
+		return nil, fmt.Errorf("failed to get payment method: %w", err)
+	}
+
+	return &method, nil
+}
+
+// Delete forgets one of the user's saved payment methods.
+func (r *PaymentMethodRepository) Delete(ctx context.Context, id, userID int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM saved_payment_methods WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to delete payment method")
+		return fmt.Errorf("failed to delete payment method: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperrors.NotFound(fmt.Sprintf("payment method with id %d not found", id))
+	}
+	return nil
+}
@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/pkg/fieldcrypt"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var payoutAccountColumns = []string{"seller_id", "method", "holder_name", "account_hint", "created_at", "updated_at"}
+
+var payoutColumns = []string{
+	"id", "seller_id", "amount::float8 AS amount", "method", "provider", "provider_ref", "status", "attempts",
+	"last_error", "next_attempt_at", "created_at", "updated_at",
+}
+
+// itemDeliveredAt is when the order item of oi in order o was delivered,
+// by its own fulfillment or the whole order's; NULL until it is.
+const itemDeliveredAt = `CASE
+		WHEN oi.fulfillment_status = 'delivered' THEN COALESCE(oi.fulfillment_updated_at, o.updated_at)
+		WHEN o.status = 'delivered' THEN COALESCE(
+			(SELECT MAX(h.created_at) FROM order_status_history h WHERE h.order_id = o.id AND h.to_status = 'delivered'),
+			o.updated_at)
+	END`
+
+// unsettledSale holds back the order item of oi in order o, of product p
+// in category c sold by seller s, from its seller's payout: it is not
+// delivered yet, or less than its settlement delay ago, or a chargeback or
+// a ticket the buyer opened about the order is still open. The delays are
+// $4 to $7, in seconds: low, standard and high category risk, and at most
+// the trusted seller delay.
+const unsettledSale = `(` + itemDeliveredAt + `) IS NULL
+	OR (` + itemDeliveredAt + `) + CASE
+		WHEN s.trusted THEN LEAST($7::float8, CASE c.risk_level WHEN 'low' THEN $4::float8 WHEN 'high' THEN $6::float8 ELSE $5::float8 END)
+		ELSE CASE c.risk_level WHEN 'low' THEN $4::float8 WHEN 'high' THEN $6::float8 ELSE $5::float8 END
+	END * INTERVAL '1 second' > NOW()
+	OR EXISTS (SELECT 1 FROM chargebacks cb WHERE cb.order_id = o.id AND cb.status = 'open')
+	OR EXISTS (SELECT 1 FROM support_tickets t WHERE t.order_id = o.id AND t.user_id = o.user_id AND t.status IN ('open', 'pending'))`
+
+// batchPayoutsQuery pays every seller with a payout account the statements
+// of months before $1 that no payout covers yet and whose sales have all
+// settled, when together they come to at least $2, in one payout per
+// seller sent through provider $3. Cancelled orders and items and refunded
+// orders have nothing to settle.
+const batchPayoutsQuery = `WITH payable AS (
+		SELECT st.id, st.seller_id, st.payout
+		FROM seller_statements st
+		JOIN seller_payout_accounts a ON a.seller_id = st.seller_id
+		JOIN sellers s ON s.id = st.seller_id
+		WHERE st.payout_id IS NULL AND st.period < $1
+		AND NOT EXISTS (
+			SELECT 1
+			FROM order_items oi
+			JOIN orders o ON o.id = oi.order_id
+			JOIN products p ON p.id = oi.product_id
+			LEFT JOIN categories c ON c.id = p.category_id
+			WHERE p.seller_id = st.seller_id
+			AND o.created_at >= st.period AND o.created_at < st.period + INTERVAL '1 month'
+			AND COALESCE(o.status, 'pending') <> 'cancelled' AND oi.fulfillment_status <> 'cancelled'
+			AND COALESCE(o.payment_status, '') <> 'refunded'
+			AND (` + unsettledSale + `)
+		)
+	), due AS (
+		SELECT seller_id, SUM(payout) AS amount
+		FROM payable
+		GROUP BY seller_id
+		HAVING SUM(payout) >= $2 AND SUM(payout) > 0
+	), created AS (
+		INSERT INTO payouts (seller_id, amount, method, provider)
+		SELECT due.seller_id, due.amount, a.method, $3
+		FROM due JOIN seller_payout_accounts a ON a.seller_id = due.seller_id
+		RETURNING id, seller_id
+	), linked AS (
+		UPDATE seller_statements st SET payout_id = created.id
+		FROM created
+		JOIN payable ON payable.seller_id = created.seller_id
+		WHERE st.id = payable.id
+		RETURNING st.id
+	)
+	SELECT COUNT(*) FROM created`
+
+// PayoutRepository stores sellers' payout accounts and the payouts sent to
+// them. Accounts are sealed with the keyring; a nil keyring stores them as
+// plaintext.
+type PayoutRepository struct {
+	db      *pgxpool.Pool
+	keyring *fieldcrypt.Keyring
+}
+
+func NewPayoutRepository(db *pgxpool.Pool, keyring *fieldcrypt.Keyring) *PayoutRepository {
+	return &PayoutRepository{db: db, keyring: keyring}
+}
+
+// SetAccount registers where the payouts of the seller owned by the user
+// are sent, replacing the account on file.
+func (r *PayoutRepository) SetAccount(ctx context.Context, userID int, req *models.SetPayoutAccountRequest) (*models.PayoutAccount, error) {
+	account, err := r.keyring.Encrypt(req.Account)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to encrypt payout account")
+		return nil, fmt.Errorf("failed to encrypt payout account: %w", err)
+	}
+	hint := req.Account
+	if len(hint) > 4 {
+		hint = hint[len(hint)-4:]
+	}
+
+	var saved models.PayoutAccount
+	err = pgxscan.Get(ctx, r.db, &saved, `INSERT INTO seller_payout_accounts (seller_id, method, holder_name, account, account_hint)
+		SELECT id, $2, $3, $4, $5 FROM sellers WHERE user_id = $1
+		ON CONFLICT (seller_id) DO UPDATE SET method = EXCLUDED.method, holder_name = EXCLUDED.holder_name,
+			account = EXCLUDED.account, account_hint = EXCLUDED.account_hint, updated_at = NOW()
+		`+returning(payoutAccountColumns), userID, req.Method, req.HolderName, account, hint)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.NotFound("seller profile not found")
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to save payout account")
+		return nil, fmt.Errorf("failed to save payout account: %w", err)
+	}
+
+	return &saved, nil
+}
+
+// GetAccount returns the payout account of the seller owned by the user.
+func (r *PayoutRepository) GetAccount(ctx context.Context, userID int) (*models.PayoutAccount, error) {
+	query, args, err := psql.Select(payoutAccountColumns...).From("seller_payout_accounts").
+		Where("seller_id = (SELECT id FROM sellers WHERE user_id = ?)", userID).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build payout account query")
+		return nil, fmt.Errorf("failed to build payout account query: %w", err)
+	}
+
+	var account models.PayoutAccount
+	if err := pgxscan.Get(ctx, r.db, &account, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.NotFound("no payout account on file")
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get payout account")
+		return nil, fmt.Errorf("failed to get payout account: %w", err)
+	}
+
+	return &account, nil
+}
+
+// ListForSeller returns the payouts of the seller owned by the user,
+// newest first.
+func (r *PayoutRepository) ListForSeller(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.Payout, int64, error) {
+	owned := sq.Expr("seller_id = (SELECT id FROM sellers WHERE user_id = ?)", userID)
+
+	countQuery, countArgs, err := psql.Select("COUNT(*)").From("payouts").Where(owned).ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build payouts count query")
+		return nil, 0, fmt.Errorf("failed to build payouts count query: %w", err)
+	}
+	var totalItems int64
+	if err := r.db.QueryRow(ctx, countQuery, countArgs...).Scan(&totalItems); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to count payouts")
+		return nil, 0, fmt.Errorf("failed to count payouts: %w", err)
+	}
+
+	payouts := []*models.Payout{}
+	if totalItems == 0 {
+		return payouts, 0, nil
+	}
+
+	query, args, err := psql.Select(payoutColumns...).From("payouts").
+		Where(owned).
+		OrderBy("id DESC").
+		Limit(uint64(pagination.GetLimit())).
+		Offset(uint64(pagination.GetOffset())).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build payouts query")
+		return nil, 0, fmt.Errorf("failed to build payouts query: %w", err)
+	}
+	if err := pgxscan.Select(ctx, r.db, &payouts, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get payouts")
+		return nil, 0, fmt.Errorf("failed to get payouts: %w", err)
+	}
+
+	return payouts, totalItems, nil
+}
+
+// Batch creates the payouts of the statements of months before before that
+// are not paid yet and whose sales have settled after delays, one per
+// seller whose payable statements come to at least minimum, and returns how
+// many it created.
+func (r *PayoutRepository) Batch(ctx context.Context, provider string, before time.Time, minimum float64, delays models.SettlementDelays) (int64, error) {
+	var created int64
+	err := r.db.QueryRow(ctx, batchPayoutsQuery, before, minimum, provider,
+		delays.Low.Seconds(), delays.Standard.Seconds(), delays.High.Seconds(), delays.Trusted.Seconds()).Scan(&created)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to batch payouts")
+		return 0, fmt.Errorf("failed to batch payouts: %w", err)
+	}
+	return created, nil
+}
+
+// Due returns up to limit pending payouts whose next attempt is due by now,
+// oldest first, with the account each is sent to. A payout goes to the
+// account on file when it is sent, by that account's method.
+func (r *PayoutRepository) Due(ctx context.Context, now time.Time, limit int) ([]*models.DuePayout, error) {
+	columns := make([]string, 0, len(payoutColumns)+2)
+	for _, c := range payoutColumns {
+		if c == "method" {
+			columns = append(columns, "a.method")
+			continue
+		}
+		columns = append(columns, "p."+c)
+	}
+	columns = append(columns, "a.account", "a.holder_name")
+
+	query, args, err := psql.Select(columns...).From("payouts p").
+		Join("seller_payout_accounts a ON a.seller_id = p.seller_id").
+		Where(sq.Eq{"p.status": models.PayoutStatusPending}).
+		Where(sq.LtOrEq{"p.next_attempt_at": now}).
+		OrderBy("p.id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build due payouts query")
+		return nil, fmt.Errorf("failed to build due payouts query: %w", err)
+	}
+
+	due := []*models.DuePayout{}
+	if err := pgxscan.Select(ctx, r.db, &due, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get due payouts")
+		return nil, fmt.Errorf("failed to get due payouts: %w", err)
+	}
+	for _, p := range due {
+		account, err := r.keyring.Decrypt(p.Account)
+		if err != nil {
+			logger.GetLogger().WithField("err", err).Errorf("failed to decrypt payout account of seller %d", p.SellerID)
+			return nil, fmt.Errorf("failed to decrypt payout account of seller %d: %w", p.SellerID, err)
+		}
+		p.Account = account
+	}
+	return due, nil
+}
+
+// MarkPaid records that the provider sent a pending payout as ref.
+func (r *PayoutRepository) MarkPaid(ctx context.Context, id int, ref string) error {
+	_, err := r.db.Exec(ctx, `UPDATE payouts SET status = 'paid', provider_ref = $2, attempts = attempts + 1,
+		last_error = '', next_attempt_at = NULL, updated_at = NOW() WHERE id = $1 AND status = 'pending'`, id, ref)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to mark payout paid")
+		return fmt.Errorf("failed to mark payout paid: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a refused attempt at a pending payout. With a retry
+// time the payout is tried again then; without one it fails, lets go of its
+// statements so a later payout covers them, and the seller is told to check
+// their payout account.
+func (r *PayoutRepository) MarkFailed(ctx context.Context, id int, reason string, retryAt *time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	status := models.PayoutStatusPending
+	if retryAt == nil {
+		status = models.PayoutStatusFailed
+	}
+	var sellerID int
+	var amount float64
+	err = tx.QueryRow(ctx, `UPDATE payouts SET status = $2, attempts = attempts + 1, last_error = $3,
+		next_attempt_at = $4, updated_at = NOW() WHERE id = $1 AND status = 'pending'
+		RETURNING seller_id, amount::float8`, id, status, reason, retryAt).Scan(&sellerID, &amount)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to mark payout failed")
+		return fmt.Errorf("failed to mark payout failed: %w", err)
+	}
+
+	if retryAt == nil {
+		if _, err := tx.Exec(ctx, `UPDATE seller_statements SET payout_id = NULL WHERE payout_id = $1`, id); err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to release payout statements")
+			return fmt.Errorf("failed to release payout statements: %w", err)
+		}
+		var sellerUserID int
+		if err := tx.QueryRow(ctx, `SELECT user_id FROM sellers WHERE id = $1`, sellerID).Scan(&sellerUserID); err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to get payout seller")
+			return fmt.Errorf("failed to get payout seller: %w", err)
+		}
+		if err := notify(ctx, tx, sellerUserID, "payout_failed",
+			fmt.Sprintf("Your payout #%d of %.2f could not be sent: %s. Please check your payout account; the amount is included in your next payout.",
+				id, amount, reason)); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -2,66 +2,152 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"strings"
+
 	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
 	"github.com/Zifeldev/marketback/service/Market/internal/logger"
 	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/readonly"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 var psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
+// productColumns select a products row into models.Product. Nullable
+// columns get their defaults here so every query scans the same shape.
+var productColumns = []string{
+	"id", "seller_id", "category_id", "title", "COALESCE(description, '') AS description",
+	"price::float8 AS price", "stock", "sizes", "COALESCE(image_url, '') AS image_url",
+	"COALESCE(status, 'pending') AS status", "created_at", "updated_at",
+	"low_stock_threshold", "weight_grams", "length_mm", "width_mm", "height_mm",
+}
+
+// productDetailColumns select products p joined with sellers s and
+// categories c into models.ProductWithDetails.
+var productDetailColumns = []string{
+	"p.id", "p.seller_id", "p.category_id", "p.title", "COALESCE(p.description, '') AS description",
+	"p.price::float8 AS price", "p.stock", "p.sizes", "COALESCE(p.image_url, '') AS image_url",
+	"COALESCE(p.status, 'pending') AS status", "p.created_at", "p.updated_at",
+	"p.weight_grams", "p.length_mm", "p.width_mm", "p.height_mm",
+	"p.rating::float8 AS rating", "p.review_count",
+	"COALESCE(s.shop_name, '') AS seller_name",
+	"COALESCE(s.rating, 0)::float8 AS seller_rating",
+	"COALESCE(c.name, '') AS category_name",
+}
+
+// listingColumns select a product_listing row into models.ProductWithDetails.
+var listingColumns = []string{
+	"id", "seller_id", "category_id", "title", "description",
+	"price", "stock", "sizes", "image_url", "status",
+	"created_at", "updated_at", "rating", "review_count",
+	"seller_name", "seller_rating", "category_name",
+}
+
+// dimensionColumns are the columns of models.Dimensions, in the order
+// dimensionValues returns them.
+var dimensionColumns = []string{"weight_grams", "length_mm", "width_mm", "height_mm"}
+
+func dimensionValues(d models.Dimensions) []interface{} {
+	return []interface{}{d.WeightGrams, d.LengthMM, d.WidthMM, d.HeightMM}
+}
+
+// setDimensions adds the dimensions set in d to an update.
+func setDimensions(b sq.UpdateBuilder, d models.Dimensions) sq.UpdateBuilder {
+	for i, v := range []*int{d.WeightGrams, d.LengthMM, d.WidthMM, d.HeightMM} {
+		if v != nil {
+			b = b.Set(dimensionColumns[i], *v)
+		}
+	}
+	return b
+}
+
+// returning renders a RETURNING clause for columns.
+func returning(columns []string) string {
+	return "RETURNING " + strings.Join(columns, ", ")
+}
+
 type ProductRepository struct {
-	db *pgxpool.Pool
+	db       *pgxpool.Pool
+	readOnly *readonly.Guard
 }
 
-func NewProductRepository(db *pgxpool.Pool) *ProductRepository {
-	return &ProductRepository{db: db}
+// NewProductRepository creates a product repository. A nil guard never
+// blocks writes.
+func NewProductRepository(db *pgxpool.Pool, guard *readonly.Guard) *ProductRepository {
+	return &ProductRepository{db: db, readOnly: guard}
 }
 
 func (r *ProductRepository) Create(ctx context.Context, sellerID int, req *models.CreateProductRequest) (*models.Product, error) {
+	if err := r.readOnly.Check(readonly.TableProducts); err != nil {
+		return nil, err
+	}
+
 	query, args, err := psql.Insert("products").
-		Columns("seller_id", "category_id", "title", "description", "price", "stock", "sizes", "image_url").
-		Values(sellerID, req.CategoryID, req.Title, req.Description, req.Price, req.Stock, req.Sizes, req.ImageURL).
-		Suffix("RETURNING id, seller_id, category_id, title, COALESCE(description, '') as description, price::float8, stock, sizes, COALESCE(image_url, '') as image_url, COALESCE(status, 'pending') as status, created_at, updated_at").
+		Columns("seller_id", "category_id", "title", "description", "price", "stock", "sizes", "image_url", "low_stock_threshold").
+		Columns(dimensionColumns...).
+		Values(append([]interface{}{sellerID, req.CategoryID, req.Title, req.Description, req.Price, req.Stock, req.Sizes, req.ImageURL, req.LowStockThreshold},
+			dimensionValues(req.Dimensions)...)...).
+		Suffix(returning(productColumns)).
 		ToSql()
 	if err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to build insert query")
 		return nil, fmt.Errorf("failed to build insert query: %w", err)
 	}
 
-	var product models.Product
-	err = r.db.QueryRow(ctx, query, args...).Scan(
-		&product.ID,
-		&product.SellerID,
-		&product.CategoryID,
-		&product.Title,
-		&product.Description,
-		&product.Price,
-		&product.Stock,
-		&product.Sizes,
-		&product.ImageURL,
-		&product.Status,
-		&product.CreatedAt,
-		&product.UpdatedAt,
-	)
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var product models.Product
+	if err := pgxscan.Get(ctx, tx, &product, query, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, apperrors.NotFound(fmt.Sprintf("category with id %d not found", req.CategoryID))
+		}
 		logger.GetLogger().WithField("err", err).Error("failed to create product")
 		return nil, fmt.Errorf("failed to create product: %w", err)
 	}
 
+	// With variants the product's stock and sizes are theirs.
+	if len(req.Variants) > 0 {
+		for i := range req.Variants {
+			if _, err := insertVariant(ctx, tx, product.ID, product.Price, &req.Variants[i]); err != nil {
+				return nil, err
+			}
+		}
+		if err := syncVariantTotals(ctx, tx, product.ID); err != nil {
+			return nil, err
+		}
+		query, args, err := psql.Select(productColumns...).From("products").Where(sq.Eq{"id": product.ID}).ToSql()
+		if err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to build select query")
+			return nil, fmt.Errorf("failed to build select query: %w", err)
+		}
+		if err := pgxscan.Get(ctx, tx, &product, query, args...); err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to get product")
+			return nil, fmt.Errorf("failed to get product: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return &product, nil
 }
 
 func (r *ProductRepository) GetByID(ctx context.Context, id int) (*models.ProductWithDetails, error) {
-	query, args, err := psql.Select(
-		"p.id", "p.seller_id", "p.category_id", "p.title", "COALESCE(p.description, '') as description",
-		"p.price::float8", "p.stock", "p.sizes", "COALESCE(p.image_url, '') as image_url", "COALESCE(p.status, 'pending') as status",
-		"p.created_at", "p.updated_at",
-		"COALESCE(s.shop_name, '') as seller_name",
-		"COALESCE(c.name, '') as category_name",
-	).From("products p").
+	query, args, err := psql.Select(productDetailColumns...).
+		From("products p").
 		LeftJoin("sellers s ON p.seller_id = s.id").
 		LeftJoin("categories c ON p.category_id = c.id").
 		Where(sq.Eq{"p.id": id}).
@@ -72,24 +158,7 @@ func (r *ProductRepository) GetByID(ctx context.Context, id int) (*models.Produc
 	}
 
 	var product models.ProductWithDetails
-	err = r.db.QueryRow(ctx, query, args...).Scan(
-		&product.ID,
-		&product.SellerID,
-		&product.CategoryID,
-		&product.Title,
-		&product.Description,
-		&product.Price,
-		&product.Stock,
-		&product.Sizes,
-		&product.ImageURL,
-		&product.Status,
-		&product.CreatedAt,
-		&product.UpdatedAt,
-		&product.SellerName,
-		&product.CategoryName,
-	)
-
-	if err != nil {
+	if err := pgxscan.Get(ctx, r.db, &product, query, args...); err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to get product")
 		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
@@ -97,57 +166,69 @@ func (r *ProductRepository) GetByID(ctx context.Context, id int) (*models.Produc
 	return &product, nil
 }
 
-func (r *ProductRepository) GetAll(ctx context.Context, categoryID, sellerID *int, status string, pagination *models.PaginationParams) ([]*models.ProductWithDetails, int64, error) {
-	countBuilder := psql.Select("COUNT(*)").
+// GetByIDs returns the active products among ids, keyed by ID.
+func (r *ProductRepository) GetByIDs(ctx context.Context, ids []int) (map[int]*models.ProductWithDetails, error) {
+	query, args, err := psql.Select(productDetailColumns...).
 		From("products p").
-		Where("p.category_id IS NOT NULL")
-
-	if categoryID != nil {
-		countBuilder = countBuilder.Where(sq.Eq{"p.category_id": *categoryID})
-	}
-	if sellerID != nil {
-		countBuilder = countBuilder.Where(sq.Eq{"p.seller_id": *sellerID})
-	}
-	if status != "" {
-		countBuilder = countBuilder.Where(sq.Eq{"p.status": status})
+		LeftJoin("sellers s ON p.seller_id = s.id").
+		LeftJoin("categories c ON p.category_id = c.id").
+		Where(sq.Eq{"p.id": ids, "p.status": "active"}).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build select query")
+		return nil, fmt.Errorf("failed to build select query: %w", err)
 	}
 
-	countQuery, countArgs, err := countBuilder.ToSql()
-	if err != nil {
-		logger.GetLogger().WithField("err", err).Error("failed to build count query")
-		return nil, 0, fmt.Errorf("failed to build count query: %w", err)
+	var rows []*models.ProductWithDetails
+	if err := pgxscan.Select(ctx, r.db, &rows, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get products")
+		return nil, fmt.Errorf("failed to get products: %w", err)
 	}
 
-	var totalItems int64
-	err = r.db.QueryRow(ctx, countQuery, countArgs...).Scan(&totalItems)
-	if err != nil {
-		logger.GetLogger().WithField("err", err).Error("failed to count products")
-		return nil, 0, fmt.Errorf("failed to count products: %w", err)
+	products := make(map[int]*models.ProductWithDetails, len(rows))
+	for _, product := range rows {
+		products[product.ID] = product
 	}
 
-	selectBuilder := psql.Select(
-		"p.id", "p.seller_id", "p.category_id", "p.title", "COALESCE(p.description, '') as description",
-		"p.price::float8", "p.stock", "p.sizes", "COALESCE(p.image_url, '') as image_url", "COALESCE(p.status, 'pending') as status",
-		"p.created_at", "p.updated_at",
-		"COALESCE(s.shop_name, '') as seller_name",
-		"COALESCE(c.name, '') as category_name",
-	).
-		From("products p").
-		LeftJoin("sellers s ON p.seller_id = s.id").
-		LeftJoin("categories c ON p.category_id = c.id").
-		Where("p.category_id IS NOT NULL").
-		OrderBy("p.created_at DESC")
+	return products, nil
+}
 
+// GetAll lists products from the product_listing materialized view, which
+// already carries seller and category data. The view is refreshed by
+// RefreshListing, so changes show up with up to one refresh interval of lag.
+// With count=estimate the total is approximate (see countRows).
+//
+// With a visitor bucket, products of sellers on probation are left out
+// unless the seller's traffic share reaches the bucket; see
+// models.TrafficBuckets.
+func (r *ProductRepository) GetAll(ctx context.Context, categoryID, sellerID *int, status string, visitorBucket *int, pagination *models.PaginationParams) ([]*models.ProductWithDetails, int64, error) {
+	where := sq.And{}
+	if visitorBucket != nil {
+		where = append(where, sq.Or{
+			sq.Eq{"probation_traffic_percent": nil},
+			sq.GtOrEq{"probation_traffic_percent": (*visitorBucket + 1) * 100 / models.TrafficBuckets},
+		})
+	}
 	if categoryID != nil {
-		selectBuilder = selectBuilder.Where(sq.Eq{"p.category_id": *categoryID})
+		where = append(where, sq.Eq{"category_id": *categoryID})
 	}
 	if sellerID != nil {
-		selectBuilder = selectBuilder.Where(sq.Eq{"p.seller_id": *sellerID})
+		where = append(where, sq.Eq{"seller_id": *sellerID})
 	}
 	if status != "" {
-		selectBuilder = selectBuilder.Where(sq.Eq{"p.status": status})
+		where = append(where, sq.Eq{"status": status})
 	}
 
+	totalItems, err := countRows(ctx, r.db, "product_listing", where, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	selectBuilder := psql.Select(listingColumns...).
+		From("product_listing").
+		Where(where).
+		OrderBy("created_at DESC", "id DESC")
+
 	if pagination != nil {
 		selectBuilder = selectBuilder.Limit(uint64(pagination.GetLimit())).Offset(uint64(pagination.GetOffset()))
 	}
@@ -158,46 +239,62 @@ func (r *ProductRepository) GetAll(ctx context.Context, categoryID, sellerID *in
 		return nil, 0, fmt.Errorf("failed to build select query: %w", err)
 	}
 
-	rows, err := r.db.Query(ctx, query, args...)
-	if err != nil {
+	var products []*models.ProductWithDetails
+	if err := pgxscan.Select(ctx, r.db, &products, query, args...); err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to get products")
 		return nil, 0, fmt.Errorf("failed to get products: %w", err)
 	}
-	defer rows.Close()
 
-	var products []*models.ProductWithDetails
-	for rows.Next() {
-		var product models.ProductWithDetails
-		if err := rows.Scan(
-			&product.ID,
-			&product.SellerID,
-			&product.CategoryID,
-			&product.Title,
-			&product.Description,
-			&product.Price,
-			&product.Stock,
-			&product.Sizes,
-			&product.ImageURL,
-			&product.Status,
-			&product.CreatedAt,
-			&product.UpdatedAt,
-			&product.SellerName,
-			&product.CategoryName,
-		); err != nil {
-			logger.GetLogger().WithField("err", err).Error("failed to scan product")
-			return nil, 0, fmt.Errorf("failed to scan product: %w", err)
-		}
-		products = append(products, &product)
+	return products, totalItems, nil
+}
+
+// GetActiveListing returns every active product from the product_listing
+// view, in id order, leaving out sellers on probation. It backs the catalog
+// feeds.
+func (r *ProductRepository) GetActiveListing(ctx context.Context) ([]*models.ProductWithDetails, error) {
+	query, args, err := psql.Select(listingColumns...).
+		From("product_listing").
+		Where(sq.Eq{"status": "active", "probation_traffic_percent": nil}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build active listing query")
+		return nil, fmt.Errorf("failed to build active listing query: %w", err)
 	}
 
-	return products, totalItems, nil
+	products := []*models.ProductWithDetails{}
+	if err := pgxscan.Select(ctx, r.db, &products, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get active listing")
+		return nil, fmt.Errorf("failed to get active listing: %w", err)
+	}
+
+	return products, nil
+}
+
+// RefreshListing rebuilds the product_listing view without blocking readers.
+func (r *ProductRepository) RefreshListing(ctx context.Context) error {
+	if _, err := r.db.Exec(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY product_listing"); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to refresh product listing")
+		return fmt.Errorf("failed to refresh product listing: %w", err)
+	}
+	return nil
 }
 
 func (r *ProductRepository) Update(ctx context.Context, id int, req *models.UpdateProductRequest) (*models.Product, error) {
+	if err := r.readOnly.Check(readonly.TableProducts); err != nil {
+		return nil, err
+	}
+
+	if req.Stock != nil || req.Sizes != nil || req.Price != nil {
+		if err := r.checkVariants(ctx, id, req); err != nil {
+			return nil, err
+		}
+	}
+
 	updateBuilder := psql.Update("products").
 		Set("updated_at", sq.Expr("NOW()")).
 		Where(sq.Eq{"id": id}).
-		Suffix("RETURNING id, seller_id, category_id, title, COALESCE(description, '') as description, price::float8, stock, sizes, COALESCE(image_url, '') as image_url, COALESCE(status, 'pending') as status, created_at, updated_at")
+		Suffix(returning(productColumns))
 
 	if req.CategoryID != nil {
 		updateBuilder = updateBuilder.Set("category_id", *req.CategoryID)
@@ -223,6 +320,10 @@ func (r *ProductRepository) Update(ctx context.Context, id int, req *models.Upda
 	if req.Status != nil {
 		updateBuilder = updateBuilder.Set("status", *req.Status)
 	}
+	if req.LowStockThreshold != nil {
+		updateBuilder = updateBuilder.Set("low_stock_threshold", *req.LowStockThreshold)
+	}
+	updateBuilder = setDimensions(updateBuilder, req.Dimensions)
 
 	query, args, err := updateBuilder.ToSql()
 	if err != nil {
@@ -231,22 +332,11 @@ func (r *ProductRepository) Update(ctx context.Context, id int, req *models.Upda
 	}
 
 	var product models.Product
-	err = r.db.QueryRow(ctx, query, args...).Scan(
-		&product.ID,
-		&product.SellerID,
-		&product.CategoryID,
-		&product.Title,
-		&product.Description,
-		&product.Price,
-		&product.Stock,
-		&product.Sizes,
-		&product.ImageURL,
-		&product.Status,
-		&product.CreatedAt,
-		&product.UpdatedAt,
-	)
-
-	if err != nil {
+	if err := pgxscan.Get(ctx, r.db, &product, query, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" && req.CategoryID != nil {
+			return nil, apperrors.NotFound(fmt.Sprintf("category with id %d not found", *req.CategoryID))
+		}
 		logger.GetLogger().WithField("err", err).Error("failed to update product")
 		return nil, fmt.Errorf("failed to update product: %w", err)
 	}
@@ -254,7 +344,38 @@ func (r *ProductRepository) Update(ctx context.Context, id int, req *models.Upda
 	return &product, nil
 }
 
+// checkVariants rejects updates a product with variants cannot take: its
+// stock and sizes are its variants', and its price must leave every
+// variant a positive one.
+func (r *ProductRepository) checkVariants(ctx context.Context, id int, req *models.UpdateProductRequest) error {
+	var count int
+	var minDelta float64
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*), COALESCE(MIN(price_delta), 0)::float8 FROM product_variants WHERE product_id = $1`, id).
+		Scan(&count, &minDelta)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get product variants")
+		return fmt.Errorf("failed to get product variants: %w", err)
+	}
+	if count == 0 {
+		return nil
+	}
+	if req.Stock != nil {
+		return apperrors.ValidationError("stock", "the product has variants; set the stock of each variant")
+	}
+	if req.Sizes != nil {
+		return apperrors.ValidationError("sizes", "the product has variants; sizes come from them")
+	}
+	if req.Price != nil && *req.Price+minDelta <= 0 {
+		return apperrors.ValidationError("price", "must leave every variant a positive price")
+	}
+	return nil
+}
+
 func (r *ProductRepository) Delete(ctx context.Context, id int) error {
+	if err := r.readOnly.Check(readonly.TableProducts); err != nil {
+		return err
+	}
+
 	query, args, err := psql.Delete("products").
 		Where(sq.Eq{"id": id}).
 		ToSql()
@@ -278,10 +399,8 @@ func (r *ProductRepository) Delete(ctx context.Context, id int) error {
 }
 
 func (r *ProductRepository) GetBySellerID(ctx context.Context, sellerID int) ([]*models.Product, error) {
-	query, args, err := psql.Select(
-		"id", "seller_id", "category_id", "title", "COALESCE(description, '') as description",
-		"price::float8", "stock", "sizes", "COALESCE(image_url, '') as image_url", "COALESCE(status, 'pending') as status", "created_at", "updated_at",
-	).From("products").
+	query, args, err := psql.Select(productColumns...).
+		From("products").
 		Where(sq.Eq{"seller_id": sellerID}).
 		OrderBy("created_at DESC").
 		ToSql()
@@ -290,35 +409,50 @@ func (r *ProductRepository) GetBySellerID(ctx context.Context, sellerID int) ([]
 		return nil, fmt.Errorf("failed to build select query: %w", err)
 	}
 
-	rows, err := r.db.Query(ctx, query, args...)
-	if err != nil {
+	var products []*models.Product
+	if err := pgxscan.Select(ctx, r.db, &products, query, args...); err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to get products by seller")
 		return nil, fmt.Errorf("failed to get products by seller: %w", err)
 	}
-	defer rows.Close()
 
-	var products []*models.Product
-	for rows.Next() {
-		var product models.Product
-		if err := rows.Scan(
-			&product.ID,
-			&product.SellerID,
-			&product.CategoryID,
-			&product.Title,
-			&product.Description,
-			&product.Price,
-			&product.Stock,
-			&product.Sizes,
-			&product.ImageURL,
-			&product.Status,
-			&product.CreatedAt,
-			&product.UpdatedAt,
-		); err != nil {
-			logger.GetLogger().WithField("err", err).Error("failed to scan product")
-			return nil, fmt.Errorf("failed to scan product: %w", err)
-		}
-		products = append(products, &product)
+	return products, nil
+}
+
+// GetSellerPage returns up to limit of the seller's products with an id
+// below beforeID, newest first; beforeID 0 starts at the newest. Exports
+// page through large catalogs with it.
+func (r *ProductRepository) GetSellerPage(ctx context.Context, sellerID, beforeID, limit int) ([]*models.Product, error) {
+	where := sq.And{sq.Eq{"seller_id": sellerID}}
+	if beforeID > 0 {
+		where = append(where, sq.Lt{"id": beforeID})
+	}
+
+	query, args, err := psql.Select(productColumns...).
+		From("products").
+		Where(where).
+		OrderBy("id DESC").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build select query")
+		return nil, fmt.Errorf("failed to build select query: %w", err)
+	}
+
+	products := []*models.Product{}
+	if err := pgxscan.Select(ctx, r.db, &products, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get products by seller")
+		return nil, fmt.Errorf("failed to get products by seller: %w", err)
 	}
 
 	return products, nil
 }
+
+// CountBySellerID returns how many products the seller has.
+func (r *ProductRepository) CountBySellerID(ctx context.Context, sellerID int) (int, error) {
+	var count int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM products WHERE seller_id = $1`, sellerID).Scan(&count); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to count seller products")
+		return 0, fmt.Errorf("failed to count seller products: %w", err)
+	}
+	return count, nil
+}
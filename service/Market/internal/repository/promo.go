@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// promoColumns select a promo_codes row into models.PromoCode.
+var promoColumns = []string{
+	"id", "code", "funded_by", "seller_id", "percent_off", "expires_at", "active", "created_by", "created_at",
+}
+
+type PromoRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPromoRepository(db *pgxpool.Pool) *PromoRepository {
+	return &PromoRepository{db: db}
+}
+
+// Create stores a new code, upper-cased. Codes are unique regardless of
+// who funds them.
+func (r *PromoRepository) Create(ctx context.Context, createdBy int, req *models.CreatePromoCodeRequest) (*models.PromoCode, error) {
+	query, args, err := psql.Insert("promo_codes").
+		Columns("code", "funded_by", "seller_id", "percent_off", "expires_at", "created_by").
+		Values(strings.ToUpper(req.Code), req.FundedBy, req.SellerID, req.PercentOff, req.ExpiresAt, createdBy).
+		Suffix(returning(promoColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build insert promo code query")
+		return nil, fmt.Errorf("failed to build insert promo code query: %w", err)
+	}
+
+	var promo models.PromoCode
+	if err := pgxscan.Get(ctx, r.db, &promo, query, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				return nil, apperrors.Conflict(fmt.Sprintf("promo code %s already exists", strings.ToUpper(req.Code)))
+			case "23503":
+				return nil, apperrors.NotFound("seller not found")
+			}
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to create promo code")
+		return nil, fmt.Errorf("failed to create promo code: %w", err)
+	}
+
+	return &promo, nil
+}
+
+// GetAll lists codes newest first, optionally only those of a seller.
+func (r *PromoRepository) GetAll(ctx context.Context, sellerID *int, pagination *models.PaginationParams) ([]*models.PromoCode, int64, error) {
+	where := sq.And{}
+	if sellerID != nil {
+		where = append(where, sq.Eq{"seller_id": *sellerID})
+	}
+
+	countQuery, countArgs, err := psql.Select("COUNT(*)").From("promo_codes").Where(where).ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build count query")
+		return nil, 0, fmt.Errorf("failed to build count query: %w", err)
+	}
+
+	var totalItems int64
+	if err := r.db.QueryRow(ctx, countQuery, countArgs...).Scan(&totalItems); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to count promo codes")
+		return nil, 0, fmt.Errorf("failed to count promo codes: %w", err)
+	}
+
+	if totalItems == 0 {
+		return []*models.PromoCode{}, 0, nil
+	}
+
+	query, args, err := psql.Select(promoColumns...).From("promo_codes").
+		Where(where).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(pagination.GetLimit())).
+		Offset(uint64(pagination.GetOffset())).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build promo codes query")
+		return nil, 0, fmt.Errorf("failed to build promo codes query: %w", err)
+	}
+
+	promos := []*models.PromoCode{}
+	if err := pgxscan.Select(ctx, r.db, &promos, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get promo codes")
+		return nil, 0, fmt.Errorf("failed to get promo codes: %w", err)
+	}
+
+	return promos, totalItems, nil
+}
+
+// Deactivate stops a code from being redeemed. Orders that already used it
+// keep their discount. With a seller, only that seller's codes are found.
+func (r *PromoRepository) Deactivate(ctx context.Context, id int, sellerID *int) (*models.PromoCode, error) {
+	where := sq.And{sq.Eq{"id": id}}
+	if sellerID != nil {
+		where = append(where, sq.Eq{"seller_id": *sellerID})
+	}
+
+	query, args, err := psql.Update("promo_codes").
+		Set("active", false).
+		Where(where).
+		Suffix(returning(promoColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build deactivate promo code query")
+		return nil, fmt.Errorf("failed to build deactivate promo code query: %w", err)
+	}
+
+	var promo models.PromoCode
+	if err := pgxscan.Get(ctx, r.db, &promo, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.NotFound(fmt.Sprintf("promo code with id %d not found", id))
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to deactivate promo code")
+		return nil, fmt.Errorf("failed to deactivate promo code: %w", err)
+	}
+
+	return &promo, nil
+}
+
+// redeemablePromo looks up an active, unexpired code at checkout.
+func redeemablePromo(ctx context.Context, tx pgx.Tx, code string) (*models.PromoCode, error) {
+	query := `SELECT ` + strings.Join(promoColumns, ", ") + ` FROM promo_codes
+		WHERE code = $1 AND active AND (expires_at IS NULL OR expires_at > NOW())`
+
+	var promo models.PromoCode
+	if err := pgxscan.Get(ctx, tx, &promo, query, strings.ToUpper(strings.TrimSpace(code))); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.ValidationError("promo_code", "unknown or expired promo code")
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get promo code")
+		return nil, fmt.Errorf("failed to get promo code: %w", err)
+	}
+
+	return &promo, nil
+}
@@ -0,0 +1,325 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/clients/authclient"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// reportColumns select a reports row into models.Report.
+var reportColumns = []string{
+	"id", "reporter_id", "source", "target_type", "target_id", "reason", "COALESCE(details, '') AS details", "status",
+	"COALESCE(resolution, '') AS resolution", "COALESCE(resolution_note, '') AS resolution_note", "resolved_by", "resolved_at", "created_at",
+}
+
+// AccountSuspender suspends user accounts in the Auth service, which then
+// rejects their access tokens, also when introspected by ActiveSession.
+// *authclient.Client implements it.
+type AccountSuspender interface {
+	SuspendUser(ctx context.Context, userID int64) error
+}
+
+// ReportRepository resolves reports; ban_user suspends the seller's account
+// through accounts, and is refused when it is nil.
+type ReportRepository struct {
+	db       *pgxpool.Pool
+	accounts AccountSuspender
+}
+
+func NewReportRepository(db *pgxpool.Pool, accounts AccountSuspender) *ReportRepository {
+	return &ReportRepository{db: db, accounts: accounts}
+}
+
+// Create files a report. A user can have only one open report per target.
+func (r *ReportRepository) Create(ctx context.Context, reporterID int, req *models.CreateReportRequest) (*models.Report, error) {
+	exists, err := r.targetExists(ctx, req.TargetType, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, apperrors.NotFound(fmt.Sprintf("%s with id %d not found", req.TargetType, req.TargetID))
+	}
+
+	query, args, err := psql.Insert("reports").
+		Columns("reporter_id", "target_type", "target_id", "reason", "details").
+		Values(reporterID, req.TargetType, req.TargetID, req.Reason, req.Details).
+		Suffix(returning(reportColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build insert report query")
+		return nil, fmt.Errorf("failed to build insert report query: %w", err)
+	}
+
+	var rep models.Report
+	if err := pgxscan.Get(ctx, r.db, &rep, query, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, apperrors.Conflict("you already have an open report for this " + req.TargetType)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to create report")
+		return nil, fmt.Errorf("failed to create report: %w", err)
+	}
+
+	return &rep, nil
+}
+
+func (r *ReportRepository) targetExists(ctx context.Context, targetType string, targetID int) (bool, error) {
+	var query string
+	switch targetType {
+	case models.ReportTargetProduct:
+		query = `SELECT EXISTS(SELECT 1 FROM products WHERE id = $1)`
+	case models.ReportTargetSeller:
+		query = `SELECT EXISTS(SELECT 1 FROM sellers WHERE id = $1)`
+	default:
+		return false, apperrors.ValidationError("target_type", "must be product or seller")
+	}
+
+	var exists bool
+	if err := r.db.QueryRow(ctx, query, targetID).Scan(&exists); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to check report target")
+		return false, fmt.Errorf("failed to check report target: %w", err)
+	}
+	return exists, nil
+}
+
+// GetAll returns reports oldest first so the moderation queue is worked in
+// arrival order. An empty status returns every report.
+func (r *ReportRepository) GetAll(ctx context.Context, status string, pagination *models.PaginationParams) ([]*models.Report, int64, error) {
+	countBuilder := psql.Select("COUNT(*)").From("reports")
+	selectBuilder := psql.Select(reportColumns...).From("reports")
+	if status != "" {
+		countBuilder = countBuilder.Where(sq.Eq{"status": status})
+		selectBuilder = selectBuilder.Where(sq.Eq{"status": status})
+	}
+
+	countQuery, countArgs, err := countBuilder.ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build count query")
+		return nil, 0, fmt.Errorf("failed to build count query: %w", err)
+	}
+
+	var totalItems int64
+	if err := r.db.QueryRow(ctx, countQuery, countArgs...).Scan(&totalItems); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to count reports")
+		return nil, 0, fmt.Errorf("failed to count reports: %w", err)
+	}
+
+	if totalItems == 0 {
+		return []*models.Report{}, 0, nil
+	}
+
+	query, args, err := selectBuilder.
+		OrderBy("created_at", "id").
+		Limit(uint64(pagination.GetLimit())).
+		Offset(uint64(pagination.GetOffset())).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build reports query")
+		return nil, 0, fmt.Errorf("failed to build reports query: %w", err)
+	}
+
+	reports := []*models.Report{}
+	if err := pgxscan.Select(ctx, r.db, &reports, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get reports")
+		return nil, 0, fmt.Errorf("failed to get reports: %w", err)
+	}
+
+	return reports, totalItems, nil
+}
+
+// Resolve closes an open report, applies the moderation action to the
+// reported content and notifies both the reporter and the affected seller.
+func (r *ReportRepository) Resolve(ctx context.Context, id, adminID int, req *models.ResolveReportRequest) (*models.Report, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	lockQuery, lockArgs, err := psql.Select(reportColumns...).
+		From("reports").
+		Where(sq.Eq{"id": id}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build report lock query")
+		return nil, fmt.Errorf("failed to build report lock query: %w", err)
+	}
+
+	rep := &models.Report{}
+	if err := pgxscan.Get(ctx, tx, rep, lockQuery, lockArgs...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.NotFound(fmt.Sprintf("report with id %d not found", id))
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get report")
+		return nil, fmt.Errorf("failed to get report: %w", err)
+	}
+	if rep.Status != models.ReportStatusOpen {
+		return nil, apperrors.Conflict(fmt.Sprintf("report %d is already resolved", id))
+	}
+
+	// Every report target but a review belongs to a seller; a review
+	// belongs to its author, who is told the outcome instead.
+	var sellerID, sellerUserID int
+	ownerQuery := `SELECT id, user_id FROM sellers WHERE id = $1`
+	switch rep.TargetType {
+	case models.ReportTargetProduct:
+		ownerQuery = `SELECT s.id, s.user_id FROM products p JOIN sellers s ON s.id = p.seller_id WHERE p.id = $1`
+	case models.ReportTargetReview:
+		if req.Action != models.ReportActionDismiss && req.Action != models.ReportActionHideContent {
+			return nil, apperrors.ValidationError("action", "must be dismiss or hide_content for a review")
+		}
+		ownerQuery = `SELECT 0, user_id FROM reviews WHERE id = $1`
+	}
+	if err := tx.QueryRow(ctx, ownerQuery, rep.TargetID).Scan(&sellerID, &sellerUserID); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		logger.GetLogger().WithField("err", err).Error("failed to get reported content owner")
+		return nil, fmt.Errorf("failed to get reported content owner: %w", err)
+	}
+
+	var sellerMessage string
+	switch req.Action {
+	case models.ReportActionDismiss:
+		// Dismissing an automated hold releases the content.
+		if rep.Source == models.ReportSourceAuto && rep.TargetType == models.ReportTargetProduct {
+			_, err = tx.Exec(ctx, `UPDATE products SET status = 'active', updated_at = NOW() WHERE id = $1 AND status = 'pending'`, rep.TargetID)
+			sellerMessage = fmt.Sprintf("Your product #%d passed manual review and is now published.", rep.TargetID)
+		} else if rep.Source == models.ReportSourceAuto && rep.TargetType == models.ReportTargetReview {
+			err = setReviewStatus(ctx, tx, rep.TargetID, models.ReviewStatusPending, models.ReviewStatusPublished)
+			sellerMessage = fmt.Sprintf("Your review #%d passed manual review and is now published.", rep.TargetID)
+		}
+	case models.ReportActionHideContent:
+		switch rep.TargetType {
+		case models.ReportTargetReview:
+			err = setReviewStatus(ctx, tx, rep.TargetID, "", models.ReviewStatusHidden)
+			sellerMessage = fmt.Sprintf("Your review #%d was hidden after a moderation review.", rep.TargetID)
+		case models.ReportTargetProduct:
+			_, err = tx.Exec(ctx, `UPDATE products SET status = 'blocked', updated_at = NOW() WHERE id = $1`, rep.TargetID)
+			sellerMessage = fmt.Sprintf("Your product #%d was hidden after a moderation review.", rep.TargetID)
+		default:
+			_, err = tx.Exec(ctx, `UPDATE sellers SET is_active = false, updated_at = NOW() WHERE id = $1`, rep.TargetID)
+			sellerMessage = "Your shop was hidden after a moderation review."
+		}
+	case models.ReportActionWarnSeller:
+		sellerMessage = "You received a warning after a moderation review."
+	case models.ReportActionBanUser:
+		if r.accounts == nil {
+			return nil, apperrors.ValidationError("action", "ban_user needs the Auth service (AUTH_URL)")
+		}
+		if _, err = tx.Exec(ctx, `UPDATE sellers SET is_active = false, updated_at = NOW() WHERE id = $1`, sellerID); err == nil {
+			_, err = tx.Exec(ctx, `UPDATE products SET status = 'blocked', updated_at = NOW() WHERE seller_id = $1 AND status <> 'deleted'`, sellerID)
+		}
+		// The account is suspended before the ban commits; suspending is
+		// idempotent, so a resolution that fails afterwards can be retried.
+		// A user deleted in Auth has no account left to suspend.
+		if err == nil && sellerUserID != 0 {
+			if err = r.accounts.SuspendUser(ctx, int64(sellerUserID)); errors.Is(err, authclient.ErrUserNotFound) {
+				err = nil
+			}
+		}
+		sellerMessage = "Your account was suspended after a moderation review."
+	}
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to apply moderation action")
+		return nil, fmt.Errorf("failed to apply moderation action: %w", err)
+	}
+	if sellerMessage != "" && sellerUserID != 0 {
+		if req.Note != "" {
+			sellerMessage += " " + req.Note
+		}
+		if err := notify(ctx, tx, sellerUserID, "moderation_action", sellerMessage); err != nil {
+			return nil, err
+		}
+	}
+
+	if rep.ReporterID != nil {
+		outcome := "no action was taken"
+		if req.Action != models.ReportActionDismiss {
+			outcome = "action was taken"
+		}
+		if err := notify(ctx, tx, *rep.ReporterID, "report_resolved",
+			fmt.Sprintf("Your report #%d was reviewed and %s. Thank you.", rep.ID, outcome)); err != nil {
+			return nil, err
+		}
+	}
+
+	query, args, err := psql.Update("reports").
+		Set("status", models.ReportStatusResolved).
+		Set("resolution", req.Action).
+		Set("resolution_note", req.Note).
+		Set("resolved_by", adminID).
+		Set("resolved_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id}).
+		Suffix(returning(reportColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build resolve report query")
+		return nil, fmt.Errorf("failed to build resolve report query: %w", err)
+	}
+
+	rep = &models.Report{}
+	if err := pgxscan.Get(ctx, tx, rep, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to resolve report")
+		return nil, fmt.Errorf("failed to resolve report: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return rep, nil
+}
+
+// HoldProduct puts a product back to "pending" and opens an automated
+// moderation report for it. Repeated holds of the same product while a
+// report is still open only refresh the product status.
+func (r *ReportRepository) HoldProduct(ctx context.Context, productID int, reasons []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `UPDATE products SET status = 'pending', updated_at = NOW() WHERE id = $1`, productID); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to hold product")
+		return fmt.Errorf("failed to hold product: %w", err)
+	}
+
+	if err := openAutoReport(ctx, tx, models.ReportTargetProduct, productID, reasons); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// openAutoReport opens an automated moderation report for held content, or
+// refreshes the reasons of the one still open for it.
+func openAutoReport(ctx context.Context, tx pgx.Tx, targetType string, targetID int, reasons []string) error {
+	_, err := tx.Exec(ctx, `INSERT INTO reports (source, target_type, target_id, reason, details)
+		VALUES ('auto', $1, $2, 'auto_moderation', $3)
+		ON CONFLICT (target_type, target_id) WHERE source = 'auto' AND status = 'open'
+		DO UPDATE SET details = EXCLUDED.details`, targetType, targetID, strings.Join(reasons, "; "))
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to create moderation report")
+		return fmt.Errorf("failed to create moderation report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+)
+
+// lockStock locks the product row until the transaction ends, so that
+// reservations for it are checked one at a time, and returns its stock, or
+// the stock of the variant for variant items. Without reservations nothing
+// is locked.
+func (r *CartRepository) lockStock(ctx context.Context, tx pgx.Tx, productID int, variantID *int) (int, error) {
+	if r.reservationTTL <= 0 {
+		return 0, nil
+	}
+
+	var stock int
+	err := tx.QueryRow(ctx, `SELECT stock FROM products WHERE id = $1 FOR UPDATE`, productID).Scan(&stock)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, apperrors.ProductNotFound(productID)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to lock product stock")
+		return 0, fmt.Errorf("failed to lock product stock: %w", err)
+	}
+	if variantID == nil {
+		return stock, nil
+	}
+
+	err = tx.QueryRow(ctx, `SELECT stock FROM product_variants WHERE id = $1 FOR UPDATE`, *variantID).Scan(&stock)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to lock variant stock")
+		return 0, fmt.Errorf("failed to lock variant stock: %w", err)
+	}
+	return stock, nil
+}
+
+// itemVariant returns the variant of productID a cart item is for. Products
+// with variants are only sold by variant; products without have none.
+func itemVariant(ctx context.Context, tx pgx.Tx, productID int, variantID *int) (*models.ProductVariant, error) {
+	if variantID == nil {
+		var hasVariants bool
+		err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM product_variants WHERE product_id = $1)`, productID).Scan(&hasVariants)
+		if err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to get product variants")
+			return nil, fmt.Errorf("failed to get product variants: %w", err)
+		}
+		if hasVariants {
+			return nil, apperrors.ValidationError("variant_id", "required for products with variants")
+		}
+		return nil, nil
+	}
+
+	var variant models.ProductVariant
+	err := pgxscan.Get(ctx, tx, &variant, `SELECT `+strings.Join(variantColumns, ", ")+`
+		FROM product_variants WHERE id = $1 AND product_id = $2`, *variantID, productID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.NotFound(fmt.Sprintf("variant with id %d not found", *variantID))
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get variant")
+		return nil, fmt.Errorf("failed to get variant: %w", err)
+	}
+	return &variant, nil
+}
+
+// reservedStock is the condition on stock_reservations r for the stock an
+// item draws on: its variant's, or its product's.
+func reservedStock(productID int, variantID *int) (string, int) {
+	if variantID != nil {
+		return "r.variant_id = $1", *variantID
+	}
+	return "r.product_id = $1", productID
+}
+
+// reserve holds stock for the cart item's whole quantity until the
+// reservation TTL from now. stock is the product's or variant's stock,
+// locked by lockStock; it fails when the unexpired reservations of other
+// cart items leave too little of it.
+func (r *CartRepository) reserve(ctx context.Context, tx pgx.Tx, item *models.CartItem, stock int) error {
+	if r.reservationTTL <= 0 {
+		return nil
+	}
+
+	cond, id := reservedStock(item.ProductID, item.VariantID)
+	var held int
+	err := tx.QueryRow(ctx, `SELECT COALESCE(SUM(r.quantity), 0) FROM stock_reservations r
+		WHERE `+cond+` AND r.cart_item_id <> $2 AND r.expires_at > NOW()`, id, item.ID).Scan(&held)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to sum stock reservations")
+		return fmt.Errorf("failed to sum stock reservations: %w", err)
+	}
+	if stock-held < item.Quantity {
+		return apperrors.InsufficientStockForProduct(item.ProductID)
+	}
+
+	err = tx.QueryRow(ctx, `INSERT INTO stock_reservations (cart_item_id, product_id, variant_id, quantity, expires_at)
+		VALUES ($1, $2, $3, $4, NOW() + $5 * INTERVAL '1 millisecond')
+		ON CONFLICT (cart_item_id) DO UPDATE SET quantity = EXCLUDED.quantity, expires_at = EXCLUDED.expires_at
+		RETURNING expires_at`, item.ID, item.ProductID, item.VariantID, item.Quantity, r.reservationTTL.Milliseconds()).Scan(&item.ReservedUntil)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to reserve stock")
+		return fmt.Errorf("failed to reserve stock: %w", err)
+	}
+	return nil
+}
+
+// ReleaseExpired deletes expired reservations and returns how many there
+// were. Expired reservations no longer hold stock either way; this only
+// keeps the table small.
+func (r *CartRepository) ReleaseExpired(ctx context.Context) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM stock_reservations WHERE expires_at <= NOW()`)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to release expired stock reservations")
+		return 0, fmt.Errorf("failed to release expired stock reservations: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// heldByOthers returns how much of the product, or of its variant, is held
+// by the unexpired reservations of other users' carts.
+func heldByOthers(ctx context.Context, tx pgx.Tx, productID int, variantID *int, userID int) (int, error) {
+	cond, id := reservedStock(productID, variantID)
+	var held int
+	err := tx.QueryRow(ctx, `SELECT COALESCE(SUM(r.quantity), 0) FROM stock_reservations r
+		JOIN cart_items ci ON ci.id = r.cart_item_id
+		JOIN carts c ON c.id = ci.cart_id
+		WHERE `+cond+` AND r.expires_at > NOW() AND c.user_id IS DISTINCT FROM $2`, id, userID).Scan(&held)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to sum stock reservations")
+		return 0, fmt.Errorf("failed to sum stock reservations: %w", err)
+	}
+	return held, nil
+}
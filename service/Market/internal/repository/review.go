@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/readonly"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// reviewColumns select a reviews row into models.Review.
+var reviewColumns = []string{
+	"id", "product_id", "user_id", "rating", "COALESCE(comment, '') AS comment", "status", "created_at",
+}
+
+// hasReceivedProduct matches when user $1 received product $2 in an order
+// that was not cancelled.
+const hasReceivedProduct = `SELECT EXISTS (
+	SELECT 1 FROM order_items oi JOIN orders o ON o.id = oi.order_id
+	WHERE o.user_id = $1 AND oi.product_id = $2 AND o.status <> 'cancelled'
+		AND (oi.fulfillment_status = 'delivered' OR o.status = 'delivered'))`
+
+type ReviewRepository struct {
+	db       *pgxpool.Pool
+	readOnly *readonly.Guard
+}
+
+// NewReviewRepository creates a review repository. A nil guard never
+// blocks writes.
+func NewReviewRepository(db *pgxpool.Pool, guard *readonly.Guard) *ReviewRepository {
+	return &ReviewRepository{db: db, readOnly: guard}
+}
+
+// Create adds userID's review of a product they received and updates the
+// product and seller ratings. A user reviews a product once. With
+// holdReasons from moderation the review is pending, left out of the
+// ratings, and an automated moderation report is opened for it.
+func (r *ReviewRepository) Create(ctx context.Context, productID, userID int, req *models.CreateReviewRequest, holdReasons []string) (*models.Review, error) {
+	// Reviews update the product's rating.
+	if err := r.readOnly.Check(readonly.TableProducts); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sellerID, err := lockRated(ctx, tx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var received bool
+	if err := tx.QueryRow(ctx, hasReceivedProduct, userID, productID).Scan(&received); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to check product purchase")
+		return nil, fmt.Errorf("failed to check product purchase: %w", err)
+	}
+	if !received {
+		return nil, apperrors.Forbidden("only buyers who received the product can review it")
+	}
+
+	status := models.ReviewStatusPublished
+	if len(holdReasons) > 0 {
+		status = models.ReviewStatusPending
+	}
+	query, args, err := psql.Insert("reviews").
+		Columns("product_id", "user_id", "rating", "comment", "status").
+		Values(productID, userID, req.Rating, req.Comment, status).
+		Suffix(returning(reviewColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build insert review query")
+		return nil, fmt.Errorf("failed to build insert review query: %w", err)
+	}
+
+	var review models.Review
+	if err := pgxscan.Get(ctx, tx, &review, query, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, apperrors.Conflict("you have already reviewed this product")
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to create review")
+		return nil, fmt.Errorf("failed to create review: %w", err)
+	}
+
+	if status == models.ReviewStatusPending {
+		if err := openAutoReport(ctx, tx, models.ReportTargetReview, review.ID, holdReasons); err != nil {
+			return nil, err
+		}
+	} else if err := updateRatings(ctx, tx, productID, sellerID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &review, nil
+}
+
+// GetByProduct returns a product's published reviews, newest first.
+func (r *ReviewRepository) GetByProduct(ctx context.Context, productID int, pagination *models.PaginationParams) ([]*models.Review, int64, error) {
+	var exists bool
+	if err := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM products WHERE id = $1)`, productID).Scan(&exists); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to check product")
+		return nil, 0, fmt.Errorf("failed to check product: %w", err)
+	}
+	if !exists {
+		return nil, 0, apperrors.ProductNotFound(productID)
+	}
+
+	where := sq.And{sq.Eq{"product_id": productID, "status": models.ReviewStatusPublished}}
+
+	totalItems, err := countRows(ctx, r.db, "reviews", where, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if totalItems == 0 {
+		return []*models.Review{}, 0, nil
+	}
+
+	query, args, err := psql.Select(reviewColumns...).
+		From("reviews").
+		Where(where).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(pagination.GetLimit())).
+		Offset(uint64(pagination.GetOffset())).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build reviews query")
+		return nil, 0, fmt.Errorf("failed to build reviews query: %w", err)
+	}
+
+	reviews := []*models.Review{}
+	if err := pgxscan.Select(ctx, r.db, &reviews, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get reviews")
+		return nil, 0, fmt.Errorf("failed to get reviews: %w", err)
+	}
+	return reviews, totalItems, nil
+}
+
+// Delete removes userID's own review and updates the product and seller
+// ratings.
+func (r *ReviewRepository) Delete(ctx context.Context, id, userID int) error {
+	if err := r.readOnly.Check(readonly.TableProducts); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var productID int
+	err = tx.QueryRow(ctx, `SELECT product_id FROM reviews WHERE id = $1 AND user_id = $2`, id, userID).Scan(&productID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperrors.NotFound("review not found")
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get review")
+		return fmt.Errorf("failed to get review: %w", err)
+	}
+
+	sellerID, err := lockRated(ctx, tx, productID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM reviews WHERE id = $1`, id); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to delete review")
+		return fmt.Errorf("failed to delete review: %w", err)
+	}
+
+	if err := updateRatings(ctx, tx, productID, sellerID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// setReviewStatus moves a review to status, when it is in from or, with an
+// empty from, in any other status, and updates the ratings it counts
+// towards. A review deleted in the meantime is left alone.
+func setReviewStatus(ctx context.Context, tx pgx.Tx, id int, from, status string) error {
+	var productID int
+	err := tx.QueryRow(ctx, `SELECT product_id FROM reviews WHERE id = $1`, id).Scan(&productID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get review")
+		return fmt.Errorf("failed to get review: %w", err)
+	}
+
+	sellerID, err := lockRated(ctx, tx, productID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE reviews SET status = $2 WHERE id = $1 AND ($3 = '' OR status = $3)`, id, status, from); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to update review status")
+		return fmt.Errorf("failed to update review status: %w", err)
+	}
+	return updateRatings(ctx, tx, productID, sellerID)
+}
+
+// lockRated locks a product and its seller, in that order, so concurrent
+// reviews update their ratings one after another, and returns the seller.
+func lockRated(ctx context.Context, tx pgx.Tx, productID int) (int, error) {
+	var sellerID int
+	err := tx.QueryRow(ctx, `SELECT seller_id FROM products WHERE id = $1 FOR UPDATE`, productID).Scan(&sellerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, apperrors.ProductNotFound(productID)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to lock product")
+		return 0, fmt.Errorf("failed to lock product: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM sellers WHERE id = $1 FOR UPDATE`, sellerID); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to lock seller")
+		return 0, fmt.Errorf("failed to lock seller: %w", err)
+	}
+	return sellerID, nil
+}
+
+// updateRatings recomputes the average rating and review count of a product
+// and of its seller, over the published reviews of all of the seller's
+// products.
+func updateRatings(ctx context.Context, tx pgx.Tx, productID, sellerID int) error {
+	_, err := tx.Exec(ctx, `UPDATE products SET
+			rating = COALESCE((SELECT ROUND(AVG(rating), 2) FROM reviews WHERE product_id = $1 AND status = 'published'), 0),
+			review_count = (SELECT COUNT(*) FROM reviews WHERE product_id = $1 AND status = 'published')
+		WHERE id = $1`, productID)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to update product rating")
+		return fmt.Errorf("failed to update product rating: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE sellers s SET
+			rating = COALESCE(agg.rating, 0),
+			review_count = agg.reviews
+		FROM (
+			SELECT ROUND(AVG(r.rating), 2) AS rating, COUNT(r.id) AS reviews
+			FROM reviews r JOIN products p ON p.id = r.product_id
+			WHERE p.seller_id = $1 AND r.status = 'published'
+		) agg
+		WHERE s.id = $1`, sellerID)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to update seller rating")
+		return fmt.Errorf("failed to update seller rating: %w", err)
+	}
+	return nil
+}
@@ -2,27 +2,64 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/pkg/fieldcrypt"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
 	"github.com/Zifeldev/marketback/service/Market/internal/logger"
 	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// sellerColumns select a sellers row into models.Seller.
+var sellerColumns = []string{
+	"id", "user_id", "shop_name", "COALESCE(description, '') AS description",
+	"COALESCE(return_policy, '') AS return_policy", "COALESCE(shipping_policy, '') AS shipping_policy",
+	"(COALESCE(payout_details, '') <> '' OR " + sellerPayoutAccount + ") AS has_payout_details",
+	"COALESCE(rating, 0)::float8 AS rating", "review_count", "COALESCE(is_active, false) AS is_active",
+	"api_plan", "cancellation_window_minutes", "COALESCE(custom_domain, '') AS custom_domain",
+	"probation_orders", "probation_traffic_percent::int AS probation_traffic_percent",
+	"COALESCE(probation_orders > " + sellerDeliveredOrders + ", false) AS on_probation",
+	"trusted", "created_at", "updated_at",
+}
+
+// sellerPayoutAccount tells whether the seller of the sellers row has
+// registered a payout account.
+const sellerPayoutAccount = `EXISTS (SELECT 1 FROM seller_payout_accounts a WHERE a.seller_id = sellers.id)`
+
+// sellerDeliveredOrders counts the orders in which the seller of the
+// sellers row has had items delivered, the progress out of probation. The
+// product_listing view counts them the same way.
+const sellerDeliveredOrders = `(SELECT COUNT(DISTINCT oi.order_id)
+	FROM order_items oi
+	JOIN orders o ON o.id = oi.order_id
+	JOIN products p ON p.id = oi.product_id
+	WHERE p.seller_id = sellers.id AND (oi.fulfillment_status = 'delivered' OR o.status = 'delivered'))`
+
 type SellerRepository struct {
-	db *pgxpool.Pool
+	db      *pgxpool.Pool
+	keyring *fieldcrypt.Keyring
 }
 
-func NewSellerRepository(db *pgxpool.Pool) *SellerRepository {
-	return &SellerRepository{db: db}
+// NewSellerRepository creates the seller repository. Payout details are
+// sealed with keyring; a nil keyring stores them as plaintext.
+func NewSellerRepository(db *pgxpool.Pool, keyring *fieldcrypt.Keyring) *SellerRepository {
+	return &SellerRepository{db: db, keyring: keyring}
 }
 
-func (r *SellerRepository) Create(ctx context.Context, userID int, req *models.CreateSellerRequest) (*models.Seller, error) {
+// Create creates the seller profile of userID, on the given probation when
+// its orders are above zero.
+func (r *SellerRepository) Create(ctx context.Context, userID int, req *models.CreateSellerRequest, probation models.SetSellerProbationRequest) (*models.Seller, error) {
+	orders, traffic := probationColumns(probation)
 	query, args, err := psql.Insert("sellers").
-		Columns("user_id", "shop_name", "description").
-		Values(userID, req.ShopName, req.Description).
-		Suffix("RETURNING id, user_id, shop_name, description, rating::float8, is_active, created_at, updated_at").
+		Columns("user_id", "shop_name", "description", "probation_orders", "probation_traffic_percent").
+		Values(userID, req.ShopName, req.Description, orders, traffic).
+		Suffix(returning(sellerColumns)).
 		ToSql()
 	if err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to build insert seller query")
@@ -30,18 +67,7 @@ func (r *SellerRepository) Create(ctx context.Context, userID int, req *models.C
 	}
 
 	var seller models.Seller
-	err = r.db.QueryRow(ctx, query, args...).Scan(
-		&seller.ID,
-		&seller.UserID,
-		&seller.ShopName,
-		&seller.Description,
-		&seller.Rating,
-		&seller.IsActive,
-		&seller.CreatedAt,
-		&seller.UpdatedAt,
-	)
-
-	if err != nil {
+	if err := pgxscan.Get(ctx, r.db, &seller, query, args...); err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to create seller")
 		return nil, fmt.Errorf("failed to create seller: %w", err)
 	}
@@ -50,21 +76,17 @@ func (r *SellerRepository) Create(ctx context.Context, userID int, req *models.C
 }
 
 func (r *SellerRepository) GetByID(ctx context.Context, id int) (*models.Seller, error) {
-	query := `SELECT id, user_id, shop_name, COALESCE(description, '') as description, rating::float8 as rating, is_active, created_at, updated_at FROM sellers WHERE id = $1`
+	query, args, err := psql.Select(sellerColumns...).
+		From("sellers").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build select seller query")
+		return nil, fmt.Errorf("failed to build select seller query: %w", err)
+	}
 
 	var seller models.Seller
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&seller.ID,
-		&seller.UserID,
-		&seller.ShopName,
-		&seller.Description,
-		&seller.Rating,
-		&seller.IsActive,
-		&seller.CreatedAt,
-		&seller.UpdatedAt,
-	)
-
-	if err != nil {
+	if err := pgxscan.Get(ctx, r.db, &seller, query, args...); err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to get seller")
 		return nil, fmt.Errorf("failed to get seller: %w", err)
 	}
@@ -73,21 +95,17 @@ func (r *SellerRepository) GetByID(ctx context.Context, id int) (*models.Seller,
 }
 
 func (r *SellerRepository) GetByUserID(ctx context.Context, userID int) (*models.Seller, error) {
-	query := `SELECT id, user_id, shop_name, COALESCE(description, '') as description, rating::float8 as rating, is_active, created_at, updated_at FROM sellers WHERE user_id = $1`
+	query, args, err := psql.Select(sellerColumns...).
+		From("sellers").
+		Where(sq.Eq{"user_id": userID}).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build select seller query")
+		return nil, fmt.Errorf("failed to build select seller query: %w", err)
+	}
 
 	var seller models.Seller
-	err := r.db.QueryRow(ctx, query, userID).Scan(
-		&seller.ID,
-		&seller.UserID,
-		&seller.ShopName,
-		&seller.Description,
-		&seller.Rating,
-		&seller.IsActive,
-		&seller.CreatedAt,
-		&seller.UpdatedAt,
-	)
-
-	if err != nil {
+	if err := pgxscan.Get(ctx, r.db, &seller, query, args...); err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to get seller by user ID")
 		return nil, fmt.Errorf("failed to get seller by user ID: %w", err)
 	}
@@ -99,7 +117,7 @@ func (r *SellerRepository) Update(ctx context.Context, id int, req *models.Updat
 	updateBuilder := psql.Update("sellers").
 		Set("updated_at", sq.Expr("NOW()")).
 		Where(sq.Eq{"id": id}).
-		Suffix("RETURNING id, user_id, shop_name, description, rating::float8, is_active, created_at, updated_at")
+		Suffix(returning(sellerColumns))
 
 	if req.ShopName != "" {
 		updateBuilder = updateBuilder.Set("shop_name", req.ShopName)
@@ -107,6 +125,20 @@ func (r *SellerRepository) Update(ctx context.Context, id int, req *models.Updat
 	if req.Description != "" {
 		updateBuilder = updateBuilder.Set("description", req.Description)
 	}
+	if req.ReturnPolicy != "" {
+		updateBuilder = updateBuilder.Set("return_policy", req.ReturnPolicy)
+	}
+	if req.ShippingPolicy != "" {
+		updateBuilder = updateBuilder.Set("shipping_policy", req.ShippingPolicy)
+	}
+	if req.PayoutDetails != "" {
+		payout, err := r.keyring.Encrypt(req.PayoutDetails)
+		if err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to encrypt payout details")
+			return nil, fmt.Errorf("failed to encrypt payout details: %w", err)
+		}
+		updateBuilder = updateBuilder.Set("payout_details", payout)
+	}
 
 	query, args, err := updateBuilder.ToSql()
 	if err != nil {
@@ -115,18 +147,7 @@ func (r *SellerRepository) Update(ctx context.Context, id int, req *models.Updat
 	}
 
 	var seller models.Seller
-	err = r.db.QueryRow(ctx, query, args...).Scan(
-		&seller.ID,
-		&seller.UserID,
-		&seller.ShopName,
-		&seller.Description,
-		&seller.Rating,
-		&seller.IsActive,
-		&seller.CreatedAt,
-		&seller.UpdatedAt,
-	)
-
-	if err != nil {
+	if err := pgxscan.Get(ctx, r.db, &seller, query, args...); err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to update seller")
 		return nil, fmt.Errorf("failed to update seller: %w", err)
 	}
@@ -159,34 +180,280 @@ func (r *SellerRepository) UpdateStatus(ctx context.Context, id int, isActive bo
 	return nil
 }
 
-func (r *SellerRepository) GetAll(ctx context.Context) ([]*models.Seller, error) {
-	query := `SELECT id, user_id, shop_name, COALESCE(description, '') as description, rating::float8 as rating, is_active, created_at, updated_at FROM sellers ORDER BY created_at DESC`
+// SetCancellationWindow sets or, with nil minutes, clears a seller's own
+// buyer cancellation window.
+func (r *SellerRepository) SetCancellationWindow(ctx context.Context, id int, minutes *int) (*models.Seller, error) {
+	query, args, err := psql.Update("sellers").
+		Set("cancellation_window_minutes", minutes).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id}).
+		Suffix(returning(sellerColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build update cancellation window query")
+		return nil, fmt.Errorf("failed to build update cancellation window query: %w", err)
+	}
+
+	var seller models.Seller
+	if err := pgxscan.Get(ctx, r.db, &seller, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.SellerNotFound(id)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to update cancellation window")
+		return nil, fmt.Errorf("failed to update cancellation window: %w", err)
+	}
+	return &seller, nil
+}
+
+// SetProbation puts a seller on probation or, with zero orders, takes them
+// off it. Listings pick the change up on the next refresh of the
+// product_listing view.
+func (r *SellerRepository) SetProbation(ctx context.Context, id int, req *models.SetSellerProbationRequest) (*models.Seller, error) {
+	orders, traffic := probationColumns(*req)
+	query, args, err := psql.Update("sellers").
+		Set("probation_orders", orders).
+		Set("probation_traffic_percent", traffic).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id}).
+		Suffix(returning(sellerColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build update probation query")
+		return nil, fmt.Errorf("failed to build update probation query: %w", err)
+	}
+
+	var seller models.Seller
+	if err := pgxscan.Get(ctx, r.db, &seller, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.SellerNotFound(id)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to update probation")
+		return nil, fmt.Errorf("failed to update probation: %w", err)
+	}
+	return &seller, nil
+}
+
+// SetTrusted marks the seller as trusted, or takes the mark away.
+func (r *SellerRepository) SetTrusted(ctx context.Context, id int, trusted bool) (*models.Seller, error) {
+	query, args, err := psql.Update("sellers").
+		Set("trusted", trusted).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id}).
+		Suffix(returning(sellerColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build update trusted query")
+		return nil, fmt.Errorf("failed to build update trusted query: %w", err)
+	}
+
+	var seller models.Seller
+	if err := pgxscan.Get(ctx, r.db, &seller, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.SellerNotFound(id)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to update trusted")
+		return nil, fmt.Errorf("failed to update trusted: %w", err)
+	}
+	return &seller, nil
+}
+
+// probationColumns returns the probation_orders and
+// probation_traffic_percent values of a probation, both NULL without one.
+func probationColumns(probation models.SetSellerProbationRequest) (*int, *int) {
+	if probation.Orders <= 0 {
+		return nil, nil
+	}
+	return &probation.Orders, &probation.TrafficPercent
+}
+
+// SetCustomDomain maps domain to the seller's storefront or, when empty,
+// removes the seller's domain. Storefront settings saved for the old domain
+// move with it, or are deleted with it. Domains taken by another seller or
+// with storefront settings of their own are a conflict.
+func (r *SellerRepository) SetCustomDomain(ctx context.Context, id int, domain string) (*models.Seller, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var old string
+	err = tx.QueryRow(ctx, `SELECT COALESCE(custom_domain, '') FROM sellers WHERE id = $1 FOR UPDATE`, id).Scan(&old)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.SellerNotFound(id)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get seller domain")
+		return nil, fmt.Errorf("failed to get seller domain: %w", err)
+	}
+
+	if domain != "" && domain != old {
+		var taken bool
+		err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM storefront_settings WHERE tenant = $1)`, domain).Scan(&taken)
+		if err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to check storefront settings")
+			return nil, fmt.Errorf("failed to check storefront settings: %w", err)
+		}
+		if taken {
+			return nil, apperrors.Conflict(fmt.Sprintf("domain %s is already in use", domain))
+		}
+	}
+
+	query, args, err := psql.Update("sellers").
+		Set("custom_domain", sq.Expr("NULLIF(?, '')", domain)).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id}).
+		Suffix(returning(sellerColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build update seller domain query")
+		return nil, fmt.Errorf("failed to build update seller domain query: %w", err)
+	}
+
+	var seller models.Seller
+	if err := pgxscan.Get(ctx, tx, &seller, query, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, apperrors.Conflict(fmt.Sprintf("domain %s is already in use", domain))
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to update seller domain")
+		return nil, fmt.Errorf("failed to update seller domain: %w", err)
+	}
+
+	if old != "" && old != domain {
+		stmt := `DELETE FROM storefront_settings WHERE tenant = $1`
+		args := []interface{}{old}
+		if domain != "" {
+			stmt = `UPDATE storefront_settings SET tenant = $2 WHERE tenant = $1`
+			args = append(args, domain)
+		}
+		if _, err := tx.Exec(ctx, stmt, args...); err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to move storefront settings")
+			return nil, fmt.Errorf("failed to move storefront settings: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &seller, nil
+}
+
+// GetIDByDomain returns the ID of the active seller whose storefront is
+// served on domain, or 0 when there is none.
+func (r *SellerRepository) GetIDByDomain(ctx context.Context, domain string) (int, error) {
+	var id int
+	err := r.db.QueryRow(ctx, `SELECT id FROM sellers WHERE custom_domain = $1 AND is_active = true`, domain).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get seller by domain")
+		return 0, fmt.Errorf("failed to get seller by domain: %w", err)
+	}
+	return id, nil
+}
 
-	rows, err := r.db.Query(ctx, query)
+func (r *SellerRepository) GetAll(ctx context.Context) ([]*models.Seller, error) {
+	query, args, err := psql.Select(sellerColumns...).
+		From("sellers").
+		OrderBy("created_at DESC").
+		ToSql()
 	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build select sellers query")
+		return nil, fmt.Errorf("failed to build select sellers query: %w", err)
+	}
+
+	var sellers []*models.Seller
+	if err := pgxscan.Select(ctx, r.db, &sellers, query, args...); err != nil {
 		logger.GetLogger().WithField("err", err).Error("failed to get sellers")
 		return nil, fmt.Errorf("failed to get sellers: %w", err)
 	}
-	defer rows.Close()
 
-	var sellers []*models.Seller
-	for rows.Next() {
-		var seller models.Seller
-		if err := rows.Scan(
-			&seller.ID,
-			&seller.UserID,
-			&seller.ShopName,
-			&seller.Description,
-			&seller.Rating,
-			&seller.IsActive,
-			&seller.CreatedAt,
-			&seller.UpdatedAt,
-		); err != nil {
-			logger.GetLogger().WithField("err", err).Error("failed to scan seller")
-			return nil, fmt.Errorf("failed to scan seller: %w", err)
+	return sellers, nil
+}
+
+// GetOnboardingState reports which onboarding steps the seller of userID
+// has completed. A user without a seller profile has completed none.
+func (r *SellerRepository) GetOnboardingState(ctx context.Context, userID int) (*models.SellerOnboardingState, error) {
+	query, args, err := psql.Select(
+		"COALESCE(s.shop_name, '') <> '' AND COALESCE(s.description, '') <> '' AS has_profile",
+		"COALESCE(s.is_active, false) AS verified",
+		"EXISTS (SELECT 1 FROM products p WHERE p.seller_id = s.id AND p.status <> 'deleted') AS has_product",
+		"(COALESCE(s.payout_details, '') <> '' OR EXISTS (SELECT 1 FROM seller_payout_accounts a WHERE a.seller_id = s.id)) AS has_payout_details",
+		"COALESCE(s.return_policy, '') <> '' AND COALESCE(s.shipping_policy, '') <> '' AS has_policies",
+	).
+		From("sellers s").
+		Where(sq.Eq{"s.user_id": userID}).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build seller onboarding query")
+		return nil, fmt.Errorf("failed to build seller onboarding query: %w", err)
+	}
+
+	var state models.SellerOnboardingState
+	if err := pgxscan.Get(ctx, r.db, &state, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &models.SellerOnboardingState{}, nil
 		}
-		sellers = append(sellers, &seller)
+		logger.GetLogger().WithField("err", err).Error("failed to get seller onboarding state")
+		return nil, fmt.Errorf("failed to get seller onboarding state: %w", err)
 	}
 
-	return sellers, nil
+	return &state, nil
+}
+
+// sellerInvoicingColumns select a seller_invoicing row into
+// models.SellerInvoicing.
+var sellerInvoicingColumns = []string{
+	"seller_id", "legal_name", "country", "tax_id", "address_line", "city", "postal_code", "updated_at",
+}
+
+// GetInvoicing returns the seller's invoicing details, or a not-found error
+// when none are on file.
+func (r *SellerRepository) GetInvoicing(ctx context.Context, sellerID int) (*models.SellerInvoicing, error) {
+	query, args, err := psql.Select(sellerInvoicingColumns...).
+		From("seller_invoicing").
+		Where(sq.Eq{"seller_id": sellerID}).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build select seller invoicing query")
+		return nil, fmt.Errorf("failed to build select seller invoicing query: %w", err)
+	}
+
+	var invoicing models.SellerInvoicing
+	if err := pgxscan.Get(ctx, r.db, &invoicing, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.NotFound("no invoicing details on file")
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get seller invoicing")
+		return nil, fmt.Errorf("failed to get seller invoicing: %w", err)
+	}
+	return &invoicing, nil
+}
+
+// SetInvoicing creates or replaces the seller's invoicing details. The
+// request is expected to be validated already.
+func (r *SellerRepository) SetInvoicing(ctx context.Context, sellerID int, req *models.UpdateSellerInvoicingRequest) (*models.SellerInvoicing, error) {
+	query, args, err := psql.Insert("seller_invoicing").
+		Columns("seller_id", "legal_name", "country", "tax_id", "address_line", "city", "postal_code").
+		Values(sellerID, req.LegalName, req.Country, req.TaxID, req.AddressLine, req.City, req.PostalCode).
+		Suffix(`ON CONFLICT (seller_id) DO UPDATE SET legal_name = EXCLUDED.legal_name,
+			country = EXCLUDED.country, tax_id = EXCLUDED.tax_id, address_line = EXCLUDED.address_line,
+			city = EXCLUDED.city, postal_code = EXCLUDED.postal_code, updated_at = NOW() ` + returning(sellerInvoicingColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build upsert seller invoicing query")
+		return nil, fmt.Errorf("failed to build upsert seller invoicing query: %w", err)
+	}
+
+	var invoicing models.SellerInvoicing
+	if err := pgxscan.Get(ctx, r.db, &invoicing, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to save seller invoicing")
+		return nil, fmt.Errorf("failed to save seller invoicing: %w", err)
+	}
+	return &invoicing, nil
 }
@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var healthRuleColumns = []string{
+	"id", "metric", "threshold::float8 AS threshold", "action", "min_orders", "enabled", "created_by", "created_at", "updated_at",
+}
+
+var healthActionColumns = []string{
+	"id", "seller_id", "rule_id", "action", "metric", "value::float8 AS value", "threshold::float8 AS threshold", "created_at",
+}
+
+// sellerHealthOrders is one row per seller and order placed within the
+// window, flagging whether the seller cancelled any of its items, shipped
+// any too long after the order (or has yet to, past that limit), and whether
+// the buyer opened a ticket about the order. Its arguments are the shipping
+// limit twice, then the window, all in seconds. Orders the buyer cancelled
+// are left out: they are not the seller's doing.
+const sellerHealthOrders = `SELECT p.seller_id, oi.order_id,
+		bool_or(oi.fulfillment_status = 'cancelled') AS cancelled,
+		bool_or(CASE WHEN oi.shipped_at IS NOT NULL THEN oi.shipped_at > o.created_at + ? * INTERVAL '1 second'
+			ELSE oi.fulfillment_status IN ('pending', 'processing') AND NOW() > o.created_at + ? * INTERVAL '1 second' END) AS late,
+		EXISTS (SELECT 1 FROM support_tickets t WHERE t.order_id = o.id AND t.user_id = o.user_id) AS disputed
+	FROM order_items oi
+	JOIN orders o ON o.id = oi.order_id
+	JOIN products p ON p.id = oi.product_id
+	WHERE o.created_at >= NOW() - ? * INTERVAL '1 second' AND COALESCE(o.status, 'pending') <> 'cancelled'
+	GROUP BY p.seller_id, oi.order_id, o.id`
+
+type SellerHealthRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSellerHealthRepository(db *pgxpool.Pool) *SellerHealthRepository {
+	return &SellerHealthRepository{db: db}
+}
+
+func (r *SellerHealthRepository) counts(ctx context.Context, window, shipWithin time.Duration, where sq.Sqlizer) ([]*models.SellerHealthCounts, error) {
+	shipSeconds := shipWithin.Seconds()
+	query, args, err := psql.Select(
+		"s.id AS seller_id", "s.user_id", "COALESCE(s.is_active, false) AS is_active",
+		"COUNT(h.order_id) AS orders",
+		"COUNT(*) FILTER (WHERE h.cancelled) AS cancelled",
+		"COUNT(*) FILTER (WHERE h.late) AS late",
+		"COUNT(*) FILTER (WHERE h.disputed) AS disputed",
+	).
+		From("sellers s").
+		LeftJoin("("+sellerHealthOrders+") h ON h.seller_id = s.id", shipSeconds, shipSeconds, window.Seconds()).
+		Where(where).
+		GroupBy("s.id").
+		OrderBy("s.id").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build seller health query")
+		return nil, fmt.Errorf("failed to build seller health query: %w", err)
+	}
+
+	counts := []*models.SellerHealthCounts{}
+	if err := pgxscan.Select(ctx, r.db, &counts, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get seller health")
+		return nil, fmt.Errorf("failed to get seller health: %w", err)
+	}
+	return counts, nil
+}
+
+// GetAllCounts returns the health counts of every seller.
+func (r *SellerHealthRepository) GetAllCounts(ctx context.Context, window, shipWithin time.Duration) ([]*models.SellerHealthCounts, error) {
+	return r.counts(ctx, window, shipWithin, sq.And{})
+}
+
+// GetCounts returns the health counts of a seller.
+func (r *SellerHealthRepository) GetCounts(ctx context.Context, sellerID int, window, shipWithin time.Duration) (*models.SellerHealthCounts, error) {
+	counts, err := r.counts(ctx, window, shipWithin, sq.Eq{"s.id": sellerID})
+	if err != nil {
+		return nil, err
+	}
+	if len(counts) == 0 {
+		return nil, apperrors.SellerNotFound(sellerID)
+	}
+	return counts[0], nil
+}
+
+// GetCountsByUser returns the health counts of the seller owned by a user.
+func (r *SellerHealthRepository) GetCountsByUser(ctx context.Context, userID int, window, shipWithin time.Duration) (*models.SellerHealthCounts, error) {
+	counts, err := r.counts(ctx, window, shipWithin, sq.Eq{"s.user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+	if len(counts) == 0 {
+		return nil, apperrors.NotFound("seller profile not found")
+	}
+	return counts[0], nil
+}
+
+// GetRules returns every rule, oldest first.
+func (r *SellerHealthRepository) GetRules(ctx context.Context) ([]*models.SellerHealthRule, error) {
+	query, args, err := psql.Select(healthRuleColumns...).From("seller_health_rules").OrderBy("id").ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build seller health rules query")
+		return nil, fmt.Errorf("failed to build seller health rules query: %w", err)
+	}
+
+	rules := []*models.SellerHealthRule{}
+	if err := pgxscan.Select(ctx, r.db, &rules, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get seller health rules")
+		return nil, fmt.Errorf("failed to get seller health rules: %w", err)
+	}
+	return rules, nil
+}
+
+func ruleMinOrders(req *models.SellerHealthRuleRequest) int {
+	if req.MinOrders != nil {
+		return *req.MinOrders
+	}
+	return 10
+}
+
+func ruleEnabled(req *models.SellerHealthRuleRequest) bool {
+	return req.Enabled == nil || *req.Enabled
+}
+
+// CreateRule adds a rule. Rules are enabled and need 10 orders unless the
+// request says otherwise.
+func (r *SellerHealthRepository) CreateRule(ctx context.Context, adminID int, req *models.SellerHealthRuleRequest) (*models.SellerHealthRule, error) {
+	query, args, err := psql.Insert("seller_health_rules").
+		Columns("metric", "threshold", "action", "min_orders", "enabled", "created_by").
+		Values(req.Metric, *req.Threshold, req.Action, ruleMinOrders(req), ruleEnabled(req), adminID).
+		Suffix(returning(healthRuleColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build insert seller health rule query")
+		return nil, fmt.Errorf("failed to build insert seller health rule query: %w", err)
+	}
+
+	var rule models.SellerHealthRule
+	if err := pgxscan.Get(ctx, r.db, &rule, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to create seller health rule")
+		return nil, fmt.Errorf("failed to create seller health rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// UpdateRule replaces a rule.
+func (r *SellerHealthRepository) UpdateRule(ctx context.Context, id int, req *models.SellerHealthRuleRequest) (*models.SellerHealthRule, error) {
+	query, args, err := psql.Update("seller_health_rules").
+		Set("metric", req.Metric).
+		Set("threshold", *req.Threshold).
+		Set("action", req.Action).
+		Set("min_orders", ruleMinOrders(req)).
+		Set("enabled", ruleEnabled(req)).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id}).
+		Suffix(returning(healthRuleColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build update seller health rule query")
+		return nil, fmt.Errorf("failed to build update seller health rule query: %w", err)
+	}
+
+	var rule models.SellerHealthRule
+	if err := pgxscan.Get(ctx, r.db, &rule, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.NotFound("seller health rule not found")
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to update seller health rule")
+		return nil, fmt.Errorf("failed to update seller health rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// DeleteRule removes a rule. Actions it took stay on record.
+func (r *SellerHealthRepository) DeleteRule(ctx context.Context, id int) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM seller_health_rules WHERE id = $1`, id)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to delete seller health rule")
+		return fmt.Errorf("failed to delete seller health rule: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperrors.NotFound("seller health rule not found")
+	}
+	return nil
+}
+
+// GetActions lists the actions taken, newest first, optionally for one
+// seller.
+func (r *SellerHealthRepository) GetActions(ctx context.Context, sellerID *int, pagination *models.PaginationParams) ([]*models.SellerHealthAction, int64, error) {
+	where := sq.And{}
+	if sellerID != nil {
+		where = append(where, sq.Eq{"seller_id": *sellerID})
+	}
+
+	totalItems, err := countRows(ctx, r.db, "seller_health_actions", where, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+	if totalItems == 0 && !pagination.EstimateCount() {
+		return []*models.SellerHealthAction{}, 0, nil
+	}
+
+	query, args, err := psql.Select(healthActionColumns...).
+		From("seller_health_actions").
+		Where(where).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(pagination.GetLimit())).
+		Offset(uint64(pagination.GetOffset())).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build seller health actions query")
+		return nil, 0, fmt.Errorf("failed to build seller health actions query: %w", err)
+	}
+
+	actions := []*models.SellerHealthAction{}
+	if err := pgxscan.Select(ctx, r.db, &actions, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get seller health actions")
+		return nil, 0, fmt.Errorf("failed to get seller health actions: %w", err)
+	}
+	return actions, totalItems, nil
+}
+
+// GetRecentActions returns the actions taken within the last period.
+func (r *SellerHealthRepository) GetRecentActions(ctx context.Context, period time.Duration) ([]*models.SellerHealthAction, error) {
+	query, args, err := psql.Select(healthActionColumns...).
+		From("seller_health_actions").
+		Where("created_at >= NOW() - ? * INTERVAL '1 second'", period.Seconds()).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build seller health actions query")
+		return nil, fmt.Errorf("failed to build seller health actions query: %w", err)
+	}
+
+	actions := []*models.SellerHealthAction{}
+	if err := pgxscan.Select(ctx, r.db, &actions, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get seller health actions")
+		return nil, fmt.Errorf("failed to get seller health actions: %w", err)
+	}
+	return actions, nil
+}
+
+// ApplyAction records an action, deactivates the seller when it is a
+// suspension and notifies the seller, all in one transaction.
+func (r *SellerHealthRepository) ApplyAction(ctx context.Context, sellerUserID int, action *models.SellerHealthAction, message string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO seller_health_actions (seller_id, rule_id, action, metric, value, threshold) VALUES ($1, $2, $3, $4, $5, $6)`,
+		action.SellerID, action.RuleID, action.Action, action.Metric, action.Value, action.Threshold)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to record seller health action")
+		return fmt.Errorf("failed to record seller health action: %w", err)
+	}
+
+	if action.Action == models.HealthActionSuspend {
+		if _, err := tx.Exec(ctx, `UPDATE sellers SET is_active = false, updated_at = NOW() WHERE id = $1`, action.SellerID); err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to suspend seller")
+			return fmt.Errorf("failed to suspend seller: %w", err)
+		}
+	}
+
+	if err := notify(ctx, tx, sellerUserID, "seller_health", message); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,327 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/money"
+	"github.com/Zifeldev/marketback/service/Market/internal/readonly"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+)
+
+// sellerOwnsItem matches order items (aliased oi) of products sold by the
+// seller owned by a user.
+const sellerOwnsItem = `EXISTS (SELECT 1 FROM products p JOIN sellers s ON s.id = p.seller_id
+	WHERE p.id = oi.product_id AND s.user_id = ?)`
+
+// hasSellerItems matches orders with at least one item of the seller owned
+// by sellerUserID, optionally in fulfillmentStatus.
+func hasSellerItems(sellerUserID int, fulfillmentStatus string) (sq.Sqlizer, error) {
+	items := sq.And{sq.Expr("oi.order_id = orders.id"), sq.Expr(sellerOwnsItem, sellerUserID)}
+	if fulfillmentStatus != "" {
+		items = append(items, sq.Eq{"oi.fulfillment_status": fulfillmentStatus})
+	}
+	itemsSQL, itemsArgs, err := items.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	return sq.Expr("EXISTS (SELECT 1 FROM order_items oi WHERE "+itemsSQL+")", itemsArgs...), nil
+}
+
+// GetSellerOrders returns one page of the orders containing the seller's
+// items, newest first. Each order carries only the seller's items.
+func (r *OrderRepository) GetSellerOrders(ctx context.Context, sellerUserID int, fulfillmentStatus string, pagination *models.PaginationParams) ([]*models.SellerOrder, int64, error) {
+	owned, err := hasSellerItems(sellerUserID, fulfillmentStatus)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build seller orders filter")
+		return nil, 0, fmt.Errorf("failed to build seller orders filter: %w", err)
+	}
+	where := sq.And{owned}
+
+	totalItems, err := countRows(ctx, r.db, "orders", where, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+	if totalItems == 0 && !pagination.EstimateCount() {
+		return []*models.SellerOrder{}, 0, nil
+	}
+
+	query, args, err := psql.Select(orderColumns...).
+		From("orders").
+		Where(where).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(pagination.GetLimit())).
+		Offset(uint64(pagination.GetOffset())).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build seller orders query")
+		return nil, 0, fmt.Errorf("failed to build seller orders query: %w", err)
+	}
+
+	orders := []*models.SellerOrder{}
+	if err := pgxscan.Select(ctx, r.db, &orders, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get seller orders")
+		return nil, 0, fmt.Errorf("failed to get seller orders: %w", err)
+	}
+	for _, order := range orders {
+		if err := r.openAddress(&order.Order); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if err := r.attachSellerItems(ctx, sellerUserID, orders); err != nil {
+		return nil, 0, err
+	}
+	return orders, totalItems, nil
+}
+
+// GetSellerOrder returns an order with the seller's items. Orders without
+// any are reported as not found, so sellers cannot probe for other orders.
+func (r *OrderRepository) GetSellerOrder(ctx context.Context, orderID, sellerUserID int) (*models.SellerOrder, error) {
+	owned, err := hasSellerItems(sellerUserID, "")
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build seller orders filter")
+		return nil, fmt.Errorf("failed to build seller orders filter: %w", err)
+	}
+
+	query, args, err := psql.Select(orderColumns...).
+		From("orders").
+		Where(sq.Eq{"id": orderID}).
+		Where(owned).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build seller order query")
+		return nil, fmt.Errorf("failed to build seller order query: %w", err)
+	}
+
+	order := &models.SellerOrder{}
+	if err := pgxscan.Get(ctx, r.db, &order.Order, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.OrderNotFound(orderID)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get seller order")
+		return nil, fmt.Errorf("failed to get seller order: %w", err)
+	}
+	if err := r.openAddress(&order.Order); err != nil {
+		return nil, err
+	}
+
+	if err := r.attachSellerItems(ctx, sellerUserID, []*models.SellerOrder{order}); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// GetPackingSlip returns the packing slip of the seller's items of a gift
+// order, leaving out cancelled items.
+func (r *OrderRepository) GetPackingSlip(ctx context.Context, orderID, sellerUserID int) (*models.PackingSlip, error) {
+	order, err := r.GetSellerOrder(ctx, orderID, sellerUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.IsGift {
+		return nil, apperrors.Conflict("packing slips are only made for gift orders")
+	}
+
+	slip := &models.PackingSlip{
+		OrderNumber:  order.OrderNumber,
+		OrderedAt:    order.CreatedAt,
+		DeliveryAddr: order.DeliveryAddr,
+		GiftMessage:  order.GiftMessage,
+	}
+	if err := r.db.QueryRow(ctx, `SELECT shop_name FROM sellers WHERE user_id = $1`, sellerUserID).Scan(&slip.ShopName); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get shop name")
+		return nil, fmt.Errorf("failed to get shop name: %w", err)
+	}
+
+	query, args, err := psql.Select("p.title", "COALESCE(oi.size, '') AS size", "COALESCE(oi.color, '') AS color", "oi.quantity").
+		From("order_items oi").
+		Join("products p ON p.id = oi.product_id").
+		Where(sq.Eq{"oi.order_id": orderID}).
+		Where(sq.NotEq{"oi.fulfillment_status": models.FulfillmentCancelled}).
+		Where(sellerOwnsItem, sellerUserID).
+		OrderBy("oi.id").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build packing slip items query")
+		return nil, fmt.Errorf("failed to build packing slip items query: %w", err)
+	}
+
+	slip.Items = []models.PackingSlipItem{}
+	if err := pgxscan.Select(ctx, r.db, &slip.Items, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get packing slip items")
+		return nil, fmt.Errorf("failed to get packing slip items: %w", err)
+	}
+	return slip, nil
+}
+
+// attachSellerItems loads the seller's items of all orders in one query and
+// totals them per order.
+func (r *OrderRepository) attachSellerItems(ctx context.Context, sellerUserID int, orders []*models.SellerOrder) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*models.SellerOrder, len(orders))
+	ids := make([]int, 0, len(orders))
+	for _, order := range orders {
+		order.Items = []models.OrderItem{}
+		byID[order.ID] = order
+		ids = append(ids, order.ID)
+	}
+
+	query, args, err := psql.Select(orderItemColumns...).
+		From("order_items oi").
+		Where("oi.order_id = ANY(?)", ids).
+		Where(sellerOwnsItem, sellerUserID).
+		OrderBy("oi.order_id", "oi.id").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build seller order items query")
+		return fmt.Errorf("failed to build seller order items query: %w", err)
+	}
+
+	var items []models.OrderItem
+	if err := pgxscan.Select(ctx, r.db, &items, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get seller order items")
+		return fmt.Errorf("failed to get seller order items: %w", err)
+	}
+
+	totals := make(map[int]money.Cents, len(orders))
+	for _, item := range items {
+		order, ok := byID[item.OrderID]
+		if !ok {
+			continue
+		}
+		order.Items = append(order.Items, item)
+		line, err := money.LineTotal(money.FromFloat(item.Price), item.Quantity)
+		if err != nil {
+			return fmt.Errorf("order item %d: %w", item.ID, err)
+		}
+		if item.DiscountFundedBy == models.PromoFundedBySeller {
+			line -= money.FromFloat(item.DiscountAmount)
+		}
+		totals[item.OrderID] += line
+	}
+	for id, total := range totals {
+		byID[id].SellerTotal = total.Float()
+	}
+	return nil
+}
+
+// UpdateFulfillment moves the seller's items of an order to req.Status.
+// Listed items must all be the seller's and able to move; without a list,
+// items that cannot move are left as they are. Cancelled orders cannot be
+// fulfilled.
+func (r *OrderRepository) UpdateFulfillment(ctx context.Context, orderID, sellerUserID int, req *models.UpdateFulfillmentRequest) (*models.SellerOrder, error) {
+	if err := r.readOnly.Check(readonly.TableOrders); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Holding the order keeps it from being cancelled while its items move.
+	var orderStatus string
+	err = tx.QueryRow(ctx, `SELECT COALESCE(status, 'pending') FROM orders WHERE id = $1 FOR SHARE`, orderID).Scan(&orderStatus)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.OrderNotFound(orderID)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to lock order")
+		return nil, fmt.Errorf("failed to lock order: %w", err)
+	}
+
+	query, args, err := psql.Select("oi.id", "oi.fulfillment_status").
+		From("order_items oi").
+		Where(sq.Eq{"oi.order_id": orderID}).
+		Where(sellerOwnsItem, sellerUserID).
+		Suffix("FOR UPDATE OF oi").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build seller order items query")
+		return nil, fmt.Errorf("failed to build seller order items query: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to lock seller order items")
+		return nil, fmt.Errorf("failed to lock seller order items: %w", err)
+	}
+	current := map[int]string{}
+	var ids []int
+	for rows.Next() {
+		var id int
+		var status string
+		if err := rows.Scan(&id, &status); err != nil {
+			rows.Close()
+			logger.GetLogger().WithField("err", err).Error("failed to scan seller order item")
+			return nil, fmt.Errorf("failed to scan seller order item: %w", err)
+		}
+		current[id] = status
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to lock seller order items")
+		return nil, fmt.Errorf("failed to lock seller order items: %w", err)
+	}
+	if len(current) == 0 {
+		return nil, apperrors.OrderNotFound(orderID)
+	}
+	if orderStatus == "cancelled" {
+		return nil, apperrors.Conflict("cancelled orders cannot be fulfilled")
+	}
+
+	explicit := len(req.ItemIDs) > 0
+	if explicit {
+		ids = req.ItemIDs
+	}
+	var move []int
+	settled := 0
+	for _, id := range ids {
+		from, ok := current[id]
+		switch {
+		case !ok:
+			return nil, apperrors.NotFound(fmt.Sprintf("order item %d not found", id))
+		case from == req.Status:
+			settled++
+		case models.CanFulfill(from, req.Status):
+			move = append(move, id)
+		case explicit:
+			return nil, apperrors.Conflict(fmt.Sprintf("order item %d cannot move from %s to %s", id, from, req.Status))
+		}
+	}
+	if len(move) == 0 && settled == 0 {
+		return nil, apperrors.Conflict(fmt.Sprintf("no items can move to %s", req.Status))
+	}
+
+	if len(move) > 0 {
+		_, err = tx.Exec(ctx,
+			`UPDATE order_items SET fulfillment_status = $1, fulfillment_updated_at = NOW(),
+				shipped_at = CASE WHEN $1 = 'shipped' THEN NOW() ELSE shipped_at END
+			WHERE id = ANY($2)`,
+			req.Status, move)
+		if err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to update fulfillment status")
+			return nil, fmt.Errorf("failed to update fulfillment status: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetSellerOrder(ctx, orderID, sellerUserID)
+}
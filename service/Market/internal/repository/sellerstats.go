@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// sellerSale is what an order item earned its seller: what it sold for less
+// the discount the seller funded.
+const sellerSale = `oi.price * oi.quantity - ` + sellerDiscount
+
+// sellerSales are the order items of the seller owned by the user $1 in
+// orders placed from $2 until $3 that were neither cancelled nor refunded.
+const sellerSales = `SELECT o.id AS order_id, o.created_at, oi.product_id, oi.quantity, ` + sellerSale + ` AS amount
+	FROM order_items oi
+	JOIN orders o ON o.id = oi.order_id
+	JOIN products p ON p.id = oi.product_id
+	JOIN sellers s ON s.id = p.seller_id
+	WHERE s.user_id = $1 AND o.created_at >= $2::timestamp AND o.created_at < $3::timestamp
+	AND COALESCE(o.status, 'pending') <> 'cancelled'
+	AND COALESCE(o.payment_status, '') <> 'refunded'`
+
+// lowStockQuery lists the active products and variants of the seller owned
+// by the user $1 with at most their product's low-stock threshold, $2 for
+// products without one, in stock. Products with variants are stocked by
+// variant.
+const lowStockQuery = `SELECT p.id AS product_id, NULL::int AS variant_id, p.title, '' AS sku, p.stock,
+		COALESCE(p.low_stock_threshold, $2) AS threshold
+	FROM products p
+	JOIN sellers s ON s.id = p.seller_id
+	WHERE s.user_id = $1 AND p.status = 'active' AND p.stock <= COALESCE(p.low_stock_threshold, $2)
+	AND NOT EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = p.id)
+	UNION ALL
+	SELECT p.id, v.id, p.title, v.sku, v.stock, COALESCE(p.low_stock_threshold, $2)
+	FROM product_variants v
+	JOIN products p ON p.id = v.product_id
+	JOIN sellers s ON s.id = p.seller_id
+	WHERE s.user_id = $1 AND p.status = 'active' AND v.stock <= COALESCE(p.low_stock_threshold, $2)
+	ORDER BY stock, product_id, variant_id`
+
+type SellerStatsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSellerStatsRepository(db *pgxpool.Pool) *SellerStatsRepository {
+	return &SellerStatsRepository{db: db}
+}
+
+// AddViews adds views to the counts of products on a day. Views of
+// products deleted since are dropped.
+func (r *SellerStatsRepository) AddViews(ctx context.Context, day time.Time, views map[int]int64) error {
+	productIDs := make([]int, 0, len(views))
+	counts := make([]int64, 0, len(views))
+	for productID, n := range views {
+		productIDs = append(productIDs, productID)
+		counts = append(counts, n)
+	}
+
+	_, err := r.db.Exec(ctx, `INSERT INTO product_views (product_id, day, views)
+		SELECT v.product_id, $1::date, v.views
+		FROM unnest($2::int[], $3::bigint[]) AS v(product_id, views)
+		JOIN products p ON p.id = v.product_id
+		ON CONFLICT (product_id, day) DO UPDATE SET views = product_views.views + EXCLUDED.views`,
+		day, productIDs, counts)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to add product views")
+		return fmt.Errorf("failed to add product views: %w", err)
+	}
+	return nil
+}
+
+// GetSellerStats reports the sales of the seller owned by the user in the
+// days from from until to, excluded: revenue per interval bucket, every
+// product sold or viewed in the range with its conversion, and the active
+// products and variants with at most lowStock in stock.
+func (r *SellerStatsRepository) GetSellerStats(ctx context.Context, userID int, from, to time.Time, interval string, lowStock int) (*models.SellerStats, error) {
+	stats := &models.SellerStats{
+		Interval:          interval,
+		Revenue:           []*models.RevenuePoint{},
+		Products:          []*models.ProductStats{},
+		LowStock:          []*models.LowStockAlert{},
+		LowStockThreshold: lowStock,
+	}
+
+	// Buckets without sales are reported as zero so the series is
+	// continuous.
+	revenueQuery := `WITH sales AS (` + sellerSales + `)
+		SELECT to_char(b.start, 'YYYY-MM-DD') AS period,
+			COUNT(DISTINCT sales.order_id) AS orders,
+			COALESCE(SUM(sales.quantity), 0) AS units,
+			COALESCE(SUM(sales.amount), 0)::float8 AS revenue
+		FROM generate_series(date_trunc($4::text, $2::timestamp), $3::timestamp - interval '1 day', ('1 ' || $4::text)::interval) AS b(start)
+		LEFT JOIN sales ON date_trunc($4::text, sales.created_at) = b.start
+		GROUP BY b.start
+		ORDER BY b.start`
+	if err := pgxscan.Select(ctx, r.db, &stats.Revenue, revenueQuery, userID, from, to, interval); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get seller revenue")
+		return nil, fmt.Errorf("failed to get seller revenue: %w", err)
+	}
+
+	productsQuery := `WITH sales AS (` + sellerSales + `), sold AS (
+			SELECT product_id, COUNT(DISTINCT order_id) AS orders, SUM(quantity) AS units, SUM(amount) AS revenue
+			FROM sales
+			GROUP BY product_id
+		), viewed AS (
+			SELECT pv.product_id, SUM(pv.views) AS views
+			FROM product_views pv
+			JOIN products p ON p.id = pv.product_id
+			JOIN sellers s ON s.id = p.seller_id
+			WHERE s.user_id = $1 AND pv.day >= $2::timestamp AND pv.day < $3::timestamp
+			GROUP BY pv.product_id
+		)
+		SELECT p.id AS product_id, p.title,
+			COALESCE(sold.units, 0) AS units_sold,
+			COALESCE(sold.orders, 0) AS orders,
+			COALESCE(sold.revenue, 0)::float8 AS revenue,
+			COALESCE(viewed.views, 0)::bigint AS views,
+			CASE WHEN COALESCE(viewed.views, 0) > 0
+				THEN ROUND(COALESCE(sold.orders, 0)::numeric / viewed.views, 4)::float8 ELSE 0 END AS conversion
+		FROM products p
+		LEFT JOIN sold ON sold.product_id = p.id
+		LEFT JOIN viewed ON viewed.product_id = p.id
+		WHERE sold.product_id IS NOT NULL OR viewed.product_id IS NOT NULL
+		ORDER BY units_sold DESC, views DESC, p.id`
+	if err := pgxscan.Select(ctx, r.db, &stats.Products, productsQuery, userID, from, to); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get seller product stats")
+		return nil, fmt.Errorf("failed to get seller product stats: %w", err)
+	}
+
+	if err := pgxscan.Select(ctx, r.db, &stats.LowStock, lowStockQuery, userID, lowStock); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get low stock products")
+		return nil, fmt.Errorf("failed to get low stock products: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetLowStock lists the active products and variants of the seller owned by
+// the user that are at or below their product's low-stock threshold, or
+// threshold for products without one, emptiest first.
+func (r *SellerStatsRepository) GetLowStock(ctx context.Context, userID, threshold int) ([]*models.LowStockAlert, error) {
+	alerts := []*models.LowStockAlert{}
+	if err := pgxscan.Select(ctx, r.db, &alerts, lowStockQuery, userID, threshold); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get low stock products")
+		return nil, fmt.Errorf("failed to get low stock products: %w", err)
+	}
+	return alerts, nil
+}
+
+// lowStockProduct is an active product that ran low on stock since its
+// seller was last alerted.
+type lowStockProduct struct {
+	ID        int    `db:"id"`
+	UserID    int    `db:"user_id"`
+	Title     string `db:"title"`
+	Stock     int    `db:"stock"`
+	Threshold int    `db:"threshold"`
+}
+
+// AlertLowStock notifies the sellers of up to limit active products whose
+// stock dropped to their low-stock threshold, or threshold for products
+// without one, and returns how many it alerted. A product is alerted once
+// until it is restocked above its threshold. Products locked by another
+// run are left to it.
+func (r *SellerStatsRepository) AlertLowStock(ctx context.Context, threshold, limit int) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `UPDATE products SET low_stock_alerted_at = NULL
+		WHERE low_stock_alerted_at IS NOT NULL AND stock > COALESCE(low_stock_threshold, $1)`, threshold)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to reset restocked products")
+		return 0, fmt.Errorf("failed to reset restocked products: %w", err)
+	}
+
+	var products []*lowStockProduct
+	err = pgxscan.Select(ctx, tx, &products, `SELECT p.id, s.user_id, p.title, p.stock,
+			COALESCE(p.low_stock_threshold, $1) AS threshold
+		FROM products p
+		JOIN sellers s ON s.id = p.seller_id
+		WHERE p.status = 'active' AND p.low_stock_alerted_at IS NULL
+		AND p.stock <= COALESCE(p.low_stock_threshold, $1)
+		ORDER BY p.id LIMIT $2
+		FOR UPDATE OF p SKIP LOCKED`, threshold, limit)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get low stock products")
+		return 0, fmt.Errorf("failed to get low stock products: %w", err)
+	}
+	if len(products) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]int, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+		message := fmt.Sprintf("%s is low on stock: %d left (threshold %d)", p.Title, p.Stock, p.Threshold)
+		if err := notify(ctx, tx, p.UserID, "low_stock", message); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := tx.Exec(ctx, `UPDATE products SET low_stock_alerted_at = NOW() WHERE id = ANY($1)`, ids); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to mark low stock products alerted")
+		return 0, fmt.Errorf("failed to mark low stock products alerted: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return len(products), nil
+}
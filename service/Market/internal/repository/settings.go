@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var settingColumns = []string{"key", "value", "updated_by", "updated_at"}
+
+type SettingsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSettingsRepository(db *pgxpool.Pool) *SettingsRepository {
+	return &SettingsRepository{db: db}
+}
+
+func (r *SettingsRepository) GetAll(ctx context.Context) ([]*models.Setting, error) {
+	query, args, err := psql.Select(settingColumns...).From("settings").OrderBy("key").ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build settings query")
+		return nil, fmt.Errorf("failed to build settings query: %w", err)
+	}
+
+	settings := []*models.Setting{}
+	if err := pgxscan.Select(ctx, r.db, &settings, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get settings")
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// Set stores the value of a key, replacing any previous override.
+func (r *SettingsRepository) Set(ctx context.Context, key, value string, adminID int) (*models.Setting, error) {
+	query, args, err := psql.Insert("settings").
+		Columns("key", "value", "updated_by", "updated_at").
+		Values(key, value, adminID, sq.Expr("NOW()")).
+		Suffix("ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at " + returning(settingColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build upsert setting query")
+		return nil, fmt.Errorf("failed to build upsert setting query: %w", err)
+	}
+
+	var setting models.Setting
+	if err := pgxscan.Get(ctx, r.db, &setting, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to save setting")
+		return nil, fmt.Errorf("failed to save setting: %w", err)
+	}
+
+	return &setting, nil
+}
+
+// Delete removes the override of a key so its default applies again.
+func (r *SettingsRepository) Delete(ctx context.Context, key string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM settings WHERE key = $1`, key)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to delete setting")
+		return fmt.Errorf("failed to delete setting: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperrors.NotFound(fmt.Sprintf("setting %s is not overridden", key))
+	}
+
+	return nil
+}
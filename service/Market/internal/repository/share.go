@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// shareLinkColumns select a share_links row into models.ShareLink.
+var shareLinkColumns = []string{"id", "code", "product_id", "source", "clicks", "last_clicked_at", "created_at"}
+
+const (
+	shareCodeAlphabet = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	shareCodeLength   = 8
+	// shareCodeAttempts bounds retries on the (unlikely) code collision.
+	shareCodeAttempts = 3
+)
+
+type ShareLinkRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewShareLinkRepository(db *pgxpool.Pool) *ShareLinkRepository {
+	return &ShareLinkRepository{db: db}
+}
+
+func newShareCode() (string, error) {
+	code := make([]byte, shareCodeLength)
+	max := big.NewInt(int64(len(shareCodeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = shareCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// Create returns the user's link for a product and source, creating it on
+// first share. Only active products can be shared.
+func (r *ShareLinkRepository) Create(ctx context.Context, productID, userID int, source string) (*models.ShareLink, error) {
+	query := `INSERT INTO share_links (code, product_id, created_by, source)
+		SELECT $1, p.id, $3, $4 FROM products p WHERE p.id = $2 AND p.status = 'active'
+		ON CONFLICT (product_id, created_by, source) DO UPDATE SET source = EXCLUDED.source
+		` + returning(shareLinkColumns)
+
+	for attempt := 1; ; attempt++ {
+		code, err := newShareCode()
+		if err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to generate share code")
+			return nil, fmt.Errorf("failed to generate share code: %w", err)
+		}
+
+		var link models.ShareLink
+		err = pgxscan.Get(ctx, r.db, &link, query, code, productID, userID, source)
+		if err == nil {
+			return &link, nil
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.ProductNotFound(productID)
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "share_links_code_key" && attempt < shareCodeAttempts {
+			continue
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to create share link")
+		return nil, fmt.Errorf("failed to create share link: %w", err)
+	}
+}
+
+// Click records a visit through a short link and returns the product it
+// points to.
+func (r *ShareLinkRepository) Click(ctx context.Context, code string) (int, error) {
+	var productID int
+	err := r.db.QueryRow(ctx, `UPDATE share_links SET clicks = clicks + 1, last_clicked_at = NOW()
+		WHERE code = $1 RETURNING product_id`, code).Scan(&productID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, apperrors.NotFound("share link not found")
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to record share link click")
+		return 0, fmt.Errorf("failed to record share link click: %w", err)
+	}
+	return productID, nil
+}
+
+// GetSellerStats sums the share links of the products of the seller owned
+// by the user per product and source, most clicked first.
+func (r *ShareLinkRepository) GetSellerStats(ctx context.Context, userID int) ([]*models.ShareLinkStats, error) {
+	query := `SELECT sl.product_id, p.title AS product_title, sl.source,
+			COUNT(*) AS links, SUM(sl.clicks)::bigint AS clicks, MAX(sl.last_clicked_at) AS last_clicked_at
+		FROM share_links sl
+		JOIN products p ON p.id = sl.product_id
+		JOIN sellers s ON s.id = p.seller_id
+		WHERE s.user_id = $1
+		GROUP BY sl.product_id, p.title, sl.source
+		ORDER BY clicks DESC, sl.product_id, sl.source`
+
+	stats := []*models.ShareLinkStats{}
+	if err := pgxscan.Select(ctx, r.db, &stats, query, userID); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get share link stats")
+		return nil, fmt.Errorf("failed to get share link stats: %w", err)
+	}
+	return stats, nil
+}
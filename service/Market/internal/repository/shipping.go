@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/readonly"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// shippingColumns select a product_shipping_restrictions row into
+// models.ShippingRestrictions.
+var shippingColumns = []string{"product_id", "ships_to", "no_ship_to"}
+
+type ShippingRepository struct {
+	db       *pgxpool.Pool
+	readOnly *readonly.Guard
+}
+
+func NewShippingRepository(db *pgxpool.Pool, guard *readonly.Guard) *ShippingRepository {
+	return &ShippingRepository{db: db, readOnly: guard}
+}
+
+// Get returns the restrictions of a product. Products without a row ship
+// everywhere and get empty lists.
+func (r *ShippingRepository) Get(ctx context.Context, productID int) (*models.ShippingRestrictions, error) {
+	query := `SELECT ` + strings.Join(shippingColumns, ", ") + ` FROM product_shipping_restrictions WHERE product_id = $1`
+
+	res := models.ShippingRestrictions{ProductID: productID, ShipsTo: []string{}, NoShipTo: []string{}}
+	err := pgxscan.Get(ctx, r.db, &res, query, productID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		logger.GetLogger().WithField("err", err).Error("failed to get shipping restrictions")
+		return nil, fmt.Errorf("failed to get shipping restrictions: %w", err)
+	}
+
+	return &res, nil
+}
+
+// GetForProducts returns restrictions keyed by product ID. Unrestricted
+// products are absent from the map.
+func (r *ShippingRepository) GetForProducts(ctx context.Context, productIDs []int) (map[int]*models.ShippingRestrictions, error) {
+	query := `SELECT ` + strings.Join(shippingColumns, ", ") + ` FROM product_shipping_restrictions
+		WHERE product_id = ANY($1) AND (cardinality(ships_to) > 0 OR cardinality(no_ship_to) > 0)`
+
+	var restrictions []*models.ShippingRestrictions
+	if err := pgxscan.Select(ctx, r.db, &restrictions, query, productIDs); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get shipping restrictions")
+		return nil, fmt.Errorf("failed to get shipping restrictions: %w", err)
+	}
+
+	result := make(map[int]*models.ShippingRestrictions, len(restrictions))
+	for _, res := range restrictions {
+		result[res.ProductID] = res
+	}
+
+	return result, nil
+}
+
+// Set replaces the restrictions of a product. Codes must already be
+// normalized.
+func (r *ShippingRepository) Set(ctx context.Context, productID int, shipsTo, noShipTo []string) (*models.ShippingRestrictions, error) {
+	if err := r.readOnly.Check(readonly.TableProducts); err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO product_shipping_restrictions (product_id, ships_to, no_ship_to, updated_at)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
+		ON CONFLICT (product_id) DO UPDATE
+		SET ships_to = EXCLUDED.ships_to, no_ship_to = EXCLUDED.no_ship_to, updated_at = CURRENT_TIMESTAMP
+		` + returning(shippingColumns)
+
+	var res models.ShippingRestrictions
+	err := pgxscan.Get(ctx, r.db, &res, query, productID, shipsTo, noShipTo)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to set shipping restrictions")
+		return nil, fmt.Errorf("failed to set shipping restrictions: %w", err)
+	}
+
+	return &res, nil
+}
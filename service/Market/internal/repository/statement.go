@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// statementColumns select a seller_statements row joined with its seller
+// and the seller's invoicing details into models.SellerStatement.
+var statementColumns = []string{
+	"st.id", "st.seller_id", "s.shop_name",
+	"COALESCE(si.legal_name, '') AS legal_name", "COALESCE(si.tax_id, '') AS tax_id",
+	"COALESCE(si.address_line || ', ' || si.postal_code || ' ' || si.city || ', ' || si.country, '') AS invoice_address",
+	"to_char(st.period, 'YYYY-MM') AS period", "st.orders_count", "st.items_sold",
+	"st.gross_sales::float8 AS gross_sales", "st.discounts::float8 AS discounts",
+	"st.commission::float8 AS commission", "st.refunds::float8 AS refunds",
+	"st.chargebacks::float8 AS chargebacks", "st.payout::float8 AS payout", "st.payout_id", "st.generated_at",
+}
+
+// sellerDiscount is the part of an order item's discount its seller funded.
+const sellerDiscount = `CASE WHEN oi.discount_funded_by = 'seller' THEN oi.discount_amount ELSE 0 END`
+
+type StatementRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewStatementRepository(db *pgxpool.Pool) *StatementRepository {
+	return &StatementRepository{db: db}
+}
+
+// Generate aggregates the order items and payout holds of every seller for
+// the month starting at period and upserts their statements. Commission
+// comes from the amount snapshotted on each order item. Only discounts the
+// seller funded count against them; refunds are what the seller had sold
+// for. Chargebacks are the holds taken in the month less those released in
+// it, and come off the payout. Statements already paid out are kept as
+// they were paid.
+func (r *StatementRepository) Generate(ctx context.Context, period time.Time) (int64, error) {
+	query := `WITH sales AS (
+			SELECT p.seller_id,
+				COUNT(DISTINCT o.id) AS orders_count,
+				SUM(oi.quantity) AS items_sold,
+				SUM(oi.price * oi.quantity) AS gross_sales,
+				SUM(` + sellerDiscount + `) AS discounts,
+				SUM(CASE WHEN o.payment_status = 'refunded' THEN 0 ELSE oi.commission_amount END) AS commission,
+				SUM(CASE WHEN o.payment_status = 'refunded' THEN oi.price * oi.quantity - ` + sellerDiscount + ` ELSE 0 END) AS refunds,
+				SUM(CASE WHEN o.payment_status = 'refunded' THEN 0
+					ELSE oi.price * oi.quantity - ` + sellerDiscount + ` - oi.commission_amount END) AS payout
+			FROM order_items oi
+			JOIN orders o ON o.id = oi.order_id
+			JOIN products p ON p.id = oi.product_id
+			WHERE o.created_at >= $1 AND o.created_at < $2
+			AND COALESCE(o.status, 'pending') <> 'cancelled'
+			GROUP BY p.seller_id
+		), held AS (
+			SELECT seller_id,
+				SUM(CASE WHEN created_at >= $1 AND created_at < $2 THEN amount ELSE 0 END)
+				- SUM(CASE WHEN status = 'released' AND resolved_at >= $1 AND resolved_at < $2 THEN amount ELSE 0 END) AS chargebacks
+			FROM payout_holds
+			WHERE (created_at >= $1 AND created_at < $2) OR (resolved_at >= $1 AND resolved_at < $2)
+			GROUP BY seller_id
+		)
+		INSERT INTO seller_statements
+			(seller_id, period, orders_count, items_sold, gross_sales, discounts, commission, refunds, chargebacks, payout, generated_at)
+		SELECT COALESCE(sales.seller_id, held.seller_id), $1::date,
+			COALESCE(sales.orders_count, 0),
+			COALESCE(sales.items_sold, 0),
+			COALESCE(sales.gross_sales, 0),
+			COALESCE(sales.discounts, 0),
+			COALESCE(sales.commission, 0),
+			COALESCE(sales.refunds, 0),
+			COALESCE(held.chargebacks, 0),
+			COALESCE(sales.payout, 0) - COALESCE(held.chargebacks, 0),
+			NOW()
+		FROM sales
+		FULL JOIN held ON held.seller_id = sales.seller_id
+		ON CONFLICT (seller_id, period) DO UPDATE SET
+			orders_count = EXCLUDED.orders_count,
+			items_sold = EXCLUDED.items_sold,
+			gross_sales = EXCLUDED.gross_sales,
+			discounts = EXCLUDED.discounts,
+			commission = EXCLUDED.commission,
+			refunds = EXCLUDED.refunds,
+			chargebacks = EXCLUDED.chargebacks,
+			payout = EXCLUDED.payout,
+			generated_at = EXCLUDED.generated_at
+		WHERE seller_statements.payout_id IS NULL`
+
+	tag, err := r.db.Exec(ctx, query, period, period.AddDate(0, 1, 0))
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to generate seller statements")
+		return 0, fmt.Errorf("failed to generate seller statements: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// GetSellerStatements lists the statements of the seller owned by the user,
+// newest period first.
+func (r *StatementRepository) GetSellerStatements(ctx context.Context, userID int, pagination *models.PaginationParams) ([]*models.SellerStatement, int64, error) {
+	var totalItems int64
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM seller_statements st
+		JOIN sellers s ON s.id = st.seller_id WHERE s.user_id = $1`, userID).Scan(&totalItems)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to count seller statements")
+		return nil, 0, fmt.Errorf("failed to count seller statements: %w", err)
+	}
+
+	if totalItems == 0 {
+		return []*models.SellerStatement{}, 0, nil
+	}
+
+	statements := []*models.SellerStatement{}
+	err = pgxscan.Select(ctx, r.db, &statements, `SELECT `+strings.Join(statementColumns, ", ")+` FROM seller_statements st
+		JOIN sellers s ON s.id = st.seller_id
+		LEFT JOIN seller_invoicing si ON si.seller_id = st.seller_id WHERE s.user_id = $1
+		ORDER BY st.period DESC LIMIT $2 OFFSET $3`, userID, pagination.GetLimit(), pagination.GetOffset())
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get seller statements")
+		return nil, 0, fmt.Errorf("failed to get seller statements: %w", err)
+	}
+
+	return statements, totalItems, nil
+}
+
+// GetSellerStatement returns one period's statement of the seller owned by
+// the user.
+func (r *StatementRepository) GetSellerStatement(ctx context.Context, userID int, period time.Time) (*models.SellerStatement, error) {
+	var st models.SellerStatement
+	err := pgxscan.Get(ctx, r.db, &st, `SELECT `+strings.Join(statementColumns, ", ")+` FROM seller_statements st
+		JOIN sellers s ON s.id = st.seller_id
+		LEFT JOIN seller_invoicing si ON si.seller_id = st.seller_id WHERE s.user_id = $1 AND st.period = $2::date`, userID, period)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.NotFound(fmt.Sprintf("no statement for %s", period.Format("2006-01")))
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get seller statement")
+		return nil, fmt.Errorf("failed to get seller statement: %w", err)
+	}
+
+	return &st, nil
+}
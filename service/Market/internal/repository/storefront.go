@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var storefrontColumns = []string{
+	"tenant", "name", "logo_url", "primary_color", "secondary_color", "accent_color",
+	"contact_email", "contact_phone", "contact_address", "footer_links",
+	"default_currency", "default_locale", "updated_by", "updated_at",
+}
+
+type StorefrontRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewStorefrontRepository(db *pgxpool.Pool) *StorefrontRepository {
+	return &StorefrontRepository{db: db}
+}
+
+// Resolve returns the settings of the tenant, falling back to the default
+// tenant. It returns nil when neither is configured.
+func (r *StorefrontRepository) Resolve(ctx context.Context, tenant string) (*models.StorefrontSettings, error) {
+	query, args, err := psql.Select(storefrontColumns...).From("storefront_settings").
+		Where("tenant IN (?, ?)", tenant, models.DefaultStorefrontTenant).
+		OrderBy("tenant = '" + models.DefaultStorefrontTenant + "'").
+		Limit(1).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build storefront settings query")
+		return nil, fmt.Errorf("failed to build storefront settings query: %w", err)
+	}
+
+	var settings models.StorefrontSettings
+	if err := pgxscan.Get(ctx, r.db, &settings, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get storefront settings")
+		return nil, fmt.Errorf("failed to get storefront settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+func (r *StorefrontRepository) GetAll(ctx context.Context) ([]*models.StorefrontSettings, error) {
+	query, args, err := psql.Select(storefrontColumns...).From("storefront_settings").
+		OrderBy("tenant").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build storefront settings query")
+		return nil, fmt.Errorf("failed to build storefront settings query: %w", err)
+	}
+
+	settings := []*models.StorefrontSettings{}
+	if err := pgxscan.Select(ctx, r.db, &settings, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get storefront settings")
+		return nil, fmt.Errorf("failed to get storefront settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// Upsert replaces all settings of the tenant, creating it if needed.
+func (r *StorefrontRepository) Upsert(ctx context.Context, tenant string, adminID int, req *models.UpdateStorefrontSettingsRequest) (*models.StorefrontSettings, error) {
+	query, args, err := psql.Insert("storefront_settings").
+		Columns("tenant", "name", "logo_url", "primary_color", "secondary_color", "accent_color",
+			"contact_email", "contact_phone", "contact_address", "footer_links",
+			"default_currency", "default_locale", "updated_by", "updated_at").
+		Values(tenant, req.Name, req.LogoURL, req.PrimaryColor, req.SecondaryColor, req.AccentColor,
+			req.ContactEmail, req.ContactPhone, req.ContactAddress, models.FooterLinksJSON(req.FooterLinks),
+			req.DefaultCurrency, req.DefaultLocale, adminID, sq.Expr("NOW()")).
+		Suffix(`ON CONFLICT (tenant) DO UPDATE SET
+			name = EXCLUDED.name, logo_url = EXCLUDED.logo_url,
+			primary_color = EXCLUDED.primary_color, secondary_color = EXCLUDED.secondary_color, accent_color = EXCLUDED.accent_color,
+			contact_email = EXCLUDED.contact_email, contact_phone = EXCLUDED.contact_phone, contact_address = EXCLUDED.contact_address,
+			footer_links = EXCLUDED.footer_links,
+			default_currency = EXCLUDED.default_currency, default_locale = EXCLUDED.default_locale,
+			updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at ` + returning(storefrontColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build upsert storefront settings query")
+		return nil, fmt.Errorf("failed to build upsert storefront settings query: %w", err)
+	}
+
+	var settings models.StorefrontSettings
+	if err := pgxscan.Get(ctx, r.db, &settings, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to save storefront settings")
+		return nil, fmt.Errorf("failed to save storefront settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+func (r *StorefrontRepository) Delete(ctx context.Context, tenant string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM storefront_settings WHERE tenant = $1`, tenant)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to delete storefront settings")
+		return fmt.Errorf("failed to delete storefront settings: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperrors.NotFound(fmt.Sprintf("storefront settings for %q not found", tenant))
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SystemRepository reads the database side of the system status.
+type SystemRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSystemRepository(db *pgxpool.Pool) *SystemRepository {
+	return &SystemRepository{db: db}
+}
+
+// GetOutboxStatus counts the domain events waiting to be published and
+// how many of them failed to publish at least once.
+func (r *SystemRepository) GetOutboxStatus(ctx context.Context) (*models.OutboxStatus, error) {
+	var status models.OutboxStatus
+	err := pgxscan.Get(ctx, r.db, &status, `SELECT COUNT(*) AS pending,
+		COUNT(*) FILTER (WHERE attempts > 0) AS failing,
+		MIN(created_at) AS oldest_pending_at
+		FROM event_outbox WHERE published_at IS NULL`)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get outbox status")
+		return nil, fmt.Errorf("failed to get outbox status: %w", err)
+	}
+	return &status, nil
+}
+
+// GetExportQueueStatus counts the product export jobs not finished yet.
+func (r *SystemRepository) GetExportQueueStatus(ctx context.Context) (*models.ExportQueueStatus, error) {
+	var status models.ExportQueueStatus
+	err := pgxscan.Get(ctx, r.db, &status, `SELECT COUNT(*) FILTER (WHERE status = 'queued') AS queued,
+		COUNT(*) FILTER (WHERE status = 'running') AS running,
+		MIN(created_at) FILTER (WHERE status = 'queued') AS oldest_queued_at
+		FROM export_jobs WHERE status IN ('queued', 'running')`)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get export queue status")
+		return nil, fmt.Errorf("failed to get export queue status: %w", err)
+	}
+	return &status, nil
+}
+
+// GetReplication lists the streaming replicas of the database and whether
+// the database is a replica itself.
+func (r *SystemRepository) GetReplication(ctx context.Context) ([]models.ReplicaStatus, bool, error) {
+	var inRecovery bool
+	if err := r.db.QueryRow(ctx, `SELECT pg_is_in_recovery()`).Scan(&inRecovery); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get recovery status")
+		return nil, false, fmt.Errorf("failed to get recovery status: %w", err)
+	}
+
+	replicas := []models.ReplicaStatus{}
+	err := pgxscan.Select(ctx, r.db, &replicas, `SELECT COALESCE(application_name, '') AS name,
+		COALESCE(state, '') AS state,
+		EXTRACT(EPOCH FROM replay_lag)::float8 AS replay_lag_seconds,
+		pg_wal_lsn_diff(pg_current_wal_lsn(), replay_lsn)::bigint AS replay_lag_bytes
+		FROM pg_stat_replication ORDER BY application_name`)
+	if err != nil && !inRecovery {
+		logger.GetLogger().WithField("err", err).Error("failed to get replication status")
+		return nil, inRecovery, fmt.Errorf("failed to get replication status: %w", err)
+	}
+	return replicas, inRecovery, nil
+}
@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ticketColumns select a support_tickets row into models.Ticket.
+var ticketColumns = []string{
+	"id", "user_id", "user_role", "COALESCE(user_email, '') AS user_email", "category", "subject", "order_id", "status", "created_at", "updated_at",
+}
+
+// ticketMessageColumns select a ticket_messages row into models.TicketMessage.
+var ticketMessageColumns = []string{"id", "ticket_id", "author_id", "author_role", "body", "attachments", "created_at"}
+
+type TicketRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTicketRepository(db *pgxpool.Pool) *TicketRepository {
+	return &TicketRepository{db: db}
+}
+
+// Create opens a ticket with its first message. A referenced order must
+// have been placed by the requester or contain the requester's products.
+func (r *TicketRepository) Create(ctx context.Context, ticket *models.Ticket, first *models.TicketMessage) (*models.Ticket, error) {
+	if ticket.OrderID != nil {
+		var related bool
+		err := r.db.QueryRow(ctx, `SELECT EXISTS(
+			SELECT 1 FROM orders o WHERE o.id = $1 AND (o.user_id = $2 OR EXISTS(
+				SELECT 1 FROM order_items oi
+				JOIN products p ON p.id = oi.product_id
+				JOIN sellers s ON s.id = p.seller_id
+				WHERE oi.order_id = o.id AND s.user_id = $2)))`, *ticket.OrderID, ticket.UserID).Scan(&related)
+		if err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to check ticket order")
+			return nil, fmt.Errorf("failed to check ticket order: %w", err)
+		}
+		if !related {
+			return nil, apperrors.OrderNotFound(*ticket.OrderID)
+		}
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var email interface{}
+	if ticket.UserEmail != "" {
+		email = ticket.UserEmail
+	}
+	query, args, err := psql.Insert("support_tickets").
+		Columns("user_id", "user_role", "user_email", "category", "subject", "order_id").
+		Values(ticket.UserID, ticket.UserRole, email, ticket.Category, ticket.Subject, ticket.OrderID).
+		Suffix(returning(ticketColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build insert ticket query")
+		return nil, fmt.Errorf("failed to build insert ticket query: %w", err)
+	}
+
+	created := &models.Ticket{}
+	if err := pgxscan.Get(ctx, tx, created, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to create ticket")
+		return nil, fmt.Errorf("failed to create ticket: %w", err)
+	}
+
+	first.TicketID = created.ID
+	msg, err := insertTicketMessage(ctx, tx, first)
+	if err != nil {
+		return nil, err
+	}
+	created.Messages = []*models.TicketMessage{msg}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return created, nil
+}
+
+func insertTicketMessage(ctx context.Context, tx pgx.Tx, m *models.TicketMessage) (*models.TicketMessage, error) {
+	attachments := m.Attachments
+	if attachments == nil {
+		attachments = []string{}
+	}
+	query, args, err := psql.Insert("ticket_messages").
+		Columns("ticket_id", "author_id", "author_role", "body", "attachments").
+		Values(m.TicketID, m.AuthorID, m.AuthorRole, m.Body, attachments).
+		Suffix(returning(ticketMessageColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build insert ticket message query")
+		return nil, fmt.Errorf("failed to build insert ticket message query: %w", err)
+	}
+
+	msg := &models.TicketMessage{}
+	if err := pgxscan.Get(ctx, tx, msg, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to create ticket message")
+		return nil, fmt.Errorf("failed to create ticket message: %w", err)
+	}
+	return msg, nil
+}
+
+// GetByID returns a ticket with its conversation, oldest message first.
+func (r *TicketRepository) GetByID(ctx context.Context, id int) (*models.Ticket, error) {
+	query, args, err := psql.Select(ticketColumns...).From("support_tickets").Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build ticket query")
+		return nil, fmt.Errorf("failed to build ticket query: %w", err)
+	}
+
+	ticket := &models.Ticket{}
+	if err := pgxscan.Get(ctx, r.db, ticket, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.NotFound(fmt.Sprintf("ticket with id %d not found", id))
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get ticket")
+		return nil, fmt.Errorf("failed to get ticket: %w", err)
+	}
+
+	query, args, err = psql.Select(ticketMessageColumns...).
+		From("ticket_messages").
+		Where(sq.Eq{"ticket_id": id}).
+		OrderBy("created_at", "id").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build ticket messages query")
+		return nil, fmt.Errorf("failed to build ticket messages query: %w", err)
+	}
+
+	ticket.Messages = []*models.TicketMessage{}
+	if err := pgxscan.Select(ctx, r.db, &ticket.Messages, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get ticket messages")
+		return nil, fmt.Errorf("failed to get ticket messages: %w", err)
+	}
+
+	return ticket, nil
+}
+
+// GetAll lists tickets without their messages, most recently updated first.
+// A nil userID lists every user's tickets; an empty status lists all statuses.
+func (r *TicketRepository) GetAll(ctx context.Context, userID *int, status string, pagination *models.PaginationParams) ([]*models.Ticket, int64, error) {
+	countBuilder := psql.Select("COUNT(*)").From("support_tickets")
+	selectBuilder := psql.Select(ticketColumns...).From("support_tickets")
+	if userID != nil {
+		countBuilder = countBuilder.Where(sq.Eq{"user_id": *userID})
+		selectBuilder = selectBuilder.Where(sq.Eq{"user_id": *userID})
+	}
+	if status != "" {
+		countBuilder = countBuilder.Where(sq.Eq{"status": status})
+		selectBuilder = selectBuilder.Where(sq.Eq{"status": status})
+	}
+
+	countQuery, countArgs, err := countBuilder.ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build count query")
+		return nil, 0, fmt.Errorf("failed to build count query: %w", err)
+	}
+
+	var totalItems int64
+	if err := r.db.QueryRow(ctx, countQuery, countArgs...).Scan(&totalItems); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to count tickets")
+		return nil, 0, fmt.Errorf("failed to count tickets: %w", err)
+	}
+
+	if totalItems == 0 {
+		return []*models.Ticket{}, 0, nil
+	}
+
+	query, args, err := selectBuilder.
+		OrderBy("updated_at DESC", "id DESC").
+		Limit(uint64(pagination.GetLimit())).
+		Offset(uint64(pagination.GetOffset())).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build tickets query")
+		return nil, 0, fmt.Errorf("failed to build tickets query: %w", err)
+	}
+
+	tickets := []*models.Ticket{}
+	if err := pgxscan.Select(ctx, r.db, &tickets, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get tickets")
+		return nil, 0, fmt.Errorf("failed to get tickets: %w", err)
+	}
+
+	return tickets, totalItems, nil
+}
+
+// AddMessage appends a message to a ticket and moves it along: a requester
+// reply reopens the ticket for support, an admin reply hands it back to the
+// requester unless status says otherwise. Closed tickets accept admin
+// messages only. The requester gets an in-app notification for admin replies.
+func (r *TicketRepository) AddMessage(ctx context.Context, msg *models.TicketMessage, status string) (*models.Ticket, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	ticket, err := lockTicket(ctx, tx, msg.TicketID)
+	if err != nil {
+		return nil, err
+	}
+
+	staff := msg.AuthorRole == "admin"
+	switch {
+	case staff && status == "":
+		status = models.TicketStatusPending
+	case !staff && ticket.Status == models.TicketStatusClosed:
+		return nil, apperrors.Conflict(fmt.Sprintf("ticket %d is closed", ticket.ID))
+	case !staff:
+		status = models.TicketStatusOpen
+	}
+
+	if _, err := insertTicketMessage(ctx, tx, msg); err != nil {
+		return nil, err
+	}
+	if err := setTicketStatus(ctx, tx, ticket.ID, status); err != nil {
+		return nil, err
+	}
+	if staff {
+		if err := notify(ctx, tx, ticket.UserID, "ticket_reply",
+			fmt.Sprintf("Support replied to your ticket #%d \"%s\".", ticket.ID, ticket.Subject)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByID(ctx, ticket.ID)
+}
+
+// UpdateStatus changes a ticket's status and notifies the requester.
+func (r *TicketRepository) UpdateStatus(ctx context.Context, id int, status string) (*models.Ticket, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	ticket, err := lockTicket(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.Status == status {
+		return ticket, nil
+	}
+
+	if err := setTicketStatus(ctx, tx, id, status); err != nil {
+		return nil, err
+	}
+	if err := notify(ctx, tx, ticket.UserID, "ticket_status",
+		fmt.Sprintf("Your ticket #%d \"%s\" is now %s.", ticket.ID, ticket.Subject, status)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	ticket.Status = status
+	return ticket, nil
+}
+
+func lockTicket(ctx context.Context, tx pgx.Tx, id int) (*models.Ticket, error) {
+	query, args, err := psql.Select(ticketColumns...).
+		From("support_tickets").
+		Where(sq.Eq{"id": id}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build ticket lock query")
+		return nil, fmt.Errorf("failed to build ticket lock query: %w", err)
+	}
+
+	ticket := &models.Ticket{}
+	if err := pgxscan.Get(ctx, tx, ticket, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.NotFound(fmt.Sprintf("ticket with id %d not found", id))
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to get ticket")
+		return nil, fmt.Errorf("failed to get ticket: %w", err)
+	}
+	return ticket, nil
+}
+
+func setTicketStatus(ctx context.Context, tx pgx.Tx, id int, status string) error {
+	if _, err := tx.Exec(ctx, `UPDATE support_tickets SET status = $1, updated_at = NOW() WHERE id = $2`, status, id); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to update ticket status")
+		return fmt.Errorf("failed to update ticket status: %w", err)
+	}
+	return nil
+}
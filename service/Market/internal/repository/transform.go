@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// transformRuleColumns select a transform_rules row, with the owner of its
+// seller, into models.TransformRule.
+var transformRuleColumns = []string{
+	"id", "seller_id", "target", "match", "description", "patch", "enabled", "created_by", "created_at", "updated_at",
+	"(SELECT s.user_id FROM sellers s WHERE s.id = transform_rules.seller_id) AS seller_user_id",
+}
+
+type TransformRuleRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTransformRuleRepository(db *pgxpool.Pool) *TransformRuleRepository {
+	return &TransformRuleRepository{db: db}
+}
+
+// Create stores a rule; its patch has to be validated by the caller.
+func (r *TransformRuleRepository) Create(ctx context.Context, adminID int, req *models.CreateTransformRuleRequest) (*models.TransformRule, error) {
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	query, args, err := psql.Insert("transform_rules").
+		Columns("seller_id", "target", "match", "description", "patch", "enabled", "created_by").
+		Values(req.SellerID, req.Target, req.Match, req.Description, req.Patch, enabled, adminID).
+		Suffix(returning(transformRuleColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build insert transform rule query")
+		return nil, fmt.Errorf("failed to build insert transform rule query: %w", err)
+	}
+
+	var rule models.TransformRule
+	if err := pgxscan.Get(ctx, r.db, &rule, query, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, apperrors.SellerNotFound(*req.SellerID)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to create transform rule")
+		return nil, fmt.Errorf("failed to create transform rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// GetAll lists every rule in the order rules apply.
+func (r *TransformRuleRepository) GetAll(ctx context.Context) ([]*models.TransformRule, error) {
+	return r.list(ctx, sq.And{})
+}
+
+// ListEnabled lists the rules in effect in the order they apply.
+func (r *TransformRuleRepository) ListEnabled(ctx context.Context) ([]*models.TransformRule, error) {
+	return r.list(ctx, sq.Eq{"enabled": true})
+}
+
+func (r *TransformRuleRepository) list(ctx context.Context, where sq.Sqlizer) ([]*models.TransformRule, error) {
+	query, args, err := psql.Select(transformRuleColumns...).
+		From("transform_rules").
+		Where(where).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build select transform rules query")
+		return nil, fmt.Errorf("failed to build select transform rules query: %w", err)
+	}
+
+	rules := []*models.TransformRule{}
+	if err := pgxscan.Select(ctx, r.db, &rules, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get transform rules")
+		return nil, fmt.Errorf("failed to get transform rules: %w", err)
+	}
+	return rules, nil
+}
+
+// Update changes the given fields of a rule; a new patch has to be
+// validated by the caller.
+func (r *TransformRuleRepository) Update(ctx context.Context, id int, req *models.UpdateTransformRuleRequest) (*models.TransformRule, error) {
+	updateBuilder := psql.Update("transform_rules").
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id}).
+		Suffix(returning(transformRuleColumns))
+
+	if req.Match != nil {
+		updateBuilder = updateBuilder.Set("match", *req.Match)
+	}
+	if req.Description != nil {
+		updateBuilder = updateBuilder.Set("description", *req.Description)
+	}
+	if req.Patch != nil {
+		updateBuilder = updateBuilder.Set("patch", req.Patch)
+	}
+	if req.Enabled != nil {
+		updateBuilder = updateBuilder.Set("enabled", *req.Enabled)
+	}
+
+	query, args, err := updateBuilder.ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build update transform rule query")
+		return nil, fmt.Errorf("failed to build update transform rule query: %w", err)
+	}
+
+	var rule models.TransformRule
+	if err := pgxscan.Get(ctx, r.db, &rule, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.NotFound(fmt.Sprintf("transform rule with id %d not found", id))
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to update transform rule")
+		return nil, fmt.Errorf("failed to update transform rule: %w", err)
+	}
+	return &rule, nil
+}
+
+func (r *TransformRuleRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM transform_rules WHERE id = $1`, id)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to delete transform rule")
+		return fmt.Errorf("failed to delete transform rule: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperrors.NotFound(fmt.Sprintf("transform rule with id %d not found", id))
+	}
+	return nil
+}
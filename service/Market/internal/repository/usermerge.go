@@ -0,0 +1,328 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// userMergeColumns select a user_merges row into models.UserMerge.
+var userMergeColumns = []string{
+	"id", "source_user_id", "target_user_id", "merged_by", "orders", "cart_items", "seller_moved",
+	"tickets", "notifications", "reports", "share_links", "reviews", "store_credit::float8 AS store_credit",
+	"payment_methods", "created_at",
+}
+
+type UserMergeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewUserMergeRepository(db *pgxpool.Pool) *UserMergeRepository {
+	return &UserMergeRepository{db: db}
+}
+
+// Merge moves everything the source user owns in the market to the target
+// user in one transaction and records the merge. The source user's cart is
+// folded into the target's cart. Open reports the target already filed
+// against the same content are dismissed as duplicates, and share links
+// the target already has for the same product and source stay with the
+// source user so their codes keep working. A product reviewed from both
+// accounts keeps the target's review only, and the source's store credit is
+// added to the target's. Saved payment methods move with the provider
+// customer they were saved under, which the target takes over for providers
+// it has none with. Staff actions (deliveries,
+// proofs, resolutions) keep their original actor.
+func (r *UserMergeRepository) Merge(ctx context.Context, adminID int, req *models.MergeUsersRequest) (*models.UserMerge, error) {
+	source, target := req.SourceUserID, req.TargetUserID
+	if source == target {
+		return nil, apperrors.ValidationError("target_user_id", "must differ from source_user_id")
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Both shops cannot be kept under one user, and merging shops is a
+	// decision for the sellers.
+	var sellers int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM sellers WHERE user_id IN ($1, $2)`, source, target).Scan(&sellers); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to check seller profiles")
+		return nil, fmt.Errorf("failed to check seller profiles: %w", err)
+	}
+	if sellers == 2 {
+		return nil, apperrors.Conflict("both users have a seller profile")
+	}
+
+	merge := &models.UserMerge{SourceUserID: source, TargetUserID: target, MergedBy: adminID}
+
+	tag, err := tx.Exec(ctx, `UPDATE orders SET user_id = $2, updated_at = NOW() WHERE user_id = $1`, source, target)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to move orders")
+		return nil, fmt.Errorf("failed to move orders: %w", err)
+	}
+	merge.Orders = tag.RowsAffected()
+
+	if merge.CartItems, err = mergeCarts(ctx, tx, source, target); err != nil {
+		return nil, err
+	}
+
+	tag, err = tx.Exec(ctx, `UPDATE sellers SET user_id = $2, updated_at = NOW() WHERE user_id = $1`, source, target)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to move seller profile")
+		return nil, fmt.Errorf("failed to move seller profile: %w", err)
+	}
+	merge.SellerMoved = tag.RowsAffected() > 0
+	if merge.SellerMoved {
+		_, err = tx.Exec(ctx, `INSERT INTO seller_api_usage (user_id, day, requests, errors, throttled)
+			SELECT $2, day, requests, errors, throttled FROM seller_api_usage WHERE user_id = $1
+			ON CONFLICT (user_id, day) DO UPDATE SET requests = seller_api_usage.requests + EXCLUDED.requests,
+				errors = seller_api_usage.errors + EXCLUDED.errors, throttled = seller_api_usage.throttled + EXCLUDED.throttled`,
+			source, target)
+		if err == nil {
+			_, err = tx.Exec(ctx, `DELETE FROM seller_api_usage WHERE user_id = $1`, source)
+		}
+		if err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to move seller api usage")
+			return nil, fmt.Errorf("failed to move seller api usage: %w", err)
+		}
+	}
+
+	tag, err = tx.Exec(ctx, `UPDATE support_tickets SET user_id = $2 WHERE user_id = $1`, source, target)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to move support tickets")
+		return nil, fmt.Errorf("failed to move support tickets: %w", err)
+	}
+	merge.Tickets = tag.RowsAffected()
+
+	tag, err = tx.Exec(ctx, `UPDATE notifications SET user_id = $2 WHERE user_id = $1`, source, target)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to move notifications")
+		return nil, fmt.Errorf("failed to move notifications: %w", err)
+	}
+	merge.Notifications = tag.RowsAffected()
+
+	_, err = tx.Exec(ctx, `UPDATE reports r SET status = 'resolved', resolution = 'dismiss',
+			resolution_note = 'Duplicate of a report by the merged account.', resolved_by = $3, resolved_at = NOW()
+		WHERE r.reporter_id = $1 AND r.status = 'open' AND EXISTS (
+			SELECT 1 FROM reports o WHERE o.reporter_id = $2 AND o.status = 'open'
+				AND o.target_type = r.target_type AND o.target_id = r.target_id)`, source, target, adminID)
+	if err == nil {
+		tag, err = tx.Exec(ctx, `UPDATE reports SET reporter_id = $2 WHERE reporter_id = $1`, source, target)
+	}
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to move reports")
+		return nil, fmt.Errorf("failed to move reports: %w", err)
+	}
+	merge.Reports = tag.RowsAffected()
+
+	tag, err = tx.Exec(ctx, `UPDATE share_links s SET created_by = $2
+		WHERE s.created_by = $1 AND NOT EXISTS (
+			SELECT 1 FROM share_links t WHERE t.created_by = $2 AND t.product_id = s.product_id AND t.source = s.source)`,
+		source, target)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to move share links")
+		return nil, fmt.Errorf("failed to move share links: %w", err)
+	}
+	merge.ShareLinks = tag.RowsAffected()
+
+	if merge.Reviews, err = mergeReviews(ctx, tx, source, target); err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx, `DELETE FROM store_credits WHERE user_id = $1 RETURNING balance::float8`, source).Scan(&merge.StoreCredit)
+	if err == nil {
+		_, err = tx.Exec(ctx, `INSERT INTO store_credits (user_id, balance) VALUES ($1, $2)
+			ON CONFLICT (user_id) DO UPDATE SET balance = store_credits.balance + EXCLUDED.balance, updated_at = NOW()`,
+			target, merge.StoreCredit)
+	} else if errors.Is(err, pgx.ErrNoRows) {
+		err = nil
+	}
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to move store credit")
+		return nil, fmt.Errorf("failed to move store credit: %w", err)
+	}
+
+	// A saved method is charged with its own customer, so the source's
+	// customers that stay behind keep working for the methods moved.
+	_, err = tx.Exec(ctx, `UPDATE payment_customers s SET user_id = $2
+		WHERE s.user_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM payment_customers t WHERE t.user_id = $2 AND t.provider = s.provider)`, source, target)
+	if err == nil {
+		tag, err = tx.Exec(ctx, `UPDATE saved_payment_methods SET user_id = $2 WHERE user_id = $1`, source, target)
+	}
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to move saved payment methods")
+		return nil, fmt.Errorf("failed to move saved payment methods: %w", err)
+	}
+	merge.PaymentMethods = tag.RowsAffected()
+
+	query, args, err := psql.Insert("user_merges").
+		Columns("source_user_id", "target_user_id", "merged_by", "orders", "cart_items", "seller_moved",
+			"tickets", "notifications", "reports", "share_links", "reviews", "store_credit", "payment_methods").
+		Values(merge.SourceUserID, merge.TargetUserID, merge.MergedBy, merge.Orders, merge.CartItems, merge.SellerMoved,
+			merge.Tickets, merge.Notifications, merge.Reports, merge.ShareLinks, merge.Reviews, merge.StoreCredit,
+			merge.PaymentMethods).
+		Suffix(returning(userMergeColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build insert user merge query")
+		return nil, fmt.Errorf("failed to build insert user merge query: %w", err)
+	}
+
+	merge = &models.UserMerge{}
+	if err := pgxscan.Get(ctx, tx, merge, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to record user merge")
+		return nil, fmt.Errorf("failed to record user merge: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	logger.GetLogger().WithFields(map[string]interface{}{
+		"merge_id":       merge.ID,
+		"source_user_id": merge.SourceUserID,
+		"target_user_id": merge.TargetUserID,
+		"merged_by":      merge.MergedBy,
+		"orders":         merge.Orders,
+		"seller_moved":   merge.SellerMoved,
+	}).Info("user accounts merged")
+
+	return merge, nil
+}
+
+// mergeCarts folds the source user's cart items into the target's cart,
+// adding up quantities of the same product variant, and returns the number
+// of source items merged.
+func mergeCarts(ctx context.Context, tx pgx.Tx, source, target int) (int64, error) {
+	var targetCartID int
+	err := tx.QueryRow(ctx, `SELECT id FROM carts WHERE user_id = $1 ORDER BY id LIMIT 1`, target).Scan(&targetCartID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The target has no cart: take over the source's.
+		var items int64
+		err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM cart_items
+			WHERE cart_id IN (SELECT id FROM carts WHERE user_id = $1)`, source).Scan(&items)
+		if err == nil {
+			_, err = tx.Exec(ctx, `UPDATE carts SET user_id = $2, updated_at = NOW() WHERE user_id = $1`, source, target)
+		}
+		if err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to move cart")
+			return 0, fmt.Errorf("failed to move cart: %w", err)
+		}
+		return items, nil
+	}
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get target cart")
+		return 0, fmt.Errorf("failed to get target cart: %w", err)
+	}
+
+	var merged int64
+	tag, err := tx.Exec(ctx, `UPDATE cart_items t SET quantity = t.quantity + s.quantity, updated_at = NOW()
+		FROM cart_items s JOIN carts c ON c.id = s.cart_id
+		WHERE c.user_id = $1 AND t.cart_id = $2 AND t.product_id = s.product_id
+			AND t.size IS NOT DISTINCT FROM s.size AND t.color IS NOT DISTINCT FROM s.color
+			AND t.variant_id IS NOT DISTINCT FROM s.variant_id`, source, targetCartID)
+	if err == nil {
+		merged = tag.RowsAffected()
+		tag, err = tx.Exec(ctx, `UPDATE cart_items s SET cart_id = $2, updated_at = NOW()
+			FROM carts c
+			WHERE c.id = s.cart_id AND c.user_id = $1 AND NOT EXISTS (
+				SELECT 1 FROM cart_items t WHERE t.cart_id = $2 AND t.product_id = s.product_id
+					AND t.size IS NOT DISTINCT FROM s.size AND t.color IS NOT DISTINCT FROM s.color
+					AND t.variant_id IS NOT DISTINCT FROM s.variant_id)`, source, targetCartID)
+	}
+	if err == nil {
+		merged += tag.RowsAffected()
+		// Remaining items were added to the target's quantities above.
+		_, err = tx.Exec(ctx, `DELETE FROM carts WHERE user_id = $1`, source)
+	}
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to merge carts")
+		return 0, fmt.Errorf("failed to merge carts: %w", err)
+	}
+	return merged, nil
+}
+
+// mergeReviews moves the source user's reviews to the target and returns
+// how many moved. A user reviews a product once, so the source's review of
+// a product the target also reviewed is deleted and the ratings it counted
+// towards are updated.
+func mergeReviews(ctx context.Context, tx pgx.Tx, source, target int) (int64, error) {
+	var duplicates []int
+	err := pgxscan.Select(ctx, tx, &duplicates, `SELECT s.product_id FROM reviews s
+		JOIN reviews t ON t.product_id = s.product_id AND t.user_id = $2
+		WHERE s.user_id = $1 ORDER BY s.product_id`, source, target)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to find duplicate reviews")
+		return 0, fmt.Errorf("failed to find duplicate reviews: %w", err)
+	}
+
+	sellers := make([]int, len(duplicates))
+	for i, productID := range duplicates {
+		if sellers[i], err = lockRated(ctx, tx, productID); err != nil {
+			return 0, err
+		}
+	}
+	if len(duplicates) > 0 {
+		if _, err := tx.Exec(ctx, `DELETE FROM reviews WHERE user_id = $1 AND product_id = ANY($2)`, source, duplicates); err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to delete duplicate reviews")
+			return 0, fmt.Errorf("failed to delete duplicate reviews: %w", err)
+		}
+	}
+
+	tag, err := tx.Exec(ctx, `UPDATE reviews SET user_id = $2 WHERE user_id = $1`, source, target)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to move reviews")
+		return 0, fmt.Errorf("failed to move reviews: %w", err)
+	}
+
+	for i, productID := range duplicates {
+		if err := updateRatings(ctx, tx, productID, sellers[i]); err != nil {
+			return 0, err
+		}
+	}
+	return tag.RowsAffected(), nil
+}
+
+// GetAll returns the merge audit log, newest first.
+func (r *UserMergeRepository) GetAll(ctx context.Context, pagination *models.PaginationParams) ([]*models.UserMerge, int64, error) {
+	var totalItems int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM user_merges`).Scan(&totalItems); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to count user merges")
+		return nil, 0, fmt.Errorf("failed to count user merges: %w", err)
+	}
+
+	if totalItems == 0 {
+		return []*models.UserMerge{}, 0, nil
+	}
+
+	query, args, err := psql.Select(userMergeColumns...).
+		From("user_merges").
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(pagination.GetLimit())).
+		Offset(uint64(pagination.GetOffset())).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build user merges query")
+		return nil, 0, fmt.Errorf("failed to build user merges query: %w", err)
+	}
+
+	merges := []*models.UserMerge{}
+	if err := pgxscan.Select(ctx, r.db, &merges, query, args...); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get user merges")
+		return nil, 0, fmt.Errorf("failed to get user merges: %w", err)
+	}
+
+	return merges, totalItems, nil
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/readonly"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// variantColumns select a product_variants row into models.ProductVariant.
+var variantColumns = []string{
+	"id", "product_id", "sku", "size", "color", "price_delta::float8 AS price_delta", "stock", "created_at", "updated_at",
+	"weight_grams", "length_mm", "width_mm", "height_mm",
+}
+
+// syncVariantTotalsQuery sets the stock of product $1 to the sum of its
+// variants' and its sizes to theirs, in the order they were added.
+const syncVariantTotalsQuery = `UPDATE products SET
+		stock = (SELECT COALESCE(SUM(stock), 0) FROM product_variants WHERE product_id = $1),
+		sizes = (SELECT COALESCE(jsonb_agg(size ORDER BY first_id), '[]'::jsonb) FROM (
+			SELECT size, MIN(id) AS first_id FROM product_variants
+			WHERE product_id = $1 AND size <> '' GROUP BY size) s),
+		updated_at = NOW()
+	WHERE id = $1`
+
+type VariantRepository struct {
+	db       *pgxpool.Pool
+	readOnly *readonly.Guard
+}
+
+// NewVariantRepository creates a product variant repository. A nil guard
+// never blocks writes.
+func NewVariantRepository(db *pgxpool.Pool, guard *readonly.Guard) *VariantRepository {
+	return &VariantRepository{db: db, readOnly: guard}
+}
+
+// GetByProductID returns the variants of a product in the order they were
+// added; products sold as a whole have none.
+func (r *VariantRepository) GetByProductID(ctx context.Context, productID int) ([]*models.ProductVariant, error) {
+	query := `SELECT ` + strings.Join(variantColumns, ", ") + ` FROM product_variants WHERE product_id = $1 ORDER BY id`
+
+	variants := []*models.ProductVariant{}
+	if err := pgxscan.Select(ctx, r.db, &variants, query, productID); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to get product variants")
+		return nil, fmt.Errorf("failed to get product variants: %w", err)
+	}
+	return variants, nil
+}
+
+// Create adds a variant to a product. The first variant turns a product
+// sold as a whole into one sold by variant: its stock becomes theirs and
+// cart items without a variant are dropped.
+func (r *VariantRepository) Create(ctx context.Context, productID int, req *models.CreateVariantRequest) (*models.ProductVariant, error) {
+	if err := r.readOnly.Check(readonly.TableProducts); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	price, err := lockProductPrice(ctx, tx, productID)
+	if err != nil {
+		return nil, err
+	}
+	variant, err := insertVariant(ctx, tx, productID, price, req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM cart_items WHERE product_id = $1 AND variant_id IS NULL`, productID); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to remove cart items without a variant")
+		return nil, fmt.Errorf("failed to remove cart items without a variant: %w", err)
+	}
+	if err := syncVariantTotals(ctx, tx, productID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return variant, nil
+}
+
+// Update changes a variant of a product.
+func (r *VariantRepository) Update(ctx context.Context, productID, variantID int, req *models.UpdateVariantRequest) (*models.ProductVariant, error) {
+	if err := r.readOnly.Check(readonly.TableProducts); err != nil {
+		return nil, err
+	}
+
+	updateBuilder := psql.Update("product_variants").
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": variantID, "product_id": productID}).
+		Suffix(returning(variantColumns))
+	if req.SKU != nil {
+		updateBuilder = updateBuilder.Set("sku", *req.SKU)
+	}
+	if req.Size != nil {
+		updateBuilder = updateBuilder.Set("size", *req.Size)
+	}
+	if req.Color != nil {
+		updateBuilder = updateBuilder.Set("color", *req.Color)
+	}
+	if req.PriceDelta != nil {
+		updateBuilder = updateBuilder.Set("price_delta", *req.PriceDelta)
+	}
+	if req.Stock != nil {
+		updateBuilder = updateBuilder.Set("stock", *req.Stock)
+	}
+	updateBuilder = setDimensions(updateBuilder, req.Dimensions)
+
+	query, args, err := updateBuilder.ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build update variant query")
+		return nil, fmt.Errorf("failed to build update variant query: %w", err)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	price, err := lockProductPrice(ctx, tx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var variant models.ProductVariant
+	if err := pgxscan.Get(ctx, tx, &variant, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.NotFound(fmt.Sprintf("variant with id %d not found", variantID))
+		}
+		if isVariantConflict(err) {
+			return nil, variantConflict()
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to update variant")
+		return nil, fmt.Errorf("failed to update variant: %w", err)
+	}
+	if price+variant.PriceDelta <= 0 {
+		return nil, apperrors.ValidationError("price_delta", "must leave the variant a positive price")
+	}
+	if err := syncVariantTotals(ctx, tx, productID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &variant, nil
+}
+
+// Delete removes a variant of a product, and with it the cart items for
+// it. Orders keep its SKU. A product left without variants has no stock
+// until the seller sets it.
+func (r *VariantRepository) Delete(ctx context.Context, productID, variantID int) error {
+	if err := r.readOnly.Check(readonly.TableProducts); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to begin transaction")
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := lockProductPrice(ctx, tx, productID); err != nil {
+		return err
+	}
+	tag, err := tx.Exec(ctx, `DELETE FROM product_variants WHERE id = $1 AND product_id = $2`, variantID, productID)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to delete variant")
+		return fmt.Errorf("failed to delete variant: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperrors.NotFound(fmt.Sprintf("variant with id %d not found", variantID))
+	}
+	if err := syncVariantTotals(ctx, tx, productID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to commit transaction")
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// lockProductPrice locks the product row, serializing changes to its
+// variants and their totals, and returns its price.
+func lockProductPrice(ctx context.Context, tx pgx.Tx, productID int) (float64, error) {
+	var price float64
+	err := tx.QueryRow(ctx, `SELECT price::float8 FROM products WHERE id = $1 FOR UPDATE`, productID).Scan(&price)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, apperrors.ProductNotFound(productID)
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to lock product")
+		return 0, fmt.Errorf("failed to lock product: %w", err)
+	}
+	return price, nil
+}
+
+// insertVariant adds a variant to a product priced price; the caller
+// syncs the product's totals.
+func insertVariant(ctx context.Context, tx pgx.Tx, productID int, price float64, req *models.CreateVariantRequest) (*models.ProductVariant, error) {
+	if price+req.PriceDelta <= 0 {
+		return nil, apperrors.ValidationError("price_delta", "must leave the variant a positive price")
+	}
+
+	query, args, err := psql.Insert("product_variants").
+		Columns("product_id", "sku", "size", "color", "price_delta", "stock").
+		Columns(dimensionColumns...).
+		Values(append([]interface{}{productID, req.SKU, req.Size, req.Color, req.PriceDelta, req.Stock},
+			dimensionValues(req.Dimensions)...)...).
+		Suffix(returning(variantColumns)).
+		ToSql()
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to build insert variant query")
+		return nil, fmt.Errorf("failed to build insert variant query: %w", err)
+	}
+
+	var variant models.ProductVariant
+	if err := pgxscan.Get(ctx, tx, &variant, query, args...); err != nil {
+		if isVariantConflict(err) {
+			return nil, variantConflict()
+		}
+		logger.GetLogger().WithField("err", err).Error("failed to create variant")
+		return nil, fmt.Errorf("failed to create variant: %w", err)
+	}
+	return &variant, nil
+}
+
+func syncVariantTotals(ctx context.Context, tx pgx.Tx, productID int) error {
+	if _, err := tx.Exec(ctx, syncVariantTotalsQuery, productID); err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to update product stock from variants")
+		return fmt.Errorf("failed to update product stock from variants: %w", err)
+	}
+	return nil
+}
+
+func isVariantConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+func variantConflict() error {
+	return apperrors.Conflict("the product already has a variant with this SKU or this size and color")
+}
@@ -0,0 +1,61 @@
+// Package reservation releases expired cart stock reservations.
+package reservation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+	"github.com/Zifeldev/marketback/service/Market/internal/workers"
+)
+
+// Releaser deletes expired reservations.
+type Releaser interface {
+	ReleaseExpired(ctx context.Context) (int64, error)
+}
+
+// Sweeper releases expired reservations on a fixed interval. Expired
+// reservations stop holding stock as soon as they expire; sweeping only
+// removes them.
+type Sweeper struct {
+	releaser Releaser
+}
+
+func NewSweeper(releaser Releaser) *Sweeper {
+	return &Sweeper{releaser: releaser}
+}
+
+// RunOnce releases the reservations that have expired.
+func (s *Sweeper) RunOnce(ctx context.Context) error {
+	released, err := s.releaser.ReleaseExpired(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to release stock reservations: %w", err)
+	}
+
+	metrics.StockReservationsReleasedTotal.Add(float64(released))
+	if released > 0 {
+		logger.GetLogger().WithField("released", released).Debug("expired stock reservations released")
+	}
+	return nil
+}
+
+// Start runs the sweeper every interval until ctx is cancelled.
+func (s *Sweeper) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		workers.Beat(ctx)
+		if err := s.RunOnce(ctx); err != nil {
+			metrics.StockReservationSweepFailuresTotal.Inc()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package reservation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReleaser struct {
+	calls int
+	err   error
+}
+
+func (f *fakeReleaser) ReleaseExpired(ctx context.Context) (int64, error) {
+	f.calls++
+	return 2, f.err
+}
+
+func TestSweeper_RunOnce(t *testing.T) {
+	r := &fakeReleaser{}
+	require.NoError(t, NewSweeper(r).RunOnce(context.Background()))
+	require.Equal(t, 1, r.calls)
+}
+
+func TestSweeper_RunOnce_Error(t *testing.T) {
+	s := NewSweeper(&fakeReleaser{err: errors.New("db down")})
+	require.Error(t, s.RunOnce(context.Background()))
+}
+
+func TestSweeper_Start_StopsOnCancel(t *testing.T) {
+	r := &fakeReleaser{}
+	s := NewSweeper(r)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		s.Start(ctx, time.Hour)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("sweeper did not stop")
+	}
+	require.Equal(t, 1, r.calls)
+}
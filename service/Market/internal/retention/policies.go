@@ -0,0 +1,55 @@
+package retention
+
+import "time"
+
+// AnonymizedAddress replaces delivery addresses of purged orders
+const AnonymizedAddress = "[redacted]"
+
+// ExpiredCarts removes carts (and their items) untouched since the cutoff
+func ExpiredCarts(after time.Duration) Policy {
+	return Policy{
+		Name:  "expired_carts",
+		After: after,
+		Count: `SELECT COUNT(*) FROM carts c
+			WHERE c.updated_at < $1
+			AND NOT EXISTS (SELECT 1 FROM cart_items ci WHERE ci.cart_id = c.id AND ci.updated_at >= $1)`,
+		Purge: `DELETE FROM carts c
+			WHERE c.updated_at < $1
+			AND NOT EXISTS (SELECT 1 FROM cart_items ci WHERE ci.cart_id = c.id AND ci.updated_at >= $1)`,
+	}
+}
+
+// OrderAddresses anonymizes delivery addresses and buyer emails of completed orders
+func OrderAddresses(after time.Duration) Policy {
+	return Policy{
+		Name:  "order_addresses",
+		After: after,
+		Count: `SELECT COUNT(*) FROM orders
+			WHERE created_at < $1 AND status IN ('delivered', 'cancelled')
+			AND delivery_address <> '` + AnonymizedAddress + `'`,
+		Purge: `UPDATE orders SET delivery_address = '` + AnonymizedAddress + `',
+			delivery_lat = NULL, delivery_lng = NULL, buyer_email = NULL, updated_at = NOW()
+			WHERE created_at < $1 AND status IN ('delivered', 'cancelled')
+			AND delivery_address <> '` + AnonymizedAddress + `'`,
+	}
+}
+
+// PublishedEvents removes domain events the relay published before the cutoff
+func PublishedEvents(after time.Duration) Policy {
+	return Policy{
+		Name:  "published_events",
+		After: after,
+		Count: `SELECT COUNT(*) FROM event_outbox WHERE published_at < $1`,
+		Purge: `DELETE FROM event_outbox WHERE published_at < $1`,
+	}
+}
+
+// UserMerges removes the audit log of user merges made before the cutoff
+func UserMerges(after time.Duration) Policy {
+	return Policy{
+		Name:  "user_merges",
+		After: after,
+		Count: `SELECT COUNT(*) FROM user_merges WHERE created_at < $1`,
+		Purge: `DELETE FROM user_merges WHERE created_at < $1`,
+	}
+}
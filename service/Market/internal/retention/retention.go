@@ -0,0 +1,106 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+	"github.com/Zifeldev/marketback/service/Market/internal/workers"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DB is the subset of pgxpool.Pool used by the retention runner
+type DB interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Policy describes a single purge or anonymization rule. Both statements
+// receive the cutoff timestamp (now - After) as $1. A zero After disables
+// the policy.
+type Policy struct {
+	Name  string
+	After time.Duration
+	Count string
+	Purge string
+}
+
+type Runner struct {
+	db       DB
+	policies []Policy
+	dryRun   bool
+	now      func() time.Time
+}
+
+func NewRunner(db DB, dryRun bool, policies ...Policy) *Runner {
+	return &Runner{
+		db:       db,
+		policies: policies,
+		dryRun:   dryRun,
+		now:      time.Now,
+	}
+}
+
+// RunOnce applies every enabled policy and returns the number of affected
+// rows per policy. In dry-run mode rows are only counted.
+func (r *Runner) RunOnce(ctx context.Context) (map[string]int64, error) {
+	result := make(map[string]int64, len(r.policies))
+
+	for _, p := range r.policies {
+		if p.After <= 0 {
+			continue
+		}
+		cutoff := r.now().Add(-p.After)
+
+		var affected int64
+		mode := "purged"
+		if r.dryRun {
+			mode = "dry_run"
+			if err := r.db.QueryRow(ctx, p.Count, cutoff).Scan(&affected); err != nil {
+				logger.GetLogger().WithField("err", err).WithField("policy", p.Name).Error("failed to count retention candidates")
+				return result, fmt.Errorf("failed to count retention candidates for %s: %w", p.Name, err)
+			}
+		} else {
+			tag, err := r.db.Exec(ctx, p.Purge, cutoff)
+			if err != nil {
+				logger.GetLogger().WithField("err", err).WithField("policy", p.Name).Error("failed to apply retention policy")
+				return result, fmt.Errorf("failed to apply retention policy %s: %w", p.Name, err)
+			}
+			affected = tag.RowsAffected()
+		}
+
+		result[p.Name] = affected
+		metrics.RetentionRowsTotal.WithLabelValues(p.Name, mode).Add(float64(affected))
+		logger.GetLogger().WithFields(map[string]interface{}{
+			"policy":  p.Name,
+			"mode":    mode,
+			"rows":    affected,
+			"cutoff":  cutoff,
+			"dry_run": r.dryRun,
+		}).Info("retention policy applied")
+	}
+
+	return result, nil
+}
+
+// Start runs the policies every interval until ctx is cancelled
+func (r *Runner) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		workers.Beat(ctx)
+		if _, err := r.RunOnce(ctx); err != nil {
+			metrics.RetentionRunFailuresTotal.Inc()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
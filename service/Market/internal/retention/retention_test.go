@@ -0,0 +1,105 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRow struct {
+	n   int64
+	err error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	*(dest[0].(*int64)) = r.n
+	return nil
+}
+
+type fakeDB struct {
+	execs   []string
+	queries []string
+	cutoffs []time.Time
+	rows    int64
+	err     error
+}
+
+func (f *fakeDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	f.execs = append(f.execs, sql)
+	f.cutoffs = append(f.cutoffs, args[0].(time.Time))
+	if f.err != nil {
+		return pgconn.CommandTag{}, f.err
+	}
+	return pgconn.NewCommandTag("DELETE " + strconv.FormatInt(f.rows, 10)), nil
+}
+
+func (f *fakeDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	f.queries = append(f.queries, sql)
+	f.cutoffs = append(f.cutoffs, args[0].(time.Time))
+	return fakeRow{n: f.rows, err: f.err}
+}
+
+func TestRunner_RunOnce_Purge(t *testing.T) {
+	db := &fakeDB{rows: 3}
+	now := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	r := NewRunner(db, false, ExpiredCarts(24*time.Hour), OrderAddresses(0))
+	r.now = func() time.Time { return now }
+
+	result, err := r.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int64{"expired_carts": 3}, result)
+	assert.Len(t, db.execs, 1)
+	assert.Empty(t, db.queries)
+	assert.Equal(t, now.Add(-24*time.Hour), db.cutoffs[0])
+}
+
+func TestRunner_RunOnce_DryRun(t *testing.T) {
+	db := &fakeDB{rows: 7}
+
+	r := NewRunner(db, true, ExpiredCarts(time.Hour), OrderAddresses(time.Hour))
+
+	result, err := r.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(7), result["expired_carts"])
+	assert.Equal(t, int64(7), result["order_addresses"])
+	assert.Empty(t, db.execs)
+	assert.Len(t, db.queries, 2)
+}
+
+func TestRunner_RunOnce_Error(t *testing.T) {
+	db := &fakeDB{err: errors.New("connection refused")}
+
+	r := NewRunner(db, false, ExpiredCarts(time.Hour))
+
+	_, err := r.RunOnce(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "expired_carts")
+}
+
+func TestRunner_RunOnce_UserMerges(t *testing.T) {
+	db := &fakeDB{rows: 2}
+	now := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	r := NewRunner(db, false, UserMerges(365*24*time.Hour))
+	r.now = func() time.Time { return now }
+
+	result, err := r.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int64{"user_merges": 2}, result)
+	assert.Contains(t, db.execs[0], "DELETE FROM user_merges")
+	assert.Equal(t, now.Add(-365*24*time.Hour), db.cutoffs[0])
+}
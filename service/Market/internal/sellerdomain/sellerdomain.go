@@ -0,0 +1,110 @@
+// Package sellerdomain maps the custom domains sellers serve their own
+// storefront on to the sellers. Lookups, including misses, are cached for a
+// short while since every public request is resolved.
+package sellerdomain
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCached bounds the cache; past it the cache is dropped rather than
+// letting arbitrary Host headers grow it.
+const maxCached = 10000
+
+// Source looks up the active seller a domain is mapped to; 0 means none.
+type Source interface {
+	GetIDByDomain(ctx context.Context, domain string) (int, error)
+}
+
+type cachedSeller struct {
+	sellerID int
+	expires  time.Time
+}
+
+type Resolver struct {
+	source   Source
+	reserved map[string]bool
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedSeller
+}
+
+// NewResolver creates a resolver. Reserved hosts are the marketplace's own
+// and never resolve to a seller.
+func NewResolver(source Source, reserved []string, ttl time.Duration) *Resolver {
+	r := &Resolver{
+		source:   source,
+		reserved: make(map[string]bool, len(reserved)),
+		ttl:      ttl,
+		now:      time.Now,
+		cache:    make(map[string]cachedSeller),
+	}
+	for _, h := range reserved {
+		r.reserved[Host(h)] = true
+	}
+	return r
+}
+
+// Host normalizes a Host header: the port and a trailing dot are dropped
+// and the name is lowercased.
+func Host(hostport string) string {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
+
+// Reserved tells whether a domain belongs to the marketplace itself. IP
+// addresses count as reserved.
+func (r *Resolver) Reserved(domain string) bool {
+	host := Host(domain)
+	return host == "" || r.reserved[host] || net.ParseIP(host) != nil
+}
+
+// Resolve returns the ID of the seller whose storefront is served on the
+// request host, or 0 for the marketplace's own hosts and unknown domains.
+func (r *Resolver) Resolve(ctx context.Context, hostport string) (int, error) {
+	host := Host(hostport)
+	if r == nil || r.Reserved(host) {
+		return 0, nil
+	}
+
+	now := r.now()
+	r.mu.Lock()
+	cached, ok := r.cache[host]
+	r.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.sellerID, nil
+	}
+
+	sellerID, err := r.source.GetIDByDomain(ctx, host)
+	if err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	if len(r.cache) >= maxCached {
+		r.cache = make(map[string]cachedSeller)
+	}
+	r.cache[host] = cachedSeller{sellerID: sellerID, expires: now.Add(r.ttl)}
+	r.mu.Unlock()
+	return sellerID, nil
+}
+
+// Forget drops a domain from the cache, so changes made on this instance
+// apply right away.
+func (r *Resolver) Forget(domain string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	delete(r.cache, Host(domain))
+	r.mu.Unlock()
+}
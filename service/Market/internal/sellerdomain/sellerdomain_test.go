@@ -0,0 +1,91 @@
+package sellerdomain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type sourceFunc func(ctx context.Context, domain string) (int, error)
+
+func (f sourceFunc) GetIDByDomain(ctx context.Context, domain string) (int, error) {
+	return f(ctx, domain)
+}
+
+func TestHost(t *testing.T) {
+	require.Equal(t, "shop.example.com", Host("Shop.Example.com:8080"))
+	require.Equal(t, "shop.example.com", Host("shop.example.com."))
+	require.Equal(t, "::1", Host("[::1]:80"))
+	require.Equal(t, "", Host(""))
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var lookups []string
+	domains := map[string]int{"shop.example.com": 7}
+	source := sourceFunc(func(ctx context.Context, domain string) (int, error) {
+		lookups = append(lookups, domain)
+		return domains[domain], nil
+	})
+	r := NewResolver(source, []string{"market.example.com", "localhost"}, time.Minute)
+	r.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	id, err := r.Resolve(ctx, "SHOP.example.com:443")
+	require.NoError(t, err)
+	require.Equal(t, 7, id)
+
+	// Reserved hosts and IPs are never looked up.
+	for _, host := range []string{"market.example.com", "localhost:8080", "10.0.0.1:8080", "[::1]:80"} {
+		id, err = r.Resolve(ctx, host)
+		require.NoError(t, err)
+		require.Zero(t, id, host)
+	}
+
+	// Hits and misses are cached until the TTL passes.
+	id, err = r.Resolve(ctx, "other.example.com")
+	require.NoError(t, err)
+	require.Zero(t, id)
+	domains["shop.example.com"] = 0
+	_, _ = r.Resolve(ctx, "shop.example.com")
+	_, _ = r.Resolve(ctx, "other.example.com")
+	require.Equal(t, []string{"shop.example.com", "other.example.com"}, lookups)
+
+	now = now.Add(time.Minute + time.Second)
+	id, err = r.Resolve(ctx, "shop.example.com")
+	require.NoError(t, err)
+	require.Zero(t, id)
+	require.Len(t, lookups, 3)
+
+	// Forgotten domains are looked up again.
+	domains["other.example.com"] = 9
+	r.Forget("Other.Example.com")
+	id, err = r.Resolve(ctx, "other.example.com")
+	require.NoError(t, err)
+	require.Equal(t, 9, id)
+}
+
+func TestResolver_Error(t *testing.T) {
+	calls := 0
+	r := NewResolver(sourceFunc(func(ctx context.Context, domain string) (int, error) {
+		calls++
+		return 0, errors.New("db down")
+	}), nil, time.Minute)
+
+	_, err := r.Resolve(context.Background(), "shop.example.com")
+	require.Error(t, err)
+	_, err = r.Resolve(context.Background(), "shop.example.com")
+	require.Error(t, err)
+	require.Equal(t, 2, calls, "errors are not cached")
+}
+
+func TestResolver_Nil(t *testing.T) {
+	var r *Resolver
+	id, err := r.Resolve(context.Background(), "shop.example.com")
+	require.NoError(t, err)
+	require.Zero(t, id)
+	r.Forget("shop.example.com")
+}
@@ -0,0 +1,136 @@
+package sellerhealth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/workers"
+)
+
+// Store is what the enforcer reads health from and records actions in.
+type Store interface {
+	GetRules(ctx context.Context) ([]*models.SellerHealthRule, error)
+	GetAllCounts(ctx context.Context, window, shipWithin time.Duration) ([]*models.SellerHealthCounts, error)
+	// GetRecentActions returns the actions taken within the last period.
+	GetRecentActions(ctx context.Context, period time.Duration) ([]*models.SellerHealthAction, error)
+	// ApplyAction records the action, suspends the seller for suspend
+	// actions and sends message to the seller's user.
+	ApplyAction(ctx context.Context, sellerUserID int, action *models.SellerHealthAction, message string) error
+}
+
+// Enforcer applies the health rules to every active seller. A rule acts on
+// a seller at most once per cooldown, so warnings are not repeated on every
+// run and reinstated sellers get time to recover.
+type Enforcer struct {
+	store    Store
+	policy   Policy
+	cooldown time.Duration
+}
+
+func NewEnforcer(store Store, policy Policy, cooldown time.Duration) *Enforcer {
+	return &Enforcer{store: store, policy: policy, cooldown: cooldown}
+}
+
+type ruleKey struct{ sellerID, ruleID int }
+
+// RunOnce evaluates every active seller and applies the actions of the
+// rules they breach. A breached suspend rule takes precedence over
+// warnings. It returns how many actions were applied.
+func (e *Enforcer) RunOnce(ctx context.Context) (int, error) {
+	rules, err := e.store.GetRules(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(rules) == 0 {
+		return 0, nil
+	}
+
+	sellers, err := e.store.GetAllCounts(ctx, e.policy.Window, e.policy.ShipWithin)
+	if err != nil {
+		return 0, err
+	}
+
+	recent, err := e.store.GetRecentActions(ctx, e.cooldown)
+	if err != nil {
+		return 0, err
+	}
+	cooling := make(map[ruleKey]bool, len(recent))
+	for _, a := range recent {
+		if a.RuleID != nil {
+			cooling[ruleKey{a.SellerID, *a.RuleID}] = true
+		}
+	}
+
+	applied := 0
+	var errs []error
+	for _, counts := range sellers {
+		if !counts.IsActive {
+			continue
+		}
+		for _, b := range e.due(Evaluate(counts, rules, e.policy), cooling) {
+			ruleID := b.RuleID
+			action := &models.SellerHealthAction{
+				SellerID:  counts.SellerID,
+				RuleID:    &ruleID,
+				Action:    b.Action,
+				Metric:    b.Metric,
+				Value:     b.Value,
+				Threshold: b.Threshold,
+			}
+			if err := e.store.ApplyAction(ctx, counts.UserID, action, Message(b, e.policy)); err != nil {
+				logger.GetLogger().WithFields(map[string]interface{}{
+					"err":       err,
+					"seller_id": counts.SellerID,
+					"rule_id":   b.RuleID,
+				}).Error("failed to apply seller health action")
+				errs = append(errs, fmt.Errorf("seller %d: %w", counts.SellerID, err))
+				continue
+			}
+			metrics.SellerHealthActionsTotal.WithLabelValues(b.Action).Inc()
+			applied++
+		}
+	}
+
+	logger.GetLogger().WithField("actions", applied).Info("seller health rules enforced")
+	return applied, errors.Join(errs...)
+}
+
+// due picks the breaches to act on: the first suspension out of cooldown,
+// or else every warning out of cooldown.
+func (e *Enforcer) due(h *models.SellerHealth, cooling map[ruleKey]bool) []models.HealthBreach {
+	var warnings []models.HealthBreach
+	for _, b := range h.Breaches {
+		if cooling[ruleKey{h.SellerID, b.RuleID}] {
+			continue
+		}
+		if b.Action == models.HealthActionSuspend {
+			return []models.HealthBreach{b}
+		}
+		warnings = append(warnings, b)
+	}
+	return warnings
+}
+
+// Start runs the enforcer every interval until ctx is cancelled.
+func (e *Enforcer) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		workers.Beat(ctx)
+		if _, err := e.RunOnce(ctx); err != nil {
+			metrics.SellerHealthRunFailuresTotal.Inc()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Package sellerhealth scores sellers on cancellations, late shipments and
+// disputes, and enforces the admin-defined rules on them.
+package sellerhealth
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+)
+
+// Weights of each rate in the penalty taken off a perfect score of 100.
+// Disputes weigh most: they cost support time as well as a buyer.
+const (
+	cancellationWeight = 2
+	lateShipmentWeight = 1
+	disputeWeight      = 3
+)
+
+// Policy is how seller health is measured.
+type Policy struct {
+	// Window is how far back orders count.
+	Window time.Duration
+	// ShipWithin is how long after the order an item may ship before it is
+	// late. Unshipped items become late once it passes.
+	ShipWithin time.Duration
+}
+
+// WindowDays is the window in whole days.
+func (p Policy) WindowDays() int {
+	return int(p.Window / (24 * time.Hour))
+}
+
+// Score turns the rates into a 0..100 score: 100 minus the weighted rates
+// in percent, so 10% cancellations alone make 80.
+func Score(cancellationRate, lateShipmentRate, disputeRate float64) float64 {
+	penalty := cancellationWeight*cancellationRate + lateShipmentWeight*lateShipmentRate + disputeWeight*disputeRate
+	return round(math.Max(0, 100*(1-penalty)), 1)
+}
+
+// Evaluate computes a seller's health from the counts and lists the
+// enabled rules it breaches.
+func Evaluate(counts *models.SellerHealthCounts, rules []*models.SellerHealthRule, policy Policy) *models.SellerHealth {
+	h := &models.SellerHealth{
+		SellerID:        counts.SellerID,
+		WindowDays:      policy.WindowDays(),
+		Orders:          counts.Orders,
+		CancelledOrders: counts.Cancelled,
+		LateOrders:      counts.Late,
+		DisputedOrders:  counts.Disputed,
+		Score:           100,
+		Breaches:        []models.HealthBreach{},
+	}
+	if counts.Orders > 0 {
+		orders := float64(counts.Orders)
+		h.CancellationRate = round(float64(counts.Cancelled)/orders, 4)
+		h.LateShipmentRate = round(float64(counts.Late)/orders, 4)
+		h.DisputeRate = round(float64(counts.Disputed)/orders, 4)
+		h.Score = Score(h.CancellationRate, h.LateShipmentRate, h.DisputeRate)
+	}
+
+	for _, rule := range rules {
+		if !rule.Enabled || h.Orders < rule.MinOrders {
+			continue
+		}
+		value := Metric(h, rule.Metric)
+		breached := value > rule.Threshold
+		if rule.Metric == models.HealthMetricScore {
+			breached = value < rule.Threshold
+		}
+		if breached {
+			h.Breaches = append(h.Breaches, models.HealthBreach{
+				RuleID:    rule.ID,
+				Metric:    rule.Metric,
+				Value:     value,
+				Threshold: rule.Threshold,
+				Action:    rule.Action,
+			})
+		}
+	}
+	return h
+}
+
+// Metric returns the named metric of h, or 0 for unknown names.
+func Metric(h *models.SellerHealth, metric string) float64 {
+	switch metric {
+	case models.HealthMetricScore:
+		return h.Score
+	case models.HealthMetricCancellationRate:
+		return h.CancellationRate
+	case models.HealthMetricLateShipmentRate:
+		return h.LateShipmentRate
+	case models.HealthMetricDisputeRate:
+		return h.DisputeRate
+	}
+	return 0
+}
+
+// IsRate tells whether metric is a 0..1 rate rather than the score.
+func IsRate(metric string) bool {
+	return metric != models.HealthMetricScore
+}
+
+var metricLabels = map[string]string{
+	models.HealthMetricScore:            "health score",
+	models.HealthMetricCancellationRate: "cancellation rate",
+	models.HealthMetricLateShipmentRate: "late shipment rate",
+	models.HealthMetricDisputeRate:      "dispute rate",
+}
+
+func formatMetric(metric string, v float64) string {
+	if IsRate(metric) {
+		return fmt.Sprintf("%.1f%%", 100*v)
+	}
+	return fmt.Sprintf("%.1f", v)
+}
+
+// Message is the notification sent to a seller for a breach.
+func Message(b models.HealthBreach, policy Policy) string {
+	label := metricLabels[b.Metric]
+	value := formatMetric(b.Metric, b.Value)
+	limit := formatMetric(b.Metric, b.Threshold)
+	if b.Action == models.HealthActionSuspend {
+		return fmt.Sprintf("Your shop was suspended: your %s over the last %d days is %s, past the limit of %s. Contact support to appeal.",
+			label, policy.WindowDays(), value, limit)
+	}
+	return fmt.Sprintf("Your %s over the last %d days is %s, past the limit of %s. Shops that stay past their limits may be suspended.",
+		label, policy.WindowDays(), value, limit)
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
@@ -0,0 +1,147 @@
+package sellerhealth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+var testPolicy = Policy{Window: 90 * 24 * time.Hour, ShipWithin: 72 * time.Hour}
+
+func TestScore(t *testing.T) {
+	require.Equal(t, 100.0, Score(0, 0, 0))
+	require.Equal(t, 80.0, Score(0.1, 0, 0))
+	require.Equal(t, 90.0, Score(0, 0.1, 0))
+	require.Equal(t, 70.0, Score(0, 0, 0.1))
+	require.Equal(t, 0.0, Score(0.5, 0.5, 0.5))
+}
+
+func TestEvaluate(t *testing.T) {
+	rules := []*models.SellerHealthRule{
+		{ID: 1, Metric: models.HealthMetricCancellationRate, Threshold: 0.05, Action: models.HealthActionWarn, MinOrders: 10, Enabled: true},
+		{ID: 2, Metric: models.HealthMetricScore, Threshold: 60, Action: models.HealthActionSuspend, MinOrders: 10, Enabled: true},
+		{ID: 3, Metric: models.HealthMetricDisputeRate, Threshold: 0, Action: models.HealthActionWarn, Enabled: false},
+		{ID: 4, Metric: models.HealthMetricLateShipmentRate, Threshold: 0.2, Action: models.HealthActionWarn, MinOrders: 50, Enabled: true},
+	}
+
+	t.Run("breaching", func(t *testing.T) {
+		h := Evaluate(&models.SellerHealthCounts{SellerID: 7, Orders: 20, Cancelled: 4, Late: 2, Disputed: 1}, rules, testPolicy)
+		require.Equal(t, 90, h.WindowDays)
+		require.Equal(t, 0.2, h.CancellationRate)
+		require.Equal(t, 0.1, h.LateShipmentRate)
+		require.Equal(t, 0.05, h.DisputeRate)
+		require.Equal(t, 35.0, h.Score)
+		require.Equal(t, []models.HealthBreach{
+			{RuleID: 1, Metric: models.HealthMetricCancellationRate, Value: 0.2, Threshold: 0.05, Action: models.HealthActionWarn},
+			{RuleID: 2, Metric: models.HealthMetricScore, Value: 35, Threshold: 60, Action: models.HealthActionSuspend},
+		}, h.Breaches, "disabled rules and rules needing more orders are skipped")
+	})
+
+	t.Run("too few orders", func(t *testing.T) {
+		h := Evaluate(&models.SellerHealthCounts{SellerID: 7, Orders: 3, Cancelled: 3}, rules, testPolicy)
+		require.Equal(t, 1.0, h.CancellationRate)
+		require.Empty(t, h.Breaches)
+	})
+
+	t.Run("no orders", func(t *testing.T) {
+		h := Evaluate(&models.SellerHealthCounts{SellerID: 7}, rules, testPolicy)
+		require.Equal(t, 100.0, h.Score)
+		require.Empty(t, h.Breaches)
+	})
+}
+
+func TestMessage(t *testing.T) {
+	require.Equal(t,
+		"Your cancellation rate over the last 90 days is 20.0%, past the limit of 5.0%. Shops that stay past their limits may be suspended.",
+		Message(models.HealthBreach{Metric: models.HealthMetricCancellationRate, Value: 0.2, Threshold: 0.05, Action: models.HealthActionWarn}, testPolicy))
+	require.Equal(t,
+		"Your shop was suspended: your health score over the last 90 days is 45.0, past the limit of 60.0. Contact support to appeal.",
+		Message(models.HealthBreach{Metric: models.HealthMetricScore, Value: 45, Threshold: 60, Action: models.HealthActionSuspend}, testPolicy))
+}
+
+type fakeStore struct {
+	rules   []*models.SellerHealthRule
+	counts  []*models.SellerHealthCounts
+	recent  []*models.SellerHealthAction
+	applied []*models.SellerHealthAction
+	failFor int
+}
+
+func (f *fakeStore) GetRules(ctx context.Context) ([]*models.SellerHealthRule, error) {
+	return f.rules, nil
+}
+
+func (f *fakeStore) GetAllCounts(ctx context.Context, window, shipWithin time.Duration) ([]*models.SellerHealthCounts, error) {
+	return f.counts, nil
+}
+
+func (f *fakeStore) GetRecentActions(ctx context.Context, period time.Duration) ([]*models.SellerHealthAction, error) {
+	return f.recent, nil
+}
+
+func (f *fakeStore) ApplyAction(ctx context.Context, sellerUserID int, action *models.SellerHealthAction, message string) error {
+	if action.SellerID == f.failFor {
+		return errors.New("db down")
+	}
+	f.applied = append(f.applied, action)
+	return nil
+}
+
+func TestEnforcer_RunOnce(t *testing.T) {
+	ruleID := func(id int) *int { return &id }
+	store := &fakeStore{
+		rules: []*models.SellerHealthRule{
+			{ID: 1, Metric: models.HealthMetricCancellationRate, Threshold: 0.05, Action: models.HealthActionWarn, Enabled: true},
+			{ID: 2, Metric: models.HealthMetricLateShipmentRate, Threshold: 0.1, Action: models.HealthActionWarn, Enabled: true},
+			{ID: 3, Metric: models.HealthMetricCancellationRate, Threshold: 0.3, Action: models.HealthActionSuspend, Enabled: true},
+		},
+		counts: []*models.SellerHealthCounts{
+			{SellerID: 1, UserID: 11, IsActive: true, Orders: 10},                        // healthy
+			{SellerID: 2, UserID: 12, IsActive: true, Orders: 10, Cancelled: 1, Late: 2}, // two warnings
+			{SellerID: 3, UserID: 13, IsActive: true, Orders: 10, Cancelled: 5, Late: 2}, // suspended
+			{SellerID: 4, UserID: 14, IsActive: false, Orders: 10, Cancelled: 9},         // already inactive
+			{SellerID: 5, UserID: 15, IsActive: true, Orders: 10, Cancelled: 1, Late: 2}, // warned recently for rule 1
+		},
+		recent: []*models.SellerHealthAction{{SellerID: 5, RuleID: ruleID(1), Action: models.HealthActionWarn}},
+	}
+
+	applied, err := NewEnforcer(store, testPolicy, 7*24*time.Hour).RunOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 4, applied)
+
+	type got struct {
+		seller, rule int
+		action       string
+	}
+	var actions []got
+	for _, a := range store.applied {
+		actions = append(actions, got{a.SellerID, *a.RuleID, a.Action})
+	}
+	require.Equal(t, []got{
+		{2, 1, models.HealthActionWarn},
+		{2, 2, models.HealthActionWarn},
+		{3, 3, models.HealthActionSuspend},
+		{5, 2, models.HealthActionWarn},
+	}, actions)
+}
+
+func TestEnforcer_RunOnce_Errors(t *testing.T) {
+	store := &fakeStore{
+		rules: []*models.SellerHealthRule{
+			{ID: 1, Metric: models.HealthMetricCancellationRate, Threshold: 0.05, Action: models.HealthActionWarn, Enabled: true},
+		},
+		counts: []*models.SellerHealthCounts{
+			{SellerID: 1, UserID: 11, IsActive: true, Orders: 10, Cancelled: 1},
+			{SellerID: 2, UserID: 12, IsActive: true, Orders: 10, Cancelled: 1},
+		},
+		failFor: 1,
+	}
+
+	applied, err := NewEnforcer(store, testPolicy, time.Hour).RunOnce(context.Background())
+	require.Error(t, err)
+	require.Equal(t, 1, applied, "a failing seller does not stop the others")
+}
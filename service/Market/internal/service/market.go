@@ -2,20 +2,60 @@ package service
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/geocode"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/mailer"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
 	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/money"
 	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/settings"
+	"github.com/Zifeldev/marketback/service/Market/internal/shipping"
+	"github.com/Zifeldev/marketback/service/Market/internal/trending"
 )
 
 type MarketService struct {
-	orderRepo *repository.OrderRepository
-	cartRepo  *repository.CartRepository
+	orderRepo    *repository.OrderRepository
+	cartRepo     *repository.CartRepository
+	shippingRepo *repository.ShippingRepository
+	carrier      shipping.Limits
+	addresses    geocode.Provider
+	trending     *trending.Tracker
+	settings     *settings.Store
+	notifier     *mailer.Notifier
 }
 
-func NewMarketService(orderRepo *repository.OrderRepository, cartRepo *repository.CartRepository) *MarketService {
+const emailTimeout = 30 * time.Second
+
+// NewMarketService creates the market service. With a nil shippingRepo
+// orders are accepted without checking shipping restrictions; zero carrier
+// limits are not checked; with a nil address provider delivery addresses
+// are stored as entered; with a nil tracker purchases are not counted
+// towards trending products; with nil settings no minimum order amount is
+// enforced; with a nil notifier no order confirmation is emailed.
+func NewMarketService(
+	orderRepo *repository.OrderRepository,
+	cartRepo *repository.CartRepository,
+	shippingRepo *repository.ShippingRepository,
+	carrier shipping.Limits,
+	addresses geocode.Provider,
+	tracker *trending.Tracker,
+	siteSettings *settings.Store,
+	notifier *mailer.Notifier,
+) *MarketService {
 	return &MarketService{
-		orderRepo: orderRepo,
-		cartRepo:  cartRepo,
+		orderRepo:    orderRepo,
+		cartRepo:     cartRepo,
+		shippingRepo: shippingRepo,
+		carrier:      carrier,
+		addresses:    addresses,
+		trending:     tracker,
+		settings:     siteSettings,
+		notifier:     notifier,
 	}
 }
 
@@ -29,7 +69,207 @@ func (s *MarketService) CreateOrder(ctx context.Context, userID int, req *models
 		return nil, ErrEmptyCart
 	}
 
-	return s.orderRepo.Create(ctx, userID, req, cartItems)
+	if err := s.checkMinimumOrder(ctx, cartItems); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateAddress(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkShipping(ctx, req, cartItems); err != nil {
+		return nil, err
+	}
+
+	pkg := s.Package(cartItems)
+	if msg := shipping.Message(pkg); msg != "" {
+		return nil, apperrors.PackageTooLarge(msg, pkg)
+	}
+	req.Package = pkg
+
+	order, err := s.orderRepo.Create(ctx, userID, req, cartItems)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range order.Items {
+		if err := s.trending.RecordPurchase(ctx, item.ProductID, item.Quantity); err != nil {
+			logger.FromContext(ctx).WithField("err", err).Warn("failed to record trending purchase")
+			break
+		}
+	}
+
+	s.sendConfirmation(req.Email, order, cartItems)
+
+	return order, nil
+}
+
+// CancelOrder cancels the user's own order while its cancellation window
+// is open.
+func (s *MarketService) CancelOrder(ctx context.Context, userID, orderID int) (*models.OrderWithItems, error) {
+	return s.orderRepo.CancelByBuyer(ctx, orderID, userID, s.settings.OrderCancellationWindow(ctx))
+}
+
+// SetCancellationWindow fills in how long the buyer has left to cancel
+// order; orders that cannot be cancelled are left as they are.
+func (s *MarketService) SetCancellationWindow(ctx context.Context, order *models.OrderWithItems) error {
+	if order.CancelBlocker() != "" {
+		return nil
+	}
+
+	deadline, left, err := s.orderRepo.CancellationDeadline(ctx, order.ID, s.settings.OrderCancellationWindow(ctx))
+	if err != nil {
+		return err
+	}
+	order.SetCancellation(deadline, left)
+	return nil
+}
+
+// orderConfirmationEmail is the data of the order_confirmation email
+// template.
+type orderConfirmationEmail struct {
+	OrderNumber     string
+	Items           []orderConfirmationItem
+	Total           string
+	DeliveryAddress string
+}
+
+type orderConfirmationItem struct {
+	Quantity int
+	Title    string
+	Price    string
+}
+
+// sendConfirmation emails the order summary to the buyer in the background.
+func (s *MarketService) sendConfirmation(to string, order *models.OrderWithItems, cartItems []*models.CartItemWithDetails) {
+	if !s.notifier.Enabled(mailer.EventOrderConfirmation) || to == "" {
+		return
+	}
+
+	data := orderConfirmationEmail{
+		OrderNumber:     order.OrderNumber,
+		Items:           make([]orderConfirmationItem, len(cartItems)),
+		Total:           money.FromFloat(order.TotalAmount).String(),
+		DeliveryAddress: order.DeliveryAddr,
+	}
+	for i, item := range cartItems {
+		data.Items[i] = orderConfirmationItem{
+			Quantity: item.Quantity,
+			Title:    item.ProductTitle,
+			Price:    money.FromFloat(item.ProductPrice).String(),
+		}
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), emailTimeout)
+		defer cancel()
+		if err := s.notifier.Notify(ctx, mailer.EventOrderConfirmation, to, data); err != nil {
+			logger.GetLogger().WithFields(map[string]interface{}{
+				"err":          err,
+				"order_number": order.OrderNumber,
+			}).Error("failed to send order confirmation")
+		}
+	}()
+}
+
+// checkMinimumOrder rejects carts below the configured minimum order amount.
+func (s *MarketService) checkMinimumOrder(ctx context.Context, cartItems []*models.CartItemWithDetails) error {
+	if s.settings == nil {
+		return nil
+	}
+	minimum := s.settings.MinOrderAmount(ctx)
+	if minimum <= 0 {
+		return nil
+	}
+
+	lines := make([]money.Line, len(cartItems))
+	for i, item := range cartItems {
+		lines[i] = money.Line{Price: money.FromFloat(item.ProductPrice), Quantity: item.Quantity}
+	}
+	total, err := money.Total(lines)
+	if err != nil {
+		return err
+	}
+	if total < money.FromFloat(minimum) {
+		return apperrors.BelowMinimumOrder(minimum, total.Float())
+	}
+	return nil
+}
+
+// validateAddress normalizes the delivery address through the configured
+// provider and records its coordinates. Unknown addresses are rejected;
+// provider outages are logged and the address is kept as entered so that
+// checkout does not depend on a third-party API.
+func (s *MarketService) validateAddress(ctx context.Context, req *models.CreateOrderRequest) error {
+	if s.addresses == nil {
+		return nil
+	}
+
+	addr, err := s.addresses.Validate(ctx, req.DeliveryAddr)
+	switch {
+	case errors.Is(err, geocode.ErrNotFound):
+		metrics.AddressValidationsTotal.WithLabelValues(s.addresses.Name(), "not_found").Inc()
+		return apperrors.ValidationError("delivery_address", err.Error())
+	case err != nil:
+		metrics.AddressValidationsTotal.WithLabelValues(s.addresses.Name(), "error").Inc()
+		logger.FromContext(ctx).WithField("err", err).Warn("address validation unavailable, keeping address as entered")
+		return nil
+	}
+	metrics.AddressValidationsTotal.WithLabelValues(s.addresses.Name(), "ok").Inc()
+
+	req.DeliveryAddr = addr.Formatted
+	req.DeliveryLat = addr.Lat
+	req.DeliveryLng = addr.Lng
+	// The geocoded country wins over the client's so shipping restrictions
+	// cannot be bypassed by declaring a different one.
+	if addr.Country != "" {
+		req.DeliveryCountry = addr.Country
+		req.DeliveryRegion = addr.Region
+	}
+	return nil
+}
+
+// checkShipping rejects the order when any cart item cannot be delivered to
+// the requested country or region, listing every blocked item.
+func (s *MarketService) checkShipping(ctx context.Context, req *models.CreateOrderRequest, cartItems []*models.CartItemWithDetails) error {
+	if s.shippingRepo == nil {
+		return nil
+	}
+
+	productIDs := make([]int, 0, len(cartItems))
+	for _, item := range cartItems {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	restrictions, err := s.shippingRepo.GetForProducts(ctx, productIDs)
+	if err != nil {
+		return err
+	}
+	if len(restrictions) == 0 {
+		return nil
+	}
+
+	if req.DeliveryCountry == "" {
+		return apperrors.ValidationError("delivery_country", "required because some items have shipping restrictions")
+	}
+	dest, err := shipping.NewDestination(req.DeliveryCountry, req.DeliveryRegion)
+	if err != nil {
+		return apperrors.ValidationError("delivery_country", err.Error())
+	}
+
+	if blocked := shipping.BlockedItems(cartItems, restrictions, dest); len(blocked) > 0 {
+		return apperrors.ShippingRestricted(dest.String(), blocked)
+	}
+	return nil
+}
+
+// Package computes the parcel cartItems ship in, checked against the
+// carrier's limits. A nil service checks no limits.
+func (s *MarketService) Package(cartItems []*models.CartItemWithDetails) *models.ShippingPackage {
+	var limits shipping.Limits
+	if s != nil {
+		limits = s.carrier
+	}
+	return shipping.Package(cartItems, limits)
 }
 
 var ErrEmptyCart = &ServiceError{Message: "cart is empty"}
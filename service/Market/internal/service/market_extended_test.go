@@ -8,7 +8,10 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/mailer"
 	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/settings"
 )
 
 // Extended service layer tests
@@ -190,3 +193,43 @@ func TestMarketService_CreateOrder_LargeQuantity(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expectedTotal, order.TotalAmount)
 }
+
+func TestMarketService_CheckMinimumOrder(t *testing.T) {
+	siteSettings := settings.New(nil, nil)
+	siteSettings.SetDefault(settings.MinOrderAmount, "30")
+	s := &MarketService{settings: siteSettings}
+
+	items := []*models.CartItemWithDetails{{
+		CartItem:     models.CartItem{ProductID: 1, Quantity: 2},
+		ProductPrice: 12.5,
+	}}
+	err := s.checkMinimumOrder(context.Background(), items)
+	appErr := apperrors.GetAppError(err)
+	require.NotNil(t, appErr)
+	require.Equal(t, apperrors.CodeBelowMinimumOrder, appErr.Code)
+
+	items[0].Quantity = 3
+	require.NoError(t, s.checkMinimumOrder(context.Background(), items))
+
+	require.NoError(t, (&MarketService{}).checkMinimumOrder(context.Background(), items[:0]))
+}
+
+func TestMarketService_SendConfirmation(t *testing.T) {
+	outbox, err := mailer.NewOutbox("shop@example.com", "", 10)
+	require.NoError(t, err)
+	notifier, err := mailer.NewNotifier(outbox, "", nil)
+	require.NoError(t, err)
+	s := &MarketService{notifier: notifier}
+
+	order := &models.OrderWithItems{Order: models.Order{OrderNumber: "MB-2024-000042", TotalAmount: 25, DeliveryAddr: "1 Main St"}}
+	items := []*models.CartItemWithDetails{{CartItem: models.CartItem{Quantity: 2}, ProductTitle: "Hat", ProductPrice: 12.5}}
+
+	s.sendConfirmation("", order, items)
+	s.sendConfirmation("buyer@example.com", order, items)
+
+	require.Eventually(t, func() bool { return len(outbox.Messages("")) == 1 }, 5*time.Second, 10*time.Millisecond)
+	msg := outbox.Messages("buyer@example.com")[0]
+	require.Equal(t, "Order MB-2024-000042 confirmed", msg.Subject)
+	require.Contains(t, msg.Body, "2 × Hat  12.50")
+	require.Contains(t, msg.Body, "Total: 25.00")
+}
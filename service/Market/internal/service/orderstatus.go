@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+)
+
+// UpdateOrderStatus moves an order to status on behalf of adminID and
+// records the change in the order's status history. Transitions the status
+// machine, models.CanMoveOrder, does not allow are rejected with a
+// conflict.
+func (s *MarketService) UpdateOrderStatus(ctx context.Context, orderID int, status string, adminID int) (*models.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !models.CanMoveOrder(order.Status, status) {
+		return nil, apperrors.Conflict(fmt.Sprintf("order cannot move from %s to %s", order.Status, status))
+	}
+
+	return s.orderRepo.UpdateStatus(ctx, orderID, order.Status, status, adminID)
+}
@@ -0,0 +1,504 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/money"
+	"github.com/Zifeldev/marketback/service/Market/internal/payment"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+)
+
+// payLockTTL bounds how long an attempt that never finished, e.g. on an
+// instance that crashed, keeps its order from being paid. It outlasts the
+// provider's timeout, so the lock of a live attempt does not expire.
+const payLockTTL = 2 * time.Minute
+
+// PaymentService pays and refunds orders through a payment provider and
+// applies the provider's asynchronous events. An order may be paid partly
+// by gift card and store credit, with the provider charged for the rest.
+// With providers that implement payment.Vault, buyers may save the method
+// they pay with and pay later orders with it in one click. Charges the
+// buyer's bank wants authenticated, e.g. by 3-D Secure, wait for the buyer
+// to pass the challenge and come back to returnURL. Chargebacks of card
+// payments hold the sellers' payouts until the provider decides them.
+type PaymentService struct {
+	provider    payment.Provider
+	payments    repository.PaymentRepo
+	methods     repository.PaymentMethodRepo
+	orders      repository.OrderRepo
+	chargebacks repository.ChargebackRepo
+	returnURL   string
+}
+
+func NewPaymentService(provider payment.Provider, payments repository.PaymentRepo, methods repository.PaymentMethodRepo, orders repository.OrderRepo, chargebacks repository.ChargebackRepo, returnURL string) *PaymentService {
+	return &PaymentService{provider: provider, payments: payments, methods: methods, orders: orders, chargebacks: chargebacks, returnURL: returnURL}
+}
+
+// Pay charges the user's order, spending the gift card and store credit
+// of the request first and charging the card for what they leave: a saved
+// method, or the one the buyer enters, saved when asked. A failed attempt
+// may be retried; an order that is paid, has a charge pending or is being
+// paid by another request may not.
+// A charge the buyer has to authenticate requires action: the buyer
+// follows its action_url and then confirms the payment.
+func (s *PaymentService) Pay(ctx context.Context, userID, orderID int, req *models.PayOrderRequest) (*models.OrderPayments, error) {
+	order, err := s.userOrder(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status == "cancelled" {
+		return nil, apperrors.Conflict("cancelled orders cannot be paid")
+	}
+
+	// Claimed before its payments are checked, so that a concurrent attempt
+	// cannot pass the same check and charge the order again
+	if err := s.payments.Lock(ctx, orderID, payLockTTL); err != nil {
+		return nil, err
+	}
+	defer s.unlock(ctx, orderID)
+
+	payments, err := s.payments.ListForOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range payments {
+		switch p.Status {
+		case models.PaymentStatusPending, models.PaymentStatusRequiresAction:
+			return nil, apperrors.Conflict("a payment for this order is already in progress")
+		case models.PaymentStatusPaid, models.PaymentStatusRefunded:
+			return nil, apperrors.Conflict("order is already paid")
+		}
+	}
+
+	chargeReq := payment.ChargeRequest{OrderNumber: order.OrderNumber, Scenario: req.Scenario, ReturnURL: s.orderReturnURL(orderID)}
+	if err := s.useVault(ctx, userID, req, &chargeReq); err != nil {
+		return nil, err
+	}
+
+	held, due, err := s.payments.Redeem(ctx, orderID, userID, order.TotalAmount, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var clientSecret string
+	if due > 0 {
+		chargeReq.Amount = money.FromFloat(due)
+		charge, err := s.provider.Charge(ctx, chargeReq)
+		if err != nil {
+			s.release(ctx, held)
+			return nil, fmt.Errorf("failed to charge order %d: %w", orderID, err)
+		}
+
+		card := &models.Payment{
+			OrderID:     orderID,
+			Method:      models.PaymentMethodCard,
+			Provider:    s.provider.Name(),
+			ProviderRef: charge.Ref,
+			Amount:      due,
+			Status:      charge.Status,
+		}
+		if charge.Action != nil {
+			card.ActionType, card.ActionURL = charge.Action.Type, charge.Action.URL
+		}
+		if _, err := s.payments.Create(ctx, card); err != nil {
+			s.release(ctx, held)
+			return nil, err
+		}
+		clientSecret = charge.ClientSecret
+		if charge.PaymentMethod != "" {
+			s.saveMethod(ctx, userID, chargeReq.Customer, charge.PaymentMethod)
+		}
+	}
+
+	result, err := s.orderPayments(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range result.Payments {
+		if p.Method == models.PaymentMethodCard && (p.Status == models.PaymentStatusPending || p.Status == models.PaymentStatusRequiresAction) {
+			p.ClientSecret = clientSecret
+		}
+	}
+	return result, nil
+}
+
+// ConfirmPayment asks the provider how the order's card charge went once
+// the buyer is back from authenticating it, and settles the payment when
+// the charge is paid or failed. Charges still waiting are left as they are.
+func (s *PaymentService) ConfirmPayment(ctx context.Context, userID, orderID int) (*models.OrderPayments, error) {
+	if _, err := s.userOrder(ctx, userID, orderID); err != nil {
+		return nil, err
+	}
+	confirmer, ok := s.provider.(payment.Confirmer)
+	if !ok {
+		return nil, apperrors.BadRequest(fmt.Sprintf("payment provider %s cannot confirm payments", s.provider.Name()))
+	}
+
+	payments, err := s.payments.ListForOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	var card *models.Payment
+	for _, p := range payments {
+		if p.Method == models.PaymentMethodCard && (p.Status == models.PaymentStatusPending || p.Status == models.PaymentStatusRequiresAction) {
+			card = p
+		}
+	}
+	if card == nil {
+		return nil, apperrors.Conflict("order has no payment waiting for confirmation")
+	}
+
+	ev, err := confirmer.Confirm(ctx, card.ProviderRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to confirm payment of order %d: %w", orderID, err)
+	}
+	if ev.Status == models.PaymentStatusPaid || ev.Status == models.PaymentStatusFailed {
+		if _, err := s.HandleEvent(ctx, *ev); err != nil {
+			return nil, err
+		}
+	}
+	return s.orderPayments(ctx, orderID)
+}
+
+// orderReturnURL is where the buyer comes back to after authenticating the
+// order's charge, or "" when no return URL is configured.
+func (s *PaymentService) orderReturnURL(orderID int) string {
+	if s.returnURL == "" {
+		return ""
+	}
+	u, err := url.Parse(s.returnURL)
+	if err != nil {
+		return s.returnURL
+	}
+	q := u.Query()
+	q.Set("order_id", fmt.Sprint(orderID))
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// Payments returns the payments of the user's order.
+func (s *PaymentService) Payments(ctx context.Context, userID, orderID int) (*models.OrderPayments, error) {
+	if _, err := s.userOrder(ctx, userID, orderID); err != nil {
+		return nil, err
+	}
+	return s.orderPayments(ctx, orderID)
+}
+
+func (s *PaymentService) userOrder(ctx context.Context, userID, orderID int) (*models.OrderWithItems, error) {
+	order, err := s.orders.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, apperrors.OrderNotFound(orderID)
+	}
+	return order, nil
+}
+
+func (s *PaymentService) orderPayments(ctx context.Context, orderID int) (*models.OrderPayments, error) {
+	payments, err := s.payments.ListForOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.OrderPayments{
+		OrderID:       orderID,
+		PaymentStatus: models.OrderPaymentStatus(payments),
+		Payments:      payments,
+	}, nil
+}
+
+// useVault sets up a charge of the saved method the request names, or one
+// that saves the buyer's method, under the buyer's customer at the
+// provider.
+func (s *PaymentService) useVault(ctx context.Context, userID int, req *models.PayOrderRequest, charge *payment.ChargeRequest) error {
+	if req.SavedPaymentMethodID == 0 && !req.SaveMethod {
+		return nil
+	}
+	if req.SavedPaymentMethodID != 0 && req.SaveMethod {
+		return apperrors.ValidationError("save_method", "cannot be used with saved_payment_method_id")
+	}
+	vault, ok := s.provider.(payment.Vault)
+	if !ok {
+		return apperrors.BadRequest(fmt.Sprintf("payment provider %s cannot save payment methods", s.provider.Name()))
+	}
+
+	if req.SavedPaymentMethodID != 0 {
+		method, err := s.methods.Get(ctx, req.SavedPaymentMethodID, userID)
+		if err != nil {
+			return err
+		}
+		if method.Provider != s.provider.Name() {
+			return apperrors.ValidationError("saved_payment_method_id", "payment method was saved with another provider")
+		}
+		charge.Customer, charge.PaymentMethod = method.CustomerRef, method.MethodRef
+		return nil
+	}
+
+	customer, err := s.methods.GetCustomer(ctx, userID, s.provider.Name())
+	if err != nil {
+		return err
+	}
+	if customer == "" {
+		created, err := vault.CreateCustomer(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to create payment customer for user %d: %w", userID, err)
+		}
+		if customer, err = s.methods.SaveCustomer(ctx, userID, s.provider.Name(), created); err != nil {
+			return err
+		}
+	}
+	charge.Customer, charge.SaveMethod = customer, true
+	return nil
+}
+
+// saveMethod keeps the payment method of a paid charge. The payment stands
+// when this fails; the buyer only has to enter the method again.
+func (s *PaymentService) saveMethod(ctx context.Context, userID int, customer, method string) {
+	_, err := s.methods.Save(ctx, &models.SavedPaymentMethod{
+		UserID:      userID,
+		Provider:    s.provider.Name(),
+		CustomerRef: customer,
+		MethodRef:   method,
+	})
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Errorf("failed to save payment method of user %d", userID)
+	}
+}
+
+// PaymentMethods returns the user's saved payment methods.
+func (s *PaymentService) PaymentMethods(ctx context.Context, userID int) ([]*models.SavedPaymentMethod, error) {
+	return s.methods.List(ctx, userID)
+}
+
+// DeletePaymentMethod deletes one of the user's saved payment methods at
+// the provider and forgets it.
+func (s *PaymentService) DeletePaymentMethod(ctx context.Context, userID, id int) error {
+	method, err := s.methods.Get(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if vault, ok := s.provider.(payment.Vault); ok && method.Provider == s.provider.Name() {
+		if err := vault.DetachMethod(ctx, method.MethodRef); err != nil {
+			return fmt.Errorf("failed to detach payment method %d: %w", id, err)
+		}
+	}
+	return s.methods.Delete(ctx, id, userID)
+}
+
+// unlock ends the order's payment attempt, even when the request that made
+// it was cancelled.
+func (s *PaymentService) unlock(ctx context.Context, orderID int) {
+	if err := s.payments.Unlock(context.WithoutCancel(ctx), orderID); err != nil {
+		logger.GetLogger().WithField("err", err).Errorf("failed to unlock order %d for payment", orderID)
+	}
+}
+
+// release voids the balance payments held for a card charge that was
+// never recorded, giving their amounts back.
+func (s *PaymentService) release(ctx context.Context, held []*models.Payment) {
+	for _, p := range held {
+		if p.Status != models.PaymentStatusPending {
+			continue
+		}
+		if _, err := s.payments.Settle(ctx, p.ID, models.PaymentStatusVoided); err != nil {
+			logger.GetLogger().WithField("err", err).Errorf("failed to void %s payment %d", p.Method, p.ID)
+		}
+	}
+}
+
+// HandleWebhook verifies and applies an event delivered to the provider's
+// webhook: it settles a payment or records a dispute of one. It returns a
+// nil payment for events the provider reports that do neither.
+func (s *PaymentService) HandleWebhook(ctx context.Context, payload []byte, header http.Header) (*models.Payment, error) {
+	hooks, ok := s.provider.(payment.Webhooks)
+	if !ok {
+		return nil, apperrors.NotFound(fmt.Sprintf("payment provider %s has no webhook", s.provider.Name()))
+	}
+
+	ev, err := hooks.ParseEvent(payload, header)
+	if err != nil {
+		if errors.Is(err, payment.ErrBadSignature) {
+			return nil, apperrors.BadRequest("invalid webhook signature")
+		}
+		return nil, apperrors.BadRequest(err.Error())
+	}
+	if ev == nil {
+		return nil, nil
+	}
+	if ev.Dispute != nil {
+		return s.HandleDispute(ctx, *ev)
+	}
+	return s.HandleEvent(ctx, *ev)
+}
+
+// HandleEvent settles a pending payment, saving its payment method when
+// the charge asked to. Events for payments that are already settled are
+// ignored, so providers may deliver them twice.
+func (s *PaymentService) HandleEvent(ctx context.Context, ev payment.Event) (*models.Payment, error) {
+	p, err := s.payments.GetByRef(ctx, ev.Ref)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil || p.Method != models.PaymentMethodCard {
+		return nil, apperrors.NotFound(fmt.Sprintf("payment %s not found", ev.Ref))
+	}
+
+	settled, err := s.payments.Settle(ctx, p.ID, ev.Status)
+	if err != nil {
+		return nil, err
+	}
+	if settled == nil {
+		return p, nil
+	}
+
+	if ev.PaymentMethod != "" && settled.Status == models.PaymentStatusPaid {
+		order, err := s.orders.GetByID(ctx, settled.OrderID)
+		if err != nil {
+			logger.GetLogger().WithField("err", err).Errorf("failed to save payment method of order %d", settled.OrderID)
+		} else {
+			s.saveMethod(ctx, order.UserID, ev.Customer, ev.PaymentMethod)
+		}
+	}
+	return settled, nil
+}
+
+// HandleDispute records the chargeback an event reports against a card
+// payment, opening it the first time it is reported and deciding it once
+// the provider has. It returns the disputed payment.
+func (s *PaymentService) HandleDispute(ctx context.Context, ev payment.Event) (*models.Payment, error) {
+	p, err := s.payments.GetByRef(ctx, ev.Ref)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil || p.Method != models.PaymentMethodCard {
+		return nil, apperrors.NotFound(fmt.Sprintf("payment %s not found", ev.Ref))
+	}
+
+	cb, err := s.chargebacks.GetByRef(ctx, p.Provider, ev.Dispute.Ref)
+	if err != nil {
+		return nil, err
+	}
+	if cb == nil {
+		var dueBy *time.Time
+		if !ev.Dispute.EvidenceDueBy.IsZero() {
+			dueBy = &ev.Dispute.EvidenceDueBy
+		}
+		if cb, err = s.openChargeback(ctx, p, ev.Dispute.Ref, ev.Dispute.Amount, ev.Dispute.Reason, dueBy); err != nil {
+			return nil, err
+		}
+	}
+	if ev.Dispute.Status != models.ChargebackStatusOpen {
+		if _, err := s.chargebacks.Resolve(ctx, cb.ID, ev.Dispute.Status); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// OpenChargeback records a chargeback the provider reported outside its
+// webhook against the order's paid card payment.
+func (s *PaymentService) OpenChargeback(ctx context.Context, req *models.CreateChargebackRequest) (*models.Chargeback, error) {
+	payments, err := s.payments.ListForOrder(ctx, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	var card *models.Payment
+	for _, p := range payments {
+		if p.Method == models.PaymentMethodCard && (p.Status == models.PaymentStatusPaid || p.Status == models.PaymentStatusRefunded) {
+			card = p
+		}
+	}
+	if card == nil {
+		return nil, apperrors.Conflict("order has no paid card payment")
+	}
+	return s.openChargeback(ctx, card, req.ProviderRef, money.FromFloat(req.Amount), req.Reason, req.EvidenceDueBy)
+}
+
+// openChargeback holds amount of the card payment from the sellers'
+// payouts. It cannot exceed the payment.
+func (s *PaymentService) openChargeback(ctx context.Context, p *models.Payment, ref string, amount money.Cents, reason string, dueBy *time.Time) (*models.Chargeback, error) {
+	if p.Status != models.PaymentStatusPaid && p.Status != models.PaymentStatusRefunded {
+		return nil, apperrors.Conflict("only paid payments can be charged back")
+	}
+	if amount <= 0 || amount > money.FromFloat(p.Amount) {
+		return nil, apperrors.ValidationError("amount", fmt.Sprintf("must be positive and at most the payment's %s", money.FromFloat(p.Amount)))
+	}
+	return s.chargebacks.Open(ctx, &models.Chargeback{
+		PaymentID:     p.ID,
+		OrderID:       p.OrderID,
+		Provider:      p.Provider,
+		ProviderRef:   ref,
+		Amount:        amount.Float(),
+		Reason:        reason,
+		EvidenceDueBy: dueBy,
+	})
+}
+
+// ResolveChargeback decides an open chargeback won or lost, releasing or
+// debiting the sellers' holds.
+func (s *PaymentService) ResolveChargeback(ctx context.Context, id int, status string) (*models.Chargeback, error) {
+	return s.chargebacks.Resolve(ctx, id, status)
+}
+
+// Refund gives back amount of the order's payments, or all that is left of
+// them when amount is zero. The most recent payments are refunded first,
+// so the card is refunded before store credit and gift cards, which get
+// their amounts back as balance.
+func (s *PaymentService) Refund(ctx context.Context, orderID int, req *models.RefundPaymentRequest) (*models.OrderPayments, error) {
+	payments, err := s.payments.ListForOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var refundable []*models.Payment
+	var paid, refunded money.Cents
+	for _, p := range payments {
+		if p.Status == models.PaymentStatusPaid {
+			refundable = append(refundable, p)
+			paid += money.FromFloat(p.Amount)
+			refunded += money.FromFloat(p.RefundedAmount)
+		}
+	}
+	if len(refundable) == 0 {
+		return nil, apperrors.Conflict("order has no refundable payment")
+	}
+
+	amount := paid - refunded
+	if req.Amount > 0 {
+		amount = money.FromFloat(req.Amount)
+	}
+	if err := money.CheckRefund(paid, refunded, amount); err != nil {
+		return nil, apperrors.BadRequest(fmt.Sprintf("refund exceeds the refundable amount of %s", paid-refunded))
+	}
+
+	for i := len(refundable) - 1; i >= 0 && amount > 0; i-- {
+		p := refundable[i]
+		part := min(amount, money.FromFloat(p.Amount)-money.FromFloat(p.RefundedAmount))
+		if part <= 0 {
+			continue
+		}
+
+		if p.Method == models.PaymentMethodCard {
+			if err := s.provider.Refund(ctx, p.ProviderRef, part); err != nil {
+				if errors.Is(err, payment.ErrDeclined) {
+					return nil, apperrors.Conflict("refund was declined by the payment provider")
+				}
+				return nil, fmt.Errorf("failed to refund order %d: %w", orderID, err)
+			}
+		}
+		if _, err := s.payments.AddRefund(ctx, p.ID, part.Float()); err != nil {
+			return nil, err
+		}
+		amount -= part
+	}
+
+	return s.orderPayments(ctx, orderID)
+}
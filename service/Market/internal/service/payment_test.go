@@ -0,0 +1,735 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/payment"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+)
+
+// fakePayments keeps payments in memory, with the same unsettled-only
+// settle and bounded refund rules as the database. Redeem spends the
+// GIFT10 card's and the user's store credit balances. Orders are locked
+// for payment until unlocked, whatever the ttl.
+type fakePayments struct {
+	byID        map[int]*models.Payment
+	giftCard    float64
+	storeCredit float64
+
+	mu     sync.Mutex
+	locked map[int]bool
+}
+
+var _ repository.PaymentRepo = (*fakePayments)(nil)
+
+func newFakePayments(payments ...*models.Payment) *fakePayments {
+	f := &fakePayments{byID: map[int]*models.Payment{}}
+	for _, p := range payments {
+		f.byID[p.ID] = p
+	}
+	return f
+}
+
+func (f *fakePayments) Create(ctx context.Context, p *models.Payment) (*models.Payment, error) {
+	p.ID = len(f.byID) + 1
+	f.byID[p.ID] = p
+	f.settleHolds(p)
+	return p, nil
+}
+
+func (f *fakePayments) Lock(ctx context.Context, orderID int, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.locked[orderID] {
+		return apperrors.Conflict("a payment for this order is already in progress")
+	}
+	if f.locked == nil {
+		f.locked = map[int]bool{}
+	}
+	f.locked[orderID] = true
+	return nil
+}
+
+func (f *fakePayments) Unlock(ctx context.Context, orderID int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.locked, orderID)
+	return nil
+}
+
+func (f *fakePayments) Redeem(ctx context.Context, orderID, userID int, due float64, req *models.PayOrderRequest) ([]*models.Payment, float64, error) {
+	var spent []*models.Payment
+	if req.GiftCardCode != "" {
+		if req.GiftCardCode != "GIFT10" {
+			return nil, 0, apperrors.ValidationError("gift_card_code", "unknown or expired gift card")
+		}
+		amount := min(due, f.giftCard)
+		f.giftCard -= amount
+		due -= amount
+		spent = append(spent, &models.Payment{Method: models.PaymentMethodGiftCard, Amount: amount})
+	}
+	if req.UseStoreCredit && due > 0 && f.storeCredit > 0 {
+		amount := min(due, f.storeCredit)
+		f.storeCredit -= amount
+		due -= amount
+		spent = append(spent, &models.Payment{Method: models.PaymentMethodStoreCredit, Amount: amount})
+	}
+
+	for _, p := range spent {
+		p.ID, p.OrderID, p.Provider, p.Status = len(f.byID)+1, orderID, p.Method, models.PaymentStatusPending
+		if due == 0 {
+			p.Status = models.PaymentStatusPaid
+		}
+		f.byID[p.ID] = p
+	}
+	return spent, due, nil
+}
+
+func (f *fakePayments) settleHolds(card *models.Payment) {
+	for _, p := range f.byID {
+		if p.Method == models.PaymentMethodCard || p.Status != models.PaymentStatusPending || p.OrderID != card.OrderID {
+			continue
+		}
+		switch card.Status {
+		case models.PaymentStatusPaid:
+			p.Status = models.PaymentStatusPaid
+		case models.PaymentStatusFailed:
+			p.Status = models.PaymentStatusVoided
+			f.giveBack(p, p.Amount)
+		}
+	}
+}
+
+func (f *fakePayments) giveBack(p *models.Payment, amount float64) {
+	switch p.Method {
+	case models.PaymentMethodGiftCard:
+		f.giftCard += amount
+	case models.PaymentMethodStoreCredit:
+		f.storeCredit += amount
+	}
+}
+
+func (f *fakePayments) GetByRef(ctx context.Context, ref string) (*models.Payment, error) {
+	for _, p := range f.byID {
+		if p.ProviderRef == ref {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakePayments) ListForOrder(ctx context.Context, orderID int) ([]*models.Payment, error) {
+	payments := []*models.Payment{}
+	for id := 1; id <= len(f.byID); id++ {
+		if p, ok := f.byID[id]; ok && p.OrderID == orderID {
+			payments = append(payments, p)
+		}
+	}
+	return payments, nil
+}
+
+func (f *fakePayments) Settle(ctx context.Context, id int, status string) (*models.Payment, error) {
+	p := f.byID[id]
+	if p.Status != models.PaymentStatusPending && p.Status != models.PaymentStatusRequiresAction {
+		return nil, nil
+	}
+	p.Status, p.ActionType, p.ActionURL = status, "", ""
+	f.settleHolds(p)
+	if status == models.PaymentStatusVoided {
+		f.giveBack(p, p.Amount)
+	}
+	return p, nil
+}
+
+func (f *fakePayments) AddRefund(ctx context.Context, id int, amount float64) (*models.Payment, error) {
+	p := f.byID[id]
+	p.RefundedAmount += amount
+	if p.RefundedAmount >= p.Amount {
+		p.Status = models.PaymentStatusRefunded
+	}
+	f.giveBack(p, amount)
+	return p, nil
+}
+
+// fakeMethods keeps saved payment methods and customers in memory.
+type fakeMethods struct {
+	customers map[int]string
+	byID      map[int]*models.SavedPaymentMethod
+}
+
+var _ repository.PaymentMethodRepo = (*fakeMethods)(nil)
+
+func newFakeMethods() *fakeMethods {
+	return &fakeMethods{customers: map[int]string{}, byID: map[int]*models.SavedPaymentMethod{}}
+}
+
+func (f *fakeMethods) GetCustomer(ctx context.Context, userID int, provider string) (string, error) {
+	return f.customers[userID], nil
+}
+
+func (f *fakeMethods) SaveCustomer(ctx context.Context, userID int, provider, customer string) (string, error) {
+	f.customers[userID] = customer
+	return customer, nil
+}
+
+func (f *fakeMethods) Save(ctx context.Context, m *models.SavedPaymentMethod) (*models.SavedPaymentMethod, error) {
+	m.ID = len(f.byID) + 1
+	f.byID[m.ID] = m
+	return m, nil
+}
+
+func (f *fakeMethods) List(ctx context.Context, userID int) ([]*models.SavedPaymentMethod, error) {
+	methods := []*models.SavedPaymentMethod{}
+	for _, m := range f.byID {
+		if m.UserID == userID {
+			methods = append(methods, m)
+		}
+	}
+	return methods, nil
+}
+
+func (f *fakeMethods) Get(ctx context.Context, id, userID int) (*models.SavedPaymentMethod, error) {
+	if m, ok := f.byID[id]; ok && m.UserID == userID {
+		return m, nil
+	}
+	return nil, apperrors.NotFound("payment method not found")
+}
+
+func (f *fakeMethods) Delete(ctx context.Context, id, userID int) error {
+	if _, err := f.Get(ctx, id, userID); err != nil {
+		return err
+	}
+	delete(f.byID, id)
+	return nil
+}
+
+// fakeChargebacks keeps chargebacks in memory, one per provider reference,
+// and decides only open ones.
+type fakeChargebacks struct {
+	byID map[int]*models.Chargeback
+}
+
+var _ repository.ChargebackRepo = (*fakeChargebacks)(nil)
+
+func newFakeChargebacks() *fakeChargebacks {
+	return &fakeChargebacks{byID: map[int]*models.Chargeback{}}
+}
+
+func (f *fakeChargebacks) Open(ctx context.Context, cb *models.Chargeback) (*models.Chargeback, error) {
+	if existing, _ := f.GetByRef(ctx, cb.Provider, cb.ProviderRef); existing != nil {
+		return existing, nil
+	}
+	cb.ID, cb.Status = len(f.byID)+1, models.ChargebackStatusOpen
+	f.byID[cb.ID] = cb
+	return cb, nil
+}
+
+func (f *fakeChargebacks) GetByID(ctx context.Context, id int) (*models.Chargeback, error) {
+	if cb, ok := f.byID[id]; ok {
+		return cb, nil
+	}
+	return nil, apperrors.NotFound("chargeback not found")
+}
+
+func (f *fakeChargebacks) GetByRef(ctx context.Context, provider, ref string) (*models.Chargeback, error) {
+	for _, cb := range f.byID {
+		if cb.Provider == provider && cb.ProviderRef == ref {
+			return cb, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeChargebacks) Resolve(ctx context.Context, id int, status string) (*models.Chargeback, error) {
+	cb, err := f.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if cb.Status != models.ChargebackStatusOpen && cb.Status != status {
+		return nil, apperrors.Conflict("chargeback is already decided")
+	}
+	cb.Status = status
+	return cb, nil
+}
+
+type fakeOrders struct {
+	order *models.OrderWithItems
+}
+
+var _ repository.OrderRepo = (*fakeOrders)(nil)
+
+func (f *fakeOrders) GetUserOrders(ctx context.Context, userID int, query string, pagination *models.PaginationParams) ([]*models.OrderWithItems, int64, error) {
+	return nil, 0, nil
+}
+
+func (f *fakeOrders) GetByID(ctx context.Context, orderID int) (*models.OrderWithItems, error) {
+	return f.order, nil
+}
+
+func (f *fakeOrders) GetByNumber(ctx context.Context, orderNumber string) (*models.OrderWithItems, error) {
+	return f.order, nil
+}
+
+func testOrder() *fakeOrders {
+	return &fakeOrders{order: &models.OrderWithItems{Order: models.Order{
+		ID: 7, OrderNumber: "MB-2024-000007", UserID: 3, TotalAmount: 42.50, Status: "pending",
+	}}}
+}
+
+func appStatus(t *testing.T, err error) int {
+	t.Helper()
+	appErr := apperrors.GetAppError(err)
+	require.NotNil(t, appErr, "expected an AppError, got %v", err)
+	return appErr.HTTPStatus
+}
+
+func TestPaymentService_Pay(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     int
+		existing   []*models.Payment
+		scenario   string
+		wantStatus string
+		wantHTTP   int
+	}{
+		{name: "paid", userID: 3, wantStatus: models.PaymentStatusPaid},
+		{name: "declined", userID: 3, scenario: payment.ScenarioFail, wantStatus: models.PaymentStatusFailed},
+		{name: "retry after failure", userID: 3, existing: []*models.Payment{{ID: 1, OrderID: 7, Status: models.PaymentStatusFailed}}, wantStatus: models.PaymentStatusPaid},
+		{name: "someone else's order", userID: 4, wantHTTP: http.StatusNotFound},
+		{name: "already paid", userID: 3, existing: []*models.Payment{{ID: 1, OrderID: 7, Status: models.PaymentStatusPaid}}, wantHTTP: http.StatusConflict},
+		{name: "charge pending", userID: 3, existing: []*models.Payment{{ID: 1, OrderID: 7, Status: models.PaymentStatusPending}}, wantHTTP: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewPaymentService(payment.NewSandbox(payment.ScenarioSucceed, time.Hour), newFakePayments(tt.existing...), newFakeMethods(), testOrder(), nil, "")
+
+			paid, err := svc.Pay(context.Background(), tt.userID, 7, &models.PayOrderRequest{Scenario: tt.scenario})
+			if tt.wantHTTP != 0 {
+				assert.Equal(t, tt.wantHTTP, appStatus(t, err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, paid.PaymentStatus)
+			p := paid.Payments[len(paid.Payments)-1]
+			assert.Equal(t, tt.wantStatus, p.Status)
+			assert.Equal(t, 42.50, p.Amount)
+			assert.Equal(t, "sandbox", p.Provider)
+		})
+	}
+}
+
+// blockingProvider counts its charges and holds each until released.
+type blockingProvider struct {
+	payment.Provider
+	charges  int
+	charging chan struct{}
+	release  chan struct{}
+}
+
+func (p *blockingProvider) Charge(ctx context.Context, req payment.ChargeRequest) (*payment.Charge, error) {
+	p.charges++
+	p.charging <- struct{}{}
+	<-p.release
+	return p.Provider.Charge(ctx, req)
+}
+
+func TestPaymentService_PayConcurrently(t *testing.T) {
+	provider := &blockingProvider{
+		Provider: payment.NewSandbox(payment.ScenarioSucceed, time.Hour),
+		charging: make(chan struct{}),
+		release:  make(chan struct{}),
+	}
+	payments := newFakePayments()
+	svc := NewPaymentService(provider, payments, newFakeMethods(), testOrder(), nil, "")
+
+	done := make(chan error)
+	go func() {
+		_, err := svc.Pay(context.Background(), 3, 7, &models.PayOrderRequest{})
+		done <- err
+	}()
+	<-provider.charging
+
+	// The second attempt comes while the first waits on the provider
+	_, err := svc.Pay(context.Background(), 3, 7, &models.PayOrderRequest{})
+	assert.Equal(t, http.StatusConflict, appStatus(t, err))
+
+	close(provider.release)
+	require.NoError(t, <-done)
+	assert.Equal(t, 1, provider.charges)
+	assert.Empty(t, payments.locked)
+
+	_, err = svc.Pay(context.Background(), 3, 7, &models.PayOrderRequest{})
+	assert.Equal(t, http.StatusConflict, appStatus(t, err))
+	assert.Equal(t, 1, provider.charges)
+}
+
+func TestPaymentService_DelayedPaymentIsSettledOnce(t *testing.T) {
+	payments := newFakePayments()
+	svc := NewPaymentService(payment.NewSandbox(payment.ScenarioDelay, time.Hour), payments, newFakeMethods(), testOrder(), nil, "")
+
+	paid, err := svc.Pay(context.Background(), 3, 7, &models.PayOrderRequest{})
+	require.NoError(t, err)
+	p := paid.Payments[0]
+	require.Equal(t, models.PaymentStatusPending, p.Status)
+
+	settled, err := svc.HandleEvent(context.Background(), payment.Event{Ref: p.ProviderRef, Status: models.PaymentStatusPaid})
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentStatusPaid, settled.Status)
+
+	again, err := svc.HandleEvent(context.Background(), payment.Event{Ref: p.ProviderRef, Status: models.PaymentStatusFailed})
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentStatusPaid, again.Status)
+
+	_, err = svc.HandleEvent(context.Background(), payment.Event{Ref: "sandbox_unknown", Status: models.PaymentStatusPaid})
+	assert.Equal(t, http.StatusNotFound, appStatus(t, err))
+}
+
+func TestPaymentService_Refund(t *testing.T) {
+	paid := func() *models.Payment {
+		return &models.Payment{ID: 1, OrderID: 7, Method: models.PaymentMethodCard, ProviderRef: "sandbox_a", Amount: 42.50, Status: models.PaymentStatusPaid}
+	}
+	sandbox := payment.NewSandbox(payment.ScenarioSucceed, 0)
+
+	t.Run("partial then rest", func(t *testing.T) {
+		svc := NewPaymentService(sandbox, newFakePayments(paid()), newFakeMethods(), testOrder(), nil, "")
+
+		refunded, err := svc.Refund(context.Background(), 7, &models.RefundPaymentRequest{Amount: 10})
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentStatusPaid, refunded.PaymentStatus)
+		assert.Equal(t, 10.0, refunded.Payments[0].RefundedAmount)
+
+		refunded, err = svc.Refund(context.Background(), 7, &models.RefundPaymentRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentStatusRefunded, refunded.PaymentStatus)
+		assert.Equal(t, 42.50, refunded.Payments[0].RefundedAmount)
+	})
+
+	t.Run("more than paid", func(t *testing.T) {
+		svc := NewPaymentService(sandbox, newFakePayments(paid()), newFakeMethods(), testOrder(), nil, "")
+
+		_, err := svc.Refund(context.Background(), 7, &models.RefundPaymentRequest{Amount: 42.51})
+		require.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, appStatus(t, err))
+		assert.Contains(t, err.Error(), "42.50")
+	})
+
+	t.Run("unpaid order", func(t *testing.T) {
+		svc := NewPaymentService(sandbox, newFakePayments(), newFakeMethods(), testOrder(), nil, "")
+
+		_, err := svc.Refund(context.Background(), 7, &models.RefundPaymentRequest{})
+		assert.Equal(t, http.StatusConflict, appStatus(t, err))
+	})
+
+	t.Run("declined by provider", func(t *testing.T) {
+		svc := NewPaymentService(payment.NewSandbox(payment.ScenarioFail, 0), newFakePayments(paid()), newFakeMethods(), testOrder(), nil, "")
+
+		_, err := svc.Refund(context.Background(), 7, &models.RefundPaymentRequest{})
+		assert.Equal(t, http.StatusConflict, appStatus(t, err))
+	})
+}
+
+func TestPaymentService_PaySplit(t *testing.T) {
+	methods := func(payments []*models.Payment) map[string]string {
+		got := map[string]string{}
+		for _, p := range payments {
+			got[p.Method] = p.Status
+		}
+		return got
+	}
+
+	t.Run("gift card and store credit then card", func(t *testing.T) {
+		payments := &fakePayments{byID: map[int]*models.Payment{}, giftCard: 10, storeCredit: 20}
+		svc := NewPaymentService(payment.NewSandbox(payment.ScenarioSucceed, time.Hour), payments, newFakeMethods(), testOrder(), nil, "")
+
+		paid, err := svc.Pay(context.Background(), 3, 7, &models.PayOrderRequest{GiftCardCode: "GIFT10", UseStoreCredit: true})
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentStatusPaid, paid.PaymentStatus)
+		require.Len(t, paid.Payments, 3)
+		assert.Equal(t, []float64{10, 20, 12.50}, []float64{paid.Payments[0].Amount, paid.Payments[1].Amount, paid.Payments[2].Amount})
+		assert.Equal(t, map[string]string{
+			models.PaymentMethodGiftCard:    models.PaymentStatusPaid,
+			models.PaymentMethodStoreCredit: models.PaymentStatusPaid,
+			models.PaymentMethodCard:        models.PaymentStatusPaid,
+		}, methods(paid.Payments))
+		assert.Zero(t, payments.giftCard)
+		assert.Zero(t, payments.storeCredit)
+	})
+
+	t.Run("covered without a card", func(t *testing.T) {
+		payments := &fakePayments{byID: map[int]*models.Payment{}, storeCredit: 100}
+		svc := NewPaymentService(payment.NewSandbox(payment.ScenarioFail, time.Hour), payments, newFakeMethods(), testOrder(), nil, "")
+
+		paid, err := svc.Pay(context.Background(), 3, 7, &models.PayOrderRequest{UseStoreCredit: true})
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentStatusPaid, paid.PaymentStatus)
+		require.Len(t, paid.Payments, 1)
+		assert.Equal(t, 42.50, paid.Payments[0].Amount)
+		assert.Equal(t, 57.50, payments.storeCredit)
+	})
+
+	t.Run("declined card gives the balances back", func(t *testing.T) {
+		payments := &fakePayments{byID: map[int]*models.Payment{}, giftCard: 10}
+		svc := NewPaymentService(payment.NewSandbox(payment.ScenarioSucceed, time.Hour), payments, newFakeMethods(), testOrder(), nil, "")
+
+		paid, err := svc.Pay(context.Background(), 3, 7, &models.PayOrderRequest{GiftCardCode: "GIFT10", Scenario: payment.ScenarioFail})
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentStatusFailed, paid.PaymentStatus)
+		assert.Equal(t, map[string]string{
+			models.PaymentMethodGiftCard: models.PaymentStatusVoided,
+			models.PaymentMethodCard:     models.PaymentStatusFailed,
+		}, methods(paid.Payments))
+		assert.Equal(t, 10.0, payments.giftCard)
+
+		// The retry spends the card again.
+		paid, err = svc.Pay(context.Background(), 3, 7, &models.PayOrderRequest{GiftCardCode: "GIFT10"})
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentStatusPaid, paid.PaymentStatus)
+		assert.Zero(t, payments.giftCard)
+	})
+
+	t.Run("delayed card holds the balances", func(t *testing.T) {
+		payments := &fakePayments{byID: map[int]*models.Payment{}, storeCredit: 5}
+		svc := NewPaymentService(payment.NewSandbox(payment.ScenarioDelay, time.Hour), payments, newFakeMethods(), testOrder(), nil, "")
+
+		paid, err := svc.Pay(context.Background(), 3, 7, &models.PayOrderRequest{UseStoreCredit: true})
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentStatusPending, paid.PaymentStatus)
+		card := paid.Payments[1]
+		assert.Equal(t, 37.50, card.Amount)
+
+		_, err = svc.HandleEvent(context.Background(), payment.Event{Ref: paid.Payments[0].ProviderRef, Status: models.PaymentStatusPaid})
+		assert.Equal(t, http.StatusNotFound, appStatus(t, err), "balance payments are not settled by provider events")
+
+		_, err = svc.HandleEvent(context.Background(), payment.Event{Ref: card.ProviderRef, Status: models.PaymentStatusPaid})
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentStatusPaid, paid.Payments[0].Status)
+	})
+
+	t.Run("unknown gift card", func(t *testing.T) {
+		svc := NewPaymentService(payment.NewSandbox(payment.ScenarioSucceed, time.Hour), newFakePayments(), newFakeMethods(), testOrder(), nil, "")
+
+		_, err := svc.Pay(context.Background(), 3, 7, &models.PayOrderRequest{GiftCardCode: "NOPE"})
+		assert.Equal(t, http.StatusBadRequest, appStatus(t, err))
+	})
+}
+
+func TestPaymentService_RefundSplit(t *testing.T) {
+	payments := &fakePayments{byID: map[int]*models.Payment{
+		1: {ID: 1, OrderID: 7, Method: models.PaymentMethodGiftCard, Amount: 10, Status: models.PaymentStatusPaid},
+		2: {ID: 2, OrderID: 7, Method: models.PaymentMethodStoreCredit, Amount: 20, Status: models.PaymentStatusPaid},
+		3: {ID: 3, OrderID: 7, Method: models.PaymentMethodCard, ProviderRef: "sandbox_a", Amount: 12.50, Status: models.PaymentStatusPaid},
+	}}
+	svc := NewPaymentService(payment.NewSandbox(payment.ScenarioSucceed, 0), payments, newFakeMethods(), testOrder(), nil, "")
+
+	// The card first, then store credit.
+	refunded, err := svc.Refund(context.Background(), 7, &models.RefundPaymentRequest{Amount: 20})
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentStatusPaid, refunded.PaymentStatus)
+	assert.Equal(t, models.PaymentStatusRefunded, refunded.Payments[2].Status)
+	assert.Equal(t, 7.50, refunded.Payments[1].RefundedAmount)
+	assert.Equal(t, 7.50, payments.storeCredit)
+	assert.Zero(t, payments.giftCard)
+
+	refunded, err = svc.Refund(context.Background(), 7, &models.RefundPaymentRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentStatusRefunded, refunded.PaymentStatus)
+	assert.Equal(t, 20.0, payments.storeCredit)
+	assert.Equal(t, 10.0, payments.giftCard)
+}
+
+func TestPaymentService_SavedMethods(t *testing.T) {
+	methods := newFakeMethods()
+	svc := NewPaymentService(payment.NewSandbox(payment.ScenarioSucceed, time.Hour), newFakePayments(), methods, testOrder(), nil, "")
+
+	paid, err := svc.Pay(context.Background(), 3, 7, &models.PayOrderRequest{SaveMethod: true})
+	require.NoError(t, err)
+	require.Equal(t, models.PaymentStatusPaid, paid.PaymentStatus)
+
+	saved, err := svc.PaymentMethods(context.Background(), 3)
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+	assert.Equal(t, "sandbox", saved[0].Provider)
+	assert.Equal(t, methods.customers[3], saved[0].CustomerRef)
+	assert.NotEmpty(t, saved[0].MethodRef)
+
+	// One click: the next order is charged with the saved method.
+	next := NewPaymentService(payment.NewSandbox(payment.ScenarioSucceed, time.Hour), newFakePayments(), methods, testOrder(), nil, "")
+	paid, err = next.Pay(context.Background(), 3, 7, &models.PayOrderRequest{SavedPaymentMethodID: saved[0].ID})
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentStatusPaid, paid.PaymentStatus)
+
+	_, err = NewPaymentService(payment.NewSandbox(payment.ScenarioSucceed, 0), newFakePayments(), methods, testOrder(), nil, "").
+		Pay(context.Background(), 3, 7, &models.PayOrderRequest{SavedPaymentMethodID: saved[0].ID, SaveMethod: true})
+	assert.Equal(t, http.StatusBadRequest, appStatus(t, err))
+
+	other := &fakeOrders{order: &models.OrderWithItems{Order: models.Order{ID: 8, UserID: 4, TotalAmount: 5, Status: "pending"}}}
+	_, err = NewPaymentService(payment.NewSandbox(payment.ScenarioSucceed, 0), newFakePayments(), methods, other, nil, "").
+		Pay(context.Background(), 4, 8, &models.PayOrderRequest{SavedPaymentMethodID: saved[0].ID})
+	assert.Equal(t, http.StatusNotFound, appStatus(t, err), "other users' methods are not found")
+
+	require.NoError(t, svc.DeletePaymentMethod(context.Background(), 3, saved[0].ID))
+	saved, err = svc.PaymentMethods(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Empty(t, saved)
+}
+
+func TestPaymentService_SaveMethodFromEvent(t *testing.T) {
+	methods := newFakeMethods()
+	svc := NewPaymentService(payment.NewSandbox(payment.ScenarioDelay, time.Hour), newFakePayments(), methods, testOrder(), nil, "")
+
+	paid, err := svc.Pay(context.Background(), 3, 7, &models.PayOrderRequest{SaveMethod: true})
+	require.NoError(t, err)
+	require.Equal(t, models.PaymentStatusPending, paid.PaymentStatus)
+	assert.Empty(t, methods.byID, "pending charges save nothing yet")
+
+	_, err = svc.HandleEvent(context.Background(), payment.Event{
+		Ref: paid.Payments[0].ProviderRef, Status: models.PaymentStatusPaid, Customer: methods.customers[3], PaymentMethod: "sandbox_pm_1",
+	})
+	require.NoError(t, err)
+	require.Len(t, methods.byID, 1)
+	assert.Equal(t, 3, methods.byID[1].UserID)
+	assert.Equal(t, "sandbox_pm_1", methods.byID[1].MethodRef)
+}
+
+// hookedSandbox is a sandbox that also receives webhooks, returning a
+// fixed event or error from each delivery.
+type hookedSandbox struct {
+	*payment.Sandbox
+	ev  *payment.Event
+	err error
+}
+
+func (h *hookedSandbox) ParseEvent(payload []byte, header http.Header) (*payment.Event, error) {
+	return h.ev, h.err
+}
+
+func TestPaymentService_HandleWebhook(t *testing.T) {
+	pending := &models.Payment{ID: 1, OrderID: 7, Method: models.PaymentMethodCard, ProviderRef: "sandbox_a", Amount: 42.50, Status: models.PaymentStatusPending}
+	provider := &hookedSandbox{Sandbox: payment.NewSandbox(payment.ScenarioSucceed, 0)}
+	svc := NewPaymentService(provider, newFakePayments(pending), newFakeMethods(), testOrder(), nil, "")
+
+	provider.err = payment.ErrBadSignature
+	_, err := svc.HandleWebhook(context.Background(), nil, http.Header{})
+	assert.Equal(t, http.StatusBadRequest, appStatus(t, err))
+
+	provider.err = nil
+	p, err := svc.HandleWebhook(context.Background(), nil, http.Header{})
+	require.NoError(t, err)
+	assert.Nil(t, p, "ignored events settle nothing")
+
+	provider.ev = &payment.Event{Ref: "sandbox_a", Status: models.PaymentStatusPaid}
+	p, err = svc.HandleWebhook(context.Background(), nil, http.Header{})
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentStatusPaid, p.Status)
+
+	plain := NewPaymentService(payment.NewSandbox(payment.ScenarioSucceed, 0), newFakePayments(), newFakeMethods(), testOrder(), nil, "")
+	_, err = plain.HandleWebhook(context.Background(), nil, http.Header{})
+	assert.Equal(t, http.StatusNotFound, appStatus(t, err))
+}
+
+func TestPaymentService_ConfirmChallenge(t *testing.T) {
+	payments := newFakePayments()
+	payments.giftCard = 10
+	svc := NewPaymentService(payment.NewSandbox(payment.ScenarioChallenge, time.Hour), payments, newFakeMethods(), testOrder(), nil,
+		"https://shop.example/checkout/return")
+
+	started, err := svc.Pay(context.Background(), 3, 7, &models.PayOrderRequest{GiftCardCode: "GIFT10"})
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentStatusPending, started.PaymentStatus)
+	card := started.Payments[1]
+	assert.Equal(t, models.PaymentStatusRequiresAction, card.Status)
+	assert.Equal(t, models.PaymentActionRedirect, card.ActionType)
+	assert.Contains(t, card.ActionURL, "https://shop.example/checkout/return?order_id=7")
+
+	_, err = svc.Pay(context.Background(), 3, 7, &models.PayOrderRequest{})
+	assert.Equal(t, http.StatusConflict, appStatus(t, err), "a challenged charge is in progress")
+
+	_, err = svc.ConfirmPayment(context.Background(), 4, 7)
+	assert.Equal(t, http.StatusNotFound, appStatus(t, err), "other users' orders are not found")
+
+	confirmed, err := svc.ConfirmPayment(context.Background(), 3, 7)
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentStatusPaid, confirmed.PaymentStatus)
+	assert.Empty(t, confirmed.Payments[1].ActionURL)
+	assert.Equal(t, models.PaymentStatusPaid, confirmed.Payments[0].Status, "the held gift card is taken")
+
+	_, err = svc.ConfirmPayment(context.Background(), 3, 7)
+	assert.Equal(t, http.StatusConflict, appStatus(t, err), "nothing is left to confirm")
+
+	unconfirmable := NewPaymentService(struct{ payment.Provider }{payment.NewSandbox(payment.ScenarioSucceed, 0)},
+		newFakePayments(), newFakeMethods(), testOrder(), nil, "")
+	_, err = unconfirmable.ConfirmPayment(context.Background(), 3, 7)
+	assert.Equal(t, http.StatusBadRequest, appStatus(t, err))
+}
+
+func TestPaymentService_HandleDispute(t *testing.T) {
+	paid := &models.Payment{ID: 1, OrderID: 7, Method: models.PaymentMethodCard, Provider: "sandbox", ProviderRef: "sandbox_a", Amount: 42.50, Status: models.PaymentStatusPaid}
+	pending := &models.Payment{ID: 2, OrderID: 8, Method: models.PaymentMethodCard, Provider: "sandbox", ProviderRef: "sandbox_b", Amount: 10, Status: models.PaymentStatusPending}
+	chargebacks := newFakeChargebacks()
+	provider := &hookedSandbox{Sandbox: payment.NewSandbox(payment.ScenarioSucceed, 0)}
+	svc := NewPaymentService(provider, newFakePayments(paid, pending), newFakeMethods(), testOrder(), chargebacks, "")
+
+	dueBy := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	provider.ev = &payment.Event{Ref: "sandbox_a", Dispute: &payment.Dispute{
+		Ref: "dp_1", Amount: 4250, Reason: "fraudulent", Status: models.ChargebackStatusOpen, EvidenceDueBy: dueBy,
+	}}
+	p, err := svc.HandleWebhook(context.Background(), nil, http.Header{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, p.ID)
+	require.Len(t, chargebacks.byID, 1)
+	cb := chargebacks.byID[1]
+	assert.Equal(t, 42.50, cb.Amount)
+	assert.Equal(t, &dueBy, cb.EvidenceDueBy)
+	assert.Equal(t, 7, cb.OrderID)
+
+	_, err = svc.HandleWebhook(context.Background(), nil, http.Header{})
+	require.NoError(t, err)
+	assert.Len(t, chargebacks.byID, 1, "repeated deliveries open nothing new")
+
+	provider.ev.Dispute = &payment.Dispute{Ref: "dp_1", Amount: 4250, Status: models.ChargebackStatusLost}
+	_, err = svc.HandleWebhook(context.Background(), nil, http.Header{})
+	require.NoError(t, err)
+	assert.Equal(t, models.ChargebackStatusLost, cb.Status)
+
+	provider.ev = &payment.Event{Ref: "sandbox_b", Dispute: &payment.Dispute{Ref: "dp_2", Amount: 1000, Status: models.ChargebackStatusOpen}}
+	_, err = svc.HandleWebhook(context.Background(), nil, http.Header{})
+	assert.Equal(t, http.StatusConflict, appStatus(t, err), "unpaid payments cannot be charged back")
+
+	provider.ev = &payment.Event{Ref: "unknown", Dispute: &payment.Dispute{Ref: "dp_3", Amount: 1000, Status: models.ChargebackStatusOpen}}
+	_, err = svc.HandleWebhook(context.Background(), nil, http.Header{})
+	assert.Equal(t, http.StatusNotFound, appStatus(t, err))
+}
+
+func TestPaymentService_OpenChargeback(t *testing.T) {
+	gift := &models.Payment{ID: 1, OrderID: 7, Method: models.PaymentMethodGiftCard, Amount: 10, Status: models.PaymentStatusPaid}
+	card := &models.Payment{ID: 2, OrderID: 7, Method: models.PaymentMethodCard, Provider: "stripe", ProviderRef: "pi_1", Amount: 32.50, Status: models.PaymentStatusPaid}
+	chargebacks := newFakeChargebacks()
+	svc := NewPaymentService(payment.NewSandbox(payment.ScenarioSucceed, 0), newFakePayments(gift, card), newFakeMethods(), testOrder(), chargebacks, "")
+
+	_, err := svc.OpenChargeback(context.Background(), &models.CreateChargebackRequest{OrderID: 7, ProviderRef: "dp_1", Amount: 40})
+	assert.Equal(t, http.StatusBadRequest, appStatus(t, err), "only the card payment can be charged back")
+
+	cb, err := svc.OpenChargeback(context.Background(), &models.CreateChargebackRequest{OrderID: 7, ProviderRef: "dp_1", Amount: 32.50, Reason: "product_not_received"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, cb.PaymentID)
+	assert.Equal(t, "stripe", cb.Provider)
+
+	_, err = svc.OpenChargeback(context.Background(), &models.CreateChargebackRequest{OrderID: 8, ProviderRef: "dp_2", Amount: 5})
+	assert.Equal(t, http.StatusConflict, appStatus(t, err))
+
+	won, err := svc.ResolveChargeback(context.Background(), cb.ID, models.ChargebackStatusWon)
+	require.NoError(t, err)
+	assert.Equal(t, models.ChargebackStatusWon, won.Status)
+	_, err = svc.ResolveChargeback(context.Background(), cb.ID, models.ChargebackStatusLost)
+	assert.Equal(t, http.StatusConflict, appStatus(t, err))
+}
@@ -0,0 +1,36 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Zifeldev/marketback/clients/authclient"
+	"github.com/redis/go-redis/v9"
+)
+
+// Denylist reads the access tokens the Auth service revoked from the Redis
+// it records them in, so a logout takes effect in Market on the next
+// request without introspecting every token.
+type Denylist struct {
+	redis  *redis.Client
+	prefix string
+}
+
+// NewDenylist reads revoked tokens under prefix, the Auth service's
+// REDIS_PREFIX.
+func NewDenylist(client *redis.Client, prefix string) *Denylist {
+	return &Denylist{redis: client, prefix: prefix}
+}
+
+// Revoked reports whether the access token with ID jti was revoked. Tokens
+// without an ID cannot be.
+func (d *Denylist) Revoked(ctx context.Context, jti string) (bool, error) {
+	if jti == "" {
+		return false, nil
+	}
+	n, err := d.redis.Exists(ctx, authclient.DenylistKey(d.prefix, jti)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check access token denylist: %w", err)
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,66 @@
+// Package session checks access tokens with the Auth service, so a logout or
+// an admin revoking a user's sessions takes effect in Market before the token
+// expires. Answers are cached in Redis for a short TTL to keep introspection
+// off the hot path. Denylist reads revoked tokens straight from the Auth
+// service's Redis instead.
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/Zifeldev/marketback/clients/authclient"
+	"github.com/Zifeldev/marketback/service/Market/internal/cache"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+)
+
+// Introspector asks the Auth service about a token. *authclient.Client
+// implements it.
+type Introspector interface {
+	Introspect(ctx context.Context, token string) (*authclient.Introspection, error)
+}
+
+// Checker reports whether access tokens are still active. A nil cache or a
+// zero TTL introspects every request.
+type Checker struct {
+	auth  Introspector
+	cache *cache.RedisCache
+	ttl   time.Duration
+}
+
+func NewChecker(auth Introspector, cache *cache.RedisCache, ttl time.Duration) *Checker {
+	return &Checker{auth: auth, cache: cache, ttl: ttl}
+}
+
+// Active reports whether token is still active. Errors mean the Auth service
+// could not answer.
+func (c *Checker) Active(ctx context.Context, token string) (bool, error) {
+	key := cacheKey(token)
+	if c.cache != nil && c.ttl > 0 {
+		var active bool
+		if err := c.cache.Get(ctx, key, &active); err == nil {
+			metrics.RedisHitsTotal.Inc()
+			return active, nil
+		}
+		metrics.RedisMissesTotal.Inc()
+	}
+
+	resp, err := c.auth.Introspect(ctx, token)
+	if err != nil {
+		return false, fmt.Errorf("failed to introspect token: %w", err)
+	}
+
+	if c.cache != nil && c.ttl > 0 {
+		_ = c.cache.Set(ctx, key, resp.Active, c.ttl)
+	}
+	return resp.Active, nil
+}
+
+// cacheKey hashes the token so bearer tokens are not stored in Redis.
+func cacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "session:" + hex.EncodeToString(sum[:])
+}
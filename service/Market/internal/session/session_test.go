@@ -0,0 +1,50 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Zifeldev/marketback/clients/authclient"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeIntrospector struct {
+	active map[string]bool
+	err    error
+	calls  int
+}
+
+func (f *fakeIntrospector) Introspect(ctx context.Context, token string) (*authclient.Introspection, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &authclient.Introspection{Active: f.active[token]}, nil
+}
+
+func TestChecker_Active(t *testing.T) {
+	ctx := context.Background()
+	auth := &fakeIntrospector{active: map[string]bool{"live": true}}
+	c := NewChecker(auth, nil, 0)
+
+	active, err := c.Active(ctx, "live")
+	require.NoError(t, err)
+	require.True(t, active)
+
+	active, err = c.Active(ctx, "revoked")
+	require.NoError(t, err)
+	require.False(t, active)
+	require.Equal(t, 2, auth.calls)
+
+	auth.err = errors.New("connection refused")
+	_, err = c.Active(ctx, "live")
+	require.Error(t, err)
+}
+
+func TestCacheKey(t *testing.T) {
+	key := cacheKey("header.payload.signature")
+	require.Equal(t, key, cacheKey("header.payload.signature"))
+	require.NotEqual(t, key, cacheKey("header.payload.other"))
+	require.NotContains(t, key, "payload")
+}
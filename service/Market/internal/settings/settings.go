@@ -0,0 +1,375 @@
+// Package settings holds site-wide configuration that admins change at
+// runtime, such as the minimum order amount or the support email address.
+// Every key has a type and a default; admin overrides are stored in the
+// settings table and cached in Redis.
+package settings
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/mail"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/cache"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+)
+
+const (
+	cacheKey = "settings:all"
+	cacheTTL = 10 * time.Minute
+)
+
+type Type string
+
+const (
+	// TypeAmount is a non-negative amount in the default currency.
+	TypeAmount Type = "amount"
+	// TypeEmail is an email address or empty.
+	TypeEmail Type = "email"
+	// TypeMinutes is a non-negative whole number of minutes.
+	TypeMinutes Type = "minutes"
+	// TypeCount is a non-negative whole number.
+	TypeCount Type = "count"
+	// TypePercent is a non-negative percentage.
+	TypePercent Type = "percent"
+)
+
+// Key is a known setting.
+type Key struct {
+	Name        string
+	Type        Type
+	Default     string
+	Description string
+}
+
+var (
+	MinOrderAmount = Key{
+		Name:        "min_order_amount",
+		Type:        TypeAmount,
+		Default:     "0",
+		Description: "Smallest cart total accepted at checkout; 0 disables the minimum",
+	}
+	FreeShippingThreshold = Key{
+		Name:        "free_shipping_threshold",
+		Type:        TypeAmount,
+		Default:     "0",
+		Description: "Cart total from which shipping is free; 0 disables free shipping",
+	}
+	SupportEmail = Key{
+		Name:        "support_email",
+		Type:        TypeEmail,
+		Default:     "",
+		Description: "Address that receives new support tickets and requester replies",
+	}
+	OrderCancellationWindow = Key{
+		Name:        "order_cancellation_window",
+		Type:        TypeMinutes,
+		Default:     "30",
+		Description: "Minutes after purchase during which buyers can cancel an order themselves, unless the seller has its own window; 0 disables self-cancellation",
+	}
+	InventoryRiskPercent = Key{
+		Name:        "inventory_risk_percent",
+		Type:        TypePercent,
+		Default:     "20",
+		Description: "Largest price change or stock drop, in percent, that inventory imports apply without the seller's confirmation",
+	}
+	SellerProbationOrders = Key{
+		Name:        "seller_probation_orders",
+		Type:        TypeCount,
+		Default:     "0",
+		Description: "Delivered orders new sellers need before their products are listed to every visitor; 0 lists new sellers right away",
+	}
+	SellerProbationTraffic = Key{
+		Name:        "seller_probation_traffic",
+		Type:        TypePercent,
+		Default:     "10",
+		Description: "Share of visitors, in steps of 10 percent, shown the products of sellers on probation in listings; direct links always work",
+	}
+)
+
+// Keys lists every known setting in display order.
+var Keys = []Key{
+	MinOrderAmount, FreeShippingThreshold, SupportEmail, OrderCancellationWindow, InventoryRiskPercent,
+	SellerProbationOrders, SellerProbationTraffic,
+}
+
+// Lookup finds a known setting by name.
+func Lookup(name string) (Key, bool) {
+	for _, k := range Keys {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+// Normalize checks value against the key's type and returns its canonical
+// form.
+func (k Key) Normalize(value string) (string, error) {
+	switch k.Type {
+	case TypeAmount:
+		amount, err := strconv.ParseFloat(value, 64)
+		if err != nil || amount < 0 {
+			return "", apperrors.ValidationError(k.Name, "must be a non-negative amount")
+		}
+		return strconv.FormatFloat(amount, 'f', -1, 64), nil
+	case TypeEmail:
+		if value == "" {
+			return "", nil
+		}
+		addr, err := mail.ParseAddress(value)
+		if err != nil || addr.Name != "" {
+			return "", apperrors.ValidationError(k.Name, "must be an email address")
+		}
+		return addr.Address, nil
+	case TypeMinutes:
+		minutes, err := strconv.Atoi(value)
+		if err != nil || minutes < 0 {
+			return "", apperrors.ValidationError(k.Name, "must be a non-negative number of minutes")
+		}
+		return strconv.Itoa(minutes), nil
+	case TypeCount:
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 0 {
+			return "", apperrors.ValidationError(k.Name, "must be a non-negative whole number")
+		}
+		return strconv.Itoa(count), nil
+	case TypePercent:
+		percent, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(percent) || math.IsInf(percent, 0) || percent < 0 {
+			return "", apperrors.ValidationError(k.Name, "must be a non-negative percentage")
+		}
+		return strconv.FormatFloat(percent, 'f', -1, 64), nil
+	}
+	return value, nil
+}
+
+// Entry is a setting as shown to admins.
+type Entry struct {
+	Key         string     `json:"key"`
+	Type        Type       `json:"type"`
+	Description string     `json:"description"`
+	Default     string     `json:"default"`
+	Value       string     `json:"value"`
+	Overridden  bool       `json:"overridden"`
+	UpdatedBy   *int       `json:"updated_by,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// Store reads and writes settings. A nil repo serves the defaults only, a
+// nil cache reads the database on every lookup and a nil *Store serves the
+// built-in defaults.
+type Store struct {
+	repo  repository.SettingsRepo
+	cache *cache.RedisCache
+
+	mu       sync.RWMutex
+	defaults map[string]string
+}
+
+func New(repo repository.SettingsRepo, cache *cache.RedisCache) *Store {
+	defaults := make(map[string]string, len(Keys))
+	for _, k := range Keys {
+		defaults[k.Name] = k.Default
+	}
+	return &Store{repo: repo, cache: cache, defaults: defaults}
+}
+
+// SetDefault replaces the built-in default of a key, e.g. with a value
+// from the environment.
+func (s *Store) SetDefault(k Key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.defaults[k.Name] = value
+}
+
+func (s *Store) defaultValue(name string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.defaults[name]
+}
+
+// String returns the value of a key. Lookups never fail: when the settings
+// cannot be read the default is used so checkout keeps working.
+func (s *Store) String(ctx context.Context, k Key) string {
+	if s == nil {
+		return k.Default
+	}
+	if value, ok := s.overrides(ctx)[k.Name]; ok {
+		return value
+	}
+	return s.defaultValue(k.Name)
+}
+
+// Amount returns the value of an amount key.
+func (s *Store) Amount(ctx context.Context, k Key) float64 {
+	amount, err := strconv.ParseFloat(s.String(ctx, k), 64)
+	if err != nil {
+		return 0
+	}
+	return amount
+}
+
+// Minutes returns the value of a minutes key as a duration.
+func (s *Store) Minutes(ctx context.Context, k Key) time.Duration {
+	minutes, err := strconv.Atoi(s.String(ctx, k))
+	if err != nil || minutes < 0 {
+		return 0
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+func (s *Store) MinOrderAmount(ctx context.Context) float64 {
+	return s.Amount(ctx, MinOrderAmount)
+}
+
+func (s *Store) FreeShippingThreshold(ctx context.Context) float64 {
+	return s.Amount(ctx, FreeShippingThreshold)
+}
+
+func (s *Store) SupportEmail(ctx context.Context) string {
+	return s.String(ctx, SupportEmail)
+}
+
+func (s *Store) OrderCancellationWindow(ctx context.Context) time.Duration {
+	return s.Minutes(ctx, OrderCancellationWindow)
+}
+
+// InventoryRiskPercent returns the inventory import risk threshold.
+// Percent keys read like amount keys.
+func (s *Store) InventoryRiskPercent(ctx context.Context) float64 {
+	return s.Amount(ctx, InventoryRiskPercent)
+}
+
+// SellerProbation returns the probation new sellers start on; zero orders
+// means none. The traffic share is capped at 100 percent.
+func (s *Store) SellerProbation(ctx context.Context) models.SetSellerProbationRequest {
+	orders, err := strconv.Atoi(s.String(ctx, SellerProbationOrders))
+	if err != nil || orders < 0 {
+		orders = 0
+	}
+	return models.SetSellerProbationRequest{
+		Orders:         orders,
+		TrafficPercent: int(math.Min(s.Amount(ctx, SellerProbationTraffic), 100)),
+	}
+}
+
+func (s *Store) overrides(ctx context.Context) map[string]string {
+	values := map[string]string{}
+	if s.repo == nil {
+		return values
+	}
+
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, cacheKey, &values); err == nil {
+			metrics.RedisHitsTotal.Inc()
+			return values
+		}
+		metrics.RedisMissesTotal.Inc()
+	}
+
+	rows, err := s.repo.GetAll(ctx)
+	if err != nil {
+		logger.FromContext(ctx).WithField("err", err).Warn("failed to load settings, using defaults")
+		return values
+	}
+	for _, row := range rows {
+		values[row.Key] = row.Value
+	}
+
+	if s.cache != nil {
+		_ = s.cache.Set(ctx, cacheKey, values, cacheTTL)
+	}
+	return values
+}
+
+// List returns every known setting with its current value, read from the
+// database.
+func (s *Store) List(ctx context.Context) ([]*Entry, error) {
+	rows := []*models.Setting{}
+	if s.repo != nil {
+		var err error
+		if rows, err = s.repo.GetAll(ctx); err != nil {
+			return nil, err
+		}
+	}
+	byKey := make(map[string]*models.Setting, len(rows))
+	for _, row := range rows {
+		byKey[row.Key] = row
+	}
+
+	entries := make([]*Entry, 0, len(Keys))
+	for _, k := range Keys {
+		entry := &Entry{
+			Key:         k.Name,
+			Type:        k.Type,
+			Description: k.Description,
+			Default:     s.defaultValue(k.Name),
+		}
+		entry.Value = entry.Default
+		if row, ok := byKey[k.Name]; ok {
+			entry.Value = row.Value
+			entry.Overridden = true
+			entry.UpdatedBy = row.UpdatedBy
+			updatedAt := row.UpdatedAt
+			entry.UpdatedAt = &updatedAt
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Set validates and stores an override.
+func (s *Store) Set(ctx context.Context, name, value string, adminID int) error {
+	k, err := lookup(name)
+	if err != nil {
+		return err
+	}
+	value, err = k.Normalize(value)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.repo.Set(ctx, k.Name, value, adminID); err != nil {
+		return err
+	}
+	s.invalidate(ctx)
+	return nil
+}
+
+// Reset removes an override so the default applies again.
+func (s *Store) Reset(ctx context.Context, name string) error {
+	k, err := lookup(name)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, k.Name); err != nil {
+		return err
+	}
+	s.invalidate(ctx)
+	return nil
+}
+
+func (s *Store) invalidate(ctx context.Context) {
+	if s.cache != nil {
+		if err := s.cache.Delete(ctx, cacheKey); err != nil {
+			logger.GetLogger().WithField("err", err).Warn("failed to invalidate settings cache")
+		}
+	}
+}
+
+func lookup(name string) (Key, error) {
+	k, ok := Lookup(name)
+	if !ok {
+		return Key{}, apperrors.NotFound(fmt.Sprintf("unknown setting %s", name))
+	}
+	return k, nil
+}
@@ -0,0 +1,146 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	rows  map[string]string
+	err   error
+	reads int
+}
+
+func (f *fakeRepo) GetAll(ctx context.Context) ([]*models.Setting, error) {
+	f.reads++
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := []*models.Setting{}
+	for k, v := range f.rows {
+		out = append(out, &models.Setting{Key: k, Value: v, UpdatedAt: time.Now()})
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) Set(ctx context.Context, key, value string, adminID int) (*models.Setting, error) {
+	f.rows[key] = value
+	return &models.Setting{Key: key, Value: value, UpdatedBy: &adminID}, nil
+}
+
+func (f *fakeRepo) Delete(ctx context.Context, key string) error {
+	if _, ok := f.rows[key]; !ok {
+		return apperrors.NotFound("not overridden")
+	}
+	delete(f.rows, key)
+	return nil
+}
+
+func TestKey_Normalize(t *testing.T) {
+	tests := []struct {
+		key   Key
+		value string
+		want  string
+		ok    bool
+	}{
+		{MinOrderAmount, "25.50", "25.5", true},
+		{MinOrderAmount, "0", "0", true},
+		{MinOrderAmount, "-1", "", false},
+		{MinOrderAmount, "ten", "", false},
+		{SupportEmail, "help@example.com", "help@example.com", true},
+		{SupportEmail, "", "", true},
+		{SupportEmail, "Help <help@example.com>", "", false},
+		{SupportEmail, "not-an-email", "", false},
+		{OrderCancellationWindow, "045", "45", true},
+		{OrderCancellationWindow, "0", "0", true},
+		{OrderCancellationWindow, "-5", "", false},
+		{OrderCancellationWindow, "1.5", "", false},
+		{InventoryRiskPercent, "12.50", "12.5", true},
+		{InventoryRiskPercent, "-1", "", false},
+		{InventoryRiskPercent, "Inf", "", false},
+		{InventoryRiskPercent, "NaN", "", false},
+		{SellerProbationOrders, "05", "5", true},
+		{SellerProbationOrders, "-1", "", false},
+		{SellerProbationOrders, "2.5", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key.Name+"="+tt.value, func(t *testing.T) {
+			got, err := tt.key.Normalize(tt.value)
+			if !tt.ok {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStore_Values(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeRepo{rows: map[string]string{}}
+	s := New(repo, nil)
+	s.SetDefault(SupportEmail, "env@example.com")
+
+	require.Equal(t, 0.0, s.MinOrderAmount(ctx))
+	require.Equal(t, "env@example.com", s.SupportEmail(ctx))
+
+	require.NoError(t, s.Set(ctx, "min_order_amount", "20", 1))
+	require.NoError(t, s.Set(ctx, "support_email", "desk@example.com", 1))
+	require.Equal(t, 20.0, s.MinOrderAmount(ctx))
+	require.Equal(t, "desk@example.com", s.SupportEmail(ctx))
+
+	require.NoError(t, s.Reset(ctx, "support_email"))
+	require.Equal(t, "env@example.com", s.SupportEmail(ctx))
+
+	require.Equal(t, models.SetSellerProbationRequest{Orders: 0, TrafficPercent: 10}, s.SellerProbation(ctx))
+	require.NoError(t, s.Set(ctx, "seller_probation_orders", "3", 1))
+	require.NoError(t, s.Set(ctx, "seller_probation_traffic", "250", 1))
+	require.Equal(t, models.SetSellerProbationRequest{Orders: 3, TrafficPercent: 100}, s.SellerProbation(ctx))
+
+	var appErr *apperrors.AppError
+	require.ErrorAs(t, s.Set(ctx, "unknown", "1", 1), &appErr)
+	require.Equal(t, apperrors.CodeNotFound, appErr.Code)
+	require.ErrorAs(t, s.Set(ctx, "free_shipping_threshold", "-5", 1), &appErr)
+	require.Equal(t, apperrors.CodeValidationError, appErr.Code)
+}
+
+func TestStore_FallsBackToDefaults(t *testing.T) {
+	ctx := context.Background()
+	s := New(&fakeRepo{err: errors.New("db down")}, nil)
+	s.SetDefault(MinOrderAmount, "15")
+
+	require.Equal(t, 15.0, s.MinOrderAmount(ctx))
+
+	var nilStore *Store
+	require.Equal(t, "", nilStore.SupportEmail(ctx))
+	require.Equal(t, 30*time.Minute, nilStore.OrderCancellationWindow(ctx))
+	require.Equal(t, 0.0, nilStore.FreeShippingThreshold(ctx))
+}
+
+func TestStore_List(t *testing.T) {
+	ctx := context.Background()
+	s := New(&fakeRepo{rows: map[string]string{"free_shipping_threshold": "50"}}, nil)
+
+	entries, err := s.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, len(Keys))
+
+	for _, e := range entries {
+		if e.Key == FreeShippingThreshold.Name {
+			require.True(t, e.Overridden)
+			require.Equal(t, "50", e.Value)
+			require.Equal(t, "0", e.Default)
+		} else {
+			require.False(t, e.Overridden)
+			require.Equal(t, e.Default, e.Value)
+		}
+	}
+}
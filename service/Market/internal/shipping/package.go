@@ -0,0 +1,100 @@
+package shipping
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+)
+
+// Limits are the largest parcel a carrier accepts. Girth is the longest
+// side plus twice the sum of the other two. A zero limit is not checked.
+type Limits struct {
+	MaxWeightGrams int
+	MaxLengthMM    int
+	MaxGirthMM     int
+}
+
+// Package computes the parcel items ship in and checks it against limits.
+// The weight is the sum over all units; the box lays the units flat on top
+// of each other, so it is as long and wide as the largest of them and as
+// high as all of them together. Units without a size add no volume.
+//
+// Every unit must fit the carrier's limits on its own; the parcel as a
+// whole is only checked for weight, since the box is a rough estimate.
+func Package(items []*models.CartItemWithDetails, limits Limits) *models.ShippingPackage {
+	pkg := &models.ShippingPackage{Complete: true}
+	for _, item := range items {
+		if item.WeightGrams == nil {
+			pkg.Complete = false
+		} else {
+			pkg.WeightGrams += *item.WeightGrams * item.Quantity
+		}
+
+		sides := sortedSides(item.Dimensions)
+		pkg.LengthMM = max(pkg.LengthMM, sides[0])
+		pkg.WidthMM = max(pkg.WidthMM, sides[1])
+		pkg.HeightMM += sides[2] * item.Quantity
+
+		if reason := oversize(item.Dimensions, sides, limits); reason != "" {
+			pkg.Oversize = append(pkg.Oversize, models.BlockedItem{
+				ProductID:    item.ProductID,
+				ProductTitle: item.ProductTitle,
+				Reason:       reason,
+			})
+		}
+	}
+
+	box := sortedSides(models.Dimensions{LengthMM: &pkg.LengthMM, WidthMM: &pkg.WidthMM, HeightMM: &pkg.HeightMM})
+	pkg.LengthMM, pkg.WidthMM, pkg.HeightMM = box[0], box[1], box[2]
+
+	if limits.MaxWeightGrams > 0 && pkg.WeightGrams > limits.MaxWeightGrams {
+		pkg.Exceeds = fmt.Sprintf("the parcel weighs %s, over the carrier limit of %s; order fewer items at once",
+			kilograms(pkg.WeightGrams), kilograms(limits.MaxWeightGrams))
+	}
+	return pkg
+}
+
+// Message summarizes why pkg cannot be shipped, or returns "" when it can.
+func Message(pkg *models.ShippingPackage) string {
+	switch {
+	case len(pkg.Oversize) > 0:
+		return "some items are too large for the carrier"
+	case pkg.Exceeds != "":
+		return pkg.Exceeds
+	}
+	return ""
+}
+
+// oversize explains why one unit with dimensions d exceeds limits, or
+// returns "" when it fits.
+func oversize(d models.Dimensions, sides [3]int, limits Limits) string {
+	if limits.MaxWeightGrams > 0 && d.WeightGrams != nil && *d.WeightGrams > limits.MaxWeightGrams {
+		return fmt.Sprintf("weighs %s, over the carrier limit of %s",
+			kilograms(*d.WeightGrams), kilograms(limits.MaxWeightGrams))
+	}
+	if limits.MaxLengthMM > 0 && sides[0] > limits.MaxLengthMM {
+		return fmt.Sprintf("is %d mm long, over the carrier limit of %d mm", sides[0], limits.MaxLengthMM)
+	}
+	if girth := sides[0] + 2*(sides[1]+sides[2]); limits.MaxGirthMM > 0 && girth > limits.MaxGirthMM {
+		return fmt.Sprintf("has a girth of %d mm, over the carrier limit of %d mm", girth, limits.MaxGirthMM)
+	}
+	return ""
+}
+
+// sortedSides returns the sides of d longest first, unset sides as zero.
+func sortedSides(d models.Dimensions) [3]int {
+	var sides [3]int
+	for i, side := range []*int{d.LengthMM, d.WidthMM, d.HeightMM} {
+		if side != nil {
+			sides[i] = *side
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sides[:])))
+	return sides
+}
+
+func kilograms(grams int) string {
+	return strconv.FormatFloat(float64(grams)/1000, 'f', -1, 64) + " kg"
+}
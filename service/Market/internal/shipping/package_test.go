@@ -0,0 +1,81 @@
+package shipping
+
+import (
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(n int) *int { return &n }
+
+func packageItem(productID, quantity int, d models.Dimensions) *models.CartItemWithDetails {
+	return &models.CartItemWithDetails{
+		CartItem:     models.CartItem{ProductID: productID, Quantity: quantity},
+		ProductTitle: "Item",
+		Dimensions:   d,
+	}
+}
+
+var carrier = Limits{MaxWeightGrams: 31500, MaxLengthMM: 1200, MaxGirthMM: 3000}
+
+func TestPackage(t *testing.T) {
+	items := []*models.CartItemWithDetails{
+		packageItem(1, 2, models.Dimensions{WeightGrams: intPtr(450), LengthMM: intPtr(40), WidthMM: intPtr(300), HeightMM: intPtr(200)}),
+		packageItem(2, 1, models.Dimensions{WeightGrams: intPtr(1200), LengthMM: intPtr(350), WidthMM: intPtr(100), HeightMM: intPtr(100)}),
+	}
+
+	pkg := Package(items, carrier)
+	require.Equal(t, 2100, pkg.WeightGrams)
+	// 350 long, 200 wide and 2×40 + 100 high, longest side first.
+	require.Equal(t, 350, pkg.LengthMM)
+	require.Equal(t, 200, pkg.WidthMM)
+	require.Equal(t, 180, pkg.HeightMM)
+	require.True(t, pkg.Complete)
+	require.Empty(t, pkg.Oversize)
+	require.Empty(t, Message(pkg))
+}
+
+func TestPackage_MissingWeight(t *testing.T) {
+	items := []*models.CartItemWithDetails{
+		packageItem(1, 1, models.Dimensions{WeightGrams: intPtr(500)}),
+		packageItem(2, 3, models.Dimensions{}),
+	}
+
+	pkg := Package(items, carrier)
+	require.Equal(t, 500, pkg.WeightGrams)
+	require.False(t, pkg.Complete)
+	require.Zero(t, pkg.LengthMM)
+	require.Empty(t, Message(pkg))
+}
+
+func TestPackage_OversizeItems(t *testing.T) {
+	items := []*models.CartItemWithDetails{
+		packageItem(1, 1, models.Dimensions{WeightGrams: intPtr(40000)}),
+		packageItem(2, 1, models.Dimensions{LengthMM: intPtr(100), WidthMM: intPtr(1500), HeightMM: intPtr(100)}),
+		packageItem(3, 1, models.Dimensions{LengthMM: intPtr(1000), WidthMM: intPtr(600), HeightMM: intPtr(500)}),
+		packageItem(4, 1, models.Dimensions{LengthMM: intPtr(1000), WidthMM: intPtr(100), HeightMM: intPtr(100)}),
+	}
+
+	pkg := Package(items, carrier)
+	require.Len(t, pkg.Oversize, 3)
+	require.Equal(t, 1, pkg.Oversize[0].ProductID)
+	require.Equal(t, "weighs 40 kg, over the carrier limit of 31.5 kg", pkg.Oversize[0].Reason)
+	require.Equal(t, "is 1500 mm long, over the carrier limit of 1200 mm", pkg.Oversize[1].Reason)
+	require.Equal(t, "has a girth of 3200 mm, over the carrier limit of 3000 mm", pkg.Oversize[2].Reason)
+	require.Equal(t, "some items are too large for the carrier", Message(pkg))
+}
+
+func TestPackage_TooHeavy(t *testing.T) {
+	items := []*models.CartItemWithDetails{
+		packageItem(1, 4, models.Dimensions{WeightGrams: intPtr(8000)}),
+	}
+
+	pkg := Package(items, carrier)
+	require.Empty(t, pkg.Oversize)
+	require.Equal(t, "the parcel weighs 32 kg, over the carrier limit of 31.5 kg; order fewer items at once", pkg.Exceeds)
+	require.Equal(t, pkg.Exceeds, Message(pkg))
+
+	pkg = Package(items, Limits{})
+	require.Empty(t, Message(pkg), "zero limits are not checked")
+}
@@ -0,0 +1,106 @@
+// Package shipping validates delivery destinations against per-product
+// shipping restrictions and parcels against the carrier's limits.
+package shipping
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+)
+
+var codePattern = regexp.MustCompile(`^[A-Z]{2}(-[A-Z0-9]{1,3})?$`)
+
+// Destination is where an order is delivered. Region is an ISO 3166-2 code
+// such as "US-AK" and may be empty.
+type Destination struct {
+	Country string
+	Region  string
+}
+
+// NewDestination normalizes and validates a country/region pair. A bare
+// subdivision ("AK") is qualified with the country.
+func NewDestination(country, region string) (Destination, error) {
+	d := Destination{
+		Country: strings.ToUpper(strings.TrimSpace(country)),
+		Region:  strings.ToUpper(strings.TrimSpace(region)),
+	}
+	if d.Country == "" {
+		return Destination{}, fmt.Errorf("country is required")
+	}
+	if !codePattern.MatchString(d.Country) || strings.Contains(d.Country, "-") {
+		return Destination{}, fmt.Errorf("invalid country code %q", country)
+	}
+	if d.Region != "" {
+		if !strings.Contains(d.Region, "-") {
+			d.Region = d.Country + "-" + d.Region
+		}
+		if !codePattern.MatchString(d.Region) || !strings.HasPrefix(d.Region, d.Country+"-") {
+			return Destination{}, fmt.Errorf("invalid region code %q", region)
+		}
+	}
+	return d, nil
+}
+
+// NormalizeCodes upper-cases, validates and de-duplicates restriction codes.
+func NormalizeCodes(codes []string) ([]string, error) {
+	out := make([]string, 0, len(codes))
+	seen := make(map[string]bool, len(codes))
+	for _, c := range codes {
+		c = strings.ToUpper(strings.TrimSpace(c))
+		if !codePattern.MatchString(c) {
+			return nil, fmt.Errorf("invalid country or region code %q", c)
+		}
+		if !seen[c] {
+			seen[c] = true
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func contains(codes []string, d Destination) bool {
+	for _, c := range codes {
+		if c == d.Country || (d.Region != "" && c == d.Region) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reason explains why r blocks delivery to d, or returns "" when allowed.
+func Reason(r *models.ShippingRestrictions, d Destination) string {
+	if r == nil {
+		return ""
+	}
+	if contains(r.NoShipTo, d) {
+		return fmt.Sprintf("does not ship to %s", d.String())
+	}
+	if len(r.ShipsTo) > 0 && !contains(r.ShipsTo, d) {
+		return fmt.Sprintf("ships only to %s", strings.Join(r.ShipsTo, ", "))
+	}
+	return ""
+}
+
+func (d Destination) String() string {
+	if d.Region != "" {
+		return d.Region
+	}
+	return d.Country
+}
+
+// BlockedItems returns the cart items that cannot be shipped to d.
+func BlockedItems(items []*models.CartItemWithDetails, restrictions map[int]*models.ShippingRestrictions, d Destination) []models.BlockedItem {
+	var blocked []models.BlockedItem
+	for _, item := range items {
+		if reason := Reason(restrictions[item.ProductID], d); reason != "" {
+			blocked = append(blocked, models.BlockedItem{
+				ProductID:    item.ProductID,
+				ProductTitle: item.ProductTitle,
+				Reason:       reason,
+			})
+		}
+	}
+	return blocked
+}
@@ -0,0 +1,73 @@
+package shipping
+
+import (
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDestination(t *testing.T) {
+	d, err := NewDestination(" us ", "ak")
+	require.NoError(t, err)
+	require.Equal(t, Destination{Country: "US", Region: "US-AK"}, d)
+
+	d, err = NewDestination("de", "")
+	require.NoError(t, err)
+	require.Equal(t, "DE", d.String())
+
+	_, err = NewDestination("", "")
+	require.Error(t, err)
+	_, err = NewDestination("USA", "")
+	require.Error(t, err)
+	_, err = NewDestination("US", "CA-ON")
+	require.Error(t, err, "region must belong to the country")
+}
+
+func TestNormalizeCodes(t *testing.T) {
+	codes, err := NormalizeCodes([]string{"de", "US-ak", "DE"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"DE", "US-AK"}, codes)
+
+	codes, err = NormalizeCodes(nil)
+	require.NoError(t, err)
+	require.NotNil(t, codes)
+	require.Empty(t, codes)
+
+	_, err = NormalizeCodes([]string{"Germany"})
+	require.Error(t, err)
+}
+
+func TestReason(t *testing.T) {
+	us := Destination{Country: "US", Region: "US-CA"}
+	alaska := Destination{Country: "US", Region: "US-AK"}
+	de := Destination{Country: "DE"}
+
+	require.Empty(t, Reason(nil, us))
+
+	allow := &models.ShippingRestrictions{ShipsTo: []string{"US"}, NoShipTo: []string{"US-AK"}}
+	require.Empty(t, Reason(allow, us))
+	require.Equal(t, "does not ship to US-AK", Reason(allow, alaska))
+	require.Equal(t, "ships only to US", Reason(allow, de))
+
+	deny := &models.ShippingRestrictions{NoShipTo: []string{"DE"}}
+	require.Empty(t, Reason(deny, us))
+	require.NotEmpty(t, Reason(deny, de))
+}
+
+func TestBlockedItems(t *testing.T) {
+	items := []*models.CartItemWithDetails{
+		{CartItem: models.CartItem{ProductID: 1}, ProductTitle: "Knife"},
+		{CartItem: models.CartItem{ProductID: 2}, ProductTitle: "Socks"},
+	}
+	restrictions := map[int]*models.ShippingRestrictions{
+		1: {ProductID: 1, NoShipTo: []string{"GB"}},
+	}
+
+	blocked := BlockedItems(items, restrictions, Destination{Country: "GB"})
+	require.Len(t, blocked, 1)
+	require.Equal(t, 1, blocked[0].ProductID)
+	require.Equal(t, "Knife", blocked[0].ProductTitle)
+
+	require.Empty(t, BlockedItems(items, restrictions, Destination{Country: "FR"}))
+}
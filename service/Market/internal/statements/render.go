@@ -0,0 +1,103 @@
+package statements
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/jung-kurt/gofpdf"
+)
+
+func lines(st *models.SellerStatement) [][2]string {
+	money := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+	return [][2]string{
+		{"Orders", strconv.Itoa(st.OrdersCount)},
+		{"Items sold", strconv.Itoa(st.ItemsSold)},
+		{"Gross sales", money(st.GrossSales)},
+		{"Discounts", money(-st.Discounts)},
+		{"Refunds", money(-st.Refunds)},
+		{"Commission", money(-st.Commission)},
+		{"Chargebacks", money(-st.Chargebacks)},
+		{"Payout", money(st.Payout)},
+	}
+}
+
+// invoicing lists the seller's invoicing details that are on file.
+func invoicing(st *models.SellerStatement) [][2]string {
+	var out [][2]string
+	for _, l := range [][2]string{
+		{"legal_name", st.LegalName},
+		{"tax_id", st.TaxID},
+		{"address", st.InvoiceAddress},
+	} {
+		if l[1] != "" {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// WriteCSV writes the statement as two-column CSV.
+func WriteCSV(w io.Writer, st *models.SellerStatement) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{{"shop", st.ShopName}}
+	for _, l := range invoicing(st) {
+		rows = append(rows, []string{l[0], l[1]})
+	}
+	rows = append(rows, []string{"period", st.Period})
+	for _, l := range lines(st) {
+		rows = append(rows, []string{l[0], l[1]})
+	}
+	rows = append(rows, []string{"generated_at", st.GeneratedAt.UTC().Format("2006-01-02 15:04:05")})
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// PDF renders the statement as a one-page A4 document.
+func PDF(st *models.SellerStatement) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(fmt.Sprintf("Statement %s", st.Period), true)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.Cell(0, 10, tr(fmt.Sprintf("Seller statement %s", st.Period)))
+	pdf.Ln(10)
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.Cell(0, 7, tr(st.ShopName))
+	pdf.Ln(7)
+	pdf.SetFont("Helvetica", "", 9)
+	for _, l := range invoicing(st) {
+		text := l[1]
+		if l[0] == "tax_id" {
+			text = "Tax ID: " + text
+		}
+		pdf.Cell(0, 5, tr(text))
+		pdf.Ln(5)
+	}
+	pdf.Ln(5)
+	pdf.SetFont("Helvetica", "", 11)
+
+	for _, l := range lines(st) {
+		if l[0] == "Payout" {
+			pdf.SetFont("Helvetica", "B", 11)
+		}
+		pdf.CellFormat(60, 8, l[0], "B", 0, "L", false, 0, "")
+		pdf.CellFormat(40, 8, l[1], "B", 1, "R", false, 0, "")
+	}
+
+	pdf.Ln(6)
+	pdf.SetFont("Helvetica", "I", 8)
+	pdf.Cell(0, 5, fmt.Sprintf("Generated %s UTC", st.GeneratedAt.UTC().Format("2006-01-02 15:04")))
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
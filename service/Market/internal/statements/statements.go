@@ -0,0 +1,85 @@
+// Package statements produces monthly seller statements and renders them
+// for download.
+package statements
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+	"github.com/Zifeldev/marketback/service/Market/internal/workers"
+)
+
+// PeriodLayout is the YYYY-MM form periods are exchanged in.
+const PeriodLayout = "2006-01"
+
+// ParsePeriod parses "YYYY-MM" into the first instant of that month (UTC).
+func ParsePeriod(s string) (time.Time, error) {
+	t, err := time.Parse(PeriodLayout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("period must be YYYY-MM")
+	}
+	return t, nil
+}
+
+// MonthStart truncates t to the first instant of its month (UTC).
+func MonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Generator aggregates and stores the statements of every seller for the
+// month starting at period, returning how many were written.
+type Generator interface {
+	Generate(ctx context.Context, period time.Time) (int64, error)
+}
+
+// Scheduler regenerates the last closed month on every run. Generation is an
+// upsert, so repeated runs are harmless and pick up late refunds.
+type Scheduler struct {
+	gen Generator
+	now func() time.Time
+}
+
+func NewScheduler(gen Generator) *Scheduler {
+	return &Scheduler{gen: gen, now: time.Now}
+}
+
+// RunOnce generates statements for the previous calendar month.
+func (s *Scheduler) RunOnce(ctx context.Context) error {
+	period := MonthStart(s.now()).AddDate(0, -1, 0)
+
+	written, err := s.gen.Generate(ctx, period)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).WithField("period", period.Format(PeriodLayout)).Error("failed to generate seller statements")
+		return fmt.Errorf("failed to generate seller statements for %s: %w", period.Format(PeriodLayout), err)
+	}
+
+	metrics.StatementsGeneratedTotal.Add(float64(written))
+	logger.GetLogger().WithFields(map[string]interface{}{
+		"period":     period.Format(PeriodLayout),
+		"statements": written,
+	}).Info("seller statements generated")
+	return nil
+}
+
+// Start runs the scheduler every interval until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		workers.Beat(ctx)
+		if err := s.RunOnce(ctx); err != nil {
+			metrics.StatementRunFailuresTotal.Inc()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
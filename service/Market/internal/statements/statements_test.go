@@ -0,0 +1,94 @@
+package statements
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeGenerator struct {
+	periods []time.Time
+	err     error
+}
+
+func (f *fakeGenerator) Generate(ctx context.Context, period time.Time) (int64, error) {
+	f.periods = append(f.periods, period)
+	return 3, f.err
+}
+
+func TestParsePeriod(t *testing.T) {
+	p, err := ParsePeriod("2026-02")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), p)
+
+	_, err = ParsePeriod("2026-13")
+	require.Error(t, err)
+	_, err = ParsePeriod("02/2026")
+	require.Error(t, err)
+}
+
+func TestScheduler_RunOnce_PreviousMonth(t *testing.T) {
+	gen := &fakeGenerator{}
+	s := NewScheduler(gen)
+	s.now = func() time.Time { return time.Date(2026, 1, 3, 8, 0, 0, 0, time.UTC) }
+
+	require.NoError(t, s.RunOnce(context.Background()))
+	require.Equal(t, []time.Time{time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)}, gen.periods)
+}
+
+func TestScheduler_RunOnce_Error(t *testing.T) {
+	s := NewScheduler(&fakeGenerator{err: errors.New("db down")})
+	require.Error(t, s.RunOnce(context.Background()))
+}
+
+func testStatement() *models.SellerStatement {
+	return &models.SellerStatement{
+		ShopName:    "Café Shop",
+		Period:      "2026-02",
+		OrdersCount: 4,
+		ItemsSold:   7,
+		GrossSales:  250,
+		Discounts:   10,
+		Commission:  22.5,
+		Refunds:     25,
+		Chargebacks: 12.5,
+		Payout:      180,
+		GeneratedAt: time.Date(2026, 3, 1, 0, 5, 0, 0, time.UTC),
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, testStatement()))
+
+	out := buf.String()
+	require.Contains(t, out, "period,2026-02\n")
+	require.Contains(t, out, "Gross sales,250.00\n")
+	require.Contains(t, out, "Discounts,-10.00\n")
+	require.Contains(t, out, "Commission,-22.50\n")
+	require.Contains(t, out, "Chargebacks,-12.50\n")
+	require.Contains(t, out, "Payout,180.00\n")
+	require.NotContains(t, out, "tax_id")
+
+	st := testStatement()
+	st.LegalName, st.TaxID, st.InvoiceAddress = "Café Shop GmbH", "DE123456789", "Hauptstrasse 1, 10115 Berlin, DE"
+	buf.Reset()
+	require.NoError(t, WriteCSV(&buf, st))
+	out = buf.String()
+	require.Contains(t, out, "legal_name,Café Shop GmbH\n")
+	require.Contains(t, out, "tax_id,DE123456789\n")
+	require.Contains(t, out, "address,\"Hauptstrasse 1, 10115 Berlin, DE\"\n")
+}
+
+func TestPDF(t *testing.T) {
+	st := testStatement()
+	st.LegalName, st.TaxID, st.InvoiceAddress = "Café Shop GmbH", "DE123456789", "Hauptstrasse 1, 10115 Berlin, DE"
+	out, err := PDF(st)
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(out, []byte("%PDF-")))
+}
@@ -0,0 +1,68 @@
+// Package stockalerts notifies sellers when orders run their products low
+// on stock.
+package stockalerts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+	"github.com/Zifeldev/marketback/service/Market/internal/workers"
+)
+
+// batchSize bounds the products alerted in one transaction.
+const batchSize = 100
+
+// Store alerts the sellers of up to limit products that dropped to their
+// low-stock threshold, threshold for products without one, returning how
+// many; see repository.SellerStatsRepository.
+type Store interface {
+	AlertLowStock(ctx context.Context, threshold, limit int) (int, error)
+}
+
+// Scanner finds products that ran low since the last run.
+type Scanner struct {
+	store     Store
+	threshold int
+}
+
+func NewScanner(store Store, threshold int) *Scanner {
+	return &Scanner{store: store, threshold: threshold}
+}
+
+// RunOnce alerts every product that ran low, a batch at a time.
+func (s *Scanner) RunOnce(ctx context.Context) error {
+	for ctx.Err() == nil {
+		n, err := s.store.AlertLowStock(ctx, s.threshold, batchSize)
+		if err != nil {
+			logger.GetLogger().WithField("err", err).Error("failed to alert low stock")
+			return fmt.Errorf("failed to alert low stock: %w", err)
+		}
+		metrics.LowStockAlertsTotal.Add(float64(n))
+		if n < batchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// Start runs the scanner every interval until ctx is cancelled.
+func (s *Scanner) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		workers.Beat(ctx)
+		if err := s.RunOnce(ctx); err != nil {
+			metrics.LowStockRunFailuresTotal.Inc()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package stockalerts
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	pending    int
+	thresholds []int
+	err        error
+}
+
+func (f *fakeStore) AlertLowStock(ctx context.Context, threshold, limit int) (int, error) {
+	f.thresholds = append(f.thresholds, threshold)
+	if f.err != nil {
+		return 0, f.err
+	}
+	n := min(f.pending, limit)
+	f.pending -= n
+	return n, nil
+}
+
+func TestScanner_RunOnce(t *testing.T) {
+	store := &fakeStore{pending: batchSize + 3}
+	require.NoError(t, NewScanner(store, 5).RunOnce(context.Background()))
+	require.Zero(t, store.pending)
+	require.Equal(t, []int{5, 5}, store.thresholds, "runs until a batch comes back short")
+}
+
+func TestScanner_RunOnce_Error(t *testing.T) {
+	store := &fakeStore{err: errors.New("db down")}
+	require.Error(t, NewScanner(store, 5).RunOnce(context.Background()))
+}
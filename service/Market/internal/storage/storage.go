@@ -0,0 +1,91 @@
+// Package storage abstracts where uploaded files live. Only a local-disk
+// backend exists today; files are served by the router under /uploads.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("file not found")
+	ErrInvalidKey = errors.New("invalid file key")
+)
+
+// Storage stores files under slash-separated keys such as "proofs/abc.jpg".
+type Storage interface {
+	// Put writes r under key and returns the public URL of the file.
+	Put(ctx context.Context, key string, r io.Reader) (string, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Local keeps files in a directory on disk.
+type Local struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocal creates dir if needed. URLs are built as baseURL + "/uploads/" + key.
+func NewLocal(dir, baseURL string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create upload directory: %w", err)
+	}
+	return &Local{dir: dir, baseURL: baseURL}, nil
+}
+
+func (l *Local) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "..") || strings.Contains(key, `\`) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(l.dir, filepath.FromSlash(key)), nil
+}
+
+func (l *Local) Put(_ context.Context, key string, r io.Reader) (string, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	f, err := os.Create(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(p)
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(p)
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return l.URL(key), nil
+}
+
+func (l *Local) Delete(_ context.Context, key string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil {
+		if os.IsNotExist(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// URL returns the public URL for key.
+func (l *Local) URL(key string) string {
+	return fmt.Sprintf("%s/uploads/%s", l.baseURL, key)
+}
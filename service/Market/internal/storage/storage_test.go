@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalPutDelete(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLocal(dir, "http://cdn")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	url, err := s.Put(ctx, "proofs/a.jpg", strings.NewReader("img"))
+	require.NoError(t, err)
+	assert.Equal(t, "http://cdn/uploads/proofs/a.jpg", url)
+
+	data, err := os.ReadFile(filepath.Join(dir, "proofs", "a.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, "img", string(data))
+
+	require.NoError(t, s.Delete(ctx, "proofs/a.jpg"))
+	assert.ErrorIs(t, s.Delete(ctx, "proofs/a.jpg"), ErrNotFound)
+}
+
+func TestLocalRejectsTraversal(t *testing.T) {
+	s, err := NewLocal(t.TempDir(), "")
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "../x.jpg", "/etc/passwd", `a\b.jpg`} {
+		_, err := s.Put(context.Background(), key, strings.NewReader(""))
+		assert.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+}
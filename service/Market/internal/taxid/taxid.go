@@ -0,0 +1,85 @@
+// Package taxid validates the tax registration numbers and postal codes
+// sellers put on their invoicing details, by country.
+package taxid
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// format is how one country writes tax IDs, after Normalize, and postal
+// codes. Name is what the tax ID is called in error messages.
+type format struct {
+	Name   string
+	TaxID  *regexp.Regexp
+	Postal *regexp.Regexp
+}
+
+// formats covers the countries most sellers register in. EU VAT numbers
+// include their country prefix, as on intra-EU invoices.
+var formats = map[string]format{
+	"AT": {"VAT number", regexp.MustCompile(`^ATU\d{8}$`), regexp.MustCompile(`^\d{4}$`)},
+	"AU": {"ABN", regexp.MustCompile(`^\d{11}$`), regexp.MustCompile(`^\d{4}$`)},
+	"BE": {"VAT number", regexp.MustCompile(`^BE[01]\d{9}$`), regexp.MustCompile(`^\d{4}$`)},
+	"CA": {"business number", regexp.MustCompile(`^\d{9}(RT\d{4})?$`), regexp.MustCompile(`^[A-Z]\d[A-Z] ?\d[A-Z]\d$`)},
+	"CH": {"UID", regexp.MustCompile(`^CHE\d{9}(MWST|TVA|IVA)?$`), regexp.MustCompile(`^\d{4}$`)},
+	"DE": {"VAT number", regexp.MustCompile(`^DE\d{9}$`), regexp.MustCompile(`^\d{5}$`)},
+	"ES": {"VAT number", regexp.MustCompile(`^ES[A-Z0-9]\d{7}[A-Z0-9]$`), regexp.MustCompile(`^\d{5}$`)},
+	"FR": {"VAT number", regexp.MustCompile(`^FR[A-HJ-NP-Z0-9]{2}\d{9}$`), regexp.MustCompile(`^\d{5}$`)},
+	"GB": {"VAT number", regexp.MustCompile(`^GB(\d{9}|\d{12}|GD\d{3}|HA\d{3})$`), regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`)},
+	"IT": {"VAT number", regexp.MustCompile(`^IT\d{11}$`), regexp.MustCompile(`^\d{5}$`)},
+	"NL": {"VAT number", regexp.MustCompile(`^NL\d{9}B\d{2}$`), regexp.MustCompile(`^\d{4} ?[A-Z]{2}$`)},
+	"PL": {"VAT number", regexp.MustCompile(`^PL\d{10}$`), regexp.MustCompile(`^\d{2}-\d{3}$`)},
+	"SE": {"VAT number", regexp.MustCompile(`^SE\d{10}01$`), regexp.MustCompile(`^\d{3} ?\d{2}$`)},
+	"US": {"EIN", regexp.MustCompile(`^\d{9}$`), regexp.MustCompile(`^\d{5}(-\d{4})?$`)},
+}
+
+// genericTaxID and genericPostal accept any plausible value for countries
+// without a known format.
+var (
+	genericTaxID  = regexp.MustCompile(`^[A-Z0-9]{4,20}$`)
+	genericPostal = regexp.MustCompile(`^[A-Z0-9][A-Z0-9 -]{1,10}$`)
+)
+
+// Country normalizes and validates an ISO 3166-1 alpha-2 country code.
+func Country(code string) (string, error) {
+	c := strings.ToUpper(strings.TrimSpace(code))
+	if !countryPattern.MatchString(c) {
+		return "", fmt.Errorf("invalid country code %q", code)
+	}
+	return c, nil
+}
+
+// Normalize strips spaces, dots and dashes from a tax ID, upper-cases it
+// and checks it against the country's format.
+func Normalize(country, id string) (string, error) {
+	n := strings.ToUpper(strings.NewReplacer(" ", "", ".", "", "-", "").Replace(id))
+	f, ok := formats[country]
+	if !ok {
+		if !genericTaxID.MatchString(n) {
+			return "", fmt.Errorf("must be 4 to 20 letters or digits")
+		}
+		return n, nil
+	}
+	if !f.TaxID.MatchString(n) {
+		return "", fmt.Errorf("not a valid %s %s", country, f.Name)
+	}
+	return n, nil
+}
+
+// PostalCode upper-cases a postal code and checks it against the
+// country's format.
+func PostalCode(country, code string) (string, error) {
+	p := strings.ToUpper(strings.TrimSpace(code))
+	pattern := genericPostal
+	if f, ok := formats[country]; ok {
+		pattern = f.Postal
+	}
+	if !pattern.MatchString(p) {
+		return "", fmt.Errorf("not a valid %s postal code", country)
+	}
+	return p, nil
+}
@@ -0,0 +1,70 @@
+package taxid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountry(t *testing.T) {
+	c, err := Country(" de ")
+	require.NoError(t, err)
+	assert.Equal(t, "DE", c)
+
+	_, err = Country("DEU")
+	assert.Error(t, err)
+	_, err = Country("")
+	assert.Error(t, err)
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		country string
+		id      string
+		want    string
+		wantErr bool
+	}{
+		{country: "DE", id: "de 123 456 789", want: "DE123456789"},
+		{country: "DE", id: "123456789", wantErr: true},
+		{country: "NL", id: "NL123456789B01", want: "NL123456789B01"},
+		{country: "FR", id: "FR IO 123456789", wantErr: true},
+		{country: "GB", id: "GB 123 4567 89", want: "GB123456789"},
+		{country: "US", id: "12-3456789", want: "123456789"},
+		{country: "US", id: "123-45-678", wantErr: true},
+		{country: "CH", id: "CHE-123.456.789 MWST", want: "CHE123456789MWST"},
+		{country: "BR", id: "12.345.678/0001-95", wantErr: true},
+		{country: "BR", id: "12.345.678-000195", want: "12345678000195"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.country+" "+tt.id, func(t *testing.T) {
+			got, err := Normalize(tt.country, tt.id)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPostalCode(t *testing.T) {
+	p, err := PostalCode("NL", "1012 ab")
+	require.NoError(t, err)
+	assert.Equal(t, "1012 AB", p)
+
+	p, err = PostalCode("GB", "sw1a 1aa")
+	require.NoError(t, err)
+	assert.Equal(t, "SW1A 1AA", p)
+
+	_, err = PostalCode("DE", "1011")
+	assert.Error(t, err)
+	_, err = PostalCode("PL", "00950")
+	assert.Error(t, err, "Polish codes are written with a dash")
+
+	p, err = PostalCode("JP", "100-0001")
+	require.NoError(t, err)
+	assert.Equal(t, "100-0001", p)
+}
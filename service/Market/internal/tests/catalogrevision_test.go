@@ -0,0 +1,135 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCatalogRevisions checks that restoring a revision undoes a bad
+// import: prices, variants and deleted products come back, products added
+// since are deleted, and the restore itself can be undone.
+func TestCatalogRevisions(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var sellerID, otherID, categoryID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (70, 'Tee Shop', true) RETURNING id`).Scan(&sellerID))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (71, 'Other Shop', true) RETURNING id`).Scan(&otherID))
+	require.NoError(t, pool.QueryRow(ctx, `INSERT INTO categories (name) VALUES ('Tees') RETURNING id`).Scan(&categoryID))
+
+	products := repository.NewProductRepository(pool, nil)
+	tee, err := products.Create(ctx, sellerID, &models.CreateProductRequest{
+		CategoryID: categoryID, Title: "Tee", Price: 20,
+		Variants: []models.CreateVariantRequest{
+			{SKU: "TEE-S", Size: "S", Stock: 4},
+			{SKU: "TEE-M", Size: "M", Stock: 2, PriceDelta: 5},
+		},
+	})
+	require.NoError(t, err)
+	mug, err := products.Create(ctx, sellerID, &models.CreateProductRequest{CategoryID: categoryID, Title: "Mug", Price: 8, Stock: 10})
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `UPDATE products SET status = 'active' WHERE seller_id = $1`, sellerID)
+	require.NoError(t, err)
+
+	repo := repository.NewCatalogRevisionRepository(pool, nil, 3)
+	before, err := repo.Create(ctx, sellerID, models.CatalogRevisionImport, "products.csv", 70)
+	require.NoError(t, err)
+	require.Equal(t, 1, before.Version)
+	require.Equal(t, 2, before.ProductCount)
+
+	// A bad import: the tee is repriced and its medium swapped for a large,
+	// the mug is deleted and a cap added. Orders take from the stock.
+	_, err = pool.Exec(ctx, `UPDATE products SET price = 2, title = 'Tee!' WHERE id = $1`, tee.ID)
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `DELETE FROM product_variants WHERE product_id = $1 AND sku = 'TEE-M'`, tee.ID)
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `UPDATE product_variants SET stock = 1 WHERE product_id = $1 AND sku = 'TEE-S'`, tee.ID)
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `INSERT INTO product_variants (product_id, sku, size, stock) VALUES ($1, 'TEE-L', 'L', 3)`, tee.ID)
+	require.NoError(t, err)
+	require.NoError(t, products.Delete(ctx, mug.ID))
+	capProduct, err := products.Create(ctx, sellerID, &models.CreateProductRequest{CategoryID: categoryID, Title: "Cap", Price: 12, Stock: 5})
+	require.NoError(t, err)
+
+	var appErr *apperrors.AppError
+	_, err = repo.Restore(ctx, before.ID, otherID, false, 71)
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeNotFound, appErr.Code)
+
+	restored, err := repo.Restore(ctx, before.ID, sellerID, false, 70)
+	require.NoError(t, err)
+	require.Equal(t, 1, restored.Restored)
+	require.Equal(t, 1, restored.Recreated)
+	require.Equal(t, 1, restored.Hidden)
+	require.Equal(t, 2, restored.Backup.Version)
+	require.Equal(t, models.CatalogRevisionRestore, restored.Backup.Reason)
+
+	var (
+		title  string
+		price  float64
+		stock  int
+		status string
+	)
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT title, price::float8, stock FROM products WHERE id = $1`, tee.ID).Scan(&title, &price, &stock))
+	require.Equal(t, "Tee", title)
+	require.Equal(t, 20.0, price)
+	require.Equal(t, 1, stock, "stock is kept: variant S at 1, M recreated empty")
+
+	rows, err := pool.Query(ctx, `SELECT sku, stock FROM product_variants WHERE product_id = $1 ORDER BY id`, tee.ID)
+	require.NoError(t, err)
+	variantStock := map[string]int{}
+	for rows.Next() {
+		var sku string
+		var n int
+		require.NoError(t, rows.Scan(&sku, &n))
+		variantStock[sku] = n
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, map[string]int{"TEE-S": 1, "TEE-M": 0}, variantStock)
+
+	require.NoError(t, pool.QueryRow(ctx, `SELECT title, status FROM products WHERE id = $1`, mug.ID).Scan(&title, &status))
+	require.Equal(t, "Mug", title)
+	require.Equal(t, "active", status)
+	require.NoError(t, pool.QueryRow(ctx, `SELECT status FROM products WHERE id = $1`, capProduct.ID).Scan(&status))
+	require.Equal(t, "deleted", status)
+
+	// Undoing the restore with stock brings the import back as it was.
+	undone, err := repo.Restore(ctx, restored.Backup.ID, sellerID, true, 70)
+	require.NoError(t, err)
+	require.Equal(t, 2, undone.Restored)
+	require.Equal(t, 1, undone.Hidden)
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT price::float8, stock FROM products WHERE id = $1`, tee.ID).Scan(&price, &stock))
+	require.Equal(t, 2.0, price)
+	require.Equal(t, 4, stock, "variants S at 1 and L at 3")
+	require.NoError(t, pool.QueryRow(ctx, `SELECT status FROM products WHERE id = $1`, mug.ID).Scan(&status))
+	require.Equal(t, "deleted", status)
+
+	revisions, err := repo.GetBySeller(ctx, sellerID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	require.Equal(t, 3, revisions[0].Version)
+	require.Empty(t, revisions[0].Products)
+
+	_, err = repo.Create(ctx, sellerID, models.CatalogRevisionManual, "", 70)
+	require.NoError(t, err)
+	revisions, err = repo.GetBySeller(ctx, sellerID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3, "only the newest revisions are kept")
+	require.Equal(t, 2, revisions[2].Version)
+
+	_, err = repo.GetByID(ctx, before.ID, sellerID)
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeNotFound, appErr.Code)
+}
@@ -0,0 +1,107 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChargebackHoldsPayouts checks that a chargeback is split over the
+// order's sellers, opens a dispute ticket for each and comes off their
+// statement until it is won.
+func TestChargebackHoldsPayouts(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var categoryID int
+	require.NoError(t, pool.QueryRow(ctx, `INSERT INTO categories (name) VALUES ('Chargeback') RETURNING id`).Scan(&categoryID))
+
+	var orderID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO orders (user_id, total_amount, status, delivery_address, order_number)
+		VALUES (640, 30, 'pending', 'addr', 'MB-C-' || nextval('order_number_seq')) RETURNING id`).Scan(&orderID))
+	sellerIDs := map[int]int{}
+	for userID, quantity := range map[int]int{641: 2, 642: 1} {
+		var sellerID, productID int
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO sellers (user_id, shop_name, is_active) VALUES ($1, 'Shop', true) RETURNING id`, userID).Scan(&sellerID))
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO products (seller_id, category_id, title, price, stock, status) VALUES ($1, $2, 'Mug', 10, 5, 'active') RETURNING id`,
+			sellerID, categoryID).Scan(&productID))
+		_, err := pool.Exec(ctx, `INSERT INTO order_items (order_id, product_id, quantity, price) VALUES ($1, $2, $3, 10)`,
+			orderID, productID, quantity)
+		require.NoError(t, err)
+		sellerIDs[userID] = sellerID
+	}
+
+	p, err := repository.NewPaymentRepository(pool, nil, nil).Create(ctx, &models.Payment{
+		OrderID: orderID, Method: models.PaymentMethodCard, Provider: "stripe", ProviderRef: "pi_cb", Amount: 30, Status: models.PaymentStatusPaid,
+	})
+	require.NoError(t, err)
+
+	chargebacks := repository.NewChargebackRepository(pool)
+	dueBy := time.Now().UTC().Add(7 * 24 * time.Hour).Truncate(time.Second)
+	cb, err := chargebacks.Open(ctx, &models.Chargeback{
+		PaymentID: p.ID, OrderID: orderID, Provider: "stripe", ProviderRef: "dp_1", Amount: 30, Reason: "fraudulent", EvidenceDueBy: &dueBy,
+	})
+	require.NoError(t, err)
+	require.Equal(t, models.ChargebackStatusOpen, cb.Status)
+	require.Len(t, cb.Holds, 2)
+	held := map[int]float64{}
+	for _, hold := range cb.Holds {
+		require.Equal(t, models.PayoutHoldHeld, hold.Status)
+		require.NotNil(t, hold.TicketID)
+		held[hold.SellerID] = hold.Amount
+	}
+	require.Equal(t, map[int]float64{sellerIDs[641]: 20, sellerIDs[642]: 10}, held)
+
+	again, err := chargebacks.Open(ctx, &models.Chargeback{
+		PaymentID: p.ID, OrderID: orderID, Provider: "stripe", ProviderRef: "dp_1", Amount: 30,
+	})
+	require.NoError(t, err)
+	require.Equal(t, cb.ID, again.ID, "a reported chargeback is opened once")
+
+	var tickets, notifications int
+	require.NoError(t, pool.QueryRow(ctx, `SELECT COUNT(*) FROM support_tickets t
+		JOIN ticket_messages m ON m.ticket_id = t.id AND m.author_role = 'system'
+		WHERE t.order_id = $1 AND t.status = 'pending' AND t.user_role = 'seller'`, orderID).Scan(&tickets))
+	require.Equal(t, 2, tickets)
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM notifications WHERE kind = 'chargeback' AND user_id IN (641, 642)`).Scan(&notifications))
+	require.Equal(t, 2, notifications)
+
+	var period time.Time
+	require.NoError(t, pool.QueryRow(ctx, `SELECT date_trunc('month', NOW())::date`).Scan(&period))
+	statements := repository.NewStatementRepository(pool)
+	_, err = statements.Generate(ctx, period)
+	require.NoError(t, err)
+	st, err := statements.GetSellerStatement(ctx, 641, period)
+	require.NoError(t, err)
+	require.Equal(t, 20.0, st.Chargebacks)
+	require.Equal(t, 0.0, st.Payout)
+
+	won, err := chargebacks.Resolve(ctx, cb.ID, models.ChargebackStatusWon)
+	require.NoError(t, err)
+	require.Equal(t, models.ChargebackStatusWon, won.Status)
+	require.Equal(t, models.PayoutHoldReleased, won.Holds[0].Status)
+
+	_, err = statements.Generate(ctx, period)
+	require.NoError(t, err)
+	st, err = statements.GetSellerStatement(ctx, 641, period)
+	require.NoError(t, err)
+	require.Equal(t, 0.0, st.Chargebacks, "a hold released in its own month cancels out")
+	require.Equal(t, 20.0, st.Payout)
+
+	var appErr *apperrors.AppError
+	_, err = chargebacks.Resolve(ctx, cb.ID, models.ChargebackStatusLost)
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeConflict, appErr.Code)
+}
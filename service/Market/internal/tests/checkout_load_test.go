@@ -0,0 +1,325 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/controllers"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/service"
+	"github.com/Zifeldev/marketback/service/Market/internal/shipping"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+var (
+	loadBuyers   = flag.Int("load.buyers", 200, "concurrent buyers in TestCheckoutLoad")
+	loadStock    = flag.Int("load.stock", 50, "units of the contended product in TestCheckoutLoad")
+	loadMaxConns = flag.Int("load.max-conns", 20, "database pool size in TestCheckoutLoad")
+	loadReport   = flag.String("load.report", "", "write the TestCheckoutLoad latency baseline to this JSON file")
+)
+
+// marketMigrations is db/market_migrations relative to this package.
+var marketMigrations = filepath.Join("..", "..", "..", "..", "db", "market_migrations")
+
+// latencySummary is one operation's latency distribution in milliseconds.
+type latencySummary struct {
+	Count int     `json:"count"`
+	P50   float64 `json:"p50_ms"`
+	P95   float64 `json:"p95_ms"`
+	P99   float64 `json:"p99_ms"`
+	Max   float64 `json:"max_ms"`
+}
+
+// checkoutLoadReport is the baseline written by -load.report.
+type checkoutLoadReport struct {
+	Buyers          int            `json:"buyers"`
+	Stock           int            `json:"stock"`
+	MaxConns        int            `json:"max_conns"`
+	GOMAXPROCS      int            `json:"gomaxprocs"`
+	Accepted        int            `json:"accepted_orders"`
+	Rejected        int            `json:"rejected_orders"`
+	CheckoutsPerSec float64        `json:"checkouts_per_second"`
+	AddToCart       latencySummary `json:"add_to_cart"`
+	CreateOrder     latencySummary `json:"create_order"`
+	CheckoutWindow  float64        `json:"checkout_window_ms"`
+}
+
+func summarize(samples []time.Duration) latencySummary {
+	if len(samples) == 0 {
+		return latencySummary{}
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	at := func(q float64) float64 {
+		i := int(q*float64(len(sorted))+0.5) - 1
+		if i < 0 {
+			i = 0
+		}
+		if i >= len(sorted) {
+			i = len(sorted) - 1
+		}
+		return float64(sorted[i].Microseconds()) / 1000
+	}
+	return latencySummary{Count: len(sorted), P50: at(0.50), P95: at(0.95), P99: at(0.99), Max: at(1)}
+}
+
+// TestCheckoutLoad has -load.buyers buyers race for -load.stock units of one
+// product through the real cart and order handlers, then checks that stock
+// was neither oversold nor lost. Every cart also holds a plentiful product,
+// added in alternating order, so checkouts lock rows in both orders.
+// Latency percentiles are logged and, with -load.report, written as JSON:
+//
+//	go test -tags integration -run CheckoutLoad -load.report=checkout.json ./internal/tests/
+func TestCheckoutLoad(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping load test in short mode")
+	}
+	buyers, stock := *loadBuyers, *loadStock
+	ctx := context.Background()
+
+	pool := startMarketDB(t, *loadMaxConns)
+
+	var sellerID, categoryID, hotID, plentyID int
+	err := pool.QueryRow(ctx, `INSERT INTO sellers (user_id, shop_name, is_active) VALUES (1, 'Load Shop', true) RETURNING id`).Scan(&sellerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.QueryRow(ctx, `INSERT INTO categories (name) VALUES ('Load') RETURNING id`).Scan(&categoryID); err != nil {
+		t.Fatal(err)
+	}
+	insertProduct := `INSERT INTO products (seller_id, category_id, title, price, stock, status)
+		VALUES ($1, $2, $3, $4, $5, 'active') RETURNING id`
+	if err := pool.QueryRow(ctx, insertProduct, sellerID, categoryID, "Limited drop", 99.0, stock).Scan(&hotID); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.QueryRow(ctx, insertProduct, sellerID, categoryID, "Socks", 5.0, buyers*10).Scan(&plentyID); err != nil {
+		t.Fatal(err)
+	}
+
+	router := checkoutRouter(pool)
+	call := func(userID int, path, body string) (int, time.Duration) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User-ID", strconv.Itoa(userID))
+		w := httptest.NewRecorder()
+		start := time.Now()
+		router.ServeHTTP(w, req)
+		return w.Code, time.Since(start)
+	}
+
+	var (
+		mu        sync.Mutex
+		addTimes  []time.Duration
+		buyTimes  []time.Duration
+		accepted  int
+		rejected  int
+		startAdds = make(chan struct{})
+		startBuys = make(chan struct{})
+		added     sync.WaitGroup
+		done      sync.WaitGroup
+	)
+	for i := 0; i < buyers; i++ {
+		added.Add(1)
+		done.Add(1)
+		go func(userID int, items []int) {
+			defer done.Done()
+
+			<-startAdds
+			for _, productID := range items {
+				code, elapsed := call(userID, "/api/user/cart/items", fmt.Sprintf(`{"product_id":%d,"quantity":1}`, productID))
+				if code != http.StatusCreated {
+					t.Errorf("buyer %d: add to cart returned %d", userID, code)
+				}
+				mu.Lock()
+				addTimes = append(addTimes, elapsed)
+				mu.Unlock()
+			}
+			added.Done()
+
+			<-startBuys
+			code, elapsed := call(userID, "/api/user/orders", `{"delivery_address":"1 Load Street","payment_method":"card"}`)
+			mu.Lock()
+			buyTimes = append(buyTimes, elapsed)
+			if code == http.StatusCreated {
+				accepted++
+			} else {
+				rejected++
+			}
+			mu.Unlock()
+		}(1000+i, alternate(i, hotID, plentyID))
+	}
+
+	close(startAdds)
+	added.Wait()
+	checkoutStart := time.Now()
+	close(startBuys)
+	done.Wait()
+	window := time.Since(checkoutStart)
+
+	var left, sold, orders int
+	if err := pool.QueryRow(ctx, `SELECT stock FROM products WHERE id = $1`, hotID).Scan(&left); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.QueryRow(ctx, `SELECT COALESCE(SUM(quantity), 0) FROM order_items WHERE product_id = $1`, hotID).Scan(&sold); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM orders`).Scan(&orders); err != nil {
+		t.Fatal(err)
+	}
+
+	if left < 0 || sold > stock {
+		t.Fatalf("oversold: %d of %d units sold, %d left", sold, stock, left)
+	}
+	if sold != stock-left {
+		t.Fatalf("stock and order items disagree: stock dropped by %d, order items hold %d", stock-left, sold)
+	}
+	if orders != accepted || sold != accepted {
+		t.Fatalf("%d orders accepted, but %d stored holding %d units", accepted, orders, sold)
+	}
+	if want := min(buyers, stock); accepted != want {
+		t.Fatalf("accepted %d orders, want %d (rejected %d)", accepted, want, rejected)
+	}
+
+	report := checkoutLoadReport{
+		Buyers:          buyers,
+		Stock:           stock,
+		MaxConns:        *loadMaxConns,
+		GOMAXPROCS:      runtime.GOMAXPROCS(0),
+		Accepted:        accepted,
+		Rejected:        rejected,
+		CheckoutsPerSec: float64(buyers) / window.Seconds(),
+		AddToCart:       summarize(addTimes),
+		CreateOrder:     summarize(buyTimes),
+		CheckoutWindow:  float64(window.Microseconds()) / 1000,
+	}
+	t.Logf("add to cart:  %+v", report.AddToCart)
+	t.Logf("create order: %+v", report.CreateOrder)
+	t.Logf("%d accepted, %d rejected, %.0f checkouts/s", accepted, rejected, report.CheckoutsPerSec)
+
+	if *loadReport != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(*loadReport, append(data, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// alternate returns both products, in a different order for odd buyers.
+func alternate(i, a, b int) []int {
+	if i%2 == 0 {
+		return []int{a, b}
+	}
+	return []int{b, a}
+}
+
+// checkoutRouter serves the cart and order handlers with the repositories
+// used in production. The buyer is taken from the X-User-ID header.
+func checkoutRouter(pool *pgxpool.Pool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	productRepo := repository.NewProductRepository(pool, nil)
+	categoryRepo := repository.NewCategoryRepository(pool, nil)
+	// Without reservations every buyer reaches checkout and races there.
+	cartRepo := repository.NewCartRepository(pool, 0)
+	orderRepo := repository.NewOrderRepository(pool, nil, nil, nil, nil)
+	marketService := service.NewMarketService(orderRepo, cartRepo, nil, shipping.Limits{}, nil, nil, nil, nil)
+	marketCtrl := controllers.NewMarketController(productRepo, categoryRepo, cartRepo, orderRepo, marketService)
+
+	user := router.Group("/api/user", func(c *gin.Context) {
+		userID, _ := strconv.Atoi(c.GetHeader("X-User-ID"))
+		c.Set("user_id", userID)
+		c.Next()
+	})
+	user.POST("/cart/items", marketCtrl.AddToCart)
+	user.POST("/orders", marketCtrl.CreateOrder)
+	return router
+}
+
+// startMarketDB starts Postgres with the real Market migrations applied
+// (without the dev seed) and returns a pool of at most maxConns connections.
+func startMarketDB(t *testing.T, maxConns int) *pgxpool.Pool {
+	t.Helper()
+	ctx := context.Background()
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "postgres:15-alpine",
+			ExposedPorts: []string{"5432/tcp"},
+			Env: map[string]string{
+				"POSTGRES_USER":     "testuser",
+				"POSTGRES_PASSWORD": "testpass",
+				"POSTGRES_DB":       "testdb",
+			},
+			WaitingFor: wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(60 * time.Second),
+		},
+		Started: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { container.Terminate(ctx) })
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := container.MappedPort(ctx, "5432")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := pgxpool.ParseConfig(fmt.Sprintf("postgres://testuser:testpass@%s:%s/testdb?sslmode=disable", host, port.Port()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.MaxConns = int32(maxConns)
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+
+	files, err := filepath.Glob(filepath.Join(marketMigrations, "*.up.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		if strings.Contains(filepath.Base(file), "dev_seed") {
+			continue
+		}
+		sql, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := pool.Exec(ctx, string(sql)); err != nil {
+			t.Fatalf("%s: %v", filepath.Base(file), err)
+		}
+	}
+	return pool
+}
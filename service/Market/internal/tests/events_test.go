@@ -0,0 +1,133 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/retention"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOrderEventsOutbox follows an order through checkout, payment and a
+// cancelled sibling order and checks the events recorded in the outbox and
+// relayed from it.
+func TestOrderEventsOutbox(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var sellerID, categoryID, productID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (70, 'Event Shop', true) RETURNING id`).Scan(&sellerID))
+	require.NoError(t, pool.QueryRow(ctx, `INSERT INTO categories (name) VALUES ('Events') RETURNING id`).Scan(&categoryID))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO products (seller_id, category_id, title, price, stock, status) VALUES ($1, $2, 'Lamp', 20, 8, 'active') RETURNING id`,
+		sellerID, categoryID).Scan(&productID))
+
+	outbox := repository.NewEventOutbox(5)
+	carts := repository.NewCartRepository(pool, 0)
+	orders := repository.NewOrderRepository(pool, nil, nil, nil, outbox)
+	payments := repository.NewPaymentRepository(pool, nil, outbox)
+	relay := repository.NewEventRepository(pool)
+
+	checkout := func(userID, quantity int) *models.OrderWithItems {
+		t.Helper()
+		_, err := carts.AddItem(ctx, userID, &models.AddToCartRequest{ProductID: productID, Quantity: quantity})
+		require.NoError(t, err)
+		cart, err := carts.GetUserCart(ctx, userID)
+		require.NoError(t, err)
+		order, err := orders.Create(ctx, userID, &models.CreateOrderRequest{
+			PaymentMethod: "card", DeliveryAddr: "3 Event Lane", Email: "buyer@example.com",
+		}, cart)
+		require.NoError(t, err)
+		return order
+	}
+
+	// Stock goes 8 -> 6 -> 3: only the second order crosses the threshold.
+	first := checkout(700, 2)
+	second := checkout(701, 3)
+
+	p, err := payments.Create(ctx, &models.Payment{
+		OrderID: first.ID, Provider: "stripe", ProviderRef: "pi_events", Amount: first.TotalAmount, Status: models.PaymentStatusPending,
+	})
+	require.NoError(t, err)
+	_, err = payments.Settle(ctx, p.ID, models.PaymentStatusPaid)
+	require.NoError(t, err)
+	_, err = orders.CancelByBuyer(ctx, second.ID, 701, time.Hour)
+	require.NoError(t, err)
+
+	// A failed publish keeps the batch for the next run.
+	n, err := relay.Relay(ctx, 10, func(ctx context.Context, events []*models.OutboxEvent) error {
+		return errors.New("broker down")
+	})
+	require.ErrorContains(t, err, "broker down")
+	require.Zero(t, n)
+	var attempts int
+	var lastError string
+	require.NoError(t, pool.QueryRow(ctx, `SELECT MIN(attempts), MIN(last_error) FROM event_outbox`).Scan(&attempts, &lastError))
+	require.Equal(t, 1, attempts)
+	require.Equal(t, "broker down", lastError)
+
+	var published []*models.OutboxEvent
+	n, err = relay.Relay(ctx, 10, func(ctx context.Context, events []*models.OutboxEvent) error {
+		published = append(published, events...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 7, n)
+
+	types := make([]string, len(published))
+	for i, ev := range published {
+		types[i] = ev.Type
+	}
+	require.Equal(t, []string{
+		models.EventOrderCreated,
+		models.EventProductStockLow,
+		models.EventOrderCreated,
+		models.EventOrderStatusChanged,
+		models.EventOrderPaid,
+		models.EventOrderStatusChanged,
+		models.EventOrderCancelled,
+	}, types)
+
+	var created models.OrderCreatedEvent
+	require.NoError(t, json.Unmarshal(published[0].Payload, &created))
+	require.Equal(t, first.ID, created.OrderID)
+	require.Equal(t, first.OrderNumber, created.OrderNumber)
+	require.Equal(t, "buyer@example.com", created.Email)
+	require.Len(t, created.Items, 1)
+	require.Equal(t, 2, created.Items[0].Quantity)
+
+	var low models.ProductStockLowEvent
+	require.NoError(t, json.Unmarshal(published[1].Payload, &low))
+	require.Equal(t, models.ProductStockLowEvent{ProductID: productID, SellerID: sellerID, Stock: 3, Threshold: 5}, low)
+
+	var cancelled models.OrderStatusEvent
+	require.NoError(t, json.Unmarshal(published[6].Payload, &cancelled))
+	require.Equal(t, second.ID, cancelled.OrderID)
+	require.Equal(t, models.StatusActorBuyer, cancelled.Actor)
+	require.Equal(t, models.OrderStatusPending, cancelled.FromStatus)
+	require.Equal(t, models.OrderStatusCancelled, cancelled.Status)
+
+	// Published events are marked, not relayed again, until retention
+	// purges them.
+	n, err = relay.Relay(ctx, 10, func(ctx context.Context, events []*models.OutboxEvent) error {
+		return errors.New("nothing to publish")
+	})
+	require.NoError(t, err)
+	require.Zero(t, n)
+	var marked int
+	require.NoError(t, pool.QueryRow(ctx, `SELECT COUNT(*) FROM event_outbox WHERE published_at IS NOT NULL`).Scan(&marked))
+	require.Equal(t, 7, marked)
+
+	purged, err := retention.NewRunner(pool, false, retention.PublishedEvents(time.Nanosecond)).RunOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), purged["published_events"])
+}
@@ -0,0 +1,84 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/catalog"
+	"github.com/Zifeldev/marketback/service/Market/internal/exports"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExportJobs runs a background product export through to its expiry
+// and checks that abandoned jobs are taken over.
+func TestExportJobs(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var sellerID, otherSellerID, categoryID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (50, 'Big Shop', true) RETURNING id`).Scan(&sellerID))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (51, 'Other Shop', true) RETURNING id`).Scan(&otherSellerID))
+	require.NoError(t, pool.QueryRow(ctx, `INSERT INTO categories (name) VALUES ('Bulk') RETURNING id`).Scan(&categoryID))
+	total := exports.PageSize + 3
+	_, err := pool.Exec(ctx, `INSERT INTO products (seller_id, category_id, title, price, stock)
+		SELECT $1, $2, 'Item ' || n, 5, 1 FROM generate_series(1, $3) n`, sellerID, categoryID, total)
+	require.NoError(t, err)
+
+	products := repository.NewProductRepository(pool, nil)
+	count, err := products.CountBySellerID(ctx, sellerID)
+	require.NoError(t, err)
+	require.Equal(t, total, count)
+
+	jobs := repository.NewExportJobRepository(pool)
+	job, err := jobs.Create(ctx, sellerID, catalog.FormatCSV, count)
+	require.NoError(t, err)
+	require.Equal(t, models.ExportQueued, job.Status)
+	_, err = jobs.GetByID(ctx, job.ID, otherSellerID)
+	require.Error(t, err, "other sellers' exports are not found")
+
+	runner, err := exports.NewRunner(jobs, products, t.TempDir(), time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, runner.RunOnce(ctx))
+
+	job, err = jobs.GetByID(ctx, job.ID, sellerID)
+	require.NoError(t, err)
+	require.Equal(t, models.ExportDone, job.Status)
+	require.Equal(t, total, job.ProcessedRows)
+	require.NotNil(t, job.ExpiresAt)
+	_, err = runner.Path(job.FileName)
+	require.NoError(t, err)
+
+	// A running job whose worker went away is picked up again.
+	stuck, err := jobs.Create(ctx, sellerID, catalog.FormatXLSX, count)
+	require.NoError(t, err)
+	claimed, err := jobs.Claim(ctx, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, stuck.ID, claimed.ID)
+	claimed, err = jobs.Claim(ctx, time.Minute)
+	require.NoError(t, err)
+	require.Nil(t, claimed, "running jobs are left alone while they progress")
+	_, err = pool.Exec(ctx, `UPDATE export_jobs SET updated_at = NOW() - INTERVAL '10 minutes' WHERE id = $1`, stuck.ID)
+	require.NoError(t, err)
+	require.NoError(t, runner.RunOnce(ctx))
+	stuck, err = jobs.GetByID(ctx, stuck.ID, 0)
+	require.NoError(t, err)
+	require.Equal(t, models.ExportDone, stuck.Status)
+
+	// Expired files are removed.
+	_, err = pool.Exec(ctx, `UPDATE export_jobs SET expires_at = NOW() - INTERVAL '1 second' WHERE id = $1`, job.ID)
+	require.NoError(t, err)
+	require.NoError(t, runner.RunOnce(ctx))
+	job, err = jobs.GetByID(ctx, job.ID, sellerID)
+	require.NoError(t, err)
+	require.Equal(t, models.ExportExpired, job.Status)
+	_, err = runner.Path(job.FileName)
+	require.ErrorIs(t, err, exports.ErrNotFound)
+}
@@ -4,15 +4,18 @@
 package integration
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
 	"github.com/Zifeldev/marketback/service/Market/internal/controllers"
 	"github.com/Zifeldev/marketback/service/Market/internal/models"
 	"github.com/Zifeldev/marketback/service/Market/internal/repository"
@@ -32,6 +35,11 @@ type IntegrationTestSuite struct {
 	router     *gin.Engine
 	sellerCtrl *controllers.SellerController
 	marketCtrl *controllers.MarketController
+	exportCtrl *controllers.ExportController
+
+	onboardingCtrl *controllers.OnboardingController
+
+	categoryRepo *repository.CategoryRepository
 }
 
 func TestIntegrationSuite(t *testing.T) {
@@ -81,14 +89,17 @@ func (s *IntegrationTestSuite) SetupSuite() {
 	s.runMigrations()
 
 	// Setup repositories and controllers
-	sellerRepo := repository.NewSellerRepository(pool)
-	productRepo := repository.NewProductRepository(pool)
-	cartRepo := repository.NewCartRepository(pool)
+	sellerRepo := repository.NewSellerRepository(pool, nil)
+	productRepo := repository.NewProductRepository(pool, nil)
+	cartRepo := repository.NewCartRepository(pool, 0)
 	categoryRepo := repository.NewCategoryRepository(pool, nil) // nil cache for tests
-	orderRepo := repository.NewOrderRepository(pool)
+	orderRepo := repository.NewOrderRepository(pool, nil, nil, nil, nil)
 
-	s.sellerCtrl = controllers.NewSellerController(sellerRepo, productRepo)
+	s.sellerCtrl = controllers.NewSellerController(sellerRepo, productRepo, nil, nil, nil, nil)
+	s.exportCtrl = controllers.NewExportController(sellerRepo, productRepo, nil, nil, nil, "", 0)
+	s.onboardingCtrl = controllers.NewOnboardingController(sellerRepo, "/seller")
 	s.marketCtrl = controllers.NewMarketController(productRepo, categoryRepo, cartRepo, orderRepo, nil)
+	s.categoryRepo = categoryRepo
 
 	// Setup router
 	gin.SetMode(gin.TestMode)
@@ -110,6 +121,18 @@ func (s *IntegrationTestSuite) SetupTest() {
 	s.cleanTables()
 }
 
+// productListingView mirrors db/market_migrations/0022 for GET /api/products.
+const productListingView = `CREATE MATERIALIZED VIEW IF NOT EXISTS product_listing AS
+	SELECT p.id, p.seller_id, p.category_id, p.title, COALESCE(p.description, '') AS description,
+		p.price::float8 AS price, p.stock, p.sizes, COALESCE(p.image_url, '') AS image_url,
+		COALESCE(p.status, 'pending') AS status, p.created_at, p.updated_at,
+		COALESCE(s.shop_name, '') AS seller_name, COALESCE(s.rating, 0)::float8 AS seller_rating,
+		COALESCE(c.name, '') AS category_name
+	FROM products p
+	LEFT JOIN sellers s ON p.seller_id = s.id
+	LEFT JOIN categories c ON p.category_id = c.id
+	WHERE p.category_id IS NOT NULL`
+
 func (s *IntegrationTestSuite) runMigrations() {
 	migrations := []string{
 		`CREATE TABLE IF NOT EXISTS sellers (
@@ -119,6 +142,20 @@ func (s *IntegrationTestSuite) runMigrations() {
 			description TEXT,
 			rating DECIMAL(3, 2) DEFAULT 0.00,
 			is_active BOOLEAN DEFAULT false,
+			api_plan VARCHAR(20) NOT NULL DEFAULT 'basic',
+			payout_details TEXT,
+			return_policy TEXT,
+			shipping_policy TEXT,
+			trusted BOOLEAN NOT NULL DEFAULT false,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS seller_payout_accounts (
+			seller_id INTEGER PRIMARY KEY REFERENCES sellers(id) ON DELETE CASCADE,
+			method VARCHAR(20) NOT NULL,
+			holder_name VARCHAR(200) NOT NULL,
+			account TEXT NOT NULL,
+			account_hint VARCHAR(4) NOT NULL,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)`,
@@ -126,6 +163,7 @@ func (s *IntegrationTestSuite) runMigrations() {
 			id SERIAL PRIMARY KEY,
 			name VARCHAR(255) NOT NULL,
 			description TEXT,
+			risk_level VARCHAR(10) NOT NULL DEFAULT 'standard',
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)`,
@@ -140,9 +178,32 @@ func (s *IntegrationTestSuite) runMigrations() {
 			image_url VARCHAR(500),
 			stock INTEGER DEFAULT 0,
 			status VARCHAR(50) DEFAULT 'pending',
+			low_stock_threshold INTEGER,
+			low_stock_alerted_at TIMESTAMP,
+			weight_grams INTEGER,
+			length_mm INTEGER,
+			width_mm INTEGER,
+			height_mm INTEGER,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)`,
+		`CREATE TABLE IF NOT EXISTS product_variants (
+			id SERIAL PRIMARY KEY,
+			product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+			sku VARCHAR(64) NOT NULL,
+			size VARCHAR(50) NOT NULL DEFAULT '',
+			color VARCHAR(50) NOT NULL DEFAULT '',
+			price_delta DECIMAL(10, 2) NOT NULL DEFAULT 0,
+			stock INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0),
+			weight_grams INTEGER,
+			length_mm INTEGER,
+			width_mm INTEGER,
+			height_mm INTEGER,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (product_id, sku),
+			UNIQUE (product_id, size, color)
+		)`,
 		`CREATE TABLE IF NOT EXISTS carts (
 			id SERIAL PRIMARY KEY,
 			user_id INTEGER NOT NULL,
@@ -156,10 +217,18 @@ func (s *IntegrationTestSuite) runMigrations() {
 			quantity INTEGER NOT NULL DEFAULT 1,
 			size VARCHAR(50),
 			color VARCHAR(50),
+			variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			UNIQUE(cart_id, product_id, size, color)
 		)`,
+		`CREATE TABLE IF NOT EXISTS stock_reservations (
+			cart_item_id INTEGER PRIMARY KEY REFERENCES cart_items(id) ON DELETE CASCADE,
+			product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+			variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE,
+			quantity INTEGER NOT NULL CHECK (quantity > 0),
+			expires_at TIMESTAMP NOT NULL
+		)`,
 		`CREATE TABLE IF NOT EXISTS orders (
 			id SERIAL PRIMARY KEY,
 			user_id INTEGER NOT NULL,
@@ -180,6 +249,8 @@ func (s *IntegrationTestSuite) runMigrations() {
 			price DECIMAL(10, 2) NOT NULL,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)`,
+		productListingView,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_product_listing_id ON product_listing(id)`,
 		// Insert test category
 		`INSERT INTO categories (id, name, description) VALUES (1, 'Test Category', 'Test description') ON CONFLICT DO NOTHING`,
 	}
@@ -207,8 +278,11 @@ func (s *IntegrationTestSuite) setupRoutes() {
 	seller.PUT("/profile", s.mockAuth(42), s.sellerCtrl.UpdateSellerProfile)
 	seller.POST("/products", s.mockAuth(42), s.sellerCtrl.CreateProduct)
 	seller.GET("/products", s.mockAuth(42), s.sellerCtrl.GetSellerProducts)
+	seller.POST("/products/import", s.mockAuth(42), s.sellerCtrl.ImportProducts)
+	seller.GET("/products/export", s.mockAuth(42), s.exportCtrl.ExportProducts)
 	seller.PUT("/products/:id", s.mockAuth(42), s.sellerCtrl.UpdateProduct)
 	seller.DELETE("/products/:id", s.mockAuth(42), s.sellerCtrl.DeleteProduct)
+	seller.GET("/onboarding", s.mockAuth(42), s.onboardingCtrl.GetOnboarding)
 
 	// Market routes
 	api.GET("/products", s.marketCtrl.GetProducts)
@@ -255,6 +329,63 @@ func (s *IntegrationTestSuite) TestSellerRegistrationFlow() {
 	s.Equal("Integration Test Shop", seller.ShopName)
 }
 
+func (s *IntegrationTestSuite) TestSellerOnboardingFlow() {
+	completed := func() map[string]bool {
+		req := httptest.NewRequest("GET", "/api/seller/onboarding", nil)
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		s.Require().Equal(http.StatusOK, w.Code)
+
+		var onboarding models.SellerOnboarding
+		s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &onboarding))
+		steps := make(map[string]bool)
+		for _, step := range onboarding.Steps {
+			steps[step.Key] = step.Completed
+		}
+		return steps
+	}
+
+	// Nothing is done before the shop is registered
+	for key, done := range completed() {
+		s.False(done, key)
+	}
+
+	body := `{"shop_name":"Onboarding Shop"}`
+	req := httptest.NewRequest("POST", "/api/seller/register", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Require().Equal(http.StatusCreated, w.Code)
+	s.False(completed()[models.OnboardingStepProfile]) // no description yet
+
+	body = `{"description":"Hand-made hats","return_policy":"30 days","shipping_policy":"Ships in 2 days","payout_details":"DE89370400440532013000"}`
+	req = httptest.NewRequest("PUT", "/api/seller/profile", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Require().Equal(http.StatusOK, w.Code)
+	s.NotContains(w.Body.String(), "DE89370400440532013000")
+
+	var seller models.Seller
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &seller))
+	s.True(seller.HasPayoutDetails)
+
+	productBody := `{"category_id":1,"title":"Hat","description":"A hat","price":20,"stock":5}`
+	req = httptest.NewRequest("POST", "/api/seller/products", strings.NewReader(productBody))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Require().Equal(http.StatusCreated, w.Code)
+
+	s.Equal(map[string]bool{
+		models.OnboardingStepProfile:  true,
+		models.OnboardingStepKYC:      false, // pending admin verification
+		models.OnboardingStepProduct:  true,
+		models.OnboardingStepPayout:   true,
+		models.OnboardingStepPolicies: true,
+	}, completed())
+}
+
 func (s *IntegrationTestSuite) TestProductCRUDFlow() {
 	// First register seller
 	body := `{"shop_name":"Product Test Shop","description":"Test"}`
@@ -307,6 +438,17 @@ func (s *IntegrationTestSuite) TestProductCRUDFlow() {
 	s.Equal("Updated Product", product.Title)
 	s.Equal(149.99, product.Price)
 
+	// Sellers cannot publish a product awaiting moderation themselves
+	req = httptest.NewRequest("PUT", fmt.Sprintf("/api/seller/products/%d", productID), strings.NewReader(`{"status":"active"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+
+	s.Require().Equal(http.StatusOK, w.Code)
+	err = json.Unmarshal(w.Body.Bytes(), &product)
+	s.Require().NoError(err)
+	s.Equal("pending", product.Status)
+
 	// Delete product
 	req = httptest.NewRequest("DELETE", fmt.Sprintf("/api/seller/products/%d", productID), nil)
 	w = httptest.NewRecorder()
@@ -325,6 +467,71 @@ func (s *IntegrationTestSuite) TestProductCRUDFlow() {
 	s.Len(products, 0)
 }
 
+func (s *IntegrationTestSuite) TestProductImportExportFlow() {
+	body := `{"shop_name":"Import Shop","description":"Test"}`
+	req := httptest.NewRequest("POST", "/api/seller/register", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Require().Equal(http.StatusCreated, w.Code)
+
+	upload := func(filename, content string) models.CatalogImport {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		fw, err := mw.CreateFormFile("file", filename)
+		s.Require().NoError(err)
+		_, err = fw.Write([]byte(content))
+		s.Require().NoError(err)
+		s.Require().NoError(mw.Close())
+
+		req := httptest.NewRequest("POST", "/api/seller/products/import", &buf)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		s.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+		var result models.CatalogImport
+		s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &result))
+		return result
+	}
+
+	result := upload("catalog.csv", "title,category_id,price,stock,sizes\n"+
+		"Scarf,1,12.50,3,S|M\n"+
+		"Hat,99,10,1,\n"+
+		"Belt,1,-1,2,\n")
+	s.Equal(1, result.Created)
+	s.Equal(2, result.Failed)
+	s.Equal([]string{"category with id 99 not found"}, result.Rows[1].Errors)
+	s.Equal(4, result.Rows[2].Line)
+	scarfID := result.Rows[0].ProductID
+
+	// Export, change the price and import the file back.
+	req = httptest.NewRequest("GET", "/api/seller/products/export", nil)
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Require().Equal(http.StatusOK, w.Code)
+	exported := w.Body.String()
+	s.Contains(exported, fmt.Sprintf("%d,Scarf,,1,12.50,3,S|M,,pending", scarfID))
+
+	result = upload("catalog.csv", strings.Replace(exported, "12.50", "14.00", 1))
+	s.Equal(1, result.Updated)
+	s.Equal(0, result.Failed)
+
+	result = upload("catalog.csv", "id,price\n999999,5\n")
+	s.Equal([]string{"id: product 999999 not found"}, result.Rows[0].Errors)
+
+	req = httptest.NewRequest("GET", "/api/seller/products/export?format=xlsx", nil)
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Require().Equal(http.StatusOK, w.Code)
+	result = upload("catalog.xlsx", w.Body.String())
+	s.Equal(1, result.Updated, "XLSX exports import back too")
+
+	product, err := repository.NewProductRepository(s.pool, nil).GetByID(s.ctx, scarfID)
+	s.Require().NoError(err)
+	s.Equal(14.0, product.Price)
+}
+
 func (s *IntegrationTestSuite) TestAddToCartFlow() {
 	// Setup: register seller and create product
 	sellerBody := `{"shop_name":"Cart Test Shop","description":"Test"}`
@@ -388,6 +595,10 @@ func (s *IntegrationTestSuite) TestGetProductsWithPagination() {
 		s.Require().Equal(http.StatusCreated, w.Code)
 	}
 
+	// Listing is served from the materialized view
+	_, err := s.pool.Exec(s.ctx, "REFRESH MATERIALIZED VIEW product_listing")
+	s.Require().NoError(err)
+
 	// Get products with pagination
 	req = httptest.NewRequest("GET", "/api/products?page=1&page_size=2", nil)
 	w = httptest.NewRecorder()
@@ -399,13 +610,44 @@ func (s *IntegrationTestSuite) TestGetProductsWithPagination() {
 		Data       []models.ProductWithDetails `json:"data"`
 		Pagination models.PaginationMeta       `json:"pagination"`
 	}
-	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	err = json.Unmarshal(w.Body.Bytes(), &resp)
 	s.Require().NoError(err)
 	s.Len(resp.Data, 2)
 	s.Equal(int64(5), resp.Pagination.TotalItems)
 	s.Equal(3, resp.Pagination.TotalPages)
 }
 
+func (s *IntegrationTestSuite) TestDeleteCategoryWithProducts() {
+	sellerBody := `{"shop_name":"Category Test Shop","description":"Test"}`
+	req := httptest.NewRequest("POST", "/api/seller/register", strings.NewReader(sellerBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Require().Equal(http.StatusCreated, w.Code)
+
+	var doomed, target int
+	s.Require().NoError(s.pool.QueryRow(s.ctx, `INSERT INTO categories (name) VALUES ('Doomed') RETURNING id`).Scan(&doomed))
+	s.Require().NoError(s.pool.QueryRow(s.ctx, `INSERT INTO categories (name) VALUES ('Target') RETURNING id`).Scan(&target))
+	defer s.pool.Exec(s.ctx, `DELETE FROM categories WHERE id IN ($1, $2)`, doomed, target)
+
+	productBody := fmt.Sprintf(`{"category_id":%d,"title":"Orphan candidate","price":10,"stock":1}`, doomed)
+	req = httptest.NewRequest("POST", "/api/seller/products", strings.NewReader(productBody))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Require().Equal(http.StatusCreated, w.Code)
+
+	err := s.categoryRepo.Delete(s.ctx, doomed, nil)
+	s.Require().Error(err)
+	s.Equal(http.StatusConflict, apperrors.GetHTTPStatus(err))
+
+	s.Require().NoError(s.categoryRepo.Delete(s.ctx, doomed, &target))
+
+	var categoryID int
+	s.Require().NoError(s.pool.QueryRow(s.ctx, `SELECT category_id FROM products WHERE title = 'Orphan candidate'`).Scan(&categoryID))
+	s.Equal(target, categoryID)
+}
+
 func (s *IntegrationTestSuite) TestSellerCannotUpdateOthersProduct() {
 	// This test verifies that a seller cannot update products belonging to another seller
 
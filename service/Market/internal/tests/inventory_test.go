@@ -0,0 +1,96 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInventoryImport checks that safe lines apply at once, risky lines
+// wait for confirmation and other sellers' products are skipped.
+func TestInventoryImport(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var categoryID int
+	require.NoError(t, pool.QueryRow(ctx, `INSERT INTO categories (name) VALUES ('Inventory') RETURNING id`).Scan(&categoryID))
+	seller := func(userID int) int {
+		var id int
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO sellers (user_id, shop_name, is_active) VALUES ($1, 'Shop', true) RETURNING id`, userID).Scan(&id))
+		return id
+	}
+	product := func(sellerID int) int {
+		var id int
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO products (seller_id, category_id, title, price, stock, status) VALUES ($1, $2, 'Mug', 100, 10, 'active') RETURNING id`,
+			sellerID, categoryID).Scan(&id))
+		return id
+	}
+	values := func(productID int) (stock int, price float64) {
+		require.NoError(t, pool.QueryRow(ctx,
+			`SELECT stock, price::float8 FROM products WHERE id = $1`, productID).Scan(&stock, &price))
+		return
+	}
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+
+	shop, other := seller(10), seller(20)
+	restocked, recounted, repriced := product(shop), product(shop), product(shop)
+	foreign := product(other)
+
+	repo := repository.NewInventoryRepository(pool, nil)
+	imp, err := repo.CreateImport(ctx, shop, &models.InventoryImportRequest{Rows: []models.InventoryImportLine{
+		{ProductID: restocked, Stock: intPtr(25)},
+		{ProductID: recounted, Stock: intPtr(2)},
+		{ProductID: repriced, Price: floatPtr(180)},
+		{ProductID: foreign, Stock: intPtr(0)},
+	}}, 20)
+	require.NoError(t, err)
+	require.Equal(t, models.InventoryImportAwaitingConfirmation, imp.Status)
+	require.Equal(t, 1, imp.Applied)
+	require.Equal(t, 2, imp.Held)
+	require.Equal(t, 1, imp.Skipped)
+	require.Equal(t, 3, imp.Mismatches)
+
+	stock, _ := values(restocked)
+	require.Equal(t, 25, stock)
+	stock, _ = values(recounted)
+	require.Equal(t, 10, stock, "held until confirmed")
+	stock, _ = values(foreign)
+	require.Equal(t, 10, stock, "other sellers' products are untouched")
+
+	var appErr *apperrors.AppError
+	_, err = repo.ConfirmImport(ctx, imp.ID, other)
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeNotFound, appErr.Code)
+
+	confirmed, err := repo.ConfirmImport(ctx, imp.ID, shop)
+	require.NoError(t, err)
+	require.Equal(t, models.InventoryImportConfirmed, confirmed.Status)
+	require.Equal(t, 3, confirmed.Applied)
+	require.Len(t, confirmed.Rows, 4)
+
+	stock, _ = values(recounted)
+	require.Equal(t, 2, stock)
+	_, price := values(repriced)
+	require.Equal(t, 180.0, price)
+
+	_, err = repo.DiscardImport(ctx, imp.ID, shop)
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeConflict, appErr.Code, "imports are decided once")
+
+	_, err = repo.CreateImport(ctx, shop, &models.InventoryImportRequest{Rows: []models.InventoryImportLine{
+		{ProductID: restocked, Stock: intPtr(1)},
+		{ProductID: restocked, Stock: intPtr(2)},
+	}}, 20)
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeValidationError, appErr.Code)
+}
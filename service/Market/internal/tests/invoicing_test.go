@@ -0,0 +1,60 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSellerInvoicingOnStatements checks that invoicing details are
+// replaced as a whole and show up on the seller's statements.
+func TestSellerInvoicingOnStatements(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var sellerID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (30, 'Shop', true) RETURNING id`).Scan(&sellerID))
+	_, err := pool.Exec(ctx, `INSERT INTO seller_statements
+		(seller_id, period, orders_count, items_sold, gross_sales, commission, refunds, payout, generated_at)
+		VALUES ($1, '2026-02-01', 1, 1, 10, 1, 0, 9, NOW())`, sellerID)
+	require.NoError(t, err)
+
+	sellers := repository.NewSellerRepository(pool, nil)
+	statements := repository.NewStatementRepository(pool)
+	period := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	var appErr *apperrors.AppError
+	_, err = sellers.GetInvoicing(ctx, sellerID)
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeNotFound, appErr.Code)
+
+	st, err := statements.GetSellerStatement(ctx, 30, period)
+	require.NoError(t, err)
+	require.Empty(t, st.TaxID)
+
+	req := &models.UpdateSellerInvoicingRequest{
+		LegalName: "Shop GmbH", Country: "DE", TaxID: "DE123456789",
+		AddressLine: "Hauptstrasse 1", City: "Berlin", PostalCode: "10115",
+	}
+	_, err = sellers.SetInvoicing(ctx, sellerID, req)
+	require.NoError(t, err)
+	req.AddressLine = "Hauptstrasse 2"
+	invoicing, err := sellers.SetInvoicing(ctx, sellerID, req)
+	require.NoError(t, err)
+	require.Equal(t, "Hauptstrasse 2", invoicing.AddressLine)
+
+	st, err = statements.GetSellerStatement(ctx, 30, period)
+	require.NoError(t, err)
+	require.Equal(t, "Shop GmbH", st.LegalName)
+	require.Equal(t, "DE123456789", st.TaxID)
+	require.Equal(t, "Hauptstrasse 2, 10115 Berlin, DE", st.InvoiceAddress)
+}
@@ -0,0 +1,89 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOrderCancelByBuyer checks that the shortest seller window applies and
+// that cancelling restocks open items and notifies the sellers.
+func TestOrderCancelByBuyer(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var categoryID int
+	require.NoError(t, pool.QueryRow(ctx, `INSERT INTO categories (name) VALUES ('Cancel') RETURNING id`).Scan(&categoryID))
+
+	product := func(userID int, window any) int {
+		var sellerID, productID int
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO sellers (user_id, shop_name, is_active, cancellation_window_minutes) VALUES ($1, 'Shop', true, $2) RETURNING id`,
+			userID, window).Scan(&sellerID))
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO products (seller_id, category_id, title, price, stock, status) VALUES ($1, $2, 'Mug', 10, 5, 'active') RETURNING id`,
+			sellerID, categoryID).Scan(&productID))
+		return productID
+	}
+	order := func(age string, productIDs ...int) int {
+		var id int
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO orders (user_id, total_amount, status, delivery_address, order_number, created_at)
+			VALUES (500, 10, 'pending', 'addr', 'MB-C-' || nextval('order_number_seq'), NOW() - $1::interval) RETURNING id`,
+			age).Scan(&id))
+		for _, productID := range productIDs {
+			_, err := pool.Exec(ctx,
+				`INSERT INTO order_items (order_id, product_id, quantity, price) VALUES ($1, $2, 2, 10)`, id, productID)
+			require.NoError(t, err)
+		}
+		return id
+	}
+
+	strict := product(10, 5)    // five minutes
+	lenient := product(20, nil) // the global window
+
+	repo := repository.NewOrderRepository(pool, nil, nil, nil, nil)
+	global := time.Hour
+
+	_, left, err := repo.CancellationDeadline(ctx, order("1 minute", lenient), global)
+	require.NoError(t, err)
+	require.InDelta(t, 59*time.Minute, left, float64(5*time.Second))
+
+	var appErr *apperrors.AppError
+	late := order("10 minutes", strict, lenient)
+	_, err = repo.CancelByBuyer(ctx, late, 500, global)
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeConflict, appErr.Code, "the strict seller's window has closed")
+
+	_, err = repo.CancelByBuyer(ctx, late, 501, global)
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeNotFound, appErr.Code, "other buyers' orders are hidden")
+
+	open := order("1 minute", strict, lenient)
+	cancelled, err := repo.CancelByBuyer(ctx, open, 500, global)
+	require.NoError(t, err)
+	require.Equal(t, "cancelled", cancelled.Status)
+	for _, item := range cancelled.Items {
+		require.Equal(t, "cancelled", item.FulfillmentStatus)
+	}
+
+	var stock int
+	require.NoError(t, pool.QueryRow(ctx, `SELECT stock FROM products WHERE id = $1`, strict).Scan(&stock))
+	require.Equal(t, 7, stock)
+
+	var notified int
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM notifications WHERE kind = 'order_cancelled' AND user_id IN (10, 20)`).Scan(&notified))
+	require.Equal(t, 2, notified)
+
+	_, err = repo.CancelByBuyer(ctx, open, 500, global)
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeConflict, appErr.Code, "orders are cancelled once")
+}
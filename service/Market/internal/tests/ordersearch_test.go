@@ -0,0 +1,92 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOrderSearch checks the buyer's order search and the admin filters.
+func TestOrderSearch(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var shopA, shopB, categoryID, lamp, rug int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (50, 'Shop A', true) RETURNING id`).Scan(&shopA))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (51, 'Shop B', true) RETURNING id`).Scan(&shopB))
+	require.NoError(t, pool.QueryRow(ctx, `INSERT INTO categories (name) VALUES ('Home') RETURNING id`).Scan(&categoryID))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO products (seller_id, category_id, title, price, stock, status) VALUES ($1, $2, 'Brass Desk Lamp', 40, 10, 'active') RETURNING id`,
+		shopA, categoryID).Scan(&lamp))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO products (seller_id, category_id, title, price, stock, status) VALUES ($1, $2, 'Wool Rug 100%', 150, 10, 'active') RETURNING id`,
+		shopB, categoryID).Scan(&rug))
+
+	carts := repository.NewCartRepository(pool, 0)
+	orders := repository.NewOrderRepository(pool, nil, nil, nil, nil)
+	checkout := func(userID int, email string, productID int) *models.OrderWithItems {
+		t.Helper()
+		_, err := carts.AddItem(ctx, userID, &models.AddToCartRequest{ProductID: productID, Quantity: 1})
+		require.NoError(t, err)
+		cart, err := carts.GetUserCart(ctx, userID)
+		require.NoError(t, err)
+		order, err := orders.Create(ctx, userID, &models.CreateOrderRequest{PaymentMethod: "card", DeliveryAddr: "3 Search St", Email: email}, cart)
+		require.NoError(t, err)
+		return order
+	}
+	lampOrder := checkout(60, "ann@example.com", lamp)
+	rugOrder := checkout(60, "ann@example.com", rug)
+	otherOrder := checkout(61, "bob@example.org", lamp)
+	_, err := pool.Exec(ctx, `UPDATE orders SET created_at = NOW() - INTERVAL '10 days' WHERE id = $1`, lampOrder.ID)
+	require.NoError(t, err)
+
+	pagination := &models.PaginationParams{Page: 1, PageSize: 20}
+	ids := func(list []*models.OrderWithItems) []int {
+		out := []int{}
+		for _, o := range list {
+			out = append(out, o.ID)
+		}
+		return out
+	}
+
+	userSearch := func(userID int, q string) []int {
+		t.Helper()
+		list, total, err := orders.GetUserOrders(ctx, userID, q, pagination)
+		require.NoError(t, err)
+		require.Len(t, list, int(total))
+		return ids(list)
+	}
+	require.Equal(t, []int{lampOrder.ID}, userSearch(60, "desk lamp"))
+	require.Equal(t, []int{rugOrder.ID}, userSearch(60, rugOrder.OrderNumber))
+	require.Equal(t, []int{rugOrder.ID}, userSearch(60, "100%"), "wildcards match literally")
+	require.Empty(t, userSearch(60, "%"))
+	require.Empty(t, userSearch(61, "rug"), "only the buyer's own orders")
+
+	adminSearch := func(filter models.OrderFilter) []int {
+		t.Helper()
+		list, total, err := orders.GetAll(ctx, pagination, &filter)
+		require.NoError(t, err)
+		require.Len(t, list, int(total))
+		return ids(list)
+	}
+	minAmount, maxAmount := 100.0, 200.0
+	from := time.Now().AddDate(0, 0, -3)
+	today := time.Now()
+	require.Equal(t, []int{otherOrder.ID, rugOrder.ID, lampOrder.ID}, adminSearch(models.OrderFilter{}))
+	require.Equal(t, []int{otherOrder.ID}, adminSearch(models.OrderFilter{Q: "EXAMPLE.ORG"}))
+	require.Equal(t, []int{otherOrder.ID, lampOrder.ID}, adminSearch(models.OrderFilter{Q: "lamp"}))
+	require.Equal(t, []int{rugOrder.ID, lampOrder.ID}, adminSearch(models.OrderFilter{Email: "ann@"}))
+	require.Equal(t, []int{rugOrder.ID}, adminSearch(models.OrderFilter{SellerID: &shopB}))
+	require.Equal(t, []int{rugOrder.ID}, adminSearch(models.OrderFilter{MinAmount: &minAmount, MaxAmount: &maxAmount}))
+	require.Equal(t, []int{otherOrder.ID}, adminSearch(models.OrderFilter{Product: "lamp", From: &from, To: &today}))
+	require.Equal(t, []int{lampOrder.ID}, adminSearch(models.OrderFilter{OrderNumber: lampOrder.OrderNumber, Status: "pending"}))
+}
@@ -0,0 +1,67 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/service"
+	"github.com/Zifeldev/marketback/service/Market/internal/shipping"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOrderStatusHistory checks that admins can only make the transitions
+// of the status machine and that every change, admin or courier, is
+// recorded.
+func TestOrderStatusHistory(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var orderID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO orders (user_id, total_amount, status, delivery_address, order_number)
+		VALUES (500, 10, 'pending', 'addr', 'MB-S-' || nextval('order_number_seq')) RETURNING id`).Scan(&orderID))
+
+	orders := repository.NewOrderRepository(pool, nil, nil, nil, nil)
+	deliveries := repository.NewDeliveryRepository(pool, nil, nil, nil)
+	svc := service.NewMarketService(orders, nil, nil, shipping.Limits{}, nil, nil, nil, nil)
+
+	var appErr *apperrors.AppError
+	_, err := svc.UpdateOrderStatus(ctx, orderID, models.OrderStatusShipped, 1)
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeConflict, appErr.Code, "pending orders are paid before shipping")
+
+	paid, err := svc.UpdateOrderStatus(ctx, orderID, models.OrderStatusPaid, 1)
+	require.NoError(t, err)
+	require.Equal(t, models.OrderStatusPaid, paid.Status)
+
+	_, err = svc.UpdateOrderStatus(ctx, orderID, models.OrderStatusCancelled, 1)
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeConflict, appErr.Code, "paid orders are refunded, not cancelled")
+
+	_, err = deliveries.Assign(ctx, orderID, 70, 2)
+	require.NoError(t, err)
+	_, err = deliveries.Assign(ctx, orderID, 71, 2) // reassigning keeps the status
+	require.NoError(t, err)
+	_, err = deliveries.MarkDelivered(ctx, orderID, 71, "https://example.com/proof.jpg")
+	require.NoError(t, err)
+
+	_, err = svc.UpdateOrderStatus(ctx, 999999, models.OrderStatusPaid, 1)
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeNotFound, appErr.Code)
+
+	history, err := orders.GetStatusHistory(ctx, orderID)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	require.Equal(t, []string{"pending", "paid", "shipped"}, []string{history[0].FromStatus, history[1].FromStatus, history[2].FromStatus})
+	require.Equal(t, []string{"paid", "shipped", "delivered"}, []string{history[0].ToStatus, history[1].ToStatus, history[2].ToStatus})
+	require.Equal(t, models.StatusActorAdmin, history[1].Actor)
+	require.Equal(t, 2, *history[1].ChangedBy)
+	require.Equal(t, models.StatusActorCourier, history[2].Actor)
+	require.Equal(t, 71, *history[2].ChangedBy)
+}
@@ -0,0 +1,246 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPaymentMovesOrder checks that settling a payment marks its order
+// paid and a full refund marks it refunded, both recorded with the
+// payment actor.
+func TestPaymentMovesOrder(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var orderID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO orders (user_id, total_amount, status, delivery_address, order_number)
+		VALUES (600, 30, 'pending', 'addr', 'MB-P-' || nextval('order_number_seq')) RETURNING id`).Scan(&orderID))
+
+	payments := repository.NewPaymentRepository(pool, nil, nil)
+	orders := repository.NewOrderRepository(pool, nil, nil, nil, nil)
+	status := func() string {
+		var s string
+		require.NoError(t, pool.QueryRow(ctx, `SELECT status FROM orders WHERE id = $1`, orderID).Scan(&s))
+		return s
+	}
+
+	p, err := payments.Create(ctx, &models.Payment{
+		OrderID: orderID, Provider: "stripe", ProviderRef: "pi_test", Amount: 30, Status: models.PaymentStatusPending,
+	})
+	require.NoError(t, err)
+	require.Equal(t, models.OrderStatusPending, status())
+
+	_, err = payments.Settle(ctx, p.ID, models.PaymentStatusPaid)
+	require.NoError(t, err)
+	require.Equal(t, models.OrderStatusPaid, status())
+
+	_, err = payments.AddRefund(ctx, p.ID, 10)
+	require.NoError(t, err)
+	require.Equal(t, models.OrderStatusPaid, status(), "partial refunds keep the order paid")
+
+	refunded, err := payments.AddRefund(ctx, p.ID, 20)
+	require.NoError(t, err)
+	require.Equal(t, models.PaymentStatusRefunded, refunded.Status)
+	require.Equal(t, models.OrderStatusRefunded, status())
+
+	history, err := orders.GetStatusHistory(ctx, orderID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, []string{"paid", "refunded"}, []string{history[0].ToStatus, history[1].ToStatus})
+	require.Equal(t, models.StatusActorPayment, history[1].Actor)
+	require.Nil(t, history[1].ChangedBy)
+}
+
+// TestPaymentLock checks that an order is paid by one attempt at a time:
+// concurrent claims give one winner, and the order is free again once
+// unlocked or once an abandoned claim expires.
+func TestPaymentLock(t *testing.T) {
+	pool := startMarketDB(t, 8)
+	ctx := context.Background()
+
+	var orderID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO orders (user_id, total_amount, status, delivery_address, order_number)
+		VALUES (605, 30, 'pending', 'addr', 'MB-L-' || nextval('order_number_seq')) RETURNING id`).Scan(&orderID))
+	payments := repository.NewPaymentRepository(pool, nil, nil)
+
+	errs := make(chan error, 8)
+	for range 8 {
+		go func() { errs <- payments.Lock(ctx, orderID, time.Minute) }()
+	}
+	var locked int
+	for range 8 {
+		if err := <-errs; err == nil {
+			locked++
+		} else {
+			require.Equal(t, http.StatusConflict, apperrors.GetAppError(err).HTTPStatus)
+		}
+	}
+	require.Equal(t, 1, locked)
+
+	require.NoError(t, payments.Unlock(ctx, orderID))
+	require.NoError(t, payments.Lock(ctx, orderID, time.Minute))
+
+	_, err := pool.Exec(ctx, `UPDATE orders SET payment_locked_until = NOW() - INTERVAL '1 second' WHERE id = $1`, orderID)
+	require.NoError(t, err)
+	require.NoError(t, payments.Lock(ctx, orderID, time.Minute), "an expired lock is taken over")
+}
+
+// TestSplitPayment pays an order with a gift card and store credit held
+// for a card charge: the declined charge gives the balances back, the paid
+// retry takes them, and a refund returns store credit.
+func TestSplitPayment(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var orderID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO orders (user_id, total_amount, status, delivery_address, order_number)
+		VALUES (610, 30, 'pending', 'addr', 'MB-S-' || nextval('order_number_seq')) RETURNING id`).Scan(&orderID))
+
+	payments := repository.NewPaymentRepository(pool, nil, nil)
+	giftCards := repository.NewGiftCardRepository(pool)
+	card, err := giftCards.Create(ctx, 1, &models.CreateGiftCardRequest{Amount: 10})
+	require.NoError(t, err)
+	require.Len(t, card.Code, 16)
+	_, err = giftCards.GrantStoreCredit(ctx, 610, 5)
+	require.NoError(t, err)
+
+	balances := func() (float64, float64) {
+		gift, err := giftCards.GetBalance(ctx, card.Code)
+		require.NoError(t, err)
+		credit, err := giftCards.GetStoreCredit(ctx, 610)
+		require.NoError(t, err)
+		return gift.Balance, credit.Balance
+	}
+	paymentStatus := func() string {
+		var s string
+		require.NoError(t, pool.QueryRow(ctx, `SELECT payment_status FROM orders WHERE id = $1`, orderID).Scan(&s))
+		return s
+	}
+	pay := func(ref, status string) []*models.Payment {
+		held, due, err := payments.Redeem(ctx, orderID, 610, 30, &models.PayOrderRequest{
+			GiftCardCode: strings.ToLower(card.Code), UseStoreCredit: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, held, 2)
+		require.Equal(t, 15.0, due)
+		require.Equal(t, models.PaymentStatusPending, paymentStatus())
+
+		_, err = payments.Create(ctx, &models.Payment{
+			OrderID: orderID, Method: models.PaymentMethodCard, Provider: "stripe", ProviderRef: ref, Amount: due, Status: status,
+		})
+		require.NoError(t, err)
+		return held
+	}
+
+	pay("pi_split_declined", models.PaymentStatusFailed)
+	require.Equal(t, models.PaymentStatusFailed, paymentStatus())
+	gift, credit := balances()
+	require.Equal(t, []float64{10, 5}, []float64{gift, credit}, "a declined card gives the balances back")
+
+	held := pay("pi_split_paid", models.PaymentStatusPaid)
+	require.Equal(t, models.PaymentStatusPaid, paymentStatus())
+	gift, credit = balances()
+	require.Equal(t, []float64{0, 0}, []float64{gift, credit})
+
+	all, err := payments.ListForOrder(ctx, orderID)
+	require.NoError(t, err)
+	require.Len(t, all, 6)
+	statuses := make([]string, len(all))
+	for i, p := range all {
+		statuses[i] = p.Status
+	}
+	require.Equal(t, []string{"voided", "voided", "failed", "paid", "paid", "paid"}, statuses)
+
+	_, err = payments.AddRefund(ctx, held[1].ID, 5)
+	require.NoError(t, err)
+	_, credit = balances()
+	require.Equal(t, 5.0, credit)
+	require.Equal(t, models.PaymentStatusPaid, paymentStatus(), "the rest of the order stays paid")
+
+	_, _, err = payments.Redeem(ctx, orderID, 610, 30, &models.PayOrderRequest{GiftCardCode: card.Code})
+	require.Error(t, err, "an empty gift card cannot be spent")
+}
+
+// TestSavedPaymentMethods keeps one customer per user and provider and
+// only lets users see and forget their own saved methods.
+func TestSavedPaymentMethods(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+	methods := repository.NewPaymentMethodRepository(pool)
+
+	customer, err := methods.GetCustomer(ctx, 620, "stripe")
+	require.NoError(t, err)
+	require.Empty(t, customer)
+
+	customer, err = methods.SaveCustomer(ctx, 620, "stripe", "cus_first")
+	require.NoError(t, err)
+	require.Equal(t, "cus_first", customer)
+	customer, err = methods.SaveCustomer(ctx, 620, "stripe", "cus_second")
+	require.NoError(t, err)
+	require.Equal(t, "cus_first", customer, "the first customer is kept")
+
+	saved, err := methods.Save(ctx, &models.SavedPaymentMethod{UserID: 620, Provider: "stripe", CustomerRef: customer, MethodRef: "pm_card"})
+	require.NoError(t, err)
+	again, err := methods.Save(ctx, &models.SavedPaymentMethod{UserID: 620, Provider: "stripe", CustomerRef: customer, MethodRef: "pm_card"})
+	require.NoError(t, err)
+	require.Equal(t, saved.ID, again.ID, "saving a method twice keeps one row")
+
+	list, err := methods.List(ctx, 620)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	_, err = methods.Get(ctx, saved.ID, 621)
+	require.Error(t, err)
+	require.Error(t, methods.Delete(ctx, saved.ID, 621), "other users cannot delete it")
+	require.NoError(t, methods.Delete(ctx, saved.ID, 620))
+
+	list, err = methods.List(ctx, 620)
+	require.NoError(t, err)
+	require.Empty(t, list)
+}
+
+// TestPaymentChallenge checks that a card payment waiting for 3-D Secure
+// keeps its action until the challenge settles it.
+func TestPaymentChallenge(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var orderID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO orders (user_id, total_amount, status, delivery_address, order_number)
+		VALUES (630, 30, 'pending', 'addr', 'MB-P-' || nextval('order_number_seq')) RETURNING id`).Scan(&orderID))
+
+	payments := repository.NewPaymentRepository(pool, nil, nil)
+	p, err := payments.Create(ctx, &models.Payment{
+		OrderID: orderID, Provider: "stripe", ProviderRef: "pi_3ds", Amount: 30, Status: models.PaymentStatusRequiresAction,
+		ActionType: models.PaymentActionRedirect, ActionURL: "https://hooks.stripe.test/3ds",
+	})
+	require.NoError(t, err)
+	require.Equal(t, models.PaymentStatusRequiresAction, p.Status)
+	require.Equal(t, "https://hooks.stripe.test/3ds", p.ActionURL)
+
+	settled, err := payments.Settle(ctx, p.ID, models.PaymentStatusPaid)
+	require.NoError(t, err)
+	require.NotNil(t, settled)
+	require.Equal(t, models.PaymentStatusPaid, settled.Status)
+	require.Empty(t, settled.ActionType)
+	require.Empty(t, settled.ActionURL)
+
+	var paymentStatus string
+	require.NoError(t, pool.QueryRow(ctx, `SELECT payment_status FROM orders WHERE id = $1`, orderID).Scan(&paymentStatus))
+	require.Equal(t, models.PaymentStatusPaid, paymentStatus)
+}
@@ -0,0 +1,102 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPayoutsPayClosedStatements checks that the unpaid statements of
+// closed months are batched into one payout per seller with an account,
+// and that a failed payout lets go of them for the next batch.
+func TestPayoutsPayClosedStatements(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	payouts := repository.NewPayoutRepository(pool, nil)
+	_, err := payouts.SetAccount(ctx, 650, &models.SetPayoutAccountRequest{
+		Method: models.PayoutMethodBankTransfer, HolderName: "Shop", Account: "DE89370400440532013000",
+	})
+	var appErr *apperrors.AppError
+	require.ErrorAs(t, err, &appErr, "a user without a seller profile has no payout account")
+	require.Equal(t, apperrors.CodeNotFound, appErr.Code)
+
+	sellerIDs := map[int]int{}
+	for _, userID := range []int{651, 652} {
+		var sellerID int
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO sellers (user_id, shop_name, is_active) VALUES ($1, 'Shop', true) RETURNING id`, userID).Scan(&sellerID))
+		sellerIDs[userID] = sellerID
+		for _, period := range []string{"2026-01-01", "2026-02-01"} {
+			_, err := pool.Exec(ctx, `INSERT INTO seller_statements (seller_id, period, payout) VALUES ($1, $2, 30)`, sellerID, period)
+			require.NoError(t, err)
+		}
+	}
+	account, err := payouts.SetAccount(ctx, 651, &models.SetPayoutAccountRequest{
+		Method: models.PayoutMethodBankTransfer, HolderName: "Shop", Account: "DE89370400440532013000",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "3000", account.AccountHint)
+
+	created, err := payouts.Batch(ctx, "sandbox", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), 10, models.SettlementDelays{})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), created, "the seller without an account is not paid")
+
+	now := time.Now()
+	due, err := payouts.Due(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.Equal(t, sellerIDs[651], due[0].SellerID)
+	require.Equal(t, 30.0, due[0].Amount, "only closed months are paid")
+	require.Equal(t, "DE89370400440532013000", due[0].Account)
+
+	created, err = payouts.Batch(ctx, "sandbox", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), 10, models.SettlementDelays{})
+	require.NoError(t, err)
+	require.Zero(t, created, "statements are paid once")
+
+	retryAt := now.Add(time.Hour)
+	require.NoError(t, payouts.MarkFailed(ctx, due[0].ID, "account closed", &retryAt))
+	due, err = payouts.Due(ctx, now, 10)
+	require.NoError(t, err)
+	require.Empty(t, due, "a refused payout waits for its retry")
+
+	require.NoError(t, payouts.MarkFailed(ctx, 0, "unknown", nil))
+	list, total, err := payouts.ListForSeller(ctx, 651, &models.PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.Equal(t, models.PayoutStatusPending, list[0].Status)
+	require.Equal(t, 1, list[0].Attempts)
+	require.Equal(t, "account closed", list[0].LastError)
+
+	require.NoError(t, payouts.MarkFailed(ctx, list[0].ID, "account closed", nil))
+	var notifications int
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM notifications WHERE kind = 'payout_failed' AND user_id = 651`).Scan(&notifications))
+	require.Equal(t, 1, notifications)
+
+	created, err = payouts.Batch(ctx, "sandbox", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 10, models.SettlementDelays{})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), created)
+	due, err = payouts.Due(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.Equal(t, 60.0, due[0].Amount, "a failed payout's statements are paid with the next")
+
+	require.NoError(t, payouts.MarkPaid(ctx, due[0].ID, "sandbox_po_1"))
+	list, _, err = payouts.ListForSeller(ctx, 651, &models.PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Equal(t, models.PayoutStatusPaid, list[0].Status)
+	require.Equal(t, "sandbox_po_1", *list[0].ProviderRef)
+
+	st, err := repository.NewStatementRepository(pool).GetSellerStatement(ctx, 651, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, due[0].ID, *st.PayoutID)
+}
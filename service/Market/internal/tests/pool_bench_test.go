@@ -0,0 +1,161 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// BenchmarkCheckoutQueryExecModes runs the statements of the checkout path
+// (cart read, stock lock, stock decrement, order insert) against every
+// DB_QUERY_EXEC_MODE so the effect of the statement cache can be compared:
+//
+//	go test -tags integration -run '^$' -bench CheckoutQueryExecModes ./internal/tests/
+func BenchmarkCheckoutQueryExecModes(b *testing.B) {
+	ctx := context.Background()
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "postgres:15-alpine",
+			ExposedPorts: []string{"5432/tcp"},
+			Env: map[string]string{
+				"POSTGRES_USER":     "testuser",
+				"POSTGRES_PASSWORD": "testpass",
+				"POSTGRES_DB":       "testdb",
+			},
+			WaitingFor: wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(60 * time.Second),
+		},
+		Started: true,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer container.Terminate(ctx)
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		b.Fatal(err)
+	}
+	port, err := container.MappedPort(ctx, "5432")
+	if err != nil {
+		b.Fatal(err)
+	}
+	connStr := fmt.Sprintf("postgres://testuser:testpass@%s:%s/testdb?sslmode=disable", host, port.Port())
+
+	setup, err := pgxpool.New(ctx, connStr)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE products (id SERIAL PRIMARY KEY, title TEXT NOT NULL, price DECIMAL(10, 2) NOT NULL, stock INTEGER NOT NULL)`,
+		`CREATE TABLE cart_items (id SERIAL PRIMARY KEY, user_id INTEGER NOT NULL, product_id INTEGER NOT NULL REFERENCES products(id), quantity INTEGER NOT NULL)`,
+		`CREATE TABLE orders (id SERIAL PRIMARY KEY, user_id INTEGER NOT NULL, total_amount DECIMAL(10, 2) NOT NULL)`,
+		`INSERT INTO products (title, price, stock) SELECT 'product ' || g, 10 + g, 1000000000 FROM generate_series(1, 100) g`,
+		`INSERT INTO cart_items (user_id, product_id, quantity) SELECT 1, g, 1 FROM generate_series(1, 5) g`,
+	} {
+		if _, err := setup.Exec(ctx, stmt); err != nil {
+			b.Fatal(err)
+		}
+	}
+	setup.Close()
+
+	modes := map[string]pgx.QueryExecMode{
+		"cache_statement": pgx.QueryExecModeCacheStatement,
+		"cache_describe":  pgx.QueryExecModeCacheDescribe,
+		"describe_exec":   pgx.QueryExecModeDescribeExec,
+		"exec":            pgx.QueryExecModeExec,
+		"simple_protocol": pgx.QueryExecModeSimpleProtocol,
+	}
+	for name, mode := range modes {
+		b.Run(name, func(b *testing.B) {
+			cfg, err := pgxpool.ParseConfig(connStr)
+			if err != nil {
+				b.Fatal(err)
+			}
+			cfg.MaxConns = 10
+			cfg.ConnConfig.DefaultQueryExecMode = mode
+
+			pool, err := pgxpool.NewWithConfig(ctx, cfg)
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer pool.Close()
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if err := checkout(ctx, pool); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+			b.StopTimer()
+
+			stat := pool.Stat()
+			b.ReportMetric(float64(stat.EmptyAcquireCount())/float64(b.N), "waits/op")
+		})
+	}
+}
+
+func checkout(ctx context.Context, pool *pgxpool.Pool) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT ci.product_id, ci.quantity, p.price
+		FROM cart_items ci
+		JOIN products p ON p.id = ci.product_id
+		WHERE ci.user_id = $1`, 1)
+	if err != nil {
+		return err
+	}
+	type line struct {
+		productID, quantity int
+		price               float64
+	}
+	var lines []line
+	for rows.Next() {
+		var l line
+		if err := rows.Scan(&l.productID, &l.quantity, &l.price); err != nil {
+			rows.Close()
+			return err
+		}
+		lines = append(lines, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var total float64
+	for _, l := range lines {
+		var stock int
+		if err := tx.QueryRow(ctx, `SELECT stock FROM products WHERE id = $1 FOR UPDATE`, l.productID).Scan(&stock); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `UPDATE products SET stock = stock - $1 WHERE id = $2`, l.quantity, l.productID); err != nil {
+			return err
+		}
+		total += l.price * float64(l.quantity)
+	}
+
+	if _, err := tx.Exec(ctx, `INSERT INTO orders (user_id, total_amount) VALUES ($1, $2)`, 1, total); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,99 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSellerProbation checks that listings show the products of a seller on
+// probation to the visitor buckets of its traffic share only, and to
+// everyone once it has delivered enough orders.
+func TestSellerProbation(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var categoryID int
+	require.NoError(t, pool.QueryRow(ctx, `INSERT INTO categories (name) VALUES ('Probation') RETURNING id`).Scan(&categoryID))
+
+	sellers := repository.NewSellerRepository(pool, nil)
+	veteran, err := sellers.Create(ctx, 80, &models.CreateSellerRequest{ShopName: "Old Shop"}, models.SetSellerProbationRequest{})
+	require.NoError(t, err)
+	require.False(t, veteran.OnProbation)
+	require.Nil(t, veteran.ProbationOrders)
+	newcomer, err := sellers.Create(ctx, 81, &models.CreateSellerRequest{ShopName: "New Shop"},
+		models.SetSellerProbationRequest{Orders: 2, TrafficPercent: 20})
+	require.NoError(t, err)
+	require.True(t, newcomer.OnProbation)
+	require.Equal(t, 2, *newcomer.ProbationOrders)
+	require.Equal(t, 20, *newcomer.ProbationTrafficPercent)
+
+	product := func(sellerID int) int {
+		var id int
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO products (seller_id, category_id, title, price, stock, status) VALUES ($1, $2, 'Mug', 10, 5, 'active') RETURNING id`,
+			sellerID, categoryID).Scan(&id))
+		return id
+	}
+	oldMug, newMug := product(veteran.ID), product(newcomer.ID)
+	deliver := func(productID int) {
+		var orderID int
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO orders (user_id, total_amount, status, delivery_address, order_number)
+			VALUES (900, 10, 'confirmed', 'addr', 'MB-P-' || nextval('order_number_seq')) RETURNING id`).Scan(&orderID))
+		_, err := pool.Exec(ctx,
+			`INSERT INTO order_items (order_id, product_id, quantity, price, fulfillment_status) VALUES ($1, $2, 1, 10, 'delivered')`,
+			orderID, productID)
+		require.NoError(t, err)
+	}
+
+	products := repository.NewProductRepository(pool, nil)
+	listed := func(bucket *int) []int {
+		require.NoError(t, products.RefreshListing(ctx))
+		page, _, err := products.GetAll(ctx, &categoryID, nil, "active", bucket, &models.PaginationParams{Page: 1})
+		require.NoError(t, err)
+		ids := []int{}
+		for _, p := range page {
+			ids = append(ids, p.ID)
+		}
+		return ids
+	}
+	bucket := func(b int) *int { return &b }
+
+	require.ElementsMatch(t, []int{oldMug, newMug}, listed(bucket(0)))
+	require.ElementsMatch(t, []int{oldMug, newMug}, listed(bucket(1)))
+	require.Equal(t, []int{oldMug}, listed(bucket(2)))
+	require.ElementsMatch(t, []int{oldMug, newMug}, listed(nil), "storefronts are not bucketed")
+
+	feed, err := products.GetActiveListing(ctx)
+	require.NoError(t, err)
+	require.Len(t, feed, 1)
+	require.Equal(t, oldMug, feed[0].ID)
+
+	deliver(newMug)
+	require.Equal(t, []int{oldMug}, listed(bucket(9)))
+	deliver(newMug)
+	require.ElementsMatch(t, []int{oldMug, newMug}, listed(bucket(9)), "two delivered orders end probation")
+
+	seller, err := sellers.GetByID(ctx, newcomer.ID)
+	require.NoError(t, err)
+	require.False(t, seller.OnProbation)
+
+	// Admins can put sellers back on probation, counting past orders.
+	seller, err = sellers.SetProbation(ctx, newcomer.ID, &models.SetSellerProbationRequest{Orders: 3, TrafficPercent: 0})
+	require.NoError(t, err)
+	require.True(t, seller.OnProbation)
+	require.Equal(t, []int{oldMug}, listed(bucket(0)))
+
+	seller, err = sellers.SetProbation(ctx, newcomer.ID, &models.SetSellerProbationRequest{})
+	require.NoError(t, err)
+	require.False(t, seller.OnProbation)
+	require.Nil(t, seller.ProbationOrders)
+	require.ElementsMatch(t, []int{oldMug, newMug}, listed(bucket(0)))
+}
@@ -0,0 +1,108 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPromoCodeFunding checks that promotion codes discount the lines they
+// apply to and that only seller-funded discounts reduce seller payouts.
+func TestPromoCodeFunding(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var shopA, shopB, categoryID, productA, productB int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (50, 'Shop A', true) RETURNING id`).Scan(&shopA))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (51, 'Shop B', true) RETURNING id`).Scan(&shopB))
+	require.NoError(t, pool.QueryRow(ctx, `INSERT INTO categories (name) VALUES ('Caps') RETURNING id`).Scan(&categoryID))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO products (seller_id, category_id, title, price, stock, status) VALUES ($1, $2, 'Cap', 100, 10, 'active') RETURNING id`,
+		shopA, categoryID).Scan(&productA))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO products (seller_id, category_id, title, price, stock, status) VALUES ($1, $2, 'Beanie', 50, 10, 'active') RETURNING id`,
+		shopB, categoryID).Scan(&productB))
+	_, err := pool.Exec(ctx, `INSERT INTO commission_rates (rate, effective_from, created_by) VALUES (0.1, NOW() - INTERVAL '1 day', 1)`)
+	require.NoError(t, err)
+
+	promos := repository.NewPromoRepository(pool)
+	_, err = promos.Create(ctx, 1, &models.CreatePromoCodeRequest{Code: "market10", PercentOff: 10, FundedBy: models.PromoFundedByMarketplace})
+	require.NoError(t, err)
+	_, err = promos.Create(ctx, 50, &models.CreatePromoCodeRequest{Code: "SHOPA20", PercentOff: 20, FundedBy: models.PromoFundedBySeller, SellerID: &shopA})
+	require.NoError(t, err)
+	past := time.Now().Add(-time.Hour)
+	_, err = promos.Create(ctx, 1, &models.CreatePromoCodeRequest{Code: "OLD", PercentOff: 50, FundedBy: models.PromoFundedByMarketplace, ExpiresAt: &past})
+	require.NoError(t, err)
+	_, err = promos.Create(ctx, 1, &models.CreatePromoCodeRequest{Code: "Market10", PercentOff: 5, FundedBy: models.PromoFundedByMarketplace})
+	var appErr *apperrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeConflict, appErr.Code, "codes are case-insensitive")
+
+	carts := repository.NewCartRepository(pool, 0)
+	orders := repository.NewOrderRepository(pool, nil, nil, nil, nil)
+	checkout := func(userID int, code string, productIDs ...int) (*models.OrderWithItems, error) {
+		t.Helper()
+		for _, id := range productIDs {
+			_, err := carts.AddItem(ctx, userID, &models.AddToCartRequest{ProductID: id, Quantity: 1})
+			require.NoError(t, err)
+		}
+		cart, err := carts.GetUserCart(ctx, userID)
+		require.NoError(t, err)
+		return orders.Create(ctx, userID, &models.CreateOrderRequest{PaymentMethod: "card", DeliveryAddr: "2 Cap Road", PromoCode: code}, cart)
+	}
+	requireInvalidCode := func(err error) {
+		t.Helper()
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, apperrors.CodeValidationError, appErr.Code)
+	}
+
+	// Shop A funds 20% off its own cap only.
+	order, err := checkout(60, "shopa20", productA, productB)
+	require.NoError(t, err)
+	require.Equal(t, "SHOPA20", order.PromoCode)
+	require.Equal(t, 130.0, order.TotalAmount)
+	require.Equal(t, 20.0, order.DiscountAmount)
+
+	// The marketplace funds 10% off; the seller's line is untouched.
+	order, err = checkout(61, "MARKET10", productA)
+	require.NoError(t, err)
+	require.Equal(t, 90.0, order.TotalAmount)
+	require.Equal(t, models.PromoFundedByMarketplace, order.Items[0].DiscountFundedBy)
+
+	_, err = checkout(62, "SHOPA20", productB)
+	requireInvalidCode(err)
+	_, err = checkout(62, "OLD", productB)
+	requireInvalidCode(err)
+
+	var commissionA float64
+	require.NoError(t, pool.QueryRow(ctx, `SELECT SUM(commission_amount)::float8 FROM order_items WHERE product_id = $1`, productA).Scan(&commissionA))
+	require.Equal(t, 18.0, commissionA, "commission on 80 after the seller's discount and on 100 under the marketplace's")
+
+	now := time.Now().UTC()
+	period := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	statements := repository.NewStatementRepository(pool)
+	_, err = statements.Generate(ctx, period)
+	require.NoError(t, err)
+
+	st, err := statements.GetSellerStatement(ctx, 50, period)
+	require.NoError(t, err)
+	require.Equal(t, 200.0, st.GrossSales)
+	require.Equal(t, 20.0, st.Discounts, "only the discount Shop A funded")
+	require.Equal(t, 18.0, st.Commission)
+	require.Equal(t, 162.0, st.Payout)
+
+	st, err = statements.GetSellerStatement(ctx, 51, period)
+	require.NoError(t, err)
+	require.Equal(t, 0.0, st.Discounts)
+	require.Equal(t, 45.0, st.Payout)
+}
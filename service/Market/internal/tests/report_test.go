@@ -0,0 +1,67 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAccounts records the users suspended in Auth
+type fakeAccounts struct {
+	suspended []int64
+	err       error
+}
+
+func (f *fakeAccounts) SuspendUser(ctx context.Context, userID int64) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.suspended = append(f.suspended, userID)
+	return nil
+}
+
+// TestBanUser checks that banning a reported seller suspends the account in
+// Auth along with the shop, and that nothing is banned when Auth fails.
+func TestBanUser(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var sellerID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (530, 'Fraud', true) RETURNING id`).Scan(&sellerID))
+	isActive := func() bool {
+		var active bool
+		require.NoError(t, pool.QueryRow(ctx, `SELECT is_active FROM sellers WHERE id = $1`, sellerID).Scan(&active))
+		return active
+	}
+
+	report, err := repository.NewReportRepository(pool, nil).Create(ctx, 531,
+		&models.CreateReportRequest{TargetType: models.ReportTargetSeller, TargetID: sellerID, Reason: "fraud"})
+	require.NoError(t, err)
+	ban := &models.ResolveReportRequest{Action: models.ReportActionBanUser}
+
+	// Without Auth the account could not be banned
+	var appErr *apperrors.AppError
+	_, err = repository.NewReportRepository(pool, nil).Resolve(ctx, report.ID, 1, ban)
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeValidationError, appErr.Code)
+
+	_, err = repository.NewReportRepository(pool, &fakeAccounts{err: errors.New("auth unavailable")}).Resolve(ctx, report.ID, 1, ban)
+	require.Error(t, err)
+	require.True(t, isActive())
+
+	accounts := &fakeAccounts{}
+	resolved, err := repository.NewReportRepository(pool, accounts).Resolve(ctx, report.ID, 1, ban)
+	require.NoError(t, err)
+	require.Equal(t, models.ReportStatusResolved, resolved.Status)
+	require.Equal(t, []int64{530}, accounts.suspended)
+	require.False(t, isActive())
+}
@@ -0,0 +1,83 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStockReservations checks that cart items hold stock against other
+// carts until they expire and that checkout turns them into deductions.
+func TestStockReservations(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var sellerID, categoryID, productID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (40, 'Shop', true) RETURNING id`).Scan(&sellerID))
+	require.NoError(t, pool.QueryRow(ctx, `INSERT INTO categories (name) VALUES ('Drops') RETURNING id`).Scan(&categoryID))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO products (seller_id, category_id, title, price, stock, status) VALUES ($1, $2, 'Sneaker', 120, 3, 'active') RETURNING id`,
+		sellerID, categoryID).Scan(&productID))
+
+	carts := repository.NewCartRepository(pool, time.Hour)
+	orders := repository.NewOrderRepository(pool, nil, nil, nil, nil)
+	const alice, bob = 41, 42
+
+	var appErr *apperrors.AppError
+	requireNoStock := func(err error) {
+		t.Helper()
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, apperrors.CodeInsufficientStock, appErr.Code)
+	}
+
+	aliceItem, err := carts.AddItem(ctx, alice, &models.AddToCartRequest{ProductID: productID, Quantity: 2})
+	require.NoError(t, err)
+	require.NotNil(t, aliceItem.ReservedUntil)
+
+	_, err = carts.AddItem(ctx, bob, &models.AddToCartRequest{ProductID: productID, Quantity: 2})
+	requireNoStock(err)
+	bobItem, err := carts.AddItem(ctx, bob, &models.AddToCartRequest{ProductID: productID, Quantity: 1})
+	require.NoError(t, err)
+
+	_, err = carts.UpdateItem(ctx, aliceItem.ID, alice, &models.UpdateCartItemRequest{Quantity: 3})
+	requireNoStock(err)
+
+	// Alice's reservation runs out; Bob can take her units.
+	_, err = pool.Exec(ctx, `UPDATE stock_reservations SET expires_at = NOW() - INTERVAL '1 second' WHERE cart_item_id = $1`, aliceItem.ID)
+	require.NoError(t, err)
+	_, err = carts.UpdateItem(ctx, bobItem.ID, bob, &models.UpdateCartItemRequest{Quantity: 3})
+	require.NoError(t, err)
+
+	released, err := carts.ReleaseExpired(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), released)
+
+	aliceCart, err := carts.GetUserCart(ctx, alice)
+	require.NoError(t, err)
+	require.Len(t, aliceCart, 1)
+	require.Nil(t, aliceCart[0].ReservedUntil, "the item stays in the cart without a reservation")
+
+	order := &models.CreateOrderRequest{PaymentMethod: "card", DeliveryAddr: "1 Drop Street"}
+	_, err = orders.Create(ctx, alice, order, aliceCart)
+	requireNoStock(err)
+
+	bobCart, err := carts.GetUserCart(ctx, bob)
+	require.NoError(t, err)
+	_, err = orders.Create(ctx, bob, order, bobCart)
+	require.NoError(t, err)
+
+	var stock, reservations int
+	require.NoError(t, pool.QueryRow(ctx, `SELECT stock FROM products WHERE id = $1`, productID).Scan(&stock))
+	require.NoError(t, pool.QueryRow(ctx, `SELECT COUNT(*) FROM stock_reservations`).Scan(&reservations))
+	require.Equal(t, 0, stock)
+	require.Equal(t, 0, reservations, "checkout converts the reservation into the deduction")
+}
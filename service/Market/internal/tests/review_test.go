@@ -0,0 +1,206 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReviews checks that only buyers who received a product can review it
+// and that product and seller ratings follow the reviews.
+func TestReviews(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var categoryID, sellerID int
+	require.NoError(t, pool.QueryRow(ctx, `INSERT INTO categories (name) VALUES ('Reviews') RETURNING id`).Scan(&categoryID))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (10, 'Shop', true) RETURNING id`).Scan(&sellerID))
+	product := func() int {
+		var id int
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO products (seller_id, category_id, title, price, stock, status) VALUES ($1, $2, 'Mug', 10, 5, 'active') RETURNING id`,
+			sellerID, categoryID).Scan(&id))
+		return id
+	}
+	buy := func(userID, productID int, fulfillment string) {
+		var orderID int
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO orders (user_id, total_amount, status, delivery_address, order_number)
+			VALUES ($1, 10, 'confirmed', 'addr', 'MB-R-' || nextval('order_number_seq')) RETURNING id`, userID).Scan(&orderID))
+		_, err := pool.Exec(ctx,
+			`INSERT INTO order_items (order_id, product_id, quantity, price, fulfillment_status) VALUES ($1, $2, 1, 10, $3)`,
+			orderID, productID, fulfillment)
+		require.NoError(t, err)
+	}
+
+	mug, cup := product(), product()
+	buy(500, mug, "delivered")
+	buy(501, mug, "delivered")
+	buy(501, cup, "delivered")
+	buy(502, mug, "shipped") // not received yet
+
+	repo := repository.NewReviewRepository(pool, nil)
+	review := func(userID, productID, rating int) (*models.Review, error) {
+		return repo.Create(ctx, productID, userID, &models.CreateReviewRequest{Rating: rating}, nil)
+	}
+
+	_, err := review(500, mug, 5)
+	require.NoError(t, err)
+	_, err = review(501, mug, 2)
+	require.NoError(t, err)
+	cupReview, err := review(501, cup, 4)
+	require.NoError(t, err)
+
+	var appErr *apperrors.AppError
+	_, err = review(502, mug, 1)
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeForbidden, appErr.Code)
+	_, err = review(500, mug, 1)
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeConflict, appErr.Code)
+
+	ratings := func() (mugRating float64, mugReviews int, sellerRating float64, sellerReviews int) {
+		require.NoError(t, pool.QueryRow(ctx,
+			`SELECT p.rating::float8, p.review_count, s.rating::float8, s.review_count
+			FROM products p JOIN sellers s ON s.id = p.seller_id WHERE p.id = $1`, mug).
+			Scan(&mugRating, &mugReviews, &sellerRating, &sellerReviews))
+		return
+	}
+	mugRating, mugReviews, sellerRating, sellerReviews := ratings()
+	require.Equal(t, 3.5, mugRating)
+	require.Equal(t, 2, mugReviews)
+	require.Equal(t, 3.67, sellerRating)
+	require.Equal(t, 3, sellerReviews)
+
+	err = repo.Delete(ctx, cupReview.ID, 500)
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeNotFound, appErr.Code, "only the author deletes a review")
+	require.NoError(t, repo.Delete(ctx, cupReview.ID, 501))
+
+	_, _, sellerRating, sellerReviews = ratings()
+	require.Equal(t, 3.5, sellerRating)
+	require.Equal(t, 2, sellerReviews)
+
+	reviews, total, err := repo.GetByProduct(ctx, mug, &models.PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), total)
+	require.Len(t, reviews, 2)
+}
+
+// TestHeldReview checks that a review moderation held stays out of the
+// listing and the ratings until its report is resolved.
+func TestHeldReview(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var categoryID, sellerID, productID, orderID int
+	require.NoError(t, pool.QueryRow(ctx, `INSERT INTO categories (name) VALUES ('Held') RETURNING id`).Scan(&categoryID))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (11, 'Held shop', true) RETURNING id`).Scan(&sellerID))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO products (seller_id, category_id, title, price, stock, status) VALUES ($1, $2, 'Lamp', 10, 5, 'active') RETURNING id`,
+		sellerID, categoryID).Scan(&productID))
+	for _, userID := range []int{520, 521} {
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO orders (user_id, total_amount, status, delivery_address, order_number)
+			VALUES ($1, 10, 'delivered', 'addr', 'MB-H-' || nextval('order_number_seq')) RETURNING id`, userID).Scan(&orderID))
+		_, err := pool.Exec(ctx, `INSERT INTO order_items (order_id, product_id, quantity, price) VALUES ($1, $2, 1, 10)`, orderID, productID)
+		require.NoError(t, err)
+	}
+
+	reviews := repository.NewReviewRepository(pool, nil)
+	reports := repository.NewReportRepository(pool, nil)
+	rating := func() (float64, int) {
+		var r float64
+		var n int
+		require.NoError(t, pool.QueryRow(ctx, `SELECT rating::float8, review_count FROM products WHERE id = $1`, productID).Scan(&r, &n))
+		return r, n
+	}
+	autoReport := func(reviewID int) int {
+		var id int
+		require.NoError(t, pool.QueryRow(ctx,
+			`SELECT id FROM reports WHERE source = 'auto' AND target_type = 'review' AND target_id = $1 AND status = 'open'`, reviewID).Scan(&id))
+		return id
+	}
+
+	kept, err := reviews.Create(ctx, productID, 520, &models.CreateReviewRequest{Rating: 1, Comment: "Total scam"}, []string{"banned_words: scam"})
+	require.NoError(t, err)
+	require.Equal(t, models.ReviewStatusPending, kept.Status)
+	hidden, err := reviews.Create(ctx, productID, 521, &models.CreateReviewRequest{Rating: 2, Comment: "Scam"}, []string{"banned_words: scam"})
+	require.NoError(t, err)
+
+	listed, total, err := reviews.GetByProduct(ctx, productID, &models.PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Zero(t, total)
+	require.Empty(t, listed)
+	r, n := rating()
+	require.Zero(t, r)
+	require.Zero(t, n)
+
+	var appErr *apperrors.AppError
+	_, err = reports.Resolve(ctx, autoReport(kept.ID), 1, &models.ResolveReportRequest{Action: models.ReportActionBanUser})
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeValidationError, appErr.Code)
+
+	_, err = reports.Resolve(ctx, autoReport(kept.ID), 1, &models.ResolveReportRequest{Action: models.ReportActionDismiss})
+	require.NoError(t, err)
+	_, err = reports.Resolve(ctx, autoReport(hidden.ID), 1, &models.ResolveReportRequest{Action: models.ReportActionHideContent})
+	require.NoError(t, err)
+
+	listed, total, err = reviews.GetByProduct(ctx, productID, &models.PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.Equal(t, kept.ID, listed[0].ID)
+	r, n = rating()
+	require.Equal(t, 1.0, r)
+	require.Equal(t, 1, n)
+
+	var notified int
+	require.NoError(t, pool.QueryRow(ctx, `SELECT COUNT(*) FROM notifications WHERE user_id IN (520, 521)`).Scan(&notified))
+	require.Equal(t, 2, notified, "authors are told the outcome")
+}
+
+// TestMergeReviews checks that merging accounts moves reviews and drops
+// the source's review of a product both accounts reviewed.
+func TestMergeReviews(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var categoryID, sellerID int
+	require.NoError(t, pool.QueryRow(ctx, `INSERT INTO categories (name) VALUES ('Merged') RETURNING id`).Scan(&categoryID))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (12, 'Merged shop', true) RETURNING id`).Scan(&sellerID))
+	product := func() int {
+		var id int
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO products (seller_id, category_id, title, price, stock, status) VALUES ($1, $2, 'Pen', 10, 5, 'active') RETURNING id`,
+			sellerID, categoryID).Scan(&id))
+		return id
+	}
+	pen, ink := product(), product()
+	_, err := pool.Exec(ctx, `INSERT INTO reviews (product_id, user_id, rating) VALUES ($1, 530, 1), ($1, 531, 5), ($2, 530, 4)`, pen, ink)
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `UPDATE products SET rating = 3, review_count = 2 WHERE id = $1`, pen)
+	require.NoError(t, err)
+
+	merge, err := repository.NewUserMergeRepository(pool).Merge(ctx, 1, &models.MergeUsersRequest{SourceUserID: 530, TargetUserID: 531})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), merge.Reviews)
+
+	var reviews int
+	require.NoError(t, pool.QueryRow(ctx, `SELECT COUNT(*) FROM reviews WHERE user_id = 531`).Scan(&reviews))
+	require.Equal(t, 2, reviews)
+	var rating float64
+	var count int
+	require.NoError(t, pool.QueryRow(ctx, `SELECT rating::float8, review_count FROM products WHERE id = $1`, pen).Scan(&rating, &count))
+	require.Equal(t, 5.0, rating)
+	require.Equal(t, 1, count)
+}
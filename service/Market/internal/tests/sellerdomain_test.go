@@ -0,0 +1,80 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSellerCustomDomain maps domains to sellers and checks that storefront
+// settings follow the domain they belong to.
+func TestSellerCustomDomain(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var sellerID, otherSellerID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (60, 'Domain Shop', true) RETURNING id`).Scan(&sellerID))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (61, 'Other Shop', true) RETURNING id`).Scan(&otherSellerID))
+
+	sellers := repository.NewSellerRepository(pool, nil)
+	storefronts := repository.NewStorefrontRepository(pool)
+
+	seller, err := sellers.SetCustomDomain(ctx, sellerID, "shop.example.com")
+	require.NoError(t, err)
+	require.Equal(t, "shop.example.com", seller.CustomDomain)
+	id, err := sellers.GetIDByDomain(ctx, "shop.example.com")
+	require.NoError(t, err)
+	require.Equal(t, sellerID, id)
+
+	var appErr *apperrors.AppError
+	_, err = sellers.SetCustomDomain(ctx, otherSellerID, "shop.example.com")
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeConflict, appErr.Code)
+
+	// Domains with settings of their own cannot be claimed.
+	_, err = storefronts.Upsert(ctx, "brand.example.com", 1, &models.UpdateStorefrontSettingsRequest{
+		Name: "Brand", DefaultCurrency: "EUR", DefaultLocale: "de",
+	})
+	require.NoError(t, err)
+	_, err = sellers.SetCustomDomain(ctx, otherSellerID, "brand.example.com")
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeConflict, appErr.Code)
+
+	// Settings move along with the domain.
+	_, err = storefronts.Upsert(ctx, "shop.example.com", 60, &models.UpdateStorefrontSettingsRequest{
+		Name: "Domain Shop", DefaultCurrency: "USD", DefaultLocale: "en",
+	})
+	require.NoError(t, err)
+	_, err = sellers.SetCustomDomain(ctx, sellerID, "www.shop.example.com")
+	require.NoError(t, err)
+	settings, err := storefronts.Resolve(ctx, "www.shop.example.com")
+	require.NoError(t, err)
+	require.Equal(t, "Domain Shop", settings.Name)
+	id, err = sellers.GetIDByDomain(ctx, "shop.example.com")
+	require.NoError(t, err)
+	require.Zero(t, id)
+
+	// Inactive sellers' domains are not served.
+	_, err = pool.Exec(ctx, `UPDATE sellers SET is_active = false WHERE id = $1`, sellerID)
+	require.NoError(t, err)
+	id, err = sellers.GetIDByDomain(ctx, "www.shop.example.com")
+	require.NoError(t, err)
+	require.Zero(t, id)
+
+	// Removing the domain deletes its settings.
+	seller, err = sellers.SetCustomDomain(ctx, sellerID, "")
+	require.NoError(t, err)
+	require.Empty(t, seller.CustomDomain)
+	settings, err = storefronts.Resolve(ctx, "www.shop.example.com")
+	require.NoError(t, err)
+	require.Nil(t, settings)
+}
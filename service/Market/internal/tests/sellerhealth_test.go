@@ -0,0 +1,112 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/sellerhealth"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSellerHealthEnforcement checks the health counts against the real
+// schema and that one enforcement run suspends a failing seller once.
+func TestSellerHealthEnforcement(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var categoryID int
+	require.NoError(t, pool.QueryRow(ctx, `INSERT INTO categories (name) VALUES ('Health') RETURNING id`).Scan(&categoryID))
+
+	seller := func(userID int) (sellerID, productID int) {
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO sellers (user_id, shop_name, is_active) VALUES ($1, 'Shop', true) RETURNING id`, userID).Scan(&sellerID))
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO products (seller_id, category_id, title, price, stock, status) VALUES ($1, $2, 'Mug', 10, 100, 'active') RETURNING id`,
+			sellerID, categoryID).Scan(&productID))
+		return sellerID, productID
+	}
+	// order places an order age ago with one item of product, shipped
+	// shippedAfter the order (nil for not shipped).
+	order := func(productID int, age, status, fulfillment string, shippedAfter any) int {
+		var id int
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO orders (user_id, total_amount, status, delivery_address, order_number, created_at)
+			VALUES (500, 10, $1, 'addr', 'MB-H-' || nextval('order_number_seq'), NOW() - $2::interval) RETURNING id`,
+			status, age).Scan(&id))
+		_, err := pool.Exec(ctx,
+			`INSERT INTO order_items (order_id, product_id, quantity, price, fulfillment_status, shipped_at)
+			SELECT id, $2, 1, 10, $3, created_at + $4::interval FROM orders WHERE id = $1`,
+			id, productID, fulfillment, shippedAfter)
+		require.NoError(t, err)
+		return id
+	}
+
+	failing, failingProduct := seller(10)
+	healthy, healthyProduct := seller(20)
+
+	for i := 0; i < 5; i++ {
+		order(failingProduct, "5 days", "confirmed", "shipped", "1 day")
+		order(healthyProduct, "5 days", "confirmed", "delivered", "2 days")
+	}
+	order(failingProduct, "5 days", "confirmed", "cancelled", nil)
+	order(failingProduct, "5 days", "confirmed", "cancelled", nil)
+	order(failingProduct, "5 days", "confirmed", "shipped", "4 days") // shipped late
+	order(failingProduct, "5 days", "confirmed", "processing", nil)   // overdue
+	disputed := order(failingProduct, "5 days", "confirmed", "delivered", "1 day")
+	order(failingProduct, "1 hour", "pending", "pending", nil)      // not due yet
+	order(failingProduct, "5 days", "cancelled", "pending", nil)    // cancelled by the buyer
+	order(failingProduct, "60 days", "confirmed", "cancelled", nil) // outside the window
+	_, err := pool.Exec(ctx,
+		`INSERT INTO support_tickets (user_id, user_role, category, subject, order_id) VALUES (500, 'user', 'order', 'Broken', $1)`, disputed)
+	require.NoError(t, err)
+
+	repo := repository.NewSellerHealthRepository(pool)
+	policy := sellerhealth.Policy{Window: 30 * 24 * time.Hour, ShipWithin: 72 * time.Hour}
+
+	counts, err := repo.GetCounts(ctx, failing, policy.Window, policy.ShipWithin)
+	require.NoError(t, err)
+	require.Equal(t, models.SellerHealthCounts{
+		SellerID: failing, UserID: 10, IsActive: true, Orders: 11, Cancelled: 2, Late: 2, Disputed: 1,
+	}, *counts)
+
+	threshold := func(v float64) *float64 { return &v }
+	_, err = repo.CreateRule(ctx, 1, &models.SellerHealthRuleRequest{
+		Metric: models.HealthMetricCancellationRate, Threshold: threshold(0.1), Action: models.HealthActionWarn,
+	})
+	require.NoError(t, err)
+	_, err = repo.CreateRule(ctx, 1, &models.SellerHealthRuleRequest{
+		Metric: models.HealthMetricScore, Threshold: threshold(50), Action: models.HealthActionSuspend,
+	})
+	require.NoError(t, err)
+
+	enforcer := sellerhealth.NewEnforcer(repo, policy, 24*time.Hour)
+	applied, err := enforcer.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, applied, "the suspension replaces the warning")
+
+	applied, err = enforcer.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Zero(t, applied, "suspended sellers are not acted on again")
+
+	var active bool
+	require.NoError(t, pool.QueryRow(ctx, `SELECT is_active FROM sellers WHERE id = $1`, failing).Scan(&active))
+	require.False(t, active)
+	require.NoError(t, pool.QueryRow(ctx, `SELECT is_active FROM sellers WHERE id = $1`, healthy).Scan(&active))
+	require.True(t, active)
+
+	actions, total, err := repo.GetActions(ctx, &failing, &models.PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.Equal(t, models.HealthActionSuspend, actions[0].Action)
+
+	var notified int
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM notifications WHERE user_id = 10 AND kind = 'seller_health'`).Scan(&notified))
+	require.Equal(t, 1, notified)
+}
@@ -0,0 +1,145 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSellerStats checks the revenue series, per-product conversion from
+// stored views and low-stock alerts of one seller.
+func TestSellerStats(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var categoryID, sellerID, otherID int
+	require.NoError(t, pool.QueryRow(ctx, `INSERT INTO categories (name) VALUES ('Stats') RETURNING id`).Scan(&categoryID))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (660, 'Shop', true) RETURNING id`).Scan(&sellerID))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (661, 'Other', true) RETURNING id`).Scan(&otherID))
+
+	product := func(sellerID int, title string, stock int) int {
+		var id int
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO products (seller_id, category_id, title, price, stock, status) VALUES ($1, $2, $3, 10, $4, 'active') RETURNING id`,
+			sellerID, categoryID, title, stock).Scan(&id))
+		return id
+	}
+	mug, hat, foreign := product(sellerID, "Mug", 2), product(sellerID, "Hat", 50), product(otherID, "Cap", 0)
+
+	order := func(day time.Time, status string, items map[int]int) {
+		var orderID int
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO orders (user_id, total_amount, status, delivery_address, order_number, created_at)
+			VALUES (662, 10, $1, 'addr', 'MB-S-' || nextval('order_number_seq'), $2) RETURNING id`, status, day).Scan(&orderID))
+		for productID, quantity := range items {
+			_, err := pool.Exec(ctx, `INSERT INTO order_items (order_id, product_id, quantity, price) VALUES ($1, $2, $3, 10)`,
+				orderID, productID, quantity)
+			require.NoError(t, err)
+		}
+	}
+	day1 := time.Date(2026, 2, 2, 10, 0, 0, 0, time.UTC)
+	day3 := time.Date(2026, 2, 4, 10, 0, 0, 0, time.UTC)
+	order(day1, "paid", map[int]int{mug: 2, foreign: 1})
+	order(day3, "paid", map[int]int{mug: 1})
+	order(day3, "cancelled", map[int]int{hat: 5})
+
+	stats := repository.NewSellerStatsRepository(pool)
+	require.NoError(t, stats.AddViews(ctx, time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC), map[int]int64{mug: 30, hat: 10, 999999: 4}))
+	require.NoError(t, stats.AddViews(ctx, time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC), map[int]int64{mug: 20}))
+
+	got, err := stats.GetSellerStats(ctx, 660, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 6, 0, 0, 0, 0, time.UTC), "day", 5)
+	require.NoError(t, err)
+
+	require.Len(t, got.Revenue, 5, "days without sales are reported")
+	require.Equal(t, "2026-02-01", got.Revenue[0].Period)
+	require.Equal(t, 0.0, got.Revenue[0].Revenue)
+	require.Equal(t, 20.0, got.Revenue[1].Revenue, "other sellers' items are left out")
+	require.Equal(t, 10.0, got.Revenue[3].Revenue, "cancelled orders are left out")
+
+	require.Len(t, got.Products, 2)
+	require.Equal(t, mug, got.Products[0].ProductID)
+	require.Equal(t, 3, got.Products[0].UnitsSold)
+	require.Equal(t, int64(50), got.Products[0].Views)
+	require.Equal(t, 0.04, got.Products[0].Conversion)
+	require.Equal(t, hat, got.Products[1].ProductID)
+	require.Equal(t, 0, got.Products[1].UnitsSold)
+
+	require.Len(t, got.LowStock, 1)
+	require.Equal(t, mug, got.LowStock[0].ProductID)
+	require.Equal(t, 2, got.LowStock[0].Stock)
+
+	got, err = stats.GetSellerStats(ctx, 660, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), "week", 5)
+	require.NoError(t, err)
+	require.Equal(t, "2026-01-26", got.Revenue[0].Period, "weeks start on Monday")
+	require.Equal(t, 30.0, got.Revenue[1].Revenue)
+}
+
+// TestLowStockAlerts checks that sellers are notified once of products that
+// ran low, by their own threshold or the default, and again after a
+// restock.
+func TestLowStockAlerts(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var categoryID, sellerID int
+	require.NoError(t, pool.QueryRow(ctx, `INSERT INTO categories (name) VALUES ('Low stock') RETURNING id`).Scan(&categoryID))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (680, 'Shop', true) RETURNING id`).Scan(&sellerID))
+
+	products := repository.NewProductRepository(pool, nil)
+	product := func(title string, stock int, threshold *int) int {
+		p, err := products.Create(ctx, sellerID, &models.CreateProductRequest{
+			CategoryID: categoryID, Title: title, Price: 10, Stock: stock, LowStockThreshold: threshold,
+		})
+		require.NoError(t, err)
+		_, err = pool.Exec(ctx, `UPDATE products SET status = 'active' WHERE id = $1`, p.ID)
+		require.NoError(t, err)
+		return p.ID
+	}
+	twenty := 20
+	mug, hat := product("Mug", 12, &twenty), product("Hat", 12, nil)
+
+	stats := repository.NewSellerStatsRepository(pool)
+	low, err := stats.GetLowStock(ctx, 680, 5)
+	require.NoError(t, err)
+	require.Len(t, low, 1, "only the mug is at its own threshold")
+	require.Equal(t, mug, low[0].ProductID)
+	require.Equal(t, 20, low[0].Threshold)
+
+	alerts := func() int {
+		var n int
+		require.NoError(t, pool.QueryRow(ctx,
+			`SELECT COUNT(*) FROM notifications WHERE kind = 'low_stock' AND user_id = 680`).Scan(&n))
+		return n
+	}
+	n, err := stats.AlertLowStock(ctx, 5, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	n, err = stats.AlertLowStock(ctx, 5, 10)
+	require.NoError(t, err)
+	require.Zero(t, n, "a product is alerted once")
+
+	_, err = pool.Exec(ctx, `UPDATE products SET stock = 4 WHERE id = $1`, hat)
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `UPDATE products SET stock = 30 WHERE id = $1`, mug)
+	require.NoError(t, err)
+	n, err = stats.AlertLowStock(ctx, 5, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, n, "the hat fell to the default threshold")
+
+	_, err = pool.Exec(ctx, `UPDATE products SET stock = 2 WHERE id = $1`, mug)
+	require.NoError(t, err)
+	n, err = stats.AlertLowStock(ctx, 5, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, n, "a restocked product is alerted again")
+	require.Equal(t, 3, alerts())
+}
@@ -0,0 +1,81 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPayoutsWaitForSettlement checks that a month is only paid out once
+// its sales were delivered the settlement delay of their category's risk
+// level ago, sooner for trusted sellers, and no buyer ticket is open.
+func TestPayoutsWaitForSettlement(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var categoryID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO categories (name, risk_level) VALUES ('Settlement', 'high') RETURNING id`).Scan(&categoryID))
+
+	payouts := repository.NewPayoutRepository(pool, nil)
+	sellers := repository.NewSellerRepository(pool, nil)
+	sellerIDs := map[int]int{}
+	orderIDs := map[int]int{}
+	for _, userID := range []int{670, 671} {
+		var sellerID, productID, orderID int
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO sellers (user_id, shop_name, is_active) VALUES ($1, 'Shop', true) RETURNING id`, userID).Scan(&sellerID))
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO products (seller_id, category_id, title, price, stock, status) VALUES ($1, $2, 'Mug', 30, 5, 'active') RETURNING id`,
+			sellerID, categoryID).Scan(&productID))
+		require.NoError(t, pool.QueryRow(ctx,
+			`INSERT INTO orders (user_id, total_amount, status, delivery_address, order_number, created_at, updated_at)
+			VALUES (672, 30, 'delivered', 'addr', 'MB-D-' || nextval('order_number_seq'), '2026-01-10', NOW() - INTERVAL '5 days')
+			RETURNING id`).Scan(&orderID))
+		_, err := pool.Exec(ctx, `INSERT INTO order_items (order_id, product_id, quantity, price) VALUES ($1, $2, 1, 30)`, orderID, productID)
+		require.NoError(t, err)
+		_, err = pool.Exec(ctx, `INSERT INTO seller_statements (seller_id, period, payout) VALUES ($1, '2026-01-01', 30)`, sellerID)
+		require.NoError(t, err)
+		_, err = payouts.SetAccount(ctx, userID, &models.SetPayoutAccountRequest{
+			Method: models.PayoutMethodBankTransfer, HolderName: "Shop", Account: "DE89370400440532013000",
+		})
+		require.NoError(t, err)
+		sellerIDs[userID], orderIDs[userID] = sellerID, orderID
+	}
+
+	seller, err := sellers.SetTrusted(ctx, sellerIDs[671], true)
+	require.NoError(t, err)
+	require.True(t, seller.Trusted)
+
+	delays := models.SettlementDelays{Low: 24 * time.Hour, Standard: 72 * time.Hour, High: 240 * time.Hour, Trusted: 48 * time.Hour}
+	before := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	created, err := payouts.Batch(ctx, "sandbox", before, 10, delays)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), created)
+	due, err := payouts.Due(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.Equal(t, sellerIDs[671], due[0].SellerID, "only the trusted seller's sale has settled")
+
+	_, err = repository.NewCategoryRepository(pool, nil).Update(ctx, categoryID, &models.UpdateCategoryRequest{RiskLevel: models.CategoryRiskLow})
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `INSERT INTO support_tickets (user_id, user_role, category, subject, order_id)
+		VALUES (672, 'user', 'order', 'Broken mug', $1)`, orderIDs[670])
+	require.NoError(t, err)
+	created, err = payouts.Batch(ctx, "sandbox", before, 10, delays)
+	require.NoError(t, err)
+	require.Zero(t, created, "an open buyer ticket holds the payout")
+
+	_, err = pool.Exec(ctx, `UPDATE support_tickets SET status = 'resolved' WHERE order_id = $1`, orderIDs[670])
+	require.NoError(t, err)
+	created, err = payouts.Batch(ctx, "sandbox", before, 10, delays)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), created, "low risk sales settle after a day")
+}
@@ -0,0 +1,78 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/Zifeldev/marketback/service/Market/internal/transform"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTransformRules checks that stored rules are loaded with their seller's
+// owner, apply to that seller's events and notifications only, and stop
+// applying once disabled.
+func TestTransformRules(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var sellerID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (690, 'Shop', true) RETURNING id`).Scan(&sellerID))
+
+	repo := repository.NewTransformRuleRepository(pool)
+	_, err := repo.Create(ctx, 1, &models.CreateTransformRuleRequest{
+		SellerID: &sellerID, Target: models.TransformTargetNotification, Match: "low_stock",
+		Patch: json.RawMessage(`[{"op":"replace","path":"/message","value":"Restock soon"}]`),
+	})
+	require.NoError(t, err)
+	event, err := repo.Create(ctx, 1, &models.CreateTransformRuleRequest{
+		SellerID: &sellerID, Target: models.TransformTargetEvent, Match: transform.MatchAll,
+		Patch: json.RawMessage(`[{"op":"add","path":"/erp_id","value":"S-1"}]`),
+	})
+	require.NoError(t, err)
+	require.True(t, event.Enabled, "rules are enabled by default")
+
+	missing := 999999
+	_, err = repo.Create(ctx, 1, &models.CreateTransformRuleRequest{
+		SellerID: &missing, Target: models.TransformTargetEvent, Match: transform.MatchAll,
+		Patch: json.RawMessage(`[{"op":"remove","path":"/a"}]`),
+	})
+	var appErr *apperrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperrors.CodeNotFound, appErr.Code)
+
+	rules := transform.NewRules(repo)
+	require.NoError(t, rules.Refresh(ctx))
+
+	own := &models.Notification{UserID: 690, Kind: "low_stock", Message: "Mug is low on stock"}
+	other := &models.Notification{UserID: 691, Kind: "low_stock", Message: "Hat is low on stock"}
+	rules.Notification(own)
+	rules.Notification(other)
+	require.Equal(t, "Restock soon", own.Message)
+	require.Equal(t, "Hat is low on stock", other.Message)
+
+	data, err := json.Marshal(models.ProductStockLowEvent{SellerID: sellerID, ProductID: 1, Stock: 2})
+	require.NoError(t, err)
+	require.Contains(t, string(rules.Event(models.EventProductStockLow, data)), `"erp_id":"S-1"`)
+
+	disabled := false
+	_, err = repo.Update(ctx, event.ID, &models.UpdateTransformRuleRequest{Enabled: &disabled})
+	require.NoError(t, err)
+	require.NoError(t, rules.Refresh(ctx))
+	require.JSONEq(t, string(data), string(rules.Event(models.EventProductStockLow, data)))
+
+	all, err := repo.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2, "disabled rules are still listed")
+
+	require.NoError(t, repo.Delete(ctx, event.ID))
+	require.ErrorAs(t, repo.Delete(ctx, event.ID), &appErr)
+	require.Equal(t, apperrors.CodeNotFound, appErr.Code)
+}
@@ -0,0 +1,82 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMergeStoreCredit checks that a merge adds the source user's store
+// credit to the target's balance.
+func TestMergeStoreCredit(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	giftCards := repository.NewGiftCardRepository(pool)
+	_, err := giftCards.GrantStoreCredit(ctx, 540, 15)
+	require.NoError(t, err)
+	_, err = giftCards.GrantStoreCredit(ctx, 541, 5)
+	require.NoError(t, err)
+
+	merges := repository.NewUserMergeRepository(pool)
+	merge, err := merges.Merge(ctx, 1, &models.MergeUsersRequest{SourceUserID: 540, TargetUserID: 541})
+	require.NoError(t, err)
+	require.Equal(t, 15.0, merge.StoreCredit)
+
+	var balance float64
+	require.NoError(t, pool.QueryRow(ctx, `SELECT balance::float8 FROM store_credits WHERE user_id = 541`).Scan(&balance))
+	require.Equal(t, 20.0, balance)
+	var left int
+	require.NoError(t, pool.QueryRow(ctx, `SELECT COUNT(*) FROM store_credits WHERE user_id = 540`).Scan(&left))
+	require.Zero(t, left)
+
+	// Without store credit nothing moves
+	merge, err = merges.Merge(ctx, 1, &models.MergeUsersRequest{SourceUserID: 542, TargetUserID: 541})
+	require.NoError(t, err)
+	require.Zero(t, merge.StoreCredit)
+}
+
+// TestMergePaymentMethods checks that a merge moves saved payment methods
+// and the provider customers the target has none with.
+func TestMergePaymentMethods(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	methods := repository.NewPaymentMethodRepository(pool)
+	for _, m := range []*models.SavedPaymentMethod{
+		{UserID: 550, Provider: "stripe", CustomerRef: "cus_source", MethodRef: "pm_source"},
+		{UserID: 550, Provider: "sandbox", CustomerRef: "cus_sandbox", MethodRef: "pm_sandbox"},
+		{UserID: 551, Provider: "stripe", CustomerRef: "cus_target", MethodRef: "pm_target"},
+	} {
+		_, err := methods.SaveCustomer(ctx, m.UserID, m.Provider, m.CustomerRef)
+		require.NoError(t, err)
+		_, err = methods.Save(ctx, m)
+		require.NoError(t, err)
+	}
+
+	merge, err := repository.NewUserMergeRepository(pool).Merge(ctx, 1, &models.MergeUsersRequest{SourceUserID: 550, TargetUserID: 551})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), merge.PaymentMethods)
+
+	saved, err := methods.List(ctx, 551)
+	require.NoError(t, err)
+	require.Len(t, saved, 3)
+	for _, m := range saved {
+		if m.MethodRef == "pm_source" {
+			require.Equal(t, "cus_source", m.CustomerRef, "a moved method keeps its customer")
+		}
+	}
+
+	stripe, err := methods.GetCustomer(ctx, 551, "stripe")
+	require.NoError(t, err)
+	require.Equal(t, "cus_target", stripe)
+	sandbox, err := methods.GetCustomer(ctx, 551, "sandbox")
+	require.NoError(t, err)
+	require.Equal(t, "cus_sandbox", sandbox)
+}
@@ -0,0 +1,120 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/apperrors"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProductVariants checks that variants carry their own stock and price
+// through the cart, checkout and cancellation, and that the product's stock
+// and sizes follow them.
+func TestProductVariants(t *testing.T) {
+	pool := startMarketDB(t, 4)
+	ctx := context.Background()
+
+	var sellerID, categoryID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO sellers (user_id, shop_name, is_active) VALUES (50, 'Tee Shop', true) RETURNING id`).Scan(&sellerID))
+	require.NoError(t, pool.QueryRow(ctx, `INSERT INTO categories (name) VALUES ('Tees') RETURNING id`).Scan(&categoryID))
+
+	products := repository.NewProductRepository(pool, nil)
+	variants := repository.NewVariantRepository(pool, nil)
+	product, err := products.Create(ctx, sellerID, &models.CreateProductRequest{
+		CategoryID: categoryID, Title: "Tee", Price: 20,
+		Variants: []models.CreateVariantRequest{
+			{SKU: "TEE-S-RED", Size: "S", Color: "red", Stock: 2},
+			{SKU: "TEE-M-RED", Size: "M", Color: "red", Stock: 1, PriceDelta: 5},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, product.Stock)
+	require.Equal(t, models.SizesJSON{"S", "M"}, product.Sizes)
+	_, err = pool.Exec(ctx, `UPDATE products SET status = 'active' WHERE id = $1`, product.ID)
+	require.NoError(t, err)
+
+	list, err := variants.GetByProductID(ctx, product.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	small, medium := list[0], list[1]
+
+	var appErr *apperrors.AppError
+	requireCode := func(err error, code string) {
+		t.Helper()
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, code, appErr.Code)
+	}
+
+	carts := repository.NewCartRepository(pool, time.Minute)
+	_, err = carts.AddItem(ctx, 60, &models.AddToCartRequest{ProductID: product.ID, Quantity: 1})
+	requireCode(err, apperrors.CodeValidationError)
+
+	item, err := carts.AddItem(ctx, 60, &models.AddToCartRequest{ProductID: product.ID, Quantity: 1, VariantID: &medium.ID, Size: "XL"})
+	require.NoError(t, err)
+	require.Equal(t, "M", item.Size, "the variant's size")
+	_, err = carts.UpdateItem(ctx, item.ID, 60, &models.UpdateCartItemRequest{Quantity: 1, Size: "S"})
+	requireCode(err, apperrors.CodeValidationError)
+
+	// The medium tee is held by the first cart; the small ones are not.
+	_, err = carts.AddItem(ctx, 61, &models.AddToCartRequest{ProductID: product.ID, Quantity: 1, VariantID: &medium.ID})
+	requireCode(err, apperrors.CodeInsufficientStock)
+	_, err = carts.AddItem(ctx, 61, &models.AddToCartRequest{ProductID: product.ID, Quantity: 2, VariantID: &small.ID})
+	require.NoError(t, err)
+
+	cart, err := carts.GetUserCart(ctx, 60)
+	require.NoError(t, err)
+	require.Equal(t, 25.0, cart[0].ProductPrice)
+	require.Equal(t, "TEE-M-RED", cart[0].SKU)
+
+	orders := repository.NewOrderRepository(pool, nil, nil, nil, nil)
+	order, err := orders.Create(ctx, 60, &models.CreateOrderRequest{PaymentMethod: "card", DeliveryAddr: "4 Tee Lane"}, cart)
+	require.NoError(t, err)
+	require.Equal(t, 25.0, order.TotalAmount)
+	require.Equal(t, medium.ID, *order.Items[0].VariantID)
+	require.Equal(t, "TEE-M-RED", order.Items[0].SKU)
+	require.Equal(t, "red", order.Items[0].Color)
+
+	stock := func() (productStock, variantStock int) {
+		t.Helper()
+		require.NoError(t, pool.QueryRow(ctx,
+			`SELECT p.stock, v.stock FROM products p JOIN product_variants v ON v.product_id = p.id WHERE v.id = $1`,
+			medium.ID).Scan(&productStock, &variantStock))
+		return productStock, variantStock
+	}
+	productStock, mediumStock := stock()
+	require.Equal(t, 2, productStock)
+	require.Equal(t, 0, mediumStock)
+
+	_, err = orders.CancelByBuyer(ctx, order.ID, 60, time.Hour)
+	require.NoError(t, err)
+	productStock, mediumStock = stock()
+	require.Equal(t, 3, productStock)
+	require.Equal(t, 1, mediumStock, "cancelling restocks the variant")
+
+	// Stock is set per variant, and prices stay positive.
+	newStock := 10
+	_, err = products.Update(ctx, product.ID, &models.UpdateProductRequest{Stock: &newStock})
+	requireCode(err, apperrors.CodeValidationError)
+	delta := -20.0
+	_, err = variants.Update(ctx, product.ID, small.ID, &models.UpdateVariantRequest{PriceDelta: &delta})
+	requireCode(err, apperrors.CodeValidationError)
+	_, err = variants.Create(ctx, product.ID, &models.CreateVariantRequest{SKU: "TEE-S-RED", Size: "L"})
+	requireCode(err, apperrors.CodeConflict)
+
+	require.NoError(t, variants.Delete(ctx, product.ID, small.ID))
+	updated, err := products.GetByID(ctx, product.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, updated.Stock)
+	require.Equal(t, models.SizesJSON{"M"}, updated.Sizes)
+	cart, err = carts.GetUserCart(ctx, 61)
+	require.NoError(t, err)
+	require.Empty(t, cart, "cart items for a removed variant go with it")
+}
@@ -0,0 +1,377 @@
+// Package transform applies admin-defined rules to outbound payloads, the
+// domain events sent to the broker and in-app notifications, so integrations
+// can be adjusted without a deploy.
+//
+// A rule is a JSON Patch (RFC 6902): a list of add, remove, replace, move,
+// copy and test operations on the payload's JSON document. Patches cannot
+// call out, loop or read anything but the document, so they run sandboxed
+// by construction; their size and the documents they touch are bounded.
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxOperations bounds the operations of a patch.
+	MaxOperations = 50
+	// maxPatchSize bounds the encoded patch, values included.
+	maxPatchSize = 16 << 10
+	// maxDocumentSize bounds the documents patches are applied to and
+	// produce.
+	maxDocumentSize = 1 << 20
+)
+
+// ErrTestFailed is returned by Apply when a test operation does not match;
+// the rule then leaves the document alone.
+var ErrTestFailed = errors.New("test operation failed")
+
+// Operation is one JSON Patch operation. Path and From are JSON Pointers
+// (RFC 6901).
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Patch is a parsed, validated JSON Patch.
+type Patch []Operation
+
+// Parse decodes and validates a JSON Patch.
+func Parse(raw []byte) (Patch, error) {
+	if len(raw) > maxPatchSize {
+		return nil, fmt.Errorf("patch must be at most %d bytes", maxPatchSize)
+	}
+	var p Patch
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, fmt.Errorf("patch must be a JSON array of operations: %w", err)
+	}
+	if len(p) == 0 || len(p) > MaxOperations {
+		return nil, fmt.Errorf("patch must have 1 to %d operations", MaxOperations)
+	}
+	for i, op := range p {
+		if err := op.validate(); err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+	return p, nil
+}
+
+func (op Operation) validate() error {
+	if _, err := pointer(op.Path); err != nil {
+		return fmt.Errorf("path: %w", err)
+	}
+	switch op.Op {
+	case "add", "replace", "test":
+		if len(op.Value) == 0 {
+			return fmt.Errorf("%s needs a value", op.Op)
+		}
+		if !json.Valid(op.Value) {
+			return fmt.Errorf("value is not valid JSON")
+		}
+	case "remove":
+		if op.Path == "" {
+			return fmt.Errorf("cannot remove the whole document")
+		}
+	case "move", "copy":
+		if _, err := pointer(op.From); err != nil {
+			return fmt.Errorf("from: %w", err)
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return fmt.Errorf("cannot %s a value into itself", op.Op)
+		}
+	default:
+		return fmt.Errorf("unknown op %q, must be add, remove, replace, move, copy or test", op.Op)
+	}
+	return nil
+}
+
+// Apply returns doc with the patch applied. The operations apply all or
+// none: on an error, ErrTestFailed included, doc is to be used unchanged.
+func (p Patch) Apply(doc []byte) ([]byte, error) {
+	if len(doc) > maxDocumentSize {
+		return nil, fmt.Errorf("document must be at most %d bytes", maxDocumentSize)
+	}
+	root, err := decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("document is not valid JSON: %w", err)
+	}
+	var out []byte
+	for i, op := range p {
+		if root, err = op.apply(root); err != nil {
+			if errors.Is(err, ErrTestFailed) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+		// Copies can grow the document geometrically, so its size is
+		// bounded after every operation rather than at the end
+		if out, err = json.Marshal(root); err != nil {
+			return nil, fmt.Errorf("failed to encode document: %w", err)
+		}
+		if len(out) > maxDocumentSize {
+			return nil, fmt.Errorf("operation %d (%s %s): patched document must be at most %d bytes", i, op.Op, op.Path, maxDocumentSize)
+		}
+	}
+	return out, nil
+}
+
+func (op Operation) apply(root any) (any, error) {
+	path, _ := pointer(op.Path)
+	switch op.Op {
+	case "add":
+		value, err := decode(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, path, value)
+	case "remove":
+		root, _, err := remove(root, path)
+		return root, err
+	case "replace":
+		value, err := decode(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if root, _, err = remove(root, path); err != nil {
+			return nil, err
+		}
+		return add(root, path, value)
+	case "move":
+		from, _ := pointer(op.From)
+		root, value, err := remove(root, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, path, value)
+	case "copy":
+		from, _ := pointer(op.From)
+		value, err := get(root, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, path, clone(value))
+	case "test":
+		want, err := decode(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		got, err := get(root, path)
+		if err != nil || !equal(got, want) {
+			return nil, ErrTestFailed
+		}
+		return root, nil
+	}
+	return nil, fmt.Errorf("unknown op %q", op.Op)
+}
+
+// decode parses JSON keeping numbers exact.
+func decode(raw []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return v, nil
+}
+
+// pointer splits a JSON Pointer into its unescaped reference tokens; the
+// empty pointer is the whole document.
+func pointer(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(s, "/") {
+		return nil, fmt.Errorf("%q must be empty or start with /", s)
+	}
+	tokens := strings.Split(s[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+	}
+	return tokens, nil
+}
+
+// index resolves an array reference token; "-" is past the end when end is
+// allowed.
+func index(token string, n int, end bool) (int, error) {
+	if token == "-" && end {
+		return n, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if i > n || (i == n && !end) {
+		return 0, fmt.Errorf("array index %d out of range", i)
+	}
+	return i, nil
+}
+
+func get(v any, path []string) (any, error) {
+	for _, token := range path {
+		switch node := v.(type) {
+		case map[string]any:
+			child, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("no member %q", token)
+			}
+			v = child
+		case []any:
+			i, err := index(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			v = node[i]
+		default:
+			return nil, fmt.Errorf("cannot descend into a scalar at %q", token)
+		}
+	}
+	return v, nil
+}
+
+// add sets path to value, inserting into arrays, and returns the new root.
+func add(root any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := get(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		node[last] = value
+		return root, nil
+	case []any:
+		i, err := index(last, len(node), true)
+		if err != nil {
+			return nil, err
+		}
+		grown := append(node[:i:i], append([]any{value}, node[i:]...)...)
+		return set(root, path[:len(path)-1], grown)
+	}
+	return nil, fmt.Errorf("cannot add to a scalar")
+}
+
+// remove deletes path and returns the new root and the removed value.
+func remove(root any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("cannot remove the whole document")
+	}
+	parent, err := get(root, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		value, ok := node[last]
+		if !ok {
+			return nil, nil, fmt.Errorf("no member %q", last)
+		}
+		delete(node, last)
+		return root, value, nil
+	case []any:
+		i, err := index(last, len(node), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		value := node[i]
+		shrunk := append(node[:i:i], node[i+1:]...)
+		root, err = set(root, path[:len(path)-1], shrunk)
+		return root, value, err
+	}
+	return nil, nil, fmt.Errorf("cannot remove from a scalar")
+}
+
+// set replaces the value at an existing path, for arrays that were
+// reallocated, and returns the new root.
+func set(root any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := get(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		node[last] = value
+	case []any:
+		i, err := index(last, len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		node[i] = value
+	}
+	return root, nil
+}
+
+func clone(v any) any {
+	switch node := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(node))
+		for k, child := range node {
+			out[k] = clone(child)
+		}
+		return out
+	case []any:
+		out := make([]any, len(node))
+		for i, child := range node {
+			out[i] = clone(child)
+		}
+		return out
+	}
+	return v
+}
+
+// equal compares JSON values, numbers by value.
+func equal(a, b any) bool {
+	switch x := a.(type) {
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, ok := y[k]
+			if !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		xf, okx := new(big.Float).SetString(string(x))
+		yf, oky := new(big.Float).SetString(string(y))
+		return okx && oky && xf.Cmp(yf) == 0
+	}
+	return a == b
+}
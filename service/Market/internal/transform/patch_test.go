@@ -0,0 +1,82 @@
+package transform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatch_Apply(t *testing.T) {
+	p, err := Parse([]byte(`[
+		{"op": "test", "path": "/type", "value": "order"},
+		{"op": "add", "path": "/meta", "value": {"source": "marketback"}},
+		{"op": "move", "from": "/order_number", "path": "/reference"},
+		{"op": "copy", "from": "/items/0", "path": "/items/-"},
+		{"op": "replace", "path": "/total", "value": 10.50},
+		{"op": "remove", "path": "/email"},
+		{"op": "add", "path": "/tags~1labels", "value": ["a"]}
+	]`))
+	require.NoError(t, err)
+
+	out, err := p.Apply([]byte(`{"type": "order", "order_number": "MB-1", "items": [{"id": 1}], "total": 10.5, "email": "a@b.c"}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type": "order", "meta": {"source": "marketback"}, "reference": "MB-1",
+		"items": [{"id": 1}, {"id": 1}], "total": 10.50, "tags/labels": ["a"]}`, string(out))
+}
+
+func TestPatch_ApplyTest(t *testing.T) {
+	p, err := Parse([]byte(`[{"op": "test", "path": "/total", "value": 10.50}, {"op": "remove", "path": "/total"}]`))
+	require.NoError(t, err)
+
+	out, err := p.Apply([]byte(`{"total": 10.5}`))
+	require.NoError(t, err, "numbers compare by value")
+	require.JSONEq(t, `{}`, string(out))
+
+	_, err = p.Apply([]byte(`{"total": 3}`))
+	require.ErrorIs(t, err, ErrTestFailed)
+}
+
+func TestPatch_ApplyErrors(t *testing.T) {
+	p, err := Parse([]byte(`[{"op": "remove", "path": "/missing"}]`))
+	require.NoError(t, err)
+	_, err = p.Apply([]byte(`{"total": 1}`))
+	require.ErrorContains(t, err, "no member")
+
+	p, err = Parse([]byte(`[{"op": "add", "path": "/items/5", "value": 1}]`))
+	require.NoError(t, err)
+	_, err = p.Apply([]byte(`{"items": []}`))
+	require.ErrorContains(t, err, "out of range")
+}
+
+func TestPatch_ApplyBoundsGrowth(t *testing.T) {
+	// Copying two members into each other grows the document geometrically;
+	// it must be stopped long before MaxOperations copies
+	ops := make([]string, 0, MaxOperations)
+	for i := 0; i < MaxOperations/2; i++ {
+		ops = append(ops, `{"op": "copy", "from": "/a", "path": "/b/-"}`, `{"op": "copy", "from": "/b", "path": "/a/-"}`)
+	}
+	p, err := Parse([]byte("[" + strings.Join(ops, ",") + "]"))
+	require.NoError(t, err)
+
+	_, err = p.Apply([]byte(`{"a": ["` + strings.Repeat("x", 1024) + `"], "b": []}`))
+	require.ErrorContains(t, err, "patched document must be at most")
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for name, raw := range map[string]string{
+		"not an array":  `{"op": "add"}`,
+		"empty":         `[]`,
+		"unknown op":    `[{"op": "eval", "path": "/a"}]`,
+		"bad pointer":   `[{"op": "remove", "path": "a"}]`,
+		"no value":      `[{"op": "add", "path": "/a"}]`,
+		"remove root":   `[{"op": "remove", "path": ""}]`,
+		"move into own": `[{"op": "move", "from": "/a", "path": "/a/b"}]`,
+		"copy into own": `[{"op": "copy", "from": "", "path": "/a"}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(raw))
+			require.Error(t, err)
+		})
+	}
+}
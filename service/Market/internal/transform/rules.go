@@ -0,0 +1,170 @@
+package transform
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+	"github.com/Zifeldev/marketback/service/Market/internal/metrics"
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/Zifeldev/marketback/service/Market/internal/workers"
+)
+
+// MatchAll is the match of rules applying to every event type or
+// notification kind.
+const MatchAll = "*"
+
+// Store lists the enabled rules in the order they apply; see
+// repository.TransformRuleRepository.
+type Store interface {
+	ListEnabled(ctx context.Context) ([]*models.TransformRule, error)
+}
+
+type rule struct {
+	id       int
+	match    string
+	sellerID *int
+	userID   *int
+	patch    Patch
+}
+
+// Rules is the set of enabled rules, reloaded from the store. A nil *Rules
+// applies nothing.
+type Rules struct {
+	store Store
+
+	mu       sync.RWMutex
+	byTarget map[string][]rule
+}
+
+func NewRules(store Store) *Rules {
+	return &Rules{store: store}
+}
+
+// Refresh reloads the rules. A stored rule that no longer parses is
+// skipped and logged.
+func (r *Rules) Refresh(ctx context.Context) error {
+	stored, err := r.store.ListEnabled(ctx)
+	if err != nil {
+		logger.GetLogger().WithField("err", err).Error("failed to load transform rules")
+		return fmt.Errorf("failed to load transform rules: %w", err)
+	}
+	byTarget := map[string][]rule{}
+	for _, s := range stored {
+		patch, err := Parse(s.Patch)
+		if err != nil {
+			logger.GetLogger().WithFields(map[string]interface{}{
+				"err":     err,
+				"rule_id": s.ID,
+			}).Warn("skipping invalid transform rule")
+			continue
+		}
+		byTarget[s.Target] = append(byTarget[s.Target], rule{
+			id: s.ID, match: s.Match, sellerID: s.SellerID, userID: s.SellerUserID, patch: patch,
+		})
+	}
+
+	r.mu.Lock()
+	r.byTarget = byTarget
+	r.mu.Unlock()
+	return nil
+}
+
+// Start refreshes the rules every interval until ctx is cancelled.
+func (r *Rules) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		workers.Beat(ctx)
+		_ = r.Refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Event returns the payload of an event of eventType with the matching
+// event rules applied. Seller rules apply to payloads whose top-level
+// seller_id is theirs.
+func (r *Rules) Event(eventType string, data json.RawMessage) json.RawMessage {
+	rules := r.rules(models.TransformTargetEvent)
+	if len(rules) == 0 {
+		return data
+	}
+	var about struct {
+		SellerID *int `json:"seller_id"`
+	}
+	_ = json.Unmarshal(data, &about)
+	for _, rl := range rules {
+		if !rl.matches(eventType) || (rl.sellerID != nil && (about.SellerID == nil || *rl.sellerID != *about.SellerID)) {
+			continue
+		}
+		data = rl.apply(models.TransformTargetEvent, data)
+	}
+	return data
+}
+
+// Notification applies the matching notification rules to n's message.
+// Rules see {"kind": ..., "message": ...}; a patched message that is not a
+// string is ignored. Seller rules apply to the notifications of the
+// seller's owner.
+func (r *Rules) Notification(n *models.Notification) {
+	rules := r.rules(models.TransformTargetNotification)
+	if len(rules) == 0 {
+		return
+	}
+	type document struct {
+		Kind    string `json:"kind"`
+		Message any    `json:"message"`
+	}
+	for _, rl := range rules {
+		if !rl.matches(n.Kind) || (rl.userID != nil && *rl.userID != n.UserID) {
+			continue
+		}
+		doc, _ := json.Marshal(document{Kind: n.Kind, Message: n.Message})
+		var out document
+		if err := json.Unmarshal(rl.apply(models.TransformTargetNotification, doc), &out); err != nil {
+			continue
+		}
+		if message, ok := out.Message.(string); ok {
+			n.Message = message
+		}
+	}
+}
+
+func (r *Rules) rules(target string) []rule {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.byTarget[target]
+}
+
+func (rl rule) matches(name string) bool {
+	return rl.match == MatchAll || rl.match == name
+}
+
+// apply returns doc patched, or unchanged when the patch does not apply.
+func (rl rule) apply(target string, doc []byte) []byte {
+	out, err := rl.patch.Apply(doc)
+	if err != nil {
+		if !errors.Is(err, ErrTestFailed) {
+			metrics.TransformFailuresTotal.WithLabelValues(target).Inc()
+			logger.GetLogger().WithFields(map[string]interface{}{
+				"err":     err,
+				"rule_id": rl.id,
+			}).Warn("transform rule did not apply")
+		}
+		return doc
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+package transform
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	rules []*models.TransformRule
+	err   error
+}
+
+func (f *fakeStore) ListEnabled(ctx context.Context) ([]*models.TransformRule, error) {
+	return f.rules, f.err
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestRules_Event(t *testing.T) {
+	store := &fakeStore{rules: []*models.TransformRule{
+		{ID: 1, Target: models.TransformTargetEvent, Match: MatchAll, Patch: json.RawMessage(`[{"op":"add","path":"/source","value":"market"}]`)},
+		{ID: 2, Target: models.TransformTargetEvent, Match: "ProductStockLow", SellerID: intPtr(3),
+			Patch: json.RawMessage(`[{"op":"move","from":"/stock","path":"/qty"}]`)},
+		{ID: 3, Target: models.TransformTargetEvent, Match: "ProductStockLow",
+			Patch: json.RawMessage(`[{"op":"test","path":"/qty","value":0},{"op":"add","path":"/sold_out","value":true}]`)},
+		{ID: 4, Target: models.TransformTargetEvent, Match: "ProductStockLow", Patch: json.RawMessage(`[{"op":"remove","path":"/missing"}]`)},
+		{ID: 5, Target: models.TransformTargetEvent, Match: MatchAll, Patch: json.RawMessage(`not a patch`)},
+	}}
+	rules := NewRules(store)
+	require.NoError(t, rules.Refresh(context.Background()))
+
+	got := rules.Event("ProductStockLow", json.RawMessage(`{"seller_id":3,"stock":0}`))
+	require.JSONEq(t, `{"seller_id":3,"qty":0,"source":"market","sold_out":true}`, string(got))
+
+	got = rules.Event("ProductStockLow", json.RawMessage(`{"seller_id":4,"stock":0}`))
+	require.JSONEq(t, `{"seller_id":4,"stock":0,"source":"market"}`, string(got), "seller rules only apply to their seller")
+
+	got = rules.Event("OrderCreated", json.RawMessage(`{"order_id":1}`))
+	require.JSONEq(t, `{"order_id":1,"source":"market"}`, string(got))
+}
+
+func TestRules_Notification(t *testing.T) {
+	store := &fakeStore{rules: []*models.TransformRule{
+		{ID: 1, Target: models.TransformTargetNotification, Match: "low_stock", SellerUserID: intPtr(10),
+			Patch: json.RawMessage(`[{"op":"replace","path":"/message","value":"Restock soon"}]`)},
+		{ID: 2, Target: models.TransformTargetNotification, Match: MatchAll,
+			Patch: json.RawMessage(`[{"op":"replace","path":"/message","value":42}]`)},
+	}}
+	rules := NewRules(store)
+	require.NoError(t, rules.Refresh(context.Background()))
+
+	own := &models.Notification{UserID: 10, Kind: "low_stock", Message: "Mug is low on stock"}
+	other := &models.Notification{UserID: 11, Kind: "low_stock", Message: "Hat is low on stock"}
+	rules.Notification(own)
+	rules.Notification(other)
+	require.Equal(t, "Restock soon", own.Message)
+	require.Equal(t, "Hat is low on stock", other.Message, "messages that are not strings are ignored")
+}
+
+func TestRules_Nil(t *testing.T) {
+	var rules *Rules
+	require.JSONEq(t, `{"a":1}`, string(rules.Event("OrderCreated", json.RawMessage(`{"a":1}`))))
+	n := &models.Notification{Message: "hi"}
+	rules.Notification(n)
+	require.Equal(t, "hi", n.Message)
+}
+
+func TestRules_RefreshError(t *testing.T) {
+	rules := NewRules(&fakeStore{err: errors.New("db down")})
+	require.Error(t, rules.Refresh(context.Background()))
+}
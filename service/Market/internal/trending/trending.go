@@ -0,0 +1,133 @@
+// Package trending ranks products by recent clickstream activity. Events are
+// counted in hourly Redis sorted sets; a window is the sum of its hourly
+// buckets weighted by decay^age, so recent activity counts the most.
+package trending
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Event weights. A purchase says much more about demand than a page view.
+const (
+	ViewWeight     = 1.0
+	PurchaseWeight = 10.0
+)
+
+// Windows maps the supported window names to their length in hours.
+var Windows = map[string]int{
+	"1h":  1,
+	"24h": 24,
+	"7d":  24 * 7,
+}
+
+const (
+	keyPrefix = "trending"
+	// bucketTTL keeps buckets just long enough for the widest window.
+	bucketTTL = time.Duration(24*7+1) * time.Hour
+	// rankingTTL caches merged windows so reads do not re-union every time.
+	rankingTTL = time.Minute
+)
+
+// Score is a product's decayed activity within a window.
+type Score struct {
+	ProductID int
+	Score     float64
+}
+
+type Tracker struct {
+	client redis.Cmdable
+	decay  float64
+	now    func() time.Time
+}
+
+// NewTracker creates a tracker. decay is the hourly weight multiplier in
+// (0, 1]; 1 disables decay.
+func NewTracker(client redis.Cmdable, decay float64) *Tracker {
+	return &Tracker{client: client, decay: decay, now: time.Now}
+}
+
+func bucketKey(t time.Time) string {
+	return fmt.Sprintf("%s:h:%d", keyPrefix, t.Unix()/3600)
+}
+
+// buckets returns the hourly keys of a window, newest first, with their
+// decay weights.
+func (t *Tracker) buckets(hours int) ([]string, []float64) {
+	now := t.now()
+	keys := make([]string, hours)
+	weights := make([]float64, hours)
+	for age := 0; age < hours; age++ {
+		keys[age] = bucketKey(now.Add(-time.Duration(age) * time.Hour))
+		weights[age] = math.Pow(t.decay, float64(age))
+	}
+	return keys, weights
+}
+
+func (t *Tracker) record(ctx context.Context, productID int, weight float64) error {
+	if t == nil {
+		return nil
+	}
+	key := bucketKey(t.now())
+	pipe := t.client.TxPipeline()
+	pipe.ZIncrBy(ctx, key, weight, strconv.Itoa(productID))
+	pipe.Expire(ctx, key, bucketTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// RecordView counts a product page view. A nil tracker ignores events.
+func (t *Tracker) RecordView(ctx context.Context, productID int) error {
+	return t.record(ctx, productID, ViewWeight)
+}
+
+// RecordPurchase counts a purchased quantity of a product.
+func (t *Tracker) RecordPurchase(ctx context.Context, productID, quantity int) error {
+	return t.record(ctx, productID, PurchaseWeight*float64(quantity))
+}
+
+// Top returns the highest-scoring products of a window.
+func (t *Tracker) Top(ctx context.Context, window string, limit int) ([]Score, error) {
+	hours, ok := Windows[window]
+	if !ok {
+		return nil, fmt.Errorf("unknown trending window %q", window)
+	}
+	if t == nil {
+		return []Score{}, nil
+	}
+
+	ranking := fmt.Sprintf("%s:window:%s", keyPrefix, window)
+	exists, err := t.client.Exists(ctx, ranking).Result()
+	if err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		keys, weights := t.buckets(hours)
+		pipe := t.client.TxPipeline()
+		pipe.ZUnionStore(ctx, ranking, &redis.ZStore{Keys: keys, Weights: weights, Aggregate: "SUM"})
+		pipe.Expire(ctx, ranking, rankingTTL)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	members, err := t.client.ZRevRangeWithScores(ctx, ranking, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]Score, 0, len(members))
+	for _, m := range members {
+		id, err := strconv.Atoi(fmt.Sprint(m.Member))
+		if err != nil {
+			continue
+		}
+		scores = append(scores, Score{ProductID: id, Score: m.Score})
+	}
+	return scores, nil
+}
@@ -0,0 +1,42 @@
+package trending
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuckets(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	tr := NewTracker(nil, 0.5)
+	tr.now = func() time.Time { return now }
+
+	keys, weights := tr.buckets(3)
+	require.Equal(t, []string{
+		bucketKey(now),
+		bucketKey(now.Add(-time.Hour)),
+		bucketKey(now.Add(-2 * time.Hour)),
+	}, keys)
+	require.Equal(t, []float64{1, 0.5, 0.25}, weights)
+}
+
+func TestBucketKey_Hourly(t *testing.T) {
+	a := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	require.Equal(t, bucketKey(a), bucketKey(a.Add(59*time.Minute)))
+	require.NotEqual(t, bucketKey(a), bucketKey(a.Add(time.Hour)))
+}
+
+func TestTracker_Nil(t *testing.T) {
+	var tr *Tracker
+	require.NoError(t, tr.RecordView(context.Background(), 1))
+	require.NoError(t, tr.RecordPurchase(context.Background(), 1, 2))
+
+	scores, err := tr.Top(context.Background(), "24h", 10)
+	require.NoError(t, err)
+	require.Empty(t, scores)
+
+	_, err = tr.Top(context.Background(), "1y", 10)
+	require.Error(t, err)
+}
@@ -0,0 +1,75 @@
+// Package users looks up Marketback users in the Auth service, so admin
+// lists can show who placed an order or owns a shop instead of only a user
+// ID, and admins can only assign orders to couriers. Email lookups are best
+// effort: when Auth cannot answer, lists go out without emails.
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Zifeldev/marketback/clients/authclient"
+	"github.com/Zifeldev/marketback/service/Market/internal/logger"
+)
+
+// Lookup fetches users from the Auth service. *authclient.GRPCClient
+// implements it.
+type Lookup interface {
+	ListUsersByIDs(ctx context.Context, ids []int64) (map[int64]*authclient.User, error)
+}
+
+// ErrNotFound is returned for a user the Auth service does not know
+var ErrNotFound = errors.New("user not found")
+
+// Directory resolves user IDs to emails. A nil Directory knows no one.
+type Directory struct {
+	lookup Lookup
+}
+
+func NewDirectory(lookup Lookup) *Directory {
+	return &Directory{lookup: lookup}
+}
+
+// Emails returns the emails of the users with the given IDs, by ID. Users
+// Auth does not know, and all of them when the lookup fails, are missing.
+func (d *Directory) Emails(ctx context.Context, ids []int) map[int]string {
+	if d == nil || len(ids) == 0 {
+		return nil
+	}
+
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, int64(id))
+		}
+	}
+
+	found, err := d.lookup.ListUsersByIDs(ctx, unique)
+	if err != nil {
+		logger.FromContext(ctx).WithField("err", err).Warn("failed to look up users in the Auth service")
+		return nil
+	}
+
+	emails := make(map[int]string, len(found))
+	for id, user := range found {
+		emails[int(id)] = user.GetEmail()
+	}
+	return emails
+}
+
+// Role returns the role of a user. Unlike Emails it reports a failed
+// lookup, for checks that must not pass without an answer.
+func (d *Directory) Role(ctx context.Context, id int) (string, error) {
+	found, err := d.lookup.ListUsersByIDs(ctx, []int64{int64(id)})
+	if err != nil {
+		return "", fmt.Errorf("failed to look up user in the Auth service: %w", err)
+	}
+	user, ok := found[int64(id)]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return user.GetRole(), nil
+}
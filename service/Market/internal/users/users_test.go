@@ -0,0 +1,65 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Zifeldev/marketback/clients/authclient"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLookup struct {
+	asked [][]int64
+	err   error
+}
+
+func (f *fakeLookup) ListUsersByIDs(ctx context.Context, ids []int64) (map[int64]*authclient.User, error) {
+	f.asked = append(f.asked, ids)
+	if f.err != nil {
+		return nil, f.err
+	}
+	users := map[int64]*authclient.User{}
+	for _, id := range ids {
+		if id != 3 {
+			users[id] = &authclient.User{Id: id, Email: "user@example.com", Role: "user"}
+		}
+	}
+	return users, nil
+}
+
+func TestDirectory_Emails(t *testing.T) {
+	ctx := context.Background()
+	lookup := &fakeLookup{}
+	d := NewDirectory(lookup)
+
+	emails := d.Emails(ctx, []int{1, 3, 1, 2})
+	require.Equal(t, map[int]string{1: "user@example.com", 2: "user@example.com"}, emails)
+	require.Equal(t, [][]int64{{1, 3, 2}}, lookup.asked, "each user is asked for once")
+
+	require.Nil(t, d.Emails(ctx, nil))
+	require.Len(t, lookup.asked, 1, "no lookup without users")
+
+	lookup.err = errors.New("connection refused")
+	require.Nil(t, d.Emails(ctx, []int{1}))
+
+	var none *Directory
+	require.Nil(t, none.Emails(ctx, []int{1}))
+}
+
+func TestDirectory_Role(t *testing.T) {
+	ctx := context.Background()
+	lookup := &fakeLookup{}
+	d := NewDirectory(lookup)
+
+	role, err := d.Role(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, "user", role)
+
+	_, err = d.Role(ctx, 3)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	lookup.err = errors.New("connection refused")
+	_, err = d.Role(ctx, 1)
+	require.ErrorIs(t, err, lookup.err)
+}
@@ -0,0 +1,116 @@
+// Package workers keeps track of the background workers of the service, so
+// the system status can tell which of them still run.
+package workers
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+)
+
+// minStaleAfter is the least time without a run before a worker is reported
+// stalled, so frequent workers backing off after failures are not.
+const minStaleAfter = 2 * time.Minute
+
+type beatKey struct{}
+
+// Beat records a run of the worker started with ctx by Registry.Go. Workers
+// call it once per loop; outside a registered worker it does nothing.
+func Beat(ctx context.Context) {
+	if w, ok := ctx.Value(beatKey{}).(*worker); ok {
+		w.beat()
+	}
+}
+
+// Registry lists the workers started through it. A nil Registry starts
+// workers without tracking them.
+type Registry struct {
+	mu      sync.Mutex
+	workers map[string]*worker
+	now     func() time.Time
+}
+
+type worker struct {
+	mu        sync.Mutex
+	interval  time.Duration
+	startedAt time.Time
+	lastRunAt time.Time
+	stopped   bool
+	now       func() time.Time
+}
+
+func NewRegistry() *Registry {
+	return &Registry{workers: map[string]*worker{}, now: time.Now}
+}
+
+// Go runs start(ctx, interval) in a goroutine as the worker name.
+func (r *Registry) Go(ctx context.Context, name string, interval time.Duration, start func(ctx context.Context, interval time.Duration)) {
+	if r == nil {
+		go start(ctx, interval)
+		return
+	}
+
+	w := &worker{interval: interval, startedAt: r.now(), now: r.now}
+	r.mu.Lock()
+	r.workers[name] = w
+	r.mu.Unlock()
+
+	go func() {
+		defer w.stop()
+		start(context.WithValue(ctx, beatKey{}, w), interval)
+	}()
+}
+
+func (w *worker) beat() {
+	w.mu.Lock()
+	w.lastRunAt = w.now()
+	w.mu.Unlock()
+}
+
+func (w *worker) stop() {
+	w.mu.Lock()
+	w.stopped = true
+	w.mu.Unlock()
+}
+
+// Status reports every worker by name. A worker is stalled when it has
+// not run for three intervals, and at least two minutes, since it last
+// ran or started.
+func (r *Registry) Status() []models.WorkerStatus {
+	if r == nil {
+		return []models.WorkerStatus{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	statuses := make([]models.WorkerStatus, 0, len(r.workers))
+	for name, w := range r.workers {
+		w.mu.Lock()
+		status := models.WorkerStatus{
+			Name:      name,
+			Interval:  w.interval.String(),
+			StartedAt: w.startedAt,
+			State:     models.WorkerRunning,
+		}
+		since := w.startedAt
+		if !w.lastRunAt.IsZero() {
+			lastRunAt := w.lastRunAt
+			status.LastRunAt = &lastRunAt
+			since = lastRunAt
+		}
+		switch {
+		case w.stopped:
+			status.State = models.WorkerStopped
+		case now.Sub(since) > max(3*w.interval, minStaleAfter):
+			status.State = models.WorkerStalled
+		}
+		w.mu.Unlock()
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
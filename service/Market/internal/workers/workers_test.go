@@ -0,0 +1,82 @@
+package workers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zifeldev/marketback/service/Market/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Status(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := NewRegistry()
+	r.now = func() time.Time { return now }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ran := make(chan struct{})
+	r.Go(ctx, "listing", time.Minute, func(ctx context.Context, interval time.Duration) {
+		Beat(ctx)
+		close(ran)
+		<-ctx.Done()
+	})
+	<-ran
+
+	statuses := r.Status()
+	require.Len(t, statuses, 1)
+	require.Equal(t, "listing", statuses[0].Name)
+	require.Equal(t, models.WorkerRunning, statuses[0].State)
+	require.Equal(t, "1m0s", statuses[0].Interval)
+	require.NotNil(t, statuses[0].LastRunAt)
+	require.Equal(t, now, *statuses[0].LastRunAt)
+
+	// Three intervals pass without a run.
+	now = now.Add(3*time.Minute + time.Second)
+	require.Equal(t, models.WorkerStalled, r.Status()[0].State)
+}
+
+func TestRegistry_Status_StaleAfterAtLeastTwoMinutes(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := NewRegistry()
+	r.now = func() time.Time { return now }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.Go(ctx, "event_relay", time.Second, func(ctx context.Context, interval time.Duration) {
+		<-ctx.Done()
+	})
+
+	now = now.Add(time.Minute)
+	status := r.Status()[0]
+	require.Equal(t, models.WorkerRunning, status.State)
+	require.Nil(t, status.LastRunAt)
+
+	now = now.Add(2 * time.Minute)
+	require.Equal(t, models.WorkerStalled, r.Status()[0].State)
+}
+
+func TestRegistry_Status_Stopped(t *testing.T) {
+	r := NewRegistry()
+	done := make(chan struct{})
+	r.Go(context.Background(), "feed", time.Hour, func(ctx context.Context, interval time.Duration) {
+		close(done)
+	})
+	<-done
+
+	require.Eventually(t, func() bool {
+		return r.Status()[0].State == models.WorkerStopped
+	}, time.Second, time.Millisecond)
+}
+
+func TestRegistry_Nil(t *testing.T) {
+	var r *Registry
+	done := make(chan struct{})
+	r.Go(context.Background(), "feed", time.Hour, func(ctx context.Context, interval time.Duration) {
+		Beat(ctx)
+		close(done)
+	})
+	<-done
+	require.Empty(t, r.Status())
+}